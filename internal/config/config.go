@@ -48,20 +48,209 @@ type Config struct {
 
 	// Planning configuration
 	Planning PlanningConfig `mapstructure:"planning"`
+
+	// Diagnostic analysis configuration
+	Analysis AnalysisConfig `mapstructure:"analysis"`
+
+	// Where collections are written and how long they are kept
+	Diagnostics DiagnosticsConfig `mapstructure:"diagnostics"`
+
+	// Idle limits for watches, port-forwards and exec sessions
+	Sessions SessionConfig `mapstructure:"sessions"`
+
+	// Lease election so one replica runs scheduled jobs
+	LeaderElection LeaderElectionConfig `mapstructure:"leader-election"`
+
+	// Backend for state shared by replicas: chat sessions, approvals, job state and locks
+	Store StoreConfig `mapstructure:"store"`
+
+	// How openshift_must_gather runs: with oc or as an in-cluster Job
+	MustGather MustGatherConfig `mapstructure:"must-gather"`
+
+	// Inventory export configuration
+	Inventory InventoryConfig `mapstructure:"inventory"`
+
+	// Routing of findings to owning teams
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+
+	// Thanos querier endpoints for PromQL queries
+	Monitoring MonitoringConfig `mapstructure:"monitoring"`
+
+	// Remediations that run without approval, and their kill switch
+	AutoRemediation AutoRemediationConfig `mapstructure:"auto-remediation"`
+
+	// Per-team usage reporting: API keys of each team and LLM token prices
+	Usage UsageConfig `mapstructure:"usage"`
+
+	// Git repository for action records and generated YAML
+	Git GitConfig `mapstructure:"git"`
+}
+
+// GitConfig holds the Git repository where action records are committed
+type GitConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	RepoPath     string `mapstructure:"repo-path"`
+	RemoteURL    string `mapstructure:"remote-url"`
+	Branch       string `mapstructure:"branch"`
+	AutoCommit   bool   `mapstructure:"auto-commit"`
+	AutoPush     bool   `mapstructure:"auto-push"`
+	CommitUser   string `mapstructure:"commit-user"`
+	CommitEmail  string `mapstructure:"commit-email"`
+	SyncInterval string `mapstructure:"sync-interval"` // empty disables scheduled pulls
+	PullStrategy string `mapstructure:"pull-strategy"` // rebase, merge or ff-only
+
+	// Hosting API for pull requests, status checks and browsing
+	Provider      string `mapstructure:"provider"`       // github, gitlab, gitea or bitbucket; empty detects from remote-url
+	ProviderURL   string `mapstructure:"provider-url"`   // API base URL override, e.g. for self-hosted instances
+	ProviderToken string `mapstructure:"provider-token"` // API token; user:app-password for Bitbucket
+
+	// Secret shared with the provider's push and pull request webhook
+	WebhookSecret string `mapstructure:"webhook-secret"`
+}
+
+// NotificationsConfig routes health check and alert findings to the owning
+// team's channel or ticket queue
+type NotificationsConfig struct {
+	SlackWebhookURL       string                       `mapstructure:"slack-webhook-url"` // used with a team's slack-channel annotation
+	Default               NotificationRoute            `mapstructure:"default"`
+	TeamRoutes            map[string]NotificationRoute `mapstructure:"team-routes"`           // keyed by lowercase team name
+	HealthCheckInterval   string                       `mapstructure:"health-check-interval"` // empty disables scheduled health checks
+	HealthCheckNamespaces []string                     `mapstructure:"health-check-namespaces"`
+}
+
+// NotificationRoute is a Slack incoming webhook or a generic JSON webhook
+type NotificationRoute struct {
+	Type    string `mapstructure:"type"` // slack or webhook
+	URL     string `mapstructure:"url"`
+	Channel string `mapstructure:"channel"`
+}
+
+// MonitoringConfig overrides the discovered Thanos querier, for servers that
+// run outside the cluster and cannot reach its service DNS name
+type MonitoringConfig struct {
+	QuerierURL         string `mapstructure:"querier-url"` // cluster-wide, needs cluster-monitoring-view
+	TenancyURL         string `mapstructure:"tenancy-url"` // namespace-scoped, needs view access to the namespace
+	InsecureSkipVerify bool   `mapstructure:"insecure-skip-verify"`
+
+	// Alertmanager endpoints for collect_alerts
+	AlertmanagerURL        string `mapstructure:"alertmanager-url"`
+	AlertmanagerTenancyURL string `mapstructure:"alertmanager-tenancy-url"`
+
+	// PromQL range queries collect_metrics_snapshot captures, by name
+	SnapshotQueries map[string]string `mapstructure:"snapshot-queries"`
+}
+
+// UsageConfig attributes chat and tool usage to the team owning the API key
+// a request presents, and prices the LLM tokens it consumed
+type UsageConfig struct {
+	TeamAPIKeys map[string][]string `mapstructure:"team-api-keys"` // keyed by lowercase team name
+	LLMPrices   map[string]LLMPrice `mapstructure:"llm-prices"`    // keyed by lowercase model, else provider
+}
+
+// LLMPrice is the USD price per million tokens
+type LLMPrice struct {
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+}
+
+// AutoRemediationConfig runs remediations that meet the confidence threshold
+// and policy checks without approval; the others wait in approve_remediation
+type AutoRemediationConfig struct {
+	Enabled             bool     `mapstructure:"enabled"`
+	Interval            string   `mapstructure:"interval"`   // empty only sweeps when auto_remediate is called
+	Namespaces          []string `mapstructure:"namespaces"` // the only namespaces remediated automatically
+	MinConfidence       float64  `mapstructure:"min-confidence"`
+	Recipes             []string `mapstructure:"recipes"`          // empty allows every recipe
+	MaxMemoryLimit      string   `mapstructure:"max-memory-limit"` // cap for the memory-limit recipe, e.g. 4Gi
+	TimeoutSeconds      int      `mapstructure:"timeout-seconds"`
+	KillSwitchConfigMap string   `mapstructure:"kill-switch-configmap"` // "namespace/name"
+}
+
+// DiagnosticsConfig sets the collection directory and its retention policy
+type DiagnosticsConfig struct {
+	BaseDir         string `mapstructure:"base-dir"`
+	MaxDiskUsage    string `mapstructure:"max-disk-usage"`   // quantity, e.g. 20Gi; empty is unlimited
+	TTL             string `mapstructure:"ttl"`              // e.g. 168h; empty keeps artifacts until deleted
+	CleanupInterval string `mapstructure:"cleanup-interval"` // how often retention runs
+}
+
+// SessionConfig bounds idle sessions and finished job records
+type SessionConfig struct {
+	IdleTimeout  string `mapstructure:"idle-timeout"`  // idle watches, port-forwards and exec sessions are closed after this
+	ReapInterval string `mapstructure:"reap-interval"` // how often idle sessions are reaped
+	JobRetention string `mapstructure:"job-retention"` // how long finished must-gather jobs stay listed
+}
+
+// LeaderElectionConfig elects one replica through a Lease to run scheduled
+// health checks, Git sync, inventory exports and retention cleanup
+type LeaderElectionConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	LeaseName     string `mapstructure:"lease-name"`
+	Namespace     string `mapstructure:"namespace"`      // empty uses the pod's namespace
+	LeaseDuration string `mapstructure:"lease-duration"` // how long a lease is valid without renewal
+	RenewDeadline string `mapstructure:"renew-deadline"` // how long the leader retries renewal
+	RetryPeriod   string `mapstructure:"retry-period"`   // how often candidates retry
+}
+
+// StoreConfig selects where replicas keep shared state
+type StoreConfig struct {
+	Backend   string `mapstructure:"backend"` // memory (single replica) or redis
+	Address   string `mapstructure:"address"` // redis host:port, or rediss://host:port for TLS
+	CAFile    string `mapstructure:"ca-file"` // CAs that sign a rediss server's certificate
+	Password  string `mapstructure:"password"`
+	DB        int    `mapstructure:"db"`
+	KeyPrefix string `mapstructure:"key-prefix"`
+}
+
+// MustGatherConfig selects how must-gather runs; job mode needs no oc binary
+type MustGatherConfig struct {
+	Mode           string `mapstructure:"mode"` // auto, oc or job
+	Namespace      string `mapstructure:"namespace"`
+	ServiceAccount string `mapstructure:"service-account"`
+	StorageClass   string `mapstructure:"storage-class"`
+	StorageSize    string `mapstructure:"storage-size"`
+}
+
+// InventoryConfig holds settings for scheduled CMDB inventory exports
+type InventoryConfig struct {
+	ExportInterval string   `mapstructure:"export-interval"` // empty disables scheduled exports
+	ExportDir      string   `mapstructure:"export-dir"`
+	Format         string   `mapstructure:"format"` // json or csv
+	ClusterName    string   `mapstructure:"cluster-name"`
+	Namespaces     []string `mapstructure:"namespaces"`
+	SBOMDir        string   `mapstructure:"sbom-dir"`     // SBOMs by image digest, read by image_inventory
+	SBOMCommand    []string `mapstructure:"sbom-command"` // generates an SPDX or CycloneDX JSON SBOM; {image} is replaced
+}
+
+// AnalysisConfig holds memory limits and timeline settings for diagnostic log analysis
+type AnalysisConfig struct {
+	MaxLineLength       int               `mapstructure:"max-line-length"`
+	MaxEvidencePerIssue int               `mapstructure:"max-evidence-per-issue"`
+	MaxIssues           int               `mapstructure:"max-issues"`
+	MaxEvidenceBytes    int64             `mapstructure:"max-evidence-bytes"`
+	MaxHeapMB           int               `mapstructure:"max-heap-mb"`
+	Timezone            string            `mapstructure:"timezone"`   // zone for log timestamps without an offset
+	ClockSkew           map[string]string `mapstructure:"clock-skew"` // origin -> how far its clock runs ahead
+	Locales             []string          `mapstructure:"locales"`    // pattern packs applied to every log
+	RuleDir             string            `mapstructure:"rule-dir"`   // custom rule packs, re-read by reload_analysis_rules
 }
 
 // LLMConfig holds LLM provider configuration
 type LLMConfig struct {
 	Provider string       `mapstructure:"provider"`
 	OpenAI   OpenAIConfig `mapstructure:"openai"`
-	Gemini   GeminiConfig `mapstructure:"gemini"`
-	Ollama   OllamaConfig `mapstructure:"ollama"`
-	Claude   ClaudeConfig `mapstructure:"claude"`
+	// OpenAICompatible is a self-hosted server speaking the OpenAI chat
+	// API (vLLM, LM Studio, llama.cpp server); base_url and model are required
+	OpenAICompatible OpenAIConfig `mapstructure:"openai_compatible"`
+	Gemini           GeminiConfig `mapstructure:"gemini"`
+	Ollama           OllamaConfig `mapstructure:"ollama"`
+	Claude           ClaudeConfig `mapstructure:"claude"`
 }
 
 // OpenAIConfig holds OpenAI configuration
 type OpenAIConfig struct {
 	APIKey      string  `mapstructure:"api_key"`
+	BaseURL     string  `mapstructure:"base_url"` // e.g. an Azure OpenAI or proxy endpoint; empty uses api.openai.com
 	Model       string  `mapstructure:"model"`
 	Temperature float64 `mapstructure:"temperature"`
 	MaxTokens   int     `mapstructure:"max_tokens"`
@@ -70,22 +259,31 @@ type OpenAIConfig struct {
 // GeminiConfig holds Gemini configuration
 type GeminiConfig struct {
 	APIKey      string  `mapstructure:"api_key"`
+	BaseURL     string  `mapstructure:"base_url"` // empty uses generativelanguage.googleapis.com
 	Model       string  `mapstructure:"model"`
 	Temperature float64 `mapstructure:"temperature"`
+	MaxTokens   int     `mapstructure:"max_tokens"`
+	// SafetySettings sets the block threshold per harm category, e.g.
+	// dangerous_content: block_only_high
+	SafetySettings map[string]string `mapstructure:"safety_settings"`
 }
 
 // OllamaConfig holds Ollama configuration
 type OllamaConfig struct {
-	Endpoint string `mapstructure:"endpoint"`
-	Model    string `mapstructure:"model"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Model       string  `mapstructure:"model"`
+	KeepAlive   string  `mapstructure:"keep_alive"` // how long the model stays loaded, e.g. 30m; -1 keeps it loaded
+	Temperature float64 `mapstructure:"temperature"`
 }
 
 // ClaudeConfig holds Claude configuration
 type ClaudeConfig struct {
-	APIKey      string  `mapstructure:"api_key"`
-	Model       string  `mapstructure:"model"`
-	Temperature float64 `mapstructure:"temperature"`
-	MaxTokens   int     `mapstructure:"max_tokens"`
+	APIKey       string  `mapstructure:"api_key"`
+	BaseURL      string  `mapstructure:"base_url"` // empty uses api.anthropic.com
+	Model        string  `mapstructure:"model"`
+	SystemPrompt string  `mapstructure:"system_prompt"` // placed before the instruction of every call, e.g. site conventions
+	Temperature  float64 `mapstructure:"temperature"`
+	MaxTokens    int     `mapstructure:"max_tokens"`
 }
 
 // PlanningConfig holds planning configuration
@@ -114,6 +312,41 @@ type MCPConfig struct {
 	Profile    string `mapstructure:"profile"`
 	SSEBaseURL string `mapstructure:"sse-base-url"`
 	ReadOnly   bool   `mapstructure:"read-only"`
+
+	// Default chat response style: developer, sre or manager; empty follows the profile
+	ResponsePersona string `mapstructure:"response-persona"`
+
+	// Zone and format (default, rfc3339 or relative) of timestamps in tool output
+	OutputTimezone   string `mapstructure:"output-timezone"`
+	OutputTimeFormat string `mapstructure:"output-time-format"`
+
+	// Tool execution limits
+	ToolTimeout         string            `mapstructure:"tool-timeout"`
+	ToolTimeouts        map[string]string `mapstructure:"tool-timeouts"`
+	BreakerThreshold    int               `mapstructure:"breaker-threshold"`
+	BreakerOpenDuration string            `mapstructure:"breaker-open-duration"`
+
+	// Directory for cluster baselines saved by capture_baseline
+	BaselineDir string `mapstructure:"baseline-dir"`
+
+	// Directory for exported chat session transcripts
+	TranscriptDir string `mapstructure:"transcript-dir"`
+
+	// Directory of runbook tool set definitions, re-read by reload_tool_sets
+	ToolSetDir string `mapstructure:"tool-set-dir"`
+
+	// Directory of tenant templates for provisioning namespaces with create_namespace
+	TenantTemplateDir string `mapstructure:"tenant-template-dir"`
+
+	// Record changes made by tools as Events, and optionally annotations, on the changed resources
+	ActionEvents      bool `mapstructure:"action-events"`
+	ActionAnnotations bool `mapstructure:"action-annotations"`
+
+	// Store every change as a cluster-scoped ActionRecord (needs the CRD from install_action_record_crd)
+	ActionRecords bool `mapstructure:"action-records"`
+
+	// ConfigMap ("namespace/name") whose data freezes write tools cluster-wide
+	ChangeFreezeConfigMap string `mapstructure:"change-freeze-configmap"`
 }
 
 // Load loads configuration from various sources
@@ -186,6 +419,30 @@ func Load(cmd *cobra.Command) (*Config, error) {
 		cfg.Model = model
 	}
 
+	// llm_config.yaml references API keys as ${VAR}
+	cfg.LLM.OpenAI.APIKey = os.ExpandEnv(cfg.LLM.OpenAI.APIKey)
+	if cfg.LLM.OpenAI.APIKey == "" {
+		cfg.LLM.OpenAI.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if baseURL := os.Getenv("OPENAI_BASE_URL"); baseURL != "" && cfg.LLM.OpenAI.BaseURL == "" {
+		cfg.LLM.OpenAI.BaseURL = baseURL
+	}
+	cfg.LLM.OpenAICompatible.APIKey = os.ExpandEnv(cfg.LLM.OpenAICompatible.APIKey)
+	cfg.LLM.Gemini.APIKey = os.ExpandEnv(cfg.LLM.Gemini.APIKey)
+	if cfg.LLM.Gemini.APIKey == "" {
+		cfg.LLM.Gemini.APIKey = cfg.GeminiAPIKey
+	}
+	if endpoint := os.Getenv("OLLAMA_ENDPOINT"); endpoint != "" {
+		cfg.LLM.Ollama.Endpoint = endpoint
+	}
+	if model := os.Getenv("OLLAMA_MODEL"); model != "" {
+		cfg.LLM.Ollama.Model = model
+	}
+	cfg.LLM.Claude.APIKey = os.ExpandEnv(cfg.LLM.Claude.APIKey)
+	if cfg.LLM.Claude.APIKey == "" {
+		cfg.LLM.Claude.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	}
+
 	// Set default kubeconfig if not specified
 	if cfg.Kubeconfig == "" {
 		if home, err := os.UserHomeDir(); err == nil {
@@ -209,6 +466,18 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("debug", false)
 	v.SetDefault("model", "gemini-2.0-flash-001")
 	v.SetDefault("llm-provider", "gemini")
+	v.SetDefault("llm.openai.model", "gpt-4")
+	v.SetDefault("llm.openai.temperature", 0.1)
+	v.SetDefault("llm.openai.max_tokens", 1500)
+	v.SetDefault("llm.openai_compatible.temperature", 0.1)
+	v.SetDefault("llm.openai_compatible.max_tokens", 1500)
+	v.SetDefault("llm.gemini.temperature", 0.1)
+	v.SetDefault("llm.gemini.max_tokens", 1500)
+	v.SetDefault("llm.ollama.endpoint", "http://localhost:11434")
+	v.SetDefault("llm.ollama.model", "llama3.1")
+	v.SetDefault("llm.ollama.temperature", 0.1)
+	v.SetDefault("llm.claude.temperature", 0.1)
+	v.SetDefault("llm.claude.max_tokens", 1500)
 	v.SetDefault("confidence-threshold", 0.7)
 	v.SetDefault("evidence-limit", 10)
 
@@ -216,6 +485,64 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("mcp.enabled", true)
 	v.SetDefault("mcp.profile", "sre")
 	v.SetDefault("mcp.read-only", false)
+	v.SetDefault("mcp.tool-timeout", "2m")
+	v.SetDefault("mcp.breaker-threshold", 5)
+	v.SetDefault("mcp.breaker-open-duration", "30s")
+	v.SetDefault("mcp.baseline-dir", "/tmp/diagnostics/baselines")
+	v.SetDefault("mcp.transcript-dir", "/tmp/diagnostics/transcripts")
+	v.SetDefault("mcp.action-events", true)
+	v.SetDefault("mcp.action-annotations", false)
+	v.SetDefault("mcp.change-freeze-configmap", "openshift-mcp/change-freeze")
+
+	// Analysis defaults
+	v.SetDefault("analysis.max-line-length", 65536)
+	v.SetDefault("analysis.max-evidence-per-issue", 10)
+	v.SetDefault("analysis.max-issues", 1000)
+	v.SetDefault("analysis.max-evidence-bytes", 8388608)
+	v.SetDefault("analysis.max-heap-mb", 1024)
+	v.SetDefault("analysis.timezone", "UTC")
+
+	// Diagnostics defaults
+	v.SetDefault("diagnostics.base-dir", "/tmp/diagnostics")
+	v.SetDefault("diagnostics.cleanup-interval", "1h")
+
+	// Session defaults
+	v.SetDefault("sessions.idle-timeout", "15m")
+	v.SetDefault("sessions.reap-interval", "1m")
+	v.SetDefault("sessions.job-retention", "24h")
+
+	// Leader election defaults
+	v.SetDefault("leader-election.enabled", false)
+	v.SetDefault("leader-election.lease-name", "openshift-mcp-scheduler")
+	v.SetDefault("leader-election.lease-duration", "15s")
+	v.SetDefault("leader-election.renew-deadline", "10s")
+	v.SetDefault("leader-election.retry-period", "2s")
+
+	// Shared state defaults
+	v.SetDefault("store.backend", "memory")
+	v.SetDefault("store.key-prefix", "openshift-mcp:")
+
+	// Must-gather defaults
+	v.SetDefault("must-gather.mode", "auto")
+	v.SetDefault("must-gather.namespace", "openshift-mcp")
+	v.SetDefault("must-gather.service-account", "must-gather")
+	v.SetDefault("must-gather.storage-size", "10Gi")
+
+	// Inventory defaults
+	v.SetDefault("inventory.export-dir", "/tmp/diagnostics/inventory")
+	v.SetDefault("inventory.format", "json")
+	v.SetDefault("inventory.sbom-dir", "/tmp/diagnostics/sbom")
+
+	// Auto-remediation defaults
+	v.SetDefault("auto-remediation.enabled", false)
+	v.SetDefault("auto-remediation.min-confidence", 0.9)
+	v.SetDefault("auto-remediation.timeout-seconds", 180)
+	v.SetDefault("auto-remediation.kill-switch-configmap", "openshift-mcp/auto-remediation")
+
+	// Git defaults
+	v.SetDefault("git.enabled", false)
+	v.SetDefault("git.branch", "main")
+	v.SetDefault("git.pull-strategy", "rebase")
 
 	// Server defaults
 	v.SetDefault("server.host", "0.0.0.0")
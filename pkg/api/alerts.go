@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+// alertDiagnosisTimeout bounds the diagnosis and notification of one alert
+const alertDiagnosisTimeout = 2 * time.Minute
+
+// AlertmanagerWebhook is the payload Alertmanager posts to webhook receivers
+type AlertmanagerWebhook struct {
+	Status string              `json:"status"`
+	Alerts []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert in an Alertmanager webhook
+type AlertmanagerAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// alertTarget picks the resource an alert is about from its labels
+func alertTarget(labels map[string]string) (resourceType, resourceName string) {
+	for _, candidate := range []string{"pod", "deployment", "service"} {
+		if name := labels[candidate]; name != "" {
+			return candidate, name
+		}
+	}
+	// Namespace-wide alerts diagnose every failing pod in the namespace
+	return "pod", ""
+}
+
+// handleAlertWebhook diagnoses firing alerts and routes each report to the
+// owning team. Alertmanager expects a quick response, so diagnoses run in
+// the background.
+func (s *Server) handleAlertWebhook(c *gin.Context) {
+	var webhook AlertmanagerWebhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	accepted := 0
+	for _, alert := range webhook.Alerts {
+		namespace := alert.Labels["namespace"]
+		if alert.Status != "firing" || namespace == "" {
+			continue
+		}
+		accepted++
+
+		title := alert.Labels["alertname"]
+		if summary := alert.Annotations["summary"]; summary != "" {
+			title += ": " + summary
+		}
+		severity := alert.Labels["severity"]
+		resourceType, resourceName := alertTarget(alert.Labels)
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), alertDiagnosisTimeout)
+			defer cancel()
+			if _, err := s.mcpServer.DiagnoseAndNotify(ctx, title, severity, resourceType, resourceName, namespace); err != nil {
+				logrus.WithError(err).Warnf("Failed to route alert %s", title)
+			}
+		}()
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"received": len(webhook.Alerts),
+		"accepted": accepted,
+	})
+}
+
+// notificationConfig converts the notifications section for the MCP server
+func notificationConfig(cfg config.NotificationsConfig) *mcpserver.NotificationConfig {
+	route := func(r config.NotificationRoute) mcpserver.NotificationRoute {
+		return mcpserver.NotificationRoute{Type: r.Type, URL: r.URL, Channel: r.Channel}
+	}
+	teamRoutes := make(map[string]mcpserver.NotificationRoute, len(cfg.TeamRoutes))
+	for team, r := range cfg.TeamRoutes {
+		teamRoutes[team] = route(r)
+	}
+	return &mcpserver.NotificationConfig{
+		SlackWebhookURL:       cfg.SlackWebhookURL,
+		DefaultRoute:          route(cfg.Default),
+		TeamRoutes:            teamRoutes,
+		HealthCheckInterval:   cfg.HealthCheckInterval,
+		HealthCheckNamespaces: cfg.HealthCheckNamespaces,
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import "testing"
+
+func TestAlertTarget(t *testing.T) {
+	tests := []struct {
+		labels       map[string]string
+		expectedType string
+		expectedName string
+	}{
+		{map[string]string{"namespace": "shop", "pod": "web-1", "deployment": "web"}, "pod", "web-1"},
+		{map[string]string{"namespace": "shop", "deployment": "web"}, "deployment", "web"},
+		{map[string]string{"namespace": "shop", "service": "web"}, "service", "web"},
+		{map[string]string{"namespace": "shop"}, "pod", ""},
+	}
+
+	for _, tt := range tests {
+		resourceType, resourceName := alertTarget(tt.labels)
+		if resourceType != tt.expectedType || resourceName != tt.expectedName {
+			t.Errorf("alertTarget(%v) = %s %q, expected %s %q", tt.labels, resourceType, resourceName, tt.expectedType, tt.expectedName)
+		}
+	}
+}
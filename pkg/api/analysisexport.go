@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// AnalysisExportRequest selects the artifact to analyze and the format of
+// the exported result
+type AnalysisExportRequest struct {
+	Kind   string `json:"kind" binding:"required"` // must_gather, logs, tcpdump, ingress, ovn or storage
+	Path   string `json:"path" binding:"required"` // the artifact's directory, file or bundle
+	Format string `json:"format,omitempty"`        // json (default), sarif or html
+	Save   bool   `json:"save,omitempty"`          // also store the export in the reports directory
+}
+
+// HandleAnalysisExport analyzes an artifact and returns the full result as
+// JSON, SARIF or a standalone HTML report, e.g. for a CI pipeline
+func (h *EnhancedChatHandler) HandleAnalysisExport(c *gin.Context) {
+	var req AnalysisExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := diagnostics.ParseExportFormat(req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if format == "" {
+		format = diagnostics.ExportJSON
+	}
+	if h.server == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MCP server not available"})
+		return
+	}
+
+	result, err := h.server.AnalyzeArtifact(c.Request.Context(), req.Kind, req.Path)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := result.Export(format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("%s.%s", req.Kind, format)
+	if req.Save {
+		path, err := h.server.ExportAnalysis(result, format)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		filename = filepath.Base(path)
+		c.Header("X-Report-Path", path)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, diagnostics.ExportContentType(format), data)
+}
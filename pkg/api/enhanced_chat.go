@@ -2,10 +2,14 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +18,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/llm"
 	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/types"
 )
 
@@ -24,6 +30,10 @@ type EnhancedChatRequest struct {
 	MaxSteps    int    `json:"max_steps,omitempty"`   // Maximum number of iterative steps
 	Interactive bool   `json:"interactive,omitempty"` // Whether to support interactive mode
 	Profile     string `json:"profile,omitempty"`     // Profile to use (sre, developer, admin)
+	Persona     string `json:"persona,omitempty"`     // Response style (developer, sre, manager), defaults to the profile's
+	SessionID   string `json:"session_id,omitempty"`  // Conversation the request belongs to, recorded on cluster changes
+	Timezone    string `json:"timezone,omitempty"`    // IANA zone timestamps in step results are shown in
+	TimeFormat  string `json:"time_format,omitempty"` // default, rfc3339 or relative ("5m ago")
 }
 
 // EnhancedChatResponse represents an enhanced chat response with step-by-step execution
@@ -39,6 +49,7 @@ type EnhancedChatResponse struct {
 
 // ExecutionStep represents a single step in the execution process
 type ExecutionStep struct {
+	StepID     string                 `json:"step_id"`
 	StepNumber int                    `json:"step_number"`
 	Action     string                 `json:"action"`
 	ToolUsed   string                 `json:"tool_used"`
@@ -46,8 +57,34 @@ type ExecutionStep struct {
 	Result     string                 `json:"result"`
 	Success    bool                   `json:"success"`
 	Error      string                 `json:"error,omitempty"`
+	ErrorKind  string                 `json:"error_kind,omitempty"` // NotFound, Forbidden, Conflict, Timeout, ClusterUnavailable, ...
+	Retryable  bool                   `json:"retryable,omitempty"`
 	Duration   time.Duration          `json:"duration"`
 	Timestamp  time.Time              `json:"timestamp"`
+
+	// Truncated results keep their first lines in Result; the full output
+	// is paged through with get_step_output or /api/v1/chat/steps/:id/output
+	Truncated  bool `json:"truncated,omitempty"`
+	TotalLines int  `json:"total_lines,omitempty"`
+}
+
+// maxStepResultChars is the longest step result returned inline
+const maxStepResultChars = 4000
+
+// truncateStepResult cuts a result at the last line that fits in limit
+// characters and returns how many lines were kept and the total
+func truncateStepResult(result string, limit int) (string, int, int) {
+	total := strings.Count(result, "\n") + 1
+	if len(result) <= limit {
+		return result, total, total
+	}
+	cut := strings.LastIndex(result[:limit], "\n")
+	if cut <= 0 {
+		// A single very long line is still cut on a line boundary
+		cut = limit
+	}
+	kept := result[:cut]
+	return kept, strings.Count(kept, "\n") + 1, total
 }
 
 // EnhancedChatHandler handles enhanced chat requests with MCP tool integration
@@ -56,6 +93,8 @@ type EnhancedChatHandler struct {
 	maxSteps       int
 	defaultProfile string
 	config         *config.Config
+	sessions       *sessionLog
+	wizards        *wizardSessions
 }
 
 // NewEnhancedChatHandler creates a new enhanced chat handler
@@ -65,9 +104,18 @@ func NewEnhancedChatHandler(server *mcpserver.Server, cfg *config.Config) *Enhan
 		maxSteps:       10, // Default maximum steps
 		defaultProfile: "sre",
 		config:         cfg,
+		sessions:       newSessionLog(),
+		wizards:        newWizardSessions(),
 	}
 }
 
+// SetStore keeps chat and wizard sessions in st, so replicas behind a route
+// serve them without sticky sessions
+func (h *EnhancedChatHandler) SetStore(st store.Store) {
+	h.sessions.store = st
+	h.wizards.store = st
+}
+
 // extractNamespaceFromQuery extracts namespace from the query string
 func (h *EnhancedChatHandler) extractNamespaceFromQuery(query string) string {
 	// Common namespace patterns
@@ -105,17 +153,115 @@ func (h *EnhancedChatHandler) extractNamespaceFromQuery(query string) string {
 func (h *EnhancedChatHandler) buildPlanningPrompt(query string) string {
 	availableTools := []string{
 		"list_pods - List pods in a namespace (parameters: namespace)",
+		"list_deployments - List deployments with ready/desired replicas, use before naming a deployment (parameters: namespace or \"all\", label_selector)",
+		"list_statefulsets - List statefulsets with ready/desired replicas and rollout state (parameters: namespace or \"all\", label_selector)",
+		"list_daemonsets - List daemonsets with scheduled/ready pods per node (parameters: namespace or \"all\", label_selector)",
+		"get_pod_logs - Get recent logs from a pod (parameters: pod_name, namespace, container, tail_lines, since, timestamps, previous=true for the crashed instance)",
+		"exec_in_pod - Run a diagnostic command such as nslookup, curl or env inside a running pod (parameters: pod_name, namespace, command, container, shell=true for pipes, timeout_seconds)",
+		"port_forward - Check a pod or service from the MCP host through a temporary port-forward (parameters: namespace, pod_name or service_name, port, probe_path such as /healthz, probe_scheme, ttl_seconds)",
+		"list_nodes - List nodes with roles, readiness, pressure conditions and allocatable resources (parameters: role such as worker or infra, label_selector)",
+		"describe_node - Show a node's conditions, taints, requested vs allocatable resources and pod counts (parameters: node_name)",
+		"analyze_node_capacity - Explain why pods are Pending or were evicted from node conditions, requested vs allocatable resources, scheduler reasons and eviction thresholds (parameters: namespace, eviction_hard)",
+		"top_pods - Show actual CPU and memory usage of pods from the metrics API vs their requests and memory limits; use it for performance questions instead of suggesting oc adm top (parameters: namespace, label_selector, sort_by cpu|memory, containers, limit)",
+		"top_nodes - Show actual CPU and memory usage of nodes as a share of allocatable (parameters: label_selector, sort_by cpu|memory)",
+		"cordon_node - Stop scheduling new pods on a node (parameters: node_name)",
+		"uncordon_node - Allow scheduling on a node again after maintenance (parameters: node_name)",
+		"drain_node - Cordon a node and evict its pods respecting PodDisruptionBudgets; use dry_run=true first (parameters: node_name, ignore_daemonsets, delete_emptydir_data, force, grace_period_seconds, timeout_seconds, dry_run)",
+		"list_pvcs - List PersistentVolumeClaims with binding status, capacity and storage class (parameters: namespace or \"all\", label_selector)",
+		"get_pvc - Show a claim's bound volume, storage class, mounting pods and volume events (parameters: pvc_name, namespace)",
+		"diagnose_pvc - Find why a claim is Pending or its volume fails to attach or mount (parameters: pvc_name, namespace)",
+		"list_hpas - List HorizontalPodAutoscalers with target, replica range and current versus target metrics (parameters: namespace or \"all\", label_selector)",
+		"diagnose_hpa - Find why an autoscaler does not scale: missing metrics-server, unknown metrics, missing requests, missing target; shows scaling events (parameters: hpa_name, namespace)",
+		"create_hpa - Autoscale a deployment or statefulset on CPU/memory utilization; use dry_run=true to preview (parameters: target_name, namespace, target_kind, name, min_replicas, max_replicas, cpu_percent, memory_percent, dry_run)",
+		"list_network_policies - List NetworkPolicies with the pods they select and the traffic they allow (parameters: namespace or \"all\", label_selector)",
+		"explain_network_policy - Explain in plain language which traffic a NetworkPolicy permits; use it to follow up when connectivity problems point at NetworkPolicies (parameters: policy_name, namespace)",
+		"generate_network_policy - Generate NetworkPolicy YAML from a template: deny-all, deny-all-ingress, deny-all-egress, allow-same-namespace, allow-from-namespace, allow-from-pods, allow-from-ingress, allow-from-monitoring, allow-dns-egress (parameters: template, namespace, name, pod_selector, from_namespace, from_pod_selector, ports, save_to_git)",
+		"list_jobs - List Jobs with completion status and failed attempts (parameters: namespace or \"all\", label_selector)",
+		"list_cronjobs - List CronJobs with schedule, suspension and last schedule and success times (parameters: namespace or \"all\", label_selector)",
+		"trigger_cronjob - Run a CronJob now by creating a Job from it (parameters: cronjob_name, namespace, job_name)",
+		"diagnose_job - Find why a Job failed or is stuck: backoff limit, deadline, failed pod reasons (parameters: job_name, namespace)",
+		"list_routes - List Routes with host, backing service, TLS termination and router admission (parameters: namespace or \"all\", label_selector)",
+		"openshift_route_analyze - Check a Route end to end: backing service endpoints, target port, TLS and certificate expiry, router admission, router and load balancer health, DNS (parameters: route_name, namespace)",
+		"analyze_route_sharding - Check which IngressController shard selects and admits each route, routes no shard admits with the labels that fix them, overlapping shards and router placement across zones (parameters: namespace)",
+		"openshift_must_gather - Start a must-gather collection in the background and return its job ID (parameters: image, node_name, since, dest_dir)",
+		"must_gather_status - Show a must-gather job's phase, collected size and recent output, or list jobs (parameters: job_id)",
+		"list_diagnostics - List collected diagnostics artifacts with IDs, paths, sizes and ages, or one artifact's details (parameters: id, type)",
+		"list_imagestreams - List ImageStreams with tags, current images and import errors (parameters: namespace or \"all\", name for tag history)",
+		"check_dns_telemetry - Check CoreDNS SERVFAIL rate, cache hits and upstream latency, and correlate them with lookups from a namespace's pods to tell cluster DNS from upstream resolver problems (parameters: namespace, pods, external_name, window)",
+		"diagnose_cluster_dns - Check the DNS operator, the CoreDNS pods and the upstream resolvers, and run test lookups from several nodes; use it first when names do not resolve (parameters: nodes, max_nodes, external_name)",
+		"check_node_connection_limits - Check nodes for conntrack table saturation and ephemeral port exhaustion, which cause intermittent connection refused or timeouts that pod-level checks cannot explain (parameters: node, window)",
+		"probe_control_plane - Answer whether the platform or the application is broken: time API server requests, read API server error rates per instance, and check the control plane operators, the OAuth route and identity providers, and the API certificates and load balancer (parameters: samples)",
+		"audit_certificates - Find expired and expiring certificates in TLS secrets, routes, the API server and kubelets (parameters: namespace, checks, warning_days, critical_days)",
+		"image_inventory - Inventory running images by digest and search their SBOMs for a package, e.g. which workloads run log4j 2.x (parameters: namespace, image, package, version, generate_sboms)",
+		"trace_image - Trace an internal registry image from an ImagePullBackOff to its ImageStream tag, import error and producing BuildConfig (parameters: image, namespace of the pod)",
+		"list_buildconfigs - List BuildConfigs with source, output and latest build (parameters: namespace, name for recent builds)",
+		"get_build_logs - Show a build's failure reason and the logs of each build step (parameters: build_name, namespace, tail_lines)",
+		"list_secrets - List secrets with their type and key names, never values (parameters: namespace or \"all\", label_selector)",
+		"get_secret - Confirm a secret exists and see its keys with values redacted; only reveal a value when the user explicitly asks (parameters: secret_name, namespace, reveal, keys)",
+		"can_i - Check whether the server (or as_user) may perform a verb on a resource before attempting a change that could be Forbidden (parameters: verb, resource, namespace, resource_name, as_user, as_groups)",
+		"who_can - List users, groups and service accounts allowed a verb on a resource (parameters: verb, resource, namespace, resource_name)",
+		"audit_rbac - Find bindings to deleted users, groups and service accounts, unused roles and wildcard grants, with a risk score per namespace and cleanup YAML (parameters: namespace, include_platform)",
+		"request_elevation - Show the exact permission a Forbidden call lacked and the Role/RoleBinding YAML that would grant it (parameters: elevation_id from the Forbidden result, or verb, resource, namespace, resource_name, user)",
+		"install_action_record_crd - Install the ActionRecord CRD so changes made by tools can be stored in the cluster (parameters: dry_run)",
+		"list_action_records - List the changes tools made to the cluster, newest first: what, when, which tool and session (parameters: namespace, action, target, session_id, since, limit)",
 		"list_namespaces - List all namespaces (no parameters needed)",
+		"query_metrics - Run a PromQL query; pass namespace to scope it through the tenancy-aware querier, which includes the app's own metrics (parameters: query, namespace, limit)",
+		"collect_metrics_snapshot - Capture CPU, memory, restart and throttling history as a diagnostics artifact whose spikes join the log analysis timeline (parameters: namespace, queries, since, end, step, output_dir)",
+		"collect_ovn_diagnostics - Collect ovnkube logs, OVN database status, ovn-controller connections, OVS bridges and node gateway configuration and report known OVN-Kubernetes failures, e.g. for pods stuck in ContainerCreating with FailedCreatePodSandBox (parameters: node, since, output_dir, compressed)",
+		"analyze_ovn_diagnostics - Analyze an earlier OVN-Kubernetes collection (parameters: path, export)",
+		"collect_ingress_diagnostics - Collect router logs, HAProxy state, IngressController conditions, load balancer services and rejected routes from openshift-ingress and report reload errors, admission conflicts and load balancer problems, e.g. when many routes return 503 at once (parameters: controller, since, output_dir, compressed)",
+		"analyze_ingress_diagnostics - Analyze an earlier ingress collection (parameters: path, export)",
+		"collect_storage_diagnostics - Collect CSI driver logs, controller and node plugin health, failing VolumeAttachments, volumes stuck terminating and provisioning, attach and mount events and report storage root causes, e.g. when pods hang in ContainerCreating on volumes (parameters: namespace, since, output_dir, compressed)",
+		"analyze_storage_diagnostics - Analyze an earlier storage collection (parameters: path, export)",
+		"analyze_must_gather_diff - Compare two must-gathers taken before and after an upgrade or incident: newly Degraded operators, changed node conditions and newly failing pods (parameters: before_path, after_path)",
+		"collect_alerts - Collect firing and recently resolved Alertmanager alerts and pick out those about a resource, e.g. KubePodCrashLooping for a pod (parameters: namespace, resource_type, resource_name, since, include_silenced, output_dir)",
+		"open_war_room - Open an incident war room that refreshes a service's rollout, pods, firing alerts, warning events and error rate periodically and streams the summary to the chat until closed (parameters: namespace, service, interval_seconds, duration_minutes, error_rate_query)",
+		"war_room_status - Show the latest summary and change log of a war room, or list the open ones (parameters: room_id)",
+		"close_war_room - Close a war room and stop its updates (parameters: room_id)",
 		"get_events - Get events from a namespace (parameters: namespace)",
 		"get_resource - Get details about a specific resource (parameters: resource_type, name, namespace)",
+		"watch_resource - Watch a resource or label selector for up to timeout_seconds, reporting state transitions, until a condition is met (parameters: resource_type, name, namespace, label_selector, until such as ready, deleted or Available=True, timeout_seconds)",
 		"create_configmap - Create a ConfigMap (parameters: name, namespace, data)",
-		"create_namespace - Create a new namespace (parameters: namespace_name)",
-		"create_resource - Create any Kubernetes resource (parameters: yaml, namespace)",
-		"delete_resource - Delete a Kubernetes resource (parameters: resource_type, name, namespace)",
+		"create_secret - Create a Secret (parameters: name, namespace, data, type)",
+		"start_build - Start a build from a BuildConfig (parameters: buildconfig_name, namespace, commit)",
+		"create_route - Expose a Service with a Route (parameters: service, namespace, name, host, path, port, tls_termination=edge|passthrough|reencrypt, insecure_policy)",
+		"create_namespace - Create a new namespace; pass team or template to provision it with quota, limits, baseline network policies and team RoleBindings committed to Git (parameters: namespace_name, template, team, admin_groups, edit_groups, view_groups, labels, dry_run, save_to_git)",
+		"clone_namespace - Copy a namespace's workloads and configuration into a new namespace for a test replica; use dry_run=true to preview (parameters: source_namespace, target_namespace, resource_types, secrets=skip|copy|placeholder, rename=old=new,..., labels=key=value,..., replicas, dry_run)",
+		"create_resource - Create any Kubernetes resource (parameters: yaml, namespace, dry_run=true to validate without changing the cluster)",
+		"patch_resource - Change a few fields of any resource without the full YAML; use dry_run=true first (parameters: resource_type, resource_name, namespace, patch_type=json|merge|strategic, patch, dry_run)",
+		"delete_resource - Delete a Kubernetes resource (parameters: resource_type, resource_name, namespace, propagation_policy, grace_period_seconds, confirm=true to actually delete)",
 		"scale_deployment - Scale a deployment (parameters: name, namespace, replicas)",
-		"apply_yaml - Apply YAML configuration (parameters: yaml, namespace)",
-		"generate_yaml - Generate YAML for common resources (parameters: resource_type, name, namespace, image, replicas, data)",
+		"rollback_deployment - Roll a deployment back to an earlier revision (parameters: deployment_name, namespace, revision=previous or a number)",
+		"remediate_deployment - Fix a missing ConfigMap key, an image tag typo or a too low memory limit of a deployment, verify the rollout and roll back automatically if it fails; use dry_run=true first (parameters: deployment_name, namespace, recipe=missing-configmap-key|image-tag-typo|memory-limit, value, timeout_seconds, dry_run)",
+		"auto_remediate - Sweep for fixable deployment issues; confident fixes that pass policy run automatically, the rest are queued for approval (parameters: namespace, dry_run)",
+		"list_remediation_approvals - List remediations waiting for approval and the auto-remediation kill switch state (parameters: status)",
+		"apply_yaml - Apply YAML configuration (parameters: yaml, namespace, dry_run=true to validate without changing the cluster)",
+		"generate_yaml - Generate YAML for common resources, or instantiate a catalog stack with template; prefer a catalog stack for \"deploy X\" requests (parameters: resource_type, name, namespace, image, replicas, data, template, parameters as a JSON object)",
+		"list_catalog - List the pre-approved application stacks (web app with route and HPA, web app with PostgreSQL, batch job) and their parameters (parameters: stack)",
+		"describe_resource_schema - Build a parameter form for a custom resource (e.g. a Kafka cluster) from its CRD's OpenAPI schema (parameters: resource_type, field, depth)",
+		"generate_custom_resource - Generate schema-valid YAML for a custom resource from field values, reporting unknown, mistyped and missing required fields (parameters: resource_type, name, namespace, values)",
 		"openshift_diagnose - Diagnose OpenShift cluster issues",
+		"get_cluster_version - Show the cluster version, whether an upgrade is in progress, update history and blocking conditions (parameters: show_updates=true for available updates and channels, history_limit)",
+		"get_cluster_operators - List ClusterOperators with Available/Progressing/Degraded conditions and messages (parameters: name for one operator's detail, unhealthy_only=true)",
+		"change_freeze_status - Show active change freezes; write tools refuse to run in frozen namespaces (parameters: namespace)",
+		"get_step_output - Page through the full output of a truncated chat step (parameters: step_id, offset, limit)",
+		"server_capabilities - List the server version, policy mode, available tools and which ones are destructive (parameters: format=json)",
+		"tool_migration_report - List deprecated tool names and their replacements, or find deprecated names in a runbook or client configuration (parameters: text, rewrite)",
+		"list_tool_sets - List the tool sets (runbooks, plugins) registered at runtime and their tools",
+		"reload_tool_sets - Pick up added, changed or removed runbook tool set definitions without a restart",
+		"list_analysis_rules - List the custom log analysis rule packs and their rules (parameters: rule_pack)",
+		"reload_analysis_rules - Pick up added, changed or removed analysis rule packs without a restart",
+		"list_known_issues - List the known issue signatures mapping log and event fingerprints to known bugs or KCS articles (parameters: search)",
+		"performance_report - List the slowest tools and chat queries with latency percentiles, API calls per call and time sinks (parameters: limit)",
+		"usage_report - Report per team since the server started: chat queries, tool calls, actions performed, top tools and LLM tokens and cost (parameters: tenant, limit)",
+		"runtime_stats - Show the server's goroutines, heap, open watches, port-forwards and exec sessions, and background jobs",
+		"self_diagnose - Check the assistant's own health: cluster connection, RBAC, Git, LLM latency and recent errors (parameters: check_llm, check_git)",
+		"notify_owners - Send a finding to the owning team's channel or ticket queue (parameters: namespace, title, message, severity, resource_type, resource_name, dry_run)",
+		"get_ownership - Find the owning team and on-call contacts of a resource or namespace (parameters: namespace, resource_type, resource_name)",
+		"capture_baseline - Save a known-good cluster baseline before an upgrade or change (parameters: name, namespaces)",
+		"compare_baseline - Compare the cluster against a saved baseline and list regressions (parameters: name)",
+		"onboard_namespace - Bring an existing namespace under GitOps: export its workloads as normalized manifests with an ArgoCD Application and commit them; use dry_run=true to preview (parameters: namespace, app_name, environment, resource_types, dry_run)",
+		"export_inventory - Export namespaces, workloads, images and owners for a CMDB (parameters: format=json or csv, namespaces, output_path)",
 	}
 
 	prompt := fmt.Sprintf(`You are an expert OpenShift SRE. Given a user query, create an execution plan using ONLY the available MCP tools listed below.
@@ -135,7 +281,8 @@ IMPORTANT:
 - For common resources (deployment, service, configmap), use generate_yaml tool first, then apply_yaml
 - For complex applications (skupper, operators, etc.), create specific YAML content and use apply_yaml
 - When using apply_yaml, provide actual YAML content in the yaml parameter, not placeholder names
-
+- After a step that changes a workload (scale, restart, rollback, apply), add a watch_resource step with until=ready to verify the change took effect
+%s%s
 YAML Content Guidelines:
 - Always provide complete, valid YAML content in the yaml parameter
 - For Skupper v2: Use "quay.io/skupper/skupper-router:2.0" image with proper deployment YAML
@@ -145,7 +292,7 @@ YAML Content Guidelines:
 Generate a JSON execution plan with this structure:
 {
   "description": "Brief description of what will be done",
-  "category": "troubleshooting|exploration|maintenance",
+  "category": "troubleshooting|exploration|maintenance|performance",
   "complexity": "low|medium|high",
   "steps": [
     {
@@ -158,11 +305,37 @@ Generate a JSON execution plan with this structure:
   ]
 }
 
-Return only the JSON, no explanations.`, query, strings.Join(availableTools, "\n"))
+Return only the JSON, no explanations.`, query, strings.Join(availableTools, "\n"), h.changeFreezeNote(), h.learnedRunbookNote(query))
 
 	return prompt
 }
 
+// changeFreezeNote tells the planner about active change freezes so it plans
+// read-only alternatives instead of changes the server will refuse
+func (h *EnhancedChatHandler) changeFreezeNote() string {
+	if h.server == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	freezes, err := h.server.ChangeFreezes(ctx)
+	if err != nil {
+		logrus.Debugf("Could not check change freezes for planning: %v", err)
+	}
+	if len(freezes) == 0 {
+		return ""
+	}
+
+	note := "\nCHANGE FREEZE IN EFFECT:\n"
+	for _, freeze := range freezes {
+		note += fmt.Sprintf("- %s\n", freeze.Describe())
+	}
+	note += "- Write tools (create, apply, scale, delete, restart, rollback, cordon, drain, trigger) are refused in frozen scopes\n"
+	note += "- Plan read-only alternatives instead: list, get, describe and diagnose tools, or dry_run=true to prepare the change for after the freeze\n"
+	note += "- Tell the user which change is blocked by the freeze\n"
+	return note
+}
+
 // callLLMForPlanning calls the LLM service for planning
 func (h *EnhancedChatHandler) callLLMForPlanning(prompt string) (string, error) {
 	var provider string
@@ -197,6 +370,8 @@ func (h *EnhancedChatHandler) hasRealLLMIntegration() bool {
 	switch provider {
 	case "openai":
 		return h.config.LLM.OpenAI.APIKey != ""
+	case "openai-compatible":
+		return h.config.LLM.OpenAICompatible.BaseURL != ""
 	case "claude":
 		return h.config.LLM.Claude.APIKey != ""
 	case "gemini":
@@ -208,73 +383,61 @@ func (h *EnhancedChatHandler) hasRealLLMIntegration() bool {
 	}
 }
 
-// callOpenAIGPT4 integrates with OpenAI GPT-4
-func (h *EnhancedChatHandler) callOpenAIGPT4(prompt string) (string, error) {
-	// Example implementation - you'll need to add OpenAI client
-	/*
-		client := openai.NewClient(os.Getenv("OPENAI_API_KEY"))
+// planningSystemPrompt is the system instruction of planning calls
+const planningSystemPrompt = "You are an expert OpenShift/Kubernetes administrator. Respond only with valid JSON."
 
-		req := openai.ChatCompletionRequest{
-			Model: openai.GPT4,
-			Messages: []openai.ChatCompletionMessage{
-				{Role: openai.ChatMessageRoleUser, Content: prompt},
-			},
-			Temperature: 0.1, // Low temperature for consistent planning
-			MaxTokens:   1000,
+// planningCompleter returns the client of the configured provider, or nil
+// when no provider is configured
+func (h *EnhancedChatHandler) planningCompleter() (llm.Completer, error) {
+	if h.config == nil {
+		return nil, nil
+	}
+	switch h.config.LLM.Provider {
+	case "openai":
+		return llm.NewOpenAIClient(h.config.LLM.OpenAI)
+	case "openai-compatible":
+		return llm.NewOpenAICompatibleClient(h.config.LLM.OpenAICompatible)
+	case "claude":
+		return llm.NewAnthropicClient(h.config.LLM.Claude)
+	case "gemini":
+		client, err := llm.NewGeminiClientFromLLMConfig(h.config.LLM.Gemini)
+		if err != nil {
+			return nil, err
 		}
-
-		resp, err := client.CreateChatCompletion(context.Background(), req)
+		client.SetJSONOutput(true)
+		return client, nil
+	case "ollama":
+		client, err := llm.NewOllamaClient(h.config.LLM.Ollama)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-
-		return resp.Choices[0].Message.Content, nil
-	*/
-
-	// For demonstration, return a dynamic response based on the query
-	return h.generateIntelligentMockResponse(prompt)
-}
-
-// callGemini integrates with Google Gemini (already available in your server)
-func (h *EnhancedChatHandler) callGemini(prompt string) (string, error) {
-	// You can use the existing Gemini client from your server
-	// This would integrate with the LLM client already configured
-	return h.generateIntelligentMockResponse(prompt)
-}
-
-// callClaude integrates with Anthropic Claude
-func (h *EnhancedChatHandler) callClaude(prompt string) (string, error) {
-	// You can use the existing Claude client from your server
-	// This would integrate with the Anthropic client already configured
-	return h.generateIntelligentMockResponse(prompt)
+		client.SetJSONOutput(true)
+		return client, nil
+	}
+	return nil, nil
 }
 
-// callOllama integrates with local Ollama LLM
-func (h *EnhancedChatHandler) callOllama(prompt string) (string, error) {
-	// Example Ollama integration
-	/*
-		payload := map[string]interface{}{
-			"model":  "llama3.1",
-			"prompt": prompt,
-			"stream": false,
-		}
-
-		jsonPayload, _ := json.Marshal(payload)
-		resp, err := http.Post("http://localhost:11434/api/generate",
-			"application/json", bytes.NewBuffer(jsonPayload))
+// completePlanning sends a planning prompt to the configured provider, or
+// answers with a mock plan when none is configured
+func (h *EnhancedChatHandler) completePlanning(ctx context.Context, prompt string) (*llm.Completion, error) {
+	if !h.hasRealLLMIntegration() {
+		text, err := h.generateIntelligentMockResponse(prompt)
 		if err != nil {
-			return "", err
-		}
-		defer resp.Body.Close()
-
-		var result struct {
-			Response string `json:"response"`
+			return nil, err
 		}
-		json.NewDecoder(resp.Body).Decode(&result)
-		return result.Response, nil
-	*/
-
-	return h.generateIntelligentMockResponse(prompt)
+		return &llm.Completion{Text: text}, nil
+	}
+	completer, err := h.planningCompleter()
+	if err != nil {
+		return nil, err
+	}
+	if completer == nil {
+		return nil, fmt.Errorf("unsupported LLM provider: %s", h.config.LLM.Provider)
+	}
+	if closer, ok := completer.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return completer.Complete(ctx, planningSystemPrompt, prompt)
 }
 
 // generateIntelligentMockResponse creates context-aware mock responses
@@ -506,12 +669,27 @@ func (h *EnhancedChatHandler) HandleEnhancedChat(c *gin.Context) {
 	if req.Profile == "" {
 		req.Profile = h.defaultProfile
 	}
+	configuredPersona := ""
+	if h.config != nil {
+		configuredPersona = h.config.MCP.ResponsePersona
+	}
+	persona, err := resolvePersona(req.Persona, configuredPersona, req.Profile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Persona = persona
+	if _, err := mcpserver.ParseTimePreferences(req.Timezone, req.TimeFormat); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	logrus.WithFields(logrus.Fields{
 		"prompt":      req.Prompt,
 		"max_steps":   req.MaxSteps,
 		"interactive": req.Interactive,
 		"profile":     req.Profile,
+		"persona":     req.Persona,
 	}).Debug("Processing enhanced chat request")
 
 	// Execute the request with iterative capability
@@ -525,57 +703,135 @@ func (h *EnhancedChatHandler) HandleEnhancedChat(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// newPlanID returns a random identifier for one execution plan
+func newPlanID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("plan-%d", time.Now().UnixNano())
+	}
+	return "plan-" + hex.EncodeToString(id)
+}
+
 // executeIterativeQuery executes a query with iterative capability like Claude Desktop
 func (h *EnhancedChatHandler) executeIterativeQuery(ctx context.Context, req EnhancedChatRequest) (*EnhancedChatResponse, error) {
+	planID := newPlanID()
 	response := &EnhancedChatResponse{
 		Steps:     make([]ExecutionStep, 0),
 		Timestamp: time.Now(),
 		Metadata: map[string]interface{}{
 			"profile":     req.Profile,
+			"persona":     req.Persona,
 			"max_steps":   req.MaxSteps,
 			"interactive": req.Interactive,
+			"plan_id":     planID,
 		},
 	}
+	if req.SessionID != "" {
+		response.Metadata["session_id"] = req.SessionID
+	}
+
+	// Changes made by the plan's tools carry its session and plan IDs
+	ctx = mcpserver.WithActionContext(ctx, mcpserver.ActionContext{SessionID: req.SessionID, PlanID: planID})
+	if prefs, err := mcpserver.ParseTimePreferences(req.Timezone, req.TimeFormat); err == nil {
+		ctx = mcpserver.WithTimePreferences(ctx, prefs)
+	}
+	if h.server != nil {
+		var finish func()
+		ctx, finish = h.server.ProfileQuery(ctx, req.Prompt)
+		defer finish()
+		h.server.RecordQuery(ctx)
+	}
 
 	// Parse the initial query to determine the execution plan
-	executionPlan, err := h.planExecution(req.Prompt)
+	planStart := time.Now()
+	executionPlan, err := h.planExecution(ctx, req.Prompt)
+	mcpserver.RecordPhase(ctx, mcpserver.PhaseLLM, time.Since(planStart))
 	if err != nil {
 		return nil, fmt.Errorf("failed to plan execution: %w", err)
 	}
 
+	// Keep the exchange for transcript export once the plan has run
+	if req.SessionID != "" && h.sessions != nil {
+		defer func() {
+			h.sessions.record(req.SessionID, SessionExchange{
+				PlanID:    planID,
+				Prompt:    req.Prompt,
+				Profile:   req.Profile,
+				Persona:   req.Persona,
+				Plan:      executionPlan,
+				Steps:     response.Steps,
+				Response:  response.Response,
+				Completed: response.Completed,
+				Timestamp: response.Timestamp,
+			})
+		}()
+	}
+
 	// Execute the plan step by step
+	failed := false
 	for i, step := range executionPlan.Steps {
 		if i >= req.MaxSteps {
 			response.Response += fmt.Sprintf("\n⚠️  Maximum steps (%d) reached. Execution truncated.", req.MaxSteps)
 			break
 		}
 
-		executionStep := h.executeStep(ctx, i+1, step)
+		executionStep := h.executeStep(ctx, planID, i+1, step)
 		response.Steps = append(response.Steps, executionStep)
 
 		// Check if we should continue based on the step result
 		if !executionStep.Success {
-			response.Response += fmt.Sprintf("\n❌ Step %d failed: %s", i+1, executionStep.Error)
+			hint := stepFailureHint(executionStep.ErrorKind)
+			if !step.Required && executionStep.ErrorKind != "" {
+				// Optional steps that the cluster refused don't stop the plan
+				response.Response += fmt.Sprintf("\n⚠️  Step %d skipped (%s): %s%s", i+1, executionStep.ErrorKind, executionStep.Error, hint)
+				continue
+			}
+			response.Response += fmt.Sprintf("\n❌ Step %d failed: %s%s", i+1, executionStep.Error, hint)
 			response.Completed = false
-			return response, nil
+			failed = true
+			break
 		}
 
 		// Add step result to overall response
 		response.Response += fmt.Sprintf("\n📋 Step %d: %s", i+1, executionStep.Result)
 	}
 
-	// Generate final summary
-	response.Response = h.generateSummary(executionPlan, response.Steps) + response.Response
-	response.Completed = true
+	if !failed {
+		// Generate final summary
+		response.Response = h.generateSummary(executionPlan, response.Steps) + response.Response
+		response.Completed = true
 
-	// Generate next suggestion if applicable
-	if req.Interactive {
-		response.NextSuggestion = h.generateNextSuggestion(executionPlan, response.Steps)
+		// Generate next suggestion if applicable
+		if req.Interactive {
+			response.NextSuggestion = h.generateNextSuggestion(executionPlan, response.Steps)
+		}
 	}
 
+	// Tailor the response text to its reader; the steps stay as executed
+	response.Response = h.applyPersona(req.Persona, executionPlan, response)
+
 	return response, nil
 }
 
+// stepFailureHint suggests what to do about a step that failed with kind
+func stepFailureHint(kind string) string {
+	switch kind {
+	case mcpserver.ErrorNotFound:
+		return "\n💡 Check the resource name and namespace"
+	case mcpserver.ErrorForbidden:
+		return "\n💡 The server's identity lacks permission; request_elevation shows the Role/RoleBinding that would grant it"
+	case mcpserver.ErrorConflict:
+		return "\n💡 The resource changed or is busy; read it again before retrying"
+	case mcpserver.ErrorTimeout:
+		return "\n💡 The call ran out of time; retry with a narrower scope"
+	case mcpserver.ErrorClusterUnavailable:
+		return "\n💡 The cluster could not be reached; check server_status and retry later"
+	case mcpserver.ErrorInvalidArgument:
+		return "\n💡 Rephrase the request with valid parameters"
+	}
+	return ""
+}
+
 // ExecutionPlan represents a plan for executing a complex query
 type ExecutionPlan struct {
 	Query       string        `json:"query"`
@@ -595,9 +851,9 @@ type PlannedStep struct {
 }
 
 // planExecution creates an execution plan for a given query
-func (h *EnhancedChatHandler) planExecution(query string) (*ExecutionPlan, error) {
+func (h *EnhancedChatHandler) planExecution(ctx context.Context, query string) (*ExecutionPlan, error) {
 	// Try LLM-powered planning first, fallback to static patterns
-	plan, err := h.planWithLLM(query)
+	plan, err := h.planWithLLM(ctx, query)
 	if err == nil {
 		logrus.Debugf("LLM planning succeeded for query: %s", query)
 		return plan, nil
@@ -609,18 +865,18 @@ func (h *EnhancedChatHandler) planExecution(query string) (*ExecutionPlan, error
 }
 
 // planWithLLM uses LLM to generate intelligent execution plans
-func (h *EnhancedChatHandler) planWithLLM(query string) (*ExecutionPlan, error) {
+func (h *EnhancedChatHandler) planWithLLM(ctx context.Context, query string) (*ExecutionPlan, error) {
 	// Create a prompt for the LLM to generate execution plan
 	prompt := h.buildPlanningPrompt(query)
 
-	// Call your LLM service (you'll need to implement this)
-	llmResponse, err := h.callLLMForPlanning(prompt)
+	completion, err := h.completePlanning(ctx, prompt)
 	if err != nil {
 		return nil, err
 	}
+	h.recordLLMUsage(ctx, prompt, completion)
 
 	// Parse the LLM response into an ExecutionPlan
-	plan, err := h.parseLLMPlanResponseWithQuery(query, llmResponse)
+	plan, err := h.parseLLMPlanResponseWithQuery(query, completion.Text)
 	if err != nil {
 		return nil, err
 	}
@@ -628,6 +884,17 @@ func (h *EnhancedChatHandler) planWithLLM(query string) (*ExecutionPlan, error)
 	return plan, nil
 }
 
+// isResourceUsageQuery reports whether a query asks about CPU or memory
+// consumption, which the metrics API answers with real numbers
+func isResourceUsageQuery(queryLower string) bool {
+	for _, keyword := range []string{"cpu", "memory", "resource usage", "slow", "performance", "consum", "top "} {
+		if strings.Contains(queryLower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
 // planWithStaticPatterns - the existing static pattern matching logic
 func (h *EnhancedChatHandler) planWithStaticPatterns(query string) (*ExecutionPlan, error) {
 	plan := &ExecutionPlan{
@@ -695,6 +962,28 @@ func (h *EnhancedChatHandler) planWithStaticPatterns(query string) (*ExecutionPl
 				Required:    true,
 			},
 		}
+	} else if isResourceUsageQuery(queryLower) {
+		plan.Description = "Measure CPU and memory usage"
+		plan.Category = "performance"
+		plan.Complexity = "low"
+		plan.Steps = []PlannedStep{
+			{
+				Action:      "top_pods",
+				Tool:        "top_pods",
+				Parameters:  map[string]interface{}{"namespace": namespace, "sort_by": "cpu"},
+				Description: fmt.Sprintf("Show the actual CPU and memory usage of pods in %s namespace", namespace),
+				Required:    true,
+			},
+		}
+		if strings.Contains(queryLower, "node") || strings.Contains(queryLower, "cluster") {
+			plan.Steps = append(plan.Steps, PlannedStep{
+				Action:      "top_nodes",
+				Tool:        "top_nodes",
+				Parameters:  map[string]interface{}{"sort_by": "cpu"},
+				Description: "Show the actual CPU and memory usage of nodes",
+				Required:    false,
+			})
+		}
 	} else if strings.Contains(queryLower, "pod") {
 		plan.Description = "List and analyze pods"
 		plan.Category = "exploration"
@@ -753,13 +1042,23 @@ func (h *EnhancedChatHandler) planWithStaticPatterns(query string) (*ExecutionPl
 	return plan, nil
 }
 
+// humanApprovalTools record a person's decision. The planner never offers
+// them and chat plans may not run them: an approver named by the model is no
+// approval at all.
+var humanApprovalTools = map[string]bool{
+	"approve_remediation":          true,
+	"auto_remediation_kill_switch": true,
+	"grant_elevation":              true,
+}
+
 // executeStep executes a single step using the appropriate MCP tool
-func (h *EnhancedChatHandler) executeStep(ctx context.Context, stepNumber int, step PlannedStep) ExecutionStep {
+func (h *EnhancedChatHandler) executeStep(ctx context.Context, planID string, stepNumber int, step PlannedStep) ExecutionStep {
 	start := time.Now()
 
 	logrus.Debugf("Executing step %d: %s using tool %s", stepNumber, step.Action, step.Tool)
 
 	executionStep := ExecutionStep{
+		StepID:     fmt.Sprintf("%s-step-%d", planID, stepNumber),
 		StepNumber: stepNumber,
 		Action:     step.Action,
 		ToolUsed:   step.Tool,
@@ -767,6 +1066,13 @@ func (h *EnhancedChatHandler) executeStep(ctx context.Context, stepNumber int, s
 		Timestamp:  start,
 	}
 
+	if humanApprovalTools[step.Tool] {
+		executionStep.Success = false
+		executionStep.Error = fmt.Sprintf("%s needs a person's approval; call it directly, not from a chat plan", step.Tool)
+		executionStep.Result = fmt.Sprintf("Failed to execute %s: %s", step.Tool, executionStep.Error)
+		return executionStep
+	}
+
 	// Create MCP tool call request
 	callRequest := mcp.CallToolRequest{
 		Params: mcp.CallToolParams{
@@ -778,9 +1084,9 @@ func (h *EnhancedChatHandler) executeStep(ctx context.Context, stepNumber int, s
 	logrus.Debugf("About to call MCP tool: %s with params: %v", step.Tool, step.Parameters)
 
 	// Execute the tool (this would need to be implemented to call the actual MCP tools)
-	result, err := h.callMCPTool(ctx, callRequest)
+	result, toolErr, err := h.callMCPToolResult(ctx, callRequest)
 
-	logrus.Debugf("MCP tool call completed for step %d: success=%v, error=%v", stepNumber, err == nil, err)
+	logrus.Debugf("MCP tool call completed for step %d: success=%v, error=%v", stepNumber, err == nil && toolErr == nil, err)
 
 	executionStep.Duration = time.Since(start)
 
@@ -788,30 +1094,48 @@ func (h *EnhancedChatHandler) executeStep(ctx context.Context, stepNumber int, s
 		executionStep.Success = false
 		executionStep.Error = err.Error()
 		executionStep.Result = fmt.Sprintf("Failed to execute %s: %s", step.Tool, err.Error())
+	} else if toolErr != nil {
+		// The tool answered with a failure the plan can branch on
+		executionStep.Success = false
+		executionStep.Error = toolErr.Message
+		executionStep.ErrorKind = toolErr.Kind
+		executionStep.Retryable = toolErr.Retryable
+		executionStep.Result = result
 	} else {
 		executionStep.Success = true
 		executionStep.Result = result
+		if len(result) > maxStepResultChars && h.server != nil {
+			// Keep the full output so the UI and LLM can page through it
+			h.server.StoreStepOutput(executionStep.StepID, step.Tool, result)
+			kept, shown, total := truncateStepResult(result, maxStepResultChars)
+			executionStep.Result = kept + fmt.Sprintf("\n... output truncated: showing lines 1-%d of %d; use get_step_output step_id=%s offset=%d for more",
+				shown, total, executionStep.StepID, shown)
+			executionStep.Truncated = true
+			executionStep.TotalLines = total
+		}
 	}
 
 	return executionStep
 }
 
-// callMCPTool calls an MCP tool using the dynamic tool execution pattern (like Claude)
-func (h *EnhancedChatHandler) callMCPTool(ctx context.Context, request mcp.CallToolRequest) (string, error) {
-	// Use the same dynamic tool calling approach as Claude Desktop
-	// This leverages the existing MCP infrastructure without hardcoded switch statements!
-
+// callMCPToolResult calls an MCP tool and also returns the kind of failure
+// when the tool reports one
+func (h *EnhancedChatHandler) callMCPToolResult(ctx context.Context, request mcp.CallToolRequest) (string, *mcpserver.ToolErrorInfo, error) {
 	logrus.Debugf("Dynamically calling MCP tool: %s with params: %v", request.Params.Name, request.Params.Arguments)
 
-	// Use the MCP handler to execute the tool - this is the same mechanism Claude uses
-	handler := NewMCPHandler(h.server)
-	result, err := handler.executeTool(ctx, request)
-
+	result, toolErr, err := NewMCPHandler(h.server).executeToolResult(ctx, request)
 	if err != nil {
-		return "", fmt.Errorf("MCP tool execution failed: %w", err)
+		return "", nil, fmt.Errorf("MCP tool execution failed: %w", err)
 	}
+	return result, toolErr, nil
+}
 
-	return result, nil
+// callMCPTool calls an MCP tool using the dynamic tool execution pattern (like Claude)
+func (h *EnhancedChatHandler) callMCPTool(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	// Use the same dynamic tool calling approach as Claude Desktop
+	// This leverages the existing MCP infrastructure without hardcoded switch statements!
+	result, _, err := h.callMCPToolResult(ctx, request)
+	return result, err
 }
 
 // extractTextFromMCPResult extracts text content from MCP result
@@ -859,6 +1183,8 @@ func (h *EnhancedChatHandler) generateNextSuggestion(plan *ExecutionPlan, steps
 		return "Would you like me to help fix the identified issues or provide more detailed diagnostics?"
 	} else if plan.Category == "operator_check" {
 		return "Would you like me to check the operator logs or installation status?"
+	} else if plan.Category == "performance" {
+		return "Would you like me to compare the heaviest pods with their requests and limits, or check their autoscalers?"
 	}
 	return "Would you like me to explore other aspects of your cluster?"
 }
@@ -877,5 +1203,40 @@ func (h *EnhancedChatHandler) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/v1")
 	{
 		api.POST("/chat/enhanced", h.HandleEnhancedChat)
+		api.GET("/chat/steps/:step_id/output", h.HandleStepOutput)
+		api.GET("/chat/sessions/:session_id/transcript", h.HandleGetTranscript)
+		api.POST("/chat/sessions/:session_id/export", h.HandleExportTranscript)
+		api.POST("/chat/sessions/:session_id/resolve", h.HandleResolveSession)
+		api.GET("/wizard/tree", h.HandleWizardTree)
+		api.POST("/wizard/sessions", h.HandleWizardStart)
+		api.GET("/wizard/sessions/:session_id", h.HandleWizardSession)
+		api.POST("/wizard/sessions/:session_id/answer", h.HandleWizardAnswer)
+		api.GET("/war-rooms/:room_id/stream", h.HandleWarRoomStream)
+		api.POST("/analysis/export", h.HandleAnalysisExport)
+	}
+}
+
+// HandleStepOutput pages through the full output of a truncated step
+func (h *EnhancedChatHandler) HandleStepOutput(c *gin.Context) {
+	if h.server == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MCP server not available"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative number"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(mcpserver.DefaultStepOutputLines)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+
+	page, ok := h.server.StepOutput(c.Param("step_id"), offset, limit)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no stored output for this step"})
+		return
 	}
+	c.JSON(http.StatusOK, page)
 }
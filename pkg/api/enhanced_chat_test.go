@@ -1,9 +1,14 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
 )
 
 func TestLLMIntegration(t *testing.T) {
@@ -158,6 +163,31 @@ func TestLLMFallbackBehavior(t *testing.T) {
 	}
 }
 
+func TestResourceUsagePlan(t *testing.T) {
+	handler := &EnhancedChatHandler{}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"which pods use the most memory in shop namespace", []string{"top_pods"}},
+		{"why is the cluster slow, check node cpu", []string{"top_pods", "top_nodes"}},
+	}
+	for _, tt := range tests {
+		plan, err := handler.planWithStaticPatterns(tt.query)
+		if err != nil {
+			t.Fatalf("planWithStaticPatterns(%q) error = %v", tt.query, err)
+		}
+		var tools []string
+		for _, step := range plan.Steps {
+			tools = append(tools, step.Tool)
+		}
+		if plan.Category != "performance" || strings.Join(tools, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("planWithStaticPatterns(%q) = %s %v, expected performance %v", tt.query, plan.Category, tools, tt.expected)
+		}
+	}
+}
+
 func TestIntelligentMockResponse(t *testing.T) {
 	handler := &EnhancedChatHandler{}
 
@@ -197,3 +227,212 @@ func TestIntelligentMockResponse(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncateStepResult(t *testing.T) {
+	tests := []struct {
+		result   string
+		limit    int
+		expected string
+		shown    int
+		total    int
+	}{
+		{"a\nb\nc", 10, "a\nb\nc", 3, 3},
+		{"line1\nline2\nline3", 13, "line1\nline2", 2, 3},
+		{"abcdefghij\nk", 4, "abcd", 1, 2},
+	}
+
+	for _, tt := range tests {
+		kept, shown, total := truncateStepResult(tt.result, tt.limit)
+		if kept != tt.expected || shown != tt.shown || total != tt.total {
+			t.Errorf("truncateStepResult(%q, %d) = %q, %d, %d, expected %q, %d, %d", tt.result, tt.limit, kept, shown, total, tt.expected, tt.shown, tt.total)
+		}
+	}
+}
+
+func TestExecuteStepRejectsApprovalTools(t *testing.T) {
+	handler := &EnhancedChatHandler{}
+	for tool := range humanApprovalTools {
+		step := handler.executeStep(context.Background(), "plan", 1, PlannedStep{
+			Tool:       tool,
+			Parameters: map[string]interface{}{"approval_id": "a1", "approved_by": "sre"},
+		})
+		if step.Success || !strings.Contains(step.Error, "needs a person's approval") {
+			t.Errorf("%s: expected the step to be rejected, got success=%v error=%q", tool, step.Success, step.Error)
+		}
+	}
+
+	prompt := handler.buildPlanningPrompt("approve the pending remediation")
+	for tool := range humanApprovalTools {
+		if strings.Contains(prompt, "\n"+tool+" - ") {
+			t.Errorf("planner offers %s", tool)
+		}
+	}
+}
+
+func TestPlanWithOpenAI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "gpt-4o" || len(request.Messages) != 2 || request.Messages[0].Content != planningSystemPrompt {
+			t.Errorf("planning request = %+v (%v)", request, err)
+		}
+		plan := `{"description": "List the pods", "category": "exploration", "steps": [{"action": "List pods", "tool": "list_pods", "parameters": {"namespace": "shop"}, "required": true}]}`
+		content, _ := json.Marshal(plan)
+		w.Write([]byte(`{"model": "gpt-4o-2024-08-06", "choices": [{"message": {"role": "assistant", "content": ` + string(content) + `}}], "usage": {"prompt_tokens": 900, "completion_tokens": 40}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.LLM.Provider = "openai"
+	cfg.LLM.OpenAI = config.OpenAIConfig{APIKey: "sk-test", BaseURL: server.URL, Model: "gpt-4o"}
+	handler := &EnhancedChatHandler{config: cfg}
+
+	completion, err := handler.completePlanning(context.Background(), "list the pods in shop")
+	if err != nil || completion.InputTokens != 900 || completion.OutputTokens != 40 {
+		t.Fatalf("completePlanning() = %+v, %v", completion, err)
+	}
+	plan, err := handler.planWithLLM(context.Background(), "list the pods in shop")
+	if err != nil || plan.Category != "exploration" || len(plan.Steps) != 1 || plan.Steps[0].Tool != "list_pods" {
+		t.Errorf("planWithLLM() = %+v, %v", plan, err)
+	}
+}
+
+func TestPlanWithClaude(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Model    string `json:"model"`
+			System   string `json:"system"`
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "claude-sonnet-4-5" || request.System != planningSystemPrompt || len(request.Messages) != 1 {
+			t.Errorf("planning request = %+v (%v)", request, err)
+		}
+		plan := `{"description": "List the pods", "category": "exploration", "steps": [{"action": "List pods", "tool": "list_pods", "parameters": {"namespace": "shop"}, "required": true}]}`
+		content, _ := json.Marshal(plan)
+		w.Write([]byte(`{"model": "claude-sonnet-4-5-20250929", "content": [{"type": "text", "text": ` + string(content) + `}], "usage": {"input_tokens": 950, "output_tokens": 45}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.LLM.Provider = "claude"
+	cfg.LLM.Claude = config.ClaudeConfig{APIKey: "sk-ant-test", BaseURL: server.URL, Model: "claude-sonnet-4-5"}
+	handler := &EnhancedChatHandler{config: cfg}
+
+	completion, err := handler.completePlanning(context.Background(), "list the pods in shop")
+	if err != nil || completion.InputTokens != 950 || completion.OutputTokens != 45 {
+		t.Fatalf("completePlanning() = %+v, %v", completion, err)
+	}
+	plan, err := handler.planWithLLM(context.Background(), "list the pods in shop")
+	if err != nil || plan.Category != "exploration" || len(plan.Steps) != 1 || plan.Steps[0].Tool != "list_pods" {
+		t.Errorf("planWithLLM() = %+v, %v", plan, err)
+	}
+	if text, err := handler.callLLMForPlanningReal("list the pods in shop"); err != nil || !strings.Contains(text, "list_pods") {
+		t.Errorf("callLLMForPlanningReal() = %q, %v", text, err)
+	}
+}
+
+func TestPlanWithGemini(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			GenerationConfig struct {
+				ResponseMimeType string `json:"responseMimeType"`
+			} `json:"generationConfig"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.GenerationConfig.ResponseMimeType != "application/json" {
+			t.Errorf("planning request = %+v (%v)", request, err)
+		}
+		plan := `{"description": "List the pods", "category": "exploration", "steps": [{"action": "List pods", "tool": "list_pods", "parameters": {"namespace": "shop"}, "required": true}]}`
+		content, _ := json.Marshal(plan)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": ` + string(content) + `}]}}], "usageMetadata": {"promptTokenCount": 880, "candidatesTokenCount": 42}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.LLM.Provider = "gemini"
+	cfg.LLM.Gemini = config.GeminiConfig{APIKey: "gemini-test", BaseURL: server.URL, Model: "gemini-2.0-flash-001"}
+	handler := &EnhancedChatHandler{config: cfg}
+
+	completion, err := handler.completePlanning(context.Background(), "list the pods in shop")
+	if err != nil || completion.InputTokens != 880 || completion.OutputTokens != 42 {
+		t.Fatalf("completePlanning() = %+v, %v", completion, err)
+	}
+	plan, err := handler.planWithLLM(context.Background(), "list the pods in shop")
+	if err != nil || plan.Category != "exploration" || len(plan.Steps) != 1 || plan.Steps[0].Tool != "list_pods" {
+		t.Errorf("planWithLLM() = %+v, %v", plan, err)
+	}
+	if text, err := handler.callLLMForPlanningReal("list the pods in shop"); err != nil || !strings.Contains(text, "list_pods") {
+		t.Errorf("callLLMForPlanningReal() = %q, %v", text, err)
+	}
+}
+
+func TestPlanWithOllama(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Model  string `json:"model"`
+			Format string `json:"format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || r.URL.Path != "/api/chat" || request.Model != "qwen2.5:7b" || request.Format != "json" {
+			t.Errorf("planning request to %s = %+v (%v)", r.URL.Path, request, err)
+		}
+		plan := `{"description": "List the pods", "category": "exploration", "steps": [{"action": "List pods", "tool": "list_pods", "parameters": {"namespace": "shop"}, "required": true}]}`
+		content, _ := json.Marshal(plan)
+		w.Write([]byte(`{"model": "qwen2.5:7b", "message": {"role": "assistant", "content": ` + string(content) + `}, "done": true, "prompt_eval_count": 870, "eval_count": 44}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.LLM.Provider = "ollama"
+	cfg.LLM.Ollama = config.OllamaConfig{Endpoint: server.URL, Model: "qwen2.5:7b"}
+	handler := &EnhancedChatHandler{config: cfg}
+
+	completion, err := handler.completePlanning(context.Background(), "list the pods in shop")
+	if err != nil || completion.InputTokens != 870 || completion.OutputTokens != 44 {
+		t.Fatalf("completePlanning() = %+v, %v", completion, err)
+	}
+	plan, err := handler.planWithLLM(context.Background(), "list the pods in shop")
+	if err != nil || plan.Category != "exploration" || len(plan.Steps) != 1 || plan.Steps[0].Tool != "list_pods" {
+		t.Errorf("planWithLLM() = %+v, %v", plan, err)
+	}
+	if text, err := handler.callLLMForPlanningReal("list the pods in shop"); err != nil || !strings.Contains(text, "list_pods") {
+		t.Errorf("callLLMForPlanningReal() = %q, %v", text, err)
+	}
+}
+
+func TestPlanWithOpenAICompatible(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Model string `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || r.URL.Path != "/v1/chat/completions" || request.Model != "meta-llama/Llama-3.1-8B-Instruct" {
+			t.Errorf("planning request to %s = %+v (%v)", r.URL.Path, request, err)
+		}
+		plan := `{"description": "List the pods", "category": "exploration", "steps": [{"action": "List pods", "tool": "list_pods", "parameters": {"namespace": "shop"}, "required": true}]}`
+		content, _ := json.Marshal(plan)
+		w.Write([]byte(`{"model": "meta-llama/Llama-3.1-8B-Instruct", "choices": [{"message": {"role": "assistant", "content": ` + string(content) + `}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.LLM.Provider = "openai-compatible"
+	cfg.LLM.OpenAICompatible = config.OpenAIConfig{BaseURL: server.URL + "/v1", Model: "meta-llama/Llama-3.1-8B-Instruct"}
+	handler := &EnhancedChatHandler{config: cfg}
+
+	if !handler.hasRealLLMIntegration() {
+		t.Fatal("hasRealLLMIntegration() = false for a configured OpenAI-compatible server")
+	}
+	plan, err := handler.planWithLLM(context.Background(), "list the pods in shop")
+	if err != nil || plan.Category != "exploration" || len(plan.Steps) != 1 || plan.Steps[0].Tool != "list_pods" {
+		t.Errorf("planWithLLM() = %+v, %v", plan, err)
+	}
+	if text, err := handler.callLLMForPlanningReal("list the pods in shop"); err != nil || !strings.Contains(text, "list_pods") {
+		t.Errorf("callLLMForPlanningReal() = %q, %v", text, err)
+	}
+}
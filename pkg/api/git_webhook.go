@@ -0,0 +1,341 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// gitValidationTimeout bounds the validation of one push or pull request
+	gitValidationTimeout = 5 * time.Minute
+
+	// maxGitWebhookBody bounds the webhook payloads read
+	maxGitWebhookBody = 5 << 20
+
+	// maxQueuedGitValidations bounds the refs waiting for validation
+	maxQueuedGitValidations = 20
+)
+
+// gitValidationQueue validates webhook changes one at a time, so a burst of
+// pushes cannot run unbounded validations at once. A change waiting for
+// validation is replaced by a newer change to the same ref or pull request,
+// whose result is the only one the provider will show.
+type gitValidationQueue struct {
+	mu       sync.Mutex
+	pending  map[string]mcpserver.GitChange
+	order    []string
+	limit    int
+	wake     chan struct{}
+	validate func(context.Context, mcpserver.GitChange) error
+}
+
+// newGitValidationQueue starts the worker that runs validate
+func newGitValidationQueue(limit int, validate func(context.Context, mcpserver.GitChange) error) *gitValidationQueue {
+	q := &gitValidationQueue{
+		pending:  make(map[string]mcpserver.GitChange),
+		limit:    limit,
+		wake:     make(chan struct{}, 1),
+		validate: validate,
+	}
+	go q.run()
+	return q
+}
+
+func gitValidationKey(change mcpserver.GitChange) string {
+	return fmt.Sprintf("%s#%d", change.Ref, change.PullRequest)
+}
+
+// enqueue queues a change, returning the commit it replaced, if any, and
+// false when the queue is full
+func (q *gitValidationQueue) enqueue(change mcpserver.GitChange) (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := gitValidationKey(change)
+	if waiting, ok := q.pending[key]; ok {
+		q.pending[key] = change
+		return waiting.Commit, true
+	}
+	if len(q.order) >= q.limit {
+		return "", false
+	}
+	q.pending[key] = change
+	q.order = append(q.order, key)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return "", true
+}
+
+// next takes the oldest waiting change
+func (q *gitValidationQueue) next() (mcpserver.GitChange, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.order) == 0 {
+		return mcpserver.GitChange{}, false
+	}
+	key := q.order[0]
+	q.order = q.order[1:]
+	change := q.pending[key]
+	delete(q.pending, key)
+	return change, true
+}
+
+func (q *gitValidationQueue) run() {
+	for range q.wake {
+		for change, ok := q.next(); ok; change, ok = q.next() {
+			ctx, cancel := context.WithTimeout(context.Background(), gitValidationTimeout)
+			if err := q.validate(ctx, change); err != nil {
+				logrus.WithError(err).Warnf("Failed to validate Git change %s", change.Commit)
+			}
+			cancel()
+		}
+	}
+}
+
+// gitCommitFiles is the per-commit file list of GitHub, Gitea and GitLab push payloads
+type gitCommitFiles struct {
+	Added    []string `json:"added"`
+	Modified []string `json:"modified"`
+}
+
+// gitPushPayload is the push payload of GitHub, Gitea and GitLab
+type gitPushPayload struct {
+	Ref     string           `json:"ref"`
+	Before  string           `json:"before"`
+	After   string           `json:"after"`
+	Commits []gitCommitFiles `json:"commits"`
+}
+
+// change converts a push, leaving out removed files
+func (p gitPushPayload) change() (*mcpserver.GitChange, string) {
+	if p.After == "" || strings.Trim(p.After, "0") == "" {
+		return nil, "branch deleted"
+	}
+	seen := make(map[string]bool)
+	var paths []string
+	for _, commit := range p.Commits {
+		for _, file := range append(commit.Added, commit.Modified...) {
+			if !seen[file] {
+				seen[file] = true
+				paths = append(paths, file)
+			}
+		}
+	}
+	return &mcpserver.GitChange{Commit: p.After, Before: p.Before, Ref: p.Ref, Paths: paths}, ""
+}
+
+// parseGitWebhook converts a push or pull request webhook from GitHub,
+// GitLab, Gitea or Bitbucket into the change to validate. Other events
+// return the reason they are ignored.
+func parseGitWebhook(header http.Header, body []byte) (*mcpserver.GitChange, string, error) {
+	switch {
+	case header.Get("X-Gitea-Event") != "" || header.Get("X-GitHub-Event") != "":
+		event := header.Get("X-Gitea-Event")
+		if event == "" {
+			event = header.Get("X-GitHub-Event")
+		}
+		switch event {
+		case "push":
+			var push gitPushPayload
+			if err := json.Unmarshal(body, &push); err != nil {
+				return nil, "", err
+			}
+			change, ignored := push.change()
+			return change, ignored, nil
+		case "pull_request":
+			var payload struct {
+				Action      string `json:"action"`
+				Number      int    `json:"number"`
+				PullRequest struct {
+					Head struct {
+						SHA string `json:"sha"`
+						Ref string `json:"ref"`
+					} `json:"head"`
+				} `json:"pull_request"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, "", err
+			}
+			switch payload.Action {
+			case "opened", "reopened", "synchronize", "synchronized":
+				return &mcpserver.GitChange{Commit: payload.PullRequest.Head.SHA, Ref: payload.PullRequest.Head.Ref, PullRequest: payload.Number}, "", nil
+			}
+			return nil, "pull request " + payload.Action, nil
+		}
+		return nil, "event " + event, nil
+
+	case header.Get("X-Gitlab-Event") != "":
+		switch event := header.Get("X-Gitlab-Event"); event {
+		case "Push Hook":
+			var push gitPushPayload
+			if err := json.Unmarshal(body, &push); err != nil {
+				return nil, "", err
+			}
+			change, ignored := push.change()
+			return change, ignored, nil
+		case "Merge Request Hook":
+			var payload struct {
+				Attributes struct {
+					IID          int    `json:"iid"`
+					Action       string `json:"action"`
+					SourceBranch string `json:"source_branch"`
+					LastCommit   struct {
+						ID string `json:"id"`
+					} `json:"last_commit"`
+				} `json:"object_attributes"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, "", err
+			}
+			attributes := payload.Attributes
+			switch attributes.Action {
+			case "open", "reopen", "update":
+				return &mcpserver.GitChange{Commit: attributes.LastCommit.ID, Ref: attributes.SourceBranch, PullRequest: attributes.IID}, "", nil
+			}
+			return nil, "merge request " + attributes.Action, nil
+		default:
+			return nil, "event " + event, nil
+		}
+
+	case header.Get("X-Event-Key") != "":
+		type bitbucketTarget struct {
+			Target struct {
+				Hash string `json:"hash"`
+			} `json:"target"`
+			Name string `json:"name"`
+		}
+		switch event := header.Get("X-Event-Key"); event {
+		case "repo:push":
+			var payload struct {
+				Push struct {
+					Changes []struct {
+						Old *bitbucketTarget `json:"old"`
+						New *bitbucketTarget `json:"new"`
+					} `json:"changes"`
+				} `json:"push"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, "", err
+			}
+			// Bitbucket lists no files, so the changed paths come from Git
+			for i := len(payload.Push.Changes) - 1; i >= 0; i-- {
+				change := payload.Push.Changes[i]
+				if change.New == nil {
+					continue
+				}
+				gitChange := &mcpserver.GitChange{Commit: change.New.Target.Hash, Ref: change.New.Name}
+				if change.Old != nil {
+					gitChange.Before = change.Old.Target.Hash
+				} else {
+					gitChange.Before = strings.Repeat("0", 40)
+				}
+				return gitChange, "", nil
+			}
+			return nil, "branch deleted", nil
+		case "pullrequest:created", "pullrequest:updated":
+			var payload struct {
+				PullRequest struct {
+					ID     int `json:"id"`
+					Source struct {
+						Branch struct {
+							Name string `json:"name"`
+						} `json:"branch"`
+						Commit struct {
+							Hash string `json:"hash"`
+						} `json:"commit"`
+					} `json:"source"`
+				} `json:"pullrequest"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, "", err
+			}
+			pr := payload.PullRequest
+			return &mcpserver.GitChange{Commit: pr.Source.Commit.Hash, Ref: pr.Source.Branch.Name, PullRequest: pr.ID}, "", nil
+		default:
+			return nil, "event " + event, nil
+		}
+	}
+	return nil, "", fmt.Errorf("not a GitHub, GitLab, Gitea or Bitbucket webhook")
+}
+
+// verifyGitWebhook checks the webhook secret: a shared token for GitLab and
+// an HMAC-SHA256 signature of the body for the other providers
+func verifyGitWebhook(header http.Header, body []byte, secret string) bool {
+	if secret == "" {
+		return true
+	}
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		return hmac.Equal([]byte(token), []byte(secret))
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	signature := header.Get("X-Gitea-Signature")
+	if signature == "" {
+		// GitHub sends X-Hub-Signature-256 and Bitbucket X-Hub-Signature, both sha256=<hex>
+		signature = header.Get("X-Hub-Signature-256")
+		if signature == "" {
+			signature = header.Get("X-Hub-Signature")
+		}
+		var ok bool
+		if signature, ok = strings.CutPrefix(signature, "sha256="); !ok {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// handleGitWebhook validates the manifests changed by a push or pull request
+// and posts the result back to the Git provider. Providers expect a quick
+// response, so validation is queued for a background worker.
+func (s *Server) handleGitWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxGitWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !verifyGitWebhook(c.Request.Header, body, s.config.Git.WebhookSecret) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook signature"})
+		return
+	}
+
+	change, ignored, err := parseGitWebhook(c.Request.Header, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if change == nil {
+		c.JSON(http.StatusOK, gin.H{"ignored": ignored})
+		return
+	}
+
+	replaced, ok := s.gitValidations.enqueue(*change)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "too many changes waiting for validation"})
+		return
+	}
+	response := gin.H{
+		"commit":       change.Commit,
+		"pull_request": change.PullRequest,
+	}
+	if replaced != "" {
+		response["replaced"] = replaced
+	}
+	c.JSON(http.StatusAccepted, response)
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+)
+
+func TestParseGitWebhook(t *testing.T) {
+	sha := "3f2a1b4c5d6e7f8091a2b3c4d5e6f708192a3b4c"
+	zero := strings.Repeat("0", 40)
+	push := `{"ref":"refs/heads/main","before":"aaa","after":"` + sha + `","commits":[` +
+		`{"added":["apps/web.yaml"],"modified":["apps/api.yaml"]},{"added":[],"modified":["apps/web.yaml"],"removed":["old.yaml"]}]}`
+
+	tests := []struct {
+		header      string
+		event       string
+		body        string
+		commit      string
+		paths       string
+		pullRequest int
+		ignored     string
+	}{
+		{"X-GitHub-Event", "push", push, sha, "apps/web.yaml,apps/api.yaml", 0, ""},
+		{"X-GitHub-Event", "push", `{"ref":"refs/heads/old","after":"` + zero + `"}`, "", "", 0, "branch deleted"},
+		{"X-GitHub-Event", "pull_request", `{"action":"synchronize","number":42,"pull_request":{"head":{"sha":"` + sha + `","ref":"fix"}}}`, sha, "", 42, ""},
+		{"X-GitHub-Event", "pull_request", `{"action":"closed","number":42}`, "", "", 0, "pull request closed"},
+		{"X-GitHub-Event", "ping", `{}`, "", "", 0, "event ping"},
+		{"X-Gitea-Event", "pull_request", `{"action":"synchronized","number":7,"pull_request":{"head":{"sha":"` + sha + `"}}}`, sha, "", 7, ""},
+		{"X-Gitlab-Event", "Push Hook", push, sha, "apps/web.yaml,apps/api.yaml", 0, ""},
+		{"X-Gitlab-Event", "Merge Request Hook", `{"object_attributes":{"iid":3,"action":"update","source_branch":"fix","last_commit":{"id":"` + sha + `"}}}`, sha, "", 3, ""},
+		{"X-Gitlab-Event", "Merge Request Hook", `{"object_attributes":{"iid":3,"action":"merge"}}`, "", "", 0, "merge request merge"},
+		{"X-Event-Key", "repo:push", `{"push":{"changes":[{"old":{"target":{"hash":"aaa"}},"new":{"name":"main","target":{"hash":"` + sha + `"}}}]}}`, sha, "", 0, ""},
+		{"X-Event-Key", "repo:push", `{"push":{"changes":[{"old":{"target":{"hash":"aaa"}},"new":null}]}}`, "", "", 0, "branch deleted"},
+		{"X-Event-Key", "pullrequest:updated", `{"pullrequest":{"id":5,"source":{"branch":{"name":"fix"},"commit":{"hash":"3f2a1b4c5d6e"}}}}`, "3f2a1b4c5d6e", "", 5, ""},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set(tt.header, tt.event)
+		change, ignored, err := parseGitWebhook(header, []byte(tt.body))
+		if err != nil {
+			t.Errorf("parseGitWebhook(%s) error = %v", tt.event, err)
+			continue
+		}
+		if ignored != tt.ignored {
+			t.Errorf("parseGitWebhook(%s) ignored = %q, expected %q", tt.event, ignored, tt.ignored)
+		}
+		if tt.ignored != "" {
+			continue
+		}
+		if change.Commit != tt.commit || strings.Join(change.Paths, ",") != tt.paths || change.PullRequest != tt.pullRequest {
+			t.Errorf("parseGitWebhook(%s) = %s %v #%d, expected %s %s #%d", tt.event, change.Commit, change.Paths,
+				change.PullRequest, tt.commit, tt.paths, tt.pullRequest)
+		}
+	}
+
+	if _, _, err := parseGitWebhook(http.Header{}, []byte(push)); err == nil {
+		t.Error("parseGitWebhook without provider headers should fail")
+	}
+}
+
+func TestVerifyGitWebhook(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		header string
+		value  string
+		secret string
+		valid  bool
+	}{
+		{"X-Hub-Signature-256", "sha256=" + signature, "s3cret", true},
+		{"X-Hub-Signature-256", "sha256=" + signature, "other", false},
+		{"X-Hub-Signature-256", signature, "s3cret", false},
+		{"X-Hub-Signature", "sha256=" + signature, "s3cret", true},
+		{"X-Gitea-Signature", signature, "s3cret", true},
+		{"X-Gitlab-Token", "s3cret", "s3cret", true},
+		{"X-Gitlab-Token", "guess", "s3cret", false},
+		{"X-Other", "", "s3cret", false},
+		{"X-Other", "", "", true},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set(tt.header, tt.value)
+		if valid := verifyGitWebhook(header, body, tt.secret); valid != tt.valid {
+			t.Errorf("verifyGitWebhook(%s: %q, secret %q) = %v, expected %v", tt.header, tt.value, tt.secret, valid, tt.valid)
+		}
+	}
+}
+
+func TestGitValidationQueue(t *testing.T) {
+	started := make(chan string)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	var validated []string
+	q := newGitValidationQueue(2, func(ctx context.Context, change mcpserver.GitChange) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		started <- change.Commit
+		<-release
+		mu.Lock()
+		running--
+		validated = append(validated, change.Commit)
+		mu.Unlock()
+		return nil
+	})
+
+	q.enqueue(mcpserver.GitChange{Commit: "a1", Ref: "refs/heads/main"})
+	if commit := <-started; commit != "a1" {
+		t.Fatalf("first validation = %s, expected a1", commit)
+	}
+	// While a1 runs, a2 waits and is replaced by a3; the queue holds two refs
+	q.enqueue(mcpserver.GitChange{Commit: "a2", Ref: "refs/heads/main"})
+	if replaced, ok := q.enqueue(mcpserver.GitChange{Commit: "a3", Ref: "refs/heads/main"}); !ok || replaced != "a2" {
+		t.Errorf("enqueue(a3) = %q, %v, expected a2 replaced", replaced, ok)
+	}
+	q.enqueue(mcpserver.GitChange{Commit: "b1", Ref: "fix", PullRequest: 7})
+	if _, ok := q.enqueue(mcpserver.GitChange{Commit: "c1", Ref: "refs/heads/other"}); ok {
+		t.Error("enqueue beyond the limit was accepted")
+	}
+
+	for _, expected := range []string{"a3", "b1"} {
+		release <- struct{}{}
+		if commit := <-started; commit != expected {
+			t.Errorf("next validation = %s, expected %s", commit, expected)
+		}
+	}
+	release <- struct{}{}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		done := len(validated) == 3
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(validated, ",") != "a1,a3,b1" || maxRunning != 1 {
+		t.Errorf("validated %v with up to %d at once, expected a1,a3,b1 one at a time", validated, maxRunning)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+)
+
+// similarRunbookLimit bounds the learned runbooks offered to the planner
+const similarRunbookLimit = 3
+
+// SessionResolutionRequest confirms that a chat session resolved its
+// incident and names the runbook learned from it
+type SessionResolutionRequest struct {
+	Resolved    bool   `json:"resolved"`              // the user confirms the incident is resolved
+	Name        string `json:"name,omitempty"`        // runbook tool name, derived from the first prompt when empty
+	Description string `json:"description,omitempty"` // what the runbook does
+}
+
+// HandleResolveSession distills the successful steps of a resolved session
+// into a parameterized runbook draft, commits it for review and indexes it
+// so the planner suggests it for similar incidents
+func (h *EnhancedChatHandler) HandleResolveSession(c *gin.Context) {
+	var req SessionResolutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Resolved {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only sessions confirmed resolved are learned from; send resolved=true"})
+		return
+	}
+	if h.server == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MCP server not available"})
+		return
+	}
+	sessionID := c.Param("session_id")
+	transcript, ok := h.sessionTranscript(sessionID)
+	if !ok || len(transcript.Exchanges) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if last := transcript.Exchanges[len(transcript.Exchanges)-1]; !last.Completed {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("the last prompt of the session (plan %s) did not complete", last.PlanID)})
+		return
+	}
+
+	session := mcpserver.LearnedSession{
+		SessionID:   sessionID,
+		Incident:    transcript.Exchanges[0].Prompt,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+	for _, exchange := range transcript.Exchanges {
+		for _, step := range exchange.Steps {
+			if step.Success {
+				session.Steps = append(session.Steps, mcpserver.LearnedStep{Tool: step.ToolUsed, Arguments: step.Parameters})
+			}
+		}
+	}
+	learned, err := h.server.LearnRunbook(c.Request.Context(), session)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"runbook":    learned,
+	})
+}
+
+// learnedRunbookNote tells the planner about runbooks of resolved incidents
+// resembling the query, so it can reuse what worked before
+func (h *EnhancedChatHandler) learnedRunbookNote(query string) string {
+	if h.server == nil {
+		return ""
+	}
+	matches := h.server.SimilarRunbooks(query, similarRunbookLimit)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	note := "\nRUNBOOKS FROM SIMILAR RESOLVED INCIDENTS:\n"
+	for _, match := range matches {
+		incident := match.Incident
+		if incident == "" {
+			incident = match.Description
+		}
+		if match.Draft {
+			note += fmt.Sprintf("- Draft %s (%.0f%% similar) resolved %q with: %s\n", match.Name, match.Score*100, incident, strings.Join(match.Steps, " → "))
+		} else {
+			note += fmt.Sprintf("- Runbook tool %s (%.0f%% similar) resolved %q with: %s\n", match.Name, match.Score*100, incident, strings.Join(match.Steps, " → "))
+		}
+	}
+	note += "- When one fits the query, call its runbook tool, or plan the same steps for a draft with this query's resources\n"
+	return note
+}
@@ -37,24 +37,126 @@ func (h *MCPHandler) GetCapabilities(c *gin.Context) {
 		"tools": []string{
 			"openshift_diagnose",
 			"openshift_must_gather",
+			"must_gather_status",
+			"cancel_must_gather",
 			"openshift_route_analyze",
+			"analyze_route_sharding",
+			"list_routes",
+			"create_route",
+			"list_imagestreams",
+			"trace_image",
+			"image_inventory",
+			"audit_certificates",
+			"probe_control_plane",
+			"check_dns_telemetry",
+			"diagnose_cluster_dns",
+			"check_node_connection_limits",
+			"list_buildconfigs",
+			"start_build",
+			"get_build_logs",
 			"collect_sosreport",
 			"collect_tcpdump",
 			"collect_logs",
 			"analyze_must_gather",
+			"analyze_must_gather_diff",
 			"analyze_logs",
 			"analyze_tcpdump",
+			"list_diagnostics",
 			"list_pods",
+			"list_deployments",
+			"list_statefulsets",
+			"list_daemonsets",
+			"get_pod_logs",
+			"exec_in_pod",
+			"port_forward",
+			"list_nodes",
+			"describe_node",
+			"analyze_node_capacity",
+			"top_pods",
+			"top_nodes",
+			"cordon_node",
+			"uncordon_node",
+			"drain_node",
+			"list_pvcs",
+			"get_pvc",
+			"diagnose_pvc",
+			"list_hpas",
+			"diagnose_hpa",
+			"create_hpa",
+			"list_network_policies",
+			"explain_network_policy",
+			"generate_network_policy",
+			"list_jobs",
+			"list_cronjobs",
+			"trigger_cronjob",
+			"diagnose_job",
+			"list_secrets",
+			"get_secret",
+			"create_secret",
 			"get_resource",
+			"watch_resource",
 			"get_events",
 			"list_namespaces",
 			"helm_list",
 			"create_namespace",
 			"apply_yaml",
+			"patch_resource",
+			"remediate_deployment",
+			"auto_remediate",
+			"list_remediation_approvals",
+			"approve_remediation",
+			"auto_remediation_kill_switch",
+			"onboard_namespace",
+			"clone_namespace",
 			"generate_yaml",
+			"list_catalog",
+			"describe_resource_schema",
+			"generate_custom_resource",
+			"server_status",
+			"self_diagnose",
+			"server_capabilities",
+			"tool_migration_report",
+			"list_tool_sets",
+			"reload_tool_sets",
+			"list_analysis_rules",
+			"reload_analysis_rules",
+			"list_known_issues",
+			"get_step_output",
+			"change_freeze_status",
+			"can_i",
+			"who_can",
+			"audit_rbac",
+			"request_elevation",
+			"grant_elevation",
+			"install_action_record_crd",
+			"list_action_records",
+			"get_cluster_operators",
+			"get_cluster_version",
+			"performance_report",
+			"usage_report",
+			"runtime_stats",
+			"query_metrics",
+			"collect_metrics_snapshot",
+			"collect_alerts",
+			"open_war_room",
+			"war_room_status",
+			"close_war_room",
+			"collect_ovn_diagnostics",
+			"analyze_ovn_diagnostics",
+			"collect_ingress_diagnostics",
+			"analyze_ingress_diagnostics",
+			"collect_storage_diagnostics",
+			"analyze_storage_diagnostics",
 		},
 	}
 
+	// Tools registered at runtime by tool sets are callable too
+	tools := capabilities["tools"].([]string)
+	for _, set := range h.server.ToolSets() {
+		tools = append(tools, set.Tools...)
+	}
+	capabilities["tools"] = tools
+
 	c.JSON(http.StatusOK, capabilities)
 }
 
@@ -86,8 +188,22 @@ func (h *MCPHandler) CallTool(c *gin.Context) {
 		},
 	}
 
+	// Changes made by the tool are recorded against the caller's session
+	ctx := c.Request.Context()
+	if session := c.GetHeader("X-Session-ID"); session != "" {
+		ctx = mcpserver.WithActionContext(ctx, mcpserver.ActionContext{SessionID: session})
+	}
+
+	// Timestamps follow the caller's zone and format unless the arguments set them
+	prefs, err := mcpserver.ParseTimePreferences(c.GetHeader("X-Timezone"), c.GetHeader("X-Time-Format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx = mcpserver.WithTimePreferences(ctx, prefs)
+
 	// Execute the tool call
-	result, err := h.executeTool(c.Request.Context(), callRequest)
+	result, toolErr, err := h.executeToolResult(ctx, callRequest)
 	if err != nil {
 		logrus.WithError(err).Error("Tool execution failed")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -105,7 +221,10 @@ func (h *MCPHandler) CallTool(c *gin.Context) {
 			},
 		},
 		"toolName": request.Params.Name,
-		"isError":  false,
+		"isError":  toolErr != nil,
+	}
+	if toolErr != nil {
+		response["error"] = toolErr
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -113,72 +232,278 @@ func (h *MCPHandler) CallTool(c *gin.Context) {
 
 // Execute tool - simple implementation for testing
 func (h *MCPHandler) executeTool(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	result, _, err := h.executeToolResult(ctx, request)
+	return result, err
+}
+
+// executeToolResult runs a tool and returns its text along with the kind of
+// failure when the call failed
+func (h *MCPHandler) executeToolResult(ctx context.Context, request mcp.CallToolRequest) (string, *mcpserver.ToolErrorInfo, error) {
 	// Use the actual MCP server handlers instead of the limited switch statement
 	result, err := h.callServerTool(ctx, request)
 	if err != nil {
-		return fmt.Sprintf("❌ Error executing tool '%s': %v", request.Params.Name, err), nil
+		return fmt.Sprintf("❌ Error executing tool '%s': %v", request.Params.Name, err), mcpserver.NewToolError(err), nil
 	}
+	toolErr, _ := mcpserver.ResultError(result)
 
 	// Extract text content from the result
 	if result != nil && len(result.Content) > 0 {
 		if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-			return textContent.Text, nil
+			return textContent.Text, toolErr, nil
 		}
 	}
 
-	return fmt.Sprintf("Tool '%s' completed but returned no content", request.Params.Name), nil
+	return fmt.Sprintf("Tool '%s' completed but returned no content", request.Params.Name), toolErr, nil
 }
 
 func (h *MCPHandler) callServerTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Call the appropriate server handler based on the tool name
+	// Tools registered for the active profile are already wrapped with their
+	// timeout and circuit breaker
+	if h.server.HasTool(request.Params.Name) {
+		return h.server.CallTool(ctx, request)
+	}
+
+	// Tools outside the active profile get the same protection when called over HTTP
+	var handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
 	switch request.Params.Name {
 	case "openshift_diagnose":
-		return h.server.OpenShiftDiagnose(ctx, request)
+		handler = h.server.OpenShiftDiagnose
 	case "openshift_must_gather":
-		return h.server.OpenShiftMustGatherHandler(ctx, request)
+		handler = h.server.OpenShiftMustGatherHandler
+	case "must_gather_status":
+		handler = h.server.MustGatherStatusHandler
+	case "cancel_must_gather":
+		handler = h.server.CancelMustGatherHandler
 	case "openshift_route_analyze":
-		return h.server.OpenShiftRouteAnalyzeHandler(ctx, request)
+		handler = h.server.OpenShiftRouteAnalyzeHandler
+	case "analyze_route_sharding":
+		handler = h.server.AnalyzeRouteShardingHandler
+	case "list_routes":
+		handler = h.server.ListRoutesHandler
+	case "create_route":
+		handler = h.server.CreateRouteHandler
+	case "list_imagestreams":
+		handler = h.server.ListImageStreamsHandler
+	case "audit_certificates":
+		handler = h.server.AuditCertificatesHandler
+	case "probe_control_plane":
+		handler = h.server.ProbeControlPlaneHandler
+	case "check_dns_telemetry":
+		handler = h.server.CheckDNSTelemetryHandler
+	case "diagnose_cluster_dns":
+		handler = h.server.DiagnoseClusterDNSHandler
+	case "check_node_connection_limits":
+		handler = h.server.CheckNodeConnectionLimitsHandler
+	case "image_inventory":
+		handler = h.server.ImageInventoryHandler
+	case "trace_image":
+		handler = h.server.TraceImageHandler
+	case "list_buildconfigs":
+		handler = h.server.ListBuildConfigsHandler
+	case "start_build":
+		handler = h.server.StartBuildHandler
+	case "get_build_logs":
+		handler = h.server.GetBuildLogsHandler
 	case "collect_sosreport":
-		return h.server.CollectSosReportHandler(ctx, request)
+		handler = h.server.CollectSosReportHandler
 	case "collect_tcpdump":
-		return h.server.CollectTcpdumpHandler(ctx, request)
+		handler = h.server.CollectTcpdumpHandler
 	case "collect_logs":
-		return h.server.CollectLogsHandler(ctx, request)
+		handler = h.server.CollectLogsHandler
 	case "analyze_must_gather":
-		return h.server.AnalyzeMustGatherHandler(ctx, request)
+		handler = h.server.AnalyzeMustGatherHandler
+	case "analyze_must_gather_diff":
+		handler = h.server.AnalyzeMustGatherDiffHandler
 	case "analyze_logs":
-		return h.server.AnalyzeLogsHandler(ctx, request)
+		handler = h.server.AnalyzeLogsHandler
 	case "analyze_tcpdump":
-		return h.server.AnalyzeTcpdumpHandler(ctx, request)
+		handler = h.server.AnalyzeTcpdumpHandler
+	case "list_diagnostics":
+		handler = h.server.ListDiagnosticsHandler
 	case "list_pods":
-		return h.server.ListPodsHandler(ctx, request)
+		handler = h.server.ListPodsHandler
+	case "list_deployments":
+		handler = h.server.ListDeploymentsHandler
+	case "list_statefulsets":
+		handler = h.server.ListStatefulSetsHandler
+	case "list_daemonsets":
+		handler = h.server.ListDaemonSetsHandler
+	case "get_pod_logs":
+		handler = h.server.GetPodLogsHandler
+	case "exec_in_pod":
+		handler = h.server.ExecInPodHandler
+	case "port_forward":
+		handler = h.server.PortForwardHandler
+	case "list_nodes":
+		handler = h.server.ListNodesHandler
+	case "describe_node":
+		handler = h.server.DescribeNodeHandler
+	case "analyze_node_capacity":
+		handler = h.server.AnalyzeNodeCapacityHandler
+	case "top_pods":
+		handler = h.server.TopPodsHandler
+	case "top_nodes":
+		handler = h.server.TopNodesHandler
+	case "cordon_node":
+		handler = h.server.CordonNodeHandler
+	case "uncordon_node":
+		handler = h.server.UncordonNodeHandler
+	case "drain_node":
+		handler = h.server.DrainNodeHandler
+	case "list_pvcs":
+		handler = h.server.ListPVCsHandler
+	case "get_pvc":
+		handler = h.server.GetPVCHandler
+	case "diagnose_pvc":
+		handler = h.server.DiagnosePVCHandler
+	case "list_hpas":
+		handler = h.server.ListHPAsHandler
+	case "diagnose_hpa":
+		handler = h.server.DiagnoseHPAHandler
+	case "create_hpa":
+		handler = h.server.CreateHPAHandler
+	case "list_network_policies":
+		handler = h.server.ListNetworkPoliciesHandler
+	case "explain_network_policy":
+		handler = h.server.ExplainNetworkPolicyHandler
+	case "generate_network_policy":
+		handler = h.server.GenerateNetworkPolicyHandler
+	case "list_jobs":
+		handler = h.server.ListJobsHandler
+	case "list_cronjobs":
+		handler = h.server.ListCronJobsHandler
+	case "trigger_cronjob":
+		handler = h.server.TriggerCronJobHandler
+	case "diagnose_job":
+		handler = h.server.DiagnoseJobHandler
+	case "list_secrets":
+		handler = h.server.ListSecretsHandler
+	case "get_secret":
+		handler = h.server.GetSecretHandler
+	case "create_secret":
+		handler = h.server.CreateSecretHandler
 	case "get_events":
-		return h.server.GetEventsHandler(ctx, request)
+		handler = h.server.GetEventsHandler
 	case "list_namespaces":
-		return h.server.ListNamespacesHandler(ctx, request)
+		handler = h.server.ListNamespacesHandler
 	case "get_resource":
-		return h.server.GetResourceHandler(ctx, request)
+		handler = h.server.GetResourceHandler
+	case "watch_resource":
+		handler = h.server.WatchResourceHandler
 	case "get_kubeconfig":
-		return h.server.GetKubeconfigHandler(ctx, request)
+		handler = h.server.GetKubeconfigHandler
 	case "helm_list":
-		return h.server.HelmListHandler(ctx, request)
+		handler = h.server.HelmListHandler
 	case "create_namespace":
-		return h.server.CreateNamespaceHandler(ctx, request)
+		handler = h.server.CreateNamespaceHandler
 	case "create_resource":
-		return h.server.CreateResourceHandler(ctx, request)
+		handler = h.server.CreateResourceHandler
 	case "create_configmap":
-		return h.server.CreateConfigMapHandler(ctx, request)
+		handler = h.server.CreateConfigMapHandler
 	case "apply_yaml":
-		return h.server.ApplyYamlHandler(ctx, request)
+		handler = h.server.ApplyYamlHandler
 	case "delete_resource":
-		return h.server.DeleteResourceHandler(ctx, request)
+		handler = h.server.DeleteResourceHandler
+	case "patch_resource":
+		handler = h.server.PatchResourceHandler
+	case "onboard_namespace":
+		handler = h.server.OnboardNamespaceHandler
+	case "clone_namespace":
+		handler = h.server.CloneNamespaceHandler
+	case "remediate_deployment":
+		handler = h.server.RemediateDeploymentHandler
+	case "auto_remediate":
+		handler = h.server.AutoRemediateHandler
+	case "list_remediation_approvals":
+		handler = h.server.ListRemediationApprovalsHandler
+	case "approve_remediation":
+		handler = h.server.ApproveRemediationHandler
+	case "auto_remediation_kill_switch":
+		handler = h.server.AutoRemediationKillSwitchHandler
 	case "scale_deployment":
-		return h.server.ScaleDeploymentHandler(ctx, request)
+		handler = h.server.ScaleDeploymentHandler
 	case "generate_yaml":
-		return h.server.GenerateYamlHandler(ctx, request)
+		handler = h.server.GenerateYamlHandler
+	case "list_catalog":
+		handler = h.server.ListCatalogHandler
+	case "describe_resource_schema":
+		handler = h.server.DescribeResourceSchemaHandler
+	case "generate_custom_resource":
+		handler = h.server.GenerateCustomResourceHandler
+	case "server_status":
+		handler = h.server.ServerStatusHandler
+	case "self_diagnose":
+		handler = h.server.SelfDiagnoseHandler
+	case "server_capabilities":
+		handler = h.server.ServerCapabilitiesHandler
+	case "tool_migration_report":
+		handler = h.server.ToolMigrationReportHandler
+	case "list_tool_sets":
+		handler = h.server.ListToolSetsHandler
+	case "reload_tool_sets":
+		handler = h.server.ReloadToolSetsHandler
+	case "list_analysis_rules":
+		handler = h.server.ListAnalysisRulesHandler
+	case "reload_analysis_rules":
+		handler = h.server.ReloadAnalysisRulesHandler
+	case "list_known_issues":
+		handler = h.server.ListKnownIssuesHandler
+	case "get_step_output":
+		handler = h.server.GetStepOutputHandler
+	case "change_freeze_status":
+		handler = h.server.ChangeFreezeStatusHandler
+	case "can_i":
+		handler = h.server.CanIHandler
+	case "who_can":
+		handler = h.server.WhoCanHandler
+	case "audit_rbac":
+		handler = h.server.AuditRBACHandler
+	case "request_elevation":
+		handler = h.server.RequestElevationHandler
+	case "grant_elevation":
+		handler = h.server.GrantElevationHandler
+	case "install_action_record_crd":
+		handler = h.server.InstallActionRecordCRDHandler
+	case "list_action_records":
+		handler = h.server.ListActionRecordsHandler
+	case "get_cluster_operators":
+		handler = h.server.GetClusterOperatorsHandler
+	case "get_cluster_version":
+		handler = h.server.GetClusterVersionHandler
+	case "performance_report":
+		handler = h.server.PerformanceReportHandler
+	case "usage_report":
+		handler = h.server.UsageReportHandler
+	case "runtime_stats":
+		handler = h.server.RuntimeStatsHandler
+	case "query_metrics":
+		handler = h.server.QueryMetricsHandler
+	case "collect_metrics_snapshot":
+		handler = h.server.CollectMetricsSnapshotHandler
+	case "collect_alerts":
+		handler = h.server.CollectAlertsHandler
+	case "open_war_room":
+		handler = h.server.OpenWarRoomHandler
+	case "war_room_status":
+		handler = h.server.WarRoomStatusHandler
+	case "close_war_room":
+		handler = h.server.CloseWarRoomHandler
+	case "collect_ovn_diagnostics":
+		handler = h.server.CollectOVNDiagnosticsHandler
+	case "analyze_ovn_diagnostics":
+		handler = h.server.AnalyzeOVNDiagnosticsHandler
+	case "collect_ingress_diagnostics":
+		handler = h.server.CollectIngressDiagnosticsHandler
+	case "analyze_ingress_diagnostics":
+		handler = h.server.AnalyzeIngressDiagnosticsHandler
+	case "collect_storage_diagnostics":
+		handler = h.server.CollectStorageDiagnosticsHandler
+	case "analyze_storage_diagnostics":
+		handler = h.server.AnalyzeStorageDiagnosticsHandler
 	default:
-		return nil, fmt.Errorf("tool '%s' is not implemented", request.Params.Name)
+		return h.server.CallTool(ctx, request)
 	}
+	return h.server.CallWithResilience(ctx, request, handler)
 }
 
 func (h *MCPHandler) handleOpenShiftDiagnose(ctx context.Context, request mcp.CallToolRequest) (string, error) {
@@ -203,8 +528,9 @@ func (h *MCPHandler) handleOpenShiftDiagnose(ctx context.Context, request mcp.Ca
 }
 
 func (h *MCPHandler) handlePodsList(ctx context.Context, request mcp.CallToolRequest) (string, error) {
-	// Call the actual MCP server handler directly
-	result, err := h.server.ListPodsHandler(ctx, request)
+	// Call the MCP server handler through its timeout and circuit breaker
+	request.Params.Name = "list_pods"
+	result, err := h.callServerTool(ctx, request)
 	if err != nil {
 		return "", err
 	}
@@ -246,8 +572,9 @@ func (h *MCPHandler) handleEventsList(ctx context.Context, request mcp.CallToolR
 }
 
 func (h *MCPHandler) handleNamespacesList(ctx context.Context, request mcp.CallToolRequest) (string, error) {
-	// Call the actual MCP server handler directly
-	result, err := h.server.ListNamespacesHandler(ctx, request)
+	// Call the MCP server handler through its timeout and circuit breaker
+	request.Params.Name = "list_namespaces"
+	result, err := h.callServerTool(ctx, request)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,467 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Response personas tailor the chat response text to its reader. They only
+// post-process the response; the steps and their results are the same for
+// every persona.
+const (
+	// PersonaDeveloper gets the exact commands and YAML behind the answer
+	PersonaDeveloper = "developer"
+	// PersonaSRE gets the full evidence followed by findings and remediation
+	PersonaSRE = "sre"
+	// PersonaManager gets a short impact-and-status summary
+	PersonaManager = "manager"
+)
+
+// maxManagerIssues bounds the issues listed in a manager summary
+const maxManagerIssues = 3
+
+// resolvePersona picks the persona of a request: the requested one, else the
+// configured default, else the one matching the tool profile
+func resolvePersona(requested, configured, profile string) (string, error) {
+	persona := strings.ToLower(strings.TrimSpace(requested))
+	if persona == "" {
+		persona = strings.ToLower(strings.TrimSpace(configured))
+	}
+	if persona == "" {
+		if profile == PersonaDeveloper {
+			return PersonaDeveloper, nil
+		}
+		return PersonaSRE, nil
+	}
+	switch persona {
+	case PersonaDeveloper, PersonaSRE, PersonaManager:
+		return persona, nil
+	}
+	return "", fmt.Errorf("unsupported persona %q: use developer, sre or manager", persona)
+}
+
+// stepFindings are the problems, warnings and remediation hints reported in
+// step results
+type stepFindings struct {
+	problems []string
+	warnings []string
+	hints    []string
+}
+
+// collectFindings reads the ❌, ⚠️ and 💡 lines the tools write, plus the
+// errors of failed steps, without duplicates
+func collectFindings(steps []ExecutionStep) stepFindings {
+	var findings stepFindings
+	seen := make(map[string]bool)
+	add := func(list *[]string, line string) {
+		if line != "" && !seen[line] {
+			seen[line] = true
+			*list = append(*list, line)
+		}
+	}
+
+	for _, step := range steps {
+		if !step.Success {
+			add(&findings.problems, fmt.Sprintf("Step %d (%s) failed: %s", step.StepNumber, step.ToolUsed, step.Error))
+			continue
+		}
+		for _, line := range strings.Split(step.Result, "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "❌"):
+				add(&findings.problems, strings.TrimSpace(strings.TrimPrefix(line, "❌")))
+			case strings.HasPrefix(line, "⚠️"):
+				add(&findings.warnings, strings.TrimSpace(strings.TrimPrefix(line, "⚠️")))
+			case strings.HasPrefix(line, "💡"):
+				add(&findings.hints, strings.TrimSpace(strings.TrimPrefix(line, "💡")))
+			case strings.HasPrefix(line, "🔧 Fix:"):
+				add(&findings.hints, strings.TrimSpace(strings.TrimPrefix(line, "🔧 Fix:")))
+			}
+		}
+	}
+	return findings
+}
+
+// applyPersona rewrites a response for its persona from the plan and the
+// executed steps
+func (h *EnhancedChatHandler) applyPersona(persona string, plan *ExecutionPlan, response *EnhancedChatResponse) string {
+	switch persona {
+	case PersonaDeveloper:
+		return h.developerResponse(plan, response)
+	case PersonaManager:
+		return h.managerResponse(plan, response)
+	}
+	return sreResponse(response)
+}
+
+// sreResponse keeps the full step evidence and adds findings and remediation
+func sreResponse(response *EnhancedChatResponse) string {
+	findings := collectFindings(response.Steps)
+	var b strings.Builder
+	b.WriteString(response.Response)
+	if len(findings.problems)+len(findings.warnings) > 0 {
+		b.WriteString("\n\n🔎 Findings\n")
+		for _, problem := range findings.problems {
+			fmt.Fprintf(&b, "❌ %s\n", problem)
+		}
+		for _, warning := range findings.warnings {
+			fmt.Fprintf(&b, "⚠️  %s\n", warning)
+		}
+	}
+	if len(findings.hints) > 0 {
+		b.WriteString("\n🛠️  Remediation\n")
+		for i, hint := range findings.hints {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, hint)
+		}
+	}
+	return b.String()
+}
+
+// developerResponse lists the oc commands equivalent to each step and the
+// YAML the steps used or produced, then what went wrong
+func (h *EnhancedChatHandler) developerResponse(plan *ExecutionPlan, response *EnhancedChatResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🎯 **%s**\n", plan.Description)
+	fmt.Fprintf(&b, "📊 %s\n", stepStatusLine(response.Steps))
+
+	var commands, manifests []string
+	for _, step := range response.Steps {
+		if command := toolCommand(step.ToolUsed, step.Parameters); command != "" {
+			commands = append(commands, command)
+		}
+		if yaml, ok := step.Parameters["yaml"].(string); ok && strings.TrimSpace(yaml) != "" {
+			manifests = append(manifests, strings.TrimSpace(yaml))
+		}
+		if step.Success {
+			manifests = append(manifests, yamlBlocks(step.Result)...)
+		}
+	}
+
+	if len(commands) > 0 {
+		b.WriteString("\n🧑‍💻 Commands\n```bash\n")
+		b.WriteString(strings.Join(commands, "\n"))
+		b.WriteString("\n```\n")
+	}
+	for _, manifest := range manifests {
+		fence := codeFence(manifest)
+		fmt.Fprintf(&b, "\n📄 YAML\n%syaml\n%s\n%s\n", fence, manifest, fence)
+	}
+
+	findings := collectFindings(response.Steps)
+	if len(findings.problems) > 0 {
+		b.WriteString("\n❌ Errors\n")
+		for _, problem := range findings.problems {
+			fmt.Fprintf(&b, "- %s\n", problem)
+		}
+	}
+	if len(findings.hints) > 0 {
+		b.WriteString("\n💡 Next\n")
+		for _, hint := range findings.hints {
+			fmt.Fprintf(&b, "- %s\n", hint)
+		}
+	}
+	return b.String()
+}
+
+// managerResponse summarizes status and impact without raw tool output
+func (h *EnhancedChatHandler) managerResponse(plan *ExecutionPlan, response *EnhancedChatResponse) string {
+	findings := collectFindings(response.Steps)
+	var b strings.Builder
+	fmt.Fprintf(&b, "🎯 **%s**\n\n", plan.Description)
+
+	switch {
+	case len(findings.problems) > 0:
+		b.WriteString("Status: 🔴 Action needed\n")
+	case len(findings.warnings) > 0:
+		b.WriteString("Status: 🟡 Degraded\n")
+	default:
+		b.WriteString("Status: 🟢 Healthy\n")
+	}
+
+	impact := fmt.Sprintf("Impact: %d problem(s), %d warning(s)", len(findings.problems), len(findings.warnings))
+	if namespaces := stepNamespaces(response.Steps); len(namespaces) > 0 {
+		impact += " in " + strings.Join(namespaces, ", ")
+	}
+	b.WriteString(impact + "\n")
+
+	issues := append(append([]string(nil), findings.problems...), findings.warnings...)
+	if len(issues) > 0 {
+		b.WriteString("\nTop issues:\n")
+		for i, issue := range issues {
+			if i == maxManagerIssues {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(issues)-maxManagerIssues)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", issue)
+		}
+	}
+	if len(findings.hints) > 0 {
+		fmt.Fprintf(&b, "\nNext: %d remediation step(s) identified for the engineering team\n", len(findings.hints))
+	}
+	fmt.Fprintf(&b, "\nChecks: %s\n", stepStatusLine(response.Steps))
+	return b.String()
+}
+
+// stepStatusLine reports how many steps succeeded and how long they took
+func stepStatusLine(steps []ExecutionStep) string {
+	successCount := 0
+	var total float64
+	for _, step := range steps {
+		if step.Success {
+			successCount++
+		}
+		total += step.Duration.Seconds()
+	}
+	return fmt.Sprintf("%d/%d steps succeeded in %.1fs", successCount, len(steps), total)
+}
+
+// stepNamespaces returns the namespaces the steps looked at, sorted
+func stepNamespaces(steps []ExecutionStep) []string {
+	seen := make(map[string]bool)
+	for _, step := range steps {
+		if namespace, ok := step.Parameters["namespace"].(string); ok && namespace != "" && namespace != "all" {
+			seen[namespace] = true
+		}
+	}
+	namespaces := make([]string, 0, len(seen))
+	for namespace := range seen {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// yamlBlocks returns the YAML manifests in a tool result: runs of non-blank
+// lines starting at an apiVersion line
+func yamlBlocks(result string) []string {
+	var blocks, current []string
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(result, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(line, "```"):
+			flush()
+		case strings.HasPrefix(line, "apiVersion:"):
+			flush()
+			current = append(current, line)
+		case len(current) > 0:
+			current = append(current, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// toolCommand returns the oc command equivalent to a tool call, or "" when
+// the tool has no direct equivalent
+func toolCommand(tool string, params map[string]interface{}) string {
+	param := func(name string) string {
+		if value, ok := params[name]; ok && value != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return ""
+	}
+	namespaced := func(command string) string {
+		switch namespace := param("namespace"); namespace {
+		case "":
+			return command
+		case "all":
+			return command + " -A"
+		default:
+			return command + " -n " + namespace
+		}
+	}
+	withSelector := func(command string) string {
+		if selector := param("label_selector"); selector != "" {
+			command += " -l " + selector
+		}
+		return namespaced(command)
+	}
+
+	switch tool {
+	case "list_pods":
+		return namespaced("oc get pods")
+	case "list_deployments":
+		return withSelector("oc get deployments")
+	case "list_statefulsets":
+		return withSelector("oc get statefulsets")
+	case "list_daemonsets":
+		return withSelector("oc get daemonsets")
+	case "list_pvcs":
+		return withSelector("oc get pvc")
+	case "list_jobs":
+		return withSelector("oc get jobs")
+	case "list_cronjobs":
+		return withSelector("oc get cronjobs")
+	case "list_routes":
+		return withSelector("oc get routes")
+	case "list_secrets":
+		return withSelector("oc get secrets")
+	case "list_imagestreams":
+		return namespaced("oc get imagestreams")
+	case "list_buildconfigs":
+		return namespaced("oc get buildconfigs")
+	case "list_namespaces":
+		return "oc get namespaces"
+	case "list_nodes":
+		if selector := param("label_selector"); selector != "" {
+			return "oc get nodes -l " + selector
+		}
+		if role := param("role"); role != "" {
+			return "oc get nodes -l node-role.kubernetes.io/" + role
+		}
+		return "oc get nodes"
+	case "top_pods", "top_nodes":
+		command := "oc adm top " + strings.TrimPrefix(tool, "top_")
+		if sortBy := param("sort_by"); sortBy != "" {
+			command += " --sort-by=" + sortBy
+		}
+		if param("containers") == "true" {
+			command += " --containers"
+		}
+		if selector := param("label_selector"); selector != "" {
+			command += " -l " + selector
+		}
+		if tool == "top_nodes" {
+			return command
+		}
+		return namespaced(command)
+	case "watch_resource":
+		target := param("resource_type")
+		if name := param("name"); name != "" {
+			target += "/" + name
+		}
+		var command string
+		switch until := param("until"); {
+		case until == "":
+			command = "oc get " + strings.Replace(target, "/", " ", 1) + " -w"
+		case until == "deleted":
+			command = "oc wait --for=delete " + target
+		case until == "ready" || until == "exists":
+			command = "oc wait --for=condition=Ready " + target
+		case strings.Contains(strings.SplitN(until, "=", 2)[0], "."):
+			key, value, _ := strings.Cut(until, "=")
+			command = fmt.Sprintf("oc wait --for=jsonpath='{.%s}'=%s %s", key, value, target)
+		default:
+			command = "oc wait --for=condition=" + until + " " + target
+		}
+		if seconds := param("timeout_seconds"); seconds != "" && param("until") != "" {
+			command += " --timeout=" + seconds + "s"
+		}
+		return withSelector(command)
+	case "get_events":
+		return namespaced("oc get events --sort-by=.lastTimestamp")
+	case "get_pod_logs":
+		command := "oc logs " + param("pod_name")
+		if container := param("container"); container != "" {
+			command += " -c " + container
+		}
+		if param("previous") == "true" {
+			command += " --previous"
+		}
+		if tail := param("tail_lines"); tail != "" {
+			command += " --tail=" + tail
+		}
+		return namespaced(command)
+	case "exec_in_pod":
+		return namespaced("oc exec "+param("pod_name")) + " -- " + param("command")
+	case "describe_node":
+		return "oc describe node " + param("node_name")
+	case "cordon_node":
+		return "oc adm cordon " + param("node_name")
+	case "uncordon_node":
+		return "oc adm uncordon " + param("node_name")
+	case "drain_node":
+		return "oc adm drain " + param("node_name") + " --ignore-daemonsets --delete-emptydir-data"
+	case "get_pvc", "diagnose_pvc":
+		return namespaced("oc describe pvc " + param("pvc_name"))
+	case "list_hpas":
+		return withSelector("oc get hpa")
+	case "diagnose_hpa":
+		return namespaced("oc describe hpa " + param("hpa_name"))
+	case "list_network_policies":
+		return withSelector("oc get networkpolicy")
+	case "explain_network_policy":
+		return namespaced("oc describe networkpolicy " + param("policy_name"))
+	case "describe_resource_schema":
+		field := param("field")
+		if field == "" {
+			field = "spec"
+		}
+		return "oc explain " + param("resource_type") + "." + field
+	case "create_hpa":
+		kind := strings.ToLower(param("target_kind"))
+		if kind == "" {
+			kind = "deployment"
+		}
+		command := fmt.Sprintf("oc autoscale %s %s", kind, param("target_name"))
+		if replicas := param("min_replicas"); replicas != "" {
+			command += " --min=" + replicas
+		}
+		command += " --max=" + param("max_replicas")
+		if cpu := param("cpu_percent"); cpu != "" {
+			command += " --cpu-percent=" + cpu
+		}
+		return namespaced(command)
+	case "diagnose_job":
+		return namespaced("oc describe job " + param("job_name"))
+	case "trigger_cronjob":
+		return namespaced("oc create job --from=cronjob/" + param("cronjob_name") + " " + param("job_name"))
+	case "openshift_route_analyze":
+		return namespaced("oc describe route " + param("route_name"))
+	case "get_build_logs":
+		return namespaced("oc logs build/" + param("build_name"))
+	case "start_build":
+		return namespaced("oc start-build " + param("buildconfig_name"))
+	case "get_secret":
+		return namespaced("oc describe secret " + param("secret_name"))
+	case "get_resource":
+		return namespaced("oc get " + param("resource_type") + " " + param("name") + " -o yaml")
+	case "patch_resource":
+		patchType := param("patch_type")
+		if patchType == "" {
+			patchType = "strategic"
+		}
+		return namespaced("oc patch "+param("resource_type")+" "+param("resource_name")) + " --type=" + patchType + " -p '" + param("patch") + "'"
+	case "delete_resource":
+		return namespaced("oc delete " + param("resource_type") + " " + param("resource_name"))
+	case "create_namespace":
+		return "oc new-project " + param("namespace_name")
+	case "scale_deployment":
+		return namespaced("oc scale deployment " + param("name") + " --replicas=" + param("replicas"))
+	case "rollback_deployment":
+		return namespaced("oc rollout undo deployment/" + param("deployment_name"))
+	case "apply_yaml", "create_resource":
+		return namespaced("oc apply -f manifest.yaml")
+	case "openshift_must_gather":
+		command := "oc adm must-gather"
+		for _, image := range strings.Split(param("image"), ",") {
+			if image = strings.TrimSpace(image); image != "" {
+				command += " --image=" + image
+			}
+		}
+		for _, flag := range []string{"dest_dir", "node_name", "since", "timeout"} {
+			if value := param(flag); value != "" {
+				command += " --" + strings.ReplaceAll(flag, "_", "-") + "=" + value
+			}
+		}
+		return command
+	case "get_cluster_version":
+		return "oc get clusterversion"
+	case "get_cluster_operators":
+		if name := param("name"); name != "" {
+			return "oc describe clusteroperator " + name
+		}
+		return "oc get clusteroperators"
+	case "can_i":
+		return namespaced("oc auth can-i " + param("verb") + " " + param("resource"))
+	case "who_can":
+		return namespaced("oc adm policy who-can " + param("verb") + " " + param("resource"))
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolvePersona(t *testing.T) {
+	tests := []struct {
+		requested  string
+		configured string
+		profile    string
+		expected   string
+		wantErr    bool
+	}{
+		{"", "", "sre", PersonaSRE, false},
+		{"", "", "developer", PersonaDeveloper, false},
+		{"", "", "admin", PersonaSRE, false},
+		{"", "manager", "developer", PersonaManager, false},
+		{"Developer", "manager", "sre", PersonaDeveloper, false},
+		{"executive", "", "sre", "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := resolvePersona(tt.requested, tt.configured, tt.profile)
+		if result != tt.expected || (err != nil) != tt.wantErr {
+			t.Errorf("resolvePersona(%q, %q, %q) = %q, %v, expected %q", tt.requested, tt.configured, tt.profile, result, err, tt.expected)
+		}
+	}
+}
+
+func TestToolCommand(t *testing.T) {
+	tests := []struct {
+		tool     string
+		params   map[string]interface{}
+		expected string
+	}{
+		{"list_pods", map[string]interface{}{"namespace": "shop"}, "oc get pods -n shop"},
+		{"list_deployments", map[string]interface{}{"namespace": "all", "label_selector": "app=web"}, "oc get deployments -l app=web -A"},
+		{"get_pod_logs", map[string]interface{}{"pod_name": "web-1", "namespace": "shop", "previous": true, "tail_lines": 50}, "oc logs web-1 --previous --tail=50 -n shop"},
+		{"scale_deployment", map[string]interface{}{"name": "web", "namespace": "shop", "replicas": 3}, "oc scale deployment web --replicas=3 -n shop"},
+		{"create_hpa", map[string]interface{}{"target_name": "web", "namespace": "shop", "max_replicas": "5", "cpu_percent": "70"}, "oc autoscale deployment web --max=5 --cpu-percent=70 -n shop"},
+		{"describe_resource_schema", map[string]interface{}{"resource_type": "kafkas", "field": "spec.kafka"}, "oc explain kafkas.spec.kafka"},
+		{"top_pods", map[string]interface{}{"namespace": "shop", "sort_by": "memory", "containers": "true"}, "oc adm top pods --sort-by=memory --containers -n shop"},
+		{"openshift_must_gather", map[string]interface{}{"image": "quay.io/a/mg, quay.io/b/mg", "since": "2h"}, "oc adm must-gather --image=quay.io/a/mg --image=quay.io/b/mg --since=2h"},
+		{"top_nodes", map[string]interface{}{"label_selector": "node-role.kubernetes.io/worker="}, "oc adm top nodes -l node-role.kubernetes.io/worker="},
+		{"watch_resource", map[string]interface{}{"resource_type": "pods", "label_selector": "app=web", "namespace": "shop", "until": "ready", "timeout_seconds": "120"}, "oc wait --for=condition=Ready pods --timeout=120s -l app=web -n shop"},
+		{"watch_resource", map[string]interface{}{"resource_type": "pods", "name": "web-1", "until": "status.phase=Running"}, "oc wait --for=jsonpath='{.status.phase}'=Running pods/web-1"},
+		{"self_diagnose", nil, ""},
+	}
+
+	for _, tt := range tests {
+		if result := toolCommand(tt.tool, tt.params); result != tt.expected {
+			t.Errorf("toolCommand(%q, %v) = %q, expected %q", tt.tool, tt.params, result, tt.expected)
+		}
+	}
+}
+
+func TestYAMLBlocks(t *testing.T) {
+	result := "📄 Generated YAML:\n\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n\n✅ Done"
+	blocks := yamlBlocks(result)
+	if len(blocks) != 1 || blocks[0] != "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app" {
+		t.Errorf("yamlBlocks() = %q", blocks)
+	}
+}
+
+func TestApplyPersona(t *testing.T) {
+	h := &EnhancedChatHandler{}
+	plan := &ExecutionPlan{Description: "Check checkout pods"}
+	response := &EnhancedChatResponse{
+		Response: "🎯 **Check checkout pods**\n📋 Step 1: pod listing",
+		Steps: []ExecutionStep{
+			{StepNumber: 1, ToolUsed: "list_pods", Parameters: map[string]interface{}{"namespace": "shop"}, Success: true, Duration: time.Second,
+				Result: "Pods in shop:\n❌ checkout-1 CrashLoopBackOff\n⚠️  cart-2 restarted 4 times\n💡 Check get_pod_logs previous=true for checkout-1"},
+			{StepNumber: 2, ToolUsed: "get_pod_logs", Parameters: map[string]interface{}{"pod_name": "checkout-1", "namespace": "shop"}, Error: "timeout"},
+		},
+	}
+
+	tests := []struct {
+		persona  string
+		contains []string
+		excludes []string
+	}{
+		{PersonaSRE, []string{
+			"📋 Step 1: pod listing", "🔎 Findings", "❌ checkout-1 CrashLoopBackOff", "❌ Step 2 (get_pod_logs) failed: timeout",
+			"⚠️  cart-2 restarted 4 times", "🛠️  Remediation\n1. Check get_pod_logs previous=true for checkout-1",
+		}, nil},
+		{PersonaDeveloper, []string{
+			"```bash\noc get pods -n shop\noc logs checkout-1 -n shop\n```", "❌ Errors\n- checkout-1 CrashLoopBackOff", "1/2 steps succeeded",
+		}, []string{"Pods in shop:"}},
+		{PersonaManager, []string{
+			"Status: 🔴 Action needed", "Impact: 2 problem(s), 1 warning(s) in shop", "- checkout-1 CrashLoopBackOff",
+			"- cart-2 restarted 4 times", "Next: 1 remediation step(s)",
+		}, []string{"Pods in shop:", "oc get pods", "... and"}},
+	}
+
+	for _, tt := range tests {
+		text := h.applyPersona(tt.persona, plan, response)
+		for _, want := range tt.contains {
+			if !strings.Contains(text, want) {
+				t.Errorf("applyPersona(%q) missing %q:\n%s", tt.persona, want, text)
+			}
+		}
+		for _, unwanted := range tt.excludes {
+			if strings.Contains(text, unwanted) {
+				t.Errorf("applyPersona(%q) contains %q:\n%s", tt.persona, unwanted, text)
+			}
+		}
+	}
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/decision"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/llm"
 	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/memory"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/types"
 	"github.com/sirupsen/logrus"
 )
@@ -29,11 +31,14 @@ type Server struct {
 	llmClient      llm.Client
 	mcpServer      *mcpserver.Server
 	enhancedChat   *EnhancedChatHandler
+	state          store.Store // chat sessions, approvals, job state and locks shared by replicas
+	gitValidations *gitValidationQueue
 }
 
 // ChatRequest represents a chat API request
 type ChatRequest struct {
-	Prompt string `json:"prompt" binding:"required"`
+	Prompt    string `json:"prompt" binding:"required"`
+	SessionID string `json:"session_id,omitempty"`
 }
 
 // ChatResponse represents a chat API response
@@ -76,6 +81,21 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to initialize decision engine: %w", err)
 	}
 
+	state, err := store.New(store.Config{
+		Backend:   cfg.Store.Backend,
+		Address:   cfg.Store.Address,
+		CAFile:    cfg.Store.CAFile,
+		Password:  cfg.Store.Password,
+		DB:        cfg.Store.DB,
+		KeyPrefix: cfg.Store.KeyPrefix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the %s state store: %w", cfg.Store.Backend, err)
+	}
+	if store.Shared(state) {
+		logrus.Infof("Sharing sessions, approvals and job state through the %s store at %s", state.Backend(), cfg.Store.Address)
+	}
+
 	// Set gin mode based on debug setting
 	if !cfg.Debug {
 		gin.SetMode(gin.ReleaseMode)
@@ -90,6 +110,7 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		decisionEngine: decisionEngine,
 		memory:         memStore,
 		llmClient:      llmClient,
+		state:          state,
 	}
 
 	// Initialize MCP server if enabled
@@ -102,14 +123,51 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	// Initialize enhanced chat handler
 	if server.mcpServer != nil {
 		server.enhancedChat = NewEnhancedChatHandler(server.mcpServer, server.config)
+		server.enhancedChat.SetStore(server.state)
+		if llmClient != nil {
+			server.mcpServer.SetLLMProbe(llmLatencyProbe(llmClient))
+		}
+	}
+
+	if cfg.LLM.Provider == "ollama" {
+		checkOllama(cfg.LLM.Ollama)
+	}
+
+	if server.mcpServer != nil && cfg.Git.Enabled {
+		server.gitValidations = newGitValidationQueue(maxQueuedGitValidations, func(ctx context.Context, change mcpserver.GitChange) error {
+			_, err := server.mcpServer.ValidateAndReport(ctx, change)
+			return err
+		})
 	}
 
 	server.setupRoutes()
 	return server, nil
 }
 
+// checkOllama reports at startup when the local Ollama server is down or
+// lacks the configured model, instead of on every planned query
+func checkOllama(cfg config.OllamaConfig) {
+	client, err := llm.NewOllamaClient(cfg)
+	if err != nil {
+		logrus.WithError(err).Error("Invalid Ollama configuration, chat planning falls back to static patterns")
+		return
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.CheckHealth(ctx); err != nil {
+		logrus.WithError(err).Error("Ollama health check failed, chat planning falls back to static patterns")
+		return
+	}
+	logrus.Infof("Planning with Ollama model %s", cfg.Model)
+}
+
 // setupRoutes configures API routes
 func (s *Server) setupRoutes() {
+	// Attribute requests to the team owning their API key
+	s.engine.Use(s.attributeTenant)
+
 	// Health check
 	s.engine.GET("/health", s.handleHealth)
 
@@ -132,6 +190,24 @@ func (s *Server) setupRoutes() {
 		api.GET("/prompts/stats", s.handlePromptStats)
 		api.POST("/prompts/update", s.handleUpdatePrompts)
 		api.GET("/prompts/categories", s.handlePromptCategories)
+
+		// Alertmanager webhook receiver for alert-triggered diagnoses
+		if s.mcpServer != nil {
+			api.POST("/alerts", s.handleAlertWebhook)
+		}
+
+		// Queries, tool calls, actions and LLM spend per team
+		if s.mcpServer != nil {
+			api.GET("/usage", s.handleUsage)
+		}
+
+		// Git provider webhook that validates pushed manifests
+		if s.gitValidations != nil {
+			if s.config.Git.WebhookSecret == "" {
+				logrus.Warn("Git webhook secret not set, /api/v1/git/webhook accepts unsigned requests")
+			}
+			api.POST("/git/webhook", s.handleGitWebhook)
+		}
 	}
 
 	// Enhanced chat routes (with LLM intelligence)
@@ -165,6 +241,24 @@ func (s *Server) Run() error {
 	return s.engine.Run(addr)
 }
 
+// llmLatencyProbe sends a minimal prompt so self_diagnose can time the provider.
+// The client has no context support, so the probe stops waiting when ctx ends.
+func llmLatencyProbe(client llm.Client) mcpserver.LLMProbe {
+	return func(ctx context.Context) error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.GenerateResponse("Reply with OK.")
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // handleHealth handles health check requests
 func (s *Server) handleHealth(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -234,9 +328,10 @@ func (s *Server) handleEnhancedChatDirect(c *gin.Context) {
 
 	// Convert to enhanced chat request
 	enhancedReq := EnhancedChatRequest{
-		Prompt:   req.Prompt,
-		MaxSteps: 10,
-		Profile:  "sre",
+		Prompt:    req.Prompt,
+		MaxSteps:  10,
+		Profile:   "sre",
+		SessionID: req.SessionID,
 	}
 
 	// Execute with enhanced chat handler
@@ -527,14 +622,116 @@ func (s *Server) handlePromptCategories(c *gin.Context) {
 func (s *Server) initializeMCP() error {
 	// Initialize MCP server with simple configuration
 	mcpConfig := &mcpserver.Config{
-		Profile: s.config.MCP.Profile,
-		Debug:   s.config.Debug,
+		Profile:  s.config.MCP.Profile,
+		Debug:    s.config.Debug,
+		ReadOnly: s.config.MCP.ReadOnly,
+
+		ActionEvents:      s.config.MCP.ActionEvents,
+		ActionAnnotations: s.config.MCP.ActionAnnotations,
+		ActionRecords:     s.config.MCP.ActionRecords,
+
+		ChangeFreezeConfigMap: s.config.MCP.ChangeFreezeConfigMap,
+		Resilience: &mcpserver.ResilienceConfig{
+			DefaultTimeout:      s.config.MCP.ToolTimeout,
+			ToolTimeouts:        s.config.MCP.ToolTimeouts,
+			BreakerThreshold:    s.config.MCP.BreakerThreshold,
+			BreakerOpenDuration: s.config.MCP.BreakerOpenDuration,
+		},
+		AnalysisLimits: &diagnostics.AnalysisLimits{
+			MaxLineLength:       s.config.Analysis.MaxLineLength,
+			MaxEvidencePerIssue: s.config.Analysis.MaxEvidencePerIssue,
+			MaxIssues:           s.config.Analysis.MaxIssues,
+			MaxEvidenceBytes:    s.config.Analysis.MaxEvidenceBytes,
+			MaxHeapBytes:        uint64(s.config.Analysis.MaxHeapMB) * 1024 * 1024,
+		},
+		AnalysisTimezone:  s.config.Analysis.Timezone,
+		ClockSkew:         s.config.Analysis.ClockSkew,
+		AnalysisLocales:   s.config.Analysis.Locales,
+		AnalysisRuleDir:   s.config.Analysis.RuleDir,
+		OutputTimezone:    s.config.MCP.OutputTimezone,
+		OutputTimeFormat:  s.config.MCP.OutputTimeFormat,
+		BaselineDir:       s.config.MCP.BaselineDir,
+		TranscriptDir:     s.config.MCP.TranscriptDir,
+		ToolSetDir:        s.config.MCP.ToolSetDir,
+		TenantTemplateDir: s.config.MCP.TenantTemplateDir,
+		Diagnostics: &mcpserver.DiagnosticsConfig{
+			BaseDir:         s.config.Diagnostics.BaseDir,
+			MaxDiskUsage:    s.config.Diagnostics.MaxDiskUsage,
+			TTL:             s.config.Diagnostics.TTL,
+			CleanupInterval: s.config.Diagnostics.CleanupInterval,
+		},
+		Sessions: &mcpserver.SessionConfig{
+			IdleTimeout:  s.config.Sessions.IdleTimeout,
+			ReapInterval: s.config.Sessions.ReapInterval,
+			JobRetention: s.config.Sessions.JobRetention,
+		},
+		LeaderElection: &mcpserver.LeaderElectionConfig{
+			Enabled:       s.config.LeaderElection.Enabled,
+			LeaseName:     s.config.LeaderElection.LeaseName,
+			Namespace:     s.config.LeaderElection.Namespace,
+			LeaseDuration: s.config.LeaderElection.LeaseDuration,
+			RenewDeadline: s.config.LeaderElection.RenewDeadline,
+			RetryPeriod:   s.config.LeaderElection.RetryPeriod,
+		},
+		MustGather: &mcpserver.MustGatherConfig{
+			Mode:           s.config.MustGather.Mode,
+			Namespace:      s.config.MustGather.Namespace,
+			ServiceAccount: s.config.MustGather.ServiceAccount,
+			StorageClass:   s.config.MustGather.StorageClass,
+			StorageSize:    s.config.MustGather.StorageSize,
+		},
+		Inventory: &mcpserver.InventoryConfig{
+			ExportInterval: s.config.Inventory.ExportInterval,
+			ExportDir:      s.config.Inventory.ExportDir,
+			Format:         s.config.Inventory.Format,
+			ClusterName:    s.config.Inventory.ClusterName,
+			Namespaces:     s.config.Inventory.Namespaces,
+			SBOMDir:        s.config.Inventory.SBOMDir,
+			SBOMCommand:    s.config.Inventory.SBOMCommand,
+		},
+		Notifications: notificationConfig(s.config.Notifications),
+		Usage:         usageConfig(s.config.Usage),
+		Monitoring: &mcpserver.MonitoringConfig{
+			QuerierURL:             s.config.Monitoring.QuerierURL,
+			TenancyURL:             s.config.Monitoring.TenancyURL,
+			InsecureSkipVerify:     s.config.Monitoring.InsecureSkipVerify,
+			AlertmanagerURL:        s.config.Monitoring.AlertmanagerURL,
+			AlertmanagerTenancyURL: s.config.Monitoring.AlertmanagerTenancyURL,
+			SnapshotQueries:        s.config.Monitoring.SnapshotQueries,
+		},
+		AutoRemediation: &mcpserver.AutoRemediationConfig{
+			Enabled:             s.config.AutoRemediation.Enabled,
+			Interval:            s.config.AutoRemediation.Interval,
+			Namespaces:          s.config.AutoRemediation.Namespaces,
+			MinConfidence:       s.config.AutoRemediation.MinConfidence,
+			Recipes:             s.config.AutoRemediation.Recipes,
+			MaxMemoryLimit:      s.config.AutoRemediation.MaxMemoryLimit,
+			TimeoutSeconds:      s.config.AutoRemediation.TimeoutSeconds,
+			KillSwitchConfigMap: s.config.AutoRemediation.KillSwitchConfigMap,
+		},
+		GitConfig: &mcpserver.GitConfig{
+			Enabled:       s.config.Git.Enabled,
+			RepoPath:      s.config.Git.RepoPath,
+			RemoteURL:     s.config.Git.RemoteURL,
+			Branch:        s.config.Git.Branch,
+			AutoCommit:    s.config.Git.AutoCommit,
+			AutoPush:      s.config.Git.AutoPush,
+			CommitUser:    s.config.Git.CommitUser,
+			CommitEmail:   s.config.Git.CommitEmail,
+			SyncInterval:  s.config.Git.SyncInterval,
+			PullStrategy:  s.config.Git.PullStrategy,
+			Provider:      s.config.Git.Provider,
+			ProviderURL:   s.config.Git.ProviderURL,
+			ProviderToken: s.config.Git.ProviderToken,
+		},
 	}
 
 	s.mcpServer = mcpserver.NewServer(mcpConfig, s.config.Kubeconfig)
 	if s.mcpServer == nil {
 		return fmt.Errorf("failed to create MCP server")
 	}
+	s.mcpServer.SetStore(s.state)
+	s.mcpServer.StartScheduler(context.Background())
 
 	// Add MCP routes
 	mcpHandler := NewMCPHandler(s.mcpServer)
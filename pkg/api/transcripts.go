@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+const (
+	// maxSessions bounds how many chat sessions are kept for export
+	maxSessions = 100
+	// maxSessionExchanges bounds the prompts kept per session, oldest dropped
+	maxSessionExchanges = 100
+	// sharedSessionTTL is how long an idle session is kept in a shared store,
+	// which expires sessions instead of bounding their number
+	sharedSessionTTL = 24 * time.Hour
+	// sharedStoreTimeout bounds one read-modify-write against a shared store
+	sharedStoreTimeout = 5 * time.Second
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SessionExchange is one prompt of a chat session with its plan, steps and
+// final response
+type SessionExchange struct {
+	PlanID    string          `json:"plan_id"`
+	Prompt    string          `json:"prompt"`
+	Profile   string          `json:"profile"`
+	Persona   string          `json:"persona,omitempty"`
+	Plan      *ExecutionPlan  `json:"plan,omitempty"`
+	Steps     []ExecutionStep `json:"steps"`
+	Response  string          `json:"response"`
+	Completed bool            `json:"completed"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// SessionTranscript is the full record of a chat session
+type SessionTranscript struct {
+	SessionID string            `json:"session_id"`
+	Started   time.Time         `json:"started"`
+	Updated   time.Time         `json:"updated"`
+	Exchanges []SessionExchange `json:"exchanges"`
+}
+
+// sessionLog keeps the exchanges of recent chat sessions, least recently
+// updated evicted first. With a shared store the sessions live there
+// instead, so any replica can continue or export them.
+type sessionLog struct {
+	mu       sync.Mutex
+	sessions map[string]*SessionTranscript
+	order    []string
+	store    store.Store
+}
+
+func chatSessionKey(sessionID string) string {
+	return "chat/session/" + sessionID
+}
+
+// recordShared appends an exchange to a session in the shared store, holding
+// the session's lock so replicas do not drop each other's exchanges
+func (l *sessionLog) recordShared(sessionID string, exchange SessionExchange) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+	release, err := store.Acquire(ctx, l.store, chatSessionKey(sessionID), sharedStoreTimeout)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var session SessionTranscript
+	if ok, err := store.GetJSON(ctx, l.store, chatSessionKey(sessionID), &session); err != nil {
+		return err
+	} else if !ok {
+		session = SessionTranscript{SessionID: sessionID, Started: exchange.Timestamp}
+	}
+	session.Exchanges = append(session.Exchanges, exchange)
+	if len(session.Exchanges) > maxSessionExchanges {
+		session.Exchanges = session.Exchanges[len(session.Exchanges)-maxSessionExchanges:]
+	}
+	session.Updated = exchange.Timestamp
+	return store.SetJSON(ctx, l.store, chatSessionKey(sessionID), session, sharedSessionTTL)
+}
+
+func newSessionLog() *sessionLog {
+	return &sessionLog{sessions: make(map[string]*SessionTranscript)}
+}
+
+func (l *sessionLog) record(sessionID string, exchange SessionExchange) {
+	if store.Shared(l.store) {
+		if err := l.recordShared(sessionID, exchange); err != nil {
+			logrus.WithError(err).Warnf("Failed to store chat session %s", sessionID)
+		}
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	session := l.sessions[sessionID]
+	if session == nil {
+		session = &SessionTranscript{SessionID: sessionID, Started: exchange.Timestamp}
+		l.sessions[sessionID] = session
+	}
+	session.Exchanges = append(session.Exchanges, exchange)
+	if len(session.Exchanges) > maxSessionExchanges {
+		session.Exchanges = session.Exchanges[len(session.Exchanges)-maxSessionExchanges:]
+	}
+	session.Updated = exchange.Timestamp
+
+	for i, id := range l.order {
+		if id == sessionID {
+			l.order = append(l.order[:i], l.order[i+1:]...)
+			break
+		}
+	}
+	l.order = append(l.order, sessionID)
+	for len(l.order) > maxSessions {
+		delete(l.sessions, l.order[0])
+		l.order = l.order[1:]
+	}
+}
+
+func (l *sessionLog) get(sessionID string) (SessionTranscript, bool) {
+	if store.Shared(l.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		var transcript SessionTranscript
+		ok, err := store.GetJSON(ctx, l.store, chatSessionKey(sessionID), &transcript)
+		if err != nil {
+			logrus.WithError(err).Warnf("Failed to read chat session %s", sessionID)
+		}
+		return transcript, ok
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	session, ok := l.sessions[sessionID]
+	if !ok {
+		return SessionTranscript{}, false
+	}
+	transcript := *session
+	transcript.Exchanges = make([]SessionExchange, len(session.Exchanges))
+	for i, exchange := range session.Exchanges {
+		exchange.Steps = append([]ExecutionStep(nil), exchange.Steps...)
+		transcript.Exchanges[i] = exchange
+	}
+	return transcript, true
+}
+
+// sessionTranscript returns a session with truncated step results replaced
+// by their full output where it is still stored
+func (h *EnhancedChatHandler) sessionTranscript(sessionID string) (SessionTranscript, bool) {
+	if h.sessions == nil {
+		return SessionTranscript{}, false
+	}
+	transcript, ok := h.sessions.get(sessionID)
+	if !ok {
+		return transcript, false
+	}
+	if h.server != nil {
+		for i := range transcript.Exchanges {
+			for j := range transcript.Exchanges[i].Steps {
+				step := &transcript.Exchanges[i].Steps[j]
+				if !step.Truncated {
+					continue
+				}
+				if page, ok := h.server.StepOutput(step.StepID, 0, step.TotalLines); ok {
+					step.Result = page.Output
+					step.Truncated = false
+				}
+			}
+		}
+	}
+	return transcript, true
+}
+
+// codeFence returns a backtick fence longer than any run in content
+func codeFence(content string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	return fence
+}
+
+// renderTranscriptMarkdown renders a session for incident documentation
+func renderTranscriptMarkdown(transcript SessionTranscript) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Chat session %s\n\n", transcript.SessionID)
+	fmt.Fprintf(&b, "- Started: %s\n", transcript.Started.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Last activity: %s\n", transcript.Updated.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Prompts: %d\n", len(transcript.Exchanges))
+
+	for i, exchange := range transcript.Exchanges {
+		fmt.Fprintf(&b, "\n## %d. %s\n\n", i+1, strings.SplitN(strings.TrimSpace(exchange.Prompt), "\n", 2)[0])
+		fmt.Fprintf(&b, "- Time: %s\n", exchange.Timestamp.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&b, "- Plan ID: %s\n", exchange.PlanID)
+		if exchange.Profile != "" {
+			fmt.Fprintf(&b, "- Profile: %s\n", exchange.Profile)
+		}
+		if exchange.Persona != "" {
+			fmt.Fprintf(&b, "- Persona: %s\n", exchange.Persona)
+		}
+		fmt.Fprintf(&b, "- Completed: %v\n", exchange.Completed)
+		fmt.Fprintf(&b, "\n**Prompt**\n\n%s\n", quoteMarkdown(exchange.Prompt))
+
+		if exchange.Plan != nil {
+			fmt.Fprintf(&b, "\n### Plan: %s\n\n", exchange.Plan.Description)
+			if exchange.Plan.Category != "" {
+				fmt.Fprintf(&b, "Category: %s, complexity: %s\n\n", exchange.Plan.Category, exchange.Plan.Complexity)
+			}
+			for j, step := range exchange.Plan.Steps {
+				fmt.Fprintf(&b, "%d. `%s` - %s\n", j+1, step.Tool, step.Description)
+			}
+		}
+
+		if len(exchange.Steps) > 0 {
+			b.WriteString("\n### Steps\n")
+		}
+		for _, step := range exchange.Steps {
+			status := "✅"
+			if !step.Success {
+				status = "❌"
+			}
+			fmt.Fprintf(&b, "\n#### Step %d: %s (`%s`) %s %s\n\n", step.StepNumber, step.Action, step.ToolUsed, status, step.Duration.Round(time.Millisecond))
+			if len(step.Parameters) > 0 {
+				params, _ := json.Marshal(step.Parameters)
+				fmt.Fprintf(&b, "Parameters: `%s`\n\n", params)
+			}
+			if step.Error != "" {
+				fmt.Fprintf(&b, "Error: %s\n\n", step.Error)
+			}
+			fence := codeFence(step.Result)
+			fmt.Fprintf(&b, "%stext\n%s\n%s\n", fence, strings.TrimRight(step.Result, "\n"), fence)
+		}
+
+		if strings.TrimSpace(exchange.Response) != "" {
+			fmt.Fprintf(&b, "\n### Summary\n\n%s\n", strings.TrimSpace(exchange.Response))
+		}
+	}
+	return b.String()
+}
+
+// quoteMarkdown renders text as a Markdown block quote
+func quoteMarkdown(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTranscript renders a session as markdown or json and returns the
+// content with its file extension and content type
+func renderTranscript(transcript SessionTranscript, format string) (string, string, string, error) {
+	switch strings.ToLower(format) {
+	case "", "markdown", "md":
+		return renderTranscriptMarkdown(transcript), "md", "text/markdown; charset=utf-8", nil
+	case "json":
+		data, err := json.MarshalIndent(transcript, "", "  ")
+		if err != nil {
+			return "", "", "", err
+		}
+		return string(data) + "\n", "json", "application/json", nil
+	}
+	return "", "", "", fmt.Errorf("unsupported format %q: use markdown or json", format)
+}
+
+// HandleGetTranscript returns a session transcript as Markdown or JSON
+func (h *EnhancedChatHandler) HandleGetTranscript(c *gin.Context) {
+	transcript, ok := h.sessionTranscript(c.Param("session_id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	content, _, contentType, err := renderTranscript(transcript, c.DefaultQuery("format", "markdown"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, []byte(content))
+}
+
+// TranscriptExportRequest selects the format of an exported transcript and
+// whether to commit it to the Git repository
+type TranscriptExportRequest struct {
+	Format string `json:"format,omitempty"` // markdown (default) or json
+	Commit bool   `json:"commit,omitempty"`
+}
+
+// HandleExportTranscript stores a session transcript as an artifact and
+// optionally commits it under transcripts/ in the Git repository
+func (h *EnhancedChatHandler) HandleExportTranscript(c *gin.Context) {
+	var req TranscriptExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if h.server == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MCP server not available"})
+		return
+	}
+	sessionID := c.Param("session_id")
+	transcript, ok := h.sessionTranscript(sessionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	content, ext, _, err := renderTranscript(transcript, req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(sessionID, "-"), "-.")
+	if name == "" {
+		name = "session"
+	}
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), ext)
+	artifact, err := h.server.SaveTranscript(c.Request.Context(), filename, content, req.Commit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"prompts":    len(transcript.Exchanges),
+		"artifact":   artifact,
+	})
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionLogEviction(t *testing.T) {
+	log := newSessionLog()
+	start := time.Now()
+	for i := 0; i < maxSessions+1; i++ {
+		log.record(fmt.Sprintf("session-%d", i), SessionExchange{Prompt: "q", Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+	// Touching the oldest session keeps it; the next oldest is evicted instead
+	log.record("session-1", SessionExchange{Prompt: "again", Timestamp: start.Add(time.Hour)})
+	log.record("session-new", SessionExchange{Prompt: "q", Timestamp: start.Add(2 * time.Hour)})
+
+	tests := []struct {
+		sessionID string
+		expected  bool
+	}{
+		{"session-0", false},
+		{"session-1", true},
+		{"session-2", false},
+		{"session-3", true},
+		{"session-new", true},
+	}
+	for _, tt := range tests {
+		if _, ok := log.get(tt.sessionID); ok != tt.expected {
+			t.Errorf("get(%q) found = %v, expected %v", tt.sessionID, ok, tt.expected)
+		}
+	}
+
+	session, _ := log.get("session-1")
+	if len(session.Exchanges) != 2 || session.Exchanges[1].Prompt != "again" || !session.Updated.Equal(start.Add(time.Hour)) {
+		t.Errorf("get(session-1) = %+v", session)
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"plain output", "```"},
+		{"has ``` fence", "````"},
+		{"has ```` longer fence", "`````"},
+	}
+	for _, tt := range tests {
+		if result := codeFence(tt.content); result != tt.expected {
+			t.Errorf("codeFence(%q) = %q, expected %q", tt.content, result, tt.expected)
+		}
+	}
+}
+
+func TestRenderTranscript(t *testing.T) {
+	transcript := SessionTranscript{
+		SessionID: "incident-42",
+		Exchanges: []SessionExchange{{
+			PlanID:  "plan-1",
+			Prompt:  "why is checkout crashing?",
+			Profile: "sre",
+			Plan: &ExecutionPlan{Description: "Pod diagnosis", Steps: []PlannedStep{
+				{Tool: "list_pods", Description: "List pods"},
+			}},
+			Steps: []ExecutionStep{
+				{StepNumber: 1, Action: "list pods", ToolUsed: "list_pods", Success: true, Result: "checkout-1 CrashLoopBackOff"},
+				{StepNumber: 2, Action: "get logs", ToolUsed: "get_pod_logs", Error: "pod not found"},
+			},
+			Response:  "checkout is crash looping",
+			Completed: true,
+		}},
+	}
+
+	content, ext, _, err := renderTranscript(transcript, "markdown")
+	if err != nil || ext != "md" {
+		t.Fatalf("renderTranscript(markdown) = %q, %v", ext, err)
+	}
+	for _, want := range []string{
+		"# Chat session incident-42",
+		"## 1. why is checkout crashing?",
+		"> why is checkout crashing?",
+		"1. `list_pods` - List pods",
+		"#### Step 1: list pods (`list_pods`) ✅",
+		"```text\ncheckout-1 CrashLoopBackOff\n```",
+		"#### Step 2: get logs (`get_pod_logs`) ❌",
+		"Error: pod not found",
+		"### Summary\n\ncheckout is crash looping",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("markdown transcript missing %q:\n%s", want, content)
+		}
+	}
+
+	content, ext, _, err = renderTranscript(transcript, "json")
+	if err != nil || ext != "json" || !strings.Contains(content, `"session_id": "incident-42"`) {
+		t.Errorf("renderTranscript(json) = %q, %q, %v", content, ext, err)
+	}
+	if _, _, _, err := renderTranscript(transcript, "pdf"); err == nil {
+		t.Errorf("renderTranscript(pdf) expected an error")
+	}
+}
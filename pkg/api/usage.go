@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/llm"
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+)
+
+// apiKeyHeader carries the API key requests are attributed by, unless they
+// send it as a bearer token
+const apiKeyHeader = "X-API-Key"
+
+// usageConfig converts the usage settings for the MCP server
+func usageConfig(cfg config.UsageConfig) *mcpserver.UsageConfig {
+	prices := make(map[string]mcpserver.LLMPrice, len(cfg.LLMPrices))
+	for name, price := range cfg.LLMPrices {
+		prices[strings.ToLower(name)] = mcpserver.LLMPrice{Input: price.Input, Output: price.Output}
+	}
+	return &mcpserver.UsageConfig{
+		TeamAPIKeys: cfg.TeamAPIKeys,
+		LLMPrices:   prices,
+	}
+}
+
+// requestAPIKey returns the API key a request presents, if any
+func requestAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader(apiKeyHeader)); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return ""
+}
+
+// attributeTenant attributes a request, and the tool and LLM calls it makes,
+// to the team owning its API key
+func (s *Server) attributeTenant(c *gin.Context) {
+	if key := requestAPIKey(c); key != "" && s.mcpServer != nil {
+		c.Request = c.Request.WithContext(mcpserver.WithTenant(c.Request.Context(), s.mcpServer.TenantForKey(key)))
+	}
+	c.Next()
+}
+
+// handleUsage reports the queries, tool calls, actions and LLM spend per
+// team, optionally for one team (?tenant=) and with ?limit= top operations
+func (s *Server) handleUsage(c *gin.Context) {
+	limit := 5
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = parsed
+	}
+	filter := strings.TrimSpace(c.Query("tenant"))
+
+	tenants := []mcpserver.TenantUsage{}
+	var cost float64
+	for _, tenant := range s.mcpServer.Usage(limit) {
+		if filter == "" || strings.EqualFold(tenant.Tenant, filter) {
+			tenants = append(tenants, tenant)
+			cost += tenant.LLMCost
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"tenants":      tenants,
+		"llm_cost_usd": cost,
+	})
+}
+
+// llmModel returns the configured provider and model of planning calls
+func (h *EnhancedChatHandler) llmModel() (string, string) {
+	if h.config == nil {
+		return os.Getenv("LLM_PROVIDER"), ""
+	}
+	provider := h.config.LLM.Provider
+	switch provider {
+	case "openai":
+		return provider, h.config.LLM.OpenAI.Model
+	case "openai-compatible":
+		return provider, h.config.LLM.OpenAICompatible.Model
+	case "claude":
+		return provider, h.config.LLM.Claude.Model
+	case "gemini":
+		return provider, h.config.LLM.Gemini.Model
+	case "ollama":
+		return provider, h.config.LLM.Ollama.Model
+	}
+	return provider, ""
+}
+
+// estimateTokens approximates the tokens of a text at four characters each,
+// for providers that do not report usage
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// recordLLMUsage counts a planning call against the caller's team, with the
+// tokens the provider reported or else estimated ones; mock responses cost
+// nothing and are not counted
+func (h *EnhancedChatHandler) recordLLMUsage(ctx context.Context, prompt string, completion *llm.Completion) {
+	if h.server == nil || !h.hasRealLLMIntegration() {
+		return
+	}
+	provider, model := h.llmModel()
+	usage := mcpserver.LLMUsage{
+		Provider:     provider,
+		Model:        model,
+		InputTokens:  completion.InputTokens,
+		OutputTokens: completion.OutputTokens,
+	}
+	if usage.Model == "" {
+		usage.Model = completion.Model // prices are keyed by the configured model, not the dated one served
+	}
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		usage.InputTokens, usage.OutputTokens = estimateTokens(prompt), estimateTokens(completion.Text)
+	}
+	h.server.RecordLLMUsage(ctx, usage)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+)
+
+// warRoomHeartbeat keeps idle war room streams open through proxies
+// between refreshes
+const warRoomHeartbeat = 30 * time.Second
+
+// HandleWarRoomStream streams the updates of a war room as server-sent
+// events, starting with its latest summary, until the room closes or the
+// client goes away
+func (h *EnhancedChatHandler) HandleWarRoomStream(c *gin.Context) {
+	if h.server == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MCP server not available"})
+		return
+	}
+	updates, stop, ok := h.server.SubscribeWarRoom(c.Param("room_id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "war room not open"})
+		return
+	}
+	defer stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	heartbeat := time.NewTicker(warRoomHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("update", update)
+			return update.Status != mcpserver.WarRoomClosed
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"time": time.Now()})
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
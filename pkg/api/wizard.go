@@ -0,0 +1,555 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/llm"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+const (
+	// wizardRoot is the first node of every wizard session
+	wizardRoot = "start"
+	// maxWizardSessions bounds the wizard sessions kept, oldest dropped
+	maxWizardSessions = 100
+	// wizardSessionTTL is how long an idle wizard session is kept
+	wizardSessionTTL = 2 * time.Hour
+)
+
+// WizardOption is one answer to a wizard question and the node it leads to
+type WizardOption struct {
+	Answer string `json:"answer"`
+	Label  string `json:"label"`
+	Next   string `json:"next"`
+}
+
+// WizardNode is one node of the troubleshooting decision tree. Reaching a
+// node runs its tool, then either asks its question or, at a leaf, gives
+// the conclusion with the knowledge base's investigation path and causes.
+type WizardNode struct {
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Tool       string            `json:"tool,omitempty"`
+	Inputs     []string          `json:"inputs,omitempty"` // tool parameters collected from the user
+	Params     map[string]string `json:"params,omitempty"` // fixed tool parameters
+	Question   string            `json:"question,omitempty"`
+	Options    []WizardOption    `json:"options,omitempty"`
+	Conclusion string            `json:"conclusion,omitempty"`
+	Pattern    string            `json:"pattern,omitempty"` // troubleshooting pattern heading in the knowledge base
+}
+
+// wizardInputPrompts tells the user what each collected input is
+var wizardInputPrompts = map[string]string{
+	"namespace":  "Namespace of the affected application",
+	"pod_name":   "Name of the failing pod",
+	"image":      "Image the pod cannot pull",
+	"route_name": "Name of the Route",
+	"name":       "Name of the Service",
+	"pvc_name":   "Name of the PersistentVolumeClaim",
+	"node_name":  "Name of the node",
+}
+
+// wizardTree is the decision tree, following the issue patterns of the
+// troubleshooting knowledge base
+var wizardTree = map[string]WizardNode{
+	wizardRoot: {
+		ID: wizardRoot, Title: "Problem area",
+		Question: "What kind of problem are you seeing?",
+		Options: []WizardOption{
+			{Answer: "pod", Label: "A pod is crashing or won't start", Next: "pods"},
+			{Answer: "network", Label: "An application is unreachable", Next: "routes"},
+			{Answer: "storage", Label: "A volume won't bind or mount", Next: "pvcs"},
+			{Answer: "performance", Label: "Something is slow or running out of resources", Next: "nodes"},
+			{Answer: "cluster", Label: "Cluster operators or an upgrade look unhealthy", Next: "operators"},
+		},
+	},
+
+	// Application won't start
+	"pods": {
+		ID: "pods", Title: "Pod status", Tool: "list_pods", Inputs: []string{"namespace"},
+		Question: "What state is the failing pod in?",
+		Options: []WizardOption{
+			{Answer: "crashloop", Label: "CrashLoopBackOff or Error", Next: "crash_logs"},
+			{Answer: "imagepull", Label: "ImagePullBackOff or ErrImagePull", Next: "image_trace"},
+			{Answer: "pending", Label: "Pending", Next: "pending_events"},
+		},
+	},
+	"crash_logs": {
+		ID: "crash_logs", Title: "Previous container logs", Tool: "get_pod_logs", Inputs: []string{"namespace", "pod_name"},
+		Params:   map[string]string{"previous": "true", "tail_lines": "100"},
+		Question: "Do the logs show why the application exited?",
+		Options: []WizardOption{
+			{Answer: "yes", Label: "Yes, an application or configuration error", Next: "crash_app"},
+			{Answer: "no", Label: "No, the logs are empty or unclear", Next: "crash_events"},
+		},
+	},
+	"crash_app": {
+		ID: "crash_app", Title: "Application error",
+		Conclusion: "The container exits on an application error. Fix the configuration, environment variables or secrets the log points at, then restart the pod.",
+		Pattern:    "Application Won't Start",
+	},
+	"crash_events": {
+		ID: "crash_events", Title: "Namespace events", Tool: "get_events", Inputs: []string{"namespace"},
+		Conclusion: "Look for OOMKilled, failed liveness or readiness probes and missing volumes or secrets in the events.",
+		Pattern:    "Application Won't Start",
+	},
+	"image_trace": {
+		ID: "image_trace", Title: "Image trace", Tool: "trace_image", Inputs: []string{"namespace", "image"},
+		Conclusion: "Fix the image reference, the ImageStream import or the failed build the trace points at, then let the pod pull again.",
+	},
+	"pending_events": {
+		ID: "pending_events", Title: "Scheduling events", Tool: "get_events", Inputs: []string{"namespace"},
+		Question: "What do the FailedScheduling events mention?",
+		Options: []WizardOption{
+			{Answer: "volume", Label: "Unbound volumes or volume node affinity", Next: "pvcs"},
+			{Answer: "resources", Label: "Insufficient CPU or memory, taints or node selectors", Next: "node_capacity"},
+		},
+	},
+	"node_capacity": {
+		ID: "node_capacity", Title: "Node capacity", Tool: "analyze_node_capacity", Inputs: []string{"namespace"},
+		Conclusion: "Act on the reason the analysis gives for each Pending or evicted pod: right-size requests, add a toleration, uncordon or add nodes.",
+		Pattern:    "Application Won't Start",
+	},
+
+	// Network connectivity
+	"routes": {
+		ID: "routes", Title: "Routes", Tool: "list_routes", Inputs: []string{"namespace"},
+		Question: "Is the application reached through a Route?",
+		Options: []WizardOption{
+			{Answer: "yes", Label: "Yes, through a Route", Next: "route_analyze"},
+			{Answer: "no", Label: "No, through a Service inside the cluster", Next: "endpoints"},
+		},
+	},
+	"route_analyze": {
+		ID: "route_analyze", Title: "Route analysis", Tool: "openshift_route_analyze", Inputs: []string{"namespace", "route_name"},
+		Conclusion: "Fix the problems the route analysis lists, starting with the backing service endpoints.",
+		Pattern:    "Network Connectivity Issues",
+	},
+	"endpoints": {
+		ID: "endpoints", Title: "Service endpoints", Tool: "get_resource", Inputs: []string{"namespace", "name"},
+		Params:     map[string]string{"resource_type": "endpoints"},
+		Conclusion: "A Service without ready endpoints has a selector that matches no ready pod; otherwise test DNS and NetworkPolicies from a client pod with exec_in_pod.",
+		Pattern:    "Network Connectivity Issues",
+	},
+
+	// Storage
+	"pvcs": {
+		ID: "pvcs", Title: "Claims", Tool: "list_pvcs", Inputs: []string{"namespace"},
+		Question: "Which claim is Pending or failing to mount?",
+		Options: []WizardOption{
+			{Answer: "claim", Label: "I know the claim", Next: "pvc_diagnose"},
+			{Answer: "none", Label: "All claims are Bound", Next: "crash_events"},
+		},
+	},
+	"pvc_diagnose": {
+		ID: "pvc_diagnose", Title: "Claim diagnosis", Tool: "diagnose_pvc", Inputs: []string{"namespace", "pvc_name"},
+		Conclusion: "Fix the binding, provisioning or attach problem the diagnosis reports.",
+		Pattern:    "Storage Problems",
+	},
+
+	// Performance
+	"nodes": {
+		ID: "nodes", Title: "Nodes", Tool: "list_nodes",
+		Question: "Is a node under pressure or short of allocatable resources?",
+		Options: []WizardOption{
+			{Answer: "node", Label: "Yes, a specific node", Next: "node_describe"},
+			{Answer: "app", Label: "No, a single application is slow", Next: "app_pods"},
+		},
+	},
+	"node_describe": {
+		ID: "node_describe", Title: "Node detail", Tool: "describe_node", Inputs: []string{"node_name"},
+		Conclusion: "Relieve the pressure on the node: evict or limit the heaviest pods, or cordon and drain it for maintenance.",
+		Pattern:    "Performance Issues",
+	},
+	"app_pods": {
+		ID: "app_pods", Title: "Application pods", Tool: "list_pods", Inputs: []string{"namespace"},
+		Conclusion: "Compare the pods' restarts and resource requests with their usage in query_metrics, and set limits that match the load.",
+		Pattern:    "Performance Issues",
+	},
+
+	// Cluster health
+	"operators": {
+		ID: "operators", Title: "Cluster operators", Tool: "get_cluster_operators",
+		Params:   map[string]string{"unhealthy_only": "true"},
+		Question: "Is a cluster upgrade in progress?",
+		Options: []WizardOption{
+			{Answer: "yes", Label: "Yes, or one just finished", Next: "upgrade"},
+			{Answer: "no", Label: "No", Next: "nodes"},
+		},
+	},
+	"upgrade": {
+		ID: "upgrade", Title: "Cluster version", Tool: "get_cluster_version",
+		Conclusion: "Follow the operator blocking the upgrade with get_cluster_operators name=<operator>.",
+	},
+}
+
+// WizardInput is a tool parameter the wizard needs from the user
+type WizardInput struct {
+	Name   string `json:"name"`
+	Prompt string `json:"prompt"`
+}
+
+// WizardStep is what the wizard did at a node and what it asks next
+type WizardStep struct {
+	SessionID     string                 `json:"session_id"`
+	NodeID        string                 `json:"node_id"`
+	Title         string                 `json:"title"`
+	Tool          string                 `json:"tool,omitempty"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty"`
+	Result        string                 `json:"result,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+	NeedInputs    []WizardInput          `json:"need_inputs,omitempty"`
+	Question      string                 `json:"question,omitempty"`
+	Options       []WizardOption         `json:"options,omitempty"`
+	Conclusion    string                 `json:"conclusion,omitempty"`
+	Investigation []string               `json:"investigation,omitempty"`
+	LikelyCauses  []string               `json:"likely_causes,omitempty"`
+	Done          bool                   `json:"done"`
+}
+
+// wizardSession is one user's walk through the tree
+type wizardSession struct {
+	mu       sync.Mutex
+	id       string
+	node     string
+	awaiting bool // the node's tool waits for inputs
+	inputs   map[string]string
+	history  []WizardStep
+	created  time.Time
+	updated  time.Time
+}
+
+// wizardSessions keeps the wizard sessions in progress. With a shared store
+// the sessions live there, so each answer may reach a different replica.
+type wizardSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*wizardSession
+	store    store.Store
+}
+
+// wizardState is a wizard session as kept in a shared store
+type wizardState struct {
+	ID       string            `json:"id"`
+	Node     string            `json:"node"`
+	Awaiting bool              `json:"awaiting"`
+	Inputs   map[string]string `json:"inputs"`
+	History  []WizardStep      `json:"history"`
+	Created  time.Time         `json:"created"`
+	Updated  time.Time         `json:"updated"`
+}
+
+func wizardKey(id string) string {
+	return "wizard/" + id
+}
+
+// save writes a session to the shared store; callers hold session.mu
+func (w *wizardSessions) save(session *wizardSession) {
+	if !store.Shared(w.store) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+	state := wizardState{
+		ID:       session.id,
+		Node:     session.node,
+		Awaiting: session.awaiting,
+		Inputs:   session.inputs,
+		History:  session.history,
+		Created:  session.created,
+		Updated:  session.updated,
+	}
+	if err := store.SetJSON(ctx, w.store, wizardKey(session.id), state, wizardSessionTTL); err != nil {
+		logrus.WithError(err).Warnf("Failed to store wizard session %s", session.id)
+	}
+}
+
+// lock serializes answers to one session across replicas
+func (w *wizardSessions) lock(ctx context.Context, id string) (func(), error) {
+	if !store.Shared(w.store) {
+		return func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, sharedStoreTimeout)
+	defer cancel()
+	return store.Acquire(ctx, w.store, wizardKey(id), sharedStoreTimeout)
+}
+
+func newWizardSessions() *wizardSessions {
+	return &wizardSessions{sessions: make(map[string]*wizardSession)}
+}
+
+func (w *wizardSessions) add(session *wizardSession) {
+	if store.Shared(w.store) {
+		w.save(session)
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, existing := range w.sessions {
+		if time.Since(existing.updated) > wizardSessionTTL {
+			delete(w.sessions, id)
+		}
+	}
+	for len(w.sessions) >= maxWizardSessions {
+		oldest := ""
+		for id, existing := range w.sessions {
+			if oldest == "" || existing.created.Before(w.sessions[oldest].created) {
+				oldest = id
+			}
+		}
+		delete(w.sessions, oldest)
+	}
+	w.sessions[session.id] = session
+}
+
+func (w *wizardSessions) get(id string) *wizardSession {
+	if store.Shared(w.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		var state wizardState
+		if ok, err := store.GetJSON(ctx, w.store, wizardKey(id), &state); !ok {
+			if err != nil {
+				logrus.WithError(err).Warnf("Failed to read wizard session %s", id)
+			}
+			return nil
+		}
+		if state.Inputs == nil {
+			state.Inputs = make(map[string]string)
+		}
+		return &wizardSession{
+			id:       state.ID,
+			node:     state.Node,
+			awaiting: state.Awaiting,
+			inputs:   state.Inputs,
+			history:  state.History,
+			created:  state.Created,
+			updated:  state.Updated,
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sessions[id]
+}
+
+// missingInputs lists the node's inputs the session has not collected
+func (s *wizardSession) missingInputs(node WizardNode) []WizardInput {
+	var missing []WizardInput
+	for _, name := range node.Inputs {
+		if strings.TrimSpace(s.inputs[name]) == "" {
+			missing = append(missing, WizardInput{Name: name, Prompt: wizardInputPrompts[name]})
+		}
+	}
+	return missing
+}
+
+// chooseOption finds the option for an answer given by name or 1-based number
+func chooseOption(node WizardNode, answer string) (WizardOption, bool) {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(node.Options) {
+		return node.Options[n-1], true
+	}
+	for _, option := range node.Options {
+		if option.Answer == answer {
+			return option, true
+		}
+	}
+	return WizardOption{}, false
+}
+
+// enterNode moves the session to a node and runs its tool once its inputs
+// are collected
+func (h *EnhancedChatHandler) enterNode(ctx context.Context, session *wizardSession, node WizardNode) WizardStep {
+	session.node = node.ID
+	step := WizardStep{SessionID: session.id, NodeID: node.ID, Title: node.Title}
+	if missing := session.missingInputs(node); len(missing) > 0 {
+		session.awaiting = true
+		step.Tool = node.Tool
+		step.NeedInputs = missing
+		return step
+	}
+	session.awaiting = false
+
+	if node.Tool != "" {
+		params := make(map[string]interface{})
+		for name, value := range node.Params {
+			params[name] = value
+		}
+		for _, name := range node.Inputs {
+			params[name] = session.inputs[name]
+		}
+		step.Tool = node.Tool
+		step.Parameters = params
+		if h.server == nil {
+			step.Error = "MCP server not available"
+		} else {
+			request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: node.Tool, Arguments: params}}
+			result, err := h.callMCPTool(ctx, request)
+			if err != nil {
+				step.Error = err.Error()
+			}
+			step.Result = result
+		}
+	}
+
+	if len(node.Options) > 0 {
+		step.Question = node.Question
+		step.Options = node.Options
+	} else {
+		step.Done = true
+		step.Conclusion = node.Conclusion
+		if node.Pattern != "" {
+			step.Investigation, step.LikelyCauses = llm.NewOpenShiftKnowledgeBase().TroubleshootingSection(node.Pattern)
+		}
+	}
+	session.history = append(session.history, step)
+	return step
+}
+
+// WizardStartRequest starts a wizard session, optionally with inputs such as
+// the namespace already known
+type WizardStartRequest struct {
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// WizardAnswerRequest answers the current question and supplies inputs the
+// wizard asked for
+type WizardAnswerRequest struct {
+	Answer string            `json:"answer,omitempty"`
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// HandleWizardTree returns the troubleshooting decision tree
+func (h *EnhancedChatHandler) HandleWizardTree(c *gin.Context) {
+	ids := make([]string, 0, len(wizardTree))
+	for id := range wizardTree {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	nodes := make([]WizardNode, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, wizardTree[id])
+	}
+	c.JSON(http.StatusOK, gin.H{"root": wizardRoot, "nodes": nodes})
+}
+
+// HandleWizardStart starts a wizard session at the root question
+func (h *EnhancedChatHandler) HandleWizardStart(c *gin.Context) {
+	var req WizardStartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if h.wizards == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "wizard not available"})
+		return
+	}
+
+	now := time.Now()
+	session := &wizardSession{
+		id:      strings.Replace(newPlanID(), "plan-", "wizard-", 1),
+		inputs:  make(map[string]string),
+		created: now,
+		updated: now,
+	}
+	for name, value := range req.Inputs {
+		session.inputs[name] = value
+	}
+	h.wizards.add(session)
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	defer h.wizards.save(session)
+	c.JSON(http.StatusOK, h.enterNode(c.Request.Context(), session, wizardTree[wizardRoot]))
+}
+
+// HandleWizardAnswer moves a wizard session on: it takes the inputs the
+// current node waits for, or follows the answer to the next node
+func (h *EnhancedChatHandler) HandleWizardAnswer(c *gin.Context) {
+	var req WizardAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.wizards != nil {
+		release, err := h.wizards.lock(c.Request.Context(), c.Param("session_id"))
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		defer release()
+	}
+	session := h.wizardSession(c)
+	if session == nil {
+		return
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	defer h.wizards.save(session)
+	session.updated = time.Now()
+	for name, value := range req.Inputs {
+		session.inputs[name] = value
+	}
+
+	node := wizardTree[session.node]
+	if session.awaiting {
+		c.JSON(http.StatusOK, h.enterNode(c.Request.Context(), session, node))
+		return
+	}
+	if len(node.Options) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "the wizard has finished; start a new session"})
+		return
+	}
+	option, ok := chooseOption(node, req.Answer)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    fmt.Sprintf("unknown answer %q", req.Answer),
+			"question": node.Question,
+			"options":  node.Options,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, h.enterNode(c.Request.Context(), session, wizardTree[option.Next]))
+}
+
+// HandleWizardSession returns a wizard session's inputs and the steps taken
+func (h *EnhancedChatHandler) HandleWizardSession(c *gin.Context) {
+	session := h.wizardSession(c)
+	if session == nil {
+		return
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": session.id,
+		"node_id":    session.node,
+		"inputs":     session.inputs,
+		"steps":      session.history,
+		"created":    session.created,
+		"updated":    session.updated,
+	})
+}
+
+// wizardSession looks up the session of a request, answering 404 when it
+// does not exist
+func (h *EnhancedChatHandler) wizardSession(c *gin.Context) *wizardSession {
+	var session *wizardSession
+	if h.wizards != nil {
+		session = h.wizards.get(c.Param("session_id"))
+	}
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wizard session not found"})
+	}
+	return session
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/llm"
+)
+
+func TestWizardTree(t *testing.T) {
+	kb := llm.NewOpenShiftKnowledgeBase()
+	reached := map[string]bool{wizardRoot: true}
+	for id, node := range wizardTree {
+		if node.ID != id {
+			t.Errorf("wizardTree[%q].ID = %q", id, node.ID)
+		}
+		if len(node.Options) == 0 && node.Conclusion == "" {
+			t.Errorf("leaf %q has no conclusion", id)
+		}
+		if len(node.Options) > 0 && node.Question == "" {
+			t.Errorf("node %q has options but no question", id)
+		}
+		for _, option := range node.Options {
+			if _, ok := wizardTree[option.Next]; !ok {
+				t.Errorf("node %q option %q leads to unknown node %q", id, option.Answer, option.Next)
+			}
+			reached[option.Next] = true
+		}
+		for _, input := range node.Inputs {
+			if wizardInputPrompts[input] == "" {
+				t.Errorf("node %q input %q has no prompt", id, input)
+			}
+		}
+		if node.Pattern != "" {
+			if path, causes := kb.TroubleshootingSection(node.Pattern); len(path) == 0 || len(causes) == 0 {
+				t.Errorf("node %q pattern %q not found in the knowledge base", id, node.Pattern)
+			}
+		}
+	}
+	for id := range wizardTree {
+		if !reached[id] {
+			t.Errorf("node %q is unreachable", id)
+		}
+	}
+}
+
+func TestChooseOption(t *testing.T) {
+	node := wizardTree[wizardRoot]
+	tests := []struct {
+		answer   string
+		expected string
+		ok       bool
+	}{
+		{"storage", "pvcs", true},
+		{" Network ", "routes", true},
+		{"1", "pods", true},
+		{"9", "", false},
+		{"dns", "", false},
+	}
+	for _, tt := range tests {
+		option, ok := chooseOption(node, tt.answer)
+		if ok != tt.ok || option.Next != tt.expected {
+			t.Errorf("chooseOption(%q) = %q, %v, expected %q, %v", tt.answer, option.Next, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestWizardEnterNode(t *testing.T) {
+	h := &EnhancedChatHandler{}
+	session := &wizardSession{id: "wizard-1", inputs: map[string]string{"namespace": "shop"}}
+
+	step := h.enterNode(context.Background(), session, wizardTree["pvc_diagnose"])
+	if !session.awaiting || len(step.NeedInputs) != 1 || step.NeedInputs[0].Name != "pvc_name" || step.Done {
+		t.Fatalf("enterNode without pvc_name = %+v", step)
+	}
+
+	session.inputs["pvc_name"] = "data"
+	step = h.enterNode(context.Background(), session, wizardTree["pvc_diagnose"])
+	if session.awaiting || !step.Done || step.Tool != "diagnose_pvc" || step.Parameters["pvc_name"] != "data" || step.Parameters["namespace"] != "shop" {
+		t.Errorf("enterNode with inputs = %+v", step)
+	}
+	if len(step.LikelyCauses) == 0 || step.LikelyCauses[0] != "No available PVs matching PVC requirements" {
+		t.Errorf("enterNode likely causes = %q", step.LikelyCauses)
+	}
+	if len(session.history) != 1 {
+		t.Errorf("session history has %d steps, expected 1", len(session.history))
+	}
+}
@@ -167,6 +167,9 @@ func (e *Engine) handleNetworkTroubleshooting(analysis *types.Analysis) (*types.
 	analysis.Metadata["pod_info"] = troubleshootingResult.PodInfo
 	analysis.Metadata["steps_executed"] = len(troubleshootingResult.Steps)
 	analysis.Metadata["commands_executed"] = len(troubleshootingResult.Commands)
+	if troubleshootingResult.DNSSymptoms != nil {
+		analysis.Metadata["dns_symptoms"] = troubleshootingResult.DNSSymptoms
+	}
 
 	if troubleshootingResult.Success {
 		analysis.Confidence = 0.9
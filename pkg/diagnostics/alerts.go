@@ -0,0 +1,259 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertsFile holds collected alerts; analysis places their start on the
+	// incident timeline
+	AlertsFile = "alerts.json"
+
+	// Alert states
+	AlertFiring    = "firing"
+	AlertSilenced  = "silenced"
+	AlertInhibited = "inhibited"
+	AlertResolved  = "resolved"
+)
+
+// ownerLabels are the alert labels naming a pod's controller, by resource type
+var ownerLabels = map[string]string{
+	"deployment":  "deployment",
+	"statefulset": "statefulset",
+	"daemonset":   "daemonset",
+	"replicaset":  "replicaset",
+	"job":         "job_name",
+}
+
+// Alert is one alert from Alertmanager, or one that fired recently and has
+// since resolved
+type Alert struct {
+	Name        string            `json:"name"`
+	State       string            `json:"state"` // firing, silenced, inhibited or resolved
+	Severity    string            `json:"severity,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"starts_at"`
+	EndsAt      time.Time         `json:"ends_at,omitempty"` // when a resolved alert was last seen firing
+}
+
+// Summary returns the alert's summary annotation, or its description
+func (a Alert) Summary() string {
+	for _, key := range []string{"summary", "message", "description"} {
+		if text := strings.TrimSpace(a.Annotations[key]); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// AlertsSnapshot is the content of an alerts file
+type AlertsSnapshot struct {
+	Source      string    `json:"source"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Since       string    `json:"since"` // how far back resolved alerts were looked for
+	CollectedAt time.Time `json:"collected_at"`
+	Alerts      []Alert   `json:"alerts"`
+	Warnings    []string  `json:"warnings,omitempty"`
+}
+
+// AlertTarget is the resource being diagnosed
+type AlertTarget struct {
+	Namespace string
+	Type      string // pod, deployment, statefulset, daemonset, replicaset, job, node or namespace
+	Name      string
+}
+
+// AlertMatch is an alert about a target, with why it matched
+type AlertMatch struct {
+	Alert  Alert  `json:"alert"`
+	Reason string `json:"reason"`
+}
+
+// MatchAlerts returns the alerts about a target: alerts naming it, alerts
+// naming the controller that owns a target pod, alerts naming pods a target
+// controller owns, and for a namespace every alert in it. Firing alerts come
+// first, then by start time.
+func MatchAlerts(alerts []Alert, target AlertTarget) []AlertMatch {
+	kind := strings.ToLower(target.Type)
+	var matches []AlertMatch
+	for _, alert := range alerts {
+		if reason := matchAlert(alert.Labels, kind, target); reason != "" {
+			matches = append(matches, AlertMatch{Alert: alert, Reason: reason})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if firing := matches[i].Alert.State == AlertFiring; firing != (matches[j].Alert.State == AlertFiring) {
+			return firing
+		}
+		return matches[i].Alert.StartsAt.Before(matches[j].Alert.StartsAt)
+	})
+	return matches
+}
+
+// matchAlert explains why labels are about the target, or returns ""
+func matchAlert(labels map[string]string, kind string, target AlertTarget) string {
+	if kind == "node" {
+		if labels["node"] == target.Name {
+			return "node label is " + target.Name
+		}
+		if instance := labels["instance"]; instance == target.Name || strings.HasPrefix(instance, target.Name+":") {
+			return "instance label is " + instance
+		}
+		return ""
+	}
+	if labels["namespace"] != target.Namespace {
+		return ""
+	}
+	if kind == "namespace" || kind == "" || target.Name == "" {
+		return "in namespace " + target.Namespace
+	}
+
+	if kind == "pod" {
+		if labels["pod"] == target.Name {
+			return "pod label is " + target.Name
+		}
+		for _, ownerKind := range sortedOwnerKinds() {
+			if owner := labels[ownerLabels[ownerKind]]; owner != "" && strings.HasPrefix(target.Name, owner+"-") {
+				return fmt.Sprintf("%s %s owns the pod", ownerKind, owner)
+			}
+		}
+		return ""
+	}
+
+	if kind == "service" {
+		if labels["service"] == target.Name {
+			return "service label is " + target.Name
+		}
+		return ""
+	}
+	label, ok := ownerLabels[kind]
+	if !ok {
+		return ""
+	}
+	if labels[label] == target.Name {
+		return fmt.Sprintf("%s label is %s", label, target.Name)
+	}
+	if pod := labels["pod"]; pod != "" && strings.HasPrefix(pod, target.Name+"-") {
+		return fmt.Sprintf("pod %s belongs to the %s", pod, kind)
+	}
+	return ""
+}
+
+func sortedOwnerKinds() []string {
+	kinds := make([]string, 0, len(ownerLabels))
+	for kind := range ownerLabels {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// SaveAlerts writes collected alerts to alerts.json in opts.OutputDir, or a
+// new directory under the working directory, and registers them
+func (dc *DiagnosticCollector) SaveAlerts(snapshot *AlertsSnapshot, opts *CollectionOptions) (*CollectionResult, error) {
+	start := time.Now()
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("alerts-%d", start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(outputDir, AlertsFile)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, alert := range snapshot.Alerts {
+		counts[alert.State]++
+	}
+	result := &CollectionResult{
+		Type:     "alerts",
+		Status:   "completed",
+		FilePath: path,
+		Size:     int64(len(data)),
+		Duration: time.Since(start),
+		Metadata: map[string]string{
+			"namespace": snapshot.Namespace,
+			"firing":    strconv.Itoa(counts[AlertFiring]),
+			"resolved":  strconv.Itoa(counts[AlertResolved]),
+			"silenced":  strconv.Itoa(counts[AlertSilenced] + counts[AlertInhibited]),
+		},
+		Summary: fmt.Sprintf("Collected %d firing, %d resolved and %d silenced alerts in %s",
+			counts[AlertFiring], counts[AlertResolved], counts[AlertSilenced]+counts[AlertInhibited], path),
+	}
+	dc.finishCollection("", result, opts)
+	return result, nil
+}
+
+// ReadAlerts reads an alerts file
+func ReadAlerts(path string) (*AlertsSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot AlertsSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return &snapshot, nil
+}
+
+// TimelineEvents places each alert's start on the timeline
+func (a *AlertsSnapshot) TimelineEvents() []TimelineEvent {
+	var events []TimelineEvent
+	for _, alert := range a.Alerts {
+		origin := alert.Labels["namespace"]
+		if origin == "" {
+			origin = alert.Labels["node"]
+		}
+		summary := fmt.Sprintf("%s alert %s", alert.Name, alert.State)
+		if text := alert.Summary(); text != "" {
+			summary += ": " + text
+		}
+		severity := strings.ToLower(alert.Severity)
+		if severity == "" || severity == "none" {
+			severity = "info"
+		}
+		events = append(events, TimelineEvent{
+			Time:     alert.StartsAt,
+			Source:   TimelineSourceAlert,
+			Origin:   origin,
+			Category: "alerts",
+			Severity: severity,
+			Summary:  summary,
+		})
+	}
+	return events
+}
+
+// collectAlertTimelines places the alerts collected in or directly below
+// dir on the timeline
+func (ae *AnalysisEngine) collectAlertTimelines(dir string, result *AnalysisResult) {
+	paths, _ := filepath.Glob(filepath.Join(dir, AlertsFile))
+	nested, _ := filepath.Glob(filepath.Join(dir, "*", AlertsFile))
+	paths = append(paths, nested...)
+
+	for _, path := range paths {
+		snapshot, err := ReadAlerts(path)
+		if err != nil {
+			ae.logger.Warnf("Failed to read alerts %s: %v", path, err)
+			continue
+		}
+		result.events = append(result.events, snapshot.TimelineEvents()...)
+		result.Metrics["alert_snapshots"] = len(paths)
+	}
+}
@@ -0,0 +1,93 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMatchAlerts(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	alerts := []Alert{
+		{Name: "KubeContainerWaiting", State: AlertResolved, StartsAt: start, Labels: map[string]string{"namespace": "shop", "pod": "web-7d9f-abcde"}},
+		{Name: "KubePodCrashLooping", State: AlertFiring, StartsAt: start.Add(time.Minute), Labels: map[string]string{"namespace": "shop", "pod": "web-7d9f-abcde"}},
+		{Name: "KubeDeploymentReplicasMismatch", State: AlertFiring, StartsAt: start, Labels: map[string]string{"namespace": "shop", "deployment": "web"}},
+		{Name: "KubePodCrashLooping", State: AlertFiring, StartsAt: start, Labels: map[string]string{"namespace": "other", "pod": "web-7d9f-abcde"}},
+		{Name: "KubeNodeNotReady", State: AlertFiring, StartsAt: start, Labels: map[string]string{"node": "worker-1"}},
+		{Name: "NodeFilesystemSpaceFillingUp", State: AlertFiring, StartsAt: start, Labels: map[string]string{"instance": "worker-2:9100"}},
+	}
+
+	tests := []struct {
+		target   AlertTarget
+		expected []string
+	}{
+		{AlertTarget{Namespace: "shop", Type: "pod", Name: "web-7d9f-abcde"}, []string{
+			"KubeDeploymentReplicasMismatch: deployment web owns the pod",
+			"KubePodCrashLooping: pod label is web-7d9f-abcde",
+			"KubeContainerWaiting: pod label is web-7d9f-abcde",
+		}},
+		{AlertTarget{Namespace: "shop", Type: "Deployment", Name: "web"}, []string{
+			"KubeDeploymentReplicasMismatch: deployment label is web",
+			"KubePodCrashLooping: pod web-7d9f-abcde belongs to the deployment",
+			"KubeContainerWaiting: pod web-7d9f-abcde belongs to the deployment",
+		}},
+		{AlertTarget{Type: "node", Name: "worker-1"}, []string{"KubeNodeNotReady: node label is worker-1"}},
+		{AlertTarget{Type: "node", Name: "worker-2"}, []string{"NodeFilesystemSpaceFillingUp: instance label is worker-2:9100"}},
+		{AlertTarget{Namespace: "shop", Type: "service", Name: "web"}, nil},
+		{AlertTarget{Namespace: "payments", Type: "pod", Name: "web-7d9f-abcde"}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, match := range MatchAlerts(alerts, tt.target) {
+			got = append(got, match.Alert.Name+": "+match.Reason)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("MatchAlerts(%+v) = %q, expected %q", tt.target, got, tt.expected)
+		}
+	}
+}
+
+func TestAnalyzeLogsCorrelatesAlerts(t *testing.T) {
+	dir := t.TempDir()
+	logs := "2024-05-01T10:03:00Z ERROR backend web: connection refused\n"
+	if err := os.WriteFile(filepath.Join(dir, "router.log"), []byte(logs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+	snapshot := &AlertsSnapshot{Namespace: "shop", Since: "1h", Alerts: []Alert{{
+		Name:        "KubePodCrashLooping",
+		State:       AlertFiring,
+		Severity:    "warning",
+		Labels:      map[string]string{"namespace": "shop", "pod": "web-1"},
+		Annotations: map[string]string{"summary": "Pod is crash looping."},
+		StartsAt:    time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC),
+	}}}
+	result, err := dc.SaveAlerts(snapshot, &CollectionOptions{OutputDir: filepath.Join(dir, "alerts")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Metadata["firing"] != "1" || result.Metadata["artifact_id"] == "" {
+		t.Errorf("SaveAlerts() = %+v", result)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+	analysis, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(analysis.Timeline) != 2 || analysis.Timeline[0].Source != TimelineSourceAlert {
+		t.Fatalf("timeline = %+v, expected the alert before the log error", analysis.Timeline)
+	}
+	if len(analysis.Correlations) != 1 || !strings.Contains(analysis.Correlations[0].Description, "KubePodCrashLooping alert firing: Pod is crash looping.") {
+		t.Errorf("correlations = %+v, expected the alert to precede the connection errors", analysis.Correlations)
+	}
+}
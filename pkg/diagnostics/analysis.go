@@ -1,13 +1,14 @@
 package diagnostics
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -15,7 +16,17 @@ import (
 
 // AnalysisEngine performs analysis on collected diagnostic data
 type AnalysisEngine struct {
-	logger *logrus.Logger
+	logger       *logrus.Logger
+	cacheEnabled bool
+	limits       AnalysisLimits
+	timezone     *time.Location
+	clockSkew    map[string]time.Duration
+	locales      []string
+	patternPacks map[string]PatternPack
+	rulesMu      sync.RWMutex // guards rulePacks, which reload at runtime
+	rulePacks    []RulePack
+	tshark       string      // tshark for deep capture analysis; found on PATH when empty
+	command      commandFunc // overrides exec.CommandContext, e.g. in tests
 }
 
 // AnalysisResult represents the result of diagnostic analysis
@@ -27,6 +38,19 @@ type AnalysisResult struct {
 	Summary         string                 `json:"summary"`
 	Recommendations []string               `json:"recommendations"`
 	Timestamp       time.Time              `json:"timestamp"`
+
+	// Truncated is set when limits stopped the analysis from seeing or
+	// keeping everything; TruncationReasons explains what was dropped.
+	Truncated         bool     `json:"truncated,omitempty"`
+	TruncationReasons []string `json:"truncation_reasons,omitempty"`
+
+	// Timeline orders timestamped issues and events in UTC; Correlations
+	// links earlier events to later ones from other sources.
+	Timeline     []TimelineEvent `json:"timeline,omitempty"`
+	Correlations []Correlation   `json:"correlations,omitempty"`
+
+	budget *analysisBudget
+	events []TimelineEvent
 }
 
 // Issue represents a discovered issue
@@ -39,6 +63,7 @@ type Issue struct {
 	Evidence    []string          `json:"evidence"` // relevant log lines or data
 	Resolution  string            `json:"resolution"`
 	Metadata    map[string]string `json:"metadata"`
+	References  []Reference       `json:"references,omitempty"` // known bugs or KCS articles matching the issue
 }
 
 // LogPattern represents a pattern to match in logs
@@ -49,17 +74,77 @@ type LogPattern struct {
 	Category    string
 	Description string
 	Resolution  string
+
+	// Set by rule packs: lines matching Unless are skipped, Files is a glob
+	// limiting the logs matched and Docs links the team's runbook
+	Unless   *regexp.Regexp
+	Files    string
+	Docs     string
+	RulePack string
 }
 
 // NewAnalysisEngine creates a new analysis engine
 func NewAnalysisEngine(logger *logrus.Logger) *AnalysisEngine {
 	return &AnalysisEngine{
-		logger: logger,
+		logger:       logger,
+		cacheEnabled: true,
+		limits:       DefaultAnalysisLimits(),
+	}
+}
+
+// SetLimits configures memory bounds for log analysis; zero values keep defaults
+func (ae *AnalysisEngine) SetLimits(limits AnalysisLimits) {
+	ae.limits = limits.withDefaults()
+}
+
+// SetCacheEnabled toggles content-hash caching of analysis results
+func (ae *AnalysisEngine) SetCacheEnabled(enabled bool) {
+	ae.cacheEnabled = enabled
+}
+
+// SetTimezone sets the zone used for log timestamps that carry no offset;
+// the default is UTC
+func (ae *AnalysisEngine) SetTimezone(loc *time.Location) {
+	ae.timezone = loc
+}
+
+// SetClockSkew corrects timestamps from origins (files, nodes or namespaces)
+// containing origin whose clock runs ahead of the reference by offset
+func (ae *AnalysisEngine) SetClockSkew(origin string, offset time.Duration) {
+	if ae.clockSkew == nil {
+		ae.clockSkew = make(map[string]time.Duration)
+	}
+	ae.clockSkew[origin] = offset
+}
+
+// loadCache returns the analysis cache for an artifact, or nil when caching is disabled
+func (ae *AnalysisEngine) loadCache(artifactPath string) *analysisCache {
+	if !ae.cacheEnabled {
+		return nil
+	}
+	return loadAnalysisCache(artifactPath)
+}
+
+// saveCache persists the analysis cache and records hit/miss metrics
+func (ae *AnalysisEngine) saveCache(cache *analysisCache, result *AnalysisResult) {
+	if cache == nil {
+		return
+	}
+	hits, misses := cache.stats()
+	result.Metrics["cached_files"] = hits
+	result.Metrics["analyzed_files"] = misses
+	if err := cache.save(); err != nil {
+		ae.logger.Warnf("Failed to save analysis cache: %v", err)
 	}
 }
 
-// AnalyzeMustGather analyzes must-gather data
+// AnalyzeMustGather analyzes must-gather data, a directory or a bundle
 func (ae *AnalysisEngine) AnalyzeMustGather(ctx context.Context, mustGatherPath string) (*AnalysisResult, error) {
+	if IsBundle(mustGatherPath) {
+		return analyzeBundle(mustGatherPath, bundleDir, func(path string) (*AnalysisResult, error) {
+			return ae.AnalyzeMustGather(ctx, path)
+		})
+	}
 	result := &AnalysisResult{
 		Type:      "must-gather-analysis",
 		FilePath:  mustGatherPath,
@@ -70,6 +155,31 @@ func (ae *AnalysisEngine) AnalyzeMustGather(ctx context.Context, mustGatherPath
 
 	ae.logger.Infof("Starting must-gather analysis: %s", mustGatherPath)
 
+	// Return the previous result if nothing in the bundle changed and it was
+	// produced under the same limits
+	cache := ae.loadCache(mustGatherPath)
+	resultKey := result.Type + "@" + ae.limits.cacheKey() + "@" + ae.knownIssuesKey()
+	if key := ae.rulesKey(); key != "" {
+		resultKey += "@" + key
+	}
+	var fingerprint string
+	if cache != nil {
+		if fp, err := cache.fingerprint(); err != nil {
+			ae.logger.Warnf("Failed to fingerprint must-gather: %v", err)
+		} else {
+			fingerprint = fp
+			if cached, ok := cache.result(resultKey, fingerprint); ok {
+				ae.logger.Infof("Must-gather unchanged since last analysis, returning cached result")
+				cached.Timestamp = result.Timestamp
+				cached.Metrics["cache"] = "hit"
+				if err := cache.save(); err != nil {
+					ae.logger.Warnf("Failed to save analysis cache: %v", err)
+				}
+				return cached, nil
+			}
+		}
+	}
+
 	// Analyze cluster version and health
 	if err := ae.analyzeClusterHealth(mustGatherPath, result); err != nil {
 		ae.logger.Warnf("Failed to analyze cluster health: %v", err)
@@ -89,21 +199,37 @@ func (ae *AnalysisEngine) AnalyzeMustGather(ctx context.Context, mustGatherPath
 	if err := ae.analyzeEvents(mustGatherPath, result); err != nil {
 		ae.logger.Warnf("Failed to analyze events: %v", err)
 	}
+	ae.collectEventTimelines(mustGatherPath, result)
+	ae.matchKnownEvents(result)
+	ae.collectMetricTimelines(mustGatherPath, result)
+	ae.collectAlertTimelines(mustGatherPath, result)
 
 	// Analyze operator logs
-	if err := ae.analyzeOperatorLogs(mustGatherPath, result); err != nil {
+	if err := ae.analyzeOperatorLogs(mustGatherPath, cache, result); err != nil {
 		ae.logger.Warnf("Failed to analyze operator logs: %v", err)
 	}
 
 	// Generate summary and recommendations
+	ae.finishBudget(result)
+	ae.buildTimeline(result)
 	ae.generateSummaryAndRecommendations(result)
 
+	if cache != nil && fingerprint != "" && !result.Truncated {
+		cache.storeResult(resultKey, fingerprint, result)
+	}
+	ae.saveCache(cache, result)
+
 	ae.logger.Infof("Must-gather analysis completed: found %d issues", len(result.Issues))
 	return result, nil
 }
 
-// AnalyzeLogs analyzes collected log files
+// AnalyzeLogs analyzes collected log files, a file, a directory or a bundle
 func (ae *AnalysisEngine) AnalyzeLogs(ctx context.Context, logPath string) (*AnalysisResult, error) {
+	if IsBundle(logPath) {
+		return analyzeBundle(logPath, bundleDir, func(path string) (*AnalysisResult, error) {
+			return ae.AnalyzeLogs(ctx, path)
+		})
+	}
 	result := &AnalysisResult{
 		Type:      "log-analysis",
 		FilePath:  logPath,
@@ -116,6 +242,7 @@ func (ae *AnalysisEngine) AnalyzeLogs(ctx context.Context, logPath string) (*Ana
 
 	// Get log patterns for analysis
 	patterns := ae.getLogPatterns()
+	cache := ae.loadCache(logPath)
 
 	// Analyze log files
 	err := filepath.Walk(logPath, func(path string, info os.FileInfo, err error) error {
@@ -123,8 +250,8 @@ func (ae *AnalysisEngine) AnalyzeLogs(ctx context.Context, logPath string) (*Ana
 			return err
 		}
 
-		if !info.IsDir() && (strings.HasSuffix(path, ".log") || strings.HasSuffix(path, ".txt")) {
-			if err := ae.analyzeLogFile(path, patterns, result); err != nil {
+		if !info.IsDir() && isLogFile(path) {
+			if err := ae.analyzeLogFileCached(cache, path, "logs", patterns, result); err != nil {
 				ae.logger.Warnf("Failed to analyze log file %s: %v", path, err)
 			}
 		}
@@ -136,7 +263,12 @@ func (ae *AnalysisEngine) AnalyzeLogs(ctx context.Context, logPath string) (*Ana
 	}
 
 	// Analyze log metrics
+	ae.collectMetricTimelines(logPath, result)
+	ae.collectAlertTimelines(logPath, result)
+	ae.finishBudget(result)
+	ae.buildTimeline(result)
 	ae.calculateLogMetrics(result)
+	ae.saveCache(cache, result)
 
 	// Generate summary
 	ae.generateSummaryAndRecommendations(result)
@@ -145,8 +277,19 @@ func (ae *AnalysisEngine) AnalyzeLogs(ctx context.Context, logPath string) (*Ana
 	return result, nil
 }
 
-// AnalyzeTcpdump analyzes packet capture data
+// AnalyzeTcpdump analyzes packet capture data with the built-in decoder
 func (ae *AnalysisEngine) AnalyzeTcpdump(ctx context.Context, pcapPath string) (*AnalysisResult, error) {
+	return ae.AnalyzeTcpdumpWith(ctx, pcapPath, PcapQuick)
+}
+
+// AnalyzeTcpdumpWith analyzes packet capture data, a capture or a bundle
+// holding one; deep mode adds tshark's findings when tshark is installed
+func (ae *AnalysisEngine) AnalyzeTcpdumpWith(ctx context.Context, pcapPath string, mode PcapMode) (*AnalysisResult, error) {
+	if IsBundle(pcapPath) {
+		return analyzeBundle(pcapPath, bundleCapture, func(path string) (*AnalysisResult, error) {
+			return ae.AnalyzeTcpdumpWith(ctx, path, mode)
+		})
+	}
 	result := &AnalysisResult{
 		Type:      "tcpdump-analysis",
 		FilePath:  pcapPath,
@@ -155,17 +298,53 @@ func (ae *AnalysisEngine) AnalyzeTcpdump(ctx context.Context, pcapPath string) (
 		Timestamp: time.Now(),
 	}
 
-	ae.logger.Infof("Starting tcpdump analysis: %s", pcapPath)
+	ae.logger.Infof("Starting tcpdump analysis (%s): %s", mode, pcapPath)
+	result.Metrics["analysis_mode"] = string(PcapQuick)
 
-	// Use tshark for packet analysis if available
-	if err := ae.analyzePcapWithTshark(pcapPath, result); err != nil {
-		// Fallback to basic file analysis
-		ae.logger.Warnf("Tshark analysis failed, using basic analysis: %v", err)
-		if err := ae.analyzePcapBasic(pcapPath, result); err != nil {
+	if err := ae.analyzePcapBasic(pcapPath, result); err != nil {
+		return nil, fmt.Errorf("pcap analysis failed: %v", err)
+	}
+	size, _ := result.Metrics["file_size"].(int64)
+	if size > 0 {
+		// Decode the packets; other files keep the basic file analysis
+		switch err := ae.analyzePcap(ctx, pcapPath, result); {
+		case errors.Is(err, errNotPcap):
+			ae.addIssue(result, Issue{
+				Severity:    "warning",
+				Category:    "capture",
+				Title:       "Unrecognized Capture Format",
+				Description: "The file is neither a pcap nor a pcapng capture",
+				Location:    pcapPath,
+				Resolution:  "Write the capture with tcpdump -w or convert it with editcap",
+			})
+		case err != nil:
 			return nil, fmt.Errorf("pcap analysis failed: %v", err)
 		}
 	}
 
+	if mode == PcapDeep && size > 0 {
+		switch err := ae.analyzePcapWithTshark(ctx, pcapPath, result); {
+		case err == nil:
+			result.Metrics["analysis_mode"] = string(PcapDeep)
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		default:
+			ae.logger.Warnf("Deep capture analysis failed, keeping the quick analysis: %v", err)
+			resolution := "Check that tshark can read the capture: tshark -r " + pcapPath + " -c 1"
+			if errors.Is(err, errNoTshark) {
+				resolution = "Install Wireshark's tshark (the wireshark-cli package) where the server runs, or use the quick analysis"
+			}
+			ae.addIssue(result, Issue{
+				Severity:    "info",
+				Category:    "capture",
+				Title:       "Deep Analysis Unavailable",
+				Description: fmt.Sprintf("Only the quick analysis ran: %v", err),
+				Location:    pcapPath,
+				Resolution:  resolution,
+			})
+		}
+	}
+
 	ae.generateSummaryAndRecommendations(result)
 
 	ae.logger.Infof("Tcpdump analysis completed: found %d issues", len(result.Issues))
@@ -178,7 +357,7 @@ func (ae *AnalysisEngine) analyzeClusterHealth(mustGatherPath string, result *An
 	versionPath := filepath.Join(mustGatherPath, "cluster-scoped-resources", "config.openshift.io", "clusterversions.yaml")
 	if data, err := os.ReadFile(versionPath); err == nil {
 		if strings.Contains(string(data), "Degraded: \"True\"") {
-			result.Issues = append(result.Issues, Issue{
+			ae.addIssue(result, Issue{
 				Severity:    "critical",
 				Category:    "cluster",
 				Title:       "Cluster Version Degraded",
@@ -200,7 +379,7 @@ func (ae *AnalysisEngine) analyzeClusterHealth(mustGatherPath string, result *An
 				for j := i - 1; j >= 0 && j >= i-10; j-- {
 					if strings.Contains(lines[j], "name:") {
 						operatorName := strings.TrimSpace(strings.Split(lines[j], ":")[1])
-						result.Issues = append(result.Issues, Issue{
+						ae.addIssue(result, Issue{
 							Severity:    "warning",
 							Category:    "operator",
 							Title:       fmt.Sprintf("Operator %s Not Available", operatorName),
@@ -225,7 +404,7 @@ func (ae *AnalysisEngine) analyzeNodeHealth(mustGatherPath string, result *Analy
 	if data, err := os.ReadFile(nodesPath); err == nil {
 		// Check for node conditions
 		if strings.Contains(string(data), "Ready: \"False\"") {
-			result.Issues = append(result.Issues, Issue{
+			ae.addIssue(result, Issue{
 				Severity:    "critical",
 				Category:    "node",
 				Title:       "Node Not Ready",
@@ -237,7 +416,7 @@ func (ae *AnalysisEngine) analyzeNodeHealth(mustGatherPath string, result *Analy
 		}
 
 		if strings.Contains(string(data), "DiskPressure: \"True\"") {
-			result.Issues = append(result.Issues, Issue{
+			ae.addIssue(result, Issue{
 				Severity:    "warning",
 				Category:    "node",
 				Title:       "Node Disk Pressure",
@@ -249,7 +428,7 @@ func (ae *AnalysisEngine) analyzeNodeHealth(mustGatherPath string, result *Analy
 		}
 
 		if strings.Contains(string(data), "MemoryPressure: \"True\"") {
-			result.Issues = append(result.Issues, Issue{
+			ae.addIssue(result, Issue{
 				Severity:    "warning",
 				Category:    "node",
 				Title:       "Node Memory Pressure",
@@ -275,47 +454,48 @@ func (ae *AnalysisEngine) analyzePodIssues(mustGatherPath string, result *Analys
 		}
 
 		if strings.HasSuffix(path, "pods.yaml") {
-			if data, err := os.ReadFile(path); err == nil {
-				content := string(data)
-
-				// Check for crash loop backoff
-				if strings.Contains(content, "CrashLoopBackOff") {
-					result.Issues = append(result.Issues, Issue{
-						Severity:    "critical",
-						Category:    "pod",
-						Title:       "Pod in CrashLoopBackOff",
-						Description: "Pod is repeatedly crashing",
-						Location:    path,
-						Evidence:    []string{"CrashLoopBackOff status found"},
-						Resolution:  "Check pod logs for error messages and fix the underlying issue",
-					})
-				}
+			found, err := ae.scanForMarkers(path, result, "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "phase: Pending")
+			if err != nil {
+				return nil
+			}
 
-				// Check for image pull errors
-				if strings.Contains(content, "ImagePullBackOff") || strings.Contains(content, "ErrImagePull") {
-					result.Issues = append(result.Issues, Issue{
-						Severity:    "warning",
-						Category:    "pod",
-						Title:       "Image Pull Error",
-						Description: "Pod cannot pull container image",
-						Location:    path,
-						Evidence:    []string{"ImagePullBackOff or ErrImagePull status found"},
-						Resolution:  "Check image name, registry access, and authentication",
-					})
-				}
+			// Check for crash loop backoff
+			if found["CrashLoopBackOff"] {
+				ae.addIssue(result, Issue{
+					Severity:    "critical",
+					Category:    "pod",
+					Title:       "Pod in CrashLoopBackOff",
+					Description: "Pod is repeatedly crashing",
+					Location:    path,
+					Evidence:    []string{"CrashLoopBackOff status found"},
+					Resolution:  "Check pod logs for error messages and fix the underlying issue",
+				})
+			}
 
-				// Check for pending pods
-				if strings.Contains(content, "phase: Pending") {
-					result.Issues = append(result.Issues, Issue{
-						Severity:    "warning",
-						Category:    "pod",
-						Title:       "Pod Pending",
-						Description: "Pod is stuck in Pending state",
-						Location:    path,
-						Evidence:    []string{"phase: Pending found"},
-						Resolution:  "Check resource availability, node selectors, and scheduling constraints",
-					})
-				}
+			// Check for image pull errors
+			if found["ImagePullBackOff"] || found["ErrImagePull"] {
+				ae.addIssue(result, Issue{
+					Severity:    "warning",
+					Category:    "pod",
+					Title:       "Image Pull Error",
+					Description: "Pod cannot pull container image",
+					Location:    path,
+					Evidence:    []string{"ImagePullBackOff or ErrImagePull status found"},
+					Resolution:  "Check image name, registry access, and authentication",
+				})
+			}
+
+			// Check for pending pods
+			if found["phase: Pending"] {
+				ae.addIssue(result, Issue{
+					Severity:    "warning",
+					Category:    "pod",
+					Title:       "Pod Pending",
+					Description: "Pod is stuck in Pending state",
+					Location:    path,
+					Evidence:    []string{"phase: Pending found"},
+					Resolution:  "Check resource availability, node selectors, and scheduling constraints",
+				})
 			}
 		}
 		return nil
@@ -325,46 +505,76 @@ func (ae *AnalysisEngine) analyzePodIssues(mustGatherPath string, result *Analys
 // analyzeEvents analyzes cluster events
 func (ae *AnalysisEngine) analyzeEvents(mustGatherPath string, result *AnalysisResult) error {
 	eventsPath := filepath.Join(mustGatherPath, "cluster-scoped-resources", "core", "events.yaml")
-	if data, err := os.ReadFile(eventsPath); err == nil {
-		content := string(data)
-
-		// Look for error events
-		errorPatterns := []string{
-			"Failed",
-			"Error",
-			"Warning",
-			"FailedScheduling",
-			"FailedMount",
-			"Unhealthy",
-		}
 
-		for _, pattern := range errorPatterns {
-			if strings.Contains(content, pattern) {
-				result.Issues = append(result.Issues, Issue{
-					Severity:    "info",
-					Category:    "events",
-					Title:       fmt.Sprintf("Event: %s", pattern),
-					Description: fmt.Sprintf("Found events containing %s", pattern),
-					Location:    eventsPath,
-					Evidence:    []string{fmt.Sprintf("Events containing '%s' found", pattern)},
-					Resolution:  "Review events for details and address underlying issues",
-				})
-			}
+	// Look for error events
+	errorPatterns := []string{
+		"Failed",
+		"Error",
+		"Warning",
+		"FailedScheduling",
+		"FailedMount",
+		"Unhealthy",
+	}
+
+	found, err := ae.scanForMarkers(eventsPath, result, errorPatterns...)
+	if err != nil {
+		return nil
+	}
+
+	for _, pattern := range errorPatterns {
+		if found[pattern] {
+			ae.addIssue(result, Issue{
+				Severity:    "info",
+				Category:    "events",
+				Title:       fmt.Sprintf("Event: %s", pattern),
+				Description: fmt.Sprintf("Found events containing %s", pattern),
+				Location:    eventsPath,
+				Evidence:    []string{fmt.Sprintf("Events containing '%s' found", pattern)},
+				Resolution:  "Review events for details and address underlying issues",
+			})
 		}
 	}
 
 	return nil
 }
 
+// scanForMarkers streams a file and reports which marker strings it contains
+func (ae *AnalysisEngine) scanForMarkers(path string, result *AnalysisResult, markers ...string) (map[string]bool, error) {
+	budget := ae.budgetFor(result)
+	found := make(map[string]bool)
+	if budget.heapExceeded {
+		return found, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	err = readLines(file, budget.limits.MaxLineLength, func(lineNum int, line string, truncated bool) bool {
+		if truncated {
+			budget.cutLines++
+		}
+		for _, marker := range markers {
+			if !found[marker] && strings.Contains(line, marker) {
+				found[marker] = true
+			}
+		}
+		return len(found) < len(markers) && budget.checkHeap()
+	})
+	return found, err
+}
+
 // analyzeOperatorLogs analyzes operator logs
-func (ae *AnalysisEngine) analyzeOperatorLogs(mustGatherPath string, result *AnalysisResult) error {
+func (ae *AnalysisEngine) analyzeOperatorLogs(mustGatherPath string, cache *analysisCache, result *AnalysisResult) error {
 	return filepath.Walk(mustGatherPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 
-		if strings.Contains(path, "openshift-") && strings.HasSuffix(path, ".log") {
-			if err := ae.analyzeLogFile(path, ae.getOperatorLogPatterns(), result); err != nil {
+		if strings.Contains(path, "openshift-") && !info.IsDir() && isLogFile(path) {
+			if err := ae.analyzeLogFileCached(cache, path, "operator", ae.getOperatorLogPatterns(), result); err != nil {
 				ae.logger.Warnf("Failed to analyze operator log %s: %v", path, err)
 			}
 		}
@@ -372,41 +582,213 @@ func (ae *AnalysisEngine) analyzeOperatorLogs(mustGatherPath string, result *Ana
 	})
 }
 
-// analyzeLogFile analyzes a single log file
+// analyzeLogFileCached analyzes a log file, reusing cached issues when the
+// file content is unchanged since the last analysis
+func (ae *AnalysisEngine) analyzeLogFileCached(cache *analysisCache, filePath, patternSet string, patterns []LogPattern, result *AnalysisResult) error {
+	if cache == nil {
+		return ae.analyzeLogFile(filePath, patterns, result)
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	hash, err := cache.fileHash(filePath, info)
+	if err != nil {
+		return ae.analyzeLogFile(filePath, patterns, result)
+	}
+
+	// Cached issues depend on the limits they were produced under
+	budget := ae.budgetFor(result)
+	cacheKey := patternSet + "@" + budget.limits.cacheKey()
+	if key := ae.localeKey(); key != "" {
+		cacheKey += "@" + key
+	}
+	if key := ae.rulesKey(); key != "" {
+		cacheKey += "@" + key
+	}
+	cacheKey += "@" + ae.knownIssuesKey()
+	if issues, ok := cache.issues(filePath, cacheKey, hash); ok {
+		for _, issue := range issues {
+			ae.addIssue(result, issue)
+		}
+		return nil
+	}
+
+	fileResult := &AnalysisResult{Issues: []Issue{}, Metrics: make(map[string]interface{}), budget: budget}
+	losses := budget.losses()
+	if err := ae.analyzeLogFile(filePath, patterns, fileResult); err != nil {
+		return err
+	}
+
+	// Issues from a file that lost anything to the limits depend on the
+	// remaining budget, so only complete results are cached
+	if budget.losses() == losses {
+		cache.storeIssues(filePath, cacheKey, hash, fileResult.Issues)
+	}
+	result.Issues = append(result.Issues, fileResult.Issues...)
+	return nil
+}
+
+// analyzeLogFile streams a single log file, grouping matches of the same
+// pattern into one issue whose evidence is capped by the engine limits
 func (ae *AnalysisEngine) analyzeLogFile(filePath string, patterns []LogPattern, result *AnalysisResult) error {
-	file, err := os.Open(filePath)
+	budget := ae.budgetFor(result)
+	limits := budget.limits
+	if budget.heapExceeded {
+		return nil
+	}
+
+	file, err := openLogFile(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+	// Year-less timestamps are placed relative to when the log was written
+	reference := time.Now()
+	if info, err := os.Stat(filePath); err == nil {
+		reference = info.ModTime()
+	}
+	timestamps := ae.timestampParser(reference)
+
+	// Match localized messages for the configured and detected languages
+	locale := ae.detectFileLocale(filePath)
+	patterns = ae.localizePatterns(filePatterns(patterns, filePath), locale)
+
+	// Lines matching known issue signatures attach references to the issues
+	// they count towards, or raise their own issue
+	signatures := ae.knownIssueMatchers(KnownIssueLogs)
+
+	issueIndex := make(map[string]int)
+	occurrences := make(map[string]int)
+	lastLine := make(map[string]int)
+
+	// record counts a match towards the issue of a pattern, opening the
+	// issue on the first match the budget allows
+	record := func(name string, lineNum int, line string, open func() Issue) (int, bool) {
+		if idx, ok := issueIndex[name]; ok {
+			occurrences[name]++
+			lastLine[name] = lineNum
+			issue := &result.Issues[idx]
+			if t, ok := timestamps(line); ok {
+				noteSeen(issue.Metadata, t)
+			}
+			if len(issue.Evidence) < limits.MaxEvidencePerIssue && budget.allowEvidence(line) {
+				issue.Evidence = append(issue.Evidence, line)
+			}
+			return idx, true
+		}
+
+		if !budget.allowIssue() {
+			return 0, false
+		}
+
+		issue := open()
+		if budget.allowEvidence(line) {
+			issue.Evidence = []string{line}
+		}
+		issue.Location = fmt.Sprintf("%s:line %d", filePath, lineNum)
+		if issue.Metadata == nil {
+			issue.Metadata = make(map[string]string)
+		}
+		issue.Metadata["first_line"] = fmt.Sprintf("%d", lineNum)
+		if t, ok := timestamps(line); ok {
+			noteSeen(issue.Metadata, t)
+		}
+		if locale != "" {
+			issue.Metadata["locale"] = locale
+		}
+
+		issueIndex[name] = len(result.Issues)
+		occurrences[name] = 1
+		lastLine[name] = lineNum
+		result.Issues = append(result.Issues, issue)
+		return len(result.Issues) - 1, true
+	}
+
+	// Stack traces are grouped into one issue per signature, and their
+	// frames are kept away from the single-line patterns
+	var detector stackTraceDetector
+	traceIndex := make(map[string]int)
+
+	err = readLines(file, limits.MaxLineLength, func(lineNum int, line string, truncated bool) bool {
+		if truncated {
+			budget.cutLines++
+		}
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+		trace, continuation := detector.feed(lineNum, line)
+		if trace != nil {
+			ae.recordStackTrace(result, filePath, trace, traceIndex, timestamps)
+		}
+		if continuation {
+			return budget.checkHeap()
+		}
 
+		var matched []int
 		for _, pattern := range patterns {
-			if pattern.Pattern.MatchString(line) {
-				result.Issues = append(result.Issues, Issue{
+			if !pattern.Pattern.MatchString(line) || (pattern.Unless != nil && pattern.Unless.MatchString(line)) {
+				continue
+			}
+			idx, ok := record(pattern.Name, lineNum, line, func() Issue {
+				issue := Issue{
 					Severity:    pattern.Severity,
 					Category:    pattern.Category,
 					Title:       pattern.Name,
 					Description: pattern.Description,
-					Location:    fmt.Sprintf("%s:line %d", filePath, lineNum),
-					Evidence:    []string{line},
 					Resolution:  pattern.Resolution,
-				})
+					Metadata:    make(map[string]string),
+				}
+				if pattern.RulePack != "" {
+					issue.Metadata["rule_pack"] = pattern.RulePack
+				}
+				if pattern.Docs != "" {
+					issue.Metadata["docs"] = pattern.Docs
+				}
+				return issue
+			})
+			if ok {
+				matched = append(matched, idx)
 			}
 		}
+
+		for _, signature := range signatures {
+			if !signature.pattern.MatchString(line) {
+				continue
+			}
+			if len(matched) == 0 {
+				if idx, ok := record("known:"+signature.ID, lineNum, line, signature.issue); ok {
+					matched = append(matched, idx)
+				}
+				continue
+			}
+			for _, idx := range matched {
+				addReference(&result.Issues[idx], signature.Reference())
+			}
+		}
+
+		return budget.checkHeap()
+	})
+	if trace := detector.finish(); trace != nil {
+		ae.recordStackTrace(result, filePath, trace, traceIndex, timestamps)
+	}
+
+	for name, idx := range issueIndex {
+		result.Issues[idx].Metadata["occurrences"] = fmt.Sprintf("%d", occurrences[name])
+		result.Issues[idx].Metadata["last_line"] = fmt.Sprintf("%d", lastLine[name])
 	}
 
-	return scanner.Err()
+	return err
 }
 
-// getLogPatterns returns common log patterns to match
+// getLogPatterns returns common log patterns to match, with the rule packs applied
 func (ae *AnalysisEngine) getLogPatterns() []LogPattern {
+	return ae.withRules(builtinLogPatterns(), RuleAppliesLogs)
+}
+
+// builtinLogPatterns returns the log patterns every analysis starts from
+func builtinLogPatterns() []LogPattern {
 	return []LogPattern{
 		{
 			Name:        "OutOfMemory Error",
@@ -453,7 +835,7 @@ func (ae *AnalysisEngine) getLogPatterns() []LogPattern {
 
 // getOperatorLogPatterns returns patterns specific to operator logs
 func (ae *AnalysisEngine) getOperatorLogPatterns() []LogPattern {
-	patterns := ae.getLogPatterns()
+	patterns := builtinLogPatterns()
 
 	// Add operator-specific patterns
 	operatorPatterns := []LogPattern{
@@ -475,16 +857,10 @@ func (ae *AnalysisEngine) getOperatorLogPatterns() []LogPattern {
 		},
 	}
 
-	return append(patterns, operatorPatterns...)
+	return ae.withRules(append(patterns, operatorPatterns...), RuleAppliesOperator)
 }
 
 // Helper functions for different analysis types
-func (ae *AnalysisEngine) analyzePcapWithTshark(pcapPath string, result *AnalysisResult) error {
-	// This would use tshark to analyze packet captures
-	// For now, return error to trigger fallback
-	return fmt.Errorf("tshark analysis not implemented yet")
-}
-
 func (ae *AnalysisEngine) analyzePcapBasic(pcapPath string, result *AnalysisResult) error {
 	// Basic file analysis
 	info, err := os.Stat(pcapPath)
@@ -0,0 +1,279 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDiagnosticsDir is where collections go when no directory is configured
+const DefaultDiagnosticsDir = "/tmp/diagnostics"
+
+// artifactRegistryFile lists the registered artifacts in the base directory
+const artifactRegistryFile = "artifacts.json"
+
+// Artifact is a registered collection: a must-gather, capture, sosreport,
+// log directory or bundle
+type Artifact struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Path      string            `json:"path"`
+	Size      int64             `json:"size"`
+	CreatedAt time.Time         `json:"created_at"`
+	Summary   string            `json:"summary,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Managed reports whether the artifact lives under baseDir; retention only
+// removes managed artifacts, never a directory a caller chose elsewhere
+func (a *Artifact) Managed(baseDir string) bool {
+	rel, err := filepath.Rel(baseDir, a.Path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// RetentionPolicy bounds what the artifact store keeps; zero values disable
+// the corresponding limit
+type RetentionPolicy struct {
+	MaxBytes int64         // total size of managed artifacts
+	TTL      time.Duration // age after which an artifact is removed
+}
+
+// ArtifactStore registers collected artifacts by ID and enforces a
+// retention policy on them. The registry is persisted in the base
+// directory so artifacts survive restarts.
+type ArtifactStore struct {
+	mu        sync.Mutex
+	logger    *logrus.Logger
+	baseDir   string
+	policy    RetentionPolicy
+	artifacts map[string]*Artifact
+	now       func() time.Time // overridden in tests
+}
+
+// NewArtifactStore opens the registry in baseDir, dropping entries whose
+// files are gone
+func NewArtifactStore(logger *logrus.Logger, baseDir string, policy RetentionPolicy) *ArtifactStore {
+	if baseDir == "" {
+		baseDir = DefaultDiagnosticsDir
+	}
+	store := &ArtifactStore{
+		logger:    logger,
+		baseDir:   filepath.Clean(baseDir),
+		policy:    policy,
+		artifacts: make(map[string]*Artifact),
+		now:       time.Now,
+	}
+	data, err := os.ReadFile(store.registryPath())
+	if err != nil {
+		return store
+	}
+	var artifacts []*Artifact
+	if err := json.Unmarshal(data, &artifacts); err != nil {
+		logger.Warnf("Ignoring unreadable artifact registry %s: %v", store.registryPath(), err)
+		return store
+	}
+	for _, artifact := range artifacts {
+		if _, err := os.Stat(artifact.Path); err == nil {
+			store.artifacts[artifact.ID] = artifact
+		}
+	}
+	return store
+}
+
+// BaseDir returns the directory managed artifacts are written to
+func (s *ArtifactStore) BaseDir() string {
+	return s.baseDir
+}
+
+// Policy returns the retention policy
+func (s *ArtifactStore) Policy() RetentionPolicy {
+	return s.policy
+}
+
+func (s *ArtifactStore) registryPath() string {
+	return filepath.Join(s.baseDir, artifactRegistryFile)
+}
+
+// save writes the registry; callers hold s.mu
+func (s *ArtifactStore) save() error {
+	artifacts := s.sorted("")
+	data, err := json.MarshalIndent(artifacts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.baseDir, 0755); err != nil {
+		return err
+	}
+	tmp := s.registryPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.registryPath())
+}
+
+// sorted returns the artifacts of a type, or all of them, newest first;
+// callers hold s.mu
+func (s *ArtifactStore) sorted(kind string) []Artifact {
+	artifacts := make([]Artifact, 0, len(s.artifacts))
+	for _, artifact := range s.artifacts {
+		if kind == "" || artifact.Type == kind {
+			artifacts = append(artifacts, *artifact)
+		}
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		if !artifacts[i].CreatedAt.Equal(artifacts[j].CreatedAt) {
+			return artifacts[i].CreatedAt.After(artifacts[j].CreatedAt)
+		}
+		return artifacts[i].ID > artifacts[j].ID
+	})
+	return artifacts
+}
+
+// Register records a completed collection under a new ID, or id when it is
+// not empty, then applies the retention policy
+func (s *ArtifactStore) Register(id string, result *CollectionResult) (*Artifact, error) {
+	if result.FilePath == "" {
+		return nil, fmt.Errorf("%s collection has no path to register", result.Type)
+	}
+	s.mu.Lock()
+	created := s.now()
+	if id == "" {
+		id = fmt.Sprintf("%s-%s-%s", result.Type, created.UTC().Format("20060102-150405"), randomSuffix())
+	}
+	metadata := make(map[string]string, len(result.Metadata))
+	for key, value := range result.Metadata {
+		metadata[key] = value
+	}
+	artifact := &Artifact{
+		ID:        id,
+		Type:      result.Type,
+		Path:      filepath.Clean(result.FilePath),
+		Size:      result.Size,
+		CreatedAt: created.UTC(),
+		Summary:   result.Summary,
+		Metadata:  metadata,
+	}
+	s.artifacts[id] = artifact
+	err := s.save()
+	s.mu.Unlock()
+	if err != nil {
+		return artifact, fmt.Errorf("saving the artifact registry: %v", err)
+	}
+
+	if _, err := s.Cleanup(); err != nil {
+		s.logger.Warnf("Artifact retention failed: %v", err)
+	}
+	return artifact, nil
+}
+
+// List returns the artifacts of a type, or all of them, newest first
+func (s *ArtifactStore) List(kind string) []Artifact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted(kind)
+}
+
+// Get returns an artifact by ID
+func (s *ArtifactStore) Get(id string) (Artifact, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	artifact, ok := s.artifacts[id]
+	if !ok {
+		return Artifact{}, false
+	}
+	return *artifact, true
+}
+
+// Delete removes an artifact's files and its registry entry
+func (s *ArtifactStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	artifact, ok := s.artifacts[id]
+	if !ok {
+		return fmt.Errorf("no artifact %q", id)
+	}
+	if err := os.RemoveAll(artifact.Path); err != nil {
+		return fmt.Errorf("removing %s: %v", artifact.Path, err)
+	}
+	delete(s.artifacts, id)
+	return s.save()
+}
+
+// Usage returns the total size of the managed artifacts
+func (s *ArtifactStore) Usage() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for _, artifact := range s.artifacts {
+		if artifact.Managed(s.baseDir) {
+			total += artifact.Size
+		}
+	}
+	return total
+}
+
+// Cleanup applies the retention policy: managed artifacts older than the
+// TTL are removed, then the oldest until the total fits in MaxBytes. The
+// newest artifact is always kept, even when it alone exceeds the limit.
+// Entries whose files were removed by hand are dropped too.
+func (s *ArtifactStore) Cleanup() ([]Artifact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []Artifact
+	var firstErr error
+	remove := func(artifact Artifact) {
+		if err := os.RemoveAll(artifact.Path); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("removing %s: %v", artifact.ID, err)
+			}
+			return
+		}
+		delete(s.artifacts, artifact.ID)
+		removed = append(removed, artifact)
+	}
+
+	now := s.now()
+	var usage int64
+	var kept []Artifact
+	for _, artifact := range s.sorted("") {
+		if _, err := os.Stat(artifact.Path); os.IsNotExist(err) {
+			delete(s.artifacts, artifact.ID)
+			continue
+		}
+		if !artifact.Managed(s.baseDir) {
+			continue
+		}
+		if s.policy.TTL > 0 && now.Sub(artifact.CreatedAt) > s.policy.TTL {
+			remove(artifact)
+			continue
+		}
+		usage += artifact.Size
+		kept = append(kept, artifact)
+	}
+	if s.policy.MaxBytes > 0 {
+		// kept is newest first; evict from the oldest end
+		for i := len(kept) - 1; i > 0 && usage > s.policy.MaxBytes; i-- {
+			remove(kept[i])
+			if _, ok := s.artifacts[kept[i].ID]; !ok {
+				usage -= kept[i].Size
+			}
+		}
+	}
+
+	for _, artifact := range removed {
+		s.logger.Infof("Removed diagnostics artifact %s (%s, %.2f MB) under the retention policy",
+			artifact.ID, artifact.Path, float64(artifact.Size)/(1024*1024))
+	}
+	if err := s.save(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return removed, firstErr
+}
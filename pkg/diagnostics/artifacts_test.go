@@ -0,0 +1,129 @@
+package diagnostics
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writeArtifact creates a file of size bytes to register
+func writeArtifact(t *testing.T, path string, size int) string {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestArtifactStore(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	baseDir := t.TempDir()
+	store := NewArtifactStore(logger, baseDir, RetentionPolicy{})
+	clock := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return clock }
+
+	capture := writeArtifact(t, filepath.Join(baseDir, "tcpdump-1", "capture.pcap"), 100)
+	first, err := store.Register("", &CollectionResult{Type: "tcpdump", FilePath: capture, Size: 100, Metadata: map[string]string{"duration": "60s"}})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if !strings.HasPrefix(first.ID, "tcpdump-20261016-090000-") || !first.Managed(baseDir) {
+		t.Errorf("Register() = %+v, expected a tcpdump ID under the base directory", first)
+	}
+	clock = clock.Add(time.Minute)
+	logs := writeArtifact(t, filepath.Join(baseDir, "logs-1", "app.log"), 50)
+	if _, err := store.Register("logs-1", &CollectionResult{Type: "logs", FilePath: filepath.Dir(logs), Size: 50}); err != nil {
+		t.Fatal(err)
+	}
+
+	if list := store.List(""); len(list) != 2 || list[0].ID != "logs-1" {
+		t.Errorf("List() = %+v, expected 2 artifacts, newest first", list)
+	}
+	if list := store.List("tcpdump"); len(list) != 1 || list[0].Metadata["duration"] != "60s" {
+		t.Errorf("List(tcpdump) = %+v, expected the capture", list)
+	}
+	if store.Usage() != 150 {
+		t.Errorf("Usage() = %d, expected 150", store.Usage())
+	}
+
+	// The registry survives a restart
+	reopened := NewArtifactStore(logger, baseDir, RetentionPolicy{})
+	if artifact, ok := reopened.Get(first.ID); !ok || artifact.Path != capture || artifact.Size != 100 {
+		t.Errorf("Get(%s) after reopening = %+v, %v", first.ID, artifact, ok)
+	}
+
+	if err := store.Delete("logs-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(logs)); !os.IsNotExist(err) {
+		t.Errorf("Delete() left %s behind: %v", filepath.Dir(logs), err)
+	}
+	if _, ok := store.Get("logs-1"); ok {
+		t.Error("Get() found a deleted artifact")
+	}
+	if err := store.Delete("logs-1"); err == nil {
+		t.Error("Delete() of an unknown ID returned no error")
+	}
+}
+
+func TestArtifactRetention(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	baseDir := t.TempDir()
+	outside := writeArtifact(t, filepath.Join(t.TempDir(), "chosen", "capture.pcap"), 500)
+
+	tests := []struct {
+		name     string
+		policy   RetentionPolicy
+		expected []string // remaining IDs, newest first
+	}{
+		{"no limits", RetentionPolicy{}, []string{"d", "c", "b", "a", "outside"}},
+		{"ttl", RetentionPolicy{TTL: 150 * time.Minute}, []string{"d", "c", "outside"}},
+		{"disk usage", RetentionPolicy{MaxBytes: 250}, []string{"d", "c", "outside"}},
+		{"newest kept", RetentionPolicy{MaxBytes: 10}, []string{"d", "outside"}},
+	}
+	for _, tt := range tests {
+		start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+		store := NewArtifactStore(logger, filepath.Join(baseDir, tt.name), RetentionPolicy{})
+		clock := start
+		store.now = func() time.Time { return clock }
+
+		// outside is the oldest and largest but was written where the caller chose
+		store.Register("outside", &CollectionResult{Type: "tcpdump", FilePath: outside, Size: 500})
+		for i, id := range []string{"a", "b", "c", "d"} {
+			clock = start.Add(time.Duration(i+1) * time.Hour)
+			path := writeArtifact(t, filepath.Join(store.BaseDir(), id, "app.log"), 100)
+			store.Register(id, &CollectionResult{Type: "logs", FilePath: filepath.Dir(path), Size: 100})
+		}
+
+		store.policy = tt.policy
+		clock = start.Add(5 * time.Hour)
+		removed, err := store.Cleanup()
+		if err != nil {
+			t.Fatalf("%s: Cleanup() error = %v", tt.name, err)
+		}
+		var remaining []string
+		for _, artifact := range store.List("") {
+			remaining = append(remaining, artifact.ID)
+		}
+		if strings.Join(remaining, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("%s: Cleanup() kept %v, expected %v", tt.name, remaining, tt.expected)
+		}
+		for _, artifact := range removed {
+			if _, err := os.Stat(artifact.Path); !os.IsNotExist(err) {
+				t.Errorf("%s: removed artifact %s still on disk", tt.name, artifact.ID)
+			}
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("retention removed an artifact outside the base directory: %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+const benchMB = 1 << 20
+
+// benchLogLines mixes the lines real application and operator logs are made
+// of: mostly routine output, some lines every pattern set matches, JSON
+// records and a Go panic with its stack
+var benchLogLines = []string{
+	`2026-10-16T09:00:%02d.123456Z INFO  http: GET /api/v1/orders/%d 200 12.4ms user=svc-checkout`,
+	`2026-10-16T09:00:%02d.223456Z INFO  cache: refreshed %d entries from configmap openshift-config/cluster-settings`,
+	`I1016 09:00:%02d.345678       1 controller.go:%d] Reconciling ClusterOperator "network"`,
+	`{"level":"info","ts":"2026-10-16T09:00:%02d.4Z","msg":"request served","status":200,"latency_ms":%d}`,
+	`2026-10-16T09:00:%02d.523456Z DEBUG db: pool stats open=%d idle=4 wait=0`,
+	`2026-10-16T09:00:%02d.623456Z WARN  retrying request to payments.svc:8443, attempt %d`,
+	`2026-10-16T09:00:%02d.723456Z ERROR dial tcp 172.30.12.4:5432: connect: connection refused (attempt %d)`,
+	`E1016 09:00:%02d.823456       1 reflector.go:%d] failed to list *v1.Pod: context deadline exceeded`,
+	`{"level":"error","ts":"2026-10-16T09:00:%02d.9Z","msg":"x509: certificate has expired or is not yet valid","attempt":%d}`,
+	`2026-10-16T09:00:%02d.023456Z ERROR write /var/lib/data/segment-%d: no space left on device`,
+}
+
+const benchPanic = `panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x4a2b3c]
+
+goroutine 1 [running]:
+main.(*Server).handle(0x0, {0x7ffd, 0x1})
+	/src/server.go:42 +0x1c
+main.main()
+	/src/main.go:17 +0x85
+`
+
+// benchLog returns about size bytes of log; one line in ten needs attention
+// and a panic appears every 600 lines
+func benchLog(size int) []byte {
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < size; i++ {
+		line := benchLogLines[i%6]
+		if i%10 == 9 {
+			line = benchLogLines[6+(i/10)%4]
+		}
+		fmt.Fprintf(&buf, line+"\n", i%60, i)
+		if i%600 == 599 {
+			buf.WriteString(benchPanic)
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeBenchFile(tb testing.TB, path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// writeBenchMustGather lays out a must-gather of about size bytes: cluster
+// resources, pod lists with failing pods, events and operator logs
+func writeBenchMustGather(tb testing.TB, dir string, size int) int64 {
+	cluster := filepath.Join(dir, "cluster-scoped-resources")
+	writeBenchFile(tb, filepath.Join(cluster, "config.openshift.io", "clusterversions.yaml"),
+		[]byte("items:\n- status:\n    desired:\n      version: 4.16.8\n    conditions:\n    - type: Failing\n      status: \"False\"\n"))
+
+	var operators, nodes, events bytes.Buffer
+	operators.WriteString("items:\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&operators, "- metadata:\n    name: operator-%d\n  status:\n    conditions:\n    - type: Degraded\n      status: \"%t\"\n    - type: Available\n      status: \"True\"\n", i, i%10 == 0)
+	}
+	nodes.WriteString("items:\n")
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&nodes, "- metadata:\n    name: worker-%d\n  status:\n    conditions:\n    - type: Ready\n      status: \"%t\"\n    - type: MemoryPressure\n      status: \"False\"\n", i, i != 3)
+	}
+	events.WriteString("items:\n")
+	for i := 0; i < 2000; i++ {
+		reason := []string{"BackOff", "FailedScheduling", "Pulled", "Created", "Unhealthy"}[i%5]
+		fmt.Fprintf(&events, "- type: %s\n  reason: %s\n  message: event %d for pod app-%d\n  lastTimestamp: \"2026-10-16T09:%02d:00Z\"\n  involvedObject:\n    kind: Pod\n    name: app-%d\n    namespace: team-%d\n",
+			map[bool]string{true: "Warning", false: "Normal"}[i%5 != 2 && i%5 != 3], reason, i, i, i%60, i, i%20)
+	}
+	writeBenchFile(tb, filepath.Join(cluster, "config.openshift.io", "clusteroperators.yaml"), operators.Bytes())
+	writeBenchFile(tb, filepath.Join(cluster, "core", "nodes.yaml"), nodes.Bytes())
+	writeBenchFile(tb, filepath.Join(cluster, "core", "events.yaml"), events.Bytes())
+
+	for i := 0; i < 20; i++ {
+		var pods bytes.Buffer
+		pods.WriteString("items:\n")
+		for j := 0; j < 25; j++ {
+			phase, reason := "Running", "Ready"
+			switch (i + j) % 17 {
+			case 0:
+				reason = "CrashLoopBackOff"
+			case 1:
+				phase, reason = "Pending", "ImagePullBackOff"
+			}
+			fmt.Fprintf(&pods, "- metadata:\n    name: app-%d\n  status:\n    phase: %s\n    containerStatuses:\n    - name: app\n      state:\n        waiting:\n          reason: %s\n", j, phase, reason)
+		}
+		writeBenchFile(tb, filepath.Join(dir, "namespaces", fmt.Sprintf("team-%d", i), "core", "pods.yaml"), pods.Bytes())
+	}
+
+	// Operator logs make up the rest of the bundle, as in real must-gathers
+	operatorNamespaces := []string{"openshift-etcd", "openshift-kube-apiserver", "openshift-ingress", "openshift-monitoring", "openshift-sdn", "openshift-authentication", "openshift-console", "openshift-dns"}
+	var total int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	perLog := (size - int(total)) / (len(operatorNamespaces) * 2)
+	for _, namespace := range operatorNamespaces {
+		for j := 0; j < 2; j++ {
+			log := benchLog(perLog)
+			writeBenchFile(tb, filepath.Join(dir, "namespaces", namespace, "pods", fmt.Sprintf("%s-%d", namespace, j), "operator", "operator", "logs", "current.log"), log)
+			total += int64(len(log))
+		}
+	}
+	return total
+}
+
+// benchCapture returns a capture of about size bytes: short HTTP flows, a
+// retransmitted segment every 50 flows, refused connections and DNS lookups
+// with some failures
+func benchCapture(size int) []byte {
+	const client, server, dns = "10.128.2.15", "10.129.0.40", "172.30.0.10"
+	b := newPcapBuilder()
+	at := time.Duration(0)
+	step := func() time.Duration { at += 200 * time.Microsecond; return at }
+	for flow := 0; b.buf.Len() < size; flow++ {
+		port := uint16(30000 + flow%30000)
+		seq, ack := uint32(flow*1000), uint32(flow*7000)
+		switch {
+		case flow%40 == 39:
+			b.packet(step(), ethernetIPv4(client, server, 6, tcpSegment(port, 9090, seq, 0, tcpSYN, 0)))
+			b.packet(step(), ethernetIPv4(server, client, 6, tcpSegment(9090, port, 0, seq+1, tcpRST|tcpACK, 0)))
+			continue
+		case flow%10 == 0:
+			rcode := layers.DNSResponseCodeNoErr
+			if flow%30 == 0 {
+				rcode = layers.DNSResponseCodeNXDomain
+			}
+			name := fmt.Sprintf("svc-%d.team.svc.cluster.local", flow%50)
+			b.packet(step(), ethernetIPv4(client, dns, 17, udpDatagram(port, 53, dnsPayload(uint16(flow), false, 0, name))))
+			b.packet(step(), ethernetIPv4(dns, client, 17, udpDatagram(53, port, dnsPayload(uint16(flow), true, rcode, name))))
+		}
+		b.packet(step(), ethernetIPv4(client, server, 6, tcpSegment(port, 8080, seq, 0, tcpSYN, 0)))
+		b.packet(step(), ethernetIPv4(server, client, 6, tcpSegment(8080, port, ack, seq+1, tcpSYN|tcpACK, 0)))
+		b.packet(step(), ethernetIPv4(client, server, 6, tcpSegment(port, 8080, seq+1, ack+1, tcpACK, 0)))
+		b.packet(step(), ethernetIPv4(client, server, 6, tcpSegment(port, 8080, seq+1, ack+1, tcpACK, 300)))
+		if flow%50 == 0 {
+			b.packet(step(), ethernetIPv4(client, server, 6, tcpSegment(port, 8080, seq+1, ack+1, tcpACK, 300)))
+		}
+		b.packet(step(), ethernetIPv4(server, client, 6, tcpSegment(8080, port, ack+1, seq+301, tcpACK, 1200)))
+		b.packet(step(), ethernetIPv4(client, server, 6, tcpSegment(port, 8080, seq+301, ack+1201, tcpFIN|tcpACK, 0)))
+		b.packet(step(), ethernetIPv4(server, client, 6, tcpSegment(8080, port, ack+1201, seq+302, tcpFIN|tcpACK, 0)))
+	}
+	return b.buf.Bytes()
+}
+
+// analyzerBenchmarks are the analyzers under performance budgets, each with
+// a fixture the size of a typical support case upload
+var analyzerBenchmarks = []struct {
+	name    string
+	fixture func(tb testing.TB, dir string) (path string, size int64)
+	analyze func(ctx context.Context, ae *AnalysisEngine, path string) error
+}{
+	{
+		name: "logs",
+		fixture: func(tb testing.TB, dir string) (string, int64) {
+			path := filepath.Join(dir, "app.log")
+			log := benchLog(2 * benchMB)
+			writeBenchFile(tb, path, log)
+			return path, int64(len(log))
+		},
+		analyze: func(ctx context.Context, ae *AnalysisEngine, path string) error {
+			_, err := ae.AnalyzeLogs(ctx, path)
+			return err
+		},
+	},
+	{
+		name: "must-gather",
+		fixture: func(tb testing.TB, dir string) (string, int64) {
+			return dir, writeBenchMustGather(tb, dir, 4*benchMB)
+		},
+		analyze: func(ctx context.Context, ae *AnalysisEngine, path string) error {
+			_, err := ae.AnalyzeMustGather(ctx, path)
+			return err
+		},
+	},
+	{
+		name: "pcap",
+		fixture: func(tb testing.TB, dir string) (string, int64) {
+			path := filepath.Join(dir, "capture.pcap")
+			capture := benchCapture(4 * benchMB)
+			writeBenchFile(tb, path, capture)
+			return path, int64(len(capture))
+		},
+		analyze: func(ctx context.Context, ae *AnalysisEngine, path string) error {
+			_, err := ae.AnalyzeTcpdump(ctx, path)
+			return err
+		},
+	},
+}
+
+// perfBudgets bound the cost of a cold analysis per MB of input. They sit
+// about 5x above what the benchmarks measure on a laptop, so only a real
+// regression, not a noisy CI runner, breaks them; when a change legitimately
+// needs more, raise the budget in the same change and say why.
+var perfBudgets = map[string]struct {
+	msPerMB     float64
+	allocsPerMB float64
+}{
+	"logs":        {msPerMB: 2500, allocsPerMB: 65000},
+	"must-gather": {msPerMB: 3200, allocsPerMB: 100000},
+	"pcap":        {msPerMB: 20, allocsPerMB: 45000},
+}
+
+// runAnalyzerBenchmark times cold analyses of the named fixture; the analysis
+// cache is off so every iteration does the full work
+func runAnalyzerBenchmark(b *testing.B, name string) {
+	for _, bench := range analyzerBenchmarks {
+		if bench.name != name {
+			continue
+		}
+		path, size := bench.fixture(b, b.TempDir())
+		ae := newTestAnalysisEngine()
+		ae.SetCacheEnabled(false)
+		ctx := context.Background()
+		b.SetBytes(size)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := bench.analyze(ctx, ae, path); err != nil {
+				b.Fatal(err)
+			}
+		}
+		return
+	}
+	b.Fatalf("no analyzer benchmark named %q", name)
+}
+
+func BenchmarkAnalyzeLogs(b *testing.B)       { runAnalyzerBenchmark(b, "logs") }
+func BenchmarkAnalyzeMustGather(b *testing.B) { runAnalyzerBenchmark(b, "must-gather") }
+func BenchmarkAnalyzeTcpdump(b *testing.B)    { runAnalyzerBenchmark(b, "pcap") }
+
+// TestPerformanceBudgets fails when an analyzer exceeds its budget. Timing is
+// meaningless under -race or on a loaded machine, so it only runs when
+// PERF_BUDGETS is set (make perf-budgets).
+func TestPerformanceBudgets(t *testing.T) {
+	if os.Getenv("PERF_BUDGETS") == "" {
+		t.Skip("set PERF_BUDGETS=1 to check analyzer performance budgets")
+	}
+	for _, bench := range analyzerBenchmarks {
+		budget, ok := perfBudgets[bench.name]
+		if !ok {
+			t.Errorf("analyzer %q has no performance budget", bench.name)
+			continue
+		}
+		result := testing.Benchmark(func(b *testing.B) { runAnalyzerBenchmark(b, bench.name) })
+		if result.N == 0 {
+			t.Errorf("%s benchmark failed", bench.name)
+			continue
+		}
+		mb := float64(result.Bytes) / benchMB
+		msPerMB := float64(result.NsPerOp()) / 1e6 / mb
+		allocsPerMB := float64(result.AllocsPerOp()) / mb
+		t.Logf("%s: %.1f ms/MB (budget %.0f), %.0f allocs/MB (budget %.0f)", bench.name, msPerMB, budget.msPerMB, allocsPerMB, budget.allocsPerMB)
+		if msPerMB > budget.msPerMB {
+			t.Errorf("%s analysis takes %.1f ms/MB, over its %.0f ms/MB budget", bench.name, msPerMB, budget.msPerMB)
+		}
+		if allocsPerMB > budget.allocsPerMB {
+			t.Errorf("%s analysis makes %.0f allocations/MB, over its %.0f allocs/MB budget", bench.name, allocsPerMB, budget.allocsPerMB)
+		}
+	}
+}
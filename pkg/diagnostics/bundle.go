@@ -0,0 +1,323 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// BundleIndexFile is the first entry of every bundle and describes the rest
+	BundleIndexFile = "index.json"
+	// BundleExtension is appended to the collected file or directory
+	BundleExtension = ".tar.gz"
+
+	bundleIndexVersion = 1
+)
+
+// BundleIndex describes a packaged collection: what was collected, with
+// which options, and every file in the archive
+type BundleIndex struct {
+	Version     int                `json:"version"`
+	Type        string             `json:"type"` // the collection type, e.g. logs or tcpdump
+	Status      string             `json:"status"`
+	Summary     string             `json:"summary,omitempty"`
+	Source      string             `json:"source"` // the packaged file or directory
+	CollectedAt time.Time          `json:"collected_at"`
+	Duration    string             `json:"duration"`
+	Options     *CollectionOptions `json:"options,omitempty"`
+	Metadata    map[string]string  `json:"metadata,omitempty"`
+	TotalBytes  int64              `json:"total_bytes"`
+	Files       []BundleFile       `json:"files"`
+}
+
+// BundleFile is one file in a bundle
+type BundleFile struct {
+	Path     string    `json:"path"` // slash-separated, relative to the archive root
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// Bundle is a bundle unpacked for analysis; Close removes the unpacked files
+type Bundle struct {
+	Path  string // the archive
+	Dir   string // where it was unpacked
+	Index BundleIndex
+}
+
+// Close removes the unpacked files
+func (b *Bundle) Close() error {
+	return os.RemoveAll(b.Dir)
+}
+
+// Find returns the unpacked path of the first indexed file with one of the
+// extensions, or "" when there is none
+func (b *Bundle) Find(extensions ...string) string {
+	for _, file := range b.Index.Files {
+		for _, ext := range extensions {
+			if strings.HasSuffix(strings.ToLower(file.Path), ext) {
+				return filepath.Join(b.Dir, filepath.FromSlash(file.Path))
+			}
+		}
+	}
+	return ""
+}
+
+// IsBundle reports whether path looks like a packaged collection
+func IsBundle(path string) bool {
+	name := strings.ToLower(path)
+	if !strings.HasSuffix(name, BundleExtension) && !strings.HasSuffix(name, ".tgz") {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// packageIfRequested packages a completed collection when opts.Compressed is
+// set. A failure leaves the collection unpackaged and is only logged, since
+// the data itself was collected.
+func (dc *DiagnosticCollector) packageIfRequested(result *CollectionResult, opts *CollectionOptions) {
+	if opts == nil || !opts.Compressed || result.Status != "completed" {
+		return
+	}
+	if err := dc.PackageBundle(result, opts); err != nil {
+		dc.logger.Warnf("Failed to package %s collection: %v", result.Type, err)
+		result.Metadata["bundle_error"] = err.Error()
+	}
+}
+
+// PackageBundle writes the collection at result.FilePath, a file or a
+// directory, to a single tar.gz next to it with an index.json first, then
+// removes the original. result points at the bundle afterwards.
+func (dc *DiagnosticCollector) PackageBundle(result *CollectionResult, opts *CollectionOptions) error {
+	source := filepath.Clean(result.FilePath)
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	root := source
+	if !info.IsDir() {
+		root = filepath.Dir(source)
+	}
+
+	index := BundleIndex{
+		Version:     bundleIndexVersion,
+		Type:        result.Type,
+		Status:      result.Status,
+		Summary:     result.Summary,
+		Source:      source,
+		CollectedAt: time.Now().Add(-result.Duration).UTC(),
+		Duration:    result.Duration.Round(time.Millisecond).String(),
+		Options:     opts,
+		Metadata:    result.Metadata,
+	}
+	var paths []string
+	err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		index.Files = append(index.Files, BundleFile{Path: filepath.ToSlash(rel), Size: info.Size(), Modified: info.ModTime().UTC()})
+		index.TotalBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	bundlePath := source + BundleExtension
+	if err := writeBundle(bundlePath, &index, paths); err != nil {
+		os.Remove(bundlePath)
+		return err
+	}
+	if err := os.RemoveAll(source); err != nil {
+		dc.logger.Warnf("Failed to remove %s after packaging: %v", source, err)
+	}
+
+	size, err := dc.getFileSize(bundlePath)
+	if err != nil {
+		return err
+	}
+	result.FilePath = bundlePath
+	result.Size = size
+	result.Metadata["bundle"] = bundlePath
+	result.Metadata["bundle_files"] = strconv.Itoa(len(index.Files))
+	result.Metadata["uncompressed_bytes"] = strconv.FormatInt(index.TotalBytes, 10)
+	result.Summary += fmt.Sprintf("; packaged into %s (%.2f MB)", bundlePath, float64(size)/(1024*1024))
+	return nil
+}
+
+// writeBundle writes the index and then the indexed files
+func writeBundle(bundlePath string, index *BundleIndex, paths []string) error {
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	archive := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: BundleIndexFile, Mode: 0644, Size: int64(len(data)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := archive.Write(data); err != nil {
+		return err
+	}
+
+	for i, path := range paths {
+		entry := index.Files[i]
+		header := &tar.Header{Name: entry.Path, Mode: 0644, Size: entry.Size, ModTime: entry.Modified, Typeflag: tar.TypeReg}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		source, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.CopyN(archive, source, entry.Size)
+		source.Close()
+		if err != nil {
+			return fmt.Errorf("adding %s: %v", entry.Path, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// OpenBundle unpacks a bundle into a temporary directory. Only the files its
+// index lists are unpacked, at most their indexed size each, so a crafted
+// archive cannot write elsewhere or expand without bound.
+func OpenBundle(path string) (*Bundle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a gzip bundle: %v", path, err)
+	}
+	defer gz.Close()
+	archive := tar.NewReader(gz)
+
+	header, err := archive.Next()
+	if err != nil || header.Name != BundleIndexFile {
+		return nil, fmt.Errorf("%s has no %s first; it was not packaged by a collection", path, BundleIndexFile)
+	}
+	bundle := &Bundle{Path: path}
+	if err := json.NewDecoder(io.LimitReader(archive, 16<<20)).Decode(&bundle.Index); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", BundleIndexFile, err)
+	}
+	sizes := make(map[string]int64, len(bundle.Index.Files))
+	for _, entry := range bundle.Index.Files {
+		sizes[entry.Path] = entry.Size
+	}
+
+	bundle.Dir, err = os.MkdirTemp("", "bundle-")
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Clean(bundle.Dir) + string(filepath.Separator)
+	fail := func(err error) (*Bundle, error) {
+		bundle.Close()
+		return nil, err
+	}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(fmt.Errorf("reading %s: %v", path, err))
+		}
+		size, indexed := sizes[header.Name]
+		if !indexed || header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(bundle.Dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, root) {
+			return fail(fmt.Errorf("bundle entry %q is outside the bundle", header.Name))
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fail(err)
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return fail(err)
+		}
+		_, err = io.Copy(out, io.LimitReader(archive, size))
+		out.Close()
+		if err != nil {
+			return fail(fmt.Errorf("unpacking %s: %v", header.Name, err))
+		}
+		delete(sizes, header.Name)
+	}
+	for _, entry := range bundle.Index.Files {
+		if _, missing := sizes[entry.Path]; missing {
+			return fail(fmt.Errorf("%s is truncated: %d indexed files are missing, including %s", path, len(sizes), entry.Path))
+		}
+	}
+	return bundle, nil
+}
+
+// analyzeBundle unpacks a bundle, analyzes the path within it that target
+// picks and reports locations relative to the bundle instead of the
+// temporary directory
+func analyzeBundle(bundlePath string, target func(*Bundle) (string, error), analyze func(path string) (*AnalysisResult, error)) (*AnalysisResult, error) {
+	bundle, err := OpenBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer bundle.Close()
+	path, err := target(bundle)
+	if err != nil {
+		return nil, err
+	}
+	result, err := analyze(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result.FilePath = bundlePath
+	result.Metrics["bundle_type"] = bundle.Index.Type
+	result.Metrics["bundle_files"] = len(bundle.Index.Files)
+	for i := range result.Issues {
+		result.Issues[i].Location = strings.Replace(result.Issues[i].Location, bundle.Dir, bundlePath, 1)
+	}
+	return result, nil
+}
+
+// bundleDir analyzes everything in a bundle
+func bundleDir(bundle *Bundle) (string, error) {
+	return bundle.Dir, nil
+}
+
+// bundleCapture analyzes the packet capture in a bundle
+func bundleCapture(bundle *Bundle) (string, error) {
+	if path := bundle.Find(".pcap", ".pcapng", ".cap"); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("bundle %s (%s) contains no packet capture", bundle.Path, bundle.Index.Type)
+}
@@ -0,0 +1,176 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestCollector(t *testing.T) *DiagnosticCollector {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewDiagnosticCollector(logger, t.TempDir())
+}
+
+func TestPackageBundle(t *testing.T) {
+	dc := newTestCollector(t)
+	source := filepath.Join(t.TempDir(), "logs-shop")
+	files := map[string]string{
+		"api-1/api.log": "2026-10-16T09:00:00Z ERROR dial tcp 10.0.0.1:5432: connect: connection refused\n",
+		"api-2/api.log": "2026-10-16T09:00:01Z INFO started\n",
+		LogManifestFile: `{"namespace": "shop"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(source, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := &CollectionOptions{Namespace: "shop", Compressed: true}
+	result := &CollectionResult{Type: "logs", Status: "completed", FilePath: source, Duration: 2 * time.Second, Metadata: map[string]string{"pods": "2"}}
+	dc.packageIfRequested(result, opts)
+	if result.Metadata["bundle_error"] != "" {
+		t.Fatalf("packaging failed: %s", result.Metadata["bundle_error"])
+	}
+	if result.FilePath != source+BundleExtension || result.Metadata["bundle_files"] != "3" {
+		t.Errorf("result = %+v, expected it to point at the bundle of 3 files", result)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("the packaged directory was left behind: %v", err)
+	}
+	if !IsBundle(result.FilePath) {
+		t.Errorf("IsBundle(%q) = false", result.FilePath)
+	}
+
+	bundle, err := OpenBundle(result.FilePath)
+	if err != nil {
+		t.Fatalf("OpenBundle() error = %v", err)
+	}
+	defer bundle.Close()
+	index := bundle.Index
+	if index.Type != "logs" || index.Options == nil || index.Options.Namespace != "shop" || index.Metadata["pods"] != "2" || index.Duration != "2s" {
+		t.Errorf("index = %+v, expected the collection type, options and metadata", index)
+	}
+	if len(index.Files) != len(files) {
+		t.Fatalf("index lists %d files, expected %d", len(index.Files), len(files))
+	}
+	var total int64
+	for _, entry := range index.Files {
+		content, err := os.ReadFile(filepath.Join(bundle.Dir, entry.Path))
+		if err != nil || string(content) != files[entry.Path] || entry.Size != int64(len(content)) || entry.Modified.IsZero() {
+			t.Errorf("%s = %q, %v, expected %q as indexed (%+v)", entry.Path, content, err, files[entry.Path], entry)
+		}
+		total += entry.Size
+	}
+	if index.TotalBytes != total {
+		t.Errorf("index total = %d, expected %d", index.TotalBytes, total)
+	}
+
+	// Uncompressed collections are left alone
+	plain := &CollectionResult{Type: "logs", Status: "completed", FilePath: t.TempDir(), Metadata: map[string]string{}}
+	dc.packageIfRequested(plain, &CollectionOptions{})
+	if plain.Metadata["bundle"] != "" {
+		t.Errorf("a collection without compressed was packaged: %v", plain.Metadata)
+	}
+}
+
+func TestAnalyzeBundles(t *testing.T) {
+	dc := newTestCollector(t)
+	ae := newTestAnalysisEngine()
+
+	logDir := filepath.Join(t.TempDir(), "logs-1")
+	os.MkdirAll(filepath.Join(logDir, "api-1"), 0755)
+	os.WriteFile(filepath.Join(logDir, "api-1", "api.log"), []byte("ERROR dial tcp 10.0.0.1:5432: connect: connection refused\n"), 0644)
+	logs := &CollectionResult{Type: "logs", Status: "completed", FilePath: logDir, Metadata: map[string]string{}}
+	if err := dc.PackageBundle(logs, &CollectionOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ae.AnalyzeLogs(context.Background(), logs.FilePath)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs(bundle) error = %v", err)
+	}
+	if result.FilePath != logs.FilePath || result.Metrics["bundle_type"] != "logs" || len(result.Issues) == 0 {
+		t.Fatalf("AnalyzeLogs(bundle) = %+v, expected issues from the bundled log", result)
+	}
+	for _, issue := range result.Issues {
+		if !strings.HasPrefix(issue.Location, logs.FilePath) {
+			t.Errorf("issue location %q is not inside the bundle", issue.Location)
+		}
+	}
+
+	b := newPcapBuilder()
+	b.packet(0, ethernetIPv4("10.0.0.1", "10.0.0.2", 6, tcpSegment(40000, 80, 1, 0, tcpSYN, 0)))
+	b.packet(time.Millisecond, ethernetIPv4("10.0.0.2", "10.0.0.1", 6, tcpSegment(80, 40000, 0, 2, tcpRST|tcpACK, 0)))
+	capture := writeCapture(t, b.buf.Bytes())
+	tcpdump := &CollectionResult{Type: "tcpdump", Status: "completed", FilePath: capture, Metadata: map[string]string{}}
+	if err := dc.PackageBundle(tcpdump, &CollectionOptions{PodName: "api-1"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err = ae.AnalyzeTcpdump(context.Background(), tcpdump.FilePath)
+	if err != nil {
+		t.Fatalf("AnalyzeTcpdump(bundle) error = %v", err)
+	}
+	if result.FilePath != tcpdump.FilePath || result.Metrics["packets"] != 2 {
+		t.Errorf("AnalyzeTcpdump(bundle) = %+v, expected the bundled capture's 2 packets", result.Metrics)
+	}
+	if _, err := ae.AnalyzeTcpdump(context.Background(), logs.FilePath); err == nil || !strings.Contains(err.Error(), "no packet capture") {
+		t.Errorf("AnalyzeTcpdump(log bundle) error = %v, expected no packet capture", err)
+	}
+}
+
+// writeTarGz writes entries, name then content, as a gzipped tar
+func writeTarGz(t *testing.T, entries ...string) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	for i := 0; i < len(entries); i += 2 {
+		archive.WriteHeader(&tar.Header{Name: entries[i], Mode: 0644, Size: int64(len(entries[i+1])), Typeflag: tar.TypeReg})
+		archive.Write([]byte(entries[i+1]))
+	}
+	archive.Close()
+	gz.Close()
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpenBundleRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []string
+		expected string
+	}{
+		{"no index", []string{"app.log", "hello"}, "has no index.json"},
+		{"missing file", []string{BundleIndexFile, `{"files": [{"path": "app.log", "size": 5}]}`}, "is truncated"},
+		{"escaping entry", []string{BundleIndexFile, `{"files": [{"path": "../evil", "size": 4}]}`, "../evil", "evil"}, "outside the bundle"},
+	}
+	for _, tt := range tests {
+		bundle, err := OpenBundle(writeTarGz(t, tt.entries...))
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("OpenBundle(%s) = %v, %v, expected an error containing %q", tt.name, bundle, err, tt.expected)
+		}
+	}
+
+	// Entries the index does not list are not unpacked
+	bundle, err := OpenBundle(writeTarGz(t, BundleIndexFile, `{"files": [{"path": "app.log", "size": 5}]}`, "app.log", "hello", "extra.log", "ignored"))
+	if err != nil {
+		t.Fatalf("OpenBundle() error = %v", err)
+	}
+	defer bundle.Close()
+	if _, err := os.Stat(filepath.Join(bundle.Dir, "extra.log")); !os.IsNotExist(err) {
+		t.Errorf("an unindexed entry was unpacked: %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package diagnostics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// analysisCacheVersion must be bumped whenever patterns or analyzers change in
+// a way that would make previously cached issues stale.
+const analysisCacheVersion = 5
+
+// analysisCacheFile is the name of the cache stored alongside an artifact
+const analysisCacheFile = ".analysis-cache.json"
+
+// analysisCache stores per-file analysis results keyed by content hash so that
+// unchanged files (or whole unchanged bundles) are not re-analyzed.
+type analysisCache struct {
+	mu     sync.Mutex
+	path   string
+	root   string
+	data   cacheData
+	dirty  bool
+	hits   int
+	misses int
+
+	// seen tracks files touched during this analysis so entries for files
+	// that no longer exist can be pruned on save
+	seen map[string]bool
+}
+
+type cacheData struct {
+	Version int                     `json:"version"`
+	Files   map[string]*cachedFile  `json:"files"`
+	Results map[string]cachedResult `json:"results"`
+}
+
+// cachedFile holds the content hash of a file and the issues found per pattern set
+type cachedFile struct {
+	Size    int64              `json:"size"`
+	ModTime time.Time          `json:"mod_time"`
+	Hash    string             `json:"hash"`
+	Issues  map[string][]Issue `json:"issues,omitempty"`
+}
+
+// cachedResult holds a complete analysis result for a bundle fingerprint
+type cachedResult struct {
+	Fingerprint string          `json:"fingerprint"`
+	Result      *AnalysisResult `json:"result"`
+}
+
+// cachePathFor returns where the cache for an artifact lives: inside the
+// directory for bundles, next to the file for single artifacts.
+func cachePathFor(artifactPath string) (string, string) {
+	info, err := os.Stat(artifactPath)
+	if err == nil && info.IsDir() {
+		return filepath.Join(artifactPath, analysisCacheFile), artifactPath
+	}
+	dir := filepath.Dir(artifactPath)
+	return filepath.Join(dir, "."+filepath.Base(artifactPath)+analysisCacheFile), dir
+}
+
+// loadAnalysisCache loads the cache for an artifact, starting empty when the
+// cache is missing, unreadable or from an older analyzer version.
+func loadAnalysisCache(artifactPath string) *analysisCache {
+	path, root := cachePathFor(artifactPath)
+	cache := &analysisCache{
+		path: path,
+		root: root,
+		seen: make(map[string]bool),
+		data: cacheData{
+			Version: analysisCacheVersion,
+			Files:   make(map[string]*cachedFile),
+			Results: make(map[string]cachedResult),
+		},
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	var stored cacheData
+	if err := json.Unmarshal(raw, &stored); err != nil || stored.Version != analysisCacheVersion {
+		return cache
+	}
+	if stored.Files != nil {
+		cache.data.Files = stored.Files
+	}
+	if stored.Results != nil {
+		cache.data.Results = stored.Results
+	}
+	return cache
+}
+
+// key returns the cache key for a file, relative to the artifact root
+func (c *analysisCache) key(filePath string) string {
+	if rel, err := filepath.Rel(c.root, filePath); err == nil {
+		return rel
+	}
+	return filePath
+}
+
+// fileHash returns the content hash of a file. Files whose size and
+// modification time match the cached entry reuse the stored hash.
+func (c *analysisCache) fileHash(filePath string, info os.FileInfo) (string, error) {
+	key := c.key(filePath)
+
+	c.mu.Lock()
+	c.seen[key] = true
+	entry, ok := c.data.Files[key]
+	c.mu.Unlock()
+	if ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return entry.Hash, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !ok || entry.Hash != hash {
+		entry = &cachedFile{Hash: hash}
+		c.data.Files[key] = entry
+	}
+	entry.Size = info.Size()
+	entry.ModTime = info.ModTime()
+	c.dirty = true
+
+	return hash, nil
+}
+
+// issues returns cached issues for a file and pattern set if the hash matches
+func (c *analysisCache) issues(filePath, patternSet, hash string) ([]Issue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.data.Files[c.key(filePath)]
+	if !ok || entry.Hash != hash || entry.Issues == nil {
+		c.misses++
+		return nil, false
+	}
+	issues, ok := entry.Issues[patternSet]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	return issues, true
+}
+
+// storeIssues records the issues found in a file for a pattern set
+func (c *analysisCache) storeIssues(filePath, patternSet, hash string, issues []Issue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := c.key(filePath)
+	c.seen[key] = true
+	entry, ok := c.data.Files[key]
+	if !ok || entry.Hash != hash {
+		entry = &cachedFile{Hash: hash}
+		c.data.Files[key] = entry
+	}
+	if entry.Issues == nil {
+		entry.Issues = make(map[string][]Issue)
+	}
+	if issues == nil {
+		issues = []Issue{}
+	}
+	entry.Issues[patternSet] = issues
+	c.dirty = true
+}
+
+// fingerprint hashes the relative paths and content hashes of every file in
+// the artifact, excluding the cache itself.
+func (c *analysisCache) fingerprint() (string, error) {
+	var entries []string
+	err := filepath.Walk(c.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() || strings.HasPrefix(path, c.path) {
+			return nil
+		}
+		hash, err := c.fileHash(path, info)
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, c.key(path)+"="+hash)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(entries)
+	hasher := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintln(hasher, entry)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// result returns a copy of the cached full analysis result for a fingerprint
+func (c *analysisCache) result(kind, fingerprint string) (*AnalysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.data.Results[kind]
+	if !ok || cached.Fingerprint != fingerprint || cached.Result == nil {
+		return nil, false
+	}
+	return copyResult(cached.Result), true
+}
+
+// storeResult records a copy of a full analysis result for a fingerprint, so
+// later changes to the caller's result do not leak into the cache
+func (c *analysisCache) storeResult(kind, fingerprint string, result *AnalysisResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data.Results[kind] = cachedResult{Fingerprint: fingerprint, Result: copyResult(result)}
+	c.dirty = true
+}
+
+// copyResult copies a result deeply enough that metrics and issue lists are not shared
+func copyResult(result *AnalysisResult) *AnalysisResult {
+	copied := *result
+	copied.budget = nil
+	copied.events = nil
+	copied.Issues = append([]Issue(nil), result.Issues...)
+	copied.Recommendations = append([]string(nil), result.Recommendations...)
+	copied.TruncationReasons = append([]string(nil), result.TruncationReasons...)
+	copied.Timeline = append([]TimelineEvent(nil), result.Timeline...)
+	copied.Correlations = append([]Correlation(nil), result.Correlations...)
+	copied.Metrics = make(map[string]interface{}, len(result.Metrics))
+	for key, value := range result.Metrics {
+		copied.Metrics[key] = value
+	}
+	return &copied
+}
+
+// stats returns the number of cache hits and misses for per-file lookups
+func (c *analysisCache) stats() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// save writes the cache next to the artifact if anything changed
+func (c *analysisCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop entries for files that were not part of this analysis
+	if len(c.seen) > 0 {
+		for key := range c.data.Files {
+			if !c.seen[key] {
+				delete(c.data.Files, key)
+				c.dirty = true
+			}
+		}
+	}
+
+	if !c.dirty {
+		return nil
+	}
+
+	raw, err := json.Marshal(c.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis cache: %v", err)
+	}
+
+	// Write to a unique temporary file so concurrent analyses of the same
+	// artifact never share a partially written file
+	tmpFile, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write analysis cache: %v", err)
+	}
+	tmpPath := tmpFile.Name()
+	if _, err := tmpFile.Write(raw); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write analysis cache: %v", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write analysis cache: %v", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write analysis cache: %v", err)
+	}
+
+	c.dirty = false
+	return nil
+}
@@ -0,0 +1,126 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestEngine() *AnalysisEngine {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewAnalysisEngine(logger)
+}
+
+func TestAnalyzeLogsReusesCacheForUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	appLog := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(appLog, []byte("dial tcp 10.0.0.1:5432: connection refused\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	otherLog := filepath.Join(dir, "other.log")
+	if err := os.WriteFile(otherLog, []byte("all good\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	first, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("first AnalyzeLogs failed: %v", err)
+	}
+	if first.Metrics["cached_files"] != 0 {
+		t.Errorf("first run cached_files = %v, expected 0", first.Metrics["cached_files"])
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, analysisCacheFile)); err != nil {
+		t.Fatalf("cache file was not written alongside the artifact: %v", err)
+	}
+
+	// Change one file; the other should come from the cache
+	if err := os.WriteFile(otherLog, []byte("no space left on device\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("second AnalyzeLogs failed: %v", err)
+	}
+	if second.Metrics["cached_files"] != 1 || second.Metrics["analyzed_files"] != 1 {
+		t.Errorf("second run cached_files=%v analyzed_files=%v, expected 1 and 1",
+			second.Metrics["cached_files"], second.Metrics["analyzed_files"])
+	}
+	if len(second.Issues) != 2 {
+		t.Errorf("second run found %d issues, expected 2", len(second.Issues))
+	}
+}
+
+func TestAnalyzeMustGatherReturnsCachedResult(t *testing.T) {
+	dir := t.TempDir()
+	podsDir := filepath.Join(dir, "namespaces", "app", "core")
+	if err := os.MkdirAll(podsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(podsDir, "pods.yaml"), []byte("reason: CrashLoopBackOff\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	first, err := engine.AnalyzeMustGather(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("first AnalyzeMustGather failed: %v", err)
+	}
+	if _, ok := first.Metrics["cache"]; ok {
+		t.Errorf("first run unexpectedly reported a cache hit")
+	}
+
+	second, err := engine.AnalyzeMustGather(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("second AnalyzeMustGather failed: %v", err)
+	}
+	if second.Metrics["cache"] != "hit" {
+		t.Errorf("second run cache metric = %v, expected hit", second.Metrics["cache"])
+	}
+	if len(second.Issues) != len(first.Issues) {
+		t.Errorf("cached result has %d issues, expected %d", len(second.Issues), len(first.Issues))
+	}
+	if !second.Timestamp.After(first.Timestamp) {
+		t.Errorf("cached result timestamp %v is not newer than the first run %v", second.Timestamp, first.Timestamp)
+	}
+	if _, ok := second.Metrics["analyzed_files"]; ok {
+		t.Errorf("cached result carries the previous run's analyzed_files metric")
+	}
+}
+
+func TestAnalysisCachePrunesRemovedFiles(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.log")
+	gone := filepath.Join(dir, "gone.log")
+	for _, path := range []string{keep, gone} {
+		if err := os.WriteFile(path, []byte("connection refused\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := newTestEngine()
+	if _, err := engine.AnalyzeLogs(context.Background(), dir); err != nil {
+		t.Fatalf("first AnalyzeLogs failed: %v", err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.AnalyzeLogs(context.Background(), dir); err != nil {
+		t.Fatalf("second AnalyzeLogs failed: %v", err)
+	}
+
+	cache := loadAnalysisCache(dir)
+	if _, ok := cache.data.Files["gone.log"]; ok {
+		t.Errorf("cache still holds an entry for a removed file")
+	}
+	if _, ok := cache.data.Files["keep.log"]; !ok {
+		t.Errorf("cache lost the entry for an unchanged file")
+	}
+}
@@ -0,0 +1,625 @@
+package diagnostics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Share of allocatable CPU or memory requested at which a node is
+	// reported as nearly full
+	capacityRequestedWarning = 0.9
+
+	// Share of node memory between the eviction threshold and what is
+	// available at which a node is reported as close to evicting pods
+	capacityEvictionMargin = 0.05
+)
+
+// Why the scheduler rejected nodes, as classified by ParseSchedulingFailure
+const (
+	SchedulingInsufficientCPU    = "insufficient-cpu"
+	SchedulingInsufficientMemory = "insufficient-memory"
+	SchedulingInsufficientOther  = "insufficient-resource" // GPUs, ephemeral storage and other extended resources
+	SchedulingTooManyPods        = "too-many-pods"
+	SchedulingTaint              = "taint"
+	SchedulingNodeSelector       = "node-selector"
+	SchedulingPodAffinity        = "pod-affinity" // pod (anti-)affinity and topology spread constraints
+	SchedulingUnschedulable      = "unschedulable"
+	SchedulingVolume             = "volume"
+	SchedulingPorts              = "host-ports"
+	SchedulingOther              = "other"
+)
+
+// EvictionThresholds are the kubelet's hard eviction thresholds. The
+// kubelet evicts pods once available memory or disk drops below them.
+type EvictionThresholds struct {
+	MemoryAvailable  int64   `json:"memory_available"`   // bytes
+	NodefsAvailable  float64 `json:"nodefs_available"`   // share of the root filesystem
+	ImagefsAvailable float64 `json:"imagefs_available"`  // share of the image filesystem
+	NodefsInodesFree float64 `json:"nodefs_inodes_free"` // share of root filesystem inodes
+}
+
+// DefaultEvictionThresholds returns the kubelet's default evictionHard
+// settings, which OpenShift keeps unless a KubeletConfig changes them
+func DefaultEvictionThresholds() EvictionThresholds {
+	return EvictionThresholds{
+		MemoryAvailable:  100 * 1024 * 1024,
+		NodefsAvailable:  0.10,
+		ImagefsAvailable: 0.15,
+		NodefsInodesFree: 0.05,
+	}
+}
+
+// NodeCapacity is what a node offers the scheduler and how close it is to
+// evicting pods. CPU is in millicores and memory in bytes; MemoryWorkingSet
+// is zero when the metrics API did not report the node.
+type NodeCapacity struct {
+	Name              string   `json:"name"`
+	Ready             bool     `json:"ready"`
+	Unschedulable     bool     `json:"unschedulable"`
+	Taints            []string `json:"taints,omitempty"`    // NoSchedule and NoExecute taints, as key=value:effect
+	Pressures         []string `json:"pressures,omitempty"` // pressure conditions that are True, e.g. MemoryPressure
+	CapacityMemory    int64    `json:"capacity_memory"`
+	AllocatableCPU    int64    `json:"allocatable_cpu"`
+	AllocatableMemory int64    `json:"allocatable_memory"`
+	AllocatablePods   int64    `json:"allocatable_pods"`
+	RequestedCPU      int64    `json:"requested_cpu"`
+	RequestedMemory   int64    `json:"requested_memory"`
+	Pods              int      `json:"pods"` // pods that are neither Succeeded nor Failed
+	MemoryWorkingSet  int64    `json:"memory_working_set,omitempty"`
+}
+
+// schedulable reports whether new pods can land on the node at all
+func (n NodeCapacity) schedulable() bool {
+	return n.Ready && !n.Unschedulable
+}
+
+// PodPlacement is a pod that is Pending or was evicted. SchedulingMessage
+// is the message of its PodScheduled=False condition or FailedScheduling
+// event; Message is the pod status message, which explains evictions.
+type PodPlacement struct {
+	Namespace         string `json:"namespace"`
+	Name              string `json:"name"`
+	Phase             string `json:"phase"`
+	Reason            string `json:"reason,omitempty"` // pod status reason, e.g. Evicted
+	Message           string `json:"message,omitempty"`
+	NodeName          string `json:"node_name,omitempty"`
+	SchedulingMessage string `json:"scheduling_message,omitempty"`
+	RequestedCPU      int64  `json:"requested_cpu"`
+	RequestedMemory   int64  `json:"requested_memory"`
+}
+
+// Evicted reports whether the kubelet evicted the pod
+func (p PodPlacement) Evicted() bool {
+	return p.Reason == "Evicted"
+}
+
+// SchedulingReason is one reason the scheduler gave for rejecting nodes
+type SchedulingReason struct {
+	Nodes  int    `json:"nodes"` // nodes rejected for this reason; 0 when the message gives no count
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// SchedulingFailure is a parsed "0/6 nodes are available: ..." message
+type SchedulingFailure struct {
+	Available int                `json:"available"`
+	Total     int                `json:"total"`
+	Reasons   []SchedulingReason `json:"reasons"`
+}
+
+// Has reports whether the scheduler rejected nodes for a kind of reason
+func (f SchedulingFailure) Has(kind string) bool {
+	for _, reason := range f.Reasons {
+		if reason.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	schedulingSummaryPattern = regexp.MustCompile(`(\d+)/(\d+) nodes are available:\s*(.*)`)
+	schedulingCountPattern   = regexp.MustCompile(`^(\d+)\s+(.*)$`)
+	evictionResourcePattern  = regexp.MustCompile(`low on resource: ([A-Za-z0-9./-]+)`)
+)
+
+// ParseSchedulingFailure reads the scheduler's explanation of why a pod
+// fits no node. The preemption part of the message is ignored.
+func ParseSchedulingFailure(message string) (SchedulingFailure, bool) {
+	matches := schedulingSummaryPattern.FindStringSubmatch(message)
+	if matches == nil {
+		return SchedulingFailure{}, false
+	}
+	failure := SchedulingFailure{}
+	failure.Available, _ = strconv.Atoi(matches[1])
+	failure.Total, _ = strconv.Atoi(matches[2])
+
+	detail := matches[3]
+	if i := strings.Index(detail, " preemption:"); i >= 0 {
+		detail = detail[:i]
+	}
+	for _, part := range splitOutsideBraces(strings.TrimSpace(detail)) {
+		part = strings.TrimSuffix(strings.TrimSpace(part), ".")
+		if part == "" {
+			continue
+		}
+		reason := SchedulingReason{Detail: part}
+		if count := schedulingCountPattern.FindStringSubmatch(part); count != nil {
+			reason.Nodes, _ = strconv.Atoi(count[1])
+			reason.Detail = count[2]
+		}
+		reason.Kind = schedulingKind(reason.Detail)
+		failure.Reasons = append(failure.Reasons, reason)
+	}
+	return failure, true
+}
+
+// splitOutsideBraces splits on ", " except inside the {key: value} of a taint
+func splitOutsideBraces(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// schedulingKind classifies one scheduler reason
+func schedulingKind(detail string) string {
+	lower := strings.ToLower(detail)
+	switch {
+	case strings.HasPrefix(lower, "insufficient cpu"):
+		return SchedulingInsufficientCPU
+	case strings.HasPrefix(lower, "insufficient memory"):
+		return SchedulingInsufficientMemory
+	case strings.HasPrefix(lower, "insufficient "):
+		return SchedulingInsufficientOther
+	case strings.Contains(lower, "too many pods"):
+		return SchedulingTooManyPods
+	case strings.Contains(lower, "taint"):
+		return SchedulingTaint
+	case strings.Contains(lower, "node affinity/selector"), strings.Contains(lower, "node selector"):
+		return SchedulingNodeSelector
+	case strings.Contains(lower, "pod affinity"), strings.Contains(lower, "pod anti-affinity"), strings.Contains(lower, "topology spread"):
+		return SchedulingPodAffinity
+	case strings.Contains(lower, "unschedulable"):
+		return SchedulingUnschedulable
+	case strings.Contains(lower, "volume"), strings.Contains(lower, "persistentvolumeclaim"):
+		return SchedulingVolume
+	case strings.Contains(lower, "free ports"):
+		return SchedulingPorts
+	}
+	return SchedulingOther
+}
+
+// SchedulingAdvice returns what to do about each kind of reason in a
+// scheduling failure, most common kinds first
+func SchedulingAdvice(failure SchedulingFailure) []string {
+	advice := map[string]string{
+		SchedulingInsufficientCPU:    "Lower the pod's CPU request, free CPU by scaling down other workloads on the candidate nodes, or add nodes by scaling a MachineSet",
+		SchedulingInsufficientMemory: "Lower the pod's memory request, free memory by scaling down other workloads on the candidate nodes, or add nodes by scaling a MachineSet",
+		SchedulingInsufficientOther:  "Check that nodes advertise the extended resource the pod requests (oc describe node, Allocatable), e.g. that the GPU operator or device plugin runs on them",
+		SchedulingTooManyPods:        "The nodes reached their maxPods limit; spread the workload over more nodes or raise maxPods with a KubeletConfig",
+		SchedulingTaint:              "Add a toleration for the taint to the pod, or schedule it on nodes without the taint",
+		SchedulingNodeSelector:       "Check the pod's nodeSelector and node affinity against the node labels: oc get nodes --show-labels",
+		SchedulingPodAffinity:        "Relax the pod affinity, anti-affinity or topology spread constraints, or add nodes in the zones they require",
+		SchedulingUnschedulable:      "Uncordon the nodes once their maintenance is done: oc adm uncordon <node>",
+		SchedulingVolume:             "Check the pod's PersistentVolumeClaims: they must be Bound, and zonal volumes pin the pod to nodes in their zone",
+		SchedulingPorts:              "Another pod already uses the hostPort on those nodes; drop the hostPort or spread the pods",
+	}
+
+	counts := make(map[string]int)
+	var kinds []string
+	for _, reason := range failure.Reasons {
+		if _, seen := counts[reason.Kind]; !seen {
+			kinds = append(kinds, reason.Kind)
+		}
+		counts[reason.Kind] += reason.Nodes
+	}
+	sort.SliceStable(kinds, func(i, j int) bool { return counts[kinds[i]] > counts[kinds[j]] })
+
+	var steps []string
+	for _, kind := range kinds {
+		if text, ok := advice[kind]; ok {
+			steps = append(steps, text)
+		}
+	}
+	return steps
+}
+
+// ParseEvictionMessage returns the resource the kubelet ran short of when
+// it evicted a pod, e.g. memory or ephemeral-storage
+func ParseEvictionMessage(message string) (string, bool) {
+	if matches := evictionResourcePattern.FindStringSubmatch(message); matches != nil {
+		return strings.TrimSuffix(matches[1], "."), true
+	}
+	if strings.Contains(strings.ToLower(message), "ephemeral local storage usage exceeds") {
+		return "ephemeral-storage", true
+	}
+	return "", false
+}
+
+// AnalyzeNodeCapacity explains why pods are Pending or were evicted from
+// the nodes' conditions, their requested versus allocatable resources and
+// how close their memory is to the kubelet's eviction threshold
+func AnalyzeNodeCapacity(nodes []NodeCapacity, pods []PodPlacement, thresholds EvictionThresholds, now time.Time) *AnalysisResult {
+	result := &AnalysisResult{
+		Type:      "node-capacity",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+	byName := make(map[string]NodeCapacity, len(nodes))
+	for _, node := range nodes {
+		byName[node.Name] = node
+	}
+
+	schedulable := 0
+	for _, node := range nodes {
+		if node.schedulable() {
+			schedulable++
+		}
+		result.Issues = append(result.Issues, nodeCapacityIssues(node, thresholds)...)
+	}
+
+	pending, evicted := 0, 0
+	for _, pod := range pods {
+		switch {
+		case pod.Evicted():
+			evicted++
+			result.Issues = append(result.Issues, evictedPodIssue(pod, byName, thresholds))
+		case pod.Phase == "Pending" && pod.NodeName == "":
+			pending++
+			result.Issues = append(result.Issues, pendingPodIssue(pod, nodes))
+		}
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	result.Metrics["nodes"] = len(nodes)
+	result.Metrics["schedulable_nodes"] = schedulable
+	result.Metrics["pending_pods"] = pending
+	result.Metrics["evicted_pods"] = evicted
+
+	var underPressure []string
+	for _, node := range nodes {
+		if len(node.Pressures) > 0 {
+			underPressure = append(underPressure, node.Name)
+		}
+	}
+	result.Summary = fmt.Sprintf("%d of %d nodes accept new pods", schedulable, len(nodes))
+	if len(underPressure) > 0 {
+		result.Summary += fmt.Sprintf("; %s under pressure", strings.Join(underPressure, ", "))
+	}
+	result.Summary += fmt.Sprintf("; %d pods Pending, %d evicted", pending, evicted)
+
+	if pending > 0 || evicted > 0 {
+		result.Recommendations = append(result.Recommendations,
+			"Set requests close to actual usage (top_pods shows it) so the scheduler's view of free capacity matches the nodes' real load")
+	}
+	if evicted > 0 {
+		result.Recommendations = append(result.Recommendations,
+			"Evicted pods stay listed until deleted: oc delete pods --field-selector=status.phase=Failed -n <namespace>")
+	}
+	return result
+}
+
+// nodeCapacityIssues reports a node that is not ready, under pressure,
+// close to its memory eviction threshold or nearly fully requested
+func nodeCapacityIssues(node NodeCapacity, thresholds EvictionThresholds) []Issue {
+	var issues []Issue
+	location := "node/" + node.Name
+	metadata := func() map[string]string { return map[string]string{"node": node.Name} }
+
+	if !node.Ready {
+		issues = append(issues, Issue{
+			Severity:    "critical",
+			Category:    "capacity",
+			Title:       fmt.Sprintf("Node %s is not ready", node.Name),
+			Description: "The scheduler places no new pods on the node, and its pods are evicted once the not-ready toleration (5 minutes by default) runs out",
+			Location:    location,
+			Resolution:  fmt.Sprintf("Check the kubelet on the node: oc describe node %s and oc adm node-logs %s -u kubelet", node.Name, node.Name),
+			Metadata:    metadata(),
+		})
+	}
+
+	for _, pressure := range node.Pressures {
+		issue := Issue{
+			Severity: "critical",
+			Category: "capacity",
+			Title:    fmt.Sprintf("Node %s has %s", node.Name, pressure),
+			Location: location,
+			Metadata: metadata(),
+		}
+		issue.Metadata["pressure"] = pressure
+		switch pressure {
+		case "MemoryPressure":
+			issue.Description = fmt.Sprintf("Available memory fell below the eviction threshold of %s; the kubelet evicts BestEffort pods first, then Burstable pods using more memory than they request, and the node is tainted so no new pods land on it",
+				formatBytes(thresholds.MemoryAvailable))
+			issue.Resolution = "Find the pods using far more memory than they request (top_pods sorted by memory) and give them requests that match, or add memory by scaling out"
+			if node.MemoryWorkingSet > 0 {
+				issue.Evidence = append(issue.Evidence, fmt.Sprintf("Memory in use: %s of %s", formatBytes(node.MemoryWorkingSet), formatBytes(node.CapacityMemory)))
+			}
+		case "DiskPressure":
+			issue.Description = fmt.Sprintf("The root or image filesystem fell below the eviction threshold (nodefs.available %s, imagefs.available %s); the kubelet removes unused images and evicts pods with the most ephemeral storage",
+				percent(thresholds.NodefsAvailable), percent(thresholds.ImagefsAvailable))
+			issue.Resolution = fmt.Sprintf("Check what fills the disk: oc debug node/%s -- chroot /host df -h /var/lib/containers /var/log; large container logs and emptyDir volumes are the usual causes", node.Name)
+		case "PIDPressure":
+			issue.Description = "Processes on the node are close to the PID limit; the kubelet evicts pods and the node takes no new ones"
+			issue.Resolution = "Find the pod forking processes without reaping them, and set a podPidsLimit in a KubeletConfig"
+		default:
+			issue.Description = "The node reports the condition and the scheduler avoids it"
+			issue.Resolution = fmt.Sprintf("Check the node: oc describe node %s", node.Name)
+		}
+		issues = append(issues, issue)
+	}
+
+	if node.MemoryWorkingSet > 0 && node.CapacityMemory > 0 && !hasString(node.Pressures, "MemoryPressure") {
+		available := node.CapacityMemory - node.MemoryWorkingSet
+		margin := thresholds.MemoryAvailable + int64(float64(node.CapacityMemory)*capacityEvictionMargin)
+		if available < margin {
+			issue := Issue{
+				Severity:    "warning",
+				Category:    "capacity",
+				Title:       fmt.Sprintf("Node %s is close to evicting pods for memory", node.Name),
+				Description: fmt.Sprintf("%s of memory is available and the kubelet starts evicting below %s", formatBytes(available), formatBytes(thresholds.MemoryAvailable)),
+				Location:    location,
+				Evidence:    []string{fmt.Sprintf("Memory in use: %s of %s", formatBytes(node.MemoryWorkingSet), formatBytes(node.CapacityMemory))},
+				Resolution:  "Pods use more memory than they request; raise their requests to match usage so the scheduler stops overpacking the node",
+				Metadata:    metadata(),
+			}
+			if node.MemoryWorkingSet > node.AllocatableMemory && node.AllocatableMemory > 0 {
+				issue.Evidence = append(issue.Evidence, fmt.Sprintf("Usage exceeds the %s allocatable to pods", formatBytes(node.AllocatableMemory)))
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	if node.schedulable() {
+		var full []string
+		if ratio := ratioOf(node.RequestedCPU, node.AllocatableCPU); ratio >= capacityRequestedWarning {
+			full = append(full, fmt.Sprintf("CPU %s of %s requested (%s)", formatMillicores(node.RequestedCPU), formatMillicores(node.AllocatableCPU), percent(ratio)))
+		}
+		if ratio := ratioOf(node.RequestedMemory, node.AllocatableMemory); ratio >= capacityRequestedWarning {
+			full = append(full, fmt.Sprintf("memory %s of %s requested (%s)", formatBytes(node.RequestedMemory), formatBytes(node.AllocatableMemory), percent(ratio)))
+		}
+		if node.AllocatablePods > 0 && float64(node.Pods) >= float64(node.AllocatablePods)*capacityRequestedWarning {
+			full = append(full, fmt.Sprintf("%d of %d pods", node.Pods, node.AllocatablePods))
+		}
+		if len(full) > 0 {
+			issues = append(issues, Issue{
+				Severity:    "info",
+				Category:    "capacity",
+				Title:       fmt.Sprintf("Node %s is nearly fully requested", node.Name),
+				Description: "The scheduler counts requests, not usage, so pods that do not fit in what is left go elsewhere or stay Pending",
+				Location:    location,
+				Evidence:    full,
+				Resolution:  "Compare requests with actual usage (top_nodes, top_pods) and right-size the largest requests",
+				Metadata:    metadata(),
+			})
+		}
+	}
+	return issues
+}
+
+// pendingPodIssue explains why the scheduler found no node for a pod
+func pendingPodIssue(pod PodPlacement, nodes []NodeCapacity) Issue {
+	location := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	issue := Issue{
+		Severity: "warning",
+		Category: "scheduling",
+		Title:    fmt.Sprintf("Pod %s is Pending", location),
+		Location: location,
+		Metadata: map[string]string{"pod": pod.Name, "namespace": pod.Namespace},
+	}
+
+	failure, ok := ParseSchedulingFailure(pod.SchedulingMessage)
+	if !ok {
+		issue.Severity = "info"
+		issue.Description = "The scheduler has not reported why the pod is unscheduled yet"
+		if pod.SchedulingMessage != "" {
+			issue.Description = pod.SchedulingMessage
+			issue.Evidence = []string{pod.SchedulingMessage}
+		}
+		issue.Resolution = "Check the pod's events for FailedScheduling: oc get events -n " + pod.Namespace + " --field-selector involvedObject.name=" + pod.Name
+		return issue
+	}
+
+	issue.Severity = "critical"
+	issue.Evidence = []string{pod.SchedulingMessage}
+	var explanations []string
+	for _, reason := range failure.Reasons {
+		explanations = append(explanations, schedulingExplanation(reason, pod, nodes))
+	}
+	issue.Description = fmt.Sprintf("%d of %d nodes can take the pod: %s", failure.Available, failure.Total, strings.Join(explanations, "; "))
+	issue.Resolution = strings.Join(SchedulingAdvice(failure), ". ")
+	kinds := make([]string, 0, len(failure.Reasons))
+	for _, reason := range failure.Reasons {
+		kinds = append(kinds, reason.Kind)
+	}
+	issue.Metadata["reasons"] = strings.Join(kinds, ",")
+	return issue
+}
+
+// schedulingExplanation turns one scheduler reason into an explanation
+// with the free capacity of the nodes
+func schedulingExplanation(reason SchedulingReason, pod PodPlacement, nodes []NodeCapacity) string {
+	nodeCount := "all nodes"
+	if reason.Nodes > 0 {
+		nodeCount = fmt.Sprintf("%d nodes", reason.Nodes)
+	}
+	switch reason.Kind {
+	case SchedulingInsufficientCPU:
+		return insufficientExplanation(nodeCount, "CPU", pod.RequestedCPU, nodes,
+			func(n NodeCapacity) int64 { return n.AllocatableCPU - n.RequestedCPU }, formatMillicores)
+	case SchedulingInsufficientMemory:
+		return insufficientExplanation(nodeCount, "memory", pod.RequestedMemory, nodes,
+			func(n NodeCapacity) int64 { return n.AllocatableMemory - n.RequestedMemory }, formatBytes)
+	case SchedulingTaint:
+		var tainted []string
+		for _, node := range nodes {
+			if len(node.Taints) > 0 {
+				tainted = append(tainted, fmt.Sprintf("%s (%s)", node.Name, strings.Join(node.Taints, ", ")))
+			}
+		}
+		if len(tainted) > 0 {
+			return fmt.Sprintf("%s have a taint the pod does not tolerate: %s", nodeCount, strings.Join(tainted, ", "))
+		}
+		return fmt.Sprintf("%s have a taint the pod does not tolerate (%s)", nodeCount, reason.Detail)
+	case SchedulingUnschedulable:
+		var cordoned []string
+		for _, node := range nodes {
+			if node.Unschedulable {
+				cordoned = append(cordoned, node.Name)
+			}
+		}
+		if len(cordoned) > 0 {
+			return fmt.Sprintf("%s are cordoned: %s", nodeCount, strings.Join(cordoned, ", "))
+		}
+		return fmt.Sprintf("%s are cordoned", nodeCount)
+	case SchedulingNodeSelector:
+		return fmt.Sprintf("%s lack the labels the pod's nodeSelector or node affinity requires", nodeCount)
+	}
+	return fmt.Sprintf("%s: %s", nodeCount, reason.Detail)
+}
+
+// insufficientExplanation compares what a pod requests with the most any
+// schedulable node has left, and with what all of them have left together
+func insufficientExplanation(nodeCount, resourceName string, requested int64, nodes []NodeCapacity, free func(NodeCapacity) int64, format func(int64) string) string {
+	var best NodeCapacity
+	var bestFree, totalFree int64
+	for _, node := range nodes {
+		if !node.schedulable() {
+			continue
+		}
+		left := free(node)
+		if left < 0 {
+			left = 0
+		}
+		totalFree += left
+		if best.Name == "" || left > bestFree {
+			best, bestFree = node, left
+		}
+	}
+	explanation := fmt.Sprintf("%s lack the %s the pod requests", nodeCount, resourceName)
+	if requested > 0 {
+		explanation = fmt.Sprintf("%s lack the %s %s the pod requests", nodeCount, format(requested), resourceName)
+	}
+	if best.Name == "" {
+		return explanation + "; no node accepts new pods"
+	}
+	explanation += fmt.Sprintf(", the most left unrequested is %s on %s", format(bestFree), best.Name)
+	if requested > 0 && totalFree >= requested {
+		explanation += fmt.Sprintf(" (%s left across all schedulable nodes, but fragmented)", format(totalFree))
+	} else {
+		explanation += fmt.Sprintf(" (%s left across all schedulable nodes)", format(totalFree))
+	}
+	return explanation
+}
+
+// evictedPodIssue explains an eviction with the current state of the node
+// the pod ran on
+func evictedPodIssue(pod PodPlacement, nodes map[string]NodeCapacity, thresholds EvictionThresholds) Issue {
+	location := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	issue := Issue{
+		Severity: "warning",
+		Category: "eviction",
+		Title:    fmt.Sprintf("Pod %s was evicted", location),
+		Location: location,
+		Metadata: map[string]string{"pod": pod.Name, "namespace": pod.Namespace, "node": pod.NodeName},
+	}
+	if pod.Message != "" {
+		issue.Evidence = append(issue.Evidence, pod.Message)
+	}
+
+	resourceName, ok := ParseEvictionMessage(pod.Message)
+	node, known := nodes[pod.NodeName]
+	switch {
+	case ok && resourceName == "memory":
+		issue.Metadata["resource"] = resourceName
+		issue.Description = fmt.Sprintf("Node %s dropped below %s of available memory, so the kubelet evicted pods using more memory than they request", valueOrUnknown(pod.NodeName), formatBytes(thresholds.MemoryAvailable))
+		issue.Resolution = "Set the pod's memory request to its real usage (and its limit not far above), so it is neither evicted first nor packed onto an overcommitted node"
+	case ok && resourceName == "ephemeral-storage" && strings.Contains(strings.ToLower(pod.Message), "exceeds"):
+		issue.Metadata["resource"] = resourceName
+		issue.Description = "The pod wrote more to its writable layer, logs and emptyDir volumes than its ephemeral-storage limit allows"
+		issue.Resolution = "Raise the ephemeral-storage limit, or write the data to a PersistentVolume instead"
+	case ok:
+		issue.Metadata["resource"] = resourceName
+		issue.Description = fmt.Sprintf("Node %s ran low on %s (the nodefs.available threshold is %s) and the kubelet evicted the pods using the most of it", valueOrUnknown(pod.NodeName), resourceName, percent(thresholds.NodefsAvailable))
+		issue.Resolution = "Set ephemeral-storage requests and limits on pods that write to local disk, and check the node's disk usage"
+	default:
+		issue.Description = "The pod was evicted: " + valueOrUnknown(pod.Message)
+		issue.Resolution = "Check the node's events for the eviction reason"
+	}
+
+	if known {
+		switch {
+		case len(node.Pressures) > 0:
+			issue.Severity = "critical"
+			issue.Evidence = append(issue.Evidence, fmt.Sprintf("Node %s still has %s", node.Name, strings.Join(node.Pressures, ", ")))
+		default:
+			issue.Evidence = append(issue.Evidence, fmt.Sprintf("Node %s no longer reports pressure", node.Name))
+		}
+	}
+	return issue
+}
+
+func ratioOf(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
+
+func hasString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// formatMillicores renders CPU as cores when whole, otherwise in millicores
+func formatMillicores(milli int64) string {
+	if milli%1000 == 0 {
+		return strconv.FormatInt(milli/1000, 10)
+	}
+	return fmt.Sprintf("%dm", milli)
+}
+
+// formatBytes renders memory in Gi, or in Mi below 1Gi
+func formatBytes(bytes int64) string {
+	const mi = 1024 * 1024
+	if bytes < 1024*mi {
+		return fmt.Sprintf("%dMi", bytes/mi)
+	}
+	return fmt.Sprintf("%.1fGi", float64(bytes)/(1024*mi))
+}
@@ -0,0 +1,125 @@
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const gi = 1024 * 1024 * 1024
+
+func TestParseSchedulingFailure(t *testing.T) {
+	message := "0/6 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/master: }, 2 Insufficient memory, 3 node(s) didn't match Pod's node affinity/selector. preemption: 0/6 nodes are available: 3 No preemption victims found for incoming pod, 3 Preemption is not helpful for scheduling."
+
+	failure, ok := ParseSchedulingFailure(message)
+	if !ok {
+		t.Fatal("expected the message to parse")
+	}
+	if failure.Available != 0 || failure.Total != 6 || len(failure.Reasons) != 3 {
+		t.Fatalf("failure = %+v", failure)
+	}
+	expected := []SchedulingReason{
+		{Nodes: 1, Kind: SchedulingTaint, Detail: "node(s) had untolerated taint {node-role.kubernetes.io/master: }"},
+		{Nodes: 2, Kind: SchedulingInsufficientMemory, Detail: "Insufficient memory"},
+		{Nodes: 3, Kind: SchedulingNodeSelector, Detail: "node(s) didn't match Pod's node affinity/selector"},
+	}
+	for i, reason := range expected {
+		if failure.Reasons[i] != reason {
+			t.Errorf("reason %d = %+v, expected %+v", i, failure.Reasons[i], reason)
+		}
+	}
+
+	advice := SchedulingAdvice(failure)
+	if len(advice) != 3 || !strings.Contains(advice[0], "nodeSelector") {
+		t.Errorf("advice = %v, expected the node selector advice first", advice)
+	}
+
+	if failure, ok := ParseSchedulingFailure("0/3 nodes are available: pod has unbound immediate PersistentVolumeClaims."); !ok || !failure.Has(SchedulingVolume) {
+		t.Errorf("unbound claims = %+v, expected a volume reason", failure)
+	}
+	if _, ok := ParseSchedulingFailure("Successfully assigned shop/web-1 to worker-1"); ok {
+		t.Error("expected a non-scheduling message not to parse")
+	}
+}
+
+func TestParseEvictionMessage(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"The node was low on resource: memory. Threshold quantity: 100Mi, available: 91424Ki.", "memory"},
+		{"The node was low on resource: ephemeral-storage.", "ephemeral-storage"},
+		{"Pod ephemeral local storage usage exceeds the total limit of containers 1Gi.", "ephemeral-storage"},
+	}
+	for _, tt := range tests {
+		if resource, ok := ParseEvictionMessage(tt.message); !ok || resource != tt.expected {
+			t.Errorf("ParseEvictionMessage(%q) = %s, %v, expected %s", tt.message, resource, ok, tt.expected)
+		}
+	}
+	if _, ok := ParseEvictionMessage("Preempted by a higher priority pod"); ok {
+		t.Error("expected an unrelated message not to parse")
+	}
+}
+
+func TestAnalyzeNodeCapacity(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	nodes := []NodeCapacity{
+		{Name: "worker-1", Ready: true, CapacityMemory: 16 * gi, AllocatableCPU: 4000, AllocatableMemory: 15 * gi,
+			AllocatablePods: 250, RequestedCPU: 3700, RequestedMemory: 8 * gi, Pods: 40, MemoryWorkingSet: 15*gi + 512*1024*1024},
+		{Name: "worker-2", Ready: true, Pressures: []string{"MemoryPressure"}, CapacityMemory: 16 * gi, AllocatableCPU: 4000,
+			AllocatableMemory: 15 * gi, AllocatablePods: 250, RequestedCPU: 3300, RequestedMemory: 12 * gi, Pods: 50},
+		{Name: "worker-3", Ready: true, Unschedulable: true, AllocatableCPU: 4000, AllocatableMemory: 15 * gi, AllocatablePods: 250},
+	}
+	pods := []PodPlacement{
+		{Namespace: "shop", Name: "web-1", Phase: "Pending", RequestedCPU: 1000,
+			SchedulingMessage: "0/3 nodes are available: 1 node(s) were unschedulable, 2 Insufficient cpu."},
+		{Namespace: "shop", Name: "cache-1", Phase: "Failed", Reason: "Evicted", NodeName: "worker-2",
+			Message: "The node was low on resource: memory. Threshold quantity: 100Mi, available: 51200Ki."},
+		{Namespace: "shop", Name: "api-1", Phase: "Pending"},
+	}
+
+	result := AnalyzeNodeCapacity(nodes, pods, DefaultEvictionThresholds(), now)
+	if result.Metrics["pending_pods"] != 2 || result.Metrics["evicted_pods"] != 1 || result.Metrics["schedulable_nodes"] != 2 {
+		t.Errorf("metrics = %v", result.Metrics)
+	}
+	if !strings.Contains(result.Summary, "2 of 3 nodes accept new pods; worker-2 under pressure") {
+		t.Errorf("summary = %q", result.Summary)
+	}
+
+	issues := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		issues[issue.Title] = issue
+	}
+	pending, ok := issues["Pod shop/web-1 is Pending"]
+	if !ok || pending.Severity != "critical" {
+		t.Fatalf("issues = %+v, expected web-1 Pending as critical", result.Issues)
+	}
+	for _, expected := range []string{"1 nodes are cordoned: worker-3", "2 nodes lack the 1 CPU the pod requests", "the most left unrequested is 700m on worker-2", "fragmented"} {
+		if !strings.Contains(pending.Description, expected) {
+			t.Errorf("pending description = %q, expected %q", pending.Description, expected)
+		}
+	}
+	if !strings.Contains(pending.Resolution, "uncordon") || pending.Metadata["reasons"] != "unschedulable,insufficient-cpu" {
+		t.Errorf("pending issue = %+v", pending)
+	}
+
+	evicted := issues["Pod shop/cache-1 was evicted"]
+	if evicted.Severity != "critical" || evicted.Metadata["resource"] != "memory" || !strings.Contains(strings.Join(evicted.Evidence, "\n"), "still has MemoryPressure") {
+		t.Errorf("evicted issue = %+v", evicted)
+	}
+	if unreported := issues["Pod shop/api-1 is Pending"]; unreported.Severity != "info" {
+		t.Errorf("pod without a scheduling message = %+v, expected info", unreported)
+	}
+	if near, ok := issues["Node worker-1 is close to evicting pods for memory"]; !ok || len(near.Evidence) != 2 {
+		t.Errorf("worker-1 eviction margin issue = %+v", near)
+	}
+	if full, ok := issues["Node worker-1 is nearly fully requested"]; !ok || !strings.Contains(full.Evidence[0], "CPU 3700m of 4 requested") {
+		t.Errorf("worker-1 requested issue = %+v", full)
+	}
+	if _, ok := issues["Node worker-2 has MemoryPressure"]; !ok {
+		t.Errorf("issues = %+v, expected worker-2 MemoryPressure", result.Issues)
+	}
+	if result.Issues[0].Severity != "critical" || result.Issues[len(result.Issues)-1].Severity != "info" {
+		t.Errorf("issues not ordered by severity: %+v", result.Issues)
+	}
+}
@@ -0,0 +1,290 @@
+package diagnostics
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultCertWarningDays and DefaultCertCriticalDays are the expiry
+	// windows of a certificate audit
+	DefaultCertWarningDays  = 30
+	DefaultCertCriticalDays = 7
+
+	// Platform certificates live for days or weeks and are rotated well
+	// before they expire, so a certificate living at most
+	// shortLivedCertLifetime is only reported once less than
+	// rotatedLifetimeFraction of its lifetime is left and rotation should
+	// have happened
+	shortLivedCertLifetime  = 90 * 24 * time.Hour
+	rotatedLifetimeFraction = 0.1
+)
+
+// Certificate source kinds
+const (
+	CertKindSecret    = "tls-secret"
+	CertKindAPIServer = "apiserver"
+	CertKindRouter    = "router"
+	CertKindRoute     = "route"
+	CertKindKubelet   = "kubelet"
+)
+
+// CertificateInfo is one certificate found in the cluster and where it was
+// found
+type CertificateInfo struct {
+	Kind      string    `json:"kind"`   // tls-secret, apiserver, router, route or kubelet
+	Source    string    `json:"source"` // e.g. secret shop/web-tls
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	IsCA      bool      `json:"is_ca,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	// Manager names what renews the certificate, e.g. service-ca or
+	// cert-manager Certificate shop/web
+	Manager string `json:"manager,omitempty"`
+}
+
+// CertExpiryPolicy sets the days remaining at which a certificate becomes a
+// warning and critical
+type CertExpiryPolicy struct {
+	WarningDays  int
+	CriticalDays int
+}
+
+// ParseCertificatePEM reads every certificate of a PEM bundle, leaf first
+func ParseCertificatePEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certs, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// NewCertificateInfo describes a parsed certificate found at source
+func NewCertificateInfo(kind, source string, cert *x509.Certificate) CertificateInfo {
+	return CertificateInfo{
+		Kind:      kind,
+		Source:    source,
+		Subject:   certName(cert.Subject.CommonName, cert.Subject.String()),
+		Issuer:    certName(cert.Issuer.CommonName, cert.Issuer.String()),
+		DNSNames:  cert.DNSNames,
+		IsCA:      cert.IsCA,
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+	}
+}
+
+func certName(commonName, full string) string {
+	if commonName != "" {
+		return commonName
+	}
+	return full
+}
+
+// daysLeft is the whole days from now until the certificate expires,
+// negative once it has
+func (c CertificateInfo) daysLeft(now time.Time) int {
+	left := c.NotAfter.Sub(now)
+	if left < 0 {
+		return -int((-left).Hours()/24) - 1
+	}
+	return int(left.Hours() / 24)
+}
+
+// rotationOverdue reports whether the certificate should have been renewed
+// by now: any long-lived certificate, and a short-lived one with less than a
+// tenth of its lifetime left
+func (c CertificateInfo) rotationOverdue(now time.Time) bool {
+	lifetime := c.NotAfter.Sub(c.NotBefore)
+	if lifetime <= 0 || lifetime > shortLivedCertLifetime {
+		return true
+	}
+	return float64(c.NotAfter.Sub(now)) < rotatedLifetimeFraction*float64(lifetime)
+}
+
+// AuditCertificates reports the certificates that expired or expire within
+// the policy's windows as issues, most urgent first. Short-lived
+// certificates are only reported once they are overdue for rotation.
+func AuditCertificates(certs []CertificateInfo, now time.Time, policy CertExpiryPolicy) *AnalysisResult {
+	if policy.WarningDays <= 0 {
+		policy.WarningDays = DefaultCertWarningDays
+	}
+	if policy.CriticalDays <= 0 {
+		policy.CriticalDays = DefaultCertCriticalDays
+	}
+	result := &AnalysisResult{
+		Type:      "cert-expiry",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+
+	sorted := append([]CertificateInfo(nil), certs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].NotAfter.Before(sorted[j].NotAfter) })
+
+	expired, critical, warning, notYetValid := 0, 0, 0, 0
+	for _, cert := range sorted {
+		days := cert.daysLeft(now)
+		issue := Issue{
+			Category: "security",
+			Location: fmt.Sprintf("%s (%s)", cert.Source, cert.Subject),
+			Evidence: []string{
+				fmt.Sprintf("Subject: %s", cert.Subject),
+				fmt.Sprintf("Issuer: %s", cert.Issuer),
+				fmt.Sprintf("Valid: %s to %s", cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339)),
+			},
+			Resolution: certResolution(cert),
+			Metadata: map[string]string{
+				"kind":      cert.Kind,
+				"source":    cert.Source,
+				"not_after": cert.NotAfter.Format(time.RFC3339),
+				"days_left": strconv.Itoa(days),
+			},
+		}
+		if len(cert.DNSNames) > 0 {
+			issue.Evidence = append(issue.Evidence, "DNS names: "+strings.Join(cert.DNSNames, ", "))
+		}
+
+		switch {
+		case !now.Before(cert.NotAfter):
+			expired++
+			issue.Severity = "critical"
+			issue.Title = fmt.Sprintf("%s certificate expired %s", certKindTitle(cert.Kind), daysAgo(-days-1))
+			issue.Description = fmt.Sprintf("The certificate of %s expired at %s; clients reject it", cert.Source, cert.NotAfter.Format(time.RFC3339))
+		case days < policy.CriticalDays && cert.rotationOverdue(now):
+			critical++
+			issue.Severity = "critical"
+			issue.Title = fmt.Sprintf("%s certificate expires %s", certKindTitle(cert.Kind), daysAhead(days))
+			issue.Description = fmt.Sprintf("The certificate of %s expires at %s", cert.Source, cert.NotAfter.Format(time.RFC3339))
+		case days < policy.WarningDays && cert.rotationOverdue(now):
+			warning++
+			issue.Severity = "warning"
+			issue.Title = fmt.Sprintf("%s certificate expires %s", certKindTitle(cert.Kind), daysAhead(days))
+			issue.Description = fmt.Sprintf("The certificate of %s expires at %s", cert.Source, cert.NotAfter.Format(time.RFC3339))
+		case now.Before(cert.NotBefore):
+			notYetValid++
+			issue.Severity = "info"
+			issue.Title = fmt.Sprintf("%s certificate is not valid until %s", certKindTitle(cert.Kind), cert.NotBefore.Format("2006-01-02 15:04"))
+			issue.Description = "Clients reject the certificate until then; check for clock skew if it was just issued"
+			issue.Resolution = "Check that the clocks of the issuer and the nodes are synchronized"
+		default:
+			continue
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+
+	// Most urgent first: expired, then critical, then warning
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	result.Metrics["certificates"] = len(certs)
+	result.Metrics["expired"] = expired
+	result.Metrics["critical"] = critical
+	result.Metrics["warning"] = warning
+	result.Summary = fmt.Sprintf("Checked %d certificates: %d expired, %d expire within %d days, %d more within %d days",
+		len(certs), expired, critical, policy.CriticalDays, warning, policy.WarningDays)
+	if notYetValid > 0 {
+		result.Summary += fmt.Sprintf(", %d not yet valid", notYetValid)
+	}
+	if len(sorted) > 0 {
+		next := sorted[0]
+		for _, cert := range sorted {
+			if now.Before(cert.NotAfter) {
+				next = cert
+				break
+			}
+		}
+		if now.Before(next.NotAfter) {
+			result.Summary += fmt.Sprintf("; the next to expire is %s on %s", next.Source, next.NotAfter.Format("2006-01-02"))
+		}
+	}
+
+	if expired+critical > 0 {
+		result.Recommendations = append(result.Recommendations, "Renew the expired and critical certificates first; an expired API server, router or kubelet certificate takes down every client that verifies it")
+	}
+	for _, cert := range sorted {
+		if cert.Kind == CertKindKubelet && cert.daysLeft(now) < policy.WarningDays && cert.rotationOverdue(now) {
+			result.Recommendations = append(result.Recommendations, "Kubelet certificates are overdue for rotation: approve pending kubelet CSRs with oc get csr and oc adm certificate approve")
+			break
+		}
+	}
+	return result
+}
+
+func certKindTitle(kind string) string {
+	switch kind {
+	case CertKindAPIServer:
+		return "API server"
+	case CertKindRouter:
+		return "Router"
+	case CertKindRoute:
+		return "Route"
+	case CertKindKubelet:
+		return "Kubelet"
+	}
+	return "TLS secret"
+}
+
+func daysAhead(days int) string {
+	switch days {
+	case 0:
+		return "within a day"
+	case 1:
+		return "in 1 day"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
+
+func daysAgo(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
+
+// certResolution says how a certificate of its kind is renewed
+func certResolution(cert CertificateInfo) string {
+	switch {
+	case strings.HasPrefix(cert.Manager, "cert-manager"):
+		return fmt.Sprintf("Renewed by %s; check its status and the issuer with oc describe certificate", cert.Manager)
+	case cert.Manager == "service-ca":
+		return "Issued by the service CA operator; delete the secret to have it regenerated and check the service-ca operator if it is not renewed"
+	}
+	switch cert.Kind {
+	case CertKindAPIServer:
+		return "Platform API server certificates are rotated by the kube-apiserver operator; check oc get clusteroperator kube-apiserver. Named certificates in apiserver/cluster servingCerts must be replaced in their openshift-config secret"
+	case CertKindRouter:
+		return "Replace the default certificate secret of the IngressController (spec.defaultCertificate), or check the ingress operator if it manages the certificate"
+	case CertKindRoute:
+		return "Update spec.tls.certificate and spec.tls.key of the route with a renewed certificate"
+	case CertKindKubelet:
+		return "Kubelet serving certificates rotate through CSRs; approve pending ones with oc get csr and oc adm certificate approve"
+	}
+	return "Renew the certificate and update tls.crt and tls.key in the secret, then restart the workloads that do not reload it"
+}
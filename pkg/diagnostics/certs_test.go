@@ -0,0 +1,88 @@
+package diagnostics
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCertificatePEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAfter := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "web.example.com"},
+		DNSNames: []string{"web.example.com"}, NotBefore: notAfter.AddDate(-1, 0, 0), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	bundle := append(append(append([]byte{}, block...), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte{1}})...), block...)
+
+	certs, err := ParseCertificatePEM(bundle)
+	if err != nil || len(certs) != 2 {
+		t.Fatalf("ParseCertificatePEM() = %d certificates, %v", len(certs), err)
+	}
+	info := NewCertificateInfo(CertKindSecret, "secret shop/web-tls", certs[0])
+	if info.Subject != "web.example.com" || !info.NotAfter.Equal(notAfter) || len(info.DNSNames) != 1 {
+		t.Errorf("NewCertificateInfo() = %+v", info)
+	}
+	if _, err := ParseCertificatePEM([]byte("not a certificate")); err == nil {
+		t.Error("ParseCertificatePEM() accepted data without a certificate")
+	}
+}
+
+func TestAuditCertificates(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cert := func(kind, source string, lifetime, left time.Duration) CertificateInfo {
+		return CertificateInfo{Kind: kind, Source: source, Subject: source, NotBefore: now.Add(left - lifetime), NotAfter: now.Add(left)}
+	}
+	day, year := 24*time.Hour, 365*24*time.Hour
+	certs := []CertificateInfo{
+		cert(CertKindSecret, "secret shop/healthy", year, 200*day),
+		cert(CertKindRouter, "secret openshift-ingress/router-certs-default", year, 20*day+time.Hour),
+		cert(CertKindRoute, "route shop/web", year, -3*day),
+		cert(CertKindSecret, "secret shop/soon", year, 2*day+time.Hour),
+		// Rotated short-lived certificates are fine until rotation is overdue
+		cert(CertKindKubelet, "kubelet worker-1", 30*day, 10*day),
+		cert(CertKindKubelet, "kubelet worker-2", 30*day, 2*day),
+		cert(CertKindSecret, "secret shop/future", year, year+day),
+	}
+	certs[len(certs)-1].NotBefore = now.Add(day)
+
+	result := AuditCertificates(certs, now, CertExpiryPolicy{})
+	var got []string
+	for _, issue := range result.Issues {
+		got = append(got, issue.Severity+": "+issue.Title+" @ "+issue.Metadata["source"])
+	}
+	expected := []string{
+		"critical: Route certificate expired 3 days ago @ route shop/web",
+		"critical: Kubelet certificate expires in 2 days @ kubelet worker-2",
+		"critical: TLS secret certificate expires in 2 days @ secret shop/soon",
+		"warning: Router certificate expires in 20 days @ secret openshift-ingress/router-certs-default",
+		"info: TLS secret certificate is not valid until 2026-10-17 12:00 @ secret shop/future",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("AuditCertificates() issues =\n%s\nexpected\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+	if !strings.Contains(result.Summary, "Checked 7 certificates: 1 expired, 2 expire within 7 days, 1 more within 30 days, 1 not yet valid; the next to expire is kubelet worker-2") {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+	if len(result.Recommendations) != 2 || !strings.Contains(result.Recommendations[1], "oc adm certificate approve") {
+		t.Errorf("Recommendations = %q", result.Recommendations)
+	}
+
+	result = AuditCertificates(certs, now, CertExpiryPolicy{WarningDays: 10, CriticalDays: 1})
+	if result.Metrics["critical"] != 0 || result.Metrics["warning"] != 2 {
+		t.Errorf("with narrower windows, metrics = %v", result.Metrics)
+	}
+}
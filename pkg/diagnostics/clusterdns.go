@@ -0,0 +1,426 @@
+package diagnostics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// A lookup slower than this delays every client that misses the CoreDNS
+	// cache; the forward plugin gives up after about 2s
+	dnsProbeSlow = time.Second
+
+	// A CoreDNS pod restarted this many times is crash looping
+	coreDNSRestartWarning = 3
+)
+
+// Kinds of DNS probe run from a node
+const (
+	DNSProbeCluster  = "cluster"  // a cluster name through the DNS service
+	DNSProbeExternal = "external" // an external name through the DNS service
+	DNSProbeUpstream = "upstream" // an external name asked of an upstream resolver directly
+)
+
+// Outcomes of a DNS probe besides the DNS response codes
+const (
+	DNSStatusNoError  = "NOERROR"
+	DNSStatusNXDomain = "NXDOMAIN"
+	DNSStatusServfail = "SERVFAIL"
+	DNSStatusTimeout  = "timeout"
+	DNSStatusError    = "error" // the lookup could not run
+)
+
+// OperatorStatus is the state a ClusterOperator reports
+type OperatorStatus struct {
+	Name        string `json:"name"`
+	Found       bool   `json:"found"`
+	Available   bool   `json:"available"`
+	Progressing bool   `json:"progressing"`
+	Degraded    bool   `json:"degraded"`
+	Message     string `json:"message,omitempty"` // of the condition that explains the state
+}
+
+// CoreDNSPod is one pod of the dns-default DaemonSet
+type CoreDNSPod struct {
+	Name     string `json:"name"`
+	Node     string `json:"node"`
+	Phase    string `json:"phase"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	Reason   string `json:"reason,omitempty"` // why the dns container is waiting or last terminated
+}
+
+// DNSProbe is one lookup run from a node
+type DNSProbe struct {
+	Node    string        `json:"node"`
+	Kind    string        `json:"kind"`
+	Server  string        `json:"server"`
+	Name    string        `json:"name"`
+	Status  string        `json:"status"`
+	Latency time.Duration `json:"latency,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// Failed reports whether the probe got no usable answer. NXDOMAIN only
+// fails a cluster name, which must always exist.
+func (p DNSProbe) Failed() bool {
+	switch p.Status {
+	case DNSStatusNoError:
+		return false
+	case DNSStatusNXDomain:
+		return p.Kind == DNSProbeCluster
+	}
+	return true
+}
+
+// ClusterDNSState is what diagnose_cluster_dns gathered about cluster DNS
+type ClusterDNSState struct {
+	Operator       OperatorStatus `json:"operator"`
+	ServiceIP      string         `json:"service_ip,omitempty"`
+	Upstreams      []string       `json:"upstreams,omitempty"` // configured in dns.operator/default; empty uses each node's resolv.conf
+	DesiredPods    int32          `json:"desired_pods"`
+	Pods           []CoreDNSPod   `json:"pods"`
+	Probes         []DNSProbe     `json:"probes,omitempty"`
+	NodesUnprobed  []string       `json:"nodes_unprobed,omitempty"` // nodes lookups could not run from, with why
+	ClusterName    string         `json:"cluster_name"`
+	ExternalLookup string         `json:"external_lookup"`
+}
+
+var (
+	digStatusPattern    = regexp.MustCompile(`status: ([A-Z]+)`)
+	digQueryTimePattern = regexp.MustCompile(`Query time: (\d+) msec`)
+)
+
+// ParseDigOutput reads the response code and the query time of a dig run
+func ParseDigOutput(output string) (status string, latency time.Duration) {
+	lower := strings.ToLower(output)
+	if strings.Contains(lower, "timed out") || strings.Contains(lower, "no servers could be reached") {
+		return DNSStatusTimeout, 0
+	}
+	match := digStatusPattern.FindStringSubmatch(output)
+	if match == nil {
+		return DNSStatusError, 0
+	}
+	if ms := digQueryTimePattern.FindStringSubmatch(output); ms != nil {
+		value, _ := strconv.Atoi(ms[1])
+		latency = time.Duration(value) * time.Millisecond
+	}
+	return match[1], latency
+}
+
+// ParseNameservers returns the nameservers of a resolv.conf in order
+func ParseNameservers(resolvConf string) []string {
+	var nameservers []string
+	for _, line := range strings.Split(resolvConf, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			nameservers = append(nameservers, fields[1])
+		}
+	}
+	return nameservers
+}
+
+// AnalyzeClusterDNS reports on the DNS operator, the CoreDNS pods, the
+// upstream resolvers and the lookups run from each node, and concludes where
+// DNS failures come from
+func AnalyzeClusterDNS(state ClusterDNSState, now time.Time) *AnalysisResult {
+	result := &AnalysisResult{
+		Type:      "cluster-dns",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+
+	switch {
+	case !state.Operator.Found:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "info",
+			Category:    "configuration",
+			Title:       "DNS operator status not available",
+			Description: "The dns ClusterOperator could not be read, so its view of cluster DNS is missing from this report",
+			Location:    "clusteroperator/dns",
+			Resolution:  "Check that the cluster is OpenShift and the account may read clusteroperators",
+		})
+	case !state.Operator.Available:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       "DNS operator is not available",
+			Description: "The DNS operator reports that cluster DNS is not serving",
+			Location:    "clusteroperator/dns",
+			Evidence:    operatorEvidence(state.Operator),
+			Resolution:  "Check the operator and its pods: oc describe clusteroperator dns and oc get pods -n openshift-dns-operator",
+			Metadata:    map[string]string{"verdict": DNSVerdictClusterDNS},
+		})
+	case state.Operator.Degraded:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "DNS operator is degraded",
+			Description: "Cluster DNS is serving, but the DNS operator cannot reconcile it",
+			Location:    "clusteroperator/dns",
+			Evidence:    operatorEvidence(state.Operator),
+			Resolution:  "Read the Degraded message: oc describe clusteroperator dns",
+			Metadata:    map[string]string{"verdict": DNSVerdictClusterDNS},
+		})
+	}
+
+	ready := 0
+	var notReady, restarting []string
+	for _, pod := range state.Pods {
+		if pod.Ready {
+			ready++
+		} else {
+			notReady = append(notReady, fmt.Sprintf("%s on %s: %s", pod.Name, valueOrUnknown(pod.Node), valueOrUnknown(firstNonEmpty(pod.Reason, pod.Phase))))
+		}
+		if pod.Restarts >= coreDNSRestartWarning {
+			line := fmt.Sprintf("%s on %s restarted %d times", pod.Name, valueOrUnknown(pod.Node), pod.Restarts)
+			if pod.Reason != "" {
+				line += " (" + pod.Reason + ")"
+			}
+			restarting = append(restarting, line)
+		}
+	}
+	desired := int(state.DesiredPods)
+	if desired == 0 {
+		desired = len(state.Pods)
+	}
+	if ready == 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       "No CoreDNS pod is ready",
+			Description: "The dns-default DaemonSet has no ready pod, so the DNS service has no endpoints and every lookup in the cluster fails",
+			Location:    "openshift-dns/dns-default",
+			Evidence:    append([]string{fmt.Sprintf("Ready pods: 0 of %d", desired)}, notReady...),
+			Resolution:  "Check the pods and their events: oc get pods -n openshift-dns -o wide and oc describe ds/dns-default -n openshift-dns",
+			Metadata:    map[string]string{"verdict": DNSVerdictClusterDNS},
+		})
+	} else if ready < desired {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("%d of %d CoreDNS pods are not ready", desired-ready, desired),
+			Description: "The DNS service spreads queries over the ready pods; nodes without a ready pod send theirs to other nodes",
+			Location:    "openshift-dns/dns-default",
+			Evidence:    notReady,
+			Resolution:  "Check the pods that are not ready: oc describe pod -n openshift-dns <pod>, and their nodes",
+			Metadata:    map[string]string{"verdict": DNSVerdictClusterDNS},
+		})
+	}
+	if len(restarting) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "stability",
+			Title:       "CoreDNS pods are restarting",
+			Description: "Each restart drops the queries the pod was serving and empties its cache",
+			Location:    "openshift-dns/dns-default",
+			Evidence:    restarting,
+			Resolution:  "Read the previous logs: oc logs -n openshift-dns <pod> -c dns --previous; OOMKilled pods point at a query storm",
+		})
+	}
+
+	analyzeClusterDNSProbes(state, result)
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	nodes := make(map[string]bool)
+	failed := 0
+	for _, probe := range state.Probes {
+		nodes[probe.Node] = true
+		if probe.Failed() {
+			failed++
+		}
+	}
+	verdict := dnsVerdict(result.Issues)
+	result.Metrics["verdict"] = verdict
+	result.Metrics["coredns_ready"] = ready
+	result.Metrics["coredns_desired"] = desired
+	result.Metrics["nodes_probed"] = len(nodes)
+	result.Metrics["probes"] = len(state.Probes)
+	result.Metrics["probes_failed"] = failed
+	result.Metrics["upstreams"] = len(state.Upstreams)
+
+	switch verdict {
+	case DNSVerdictUpstream:
+		result.Summary = "Cluster DNS works, but the upstream resolvers it forwards to fail"
+		result.Recommendations = append(result.Recommendations, "Escalate to whoever runs the upstream resolvers; restarting CoreDNS will not help")
+	case DNSVerdictClusterDNS:
+		result.Summary = "Cluster DNS itself is failing"
+		result.Recommendations = append(result.Recommendations, "Start with the DNS operator and the dns-default pods: oc get clusteroperator dns and oc get pods -n openshift-dns -o wide")
+	case DNSVerdictPodPath:
+		result.Summary = "CoreDNS is healthy, but some nodes cannot reach the DNS service"
+		result.Recommendations = append(result.Recommendations, "Check the pod network of the affected nodes; collect_ovn_diagnostics with the node shows whether OVN-Kubernetes is healthy there")
+	default:
+		result.Summary = "Cluster DNS and its upstream resolvers look healthy"
+	}
+	result.Summary += fmt.Sprintf(" (%d/%d CoreDNS pods ready; %d of %d lookups from %d nodes failed)", ready, desired, failed, len(state.Probes), len(nodes))
+	if len(state.NodesUnprobed) > 0 {
+		result.Recommendations = append(result.Recommendations, fmt.Sprintf("Lookups could not run from %d nodes: %s", len(state.NodesUnprobed), strings.Join(state.NodesUnprobed, "; ")))
+	}
+	return result
+}
+
+// analyzeClusterDNSProbes reports the failed lookups, telling upstream
+// resolvers that fail everywhere from nodes that cannot reach the DNS
+// service
+func analyzeClusterDNSProbes(state ClusterDNSState, result *AnalysisResult) {
+	type tally struct {
+		nodes, failed []string
+		evidence      []string
+	}
+	byKind := map[string]*tally{DNSProbeCluster: {}, DNSProbeExternal: {}}
+	upstreams := make(map[string]*tally)
+	var slow []string
+	for _, probe := range state.Probes {
+		t := byKind[probe.Kind]
+		if probe.Kind == DNSProbeUpstream {
+			if upstreams[probe.Server] == nil {
+				upstreams[probe.Server] = &tally{}
+			}
+			t = upstreams[probe.Server]
+		}
+		if t == nil {
+			continue
+		}
+		t.nodes = append(t.nodes, probe.Node)
+		if probe.Failed() {
+			t.failed = append(t.failed, probe.Node)
+			t.evidence = append(t.evidence, probeEvidence(probe))
+		} else if probe.Latency >= dnsProbeSlow {
+			slow = append(slow, fmt.Sprintf("%s from %s: %s", probe.Name, probe.Node, probe.Latency))
+		}
+	}
+
+	// An upstream failing from every node is down; from some nodes, those
+	// nodes cannot reach it
+	upstreamDown := false
+	for _, server := range sortedKeys(upstreams) {
+		t := upstreams[server]
+		if len(t.failed) == 0 {
+			continue
+		}
+		issue := Issue{
+			Severity:   "critical",
+			Category:   "network",
+			Title:      fmt.Sprintf("Upstream resolver %s does not answer", server),
+			Location:   "upstream " + server,
+			Evidence:   t.evidence,
+			Resolution: "Check the resolver and the firewall between the nodes and it on port 53; the upstreams are set in dns.operator/default spec.upstreamResolvers, otherwise each node's /etc/resolv.conf",
+			Metadata:   map[string]string{"verdict": DNSVerdictUpstream, "upstream": server},
+		}
+		if len(t.failed) == len(t.nodes) {
+			upstreamDown = true
+			issue.Description = fmt.Sprintf("No probed node got an answer from %s, so CoreDNS cannot resolve external names through it", server)
+		} else {
+			issue.Severity = "warning"
+			issue.Title = fmt.Sprintf("Upstream resolver %s does not answer some nodes", server)
+			issue.Description = fmt.Sprintf("%s answered %d of %d nodes; CoreDNS pods on %s fail the external lookups they forward", server, len(t.nodes)-len(t.failed), len(t.nodes), strings.Join(t.failed, ", "))
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+
+	cluster := byKind[DNSProbeCluster]
+	if len(cluster.failed) > 0 {
+		issue := Issue{
+			Severity: "critical",
+			Category: "network",
+			Location: "service " + valueOrUnknown(state.ServiceIP),
+			Evidence: cluster.evidence,
+		}
+		if len(cluster.failed) == len(cluster.nodes) {
+			issue.Title = "Cluster names do not resolve from any node"
+			issue.Description = fmt.Sprintf("Every probed node failed to look up %s through the DNS service", state.ClusterName)
+			issue.Resolution = "Check the CoreDNS pods and their logs: oc logs -n openshift-dns ds/dns-default -c dns"
+			issue.Metadata = map[string]string{"verdict": DNSVerdictClusterDNS}
+		} else {
+			issue.Title = fmt.Sprintf("Cluster names do not resolve from nodes %s", strings.Join(cluster.failed, ", "))
+			issue.Description = fmt.Sprintf("%d of %d probed nodes reach the DNS service, so CoreDNS answers but the service path from these nodes is broken", len(cluster.nodes)-len(cluster.failed), len(cluster.nodes))
+			issue.Resolution = "Check the pod network on the failing nodes (ovnkube-node pods, OVS flows) and whether their local CoreDNS pod is ready"
+			issue.Metadata = map[string]string{"verdict": DNSVerdictPodPath}
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+
+	external := byKind[DNSProbeExternal]
+	// When no cluster name resolves either, CoreDNS is down and the issue
+	// above covers it
+	clusterDown := len(cluster.nodes) > 0 && len(cluster.failed) == len(cluster.nodes)
+	if len(external.failed) > 0 && !clusterDown {
+		issue := Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("External names do not resolve through cluster DNS from %d of %d nodes", len(external.failed), len(external.nodes)),
+			Description: fmt.Sprintf("Lookups of %s through the DNS service fail", state.ExternalLookup),
+			Location:    "service " + valueOrUnknown(state.ServiceIP),
+			Evidence:    external.evidence,
+			Metadata:    map[string]string{"verdict": DNSVerdictUpstream},
+		}
+		if upstreamDown {
+			issue.Description += ", because the upstream resolvers do not answer"
+			issue.Resolution = "Fix the upstream resolvers; cluster DNS is working"
+		} else {
+			issue.Description += " although the upstream resolvers answer the nodes directly, so CoreDNS cannot reach them or forwards elsewhere"
+			issue.Resolution = "Compare dns.operator/default spec.upstreamResolvers and spec.servers with the resolvers that work, and check egress from the CoreDNS pods to them"
+			issue.Metadata["verdict"] = DNSVerdictClusterDNS
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+
+	if len(slow) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "info",
+			Category:    "performance",
+			Title:       "Slow DNS lookups",
+			Description: fmt.Sprintf("Some lookups took longer than %s", dnsProbeSlow),
+			Location:    "cluster DNS",
+			Evidence:    slow,
+			Resolution:  "Run check_dns_telemetry to see whether the upstream latency or CoreDNS itself is slow",
+		})
+	}
+}
+
+// operatorEvidence renders the conditions of a ClusterOperator
+func operatorEvidence(status OperatorStatus) []string {
+	evidence := []string{fmt.Sprintf("Available=%t, Progressing=%t, Degraded=%t", status.Available, status.Progressing, status.Degraded)}
+	if status.Message != "" {
+		evidence = append(evidence, status.Message)
+	}
+	return evidence
+}
+
+// probeEvidence renders a failed probe
+func probeEvidence(probe DNSProbe) string {
+	line := fmt.Sprintf("%s: %s @%s: %s", probe.Node, probe.Name, probe.Server, probe.Status)
+	if probe.Error != "" {
+		line += " (" + probe.Error + ")"
+	}
+	return line
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package diagnostics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDigOutput(t *testing.T) {
+	tests := []struct {
+		output  string
+		status  string
+		latency time.Duration
+	}{
+		{";; ->>HEADER<<- opcode: QUERY, status: NOERROR, id: 4242\n;; Query time: 12 msec\n", DNSStatusNoError, 12 * time.Millisecond},
+		{";; ->>HEADER<<- opcode: QUERY, status: SERVFAIL, id: 17\n;; Query time: 2004 msec\n", DNSStatusServfail, 2004 * time.Millisecond},
+		{";; connection timed out; no servers could be reached\n", DNSStatusTimeout, 0},
+		{"dig: couldn't get address for 'nowhere': not found\n", DNSStatusError, 0},
+	}
+	for _, test := range tests {
+		status, latency := ParseDigOutput(test.output)
+		if status != test.status || latency != test.latency {
+			t.Errorf("ParseDigOutput(%q) = %s, %s, expected %s, %s", test.output, status, latency, test.status, test.latency)
+		}
+	}
+	if nameservers := ParseNameservers("search ec2.internal\nnameserver 10.0.0.2\nnameserver 10.0.0.3\n"); len(nameservers) != 2 || nameservers[1] != "10.0.0.3" {
+		t.Errorf("ParseNameservers() = %v", nameservers)
+	}
+}
+
+func TestAnalyzeClusterDNS(t *testing.T) {
+	probe := func(node, kind, server, status string) DNSProbe {
+		return DNSProbe{Node: node, Kind: kind, Server: server, Name: "example.org", Status: status}
+	}
+	state := ClusterDNSState{
+		Operator:    OperatorStatus{Found: true, Available: true},
+		ServiceIP:   "172.30.0.10",
+		DesiredPods: 3,
+		Pods: []CoreDNSPod{
+			{Name: "dns-default-a", Node: "worker-1", Ready: true},
+			{Name: "dns-default-b", Node: "worker-2", Ready: true, Restarts: 5, Reason: "OOMKilled"},
+			{Name: "dns-default-c", Node: "worker-3", Phase: "Pending"},
+		},
+		Probes: []DNSProbe{
+			probe("worker-1", DNSProbeCluster, "172.30.0.10", DNSStatusNoError),
+			probe("worker-1", DNSProbeExternal, "172.30.0.10", DNSStatusServfail),
+			probe("worker-1", DNSProbeUpstream, "10.0.0.2", DNSStatusTimeout),
+			probe("worker-2", DNSProbeCluster, "172.30.0.10", DNSStatusTimeout),
+			probe("worker-2", DNSProbeExternal, "172.30.0.10", DNSStatusTimeout),
+			probe("worker-2", DNSProbeUpstream, "10.0.0.2", DNSStatusTimeout),
+		},
+		ClusterName:    "kubernetes.default.svc.cluster.local",
+		ExternalLookup: "example.org",
+	}
+
+	result := AnalyzeClusterDNS(state, time.Now())
+	severities := make(map[string]string)
+	for _, issue := range result.Issues {
+		severities[issue.Title] = issue.Severity
+	}
+	for title, severity := range map[string]string{
+		"Upstream resolver 10.0.0.2 does not answer":                          "critical",
+		"Cluster names do not resolve from nodes worker-2":                    "critical",
+		"1 of 3 CoreDNS pods are not ready":                                   "warning",
+		"CoreDNS pods are restarting":                                         "warning",
+		"External names do not resolve through cluster DNS from 2 of 2 nodes": "warning",
+	} {
+		if severities[title] != severity {
+			t.Errorf("AnalyzeClusterDNS() issue %q = %q, expected %s; issues: %v", title, severities[title], severity, severities)
+		}
+	}
+	if len(result.Issues) != 5 {
+		t.Errorf("AnalyzeClusterDNS() returned %d issues, expected 5: %v", len(result.Issues), severities)
+	}
+	if result.Metrics["verdict"] != DNSVerdictUpstream || result.Metrics["probes_failed"] != 5 || result.Metrics["nodes_probed"] != 2 {
+		t.Errorf("AnalyzeClusterDNS() metrics = %v", result.Metrics)
+	}
+
+	// A healthy cluster, and one where no CoreDNS pod serves
+	healthy := AnalyzeClusterDNS(ClusterDNSState{
+		Operator: OperatorStatus{Found: true, Available: true},
+		Pods:     []CoreDNSPod{{Name: "dns-default-a", Ready: true}},
+		Probes:   []DNSProbe{probe("worker-1", DNSProbeCluster, "172.30.0.10", DNSStatusNoError)},
+	}, time.Now())
+	if len(healthy.Issues) != 0 || healthy.Metrics["verdict"] != DNSVerdictHealthy {
+		t.Errorf("AnalyzeClusterDNS(healthy) = %+v", healthy.Issues)
+	}
+	down := AnalyzeClusterDNS(ClusterDNSState{
+		Operator: OperatorStatus{Found: true, Degraded: true, Message: "Degraded: no dns pods available"},
+		Pods:     []CoreDNSPod{{Name: "dns-default-a", Phase: "Running", Reason: "CrashLoopBackOff"}},
+	}, time.Now())
+	if len(down.Issues) != 2 || down.Issues[0].Title != "DNS operator is not available" || down.Metrics["verdict"] != DNSVerdictClusterDNS {
+		t.Errorf("AnalyzeClusterDNS(down) = %+v", down.Issues)
+	}
+}
@@ -14,9 +14,12 @@ import (
 
 // DiagnosticCollector handles collection of various diagnostic data
 type DiagnosticCollector struct {
-	logger     *logrus.Logger
-	workingDir string
-	timeout    time.Duration
+	logger      *logrus.Logger
+	workingDir  string
+	timeout     time.Duration
+	command     commandFunc // overrides exec.CommandContext, e.g. in tests
+	mustGathers mustGatherJobs
+	artifacts   *ArtifactStore
 }
 
 // CollectionOptions defines options for diagnostic collection
@@ -44,10 +47,11 @@ type CollectionResult struct {
 	Summary  string            `json:"summary,omitempty"`
 }
 
-// NewDiagnosticCollector creates a new diagnostic collector
+// NewDiagnosticCollector creates a new diagnostic collector writing to
+// workingDir, which also holds the registry of what was collected
 func NewDiagnosticCollector(logger *logrus.Logger, workingDir string) *DiagnosticCollector {
 	if workingDir == "" {
-		workingDir = "/tmp/diagnostics"
+		workingDir = DefaultDiagnosticsDir
 	}
 
 	// Ensure working directory exists
@@ -57,171 +61,82 @@ func NewDiagnosticCollector(logger *logrus.Logger, workingDir string) *Diagnosti
 		logger:     logger,
 		workingDir: workingDir,
 		timeout:    30 * time.Minute,
+		artifacts:  NewArtifactStore(logger, workingDir, RetentionPolicy{}),
 	}
 }
 
-// CollectMustGather collects OpenShift must-gather data
-func (dc *DiagnosticCollector) CollectMustGather(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
-	start := time.Now()
-	result := &CollectionResult{
-		Type:     "must-gather",
-		Metadata: make(map[string]string),
-	}
+// WorkingDir returns the directory collected artifacts are written to
+func (dc *DiagnosticCollector) WorkingDir() string {
+	return dc.workingDir
+}
 
-	// Set default image if not specified
-	image := "registry.redhat.io/openshift4/ose-must-gather:latest"
-	if opts.Filters != nil && opts.Filters["image"] != "" {
-		image = opts.Filters["image"]
-	}
+// Artifacts returns the registry of collected artifacts
+func (dc *DiagnosticCollector) Artifacts() *ArtifactStore {
+	return dc.artifacts
+}
 
-	// Create output directory
-	outputDir := filepath.Join(dc.workingDir, fmt.Sprintf("must-gather-%d", time.Now().Unix()))
-	if opts.OutputDir != "" {
-		outputDir = opts.OutputDir
+// SetRetentionPolicy sets what the collector keeps and applies it
+func (dc *DiagnosticCollector) SetRetentionPolicy(policy RetentionPolicy) {
+	dc.artifacts.mu.Lock()
+	dc.artifacts.policy = policy
+	dc.artifacts.mu.Unlock()
+	if _, err := dc.artifacts.Cleanup(); err != nil {
+		dc.logger.Warnf("Artifact retention failed: %v", err)
 	}
-	os.MkdirAll(outputDir, 0755)
+}
 
-	// Build must-gather command
-	args := []string{
-		"adm", "must-gather",
-		"--image=" + image,
-		"--dest-dir=" + outputDir,
+// finishCollection packages a completed collection when requested and
+// registers it, under id when it is not empty
+func (dc *DiagnosticCollector) finishCollection(id string, result *CollectionResult, opts *CollectionOptions) {
+	if result.Status != "completed" {
+		return
 	}
-
-	if opts.Namespace != "" {
-		args = append(args, "--source-dir=/must-gather/"+opts.Namespace)
+	dc.packageIfRequested(result, opts)
+	artifact, err := dc.artifacts.Register(id, result)
+	if artifact != nil {
+		result.Metadata["artifact_id"] = artifact.ID
 	}
-
-	dc.logger.Infof("Starting must-gather collection with image: %s", image)
-
-	cmd := exec.CommandContext(ctx, "oc", args...)
-	output, err := cmd.CombinedOutput()
-
-	result.Duration = time.Since(start)
-	result.FilePath = outputDir
-	result.Metadata["image"] = image
-	result.Metadata["command"] = strings.Join(args, " ")
-
 	if err != nil {
-		result.Status = "failed"
-		result.ErrorMsg = fmt.Sprintf("Must-gather failed: %v, output: %s", err, string(output))
-		return result, err
-	}
-
-	// Get directory size
-	if size, err := dc.getDirSize(outputDir); err == nil {
-		result.Size = size
+		dc.logger.Warnf("Failed to register %s collection: %v", result.Type, err)
 	}
-
-	result.Status = "completed"
-	result.Summary = fmt.Sprintf("Must-gather collected successfully in %s (%.2f MB)",
-		outputDir, float64(result.Size)/(1024*1024))
-
-	dc.logger.Infof("Must-gather collection completed: %s", result.Summary)
-	return result, nil
 }
 
-// CollectSosReport collects sosreport from a specific node
-func (dc *DiagnosticCollector) CollectSosReport(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
-	start := time.Now()
+// CollectMustGather collects OpenShift must-gather data, waiting for the
+// collection to finish; StartMustGather runs it in the background instead
+func (dc *DiagnosticCollector) CollectMustGather(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
 	result := &CollectionResult{
-		Type:     "sosreport",
+		Type:     "must-gather",
 		Metadata: make(map[string]string),
 	}
 
-	if opts.NodeName == "" {
-		return nil, fmt.Errorf("node name is required for sosreport collection")
-	}
-
-	// Create output directory
-	outputDir := filepath.Join(dc.workingDir, fmt.Sprintf("sosreport-%s-%d", opts.NodeName, time.Now().Unix()))
-	os.MkdirAll(outputDir, 0755)
-
-	// Create debug pod for sosreport collection
-	debugPodYAML := fmt.Sprintf(`
-apiVersion: v1
-kind: Pod
-metadata:
-  name: sosreport-collector-%d
-  namespace: default
-spec:
-  nodeName: %s
-  hostNetwork: true
-  hostPID: true
-  hostIPC: true
-  tolerations:
-  - operator: Exists
-  securityContext:
-    privileged: true
-  containers:
-  - name: collector
-    image: registry.redhat.io/rhel8/support-tools:latest
-    command: ["/bin/bash", "-c"]
-    args:
-    - |
-      chroot /host sosreport --batch --tmp-dir=/host/tmp/sosreport-%s --name=%s
-      tar -czf /host/tmp/sosreport-%s.tar.gz -C /host/tmp sosreport-%s-*
-      sleep infinity
-    volumeMounts:
-    - name: host
-      mountPath: /host
-  volumes:
-  - name: host
-    hostPath:
-      path: /
-  restartPolicy: Never
-`, time.Now().Unix(), opts.NodeName, opts.NodeName, opts.NodeName, opts.NodeName, opts.NodeName)
-
-	// Apply debug pod
-	podFile := filepath.Join(outputDir, "sosreport-pod.yaml")
-	if err := os.WriteFile(podFile, []byte(debugPodYAML), 0644); err != nil {
-		result.Status = "failed"
-		result.ErrorMsg = fmt.Sprintf("Failed to write pod YAML: %v", err)
-		return result, err
-	}
-
-	dc.logger.Infof("Creating sosreport collection pod on node: %s", opts.NodeName)
-
-	// Apply the pod
-	cmd := exec.CommandContext(ctx, "oc", "apply", "-f", podFile)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	job, err := dc.StartMustGather(opts)
+	if err != nil {
 		result.Status = "failed"
-		result.ErrorMsg = fmt.Sprintf("Failed to create sosreport pod: %v, output: %s", err, string(output))
+		result.ErrorMsg = err.Error()
 		return result, err
 	}
-
-	podName := fmt.Sprintf("sosreport-collector-%d", time.Now().Unix())
-
-	// Wait for pod to be ready and collect sosreport
-	// This is a simplified version - in production, you'd want better error handling and monitoring
-	time.Sleep(60 * time.Second) // Wait for sosreport to complete
-
-	// Copy sosreport from node
-	copyCmd := exec.CommandContext(ctx, "oc", "cp",
-		fmt.Sprintf("default/%s:/host/tmp/sosreport-%s.tar.gz", podName, opts.NodeName),
-		filepath.Join(outputDir, fmt.Sprintf("sosreport-%s.tar.gz", opts.NodeName)))
-
-	if output, err := copyCmd.CombinedOutput(); err != nil {
-		dc.logger.Warnf("Failed to copy sosreport: %v, output: %s", err, string(output))
+	done, err := dc.WaitMustGather(ctx, job.ID)
+	if err != nil {
+		dc.CancelMustGather(job.ID)
+		done, _ = dc.MustGather(job.ID)
 	}
 
-	// Cleanup debug pod
-	cleanupCmd := exec.CommandContext(ctx, "oc", "delete", "pod", podName)
-	cleanupCmd.Run()
-
-	result.Duration = time.Since(start)
-	result.FilePath = outputDir
-	result.Metadata["node"] = opts.NodeName
-	result.Metadata["pod_name"] = podName
-
-	if size, err := dc.getDirSize(outputDir); err == nil {
-		result.Size = size
+	result.Duration = done.Finished.Sub(done.Started)
+	result.FilePath = done.DestDir
+	result.Size = done.Size
+	result.Status = done.Status
+	result.Metadata["job_id"] = done.ID
+	result.Metadata["image"] = strings.Join(done.Images, ",")
+	result.Metadata["command"] = done.Command
+	if done.Status != MustGatherCompleted {
+		result.ErrorMsg = fmt.Sprintf("Must-gather %s: %s", done.Status, done.Error)
+		return result, fmt.Errorf("must-gather %s: %s", done.Status, done.Error)
 	}
 
-	result.Status = "completed"
-	result.Summary = fmt.Sprintf("Sosreport collected from node %s in %s", opts.NodeName, outputDir)
-
-	dc.logger.Infof("Sosreport collection completed: %s", result.Summary)
+	result.Summary = fmt.Sprintf("Must-gather collected successfully in %s (%.2f MB)",
+		done.DestDir, float64(result.Size)/(1024*1024))
+	dc.finishCollection(done.ID, result, opts)
+	dc.logger.Infof("Must-gather collection completed: %s", result.Summary)
 	return result, nil
 }
 
@@ -338,6 +253,7 @@ spec:
 	result.Status = "completed"
 	result.Summary = fmt.Sprintf("Tcpdump completed, capture saved to %s (%.2f MB)",
 		outputFile, float64(result.Size)/(1024*1024))
+	dc.finishCollection("", result, opts)
 
 	dc.logger.Infof("Tcpdump collection completed: %s", result.Summary)
 	return result, nil
@@ -409,6 +325,7 @@ func (dc *DiagnosticCollector) CollectLogs(ctx context.Context, opts *Collection
 
 	result.Status = "completed"
 	result.Summary = fmt.Sprintf("Collected %d log files in %s", len(files), outputDir)
+	dc.finishCollection("", result, opts)
 
 	dc.logger.Infof("Log collection completed: %s", result.Summary)
 	return result, nil
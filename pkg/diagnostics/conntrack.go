@@ -0,0 +1,235 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Share of the conntrack table in use reported as a warning and as
+	// critical; the kernel drops new connections once it is full
+	conntrackUsageWarning  = 0.8
+	conntrackUsageCritical = 0.95
+
+	// Share of the ephemeral port range held by TCP sockets reported as a
+	// warning and as critical
+	ephemeralPortsWarning  = 0.7
+	ephemeralPortsCritical = 0.9
+
+	// DefaultEphemeralPorts is the size of the kernel's default
+	// ip_local_port_range, 32768-60999
+	DefaultEphemeralPorts = 60999 - 32768 + 1
+)
+
+// Where node network telemetry was read from
+const (
+	NodeNetSourceMetrics = "metrics" // node-exporter metrics in Prometheus
+	NodeNetSourceNode    = "node"    // /proc on the node itself
+)
+
+// NodeNetTelemetry is the conntrack table and TCP socket usage of a node.
+// Drops and InsertFailed count over the telemetry window; they are only
+// known from metrics. Fields without data are left zero.
+type NodeNetTelemetry struct {
+	Node             string  `json:"node"`
+	Source           string  `json:"source"`
+	ConntrackEntries float64 `json:"conntrack_entries"`
+	ConntrackLimit   float64 `json:"conntrack_limit"`
+	ConntrackDrops   float64 `json:"conntrack_drops"`         // packets dropped because the table was full
+	InsertFailed     float64 `json:"conntrack_insert_failed"` // entries lost to insert races, which reset connections
+	TCPInUse         float64 `json:"tcp_inuse"`
+	TCPTimeWait      float64 `json:"tcp_time_wait"`
+	EphemeralPorts   int     `json:"ephemeral_ports"` // size of ip_local_port_range; 0 when unknown
+}
+
+// ParseNodeNetProc reads the output of
+//
+//	cat /proc/sys/net/netfilter/nf_conntrack_count /proc/sys/net/netfilter/nf_conntrack_max \
+//	    /proc/sys/net/ipv4/ip_local_port_range /proc/net/sockstat
+//
+// run on a node
+func ParseNodeNetProc(node, output string) (NodeNetTelemetry, error) {
+	telemetry := NodeNetTelemetry{Node: node, Source: NodeNetSourceNode}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 3 {
+		return telemetry, fmt.Errorf("expected conntrack count, conntrack max and the port range, got %d lines", len(lines))
+	}
+
+	var err error
+	if telemetry.ConntrackEntries, err = strconv.ParseFloat(strings.TrimSpace(lines[0]), 64); err != nil {
+		return telemetry, fmt.Errorf("invalid nf_conntrack_count %q", lines[0])
+	}
+	if telemetry.ConntrackLimit, err = strconv.ParseFloat(strings.TrimSpace(lines[1]), 64); err != nil {
+		return telemetry, fmt.Errorf("invalid nf_conntrack_max %q", lines[1])
+	}
+	ports := strings.Fields(lines[2])
+	if len(ports) != 2 {
+		return telemetry, fmt.Errorf("invalid ip_local_port_range %q", lines[2])
+	}
+	low, lowErr := strconv.Atoi(ports[0])
+	high, highErr := strconv.Atoi(ports[1])
+	if lowErr != nil || highErr != nil || high < low {
+		return telemetry, fmt.Errorf("invalid ip_local_port_range %q", lines[2])
+	}
+	telemetry.EphemeralPorts = high - low + 1
+
+	// sockstat: "TCP: inuse 120 orphan 0 tw 3000 alloc 200 mem 30"
+	for _, line := range lines[3:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "TCP:" {
+			continue
+		}
+		for i := 1; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				continue
+			}
+			switch fields[i] {
+			case "inuse":
+				telemetry.TCPInUse = value
+			case "tw":
+				telemetry.TCPTimeWait = value
+			}
+		}
+	}
+	return telemetry, nil
+}
+
+// AnalyzeNodeNetwork reports nodes whose conntrack table is full or close to
+// it, and nodes whose TCP sockets hold most of the ephemeral port range.
+// Both make new connections fail intermittently, as resets, refusals or
+// timeouts that look healthy from inside the pods.
+func AnalyzeNodeNetwork(telemetry []NodeNetTelemetry, window time.Duration, now time.Time) *AnalysisResult {
+	result := &AnalysisResult{
+		Type:      "node-network",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+	telemetry = append([]NodeNetTelemetry(nil), telemetry...)
+	sort.Slice(telemetry, func(i, j int) bool { return telemetry[i].Node < telemetry[j].Node })
+
+	var conntrackNodes, portNodes []string
+	highestConntrack := 0.0
+	for _, node := range telemetry {
+		location := "node/" + node.Node
+		metadata := func(check string) map[string]string {
+			return map[string]string{"node": node.Node, "check": check, "source": node.Source}
+		}
+
+		if node.ConntrackDrops > 0 || node.InsertFailed > 0 {
+			conntrackNodes = append(conntrackNodes, node.Node)
+			var evidence []string
+			if node.ConntrackDrops > 0 {
+				evidence = append(evidence, fmt.Sprintf("%.0f packets dropped with a full conntrack table in the last %s", node.ConntrackDrops, window))
+			}
+			if node.InsertFailed > 0 {
+				evidence = append(evidence, fmt.Sprintf("%.0f conntrack insert failures in the last %s", node.InsertFailed, window))
+			}
+			result.Issues = append(result.Issues, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("Node %s is dropping connections in conntrack", node.Node),
+				Description: "The kernel could not track new connections, so their first packets were dropped; clients see intermittent timeouts and connection refused although the pods are healthy",
+				Location:    location,
+				Evidence:    evidence,
+				Resolution:  conntrackResolution(node.Node),
+				Metadata:    metadata("conntrack-drops"),
+			})
+		}
+
+		if node.ConntrackLimit > 0 {
+			usage := node.ConntrackEntries / node.ConntrackLimit
+			if usage > highestConntrack {
+				highestConntrack = usage
+			}
+			if usage >= conntrackUsageWarning {
+				severity := "warning"
+				if usage >= conntrackUsageCritical {
+					severity = "critical"
+				}
+				if !hasString(conntrackNodes, node.Node) {
+					conntrackNodes = append(conntrackNodes, node.Node)
+				}
+				result.Issues = append(result.Issues, Issue{
+					Severity:    severity,
+					Category:    "network",
+					Title:       fmt.Sprintf("Conntrack table of node %s is %s full", node.Node, percent(usage)),
+					Description: "Once nf_conntrack_max is reached the kernel drops packets of new connections (\"nf_conntrack: table full, dropping packet\" in the node's kernel log)",
+					Location:    location,
+					Evidence:    []string{fmt.Sprintf("Conntrack entries: %.0f of %.0f", node.ConntrackEntries, node.ConntrackLimit)},
+					Resolution:  conntrackResolution(node.Node),
+					Metadata:    metadata("conntrack-usage"),
+				})
+			}
+		}
+
+		ports := node.EphemeralPorts
+		if ports == 0 {
+			ports = DefaultEphemeralPorts
+		}
+		held := node.TCPInUse + node.TCPTimeWait
+		if usage := held / float64(ports); usage >= ephemeralPortsWarning {
+			severity := "warning"
+			if usage >= ephemeralPortsCritical {
+				severity = "critical"
+			}
+			portNodes = append(portNodes, node.Node)
+			evidence := []string{fmt.Sprintf("TCP sockets: %.0f in use, %.0f in TIME_WAIT", node.TCPInUse, node.TCPTimeWait)}
+			if node.EphemeralPorts > 0 {
+				evidence = append(evidence, fmt.Sprintf("ip_local_port_range holds %d ports", node.EphemeralPorts))
+			} else {
+				evidence = append(evidence, fmt.Sprintf("Assuming the default ip_local_port_range of %d ports", DefaultEphemeralPorts))
+			}
+			description := "Source ports are unique per destination address and port, so connections to one busy destination such as a database or an egress proxy run out first and fail with \"cannot assign requested address\" or are refused"
+			if node.TCPTimeWait > node.TCPInUse {
+				description += "; most sockets are in TIME_WAIT, the mark of short-lived connections that are not reused"
+			}
+			result.Issues = append(result.Issues, Issue{
+				Severity:    severity,
+				Category:    "network",
+				Title:       fmt.Sprintf("TCP sockets on node %s hold %s of the ephemeral port range", node.Node, percent(usage)),
+				Description: description,
+				Location:    location,
+				Evidence:    evidence,
+				Resolution:  "Reuse connections with keep-alive or connection pools in the clients on this node; spreading them over more nodes or widening net.ipv4.ip_local_port_range with a Tuned profile buys headroom",
+				Metadata:    metadata("ephemeral-ports"),
+			})
+		}
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	result.Metrics["nodes"] = len(telemetry)
+	result.Metrics["conntrack_nodes"] = len(conntrackNodes)
+	result.Metrics["ephemeral_port_nodes"] = len(portNodes)
+	result.Metrics["highest_conntrack_usage"] = highestConntrack
+
+	switch {
+	case len(conntrackNodes) == 0 && len(portNodes) == 0:
+		result.Summary = fmt.Sprintf("Conntrack tables and ephemeral ports have headroom on all %d nodes (highest conntrack usage %s)", len(telemetry), percent(highestConntrack))
+	default:
+		var parts []string
+		if len(conntrackNodes) > 0 {
+			parts = append(parts, fmt.Sprintf("conntrack saturated on %s", strings.Join(conntrackNodes, ", ")))
+		}
+		if len(portNodes) > 0 {
+			parts = append(parts, fmt.Sprintf("ephemeral ports short on %s", strings.Join(portNodes, ", ")))
+		}
+		result.Summary = fmt.Sprintf("Node-level connection limits explain intermittent failures: %s", strings.Join(parts, "; "))
+		result.Recommendations = append(result.Recommendations,
+			"Match the failing clients to these nodes (oc get pods -o wide); failures that follow the node and not the pod confirm the limit is the cause")
+	}
+	return result
+}
+
+// conntrackResolution is how to relieve a saturated conntrack table
+func conntrackResolution(node string) string {
+	return fmt.Sprintf("Find what opens the most connections (oc debug node/%s -- chroot /host conntrack -S, or conntrack -L | sort by destination), reuse connections in those clients, and raise net.netfilter.nf_conntrack_max with a Tuned profile if the load is legitimate", node)
+}
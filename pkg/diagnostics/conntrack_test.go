@@ -0,0 +1,70 @@
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNodeNetProc(t *testing.T) {
+	output := "250000\n262144\n32768\t60999\n" +
+		"sockets: used 900\n" +
+		"TCP: inuse 1200 orphan 3 tw 21000 alloc 1300 mem 80\n" +
+		"UDP: inuse 20 mem 4\n"
+
+	telemetry, err := ParseNodeNetProc("worker-1", output)
+	if err != nil {
+		t.Fatalf("ParseNodeNetProc returned %v", err)
+	}
+	expected := NodeNetTelemetry{Node: "worker-1", Source: NodeNetSourceNode, ConntrackEntries: 250000, ConntrackLimit: 262144,
+		TCPInUse: 1200, TCPTimeWait: 21000, EphemeralPorts: DefaultEphemeralPorts}
+	if telemetry != expected {
+		t.Errorf("telemetry = %+v, expected %+v", telemetry, expected)
+	}
+
+	for _, invalid := range []string{"", "12\n34\n", "12\nmax\n32768 60999", "12\n34\n60999 32768"} {
+		if _, err := ParseNodeNetProc("worker-1", invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestAnalyzeNodeNetwork(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	telemetry := []NodeNetTelemetry{
+		{Node: "worker-2", Source: NodeNetSourceMetrics, ConntrackEntries: 1000, ConntrackLimit: 262144, TCPInUse: 800, TCPTimeWait: 25000},
+		{Node: "worker-1", Source: NodeNetSourceMetrics, ConntrackEntries: 255000, ConntrackLimit: 262144, ConntrackDrops: 420},
+		{Node: "worker-3", Source: NodeNetSourceMetrics, ConntrackEntries: 5000, ConntrackLimit: 262144, TCPInUse: 300, TCPTimeWait: 200},
+	}
+
+	result := AnalyzeNodeNetwork(telemetry, 15*time.Minute, now)
+	if len(result.Issues) != 3 {
+		t.Fatalf("issues = %+v, expected drops, usage and ports", result.Issues)
+	}
+	if result.Metrics["conntrack_nodes"] != 1 || result.Metrics["ephemeral_port_nodes"] != 1 {
+		t.Errorf("metrics = %v", result.Metrics)
+	}
+	if result.Summary != "Node-level connection limits explain intermittent failures: conntrack saturated on worker-1; ephemeral ports short on worker-2" {
+		t.Errorf("summary = %q", result.Summary)
+	}
+
+	drops := result.Issues[0]
+	if drops.Metadata["check"] != "conntrack-drops" || drops.Evidence[0] != "420 packets dropped with a full conntrack table in the last 15m0s" {
+		t.Errorf("first issue = %+v, expected the conntrack drops", drops)
+	}
+	for _, issue := range result.Issues {
+		if issue.Metadata["check"] == "ephemeral-ports" {
+			if issue.Severity != "critical" || !strings.Contains(issue.Description, "TIME_WAIT") || !strings.Contains(issue.Evidence[1], "Assuming the default") {
+				t.Errorf("ephemeral port issue = %+v", issue)
+			}
+		}
+		if issue.Location == "node/worker-3" {
+			t.Errorf("healthy node reported: %+v", issue)
+		}
+	}
+
+	healthy := AnalyzeNodeNetwork(telemetry[2:3], 15*time.Minute, now)
+	if len(healthy.Issues) != 0 || !strings.Contains(healthy.Summary, "headroom on all 1 nodes") {
+		t.Errorf("healthy result = %+v", healthy)
+	}
+}
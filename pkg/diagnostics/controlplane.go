@@ -0,0 +1,490 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// An API request slower than this at the 99th percentile stalls
+	// controllers and makes oc feel hung
+	apiLatencySlow = time.Second
+
+	// An API server instance failing more than this share of its requests
+	// is broken, even when the others hide it behind the load balancer
+	apiErrorRatioWarning  = 0.01
+	apiErrorRatioCritical = 0.05
+)
+
+// Components of the control plane path an issue is in
+const (
+	ControlPlaneAPIServer = "apiserver"
+	ControlPlaneOAuth     = "oauth"
+)
+
+// Verdicts of a control plane probe: whether the platform or the
+// application is at fault
+const (
+	ControlPlaneVerdictHealthy  = "healthy"        // the control plane answers; look at the application
+	ControlPlaneVerdictPlatform = "platform"       // the API server or the path to it is broken
+	ControlPlaneVerdictAuth     = "authentication" // the API serves, but logins fail
+)
+
+// APIProbe is one request made to the API server
+type APIProbe struct {
+	Path    string        `json:"path"`
+	Status  int           `json:"status,omitempty"` // HTTP status; 0 when no response came back
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"` // why no response came back
+}
+
+// Failed reports whether the API server did not serve the request. Client
+// errors such as 403 are answers and do not count.
+func (p APIProbe) Failed() bool {
+	return p.Error != "" || p.Status >= 500 || p.Status == 429
+}
+
+// ControlPlaneEndpoint is an address in the control plane path and the
+// certificate it serves
+type ControlPlaneEndpoint struct {
+	Name    string `json:"name"` // api, api-int or oauth
+	Host    string `json:"host"`
+	Address string `json:"address"`
+	Error   string `json:"error,omitempty"` // why it could not be reached
+	// Status and Latency are of its health check, when it has one
+	Status      int              `json:"status,omitempty"`
+	Latency     time.Duration    `json:"latency,omitempty"`
+	Certificate *CertificateInfo `json:"certificate,omitempty"`
+	// HostnameError says why the certificate does not cover the host
+	HostnameError string `json:"hostname_error,omitempty"`
+}
+
+// IdentityProvider is an identity provider of the cluster OAuth config and
+// what it depends on
+type IdentityProvider struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Missing lists the secrets and config maps it references that do not
+	// exist, e.g. secret openshift-config/github-client
+	Missing       []string `json:"missing,omitempty"`
+	Endpoint      string   `json:"endpoint,omitempty"` // the server logins are checked against
+	EndpointError string   `json:"endpoint_error,omitempty"`
+}
+
+// ControlPlaneState is what probe_control_plane gathered about the path from
+// a client to the API server and the OAuth server
+type ControlPlaneState struct {
+	Operators         []OperatorStatus       `json:"operators"`
+	Probes            []APIProbe             `json:"probes"`
+	Endpoints         []ControlPlaneEndpoint `json:"endpoints"`
+	IdentityProviders []IdentityProvider     `json:"identity_providers,omitempty"`
+	// InstanceErrorRatio is the share of requests each API server instance
+	// answered with a 5xx, and LatencyP99 the 99th percentile of all
+	// requests, both from the cluster metrics
+	InstanceErrorRatio map[string]float64 `json:"instance_error_ratio,omitempty"`
+	LatencyP99         time.Duration      `json:"latency_p99,omitempty"`
+	MetricsRead        bool               `json:"metrics_read"`
+}
+
+// AnalyzeControlPlane reports on the API server and OAuth operators, the
+// requests made to the API server, the certificates and reachability of the
+// control plane endpoints and the identity providers, and concludes whether
+// the platform or the application is broken
+func AnalyzeControlPlane(state ControlPlaneState, now time.Time) *AnalysisResult {
+	result := &AnalysisResult{
+		Type:      "control-plane",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+
+	for _, operator := range state.Operators {
+		component := ControlPlaneAPIServer
+		if operator.Name == "authentication" {
+			component = ControlPlaneOAuth
+		}
+		location := "clusteroperator/" + operator.Name
+		switch {
+		case !operator.Found:
+			result.Issues = append(result.Issues, Issue{
+				Severity:    "info",
+				Category:    "configuration",
+				Title:       fmt.Sprintf("%s operator status not available", operator.Name),
+				Description: fmt.Sprintf("The %s ClusterOperator could not be read, so its view is missing from this report", operator.Name),
+				Location:    location,
+				Resolution:  "Check that the cluster is OpenShift and the account may read clusteroperators",
+			})
+		case !operator.Available:
+			result.Issues = append(result.Issues, Issue{
+				Severity:    "critical",
+				Category:    "availability",
+				Title:       fmt.Sprintf("%s operator is not available", operator.Name),
+				Description: fmt.Sprintf("The %s operator reports that its operand is not serving", operator.Name),
+				Location:    location,
+				Evidence:    operatorEvidence(operator),
+				Resolution:  fmt.Sprintf("Read the conditions and the operand pods: oc describe clusteroperator %s", operator.Name),
+				Metadata:    map[string]string{"component": component},
+			})
+		case operator.Degraded:
+			result.Issues = append(result.Issues, Issue{
+				Severity:    "warning",
+				Category:    "availability",
+				Title:       fmt.Sprintf("%s operator is degraded", operator.Name),
+				Description: fmt.Sprintf("The %s operand is serving, but its operator cannot reconcile it", operator.Name),
+				Location:    location,
+				Evidence:    operatorEvidence(operator),
+				Resolution:  fmt.Sprintf("Read the Degraded message: oc describe clusteroperator %s", operator.Name),
+				Metadata:    map[string]string{"component": component},
+			})
+		}
+	}
+
+	latencies := analyzeAPIProbes(state.Probes, result)
+	analyzeAPIMetrics(state, result)
+	for _, endpoint := range state.Endpoints {
+		analyzeControlPlaneEndpoint(endpoint, now, result)
+	}
+	for _, provider := range state.IdentityProviders {
+		analyzeIdentityProvider(provider, result)
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	failed := 0
+	for _, probe := range state.Probes {
+		if probe.Failed() {
+			failed++
+		}
+	}
+	verdict := controlPlaneVerdict(result.Issues)
+	result.Metrics["verdict"] = verdict
+	result.Metrics["api_requests"] = len(state.Probes)
+	result.Metrics["api_failures"] = failed
+	if len(latencies) > 0 {
+		result.Metrics["api_latency_p50"] = latencyPercentile(latencies, 0.5).String()
+		result.Metrics["api_latency_max"] = latencies[len(latencies)-1].String()
+	}
+	if state.MetricsRead {
+		result.Metrics["api_latency_p99"] = state.LatencyP99.String()
+	}
+	result.Metrics["identity_providers"] = len(state.IdentityProviders)
+
+	switch verdict {
+	case ControlPlaneVerdictPlatform:
+		result.Summary = "The platform is impaired: the API server or the path to it is failing"
+		result.Recommendations = append(result.Recommendations, "Treat failures of applications that call the API, deploy or scale as a platform problem until the issues above are fixed")
+	case ControlPlaneVerdictAuth:
+		result.Summary = "The API server is healthy, but logins through OAuth are failing"
+		result.Recommendations = append(result.Recommendations, "Existing tokens keep working; new logins to the console and oc login fail until OAuth is fixed")
+	default:
+		result.Summary = "The API server and OAuth are healthy; if something is broken, look at the application"
+		result.Recommendations = append(result.Recommendations, "Continue with the application: its pods, events and logs")
+	}
+	result.Summary += fmt.Sprintf(" (%d of %d API requests failed)", failed, len(state.Probes))
+	return result
+}
+
+// analyzeAPIProbes reports failed and slow requests to the API server and
+// returns the latencies of the answered requests, in order. Requests that
+// fail before any API server answers point at the load balancer in front of
+// them when other requests get through.
+func analyzeAPIProbes(probes []APIProbe, result *AnalysisResult) []time.Duration {
+	if len(probes) == 0 {
+		return nil
+	}
+	var latencies []time.Duration
+	var transport, serverErrors, throttled []string
+	for _, probe := range probes {
+		switch {
+		case probe.Error != "":
+			transport = append(transport, fmt.Sprintf("%s: %s", probe.Path, probe.Error))
+		case probe.Status == 429:
+			throttled = append(throttled, fmt.Sprintf("%s: HTTP 429 after %s", probe.Path, probe.Latency))
+		case probe.Status >= 500:
+			serverErrors = append(serverErrors, fmt.Sprintf("%s: HTTP %d after %s", probe.Path, probe.Status, probe.Latency))
+		}
+		if probe.Error == "" {
+			latencies = append(latencies, probe.Latency)
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	metadata := func() map[string]string { return map[string]string{"component": ControlPlaneAPIServer} }
+
+	switch {
+	case len(transport) == len(probes):
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       "API server is unreachable",
+			Description: fmt.Sprintf("None of %d requests reached an API server", len(probes)),
+			Location:    "API server",
+			Evidence:    capEvidence(transport, 5),
+			Resolution:  "Check the API load balancer and whether any kube-apiserver pod runs: oc get pods -n openshift-kube-apiserver from a master with the localhost kubeconfig",
+			Metadata:    metadata(),
+		})
+	case len(transport) > 0:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       "Some API requests fail before reaching an API server",
+			Description: fmt.Sprintf("%d of %d requests got no response while the others were answered; the load balancer is sending some connections to a backend that does not serve", len(transport), len(probes)),
+			Location:    "API load balancer",
+			Evidence:    capEvidence(transport, 5),
+			Resolution:  "Check the load balancer's backend pool and health checks: every member should be a master serving /readyz on port 6443, and removed masters must be taken out of the pool",
+			Metadata:    metadata(),
+		})
+	}
+	if len(serverErrors) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "availability",
+			Title:       "API server answers with server errors",
+			Description: fmt.Sprintf("%d of %d requests were answered with a 5xx", len(serverErrors), len(probes)),
+			Location:    "API server",
+			Evidence:    capEvidence(serverErrors, 5),
+			Resolution:  "Check etcd and the kube-apiserver logs: oc get clusteroperator etcd kube-apiserver and oc logs -n openshift-kube-apiserver <pod> -c kube-apiserver",
+			Metadata:    metadata(),
+		})
+	}
+	if len(throttled) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "performance",
+			Title:       "API server is throttling requests",
+			Description: fmt.Sprintf("%d of %d requests were rejected by API priority and fairness", len(throttled), len(probes)),
+			Location:    "API server",
+			Evidence:    capEvidence(throttled, 5),
+			Resolution:  "Find the clients filling the queues: query apiserver_flowcontrol_rejected_requests_total by flow_schema and priority_level",
+			Metadata:    metadata(),
+		})
+	}
+	if len(latencies) > 0 {
+		if p99 := latencyPercentile(latencies, 0.99); p99 >= apiLatencySlow {
+			result.Issues = append(result.Issues, Issue{
+				Severity:    "warning",
+				Category:    "performance",
+				Title:       "API requests are slow",
+				Description: fmt.Sprintf("The slowest 1%% of requests took %s or more, the median %s", p99, latencyPercentile(latencies, 0.5)),
+				Location:    "API server",
+				Resolution:  "Check etcd latency (etcd_disk_wal_fsync_duration_seconds) and the API server's CPU; a slow load balancer shows as slow requests with a healthy API server",
+				Metadata:    metadata(),
+			})
+		}
+	}
+	return latencies
+}
+
+// analyzeAPIMetrics reports API server instances that fail requests and
+// slow requests as the cluster metrics see them
+func analyzeAPIMetrics(state ControlPlaneState, result *AnalysisResult) {
+	if !state.MetricsRead {
+		return
+	}
+	healthy := 0
+	var failing []string
+	severity := "warning"
+	for _, instance := range sortedKeys(state.InstanceErrorRatio) {
+		ratio := state.InstanceErrorRatio[instance]
+		if ratio < apiErrorRatioWarning {
+			healthy++
+			continue
+		}
+		if ratio >= apiErrorRatioCritical {
+			severity = "critical"
+		}
+		failing = append(failing, fmt.Sprintf("%s: %.1f%% of requests failed with a 5xx", instance, ratio*100))
+	}
+	if len(failing) > 0 {
+		issue := Issue{
+			Severity:    severity,
+			Category:    "availability",
+			Title:       "API server instances fail requests",
+			Description: fmt.Sprintf("%d of %d API server instances answer with server errors", len(failing), len(state.InstanceErrorRatio)),
+			Location:    "openshift-kube-apiserver",
+			Evidence:    failing,
+			Resolution:  "Check the failing instances' logs and their etcd member: oc logs -n openshift-kube-apiserver kube-apiserver-<master> -c kube-apiserver",
+			Metadata:    map[string]string{"component": ControlPlaneAPIServer},
+		}
+		if healthy > 0 {
+			issue.Description += "; clients hit them whenever the load balancer picks them, so failures look random"
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+	if state.LatencyP99 >= apiLatencySlow {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "performance",
+			Title:       "API server is slow for all clients",
+			Description: fmt.Sprintf("The 99th percentile of API requests across the cluster is %s", state.LatencyP99.Round(time.Millisecond)),
+			Location:    "openshift-kube-apiserver",
+			Resolution:  "Check etcd latency and the API server's CPU and memory; list requests of large collections are a common cause",
+			Metadata:    map[string]string{"component": ControlPlaneAPIServer},
+		})
+	}
+}
+
+// analyzeControlPlaneEndpoint reports an endpoint that cannot be reached,
+// that fails its health check or that serves a wrong or expiring
+// certificate
+func analyzeControlPlaneEndpoint(endpoint ControlPlaneEndpoint, now time.Time, result *AnalysisResult) {
+	component := ControlPlaneAPIServer
+	if endpoint.Name == "oauth" {
+		component = ControlPlaneOAuth
+	}
+	location := fmt.Sprintf("%s %s", endpoint.Name, endpoint.Address)
+	metadata := func() map[string]string { return map[string]string{"component": component} }
+
+	if endpoint.Error != "" {
+		issue := Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       fmt.Sprintf("Cannot connect to %s", endpoint.Host),
+			Description: fmt.Sprintf("Connecting to the %s endpoint failed", endpoint.Name),
+			Location:    location,
+			Evidence:    []string{endpoint.Error},
+			Resolution:  "Check that the name resolves to the load balancer and that the load balancer has healthy backends",
+			Metadata:    metadata(),
+		}
+		switch endpoint.Name {
+		case "api-int":
+			// api-int only resolves inside the cluster network
+			issue.Severity = "warning"
+			issue.Description += "; nodes and pods use it to reach the API, but it only resolves inside the cluster, so this is expected when the server runs outside"
+		case "oauth":
+			issue.Resolution = "Check the oauth-openshift route and the ingress routers: oc get route oauth-openshift -n openshift-authentication and oc get pods -n openshift-ingress"
+		}
+		result.Issues = append(result.Issues, issue)
+		return
+	}
+	if endpoint.Status != 0 && endpoint.Status != 200 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "availability",
+			Title:       fmt.Sprintf("%s health check fails", endpoint.Host),
+			Description: fmt.Sprintf("The %s endpoint answered its health check with HTTP %d", endpoint.Name, endpoint.Status),
+			Location:    location,
+			Resolution:  fmt.Sprintf("Check the pods behind it: oc get pods -n %s", endpointNamespace(endpoint.Name)),
+			Metadata:    metadata(),
+		})
+	}
+
+	cert := endpoint.Certificate
+	if cert == nil {
+		return
+	}
+	if endpoint.HostnameError != "" {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "security",
+			Title:       fmt.Sprintf("%s serves a certificate that does not match its name", endpoint.Host),
+			Description: "Clients that verify certificates refuse the connection. The load balancer may terminate TLS itself or send the connection to the wrong backend, or a named certificate is missing the host",
+			Location:    location,
+			Evidence:    append([]string{endpoint.HostnameError, "Subject: " + cert.Subject, "Issuer: " + cert.Issuer}, capEvidence(cert.DNSNames, 5)...),
+			Resolution:  "Make the load balancer pass TLS through to the masters, or fix spec.servingCerts.namedCertificates of apiserver/cluster (ingress and componentRoutes for OAuth)",
+			Metadata:    metadata(),
+		})
+	}
+	days := cert.daysLeft(now)
+	evidence := []string{"Subject: " + cert.Subject, "Not after: " + cert.NotAfter.UTC().Format(time.RFC3339)}
+	switch {
+	case days < 0:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "security",
+			Title:       fmt.Sprintf("%s serves a certificate that expired %s", endpoint.Host, daysAgo(-days-1)),
+			Description: "Every client that verifies the certificate refuses the connection",
+			Location:    location,
+			Evidence:    evidence,
+			Resolution:  "Renew the certificate; run audit_certificates for the other certificates of the cluster",
+			Metadata:    metadata(),
+		})
+	case days < DefaultCertWarningDays && cert.rotationOverdue(now):
+		severity := "warning"
+		if days < DefaultCertCriticalDays {
+			severity = "critical"
+		}
+		result.Issues = append(result.Issues, Issue{
+			Severity:    severity,
+			Category:    "security",
+			Title:       fmt.Sprintf("%s certificate expires %s", endpoint.Host, daysAhead(days)),
+			Description: "The certificate is overdue for renewal; clients fail once it expires",
+			Location:    location,
+			Evidence:    evidence,
+			Resolution:  "Renew the certificate in the secret referenced by apiserver/cluster or ingresses.config/cluster, or check the operator that rotates it",
+			Metadata:    metadata(),
+		})
+	}
+}
+
+// analyzeIdentityProvider reports an identity provider whose secrets are
+// missing or whose server cannot be reached
+func analyzeIdentityProvider(provider IdentityProvider, result *AnalysisResult) {
+	location := fmt.Sprintf("oauth/cluster identity provider %s (%s)", provider.Name, provider.Type)
+	if len(provider.Missing) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "configuration",
+			Title:       fmt.Sprintf("Identity provider %s references missing objects", provider.Name),
+			Description: "The OAuth server cannot load the provider, so logins through it fail",
+			Location:    location,
+			Evidence:    provider.Missing,
+			Resolution:  "Create the objects in openshift-config or fix the references in oauth/cluster: oc edit oauth cluster",
+			Metadata:    map[string]string{"component": ControlPlaneOAuth},
+		})
+	}
+	if provider.EndpointError != "" {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       fmt.Sprintf("Identity provider %s cannot be reached", provider.Name),
+			Description: fmt.Sprintf("Logins through %s fail while %s does not answer", provider.Name, provider.Endpoint),
+			Location:    location,
+			Evidence:    []string{provider.EndpointError},
+			Resolution:  "Check egress from the cluster to the provider: proxy settings, firewalls and the provider itself; the oauth-openshift pods make the calls",
+			Metadata:    map[string]string{"component": ControlPlaneOAuth},
+		})
+	}
+}
+
+// controlPlaneVerdict concludes from the issues whether the API server, only
+// OAuth, or nothing in the control plane is broken
+func controlPlaneVerdict(issues []Issue) string {
+	verdict := ControlPlaneVerdictHealthy
+	for _, issue := range issues {
+		if issue.Severity == "info" {
+			continue
+		}
+		switch issue.Metadata["component"] {
+		case ControlPlaneAPIServer:
+			return ControlPlaneVerdictPlatform
+		case ControlPlaneOAuth:
+			verdict = ControlPlaneVerdictAuth
+		}
+	}
+	return verdict
+}
+
+// latencyPercentile returns the latency below which the share q of sorted
+// latencies falls
+func latencyPercentile(sorted []time.Duration, q float64) time.Duration {
+	index := int(float64(len(sorted))*q+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// endpointNamespace is where the pods behind a control plane endpoint run
+func endpointNamespace(name string) string {
+	if name == "oauth" {
+		return "openshift-authentication"
+	}
+	return "openshift-kube-apiserver"
+}
@@ -0,0 +1,107 @@
+package diagnostics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeControlPlane(t *testing.T) {
+	now := time.Now()
+	healthy := []OperatorStatus{
+		{Name: "kube-apiserver", Found: true, Available: true},
+		{Name: "openshift-apiserver", Found: true, Available: true},
+		{Name: "authentication", Found: true, Available: true},
+	}
+	probes := func(statuses ...int) []APIProbe {
+		var list []APIProbe
+		for i, status := range statuses {
+			probe := APIProbe{Path: "/readyz", Status: status, Latency: time.Duration(i+1) * 10 * time.Millisecond}
+			if status == 0 {
+				probe.Error = "read: connection reset by peer"
+			}
+			list = append(list, probe)
+		}
+		return list
+	}
+	apiCert := &CertificateInfo{Subject: "api.example.com", NotBefore: now.Add(-10 * 24 * time.Hour), NotAfter: now.Add(20 * 24 * time.Hour)}
+
+	result := AnalyzeControlPlane(ControlPlaneState{
+		Operators: healthy,
+		Probes:    probes(200, 200, 200, 403),
+		Endpoints: []ControlPlaneEndpoint{
+			{Name: "api", Host: "api.example.com", Address: "api.example.com:6443", Status: 200, Certificate: apiCert},
+			{Name: "oauth", Host: "oauth.apps.example.com", Address: "oauth.apps.example.com:443", Status: 200, Certificate: apiCert},
+		},
+		IdentityProviders:  []IdentityProvider{{Name: "htpasswd", Type: "HTPasswd"}},
+		InstanceErrorRatio: map[string]float64{"10.0.0.1:6443": 0, "10.0.0.2:6443": 0.001},
+		LatencyP99:         200 * time.Millisecond,
+		MetricsRead:        true,
+	}, now)
+	if len(result.Issues) != 0 || result.Metrics["verdict"] != ControlPlaneVerdictHealthy || result.Metrics["api_failures"] != 0 {
+		t.Errorf("healthy control plane: issues %+v, metrics %v", result.Issues, result.Metrics)
+	}
+
+	// One API server failing behind the load balancer, which also drops
+	// some connections, and an API certificate that does not match its name
+	result = AnalyzeControlPlane(ControlPlaneState{
+		Operators: []OperatorStatus{
+			{Name: "kube-apiserver", Found: true, Available: true, Degraded: true, Message: "Degraded: NodeInstallerDegraded"},
+			{Name: "authentication", Found: false},
+		},
+		Probes: probes(200, 0, 200, 503, 429),
+		Endpoints: []ControlPlaneEndpoint{
+			{Name: "api", Host: "api.example.com", Address: "api.example.com:6443", Status: 200, Certificate: &CertificateInfo{
+				Subject: "lb.example.com", NotBefore: now.Add(-300 * 24 * time.Hour), NotAfter: now.Add(3 * 24 * time.Hour),
+			}, HostnameError: "x509: certificate is valid for lb.example.com, not api.example.com"},
+			{Name: "api-int", Host: "api-int.example.com", Address: "api-int.example.com:6443", Error: "no such host"},
+		},
+		InstanceErrorRatio: map[string]float64{"10.0.0.1:6443": 0, "10.0.0.2:6443": 0.2},
+		LatencyP99:         3 * time.Second,
+		MetricsRead:        true,
+	}, now)
+	issues := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, severity := range map[string]string{
+		"kube-apiserver operator is degraded":                               "warning",
+		"authentication operator status not available":                      "info",
+		"Some API requests fail before reaching an API server":              "critical",
+		"API server answers with server errors":                             "critical",
+		"API server is throttling requests":                                 "warning",
+		"API server instances fail requests":                                "critical",
+		"API server is slow for all clients":                                "warning",
+		"api.example.com serves a certificate that does not match its name": "critical",
+		"api.example.com certificate expires in 3 days":                     "critical",
+		"Cannot connect to api-int.example.com":                             "warning",
+	} {
+		if issues[title].Severity != severity {
+			t.Errorf("AnalyzeControlPlane() issue %q = %+v, expected severity %s", title, issues[title], severity)
+		}
+	}
+	if len(result.Issues) != 10 {
+		t.Errorf("AnalyzeControlPlane() issues = %+v, expected 10", result.Issues)
+	}
+	if result.Metrics["verdict"] != ControlPlaneVerdictPlatform || result.Metrics["api_failures"] != 3 || result.Issues[0].Severity != "critical" {
+		t.Errorf("impaired control plane: metrics %v, first issue %+v", result.Metrics, result.Issues[0])
+	}
+
+	// The API is fine but logins fail
+	result = AnalyzeControlPlane(ControlPlaneState{
+		Operators: healthy,
+		Probes:    probes(200, 200),
+		Endpoints: []ControlPlaneEndpoint{
+			{Name: "oauth", Host: "oauth.apps.example.com", Address: "oauth.apps.example.com:443", Status: 503},
+		},
+		IdentityProviders: []IdentityProvider{
+			{Name: "corp", Type: "OpenID", Missing: []string{"secret openshift-config/corp-client"}, Endpoint: "sso.example.com:443", EndpointError: "i/o timeout"},
+		},
+	}, now)
+	if result.Metrics["verdict"] != ControlPlaneVerdictAuth || len(result.Issues) != 3 {
+		t.Errorf("OAuth failure: verdict %v, issues %+v", result.Metrics["verdict"], result.Issues)
+	}
+
+	if result := AnalyzeControlPlane(ControlPlaneState{Probes: probes(0, 0)}, now); result.Issues[0].Title != "API server is unreachable" {
+		t.Errorf("unreachable API server: issues %+v", result.Issues)
+	}
+}
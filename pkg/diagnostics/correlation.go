@@ -0,0 +1,393 @@
+package diagnostics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeline event sources
+const (
+	TimelineSourceLog    = "log"
+	TimelineSourceEvent  = "event"
+	TimelineSourceMetric = "metric"
+	TimelineSourceAlert  = "alert"
+)
+
+const (
+	// defaultCorrelationWindow is the largest gap between a cause and its effect
+	defaultCorrelationWindow = 5 * time.Minute
+
+	// defaultSkewTolerance is the gap below which two events from different
+	// sources are treated as simultaneous rather than ordered
+	defaultSkewTolerance = 2 * time.Second
+
+	// maxTimelineEvents bounds the events kept on one timeline
+	maxTimelineEvents = 1000
+
+	// maxCorrelations bounds the correlations reported for one analysis
+	maxCorrelations = 20
+
+	// timestampSearchLength is how much of a line is searched for a timestamp
+	timestampSearchLength = 64
+)
+
+// TimelineEvent is a single point on the unified incident timeline
+type TimelineEvent struct {
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"` // log, event, metric or alert
+	Origin   string    `json:"origin"` // file, namespace or metric name
+	Category string    `json:"category"`
+	Severity string    `json:"severity"`
+	Summary  string    `json:"summary"`
+}
+
+// Correlation links an earlier event to a later one from another category
+type Correlation struct {
+	Cause       TimelineEvent `json:"cause"`
+	Effect      TimelineEvent `json:"effect"`
+	Gap         string        `json:"gap"`
+	Concurrent  bool          `json:"concurrent,omitempty"`
+	Description string        `json:"description"`
+}
+
+// Correlator aligns events from logs, Kubernetes events and metrics on one
+// timeline, correcting for known clock skew between their origins
+type Correlator struct {
+	Window    time.Duration
+	Tolerance time.Duration
+
+	skews   map[string]time.Duration
+	events  []TimelineEvent
+	dropped int
+}
+
+// NewCorrelator creates a correlator with the default window and tolerance
+func NewCorrelator() *Correlator {
+	return &Correlator{
+		Window:    defaultCorrelationWindow,
+		Tolerance: defaultSkewTolerance,
+		skews:     make(map[string]time.Duration),
+	}
+}
+
+// SetClockSkew records that clocks of origins containing the given string
+// (a node name, file or namespace) run ahead of the reference by offset
+func (c *Correlator) SetClockSkew(origin string, offset time.Duration) {
+	c.skews[origin] = offset
+}
+
+// Add places an event on the timeline in UTC, after removing clock skew
+func (c *Correlator) Add(event TimelineEvent) {
+	if event.Time.IsZero() {
+		return
+	}
+	if len(c.events) >= maxTimelineEvents {
+		c.dropped++
+		return
+	}
+
+	for origin, offset := range c.skews {
+		if strings.Contains(event.Origin, origin) {
+			event.Time = event.Time.Add(-offset)
+			break
+		}
+	}
+	event.Time = event.Time.UTC()
+	c.events = append(c.events, event)
+}
+
+// Timeline returns the events ordered by time
+func (c *Correlator) Timeline() []TimelineEvent {
+	sort.SliceStable(c.events, func(i, j int) bool {
+		return c.events[i].Time.Before(c.events[j].Time)
+	})
+	return append([]TimelineEvent(nil), c.events...)
+}
+
+// Correlate pairs each event with the later events of other categories that
+// followed it within the window. Only the first pairing of each kind of cause
+// and effect is reported.
+func (c *Correlator) Correlate() []Correlation {
+	timeline := c.Timeline()
+	seen := make(map[string]bool)
+	var correlations []Correlation
+
+	for i, cause := range timeline {
+		for _, effect := range timeline[i+1:] {
+			gap := effect.Time.Sub(cause.Time)
+			if gap > c.Window {
+				break
+			}
+			if effect.Category == cause.Category && effect.Source == cause.Source {
+				continue
+			}
+
+			key := cause.Summary + "→" + effect.Summary
+			if seen[key] || seen[effect.Summary+"→"+cause.Summary] {
+				continue
+			}
+			seen[key] = true
+
+			correlations = append(correlations, newCorrelation(cause, effect, gap, gap <= c.Tolerance))
+			if len(correlations) >= maxCorrelations {
+				return correlations
+			}
+		}
+	}
+	return correlations
+}
+
+func newCorrelation(cause, effect TimelineEvent, gap time.Duration, concurrent bool) Correlation {
+	correlation := Correlation{Cause: cause, Effect: effect, Gap: gap.String(), Concurrent: concurrent}
+	if concurrent {
+		correlation.Description = fmt.Sprintf("%s at %s coincided with %s at %s",
+			cause.Summary, cause.Time.Format("15:04:05"), effect.Summary, effect.Time.Format("15:04:05"))
+	} else {
+		correlation.Description = fmt.Sprintf("%s at %s preceded %s at %s (%s later)",
+			cause.Summary, cause.Time.Format("15:04:05"), effect.Summary, effect.Time.Format("15:04:05"), gap)
+	}
+	return correlation
+}
+
+var (
+	isoTimestamp    = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[T ](\d{2}:\d{2}:\d{2})(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	slashTimestamp  = regexp.MustCompile(`(\d{4})/(\d{2})/(\d{2}) (\d{2}:\d{2}:\d{2})(\.\d+)?`)
+	klogTimestamp   = regexp.MustCompile(`^[IWEF](\d{2})(\d{2}) (\d{2}:\d{2}:\d{2})(\.\d+)?`)
+	syslogTimestamp = regexp.MustCompile(`^([A-Z][a-z]{2}) +(\d{1,2}) (\d{2}:\d{2}:\d{2})`)
+	clfTimestamp    = regexp.MustCompile(`(\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})`)
+)
+
+// parseLogTimestamp finds the timestamp at the start of a log line and
+// returns it in UTC. Timestamps without a zone are read in loc, and formats
+// without a year (klog, syslog) take it from reference, the time the log was
+// written, rolling back a year for entries that would be in its future.
+func parseLogTimestamp(line string, loc *time.Location, reference time.Time) (time.Time, bool) {
+	if len(line) > timestampSearchLength {
+		line = line[:timestampSearchLength]
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if m := isoTimestamp.FindStringSubmatch(line); m != nil {
+		value := m[1] + "T" + m[2] + m[3]
+		zone := strings.Replace(m[4], ":", "", 1)
+		if zone == "" {
+			if t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", value, loc); err == nil {
+				return t.UTC(), true
+			}
+		} else {
+			if zone == "Z" {
+				zone = "+0000"
+			}
+			if t, err := time.Parse("2006-01-02T15:04:05.999999999-0700", value+zone); err == nil {
+				return t.UTC(), true
+			}
+		}
+	}
+
+	if m := clfTimestamp.FindStringSubmatch(line); m != nil {
+		if t, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[1]); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	if m := slashTimestamp.FindStringSubmatch(line); m != nil {
+		value := m[1] + "-" + m[2] + "-" + m[3] + "T" + m[4] + m[5]
+		if t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", value, loc); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	if reference.IsZero() {
+		reference = time.Now()
+	}
+	year := reference.In(loc).Year()
+
+	var t time.Time
+	var err error
+	if m := klogTimestamp.FindStringSubmatch(line); m != nil {
+		t, err = time.ParseInLocation("2006-01-02T15:04:05.999999999", fmt.Sprintf("%d-%s-%sT%s%s", year, m[1], m[2], m[3], m[4]), loc)
+	} else if m := syslogTimestamp.FindStringSubmatch(line); m != nil {
+		t, err = time.ParseInLocation("2006 Jan 2 15:04:05", fmt.Sprintf("%d %s %s %s", year, m[1], m[2], m[3]), loc)
+	} else {
+		return time.Time{}, false
+	}
+	if err != nil {
+		return time.Time{}, false
+	}
+	if t.After(reference.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t.UTC(), true
+}
+
+// noteSeen records when an issue was first and last seen
+func noteSeen(metadata map[string]string, t time.Time) {
+	stamp := t.Format(time.RFC3339Nano)
+	if first, err := time.Parse(time.RFC3339Nano, metadata["first_seen"]); err != nil || t.Before(first) {
+		metadata["first_seen"] = stamp
+	}
+	if last, err := time.Parse(time.RFC3339Nano, metadata["last_seen"]); err != nil || t.After(last) {
+		metadata["last_seen"] = stamp
+	}
+}
+
+// timestampParser returns a parser for lines of a file written up to reference
+func (ae *AnalysisEngine) timestampParser(reference time.Time) func(string) (time.Time, bool) {
+	return func(line string) (time.Time, bool) {
+		return parseLogTimestamp(line, ae.timezone, reference)
+	}
+}
+
+// buildTimeline places timestamped issues and collected events on one
+// timeline and correlates them
+func (ae *AnalysisEngine) buildTimeline(result *AnalysisResult) {
+	correlator := NewCorrelator()
+	for origin, offset := range ae.clockSkew {
+		correlator.SetClockSkew(origin, offset)
+	}
+
+	for _, issue := range result.Issues {
+		first, err := time.Parse(time.RFC3339Nano, issue.Metadata["first_seen"])
+		if err != nil {
+			continue
+		}
+		origin := issue.Location
+		if idx := strings.LastIndex(origin, ":line "); idx >= 0 {
+			origin = origin[:idx]
+		}
+		correlator.Add(TimelineEvent{
+			Time:     first,
+			Source:   TimelineSourceLog,
+			Origin:   origin,
+			Category: issue.Category,
+			Severity: issue.Severity,
+			Summary:  issue.Title,
+		})
+	}
+	for _, event := range result.events {
+		correlator.Add(event)
+	}
+	result.events = nil
+
+	result.Timeline = correlator.Timeline()
+	result.Correlations = correlator.Correlate()
+	if correlator.dropped > 0 {
+		result.Metrics["timeline_events_dropped"] = correlator.dropped
+	}
+}
+
+// readEventTimeline streams a must-gather events.yaml and returns its
+// Warning events as timeline entries
+func (ae *AnalysisEngine) readEventTimeline(path string, result *AnalysisResult) ([]TimelineEvent, error) {
+	budget := ae.budgetFor(result)
+	if budget.heapExceeded {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []TimelineEvent
+	var parent string
+	fields := make(map[string]string)
+	flush := func() {
+		if fields["type"] == "Warning" {
+			if event, ok := ae.eventToTimeline(fields); ok {
+				events = append(events, event)
+			}
+		}
+		fields = make(map[string]string)
+	}
+
+	err = readLines(file, budget.limits.MaxLineLength, func(lineNum int, line string, truncated bool) bool {
+		if truncated {
+			budget.cutLines++
+		}
+
+		// Items of the event list start with "- " and their fields are
+		// indented by two spaces, involvedObject fields by four
+		if strings.HasPrefix(line, "- ") {
+			flush()
+			line = "  " + line[2:]
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return len(events) < maxTimelineEvents && budget.checkHeap()
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch {
+		case indent == 2:
+			fields[key] = value
+			parent = key
+		case indent == 4 && parent == "involvedObject":
+			fields["object."+key] = value
+		}
+		return len(events) < maxTimelineEvents && budget.checkHeap()
+	})
+	flush()
+	return events, err
+}
+
+// eventToTimeline converts the fields of one event into a timeline entry
+func (ae *AnalysisEngine) eventToTimeline(fields map[string]string) (TimelineEvent, bool) {
+	var at time.Time
+	for _, key := range []string{"firstTimestamp", "eventTime", "lastTimestamp"} {
+		if value := fields[key]; value != "" && value != "null" {
+			if t, ok := parseLogTimestamp(value, ae.timezone, time.Time{}); ok {
+				at = t
+				break
+			}
+		}
+	}
+	if at.IsZero() {
+		return TimelineEvent{}, false
+	}
+
+	message := fields["message"]
+	if len(message) > 120 {
+		message = message[:120] + "..."
+	}
+	summary := fmt.Sprintf("%s %s/%s", fields["reason"], fields["object.kind"], fields["object.name"])
+	if message != "" {
+		summary += ": " + message
+	}
+
+	return TimelineEvent{
+		Time:     at,
+		Source:   TimelineSourceEvent,
+		Origin:   fields["object.namespace"],
+		Category: "events",
+		Severity: "warning",
+		Summary:  summary,
+	}, true
+}
+
+// collectEventTimelines reads the cluster and namespace events of a must-gather
+func (ae *AnalysisEngine) collectEventTimelines(mustGatherPath string, result *AnalysisResult) {
+	paths := []string{filepath.Join(mustGatherPath, "cluster-scoped-resources", "core", "events.yaml")}
+	namespaced, _ := filepath.Glob(filepath.Join(mustGatherPath, "namespaces", "*", "core", "events.yaml"))
+	paths = append(paths, namespaced...)
+
+	for _, path := range paths {
+		events, err := ae.readEventTimeline(path, result)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				ae.logger.Warnf("Failed to read events %s: %v", path, err)
+			}
+			continue
+		}
+		result.events = append(result.events, events...)
+	}
+}
@@ -0,0 +1,153 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogTimestamp(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		newYork = time.FixedZone("EST", -5*3600)
+	}
+	reference := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		line     string
+		loc      *time.Location
+		expected string
+	}{
+		{"2024-05-01T10:02:03Z ERROR out of memory", nil, "2024-05-01T10:02:03Z"},
+		{"2024-05-01T12:02:03.250+02:00 oom", nil, "2024-05-01T10:02:03.25Z"},
+		{"2024-05-01 10:02:03,123 WARN connection refused", nil, "2024-05-01T10:02:03Z"},
+		{"2024-05-01 06:02:03 local time", newYork, "2024-05-01T10:02:03Z"},
+		{"2024/05/01 10:02:03 http: panic serving", nil, "2024-05-01T10:02:03Z"},
+		{"E0501 10:02:03.500000       1 controller.go:114] sync failed", nil, "2024-05-01T10:02:03.5Z"},
+		{"May  1 10:02:03 worker-0 kubelet[1234]: OOM", nil, "2024-05-01T10:02:03Z"},
+		{"Dec 31 23:59:59 worker-0 kubelet: rotated", nil, "2023-12-31T23:59:59Z"},
+		{`10.0.0.1 - - [01/May/2024:12:02:03 +0200] "GET / HTTP/1.1" 503`, nil, "2024-05-01T10:02:03Z"},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseLogTimestamp(tt.line, tt.loc, reference)
+		if !ok {
+			t.Errorf("parseLogTimestamp(%q) found no timestamp", tt.line)
+			continue
+		}
+		if formatted := got.Format(time.RFC3339Nano); formatted != tt.expected {
+			t.Errorf("parseLogTimestamp(%q) = %s, expected %s", tt.line, formatted, tt.expected)
+		}
+	}
+
+	if _, ok := parseLogTimestamp("no timestamp here", nil, reference); ok {
+		t.Errorf("parseLogTimestamp(%q) expected no timestamp", "no timestamp here")
+	}
+}
+
+func TestCorrelatorOrdersAndCorrectsSkew(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 2, 0, 0, time.UTC)
+	correlator := NewCorrelator()
+	// The router node clock runs 90s fast, so its 10:04:30 is really 10:03:00
+	correlator.SetClockSkew("router", 90*time.Second)
+
+	correlator.Add(TimelineEvent{Time: base.Add(150 * time.Second), Source: TimelineSourceLog, Origin: "router/haproxy.log", Category: "network", Summary: "route 503s"})
+	correlator.Add(TimelineEvent{Time: base, Source: TimelineSourceEvent, Origin: "shop", Category: "memory", Summary: "OOM kill"})
+	correlator.Add(TimelineEvent{Time: base.Add(time.Hour), Source: TimelineSourceMetric, Origin: "cpu", Category: "performance", Summary: "CPU spike"})
+
+	timeline := correlator.Timeline()
+	if len(timeline) != 3 || timeline[0].Summary != "OOM kill" || !timeline[1].Time.Equal(base.Add(time.Minute)) {
+		t.Fatalf("Timeline() = %+v, expected OOM kill then skew-corrected 503s", timeline)
+	}
+
+	correlations := correlator.Correlate()
+	if len(correlations) != 1 {
+		t.Fatalf("Correlate() returned %d correlations, expected 1 (CPU spike is outside the window)", len(correlations))
+	}
+	expected := "OOM kill at 10:02:00 preceded route 503s at 10:03:00 (1m0s later)"
+	if correlations[0].Description != expected {
+		t.Errorf("Correlate()[0].Description = %q, expected %q", correlations[0].Description, expected)
+	}
+}
+
+func TestCorrelatorTreatsSkewToleranceAsConcurrent(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 2, 0, 0, time.UTC)
+	correlator := NewCorrelator()
+	correlator.Add(TimelineEvent{Time: base, Source: TimelineSourceLog, Category: "network", Summary: "connection refused"})
+	correlator.Add(TimelineEvent{Time: base.Add(time.Second), Source: TimelineSourceEvent, Category: "events", Summary: "Unhealthy Pod/web"})
+
+	correlations := correlator.Correlate()
+	if len(correlations) != 1 || !correlations[0].Concurrent || !strings.Contains(correlations[0].Description, "coincided with") {
+		t.Errorf("Correlate() = %+v, expected one concurrent correlation", correlations)
+	}
+}
+
+func TestAnalyzeMustGatherBuildsTimeline(t *testing.T) {
+	dir := t.TempDir()
+	eventsDir := filepath.Join(dir, "namespaces", "shop", "core")
+	logDir := filepath.Join(dir, "namespaces", "openshift-ingress", "pods", "router")
+	for _, d := range []string{eventsDir, logDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events := `apiVersion: v1
+items:
+- apiVersion: v1
+  firstTimestamp: "2024-05-01T10:02:00Z"
+  involvedObject:
+    kind: Pod
+    name: web-1
+    namespace: shop
+  lastTimestamp: "2024-05-01T10:02:00Z"
+  message: Container web was OOMKilled
+  metadata:
+    name: web-1.17a
+    namespace: shop
+  reason: OOMKilling
+  type: Warning
+- apiVersion: v1
+  firstTimestamp: "2024-05-01T10:01:00Z"
+  involvedObject:
+    kind: Pod
+    name: web-1
+  message: Started container
+  reason: Started
+  type: Normal
+kind: List
+`
+	if err := os.WriteFile(filepath.Join(eventsDir, "events.yaml"), []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logs := "2024-05-01T10:03:00Z ERROR backend web: connection refused\n"
+	if err := os.WriteFile(filepath.Join(logDir, "router.log"), []byte(logs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeMustGather(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeMustGather failed: %v", err)
+	}
+
+	if len(result.Timeline) != 2 {
+		t.Fatalf("timeline has %d events, expected 2: %+v", len(result.Timeline), result.Timeline)
+	}
+	if result.Timeline[0].Source != TimelineSourceEvent || result.Timeline[0].Origin != "shop" {
+		t.Errorf("timeline[0] = %+v, expected the OOMKilling event from shop", result.Timeline[0])
+	}
+
+	if len(result.Correlations) != 1 {
+		t.Fatalf("found %d correlations, expected 1", len(result.Correlations))
+	}
+	description := result.Correlations[0].Description
+	if !strings.HasPrefix(description, "OOMKilling Pod/web-1") || !strings.Contains(description, "preceded Connection Refused at 10:03:00") {
+		t.Errorf("correlation = %q, expected the OOM kill to precede the connection errors", description)
+	}
+}
@@ -0,0 +1,349 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// SERVFAIL share of CoreDNS responses reported as a warning and as
+	// critical
+	dnsServfailWarning  = 0.05
+	dnsServfailCritical = 0.20
+
+	// Share of forwarded queries the upstream resolvers fail
+	dnsUpstreamErrorWarning = 0.05
+
+	// p99 latency of forwarded queries; the forward plugin gives up on an
+	// upstream after about 2s
+	dnsUpstreamLatencyWarning  = 500 * time.Millisecond
+	dnsUpstreamLatencyCritical = 2 * time.Second
+
+	// A cache answering less than this share of queries sends most of them
+	// upstream
+	dnsCacheHitLow = 0.5
+
+	// A CoreDNS pod failing this many times more than the cluster average is
+	// the problem rather than its upstreams
+	dnsPodOutlierFactor = 3
+)
+
+// Where DNS failures come from, as AnalyzeDNS concludes
+const (
+	DNSVerdictHealthy    = "healthy"
+	DNSVerdictClusterDNS = "cluster-dns" // CoreDNS itself fails
+	DNSVerdictUpstream   = "upstream"    // the resolvers CoreDNS forwards to fail
+	DNSVerdictPodPath    = "pod-path"    // CoreDNS is healthy but pods cannot reach it
+)
+
+// DNSTelemetry is what the CoreDNS metrics say about cluster DNS over a
+// rate window. Ratios are between 0 and 1; Missing names the metrics that
+// returned no data, whose fields are left zero.
+type DNSTelemetry struct {
+	Window             time.Duration      `json:"window"`
+	RequestRate        float64            `json:"request_rate"` // queries per second
+	ServfailRatio      float64            `json:"servfail_ratio"`
+	CacheHitRatio      float64            `json:"cache_hit_ratio"`
+	UpstreamLatencyP99 time.Duration      `json:"upstream_latency_p99"`
+	UpstreamErrorRatio float64            `json:"upstream_error_ratio"`
+	HealthcheckFailed  float64            `json:"healthcheck_failed"` // times every upstream failed its health check
+	PodServfailRatio   map[string]float64 `json:"pod_servfail_ratio,omitempty"`
+	Missing            []string           `json:"missing,omitempty"`
+}
+
+// missing reports whether a metric returned no data
+func (t DNSTelemetry) missing(metric string) bool {
+	for _, name := range t.Missing {
+		if name == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// DNS telemetry metric names, as listed in DNSTelemetry.Missing
+const (
+	DNSMetricRequests        = "requests"
+	DNSMetricServfail        = "servfail"
+	DNSMetricCacheHits       = "cache_hits"
+	DNSMetricUpstreamLatency = "upstream_latency"
+	DNSMetricUpstreamErrors  = "upstream_errors"
+	DNSMetricHealthchecks    = "healthchecks"
+	DNSMetricPodServfail     = "pod_servfail"
+)
+
+// DNSSymptoms are the results of DNS lookups run from inside a pod: one
+// cluster-internal name and one external name
+type DNSSymptoms struct {
+	Pod            string   `json:"pod"`
+	Namespace      string   `json:"namespace"`
+	Nameserver     string   `json:"nameserver,omitempty"` // first nameserver of resolv.conf
+	Ndots          int      `json:"ndots,omitempty"`
+	InternalFailed bool     `json:"internal_failed"`
+	ExternalFailed bool     `json:"external_failed"`
+	Timeout        bool     `json:"timeout"`        // no nameserver answered
+	ServerFailure  bool     `json:"server_failure"` // the nameserver answered SERVFAIL
+	NotFound       bool     `json:"not_found"`      // a name did not exist (NXDOMAIN)
+	Evidence       []string `json:"evidence,omitempty"`
+}
+
+// Failed reports whether any lookup from the pod failed
+func (s DNSSymptoms) Failed() bool {
+	return s.InternalFailed || s.ExternalFailed
+}
+
+// AnalyzeDNS reports what the CoreDNS metrics show and explains the pod
+// lookup failures with them, telling cluster DNS problems from upstream
+// resolver problems and from pods that cannot reach CoreDNS at all
+func AnalyzeDNS(telemetry DNSTelemetry, symptoms []DNSSymptoms, now time.Time) *AnalysisResult {
+	result := &AnalysisResult{
+		Type:      "dns",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+	window := telemetry.Window.String()
+
+	upstreamDegraded := false
+	clusterDegraded := false
+
+	if telemetry.HealthcheckFailed > 0 {
+		upstreamDegraded = true
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       "Every upstream resolver failed CoreDNS health checks",
+			Description: fmt.Sprintf("CoreDNS found all upstream resolvers unhealthy %.0f times in the last %s, so external names could not be resolved", telemetry.HealthcheckFailed, window),
+			Location:    "CoreDNS forward plugin",
+			Evidence:    []string{fmt.Sprintf("coredns_forward_healthcheck_broken_total increased by %.0f", telemetry.HealthcheckFailed)},
+			Resolution:  "Check that the nodes reach the upstream resolvers (oc get dns.operator/default -o yaml for upstreamResolvers, otherwise each node's /etc/resolv.conf) on port 53",
+			Metadata:    map[string]string{"verdict": DNSVerdictUpstream},
+		})
+	}
+
+	if !telemetry.missing(DNSMetricUpstreamErrors) && telemetry.UpstreamErrorRatio >= dnsUpstreamErrorWarning {
+		upstreamDegraded = true
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("Upstream resolvers fail %s of forwarded queries", percent(telemetry.UpstreamErrorRatio)),
+			Description: fmt.Sprintf("Queries CoreDNS forwards outside the cluster come back as SERVFAIL or REFUSED over the last %s", window),
+			Location:    "CoreDNS forward plugin",
+			Evidence:    []string{fmt.Sprintf("Upstream error ratio: %s", percent(telemetry.UpstreamErrorRatio))},
+			Resolution:  "Check the health and rate limits of the upstream resolvers; the failures are outside the cluster",
+			Metadata:    map[string]string{"verdict": DNSVerdictUpstream},
+		})
+	}
+
+	if !telemetry.missing(DNSMetricUpstreamLatency) && telemetry.UpstreamLatencyP99 >= dnsUpstreamLatencyWarning {
+		upstreamDegraded = true
+		severity := "warning"
+		if telemetry.UpstreamLatencyP99 >= dnsUpstreamLatencyCritical {
+			severity = "critical"
+		}
+		result.Issues = append(result.Issues, Issue{
+			Severity:    severity,
+			Category:    "performance",
+			Title:       fmt.Sprintf("Upstream DNS answers take %s at p99", telemetry.UpstreamLatencyP99.Round(time.Millisecond)),
+			Description: "Slow upstream resolvers delay every lookup that misses the CoreDNS cache; past about 2s CoreDNS gives up and clients see timeouts",
+			Location:    "CoreDNS forward plugin",
+			Evidence:    []string{fmt.Sprintf("p99 of coredns_forward_request_duration_seconds: %s", telemetry.UpstreamLatencyP99.Round(time.Millisecond))},
+			Resolution:  "Check the latency from the nodes to the upstream resolvers, or point CoreDNS at closer resolvers through dns.operator/default upstreamResolvers",
+			Metadata:    map[string]string{"verdict": DNSVerdictUpstream},
+		})
+	}
+
+	if !telemetry.missing(DNSMetricServfail) && telemetry.ServfailRatio >= dnsServfailWarning {
+		severity := "warning"
+		if telemetry.ServfailRatio >= dnsServfailCritical {
+			severity = "critical"
+		}
+		verdict := DNSVerdictClusterDNS
+		description := fmt.Sprintf("CoreDNS answered %s of queries with SERVFAIL over the last %s while its upstreams look healthy", percent(telemetry.ServfailRatio), window)
+		resolution := "Check the CoreDNS pods in openshift-dns: oc logs -n openshift-dns ds/dns-default -c dns, and oc get clusteroperator dns"
+		if upstreamDegraded {
+			verdict = DNSVerdictUpstream
+			description = fmt.Sprintf("CoreDNS answered %s of queries with SERVFAIL over the last %s, passed on from failing upstream resolvers", percent(telemetry.ServfailRatio), window)
+			resolution = "Fix the upstream resolvers first; CoreDNS returns SERVFAIL when they fail"
+		} else {
+			clusterDegraded = true
+		}
+		result.Issues = append(result.Issues, Issue{
+			Severity:    severity,
+			Category:    "network",
+			Title:       fmt.Sprintf("%s of DNS responses are SERVFAIL", percent(telemetry.ServfailRatio)),
+			Description: description,
+			Location:    "CoreDNS",
+			Evidence:    []string{fmt.Sprintf("SERVFAIL ratio: %s at %.1f queries/s", percent(telemetry.ServfailRatio), telemetry.RequestRate)},
+			Resolution:  resolution,
+			Metadata:    map[string]string{"verdict": verdict},
+		})
+	}
+
+	pods := make([]string, 0, len(telemetry.PodServfailRatio))
+	for pod := range telemetry.PodServfailRatio {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+	for _, pod := range pods {
+		ratio := telemetry.PodServfailRatio[pod]
+		if len(telemetry.PodServfailRatio) < 2 || ratio < dnsServfailWarning || ratio < dnsPodOutlierFactor*telemetry.ServfailRatio {
+			continue
+		}
+		clusterDegraded = true
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("CoreDNS pod %s fails %s of its queries", pod, percent(ratio)),
+			Description: fmt.Sprintf("The pod fails far more often than the cluster average of %s, so only the clients on its node are affected", percent(telemetry.ServfailRatio)),
+			Location:    "openshift-dns/" + pod,
+			Evidence:    []string{fmt.Sprintf("SERVFAIL ratio of %s: %s", pod, percent(ratio))},
+			Resolution:  fmt.Sprintf("Check the logs of the pod and its node's connectivity; deleting it lets the DaemonSet recreate it: oc delete pod -n openshift-dns %s", pod),
+			Metadata:    map[string]string{"verdict": DNSVerdictClusterDNS, "pod": pod},
+		})
+	}
+
+	if !telemetry.missing(DNSMetricCacheHits) && telemetry.RequestRate > 0 && telemetry.CacheHitRatio < dnsCacheHitLow {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "info",
+			Category:    "performance",
+			Title:       fmt.Sprintf("CoreDNS cache answers only %s of lookups", percent(telemetry.CacheHitRatio)),
+			Description: "Most queries are forwarded or resolved again, which adds latency and load; many search domain expansions of external names (ndots:5) cause this",
+			Location:    "CoreDNS cache plugin",
+			Evidence:    []string{fmt.Sprintf("Cache hit ratio: %s", percent(telemetry.CacheHitRatio))},
+			Resolution:  "Use fully qualified external names with a trailing dot, or lower ndots in the pod's dnsConfig, to avoid the search domain lookups",
+		})
+	}
+
+	for _, symptom := range symptoms {
+		if issue, ok := dnsSymptomIssue(symptom, !telemetry.missing(DNSMetricServfail), upstreamDegraded, clusterDegraded); ok {
+			result.Issues = append(result.Issues, issue)
+		}
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	verdict := dnsVerdict(result.Issues)
+	result.Metrics["verdict"] = verdict
+	result.Metrics["request_rate"] = telemetry.RequestRate
+	result.Metrics["servfail_ratio"] = telemetry.ServfailRatio
+	result.Metrics["cache_hit_ratio"] = telemetry.CacheHitRatio
+	result.Metrics["upstream_latency_p99_ms"] = telemetry.UpstreamLatencyP99.Milliseconds()
+	result.Metrics["upstream_error_ratio"] = telemetry.UpstreamErrorRatio
+
+	failing := 0
+	for _, symptom := range symptoms {
+		if symptom.Failed() {
+			failing++
+		}
+	}
+	switch verdict {
+	case DNSVerdictUpstream:
+		result.Summary = "DNS failures come from the upstream resolvers CoreDNS forwards to, not from cluster DNS"
+	case DNSVerdictClusterDNS:
+		result.Summary = "DNS failures come from CoreDNS in the cluster"
+	case DNSVerdictPodPath:
+		result.Summary = "CoreDNS is healthy, but the affected pods cannot reach it"
+	default:
+		result.Summary = "CoreDNS and its upstream resolvers look healthy"
+	}
+	var details []string
+	if !telemetry.missing(DNSMetricRequests) {
+		details = append(details, fmt.Sprintf("%.1f queries/s, %s SERVFAIL, %s cache hits over %s", telemetry.RequestRate, percent(telemetry.ServfailRatio), percent(telemetry.CacheHitRatio), window))
+	}
+	if len(symptoms) > 0 {
+		details = append(details, fmt.Sprintf("lookups failed from %d of %d pods", failing, len(symptoms)))
+	}
+	if len(details) > 0 {
+		result.Summary += " (" + strings.Join(details, "; ") + ")"
+	}
+	if len(telemetry.Missing) > 0 {
+		result.Summary += fmt.Sprintf("; no data for %s", strings.Join(telemetry.Missing, ", "))
+	}
+
+	switch verdict {
+	case DNSVerdictUpstream:
+		result.Recommendations = append(result.Recommendations, "Escalate to whoever runs the upstream resolvers; restarting CoreDNS will not help")
+	case DNSVerdictClusterDNS:
+		result.Recommendations = append(result.Recommendations, "Check the DNS operator and the dns-default pods in openshift-dns: oc get clusteroperator dns and oc get pods -n openshift-dns -o wide")
+	case DNSVerdictPodPath:
+		result.Recommendations = append(result.Recommendations, "Check NetworkPolicies and egress firewalls that could block UDP and TCP 53/5353 from the affected pods to openshift-dns")
+	}
+	return result
+}
+
+// dnsSymptomIssue explains the failed lookups of one pod with the state of
+// cluster DNS and its upstreams, when the metrics showed it
+func dnsSymptomIssue(symptom DNSSymptoms, metricsKnown, upstreamDegraded, clusterDegraded bool) (Issue, bool) {
+	if !symptom.Failed() {
+		return Issue{}, false
+	}
+	location := fmt.Sprintf("%s/%s", symptom.Namespace, symptom.Pod)
+	issue := Issue{
+		Severity: "warning",
+		Category: "network",
+		Location: location,
+		Evidence: symptom.Evidence,
+		Metadata: map[string]string{"pod": symptom.Pod, "namespace": symptom.Namespace},
+	}
+	if symptom.Nameserver != "" {
+		issue.Evidence = append([]string{"Nameserver: " + symptom.Nameserver}, issue.Evidence...)
+	}
+
+	switch {
+	case symptom.InternalFailed && symptom.Timeout && metricsKnown && !clusterDegraded:
+		issue.Severity = "critical"
+		issue.Title = fmt.Sprintf("Pod %s cannot reach cluster DNS", location)
+		issue.Description = "Lookups time out although CoreDNS answers other clients, so the path from the pod to the DNS service is blocked"
+		issue.Resolution = "Check NetworkPolicies in the pod's namespace for egress to openshift-dns on port 5353, and the pod's dnsPolicy and resolv.conf nameserver"
+		issue.Metadata["verdict"] = DNSVerdictPodPath
+	case symptom.InternalFailed:
+		issue.Severity = "critical"
+		issue.Title = fmt.Sprintf("Cluster names do not resolve from pod %s", location)
+		issue.Description = "Service names fail to resolve"
+		if clusterDegraded {
+			issue.Description += ", matching the CoreDNS failures above"
+		}
+		issue.Resolution = "Check the CoreDNS pods in openshift-dns and the DNS operator"
+		issue.Metadata["verdict"] = DNSVerdictClusterDNS
+	case upstreamDegraded:
+		issue.Title = fmt.Sprintf("External names do not resolve from pod %s", location)
+		issue.Description = "Cluster names resolve, and the CoreDNS metrics show its upstream resolvers failing or slow"
+		issue.Resolution = "Fix the upstream resolvers; the cluster DNS is working"
+		issue.Metadata["verdict"] = DNSVerdictUpstream
+	case symptom.NotFound && !symptom.ServerFailure && !symptom.Timeout:
+		issue.Severity = "info"
+		issue.Title = fmt.Sprintf("The external name does not exist for pod %s", location)
+		issue.Description = "The upstream resolvers answered that the name does not exist (NXDOMAIN); the lookup itself works"
+		issue.Resolution = "Check the name, and whether the upstream resolvers are meant to resolve it, e.g. a split-horizon zone"
+	default:
+		issue.Title = fmt.Sprintf("External names do not resolve from pod %s", location)
+		issue.Description = "Cluster names resolve and the CoreDNS metrics look healthy, so the failure is likely specific to the upstream zone or to this pod's egress"
+		issue.Resolution = "Run the lookup from another pod and from a node (oc debug node/<node> -- chroot /host dig <name>) to tell the zone from the pod apart"
+		issue.Metadata["verdict"] = DNSVerdictUpstream
+	}
+	return issue, true
+}
+
+// dnsVerdict picks where the failures come from: the first critical
+// issue's verdict, otherwise the first verdict found
+func dnsVerdict(issues []Issue) string {
+	for _, severity := range []string{"critical", "warning"} {
+		for _, issue := range issues {
+			if issue.Severity == severity && issue.Metadata["verdict"] != "" {
+				return issue.Metadata["verdict"]
+			}
+		}
+	}
+	return DNSVerdictHealthy
+}
+
+// percent renders a ratio as a percentage
+func percent(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
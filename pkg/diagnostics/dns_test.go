@@ -0,0 +1,96 @@
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeDNSUpstream(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	telemetry := DNSTelemetry{
+		Window:             5 * time.Minute,
+		RequestRate:        120,
+		ServfailRatio:      0.12,
+		CacheHitRatio:      0.8,
+		UpstreamLatencyP99: 2500 * time.Millisecond,
+		UpstreamErrorRatio: 0.3,
+	}
+	symptoms := []DNSSymptoms{
+		{Pod: "web-1", Namespace: "shop", Nameserver: "172.30.0.10", ExternalFailed: true, ServerFailure: true},
+		{Pod: "web-2", Namespace: "shop"},
+	}
+
+	result := AnalyzeDNS(telemetry, symptoms, now)
+	if verdict := result.Metrics["verdict"]; verdict != DNSVerdictUpstream {
+		t.Fatalf("verdict = %v, expected %s; issues: %+v", verdict, DNSVerdictUpstream, result.Issues)
+	}
+	if !strings.Contains(result.Summary, "upstream resolvers") || !strings.Contains(result.Summary, "lookups failed from 1 of 2 pods") {
+		t.Errorf("summary = %q", result.Summary)
+	}
+	var servfail, pod *Issue
+	for i, issue := range result.Issues {
+		switch {
+		case strings.Contains(issue.Title, "SERVFAIL"):
+			servfail = &result.Issues[i]
+		case issue.Location == "shop/web-1":
+			pod = &result.Issues[i]
+		case issue.Location == "shop/web-2":
+			t.Errorf("healthy pod reported: %+v", issue)
+		}
+	}
+	if servfail == nil || servfail.Metadata["verdict"] != DNSVerdictUpstream {
+		t.Errorf("SERVFAIL issue = %+v, expected it blamed on the upstreams", servfail)
+	}
+	if pod == nil || pod.Metadata["verdict"] != DNSVerdictUpstream || pod.Evidence[0] != "Nameserver: 172.30.0.10" {
+		t.Errorf("pod issue = %+v", pod)
+	}
+	if result.Issues[0].Severity != "critical" {
+		t.Errorf("first issue = %+v, expected the critical latency first", result.Issues[0])
+	}
+}
+
+func TestAnalyzeDNSClusterAndPodPath(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	clusterDNS := DNSTelemetry{
+		Window:           5 * time.Minute,
+		RequestRate:      50,
+		ServfailRatio:    0.06,
+		CacheHitRatio:    0.9,
+		PodServfailRatio: map[string]float64{"dns-default-a": 0.01, "dns-default-b": 0.3},
+	}
+	symptoms := []DNSSymptoms{{Pod: "api-1", Namespace: "shop", InternalFailed: true, ServerFailure: true}}
+	result := AnalyzeDNS(clusterDNS, symptoms, now)
+	if verdict := result.Metrics["verdict"]; verdict != DNSVerdictClusterDNS {
+		t.Errorf("verdict = %v, expected %s; issues: %+v", verdict, DNSVerdictClusterDNS, result.Issues)
+	}
+	found := false
+	for _, issue := range result.Issues {
+		if issue.Metadata["pod"] == "dns-default-b" {
+			found = true
+		}
+		if issue.Metadata["pod"] == "dns-default-a" {
+			t.Errorf("healthy CoreDNS pod reported: %+v", issue)
+		}
+	}
+	if !found {
+		t.Errorf("expected the failing CoreDNS pod to be reported: %+v", result.Issues)
+	}
+
+	// CoreDNS answers everyone else, so a timing out pod cannot reach it
+	healthy := DNSTelemetry{Window: 5 * time.Minute, RequestRate: 50, CacheHitRatio: 0.9}
+	symptoms = []DNSSymptoms{{Pod: "api-1", Namespace: "shop", InternalFailed: true, ExternalFailed: true, Timeout: true}}
+	if verdict := AnalyzeDNS(healthy, symptoms, now).Metrics["verdict"]; verdict != DNSVerdictPodPath {
+		t.Errorf("verdict = %v, expected %s", verdict, DNSVerdictPodPath)
+	}
+
+	// Without metrics the same timeout is not blamed on the pod's path
+	unknown := DNSTelemetry{Window: 5 * time.Minute, Missing: []string{DNSMetricRequests, DNSMetricServfail}}
+	if verdict := AnalyzeDNS(unknown, symptoms, now).Metrics["verdict"]; verdict != DNSVerdictClusterDNS {
+		t.Errorf("verdict without metrics = %v, expected %s", verdict, DNSVerdictClusterDNS)
+	}
+
+	if result := AnalyzeDNS(healthy, nil, now); result.Metrics["verdict"] != DNSVerdictHealthy || len(result.Issues) != 0 {
+		t.Errorf("healthy DNS = %v with %+v", result.Metrics["verdict"], result.Issues)
+	}
+}
@@ -0,0 +1,343 @@
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats an analysis result can be exported in
+const (
+	ExportJSON  = "json"  // the full AnalysisResult
+	ExportSARIF = "sarif" // SARIF 2.1.0 for code scanning and security pipelines
+	ExportHTML  = "html"  // a standalone report to attach to a ticket
+)
+
+// sarifSchema is the schema of the SARIF documents exported
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// exportToolName names the analyzer in exported reports
+const exportToolName = "openshift-mcp-go"
+
+// ParseExportFormat validates an export format; an empty format means no
+// export
+func ParseExportFormat(format string) (string, error) {
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "", ExportJSON, ExportSARIF, ExportHTML:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q: use json, sarif or html", format)
+}
+
+// ExportContentType returns the content type of an export format
+func ExportContentType(format string) string {
+	switch format {
+	case ExportSARIF:
+		return "application/sarif+json"
+	case ExportHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/json"
+}
+
+// Export renders the result in one of the export formats
+func (r *AnalysisResult) Export(format string) ([]byte, error) {
+	switch format {
+	case ExportJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case ExportSARIF:
+		return exportSARIF(r)
+	case ExportHTML:
+		return exportHTML(r)
+	}
+	return nil, fmt.Errorf("unsupported export format %q: use json, sarif or html", format)
+}
+
+// SARIF 2.1.0 subset the exporter writes
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool              `json:"tool"`
+	Invocations []sarifInvocation      `json:"invocations,omitempty"`
+	Results     []sarifResult          `json:"results"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	FullDescription  *sarifMessage          `json:"fullDescription,omitempty"`
+	Help             *sarifMessage          `json:"help,omitempty"`
+	HelpURI          string                 `json:"helpUri,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool      `json:"executionSuccessful"`
+	EndTimeUTC          time.Time `json:"endTimeUtc"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+}
+
+var (
+	// sarifRuleChars are replaced in rule IDs derived from issue titles
+	sarifRuleChars = regexp.MustCompile(`[^a-z0-9]+`)
+	// fileLineLocation is an issue location of the form path:line
+	fileLineLocation = regexp.MustCompile(`^(.+):(\d+)$`)
+)
+
+// sarifRuleID derives a stable rule ID from an issue's category and title
+func sarifRuleID(issue Issue) string {
+	title := strings.Trim(sarifRuleChars.ReplaceAllString(strings.ToLower(issue.Title), "-"), "-")
+	if issue.Category == "" {
+		return title
+	}
+	return issue.Category + "/" + title
+}
+
+// sarifLevel maps an issue severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "warning":
+		return "warning"
+	}
+	return "note"
+}
+
+// sarifLocations places an issue in a file, at its line when known, or in
+// the component it names
+func sarifLocations(location string) []sarifLocation {
+	if location == "" {
+		return nil
+	}
+	if match := fileLineLocation.FindStringSubmatch(location); match != nil && strings.Contains(match[1], "/") {
+		line, _ := strconv.Atoi(match[2])
+		return []sarifLocation{{PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: match[1]},
+			Region:           &sarifRegion{StartLine: line},
+		}}}
+	}
+	if strings.HasPrefix(location, "/") {
+		return []sarifLocation{{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: location}}}}
+	}
+	return []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{Name: location}}}}
+}
+
+// exportSARIF renders the issues as SARIF results, one rule per distinct
+// issue, so pipelines can gate on and track them
+func exportSARIF(r *AnalysisResult) ([]byte, error) {
+	run := sarifRun{
+		Tool:        sarifTool{Driver: sarifDriver{Name: exportToolName, Rules: []sarifRule{}}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true, EndTimeUTC: r.Timestamp.UTC()}},
+		Results:     []sarifResult{},
+		Properties:  map[string]interface{}{"analysisType": r.Type, "path": r.FilePath},
+	}
+	if r.Summary != "" {
+		run.Properties["summary"] = r.Summary
+	}
+	if r.Truncated {
+		run.Properties["truncationReasons"] = r.TruncationReasons
+	}
+
+	rules := make(map[string]int)
+	for _, issue := range r.Issues {
+		id := sarifRuleID(issue)
+		index, ok := rules[id]
+		if !ok {
+			rule := sarifRule{ID: id, Name: issue.Title, ShortDescription: sarifMessage{Text: issue.Title}}
+			if issue.Description != "" {
+				rule.FullDescription = &sarifMessage{Text: issue.Description}
+			}
+			if issue.Resolution != "" {
+				rule.Help = &sarifMessage{Text: issue.Resolution}
+			}
+			if len(issue.References) > 0 {
+				rule.HelpURI = issue.References[0].URL
+			}
+			if issue.Category != "" {
+				rule.Properties = map[string]interface{}{"tags": []string{issue.Category}}
+			}
+			index = len(run.Tool.Driver.Rules)
+			rules[id] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		message := issue.Title
+		if issue.Description != "" {
+			message += ": " + issue.Description
+		}
+		result := sarifResult{
+			RuleID:    id,
+			RuleIndex: index,
+			Level:     sarifLevel(issue.Severity),
+			Message:   sarifMessage{Text: message},
+			Locations: sarifLocations(issue.Location),
+		}
+		properties := map[string]interface{}{"severity": issue.Severity}
+		if len(issue.Evidence) > 0 {
+			properties["evidence"] = issue.Evidence
+		}
+		if len(issue.Metadata) > 0 {
+			properties["metadata"] = issue.Metadata
+		}
+		if len(issue.References) > 0 {
+			properties["references"] = issue.References
+		}
+		result.Properties = properties
+		run.Results = append(run.Results, result)
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// htmlReport is a standalone page with inline styles, so the file can be
+// attached to a ticket or mailed without anything else
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"utc": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Result.Type}} report: {{.Result.FilePath}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 70em; color: #1f2328; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.25em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+table { border-collapse: collapse; } td, th { padding: .2em .8em .2em 0; text-align: left; vertical-align: top; }
+.issue { border: 1px solid #d0d7de; border-left-width: 6px; border-radius: 4px; margin: 1em 0; padding: .5em 1em; }
+.critical { border-left-color: #cf222e; } .warning { border-left-color: #bf8700; } .info { border-left-color: #0969da; }
+.severity { font-size: .8em; font-weight: bold; text-transform: uppercase; }
+pre { background: #f6f8fa; padding: .5em; overflow-x: auto; white-space: pre-wrap; }
+.muted { color: #59636e; }
+</style>
+</head>
+<body>
+<h1>{{.Result.Type}} report</h1>
+<table>
+<tr><th>Path</th><td>{{.Result.FilePath}}</td></tr>
+<tr><th>Analyzed</th><td>{{utc .Result.Timestamp}}</td></tr>
+<tr><th>Issues</th><td>{{.Critical}} critical, {{.Warning}} warning, {{.Info}} info</td></tr>
+</table>
+{{if .Result.Summary}}<p>{{.Result.Summary}}</p>{{end}}
+{{if .Result.Truncated}}<p class="muted">The analysis was truncated: {{range $i, $reason := .Result.TruncationReasons}}{{if $i}}; {{end}}{{$reason}}{{end}}</p>{{end}}
+<h2>Issues</h2>
+{{range .Result.Issues}}<div class="issue {{.Severity}}">
+<div class="severity">{{.Severity}}{{if .Category}} · {{.Category}}{{end}}</div>
+<h3>{{.Title}}</h3>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .Location}}<p class="muted">Location: {{.Location}}</p>{{end}}
+{{if .Evidence}}<pre>{{range .Evidence}}{{.}}
+{{end}}</pre>{{end}}
+{{if .Resolution}}<p><strong>Resolution:</strong> {{.Resolution}}</p>{{end}}
+{{range .References}}<p>Known issue: <a href="{{.URL}}">{{.Title}}</a></p>{{end}}
+{{if .Metadata}}<table class="muted">{{range $key, $value := .Metadata}}<tr><td>{{$key}}</td><td>{{$value}}</td></tr>{{end}}</table>{{end}}
+</div>
+{{else}}<p>No issues found.</p>
+{{end}}
+{{if .Result.Recommendations}}<h2>Recommendations</h2>
+<ul>{{range .Result.Recommendations}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Result.Correlations}}<h2>Correlations</h2>
+<ul>{{range .Result.Correlations}}<li>{{.Description}}</li>{{end}}</ul>{{end}}
+{{if .Metrics}}<h2>Metrics</h2>
+<table>{{range .Metrics}}<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>{{end}}</table>{{end}}
+<p class="muted">Generated by ` + exportToolName + `</p>
+</body>
+</html>
+`))
+
+// htmlMetric is a metric of the report, in name order
+type htmlMetric struct {
+	Name  string
+	Value string
+}
+
+// exportHTML renders the result as a standalone HTML page
+func exportHTML(r *AnalysisResult) ([]byte, error) {
+	data := struct {
+		Result                  *AnalysisResult
+		Critical, Warning, Info int
+		Metrics                 []htmlMetric
+	}{Result: r}
+	for _, issue := range r.Issues {
+		switch issue.Severity {
+		case "critical":
+			data.Critical++
+		case "warning":
+			data.Warning++
+		default:
+			data.Info++
+		}
+	}
+	for name, value := range r.Metrics {
+		data.Metrics = append(data.Metrics, htmlMetric{Name: name, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(data.Metrics, func(i, j int) bool { return data.Metrics[i].Name < data.Metrics[j].Name })
+
+	var buf bytes.Buffer
+	if err := htmlReport.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,101 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportTestResult() *AnalysisResult {
+	return &AnalysisResult{
+		Type:     "log-analysis",
+		FilePath: "/tmp/diagnostics/logs",
+		Issues: []Issue{
+			{Severity: "critical", Category: "etcd", Title: "Etcd Write Failure", Description: "etcd rejected writes", Location: "/tmp/diagnostics/logs/etcd.log:12",
+				Evidence: []string{"etcdserver: mvcc: database space exceeded"}, Resolution: "Defragment etcd",
+				References: []Reference{{ID: "etcd-database-space-exceeded", Kind: KnownIssueSearch, Title: "etcd database exceeded its space quota", URL: "https://access.redhat.com/search/?q=mvcc"}}},
+			{Severity: "critical", Category: "etcd", Title: "Etcd Write Failure", Location: "/tmp/diagnostics/logs/etcd.log:40"},
+			{Severity: "info", Category: "pods", Title: "Pod <restarting>", Location: "shop/api"},
+		},
+		Metrics:   map[string]interface{}{"total_lines": 120},
+		Summary:   "Found 2 critical issues",
+		Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	for input, expected := range map[string]string{"": "", "SARIF": ExportSARIF, " html ": ExportHTML, "json": ExportJSON} {
+		if format, err := ParseExportFormat(input); err != nil || format != expected {
+			t.Errorf("ParseExportFormat(%q) = %q, %v", input, format, err)
+		}
+	}
+	if _, err := ParseExportFormat("pdf"); err == nil {
+		t.Error("ParseExportFormat(pdf) accepted an unsupported format")
+	}
+}
+
+func TestExportSARIF(t *testing.T) {
+	data, err := exportTestResult().Export(ExportSARIF)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, data)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF log = %+v", log)
+	}
+	run := log.Runs[0]
+
+	// Repeated issues share one rule
+	var ids []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+	}
+	if expected := []string{"etcd/etcd-write-failure", "pods/pod-restarting"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("rule IDs = %q, expected %q", ids, expected)
+	}
+	if rule := run.Tool.Driver.Rules[0]; rule.Help == nil || rule.Help.Text != "Defragment etcd" || rule.HelpURI != "https://access.redhat.com/search/?q=mvcc" {
+		t.Errorf("rule = %+v", rule)
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("got %d results, expected 3", len(run.Results))
+	}
+	first := run.Results[0]
+	if first.Level != "error" || first.Message.Text != "Etcd Write Failure: etcd rejected writes" {
+		t.Errorf("first result = %+v", first)
+	}
+	if location := first.Locations[0].PhysicalLocation; location.ArtifactLocation.URI != "/tmp/diagnostics/logs/etcd.log" || location.Region.StartLine != 12 {
+		t.Errorf("first result location = %+v", location)
+	}
+	if second := run.Results[1]; second.RuleIndex != 0 {
+		t.Errorf("second result rule index = %d, expected the shared rule", second.RuleIndex)
+	}
+	if third := run.Results[2]; third.Level != "note" || third.Locations[0].LogicalLocations[0].Name != "shop/api" {
+		t.Errorf("third result = %+v", third)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	data, err := exportTestResult().Export(ExportHTML)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<td>2 critical, 0 warning, 1 info</td>",
+		"<h3>Etcd Write Failure</h3>",
+		`<a href="https://access.redhat.com/search/?q=mvcc">etcd database exceeded its space quota</a>`,
+		"<h3>Pod &lt;restarting&gt;</h3>",
+		"<tr><td>total_lines</td><td>120</td></tr>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report missing %q:\n%s", want, html)
+		}
+	}
+}
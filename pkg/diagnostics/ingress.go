@@ -0,0 +1,961 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// IngressNamespace runs the router pods of every IngressController
+	IngressNamespace = "openshift-ingress"
+
+	// IngressManifestFile lists what an ingress collection gathered
+	IngressManifestFile = "ingress-manifest.json"
+	// IngressStateFile holds the controllers, routers, load balancers and
+	// rejected routes of the collection
+	IngressStateFile = "ingress-state.json"
+
+	defaultIngressLogSince = "1h"
+
+	// routerControllerLabel names the IngressController a router pod serves
+	routerControllerLabel = "ingresscontroller.operator.openshift.io/deployment-ingresscontroller"
+	// routerServiceLabel names the IngressController a router service publishes
+	routerServiceLabel = "ingresscontroller.operator.openshift.io/owning-ingresscontroller"
+
+	// routerRestartWarning is the restart count from which a router is
+	// reported as restarting
+	routerRestartWarning = 3
+)
+
+// The checks run in the router container of each router pod
+const (
+	IngressCheckInfo = "haproxy-info"
+	IngressCheckStat = "haproxy-stat"
+)
+
+// routerChecks read HAProxy's process information and the state of every
+// backend through the router's admin socket
+var routerChecks = []containerCheck{
+	{IngressCheckInfo, "router", []string{"sh", "-c", "echo 'show info' | socat stdio /var/lib/haproxy/run/haproxy.sock"}},
+	{IngressCheckStat, "router", []string{"sh", "-c", "echo 'show stat' | socat stdio /var/lib/haproxy/run/haproxy.sock"}},
+}
+
+// IngressManifest describes an ingress collection
+type IngressManifest struct {
+	Controller  string             `json:"controller,omitempty"`
+	Since       string             `json:"since"`
+	CollectedAt time.Time          `json:"collected_at"`
+	Duration    string             `json:"duration"`
+	Pods        []CollectedPod     `json:"pods"`
+	Logs        []LogManifestEntry `json:"logs"`
+	Checks      []CheckOutput      `json:"checks"`
+	State       string             `json:"state,omitempty"` // relative to the collection directory
+	Errors      []string           `json:"errors,omitempty"`
+}
+
+// IngressState is the routing infrastructure behind routes: the
+// IngressControllers, their router pods, the load balancers publishing them
+// and the routes they rejected
+type IngressState struct {
+	Controllers   []IngressControllerState `json:"controllers"`
+	Pods          []RouterPodState         `json:"pods"`
+	LoadBalancers []IngressLoadBalancer    `json:"load_balancers,omitempty"`
+	Rejections    []RouteRejection         `json:"rejections,omitempty"`
+}
+
+// IngressControllerState is an IngressController and what its operator
+// reports of it
+type IngressControllerState struct {
+	Name            string `json:"name"`
+	Domain          string `json:"domain,omitempty"`
+	Strategy        string `json:"strategy,omitempty"` // endpoint publishing strategy, e.g. LoadBalancerService
+	DesiredReplicas int    `json:"desired_replicas"`
+	Available       bool   `json:"available"`
+	Degraded        bool   `json:"degraded"`
+	Message         string `json:"message,omitempty"`
+}
+
+// RouterPodState is a router pod of an IngressController
+type RouterPodState struct {
+	Name       string `json:"name"`
+	Controller string `json:"controller"`
+	Node       string `json:"node,omitempty"`
+	Ready      bool   `json:"ready"`
+	Restarts   int    `json:"restarts"`
+	Reason     string `json:"reason,omitempty"` // why the router is waiting or last terminated
+}
+
+// IngressLoadBalancer is a service publishing an IngressController and the
+// router endpoints behind it
+type IngressLoadBalancer struct {
+	Service               string   `json:"service"`
+	Controller            string   `json:"controller"`
+	Type                  string   `json:"type"`
+	Ingress               []string `json:"ingress,omitempty"` // load balancer addresses
+	ExternalTrafficPolicy string   `json:"external_traffic_policy,omitempty"`
+	ReadyEndpoints        int      `json:"ready_endpoints"`
+	NotReadyEndpoints     int      `json:"not_ready_endpoints"`
+}
+
+// RouteRejection is a route a router did not admit
+type RouteRejection struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Host      string `json:"host,omitempty"`
+	Router    string `json:"router"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// routerPodList is the part of oc get pods -o json the router state needs
+type routerPodList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName string `json:"nodeName"`
+		} `json:"spec"`
+		Status struct {
+			Phase      string `json:"phase"`
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+			ContainerStatuses []struct {
+				Name         string `json:"name"`
+				RestartCount int    `json:"restartCount"`
+				State        struct {
+					Waiting *struct {
+						Reason string `json:"reason"`
+					} `json:"waiting"`
+				} `json:"state"`
+				LastState struct {
+					Terminated *struct {
+						Reason string `json:"reason"`
+					} `json:"terminated"`
+				} `json:"lastState"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// ingressControllerList is the part of oc get ingresscontroller -o json the
+// router state needs
+type ingressControllerList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas *int   `json:"replicas"`
+			Domain   string `json:"domain"`
+		} `json:"spec"`
+		Status struct {
+			Domain                     string `json:"domain"`
+			EndpointPublishingStrategy struct {
+				Type string `json:"type"`
+			} `json:"endpointPublishingStrategy"`
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// routerServiceList is the part of oc get services -o json the load
+// balancers need
+type routerServiceList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Type                  string `json:"type"`
+			ExternalTrafficPolicy string `json:"externalTrafficPolicy"`
+		} `json:"spec"`
+		Status struct {
+			LoadBalancer struct {
+				Ingress []struct {
+					IP       string `json:"ip"`
+					Hostname string `json:"hostname"`
+				} `json:"ingress"`
+			} `json:"loadBalancer"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// endpointsList is the part of oc get endpoints -o json the load balancers
+// need
+type endpointsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Subsets []struct {
+			Addresses         []json.RawMessage `json:"addresses"`
+			NotReadyAddresses []json.RawMessage `json:"notReadyAddresses"`
+		} `json:"subsets"`
+	} `json:"items"`
+}
+
+// routeStatusList is the part of oc get routes -o json the rejections need
+type routeStatusList struct {
+	Items []struct {
+		Metadata struct {
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Host string `json:"host"`
+		} `json:"spec"`
+		Status struct {
+			Ingress []struct {
+				RouterName string `json:"routerName"`
+				Host       string `json:"host"`
+				Conditions []struct {
+					Type    string `json:"type"`
+					Status  string `json:"status"`
+					Reason  string `json:"reason"`
+					Message string `json:"message"`
+				} `json:"conditions"`
+			} `json:"ingress"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// parseIngressControllers reads every IngressController, or only controller
+func parseIngressControllers(data []byte, controller string) ([]IngressControllerState, error) {
+	var list ingressControllerList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var controllers []IngressControllerState
+	for _, item := range list.Items {
+		if controller != "" && item.Metadata.Name != controller {
+			continue
+		}
+		state := IngressControllerState{
+			Name:            item.Metadata.Name,
+			Domain:          firstNonEmpty(item.Status.Domain, item.Spec.Domain),
+			Strategy:        item.Status.EndpointPublishingStrategy.Type,
+			DesiredReplicas: 2,
+			Available:       true,
+		}
+		if item.Spec.Replicas != nil {
+			state.DesiredReplicas = *item.Spec.Replicas
+		}
+		for _, condition := range item.Status.Conditions {
+			switch {
+			case condition.Type == "Available" && condition.Status == "False":
+				state.Available = false
+				state.Message = firstNonEmpty(state.Message, condition.Message)
+			case condition.Type == "Degraded" && condition.Status == "True":
+				state.Degraded = true
+				state.Message = firstNonEmpty(state.Message, condition.Message)
+			}
+		}
+		controllers = append(controllers, state)
+	}
+	sort.Slice(controllers, func(i, j int) bool { return controllers[i].Name < controllers[j].Name })
+	return controllers, nil
+}
+
+// parseRouterPods reads the router pods of every IngressController, or only
+// of controller
+func parseRouterPods(data []byte, controller string) ([]RouterPodState, error) {
+	var list routerPodList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var pods []RouterPodState
+	for _, item := range list.Items {
+		owner := item.Metadata.Labels[routerControllerLabel]
+		if owner == "" || (controller != "" && owner != controller) || item.Status.Phase == "Succeeded" || item.Status.Phase == "Failed" {
+			continue
+		}
+		pod := RouterPodState{Name: item.Metadata.Name, Controller: owner, Node: item.Spec.NodeName}
+		for _, condition := range item.Status.Conditions {
+			if condition.Type == "Ready" {
+				pod.Ready = condition.Status == "True"
+			}
+		}
+		for _, status := range item.Status.ContainerStatuses {
+			pod.Restarts += status.RestartCount
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				pod.Reason = status.State.Waiting.Reason
+			} else if status.LastState.Terminated != nil && pod.Reason == "" {
+				pod.Reason = status.LastState.Terminated.Reason
+			}
+		}
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+// parseIngressLoadBalancers reads the services publishing every
+// IngressController, or only controller, with their router endpoints. The
+// router-internal services only serve metrics and are left out.
+func parseIngressLoadBalancers(services, endpoints []byte, controller string) ([]IngressLoadBalancer, error) {
+	var serviceList routerServiceList
+	if err := json.Unmarshal(services, &serviceList); err != nil {
+		return nil, err
+	}
+	var endpointList endpointsList
+	if err := json.Unmarshal(endpoints, &endpointList); err != nil {
+		return nil, err
+	}
+	ready := make(map[string][2]int, len(endpointList.Items))
+	for _, item := range endpointList.Items {
+		var counts [2]int
+		for _, subset := range item.Subsets {
+			counts[0] += len(subset.Addresses)
+			counts[1] += len(subset.NotReadyAddresses)
+		}
+		ready[item.Metadata.Name] = counts
+	}
+	var balancers []IngressLoadBalancer
+	for _, item := range serviceList.Items {
+		owner := item.Metadata.Labels[routerServiceLabel]
+		if owner == "" || (controller != "" && owner != controller) || strings.HasPrefix(item.Metadata.Name, "router-internal-") {
+			continue
+		}
+		balancer := IngressLoadBalancer{
+			Service:               item.Metadata.Name,
+			Controller:            owner,
+			Type:                  item.Spec.Type,
+			ExternalTrafficPolicy: item.Spec.ExternalTrafficPolicy,
+			ReadyEndpoints:        ready[item.Metadata.Name][0],
+			NotReadyEndpoints:     ready[item.Metadata.Name][1],
+		}
+		for _, ingress := range item.Status.LoadBalancer.Ingress {
+			if address := firstNonEmpty(ingress.Hostname, ingress.IP); address != "" {
+				balancer.Ingress = append(balancer.Ingress, address)
+			}
+		}
+		balancers = append(balancers, balancer)
+	}
+	sort.Slice(balancers, func(i, j int) bool { return balancers[i].Service < balancers[j].Service })
+	return balancers, nil
+}
+
+// parseRouteRejections reads the routes every router, or only controller,
+// did not admit
+func parseRouteRejections(data []byte, controller string) ([]RouteRejection, error) {
+	var list routeStatusList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var rejections []RouteRejection
+	for _, item := range list.Items {
+		for _, ingress := range item.Status.Ingress {
+			if controller != "" && ingress.RouterName != controller {
+				continue
+			}
+			for _, condition := range ingress.Conditions {
+				if condition.Type != "Admitted" || condition.Status != "False" {
+					continue
+				}
+				rejections = append(rejections, RouteRejection{
+					Namespace: item.Metadata.Namespace,
+					Name:      item.Metadata.Name,
+					Host:      firstNonEmpty(ingress.Host, item.Spec.Host),
+					Router:    ingress.RouterName,
+					Reason:    condition.Reason,
+					Message:   condition.Message,
+				})
+			}
+		}
+	}
+	return rejections, nil
+}
+
+// CollectIngress gathers the router diagnostics of openshift-ingress: the
+// logs of every router container since "since" (default 1h) including the
+// previous instance of restarted ones, HAProxy's process information and
+// backend states read from its admin socket, and the state of the
+// IngressControllers, router pods, load balancer services and rejected
+// routes. The "controller" filter limits the collection to one
+// IngressController. Parts that fail are recorded in the manifest; the
+// collection only fails when the router pods cannot be listed.
+func (dc *DiagnosticCollector) CollectIngress(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
+	start := time.Now()
+	result := &CollectionResult{
+		Type:     "ingress",
+		Metadata: make(map[string]string),
+	}
+	fail := func(err error) (*CollectionResult, error) {
+		result.Status = "failed"
+		result.ErrorMsg = err.Error()
+		result.Duration = time.Since(start)
+		return result, err
+	}
+
+	since := opts.Filters["since"]
+	if since == "" {
+		since = defaultIngressLogSince
+	}
+	if _, err := time.ParseDuration(since); err != nil {
+		return nil, fmt.Errorf("invalid since duration %q: %v", since, err)
+	}
+	controller := opts.Filters["controller"]
+	if controller != "" && !nodeNamePattern.MatchString(controller) {
+		return nil, fmt.Errorf("invalid ingress controller name %q", controller)
+	}
+	manifest := &IngressManifest{Controller: controller, Since: since, CollectedAt: start.UTC()}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("ingress-%d", start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fail(err)
+	}
+	result.FilePath = outputDir
+
+	var listing bytes.Buffer
+	if err := dc.runOC(ctx, nil, &listing, "get", "pods", "-n", IngressNamespace, "-o", "json"); err != nil {
+		return fail(fmt.Errorf("listing pods in %s: %v", IngressNamespace, err))
+	}
+	routerPods, err := parseRouterPods(listing.Bytes(), controller)
+	if err != nil {
+		return fail(fmt.Errorf("parsing the pod list: %v", err))
+	}
+	var pods podList
+	if err := json.Unmarshal(listing.Bytes(), &pods); err != nil {
+		return fail(fmt.Errorf("parsing the pod list: %v", err))
+	}
+	routers := make(map[string]bool, len(routerPods))
+	for _, pod := range routerPods {
+		routers[pod.Name] = true
+	}
+	selected := pods.Items[:0]
+	for _, pod := range pods.Items {
+		if routers[pod.Metadata.Name] {
+			selected = append(selected, pod)
+		}
+	}
+	pods.Items = selected
+	if len(pods.Items) == 0 {
+		if controller != "" {
+			return fail(fmt.Errorf("no router pods of ingress controller %s in %s", controller, IngressNamespace))
+		}
+		return fail(fmt.Errorf("no router pods in %s", IngressNamespace))
+	}
+	for _, pod := range pods.Items {
+		entry := CollectedPod{Name: pod.Metadata.Name, Node: pod.Spec.NodeName}
+		for _, container := range pod.Spec.Containers {
+			entry.Containers = append(entry.Containers, container.Name)
+		}
+		manifest.Pods = append(manifest.Pods, entry)
+	}
+
+	manifest.Logs = dc.collectContainerLogs(ctx, outputDir, IngressNamespace, since, logJobs(&pods, true), DefaultLogWorkers)
+	failed := 0
+	var size int64
+	for _, entry := range manifest.Logs {
+		size += entry.Bytes
+		if entry.Error != "" {
+			failed++
+		}
+	}
+
+	for _, pod := range manifest.Pods {
+		for _, check := range routerChecks {
+			if !hasString(pod.Containers, check.container) {
+				continue
+			}
+			output := dc.runContainerCheck(ctx, outputDir, IngressNamespace, pod, check)
+			if output.Error != "" {
+				failed++
+			}
+			manifest.Checks = append(manifest.Checks, output)
+		}
+	}
+
+	state := IngressState{Pods: routerPods}
+	read := func(what string, args ...string) []byte {
+		var out bytes.Buffer
+		if err := dc.runOC(ctx, nil, &out, args...); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("reading %s: %v", what, err))
+			return nil
+		}
+		return out.Bytes()
+	}
+	if data := read("the ingress controllers", "get", "ingresscontrollers.operator.openshift.io", "-n", "openshift-ingress-operator", "-o", "json"); data != nil {
+		if state.Controllers, err = parseIngressControllers(data, controller); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the ingress controllers: %v", err))
+		}
+	}
+	services := read("the router services", "get", "services", "-n", IngressNamespace, "-o", "json")
+	endpoints := read("the router endpoints", "get", "endpoints", "-n", IngressNamespace, "-o", "json")
+	if services != nil && endpoints != nil {
+		if state.LoadBalancers, err = parseIngressLoadBalancers(services, endpoints, controller); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the router services: %v", err))
+		}
+	}
+	if data := read("the routes", "get", "routes", "-A", "-o", "json"); data != nil {
+		if state.Rejections, err = parseRouteRejections(data, controller); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the routes: %v", err))
+		}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(outputDir, IngressStateFile), data, 0644)
+	}
+	if err != nil {
+		manifest.Errors = append(manifest.Errors, fmt.Sprintf("writing the ingress state: %v", err))
+	} else {
+		manifest.State = IngressStateFile
+		size += int64(len(data))
+	}
+
+	manifest.Duration = time.Since(start).Round(time.Millisecond).String()
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, IngressManifestFile), data, 0644); err != nil {
+		return fail(err)
+	}
+	if dirSize, err := dc.getDirSize(outputDir); err == nil {
+		size = dirSize
+	}
+
+	result.Duration = time.Since(start)
+	result.Size = size
+	result.Metadata["controller"] = controller
+	result.Metadata["since"] = since
+	result.Metadata["manifest"] = filepath.Join(outputDir, IngressManifestFile)
+	result.Metadata["pods"] = strconv.Itoa(len(manifest.Pods))
+	result.Metadata["logs"] = strconv.Itoa(len(manifest.Logs))
+	result.Metadata["checks"] = strconv.Itoa(len(manifest.Checks))
+	result.Metadata["failed"] = strconv.Itoa(failed + len(manifest.Errors))
+	if ctx.Err() != nil {
+		return fail(fmt.Errorf("ingress collection interrupted: %v", ctx.Err()))
+	}
+
+	result.Status = "completed"
+	result.Summary = fmt.Sprintf("Collected %d logs and %d checks from %d router pods (%.2f MB, %d failed) in %s",
+		len(manifest.Logs), len(manifest.Checks), len(manifest.Pods), float64(size)/(1024*1024), failed+len(manifest.Errors), outputDir)
+	dc.finishCollection("", result, opts)
+	dc.logger.Infof("Ingress collection completed: %s", result.Summary)
+	return result, nil
+}
+
+// routerSignatures are the failures the OpenShift router and its HAProxy
+// log most often
+var routerSignatures = []LogSignature{
+	{
+		Name:        "reload-failed",
+		Pattern:     regexp.MustCompile(`(?i)error reloading router|router reload failed|reload(ing)? haproxy failed`),
+		Severity:    "critical",
+		Title:       "HAProxy reloads fail",
+		Description: "The router could not load its new configuration, so route changes since the last good reload are not served; a router that keeps failing to reload turns unready",
+		Resolution:  "Read the [ALERT] lines logged with the failure for the route they name, usually a bad annotation or certificate, and fix or delete that route",
+	},
+	{
+		Name:        "config-alert",
+		Pattern:     regexp.MustCompile(`\[ALERT\]|parsing \[/var/lib/haproxy/conf/haproxy\.config:\d+\]`),
+		Severity:    "critical",
+		Title:       "HAProxy rejects its configuration",
+		Description: "HAProxy found errors in the configuration the router generated from the routes",
+		Resolution:  "Find the backend or certificate the alert names in /var/lib/haproxy/conf/haproxy.config of the router and fix the route it comes from",
+	},
+	{
+		Name:        "bind-failure",
+		Pattern:     regexp.MustCompile(`(?i)cannot bind socket|address already in use`),
+		Severity:    "critical",
+		Title:       "Router cannot bind its ports",
+		Description: "Another process holds ports 80, 443 or 1936 on the node, so the router cannot accept traffic; this happens with the HostNetwork strategy",
+		Resolution:  "Find the process holding the port: oc debug node/<node> -- chroot /host ss -ltnp, and move it or the router to another node",
+	},
+	{
+		Name:        "certificate-load",
+		Pattern:     regexp.MustCompile(`(?i)unable to load (SSL )?(certificate|private key)|error (loading|writing) (certificate|cert)|failed to write (cert|certificate)`),
+		Severity:    "warning",
+		Title:       "Router cannot load route certificates",
+		Description: "Some route certificates or keys are invalid, so their hosts are served with the default certificate or not at all",
+		Resolution:  "Check the certificate, key and CA of the routes the lines name with openssl x509 -noout -text, and that the key matches the certificate",
+	},
+	{
+		Name:        "api-watch",
+		Pattern:     regexp.MustCompile(`(?i)(failed to (list|watch)|watch of)[^\n]*(route|endpoint|service|namespace)`),
+		Severity:    "warning",
+		Title:       "Router lost its watch on the API server",
+		Description: "The router could not read routes or endpoints, so new routes and endpoint changes are not applied until the watch recovers",
+		Resolution:  "Check the API server's health with probe_control_plane and the router service account's permissions",
+	},
+	{
+		Name:        "connection-limit",
+		Pattern:     regexp.MustCompile(`(?i)too many open files|reached (the )?(process|system) (FD|memory) limit|maxconn[^\n]*reached`),
+		Severity:    "warning",
+		Title:       "Router ran into its connection limits",
+		Description: "HAProxy reached its file descriptor or connection limit, so new connections queue or are refused",
+		Resolution:  "Raise spec.tuningOptions.maxConnections of the IngressController or add router replicas",
+	},
+}
+
+// ParseHAProxyInfo reads the Name: value lines of HAProxy's show info
+func ParseHAProxyInfo(output string) map[string]string {
+	info := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			info[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return info
+}
+
+// haproxyDownRoutes returns the routes whose backend HAProxy's show stat
+// reports DOWN, as namespace/name. The router names route backends
+// be_<kind>:<namespace>:<name>.
+func haproxyDownRoutes(output string) []string {
+	var routes []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 18 || fields[1] != "BACKEND" || !strings.HasPrefix(fields[17], "DOWN") {
+			continue
+		}
+		parts := strings.Split(fields[0], ":")
+		if len(parts) != 3 || !strings.HasPrefix(parts[0], "be_") {
+			continue
+		}
+		routes = append(routes, parts[1]+"/"+parts[2])
+	}
+	return routes
+}
+
+// RouterInfrastructureIssues reports what in the routing infrastructure
+// keeps routes from being served: unavailable or degraded IngressControllers,
+// router pods that are missing, unready or restarting, load balancers
+// without an address or without ready routers behind them, and the routes
+// the routers rejected
+func RouterInfrastructureIssues(state IngressState) []Issue {
+	var issues []Issue
+	for _, controller := range state.Controllers {
+		location := "ingresscontroller/" + controller.Name
+		metadata := map[string]string{"controller": controller.Name}
+		if !controller.Available {
+			issues = append(issues, Issue{
+				Severity:    "critical",
+				Category:    "availability",
+				Title:       fmt.Sprintf("Ingress controller %s is not available", controller.Name),
+				Description: firstNonEmpty(controller.Message, "The ingress operator reports the controller's routers are not serving"),
+				Location:    location,
+				Resolution:  "Check the ingress operator: oc get clusteroperator ingress and oc logs -n openshift-ingress-operator deployment/ingress-operator",
+				Metadata:    metadata,
+			})
+		} else if controller.Degraded {
+			issues = append(issues, Issue{
+				Severity:    "warning",
+				Category:    "availability",
+				Title:       fmt.Sprintf("Ingress controller %s is degraded", controller.Name),
+				Description: firstNonEmpty(controller.Message, "The ingress operator reports the controller as degraded"),
+				Location:    location,
+				Resolution:  fmt.Sprintf("oc describe ingresscontroller %s -n openshift-ingress-operator shows which condition degrades it", controller.Name),
+				Metadata:    metadata,
+			})
+		}
+
+		var pods, notReady, restarting []string
+		for _, pod := range state.Pods {
+			if pod.Controller != controller.Name {
+				continue
+			}
+			pods = append(pods, pod.Name)
+			if !pod.Ready {
+				line := fmt.Sprintf("%s on %s", pod.Name, valueOrUnknown(pod.Node))
+				if pod.Reason != "" {
+					line += ": " + pod.Reason
+				}
+				notReady = append(notReady, line)
+			}
+			if pod.Restarts >= routerRestartWarning {
+				restarting = append(restarting, fmt.Sprintf("%s: %d restarts (%s)", pod.Name, pod.Restarts, valueOrUnknown(pod.Reason)))
+			}
+		}
+		switch {
+		case len(pods) == 0 && controller.DesiredReplicas > 0:
+			issues = append(issues, Issue{
+				Severity:    "critical",
+				Category:    "availability",
+				Title:       fmt.Sprintf("Ingress controller %s has no router pods", controller.Name),
+				Description: fmt.Sprintf("%d routers are wanted but none runs, so none of the controller's routes are served", controller.DesiredReplicas),
+				Location:    location,
+				Resolution:  fmt.Sprintf("oc describe deployment router-%s -n %s shows why its pods are not created or scheduled", controller.Name, IngressNamespace),
+				Metadata:    metadata,
+			})
+		case len(pods) > 0 && len(notReady) == len(pods):
+			issues = append(issues, Issue{
+				Severity:    "critical",
+				Category:    "availability",
+				Title:       fmt.Sprintf("No router of %s is ready", controller.Name),
+				Description: "Every router pod fails its readiness check, so the load balancer has nowhere to send the controller's traffic",
+				Location:    location,
+				Evidence:    capEvidence(notReady, maxSignatureEvidence),
+				Resolution:  "Check the routers' logs for reload errors: collect_ingress_diagnostics gathers them with HAProxy's state",
+				Metadata:    metadata,
+			})
+		case len(notReady) > 0:
+			issues = append(issues, Issue{
+				Severity:    "warning",
+				Category:    "availability",
+				Title:       fmt.Sprintf("%d of %d routers of %s are not ready", len(notReady), len(pods), controller.Name),
+				Description: "The remaining routers carry all the traffic; connections to an unready router's node fail until the load balancer's health checks drop it",
+				Location:    location,
+				Evidence:    capEvidence(notReady, maxSignatureEvidence),
+				Resolution:  "Check the unready routers' logs and events: oc describe pod <router> -n " + IngressNamespace,
+				Metadata:    metadata,
+			})
+		}
+		if len(restarting) > 0 {
+			issues = append(issues, Issue{
+				Severity:    "warning",
+				Category:    "stability",
+				Title:       fmt.Sprintf("Routers of %s are restarting", controller.Name),
+				Description: "Each restart drops the connections the router holds and leaves the node without a router until it is ready again",
+				Location:    location,
+				Evidence:    capEvidence(restarting, maxSignatureEvidence),
+				Resolution:  "Check the previous instance's log (oc logs --previous) and OOMKilled reasons; raise the router's resources if it runs out of memory",
+				Metadata:    metadata,
+			})
+		}
+	}
+
+	for _, balancer := range state.LoadBalancers {
+		location := fmt.Sprintf("service/%s (%s)", balancer.Service, IngressNamespace)
+		metadata := map[string]string{"controller": balancer.Controller, "service": balancer.Service}
+		if balancer.Type == "LoadBalancer" && len(balancer.Ingress) == 0 {
+			issues = append(issues, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("Load balancer of %s has no address", balancer.Controller),
+				Description: fmt.Sprintf("The cloud provider has not provisioned the load balancer of service %s, so the controller's domain does not reach the routers", balancer.Service),
+				Location:    location,
+				Resolution:  fmt.Sprintf("oc describe service %s -n %s shows the cloud provider's events; check the account's load balancer quota and the cloud credentials", balancer.Service, IngressNamespace),
+				Metadata:    metadata,
+			})
+		}
+		if balancer.ReadyEndpoints == 0 {
+			issues = append(issues, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("Service %s has no ready router endpoints", balancer.Service),
+				Description: fmt.Sprintf("The load balancer's health checks fail on every node (%d routers not ready), so all traffic to the controller's domain is dropped", balancer.NotReadyEndpoints),
+				Location:    location,
+				Resolution:  "Get a router ready again; check the router pods' readiness and logs",
+				Metadata:    metadata,
+			})
+		}
+	}
+
+	if len(state.Rejections) > 0 {
+		byReason := make(map[string][]string)
+		for _, rejection := range state.Rejections {
+			line := fmt.Sprintf("%s/%s (%s) by %s", rejection.Namespace, rejection.Name, valueOrUnknown(rejection.Host), rejection.Router)
+			if rejection.Message != "" {
+				line += ": " + rejection.Message
+			}
+			reason := valueOrUnknown(rejection.Reason)
+			byReason[reason] = append(byReason[reason], line)
+		}
+		for _, reason := range sortedKeys(byReason) {
+			routes := byReason[reason]
+			issue := Issue{
+				Severity:    "warning",
+				Category:    "configuration",
+				Title:       fmt.Sprintf("%d routes rejected by routers (%s)", len(routes), reason),
+				Description: "The routers do not serve these routes",
+				Location:    "routes",
+				Evidence:    capEvidence(routes, maxShardEvidence),
+				Resolution:  "Fix each route as its message says, or analyze it with openshift_route_analyze",
+				Metadata:    map[string]string{"reason": reason, "routes": strconv.Itoa(len(routes))},
+			}
+			switch reason {
+			case "HostAlreadyClaimed":
+				issue.Title = fmt.Sprintf("%d routes lose their host to older routes", len(routes))
+				issue.Description = "Another route in a different namespace claimed the same host and path first; the router only serves the oldest claim"
+				issue.Resolution = "Give the routes unique hosts, or set the IngressController's routeAdmission.namespaceOwnership to InterNamespaceAllowed if sharing hosts across namespaces is intended"
+			case "ExtendedValidationFailed":
+				issue.Title = fmt.Sprintf("%d routes fail certificate validation", len(routes))
+				issue.Description = "The routes' certificate, key or CA is invalid or does not match, so the router refuses them"
+				issue.Resolution = "Check each route's certificate chain and key with openssl, and that the certificate covers the route host"
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// AnalyzeIngress reports the failures an ingress collection shows: known
+// failure signatures in the router logs, routers near their connection
+// limit, routes without available servers in HAProxy, and the
+// infrastructure problems RouterInfrastructureIssues finds in the collected
+// state
+func (ae *AnalysisEngine) AnalyzeIngress(ctx context.Context, path string) (*AnalysisResult, error) {
+	if IsBundle(path) {
+		return analyzeBundle(path, bundleDir, func(dir string) (*AnalysisResult, error) {
+			return ae.AnalyzeIngress(ctx, dir)
+		})
+	}
+	result := &AnalysisResult{
+		Type:      "ingress-analysis",
+		FilePath:  path,
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: time.Now(),
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, IngressManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("no ingress collection in %s: %v", path, err)
+	}
+	var manifest IngressManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", IngressManifestFile, err)
+	}
+	ae.logger.Infof("Starting ingress analysis: %s", path)
+
+	nodeOf := make(map[string]string, len(manifest.Pods))
+	for _, pod := range manifest.Pods {
+		nodeOf[pod.Name] = pod.Node
+	}
+	podLabel := func(pod string) string {
+		if node := nodeOf[pod]; node != "" {
+			return fmt.Sprintf("%s (%s)", pod, node)
+		}
+		return pod
+	}
+
+	hits, err := ae.scanLogSignatures(ctx, path, manifest.Logs, routerSignatures, podLabel)
+	if err != nil {
+		return nil, err
+	}
+	matches := ae.addSignatureIssues(result, routerSignatures, hits, "network")
+
+	downRoutes := make(map[string][]string)
+	for _, check := range manifest.Checks {
+		ae.analyzeRouterCheck(path, check, podLabel(check.Pod), downRoutes, result)
+	}
+	if len(downRoutes) > 0 {
+		var evidence []string
+		for _, route := range sortedKeys(downRoutes) {
+			evidence = append(evidence, fmt.Sprintf("%s in %s", route, strings.Join(downRoutes[route], ", ")))
+		}
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("%d routes have no available servers", len(downRoutes)),
+			Description: "HAProxy marks these routes' backends DOWN, so requests to them get a 503 from the router",
+			Location:    "routes",
+			Evidence:    capEvidence(evidence, maxShardEvidence),
+			Resolution:  "Check that the routes' services have ready endpoints, and the health checks of routes with several endpoints",
+			Metadata:    map[string]string{"routes": strconv.Itoa(len(downRoutes))},
+		})
+	}
+
+	var state IngressState
+	if manifest.State != "" {
+		if data, err := os.ReadFile(filepath.Join(path, manifest.State)); err != nil {
+			ae.logger.Warnf("Failed to read %s: %v", manifest.State, err)
+		} else if err := json.Unmarshal(data, &state); err != nil {
+			ae.logger.Warnf("Invalid %s: %v", manifest.State, err)
+		}
+	}
+	for _, issue := range RouterInfrastructureIssues(state) {
+		ae.addIssue(result, issue)
+	}
+
+	failedLogs := 0
+	for _, entry := range manifest.Logs {
+		if entry.Error != "" {
+			failedLogs++
+		}
+	}
+	result.Metrics["pods"] = len(manifest.Pods)
+	result.Metrics["logs"] = len(manifest.Logs)
+	result.Metrics["logs_failed"] = failedLogs
+	result.Metrics["checks"] = len(manifest.Checks)
+	result.Metrics["signature_matches"] = matches
+	result.Metrics["controllers"] = len(state.Controllers)
+	result.Metrics["rejected_routes"] = len(state.Rejections)
+	result.Metrics["down_routes"] = len(downRoutes)
+	for _, err := range manifest.Errors {
+		result.markTruncated(err)
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+	ae.finishBudget(result)
+	ae.buildTimeline(result)
+	ae.generateSummaryAndRecommendations(result)
+	if len(result.Issues) == 0 {
+		result.Summary = fmt.Sprintf("No router failures found in %d logs and %d checks of %d router pods", len(manifest.Logs), len(manifest.Checks), len(manifest.Pods))
+	}
+
+	ae.logger.Infof("Ingress analysis completed: found %d issues", len(result.Issues))
+	return result, nil
+}
+
+// analyzeRouterCheck reports what HAProxy's process information shows, and
+// collects the routes its backend states report DOWN into downRoutes
+func (ae *AnalysisEngine) analyzeRouterCheck(path string, check CheckOutput, pod string, downRoutes map[string][]string, result *AnalysisResult) {
+	if check.Error != "" {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "collection",
+			Title:       fmt.Sprintf("Check %s failed in %s", check.Check, check.Pod),
+			Description: "The check could not run, so its part of the analysis is missing",
+			Location:    pod,
+			Evidence:    []string{check.Error},
+			Resolution:  fmt.Sprintf("Check that the %s container is running: oc get pod %s -n %s", check.Container, check.Pod, IngressNamespace),
+		})
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(path, check.File))
+	if err != nil {
+		ae.logger.Warnf("Failed to read %s: %v", check.File, err)
+		return
+	}
+
+	switch check.Check {
+	case IngressCheckInfo:
+		info := ParseHAProxyInfo(string(data))
+		current, _ := strconv.Atoi(info["CurrConns"])
+		limit, _ := strconv.Atoi(info["Maxconn"])
+		if limit > 0 && current*10 >= limit*9 {
+			ae.addIssue(result, Issue{
+				Severity:    "warning",
+				Category:    "capacity",
+				Title:       fmt.Sprintf("Router %s is near its connection limit", check.Pod),
+				Description: fmt.Sprintf("HAProxy holds %d of its %d connections; connections beyond the limit queue or are refused", current, limit),
+				Location:    pod,
+				Evidence:    []string{fmt.Sprintf("CurrConns: %d", current), fmt.Sprintf("Maxconn: %d", limit)},
+				Resolution:  "Raise spec.tuningOptions.maxConnections of the IngressController or add router replicas",
+				Metadata:    map[string]string{"check": check.Check, "node": check.Node},
+			})
+		}
+	case IngressCheckStat:
+		for _, route := range haproxyDownRoutes(string(data)) {
+			downRoutes[route] = append(downRoutes[route], check.Pod)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testRouterPods = `{"items": [
+  {"metadata": {"name": "router-default-a", "labels": {"ingresscontroller.operator.openshift.io/deployment-ingresscontroller": "default"}},
+   "spec": {"nodeName": "worker-1", "containers": [{"name": "router"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}],
+     "containerStatuses": [{"name": "router", "restartCount": 4, "lastState": {"terminated": {"reason": "OOMKilled"}}}]}},
+  {"metadata": {"name": "router-default-b", "labels": {"ingresscontroller.operator.openshift.io/deployment-ingresscontroller": "default"}},
+   "spec": {"nodeName": "worker-2", "containers": [{"name": "router"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "False"}], "containerStatuses": [{"name": "router"}]}},
+  {"metadata": {"name": "router-sharded-c", "labels": {"ingresscontroller.operator.openshift.io/deployment-ingresscontroller": "sharded"}},
+   "spec": {"nodeName": "worker-3", "containers": [{"name": "router"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}], "containerStatuses": [{"name": "router"}]}},
+  {"metadata": {"name": "router-default-old", "labels": {"ingresscontroller.operator.openshift.io/deployment-ingresscontroller": "default"}},
+   "spec": {"nodeName": "worker-1", "containers": [{"name": "router"}]},
+   "status": {"phase": "Failed"}}
+]}`
+
+const testIngressControllers = `{"items": [
+  {"metadata": {"name": "default"}, "spec": {"replicas": 2},
+   "status": {"domain": "apps.example.com", "endpointPublishingStrategy": {"type": "LoadBalancerService"},
+     "conditions": [{"type": "Available", "status": "True"}, {"type": "Degraded", "status": "False"}]}},
+  {"metadata": {"name": "sharded"}, "spec": {"replicas": 1, "domain": "internal.example.com"},
+   "status": {"conditions": [{"type": "Available", "status": "False", "message": "One or more status conditions indicate unavailable: DeploymentAvailable=False"}]}}
+]}`
+
+const testRouterServices = `{"items": [
+  {"metadata": {"name": "router-default", "labels": {"ingresscontroller.operator.openshift.io/owning-ingresscontroller": "default"}},
+   "spec": {"type": "LoadBalancer", "externalTrafficPolicy": "Local"}, "status": {"loadBalancer": {}}},
+  {"metadata": {"name": "router-internal-default", "labels": {"ingresscontroller.operator.openshift.io/owning-ingresscontroller": "default"}},
+   "spec": {"type": "ClusterIP"}, "status": {}},
+  {"metadata": {"name": "router-sharded", "labels": {"ingresscontroller.operator.openshift.io/owning-ingresscontroller": "sharded"}},
+   "spec": {"type": "LoadBalancer"}, "status": {"loadBalancer": {"ingress": [{"hostname": "lb.example.com"}]}}}
+]}`
+
+const testRouterEndpoints = `{"items": [
+  {"metadata": {"name": "router-default"}, "subsets": [{"addresses": [{"ip": "10.0.0.5"}], "notReadyAddresses": [{"ip": "10.0.0.6"}]}]},
+  {"metadata": {"name": "router-sharded"}, "subsets": [{"addresses": [{"ip": "10.0.0.7"}]}]}
+]}`
+
+const testRouteStatuses = `{"items": [
+  {"metadata": {"namespace": "shop", "name": "frontend"}, "spec": {"host": "shop.apps.example.com"},
+   "status": {"ingress": [{"routerName": "default", "conditions": [{"type": "Admitted", "status": "True"}]}]}},
+  {"metadata": {"namespace": "dup", "name": "frontend"}, "spec": {"host": "shop.apps.example.com"},
+   "status": {"ingress": [{"routerName": "default", "conditions": [{"type": "Admitted", "status": "False", "reason": "HostAlreadyClaimed", "message": "route frontend already exposes shop.apps.example.com and is older"}]}]}}
+]}`
+
+func TestCollectAndAnalyzeIngress(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+
+	downBackend := "be_http:shop:frontend,BACKEND," + strings.Repeat("0,", 15) + "DOWN,1"
+	dc.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		call := strings.Join(args, " ")
+		output := ""
+		switch {
+		case strings.HasPrefix(call, "get pods"):
+			output = testRouterPods
+		case strings.HasPrefix(call, "get ingresscontrollers"):
+			output = testIngressControllers
+		case strings.HasPrefix(call, "get services"):
+			output = testRouterServices
+		case strings.HasPrefix(call, "get endpoints"):
+			output = testRouterEndpoints
+		case strings.HasPrefix(call, "get routes"):
+			output = testRouteStatuses
+		case strings.HasPrefix(call, "logs router-default-a -c router") && strings.Contains(call, "--previous=true"):
+			output = "2026-10-16T08:40:00.000000000Z [WARNING] 288/084000 (31) : accept4(): Too many open files"
+		case strings.HasPrefix(call, "logs router-default-a -c router"):
+			output = "2026-10-16T09:00:00.000000000Z E1016 09:00:00.000000 1 limiter.go:165] error reloading router: exit status 1\n" +
+				"2026-10-16T09:00:00.000000000Z [ALERT] 288/090000 (412) : parsing [/var/lib/haproxy/conf/haproxy.config:120] : 'bind' : unable to load SSL certificate"
+		case strings.HasPrefix(call, "logs"):
+			output = "2026-10-16T08:00:00.000000000Z I1016 08:00:00.000000 1 router.go:618] router reloaded"
+		case strings.Contains(call, "show info"):
+			output = "Name: HAProxy\nMaxconn: 1000\nCurrConns: 10"
+			if strings.Contains(call, "router-default-a") {
+				output = "Name: HAProxy\nMaxconn: 1000\nCurrConns: 950"
+			}
+		case strings.Contains(call, "show stat"):
+			output = "# pxname,svname,...\n"
+			if strings.Contains(call, "router-default") {
+				output += downBackend
+			}
+		default:
+			return exec.CommandContext(ctx, "sh", "-c", "echo 'unexpected call' >&2; exit 1")
+		}
+		return exec.CommandContext(ctx, "sh", "-c", "cat <<'EOF'\n"+output+"\nEOF")
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "ingress")
+	result, err := dc.CollectIngress(context.Background(), &CollectionOptions{OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("CollectIngress() error = %v", err)
+	}
+	if result.Metadata["pods"] != "3" || result.Metadata["logs"] != "4" || result.Metadata["checks"] != "6" || result.Metadata["failed"] != "0" {
+		t.Errorf("CollectIngress() metadata = %v, expected 3 pods, 4 logs and 6 checks", result.Metadata)
+	}
+
+	analysis, err := newTestAnalysisEngine().AnalyzeIngress(context.Background(), outputDir)
+	if err != nil {
+		t.Fatalf("AnalyzeIngress() error = %v", err)
+	}
+	if analysis.Metrics["signature_matches"] != 3 || analysis.Metrics["controllers"] != 2 || analysis.Metrics["rejected_routes"] != 1 || analysis.Metrics["down_routes"] != 1 {
+		t.Errorf("AnalyzeIngress() metrics = %v", analysis.Metrics)
+	}
+	issues := make(map[string]Issue)
+	for _, issue := range analysis.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, severity := range map[string]string{
+		"HAProxy reloads fail":                                 "critical",
+		"HAProxy rejects its configuration":                    "critical",
+		"Router ran into its connection limits":                "warning",
+		"Router router-default-a is near its connection limit": "warning",
+		"1 routes have no available servers":                   "warning",
+		"Ingress controller sharded is not available":          "critical",
+		"1 of 2 routers of default are not ready":              "warning",
+		"Routers of default are restarting":                    "warning",
+		"Load balancer of default has no address":              "critical",
+		"1 routes lose their host to older routes":             "warning",
+	} {
+		if issues[title].Severity != severity {
+			t.Errorf("AnalyzeIngress() issue %q = %+v, expected severity %s", title, issues[title], severity)
+		}
+	}
+	if len(analysis.Issues) != 10 {
+		t.Errorf("AnalyzeIngress() issues = %+v, expected 10", analysis.Issues)
+	}
+	if down := issues["1 routes have no available servers"]; len(down.Evidence) != 1 || down.Evidence[0] != "shop/frontend in router-default-a, router-default-b" {
+		t.Errorf("down routes issue = %+v", down)
+	}
+	if restarting := issues["Routers of default are restarting"]; len(restarting.Evidence) != 1 || restarting.Evidence[0] != "router-default-a: 4 restarts (OOMKilled)" {
+		t.Errorf("restarting routers issue = %+v", restarting)
+	}
+	if analysis.Issues[0].Severity != "critical" {
+		t.Errorf("AnalyzeIngress() issues not sorted: %+v", analysis.Issues)
+	}
+
+	pods, err := parseRouterPods([]byte(testRouterPods), "sharded")
+	if err != nil || len(pods) != 1 || pods[0].Name != "router-sharded-c" || !pods[0].Ready {
+		t.Errorf("parseRouterPods(sharded) = %+v, %v", pods, err)
+	}
+}
+
+func TestRouterInfrastructureIssues(t *testing.T) {
+	healthy := IngressState{
+		Controllers:   []IngressControllerState{{Name: "default", DesiredReplicas: 2, Available: true}},
+		Pods:          []RouterPodState{{Name: "router-default-a", Controller: "default", Ready: true}, {Name: "router-default-b", Controller: "default", Ready: true, Restarts: 1}},
+		LoadBalancers: []IngressLoadBalancer{{Service: "router-default", Controller: "default", Type: "LoadBalancer", Ingress: []string{"203.0.113.10"}, ReadyEndpoints: 2}},
+	}
+	if issues := RouterInfrastructureIssues(healthy); len(issues) != 0 {
+		t.Errorf("healthy routers: issues %+v", issues)
+	}
+
+	down := IngressState{
+		Controllers:   []IngressControllerState{{Name: "default", DesiredReplicas: 2, Available: true, Degraded: true}, {Name: "internal", DesiredReplicas: 1, Available: true}},
+		Pods:          []RouterPodState{{Name: "router-default-a", Controller: "default", Node: "worker-1", Reason: "CrashLoopBackOff"}},
+		LoadBalancers: []IngressLoadBalancer{{Service: "router-default", Controller: "default", Type: "LoadBalancer", Ingress: []string{"203.0.113.10"}, NotReadyEndpoints: 1}},
+		Rejections:    []RouteRejection{{Namespace: "shop", Name: "api", Router: "default", Reason: "ExtendedValidationFailed"}},
+	}
+	var titles []string
+	for _, issue := range RouterInfrastructureIssues(down) {
+		titles = append(titles, issue.Title)
+	}
+	expected := []string{
+		"Ingress controller default is degraded",
+		"No router of default is ready",
+		"Ingress controller internal has no router pods",
+		"Service router-default has no ready router endpoints",
+		"1 routes fail certificate validation",
+	}
+	if strings.Join(titles, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("RouterInfrastructureIssues() = %q, expected %q", titles, expected)
+	}
+}
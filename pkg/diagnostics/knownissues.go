@@ -0,0 +1,279 @@
+package diagnostics
+
+import (
+	_ "embed"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Where a known issue signature is looked for
+const (
+	KnownIssueLogs   = "logs"   // log lines, the default
+	KnownIssueEvents = "events" // Warning event messages of a must-gather
+)
+
+// Kinds of known issue references
+const (
+	KnownIssueKCS    = "kcs"    // Red Hat Knowledgebase (KCS) article, the default
+	KnownIssueBug    = "bug"    // bug tracker entry, e.g. OCPBUGS-1234
+	KnownIssueSearch = "search" // Knowledgebase search for the fingerprint, until an article is pinned
+)
+
+// knownIssueCategory is the category of issues raised by a signature no
+// other pattern covers
+const knownIssueCategory = "known-issue"
+
+// builtinKnownIssuesYAML is the signature database shipped with the server
+//
+//go:embed knownissues.yaml
+var builtinKnownIssuesYAML []byte
+
+// KnownIssue maps a log or event fingerprint to a known bug or KCS article.
+// The built-in database ships with the server; rule packs add their own
+// under known_issues, replacing the entries with the same ID or the one
+// they name in replaces.
+type KnownIssue struct {
+	ID         string `json:"id"` // article number or bug ID
+	Title      string `json:"title"`
+	Kind       string `json:"kind,omitempty"`     // kcs (default), bug or search
+	Pattern    string `json:"pattern"`            // regular expression the line or event message matches
+	Source     string `json:"source,omitempty"`   // logs (default) or events
+	Severity   string `json:"severity,omitempty"` // of the issue raised when no other issue covers the match, default warning
+	URL        string `json:"url"`
+	Resolution string `json:"resolution,omitempty"`
+	Replaces   string `json:"replaces,omitempty"` // ID of the entry this one supersedes, e.g. to pin a search to an article
+
+	Origin string `json:"-"` // "built-in" or the rule pack defining it
+}
+
+// Reference points an issue at a known bug or KCS article matching it
+type Reference struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// knownIssueMatcher is a compiled signature
+type knownIssueMatcher struct {
+	KnownIssue
+	pattern *regexp.Regexp
+}
+
+// compile validates a signature
+func (k KnownIssue) compile() (*regexp.Regexp, error) {
+	if k.ID == "" || k.Title == "" {
+		return nil, fmt.Errorf("a known issue needs an id and a title")
+	}
+	if k.Pattern == "" || k.URL == "" {
+		return nil, fmt.Errorf("known issue %s needs a pattern and a url", k.ID)
+	}
+	pattern, err := regexp.Compile(k.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("known issue %s pattern: %v", k.ID, err)
+	}
+	switch k.Kind {
+	case "", KnownIssueKCS, KnownIssueBug, KnownIssueSearch:
+	default:
+		return nil, fmt.Errorf("known issue %s kind must be kcs, bug or search, not %q", k.ID, k.Kind)
+	}
+	switch k.Source {
+	case "", KnownIssueLogs, KnownIssueEvents:
+	default:
+		return nil, fmt.Errorf("known issue %s source must be logs or events, not %q", k.ID, k.Source)
+	}
+	switch k.Severity {
+	case "", "critical", "warning", "info":
+	default:
+		return nil, fmt.Errorf("known issue %s severity must be critical, warning or info, not %q", k.ID, k.Severity)
+	}
+	return pattern, nil
+}
+
+// Reference returns the reference attached to matching issues
+func (k KnownIssue) Reference() Reference {
+	kind := k.Kind
+	if kind == "" {
+		kind = KnownIssueKCS
+	}
+	return Reference{ID: k.ID, Kind: kind, Title: k.Title, URL: k.URL}
+}
+
+// issue is raised when a signature matches lines no other pattern covers
+func (k KnownIssue) issue() Issue {
+	severity := k.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+	resolution := k.Resolution
+	if resolution == "" {
+		resolution = "See " + k.URL
+	}
+	return Issue{
+		Severity:    severity,
+		Category:    knownIssueCategory,
+		Title:       "Known issue: " + k.Title,
+		Description: fmt.Sprintf("Matches the signature of %s", FormatReference(k.Reference())),
+		Resolution:  resolution,
+		References:  []Reference{k.Reference()},
+	}
+}
+
+// FormatReference renders where a reference points, e.g. "KCS 1234"
+func FormatReference(ref Reference) string {
+	switch ref.Kind {
+	case KnownIssueBug:
+		return "bug " + ref.ID
+	case KnownIssueSearch:
+		return "Knowledgebase search " + ref.ID
+	}
+	return "KCS " + ref.ID
+}
+
+// validateKnownIssues checks the signatures of a database or rule pack
+func validateKnownIssues(issues []KnownIssue) error {
+	ids := make(map[string]bool)
+	for i, known := range issues {
+		if _, err := known.compile(); err != nil {
+			return fmt.Errorf("known issue %d: %v", i+1, err)
+		}
+		if ids[known.ID] {
+			return fmt.Errorf("known issue %s is defined twice", known.ID)
+		}
+		ids[known.ID] = true
+	}
+	return nil
+}
+
+// BuiltinKnownIssues returns the signature database shipped with the server
+var BuiltinKnownIssues = sync.OnceValue(func() []KnownIssue {
+	var db struct {
+		KnownIssues []KnownIssue `json:"known_issues"`
+	}
+	if err := yaml.Unmarshal(builtinKnownIssuesYAML, &db); err != nil {
+		panic(fmt.Sprintf("invalid built-in known issues: %v", err))
+	}
+	if err := validateKnownIssues(db.KnownIssues); err != nil {
+		panic(fmt.Sprintf("invalid built-in known issues: %v", err))
+	}
+	for i := range db.KnownIssues {
+		db.KnownIssues[i].Origin = "built-in"
+	}
+	return db.KnownIssues
+})
+
+// KnownIssues returns the built-in signatures with those of the rule packs
+// applied: a pack's entry replaces the one with the same ID or the one it
+// names in replaces
+func (ae *AnalysisEngine) KnownIssues() []KnownIssue {
+	issues := append([]KnownIssue(nil), BuiltinKnownIssues()...)
+	index := make(map[string]int, len(issues))
+	for i, known := range issues {
+		index[known.ID] = i
+	}
+	for _, pack := range ae.RulePacks() {
+		for _, known := range pack.KnownIssues {
+			known.Origin = pack.Name
+			i, ok := index[known.ID]
+			if !ok && known.Replaces != "" {
+				if i, ok = index[known.Replaces]; ok {
+					delete(index, known.Replaces)
+				}
+			}
+			if ok {
+				issues[i] = known
+				index[known.ID] = i
+				continue
+			}
+			index[known.ID] = len(issues)
+			issues = append(issues, known)
+		}
+	}
+	return issues
+}
+
+// knownIssueMatchers compiles the signatures looked for in a source
+func (ae *AnalysisEngine) knownIssueMatchers(source string) []knownIssueMatcher {
+	var matchers []knownIssueMatcher
+	for _, known := range ae.KnownIssues() {
+		knownSource := known.Source
+		if knownSource == "" {
+			knownSource = KnownIssueLogs
+		}
+		if knownSource != source {
+			continue
+		}
+		pattern, err := known.compile()
+		if err != nil {
+			continue
+		}
+		matchers = append(matchers, knownIssueMatcher{KnownIssue: known, pattern: pattern})
+	}
+	return matchers
+}
+
+// knownIssuesKey identifies the signature database for the analysis cache
+func (ae *AnalysisEngine) knownIssuesKey() string {
+	hash := fnv.New64a()
+	for _, known := range ae.KnownIssues() {
+		fmt.Fprintf(hash, "%+v\n", known)
+	}
+	return fmt.Sprintf("known=%x", hash.Sum64())
+}
+
+// addReference attaches a known issue to an issue once
+func addReference(issue *Issue, ref Reference) {
+	for _, existing := range issue.References {
+		if existing.ID == ref.ID && existing.Kind == ref.Kind {
+			return
+		}
+	}
+	issue.References = append(issue.References, ref)
+}
+
+// matchKnownEvents raises an issue for each event signature the Warning
+// events of a must-gather match
+func (ae *AnalysisEngine) matchKnownEvents(result *AnalysisResult) {
+	matchers := ae.knownIssueMatchers(KnownIssueEvents)
+	if len(matchers) == 0 {
+		return
+	}
+	limit := ae.budgetFor(result).limits.MaxEvidencePerIssue
+	index := make(map[string]int)
+	occurrences := make(map[string]int)
+	for _, event := range result.events {
+		if event.Source != TimelineSourceEvent {
+			continue
+		}
+		for _, matcher := range matchers {
+			if !matcher.pattern.MatchString(event.Summary) {
+				continue
+			}
+			occurrences[matcher.ID]++
+			if idx, ok := index[matcher.ID]; ok {
+				if issue := &result.Issues[idx]; len(issue.Evidence) < limit {
+					issue.Evidence = append(issue.Evidence, event.Summary)
+				}
+				continue
+			}
+			issue := matcher.issue()
+			issue.Category = "events"
+			issue.Location = "events"
+			if event.Origin != "" {
+				issue.Location = "events in " + event.Origin
+			}
+			issue.Evidence = []string{event.Summary}
+			issue.Metadata = map[string]string{}
+			if ae.addIssue(result, issue) {
+				index[matcher.ID] = len(result.Issues) - 1
+			}
+		}
+	}
+	for id, idx := range index {
+		result.Issues[idx].Metadata["occurrences"] = fmt.Sprintf("%d", occurrences[id])
+	}
+}
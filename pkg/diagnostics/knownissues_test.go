@@ -0,0 +1,148 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinKnownIssues(t *testing.T) {
+	issues := BuiltinKnownIssues()
+	if len(issues) == 0 {
+		t.Fatal("the built-in known issue database is empty")
+	}
+	for _, known := range issues {
+		if known.Origin != "built-in" || !strings.HasPrefix(known.URL, "https://") {
+			t.Errorf("built-in known issue %+v", known)
+		}
+	}
+	if _, err := ParseRulePack("pack.yaml", []byte("known_issues:\n- {id: x, title: t, pattern: a, url: https://example.com, kind: jira}\n")); err == nil || !strings.Contains(err.Error(), "kind must be kcs, bug or search") {
+		t.Errorf("ParseRulePack() error = %v, expected an invalid kind", err)
+	}
+}
+
+func TestAnalyzeLogsWithKnownIssues(t *testing.T) {
+	rules := t.TempDir()
+	pack := `
+name: platform
+rules:
+- name: Etcd Write Failure
+  pattern: 'etcdserver: '
+  severity: critical
+  category: etcd
+  resolution: Check the etcd cluster health
+known_issues:
+- id: "1234567"
+  replaces: etcd-slow-disk
+  title: etcd latency on thin-provisioned disks
+  pattern: 'slow fdatasync'
+  url: https://access.redhat.com/solutions/1234567
+- id: OCPBUGS-42
+  kind: bug
+  title: Ledger writer drops connections
+  pattern: 'ledger-writer: connection reset'
+  url: https://issues.redhat.com/browse/OCPBUGS-42
+`
+	if err := os.WriteFile(filepath.Join(rules, "platform.yaml"), []byte(pack), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logs := t.TempDir()
+	content := "etcdserver: mvcc: database space exceeded\n" +
+		"kubelet: PLEG is not healthy: pleg was last seen active 3m0s ago\n" +
+		"kubelet: PLEG is not healthy: pleg was last seen active 4m0s ago\n" +
+		"ledger-writer: connection reset by peer\n" +
+		"etcd: slow fdatasync took 1.2s\n"
+	if err := os.WriteFile(filepath.Join(logs, "node.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	if _, err := engine.LoadRuleDir(rules); err != nil {
+		t.Fatalf("LoadRuleDir() error = %v", err)
+	}
+	result, err := engine.AnalyzeLogs(context.Background(), logs)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	found := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		found[issue.Title] = issue
+	}
+
+	// A signature matching the line of another issue is attached to it
+	if etcd := found["Etcd Write Failure"]; !reflect.DeepEqual(refIDs(etcd), []string{"etcd-database-space-exceeded"}) {
+		t.Errorf("Etcd Write Failure references = %+v", etcd.References)
+	}
+	if reset := found["Connection Refused"]; len(reset.References) != 1 || reset.References[0].Kind != KnownIssueBug {
+		t.Errorf("Connection Refused references = %+v", reset.References)
+	}
+
+	// Lines no pattern covers raise an issue of their own
+	pleg, ok := found["Known issue: kubelet PLEG is not healthy"]
+	if !ok {
+		t.Fatalf("missing the PLEG known issue, found %v", found)
+	}
+	if pleg.Severity != "critical" || pleg.Category != knownIssueCategory || pleg.Metadata["occurrences"] != "2" || !reflect.DeepEqual(refIDs(pleg), []string{"kubelet-pleg-not-healthy"}) {
+		t.Errorf("PLEG issue = %+v", pleg)
+	}
+
+	// A rule pack entry pins the built-in signature it replaces
+	pinned, ok := found["Known issue: etcd latency on thin-provisioned disks"]
+	if !ok || pinned.References[0].URL != "https://access.redhat.com/solutions/1234567" {
+		t.Errorf("pinned etcd issue = %+v", pinned)
+	}
+	if _, ok := found["Known issue: etcd requests slowed down by disk latency"]; ok {
+		t.Error("the built-in signature replaced by the rule pack still matched")
+	}
+}
+
+func TestAnalyzeMustGatherKnownEvents(t *testing.T) {
+	dir := t.TempDir()
+	eventsDir := filepath.Join(dir, "namespaces", "shop", "core")
+	if err := os.MkdirAll(eventsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	events := `apiVersion: v1
+items:
+- apiVersion: v1
+  firstTimestamp: "2024-05-01T10:02:00Z"
+  involvedObject:
+    kind: Pod
+    name: db-1
+    namespace: shop
+  message: 'Multi-Attach error for volume "pvc-1" Volume is already exclusively attached to one node'
+  reason: FailedAttachVolume
+  type: Warning
+kind: List
+`
+	if err := os.WriteFile(filepath.Join(eventsDir, "events.yaml"), []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+	result, err := engine.AnalyzeMustGather(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeMustGather failed: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Title == "Known issue: ReadWriteOnce volume still attached to another node" {
+			if issue.Location != "events in shop" || !reflect.DeepEqual(refIDs(issue), []string{"volume-multi-attach"}) || !strings.Contains(issue.Evidence[0], "FailedAttachVolume Pod/db-1") {
+				t.Errorf("multi-attach issue = %+v", issue)
+			}
+			return
+		}
+	}
+	t.Errorf("missing the multi-attach known issue in %+v", result.Issues)
+}
+
+func refIDs(issue Issue) []string {
+	var ids []string
+	for _, ref := range issue.References {
+		ids = append(ids, ref.ID)
+	}
+	return ids
+}
@@ -0,0 +1,233 @@
+package diagnostics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// AnalysisLimits bounds the memory used when analyzing large artifacts
+type AnalysisLimits struct {
+	MaxLineLength       int    `json:"max_line_length"`        // bytes kept per line, the rest is discarded
+	MaxEvidencePerIssue int    `json:"max_evidence_per_issue"` // evidence lines kept per issue
+	MaxIssues           int    `json:"max_issues"`             // distinct issues per analysis
+	MaxEvidenceBytes    int64  `json:"max_evidence_bytes"`     // total evidence held across all issues
+	MaxHeapBytes        uint64 `json:"max_heap_bytes"`         // stop reading when the heap grows past this
+}
+
+const (
+	defaultMaxLineLength       = 64 * 1024
+	defaultMaxEvidencePerIssue = 10
+	defaultMaxIssues           = 1000
+	defaultMaxEvidenceBytes    = 8 * 1024 * 1024
+	defaultMaxHeapBytes        = 1024 * 1024 * 1024
+
+	// heapCheckInterval is how many lines are read between heap checks
+	heapCheckInterval = 50000
+)
+
+// DefaultAnalysisLimits returns limits suitable for multi-GB log files
+func DefaultAnalysisLimits() AnalysisLimits {
+	return AnalysisLimits{
+		MaxLineLength:       defaultMaxLineLength,
+		MaxEvidencePerIssue: defaultMaxEvidencePerIssue,
+		MaxIssues:           defaultMaxIssues,
+		MaxEvidenceBytes:    defaultMaxEvidenceBytes,
+		MaxHeapBytes:        defaultMaxHeapBytes,
+	}
+}
+
+// withDefaults fills unset limits with their defaults
+func (l AnalysisLimits) withDefaults() AnalysisLimits {
+	defaults := DefaultAnalysisLimits()
+	if l.MaxLineLength <= 0 {
+		l.MaxLineLength = defaults.MaxLineLength
+	}
+	if l.MaxEvidencePerIssue <= 0 {
+		l.MaxEvidencePerIssue = defaults.MaxEvidencePerIssue
+	}
+	if l.MaxIssues <= 0 {
+		l.MaxIssues = defaults.MaxIssues
+	}
+	if l.MaxEvidenceBytes <= 0 {
+		l.MaxEvidenceBytes = defaults.MaxEvidenceBytes
+	}
+	if l.MaxHeapBytes == 0 {
+		l.MaxHeapBytes = defaults.MaxHeapBytes
+	}
+	return l
+}
+
+// cacheKey identifies the limits results were produced under, so cached
+// results are not reused after the limits change
+func (l AnalysisLimits) cacheKey() string {
+	return fmt.Sprintf("line=%d,evidence=%d,issues=%d,evidence-bytes=%d,heap=%d",
+		l.MaxLineLength, l.MaxEvidencePerIssue, l.MaxIssues, l.MaxEvidenceBytes, l.MaxHeapBytes)
+}
+
+// analysisBudget tracks resource usage across all files of one analysis and
+// counts what was dropped so truncation is reported once per limit
+type analysisBudget struct {
+	limits        AnalysisLimits
+	issues        int
+	evidenceBytes int64
+	linesRead     int64
+	heapExceeded  bool
+
+	cutLines        int
+	droppedIssues   int
+	droppedEvidence int
+}
+
+// budgetFor returns the budget shared by everything contributing to a result
+func (ae *AnalysisEngine) budgetFor(result *AnalysisResult) *analysisBudget {
+	if result.budget == nil {
+		result.budget = &analysisBudget{limits: ae.limits}
+	}
+	return result.budget
+}
+
+// allowIssue reserves room for a new distinct issue
+func (b *analysisBudget) allowIssue() bool {
+	if b.issues >= b.limits.MaxIssues {
+		b.droppedIssues++
+		return false
+	}
+	b.issues++
+	return true
+}
+
+// allowEvidence reserves room for an evidence line
+func (b *analysisBudget) allowEvidence(line string) bool {
+	if b.evidenceBytes+int64(len(line)) > b.limits.MaxEvidenceBytes {
+		b.droppedEvidence++
+		return false
+	}
+	b.evidenceBytes += int64(len(line))
+	return true
+}
+
+// allowEvidenceLines keeps the evidence lines that fit in the budget
+func (b *analysisBudget) allowEvidenceLines(lines []string) []string {
+	var kept []string
+	for _, line := range lines {
+		if b.allowEvidence(line) {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// losses returns a counter that grows whenever anything is cut or dropped
+func (b *analysisBudget) losses() int {
+	total := b.cutLines + b.droppedIssues + b.droppedEvidence
+	if b.heapExceeded {
+		total++
+	}
+	return total
+}
+
+// addIssue appends an issue if the budget allows it
+func (ae *AnalysisEngine) addIssue(result *AnalysisResult, issue Issue) bool {
+	budget := ae.budgetFor(result)
+	if !budget.allowIssue() {
+		return false
+	}
+	issue.Evidence = budget.allowEvidenceLines(issue.Evidence)
+	result.Issues = append(result.Issues, issue)
+	return true
+}
+
+// finishBudget records one truncation reason for each limit that was hit
+func (ae *AnalysisEngine) finishBudget(result *AnalysisResult) {
+	budget := result.budget
+	if budget == nil {
+		return
+	}
+	limits := budget.limits
+
+	if budget.cutLines > 0 {
+		result.markTruncated(fmt.Sprintf("%d lines longer than %d bytes were cut", budget.cutLines, limits.MaxLineLength))
+	}
+	if budget.droppedIssues > 0 {
+		result.markTruncated(fmt.Sprintf("issue limit of %d reached, %d further issues were not reported", limits.MaxIssues, budget.droppedIssues))
+	}
+	if budget.droppedEvidence > 0 {
+		result.markTruncated(fmt.Sprintf("evidence memory limit of %d bytes reached, %d evidence lines were dropped", limits.MaxEvidenceBytes, budget.droppedEvidence))
+	}
+	if budget.heapExceeded {
+		result.markTruncated(fmt.Sprintf("heap limit of %d MB reached, remaining files were not read", limits.MaxHeapBytes/(1024*1024)))
+	}
+}
+
+// checkHeap periodically samples the heap and reports whether reading may continue
+func (b *analysisBudget) checkHeap() bool {
+	if b.heapExceeded {
+		return false
+	}
+	b.linesRead++
+	if b.linesRead%heapCheckInterval != 0 {
+		return true
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.HeapAlloc > b.limits.MaxHeapBytes {
+		b.heapExceeded = true
+		return false
+	}
+	return true
+}
+
+// markTruncated flags a result as incomplete and records why
+func (r *AnalysisResult) markTruncated(reason string) {
+	r.Truncated = true
+	for _, existing := range r.TruncationReasons {
+		if existing == reason {
+			return
+		}
+	}
+	r.TruncationReasons = append(r.TruncationReasons, reason)
+}
+
+// readLines streams lines from r without ever buffering more than maxLen
+// bytes of a single line. Overlong lines are cut at maxLen and reported via
+// the truncated flag. Returning false from fn stops reading.
+func readLines(r io.Reader, maxLen int, fn func(lineNum int, line string, truncated bool) bool) error {
+	if maxLen < 16 {
+		maxLen = 16
+	}
+	reader := bufio.NewReaderSize(r, maxLen)
+	lineNum := 0
+
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read line %d: %v", lineNum+1, err)
+		}
+
+		lineNum++
+		line := string(chunk)
+		truncated := false
+
+		// Discard the remainder of an overlong line
+		for isPrefix {
+			truncated = true
+			_, isPrefix, err = reader.ReadLine()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read line %d: %v", lineNum, err)
+			}
+		}
+
+		if !fn(lineNum, line, truncated) {
+			return nil
+		}
+	}
+}
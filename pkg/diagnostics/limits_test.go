@@ -0,0 +1,166 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLinesCutsOverlongLines(t *testing.T) {
+	input := "short\n" + strings.Repeat("x", 100) + "\nlast\n"
+
+	var lines []string
+	var truncated []bool
+	err := readLines(strings.NewReader(input), 32, func(lineNum int, line string, cut bool) bool {
+		lines = append(lines, line)
+		truncated = append(truncated, cut)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("readLines returned %v", err)
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("readLines produced %d lines, expected 3", len(lines))
+	}
+	if len(lines[1]) != 32 || !truncated[1] {
+		t.Errorf("overlong line = %d bytes truncated=%v, expected 32 bytes truncated=true", len(lines[1]), truncated[1])
+	}
+	if lines[2] != "last" || truncated[2] {
+		t.Errorf("line after overlong line = %q truncated=%v, expected %q truncated=false", lines[2], truncated[2], "last")
+	}
+}
+
+func TestAnalyzeLogsGroupsAndCapsEvidence(t *testing.T) {
+	dir := t.TempDir()
+	var content strings.Builder
+	for i := 0; i < 50; i++ {
+		content.WriteString("dial tcp 10.0.0.1:5432: connection refused\n")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.log"), []byte(content.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetLimits(AnalysisLimits{MaxEvidencePerIssue: 3})
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("found %d issues, expected 1 grouped issue", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if len(issue.Evidence) != 3 {
+		t.Errorf("evidence lines = %d, expected 3", len(issue.Evidence))
+	}
+	if issue.Metadata["occurrences"] != "50" {
+		t.Errorf("occurrences = %q, expected %q", issue.Metadata["occurrences"], "50")
+	}
+}
+
+func TestAnalyzeLogsReportsIssueLimit(t *testing.T) {
+	dir := t.TempDir()
+	content := "connection refused\nno space left on device\n"
+	if err := os.WriteFile(filepath.Join(dir, "app.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetLimits(AnalysisLimits{MaxIssues: 1})
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Errorf("found %d issues, expected 1", len(result.Issues))
+	}
+	if !result.Truncated || len(result.TruncationReasons) == 0 {
+		t.Errorf("Truncated = %v with reasons %v, expected truncated with a reason", result.Truncated, result.TruncationReasons)
+	}
+}
+
+func TestAnalyzeLogsEnforcesLimitsOnCachedIssues(t *testing.T) {
+	dir := t.TempDir()
+	changed := filepath.Join(dir, "a.log")
+	if err := os.WriteFile(changed, []byte("all good\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.log"), []byte("connection refused\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetLimits(AnalysisLimits{MaxIssues: 1})
+	if _, err := engine.AnalyzeLogs(context.Background(), dir); err != nil {
+		t.Fatalf("first AnalyzeLogs failed: %v", err)
+	}
+
+	// The changed file now uses up the budget before b.log comes from the cache
+	if err := os.WriteFile(changed, []byte("no space left on device\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("second AnalyzeLogs failed: %v", err)
+	}
+	if result.Metrics["cached_files"] != 1 {
+		t.Fatalf("second run cached_files = %v, expected 1", result.Metrics["cached_files"])
+	}
+	if len(result.Issues) != 1 || !result.Truncated {
+		t.Errorf("second run found %d issues truncated=%v, expected 1 issue and a truncated result", len(result.Issues), result.Truncated)
+	}
+}
+
+func TestAnalyzeLogsReportsEachLimitOnce(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		content := "connection refused\nno space left on device\n" + strings.Repeat("x", 100) + "\n"
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("app-%d.log", i)), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := newTestEngine()
+	engine.SetLimits(AnalysisLimits{MaxIssues: 1, MaxLineLength: 32})
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.TruncationReasons) != 2 {
+		t.Errorf("TruncationReasons = %v, expected one reason for cut lines and one for the issue limit", result.TruncationReasons)
+	}
+}
+
+func TestAnalyzeMustGatherHonoursIssueLimit(t *testing.T) {
+	dir := t.TempDir()
+	podsDir := filepath.Join(dir, "namespaces", "app", "core")
+	if err := os.MkdirAll(podsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "reason: CrashLoopBackOff\nreason: ImagePullBackOff\nphase: Pending\n"
+	if err := os.WriteFile(filepath.Join(podsDir, "pods.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetLimits(AnalysisLimits{MaxIssues: 2})
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeMustGather(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeMustGather failed: %v", err)
+	}
+	if len(result.Issues) != 2 || !result.Truncated {
+		t.Errorf("found %d issues truncated=%v, expected 2 issues and a truncated result", len(result.Issues), result.Truncated)
+	}
+}
@@ -0,0 +1,254 @@
+package diagnostics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// localeSampleLines is how many lines are read to detect a log's language
+	localeSampleLines = 200
+
+	// minLocaleScore is how many stopwords a language needs to be detected
+	minLocaleScore = 5
+)
+
+// PatternPack holds localized phrases for the built-in log patterns, keyed by
+// pattern name. Localized phrases are matched in addition to the English
+// patterns, which always apply as the fallback.
+type PatternPack struct {
+	Locale  string
+	Phrases map[string][]string
+}
+
+// builtinPatternPacks translate the failures most often seen in localized
+// platform logs, using the glibc strerror translations where they exist
+var builtinPatternPacks = []PatternPack{
+	{Locale: "de", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"Nicht genügend Hauptspeicher", "Kann Speicher nicht zuweisen", "Speicher erschöpft"},
+		"Connection Refused":     {"Verbindungsaufbau abgelehnt", "Verbindung abgelehnt", "Verbindung vom Kommunikationspartner zurückgesetzt"},
+		"DNS Resolution Failure": {"Name oder Dienst nicht bekannt", "Namensauflösung fehlgeschlagen"},
+		"Disk Space Error":       {"Auf dem Gerät ist kein Speicherplatz mehr verfügbar", "Kein Speicherplatz"},
+		"Permission Denied":      {"Keine Berechtigung", "Zugriff verweigert", "Berechtigung verweigert"},
+	}},
+	{Locale: "fr", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"Impossible d'allouer de la mémoire", "Mémoire insuffisante", "mémoire épuisée"},
+		"Connection Refused":     {"Connexion refusée", "Connexion ré-initialisée par le correspondant"},
+		"DNS Resolution Failure": {"Nom ou service inconnu", "Échec de la résolution de nom"},
+		"Disk Space Error":       {"Aucun espace disponible sur le périphérique", "disque plein"},
+		"Permission Denied":      {"Permission non accordée", "Permission refusée", "Accès refusé"},
+	}},
+	{Locale: "es", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"No se puede asignar memoria", "Memoria insuficiente", "sin memoria"},
+		"Connection Refused":     {"Conexión rehusada", "Conexión rechazada", "Conexión reinicializada por la máquina remota"},
+		"DNS Resolution Failure": {"Nombre o servicio desconocido", "Fallo en la resolución de nombres"},
+		"Disk Space Error":       {"No queda espacio en el dispositivo", "disco lleno"},
+		"Permission Denied":      {"Permiso denegado", "Acceso denegado"},
+	}},
+	{Locale: "pt", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"Não foi possível alocar memória", "Memória insuficiente", "sem memória"},
+		"Connection Refused":     {"Conexão recusada", "Conexão reiniciada pela outra ponta"},
+		"DNS Resolution Failure": {"Nome ou serviço desconhecido", "Falha na resolução de nomes"},
+		"Disk Space Error":       {"Não há espaço disponível no dispositivo", "disco cheio"},
+		"Permission Denied":      {"Permissão negada", "Acesso negado"},
+	}},
+	{Locale: "ru", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"Невозможно выделить память", "Недостаточно памяти"},
+		"Connection Refused":     {"В соединении отказано", "Соединение сброшено другой стороной"},
+		"DNS Resolution Failure": {"Имя или служба не известны"},
+		"Disk Space Error":       {"На устройстве не осталось свободного места"},
+		"Permission Denied":      {"Отказано в доступе", "Доступ запрещён"},
+	}},
+	{Locale: "ja", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"メモリを確保できません", "メモリ不足"},
+		"Connection Refused":     {"接続を拒否されました", "接続が拒否されました", "接続が相手からリセットされました"},
+		"DNS Resolution Failure": {"名前解決に失敗", "名前またはサービスが不明です"},
+		"Disk Space Error":       {"デバイスに空き領域がありません", "ディスク容量不足"},
+		"Permission Denied":      {"許可がありません", "アクセスが拒否されました"},
+	}},
+	{Locale: "zh", Phrases: map[string][]string{
+		"OutOfMemory Error":      {"无法分配内存", "内存不足"},
+		"Connection Refused":     {"拒绝连接", "连接被拒绝", "连接被对方重置"},
+		"DNS Resolution Failure": {"未知的名称或服务", "域名解析失败"},
+		"Disk Space Error":       {"设备上没有空间", "磁盘空间不足"},
+		"Permission Denied":      {"权限不够", "拒绝访问", "权限被拒绝"},
+	}},
+}
+
+// localeStopwords are common words used to tell Latin-script languages apart
+var localeStopwords = map[string][]string{
+	"en": {"the", "and", "is", "not", "to", "of", "failed", "cannot", "with", "for"},
+	"de": {"der", "die", "das", "und", "nicht", "ist", "kann", "werden", "fehler", "wurde", "mit"},
+	"fr": {"le", "la", "les", "est", "pas", "une", "des", "erreur", "impossible", "du", "avec"},
+	"es": {"el", "los", "las", "una", "del", "se", "está", "puede", "fallo", "con", "por"},
+	"pt": {"não", "uma", "os", "erro", "foi", "está", "pode", "falha", "com", "ao"},
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}']+`)
+
+// detectLocale guesses the language of log text, returning "" when it looks
+// English or too little text was seen
+func detectLocale(sample string) string {
+	var kana, han, cyrillic int
+	for _, r := range sample {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		}
+	}
+	switch {
+	case kana >= minLocaleScore:
+		return "ja"
+	case han >= minLocaleScore:
+		return "zh"
+	case cyrillic >= minLocaleScore*3:
+		return "ru"
+	}
+
+	scores := make(map[string]int)
+	for _, word := range wordPattern.FindAllString(strings.ToLower(sample), -1) {
+		for locale, stopwords := range localeStopwords {
+			for _, stopword := range stopwords {
+				if word == stopword {
+					scores[locale]++
+				}
+			}
+		}
+	}
+
+	best, bestScore := "", minLocaleScore-1
+	for _, locale := range []string{"de", "fr", "es", "pt"} {
+		if scores[locale] > bestScore {
+			best, bestScore = locale, scores[locale]
+		}
+	}
+	if best == "" || scores["en"] >= bestScore {
+		return ""
+	}
+	return best
+}
+
+// SetLocales selects pattern packs that always apply, in addition to the
+// pack for each log's detected language
+func (ae *AnalysisEngine) SetLocales(locales []string) {
+	ae.locales = nil
+	for _, locale := range locales {
+		if locale = strings.ToLower(strings.TrimSpace(locale)); locale != "" {
+			ae.locales = append(ae.locales, locale)
+		}
+	}
+	sort.Strings(ae.locales)
+}
+
+// AddPatternPack registers a custom pack, extending any pack for the same locale
+func (ae *AnalysisEngine) AddPatternPack(pack PatternPack) {
+	if ae.patternPacks == nil {
+		ae.patternPacks = make(map[string]PatternPack)
+	}
+	existing, ok := ae.patternPacks[pack.Locale]
+	if !ok {
+		existing = PatternPack{Locale: pack.Locale, Phrases: make(map[string][]string)}
+	}
+	for name, phrases := range pack.Phrases {
+		existing.Phrases[name] = append(existing.Phrases[name], phrases...)
+	}
+	ae.patternPacks[pack.Locale] = existing
+}
+
+// localeKey identifies the pack configuration for the analysis cache
+func (ae *AnalysisEngine) localeKey() string {
+	if len(ae.locales) == 0 && len(ae.patternPacks) == 0 {
+		return ""
+	}
+
+	hash := fnv.New64a()
+	locales := make([]string, 0, len(ae.patternPacks))
+	for locale := range ae.patternPacks {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		phrases := ae.patternPacks[locale].Phrases
+		names := make([]string, 0, len(phrases))
+		for name := range phrases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(hash, "%s/%s=%s\n", locale, name, strings.Join(phrases[name], "|"))
+		}
+	}
+	return fmt.Sprintf("locales=%s,packs=%x", strings.Join(ae.locales, "+"), hash.Sum64())
+}
+
+// phrasesFor returns the localized phrases of a pattern for the given locales
+func (ae *AnalysisEngine) phrasesFor(name string, locales []string) []string {
+	var phrases []string
+	for _, locale := range locales {
+		for _, pack := range builtinPatternPacks {
+			if pack.Locale == locale {
+				phrases = append(phrases, pack.Phrases[name]...)
+			}
+		}
+		if pack, ok := ae.patternPacks[locale]; ok {
+			phrases = append(phrases, pack.Phrases[name]...)
+		}
+	}
+	return phrases
+}
+
+// localizePatterns extends patterns with the phrases of the configured packs
+// and the pack for the detected locale; English matching is always kept
+func (ae *AnalysisEngine) localizePatterns(patterns []LogPattern, detected string) []LogPattern {
+	locales := append([]string(nil), ae.locales...)
+	if detected != "" {
+		locales = append(locales, detected)
+	}
+	if len(locales) == 0 {
+		return patterns
+	}
+
+	localized := make([]LogPattern, len(patterns))
+	for i, pattern := range patterns {
+		localized[i] = pattern
+		phrases := ae.phrasesFor(pattern.Name, locales)
+		if len(phrases) == 0 {
+			continue
+		}
+
+		quoted := make([]string, len(phrases))
+		for j, phrase := range phrases {
+			quoted[j] = regexp.QuoteMeta(phrase)
+		}
+		expr := pattern.Pattern.String() + "|(?i:" + strings.Join(quoted, "|") + ")"
+		if compiled, err := regexp.Compile(expr); err == nil {
+			localized[i].Pattern = compiled
+		}
+	}
+	return localized
+}
+
+// detectFileLocale samples the start of a log to detect its language
+func (ae *AnalysisEngine) detectFileLocale(path string) string {
+	file, err := openLogFile(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var sample strings.Builder
+	readLines(file, ae.limits.MaxLineLength, func(lineNum int, line string, truncated bool) bool {
+		sample.WriteString(line)
+		sample.WriteByte('\n')
+		return lineNum < localeSampleLines
+	})
+	return detectLocale(sample.String())
+}
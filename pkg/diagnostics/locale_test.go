@@ -0,0 +1,102 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		sample   string
+		expected string
+	}{
+		{"the pod failed to start and is not ready for traffic with the new config", ""},
+		{"Der Dienst konnte nicht gestartet werden und die Verbindung ist mit Fehler abgebrochen, das Volume wurde nicht eingebunden", "de"},
+		{"Le service est en erreur: impossible de joindre la base, les requêtes ne passent pas avec une latence du réseau", "fr"},
+		{"El servicio no se puede iniciar, los pods están en fallo con una configuración del clúster por error", "es"},
+		{"Não foi possível iniciar o serviço, os pods estão com falha ao ler uma configuração, erro foi registrado", "pt"},
+		{"Не удалось подключиться к серверу базы данных: отказано в доступе", "ru"},
+		{"デバイスに空き領域がありません。ログを書き込めません", "ja"},
+		{"无法分配内存，服务启动失败", "zh"},
+		{"short", ""},
+	}
+
+	for _, tt := range tests {
+		if got := detectLocale(tt.sample); got != tt.expected {
+			t.Errorf("detectLocale(%q) = %q, expected %q", tt.sample, got, tt.expected)
+		}
+	}
+}
+
+func TestAnalyzeLogsMatchesLocalizedMessages(t *testing.T) {
+	dir := t.TempDir()
+	logs := map[string]string{
+		"de.log": "Der Dienst wurde mit einem Fehler beendet und die Anfrage ist nicht angekommen\n" +
+			"open /var/lib/data: Keine Berechtigung\n" +
+			"write /var/log/app.log: Auf dem Gerät ist kein Speicherplatz mehr verfügbar\n",
+		"ja.log": "サービスを開始しています\n接続を拒否されました: 10.0.0.1:5432\nメモリを確保できません\n",
+	}
+	for name, content := range logs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+
+	found := make(map[string]string)
+	for _, issue := range result.Issues {
+		found[filepath.Base(strings.Split(issue.Location, ":")[0])+"/"+issue.Title] = issue.Metadata["locale"]
+	}
+	for key, locale := range map[string]string{
+		"de.log/Permission Denied":  "de",
+		"de.log/Disk Space Error":   "de",
+		"ja.log/Connection Refused": "ja",
+		"ja.log/OutOfMemory Error":  "ja",
+	} {
+		got, ok := found[key]
+		if !ok {
+			t.Errorf("missing issue %s, found %v", key, found)
+		} else if got != locale {
+			t.Errorf("issue %s locale = %q, expected %q", key, got, locale)
+		}
+	}
+}
+
+func TestCustomPatternPack(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "it.log"), []byte("apertura file: Permesso negato\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Fatalf("found %d issues without the Italian pack, expected 0", len(result.Issues))
+	}
+
+	engine.AddPatternPack(PatternPack{Locale: "it", Phrases: map[string][]string{"Permission Denied": {"Permesso negato"}}})
+	engine.SetLocales([]string{"IT"})
+
+	result, err = engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Title != "Permission Denied" {
+		t.Errorf("issues with the Italian pack = %+v, expected Permission Denied", result.Issues)
+	}
+}
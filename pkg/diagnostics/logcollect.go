@@ -0,0 +1,295 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLogWorkers is how many containers' logs are fetched at once
+	DefaultLogWorkers = 4
+	maxLogWorkers     = 32
+
+	// LogManifestFile lists what a namespace log collection gathered
+	LogManifestFile = "manifest.json"
+)
+
+// LogManifest describes a namespace log collection
+type LogManifest struct {
+	Namespace       string             `json:"namespace"`
+	Selector        string             `json:"selector,omitempty"`
+	Since           string             `json:"since,omitempty"`
+	IncludePrevious bool               `json:"include_previous"`
+	Workers         int                `json:"workers"`
+	CollectedAt     time.Time          `json:"collected_at"`
+	Duration        string             `json:"duration"`
+	Pods            int                `json:"pods"`
+	Containers      int                `json:"containers"`
+	Failed          int                `json:"failed"`
+	TotalBytes      int64              `json:"total_bytes"`
+	Files           []LogManifestEntry `json:"files"`
+}
+
+// LogManifestEntry is one collected log, or the error that prevented it
+type LogManifestEntry struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Init      bool   `json:"init,omitempty"`
+	Previous  bool   `json:"previous,omitempty"`
+	File      string `json:"file,omitempty"` // relative to the collection directory
+	Bytes     int64  `json:"bytes"`
+	Error     string `json:"error,omitempty"`
+}
+
+// podList is the part of oc get pods -o json log collection needs
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName       string `json:"nodeName"`
+			InitContainers []struct {
+				Name string `json:"name"`
+			} `json:"initContainers"`
+			Containers []struct {
+				Name string `json:"name"`
+			} `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			InitContainerStatuses []containerRestarts `json:"initContainerStatuses"`
+			ContainerStatuses     []containerRestarts `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+type containerRestarts struct {
+	Name         string `json:"name"`
+	RestartCount int    `json:"restartCount"`
+}
+
+// parseLogWorkers reads the worker pool size, defaulting to DefaultLogWorkers
+func parseLogWorkers(value string) (int, error) {
+	if value == "" {
+		return DefaultLogWorkers, nil
+	}
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 1 || workers > maxLogWorkers {
+		return 0, fmt.Errorf("workers must be between 1 and %d, got %q", maxLogWorkers, value)
+	}
+	return workers, nil
+}
+
+// logJobs lists the logs to fetch for the listed pods: every init and app
+// container, plus the previous instance of containers that restarted
+func logJobs(pods *podList, includePrevious bool) []LogManifestEntry {
+	var jobs []LogManifestEntry
+	for _, pod := range pods.Items {
+		restarts := make(map[string]int)
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			restarts[status.Name] = status.RestartCount
+		}
+		add := func(container string, init bool) {
+			jobs = append(jobs, LogManifestEntry{Pod: pod.Metadata.Name, Container: container, Init: init})
+			if includePrevious && restarts[container] > 0 {
+				jobs = append(jobs, LogManifestEntry{Pod: pod.Metadata.Name, Container: container, Init: init, Previous: true})
+			}
+		}
+		for _, container := range pod.Spec.InitContainers {
+			add(container.Name, true)
+		}
+		for _, container := range pod.Spec.Containers {
+			add(container.Name, false)
+		}
+	}
+	return jobs
+}
+
+// CollectNamespaceLogs collects the logs of every container of the pods in
+// opts.Namespace matching the "selector" filter, fetching "workers" logs at
+// once (default 4). "previous" also fetches the logs of restarted
+// containers' previous instances and "since" limits logs to a recent
+// duration. Logs are written to <pod>/<container>.log next to a manifest of
+// what was collected, what failed and the total size.
+func (dc *DiagnosticCollector) CollectNamespaceLogs(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
+	start := time.Now()
+	result := &CollectionResult{
+		Type:     "logs",
+		Metadata: make(map[string]string),
+	}
+	fail := func(err error) (*CollectionResult, error) {
+		result.Status = "failed"
+		result.ErrorMsg = err.Error()
+		result.Duration = time.Since(start)
+		return result, err
+	}
+
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("namespace is required for namespace log collection")
+	}
+	workers, err := parseLogWorkers(opts.Filters["workers"])
+	if err != nil {
+		return nil, err
+	}
+	since := opts.Filters["since"]
+	if since != "" {
+		if _, err := time.ParseDuration(since); err != nil {
+			return nil, fmt.Errorf("invalid since duration %q: %v", since, err)
+		}
+	}
+	manifest := &LogManifest{
+		Namespace:       opts.Namespace,
+		Selector:        opts.Filters["selector"],
+		Since:           since,
+		IncludePrevious: opts.Filters["previous"] == "true",
+		Workers:         workers,
+		CollectedAt:     start.UTC(),
+	}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("logs-%s-%d", opts.Namespace, start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fail(err)
+	}
+	result.FilePath = outputDir
+
+	args := []string{"get", "pods", "-n", opts.Namespace, "-o", "json"}
+	if manifest.Selector != "" {
+		args = append(args, "-l", manifest.Selector)
+	}
+	var listing bytes.Buffer
+	if err := dc.runOC(ctx, nil, &listing, args...); err != nil {
+		return fail(fmt.Errorf("listing pods in %s: %v", opts.Namespace, err))
+	}
+	var pods podList
+	if err := json.Unmarshal(listing.Bytes(), &pods); err != nil {
+		return fail(fmt.Errorf("parsing the pod list: %v", err))
+	}
+	jobs := logJobs(&pods, manifest.IncludePrevious)
+	manifest.Pods = len(pods.Items)
+	for _, job := range jobs {
+		if !job.Previous {
+			manifest.Containers++
+		}
+	}
+
+	entries := dc.collectContainerLogs(ctx, outputDir, opts.Namespace, since, jobs, workers)
+	for _, entry := range entries {
+		manifest.TotalBytes += entry.Bytes
+		if entry.Error != "" {
+			manifest.Failed++
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Pod != entries[j].Pod {
+			return entries[i].Pod < entries[j].Pod
+		}
+		return entries[i].Init && !entries[j].Init
+	})
+	manifest.Files = entries
+	manifest.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, LogManifestFile), data, 0644); err != nil {
+		return fail(err)
+	}
+
+	result.Duration = time.Since(start)
+	result.Size = manifest.TotalBytes
+	result.Metadata["namespace"] = opts.Namespace
+	result.Metadata["selector"] = manifest.Selector
+	result.Metadata["manifest"] = filepath.Join(outputDir, LogManifestFile)
+	result.Metadata["pods"] = strconv.Itoa(manifest.Pods)
+	result.Metadata["containers"] = strconv.Itoa(manifest.Containers)
+	result.Metadata["files_collected"] = strconv.Itoa(len(entries) - manifest.Failed)
+	result.Metadata["failed"] = strconv.Itoa(manifest.Failed)
+	result.Metadata["workers"] = strconv.Itoa(workers)
+	if ctx.Err() != nil {
+		return fail(fmt.Errorf("log collection interrupted after %d of %d logs: %v", len(entries)-manifest.Failed, len(entries), ctx.Err()))
+	}
+
+	result.Status = "completed"
+	result.Summary = fmt.Sprintf("Collected %d logs from %d containers in %d pods (%.2f MB, %d failed) in %s",
+		len(entries)-manifest.Failed, manifest.Containers, manifest.Pods, float64(manifest.TotalBytes)/(1024*1024), manifest.Failed, outputDir)
+	dc.finishCollection("", result, opts)
+	dc.logger.Infof("Namespace log collection completed: %s", result.Summary)
+	return result, nil
+}
+
+// collectContainerLogs fetches the logs of jobs with a pool of workers, each
+// streaming one container's log straight to its file
+func (dc *DiagnosticCollector) collectContainerLogs(ctx context.Context, outputDir, namespace, since string, jobs []LogManifestEntry, workers int) []LogManifestEntry {
+	entries := make([]LogManifestEntry, len(jobs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(workers, len(jobs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range next {
+				entries[index] = dc.collectContainerLog(ctx, outputDir, namespace, since, jobs[index])
+			}
+		}()
+	}
+	for index := range jobs {
+		next <- index
+	}
+	close(next)
+	wg.Wait()
+	return entries
+}
+
+// collectContainerLog fetches one container's log into <pod>/<container>.log
+func (dc *DiagnosticCollector) collectContainerLog(ctx context.Context, outputDir, namespace, since string, entry LogManifestEntry) LogManifestEntry {
+	name := entry.Container + ".log"
+	if entry.Previous {
+		name = entry.Container + "-previous.log"
+	}
+	entry.File = filepath.Join(entry.Pod, name)
+	path := filepath.Join(outputDir, entry.File)
+
+	err := ctx.Err()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		var file *os.File
+		file, err = os.Create(path)
+		if err == nil {
+			args := []string{"logs", entry.Pod, "-c", entry.Container, "-n", namespace, "--timestamps=true"}
+			if entry.Previous {
+				args = append(args, "--previous=true")
+			}
+			if since != "" {
+				args = append(args, "--since="+since)
+			}
+			counter := &countingWriter{}
+			err = dc.runOC(ctx, nil, io.MultiWriter(file, counter), args...)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			entry.Bytes = counter.n
+		}
+	}
+	if err != nil {
+		os.Remove(path)
+		entry.File = ""
+		entry.Bytes = 0
+		entry.Error = err.Error()
+	}
+	return entry
+}
@@ -0,0 +1,131 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testPodList = `{"items": [
+  {"metadata": {"name": "api-1"},
+   "spec": {"initContainers": [{"name": "migrate"}], "containers": [{"name": "api"}, {"name": "proxy"}]},
+   "status": {"containerStatuses": [{"name": "api", "restartCount": 2}, {"name": "proxy", "restartCount": 0}]}},
+  {"metadata": {"name": "api-2"},
+   "spec": {"containers": [{"name": "api"}]},
+   "status": {"containerStatuses": [{"name": "api", "restartCount": 0}]}}
+]}`
+
+func TestParseLogWorkers(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+		valid    bool
+	}{
+		{"", DefaultLogWorkers, true},
+		{"8", 8, true},
+		{"0", 0, false},
+		{"33", 0, false},
+		{"many", 0, false},
+	}
+	for _, tt := range tests {
+		workers, err := parseLogWorkers(tt.value)
+		if workers != tt.expected || (err == nil) != tt.valid {
+			t.Errorf("parseLogWorkers(%q) = %d, %v, expected %d", tt.value, workers, err, tt.expected)
+		}
+	}
+}
+
+func TestCollectNamespaceLogs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+
+	var mu sync.Mutex
+	var calls []string
+	dc.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		call := strings.Join(args, " ")
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+		script := "echo 'unexpected call' >&2; exit 1"
+		switch {
+		case args[0] == "get":
+			script = "cat <<'EOF'\n" + testPodList + "\nEOF"
+		case strings.Contains(call, "-c proxy"):
+			script = "echo 'Error from server: container proxy is terminated' >&2; exit 1"
+		case args[0] == "logs":
+			script = "echo '" + args[1] + "/" + args[3] + "'"
+		}
+		return exec.CommandContext(ctx, "sh", "-c", script)
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "logs")
+	result, err := dc.CollectNamespaceLogs(context.Background(), &CollectionOptions{
+		Namespace: "shop",
+		OutputDir: outputDir,
+		Filters:   map[string]string{"selector": "app=api", "workers": "3", "previous": "true", "since": "1h"},
+	})
+	if err != nil {
+		t.Fatalf("CollectNamespaceLogs() error = %v", err)
+	}
+	if result.Metadata["pods"] != "2" || result.Metadata["containers"] != "4" || result.Metadata["failed"] != "1" || result.Metadata["files_collected"] != "4" {
+		t.Errorf("CollectNamespaceLogs() metadata = %v, expected 2 pods, 4 containers, 4 logs and 1 failure", result.Metadata)
+	}
+	if calls[0] != "get pods -n shop -o json -l app=api" {
+		t.Errorf("pod listing = %q", calls[0])
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, LogManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest LogManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.TotalBytes != result.Size || len(manifest.Files) != 5 {
+		t.Errorf("manifest = %+v, expected 5 entries totalling %d bytes", manifest, result.Size)
+	}
+	if first := manifest.Files[0]; first.Pod != "api-1" || first.Container != "migrate" || !first.Init {
+		t.Errorf("first manifest entry = %+v, expected the init container of api-1", first)
+	}
+
+	expected := map[string]string{
+		"api-1/migrate.log":      "api-1/migrate\n",
+		"api-1/api.log":          "api-1/api\n",
+		"api-1/api-previous.log": "api-1/api\n",
+		"api-2/api.log":          "api-2/api\n",
+	}
+	for _, entry := range manifest.Files {
+		if entry.Container == "proxy" {
+			if entry.Error == "" || !strings.Contains(entry.Error, "container proxy is terminated") || entry.File != "" {
+				t.Errorf("failed entry = %+v, expected the oc error and no file", entry)
+			}
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, entry.File))
+		if err != nil || string(content) != expected[entry.File] || entry.Bytes != int64(len(content)) {
+			t.Errorf("%s = %q (%d bytes), %v, expected %q", entry.File, content, entry.Bytes, err, expected[entry.File])
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "api-1", "proxy.log")); !os.IsNotExist(err) {
+		t.Errorf("the failed log left a file behind: %v", err)
+	}
+	for _, call := range calls[1:] {
+		if !strings.Contains(call, "--since=1h") || !strings.Contains(call, "-n shop") {
+			t.Errorf("log call %q missing the namespace or since", call)
+		}
+	}
+
+	if _, err := dc.CollectNamespaceLogs(context.Background(), &CollectionOptions{Namespace: "shop", Filters: map[string]string{"workers": "100"}}); err == nil {
+		t.Error("CollectNamespaceLogs() with 100 workers returned no error")
+	}
+}
@@ -0,0 +1,259 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Log formats recognised by the analyzer
+const (
+	LogFormatPlain         = "plain"
+	LogFormatJournalExport = "journal-export"
+	LogFormatJournalJSON   = "journal-json"
+)
+
+// logExtensions are the plaintext log suffixes the analyzer reads
+var logExtensions = []string{".log", ".txt", ".journal", ".export"}
+
+// compressionExtensions are stripped before matching log suffixes
+var compressionExtensions = []string{".gz", ".zst"}
+
+// isLogFile reports whether a path looks like a log the analyzer can read,
+// including compressed logs and journalctl output collected by sosreport.
+func isLogFile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	for _, ext := range compressionExtensions {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	if strings.HasPrefix(name, "journalctl") {
+		return true
+	}
+	for _, ext := range logExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// logFile is an opened log with decompression and journal decoding applied
+type logFile struct {
+	io.Reader
+	Format  string
+	closers []func() error
+}
+
+// Close releases the decompressor and the underlying file
+func (l *logFile) Close() error {
+	var firstErr error
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if err := l.closers[i](); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// openLogFile opens a log file, transparently decompressing .gz and .zst
+// files and converting systemd journal export or JSON output into one
+// plaintext line per entry.
+func openLogFile(path string) (*logFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	lf := &logFile{Reader: file, Format: LogFormatPlain, closers: []func() error{file.Close}}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz":
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			lf.Close()
+			return nil, fmt.Errorf("failed to open gzip log %s: %v", path, err)
+		}
+		lf.Reader = gz
+		lf.closers = append(lf.closers, gz.Close)
+	case ".zst":
+		zr, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			lf.Close()
+			return nil, fmt.Errorf("failed to open zstd log %s: %v", path, err)
+		}
+		lf.Reader = zr
+		lf.closers = append(lf.closers, func() error { zr.Close(); return nil })
+	}
+
+	// Sniff the decompressed content for journal formats
+	buffered := bufio.NewReader(lf.Reader)
+	head, _ := buffered.Peek(64)
+	switch {
+	case bytes.HasPrefix(head, []byte("__CURSOR=")):
+		lf.Format = LogFormatJournalExport
+		lf.Reader = newJournalReader(buffered, readJournalExportEntry)
+	case bytes.HasPrefix(head, []byte(`{"__CURSOR"`)):
+		lf.Format = LogFormatJournalJSON
+		lf.Reader = newJournalReader(buffered, readJournalJSONEntry)
+	default:
+		lf.Reader = buffered
+	}
+
+	return lf, nil
+}
+
+// journalEntryFunc decodes the next journal entry from a reader
+type journalEntryFunc func(r *bufio.Reader) (map[string]string, error)
+
+// journalReader renders journal entries as "timestamp unit: message" lines
+type journalReader struct {
+	source *bufio.Reader
+	next   journalEntryFunc
+	buf    bytes.Buffer
+	err    error
+}
+
+func newJournalReader(source *bufio.Reader, next journalEntryFunc) *journalReader {
+	return &journalReader{source: source, next: next}
+}
+
+func (j *journalReader) Read(p []byte) (int, error) {
+	for j.buf.Len() == 0 {
+		if j.err != nil {
+			return 0, j.err
+		}
+		entry, err := j.next(j.source)
+		if err != nil {
+			j.err = err
+		}
+		if entry != nil {
+			j.buf.WriteString(formatJournalEntry(entry))
+			j.buf.WriteByte('\n')
+		}
+	}
+	return j.buf.Read(p)
+}
+
+// formatJournalEntry renders an entry the way journalctl's short-iso output does
+func formatJournalEntry(entry map[string]string) string {
+	var line strings.Builder
+	if usec, err := strconv.ParseInt(entry["__REALTIME_TIMESTAMP"], 10, 64); err == nil {
+		line.WriteString(time.UnixMicro(usec).UTC().Format(time.RFC3339))
+		line.WriteByte(' ')
+	}
+
+	source := entry["SYSLOG_IDENTIFIER"]
+	if source == "" {
+		source = entry["_SYSTEMD_UNIT"]
+	}
+	if source != "" {
+		line.WriteString(source)
+		if pid := entry["_PID"]; pid != "" {
+			line.WriteString("[" + pid + "]")
+		}
+		line.WriteString(": ")
+	}
+
+	// Keep messages on one line so line-based patterns still apply
+	line.WriteString(strings.ReplaceAll(entry["MESSAGE"], "\n", " "))
+	return line.String()
+}
+
+// readJournalExportEntry reads one entry of the journal export format: text
+// fields are FIELD=value lines, binary fields are a name line followed by a
+// little-endian 64-bit length and the raw data, and entries end with a blank line.
+func readJournalExportEntry(r *bufio.Reader) (map[string]string, error) {
+	entry := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && line == "" {
+			if len(entry) > 0 {
+				return entry, io.EOF
+			}
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		if line == "" {
+			if len(entry) > 0 {
+				return entry, nil
+			}
+			continue
+		}
+
+		if idx := strings.IndexByte(line, '='); idx >= 0 {
+			entry[line[:idx]] = line[idx+1:]
+			continue
+		}
+
+		// Binary field
+		var size uint64
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+			return nil, fmt.Errorf("invalid journal export field %s: %v", line, err)
+		}
+		if size > uint64(defaultMaxLineLength) {
+			if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+				return nil, err
+			}
+			entry[line] = ""
+		} else {
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, fmt.Errorf("invalid journal export field %s: %v", line, err)
+			}
+			entry[line] = string(data)
+		}
+		if _, err := r.ReadByte(); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+}
+
+// readJournalJSONEntry reads one line of journalctl -o json output
+func readJournalJSONEntry(r *bufio.Reader) (map[string]string, error) {
+	for {
+		line, err := r.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var raw map[string]interface{}
+		if jsonErr := json.Unmarshal(line, &raw); jsonErr != nil {
+			// Keep malformed lines visible to the pattern matcher
+			return map[string]string{"MESSAGE": string(line)}, err
+		}
+
+		entry := make(map[string]string, len(raw))
+		for key, value := range raw {
+			switch v := value.(type) {
+			case string:
+				entry[key] = v
+			case []interface{}:
+				// Non-UTF-8 messages are exported as byte arrays
+				data := make([]byte, 0, len(v))
+				for _, b := range v {
+					if n, ok := b.(float64); ok {
+						data = append(data, byte(n))
+					}
+				}
+				entry[key] = string(data)
+			}
+		}
+		return entry, err
+	}
+}
@@ -0,0 +1,125 @@
+package diagnostics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestIsLogFile(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"/tmp/app.log", true},
+		{"/tmp/app.log.gz", true},
+		{"/tmp/kubelet.txt.zst", true},
+		{"/tmp/sos_commands/logs/journalctl_--no-pager", true},
+		{"/tmp/node.journal", true},
+		{"/tmp/pods.yaml", false},
+		{"/tmp/archive.tar.gz", false},
+	}
+
+	for _, tc := range testCases {
+		if result := isLogFile(tc.path); result != tc.expected {
+			t.Errorf("isLogFile(%q) = %v, expected %v", tc.path, result, tc.expected)
+		}
+	}
+}
+
+func TestOpenLogFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	plain := "dial tcp 10.0.0.1:5432: connection refused\n"
+
+	var gz bytes.Buffer
+	gzw := gzip.NewWriter(&gz)
+	gzw.Write([]byte(plain))
+	gzw.Close()
+
+	var zst bytes.Buffer
+	zw, err := zstd.NewWriter(&zst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw.Write([]byte(plain))
+	zw.Close()
+
+	// Journal export with a text entry and a binary MESSAGE field
+	var export bytes.Buffer
+	export.WriteString("__CURSOR=s=1\n__REALTIME_TIMESTAMP=1700000000000000\nSYSLOG_IDENTIFIER=kubelet\nMESSAGE=Starting kubelet\n\n")
+	export.WriteString("__CURSOR=s=2\n__REALTIME_TIMESTAMP=1700000001000000\nSYSLOG_IDENTIFIER=kubelet\nMESSAGE\n")
+	message := []byte("dial tcp: connection refused\nretrying")
+	binary.Write(&export, binary.LittleEndian, uint64(len(message)))
+	export.Write(message)
+	export.WriteString("\n\n")
+
+	json := `{"__CURSOR":"s=1","__REALTIME_TIMESTAMP":"1700000000000000","_SYSTEMD_UNIT":"crio.service","MESSAGE":"no space left on device"}` + "\n"
+
+	testCases := []struct {
+		name     string
+		content  []byte
+		format   string
+		expected string
+	}{
+		{"app.log", []byte(plain), LogFormatPlain, plain},
+		{"app.log.gz", gz.Bytes(), LogFormatPlain, plain},
+		{"app.log.zst", zst.Bytes(), LogFormatPlain, plain},
+		{"node.export", export.Bytes(), LogFormatJournalExport,
+			"2023-11-14T22:13:20Z kubelet: Starting kubelet\n2023-11-14T22:13:21Z kubelet: dial tcp: connection refused retrying\n"},
+		{"journalctl_-o_json", []byte(json), LogFormatJournalJSON,
+			"2023-11-14T22:13:20Z crio.service: no space left on device\n"},
+	}
+
+	for _, tc := range testCases {
+		path := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(path, tc.content, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := openLogFile(path)
+		if err != nil {
+			t.Errorf("openLogFile(%q) returned %v", tc.name, err)
+			continue
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			t.Errorf("reading %q returned %v", tc.name, err)
+			continue
+		}
+		if file.Format != tc.format {
+			t.Errorf("openLogFile(%q).Format = %s, expected %s", tc.name, file.Format, tc.format)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("openLogFile(%q) content = %q, expected %q", tc.name, data, tc.expected)
+		}
+	}
+}
+
+func TestAnalyzeLogsReadsCompressedLogs(t *testing.T) {
+	dir := t.TempDir()
+	var gz bytes.Buffer
+	gzw := gzip.NewWriter(&gz)
+	gzw.Write([]byte("dial tcp 10.0.0.1:5432: connection refused\n"))
+	gzw.Close()
+	if err := os.WriteFile(filepath.Join(dir, "app.log.gz"), gz.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Errorf("found %d issues in a gzip log, expected 1", len(result.Issues))
+	}
+}
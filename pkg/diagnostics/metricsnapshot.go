@@ -0,0 +1,283 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MetricsSnapshotFile holds a metrics snapshot; analysis places the
+	// spikes of any snapshot it finds on the incident timeline
+	MetricsSnapshotFile = "metrics.json"
+
+	// spikeDeviations is how many standard deviations above a series' mean a
+	// sample must be to count as a spike
+	spikeDeviations = 3.0
+
+	// maxSpikesPerQuery bounds the timeline events one query contributes
+	maxSpikesPerQuery = 20
+)
+
+// MetricQuery is one named PromQL range query of a snapshot
+type MetricQuery struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// MetricSeries is one series of a range query result
+type MetricSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values []MetricPoint     `json:"values"`
+}
+
+// MetricPoint is one sample of a series
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricQueryResult is a query's series, or the error it failed with
+type MetricQueryResult struct {
+	MetricQuery
+	Series []MetricSeries `json:"series,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// MetricsSnapshot is the content of a metrics snapshot file
+type MetricsSnapshot struct {
+	Querier     string              `json:"querier"`
+	Namespace   string              `json:"namespace,omitempty"`
+	Start       time.Time           `json:"start"`
+	End         time.Time           `json:"end"`
+	Step        string              `json:"step"`
+	CollectedAt time.Time           `json:"collected_at"`
+	Queries     []MetricQueryResult `json:"queries"`
+}
+
+// RangeQueryFunc runs a PromQL range query; the caller knows how to reach
+// and authenticate to the querier
+type RangeQueryFunc func(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]MetricSeries, error)
+
+// MetricsSnapshotRange is the window and resolution of a snapshot
+type MetricsSnapshotRange struct {
+	Start, End time.Time
+	Step       time.Duration
+	Querier    string // recorded in the snapshot
+}
+
+// CollectMetricsSnapshot runs each query over the range and writes the
+// results to metrics.json in opts.OutputDir, or a new directory under the
+// working directory, so the snapshot sits with the logs and must-gathers it
+// explains. A query that fails is recorded with its error; the collection
+// fails only when every query does.
+func (dc *DiagnosticCollector) CollectMetricsSnapshot(ctx context.Context, opts *CollectionOptions, queries []MetricQuery, window MetricsSnapshotRange, query RangeQueryFunc) (*CollectionResult, error) {
+	start := time.Now()
+	result := &CollectionResult{
+		Type:     "metrics",
+		Metadata: make(map[string]string),
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no metrics queries to run")
+	}
+	if !window.End.After(window.Start) || window.Step <= 0 {
+		return nil, fmt.Errorf("invalid range %s to %s at step %s", window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339), window.Step)
+	}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("metrics-%d", start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	snapshot := MetricsSnapshot{
+		Querier:     window.Querier,
+		Namespace:   opts.Namespace,
+		Start:       window.Start.UTC(),
+		End:         window.End.UTC(),
+		Step:        window.Step.String(),
+		CollectedAt: start.UTC(),
+	}
+	failed, series := 0, 0
+	for _, q := range queries {
+		entry := MetricQueryResult{MetricQuery: q}
+		found, err := query(ctx, q.Query, window.Start, window.End, window.Step)
+		if err != nil {
+			entry.Error = err.Error()
+			failed++
+		}
+		entry.Series = found
+		series += len(found)
+		snapshot.Queries = append(snapshot.Queries, entry)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(outputDir, MetricsSnapshotFile)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+
+	result.FilePath = path
+	result.Size = int64(len(data))
+	result.Duration = time.Since(start)
+	result.Metadata["queries"] = strconv.Itoa(len(queries))
+	result.Metadata["failed"] = strconv.Itoa(failed)
+	result.Metadata["series"] = strconv.Itoa(series)
+	result.Metadata["spikes"] = strconv.Itoa(len(snapshot.Spikes()))
+	result.Metadata["namespace"] = opts.Namespace
+	if failed == len(queries) {
+		result.Status = "failed"
+		result.ErrorMsg = snapshot.Queries[0].Error
+		return result, fmt.Errorf("all %d metrics queries failed, the first with: %s", failed, result.ErrorMsg)
+	}
+
+	result.Status = "completed"
+	result.Summary = fmt.Sprintf("Captured %d series from %d queries (%d failed) over %s to %s in %s",
+		series, len(queries), failed, snapshot.Start.Format(time.RFC3339), snapshot.End.Format(time.RFC3339), path)
+	dc.finishCollection("", result, opts)
+	dc.logger.Infof("Metrics snapshot completed: %s", result.Summary)
+	return result, nil
+}
+
+// ReadMetricsSnapshot reads a snapshot file
+func ReadMetricsSnapshot(path string) (*MetricsSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot MetricsSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return &snapshot, nil
+}
+
+// Spikes returns the samples that rose well above their series' norm, as
+// timeline events: the first sample of each run more than three standard
+// deviations above the series mean
+func (m *MetricsSnapshot) Spikes() []TimelineEvent {
+	var events []TimelineEvent
+	for _, q := range m.Queries {
+		var found []TimelineEvent
+		for _, series := range q.Series {
+			found = append(found, seriesSpikes(q.Name, series)...)
+		}
+		sort.SliceStable(found, func(i, j int) bool { return found[i].Time.Before(found[j].Time) })
+		if len(found) > maxSpikesPerQuery {
+			found = found[:maxSpikesPerQuery]
+		}
+		events = append(events, found...)
+	}
+	return events
+}
+
+// seriesSpikes finds the spikes of one series
+func seriesSpikes(name string, series MetricSeries) []TimelineEvent {
+	if len(series.Values) < 3 {
+		return nil
+	}
+	var sum, squares float64
+	for _, point := range series.Values {
+		sum += point.Value
+		squares += point.Value * point.Value
+	}
+	n := float64(len(series.Values))
+	mean := sum / n
+	deviation := math.Sqrt(math.Max(squares/n-mean*mean, 0))
+	if deviation == 0 {
+		return nil
+	}
+	threshold := mean + spikeDeviations*deviation
+
+	var events []TimelineEvent
+	above := false
+	for _, point := range series.Values {
+		if point.Value <= threshold {
+			above = false
+			continue
+		}
+		if above {
+			continue
+		}
+		above = true
+		events = append(events, TimelineEvent{
+			Time:     point.Time,
+			Source:   TimelineSourceMetric,
+			Origin:   seriesOrigin(series.Metric),
+			Category: "metrics",
+			Severity: "warning",
+			Summary: fmt.Sprintf("%s spiked to %s on %s (mean %s)", name, formatMetricValue(point.Value),
+				seriesLabels(series.Metric), formatMetricValue(mean)),
+		})
+	}
+	return events
+}
+
+// seriesOrigin names where a series comes from, matching the namespaces,
+// pods and nodes other timeline events name as their origin
+func seriesOrigin(metric map[string]string) string {
+	for _, label := range []string{"namespace", "node", "instance"} {
+		if value := metric[label]; value != "" {
+			return value
+		}
+	}
+	return metric["__name__"]
+}
+
+// seriesLabels renders a series' identifying labels, e.g. namespace/pod
+func seriesLabels(metric map[string]string) string {
+	var parts []string
+	for _, label := range []string{"namespace", "pod", "container", "node", "instance"} {
+		if value := metric[label]; value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) == 0 {
+		return "{" + strings.TrimSpace(fmt.Sprint(metric)) + "}"
+	}
+	return strings.Join(parts, "/")
+}
+
+// formatMetricValue keeps values readable: 0.25, 1.5k, 734.2M
+func formatMetricValue(value float64) string {
+	abs := math.Abs(value)
+	switch {
+	case abs >= 1e9:
+		return strconv.FormatFloat(value/1e9, 'f', 1, 64) + "G"
+	case abs >= 1e6:
+		return strconv.FormatFloat(value/1e6, 'f', 1, 64) + "M"
+	case abs >= 1e3:
+		return strconv.FormatFloat(value/1e3, 'f', 1, 64) + "k"
+	}
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
+
+// collectMetricTimelines places the spikes of the metrics snapshots in or
+// directly below dir on the timeline
+func (ae *AnalysisEngine) collectMetricTimelines(dir string, result *AnalysisResult) {
+	paths, _ := filepath.Glob(filepath.Join(dir, MetricsSnapshotFile))
+	nested, _ := filepath.Glob(filepath.Join(dir, "*", MetricsSnapshotFile))
+	paths = append(paths, nested...)
+
+	for _, path := range paths {
+		snapshot, err := ReadMetricsSnapshot(path)
+		if err != nil {
+			ae.logger.Warnf("Failed to read metrics snapshot %s: %v", path, err)
+			continue
+		}
+		result.events = append(result.events, snapshot.Spikes()...)
+		result.Metrics["metrics_snapshots"] = len(paths)
+	}
+}
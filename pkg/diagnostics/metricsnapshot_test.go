@@ -0,0 +1,113 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memorySeries is a flat series with one spike at spikeAt
+func memorySeries(pod string, start time.Time, spikeAt int) MetricSeries {
+	series := MetricSeries{Metric: map[string]string{"namespace": "shop", "pod": pod}}
+	for i := 0; i < 30; i++ {
+		value := 200e6
+		if i == spikeAt {
+			value = 900e6
+		}
+		series.Values = append(series.Values, MetricPoint{Time: start.Add(time.Duration(i) * time.Minute), Value: value})
+	}
+	return series
+}
+
+func TestCollectMetricsSnapshot(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+	start := time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC)
+	window := MetricsSnapshotRange{Start: start, End: start.Add(30 * time.Minute), Step: time.Minute, Querier: "https://thanos"}
+
+	var asked []string
+	query := func(ctx context.Context, q string, from, to time.Time, step time.Duration) ([]MetricSeries, error) {
+		asked = append(asked, q)
+		if strings.HasPrefix(q, "bad") {
+			return nil, errors.New("bad_data: parse error")
+		}
+		return []MetricSeries{memorySeries("web-1", from, 22), memorySeries("web-2", from, -1)}, nil
+	}
+	queries := []MetricQuery{{Name: "memory", Query: "container_memory_working_set_bytes"}, {Name: "broken", Query: "bad("}}
+
+	outputDir := filepath.Join(t.TempDir(), "logs-shop")
+	result, err := dc.CollectMetricsSnapshot(context.Background(), &CollectionOptions{Namespace: "shop", OutputDir: outputDir}, queries, window, query)
+	if err != nil {
+		t.Fatalf("CollectMetricsSnapshot() error = %v", err)
+	}
+	if result.FilePath != filepath.Join(outputDir, MetricsSnapshotFile) || result.Metadata["series"] != "2" || result.Metadata["failed"] != "1" || result.Metadata["spikes"] != "1" {
+		t.Errorf("CollectMetricsSnapshot() = %+v", result)
+	}
+	if _, ok := dc.Artifacts().Get(result.Metadata["artifact_id"]); !ok {
+		t.Error("the snapshot was not registered as an artifact")
+	}
+
+	snapshot, err := ReadMetricsSnapshot(result.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Queries[1].Error != "bad_data: parse error" || snapshot.Step != "1m0s" || snapshot.Querier != "https://thanos" {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+	spikes := snapshot.Spikes()
+	if len(spikes) != 1 || !spikes[0].Time.Equal(start.Add(22*time.Minute)) || spikes[0].Origin != "shop" ||
+		spikes[0].Summary != "memory spiked to 900.0M on shop/web-1 (mean 223.3M)" {
+		t.Errorf("Spikes() = %+v", spikes)
+	}
+
+	queries = []MetricQuery{{Name: "broken", Query: "bad("}}
+	if _, err := dc.CollectMetricsSnapshot(context.Background(), &CollectionOptions{}, queries, window, query); err == nil || !strings.Contains(err.Error(), "all 1 metrics queries failed") {
+		t.Errorf("CollectMetricsSnapshot() with every query failing error = %v", err)
+	}
+	window.End = window.Start
+	if _, err := dc.CollectMetricsSnapshot(context.Background(), &CollectionOptions{}, queries, window, query); err == nil {
+		t.Error("CollectMetricsSnapshot() accepted an empty range")
+	}
+}
+
+func TestAnalyzeLogsCorrelatesMetricSpikes(t *testing.T) {
+	dir := t.TempDir()
+	logs := "2024-05-01T10:03:00Z ERROR backend web: connection refused\n"
+	if err := os.WriteFile(filepath.Join(dir, "router.log"), []byte(logs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+	start := time.Date(2024, 5, 1, 9, 40, 0, 0, time.UTC)
+	window := MetricsSnapshotRange{Start: start, End: start.Add(30 * time.Minute), Step: time.Minute}
+	query := func(ctx context.Context, q string, from, to time.Time, step time.Duration) ([]MetricSeries, error) {
+		return []MetricSeries{memorySeries("web-1", from, 22)}, nil
+	}
+	if _, err := dc.CollectMetricsSnapshot(context.Background(), &CollectionOptions{OutputDir: filepath.Join(dir, "metrics")},
+		[]MetricQuery{{Name: "memory", Query: "container_memory_working_set_bytes"}}, window, query); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	if len(result.Timeline) != 2 || result.Timeline[0].Source != TimelineSourceMetric {
+		t.Fatalf("timeline = %+v, expected the memory spike before the log error", result.Timeline)
+	}
+	if len(result.Correlations) != 1 || !strings.Contains(result.Correlations[0].Description, "memory spiked to 900.0M on shop/web-1") ||
+		!strings.Contains(result.Correlations[0].Description, "preceded Connection Refused at 10:03:00") {
+		t.Errorf("correlations = %+v, expected the spike to precede the connection errors", result.Correlations)
+	}
+}
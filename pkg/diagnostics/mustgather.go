@@ -0,0 +1,398 @@
+package diagnostics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMustGatherImage is used when no image is requested
+const DefaultMustGatherImage = "registry.redhat.io/openshift4/ose-must-gather:latest"
+
+// mustGatherLogFile receives the full oc output next to the collected data
+const mustGatherLogFile = "must-gather.log"
+
+// mustGatherTailLines is how many output lines a job keeps for progress reports
+const mustGatherTailLines = 5
+
+// Must-gather job states
+const (
+	MustGatherRunning   = "running"
+	MustGatherCompleted = "completed"
+	MustGatherFailed    = "failed"
+	MustGatherCancelled = "cancelled"
+)
+
+// MustGatherJob is a must-gather collection running in the background.
+// Jobs outlive the tool call that started them and are polled by ID.
+type MustGatherJob struct {
+	ID         string    `json:"id"`
+	Status     string    `json:"status"`
+	Phase      string    `json:"phase"`
+	Images     []string  `json:"images"`
+	NodeName   string    `json:"node_name,omitempty"`
+	Since      string    `json:"since,omitempty"`
+	DestDir    string    `json:"dest_dir"`
+	Command    string    `json:"command"`
+	Started    time.Time `json:"started"`
+	Finished   time.Time `json:"finished,omitempty"`
+	Size       int64     `json:"size"`
+	Files      int       `json:"files"`
+	Lines      int       `json:"lines"`
+	LastOutput []string  `json:"last_output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Done reports whether the job has finished, successfully or not
+func (j *MustGatherJob) Done() bool {
+	return j.Status != MustGatherRunning
+}
+
+// commandFunc builds the command a collection runs, e.g. a fake in tests
+type commandFunc func(ctx context.Context, name string, args ...string) *exec.Cmd
+
+// mustGatherJobs tracks the collector's must-gather jobs
+type mustGatherJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*MustGatherJob
+	seq  int
+}
+
+// mustGatherArgs builds the oc adm must-gather arguments
+func mustGatherArgs(images []string, destDir, nodeName, since, timeout string) []string {
+	args := []string{"adm", "must-gather", "--dest-dir=" + destDir}
+	for _, image := range images {
+		args = append(args, "--image="+image)
+	}
+	if nodeName != "" {
+		args = append(args, "--node-name="+nodeName)
+	}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	if timeout != "" {
+		args = append(args, "--timeout="+timeout)
+	}
+	return args
+}
+
+// mustGatherPhase guesses how far a collection got from an output line
+func mustGatherPhase(current, line string) string {
+	switch {
+	case strings.Contains(line, "receiving incremental file list"), strings.Contains(line, "Downloading gather output"):
+		return "copying"
+	case strings.HasPrefix(line, "[must-gather-"):
+		if current == "copying" {
+			return current
+		}
+		return "gathering"
+	case strings.Contains(line, "namespace/openshift-must-gather"), strings.Contains(line, "clusterrolebinding"):
+		if current == "starting" {
+			return "creating pod"
+		}
+	}
+	return current
+}
+
+// prepareDestDir creates a must-gather destination, refusing one that
+// already holds data so collections never mix
+func prepareDestDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	switch {
+	case err == nil && len(entries) > 0:
+		return fmt.Errorf("destination %s is not empty", dir)
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("checking destination %s: %v", dir, err)
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// MustGatherRunner collects must-gather data into destDir, passing every
+// output line to output. ctx ends when the job is cancelled or times out.
+type MustGatherRunner func(ctx context.Context, destDir string, output func(line string)) error
+
+// mustGatherImages reads the comma-separated "image" filter
+func mustGatherImages(opts *CollectionOptions) []string {
+	var images []string
+	for _, image := range strings.Split(opts.Filters["image"], ",") {
+		if image = strings.TrimSpace(image); image != "" {
+			images = append(images, image)
+		}
+	}
+	if len(images) == 0 {
+		images = []string{DefaultMustGatherImage}
+	}
+	return images
+}
+
+// StartMustGather runs oc adm must-gather in the background and returns the
+// job to poll. Images are taken from the comma-separated "image" filter,
+// and "since" and "timeout" filters are passed to oc.
+func (dc *DiagnosticCollector) StartMustGather(opts *CollectionOptions) (*MustGatherJob, error) {
+	if opts == nil {
+		opts = &CollectionOptions{}
+	}
+	images := mustGatherImages(opts)
+	var args []string
+	return dc.StartMustGatherWith(opts, func(destDir string) string {
+		args = mustGatherArgs(images, destDir, opts.NodeName, opts.Filters["since"], opts.Filters["timeout"])
+		return "oc " + strings.Join(args, " ")
+	}, func(ctx context.Context, destDir string, output func(line string)) error {
+		command := dc.command
+		if command == nil {
+			command = exec.CommandContext
+		}
+		cmd := command(ctx, "oc", args...)
+		cmd.WaitDelay = 10 * time.Second
+		reader, writer := io.Pipe()
+		cmd.Stdout = writer
+		cmd.Stderr = writer
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("starting oc adm must-gather: %v", err)
+		}
+		scanned := make(chan struct{})
+		go func() {
+			defer close(scanned)
+			scanner := bufio.NewScanner(reader)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				output(scanner.Text())
+			}
+			// Drain anything left after an overlong line so oc never blocks
+			io.Copy(io.Discard, reader)
+		}()
+		err := cmd.Wait()
+		writer.Close()
+		<-scanned
+		return err
+	})
+}
+
+// StartMustGatherWith runs a must-gather collection in the background with
+// the given runner and tracks it like StartMustGather does. describe returns
+// the command shown for the job, given its destination. Only one
+// must-gather runs at a time.
+func (dc *DiagnosticCollector) StartMustGatherWith(opts *CollectionOptions, describe func(destDir string) string, run MustGatherRunner) (*MustGatherJob, error) {
+	if opts == nil {
+		opts = &CollectionOptions{}
+	}
+	images := mustGatherImages(opts)
+
+	dc.mustGathers.mu.Lock()
+	defer dc.mustGathers.mu.Unlock()
+	if dc.mustGathers.jobs == nil {
+		dc.mustGathers.jobs = make(map[string]*MustGatherJob)
+	}
+	for _, job := range dc.mustGathers.jobs {
+		if !job.Done() {
+			return nil, fmt.Errorf("must-gather %s is still running; wait for it or cancel it first", job.ID)
+		}
+	}
+
+	started := time.Now()
+	dc.mustGathers.seq++
+	id := fmt.Sprintf("mg-%s-%d", started.Format("20060102-150405"), dc.mustGathers.seq)
+	destDir := filepath.Join(dc.workingDir, "must-gather", id)
+	if opts.OutputDir != "" {
+		destDir = filepath.Clean(opts.OutputDir)
+	}
+	if err := prepareDestDir(destDir); err != nil {
+		return nil, err
+	}
+	logFile, err := os.Create(filepath.Join(destDir, mustGatherLogFile))
+	if err != nil {
+		return nil, fmt.Errorf("creating %s: %v", mustGatherLogFile, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+	job := &MustGatherJob{
+		ID:       id,
+		Status:   MustGatherRunning,
+		Phase:    "starting",
+		Images:   images,
+		NodeName: opts.NodeName,
+		Since:    opts.Filters["since"],
+		DestDir:  destDir,
+		Command:  describe(destDir),
+		Started:  started,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	dc.mustGathers.jobs[id] = job
+	dc.logger.Infof("Started must-gather %s with image(s) %s into %s", id, strings.Join(images, ", "), destDir)
+
+	output := func(line string) {
+		fmt.Fprintln(logFile, line)
+		dc.mustGathers.mu.Lock()
+		defer dc.mustGathers.mu.Unlock()
+		job.Lines++
+		job.Phase = mustGatherPhase(job.Phase, line)
+		if strings.TrimSpace(line) != "" {
+			job.LastOutput = append(job.LastOutput, line)
+			if len(job.LastOutput) > mustGatherTailLines {
+				job.LastOutput = job.LastOutput[len(job.LastOutput)-mustGatherTailLines:]
+			}
+		}
+	}
+
+	go func() {
+		err := run(ctx, destDir, output)
+		ctxErr := ctx.Err()
+		cancel()
+		logFile.Close()
+		size, _ := dc.getDirSize(destDir)
+		files := countFiles(destDir)
+
+		dc.mustGathers.mu.Lock()
+		job.Finished = time.Now()
+		job.Size = size
+		job.Files = files
+		switch {
+		case ctxErr == context.Canceled:
+			job.Status = MustGatherCancelled
+			job.Error = "cancelled"
+		case ctxErr == context.DeadlineExceeded:
+			job.Status = MustGatherFailed
+			job.Error = fmt.Sprintf("timed out after %s", dc.timeout)
+		case err != nil:
+			job.Status = MustGatherFailed
+			job.Error = err.Error()
+		default:
+			job.Status = MustGatherCompleted
+		}
+		job.Phase = job.Status
+		dc.mustGathers.mu.Unlock()
+		if job.Status == MustGatherCompleted {
+			// Registered before waiters wake so CollectMustGather can
+			// replace the entry with its bundle
+			_, err := dc.artifacts.Register(id, &CollectionResult{
+				Type:     "must-gather",
+				FilePath: destDir,
+				Size:     size,
+				Summary:  fmt.Sprintf("Must-gather with image(s) %s", strings.Join(images, ", ")),
+				Metadata: map[string]string{"job_id": id, "command": job.Command},
+			})
+			if err != nil {
+				dc.logger.Warnf("Failed to register must-gather %s: %v", id, err)
+			}
+		}
+		close(job.done)
+		dc.logger.Infof("Must-gather %s %s in %s (%.2f MB)", id, job.Status, job.Finished.Sub(started).Round(time.Second), float64(size)/(1024*1024))
+	}()
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// countFiles counts the regular files under a directory
+func countFiles(dir string) int {
+	files := 0
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files++
+		}
+		return nil
+	})
+	return files
+}
+
+// MustGather returns a snapshot of a job. While it runs, the size and file
+// count are measured from what has been copied so far.
+func (dc *DiagnosticCollector) MustGather(id string) (*MustGatherJob, bool) {
+	dc.mustGathers.mu.Lock()
+	job, ok := dc.mustGathers.jobs[id]
+	if !ok {
+		dc.mustGathers.mu.Unlock()
+		return nil, false
+	}
+	snapshot := *job
+	snapshot.LastOutput = append([]string(nil), job.LastOutput...)
+	dc.mustGathers.mu.Unlock()
+
+	if !snapshot.Done() {
+		snapshot.Size, _ = dc.getDirSize(snapshot.DestDir)
+		snapshot.Files = countFiles(snapshot.DestDir)
+	}
+	return &snapshot, true
+}
+
+// MustGathers returns snapshots of all jobs, newest first
+func (dc *DiagnosticCollector) MustGathers() []*MustGatherJob {
+	dc.mustGathers.mu.Lock()
+	ids := make([]string, 0, len(dc.mustGathers.jobs))
+	for id := range dc.mustGathers.jobs {
+		ids = append(ids, id)
+	}
+	dc.mustGathers.mu.Unlock()
+
+	var jobs []*MustGatherJob
+	for _, id := range ids {
+		if job, ok := dc.MustGather(id); ok {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Started.After(jobs[j].Started) })
+	return jobs
+}
+
+// WaitMustGather blocks until a job finishes or ctx is done
+func (dc *DiagnosticCollector) WaitMustGather(ctx context.Context, id string) (*MustGatherJob, error) {
+	dc.mustGathers.mu.Lock()
+	job, ok := dc.mustGathers.jobs[id]
+	dc.mustGathers.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("must-gather %s not found", id)
+	}
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	snapshot, _ := dc.MustGather(id)
+	return snapshot, nil
+}
+
+// PruneMustGathers forgets jobs that finished more than age ago so the job
+// list stays bounded on long-running servers. The collected data is left
+// to the artifact retention policy. It returns how many jobs were pruned.
+func (dc *DiagnosticCollector) PruneMustGathers(age time.Duration) int {
+	dc.mustGathers.mu.Lock()
+	defer dc.mustGathers.mu.Unlock()
+	pruned := 0
+	for id, job := range dc.mustGathers.jobs {
+		if job.Done() && time.Since(job.Finished) > age {
+			delete(dc.mustGathers.jobs, id)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// CancelMustGather stops a running job; the data copied so far is kept
+func (dc *DiagnosticCollector) CancelMustGather(id string) error {
+	dc.mustGathers.mu.Lock()
+	job, ok := dc.mustGathers.jobs[id]
+	if !ok {
+		dc.mustGathers.mu.Unlock()
+		return fmt.Errorf("must-gather %s not found", id)
+	}
+	if job.Done() {
+		dc.mustGathers.mu.Unlock()
+		return fmt.Errorf("must-gather %s already %s", id, job.Status)
+	}
+	dc.mustGathers.mu.Unlock()
+	job.cancel()
+	<-job.done
+	return nil
+}
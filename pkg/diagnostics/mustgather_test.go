@@ -0,0 +1,130 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newFakeMustGather returns a collector whose oc runs script with the
+// destination directory as $1
+func newFakeMustGather(t *testing.T, script string) *DiagnosticCollector {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+	dc.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		var destDir string
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "--dest-dir=") {
+				destDir = strings.TrimPrefix(arg, "--dest-dir=")
+			}
+		}
+		return exec.CommandContext(ctx, "sh", "-c", script, "sh", destDir)
+	}
+	return dc
+}
+
+func TestMustGatherArgs(t *testing.T) {
+	args := mustGatherArgs([]string{"quay.io/a/mg", "quay.io/b/mg"}, "/tmp/mg", "worker-1", "2h", "")
+	expected := []string{"adm", "must-gather", "--dest-dir=/tmp/mg", "--image=quay.io/a/mg", "--image=quay.io/b/mg", "--node-name=worker-1", "--since=2h"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("mustGatherArgs() = %v, expected %v", args, expected)
+	}
+}
+
+func TestMustGatherPhase(t *testing.T) {
+	tests := []struct {
+		current, line, expected string
+	}{
+		{"starting", "[must-gather      ] OUT namespace/openshift-must-gather-x created", "creating pod"},
+		{"creating pod", "[must-gather-abcde] POD Gathering data for ns/openshift-etcd...", "gathering"},
+		{"gathering", "receiving incremental file list", "copying"},
+		{"copying", "[must-gather-abcde] OUT gather/cluster-scoped-resources/", "copying"},
+		{"gathering", "unrelated", "gathering"},
+	}
+	for _, tt := range tests {
+		if phase := mustGatherPhase(tt.current, tt.line); phase != tt.expected {
+			t.Errorf("mustGatherPhase(%q, %q) = %q, expected %q", tt.current, tt.line, phase, tt.expected)
+		}
+	}
+}
+
+func TestStartMustGather(t *testing.T) {
+	dc := newFakeMustGather(t, `echo "[must-gather-abcde] POD Gathering data"; mkdir -p "$1/quay-io-mg"; printf 'data' > "$1/quay-io-mg/version"; echo "receiving incremental file list"`)
+
+	job, err := dc.StartMustGather(&CollectionOptions{})
+	if err != nil {
+		t.Fatalf("StartMustGather() error = %v", err)
+	}
+	if job.Status != MustGatherRunning || job.DestDir != filepath.Join(dc.WorkingDir(), "must-gather", job.ID) {
+		t.Errorf("StartMustGather() = %+v", job)
+	}
+	if !strings.Contains(job.Command, "--image="+DefaultMustGatherImage) {
+		t.Errorf("StartMustGather() command %q does not use the default image", job.Command)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done, err := dc.WaitMustGather(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("WaitMustGather() error = %v", err)
+	}
+	if done.Status != MustGatherCompleted || done.Phase != MustGatherCompleted || done.Lines != 2 || done.Files != 2 {
+		t.Errorf("WaitMustGather() = %+v", done)
+	}
+	if log, _ := os.ReadFile(filepath.Join(done.DestDir, mustGatherLogFile)); string(log) != "[must-gather-abcde] POD Gathering data\nreceiving incremental file list\n" {
+		t.Errorf("%s = %q", mustGatherLogFile, log)
+	}
+	if done.Size != int64(len("data"))+int64(len(done.LastOutput[0])+len(done.LastOutput[1])+2) {
+		t.Errorf("WaitMustGather() size = %d", done.Size)
+	}
+
+	// A destination that already holds data is refused
+	if _, err := dc.StartMustGather(&CollectionOptions{OutputDir: done.DestDir}); err == nil || !strings.Contains(err.Error(), "is not empty") {
+		t.Errorf("StartMustGather() into a used directory error = %v", err)
+	}
+}
+
+func TestCancelMustGather(t *testing.T) {
+	dc := newFakeMustGather(t, `echo started; exec sleep 30`)
+
+	job, err := dc.StartMustGather(&CollectionOptions{})
+	if err != nil {
+		t.Fatalf("StartMustGather() error = %v", err)
+	}
+	if _, err := dc.StartMustGather(&CollectionOptions{}); err == nil || !strings.Contains(err.Error(), "is still running") {
+		t.Errorf("second StartMustGather() error = %v, expected the running job to block it", err)
+	}
+	if err := dc.CancelMustGather(job.ID); err != nil {
+		t.Fatalf("CancelMustGather() error = %v", err)
+	}
+	cancelled, _ := dc.MustGather(job.ID)
+	if cancelled.Status != MustGatherCancelled {
+		t.Errorf("MustGather() after cancel = %+v", cancelled)
+	}
+	if err := dc.CancelMustGather(job.ID); err == nil {
+		t.Errorf("CancelMustGather() of a finished job succeeded")
+	}
+	if jobs := dc.MustGathers(); len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Errorf("MustGathers() = %+v", jobs)
+	}
+
+	// Finished jobs are forgotten once they are older than the retention
+	if pruned := dc.PruneMustGathers(time.Hour); pruned != 0 {
+		t.Errorf("PruneMustGathers(1h) pruned %d fresh jobs", pruned)
+	}
+	if pruned := dc.PruneMustGathers(0); pruned != 1 || len(dc.MustGathers()) != 0 {
+		t.Errorf("PruneMustGathers(0) pruned %d, %d jobs left", pruned, len(dc.MustGathers()))
+	}
+	if _, err := os.Stat(cancelled.DestDir); err != nil {
+		t.Errorf("PruneMustGathers() removed the collected data: %v", err)
+	}
+}
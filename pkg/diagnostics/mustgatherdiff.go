@@ -0,0 +1,504 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Kinds of differences between two must-gathers
+const (
+	DiffRegression  = "regression"
+	DiffChange      = "change"
+	DiffImprovement = "improvement"
+)
+
+// MustGatherChange is one difference between two must-gathers
+type MustGatherChange struct {
+	Severity string `json:"severity"` // regression, change or improvement
+	Area     string `json:"area"`     // version, operators, nodes or pods
+	Message  string `json:"message"`
+}
+
+// MustGatherStats counts what a must-gather captured
+type MustGatherStats struct {
+	Version     string `json:"version,omitempty"`
+	Operators   int    `json:"operators"`
+	Degraded    int    `json:"degraded_operators"`
+	Nodes       int    `json:"nodes"`
+	NotReady    int    `json:"not_ready_nodes"`
+	Pods        int    `json:"pods"`
+	FailingPods int    `json:"failing_pods"`
+}
+
+// MustGatherDiff compares the cluster state of two must-gathers, e.g. taken
+// before and after an upgrade or incident
+type MustGatherDiff struct {
+	Before      string             `json:"before"`
+	After       string             `json:"after"`
+	BeforeStats MustGatherStats    `json:"before_stats"`
+	AfterStats  MustGatherStats    `json:"after_stats"`
+	Changes     []MustGatherChange `json:"changes"`
+}
+
+// mustGatherOperator is the part of a ClusterOperator the diff reads
+type mustGatherOperator struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+		Versions []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"status"`
+}
+
+// mustGatherClusterVersion is the part of a ClusterVersion the diff reads
+type mustGatherClusterVersion struct {
+	Status struct {
+		Desired struct {
+			Version string `json:"version"`
+		} `json:"desired"`
+	} `json:"status"`
+}
+
+// operatorSnapshot is the state of a ClusterOperator in a must-gather
+type operatorSnapshot struct {
+	version            string
+	available          bool
+	degraded           bool
+	degradedMessage    string
+	unavailableMessage string
+}
+
+// podSnapshot is a pod of a must-gather with what is failing about it
+type podSnapshot struct {
+	name     string // namespace/name
+	workload string // namespace/kind/name of the owning workload, or the pod
+	display  string // the workload for messages, e.g. Deployment api
+	failure  string // e.g. CrashLoopBackOff, empty when healthy
+}
+
+// mustGatherSnapshot is the cluster state a must-gather captured
+type mustGatherSnapshot struct {
+	version   string
+	operators map[string]operatorSnapshot
+	nodes     map[string]corev1.Node
+	pods      map[string]podSnapshot
+}
+
+// mustGatherRoot finds the directory holding the resources: the path
+// itself, or the image directory oc adm must-gather writes them under
+func mustGatherRoot(path string) (string, error) {
+	holdsResources := func(dir string) bool {
+		for _, name := range []string{"cluster-scoped-resources", "namespaces"} {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+				return true
+			}
+		}
+		return false
+	}
+	if holdsResources(path) {
+		return path, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && holdsResources(filepath.Join(path, entry.Name())) {
+			return filepath.Join(path, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a must-gather: no cluster-scoped-resources or namespaces directory", path)
+}
+
+// readMustGatherObjects reads the objects of a list file, e.g. nodes.yaml,
+// and of the per-object files in a directory, e.g. nodes/*.yaml; must-gathers
+// hold either
+func readMustGatherObjects[T any](listFile, objectDir string) ([]T, error) {
+	var objects []T
+	if data, err := os.ReadFile(listFile); err == nil {
+		var list struct {
+			Items []T `json:"items"`
+		}
+		if err := yaml.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("%s: %v", listFile, err)
+		}
+		objects = append(objects, list.Items...)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	files, _ := filepath.Glob(filepath.Join(objectDir, "*.yaml"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var object T
+		if err := yaml.Unmarshal(data, &object); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// podFailure says why a pod is failing, or "" for a healthy pod
+func podFailure(pod corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			switch waiting.Reason {
+			case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError", "CreateContainerError", "InvalidImageName", "RunContainerError":
+				return waiting.Reason
+			}
+		}
+	}
+	switch pod.Status.Phase {
+	case corev1.PodFailed:
+		if pod.Status.Reason != "" {
+			return pod.Status.Reason
+		}
+		return string(corev1.PodFailed)
+	case corev1.PodPending:
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+				return "Unschedulable"
+			}
+		}
+	}
+	return ""
+}
+
+// podWorkload names the workload owning a pod, so a failing pod recreated
+// under a new name is recognized as the same failure
+func podWorkload(pod corev1.Pod) (string, string) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		kind, name := owner.Kind, owner.Name
+		if hash := pod.Labels["pod-template-hash"]; kind == "ReplicaSet" && hash != "" && strings.HasSuffix(name, "-"+hash) {
+			kind, name = "Deployment", strings.TrimSuffix(name, "-"+hash)
+		}
+		return pod.Namespace + "/" + kind + "/" + name, fmt.Sprintf("%s %s", kind, name)
+	}
+	return pod.Namespace + "/Pod/" + pod.Name, "pod " + pod.Name
+}
+
+// readMustGatherSnapshot reads the operators, nodes and pods of a must-gather
+func readMustGatherSnapshot(path string) (*mustGatherSnapshot, error) {
+	root, err := mustGatherRoot(path)
+	if err != nil {
+		return nil, err
+	}
+	config := filepath.Join(root, "cluster-scoped-resources", "config.openshift.io")
+	snapshot := &mustGatherSnapshot{
+		operators: make(map[string]operatorSnapshot),
+		nodes:     make(map[string]corev1.Node),
+		pods:      make(map[string]podSnapshot),
+	}
+
+	versions, err := readMustGatherObjects[mustGatherClusterVersion](filepath.Join(config, "clusterversions.yaml"), filepath.Join(config, "clusterversions"))
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		snapshot.version = version.Status.Desired.Version
+	}
+
+	operators, err := readMustGatherObjects[mustGatherOperator](filepath.Join(config, "clusteroperators.yaml"), filepath.Join(config, "clusteroperators"))
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range operators {
+		state := operatorSnapshot{available: true}
+		for _, version := range operator.Status.Versions {
+			if version.Name == "operator" {
+				state.version = version.Version
+			}
+		}
+		for _, condition := range operator.Status.Conditions {
+			switch condition.Type {
+			case "Available":
+				state.available = condition.Status == "True"
+				if !state.available {
+					state.unavailableMessage = condition.Message
+				}
+			case "Degraded":
+				state.degraded = condition.Status == "True"
+				if state.degraded {
+					state.degradedMessage = condition.Message
+				}
+			}
+		}
+		snapshot.operators[operator.Metadata.Name] = state
+	}
+
+	core := filepath.Join(root, "cluster-scoped-resources", "core")
+	nodes, err := readMustGatherObjects[corev1.Node](filepath.Join(core, "nodes.yaml"), filepath.Join(core, "nodes"))
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		snapshot.nodes[node.Name] = node
+	}
+
+	namespaces, _ := filepath.Glob(filepath.Join(root, "namespaces", "*"))
+	for _, namespace := range namespaces {
+		// Per-pod files live in pods/<name>/<name>.yaml
+		pods, err := readMustGatherObjects[corev1.Pod](filepath.Join(namespace, "core", "pods.yaml"), "")
+		if err != nil {
+			return nil, err
+		}
+		podFiles, _ := filepath.Glob(filepath.Join(namespace, "pods", "*", "*.yaml"))
+		for _, file := range podFiles {
+			if strings.TrimSuffix(filepath.Base(file), ".yaml") != filepath.Base(filepath.Dir(file)) {
+				continue
+			}
+			more, err := readMustGatherObjects[corev1.Pod]("", filepath.Dir(file))
+			if err != nil {
+				return nil, err
+			}
+			pods = append(pods, more...)
+		}
+		for _, pod := range pods {
+			workload, display := podWorkload(pod)
+			name := pod.Namespace + "/" + pod.Name
+			snapshot.pods[name] = podSnapshot{name: name, workload: workload, display: display, failure: podFailure(pod)}
+		}
+	}
+	return snapshot, nil
+}
+
+// stats counts what a snapshot captured
+func (s *mustGatherSnapshot) stats() MustGatherStats {
+	stats := MustGatherStats{Version: s.version, Operators: len(s.operators), Nodes: len(s.nodes), Pods: len(s.pods)}
+	for _, operator := range s.operators {
+		if operator.degraded {
+			stats.Degraded++
+		}
+	}
+	for _, node := range s.nodes {
+		if nodeCondition(node, corev1.NodeReady) != corev1.ConditionTrue {
+			stats.NotReady++
+		}
+	}
+	for _, pod := range s.pods {
+		if pod.failure != "" {
+			stats.FailingPods++
+		}
+	}
+	return stats
+}
+
+// nodeCondition returns the status of a node condition, Unknown when unset
+func nodeCondition(node corev1.Node, conditionType corev1.NodeConditionType) corev1.ConditionStatus {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return corev1.ConditionUnknown
+}
+
+// nodePressures are the node conditions that are bad when True
+var nodePressures = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable,
+}
+
+// withMessage appends a condition message, when there is one
+func withMessage(text, message string) string {
+	if message = strings.TrimSpace(message); message != "" {
+		return text + ": " + message
+	}
+	return text
+}
+
+// diffMustGatherSnapshots lists the differences between two snapshots
+func diffMustGatherSnapshots(before, after *mustGatherSnapshot) []MustGatherChange {
+	var changes []MustGatherChange
+	add := func(severity, area, format string, args ...interface{}) {
+		changes = append(changes, MustGatherChange{Severity: severity, Area: area, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if before.version != after.version && before.version != "" && after.version != "" {
+		add(DiffChange, "version", "cluster version %s → %s", before.version, after.version)
+	}
+
+	// Operators
+	for _, name := range sortedNames(after.operators) {
+		now := after.operators[name]
+		was, existed := before.operators[name]
+		if !existed {
+			if now.degraded || !now.available {
+				add(DiffRegression, "operators", "%s is new and unhealthy (Available=%t, Degraded=%t)", name, now.available, now.degraded)
+			} else {
+				add(DiffChange, "operators", "%s is new", name)
+			}
+			continue
+		}
+		switch {
+		case now.degraded && !was.degraded:
+			add(DiffRegression, "operators", "%s", withMessage(name+" is now Degraded", now.degradedMessage))
+		case !now.degraded && was.degraded:
+			add(DiffImprovement, "operators", "%s is no longer Degraded", name)
+		}
+		switch {
+		case !now.available && was.available:
+			add(DiffRegression, "operators", "%s", withMessage(name+" is no longer Available", now.unavailableMessage))
+		case now.available && !was.available:
+			add(DiffImprovement, "operators", "%s is Available again", name)
+		}
+		if was.version != now.version && was.version != "" && now.version != "" {
+			add(DiffChange, "operators", "%s version %s → %s", name, was.version, now.version)
+		}
+	}
+	for _, name := range sortedNames(before.operators) {
+		if _, ok := after.operators[name]; !ok {
+			add(DiffRegression, "operators", "%s is missing", name)
+		}
+	}
+
+	// Nodes
+	for _, name := range sortedNames(after.nodes) {
+		now := after.nodes[name]
+		was, existed := before.nodes[name]
+		ready := nodeCondition(now, corev1.NodeReady)
+		if !existed {
+			if ready != corev1.ConditionTrue {
+				add(DiffRegression, "nodes", "%s is new and not Ready (Ready=%s)", name, ready)
+			} else {
+				add(DiffChange, "nodes", "%s is new", name)
+			}
+			continue
+		}
+		switch wasReady := nodeCondition(was, corev1.NodeReady); {
+		case wasReady == corev1.ConditionTrue && ready != corev1.ConditionTrue:
+			add(DiffRegression, "nodes", "%s is no longer Ready (Ready=%s)", name, ready)
+		case wasReady != corev1.ConditionTrue && ready == corev1.ConditionTrue:
+			add(DiffImprovement, "nodes", "%s is Ready again", name)
+		}
+		for _, pressure := range nodePressures {
+			wasSet, isSet := nodeCondition(was, pressure) == corev1.ConditionTrue, nodeCondition(now, pressure) == corev1.ConditionTrue
+			switch {
+			case isSet && !wasSet:
+				add(DiffRegression, "nodes", "%s now has %s", name, pressure)
+			case wasSet && !isSet:
+				add(DiffImprovement, "nodes", "%s no longer has %s", name, pressure)
+			}
+		}
+		switch {
+		case now.Spec.Unschedulable && !was.Spec.Unschedulable:
+			add(DiffChange, "nodes", "%s was cordoned", name)
+		case !now.Spec.Unschedulable && was.Spec.Unschedulable:
+			add(DiffChange, "nodes", "%s was uncordoned", name)
+		}
+		if was.Status.NodeInfo.KubeletVersion != now.Status.NodeInfo.KubeletVersion {
+			add(DiffChange, "nodes", "%s kubelet %s → %s", name, was.Status.NodeInfo.KubeletVersion, now.Status.NodeInfo.KubeletVersion)
+		}
+	}
+	for _, name := range sortedNames(before.nodes) {
+		if _, ok := after.nodes[name]; !ok {
+			add(DiffRegression, "nodes", "%s is missing", name)
+		}
+	}
+
+	// Pods, matched by their workload so recreated pods are recognized
+	failedBefore := make(map[string]bool)
+	for _, pod := range before.pods {
+		if pod.failure != "" {
+			failedBefore[pod.workload] = true
+		}
+	}
+	failingAfter := make(map[string]bool)
+	stillFailing := make(map[string]bool)
+	for _, name := range sortedNames(after.pods) {
+		pod := after.pods[name]
+		if pod.failure == "" {
+			continue
+		}
+		failingAfter[pod.workload] = true
+		if failedBefore[pod.workload] {
+			stillFailing[pod.workload] = true
+			continue
+		}
+		add(DiffRegression, "pods", "%s (%s) is failing: %s", name, pod.display, pod.failure)
+	}
+	recovered := make(map[string]bool)
+	for _, name := range sortedNames(before.pods) {
+		pod := before.pods[name]
+		if pod.failure == "" || failingAfter[pod.workload] || recovered[pod.workload] {
+			continue
+		}
+		recovered[pod.workload] = true
+		namespace := strings.SplitN(pod.workload, "/", 2)[0]
+		add(DiffImprovement, "pods", "%s in %s recovered (was %s)", pod.display, namespace, pod.failure)
+	}
+	if len(stillFailing) > 0 {
+		add(DiffChange, "pods", "%d workload(s) were already failing before and still are", len(stillFailing))
+	}
+	return changes
+}
+
+// sortedNames returns the keys of a map in order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffMustGathers compares two must-gathers, directories or bundles, and
+// reports new degraded operators, changed node conditions and newly failing
+// pods of the later one
+func (ae *AnalysisEngine) DiffMustGathers(ctx context.Context, beforePath, afterPath string) (*MustGatherDiff, error) {
+	read := func(path string) (*mustGatherSnapshot, error) {
+		if IsBundle(path) {
+			bundle, err := OpenBundle(path)
+			if err != nil {
+				return nil, err
+			}
+			defer bundle.Close()
+			path = bundle.Dir
+		}
+		return readMustGatherSnapshot(path)
+	}
+
+	before, err := read(beforePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", beforePath, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	after, err := read(afterPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", afterPath, err)
+	}
+
+	diff := &MustGatherDiff{
+		Before:      beforePath,
+		After:       afterPath,
+		BeforeStats: before.stats(),
+		AfterStats:  after.stats(),
+		Changes:     diffMustGatherSnapshots(before, after),
+	}
+	ae.logger.Infof("Compared must-gathers %s and %s: %d changes", beforePath, afterPath, len(diff.Changes))
+	return diff, nil
+}
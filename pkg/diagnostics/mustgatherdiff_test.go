@@ -0,0 +1,196 @@
+package diagnostics
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const diffOperatorsBefore = `apiVersion: config.openshift.io/v1
+kind: ClusterOperatorList
+items:
+- metadata: {name: authentication}
+  status:
+    conditions:
+    - {type: Available, status: "True"}
+    - {type: Degraded, status: "False"}
+    versions: [{name: operator, version: 4.15.3}]
+- metadata: {name: dns}
+  status:
+    conditions:
+    - {type: Available, status: "True"}
+    - {type: Degraded, status: "True", message: "DNS default is degraded"}
+    versions: [{name: operator, version: 4.15.3}]
+`
+
+const diffNodesBefore = `apiVersion: v1
+kind: NodeList
+items:
+- metadata: {name: worker-0}
+  status:
+    conditions:
+    - {type: Ready, status: "True"}
+    - {type: DiskPressure, status: "False"}
+    nodeInfo: {kubeletVersion: v1.28.6}
+- metadata: {name: worker-1}
+  status:
+    conditions:
+    - {type: Ready, status: "True"}
+    nodeInfo: {kubeletVersion: v1.28.6}
+`
+
+const diffPodsBefore = `apiVersion: v1
+kind: PodList
+items:
+- metadata:
+    name: api-7d9f-abcde
+    namespace: shop
+    labels: {pod-template-hash: 7d9f}
+    ownerReferences: [{kind: ReplicaSet, name: api-7d9f, controller: true}]
+  status:
+    phase: Running
+- metadata:
+    name: cart-5c4b-fghij
+    namespace: shop
+    labels: {pod-template-hash: 5c4b}
+    ownerReferences: [{kind: ReplicaSet, name: cart-5c4b, controller: true}]
+  status:
+    phase: Running
+    containerStatuses:
+    - name: cart
+      state: {waiting: {reason: CrashLoopBackOff}}
+- metadata:
+    name: worker-0
+    namespace: shop
+    ownerReferences: [{kind: StatefulSet, name: worker, controller: true}]
+  status:
+    phase: Running
+    containerStatuses:
+    - name: worker
+      state: {waiting: {reason: ImagePullBackOff}}
+`
+
+// The after must-gather uses per-object files as newer must-gathers do
+const diffOperatorAuthentication = `metadata: {name: authentication}
+status:
+  conditions:
+  - {type: Available, status: "False", message: "OAuth server is unreachable"}
+  - {type: Degraded, status: "True", message: "OAuthServerRouteEndpointAccessibleControllerDegraded"}
+  versions: [{name: operator, version: 4.16.1}]
+`
+
+const diffOperatorDNS = `metadata: {name: dns}
+status:
+  conditions:
+  - {type: Available, status: "True"}
+  - {type: Degraded, status: "False"}
+  versions: [{name: operator, version: 4.16.1}]
+`
+
+const diffNodeWorker0 = `metadata: {name: worker-0}
+spec: {unschedulable: true}
+status:
+  conditions:
+  - {type: Ready, status: "False"}
+  - {type: DiskPressure, status: "True"}
+  nodeInfo: {kubeletVersion: v1.29.5}
+`
+
+const diffPodsAfter = `apiVersion: v1
+kind: PodList
+items:
+- metadata:
+    name: api-8e1a-klmno
+    namespace: shop
+    labels: {pod-template-hash: 8e1a}
+    ownerReferences: [{kind: ReplicaSet, name: api-8e1a, controller: true}]
+  status:
+    phase: Running
+    containerStatuses:
+    - name: api
+      state: {waiting: {reason: CrashLoopBackOff}}
+- metadata:
+    name: cart-6d5c-pqrst
+    namespace: shop
+    labels: {pod-template-hash: 6d5c}
+    ownerReferences: [{kind: ReplicaSet, name: cart-6d5c, controller: true}]
+  status:
+    phase: Running
+- metadata:
+    name: worker-0
+    namespace: shop
+    ownerReferences: [{kind: StatefulSet, name: worker, controller: true}]
+  status:
+    phase: Running
+    containerStatuses:
+    - name: worker
+      state: {waiting: {reason: ImagePullBackOff}}
+`
+
+const diffPodReport = `metadata:
+  name: report-x1
+  namespace: batch
+status:
+  phase: Pending
+  conditions:
+  - {type: PodScheduled, status: "False", reason: Unschedulable}
+`
+
+func TestDiffMustGathers(t *testing.T) {
+	before := t.TempDir()
+	writeBenchFile(t, filepath.Join(before, "cluster-scoped-resources", "config.openshift.io", "clusterversions.yaml"), []byte("items:\n- status:\n    desired:\n      version: 4.15.3\n"))
+	writeBenchFile(t, filepath.Join(before, "cluster-scoped-resources", "config.openshift.io", "clusteroperators.yaml"), []byte(diffOperatorsBefore))
+	writeBenchFile(t, filepath.Join(before, "cluster-scoped-resources", "core", "nodes.yaml"), []byte(diffNodesBefore))
+	writeBenchFile(t, filepath.Join(before, "namespaces", "shop", "core", "pods.yaml"), []byte(diffPodsBefore))
+
+	// oc adm must-gather writes the resources under a directory per image
+	after := t.TempDir()
+	root := filepath.Join(after, "quay-io-openshift-release-dev-ocp-v4-0-art-dev-sha256-abc")
+	config := filepath.Join(root, "cluster-scoped-resources", "config.openshift.io")
+	writeBenchFile(t, filepath.Join(config, "clusterversions", "version.yaml"), []byte("status:\n  desired:\n    version: 4.16.1\n"))
+	writeBenchFile(t, filepath.Join(config, "clusteroperators", "authentication.yaml"), []byte(diffOperatorAuthentication))
+	writeBenchFile(t, filepath.Join(config, "clusteroperators", "dns.yaml"), []byte(diffOperatorDNS))
+	writeBenchFile(t, filepath.Join(root, "cluster-scoped-resources", "core", "nodes", "worker-0.yaml"), []byte(diffNodeWorker0))
+	writeBenchFile(t, filepath.Join(root, "namespaces", "shop", "core", "pods.yaml"), []byte(diffPodsAfter))
+	writeBenchFile(t, filepath.Join(root, "namespaces", "batch", "pods", "report-x1", "report-x1.yaml"), []byte(diffPodReport))
+
+	diff, err := newTestEngine().DiffMustGathers(context.Background(), before, after)
+	if err != nil {
+		t.Fatalf("DiffMustGathers() error = %v", err)
+	}
+
+	var got []MustGatherChange
+	for _, change := range diff.Changes {
+		if change.Area != "version" {
+			got = append(got, change)
+		}
+	}
+	expected := []MustGatherChange{
+		{DiffRegression, "operators", "authentication is now Degraded: OAuthServerRouteEndpointAccessibleControllerDegraded"},
+		{DiffRegression, "operators", "authentication is no longer Available: OAuth server is unreachable"},
+		{DiffChange, "operators", "authentication version 4.15.3 → 4.16.1"},
+		{DiffImprovement, "operators", "dns is no longer Degraded"},
+		{DiffChange, "operators", "dns version 4.15.3 → 4.16.1"},
+		{DiffRegression, "nodes", "worker-0 is no longer Ready (Ready=False)"},
+		{DiffRegression, "nodes", "worker-0 now has DiskPressure"},
+		{DiffChange, "nodes", "worker-0 was cordoned"},
+		{DiffChange, "nodes", "worker-0 kubelet v1.28.6 → v1.29.5"},
+		{DiffRegression, "nodes", "worker-1 is missing"},
+		{DiffRegression, "pods", "batch/report-x1 (pod report-x1) is failing: Unschedulable"},
+		{DiffRegression, "pods", "shop/api-8e1a-klmno (Deployment api) is failing: CrashLoopBackOff"},
+		{DiffImprovement, "pods", "Deployment cart in shop recovered (was CrashLoopBackOff)"},
+		{DiffChange, "pods", "1 workload(s) were already failing before and still are"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("changes =\n%+v\nexpected\n%+v", got, expected)
+	}
+
+	if diff.AfterStats.Version != "4.16.1" || diff.AfterStats.Degraded != 1 || diff.AfterStats.NotReady != 1 || diff.AfterStats.FailingPods != 3 || diff.BeforeStats.FailingPods != 2 {
+		t.Errorf("stats before %+v, after %+v", diff.BeforeStats, diff.AfterStats)
+	}
+
+	if _, err := newTestEngine().DiffMustGathers(context.Background(), t.TempDir(), after); err == nil {
+		t.Error("DiffMustGathers() accepted a directory that is not a must-gather")
+	}
+}
@@ -0,0 +1,923 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// OVNNamespace runs the ovnkube pods
+	OVNNamespace = "openshift-ovn-kubernetes"
+
+	// OVNManifestFile lists what an OVN-Kubernetes collection gathered
+	OVNManifestFile = "ovn-manifest.json"
+	// OVNGatewayFile holds the gateway configuration of the nodes
+	OVNGatewayFile = "gateway.json"
+
+	defaultOVNLogSince = "1h"
+
+	// maxSignatureEvidence bounds the log lines kept per failure signature
+	maxSignatureEvidence = 5
+)
+
+// The checks run in the OVN containers of each pod
+const (
+	OVNCheckNBStatus   = "nbdb-status"
+	OVNCheckSBStatus   = "sbdb-status"
+	OVNCheckConnection = "controller-connection"
+	OVNCheckOVS        = "ovs-show"
+)
+
+// containerCheck is a command run in every collected pod having its
+// container
+type containerCheck struct {
+	name      string
+	container string
+	command   []string
+}
+
+// ovnChecks read the state of the databases, of ovn-controller's connection
+// to the southbound database and of the node's OVS bridges. Databases are
+// clustered before OVN interconnect and standalone in each node's pod after
+// it; sync-status answers for the standalone ones.
+var ovnChecks = []containerCheck{
+	{OVNCheckNBStatus, "nbdb", []string{"sh", "-c",
+		"ovn-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/status OVN_Northbound 2>/dev/null || ovn-appctl -t /var/run/ovn/ovnnb_db.ctl ovsdb-server/sync-status"}},
+	{OVNCheckSBStatus, "sbdb", []string{"sh", "-c",
+		"ovn-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/status OVN_Southbound 2>/dev/null || ovn-appctl -t /var/run/ovn/ovnsb_db.ctl ovsdb-server/sync-status"}},
+	{OVNCheckConnection, "ovn-controller", []string{"ovn-appctl", "-t", "ovn-controller", "connection-status"}},
+	{OVNCheckOVS, "ovn-controller", []string{"ovs-vsctl", "show"}},
+}
+
+// OVNManifest describes an OVN-Kubernetes collection
+type OVNManifest struct {
+	Node        string             `json:"node,omitempty"`
+	Since       string             `json:"since"`
+	CollectedAt time.Time          `json:"collected_at"`
+	Duration    string             `json:"duration"`
+	Pods        []CollectedPod     `json:"pods"`
+	Logs        []LogManifestEntry `json:"logs"`
+	Checks      []CheckOutput      `json:"checks"`
+	Gateway     string             `json:"gateway,omitempty"` // relative to the collection directory
+	Errors      []string           `json:"errors,omitempty"`
+}
+
+// CollectedPod is a pod of a collection and the node it runs on
+type CollectedPod struct {
+	Name       string   `json:"name"`
+	Node       string   `json:"node"`
+	Containers []string `json:"containers"`
+}
+
+// CheckOutput is the output of one check, or the error it failed with
+type CheckOutput struct {
+	Check     string `json:"check"`
+	Pod       string `json:"pod"`
+	Node      string `json:"node"`
+	Container string `json:"container"`
+	File      string `json:"file,omitempty"` // relative to the collection directory
+	Error     string `json:"error,omitempty"`
+}
+
+// OVNGatewayConfig is the cluster's gateway mode and each node's gateway
+type OVNGatewayConfig struct {
+	RoutingViaHost bool             `json:"routing_via_host"` // local gateway mode
+	IPForwarding   string           `json:"ip_forwarding,omitempty"`
+	Nodes          []OVNNodeGateway `json:"nodes"`
+}
+
+// OVNNodeGateway is the gateway ovnkube configured on a node, read from the
+// node's k8s.ovn.org annotations
+type OVNNodeGateway struct {
+	Node        string   `json:"node"`
+	Mode        string   `json:"mode,omitempty"` // shared or local
+	Interface   string   `json:"interface,omitempty"`
+	IPAddresses []string `json:"ip_addresses,omitempty"`
+	NextHops    []string `json:"next_hops,omitempty"`
+	Subnets     []string `json:"subnets,omitempty"`
+	Zone        string   `json:"zone,omitempty"`
+	Error       string   `json:"error,omitempty"` // why the configuration is missing or unreadable
+}
+
+// nodeAnnotationList is the part of oc get nodes -o json the gateway needs
+type nodeAnnotationList struct {
+	Items []struct {
+		Metadata struct {
+			Name        string            `json:"name"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+// l3GatewayConfig is the default network's entry of the
+// k8s.ovn.org/l3-gateway-config annotation; releases before 4.8 wrote a
+// single address and next hop
+type l3GatewayConfig struct {
+	Mode        string   `json:"mode"`
+	InterfaceID string   `json:"interface-id"`
+	IPAddresses []string `json:"ip-addresses"`
+	IPAddress   string   `json:"ip-address"`
+	NextHops    []string `json:"next-hops"`
+	NextHop     string   `json:"next-hop"`
+}
+
+// parseOVNNodeGateways reads the gateway of each node, or only of node
+func parseOVNNodeGateways(data []byte, node string) ([]OVNNodeGateway, error) {
+	var nodes nodeAnnotationList
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	var gateways []OVNNodeGateway
+	for _, item := range nodes.Items {
+		if node != "" && item.Metadata.Name != node {
+			continue
+		}
+		annotations := item.Metadata.Annotations
+		gateway := OVNNodeGateway{Node: item.Metadata.Name, Zone: annotations["k8s.ovn.org/zone-name"]}
+
+		var subnets map[string]json.RawMessage
+		if raw := annotations["k8s.ovn.org/node-subnets"]; raw != "" && json.Unmarshal([]byte(raw), &subnets) == nil {
+			var list []string
+			var single string
+			if json.Unmarshal(subnets["default"], &list) == nil {
+				gateway.Subnets = list
+			} else if json.Unmarshal(subnets["default"], &single) == nil && single != "" {
+				gateway.Subnets = []string{single}
+			}
+		}
+
+		raw, ok := annotations["k8s.ovn.org/l3-gateway-config"]
+		var configs map[string]l3GatewayConfig
+		switch {
+		case !ok:
+			gateway.Error = "no k8s.ovn.org/l3-gateway-config annotation; ovnkube never finished initializing the node"
+		case json.Unmarshal([]byte(raw), &configs) != nil:
+			gateway.Error = "unreadable k8s.ovn.org/l3-gateway-config annotation"
+		default:
+			config := configs["default"]
+			gateway.Mode = config.Mode
+			gateway.Interface = config.InterfaceID
+			gateway.IPAddresses = config.IPAddresses
+			if len(gateway.IPAddresses) == 0 && config.IPAddress != "" {
+				gateway.IPAddresses = []string{config.IPAddress}
+			}
+			gateway.NextHops = config.NextHops
+			if len(gateway.NextHops) == 0 && config.NextHop != "" {
+				gateway.NextHops = []string{config.NextHop}
+			}
+		}
+		gateways = append(gateways, gateway)
+	}
+	sort.Slice(gateways, func(i, j int) bool { return gateways[i].Node < gateways[j].Node })
+	return gateways, nil
+}
+
+// isOVNControlPlanePod matches the pods running the cluster-wide ovnkube
+// components, which are collected whatever the node
+func isOVNControlPlanePod(name string) bool {
+	return strings.HasPrefix(name, "ovnkube-control-plane") || strings.HasPrefix(name, "ovnkube-master")
+}
+
+// CollectOVN gathers OVN-Kubernetes diagnostics: the logs of every ovnkube
+// container since "since" (default 1h) including the previous instance of
+// restarted ones, the northbound and southbound database status,
+// ovn-controller's connection to the southbound database, the OVS bridges
+// and the gateway configuration of each node. opts.NodeName limits the
+// collection to that node's pods and the control plane. Checks that fail
+// are recorded in the manifest; the collection only fails when the ovnkube
+// pods cannot be listed.
+func (dc *DiagnosticCollector) CollectOVN(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
+	start := time.Now()
+	result := &CollectionResult{
+		Type:     "ovn",
+		Metadata: make(map[string]string),
+	}
+	fail := func(err error) (*CollectionResult, error) {
+		result.Status = "failed"
+		result.ErrorMsg = err.Error()
+		result.Duration = time.Since(start)
+		return result, err
+	}
+
+	since := opts.Filters["since"]
+	if since == "" {
+		since = defaultOVNLogSince
+	}
+	if _, err := time.ParseDuration(since); err != nil {
+		return nil, fmt.Errorf("invalid since duration %q: %v", since, err)
+	}
+	if opts.NodeName != "" && !nodeNamePattern.MatchString(opts.NodeName) {
+		return nil, fmt.Errorf("invalid node name %q", opts.NodeName)
+	}
+	manifest := &OVNManifest{Node: opts.NodeName, Since: since, CollectedAt: start.UTC()}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("ovn-%d", start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fail(err)
+	}
+	result.FilePath = outputDir
+
+	var listing bytes.Buffer
+	if err := dc.runOC(ctx, nil, &listing, "get", "pods", "-n", OVNNamespace, "-o", "json"); err != nil {
+		return fail(fmt.Errorf("listing pods in %s: %v", OVNNamespace, err))
+	}
+	var pods podList
+	if err := json.Unmarshal(listing.Bytes(), &pods); err != nil {
+		return fail(fmt.Errorf("parsing the pod list: %v", err))
+	}
+	selected := pods.Items[:0]
+	for _, pod := range pods.Items {
+		if opts.NodeName == "" || pod.Spec.NodeName == opts.NodeName || isOVNControlPlanePod(pod.Metadata.Name) {
+			selected = append(selected, pod)
+		}
+	}
+	pods.Items = selected
+	if len(pods.Items) == 0 {
+		return fail(fmt.Errorf("no ovnkube pods in %s; is the cluster network OVN-Kubernetes?", OVNNamespace))
+	}
+	for _, pod := range pods.Items {
+		entry := CollectedPod{Name: pod.Metadata.Name, Node: pod.Spec.NodeName}
+		for _, container := range pod.Spec.Containers {
+			entry.Containers = append(entry.Containers, container.Name)
+		}
+		manifest.Pods = append(manifest.Pods, entry)
+	}
+
+	manifest.Logs = dc.collectContainerLogs(ctx, outputDir, OVNNamespace, since, logJobs(&pods, true), DefaultLogWorkers)
+	failed := 0
+	var size int64
+	for _, entry := range manifest.Logs {
+		size += entry.Bytes
+		if entry.Error != "" {
+			failed++
+		}
+	}
+
+	for _, pod := range manifest.Pods {
+		for _, check := range ovnChecks {
+			if !hasString(pod.Containers, check.container) {
+				continue
+			}
+			output := dc.runContainerCheck(ctx, outputDir, OVNNamespace, pod, check)
+			if output.Error != "" {
+				failed++
+			}
+			manifest.Checks = append(manifest.Checks, output)
+		}
+	}
+
+	var nodes bytes.Buffer
+	if err := dc.runOC(ctx, nil, &nodes, "get", "nodes", "-o", "json"); err != nil {
+		manifest.Errors = append(manifest.Errors, fmt.Sprintf("reading the nodes' gateway configuration: %v", err))
+	} else if gateways, err := parseOVNNodeGateways(nodes.Bytes(), opts.NodeName); err != nil {
+		manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the node list: %v", err))
+	} else {
+		gateway := OVNGatewayConfig{Nodes: gateways}
+		var network bytes.Buffer
+		if err := dc.runOC(ctx, nil, &network, "get", "network.operator.openshift.io", "cluster", "-o",
+			"jsonpath={.spec.defaultNetwork.ovnKubernetesConfig.gatewayConfig.routingViaHost} {.spec.defaultNetwork.ovnKubernetesConfig.gatewayConfig.ipForwarding}"); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("reading the gateway mode: %v", err))
+		} else {
+			fields := strings.Fields(network.String())
+			gateway.RoutingViaHost = len(fields) > 0 && fields[0] == "true"
+			if len(fields) > 1 {
+				gateway.IPForwarding = fields[1]
+			}
+		}
+		data, err := json.MarshalIndent(gateway, "", "  ")
+		if err == nil {
+			err = os.WriteFile(filepath.Join(outputDir, OVNGatewayFile), data, 0644)
+		}
+		if err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("writing the gateway configuration: %v", err))
+		} else {
+			manifest.Gateway = OVNGatewayFile
+			size += int64(len(data))
+		}
+	}
+
+	manifest.Duration = time.Since(start).Round(time.Millisecond).String()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, OVNManifestFile), data, 0644); err != nil {
+		return fail(err)
+	}
+	if dirSize, err := dc.getDirSize(outputDir); err == nil {
+		size = dirSize
+	}
+
+	result.Duration = time.Since(start)
+	result.Size = size
+	result.Metadata["node"] = opts.NodeName
+	result.Metadata["since"] = since
+	result.Metadata["manifest"] = filepath.Join(outputDir, OVNManifestFile)
+	result.Metadata["pods"] = strconv.Itoa(len(manifest.Pods))
+	result.Metadata["logs"] = strconv.Itoa(len(manifest.Logs))
+	result.Metadata["checks"] = strconv.Itoa(len(manifest.Checks))
+	result.Metadata["failed"] = strconv.Itoa(failed + len(manifest.Errors))
+	if ctx.Err() != nil {
+		return fail(fmt.Errorf("OVN collection interrupted: %v", ctx.Err()))
+	}
+
+	result.Status = "completed"
+	result.Summary = fmt.Sprintf("Collected %d logs and %d checks from %d ovnkube pods (%.2f MB, %d failed) in %s",
+		len(manifest.Logs), len(manifest.Checks), len(manifest.Pods), float64(size)/(1024*1024), failed+len(manifest.Errors), outputDir)
+	dc.finishCollection("", result, opts)
+	dc.logger.Infof("OVN collection completed: %s", result.Summary)
+	return result, nil
+}
+
+// runContainerCheck runs a check in a pod's container into
+// <pod>/<check>.txt
+func (dc *DiagnosticCollector) runContainerCheck(ctx context.Context, outputDir, namespace string, pod CollectedPod, check containerCheck) CheckOutput {
+	output := CheckOutput{Check: check.name, Pod: pod.Name, Node: pod.Node, Container: check.container,
+		File: filepath.Join(pod.Name, check.name+".txt")}
+	path := filepath.Join(outputDir, output.File)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		var stdout bytes.Buffer
+		args := append([]string{"exec", "-n", namespace, pod.Name, "-c", check.container, "--"}, check.command...)
+		if err = dc.runOC(ctx, nil, &stdout, args...); err == nil {
+			err = os.WriteFile(path, stdout.Bytes(), 0644)
+		}
+	}
+	if err != nil {
+		output.File = ""
+		output.Error = err.Error()
+	}
+	return output
+}
+
+// LogSignature is a known failure recognized in a component's logs
+type LogSignature struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Severity    string
+	Title       string
+	Description string
+	Resolution  string
+}
+
+// ovnSignatures are the failures ovnkube, ovn-controller, northd and the
+// databases log most often on OpenShift
+var ovnSignatures = []LogSignature{
+	{
+		Name:        "pod-annotation-timeout",
+		Pattern:     regexp.MustCompile(`(?i)failed to get pod annotation|timed out waiting for (pod )?annotations|timed out waiting for OVS port binding|timed out waiting for pod flows`),
+		Severity:    "critical",
+		Title:       "Pods time out waiting for their OVN network",
+		Description: "The CNI gave up waiting for ovnkube to annotate the pod or for ovn-controller to bind its port, so pods stay in ContainerCreating with FailedCreatePodSandBox",
+		Resolution:  "Check ovnkube-controller on the pod's node and the control plane for the errors before the timeout; a restarted or overloaded ovnkube-node pod on that node is the usual cause",
+	},
+	{
+		Name:        "southbound-disconnected",
+		Pattern:     regexp.MustCompile(`(?i)(ovnsb_db|OVN_Southbound|sbdb)[^\n]*(connection dropped|connection attempt failed|Connection refused|connection closed)`),
+		Severity:    "critical",
+		Title:       "ovn-controller lost the southbound database",
+		Description: "Without the southbound database ovn-controller stops programming flows, so new pods get no connectivity and changes to services and policies are not applied",
+		Resolution:  "Check the sbdb container and its database size in the same pod (or the control plane before OVN interconnect), and restart the ovnkube-node pod if the database does not recover",
+	},
+	{
+		Name:        "ovs-disconnected",
+		Pattern:     regexp.MustCompile(`(?i)unix:/var/run/openvswitch/db\.sock: (connection dropped|connection attempt failed|Connection refused)`),
+		Severity:    "critical",
+		Title:       "Open vSwitch database unreachable on the node",
+		Description: "ovn-controller or ovnkube cannot reach ovsdb-server on the node, so no flows or ports are programmed",
+		Resolution:  "Check the node's Open vSwitch services: oc debug node/<node> -- chroot /host systemctl status ovsdb-server ovs-vswitchd",
+	},
+	{
+		Name:        "subnet-exhausted",
+		Pattern:     regexp.MustCompile(`(?i)(failed to allocate|error allocating)[^\n]*subnet|no (more )?available (host )?subnets|subnet[^\n]*exhausted`),
+		Severity:    "critical",
+		Title:       "No free node subnets in the cluster network",
+		Description: "Every hostPrefix subnet of clusterNetwork is taken, so new nodes get no pod subnet and their pods cannot start",
+		Resolution:  "Remove stale Node objects of deleted machines, or expand clusterNetwork in network.config.openshift.io/cluster",
+	},
+	{
+		Name:        "gateway-init",
+		Pattern:     regexp.MustCompile(`(?i)failed to (init|initialize|start) (shared |local )?gateway|gateway init failed|failed to get (default )?gateway interface|bridge br-ex (not found|does not exist)`),
+		Severity:    "critical",
+		Title:       "Node gateway initialization failed",
+		Description: "ovnkube could not set up the node's gateway on br-ex, so pods on the node cannot reach services or anything outside the cluster",
+		Resolution:  "Check that br-ex holds the node's default route interface: oc debug node/<node> -- chroot /host journalctl -u ovs-configuration",
+	},
+	{
+		Name:        "raft-instability",
+		Pattern:     regexp.MustCompile(`(?i)raft[^\n]*(election|leadership|disconnected|candidate)|election timeout|leadership transfer|has no leader`),
+		Severity:    "warning",
+		Title:       "Database RAFT cluster is electing leaders",
+		Description: "The clustered northbound or southbound database keeps losing its leader; writes stall during each election",
+		Resolution:  "Check the control plane nodes for CPU starvation and slow disks (etcd and the databases share them), and the databases' sizes",
+	},
+	{
+		Name:        "transaction-errors",
+		Pattern:     regexp.MustCompile(`(?i)(ovsdb|libovsdb|nbdb|sbdb)[^\n]*(transaction|transact)[^\n]*(error|failed|timeout)|(transaction|transact)[^\n]*context deadline exceeded`),
+		Severity:    "warning",
+		Title:       "Database transactions failing",
+		Description: "ovnkube's writes to the OVN databases fail or time out, so pod, service and policy changes are applied late or not at all",
+		Resolution:  "Look for constraint violations that point at stale objects, and for slow or electing databases in the same logs",
+	},
+	{
+		Name:        "leader-lease-lost",
+		Pattern:     regexp.MustCompile(`(?i)leader ?election lost|failed to renew lease|stopped leading`),
+		Severity:    "warning",
+		Title:       "ovnkube control plane lost its leader lease",
+		Description: "The control plane restarted its leader election, pausing node subnet and IP allocation meanwhile",
+		Resolution:  "Check API server latency and the control plane pods' CPU; repeated losses point at an overloaded API server",
+	},
+	{
+		Name:        "poll-starvation",
+		Pattern:     regexp.MustCompile(`Unreasonably long \d+ms poll interval`),
+		Severity:    "warning",
+		Title:       "OVN and OVS processes starved of CPU",
+		Description: "The processes' main loops stalled for long intervals, delaying flow programming and database updates",
+		Resolution:  "Check the node's CPU load and the reserved CPUs of the kubelet's systemReserved; heavy throttling on the node delays the networking stack",
+	},
+	{
+		Name:        "chassis-flapping",
+		Pattern:     regexp.MustCompile(`(?i)Changing chassis for lport|claimed by another chassis|Chassis[^\n]*already exists`),
+		Severity:    "warning",
+		Title:       "Logical ports moving between chassis",
+		Description: "Ports are claimed by several nodes in turn, typically because two nodes share a system-id after being cloned, which breaks their pods' traffic",
+		Resolution:  "Compare external_ids:system-id in ovs-vsctl list open_vswitch on the nodes involved; a duplicate must be regenerated on one of them",
+	},
+	{
+		Name:        "cni-wiring",
+		Pattern:     regexp.MustCompile(`(?i)failed to configure pod interface|error adding container to network|failed to (add|delete) port[^\n]*to bridge`),
+		Severity:    "warning",
+		Title:       "CNI failed to wire pod interfaces",
+		Description: "ovnkube could not create or attach the veth of some pods to br-int",
+		Resolution:  "Check the node's OVS for stale ports (ovs-vsctl show) and the kernel log for veth errors",
+	},
+}
+
+// MatchOVNSignature returns the known OVN-Kubernetes failure a log line or
+// event message shows, if any
+func MatchOVNSignature(line string) (LogSignature, bool) {
+	return matchSignature(ovnSignatures, line)
+}
+
+// matchSignature returns the first of signatures a line shows, if any
+func matchSignature(signatures []LogSignature, line string) (LogSignature, bool) {
+	for _, signature := range signatures {
+		if signature.Pattern.MatchString(line) {
+			return signature, true
+		}
+	}
+	return LogSignature{}, false
+}
+
+// OVNDBStatus is what cluster/status or sync-status reports of a database
+type OVNDBStatus struct {
+	Clustered bool
+	Role      string // leader, follower or candidate
+	Status    string // e.g. "cluster member" or "disconnected from the cluster (election timeout)"
+	Leader    string // "self", a server ID or "unknown"
+	Term      int
+	State     string // active or backup for a standalone database
+}
+
+// ParseOVNDBStatus reads the output of ovn-appctl cluster/status, or of
+// ovsdb-server/sync-status for a standalone database
+func ParseOVNDBStatus(output string) OVNDBStatus {
+	var status OVNDBStatus
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Role":
+			status.Clustered, status.Role = true, value
+		case "Status":
+			status.Clustered, status.Status = true, value
+		case "Leader":
+			status.Leader = value
+		case "Term":
+			status.Term, _ = strconv.Atoi(value)
+		case "state":
+			status.State = value
+		}
+	}
+	return status
+}
+
+// AnalyzeOVN reports the failures an OVN-Kubernetes collection shows: known
+// failure signatures in the ovnkube logs, databases without a leader or out
+// of their cluster, ovn-controllers not connected to the southbound
+// database, nodes missing their OVS bridges and nodes whose gateway was
+// never configured
+func (ae *AnalysisEngine) AnalyzeOVN(ctx context.Context, path string) (*AnalysisResult, error) {
+	if IsBundle(path) {
+		return analyzeBundle(path, bundleDir, func(dir string) (*AnalysisResult, error) {
+			return ae.AnalyzeOVN(ctx, dir)
+		})
+	}
+	result := &AnalysisResult{
+		Type:      "ovn-analysis",
+		FilePath:  path,
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: time.Now(),
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, OVNManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("no OVN collection in %s: %v", path, err)
+	}
+	var manifest OVNManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", OVNManifestFile, err)
+	}
+	ae.logger.Infof("Starting OVN analysis: %s", path)
+
+	nodeOf := make(map[string]string, len(manifest.Pods))
+	for _, pod := range manifest.Pods {
+		nodeOf[pod.Name] = pod.Node
+	}
+	podLabel := func(pod string) string {
+		if node := nodeOf[pod]; node != "" {
+			return fmt.Sprintf("%s (%s)", pod, node)
+		}
+		return pod
+	}
+
+	hits, err := ae.scanLogSignatures(ctx, path, manifest.Logs, ovnSignatures, podLabel)
+	if err != nil {
+		return nil, err
+	}
+	matches := ae.addSignatureIssues(result, ovnSignatures, hits, "network")
+
+	for _, check := range manifest.Checks {
+		ae.analyzeOVNCheck(path, check, podLabel(check.Pod), result)
+	}
+	gateway := ae.analyzeOVNGateway(path, manifest, result)
+
+	failedLogs := 0
+	for _, entry := range manifest.Logs {
+		if entry.Error != "" {
+			failedLogs++
+		}
+	}
+	result.Metrics["pods"] = len(manifest.Pods)
+	result.Metrics["logs"] = len(manifest.Logs)
+	result.Metrics["logs_failed"] = failedLogs
+	result.Metrics["checks"] = len(manifest.Checks)
+	result.Metrics["signature_matches"] = matches
+	if gateway != nil {
+		mode := "shared"
+		if gateway.RoutingViaHost {
+			mode = "local"
+		}
+		result.Metrics["gateway_mode"] = mode
+		result.Metrics["nodes"] = len(gateway.Nodes)
+	}
+	for _, err := range manifest.Errors {
+		result.markTruncated(err)
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+	ae.finishBudget(result)
+	ae.buildTimeline(result)
+	ae.generateSummaryAndRecommendations(result)
+	if len(result.Issues) == 0 {
+		result.Summary = fmt.Sprintf("No OVN-Kubernetes failures found in %d logs and %d checks of %d ovnkube pods", len(manifest.Logs), len(manifest.Checks), len(manifest.Pods))
+	}
+
+	ae.logger.Infof("OVN analysis completed: found %d issues", len(result.Issues))
+	return result, nil
+}
+
+// signatureHits accumulates one signature's matches
+type signatureHits struct {
+	count    int
+	pods     map[string]bool
+	evidence []string
+	first    time.Time
+}
+
+// scanLogSignatures matches every line of the collected logs against
+// signatures, labelling the pods they were found in with podLabel
+func (ae *AnalysisEngine) scanLogSignatures(ctx context.Context, path string, logs []LogManifestEntry, signatures []LogSignature, podLabel func(pod string) string) (map[string]*signatureHits, error) {
+	hits := make(map[string]*signatureHits)
+	for _, entry := range logs {
+		if entry.File == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := scanLogLines(filepath.Join(path, entry.File), func(line string) {
+			signature, ok := matchSignature(signatures, line)
+			if !ok {
+				return
+			}
+			hit := hits[signature.Name]
+			if hit == nil {
+				hit = &signatureHits{pods: make(map[string]bool)}
+				hits[signature.Name] = hit
+			}
+			hit.count++
+			hit.pods[podLabel(entry.Pod)] = true
+			at, message := splitLogTimestamp(line)
+			if !at.IsZero() && (hit.first.IsZero() || at.Before(hit.first)) {
+				hit.first = at
+			}
+			if len(hit.evidence) < maxSignatureEvidence {
+				hit.evidence = append(hit.evidence, fmt.Sprintf("%s/%s: %s", entry.Pod, entry.Container, truncateOutputLine(message)))
+			}
+		}); err != nil {
+			ae.logger.Warnf("Failed to read %s: %v", entry.File, err)
+		}
+	}
+	return hits, nil
+}
+
+// addSignatureIssues reports each signature that matched, in the order of
+// signatures, and returns how many lines matched
+func (ae *AnalysisEngine) addSignatureIssues(result *AnalysisResult, signatures []LogSignature, hits map[string]*signatureHits, category string) int {
+	matches := 0
+	for _, signature := range signatures {
+		hit := hits[signature.Name]
+		if hit == nil {
+			continue
+		}
+		matches += hit.count
+		pods := make([]string, 0, len(hit.pods))
+		for pod := range hit.pods {
+			pods = append(pods, pod)
+		}
+		sort.Strings(pods)
+		metadata := map[string]string{"signature": signature.Name, "occurrences": strconv.Itoa(hit.count)}
+		if !hit.first.IsZero() {
+			metadata["first_seen"] = hit.first.UTC().Format(time.RFC3339Nano)
+		}
+		ae.addIssue(result, Issue{
+			Severity:    signature.Severity,
+			Category:    category,
+			Title:       signature.Title,
+			Description: fmt.Sprintf("%s (%d log lines)", signature.Description, hit.count),
+			Location:    strings.Join(pods, ", "),
+			Evidence:    hit.evidence,
+			Resolution:  signature.Resolution,
+			Metadata:    metadata,
+		})
+	}
+	return matches
+}
+
+// scanLogLines calls match with each line of a log file
+func scanLogLines(path string, match func(line string)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// splitLogTimestamp splits the timestamp oc logs --timestamps puts in front
+// of each line from the message
+func splitLogTimestamp(line string) (time.Time, string) {
+	stamp, message, ok := strings.Cut(line, " ")
+	if !ok {
+		return time.Time{}, line
+	}
+	at, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return time.Time{}, line
+	}
+	return at, message
+}
+
+// truncateOutputLine keeps evidence lines readable
+func truncateOutputLine(line string) string {
+	const limit = 300
+	if len(line) > limit {
+		return line[:limit] + "..."
+	}
+	return line
+}
+
+// analyzeOVNCheck reports what a database, connection or OVS check shows
+func (ae *AnalysisEngine) analyzeOVNCheck(path string, check CheckOutput, pod string, result *AnalysisResult) {
+	if check.Error != "" {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "collection",
+			Title:       fmt.Sprintf("Check %s failed in %s", check.Check, check.Pod),
+			Description: "The check could not run, so its part of the analysis is missing",
+			Location:    pod,
+			Evidence:    []string{check.Error},
+			Resolution:  fmt.Sprintf("Check that the %s container is running: oc get pod %s -n %s", check.Container, check.Pod, OVNNamespace),
+		})
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(path, check.File))
+	if err != nil {
+		ae.logger.Warnf("Failed to read %s: %v", check.File, err)
+		return
+	}
+	output := strings.TrimSpace(string(data))
+	metadata := map[string]string{"check": check.Check, "node": check.Node}
+
+	switch check.Check {
+	case OVNCheckNBStatus, OVNCheckSBStatus:
+		database := "Northbound"
+		if check.Check == OVNCheckSBStatus {
+			database = "Southbound"
+		}
+		status := ParseOVNDBStatus(output)
+		evidence := strings.Split(output, "\n")
+		if len(evidence) > 8 {
+			evidence = evidence[:8]
+		}
+		switch {
+		case status.Clustered && strings.Contains(status.Status, "disconnected"):
+			ae.addIssue(result, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("%s database in %s is disconnected from its cluster", database, check.Pod),
+				Description: "This member cannot reach the RAFT cluster; with a majority of members disconnected the database stops accepting writes",
+				Location:    pod,
+				Evidence:    evidence,
+				Resolution:  "Check connectivity between the control plane nodes on ports 9643 and 9644 and the member's logs; a member that stays out must be removed and rejoined",
+				Metadata:    metadata,
+			})
+		case status.Clustered && (status.Leader == "unknown" || status.Role == "candidate"):
+			ae.addIssue(result, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("%s database has no leader according to %s", database, check.Pod),
+				Description: fmt.Sprintf("The cluster is electing a leader (term %d); no writes are accepted until one wins", status.Term),
+				Location:    pod,
+				Evidence:    evidence,
+				Resolution:  "Check that a majority of the database members are running, and the control plane nodes' CPU and disk latency",
+				Metadata:    metadata,
+			})
+		case !status.Clustered && status.State != "" && status.State != "active":
+			ae.addIssue(result, Issue{
+				Severity:    "warning",
+				Category:    "network",
+				Title:       fmt.Sprintf("%s database in %s is %s", database, check.Pod, status.State),
+				Description: "The standalone database is not the active one, so the node's components read stale data",
+				Location:    pod,
+				Evidence:    evidence,
+				Resolution:  fmt.Sprintf("Restart the %s container; each node's database is expected to be active", check.Container),
+				Metadata:    metadata,
+			})
+		}
+	case OVNCheckConnection:
+		if output != "connected" {
+			ae.addIssue(result, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("ovn-controller on %s is not connected to the southbound database", valueOrUnknown(check.Node)),
+				Description: "ovn-controller programs no flows while disconnected; pods on the node lose new connectivity and service changes",
+				Location:    pod,
+				Evidence:    []string{"connection-status: " + output},
+				Resolution:  "Check the sbdb container and ovn-controller's log in the same pod for the reason of the disconnection",
+				Metadata:    metadata,
+			})
+		}
+	case OVNCheckOVS:
+		for _, bridge := range []string{"br-int", "br-ex"} {
+			if !strings.Contains(output, "Bridge "+bridge) && !strings.Contains(output, `Bridge "`+bridge+`"`) {
+				ae.addIssue(result, Issue{
+					Severity:    "critical",
+					Category:    "network",
+					Title:       fmt.Sprintf("Node %s has no %s bridge", valueOrUnknown(check.Node), bridge),
+					Description: "OVN-Kubernetes needs br-int for pod traffic and br-ex for the node's gateway; without it the node's pods have no network",
+					Location:    pod,
+					Resolution:  "Check ovs-configuration on the node: oc debug node/" + check.Node + " -- chroot /host journalctl -u ovs-configuration",
+					Metadata:    metadata,
+				})
+			}
+		}
+		var missing []string
+		for _, line := range strings.Split(output, "\n") {
+			if strings.Contains(line, "could not open network device") {
+				missing = append(missing, strings.TrimSpace(line))
+			}
+		}
+		if len(missing) > 0 {
+			if len(missing) > maxSignatureEvidence {
+				missing = append(missing[:maxSignatureEvidence], fmt.Sprintf("... and %d more", len(missing)-maxSignatureEvidence))
+			}
+			ae.addIssue(result, Issue{
+				Severity:    "warning",
+				Category:    "network",
+				Title:       fmt.Sprintf("Stale OVS ports on node %s", valueOrUnknown(check.Node)),
+				Description: "OVS holds ports whose interfaces no longer exist, left by pods that were not cleaned up",
+				Location:    pod,
+				Evidence:    missing,
+				Resolution:  "Stale ports are usually harmless, but many of them slow OVS down; restarting the node's ovnkube-node pod removes them",
+				Metadata:    metadata,
+			})
+		}
+	}
+}
+
+// analyzeOVNGateway reports nodes whose gateway ovnkube did not configure,
+// and returns the gateway configuration when the collection has one
+func (ae *AnalysisEngine) analyzeOVNGateway(path string, manifest OVNManifest, result *AnalysisResult) *OVNGatewayConfig {
+	if manifest.Gateway == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(path, manifest.Gateway))
+	if err != nil {
+		ae.logger.Warnf("Failed to read %s: %v", manifest.Gateway, err)
+		return nil
+	}
+	var gateway OVNGatewayConfig
+	if err := json.Unmarshal(data, &gateway); err != nil {
+		ae.logger.Warnf("Invalid %s: %v", manifest.Gateway, err)
+		return nil
+	}
+
+	modes := make(map[string][]string)
+	var noNextHop []string
+	for _, node := range gateway.Nodes {
+		location := "node/" + node.Node
+		if node.Error != "" {
+			ae.addIssue(result, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("Node %s has no OVN gateway", node.Node),
+				Description: node.Error,
+				Location:    location,
+				Resolution:  fmt.Sprintf("Check the ovnkube-node pod on %s and its ovnkube-controller log", node.Node),
+				Metadata:    map[string]string{"node": node.Node},
+			})
+			continue
+		}
+		if len(node.Subnets) == 0 {
+			ae.addIssue(result, Issue{
+				Severity:    "critical",
+				Category:    "network",
+				Title:       fmt.Sprintf("Node %s has no pod subnet", node.Node),
+				Description: "The control plane allocated no subnet to the node, so pods scheduled there cannot start",
+				Location:    location,
+				Resolution:  "Check the ovnkube control plane's log for subnet allocation errors",
+				Metadata:    map[string]string{"node": node.Node},
+			})
+		}
+		if node.Mode != "" {
+			modes[node.Mode] = append(modes[node.Mode], node.Node)
+		}
+		if len(node.NextHops) == 0 {
+			noNextHop = append(noNextHop, node.Node)
+		}
+	}
+	if len(modes) > 1 {
+		var evidence []string
+		for mode, nodes := range modes {
+			evidence = append(evidence, fmt.Sprintf("%s: %s", mode, strings.Join(nodes, ", ")))
+		}
+		sort.Strings(evidence)
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "configuration",
+			Title:       "Nodes run different gateway modes",
+			Description: "Some nodes use the shared and others the local gateway mode, usually after routingViaHost changed without all ovnkube-node pods restarting",
+			Location:    "network.operator/cluster",
+			Evidence:    evidence,
+			Resolution:  "Restart the ovnkube-node pods still in the old mode so every node follows gatewayConfig.routingViaHost",
+		})
+	}
+	if len(noNextHop) > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "Nodes without a gateway next hop",
+			Description: "ovnkube found no default route on these nodes' gateway interface, so egress traffic from their pods has nowhere to go",
+			Location:    strings.Join(noNextHop, ", "),
+			Resolution:  "Check the default route on br-ex: oc debug node/<node> -- chroot /host ip route show default",
+		})
+	}
+	return &gateway
+}
@@ -0,0 +1,148 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testOVNPods = `{"items": [
+  {"metadata": {"name": "ovnkube-node-a"},
+   "spec": {"nodeName": "worker-1", "containers": [{"name": "ovn-controller"}, {"name": "nbdb"}, {"name": "sbdb"}, {"name": "ovnkube-controller"}]},
+   "status": {"containerStatuses": [{"name": "ovnkube-controller", "restartCount": 1}]}},
+  {"metadata": {"name": "ovnkube-node-b"},
+   "spec": {"nodeName": "worker-2", "containers": [{"name": "ovn-controller"}, {"name": "ovnkube-controller"}]},
+   "status": {}},
+  {"metadata": {"name": "ovnkube-control-plane-x"},
+   "spec": {"nodeName": "master-0", "containers": [{"name": "ovnkube-cluster-manager"}]},
+   "status": {}}
+]}`
+
+const testOVNNodes = `{"items": [
+  {"metadata": {"name": "worker-1", "annotations": {
+    "k8s.ovn.org/l3-gateway-config": "{\"default\":{\"mode\":\"local\",\"interface-id\":\"br-ex_worker-1\",\"ip-addresses\":[\"10.0.0.5/24\"],\"next-hops\":[\"10.0.0.1\"]}}",
+    "k8s.ovn.org/node-subnets": "{\"default\":[\"10.128.2.0/23\"]}",
+    "k8s.ovn.org/zone-name": "worker-1"}}},
+  {"metadata": {"name": "worker-2", "annotations": {
+    "k8s.ovn.org/node-subnets": "{\"default\":\"10.129.2.0/23\"}"}}}
+]}`
+
+func TestParseOVNDBStatus(t *testing.T) {
+	clustered := ParseOVNDBStatus("a8b3\nName: OVN_Northbound\nCluster ID: 1f2e\nServer ID: a8b3\nAddress: ssl:10.0.0.2:9643\nStatus: cluster member\nRole: candidate\nTerm: 42\nLeader: unknown\n")
+	if !clustered.Clustered || clustered.Role != "candidate" || clustered.Leader != "unknown" || clustered.Term != 42 || clustered.Status != "cluster member" {
+		t.Errorf("clustered status = %+v", clustered)
+	}
+	if standalone := ParseOVNDBStatus("state: active\n"); standalone.Clustered || standalone.State != "active" {
+		t.Errorf("standalone status = %+v", standalone)
+	}
+}
+
+func TestCollectAndAnalyzeOVN(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+
+	var mu sync.Mutex
+	var calls []string
+	dc.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		call := strings.Join(args, " ")
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+		output := ""
+		switch {
+		case strings.HasPrefix(call, "get pods"):
+			output = testOVNPods
+		case strings.HasPrefix(call, "get nodes"):
+			output = testOVNNodes
+		case strings.HasPrefix(call, "get network.operator"):
+			output = "true Global"
+		case strings.HasPrefix(call, "logs ovnkube-node-a -c ovnkube-controller") && strings.Contains(call, "--previous=true"):
+			output = "2026-10-16T08:50:00.000000000Z I1016 08:50:00 ovs|00012|timeval|WARN|Unreasonably long 5230ms poll interval (4100ms user, 900ms system)"
+		case strings.HasPrefix(call, "logs ovnkube-node-a -c ovnkube-controller"):
+			output = "2026-10-16T09:00:00.000000000Z E1016 09:00:00 cni.go:140 failed to get pod annotation: timed out waiting for annotations: context deadline exceeded\n" +
+				"2026-10-16T09:01:00.000000000Z E1016 09:01:00 cni.go:140 failed to get pod annotation: timed out waiting for annotations: context deadline exceeded"
+		case strings.HasPrefix(call, "logs ovnkube-node-b -c ovn-controller"):
+			output = "2026-10-16T08:59:00.000000000Z 2026-10-16T08:59:00Z|00045|reconnect|INFO|unix:/var/run/ovn/ovnsb_db.sock: connection dropped (Broken pipe)"
+		case strings.HasPrefix(call, "logs"):
+			output = "2026-10-16T08:00:00.000000000Z all good"
+		case strings.Contains(call, "ovnnb_db.ctl"):
+			output = "state: active"
+		case strings.Contains(call, "ovnsb_db.ctl"):
+			output = "state: backup"
+		case strings.Contains(call, "exec -n openshift-ovn-kubernetes ovnkube-node-b -c ovn-controller -- ovn-appctl"):
+			output = "not connected"
+		case strings.Contains(call, "connection-status"):
+			output = "connected"
+		case strings.Contains(call, "ovs-vsctl show"):
+			output = "d3c1\n    Bridge br-int\n        Port veth1\n            Interface veth1\n                error: \"could not open network device veth1 (No such device)\"\n    Bridge br-ex\n        Port br-ex\n"
+			if strings.Contains(call, "ovnkube-node-b") {
+				output = "d3c1\n    Bridge br-int\n"
+			}
+		default:
+			return exec.CommandContext(ctx, "sh", "-c", "echo 'unexpected call' >&2; exit 1")
+		}
+		return exec.CommandContext(ctx, "sh", "-c", "cat <<'EOF'\n"+output+"\nEOF")
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "ovn")
+	result, err := dc.CollectOVN(context.Background(), &CollectionOptions{OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("CollectOVN() error = %v", err)
+	}
+	if result.Metadata["pods"] != "3" || result.Metadata["logs"] != "8" || result.Metadata["checks"] != "6" || result.Metadata["failed"] != "0" {
+		t.Errorf("CollectOVN() metadata = %v, expected 3 pods, 8 logs and 6 checks", result.Metadata)
+	}
+	for _, call := range calls {
+		if strings.HasPrefix(call, "logs") && (!strings.Contains(call, "--since=1h") || !strings.Contains(call, "-n "+OVNNamespace)) {
+			t.Errorf("log call %q missing the namespace or since", call)
+		}
+	}
+
+	analysis, err := newTestAnalysisEngine().AnalyzeOVN(context.Background(), outputDir)
+	if err != nil {
+		t.Fatalf("AnalyzeOVN() error = %v", err)
+	}
+	if analysis.Metrics["gateway_mode"] != "local" || analysis.Metrics["signature_matches"] != 4 || analysis.Metrics["nodes"] != 2 {
+		t.Errorf("AnalyzeOVN() metrics = %v", analysis.Metrics)
+	}
+	issues := make(map[string]Issue)
+	for _, issue := range analysis.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, severity := range map[string]string{
+		"Pods time out waiting for their OVN network":                            "critical",
+		"ovn-controller lost the southbound database":                            "critical",
+		"OVN and OVS processes starved of CPU":                                   "warning",
+		"ovn-controller on worker-2 is not connected to the southbound database": "critical",
+		"Node worker-2 has no br-ex bridge":                                      "critical",
+		"Stale OVS ports on node worker-1":                                       "warning",
+		"Southbound database in ovnkube-node-a is backup":                        "warning",
+		"Node worker-2 has no OVN gateway":                                       "critical",
+	} {
+		if issues[title].Severity != severity {
+			t.Errorf("AnalyzeOVN() issue %q = %+v, expected severity %s", title, issues[title], severity)
+		}
+	}
+	if len(analysis.Issues) != 8 {
+		t.Errorf("AnalyzeOVN() issues = %+v, expected 8", analysis.Issues)
+	}
+	timeout := issues["Pods time out waiting for their OVN network"]
+	if timeout.Location != "ovnkube-node-a (worker-1)" || timeout.Metadata["occurrences"] != "2" || timeout.Metadata["first_seen"] != "2026-10-16T09:00:00Z" {
+		t.Errorf("annotation timeout issue = %+v", timeout)
+	}
+	if analysis.Issues[0].Severity != "critical" || len(analysis.Timeline) == 0 {
+		t.Errorf("AnalyzeOVN() issues not sorted or no timeline: %+v", analysis)
+	}
+
+	gateways, err := parseOVNNodeGateways([]byte(testOVNNodes), "worker-1")
+	if err != nil || len(gateways) != 1 || gateways[0].Mode != "local" || gateways[0].NextHops[0] != "10.0.0.1" || gateways[0].Subnets[0] != "10.128.2.0/23" {
+		t.Errorf("parseOVNNodeGateways(worker-1) = %+v, %v", gateways, err)
+	}
+}
@@ -0,0 +1,280 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/gopacket/gopacket/pcapgo"
+)
+
+// pcapngMagic starts every pcapng file: the section header block type
+var pcapngMagic = []byte{0x0A, 0x0D, 0x0D, 0x0A}
+
+// errNotPcap is returned for files that are neither pcap nor pcapng
+var errNotPcap = errors.New("not a pcap or pcapng file")
+
+// pcapPacket is one captured packet
+type pcapPacket struct {
+	timestamp time.Time
+	linkType  layers.LinkType
+	data      []byte // captured bytes, possibly cut at the snap length
+	length    int    // length on the wire
+}
+
+// pcapReader reads packets from a classic pcap or a pcapng stream, the
+// formats written by tcpdump and tshark/Wireshark respectively
+type pcapReader struct {
+	source gopacket.ZeroCopyPacketDataSource
+	ng     bool
+	link   layers.LinkType // of every packet in a classic pcap
+}
+
+// newPcapReader reads the file header and detects the format
+func newPcapReader(r io.Reader) (*pcapReader, error) {
+	buffered := bufio.NewReaderSize(r, 256*1024)
+	magic, err := buffered.Peek(len(pcapngMagic))
+	if err != nil {
+		return nil, errNotPcap
+	}
+
+	if bytes.Equal(magic, pcapngMagic) {
+		// Interfaces of one capture may differ in link type, e.g. tshark -i any
+		ng, err := pcapgo.NewNgReader(buffered, pcapgo.NgReaderOptions{WantMixedLinkType: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errNotPcap, err)
+		}
+		return &pcapReader{source: ng, ng: true}, nil
+	}
+	classic, err := pcapgo.NewReader(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNotPcap, err)
+	}
+	return &pcapReader{source: classic, link: classic.LinkType()}, nil
+}
+
+// next returns the next packet, io.EOF at the end of the capture and
+// io.ErrUnexpectedEOF when the capture was cut off mid-packet. The packet's
+// data is only valid until the following call.
+func (p *pcapReader) next() (pcapPacket, error) {
+	data, info, err := p.source.ZeroCopyReadPacketData()
+	if err != nil {
+		return pcapPacket{}, err
+	}
+	packet := pcapPacket{timestamp: info.Timestamp.UTC(), linkType: p.link, data: data, length: info.Length}
+	if p.ng && len(info.AncillaryData) > 0 {
+		packet.linkType, _ = info.AncillaryData[0].(layers.LinkType)
+	}
+	return packet, nil
+}
+
+// decodedPacket is what the analysis needs from a packet's headers
+type decodedPacket struct {
+	network   string // ipv4, ipv6, arp or other
+	transport string // tcp, udp, icmp, fragment or other
+	src, dst  netip.Addr
+
+	srcPort, dstPort uint16
+	seq, ack         uint32
+	flags            uint8
+	segmentLength    int    // TCP payload length on the wire
+	payload          []byte // captured transport payload
+}
+
+// TCP flags
+const (
+	tcpFIN = 0x01
+	tcpSYN = 0x02
+	tcpRST = 0x04
+	tcpACK = 0x10
+)
+
+// packetDecoder decodes the link, network and transport headers of packets
+// with gopacket's DecodingLayerParser. It reuses its layers, so decoding a
+// packet does not allocate, and is not safe for concurrent use.
+type packetDecoder struct {
+	ethernet layers.Ethernet
+	dot1q    layers.Dot1Q
+	sll      layers.LinuxSLL
+	sll2     layers.LinuxSLL2
+	loopback layers.Loopback
+	ipv4     layers.IPv4
+	ipv6     layers.IPv6
+	ipv6ext  layers.IPv6ExtensionSkipper
+	tcp      layers.TCP
+	udp      layers.UDP
+	dns      layers.DNS
+
+	parsers map[gopacket.LayerType]*gopacket.DecodingLayerParser // by first layer
+	decoded []gopacket.LayerType
+}
+
+// firstLayer is the layer a packet of the link type starts with
+func firstLayer(linkType layers.LinkType, data []byte) gopacket.LayerType {
+	switch linkType {
+	case layers.LinkTypeEthernet:
+		return layers.LayerTypeEthernet
+	case layers.LinkTypeLinuxSLL:
+		return layers.LayerTypeLinuxSLL
+	case layers.LinkTypeLinuxSLL2:
+		return layers.LayerTypeLinuxSLL2
+	case layers.LinkTypeNull:
+		return layers.LayerTypeLoopback
+	case layers.LinkTypeRaw, layers.LinkTypeIPv4, layers.LinkTypeIPv6:
+		// Raw captures tell IPv4 from IPv6 only by the version
+		if len(data) > 0 && data[0]>>4 == 6 {
+			return layers.LayerTypeIPv6
+		}
+		return layers.LayerTypeIPv4
+	}
+	return gopacket.LayerTypeZero
+}
+
+// parser returns the parser for packets starting with the first layer
+func (d *packetDecoder) parser(first gopacket.LayerType) *gopacket.DecodingLayerParser {
+	if parser, ok := d.parsers[first]; ok {
+		return parser
+	}
+	parser := gopacket.NewDecodingLayerParser(first,
+		&d.ethernet, &d.dot1q, &d.sll, &d.sll2, &d.loopback,
+		&d.ipv4, &d.ipv6, &d.ipv6ext, &d.tcp, &d.udp)
+	// The analysis only looks at headers; application layers end decoding
+	parser.IgnoreUnsupported = true
+	if d.parsers == nil {
+		d.parsers = make(map[gopacket.LayerType]*gopacket.DecodingLayerParser)
+	}
+	d.parsers[first] = parser
+	return parser
+}
+
+// transportName names the protocol an IP header says follows it
+func transportName(next gopacket.LayerType) string {
+	switch next {
+	case layers.LayerTypeTCP:
+		return "tcp"
+	case layers.LayerTypeUDP:
+		return "udp"
+	case layers.LayerTypeICMPv4, layers.LayerTypeICMPv6:
+		return "icmp"
+	case gopacket.LayerTypeFragment, layers.LayerTypeIPv6Fragment:
+		return "fragment"
+	}
+	return "other"
+}
+
+// decode parses the headers of a captured packet
+func (d *packetDecoder) decode(captured pcapPacket) decodedPacket {
+	first := firstLayer(captured.linkType, captured.data)
+	if first == gopacket.LayerTypeZero {
+		return decodedPacket{network: "other"}
+	}
+	// A damaged or cut-off header ends decoding with an error, leaving the
+	// layers before it decoded, which is all such a packet has to offer
+	_ = d.parser(first).DecodeLayers(captured.data, &d.decoded)
+
+	packet := decodedPacket{network: "other"}
+	next := first
+	for _, layer := range d.decoded {
+		switch layer {
+		case layers.LayerTypeEthernet:
+			next = d.ethernet.NextLayerType()
+		case layers.LayerTypeDot1Q:
+			next = d.dot1q.NextLayerType()
+		case layers.LayerTypeLinuxSLL:
+			next = d.sll.NextLayerType()
+		case layers.LayerTypeLinuxSLL2:
+			next = d.sll2.NextLayerType()
+		case layers.LayerTypeLoopback:
+			next = d.loopback.NextLayerType()
+		case layers.LayerTypeIPv4:
+			next = d.ipv4.NextLayerType()
+			packet.network, packet.src, packet.dst = "ipv4", ipAddr(d.ipv4.SrcIP), ipAddr(d.ipv4.DstIP)
+			packet.transport = transportName(next)
+		case layers.LayerTypeIPv6:
+			next = d.ipv6.NextLayerType()
+			packet.network, packet.src, packet.dst = "ipv6", ipAddr(d.ipv6.SrcIP), ipAddr(d.ipv6.DstIP)
+			packet.transport = transportName(next)
+		case layers.LayerTypeIPv6HopByHop, layers.LayerTypeIPv6Routing, layers.LayerTypeIPv6Destination:
+			next = d.ipv6ext.NextLayerType()
+			packet.transport = transportName(next)
+		case layers.LayerTypeIPv6Fragment:
+			// Only reassembly would make sense of the transport header
+			packet.transport = "fragment"
+			return packet
+		case layers.LayerTypeTCP:
+			packet.srcPort, packet.dstPort = uint16(d.tcp.SrcPort), uint16(d.tcp.DstPort)
+			packet.seq, packet.ack, packet.flags = d.tcp.Seq, d.tcp.Ack, tcpFlags(&d.tcp)
+			packet.payload = d.tcp.Payload
+			// The snap length cuts the payload, not the segment that was sent
+			packet.segmentLength = len(d.tcp.Payload) + max(captured.length-len(captured.data), 0)
+		case layers.LayerTypeUDP:
+			packet.srcPort, packet.dstPort = uint16(d.udp.SrcPort), uint16(d.udp.DstPort)
+			packet.payload = d.udp.Payload
+		}
+	}
+	if packet.network == "other" && next == layers.LayerTypeARP {
+		packet.network = "arp"
+	}
+	return packet
+}
+
+// tcpFlags packs the flags the analysis looks at
+func tcpFlags(tcp *layers.TCP) uint8 {
+	var flags uint8
+	if tcp.FIN {
+		flags |= tcpFIN
+	}
+	if tcp.SYN {
+		flags |= tcpSYN
+	}
+	if tcp.RST {
+		flags |= tcpRST
+	}
+	if tcp.ACK {
+		flags |= tcpACK
+	}
+	return flags
+}
+
+// ipAddr converts an address decoded by gopacket
+func ipAddr(ip net.IP) netip.Addr {
+	addr, _ := netip.AddrFromSlice(ip)
+	return addr
+}
+
+// dnsMessage is the part of a DNS message the analysis looks at
+type dnsMessage struct {
+	id       uint16
+	response bool
+	rcode    layers.DNSResponseCode
+	name     string
+}
+
+// dnsRcodeNames names the response codes in reports
+var dnsRcodeNames = map[layers.DNSResponseCode]string{
+	layers.DNSResponseCodeFormErr:  "FORMERR",
+	layers.DNSResponseCodeServFail: "SERVFAIL",
+	layers.DNSResponseCodeNXDomain: "NXDOMAIN",
+	layers.DNSResponseCodeNotImp:   "NOTIMP",
+	layers.DNSResponseCodeRefused:  "REFUSED",
+}
+
+// decodeDNS reads the header and first question of a DNS message
+func (d *packetDecoder) decodeDNS(payload []byte) (dnsMessage, bool) {
+	// A damaged question still leaves the 12-byte header decoded
+	if err := d.dns.DecodeFromBytes(payload, gopacket.NilDecodeFeedback); err != nil && len(payload) < 12 {
+		return dnsMessage{}, false
+	}
+	message := dnsMessage{id: d.dns.ID, response: d.dns.QR, rcode: d.dns.ResponseCode}
+	if len(d.dns.Questions) > 0 {
+		message.name = string(d.dns.Questions[0].Name)
+	}
+	return message, true
+}
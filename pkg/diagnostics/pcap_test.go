@@ -0,0 +1,374 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/gopacket/gopacket/pcapgo"
+	"github.com/sirupsen/logrus"
+)
+
+var pcapStart = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+// pcapBuilder writes a classic little-endian Ethernet capture like tcpdump -w
+type pcapBuilder struct {
+	buf bytes.Buffer
+}
+
+func newPcapBuilder() *pcapBuilder {
+	b := &pcapBuilder{}
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:4], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(header[4:6], 2)
+	binary.LittleEndian.PutUint16(header[6:8], 4)
+	binary.LittleEndian.PutUint32(header[16:20], 65535)
+	binary.LittleEndian.PutUint32(header[20:24], uint32(layers.LinkTypeEthernet))
+	b.buf.Write(header)
+	return b
+}
+
+func (b *pcapBuilder) packet(offset time.Duration, frame []byte) {
+	at := pcapStart.Add(offset)
+	header := make([]byte, 16)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(at.Unix()))
+	binary.LittleEndian.PutUint32(header[4:8], uint32(at.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(header[12:16], uint32(len(frame)))
+	b.buf.Write(header)
+	b.buf.Write(frame)
+}
+
+// ethernetIPv4 wraps a transport segment in IPv4 and Ethernet headers
+func ethernetIPv4(src, dst string, protocol byte, segment []byte) []byte {
+	frame := make([]byte, 14+20)
+	binary.BigEndian.PutUint16(frame[12:14], 0x0800)
+	ip := frame[14:]
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:4], uint16(20+len(segment)))
+	ip[8], ip[9] = 64, protocol
+	srcAddr, dstAddr := netip.MustParseAddr(src).As4(), netip.MustParseAddr(dst).As4()
+	copy(ip[12:16], srcAddr[:])
+	copy(ip[16:20], dstAddr[:])
+	return append(frame, segment...)
+}
+
+func tcpSegment(srcPort, dstPort uint16, seq, ack uint32, flags byte, payload int) []byte {
+	segment := make([]byte, 20+payload)
+	binary.BigEndian.PutUint16(segment[0:2], srcPort)
+	binary.BigEndian.PutUint16(segment[2:4], dstPort)
+	binary.BigEndian.PutUint32(segment[4:8], seq)
+	binary.BigEndian.PutUint32(segment[8:12], ack)
+	segment[12], segment[13] = 5<<4, flags
+	return segment
+}
+
+func udpDatagram(srcPort, dstPort uint16, payload []byte) []byte {
+	datagram := make([]byte, 8)
+	binary.BigEndian.PutUint16(datagram[0:2], srcPort)
+	binary.BigEndian.PutUint16(datagram[2:4], dstPort)
+	binary.BigEndian.PutUint16(datagram[4:6], uint16(8+len(payload)))
+	return append(datagram, payload...)
+}
+
+func dnsPayload(id uint16, response bool, rcode layers.DNSResponseCode, name string) []byte {
+	message := make([]byte, 12)
+	binary.BigEndian.PutUint16(message[0:2], id)
+	flags := uint16(rcode)
+	if response {
+		flags |= 0x8000
+	}
+	binary.BigEndian.PutUint16(message[2:4], flags)
+	binary.BigEndian.PutUint16(message[4:6], 1)
+	for _, label := range strings.Split(name, ".") {
+		message = append(message, byte(len(label)))
+		message = append(message, label...)
+	}
+	return append(message, 0, 0, 1, 0, 1)
+}
+
+func newTestAnalysisEngine() *AnalysisEngine {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewAnalysisEngine(logger)
+}
+
+func writeCapture(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "capture.pcap")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAnalyzeTcpdump(t *testing.T) {
+	const client, server, database, dns = "10.0.0.1", "10.0.0.2", "10.0.0.3", "172.30.0.10"
+	b := newPcapBuilder()
+	ms := time.Millisecond
+
+	// A handshake taking 30ms, then a data segment sent ten more times
+	b.packet(0, ethernetIPv4(client, server, 6, tcpSegment(40000, 443, 1000, 0, tcpSYN, 0)))
+	b.packet(30*ms, ethernetIPv4(server, client, 6, tcpSegment(443, 40000, 5000, 1001, tcpSYN|tcpACK, 0)))
+	b.packet(31*ms, ethernetIPv4(client, server, 6, tcpSegment(40000, 443, 1001, 5001, tcpACK, 0)))
+	for i := 0; i <= 10; i++ {
+		b.packet(time.Duration(40+i)*ms, ethernetIPv4(client, server, 6, tcpSegment(40000, 443, 1001, 5001, tcpACK, 100)))
+	}
+	// A keep-alive probe is not a retransmission
+	b.packet(4*time.Second, ethernetIPv4(client, server, 6, tcpSegment(40000, 443, 1100, 5001, tcpACK, 1)))
+
+	// Refused and unanswered connection attempts
+	b.packet(60*ms, ethernetIPv4(client, server, 6, tcpSegment(40001, 8080, 7000, 0, tcpSYN, 0)))
+	b.packet(61*ms, ethernetIPv4(server, client, 6, tcpSegment(8080, 40001, 0, 7001, tcpRST|tcpACK, 0)))
+	b.packet(70*ms, ethernetIPv4(client, database, 6, tcpSegment(40002, 5432, 9000, 0, tcpSYN, 0)))
+
+	// DNS: a SERVFAIL, an NXDOMAIN and a query that is never answered
+	b.packet(100*ms, ethernetIPv4(client, dns, 17, udpDatagram(50000, 53, dnsPayload(1, false, 0, "api.example.com"))))
+	b.packet(110*ms, ethernetIPv4(dns, client, 17, udpDatagram(53, 50000, dnsPayload(1, true, layers.DNSResponseCodeServFail, "api.example.com"))))
+	b.packet(120*ms, ethernetIPv4(client, dns, 17, udpDatagram(50001, 53, dnsPayload(2, false, 0, "web.shop.svc.cluster.local"))))
+	b.packet(125*ms, ethernetIPv4(dns, client, 17, udpDatagram(53, 50001, dnsPayload(2, true, layers.DNSResponseCodeNXDomain, "web.shop.svc.cluster.local"))))
+	b.packet(200*ms, ethernetIPv4(client, dns, 17, udpDatagram(50002, 53, dnsPayload(3, false, 0, "lost.example.com"))))
+
+	result, err := newTestAnalysisEngine().AnalyzeTcpdump(context.Background(), writeCapture(t, b.buf.Bytes()))
+	if err != nil {
+		t.Fatalf("AnalyzeTcpdump() error = %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"packets":             23,
+		"capture_duration":    "4s",
+		"tcp_connections":     3,
+		"tcp_retransmissions": 10,
+		"tcp_resets":          1,
+		"tcp_refused":         1,
+		"tcp_unanswered_syns": 1,
+		"handshake_p50_ms":    30.0,
+		"dns_queries":         3,
+		"dns_failures":        1,
+		"dns_nxdomain":        1,
+		"dns_unanswered":      1,
+		"dns_p95_ms":          10.0,
+	}
+	for key, value := range expected {
+		if result.Metrics[key] != value {
+			t.Errorf("AnalyzeTcpdump() metric %s = %v, expected %v", key, result.Metrics[key], value)
+		}
+	}
+	if protocols := result.Metrics["protocols"].(map[string]int); protocols["tcp"] != 18 || protocols["udp"] != 5 {
+		t.Errorf("AnalyzeTcpdump() protocols = %v", protocols)
+	}
+	if talkers := result.Metrics["top_talkers"].([]string); len(talkers) != 3 || !strings.HasPrefix(talkers[0], client+": ") || !strings.HasSuffix(talkers[0], " in 19 packets") {
+		t.Errorf("AnalyzeTcpdump() top talkers = %v", talkers)
+	}
+
+	issues := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, severity := range map[string]string{
+		"TCP Retransmissions":            "critical",
+		"Connections Refused":            "warning",
+		"Unanswered Connection Attempts": "warning",
+		"DNS Server Failures":            "warning",
+		"DNS Queries Without Response":   "critical",
+		"DNS NXDOMAIN Responses":         "info",
+	} {
+		if issues[title].Severity != severity {
+			t.Errorf("AnalyzeTcpdump() issue %q = %+v, expected severity %s", title, issues[title], severity)
+		}
+	}
+	if _, ok := issues["TCP Resets"]; ok {
+		t.Errorf("AnalyzeTcpdump() reported the refusal as a reset of an established connection")
+	}
+	if evidence := issues["Connections Refused"].Evidence; len(evidence) != 1 || evidence[0] != server+":8080 refused 1 time(s)" {
+		t.Errorf("Connections Refused evidence = %v", evidence)
+	}
+	if evidence := issues["DNS Server Failures"].Evidence; len(evidence) != 1 || evidence[0] != "SERVFAIL api.example.com (1)" {
+		t.Errorf("DNS Server Failures evidence = %v", evidence)
+	}
+}
+
+func TestAnalyzeTcpdumpPcapng(t *testing.T) {
+	var buf bytes.Buffer
+	block := func(blockType uint32, body []byte) {
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+		length := uint32(12 + len(body))
+		binary.Write(&buf, binary.BigEndian, blockType)
+		binary.Write(&buf, binary.BigEndian, length)
+		buf.Write(body)
+		binary.Write(&buf, binary.BigEndian, length)
+	}
+	// Big-endian section with nanosecond timestamps
+	block(0x0A0D0D0A, []byte{0x1A, 0x2B, 0x3C, 0x4D, 0, 1, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	block(1, []byte{0, byte(layers.LinkTypeEthernet), 0, 0, 0, 0, 0xff, 0xff, 0, 9, 0, 1, 9, 0, 0, 0, 0, 0, 0, 0})
+	frame := ethernetIPv4("10.0.0.1", "172.30.0.10", 17, udpDatagram(50000, 53, dnsPayload(7, false, 0, "example.com")))
+	units := uint64(pcapStart.UnixNano())
+	packet := make([]byte, 20)
+	binary.BigEndian.PutUint32(packet[4:8], uint32(units>>32))
+	binary.BigEndian.PutUint32(packet[8:12], uint32(units))
+	binary.BigEndian.PutUint32(packet[12:16], uint32(len(frame)))
+	binary.BigEndian.PutUint32(packet[16:20], uint32(len(frame)))
+	block(6, append(packet, frame...))
+
+	result, err := newTestAnalysisEngine().AnalyzeTcpdump(context.Background(), writeCapture(t, buf.Bytes()))
+	if err != nil {
+		t.Fatalf("AnalyzeTcpdump() error = %v", err)
+	}
+	if result.Metrics["packets"] != 1 || result.Metrics["dns_queries"] != 1 || result.Metrics["capture_start"] != pcapStart.Format(time.RFC3339) {
+		t.Errorf("AnalyzeTcpdump(pcapng) metrics = %v", result.Metrics)
+	}
+}
+
+func TestAnalyzeTcpdumpDamagedFiles(t *testing.T) {
+	b := newPcapBuilder()
+	b.packet(0, ethernetIPv4("10.0.0.1", "10.0.0.2", 6, tcpSegment(40000, 443, 1000, 0, tcpSYN, 0)))
+	b.packet(time.Millisecond, ethernetIPv4("10.0.0.2", "10.0.0.1", 6, tcpSegment(443, 40000, 5000, 1001, tcpSYN|tcpACK, 0)))
+	capture := b.buf.Bytes()
+
+	tests := []struct {
+		name      string
+		data      []byte
+		packets   interface{}
+		truncated bool
+		issue     string
+	}{
+		{"cut mid-packet", capture[:len(capture)-10], 1, true, ""},
+		{"not a capture", []byte("tcpdump: listening on eth0\n"), nil, false, "Unrecognized Capture Format"},
+		{"empty", nil, nil, false, "Empty Capture File"},
+		{"header only", capture[:24], 0, false, "No Packets Captured"},
+	}
+	for _, tt := range tests {
+		result, err := newTestAnalysisEngine().AnalyzeTcpdump(context.Background(), writeCapture(t, tt.data))
+		if err != nil {
+			t.Fatalf("%s: AnalyzeTcpdump() error = %v", tt.name, err)
+		}
+		if result.Metrics["packets"] != tt.packets || result.Truncated != tt.truncated {
+			t.Errorf("%s: AnalyzeTcpdump() packets = %v, truncated = %v %v", tt.name, result.Metrics["packets"], result.Truncated, result.TruncationReasons)
+		}
+		if tt.issue != "" && (len(result.Issues) != 1 || result.Issues[0].Title != tt.issue) {
+			t.Errorf("%s: AnalyzeTcpdump() issues = %+v, expected %q", tt.name, result.Issues, tt.issue)
+		}
+	}
+}
+
+func TestAnalyzeTcpdumpKeepalive(t *testing.T) {
+	const router, app = "10.128.2.5", "10.131.0.7"
+	b := newPcapBuilder()
+	ms := time.Millisecond
+	fromRouter := func(at time.Duration, port uint16, seq uint32, flags byte, payload int) {
+		b.packet(at, ethernetIPv4(router, app, 6, tcpSegment(port, 8080, seq, 5001, flags, payload)))
+	}
+	fromApp := func(at time.Duration, port uint16, seq uint32, flags byte, payload int) {
+		b.packet(at, ethernetIPv4(app, router, 6, tcpSegment(8080, port, seq, 1101, flags, payload)))
+	}
+	// Each connection: handshake, then a request answered unless noted
+	exchange := func(port uint16, answered bool) {
+		fromRouter(0, port, 1000, tcpSYN, 0)
+		fromApp(ms, port, 5000, tcpSYN|tcpACK, 0)
+		fromRouter(2*ms, port, 1001, tcpACK, 0)
+		fromRouter(10*ms, port, 1001, tcpACK, 100)
+		if answered {
+			fromApp(20*ms, port, 5001, tcpACK, 200)
+		}
+	}
+
+	// The app closes idle connections after 5s
+	for _, port := range []uint16{50000, 50001} {
+		exchange(port, true)
+		fromApp(5*time.Second+20*ms, port, 5201, tcpFIN|tcpACK, 0)
+	}
+	// A request sent just as the app closes the connection is reset
+	exchange(50002, true)
+	fromApp(5*time.Second+20*ms, 50002, 5201, tcpFIN|tcpACK, 0)
+	fromRouter(5*time.Second+21*ms, 50002, 1101, tcpACK, 100)
+	fromApp(5*time.Second+22*ms, 50002, 5202, tcpRST, 0)
+	// A request crossing the close on the wire: the FIN follows it
+	exchange(50003, true)
+	fromRouter(5*time.Second+19*ms, 50003, 1101, tcpACK, 100)
+	fromApp(5*time.Second+20*ms, 50003, 5201, tcpFIN|tcpACK, 0)
+	// The router closes idle connections after 30s
+	exchange(50004, true)
+	fromRouter(30*time.Second+20*ms, 50004, 1101, tcpFIN|tcpACK, 0)
+	// and gives up on a request after 30s without a response
+	exchange(50005, false)
+	fromRouter(30*time.Second+10*ms, 50005, 1101, tcpFIN|tcpACK, 0)
+
+	result, err := newTestAnalysisEngine().AnalyzeTcpdump(context.Background(), writeCapture(t, b.buf.Bytes()))
+	if err != nil {
+		t.Fatalf("AnalyzeTcpdump() error = %v", err)
+	}
+	for key, value := range map[string]interface{}{"tcp_idle_closes": 5, "tcp_abandoned_requests": 1, "tcp_stale_requests": 2} {
+		if result.Metrics[key] != value {
+			t.Errorf("AnalyzeTcpdump() metric %s = %v, expected %v", key, result.Metrics[key], value)
+		}
+	}
+
+	issues := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, evidence := range map[string]string{
+		"Requests Sent on Connections Closed While Idle": app + ":8080: 2 request(s) raced its close of connections idle for 5s (Node.js server keepAliveTimeout and Apache KeepAliveTimeout default)",
+		"Keep-alive Timeout Mismatch":                    app + ":8080 closes idle connections after 5s (Node.js server keepAliveTimeout and Apache KeepAliveTimeout default), its clients only after 30s (OpenShift router timeout client and timeout server default)",
+		"Requests Abandoned Waiting for a Response":      "clients of " + app + ":8080 gave up on 1 request(s) after 30s (OpenShift router timeout client and timeout server default)",
+		"Connections Closed by Idle Timeout":             app + ":8080 closed 4 connection(s) idle for 5s (Node.js server keepAliveTimeout and Apache KeepAliveTimeout default)",
+	} {
+		issue, ok := issues[title]
+		if !ok || len(issue.Evidence) == 0 || issue.Evidence[0] != evidence {
+			t.Errorf("AnalyzeTcpdump() issue %q = %+v, expected evidence %q", title, issue, evidence)
+		}
+	}
+}
+
+func TestAnalyzeTcpdumpLinuxSLL2(t *testing.T) {
+	// tcpdump -i any writes Linux cooked v2 headers
+	client, server := netip.MustParseAddr("fd00::1"), netip.MustParseAddr("fd00::2")
+	frame := func(src, dst netip.Addr, tcp *layers.TCP) []byte {
+		ip := &layers.IPv6{Version: 6, NextHeader: layers.IPProtocolTCP, HopLimit: 64, SrcIP: src.AsSlice(), DstIP: dst.AsSlice()}
+		tcp.SetNetworkLayerForChecksum(ip)
+		buf := gopacket.NewSerializeBuffer()
+		if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}, ip, tcp); err != nil {
+			t.Fatal(err)
+		}
+		header := make([]byte, 20)
+		binary.BigEndian.PutUint16(header[0:2], uint16(layers.EthernetTypeIPv6))
+		binary.BigEndian.PutUint16(header[8:10], uint16(layers.ARPHardwareTypeEthernet))
+		return append(header, buf.Bytes()...)
+	}
+
+	var capture bytes.Buffer
+	w := pcapgo.NewWriter(&capture)
+	if err := w.WriteFileHeader(65535, layers.LinkTypeLinuxSLL2); err != nil {
+		t.Fatal(err)
+	}
+	for i, data := range [][]byte{
+		frame(client, server, &layers.TCP{SrcPort: 40000, DstPort: 8080, Seq: 1000, SYN: true, Window: 1024}),
+		frame(server, client, &layers.TCP{SrcPort: 8080, DstPort: 40000, Ack: 1001, RST: true, ACK: true}),
+	} {
+		info := gopacket.CaptureInfo{Timestamp: pcapStart.Add(time.Duration(i) * time.Millisecond), CaptureLength: len(data), Length: len(data)}
+		if err := w.WritePacket(info, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := newTestAnalysisEngine().AnalyzeTcpdump(context.Background(), writeCapture(t, capture.Bytes()))
+	if err != nil {
+		t.Fatalf("AnalyzeTcpdump() error = %v", err)
+	}
+	if result.Metrics["packets"] != 2 || result.Metrics["tcp_refused"] != 1 {
+		t.Errorf("AnalyzeTcpdump(Linux SLL2) metrics = %v", result.Metrics)
+	}
+	if protocols := result.Metrics["protocols"].(map[string]int); protocols["tcp"] != 2 {
+		t.Errorf("AnalyzeTcpdump(Linux SLL2) protocols = %v", protocols)
+	}
+}
@@ -0,0 +1,583 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/netip"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+const (
+	// maxPcapFlows bounds each table of connections, talkers and DNS queries
+	// so a capture of a port scan cannot exhaust memory
+	maxPcapFlows = 200000
+
+	// pcapGracePeriod is how long before the end of the capture a SYN or DNS
+	// query must have been sent to count as unanswered
+	pcapGracePeriod = 3 * time.Second
+
+	// slowHandshake is the 95th percentile handshake latency reported as slow
+	slowHandshake = 100 * time.Millisecond
+)
+
+// pcapFlow is one direction of a TCP or UDP conversation
+type pcapFlow struct {
+	src, dst netip.AddrPort
+}
+
+func (f pcapFlow) reverse() pcapFlow {
+	return pcapFlow{src: f.dst, dst: f.src}
+}
+
+func (f pcapFlow) String() string {
+	return fmt.Sprintf("%s → %s", f.src, f.dst)
+}
+
+// tcpHandshake follows a connection attempt from its SYN
+type tcpHandshake struct {
+	synAt    time.Time
+	answered bool
+	refused  bool
+}
+
+// pcapTalker is the traffic sent by one address
+type pcapTalker struct {
+	packets int
+	bytes   int64
+}
+
+// dnsQueryKey matches DNS responses to queries
+type dnsQueryKey struct {
+	client netip.AddrPort
+	id     uint16
+}
+
+type dnsQuery struct {
+	name string
+	at   time.Time
+}
+
+// pcapStats accumulates what the analysis reports about a capture
+type pcapStats struct {
+	packets      int
+	bytes        int64
+	first, last  time.Time
+	protocols    map[string]int
+	talkers      map[netip.Addr]*pcapTalker
+	flowsDropped bool
+	decoder      packetDecoder
+
+	// TCP
+	seqEnd         map[pcapFlow]uint32 // highest sequence number sent per direction
+	handshakes     map[pcapFlow]*tcpHandshake
+	latencies      []time.Duration
+	segments       int
+	retransmits    int
+	retransmitFlow map[pcapFlow]int
+	resets         int
+	resetFlow      map[pcapFlow]int
+	connections    map[pcapFlow]*tcpConnection // keyed from the client
+
+	// DNS
+	queries      map[dnsQueryKey]dnsQuery
+	dnsQueries   int
+	dnsResponses int
+	dnsLatencies []time.Duration
+	dnsFailures  map[string]int // "SERVFAIL example.com" -> count
+	nxdomain     map[string]int
+}
+
+func newPcapStats() *pcapStats {
+	return &pcapStats{
+		protocols:      make(map[string]int),
+		talkers:        make(map[netip.Addr]*pcapTalker),
+		seqEnd:         make(map[pcapFlow]uint32),
+		handshakes:     make(map[pcapFlow]*tcpHandshake),
+		retransmitFlow: make(map[pcapFlow]int),
+		resetFlow:      make(map[pcapFlow]int),
+		connections:    make(map[pcapFlow]*tcpConnection),
+		queries:        make(map[dnsQueryKey]dnsQuery),
+		dnsFailures:    make(map[string]int),
+		nxdomain:       make(map[string]int),
+	}
+}
+
+// roomFor reports whether a table may grow by another entry
+func (st *pcapStats) roomFor(size int) bool {
+	if size >= maxPcapFlows {
+		st.flowsDropped = true
+		return false
+	}
+	return true
+}
+
+// add accounts one packet
+func (st *pcapStats) add(captured pcapPacket) {
+	st.packets++
+	st.bytes += int64(captured.length)
+	if !captured.timestamp.IsZero() {
+		if st.first.IsZero() || captured.timestamp.Before(st.first) {
+			st.first = captured.timestamp
+		}
+		if captured.timestamp.After(st.last) {
+			st.last = captured.timestamp
+		}
+	}
+
+	packet := st.decoder.decode(captured)
+	if packet.network != "ipv4" && packet.network != "ipv6" {
+		st.protocols[packet.network]++
+		return
+	}
+	st.protocols[packet.transport]++
+
+	talker, ok := st.talkers[packet.src]
+	if !ok && st.roomFor(len(st.talkers)) {
+		talker = &pcapTalker{}
+		st.talkers[packet.src] = talker
+	}
+	if talker != nil {
+		talker.packets++
+		talker.bytes += int64(captured.length)
+	}
+
+	flow := pcapFlow{src: netip.AddrPortFrom(packet.src, packet.srcPort), dst: netip.AddrPortFrom(packet.dst, packet.dstPort)}
+	switch packet.transport {
+	case "tcp":
+		st.addTCP(flow, packet, captured.timestamp)
+	case "udp":
+		if isDNSPort(packet.srcPort) || isDNSPort(packet.dstPort) {
+			st.addDNS(flow, packet, captured.timestamp)
+		}
+	}
+}
+
+// isDNSPort matches DNS, including CoreDNS's 5353 behind the cluster DNS service
+func isDNSPort(port uint16) bool {
+	return port == 53 || port == 5353
+}
+
+// seqAfter compares TCP sequence numbers across wraparound
+func seqAfter(a, b uint32) bool {
+	return int32(a-b) > 0
+}
+
+func (st *pcapStats) addTCP(flow pcapFlow, packet decodedPacket, at time.Time) {
+	syn, ack := packet.flags&tcpSYN != 0, packet.flags&tcpACK != 0
+	highest, seen := st.seqEnd[flow]
+	st.trackConnection(flow, packet, at, seen && packet.segmentLength <= 1 && packet.seq == highest-1)
+
+	if packet.flags&tcpRST != 0 {
+		st.resets++
+		st.resetFlow[flow]++
+		// A reset answering a SYN refuses the connection
+		if attempt, ok := st.handshakes[flow.reverse()]; ok && !attempt.answered {
+			attempt.refused = true
+		}
+	}
+
+	switch {
+	case syn && !ack:
+		if attempt, ok := st.handshakes[flow]; ok && !attempt.answered {
+			st.retransmits++
+			st.retransmitFlow[flow]++
+		} else if st.roomFor(len(st.handshakes)) {
+			st.handshakes[flow] = &tcpHandshake{synAt: at}
+		}
+	case syn && ack:
+		if attempt, ok := st.handshakes[flow.reverse()]; ok {
+			if attempt.answered {
+				st.retransmits++
+				st.retransmitFlow[flow]++
+			} else {
+				attempt.answered = true
+				if !at.IsZero() && !attempt.synAt.IsZero() {
+					st.latencies = append(st.latencies, at.Sub(attempt.synAt))
+				}
+			}
+		}
+	}
+
+	length := uint32(packet.segmentLength)
+	if packet.flags&(tcpSYN|tcpFIN) != 0 {
+		length++
+	}
+	if length == 0 {
+		return
+	}
+	if packet.segmentLength > 0 {
+		st.segments++
+	}
+	end := packet.seq + length
+	switch {
+	case !seen:
+		if st.roomFor(len(st.seqEnd)) {
+			st.seqEnd[flow] = end
+		}
+	case seqAfter(end, highest):
+		st.seqEnd[flow] = end
+	case syn:
+		// counted with the handshake
+	case packet.segmentLength <= 1 && packet.seq == highest-1:
+		// keep-alive probe
+	default:
+		st.retransmits++
+		st.retransmitFlow[flow]++
+	}
+}
+
+func (st *pcapStats) addDNS(flow pcapFlow, packet decodedPacket, at time.Time) {
+	message, ok := st.decoder.decodeDNS(packet.payload)
+	if !ok {
+		return
+	}
+	if !message.response {
+		st.dnsQueries++
+		if st.roomFor(len(st.queries)) {
+			st.queries[dnsQueryKey{client: flow.src, id: message.id}] = dnsQuery{name: message.name, at: at}
+		}
+		return
+	}
+
+	st.dnsResponses++
+	key := dnsQueryKey{client: flow.dst, id: message.id}
+	query, asked := st.queries[key]
+	if asked {
+		delete(st.queries, key)
+		if !at.IsZero() && !query.at.IsZero() {
+			st.dnsLatencies = append(st.dnsLatencies, at.Sub(query.at))
+		}
+	}
+	name := message.name
+	if name == "" {
+		name = query.name
+	}
+	switch message.rcode {
+	case layers.DNSResponseCodeNoErr:
+	case layers.DNSResponseCodeNXDomain:
+		st.nxdomain[name]++
+	default:
+		rcode := dnsRcodeNames[message.rcode]
+		if rcode == "" {
+			rcode = fmt.Sprintf("RCODE %d", message.rcode)
+		}
+		st.dnsFailures[rcode+" "+name]++
+	}
+}
+
+// percentile returns the pth percentile of durations, sorting them in place
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	// Nearest rank
+	index := int(math.Ceil(float64(len(durations))*p)) - 1
+	if index < 0 {
+		index = 0
+	}
+	return durations[index]
+}
+
+// milliseconds renders a duration for metrics
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// formatPcapBytes renders a traffic volume
+func formatPcapBytes(bytes int64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return fmt.Sprintf("%.2f MB", float64(bytes)/(1024*1024))
+	case bytes >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
+
+// topCounts returns up to limit "key: n suffix" lines, largest first
+func topCounts[K comparable](counts map[K]int, limit int, format func(K, int) string) []string {
+	keys := make([]K, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	var lines []string
+	for i, key := range keys {
+		if i == limit {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(keys)-limit))
+			break
+		}
+		lines = append(lines, format(key, counts[key]))
+	}
+	return lines
+}
+
+// analyzePcap decodes a capture and reports protocol, TCP and DNS findings
+func (ae *AnalysisEngine) analyzePcap(ctx context.Context, pcapPath string, result *AnalysisResult) error {
+	file, err := os.Open(pcapPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := newPcapReader(file)
+	if err != nil {
+		return err
+	}
+
+	stats := newPcapStats()
+	for {
+		if stats.packets%10000 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		packet, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// A capture cut off by stopping tcpdump still holds useful packets
+			result.Truncated = true
+			result.TruncationReasons = append(result.TruncationReasons,
+				fmt.Sprintf("capture ends mid-packet after %d packets: %v", stats.packets, err))
+			break
+		}
+		stats.add(packet)
+	}
+	if stats.flowsDropped {
+		result.Truncated = true
+		result.TruncationReasons = append(result.TruncationReasons,
+			fmt.Sprintf("more than %d connections, talkers or DNS queries; later ones were not tracked", maxPcapFlows))
+	}
+
+	ae.reportPcap(pcapPath, stats, result)
+	return nil
+}
+
+// reportPcap turns the capture statistics into metrics and issues
+func (ae *AnalysisEngine) reportPcap(pcapPath string, st *pcapStats, result *AnalysisResult) {
+	result.Metrics["packets"] = st.packets
+	result.Metrics["bytes"] = st.bytes
+	if !st.first.IsZero() {
+		result.Metrics["capture_start"] = st.first.Format(time.RFC3339)
+		result.Metrics["capture_duration"] = st.last.Sub(st.first).Round(time.Millisecond).String()
+	}
+	result.Metrics["protocols"] = st.protocols
+
+	talkers := make(map[netip.Addr]int, len(st.talkers))
+	for addr, talker := range st.talkers {
+		talkers[addr] = int(talker.bytes)
+	}
+	result.Metrics["top_talkers"] = topCounts(talkers, 5, func(addr netip.Addr, bytes int) string {
+		return fmt.Sprintf("%s: %s in %d packets", addr, formatPcapBytes(int64(bytes)), st.talkers[addr].packets)
+	})
+
+	ae.reportTCP(pcapPath, st, result)
+	ae.reportKeepalive(pcapPath, st, result)
+	ae.reportDNS(pcapPath, st, result)
+
+	if st.packets == 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "capture",
+			Title:       "No Packets Captured",
+			Description: "The capture file holds no packets",
+			Location:    pcapPath,
+			Resolution:  "Check the interface and filter given to tcpdump, and that traffic flowed while it ran",
+		})
+	}
+}
+
+func (ae *AnalysisEngine) reportTCP(pcapPath string, st *pcapStats, result *AnalysisResult) {
+	var refused, unanswered = make(map[netip.AddrPort]int), make(map[netip.AddrPort]int)
+	for flow, attempt := range st.handshakes {
+		switch {
+		case attempt.refused:
+			refused[flow.dst]++
+		case !attempt.answered && (attempt.synAt.IsZero() || attempt.synAt.Before(st.last.Add(-pcapGracePeriod))):
+			unanswered[flow.dst]++
+		}
+	}
+	refusedCount, unansweredCount := 0, 0
+	for _, n := range refused {
+		refusedCount += n
+	}
+	for _, n := range unanswered {
+		unansweredCount += n
+	}
+
+	rate := 0.0
+	if sent := st.segments + len(st.handshakes); sent > 0 {
+		rate = float64(st.retransmits) / float64(sent) * 100
+	}
+	result.Metrics["tcp_connections"] = len(st.handshakes)
+	result.Metrics["tcp_retransmissions"] = st.retransmits
+	result.Metrics["tcp_retransmission_rate"] = fmt.Sprintf("%.2f%%", rate)
+	result.Metrics["tcp_resets"] = st.resets
+	result.Metrics["tcp_refused"] = refusedCount
+	result.Metrics["tcp_unanswered_syns"] = unansweredCount
+	if len(st.latencies) > 0 {
+		result.Metrics["handshake_p50_ms"] = milliseconds(percentile(st.latencies, 0.5))
+		result.Metrics["handshake_p95_ms"] = milliseconds(percentile(st.latencies, 0.95))
+		result.Metrics["handshake_max_ms"] = milliseconds(st.latencies[len(st.latencies)-1])
+	}
+
+	if st.retransmits >= 10 && rate > 1 {
+		severity := "warning"
+		if rate > 5 {
+			severity = "critical"
+		}
+		ae.addIssue(result, Issue{
+			Severity:    severity,
+			Category:    "network",
+			Title:       "TCP Retransmissions",
+			Description: fmt.Sprintf("%d segments (%.2f%%) were retransmitted, a sign of packet loss or congestion", st.retransmits, rate),
+			Location:    pcapPath,
+			Evidence: topCounts(st.retransmitFlow, 5, func(flow pcapFlow, n int) string {
+				return fmt.Sprintf("%s: %d retransmission(s)", flow, n)
+			}),
+			Resolution: "Check the nodes' NIC errors and drops, MTU mismatches on the overlay network and congested links between the endpoints",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(st.retransmits)},
+		})
+	}
+	if refusedCount > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "Connections Refused",
+			Description: fmt.Sprintf("%d connection attempt(s) were answered with a reset", refusedCount),
+			Location:    pcapPath,
+			Evidence: topCounts(refused, 5, func(dst netip.AddrPort, n int) string {
+				return fmt.Sprintf("%s refused %d time(s)", dst, n)
+			}),
+			Resolution: "Nothing listens on the port: check the target pod is ready, the container port and the Service targetPort",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(refusedCount)},
+		})
+	}
+	if unansweredCount > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "Unanswered Connection Attempts",
+			Description: fmt.Sprintf("%d connection attempt(s) got no SYN-ACK", unansweredCount),
+			Location:    pcapPath,
+			Evidence: topCounts(unanswered, 5, func(dst netip.AddrPort, n int) string {
+				return fmt.Sprintf("%s: %d SYN(s) without reply", dst, n)
+			}),
+			Resolution: "Packets are dropped on the way: check NetworkPolicies, egress firewalls, security groups and that the destination is reachable from the node",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(unansweredCount)},
+		})
+	}
+	if other := st.resets - refusedCount; other > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "network",
+			Title:       "TCP Resets",
+			Description: fmt.Sprintf("%d established connection(s) were reset", other),
+			Location:    pcapPath,
+			Evidence: topCounts(st.resetFlow, 5, func(flow pcapFlow, n int) string {
+				return fmt.Sprintf("%s: %d reset(s)", flow, n)
+			}),
+			Resolution: "Resets after data usually come from idle timeouts on load balancers or applications closing connections abruptly",
+		})
+	}
+	if p95 := percentile(st.latencies, 0.95); p95 > slowHandshake {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "performance",
+			Title:       "Slow TCP Handshakes",
+			Description: fmt.Sprintf("95%% of handshakes took up to %s, more than %s", p95.Round(time.Millisecond), slowHandshake),
+			Location:    pcapPath,
+			Resolution:  "Check the load on the destination, conntrack table usage on the nodes and the latency of the path between them",
+		})
+	}
+}
+
+func (ae *AnalysisEngine) reportDNS(pcapPath string, st *pcapStats, result *AnalysisResult) {
+	if st.dnsQueries == 0 && st.dnsResponses == 0 {
+		return
+	}
+	unanswered := make(map[string]int)
+	for _, query := range st.queries {
+		if query.at.IsZero() || query.at.Before(st.last.Add(-pcapGracePeriod)) {
+			unanswered[query.name]++
+		}
+	}
+	failures, nxdomain, unansweredCount := 0, 0, 0
+	for _, n := range st.dnsFailures {
+		failures += n
+	}
+	for _, n := range st.nxdomain {
+		nxdomain += n
+	}
+	for _, n := range unanswered {
+		unansweredCount += n
+	}
+
+	result.Metrics["dns_queries"] = st.dnsQueries
+	result.Metrics["dns_responses"] = st.dnsResponses
+	result.Metrics["dns_failures"] = failures
+	result.Metrics["dns_nxdomain"] = nxdomain
+	result.Metrics["dns_unanswered"] = unansweredCount
+	if len(st.dnsLatencies) > 0 {
+		result.Metrics["dns_p95_ms"] = milliseconds(percentile(st.dnsLatencies, 0.95))
+	}
+
+	if failures > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "DNS Server Failures",
+			Description: fmt.Sprintf("%d DNS response(s) reported a server failure or refusal", failures),
+			Location:    pcapPath,
+			Evidence: topCounts(st.dnsFailures, 5, func(failure string, n int) string {
+				return fmt.Sprintf("%s (%d)", failure, n)
+			}),
+			Resolution: "Check the dns-default pods in openshift-dns and the upstream resolvers they forward to",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(failures)},
+		})
+	}
+	if unansweredCount > 0 {
+		severity := "warning"
+		if unansweredCount*10 > st.dnsQueries {
+			severity = "critical"
+		}
+		ae.addIssue(result, Issue{
+			Severity:    severity,
+			Category:    "network",
+			Title:       "DNS Queries Without Response",
+			Description: fmt.Sprintf("%d of %d DNS queries got no answer and will have timed out in the client", unansweredCount, st.dnsQueries),
+			Location:    pcapPath,
+			Evidence: topCounts(unanswered, 5, func(name string, n int) string {
+				return fmt.Sprintf("%s (%d)", name, n)
+			}),
+			Resolution: "Check that the DNS pods are running and reachable on UDP 53/5353, and for conntrack races dropping parallel A/AAAA queries",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(unansweredCount)},
+		})
+	}
+	if nxdomain > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "network",
+			Title:       "DNS NXDOMAIN Responses",
+			Description: fmt.Sprintf("%d DNS response(s) said the name does not exist", nxdomain),
+			Location:    pcapPath,
+			Evidence: topCounts(st.nxdomain, 5, func(name string, n int) string {
+				return fmt.Sprintf("%s (%d)", name, n)
+			}),
+			Resolution: "Most come from search domains tried with ndots:5; use fully qualified names ending in a dot, or lower ndots in the pod's dnsConfig",
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package diagnostics
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"time"
+)
+
+// keepaliveMinIdle is the shortest silence before a close that counts as an
+// idle or response timeout rather than the end of an exchange
+const keepaliveMinIdle = time.Second
+
+// knownIdleTimeouts names the defaults an observed idle or response timeout
+// most likely comes from
+var knownIdleTimeouts = map[time.Duration]string{
+	5 * time.Second:    "Node.js server keepAliveTimeout and Apache KeepAliveTimeout default",
+	10 * time.Second:   "OpenShift router timeout http-request default",
+	20 * time.Second:   "Tomcat keepAliveTimeout default",
+	30 * time.Second:   "OpenShift router timeout client and timeout server default",
+	60 * time.Second:   "AWS ALB/ELB idle timeout and nginx proxy_read_timeout default",
+	75 * time.Second:   "nginx keepalive_timeout default",
+	90 * time.Second:   "Go http.Transport IdleConnTimeout default",
+	300 * time.Second:  "OpenShift router timeout http-keep-alive default",
+	350 * time.Second:  "AWS NLB idle timeout",
+	3600 * time.Second: "OpenShift router timeout tunnel default",
+}
+
+// tcpConnection follows the data and the close of one connection, oriented
+// from the side that opened it
+type tcpConnection struct {
+	client, server netip.AddrPort
+
+	lastData       time.Time
+	lastFromClient bool
+	gap            time.Duration // silence before the latest data
+
+	closedAt       time.Time
+	closedByClient bool
+	idle           time.Duration // silence before the close
+	waiting        bool          // the client closed while waiting for a response
+
+	stale     bool          // a request was sent as the server closed the connection
+	staleIdle time.Duration // how long the connection was idle before that
+}
+
+// connKey groups closes by server, by who closed and after how long
+type connKey struct {
+	server   netip.AddrPort
+	byClient bool
+	after    time.Duration
+}
+
+// connection returns the tracked connection a packet belongs to and whether
+// the client sent it, starting to track it if there is room
+func (st *pcapStats) connection(flow pcapFlow, packet decodedPacket) (*tcpConnection, bool) {
+	if conn, ok := st.connections[flow]; ok {
+		return conn, true
+	}
+	if conn, ok := st.connections[flow.reverse()]; ok {
+		return conn, false
+	}
+	if !st.roomFor(len(st.connections)) {
+		return nil, false
+	}
+	syn, ack := packet.flags&tcpSYN != 0, packet.flags&tcpACK != 0
+	// Without the SYN the side on the ephemeral, higher port is the client
+	fromClient := syn && !ack || !syn && flow.src.Port() > flow.dst.Port()
+	conn := &tcpConnection{client: flow.src, server: flow.dst}
+	key := flow
+	if !fromClient {
+		conn.client, conn.server, key = flow.dst, flow.src, flow.reverse()
+	}
+	st.connections[key] = conn
+	return conn, fromClient
+}
+
+// trackConnection times the data and the first FIN or RST of a connection;
+// probe marks a TCP keep-alive probe, which is not data
+func (st *pcapStats) trackConnection(flow pcapFlow, packet decodedPacket, at time.Time, probe bool) {
+	if at.IsZero() {
+		return
+	}
+	conn, fromClient := st.connection(flow, packet)
+	if conn == nil {
+		return
+	}
+
+	if packet.segmentLength > 0 && !probe {
+		// A request after the server closed raced the close
+		if !conn.closedAt.IsZero() && fromClient && !conn.closedByClient && !conn.stale {
+			conn.stale, conn.staleIdle = true, conn.idle
+		}
+		if !conn.lastData.IsZero() {
+			conn.gap = at.Sub(conn.lastData)
+		}
+		conn.lastData, conn.lastFromClient = at, fromClient
+	}
+
+	if packet.flags&(tcpFIN|tcpRST) == 0 || !conn.closedAt.IsZero() {
+		return
+	}
+	conn.closedAt, conn.closedByClient = at, fromClient
+	if conn.lastData.IsZero() {
+		return
+	}
+	conn.idle = at.Sub(conn.lastData)
+	conn.waiting = fromClient && conn.lastFromClient
+	// A close right after the first request following a long silence crossed
+	// it: the server timed the connection out and the request was lost
+	if !fromClient && conn.lastFromClient && conn.gap >= keepaliveMinIdle && conn.idle < keepaliveMinIdle {
+		conn.stale, conn.staleIdle, conn.idle = true, conn.gap, conn.gap
+	}
+}
+
+// roundTimeout rounds an observed timeout to the second its timer was set to
+func roundTimeout(d time.Duration) time.Duration {
+	return d.Round(time.Second)
+}
+
+// describeTimeout renders a timeout with the default it matches, if any
+func describeTimeout(d time.Duration) string {
+	if known, ok := knownIdleTimeouts[d]; ok {
+		return fmt.Sprintf("%s (%s)", d, known)
+	}
+	return d.String()
+}
+
+// reportKeepalive reports connections closed by idle and response timeouts,
+// requests that raced the close of an idle connection, and servers that
+// time out idle connections before their clients do. Through the router the
+// races surface as sporadic 502s and the response timeouts as 504s.
+func (ae *AnalysisEngine) reportKeepalive(pcapPath string, st *pcapStats, result *AnalysisResult) {
+	idleCloses := make(map[connKey]int)
+	waits := make(map[connKey]int)
+	stale := make(map[connKey]int)
+	for _, conn := range st.connections {
+		if conn.closedAt.IsZero() {
+			continue
+		}
+		if conn.stale {
+			stale[connKey{server: conn.server, after: roundTimeout(conn.staleIdle)}]++
+		}
+		if conn.idle < keepaliveMinIdle {
+			continue
+		}
+		key := connKey{server: conn.server, byClient: conn.closedByClient, after: roundTimeout(conn.idle)}
+		if conn.waiting {
+			waits[key]++
+		} else {
+			idleCloses[key]++
+		}
+	}
+	count := func(counts map[connKey]int) int {
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		return total
+	}
+	idleCount, waitCount, staleCount := count(idleCloses), count(waits), count(stale)
+	result.Metrics["tcp_idle_closes"] = idleCount
+	result.Metrics["tcp_abandoned_requests"] = waitCount
+	result.Metrics["tcp_stale_requests"] = staleCount
+
+	// The shortest idle timeout of each server and the longest of its clients
+	serverIdle := make(map[netip.AddrPort]time.Duration)
+	clientIdle := make(map[netip.AddrPort]time.Duration)
+	for key := range idleCloses {
+		if key.byClient {
+			if key.after > clientIdle[key.server] {
+				clientIdle[key.server] = key.after
+			}
+		} else if current, ok := serverIdle[key.server]; !ok || key.after < current {
+			serverIdle[key.server] = key.after
+		}
+	}
+	for key := range stale {
+		if current, ok := serverIdle[key.server]; key.after > 0 && (!ok || key.after < current) {
+			serverIdle[key.server] = key.after
+		}
+	}
+	var mismatches []string
+	for server, idle := range serverIdle {
+		if client, ok := clientIdle[server]; ok && idle < client {
+			mismatches = append(mismatches, fmt.Sprintf("%s closes idle connections after %s, its clients only after %s", server, describeTimeout(idle), describeTimeout(client)))
+		}
+	}
+	sort.Strings(mismatches)
+
+	keepaliveResolution := "The server must keep idle connections open longer than its clients reuse them: raise the application's keep-alive idle timeout (Node.js server.keepAliveTimeout, Go http.Server IdleTimeout, Tomcat keepAliveTimeout, nginx keepalive_timeout) above the router's timeout http-keep-alive and timeout server, or have the client close idle connections sooner"
+	if staleCount > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "Requests Sent on Connections Closed While Idle",
+			Description: fmt.Sprintf("%d request(s) were sent on keep-alive connections the server was closing for being idle; a proxy such as the OpenShift router answers them with 502 or resets the client", staleCount),
+			Location:    pcapPath,
+			Evidence: topCounts(stale, 5, func(key connKey, n int) string {
+				if key.after == 0 {
+					return fmt.Sprintf("%s: %d request(s) after it closed the connection", key.server, n)
+				}
+				return fmt.Sprintf("%s: %d request(s) raced its close of connections idle for %s", key.server, n, describeTimeout(key.after))
+			}),
+			Resolution: keepaliveResolution,
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(staleCount)},
+		})
+	}
+	if len(mismatches) > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "Keep-alive Timeout Mismatch",
+			Description: "Servers time out idle connections before their clients stop reusing them, so some requests are sent just as the server closes the connection",
+			Location:    pcapPath,
+			Evidence:    mismatches,
+			Resolution:  keepaliveResolution,
+		})
+	}
+	if waitCount > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "performance",
+			Title:       "Requests Abandoned Waiting for a Response",
+			Description: fmt.Sprintf("Clients closed %d connection(s) after sending a request that got no response; through the router this is a 504 Gateway Timeout", waitCount),
+			Location:    pcapPath,
+			Evidence: topCounts(waits, 5, func(key connKey, n int) string {
+				return fmt.Sprintf("clients of %s gave up on %d request(s) after %s", key.server, n, describeTimeout(key.after))
+			}),
+			Resolution: "Find why the backend answers slowly (its logs, CPU throttling, slow dependencies); if the requests are legitimately long, raise the route's haproxy.router.openshift.io/timeout annotation",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(waitCount)},
+		})
+	}
+
+	// A timeout hit by a single connection is not a pattern
+	repeated := make(map[connKey]int)
+	for key, n := range idleCloses {
+		if n > 1 {
+			repeated[key] = n
+		}
+	}
+	if len(repeated) > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "network",
+			Title:       "Connections Closed by Idle Timeout",
+			Description: fmt.Sprintf("%d connection(s) were closed after a silence; repeated durations show the idle timeouts in effect", idleCount),
+			Location:    pcapPath,
+			Evidence: topCounts(repeated, 5, func(key connKey, n int) string {
+				if key.byClient {
+					return fmt.Sprintf("clients closed %d connection(s) to %s idle for %s", n, key.server, describeTimeout(key.after))
+				}
+				return fmt.Sprintf("%s closed %d connection(s) idle for %s", key.server, n, describeTimeout(key.after))
+			}),
+			Resolution: "Closing idle connections is normal; it only causes errors when a server's timeout is shorter than its clients', see Keep-alive Timeout Mismatch",
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxShardEvidence bounds the routes listed as evidence of one issue
+const maxShardEvidence = 10
+
+// IngressShard is one IngressController: which routes it selects, whether
+// the operator accepted it and where its router pods run
+type IngressShard struct {
+	Name              string `json:"name"`
+	Domain            string `json:"domain"`
+	RouteSelector     string `json:"route_selector,omitempty"`     // rendered; empty selects every route
+	NamespaceSelector string `json:"namespace_selector,omitempty"` // rendered; empty selects every namespace
+	// RouteLabels are the labels a route needs to be selected, when the
+	// selectors are plain label matches; e.g. "type=public"
+	RouteLabels     string `json:"route_labels,omitempty"`
+	NamespaceLabels string `json:"namespace_labels,omitempty"`
+
+	Admitted  bool   `json:"admitted"`  // the operator accepted the controller
+	Available bool   `json:"available"` // its routers are serving
+	Message   string `json:"message,omitempty"`
+
+	DesiredReplicas int32       `json:"desired_replicas"`
+	Pods            []RouterPod `json:"pods"`
+	Zones           []string    `json:"zones,omitempty"` // zones of the nodes its routers may run on
+}
+
+// Selective reports whether the shard selects routes by label, rather than
+// serving every route in the cluster
+func (s IngressShard) Selective() bool {
+	return s.RouteSelector != "" || s.NamespaceSelector != ""
+}
+
+// RouterPod is one router pod of a shard
+type RouterPod struct {
+	Name  string `json:"name"`
+	Node  string `json:"node"`
+	Zone  string `json:"zone,omitempty"`
+	Ready bool   `json:"ready"`
+}
+
+// ShardedRoute is one route and the shards that select and admit it
+type ShardedRoute struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Host      string            `json:"host"`
+	Selected  []string          `json:"selected,omitempty"` // shards whose selectors match the route
+	Admitted  []string          `json:"admitted,omitempty"`
+	Rejected  map[string]string `json:"rejected,omitempty"` // shard to the reason it did not admit the route
+}
+
+func (r ShardedRoute) key() string {
+	return r.Namespace + "/" + r.Name
+}
+
+// hostShard returns the shard whose wildcard domain the host falls under,
+// the longest domain when several match
+func hostShard(host string, shards []IngressShard) (IngressShard, bool) {
+	var best IngressShard
+	found := false
+	for _, shard := range shards {
+		if shard.Domain == "" || !strings.HasSuffix(host, "."+shard.Domain) {
+			continue
+		}
+		if !found || len(shard.Domain) > len(best.Domain) {
+			best, found = shard, true
+		}
+	}
+	return best, found
+}
+
+// capEvidence keeps the first limit lines of evidence
+func capEvidence(lines []string, limit int) []string {
+	if len(lines) <= limit {
+		return lines
+	}
+	return append(lines[:limit:limit], fmt.Sprintf("... and %d more", len(lines)-limit))
+}
+
+// AnalyzeRouteSharding reports ingress controllers that are not serving,
+// routes no shard admits and why, shards whose selectors overlap, route hosts
+// whose wildcard DNS points at a shard that does not serve them, and router
+// replicas that are not spread across nodes and zones
+func AnalyzeRouteSharding(shards []IngressShard, routes []ShardedRoute, now time.Time) *AnalysisResult {
+	result := &AnalysisResult{
+		Type:      "route-sharding",
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: now,
+	}
+	shards = append([]IngressShard(nil), shards...)
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Name < shards[j].Name })
+	routes = append([]ShardedRoute(nil), routes...)
+	sort.Slice(routes, func(i, j int) bool { return routes[i].key() < routes[j].key() })
+
+	for _, shard := range shards {
+		analyzeShardHealth(shard, result)
+	}
+
+	var unselected, rejected, pending, misrouted []string
+	selectedBy := make(map[string]int)
+	overlaps := make(map[string]int)
+	for _, route := range routes {
+		for _, name := range route.Selected {
+			selectedBy[name]++
+		}
+		if len(route.Selected) > 1 {
+			overlaps[strings.Join(route.Selected, " and ")]++
+		}
+
+		if len(route.Admitted) == 0 {
+			switch {
+			case len(route.Selected) == 0:
+				unselected = append(unselected, route.key()+": "+unselectedFix(route, shards))
+			case len(route.Rejected) > 0:
+				var reasons []string
+				for _, name := range sortedKeys(route.Rejected) {
+					reasons = append(reasons, fmt.Sprintf("%s: %s", name, valueOrUnknown(route.Rejected[name])))
+				}
+				rejected = append(rejected, fmt.Sprintf("%s (%s)", route.key(), strings.Join(reasons, "; ")))
+			default:
+				pending = append(pending, fmt.Sprintf("%s, selected by %s", route.key(), strings.Join(route.Selected, ", ")))
+			}
+			continue
+		}
+
+		// The wildcard record of the host's domain sends its traffic to
+		// that shard's routers
+		if owner, ok := hostShard(route.Host, shards); ok && !hasString(route.Admitted, owner.Name) {
+			misrouted = append(misrouted, fmt.Sprintf("%s: %s is under *.%s of %s, but only %s admitted it", route.key(), route.Host, owner.Domain, owner.Name, strings.Join(route.Admitted, ", ")))
+		}
+	}
+
+	if len(unselected) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("%d route(s) are not selected by any ingress controller", len(unselected)),
+			Description: "No IngressController's routeSelector and namespaceSelector match these routes, so no router serves them and their hosts answer 503 or do not resolve",
+			Location:    "openshift-ingress-operator",
+			Evidence:    capEvidence(unselected, maxShardEvidence),
+			Resolution:  "Label each route or its namespace to match the shard meant to serve it, as suggested per route, or widen a shard's selectors: oc edit ingresscontroller -n openshift-ingress-operator <name>",
+			Metadata:    map[string]string{"check": "unselected", "occurrences": fmt.Sprint(len(unselected))},
+		})
+	}
+	if len(rejected) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("%d route(s) were rejected by the ingress controllers that select them", len(rejected)),
+			Description: "The shard selects the route but refused to admit it, most often because another route already claims the host (HostAlreadyClaimed) or the host is invalid",
+			Location:    "openshift-ingress-operator",
+			Evidence:    capEvidence(rejected, maxShardEvidence),
+			Resolution:  "Read the rejection message with openshift_route_analyze; for HostAlreadyClaimed change the host or delete the older route that claims it",
+			Metadata:    map[string]string{"check": "rejected", "occurrences": fmt.Sprint(len(rejected))},
+		})
+	}
+	if len(pending) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("%d route(s) are selected but not admitted yet", len(pending)),
+			Description: "A shard selects these routes but its routers have not reported on them, which happens when the shard has no running router",
+			Location:    "openshift-ingress-operator",
+			Evidence:    capEvidence(pending, maxShardEvidence),
+			Resolution:  "Check the router pods of the selecting shards: oc get pods -n openshift-ingress -o wide",
+			Metadata:    map[string]string{"check": "pending", "occurrences": fmt.Sprint(len(pending))},
+		})
+	}
+	if len(misrouted) > 0 {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       fmt.Sprintf("%d route host(s) resolve to a shard that does not serve them", len(misrouted)),
+			Description: "The host falls under another shard's wildcard domain, so DNS sends clients to routers that do not have the route and they answer 503",
+			Location:    "openshift-ingress-operator",
+			Evidence:    capEvidence(misrouted, maxShardEvidence),
+			Resolution:  "Give the route a host under the domain of the shard that admits it, or label it for the shard that owns its domain",
+			Metadata:    map[string]string{"check": "host-domain", "occurrences": fmt.Sprint(len(misrouted))},
+		})
+	}
+
+	// Overlaps with a shard that serves everything are almost always the
+	// default controller left without a selector after sharding
+	var unselective []string
+	for _, shard := range shards {
+		if !shard.Selective() {
+			unselective = append(unselective, shard.Name)
+		}
+	}
+	if len(overlaps) > 0 {
+		severity, description := "info", "Several shards admit the same routes, so the routes are reachable through each of them; this is expected when a route is deliberately published on more than one shard"
+		if len(unselective) > 0 && len(shards) > 1 {
+			severity = "warning"
+			description = fmt.Sprintf("%s select every route, so they also admit the routes meant for the other shards and expose them on their domain", strings.Join(unselective, ", "))
+		}
+		result.Issues = append(result.Issues, Issue{
+			Severity:    severity,
+			Category:    "configuration",
+			Title:       "Ingress controller selectors overlap",
+			Description: description,
+			Location:    "openshift-ingress-operator",
+			Evidence: topCounts(overlaps, maxShardEvidence, func(pair string, n int) string {
+				return fmt.Sprintf("%s both select %d route(s)", pair, n)
+			}),
+			Resolution: "Exclude the sharded routes from the catch-all controller, e.g. a routeSelector with matchExpressions {key: type, operator: NotIn, values: [sharded]} on the default IngressController",
+			Metadata:   map[string]string{"check": "overlap"},
+		})
+	}
+	for _, shard := range shards {
+		if shard.Selective() && selectedBy[shard.Name] == 0 && len(routes) > 0 {
+			result.Issues = append(result.Issues, Issue{
+				Severity:    "info",
+				Category:    "configuration",
+				Title:       fmt.Sprintf("Ingress controller %s selects no routes", shard.Name),
+				Description: "No route matches the shard's selectors, so its routers serve nothing",
+				Location:    "ingresscontroller/" + shard.Name,
+				Evidence:    shardSelectorEvidence(shard),
+				Resolution:  "Check the selector for typos against the labels of the routes meant for it, or remove the unused shard",
+				Metadata:    map[string]string{"check": "unused", "shard": shard.Name},
+			})
+		}
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+
+	unadmitted := len(unselected) + len(rejected) + len(pending)
+	result.Metrics["shards"] = len(shards)
+	result.Metrics["routes"] = len(routes)
+	result.Metrics["unadmitted_routes"] = unadmitted
+	result.Metrics["unselected_routes"] = len(unselected)
+	result.Metrics["misrouted_hosts"] = len(misrouted)
+
+	result.Summary = fmt.Sprintf("%d ingress controllers, %d routes, %d not admitted by any shard", len(shards), len(routes), unadmitted)
+	if unadmitted == 0 && len(result.Issues) == 0 {
+		result.Summary += "; sharding looks healthy"
+	}
+	if len(unselected) > 0 {
+		result.Recommendations = append(result.Recommendations, "Fix the unselected routes first, they are down for their users:")
+		result.Recommendations = append(result.Recommendations, capEvidence(unselected, maxShardEvidence)...)
+	}
+	if len(unselective) > 0 && len(shards) > 1 {
+		result.Recommendations = append(result.Recommendations, fmt.Sprintf("Give %s a routeSelector so each route is served by the shard meant for it", strings.Join(unselective, ", ")))
+	}
+	return result
+}
+
+// analyzeShardHealth reports an ingress controller the operator did not
+// accept or whose routers are not serving, and router replicas that share a
+// node or a zone
+func analyzeShardHealth(shard IngressShard, result *AnalysisResult) {
+	location := "ingresscontroller/" + shard.Name
+	if !shard.Admitted || !shard.Available {
+		title := fmt.Sprintf("Ingress controller %s is not available", shard.Name)
+		if !shard.Admitted {
+			title = fmt.Sprintf("Ingress controller %s was not admitted", shard.Name)
+		}
+		var evidence []string
+		if shard.Message != "" {
+			evidence = append(evidence, shard.Message)
+		}
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "critical",
+			Category:    "network",
+			Title:       title,
+			Description: "The routes this shard selects are not served",
+			Location:    location,
+			Evidence:    evidence,
+			Resolution:  fmt.Sprintf("Read its conditions: oc describe ingresscontroller %s -n openshift-ingress-operator; a domain already used by another controller is not admitted", shard.Name),
+			Metadata:    map[string]string{"check": "controller", "shard": shard.Name},
+		})
+	}
+
+	ready := 0
+	nodes := make(map[string]int)
+	zones := make(map[string]int)
+	for _, pod := range shard.Pods {
+		if !pod.Ready {
+			continue
+		}
+		ready++
+		nodes[pod.Node]++
+		if pod.Zone != "" {
+			zones[pod.Zone]++
+		}
+	}
+	if shard.DesiredReplicas > 0 && int32(ready) < shard.DesiredReplicas && shard.Available {
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "availability",
+			Title:       fmt.Sprintf("Ingress controller %s runs %d of %d routers", shard.Name, ready, shard.DesiredReplicas),
+			Description: "Fewer routers are ready than requested, so the shard has less headroom and may not survive losing a node",
+			Location:    location,
+			Evidence:    routerPlacement(shard.Pods),
+			Resolution:  "Check why the router pods are not ready or not scheduled: oc get pods -n openshift-ingress -o wide; routers need nodes matching the controller's nodePlacement, one per node",
+			Metadata:    map[string]string{"check": "replicas", "shard": shard.Name},
+		})
+	}
+	if ready < 2 {
+		return
+	}
+	switch {
+	case len(nodes) == 1:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "availability",
+			Title:       fmt.Sprintf("All routers of %s run on one node", shard.Name),
+			Description: "Losing that node takes the whole shard down",
+			Location:    location,
+			Evidence:    routerPlacement(shard.Pods),
+			Resolution:  "Make more nodes match the controller's nodePlacement so the routers spread out",
+			Metadata:    map[string]string{"check": "placement", "shard": shard.Name},
+		})
+	case len(zones) == 1 && len(shard.Zones) > 1:
+		result.Issues = append(result.Issues, Issue{
+			Severity:    "warning",
+			Category:    "availability",
+			Title:       fmt.Sprintf("All routers of %s run in one zone", shard.Name),
+			Description: fmt.Sprintf("The routers could run in zones %s but all run in one, so a zone outage takes the shard down", strings.Join(shard.Zones, ", ")),
+			Location:    location,
+			Evidence:    routerPlacement(shard.Pods),
+			Resolution:  "Delete one router pod at a time so the scheduler places it in another zone, and check the nodePlacement does not pin the routers to one zone",
+			Metadata:    map[string]string{"check": "placement", "shard": shard.Name},
+		})
+	}
+}
+
+// routerPlacement renders where the routers of a shard run
+func routerPlacement(pods []RouterPod) []string {
+	lines := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		line := fmt.Sprintf("%s on %s", pod.Name, valueOrUnknown(pod.Node))
+		if pod.Zone != "" {
+			line += " in " + pod.Zone
+		}
+		if !pod.Ready {
+			line += " (not ready)"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// shardSelectorEvidence renders the selectors of a shard
+func shardSelectorEvidence(shard IngressShard) []string {
+	var evidence []string
+	if shard.RouteSelector != "" {
+		evidence = append(evidence, "routeSelector: "+shard.RouteSelector)
+	}
+	if shard.NamespaceSelector != "" {
+		evidence = append(evidence, "namespaceSelector: "+shard.NamespaceSelector)
+	}
+	return evidence
+}
+
+// unselectedFix suggests how to get a route selected: by the shard that owns
+// its host's domain when there is one, with the labels that shard needs
+func unselectedFix(route ShardedRoute, shards []IngressShard) string {
+	shard, ok := hostShard(route.Host, shards)
+	if !ok {
+		return fmt.Sprintf("%s is under no shard's domain; set a host under one", valueOrUnknown(route.Host))
+	}
+	var commands []string
+	if shard.RouteLabels != "" {
+		commands = append(commands, fmt.Sprintf("oc label route %s -n %s %s", route.Name, route.Namespace, strings.ReplaceAll(shard.RouteLabels, ",", " ")))
+	}
+	if shard.NamespaceLabels != "" {
+		commands = append(commands, fmt.Sprintf("oc label namespace %s %s", route.Namespace, strings.ReplaceAll(shard.NamespaceLabels, ",", " ")))
+	}
+	if len(commands) == 0 {
+		return fmt.Sprintf("%s is under %s of shard %s; match its selectors (%s)", route.Host, shard.Domain, shard.Name, strings.Join(shardSelectorEvidence(shard), ", "))
+	}
+	return fmt.Sprintf("%s is under %s of shard %s: %s", route.Host, shard.Domain, shard.Name, strings.Join(commands, " && "))
+}
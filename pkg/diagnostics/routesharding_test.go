@@ -0,0 +1,91 @@
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeRouteSharding(t *testing.T) {
+	shards := []IngressShard{
+		{
+			Name: "default", Domain: "apps.example.com", Admitted: true, Available: true, DesiredReplicas: 2,
+			Pods: []RouterPod{
+				{Name: "router-default-a", Node: "worker-1", Zone: "zone-a", Ready: true},
+				{Name: "router-default-b", Node: "worker-2", Zone: "zone-a", Ready: true},
+			},
+			Zones: []string{"zone-a", "zone-b"},
+		},
+		{
+			Name: "internal", Domain: "internal.example.com", RouteSelector: "type=internal", RouteLabels: "type=internal",
+			Admitted: true, Available: true, DesiredReplicas: 2,
+			Pods: []RouterPod{{Name: "router-internal-a", Node: "worker-3", Zone: "zone-b", Ready: true}},
+		},
+		{
+			Name: "public", Domain: "public.example.com", RouteSelector: "type=public", RouteLabels: "type=public",
+			Admitted: true, Message: "Available: The deployment has no available replicas", DesiredReplicas: 2,
+		},
+	}
+	routes := []ShardedRoute{
+		{Namespace: "shop", Name: "web", Host: "web.apps.example.com", Selected: []string{"default"}, Admitted: []string{"default"}},
+		{Namespace: "shop", Name: "api", Host: "api.internal.example.com", Selected: []string{"default", "internal"}, Admitted: []string{"default", "internal"}},
+		{Namespace: "shop", Name: "dup", Host: "web.apps.example.com", Selected: []string{"default"}, Rejected: map[string]string{"default": "HostAlreadyClaimed"}},
+		{Namespace: "shop", Name: "pay", Host: "pay.public.example.com", Selected: []string{"default"}, Admitted: []string{"default"}},
+	}
+
+	result := AnalyzeRouteSharding(shards, routes, time.Now())
+	issues := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, severity := range map[string]string{
+		"Ingress controller public is not available":                           "critical",
+		"Ingress controller internal runs 1 of 2 routers":                      "warning",
+		"All routers of default run in one zone":                               "warning",
+		"1 route(s) were rejected by the ingress controllers that select them": "warning",
+		"1 route host(s) resolve to a shard that does not serve them":          "warning",
+		"Ingress controller selectors overlap":                                 "warning",
+		"Ingress controller public selects no routes":                          "info",
+	} {
+		if issues[title].Severity != severity {
+			t.Errorf("AnalyzeRouteSharding() issue %q = %+v, expected severity %s", title, issues[title], severity)
+		}
+	}
+	if len(result.Issues) != 7 {
+		t.Errorf("AnalyzeRouteSharding() returned %d issues, expected 7: %+v", len(result.Issues), result.Issues)
+	}
+	if evidence := issues["1 route(s) were rejected by the ingress controllers that select them"].Evidence; len(evidence) != 1 || evidence[0] != "shop/dup (default: HostAlreadyClaimed)" {
+		t.Errorf("rejected evidence = %v", evidence)
+	}
+	if evidence := issues["Ingress controller selectors overlap"].Evidence; len(evidence) != 1 || evidence[0] != "default and internal both select 1 route(s)" {
+		t.Errorf("overlap evidence = %v", evidence)
+	}
+	if result.Metrics["unadmitted_routes"] != 1 || result.Metrics["misrouted_hosts"] != 1 {
+		t.Errorf("AnalyzeRouteSharding() metrics = %v", result.Metrics)
+	}
+}
+
+func TestAnalyzeRouteShardingUnselected(t *testing.T) {
+	shards := []IngressShard{
+		{Name: "internal", Domain: "internal.example.com", RouteSelector: "type=internal", RouteLabels: "type=internal", Admitted: true, Available: true},
+		{Name: "public", Domain: "public.example.com", RouteSelector: "type=public", RouteLabels: "type=public", NamespaceSelector: "team=web", NamespaceLabels: "team=web", Admitted: true, Available: true},
+	}
+	routes := []ShardedRoute{
+		{Namespace: "shop", Name: "shop", Host: "shop.public.example.com"},
+		{Namespace: "misc", Name: "legacy", Host: "legacy.example.org"},
+	}
+
+	result := AnalyzeRouteSharding(shards, routes, time.Now())
+	if len(result.Issues) != 3 || result.Issues[0].Title != "2 route(s) are not selected by any ingress controller" {
+		t.Fatalf("AnalyzeRouteSharding() = %+v", result.Issues)
+	}
+	evidence := strings.Join(result.Recommendations, "\n")
+	for _, want := range []string{
+		"shop/shop: shop.public.example.com is under public.example.com of shard public: oc label route shop -n shop type=public && oc label namespace shop team=web",
+		"misc/legacy: legacy.example.org is under no shard's domain",
+	} {
+		if !strings.Contains(evidence, want) {
+			t.Errorf("unselected route fixes missing %q:\n%s", want, evidence)
+		}
+	}
+}
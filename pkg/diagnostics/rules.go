@@ -0,0 +1,269 @@
+package diagnostics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Logs a rule applies to
+const (
+	RuleAppliesLogs     = "logs"     // every analyzed log, the default
+	RuleAppliesOperator = "operator" // only the operator logs of a must-gather
+)
+
+// RulePack is a rule file from the rule directory, encoding a team's known
+// issues as log patterns and as signatures pointing at their bugs or KCS
+// articles. The pack is named after the file unless it names itself.
+type RulePack struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Rules       []Rule       `json:"rules"`
+	KnownIssues []KnownIssue `json:"known_issues,omitempty"`
+
+	Source string `json:"-"` // file the pack was read from
+}
+
+// Rule matches log lines like the built-in patterns. A rule named like a
+// built-in pattern replaces it.
+type Rule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"` // regular expression a line must match
+
+	// Unless skips lines that also match it; Files limits the rule to logs
+	// whose file name matches the glob, e.g. "*etcd*"
+	Unless string `json:"unless,omitempty"`
+	Files  string `json:"files,omitempty"`
+
+	Severity    string `json:"severity"` // critical, warning or info
+	Category    string `json:"category"`
+	Description string `json:"description,omitempty"`
+	Resolution  string `json:"resolution"`
+	Docs        string `json:"docs,omitempty"`    // link to the team's runbook or KB article
+	Applies     string `json:"applies,omitempty"` // logs (default) or operator
+}
+
+// RuleLoad reports a reload of the rule directory
+type RuleLoad struct {
+	Loaded []string // packs read, with their rule counts
+	Failed []string // files skipped, with why
+}
+
+// ParseRulePack reads and validates a rule pack
+func ParseRulePack(filename string, data []byte) (RulePack, error) {
+	var pack RulePack
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return pack, fmt.Errorf("invalid rule pack: %v", err)
+	}
+	if pack.Name == "" {
+		pack.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	if len(pack.Rules) == 0 && len(pack.KnownIssues) == 0 {
+		return pack, fmt.Errorf("no rules defined")
+	}
+	names := make(map[string]bool)
+	for i, rule := range pack.Rules {
+		if _, err := rule.compile(pack.Name); err != nil {
+			return pack, fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		if names[rule.Name] {
+			return pack, fmt.Errorf("rule %s is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	if err := validateKnownIssues(pack.KnownIssues); err != nil {
+		return pack, err
+	}
+	return pack, nil
+}
+
+// compile turns a rule into the log pattern the analysis matches
+func (r Rule) compile(pack string) (LogPattern, error) {
+	if r.Name == "" {
+		return LogPattern{}, fmt.Errorf("a rule has no name")
+	}
+	if r.Pattern == "" {
+		return LogPattern{}, fmt.Errorf("rule %s has no pattern", r.Name)
+	}
+	pattern, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return LogPattern{}, fmt.Errorf("rule %s pattern: %v", r.Name, err)
+	}
+	var unless *regexp.Regexp
+	if r.Unless != "" {
+		if unless, err = regexp.Compile(r.Unless); err != nil {
+			return LogPattern{}, fmt.Errorf("rule %s unless: %v", r.Name, err)
+		}
+	}
+	if r.Files != "" {
+		if _, err := filepath.Match(r.Files, ""); err != nil {
+			return LogPattern{}, fmt.Errorf("rule %s files: %v", r.Name, err)
+		}
+	}
+	switch r.Severity {
+	case "critical", "warning", "info":
+	default:
+		return LogPattern{}, fmt.Errorf("rule %s severity must be critical, warning or info, not %q", r.Name, r.Severity)
+	}
+	switch r.Applies {
+	case "", RuleAppliesLogs, RuleAppliesOperator:
+	default:
+		return LogPattern{}, fmt.Errorf("rule %s applies must be logs or operator, not %q", r.Name, r.Applies)
+	}
+	if r.Category == "" || r.Resolution == "" {
+		return LogPattern{}, fmt.Errorf("rule %s needs a category and a resolution", r.Name)
+	}
+
+	description := r.Description
+	if description == "" {
+		description = r.Name
+	}
+	return LogPattern{
+		Name:        r.Name,
+		Pattern:     pattern,
+		Severity:    r.Severity,
+		Category:    r.Category,
+		Description: description,
+		Resolution:  r.Resolution,
+		Unless:      unless,
+		Files:       r.Files,
+		Docs:        r.Docs,
+		RulePack:    pack,
+	}, nil
+}
+
+// appliesTo reports whether a rule is matched against a pattern set; the
+// operator set extends the one for every log
+func (r Rule) appliesTo(set string) bool {
+	return r.Applies != RuleAppliesOperator || set == RuleAppliesOperator
+}
+
+// LoadRuleDir replaces the rule packs with those in dir. A file that fails
+// to parse is skipped and reported; the others still apply.
+func (ae *AnalysisEngine) LoadRuleDir(dir string) (RuleLoad, error) {
+	var load RuleLoad
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return load, fmt.Errorf("reading rule directory: %v", err)
+	}
+
+	var packs []RulePack
+	defined := make(map[string]bool)
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			load.Failed = append(load.Failed, fmt.Sprintf("%s: %v", entry.Name(), err))
+			continue
+		}
+		pack, err := ParseRulePack(entry.Name(), data)
+		if err != nil {
+			load.Failed = append(load.Failed, fmt.Sprintf("%s: %v", entry.Name(), err))
+			continue
+		}
+		if defined[pack.Name] {
+			load.Failed = append(load.Failed, fmt.Sprintf("%s: rule pack %s is already defined by another file", entry.Name(), pack.Name))
+			continue
+		}
+		defined[pack.Name] = true
+		pack.Source = path
+		packs = append(packs, pack)
+		loaded := fmt.Sprintf("%s (%d rules", pack.Name, len(pack.Rules))
+		if len(pack.KnownIssues) > 0 {
+			loaded += fmt.Sprintf(", %d known issues", len(pack.KnownIssues))
+		}
+		load.Loaded = append(load.Loaded, loaded+")")
+	}
+	ae.SetRulePacks(packs)
+	return load, nil
+}
+
+// SetRulePacks replaces the custom rule packs
+func (ae *AnalysisEngine) SetRulePacks(packs []RulePack) {
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	ae.rulesMu.Lock()
+	defer ae.rulesMu.Unlock()
+	ae.rulePacks = packs
+}
+
+// RulePacks returns the custom rule packs, by name
+func (ae *AnalysisEngine) RulePacks() []RulePack {
+	ae.rulesMu.RLock()
+	defer ae.rulesMu.RUnlock()
+	return append([]RulePack(nil), ae.rulePacks...)
+}
+
+// withRules applies the custom rules of a pattern set to the built-in
+// patterns: rules replace the built-in pattern of the same name and the
+// others are added after them
+func (ae *AnalysisEngine) withRules(patterns []LogPattern, set string) []LogPattern {
+	packs := ae.RulePacks()
+	if len(packs) == 0 {
+		return patterns
+	}
+	index := make(map[string]int, len(patterns))
+	for i, pattern := range patterns {
+		index[pattern.Name] = i
+	}
+	for _, pack := range packs {
+		for _, rule := range pack.Rules {
+			if !rule.appliesTo(set) {
+				continue
+			}
+			pattern, err := rule.compile(pack.Name)
+			if err != nil {
+				continue
+			}
+			if i, ok := index[pattern.Name]; ok {
+				patterns[i] = pattern
+				continue
+			}
+			index[pattern.Name] = len(patterns)
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// rulesKey identifies the custom rules for the analysis cache
+func (ae *AnalysisEngine) rulesKey() string {
+	packs := ae.RulePacks()
+	if len(packs) == 0 {
+		return ""
+	}
+	hash := fnv.New64a()
+	for _, pack := range packs {
+		for _, rule := range pack.Rules {
+			fmt.Fprintf(hash, "%s/%+v\n", pack.Name, rule)
+		}
+	}
+	return fmt.Sprintf("rules=%x", hash.Sum64())
+}
+
+// filePatterns drops the patterns whose files glob does not match the log
+func filePatterns(patterns []LogPattern, path string) []LogPattern {
+	var matching []LogPattern
+	for _, pattern := range patterns {
+		if pattern.Files != "" {
+			if matched, _ := filepath.Match(pattern.Files, filepath.Base(path)); !matched {
+				continue
+			}
+		}
+		matching = append(matching, pattern)
+	}
+	return matching
+}
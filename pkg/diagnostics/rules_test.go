@@ -0,0 +1,134 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const paymentsRules = `
+name: payments
+description: Known issues of the payments services
+rules:
+- name: Ledger Lock Timeout
+  pattern: '(?i)ledger.*lock wait timeout'
+  unless: 'retrying'
+  severity: critical
+  category: database
+  resolution: Check for long-running ledger transactions
+  docs: https://runbooks.example.com/payments/ledger-locks
+- name: Gateway Certificate Pinning
+  pattern: 'pin mismatch'
+  files: '*gateway*'
+  severity: warning
+  category: security
+  resolution: Rotate the pinned gateway certificate
+- name: Permission Denied
+  pattern: 'EACCES'
+  severity: info
+  category: security
+  resolution: Expected while the sidecar starts
+`
+
+func TestParseRulePack(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no rules", "name: empty\n", "no rules defined"},
+		{"bad pattern", "rules:\n- {name: x, pattern: '(', severity: info, category: c, resolution: r}\n", "rule x pattern"},
+		{"bad severity", "rules:\n- {name: x, pattern: a, severity: high, category: c, resolution: r}\n", `severity must be critical, warning or info, not "high"`},
+		{"no resolution", "rules:\n- {name: x, pattern: a, severity: info, category: c}\n", "needs a category and a resolution"},
+		{"bad applies", "rules:\n- {name: x, pattern: a, severity: info, category: c, resolution: r, applies: nodes}\n", "applies must be logs or operator"},
+		{"duplicate", "rules:\n- {name: x, pattern: a, severity: info, category: c, resolution: r}\n- {name: x, pattern: b, severity: info, category: c, resolution: r}\n", "rule x is defined twice"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseRulePack("pack.yaml", []byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ParseRulePack() error = %v, expected %q", tt.name, err, tt.want)
+		}
+	}
+
+	pack, err := ParseRulePack("team-a.yaml", []byte("rules:\n- {name: x, pattern: a, severity: info, category: c, resolution: r}\n"))
+	if err != nil || pack.Name != "team-a" {
+		t.Errorf("ParseRulePack() = %+v, %v; expected a pack named after the file", pack, err)
+	}
+}
+
+func TestAnalyzeLogsWithRulePacks(t *testing.T) {
+	rules := t.TempDir()
+	if err := os.WriteFile(filepath.Join(rules, "payments.yaml"), []byte(paymentsRules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rules, "broken.yaml"), []byte("rules: [}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logs := t.TempDir()
+	for name, content := range map[string]string{
+		"ledger.log":  "ledger: lock wait timeout, retrying\nledger: Lock wait timeout exceeded\nopen /data: EACCES\n",
+		"gateway.log": "tls: pin mismatch for api.bank.example.com\n",
+		"web.log":     "tls: pin mismatch for cdn.example.com\n",
+	} {
+		if err := os.WriteFile(filepath.Join(logs, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := newTestEngine()
+	load, err := engine.LoadRuleDir(rules)
+	if err != nil {
+		t.Fatalf("LoadRuleDir() error = %v", err)
+	}
+	if !reflect.DeepEqual(load.Loaded, []string{"payments (3 rules)"}) || len(load.Failed) != 1 || !strings.HasPrefix(load.Failed[0], "broken.yaml: ") {
+		t.Errorf("LoadRuleDir() = %+v", load)
+	}
+
+	result, err := engine.AnalyzeLogs(context.Background(), logs)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	found := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		found[filepath.Base(strings.Split(issue.Location, ":")[0])+"/"+issue.Title] = issue
+	}
+
+	ledger, ok := found["ledger.log/Ledger Lock Timeout"]
+	if !ok {
+		t.Fatalf("missing the ledger rule issue, found %v", found)
+	}
+	if ledger.Severity != "critical" || ledger.Metadata["occurrences"] != "1" || ledger.Metadata["first_line"] != "2" {
+		t.Errorf("ledger issue = %+v; the retrying line should be skipped", ledger)
+	}
+	if ledger.Metadata["docs"] != "https://runbooks.example.com/payments/ledger-locks" || ledger.Metadata["rule_pack"] != "payments" {
+		t.Errorf("ledger issue metadata = %v", ledger.Metadata)
+	}
+	if _, ok := found["gateway.log/Gateway Certificate Pinning"]; !ok {
+		t.Errorf("missing the gateway rule issue, found %v", found)
+	}
+	if _, ok := found["web.log/Gateway Certificate Pinning"]; ok {
+		t.Error("the gateway rule matched a log outside its files glob")
+	}
+	if denied, ok := found["ledger.log/Permission Denied"]; !ok || denied.Severity != "info" {
+		t.Errorf("the rule did not replace the built-in Permission Denied pattern: %+v", denied)
+	}
+
+	// Removing the pack stops its rules from applying, despite the cache
+	if err := os.Remove(filepath.Join(rules, "payments.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.LoadRuleDir(rules); err != nil {
+		t.Fatalf("LoadRuleDir() error = %v", err)
+	}
+	result, err = engine.AnalyzeLogs(context.Background(), logs)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Metadata["rule_pack"] != "" {
+			t.Errorf("issue %s from a removed rule pack", issue.Title)
+		}
+	}
+}
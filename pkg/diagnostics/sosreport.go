@@ -0,0 +1,356 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultSosReportImage runs sos against the node mounted at /host, like
+// the toolbox of oc debug node
+const DefaultSosReportImage = "registry.redhat.io/rhel9/support-tools:latest"
+
+const (
+	// defaultSosReportTimeout bounds a collection, from creating the debug
+	// pod to downloading the archive
+	defaultSosReportTimeout = 15 * time.Minute
+	// sosReportHostDir is where sos writes on the node, outside the
+	// container's overlay so large reports do not fill it
+	sosReportHostDir = "/host/var/tmp/openshift-mcp-sos"
+	// sosReportCleanupTimeout bounds removing the debug pod after a failure
+	// or cancellation, when the collection's context is already done
+	sosReportCleanupTimeout = 30 * time.Second
+)
+
+var (
+	// sosPluginPattern matches sos plugin names such as crio or openshift_ovn
+	sosPluginPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+	// nodeNamePattern matches a Kubernetes node name
+	nodeNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+	// sosArchivePattern finds the archive sos reports having written
+	sosArchivePattern = regexp.MustCompile(`(/\S+/sosreport-\S+\.tar\.(?:xz|gz|bz2))`)
+)
+
+// sosReportRun describes one sosreport collection
+type sosReportRun struct {
+	namespace string // temporary namespace holding the debug pod
+	pod       string
+	node      string
+	image     string
+	plugins   []string
+	timeout   time.Duration
+}
+
+// tmpDir is where sos writes this run's archive in the debug pod
+func (run sosReportRun) tmpDir() string {
+	return path.Join(sosReportHostDir, run.namespace)
+}
+
+// sosReportManifest is what oc debug node creates: a temporary namespace
+// allowed to run privileged pods and a pod on the node sharing its network,
+// PID and IPC namespaces with the host filesystem mounted at /host
+func sosReportManifest(run sosReportRun) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: %[1]s
+    labels:
+      app.kubernetes.io/managed-by: openshift-mcp
+      openshift.io/run-level: "0"
+      pod-security.kubernetes.io/enforce: privileged
+      pod-security.kubernetes.io/audit: privileged
+      pod-security.kubernetes.io/warn: privileged
+      security.openshift.io/scc.podSecurityLabelSync: "false"
+    annotations:
+      openshift.io/node-selector: ""
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: %[2]s
+    namespace: %[1]s
+    labels:
+      app.kubernetes.io/name: sosreport
+      app.kubernetes.io/managed-by: openshift-mcp
+  spec:
+    nodeName: %[3]q
+    hostNetwork: true
+    hostPID: true
+    hostIPC: true
+    restartPolicy: Never
+    activeDeadlineSeconds: %[5]d
+    tolerations:
+    - operator: Exists
+    containers:
+    - name: sos
+      image: %[4]q
+      command: ["/bin/sh", "-c", "sleep %[5]d"]
+      env:
+      - name: HOST
+        value: /host
+      securityContext:
+        privileged: true
+        runAsUser: 0
+      volumeMounts:
+      - name: host
+        mountPath: /host
+    volumes:
+    - name: host
+      hostPath:
+        path: /
+        type: Directory
+`, run.namespace, run.pod, run.node, run.image, int(run.timeout.Seconds()))
+}
+
+// sosReportArgs is the sos command run in the debug pod
+func sosReportArgs(run sosReportRun) []string {
+	args := []string{"sos", "report", "--batch", "--quiet", "--sysroot", "/host",
+		"--tmp-dir", run.tmpDir(), "--label", run.node}
+	crio := len(run.plugins) == 0
+	if len(run.plugins) > 0 {
+		args = append(args, "--only-plugins", strings.Join(run.plugins, ","))
+		for _, plugin := range run.plugins {
+			crio = crio || plugin == "crio"
+		}
+	}
+	if crio {
+		// Container runtime state and logs are what OpenShift support asks for
+		args = append(args, "-k", "crio.all=on", "-k", "crio.logs=on")
+	}
+	return args
+}
+
+// parseSosReportPlugins parses the comma-separated plugin filter
+func parseSosReportPlugins(value string) ([]string, error) {
+	var plugins []string
+	for _, plugin := range strings.Split(value, ",") {
+		plugin = strings.TrimSpace(plugin)
+		if plugin == "" {
+			continue
+		}
+		if !sosPluginPattern.MatchString(plugin) {
+			return nil, fmt.Errorf("invalid sos plugin name %q", plugin)
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
+
+// runOC runs oc, returning its error with the last line of stderr
+func (dc *DiagnosticCollector) runOC(ctx context.Context, stdin io.Reader, stdout io.Writer, args ...string) error {
+	command := dc.command
+	if command == nil {
+		command = exec.CommandContext
+	}
+	cmd := command(ctx, "oc", args...)
+	cmd.WaitDelay = 10 * time.Second
+	var stderr bytes.Buffer
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			return fmt.Errorf("%v: %s", err, last)
+		}
+		return err
+	}
+	return nil
+}
+
+// randomSuffix names temporary objects
+func randomSuffix() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// CollectSosReport runs sos report on a node through a privileged debug
+// pod, like oc debug node, and downloads the archive. The comma-separated
+// "plugins" filter limits the plugins sos runs, "timeout" bounds the whole
+// collection (default 15m) and "image" replaces the support-tools image.
+// The debug pod and the report on the node are removed on success, failure
+// and cancellation.
+func (dc *DiagnosticCollector) CollectSosReport(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
+	start := time.Now()
+	result := &CollectionResult{
+		Type:     "sosreport",
+		Metadata: make(map[string]string),
+	}
+
+	if opts.NodeName == "" {
+		return nil, fmt.Errorf("node name is required for sosreport collection")
+	}
+	if !nodeNamePattern.MatchString(opts.NodeName) {
+		return nil, fmt.Errorf("invalid node name %q", opts.NodeName)
+	}
+	plugins, err := parseSosReportPlugins(opts.Filters["plugins"])
+	if err != nil {
+		return nil, err
+	}
+	timeout := defaultSosReportTimeout
+	if value := opts.Filters["timeout"]; value != "" {
+		if timeout, err = time.ParseDuration(value); err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", value)
+		}
+	}
+	image := DefaultSosReportImage
+	if value := strings.TrimSpace(opts.Filters["image"]); value != "" {
+		if strings.ContainsAny(value, " \t\n\"") {
+			return nil, fmt.Errorf("invalid image %q", value)
+		}
+		image = value
+	}
+
+	run := sosReportRun{
+		namespace: "openshift-mcp-sos-" + randomSuffix(),
+		pod:       "sosreport",
+		node:      opts.NodeName,
+		image:     image,
+		plugins:   plugins,
+		timeout:   timeout,
+	}
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("sosreport-%s-%d", opts.NodeName, start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %v", err)
+	}
+
+	result.FilePath = outputDir
+	result.Metadata["node"] = run.node
+	result.Metadata["namespace"] = run.namespace
+	result.Metadata["pod_name"] = run.pod
+	result.Metadata["image"] = run.image
+	result.Metadata["plugins"] = strings.Join(plugins, ",")
+	fail := func(err error) (*CollectionResult, error) {
+		result.Duration = time.Since(start)
+		result.Status = "failed"
+		result.ErrorMsg = err.Error()
+		dc.logger.Warnf("Sosreport collection on node %s failed: %v", run.node, err)
+		return result, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dc.logger.Infof("Creating sosreport debug pod %s/%s on node %s", run.namespace, run.pod, run.node)
+	if err := dc.runOC(runCtx, strings.NewReader(sosReportManifest(run)), io.Discard, "create", "-f", "-"); err != nil {
+		return fail(fmt.Errorf("creating debug pod: %v", err))
+	}
+	podCreated := false
+	defer func() {
+		// The collection's context may be done; cleanup gets its own
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), sosReportCleanupTimeout)
+		defer cancel()
+		if podCreated {
+			if err := dc.runOC(cleanupCtx, nil, io.Discard, "exec", "-n", run.namespace, run.pod, "--", "rm", "-rf", run.tmpDir()); err != nil {
+				dc.logger.Warnf("Failed to remove %s on node %s: %v", run.tmpDir(), run.node, err)
+			}
+		}
+		if err := dc.runOC(cleanupCtx, nil, io.Discard, "delete", "namespace", run.namespace, "--ignore-not-found", "--wait=false"); err != nil {
+			dc.logger.Warnf("Failed to delete sosreport namespace %s: %v", run.namespace, err)
+		}
+	}()
+
+	remaining := time.Until(start.Add(timeout)).Round(time.Second)
+	if err := dc.runOC(runCtx, nil, io.Discard, "wait", "--for=condition=Ready", "pod/"+run.pod, "-n", run.namespace,
+		"--timeout="+remaining.String()); err != nil {
+		return fail(timeoutError(runCtx, ctx, timeout, fmt.Errorf("waiting for the debug pod: %v", err)))
+	}
+	podCreated = true
+
+	podExec := func(stdout io.Writer, command ...string) error {
+		return dc.runOC(runCtx, nil, stdout, append([]string{"exec", "-n", run.namespace, run.pod, "-c", "sos", "--"}, command...)...)
+	}
+	dc.logger.Infof("Running sos report on node %s", run.node)
+	if err := podExec(io.Discard, "mkdir", "-p", run.tmpDir()); err != nil {
+		return fail(timeoutError(runCtx, ctx, timeout, fmt.Errorf("preparing %s: %v", run.tmpDir(), err)))
+	}
+	var sosOutput bytes.Buffer
+	if err := podExec(&sosOutput, sosReportArgs(run)...); err != nil {
+		return fail(timeoutError(runCtx, ctx, timeout, fmt.Errorf("sos report: %v", err)))
+	}
+	match := sosArchivePattern.FindStringSubmatch(sosOutput.String())
+	if match == nil {
+		return fail(fmt.Errorf("sos report did not name an archive: %s", strings.TrimSpace(sosOutput.String())))
+	}
+	remoteArchive := match[1]
+	result.Metadata["remote_archive"] = remoteArchive
+
+	localArchive := filepath.Join(outputDir, path.Base(remoteArchive))
+	file, err := os.Create(localArchive)
+	if err != nil {
+		return fail(err)
+	}
+	hash := sha256.New()
+	counter := &countingWriter{}
+	err = podExec(io.MultiWriter(file, hash, counter), "cat", remoteArchive)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(localArchive)
+		return fail(timeoutError(runCtx, ctx, timeout, fmt.Errorf("downloading %s: %v", remoteArchive, err)))
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	// sos writes the archive's checksum next to it; a mismatch means the
+	// download was cut short
+	var expected bytes.Buffer
+	if err := podExec(&expected, "cat", remoteArchive+".sha256"); err == nil {
+		if fields := strings.Fields(expected.String()); len(fields) > 0 && !strings.EqualFold(fields[0], checksum) {
+			os.Remove(localArchive)
+			return fail(fmt.Errorf("checksum mismatch for %s: sos reported %s, downloaded %s", path.Base(remoteArchive), fields[0], checksum))
+		}
+		result.Metadata["checksum_verified"] = "true"
+	}
+
+	result.Duration = time.Since(start)
+	result.FilePath = localArchive
+	result.Size = counter.n
+	result.Metadata["sha256"] = checksum
+	result.Status = "completed"
+	result.Summary = fmt.Sprintf("Sosreport collected from node %s: %s (%.2f MB, sha256 %s)",
+		run.node, localArchive, float64(result.Size)/(1024*1024), checksum)
+	dc.finishCollection("", result, opts)
+
+	dc.logger.Infof("Sosreport collection completed: %s", result.Summary)
+	return result, nil
+}
+
+// timeoutError explains an error caused by the collection's own timeout
+func timeoutError(runCtx, parent context.Context, timeout time.Duration, err error) error {
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+		return fmt.Errorf("sosreport collection timed out after %s", timeout)
+	}
+	return err
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,144 @@
+package diagnostics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const sosArchiveContent = "sosreport archive"
+
+// fakeSosOC records oc calls and answers them like a cluster running sos
+type fakeSosOC struct {
+	mu       sync.Mutex
+	calls    []string
+	manifest string // file oc create writes the piped manifest to
+	sos      string // script for the sos report exec
+	checksum string // content of the archive's .sha256
+}
+
+func (f *fakeSosOC) collector(t *testing.T) *DiagnosticCollector {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+	f.manifest = filepath.Join(t.TempDir(), "manifest.yaml")
+	dc.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		call := strings.Join(args, " ")
+		f.mu.Lock()
+		f.calls = append(f.calls, call)
+		f.mu.Unlock()
+		script := "true"
+		switch {
+		case args[0] == "create":
+			script = "cat > " + f.manifest
+		case strings.Contains(call, " sos report "):
+			script = f.sos
+		case strings.HasSuffix(call, ".tar.xz.sha256"):
+			script = "echo '" + f.checksum + "'"
+		case strings.Contains(call, " cat /"):
+			script = "printf '" + sosArchiveContent + "'"
+		}
+		return exec.CommandContext(ctx, "sh", "-c", script)
+	}
+	return dc
+}
+
+func (f *fakeSosOC) called(prefix string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, call := range f.calls {
+		if strings.HasPrefix(call, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCollectSosReport(t *testing.T) {
+	sum := sha256.Sum256([]byte(sosArchiveContent))
+	fake := &fakeSosOC{
+		sos:      `echo "Your sosreport has been generated and saved in:"; echo "	/host/var/tmp/openshift-mcp-sos/x/sosreport-worker-1-2026-10-16-abcdef.tar.xz"`,
+		checksum: hex.EncodeToString(sum[:]),
+	}
+	dc := fake.collector(t)
+	outputDir := t.TempDir()
+	result, err := dc.CollectSosReport(context.Background(), &CollectionOptions{
+		NodeName:  "worker-1",
+		OutputDir: outputDir,
+		Filters:   map[string]string{"plugins": "crio, networking", "timeout": "5m"},
+	})
+	if err != nil {
+		t.Fatalf("CollectSosReport() error = %v", err)
+	}
+	if result.Status != "completed" || result.Size != int64(len(sosArchiveContent)) || result.Metadata["sha256"] != fake.checksum || result.Metadata["checksum_verified"] != "true" {
+		t.Errorf("CollectSosReport() = %+v", result)
+	}
+	data, err := os.ReadFile(result.FilePath)
+	if err != nil || string(data) != sosArchiveContent || !strings.HasPrefix(result.FilePath, outputDir) {
+		t.Errorf("archive %s = %q, %v", result.FilePath, data, err)
+	}
+
+	var sosCall string
+	for _, call := range fake.calls {
+		if strings.Contains(call, " sos report ") {
+			sosCall = call
+		}
+	}
+	for _, arg := range []string{"--only-plugins crio,networking", "-k crio.logs=on", "--label worker-1", "--batch"} {
+		if !strings.Contains(sosCall, arg) {
+			t.Errorf("sos command %q missing %q", sosCall, arg)
+		}
+	}
+	namespace := result.Metadata["namespace"]
+	manifest, _ := os.ReadFile(fake.manifest)
+	for _, text := range []string{"name: " + namespace, `nodeName: "worker-1"`, "privileged: true", "hostPID: true", "activeDeadlineSeconds: 300"} {
+		if !strings.Contains(string(manifest), text) {
+			t.Errorf("debug pod manifest missing %q:\n%s", text, manifest)
+		}
+	}
+	if !fake.called("exec -n "+namespace+" sosreport -- rm -rf /host/var/tmp/openshift-mcp-sos/"+namespace) || !fake.called("delete namespace "+namespace) {
+		t.Errorf("debug pod was not cleaned up: %v", fake.calls)
+	}
+}
+
+func TestCollectSosReportFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		sos      string
+		checksum string
+		expected string
+	}{
+		{"sos fails", "echo 'sos: plugin not found' >&2; exit 1", "", "sos: plugin not found"},
+		{"no archive", "echo 'done'", "", "did not name an archive"},
+		{"checksum mismatch", `echo "/host/var/tmp/openshift-mcp-sos/x/sosreport-worker-1.tar.xz"`, "0123abcd", "checksum mismatch"},
+	}
+	for _, tt := range tests {
+		fake := &fakeSosOC{sos: tt.sos, checksum: tt.checksum}
+		result, err := fake.collector(t).CollectSosReport(context.Background(), &CollectionOptions{NodeName: "worker-1", OutputDir: t.TempDir()})
+		if err == nil || !strings.Contains(err.Error(), tt.expected) || result.Status != "failed" {
+			t.Errorf("%s: CollectSosReport() = %+v, %v, expected an error containing %q", tt.name, result, err, tt.expected)
+		}
+		if !fake.called("delete namespace " + result.Metadata["namespace"]) {
+			t.Errorf("%s: debug namespace was not deleted: %v", tt.name, fake.calls)
+		}
+	}
+
+	for _, opts := range []*CollectionOptions{
+		{NodeName: "Worker_1"},
+		{NodeName: "worker-1", Filters: map[string]string{"plugins": "crio;reboot"}},
+		{NodeName: "worker-1", Filters: map[string]string{"timeout": "soon"}},
+	} {
+		if _, err := (&fakeSosOC{}).collector(t).CollectSosReport(context.Background(), opts); err == nil {
+			t.Errorf("CollectSosReport(%+v) returned no error", opts)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package diagnostics
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stack trace languages recognised by the analyzer
+const (
+	TraceLanguageJava   = "java"
+	TraceLanguageGo     = "go"
+	TraceLanguagePython = "python"
+)
+
+var traceLanguageNames = map[string]string{
+	TraceLanguageJava:   "Java",
+	TraceLanguageGo:     "Go",
+	TraceLanguagePython: "Python",
+}
+
+// maxStackTraceLines is how many lines of one trace are kept as evidence;
+// the rest are counted and summarised
+const maxStackTraceLines = 50
+
+var (
+	javaExceptionLine = regexp.MustCompile(`(?:^|\s)((?:[a-zA-Z_$][\w$]*\.)+[A-Z][\w$]*(?:Exception|Error|Throwable))(?::|\s*$)`)
+	javaFrameLine     = regexp.MustCompile(`^\s+at\s+([\w$.<>/]+)\(`)
+	javaCausedByLine  = regexp.MustCompile(`^\s*Caused by:\s*([\w$.]+)`)
+	javaMoreLine      = regexp.MustCompile(`^\s+\.\.\.\s+\d+\s+(?:more|common frames omitted)`)
+	javaSuppressed    = regexp.MustCompile(`^\s*Suppressed:\s`)
+
+	goPanicLine     = regexp.MustCompile(`^(?:panic|fatal error):\s*(.*)$`)
+	goGoroutineLine = regexp.MustCompile(`^goroutine \d+ \[.*\]:$`)
+	goFuncLine      = regexp.MustCompile(`^([^\s(]+)\(.*\)$`)
+	goFileLine      = regexp.MustCompile(`^\t\S+\.(?:go|s):\d+`)
+	goExtraLine     = regexp.MustCompile(`^(?:\[signal |created by |exit status |\s*\[recovered\])`)
+
+	pythonTracebackLine = regexp.MustCompile(`^\s*Traceback \(most recent call last\):`)
+	pythonFrameLine     = regexp.MustCompile(`^\s+File "([^"]+)", line \d+, in (\S+)`)
+	pythonExceptionLine = regexp.MustCompile(`^([A-Za-z_][\w.]*)(?::\s.*)?$`)
+
+	// volatileTokens are stripped from panic messages so traces that only
+	// differ by addresses, ids or sizes share a signature
+	volatileTokens = regexp.MustCompile(`0x[0-9a-fA-F]+|\d+`)
+)
+
+// stackTrace is a multi-line trace collected from a log
+type stackTrace struct {
+	Language  string
+	Exception string // exception type or normalised panic message
+	RootCause string // innermost "Caused by" exception, if any
+	TopFrame  string // frame where the exception was raised
+	StartLine int
+	Lines     []string
+	Preceding string // log line before the trace, which often carries the timestamp
+
+	frames  int
+	omitted int
+	closed  bool // a Python trace ends at its exception line
+}
+
+// Signature groups traces raised by the same exception at the same place
+func (t *stackTrace) Signature() string {
+	return strings.Join([]string{t.Language, t.Exception, t.RootCause, t.TopFrame}, "|")
+}
+
+// Text renders the kept trace lines as a single evidence entry
+func (t *stackTrace) Text() string {
+	text := strings.Join(t.Lines, "\n")
+	if t.omitted > 0 {
+		text += fmt.Sprintf("\n\t... %d more lines", t.omitted)
+	}
+	return text
+}
+
+func (t *stackTrace) add(line string) {
+	if len(t.Lines) < maxStackTraceLines {
+		t.Lines = append(t.Lines, line)
+	} else {
+		t.omitted++
+	}
+}
+
+// stackTraceDetector groups consecutive log lines into stack traces
+type stackTraceDetector struct {
+	current  *stackTrace
+	previous string
+}
+
+// feed passes the next log line to the detector. It returns a trace when the
+// line ended one, and reports whether the line was a continuation of a trace
+// (a frame rather than a log message) so callers can skip single-line patterns.
+func (d *stackTraceDetector) feed(lineNum int, line string) (*stackTrace, bool) {
+	var done *stackTrace
+	if d.current != nil {
+		if d.continues(line) {
+			return nil, true
+		}
+		done = d.finish()
+	}
+
+	d.current = startStackTrace(lineNum, line)
+	if d.current != nil {
+		d.current.Preceding = d.previous
+	}
+	d.previous = line
+	return done, false
+}
+
+// finish returns the trace in progress, if it turned out to be a real trace
+func (d *stackTraceDetector) finish() *stackTrace {
+	trace := d.current
+	d.current = nil
+	if trace == nil || trace.frames == 0 {
+		return nil
+	}
+	return trace
+}
+
+// startStackTrace returns a candidate trace when a line looks like the
+// first line of one; it only becomes a trace once frames follow
+func startStackTrace(lineNum int, line string) *stackTrace {
+	switch {
+	case pythonTracebackLine.MatchString(line):
+		return &stackTrace{Language: TraceLanguagePython, StartLine: lineNum, Lines: []string{line}}
+	case goPanicLine.MatchString(line):
+		message := goPanicLine.FindStringSubmatch(line)[1]
+		return &stackTrace{Language: TraceLanguageGo, StartLine: lineNum, Lines: []string{line},
+			Exception: strings.TrimSpace(volatileTokens.ReplaceAllString(message, "N"))}
+	}
+	if match := javaExceptionLine.FindStringSubmatch(line); match != nil {
+		return &stackTrace{Language: TraceLanguageJava, StartLine: lineNum, Lines: []string{line}, Exception: match[1]}
+	}
+	return nil
+}
+
+// continues reports whether a line belongs to the trace in progress and
+// records it if so
+func (d *stackTraceDetector) continues(line string) bool {
+	t := d.current
+	switch t.Language {
+	case TraceLanguageJava:
+		if match := javaFrameLine.FindStringSubmatch(line); match != nil {
+			if t.TopFrame == "" {
+				t.TopFrame = match[1]
+			}
+			t.frames++
+		} else if match := javaCausedByLine.FindStringSubmatch(line); match != nil && t.frames > 0 {
+			t.RootCause = match[1]
+		} else if !(t.frames > 0 && (javaMoreLine.MatchString(line) || javaSuppressed.MatchString(line))) {
+			return false
+		}
+
+	case TraceLanguageGo:
+		switch {
+		case strings.TrimSpace(line) == "":
+			// Goroutines are separated by blank lines
+		case goGoroutineLine.MatchString(line), goFileLine.MatchString(line), goExtraLine.MatchString(line):
+		case goFuncLine.MatchString(line):
+			name := goFuncLine.FindStringSubmatch(line)[1]
+			if t.TopFrame == "" && !strings.HasPrefix(name, "panic") && !strings.HasPrefix(name, "runtime.") {
+				t.TopFrame = name
+			}
+			t.frames++
+		default:
+			return false
+		}
+
+	case TraceLanguagePython:
+		if t.closed {
+			return false
+		}
+		switch {
+		case pythonFrameLine.MatchString(line):
+			// Most recent call last: the last frame raised the exception
+			match := pythonFrameLine.FindStringSubmatch(line)
+			t.TopFrame = match[1] + ":" + match[2]
+			t.frames++
+		case strings.HasPrefix(line, " "), strings.HasPrefix(line, "\t"):
+			// Source line of the previous frame
+		case pythonExceptionLine.MatchString(line) && t.frames > 0:
+			t.Exception = pythonExceptionLine.FindStringSubmatch(line)[1]
+			t.closed = true
+		default:
+			return false
+		}
+
+	default:
+		return false
+	}
+
+	t.add(line)
+	return true
+}
+
+// recordStackTrace adds a trace to the result, grouping it with earlier
+// traces of the same signature
+func (ae *AnalysisEngine) recordStackTrace(result *AnalysisResult, filePath string, trace *stackTrace, index map[string]int, timestamps func(string) (time.Time, bool)) {
+	budget := ae.budgetFor(result)
+	signature := trace.Signature()
+	text := trace.Text()
+
+	seen, hasTime := timestamps(trace.Lines[0])
+	if !hasTime && trace.Preceding != "" {
+		seen, hasTime = timestamps(trace.Preceding)
+	}
+
+	if idx, ok := index[signature]; ok {
+		issue := &result.Issues[idx]
+		if hasTime {
+			noteSeen(issue.Metadata, seen)
+		}
+		occurrences, _ := strconv.Atoi(issue.Metadata["occurrences"])
+		issue.Metadata["occurrences"] = strconv.Itoa(occurrences + 1)
+		issue.Metadata["last_line"] = fmt.Sprintf("%d", trace.StartLine)
+		if len(issue.Evidence) < budget.limits.MaxEvidencePerIssue && budget.allowEvidence(text) {
+			issue.Evidence = append(issue.Evidence, text)
+		}
+		return
+	}
+
+	if !budget.allowIssue() {
+		return
+	}
+
+	var evidence []string
+	if budget.allowEvidence(text) {
+		evidence = []string{text}
+	}
+
+	severity := "warning"
+	if trace.Language == TraceLanguageGo {
+		severity = "critical"
+	}
+
+	exception := trace.Exception
+	if exception == "" {
+		exception = "unknown exception"
+	}
+	description := fmt.Sprintf("%s stack trace raised %s", traceLanguageNames[trace.Language], exception)
+	if trace.TopFrame != "" {
+		description += " at " + trace.TopFrame
+	}
+	if trace.RootCause != "" {
+		description += fmt.Sprintf(" (caused by %s)", trace.RootCause)
+	}
+
+	index[signature] = len(result.Issues)
+	result.Issues = append(result.Issues, Issue{
+		Severity:    severity,
+		Category:    "exception",
+		Title:       fmt.Sprintf("Stack Trace: %s", exception),
+		Description: description,
+		Location:    fmt.Sprintf("%s:line %d", filePath, trace.StartLine),
+		Evidence:    evidence,
+		Resolution:  "Inspect the top application frame and root cause of the trace",
+		Metadata: map[string]string{
+			"language":    trace.Language,
+			"exception":   trace.Exception,
+			"root_cause":  trace.RootCause,
+			"top_frame":   trace.TopFrame,
+			"signature":   signature,
+			"first_line":  fmt.Sprintf("%d", trace.StartLine),
+			"last_line":   fmt.Sprintf("%d", trace.StartLine),
+			"occurrences": "1",
+		},
+	})
+	if hasTime {
+		noteSeen(result.Issues[len(result.Issues)-1].Metadata, seen)
+	}
+}
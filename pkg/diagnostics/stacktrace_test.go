@@ -0,0 +1,135 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const javaTrace = `2024-05-01 10:02:03 ERROR [http-nio-8080-exec-1] c.e.OrderController - Request failed
+java.lang.IllegalStateException: order not found
+	at com.example.orders.OrderService.load(OrderService.java:42)
+	at com.example.orders.OrderController.get(OrderController.java:17)
+	at org.springframework.web.servlet.FrameworkServlet.service(FrameworkServlet.java:897)
+Caused by: java.sql.SQLException: connection refused
+	at org.postgresql.Driver.connect(Driver.java:285)
+	... 12 more
+`
+
+const goTrace = `panic: runtime error: index out of range [5] with length 3
+
+goroutine 1 [running]:
+main.handler(0xc000012345)
+	/app/main.go:27 +0x1d
+net/http.HandlerFunc.ServeHTTP(0x6b4d20, 0xc0000a2000)
+	/usr/local/go/src/net/http/server.go:2136 +0x29
+exit status 2
+`
+
+const pythonTrace = `Traceback (most recent call last):
+  File "/app/worker.py", line 88, in run
+    self.process(job)
+  File "/app/worker.py", line 41, in process
+    conn = connect(job.host)
+ConnectionRefusedError: [Errno 111] Connection refused
+`
+
+func TestStackTraceDetector(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		language  string
+		exception string
+		topFrame  string
+		rootCause string
+		lines     int
+	}{
+		{"java", javaTrace, TraceLanguageJava, "java.lang.IllegalStateException", "com.example.orders.OrderService.load", "java.sql.SQLException", 7},
+		{"go", goTrace, TraceLanguageGo, "runtime error: index out of range [N] with length N", "main.handler", "", 8},
+		{"python", pythonTrace, TraceLanguagePython, "ConnectionRefusedError", "/app/worker.py:process", "", 6},
+	}
+
+	for _, tt := range tests {
+		var detector stackTraceDetector
+		var traces []*stackTrace
+		for i, line := range strings.Split(strings.TrimSuffix(tt.input, "\n"), "\n") {
+			if trace, _ := detector.feed(i+1, line); trace != nil {
+				traces = append(traces, trace)
+			}
+		}
+		if trace := detector.finish(); trace != nil {
+			traces = append(traces, trace)
+		}
+
+		if len(traces) != 1 {
+			t.Errorf("%s: detected %d traces, expected 1", tt.name, len(traces))
+			continue
+		}
+		trace := traces[0]
+		if trace.Language != tt.language || trace.Exception != tt.exception || trace.TopFrame != tt.topFrame || trace.RootCause != tt.rootCause {
+			t.Errorf("%s: trace = %s/%q/%q/%q, expected %s/%q/%q/%q", tt.name,
+				trace.Language, trace.Exception, trace.TopFrame, trace.RootCause,
+				tt.language, tt.exception, tt.topFrame, tt.rootCause)
+		}
+		if len(trace.Lines) != tt.lines {
+			t.Errorf("%s: trace kept %d lines, expected %d", tt.name, len(trace.Lines), tt.lines)
+		}
+	}
+}
+
+func TestStackTraceDetectorIgnoresExceptionMentions(t *testing.T) {
+	var detector stackTraceDetector
+	lines := []string{
+		"INFO retrying after java.io.IOException: timeout",
+		"INFO retry succeeded",
+	}
+	for i, line := range lines {
+		if trace, continuation := detector.feed(i+1, line); trace != nil || continuation {
+			t.Errorf("feed(%q) = %v, %v, expected no trace", line, trace, continuation)
+		}
+	}
+	if trace := detector.finish(); trace != nil {
+		t.Errorf("finish() = %v, expected nil for a trace without frames", trace)
+	}
+}
+
+func TestAnalyzeLogsGroupsStackTraces(t *testing.T) {
+	dir := t.TempDir()
+	content := javaTrace + "INFO recovered\n" + javaTrace + goTrace + pythonTrace
+	if err := os.WriteFile(filepath.Join(dir, "app.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine()
+	engine.SetCacheEnabled(false)
+
+	result, err := engine.AnalyzeLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs failed: %v", err)
+	}
+
+	traces := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		if issue.Category == "exception" {
+			traces[issue.Metadata["language"]] = issue
+		} else {
+			t.Errorf("unexpected single-line issue %q from trace lines: %v", issue.Title, issue.Evidence)
+		}
+	}
+	if len(traces) != 3 {
+		t.Fatalf("found %d stack trace issues, expected 3", len(traces))
+	}
+
+	java := traces[TraceLanguageJava]
+	if java.Metadata["occurrences"] != "2" {
+		t.Errorf("java occurrences = %s, expected 2", java.Metadata["occurrences"])
+	}
+	if len(java.Evidence) != 2 || !strings.Contains(java.Evidence[0], "Caused by: java.sql.SQLException") {
+		t.Errorf("java evidence = %q, expected two full traces", java.Evidence)
+	}
+	if traces[TraceLanguageGo].Severity != "critical" {
+		t.Errorf("go panic severity = %s, expected critical", traces[TraceLanguageGo].Severity)
+	}
+}
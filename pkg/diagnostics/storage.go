@@ -0,0 +1,881 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CSIDriversNamespace runs the CSI drivers the cluster storage operator
+	// installs; drivers of other operators, such as ODF, run elsewhere
+	CSIDriversNamespace = "openshift-cluster-csi-drivers"
+
+	// StorageManifestFile lists what a storage collection gathered
+	StorageManifestFile = "storage-manifest.json"
+	// StorageStateFile holds the CSI pods, volume attachments, volumes and
+	// storage events of the collection
+	StorageStateFile = "storage-state.json"
+
+	defaultStorageLogSince = "1h"
+
+	// csiRestartWarning is the restart count from which a CSI pod is
+	// reported as restarting
+	csiRestartWarning = 3
+	// stuckTerminatingAfter is how long a volume may be deleting before it
+	// is reported as stuck
+	stuckTerminatingAfter = 10 * time.Minute
+)
+
+// The roles of the pods of a CSI driver's namespace
+const (
+	CSIRoleController = "controller"
+	CSIRoleNode       = "node"
+	CSIRoleOperator   = "operator"
+)
+
+// storageEventReasons are the warning events in which the provisioner,
+// attacher, resizer and kubelet report volume failures
+var storageEventReasons = map[string]bool{
+	"ProvisioningFailed": true,
+	"VolumeFailedDelete": true,
+	"FailedAttachVolume": true,
+	"FailedMount":        true,
+	"FailedMapVolume":    true,
+	"VolumeResizeFailed": true,
+}
+
+// StorageManifest describes a storage collection
+type StorageManifest struct {
+	Namespace   string             `json:"namespace"`
+	Since       string             `json:"since"`
+	CollectedAt time.Time          `json:"collected_at"`
+	Duration    string             `json:"duration"`
+	Pods        []CollectedPod     `json:"pods"`
+	Logs        []LogManifestEntry `json:"logs"`
+	State       string             `json:"state,omitempty"` // relative to the collection directory
+	Errors      []string           `json:"errors,omitempty"`
+}
+
+// StorageState is what stands between a claim and a mounted volume: the CSI
+// driver pods, the attachments of volumes to nodes, the volumes that are
+// being deleted or failed, and the volume events of every namespace
+type StorageState struct {
+	Pods        []CSIPodState           `json:"pods"`
+	Attachments []VolumeAttachmentState `json:"attachments,omitempty"`
+	Volumes     []VolumeState           `json:"volumes,omitempty"`
+	Events      []StorageEvent          `json:"events,omitempty"`
+}
+
+// CSIPodState is a pod of a CSI driver's namespace
+type CSIPodState struct {
+	Name     string `json:"name"`
+	Workload string `json:"workload"` // the deployment or daemonset running the pod
+	Role     string `json:"role"`     // controller, node or operator
+	Node     string `json:"node,omitempty"`
+	Ready    bool   `json:"ready"`
+	Restarts int    `json:"restarts"`
+	Reason   string `json:"reason,omitempty"` // why a container is waiting or last terminated
+}
+
+// VolumeAttachmentState is a VolumeAttachment and the errors its attacher
+// reports
+type VolumeAttachmentState struct {
+	Name        string `json:"name"`
+	Attacher    string `json:"attacher"`
+	Node        string `json:"node"`
+	Volume      string `json:"volume,omitempty"`
+	Attached    bool   `json:"attached"`
+	Deleting    bool   `json:"deleting,omitempty"`
+	AttachError string `json:"attach_error,omitempty"`
+	DetachError string `json:"detach_error,omitempty"`
+}
+
+// VolumeState is a PersistentVolume that is being deleted or failed
+type VolumeState struct {
+	Name          string    `json:"name"`
+	Driver        string    `json:"driver,omitempty"`
+	Phase         string    `json:"phase"`
+	Claim         string    `json:"claim,omitempty"` // namespace/name
+	DeletingSince time.Time `json:"deleting_since,omitempty"`
+	Finalizers    []string  `json:"finalizers,omitempty"`
+	Message       string    `json:"message,omitempty"`
+}
+
+// StorageEvent is a warning event about a volume failure
+type StorageEvent struct {
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	LastSeen  time.Time `json:"last_seen,omitempty"`
+}
+
+// csiPodList is the part of oc get pods -o json the CSI pod state needs
+type csiPodList struct {
+	Items []struct {
+		Metadata struct {
+			Name            string `json:"name"`
+			OwnerReferences []struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"ownerReferences"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName string `json:"nodeName"`
+		} `json:"spec"`
+		Status struct {
+			Phase      string `json:"phase"`
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+			ContainerStatuses []struct {
+				RestartCount int `json:"restartCount"`
+				State        struct {
+					Waiting *struct {
+						Reason string `json:"reason"`
+					} `json:"waiting"`
+				} `json:"state"`
+				LastState struct {
+					Terminated *struct {
+						Reason string `json:"reason"`
+					} `json:"terminated"`
+				} `json:"lastState"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// volumeAttachmentList is the part of oc get volumeattachments -o json the
+// attachment state needs
+type volumeAttachmentList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string     `json:"name"`
+			DeletionTimestamp *time.Time `json:"deletionTimestamp"`
+		} `json:"metadata"`
+		Spec struct {
+			Attacher string `json:"attacher"`
+			NodeName string `json:"nodeName"`
+			Source   struct {
+				PersistentVolumeName string `json:"persistentVolumeName"`
+			} `json:"source"`
+		} `json:"spec"`
+		Status struct {
+			Attached    bool `json:"attached"`
+			AttachError *struct {
+				Message string `json:"message"`
+			} `json:"attachError"`
+			DetachError *struct {
+				Message string `json:"message"`
+			} `json:"detachError"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// persistentVolumeList is the part of oc get pv -o json the volume state
+// needs
+type persistentVolumeList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string    `json:"name"`
+			DeletionTimestamp time.Time `json:"deletionTimestamp"`
+			Finalizers        []string  `json:"finalizers"`
+		} `json:"metadata"`
+		Spec struct {
+			CSI *struct {
+				Driver string `json:"driver"`
+			} `json:"csi"`
+			ClaimRef *struct {
+				Namespace string `json:"namespace"`
+				Name      string `json:"name"`
+			} `json:"claimRef"`
+		} `json:"spec"`
+		Status struct {
+			Phase   string `json:"phase"`
+			Message string `json:"message"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// storageEventList is the part of oc get events -o json the storage events
+// need
+type storageEventList struct {
+	Items []struct {
+		Metadata struct {
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		InvolvedObject struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"involvedObject"`
+		Type          string    `json:"type"`
+		Reason        string    `json:"reason"`
+		Message       string    `json:"message"`
+		Count         int       `json:"count"`
+		LastTimestamp time.Time `json:"lastTimestamp"`
+		EventTime     time.Time `json:"eventTime"`
+	} `json:"items"`
+}
+
+// replicaSetHash is the pod template hash a deployment appends to the
+// names of its replica sets
+var replicaSetHash = regexp.MustCompile(`-[a-z0-9]{5,10}$`)
+
+// parseCSIPods reads the pods of a CSI driver's namespace. Node plugins run
+// in daemonsets; operators are the deployments named *-operator, and the
+// other deployments run driver controllers.
+func parseCSIPods(data []byte) ([]CSIPodState, error) {
+	var list csiPodList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var pods []CSIPodState
+	for _, item := range list.Items {
+		if item.Status.Phase == "Succeeded" || item.Status.Phase == "Failed" {
+			continue
+		}
+		pod := CSIPodState{Name: item.Metadata.Name, Workload: item.Metadata.Name, Role: CSIRoleController, Node: item.Spec.NodeName}
+		for _, owner := range item.Metadata.OwnerReferences {
+			switch owner.Kind {
+			case "DaemonSet":
+				pod.Workload, pod.Role = owner.Name, CSIRoleNode
+			case "ReplicaSet":
+				pod.Workload = replicaSetHash.ReplaceAllString(owner.Name, "")
+			default:
+				pod.Workload = owner.Name
+			}
+		}
+		if pod.Role == CSIRoleController && strings.HasSuffix(pod.Workload, "-operator") {
+			pod.Role = CSIRoleOperator
+		}
+		for _, condition := range item.Status.Conditions {
+			if condition.Type == "Ready" {
+				pod.Ready = condition.Status == "True"
+			}
+		}
+		for _, status := range item.Status.ContainerStatuses {
+			pod.Restarts += status.RestartCount
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				pod.Reason = status.State.Waiting.Reason
+			} else if status.LastState.Terminated != nil && pod.Reason == "" {
+				pod.Reason = status.LastState.Terminated.Reason
+			}
+		}
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+// parseVolumeAttachments reads the attachments that failed to attach or
+// detach
+func parseVolumeAttachments(data []byte) ([]VolumeAttachmentState, error) {
+	var list volumeAttachmentList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var attachments []VolumeAttachmentState
+	for _, item := range list.Items {
+		attachment := VolumeAttachmentState{
+			Name:     item.Metadata.Name,
+			Attacher: item.Spec.Attacher,
+			Node:     item.Spec.NodeName,
+			Volume:   item.Spec.Source.PersistentVolumeName,
+			Attached: item.Status.Attached,
+			Deleting: item.Metadata.DeletionTimestamp != nil,
+		}
+		if item.Status.AttachError != nil {
+			attachment.AttachError = item.Status.AttachError.Message
+		}
+		if item.Status.DetachError != nil {
+			attachment.DetachError = item.Status.DetachError.Message
+		}
+		if attachment.AttachError == "" && attachment.DetachError == "" {
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].Name < attachments[j].Name })
+	return attachments, nil
+}
+
+// parseProblemVolumes reads the volumes that are being deleted or failed
+func parseProblemVolumes(data []byte) ([]VolumeState, error) {
+	var list persistentVolumeList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var volumes []VolumeState
+	for _, item := range list.Items {
+		if item.Metadata.DeletionTimestamp.IsZero() && item.Status.Phase != "Failed" {
+			continue
+		}
+		volume := VolumeState{
+			Name:          item.Metadata.Name,
+			Phase:         item.Status.Phase,
+			DeletingSince: item.Metadata.DeletionTimestamp,
+			Finalizers:    item.Metadata.Finalizers,
+			Message:       item.Status.Message,
+		}
+		if item.Spec.CSI != nil {
+			volume.Driver = item.Spec.CSI.Driver
+		}
+		if item.Spec.ClaimRef != nil {
+			volume.Claim = item.Spec.ClaimRef.Namespace + "/" + item.Spec.ClaimRef.Name
+		}
+		volumes = append(volumes, volume)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	return volumes, nil
+}
+
+// parseStorageEvents reads the warning events about volume failures, most
+// recent first
+func parseStorageEvents(data []byte) ([]StorageEvent, error) {
+	var list storageEventList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var events []StorageEvent
+	for _, item := range list.Items {
+		if item.Type != "Warning" || !storageEventReasons[item.Reason] {
+			continue
+		}
+		event := StorageEvent{
+			Namespace: item.Metadata.Namespace,
+			Kind:      item.InvolvedObject.Kind,
+			Name:      item.InvolvedObject.Name,
+			Reason:    item.Reason,
+			Message:   item.Message,
+			Count:     max(item.Count, 1),
+			LastSeen:  item.LastTimestamp,
+		}
+		if event.LastSeen.IsZero() {
+			event.LastSeen = item.EventTime
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].LastSeen.After(events[j].LastSeen) })
+	return events, nil
+}
+
+// CollectStorage gathers the CSI driver diagnostics of a namespace (default
+// openshift-cluster-csi-drivers): the logs of every driver container,
+// including the provisioner, attacher and resizer sidecars and the previous
+// instance of restarted ones, since "since" (default 1h), and the state of
+// the driver pods, the volume attachments that are failing or pending, the
+// volumes being deleted or failed, and the volume warning events of every
+// namespace. The "namespace" filter collects the drivers of another
+// operator. Parts that fail are recorded in the manifest; the collection
+// only fails when the driver pods cannot be listed.
+func (dc *DiagnosticCollector) CollectStorage(ctx context.Context, opts *CollectionOptions) (*CollectionResult, error) {
+	start := time.Now()
+	result := &CollectionResult{
+		Type:     "storage",
+		Metadata: make(map[string]string),
+	}
+	fail := func(err error) (*CollectionResult, error) {
+		result.Status = "failed"
+		result.ErrorMsg = err.Error()
+		result.Duration = time.Since(start)
+		return result, err
+	}
+
+	since := opts.Filters["since"]
+	if since == "" {
+		since = defaultStorageLogSince
+	}
+	if _, err := time.ParseDuration(since); err != nil {
+		return nil, fmt.Errorf("invalid since duration %q: %v", since, err)
+	}
+	namespace := opts.Filters["namespace"]
+	if namespace == "" {
+		namespace = CSIDriversNamespace
+	}
+	if !nodeNamePattern.MatchString(namespace) {
+		return nil, fmt.Errorf("invalid namespace %q", namespace)
+	}
+	manifest := &StorageManifest{Namespace: namespace, Since: since, CollectedAt: start.UTC()}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dc.workingDir, fmt.Sprintf("storage-%d", start.Unix()))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fail(err)
+	}
+	result.FilePath = outputDir
+
+	var listing bytes.Buffer
+	if err := dc.runOC(ctx, nil, &listing, "get", "pods", "-n", namespace, "-o", "json"); err != nil {
+		return fail(fmt.Errorf("listing pods in %s: %v", namespace, err))
+	}
+	csiPods, err := parseCSIPods(listing.Bytes())
+	if err != nil {
+		return fail(fmt.Errorf("parsing the pod list: %v", err))
+	}
+	var pods podList
+	if err := json.Unmarshal(listing.Bytes(), &pods); err != nil {
+		return fail(fmt.Errorf("parsing the pod list: %v", err))
+	}
+	running := make(map[string]bool, len(csiPods))
+	for _, pod := range csiPods {
+		running[pod.Name] = true
+	}
+	selected := pods.Items[:0]
+	for _, pod := range pods.Items {
+		if running[pod.Metadata.Name] {
+			selected = append(selected, pod)
+		}
+	}
+	pods.Items = selected
+	if len(pods.Items) == 0 {
+		return fail(fmt.Errorf("no CSI driver pods in %s", namespace))
+	}
+	for _, pod := range pods.Items {
+		entry := CollectedPod{Name: pod.Metadata.Name, Node: pod.Spec.NodeName}
+		for _, container := range pod.Spec.Containers {
+			entry.Containers = append(entry.Containers, container.Name)
+		}
+		manifest.Pods = append(manifest.Pods, entry)
+	}
+
+	manifest.Logs = dc.collectContainerLogs(ctx, outputDir, namespace, since, logJobs(&pods, true), DefaultLogWorkers)
+	failed := 0
+	var size int64
+	for _, entry := range manifest.Logs {
+		size += entry.Bytes
+		if entry.Error != "" {
+			failed++
+		}
+	}
+
+	state := StorageState{Pods: csiPods}
+	read := func(what string, args ...string) []byte {
+		var out bytes.Buffer
+		if err := dc.runOC(ctx, nil, &out, args...); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("reading %s: %v", what, err))
+			return nil
+		}
+		return out.Bytes()
+	}
+	if data := read("the volume attachments", "get", "volumeattachments", "-o", "json"); data != nil {
+		if state.Attachments, err = parseVolumeAttachments(data); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the volume attachments: %v", err))
+		}
+	}
+	if data := read("the persistent volumes", "get", "persistentvolumes", "-o", "json"); data != nil {
+		if state.Volumes, err = parseProblemVolumes(data); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the persistent volumes: %v", err))
+		}
+	}
+	if data := read("the warning events", "get", "events", "-A", "--field-selector", "type=Warning", "-o", "json"); data != nil {
+		if state.Events, err = parseStorageEvents(data); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("parsing the events: %v", err))
+		}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(outputDir, StorageStateFile), data, 0644)
+	}
+	if err != nil {
+		manifest.Errors = append(manifest.Errors, fmt.Sprintf("writing the storage state: %v", err))
+	} else {
+		manifest.State = StorageStateFile
+		size += int64(len(data))
+	}
+
+	manifest.Duration = time.Since(start).Round(time.Millisecond).String()
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, StorageManifestFile), data, 0644); err != nil {
+		return fail(err)
+	}
+	if dirSize, err := dc.getDirSize(outputDir); err == nil {
+		size = dirSize
+	}
+
+	result.Duration = time.Since(start)
+	result.Size = size
+	result.Metadata["namespace"] = namespace
+	result.Metadata["since"] = since
+	result.Metadata["manifest"] = filepath.Join(outputDir, StorageManifestFile)
+	result.Metadata["pods"] = strconv.Itoa(len(manifest.Pods))
+	result.Metadata["logs"] = strconv.Itoa(len(manifest.Logs))
+	result.Metadata["attachments"] = strconv.Itoa(len(state.Attachments))
+	result.Metadata["events"] = strconv.Itoa(len(state.Events))
+	result.Metadata["failed"] = strconv.Itoa(failed + len(manifest.Errors))
+	if ctx.Err() != nil {
+		return fail(fmt.Errorf("storage collection interrupted: %v", ctx.Err()))
+	}
+
+	result.Status = "completed"
+	result.Summary = fmt.Sprintf("Collected %d logs from %d CSI driver pods in %s (%.2f MB, %d failed) in %s",
+		len(manifest.Logs), len(manifest.Pods), namespace, float64(size)/(1024*1024), failed+len(manifest.Errors), outputDir)
+	dc.finishCollection("", result, opts)
+	dc.logger.Infof("Storage collection completed: %s", result.Summary)
+	return result, nil
+}
+
+// csiSignatures are the failures CSI drivers and their sidecars log most
+// often
+var csiSignatures = []LogSignature{
+	{
+		Name:        "cloud-credentials",
+		Pattern:     regexp.MustCompile(`UnauthorizedOperation|AccessDenied|AuthFailure|AuthorizationFailed|InvalidClientTokenId|ExpiredToken|(?i)invalid_grant`),
+		Severity:    "critical",
+		Title:       "CSI driver is denied by the cloud provider",
+		Description: "The driver's cloud credentials are missing, expired or lack permissions, so volumes cannot be created, attached or deleted",
+		Resolution:  "Check the driver's CredentialsRequest: oc get credentialsrequests -n openshift-cloud-credential-operator, and the cloud-credential operator's conditions",
+	},
+	{
+		Name:        "cloud-quota",
+		Pattern:     regexp.MustCompile(`VolumeLimitExceeded|QuotaExceeded|(?i)quota exceeded|maximum number of (volumes|disks|attachments)`),
+		Severity:    "critical",
+		Title:       "Cloud volume quota or attachment limit reached",
+		Description: "The cloud account has no quota left for new disks, or a node has as many disks attached as its instance type allows",
+		Resolution:  "Delete released volumes and unused snapshots or raise the account's quota; spread pods with many volumes over more nodes",
+	},
+	{
+		Name:        "driver-not-registered",
+		Pattern:     regexp.MustCompile(`(?i)not found in the list of registered CSI drivers|lost connection to CSI driver|failed to connect to CSI driver|registration (probe|process) failed`),
+		Severity:    "critical",
+		Title:       "CSI driver is not registered with the kubelet",
+		Description: "The node plugin is not running or not registered on some nodes, so volumes cannot be mounted there",
+		Resolution:  "Check the node-driver-registrar container of the driver's node pods on the affected nodes and the kubelet's plugin directory /var/lib/kubelet/plugins_registry",
+	},
+	{
+		Name:        "volume-in-use",
+		Pattern:     regexp.MustCompile(`VolumeInUse|(?i)already attached to (another|a different)|is attached to (another|a different) (node|instance)|Multi-Attach error`),
+		Severity:    "warning",
+		Title:       "Volumes are still attached to another node",
+		Description: "A ReadWriteOnce volume cannot attach to a new node until it is detached from the old one, which holds up pods moving between nodes",
+		Resolution:  "Find the old VolumeAttachment with oc get volumeattachments and check why it does not detach; a node that is down must be shut down before its volumes are released",
+	},
+	{
+		Name:        "csi-timeout",
+		Pattern:     regexp.MustCompile(`code = DeadlineExceeded|context deadline exceeded`),
+		Severity:    "warning",
+		Title:       "CSI calls time out",
+		Description: "The driver or the cloud API answers too slowly, so provisioning, attaching and mounting are retried with backoff",
+		Resolution:  "Check the cloud provider's status and API throttling, and the driver controller's resource usage",
+	},
+	{
+		Name:        "leader-election",
+		Pattern:     regexp.MustCompile(`(?i)failed to renew lease|leaderelection lost|leader election lost`),
+		Severity:    "warning",
+		Title:       "CSI sidecars lose leader election",
+		Description: "The provisioner, attacher or resizer restarts when it loses its lease, pausing its work until a new leader is elected",
+		Resolution:  "Check the API server's health with probe_control_plane and the controller pods' CPU throttling",
+	},
+	{
+		Name:        "mount-failure",
+		Pattern:     regexp.MustCompile(`(?i)mount failed: exit status|wrong fs type|structure needs cleaning|fsck found errors`),
+		Severity:    "warning",
+		Title:       "Volumes fail to mount on nodes",
+		Description: "The node plugin could not mount the volume's filesystem, which may be corrupted or formatted with another filesystem",
+		Resolution:  "Check the volume's filesystem from a debug pod on the node with fsck before reusing it; restore it from a snapshot if it is corrupted",
+	},
+}
+
+// storageEventIssues describes the volume events of each reason
+var storageEventIssues = map[string]struct {
+	severity    string
+	title       string
+	description string
+	resolution  string
+}{
+	"ProvisioningFailed": {"critical", "%d claims cannot be provisioned", "The provisioner failed to create volumes for these claims, which stay Pending with their pods",
+		"Check the storage class parameters, cloud quota and the CSI controller's csi-provisioner log"},
+	"VolumeFailedDelete": {"warning", "%d volumes fail to be deleted", "The provisioner cannot delete the volumes of deleted claims, which keeps their disks and cost in the cloud",
+		"Check the CSI controller's csi-provisioner log; delete the disk in the cloud and the PV by hand if the driver cannot"},
+	"FailedAttachVolume": {"critical", "%d volumes fail to attach", "The attacher cannot attach the volumes to their pods' nodes, so the pods stay in ContainerCreating",
+		"Check the VolumeAttachments of the volumes and the CSI controller's csi-attacher log"},
+	"FailedMount": {"warning", "%d pods cannot mount their volumes", "The kubelet cannot mount the pods' volumes, so the pods stay in ContainerCreating",
+		"Check the driver's node pod on the pods' nodes and its log"},
+	"FailedMapVolume": {"warning", "%d pods cannot map their block volumes", "The kubelet cannot map the pods' raw block volumes",
+		"Check the driver's node pod on the pods' nodes and its log"},
+	"VolumeResizeFailed": {"warning", "%d volumes fail to expand", "Expanding the volumes failed, so the claims keep their old size",
+		"Check that the storage class sets allowVolumeExpansion and the CSI controller's csi-resizer log"},
+}
+
+// StorageInfrastructureIssues reports what in the storage infrastructure
+// keeps volumes from being provisioned, attached or mounted: CSI
+// controllers and node plugins that are not ready or restarting, volume
+// attachments that fail, volumes stuck deleting or failed, and the volume
+// warning events of the cluster. now is when the state was read.
+func StorageInfrastructureIssues(state StorageState, now time.Time) []Issue {
+	var issues []Issue
+
+	byWorkload := make(map[string][]CSIPodState)
+	for _, pod := range state.Pods {
+		byWorkload[pod.Workload] = append(byWorkload[pod.Workload], pod)
+	}
+	for _, workload := range sortedKeys(byWorkload) {
+		pods := byWorkload[workload]
+		role := pods[0].Role
+		var notReady, restarting []string
+		for _, pod := range pods {
+			if !pod.Ready {
+				line := fmt.Sprintf("%s on %s", pod.Name, valueOrUnknown(pod.Node))
+				if pod.Reason != "" {
+					line += ": " + pod.Reason
+				}
+				notReady = append(notReady, line)
+			}
+			if pod.Restarts >= csiRestartWarning {
+				restarting = append(restarting, fmt.Sprintf("%s: %d restarts (%s)", pod.Name, pod.Restarts, valueOrUnknown(pod.Reason)))
+			}
+		}
+		metadata := map[string]string{"workload": workload, "role": role}
+		if len(notReady) > 0 {
+			issue := Issue{
+				Severity:   "warning",
+				Category:   "storage",
+				Location:   workload,
+				Evidence:   capEvidence(notReady, maxSignatureEvidence),
+				Resolution: "Check the unready pods' containers and logs: oc describe pod <pod> -n <namespace>; collect_storage_diagnostics gathers the logs of every driver container",
+				Metadata:   metadata,
+			}
+			switch role {
+			case CSIRoleNode:
+				issue.Title = fmt.Sprintf("%d of %d CSI node plugins of %s are not ready", len(notReady), len(pods), workload)
+				issue.Description = "Volumes of the driver cannot be mounted or unmounted on these nodes, so pods using them there stay in ContainerCreating or Terminating"
+				if len(notReady) == len(pods) {
+					issue.Severity = "critical"
+				}
+			case CSIRoleOperator:
+				issue.Title = fmt.Sprintf("CSI driver operator %s is not ready", workload)
+				issue.Description = "The operator does not reconcile the driver, so the driver is not repaired or updated"
+			default:
+				issue.Title = fmt.Sprintf("%d of %d CSI controller pods of %s are not ready", len(notReady), len(pods), workload)
+				issue.Description = "The remaining controller pods provision, attach and resize volumes"
+				if len(notReady) == len(pods) {
+					issue.Severity = "critical"
+					issue.Title = fmt.Sprintf("No CSI controller pod of %s is ready", workload)
+					issue.Description = "No volume of the driver is provisioned, attached, detached or expanded until a controller pod is ready"
+				}
+			}
+			issues = append(issues, issue)
+		}
+		if len(restarting) > 0 {
+			issues = append(issues, Issue{
+				Severity:    "warning",
+				Category:    "stability",
+				Title:       fmt.Sprintf("Pods of %s are restarting", workload),
+				Description: "Volume operations in flight are retried after each restart, delaying pods waiting for their volumes",
+				Location:    workload,
+				Evidence:    capEvidence(restarting, maxSignatureEvidence),
+				Resolution:  "Check the previous instance's log of the restarting container (oc logs --previous) and OOMKilled reasons",
+				Metadata:    metadata,
+			})
+		}
+	}
+
+	attachFailures := make(map[string][]string)
+	detachFailures := make(map[string][]string)
+	for _, attachment := range state.Attachments {
+		volume := valueOrUnknown(attachment.Volume)
+		if attachment.AttachError != "" {
+			attachFailures[attachment.Attacher] = append(attachFailures[attachment.Attacher], fmt.Sprintf("%s on %s: %s", volume, attachment.Node, attachment.AttachError))
+		}
+		if attachment.DetachError != "" {
+			detachFailures[attachment.Attacher] = append(detachFailures[attachment.Attacher], fmt.Sprintf("%s from %s: %s", volume, attachment.Node, attachment.DetachError))
+		}
+	}
+	for _, attacher := range sortedKeys(attachFailures) {
+		failures := attachFailures[attacher]
+		issues = append(issues, Issue{
+			Severity:    "critical",
+			Category:    "storage",
+			Title:       fmt.Sprintf("%d volumes of %s fail to attach", len(failures), attacher),
+			Description: "The attacher reports errors attaching these volumes to their nodes, so the pods using them cannot start",
+			Location:    "volumeattachments",
+			Evidence:    capEvidence(failures, maxShardEvidence),
+			Resolution:  "Check the csi-attacher log of the driver's controller and the disks in the cloud console; a disk still attached to another instance must be detached first",
+			Metadata:    map[string]string{"attacher": attacher, "volumes": strconv.Itoa(len(failures))},
+		})
+	}
+	for _, attacher := range sortedKeys(detachFailures) {
+		failures := detachFailures[attacher]
+		issues = append(issues, Issue{
+			Severity:    "warning",
+			Category:    "storage",
+			Title:       fmt.Sprintf("%d volumes of %s fail to detach", len(failures), attacher),
+			Description: "Volumes that do not detach cannot attach to another node, so pods moving off these nodes hang with Multi-Attach errors",
+			Location:    "volumeattachments",
+			Evidence:    capEvidence(failures, maxShardEvidence),
+			Resolution:  "Check the csi-attacher log of the driver's controller; for a node that is gone, delete its Node object so the volumes are force-detached",
+			Metadata:    map[string]string{"attacher": attacher, "volumes": strconv.Itoa(len(failures))},
+		})
+	}
+
+	var stuck, failedVolumes []string
+	for _, volume := range state.Volumes {
+		if !volume.DeletingSince.IsZero() {
+			if deleting := now.Sub(volume.DeletingSince); deleting >= stuckTerminatingAfter {
+				line := fmt.Sprintf("%s (claim %s) deleting for %s", volume.Name, valueOrUnknown(volume.Claim), deleting.Round(time.Minute))
+				if len(volume.Finalizers) > 0 {
+					line += ", finalizers " + strings.Join(volume.Finalizers, ", ")
+				}
+				stuck = append(stuck, line)
+			}
+			continue
+		}
+		if volume.Phase == "Failed" {
+			failedVolumes = append(failedVolumes, fmt.Sprintf("%s (%s): %s", volume.Name, valueOrUnknown(volume.Driver), valueOrUnknown(volume.Message)))
+		}
+	}
+	if len(stuck) > 0 {
+		issues = append(issues, Issue{
+			Severity:    "warning",
+			Category:    "storage",
+			Title:       fmt.Sprintf("%d volumes are stuck terminating", len(stuck)),
+			Description: "kubernetes.io/pv-protection holds a volume while a claim is bound to it; external-provisioner finalizers hold it until the driver deletes the disk",
+			Location:    "persistentvolumes",
+			Evidence:    capEvidence(stuck, maxShardEvidence),
+			Resolution:  "Delete the claims still bound to the volumes, and check the csi-provisioner log for deletion errors; remove a finalizer by hand only once the disk is gone",
+			Metadata:    map[string]string{"volumes": strconv.Itoa(len(stuck))},
+		})
+	}
+	if len(failedVolumes) > 0 {
+		issues = append(issues, Issue{
+			Severity:    "warning",
+			Category:    "storage",
+			Title:       fmt.Sprintf("%d volumes failed to be reclaimed", len(failedVolumes)),
+			Description: "Reclaiming the volumes after their claims were deleted failed, so they stay in the cluster and in the cloud",
+			Location:    "persistentvolumes",
+			Evidence:    capEvidence(failedVolumes, maxShardEvidence),
+			Resolution:  "Fix what the messages name, then delete the volumes; delete their disks in the cloud if the driver cannot",
+			Metadata:    map[string]string{"volumes": strconv.Itoa(len(failedVolumes))},
+		})
+	}
+
+	byReason := make(map[string][]string)
+	for _, event := range state.Events {
+		line := fmt.Sprintf("%s %s/%s: %s", strings.ToLower(event.Kind), event.Namespace, event.Name, event.Message)
+		if event.Count > 1 {
+			line += fmt.Sprintf(" (x%d)", event.Count)
+		}
+		if !hasString(byReason[event.Reason], line) {
+			byReason[event.Reason] = append(byReason[event.Reason], line)
+		}
+	}
+	for _, reason := range sortedKeys(byReason) {
+		lines := byReason[reason]
+		kind := storageEventIssues[reason]
+		issues = append(issues, Issue{
+			Severity:    kind.severity,
+			Category:    "storage",
+			Title:       fmt.Sprintf(kind.title, len(lines)),
+			Description: kind.description,
+			Location:    "events",
+			Evidence:    capEvidence(lines, maxShardEvidence),
+			Resolution:  kind.resolution,
+			Metadata:    map[string]string{"reason": reason, "events": strconv.Itoa(len(lines))},
+		})
+	}
+	return issues
+}
+
+// AnalyzeStorage reports the failures a storage collection shows: known
+// failure signatures in the CSI driver logs and the infrastructure problems
+// StorageInfrastructureIssues finds in the collected state
+func (ae *AnalysisEngine) AnalyzeStorage(ctx context.Context, path string) (*AnalysisResult, error) {
+	if IsBundle(path) {
+		return analyzeBundle(path, bundleDir, func(dir string) (*AnalysisResult, error) {
+			return ae.AnalyzeStorage(ctx, dir)
+		})
+	}
+	result := &AnalysisResult{
+		Type:      "storage-analysis",
+		FilePath:  path,
+		Issues:    []Issue{},
+		Metrics:   make(map[string]interface{}),
+		Timestamp: time.Now(),
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, StorageManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("no storage collection in %s: %v", path, err)
+	}
+	var manifest StorageManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", StorageManifestFile, err)
+	}
+	ae.logger.Infof("Starting storage analysis: %s", path)
+
+	nodeOf := make(map[string]string, len(manifest.Pods))
+	for _, pod := range manifest.Pods {
+		nodeOf[pod.Name] = pod.Node
+	}
+	podLabel := func(pod string) string {
+		if node := nodeOf[pod]; node != "" {
+			return fmt.Sprintf("%s (%s)", pod, node)
+		}
+		return pod
+	}
+
+	hits, err := ae.scanLogSignatures(ctx, path, manifest.Logs, csiSignatures, podLabel)
+	if err != nil {
+		return nil, err
+	}
+	matches := ae.addSignatureIssues(result, csiSignatures, hits, "storage")
+
+	var state StorageState
+	if manifest.State != "" {
+		if data, err := os.ReadFile(filepath.Join(path, manifest.State)); err != nil {
+			ae.logger.Warnf("Failed to read %s: %v", manifest.State, err)
+		} else if err := json.Unmarshal(data, &state); err != nil {
+			ae.logger.Warnf("Invalid %s: %v", manifest.State, err)
+		}
+	}
+	for _, issue := range StorageInfrastructureIssues(state, manifest.CollectedAt) {
+		ae.addIssue(result, issue)
+	}
+
+	failedLogs := 0
+	for _, entry := range manifest.Logs {
+		if entry.Error != "" {
+			failedLogs++
+		}
+	}
+	result.Metrics["pods"] = len(manifest.Pods)
+	result.Metrics["logs"] = len(manifest.Logs)
+	result.Metrics["logs_failed"] = failedLogs
+	result.Metrics["signature_matches"] = matches
+	result.Metrics["attachments"] = len(state.Attachments)
+	result.Metrics["problem_volumes"] = len(state.Volumes)
+	result.Metrics["events"] = len(state.Events)
+	for _, err := range manifest.Errors {
+		result.markTruncated(err)
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		return rank[result.Issues[i].Severity] < rank[result.Issues[j].Severity]
+	})
+	ae.finishBudget(result)
+	ae.buildTimeline(result)
+	ae.generateSummaryAndRecommendations(result)
+	if len(result.Issues) == 0 {
+		result.Summary = fmt.Sprintf("No storage failures found in %d logs of %d CSI driver pods in %s", len(manifest.Logs), len(manifest.Pods), manifest.Namespace)
+	}
+
+	ae.logger.Infof("Storage analysis completed: found %d issues", len(result.Issues))
+	return result, nil
+}
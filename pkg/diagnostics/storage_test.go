@@ -0,0 +1,203 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testCSIPods = `{"items": [
+  {"metadata": {"name": "aws-ebs-csi-driver-controller-6b9c7d8f5d-abcde", "ownerReferences": [{"kind": "ReplicaSet", "name": "aws-ebs-csi-driver-controller-6b9c7d8f5d"}]},
+   "spec": {"nodeName": "master-0", "containers": [{"name": "csi-driver"}, {"name": "csi-provisioner"}, {"name": "csi-attacher"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}],
+     "containerStatuses": [{"name": "csi-driver"}, {"name": "csi-provisioner"}, {"name": "csi-attacher"}]}},
+  {"metadata": {"name": "aws-ebs-csi-driver-node-x1", "ownerReferences": [{"kind": "DaemonSet", "name": "aws-ebs-csi-driver-node"}]},
+   "spec": {"nodeName": "worker-1", "containers": [{"name": "csi-driver"}, {"name": "node-driver-registrar"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}],
+     "containerStatuses": [{"name": "csi-driver"}, {"name": "node-driver-registrar"}]}},
+  {"metadata": {"name": "aws-ebs-csi-driver-node-x2", "ownerReferences": [{"kind": "DaemonSet", "name": "aws-ebs-csi-driver-node"}]},
+   "spec": {"nodeName": "worker-2", "containers": [{"name": "csi-driver"}, {"name": "node-driver-registrar"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "False"}],
+     "containerStatuses": [{"name": "csi-driver", "restartCount": 5, "state": {"waiting": {"reason": "CrashLoopBackOff"}}}, {"name": "node-driver-registrar"}]}},
+  {"metadata": {"name": "aws-ebs-csi-driver-operator-7f8d9c6b5a-fghij", "ownerReferences": [{"kind": "ReplicaSet", "name": "aws-ebs-csi-driver-operator-7f8d9c6b5a"}]},
+   "spec": {"nodeName": "master-1", "containers": [{"name": "aws-ebs-csi-driver-operator"}]},
+   "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}], "containerStatuses": [{"name": "aws-ebs-csi-driver-operator"}]}},
+  {"metadata": {"name": "aws-ebs-csi-driver-node-old", "ownerReferences": [{"kind": "DaemonSet", "name": "aws-ebs-csi-driver-node"}]},
+   "spec": {"nodeName": "worker-3", "containers": [{"name": "csi-driver"}]},
+   "status": {"phase": "Failed"}}
+]}`
+
+const testVolumeAttachments = `{"items": [
+  {"metadata": {"name": "csi-aaa"}, "spec": {"attacher": "ebs.csi.aws.com", "nodeName": "worker-1", "source": {"persistentVolumeName": "pvc-ok"}},
+   "status": {"attached": true}},
+  {"metadata": {"name": "csi-bbb"}, "spec": {"attacher": "ebs.csi.aws.com", "nodeName": "worker-2", "source": {"persistentVolumeName": "pvc-db"}},
+   "status": {"attached": false, "attachError": {"message": "rpc error: code = Internal desc = Could not attach volume vol-1 to node i-2: VolumeInUse"}}},
+  {"metadata": {"name": "csi-ccc", "deletionTimestamp": "2026-10-16T08:00:00Z"}, "spec": {"attacher": "ebs.csi.aws.com", "nodeName": "worker-3", "source": {"persistentVolumeName": "pvc-web"}},
+   "status": {"attached": true, "detachError": {"message": "rpc error: code = DeadlineExceeded desc = context deadline exceeded"}}}
+]}`
+
+const testPersistentVolumes = `{"items": [
+  {"metadata": {"name": "pvc-ok"}, "spec": {"csi": {"driver": "ebs.csi.aws.com"}, "claimRef": {"namespace": "shop", "name": "data"}}, "status": {"phase": "Bound"}},
+  {"metadata": {"name": "pvc-old", "deletionTimestamp": "2020-01-01T00:00:00Z", "finalizers": ["external-provisioner.volume.kubernetes.io/finalizer"]},
+   "spec": {"csi": {"driver": "ebs.csi.aws.com"}, "claimRef": {"namespace": "shop", "name": "old"}}, "status": {"phase": "Released"}},
+  {"metadata": {"name": "pvc-new", "deletionTimestamp": "%s", "finalizers": ["kubernetes.io/pv-protection"]},
+   "spec": {"csi": {"driver": "ebs.csi.aws.com"}}, "status": {"phase": "Released"}},
+  {"metadata": {"name": "pvc-lost"}, "spec": {"csi": {"driver": "ebs.csi.aws.com"}}, "status": {"phase": "Failed", "message": "error getting deleter volume plugin"}}
+]}`
+
+const testStorageEvents = `{"items": [
+  {"metadata": {"namespace": "shop"}, "involvedObject": {"kind": "PersistentVolumeClaim", "name": "cache"}, "type": "Warning", "reason": "ProvisioningFailed",
+   "message": "failed to provision volume with StorageClass \"gp3-csi\": rpc error: code = Internal", "count": 4, "lastTimestamp": "2026-10-16T09:00:00Z"},
+  {"metadata": {"namespace": "shop"}, "involvedObject": {"kind": "Pod", "name": "db-0"}, "type": "Warning", "reason": "FailedMount",
+   "message": "MountVolume.MountDevice failed for volume \"pvc-db\"", "lastTimestamp": "2026-10-16T09:01:00Z"},
+  {"metadata": {"namespace": "shop"}, "involvedObject": {"kind": "Pod", "name": "web-1"}, "type": "Warning", "reason": "BackOff", "message": "Back-off restarting failed container"}
+]}`
+
+func TestCollectAndAnalyzeStorage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dc := NewDiagnosticCollector(logger, t.TempDir())
+
+	dc.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		call := strings.Join(args, " ")
+		output := ""
+		switch {
+		case strings.HasPrefix(call, "get pods -n "+CSIDriversNamespace):
+			output = testCSIPods
+		case strings.HasPrefix(call, "get volumeattachments"):
+			output = testVolumeAttachments
+		case strings.HasPrefix(call, "get persistentvolumes"):
+			output = fmt.Sprintf(testPersistentVolumes, time.Now().UTC().Format(time.RFC3339))
+		case strings.HasPrefix(call, "get events -A --field-selector type=Warning"):
+			output = testStorageEvents
+		case strings.HasPrefix(call, "logs aws-ebs-csi-driver-controller-6b9c7d8f5d-abcde -c csi-provisioner"):
+			output = "2026-10-16T09:00:00.000000000Z E1016 09:00:00.000000 1 controller.go:957] error syncing claim: failed to provision volume with StorageClass \"gp3-csi\": rpc error: code = Internal desc = Could not create volume: UnauthorizedOperation: You are not authorized to perform this operation"
+		case strings.HasPrefix(call, "logs aws-ebs-csi-driver-controller-6b9c7d8f5d-abcde -c csi-attacher"):
+			output = "2026-10-16T09:02:00.000000000Z E1016 09:02:00.000000 1 csi_handler.go:234] Error processing \"csi-bbb\": failed to attach: rpc error: code = Internal desc = Could not attach volume vol-1 to node i-2: VolumeInUse"
+		case strings.HasPrefix(call, "logs aws-ebs-csi-driver-node-x2 -c csi-driver") && strings.Contains(call, "--previous=true"):
+			output = "2026-10-16T08:50:00.000000000Z E1016 08:50:00.000000 1 mount_linux.go:232] Mount failed: exit status 32, wrong fs type, bad option, bad superblock on /dev/nvme1n1"
+		case strings.HasPrefix(call, "logs"):
+			output = "2026-10-16T08:00:00.000000000Z I1016 08:00:00.000000 1 driver.go:73] Driver: ebs.csi.aws.com"
+		default:
+			return exec.CommandContext(ctx, "sh", "-c", "echo 'unexpected call' >&2; exit 1")
+		}
+		return exec.CommandContext(ctx, "sh", "-c", "cat <<'EOF'\n"+output+"\nEOF")
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "storage")
+	result, err := dc.CollectStorage(context.Background(), &CollectionOptions{OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("CollectStorage() error = %v", err)
+	}
+	if result.Metadata["pods"] != "4" || result.Metadata["logs"] != "9" || result.Metadata["attachments"] != "2" || result.Metadata["events"] != "2" || result.Metadata["failed"] != "0" {
+		t.Errorf("CollectStorage() metadata = %v, expected 4 pods, 9 logs, 2 attachments and 2 events", result.Metadata)
+	}
+
+	analysis, err := newTestAnalysisEngine().AnalyzeStorage(context.Background(), outputDir)
+	if err != nil {
+		t.Fatalf("AnalyzeStorage() error = %v", err)
+	}
+	if analysis.Metrics["signature_matches"] != 3 || analysis.Metrics["problem_volumes"] != 3 {
+		t.Errorf("AnalyzeStorage() metrics = %v", analysis.Metrics)
+	}
+	issues := make(map[string]Issue)
+	for _, issue := range analysis.Issues {
+		issues[issue.Title] = issue
+	}
+	for title, severity := range map[string]string{
+		"CSI driver is denied by the cloud provider":                       "critical",
+		"Volumes are still attached to another node":                       "warning",
+		"Volumes fail to mount on nodes":                                   "warning",
+		"1 of 2 CSI node plugins of aws-ebs-csi-driver-node are not ready": "warning",
+		"Pods of aws-ebs-csi-driver-node are restarting":                   "warning",
+		"1 volumes of ebs.csi.aws.com fail to attach":                      "critical",
+		"1 volumes of ebs.csi.aws.com fail to detach":                      "warning",
+		"1 volumes are stuck terminating":                                  "warning",
+		"1 volumes failed to be reclaimed":                                 "warning",
+		"1 claims cannot be provisioned":                                   "critical",
+		"1 pods cannot mount their volumes":                                "warning",
+	} {
+		if issues[title].Severity != severity {
+			t.Errorf("AnalyzeStorage() issue %q = %+v, expected severity %s", title, issues[title], severity)
+		}
+	}
+	if len(analysis.Issues) != 11 {
+		t.Errorf("AnalyzeStorage() issues = %+v, expected 11", analysis.Issues)
+	}
+	if stuck := issues["1 volumes are stuck terminating"]; len(stuck.Evidence) != 1 || !strings.HasPrefix(stuck.Evidence[0], "pvc-old (claim shop/old) deleting for ") ||
+		!strings.HasSuffix(stuck.Evidence[0], "finalizers external-provisioner.volume.kubernetes.io/finalizer") {
+		t.Errorf("stuck volumes issue = %+v", stuck)
+	}
+	if provisioning := issues["1 claims cannot be provisioned"]; len(provisioning.Evidence) != 1 || !strings.HasSuffix(provisioning.Evidence[0], "(x4)") {
+		t.Errorf("provisioning issue = %+v", provisioning)
+	}
+	if analysis.Issues[0].Severity != "critical" {
+		t.Errorf("AnalyzeStorage() issues not sorted: %+v", analysis.Issues)
+	}
+}
+
+func TestParseCSIPods(t *testing.T) {
+	pods, err := parseCSIPods([]byte(testCSIPods))
+	if err != nil {
+		t.Fatalf("parseCSIPods() error = %v", err)
+	}
+	var got []string
+	for _, pod := range pods {
+		got = append(got, fmt.Sprintf("%s %s %s", pod.Name, pod.Workload, pod.Role))
+	}
+	expected := []string{
+		"aws-ebs-csi-driver-controller-6b9c7d8f5d-abcde aws-ebs-csi-driver-controller controller",
+		"aws-ebs-csi-driver-node-x1 aws-ebs-csi-driver-node node",
+		"aws-ebs-csi-driver-node-x2 aws-ebs-csi-driver-node node",
+		"aws-ebs-csi-driver-operator-7f8d9c6b5a-fghij aws-ebs-csi-driver-operator operator",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("parseCSIPods() = %q, expected %q", got, expected)
+	}
+}
+
+func TestStorageInfrastructureIssues(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	healthy := StorageState{
+		Pods: []CSIPodState{
+			{Name: "controller-a", Workload: "csi-controller", Role: CSIRoleController, Ready: true},
+			{Name: "node-a", Workload: "csi-node", Role: CSIRoleNode, Ready: true, Restarts: 1},
+		},
+		Volumes: []VolumeState{{Name: "pvc-1", Phase: "Released", DeletingSince: now.Add(-time.Minute)}},
+	}
+	if issues := StorageInfrastructureIssues(healthy, now); len(issues) != 0 {
+		t.Errorf("healthy storage: issues %+v", issues)
+	}
+
+	down := StorageState{
+		Pods: []CSIPodState{
+			{Name: "controller-a", Workload: "csi-controller", Role: CSIRoleController, Node: "master-0", Reason: "CrashLoopBackOff"},
+			{Name: "node-a", Workload: "csi-node", Role: CSIRoleNode, Node: "worker-1"},
+			{Name: "operator-a", Workload: "csi-operator", Role: CSIRoleOperator},
+		},
+		Events: []StorageEvent{
+			{Namespace: "shop", Kind: "PersistentVolumeClaim", Name: "data", Reason: "VolumeResizeFailed", Message: "resize not supported", Count: 1},
+			{Namespace: "shop", Kind: "PersistentVolumeClaim", Name: "data", Reason: "VolumeResizeFailed", Message: "resize not supported", Count: 1},
+		},
+	}
+	var titles []string
+	for _, issue := range StorageInfrastructureIssues(down, now) {
+		titles = append(titles, issue.Severity+" "+issue.Title)
+	}
+	expected := []string{
+		"critical No CSI controller pod of csi-controller is ready",
+		"critical 1 of 1 CSI node plugins of csi-node are not ready",
+		"warning CSI driver operator csi-operator is not ready",
+		"warning 1 volumes fail to expand",
+	}
+	if strings.Join(titles, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("StorageInfrastructureIssues() = %q, expected %q", titles, expected)
+	}
+}
@@ -0,0 +1,336 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+// PcapMode selects how AnalyzeTcpdumpWith inspects a capture
+type PcapMode string
+
+const (
+	// PcapQuick decodes the capture with the built-in decoder only
+	PcapQuick PcapMode = "quick"
+	// PcapDeep also runs tshark's TCP analysis, DNS and TLS dissectors
+	PcapDeep PcapMode = "deep"
+)
+
+// ParsePcapMode parses a capture analysis mode; empty means quick
+func ParsePcapMode(value string) (PcapMode, error) {
+	switch mode := PcapMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return PcapQuick, nil
+	case PcapQuick, PcapDeep:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown analysis mode %q (expected quick or deep)", value)
+}
+
+// errNoTshark is returned by deep analysis when tshark is not installed
+var errNoTshark = errors.New("tshark is not installed")
+
+// tsharkFilter selects the packets deep analysis reads: TCP analysis
+// findings, DNS error responses and TLS alerts
+const tsharkFilter = "tcp.analysis.flags || (dns.flags.response == 1 && dns.flags.rcode != 0) || tls.alert_message"
+
+// tsharkFields are extracted from each selected packet, in this order
+var tsharkFields = []string{
+	"ip.src", "ipv6.src", "ip.dst", "ipv6.dst", "tcp.srcport", "tcp.dstport",
+	"dns.qry.name", "dns.flags.rcode",
+	"tls.alert_message.level", "tls.alert_message.desc",
+	"_ws.col.Info",
+}
+
+const (
+	tsharkIPSrc = iota
+	tsharkIPv6Src
+	tsharkIPDst
+	tsharkIPv6Dst
+	tsharkSrcPort
+	tsharkDstPort
+	tsharkDNSName
+	tsharkDNSRcode
+	tsharkAlertLevel
+	tsharkAlertDesc
+	tsharkInfo
+)
+
+// tsharkTCPTag matches the analysis tags tshark puts in the Info column,
+// e.g. [TCP ZeroWindow] or [TCP Dup ACK 12#3]
+var tsharkTCPTag = regexp.MustCompile(`\[TCP ([A-Za-z][A-Za-z -]*?)(?: \d+#\d+)?\]`)
+
+// tlsAlertNames names the TLS alert descriptions seen in failed handshakes
+var tlsAlertNames = map[int]string{
+	10: "unexpected_message", 20: "bad_record_mac", 40: "handshake_failure",
+	42: "bad_certificate", 43: "unsupported_certificate", 44: "certificate_revoked",
+	45: "certificate_expired", 46: "certificate_unknown", 47: "illegal_parameter",
+	48: "unknown_ca", 49: "access_denied", 50: "decode_error", 51: "decrypt_error",
+	70: "protocol_version", 71: "insufficient_security", 80: "internal_error",
+	86: "inappropriate_fallback", 109: "missing_extension", 110: "unsupported_extension",
+	112: "unrecognized_name", 116: "certificate_required", 120: "no_application_protocol",
+}
+
+// tlsAlertFatal is the level of alerts that abort the connection
+const tlsAlertFatal = 2
+
+// tsharkStats accumulates the findings of one tshark pass
+type tsharkStats struct {
+	packets     int
+	tcpTags     map[string]int
+	tcpTagFlow  map[string]map[string]int // tag -> conversation -> count
+	dnsErrors   map[string]int            // "RCODE name" -> count
+	tlsFailures map[string]int            // "alert from src to dst:port" -> count
+	dropped     bool
+}
+
+func newTsharkStats() *tsharkStats {
+	return &tsharkStats{
+		tcpTags:     make(map[string]int),
+		tcpTagFlow:  make(map[string]map[string]int),
+		dnsErrors:   make(map[string]int),
+		tlsFailures: make(map[string]int),
+	}
+}
+
+// count increments a bounded map, dropping new keys once it is full
+func (st *tsharkStats) count(counts map[string]int, key string) {
+	if _, ok := counts[key]; !ok && len(counts) >= maxPcapFlows {
+		st.dropped = true
+		return
+	}
+	counts[key]++
+}
+
+// add records one line of tshark field output
+func (st *tsharkStats) add(line string) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < len(tsharkFields) {
+		return
+	}
+	st.packets++
+	src, dst := fields[tsharkIPSrc]+fields[tsharkIPv6Src], fields[tsharkIPDst]+fields[tsharkIPv6Dst]
+	conversation := fmt.Sprintf("%s → %s", joinHostPort(src, fields[tsharkSrcPort]), joinHostPort(dst, fields[tsharkDstPort]))
+
+	for _, match := range tsharkTCPTag.FindAllStringSubmatch(fields[tsharkInfo], -1) {
+		tag := match[1]
+		st.tcpTags[tag]++
+		if st.tcpTagFlow[tag] == nil {
+			st.tcpTagFlow[tag] = make(map[string]int)
+		}
+		st.count(st.tcpTagFlow[tag], conversation)
+	}
+
+	if rcode, err := strconv.Atoi(fields[tsharkDNSRcode]); err == nil && rcode != 0 {
+		name := dnsRcodeNames[layers.DNSResponseCode(rcode)]
+		if name == "" {
+			name = fmt.Sprintf("RCODE %d", rcode)
+		}
+		st.count(st.dnsErrors, name+" "+fields[tsharkDNSName])
+	}
+
+	level, _ := strconv.Atoi(fields[tsharkAlertLevel])
+	if desc, err := strconv.Atoi(fields[tsharkAlertDesc]); err == nil && level == tlsAlertFatal {
+		alert := tlsAlertNames[desc]
+		if alert == "" {
+			alert = fmt.Sprintf("alert %d", desc)
+		}
+		st.count(st.tlsFailures, fmt.Sprintf("%s from %s to %s", alert, joinHostPort(src, fields[tsharkSrcPort]), joinHostPort(dst, fields[tsharkDstPort])))
+	}
+}
+
+// joinHostPort renders an address with its port, when there is one
+func joinHostPort(host, port string) string {
+	switch {
+	case host == "":
+		return "?"
+	case port == "":
+		return host
+	case strings.Contains(host, ":"):
+		return "[" + host + "]:" + port
+	}
+	return host + ":" + port
+}
+
+// tsharkPath returns the tshark to run
+func (ae *AnalysisEngine) tsharkPath() (string, error) {
+	if ae.tshark != "" {
+		return ae.tshark, nil
+	}
+	path, err := exec.LookPath("tshark")
+	if err != nil {
+		return "", errNoTshark
+	}
+	return path, nil
+}
+
+// analyzePcapWithTshark runs one tshark field extraction over the capture
+// and reports what the built-in decoder does not see: receive windows,
+// segments missing from the capture, reordering, TLS handshake alerts and
+// DNS errors outside UDP port 53
+func (ae *AnalysisEngine) analyzePcapWithTshark(ctx context.Context, pcapPath string, result *AnalysisResult) error {
+	tshark, err := ae.tsharkPath()
+	if err != nil {
+		return err
+	}
+	args := []string{"-r", pcapPath, "-n", "-Y", tsharkFilter, "-T", "fields", "-E", "separator=/t", "-E", "occurrence=f"}
+	for _, field := range tsharkFields {
+		args = append(args, "-e", field)
+	}
+
+	command := exec.CommandContext
+	if ae.command != nil {
+		command = ae.command
+	}
+	cmd := command(ctx, tshark, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting tshark: %v", err)
+	}
+
+	stats := newTsharkStats()
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), ae.limits.MaxLineLength)
+	for scanner.Scan() {
+		stats.add(scanner.Text())
+	}
+	scanErr := scanner.Err()
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		message, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n")
+		return fmt.Errorf("tshark failed: %v: %s", err, message)
+	}
+	if scanErr != nil {
+		return fmt.Errorf("reading tshark output: %v", scanErr)
+	}
+	if stats.dropped {
+		result.Truncated = true
+		result.TruncationReasons = append(result.TruncationReasons,
+			fmt.Sprintf("tshark reported more than %d distinct conversations, DNS names or TLS alerts; later ones were not tracked", maxPcapFlows))
+	}
+
+	ae.reportTshark(pcapPath, stats, result)
+	return nil
+}
+
+// tagCount sums tshark TCP analysis tags and their conversations
+func (st *tsharkStats) tagCount(tags ...string) (int, map[string]int) {
+	total, flows := 0, make(map[string]int)
+	for _, tag := range tags {
+		total += st.tcpTags[tag]
+		for flow, n := range st.tcpTagFlow[tag] {
+			flows[flow] += n
+		}
+	}
+	return total, flows
+}
+
+// reportTshark turns the tshark findings into metrics and issues
+func (ae *AnalysisEngine) reportTshark(pcapPath string, st *tsharkStats, result *AnalysisResult) {
+	result.Metrics["tshark_packets"] = st.packets
+	if len(st.tcpTags) > 0 {
+		result.Metrics["tshark_tcp_analysis"] = st.tcpTags
+	}
+	flowEvidence := func(flows map[string]int) []string {
+		return topCounts(flows, 5, func(flow string, n int) string {
+			return fmt.Sprintf("%s (%d)", flow, n)
+		})
+	}
+
+	if windows, flows := st.tagCount("ZeroWindow", "Window Full"); windows > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "performance",
+			Title:       "TCP Receive Window Exhausted",
+			Description: fmt.Sprintf("%d segment(s) hit a full or zero receive window: the receiver is not reading as fast as data arrives", windows),
+			Location:    pcapPath,
+			Evidence:    flowEvidence(flows),
+			Resolution:  "The receiving application is stalled or CPU-throttled: check its CPU limits and throttling, slow consumers and the socket buffer sizes",
+			Metadata:    map[string]string{"occurrences": fmt.Sprint(windows), "source": "tshark"},
+		})
+	}
+	if missing, flows := st.tagCount("Previous segment not captured", "ACKed unseen segment"); missing > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "capture",
+			Title:       "Segments Missing from Capture",
+			Description: fmt.Sprintf("%d gap(s) where segments were lost on the network or not captured", missing),
+			Location:    pcapPath,
+			Evidence:    flowEvidence(flows),
+			Resolution:  "If tcpdump reported packets dropped by the kernel, capture again with a larger buffer (-B) or a tighter filter; otherwise the segments were lost on the path",
+			Metadata:    map[string]string{"occurrences": fmt.Sprint(missing), "source": "tshark"},
+		})
+	}
+	if reordered, flows := st.tagCount("Dup ACK", "Out-Of-Order"); reordered >= 10 {
+		ae.addIssue(result, Issue{
+			Severity:    "warning",
+			Category:    "network",
+			Title:       "TCP Duplicate ACKs and Reordering",
+			Description: fmt.Sprintf("%d duplicate ACK(s) or out-of-order segment(s), from packet loss or reordering on the path", reordered),
+			Location:    pcapPath,
+			Evidence:    flowEvidence(flows),
+			Resolution:  "Check for drops on the node NICs and the overlay, bonded links reordering packets and MTU mismatches",
+			Metadata:    map[string]string{"occurrences": fmt.Sprint(reordered), "source": "tshark"},
+		})
+	}
+
+	failures := 0
+	for _, n := range st.tlsFailures {
+		failures += n
+	}
+	if failures > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "critical",
+			Category:    "security",
+			Title:       "TLS Handshake Failures",
+			Description: fmt.Sprintf("%d connection(s) were aborted by a fatal TLS alert", failures),
+			Location:    pcapPath,
+			Evidence: topCounts(st.tlsFailures, 5, func(failure string, n int) string {
+				return fmt.Sprintf("%s (%d)", failure, n)
+			}),
+			Resolution: "certificate_expired: renew the certificate; unknown_ca or bad_certificate: add the CA to the client's trust bundle (the cluster proxy trustedCA for platform components); protocol_version or handshake_failure: align the TLS security profiles; unrecognized_name: check the SNI host",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(failures), "source": "tshark"},
+		})
+	}
+
+	// The built-in decoder already reports DNS errors on UDP port 53 and
+	// 5353; tshark also sees DNS over TCP and on other ports
+	dnsErrors := 0
+	for _, n := range st.dnsErrors {
+		dnsErrors += n
+	}
+	if dnsErrors > 0 {
+		result.Metrics["tshark_dns_errors"] = dnsErrors
+	}
+	native, _ := result.Metrics["dns_failures"].(int)
+	nxdomain, _ := result.Metrics["dns_nxdomain"].(int)
+	if extra := dnsErrors - native - nxdomain; extra > 0 {
+		ae.addIssue(result, Issue{
+			Severity:    "info",
+			Category:    "network",
+			Title:       "DNS Errors Outside UDP 53",
+			Description: fmt.Sprintf("tshark found %d DNS error response(s) the quick analysis did not, over TCP or on other ports", extra),
+			Location:    pcapPath,
+			Evidence: topCounts(st.dnsErrors, 5, func(failure string, n int) string {
+				return fmt.Sprintf("%s (%d)", failure, n)
+			}),
+			Resolution: "Large answers fall back to TCP; check the resolvers these queries went to and the names they failed for",
+			Metadata:   map[string]string{"occurrences": fmt.Sprint(extra), "source": "tshark"},
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package diagnostics
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tsharkOutput is tab-separated field output as tsharkFields extracts it
+var tsharkOutput = strings.Join([]string{
+	"10.0.0.2\t\t10.0.0.1\t\t8080\t40000\t\t\t\t\t8080 → 40000 [TCP ZeroWindow] [ACK] Seq=1 Ack=1 Win=0 Len=0",
+	"10.0.0.2\t\t10.0.0.1\t\t8080\t40000\t\t\t\t\t[TCP ZeroWindow] 8080 → 40000 [ACK] Seq=1 Ack=1 Win=0 Len=0",
+	"10.0.0.1\t\t10.0.0.2\t\t40000\t8080\t\t\t\t\t[TCP Window Full] 40000 → 8080 [PSH, ACK] Len=1448",
+	"10.0.0.1\t\t10.0.0.2\t\t40000\t8080\t\t\t\t\t[TCP Previous segment not captured] 40000 → 8080 [ACK] Len=1448",
+	"10.0.0.1\t\t10.0.0.2\t\t40000\t8080\t\t\t\t\t[TCP Dup ACK 7#1] 40000 → 8080 [ACK]",
+	"10.0.0.5\t\t10.0.0.1\t\t443\t40010\t\t\t2\t45\tAlert (Level: Fatal, Description: Certificate Expired)",
+	"\tfd00::5\t\tfd00::1\t443\t40011\t\t\t2\t48\tAlert (Level: Fatal, Description: Unknown CA)",
+	"10.0.0.5\t\t10.0.0.1\t\t443\t40012\t\t\t1\t0\tAlert (Level: Warning, Description: Close Notify)",
+	"172.30.0.10\t\t10.0.0.1\t\t53\t40100\tbig.example.com\t2\t\t\tStandard query response 0x1234 Server failure",
+	"truncated line",
+}, "\n") + "\n"
+
+func newFakeTsharkEngine(t *testing.T, script string) *AnalysisEngine {
+	ae := newTestAnalysisEngine()
+	ae.tshark = "tshark"
+	ae.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sh", "-c", script)
+	}
+	return ae
+}
+
+func TestParsePcapMode(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected PcapMode
+		valid    bool
+	}{
+		{"", PcapQuick, true},
+		{"quick", PcapQuick, true},
+		{" Deep ", PcapDeep, true},
+		{"thorough", "", false},
+	}
+	for _, tt := range tests {
+		mode, err := ParsePcapMode(tt.value)
+		if mode != tt.expected || (err == nil) != tt.valid {
+			t.Errorf("ParsePcapMode(%q) = %q, %v, expected %q", tt.value, mode, err, tt.expected)
+		}
+	}
+}
+
+func TestAnalyzeTcpdumpDeep(t *testing.T) {
+	b := newPcapBuilder()
+	b.packet(0, ethernetIPv4("10.0.0.1", "10.0.0.2", 6, tcpSegment(40000, 8080, 1000, 0, tcpSYN, 0)))
+	b.packet(time.Millisecond, ethernetIPv4("10.0.0.2", "10.0.0.1", 6, tcpSegment(8080, 40000, 5000, 1001, tcpSYN|tcpACK, 0)))
+	path := writeCapture(t, b.buf.Bytes())
+
+	ae := newFakeTsharkEngine(t, "printf '"+strings.ReplaceAll(tsharkOutput, "\t", `\t`)+"'")
+	result, err := ae.AnalyzeTcpdumpWith(context.Background(), path, PcapDeep)
+	if err != nil {
+		t.Fatalf("AnalyzeTcpdumpWith(deep) error = %v", err)
+	}
+	if result.Metrics["analysis_mode"] != "deep" || result.Metrics["tshark_packets"] != 9 {
+		t.Errorf("AnalyzeTcpdumpWith(deep) metrics = %v", result.Metrics)
+	}
+	if tags, _ := result.Metrics["tshark_tcp_analysis"].(map[string]int); tags["ZeroWindow"] != 2 || tags["Dup ACK"] != 1 {
+		t.Errorf("tshark TCP analysis tags = %v, expected 2 ZeroWindow and 1 Dup ACK", tags)
+	}
+
+	issues := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		issues[issue.Title] = issue
+	}
+	expected := map[string]string{
+		"TCP Receive Window Exhausted":  "3",
+		"Segments Missing from Capture": "1",
+		"TLS Handshake Failures":        "2",
+		"DNS Errors Outside UDP 53":     "1",
+	}
+	for title, occurrences := range expected {
+		if issue, ok := issues[title]; !ok || issue.Metadata["occurrences"] != occurrences {
+			t.Errorf("issue %q = %+v, expected %s occurrences", title, issue, occurrences)
+		}
+	}
+	if _, ok := issues["TCP Duplicate ACKs and Reordering"]; ok {
+		t.Error("a single duplicate ACK was reported as reordering")
+	}
+	evidence := strings.Join(issues["TLS Handshake Failures"].Evidence, "\n")
+	for _, text := range []string{"certificate_expired from 10.0.0.5:443 to 10.0.0.1:40010", "unknown_ca from [fd00::5]:443 to [fd00::1]:40011"} {
+		if !strings.Contains(evidence, text) {
+			t.Errorf("TLS evidence missing %q:\n%s", text, evidence)
+		}
+	}
+}
+
+func TestAnalyzeTcpdumpDeepFallback(t *testing.T) {
+	b := newPcapBuilder()
+	b.packet(0, ethernetIPv4("10.0.0.1", "10.0.0.2", 6, tcpSegment(40000, 8080, 1000, 0, tcpSYN, 0)))
+	path := writeCapture(t, b.buf.Bytes())
+
+	tests := []struct {
+		name   string
+		engine *AnalysisEngine
+		quick  bool
+	}{
+		{"tshark fails", newFakeTsharkEngine(t, "echo 'tshark: The file appears to be damaged' >&2; exit 2"), false},
+		{"quick mode", newFakeTsharkEngine(t, "exit 1"), true},
+	}
+	for _, tt := range tests {
+		mode := PcapDeep
+		if tt.quick {
+			mode = PcapQuick
+		}
+		result, err := tt.engine.AnalyzeTcpdumpWith(context.Background(), path, mode)
+		if err != nil {
+			t.Fatalf("%s: AnalyzeTcpdumpWith() error = %v", tt.name, err)
+		}
+		if result.Metrics["analysis_mode"] != "quick" || result.Metrics["packets"] != 1 {
+			t.Errorf("%s: metrics = %v, expected the quick analysis", tt.name, result.Metrics)
+		}
+		unavailable := false
+		for _, issue := range result.Issues {
+			if issue.Title == "Deep Analysis Unavailable" {
+				unavailable = strings.Contains(issue.Description, "damaged")
+			}
+		}
+		if unavailable == tt.quick {
+			t.Errorf("%s: issues = %+v, expected a deep analysis failure only when deep mode fails", tt.name, result.Issues)
+		}
+	}
+}
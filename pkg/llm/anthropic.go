@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	defaultAnthropicModel   = "claude-sonnet-4-5"
+	// anthropicVersion is the Messages API version the client speaks
+	anthropicVersion = "2023-06-01"
+	// defaultAnthropicMaxTokens is sent when none is configured, as the
+	// Messages API requires a limit
+	defaultAnthropicMaxTokens = 1500
+	anthropicTimeout          = 2 * time.Minute
+)
+
+// AnthropicClient implements LLM client using the Anthropic Messages API
+type AnthropicClient struct {
+	promptClient
+	apiKey       string
+	baseURL      string
+	model        string
+	systemPrompt string
+	temperature  float64
+	maxTokens    int
+	httpClient   *http.Client
+}
+
+// anthropicMessage is one turn of a Messages API conversation
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicRequest is the body of a Messages API request
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature"`
+}
+
+// anthropicResponse is the part of a Messages API response the client reads
+type anthropicResponse struct {
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicError is the error body Anthropic returns with a failed request
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewAnthropicClient creates an Anthropic client; the configured system
+// prompt is placed before the instruction of every call
+func NewAnthropicClient(cfg config.ClaudeConfig) (*AnthropicClient, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("Anthropic API key is required")
+	}
+	client := &AnthropicClient{
+		apiKey:       cfg.APIKey,
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		model:        cfg.Model,
+		systemPrompt: strings.TrimSpace(cfg.SystemPrompt),
+		temperature:  cfg.Temperature,
+		maxTokens:    cfg.MaxTokens,
+		httpClient:   &http.Client{Timeout: anthropicTimeout},
+	}
+	if client.baseURL == "" {
+		client.baseURL = defaultAnthropicBaseURL
+	}
+	if client.model == "" {
+		client.model = defaultAnthropicModel
+	}
+	if client.maxTokens <= 0 {
+		client.maxTokens = defaultAnthropicMaxTokens
+	}
+	client.promptClient = newPromptClient(client)
+	return client, nil
+}
+
+// Complete sends a prompt as the user turn, with the system instruction in
+// the system parameter
+func (a *AnthropicClient) Complete(ctx context.Context, system, prompt string) (*Completion, error) {
+	var instructions []string
+	for _, part := range []string{a.systemPrompt, system} {
+		if part != "" {
+			instructions = append(instructions, part)
+		}
+	}
+	request := anthropicRequest{
+		Model:       a.model,
+		System:      strings.Join(instructions, "\n\n"),
+		Messages:    []anthropicMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   a.maxTokens,
+		Temperature: a.temperature,
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Anthropic response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr anthropicError
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("Anthropic API error %d (%s): %s", resp.StatusCode, apiErr.Error.Type, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("Anthropic API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var response anthropicResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Anthropic response: %w", err)
+	}
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("no text in Anthropic response (stop reason %s)", response.StopReason)
+	}
+	return &Completion{
+		Text:         text.String(),
+		Model:        response.Model,
+		InputTokens:  response.Usage.InputTokens,
+		OutputTokens: response.Usage.OutputTokens,
+	}, nil
+}
+
+// Close releases idle connections
+func (a *AnthropicClient) Close() error {
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+func TestAnthropicClient(t *testing.T) {
+	var received anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "sk-ant-test" || r.Header.Get("anthropic-version") != anthropicVersion {
+			t.Errorf("request to %s with headers %v", r.URL.Path, r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatal(err)
+		}
+		if received.Messages[0].Content == "overloaded" {
+			w.WriteHeader(529)
+			w.Write([]byte(`{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`))
+			return
+		}
+		w.Write([]byte(`{"model": "claude-sonnet-4-5-20250929", "content": [{"type": "text", "text": "Check the "}, {"type": "text", "text": "pod events."}], "stop_reason": "end_turn", "usage": {"input_tokens": 150, "output_tokens": 9}}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewAnthropicClient(config.ClaudeConfig{}); err == nil {
+		t.Error("NewAnthropicClient() accepted a config without an API key")
+	}
+	client, err := NewAnthropicClient(config.ClaudeConfig{APIKey: "sk-ant-test", BaseURL: server.URL + "/", Model: "claude-sonnet-4-5", Temperature: 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completion, err := client.Complete(context.Background(), "be brief", "why is my pod pending?")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if *completion != (Completion{Text: "Check the pod events.", Model: "claude-sonnet-4-5-20250929", InputTokens: 150, OutputTokens: 9}) {
+		t.Errorf("Complete() = %+v", completion)
+	}
+	if received.Model != "claude-sonnet-4-5" || received.System != "be brief" || received.Temperature != 0.2 || received.MaxTokens != defaultAnthropicMaxTokens ||
+		len(received.Messages) != 1 || received.Messages[0] != (anthropicMessage{Role: "user", Content: "why is my pod pending?"}) {
+		t.Errorf("request = %+v", received)
+	}
+
+	if _, err := client.Complete(context.Background(), "", "overloaded"); err == nil || !strings.Contains(err.Error(), "Anthropic API error 529 (overloaded_error): Overloaded") {
+		t.Errorf("Complete() error = %v", err)
+	}
+
+	// The configured system prompt precedes the instruction of each call
+	client, err = NewAnthropicClient(config.ClaudeConfig{APIKey: "sk-ant-test", BaseURL: server.URL, SystemPrompt: "Clusters run OpenShift 4.14.", MaxTokens: 400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response, err := client.GenerateTroubleshootingResponse("pods crash", "CrashLoopBackOff", "OOMKilled"); err != nil || response != "Check the pod events." {
+		t.Errorf("GenerateTroubleshootingResponse() = %q, %v", response, err)
+	}
+	if received.Model != defaultAnthropicModel || received.MaxTokens != 400 || received.System != "Clusters run OpenShift 4.14.\n\n"+sreSystemPrompt ||
+		!strings.Contains(received.Messages[0].Content, "OOMKilled") {
+		t.Errorf("troubleshooting request = %+v", received)
+	}
+}
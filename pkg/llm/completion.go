@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Completion is the text of an LLM response and the tokens it consumed; the
+// token counts are zero when the provider does not report them
+type Completion struct {
+	Text         string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+// Completer sends a system instruction and a prompt to a model
+type Completer interface {
+	Complete(ctx context.Context, system, prompt string) (*Completion, error)
+}
+
+// sreSystemPrompt is the system instruction of knowledge-base prompts
+const sreSystemPrompt = "You are an expert OpenShift Site Reliability Engineer. Give accurate, actionable answers with the oc commands and YAML they need."
+
+// alternativeAnalysisPrompt asks for a different take on a troubleshooting
+// prompt the user declined the first answer to
+func alternativeAnalysisPrompt(prompt string) string {
+	return fmt.Sprintf(`%s
+
+ALTERNATIVE ANALYSIS REQUEST:
+The user has declined the initial analysis and wants a different approach.
+Provide:
+1. Alternative root cause analysis using different reasoning
+2. Different solution strategies
+3. Cross-correlation patterns with other cluster issues
+4. Advanced troubleshooting techniques
+
+Focus on creative, systematic approaches that might not be immediately obvious.`, prompt)
+}
+
+// promptClient implements the knowledge-base prompts of EnhancedClient on
+// top of a provider's Completer
+type promptClient struct {
+	completer     Completer
+	promptManager *PromptManager
+}
+
+func newPromptClient(completer Completer) promptClient {
+	return promptClient{completer: completer, promptManager: NewPromptManager()}
+}
+
+func (p promptClient) complete(prompt string) (string, error) {
+	completion, err := p.completer.Complete(context.Background(), sreSystemPrompt, prompt)
+	if err != nil {
+		return "", err
+	}
+	return completion.Text, nil
+}
+
+// GenerateResponse answers a prompt with OpenShift knowledge injected
+func (p promptClient) GenerateResponse(prompt string) (string, error) {
+	return p.complete(p.promptManager.InjectGeneralKnowledge(prompt))
+}
+
+// GenerateSpecializedResponse answers a prompt built for an SRE scenario
+func (p promptClient) GenerateSpecializedResponse(req *PromptRequest) (string, error) {
+	prompt, err := p.promptManager.GenerateSpecializedPrompt(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate specialized prompt: %w", err)
+	}
+	return p.complete(prompt)
+}
+
+// GetAlternativeAnalysis gives a different troubleshooting analysis of a query
+func (p promptClient) GetAlternativeAnalysis(originalQuery string) (string, error) {
+	prompt, err := p.promptManager.GenerateSpecializedPrompt(&PromptRequest{
+		Type:      "troubleshooting",
+		UserQuery: originalQuery,
+		Context:   map[string]string{"analysis_type": "alternative"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate alternative analysis prompt: %w", err)
+	}
+	response, err := p.complete(alternativeAnalysisPrompt(prompt))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🤖 **Alternative OpenShift SRE Analysis:**\n\n%s", response), nil
+}
+
+// GenerateTroubleshootingResponse troubleshoots an issue from its symptoms and logs
+func (p promptClient) GenerateTroubleshootingResponse(issue, symptoms, logs string) (string, error) {
+	return p.GenerateSpecializedResponse(&PromptRequest{
+		Type:        "troubleshooting",
+		UserQuery:   issue,
+		Context:     map[string]string{"symptoms": symptoms, "logs": logs},
+		Environment: "production",
+	})
+}
+
+// GenerateSecurityReview reviews the security of YAML configurations
+func (p promptClient) GenerateSecurityReview(yamlContent string) (string, error) {
+	return p.GenerateSpecializedResponse(&PromptRequest{
+		Type:        "security",
+		UserQuery:   "Please perform a comprehensive security review of this OpenShift configuration",
+		Context:     map[string]string{"yaml": yamlContent, "compliance": "CIS Kubernetes Benchmark"},
+		Environment: "production",
+	})
+}
+
+// GenerateIncidentResponse guides the response to an incident
+func (p promptClient) GenerateIncidentResponse(incidentType, severity, affectedServices string) (string, error) {
+	return p.GenerateSpecializedResponse(&PromptRequest{
+		Type:        "incident",
+		UserQuery:   fmt.Sprintf("Critical incident: %s", incidentType),
+		Context:     map[string]string{"affected_services": affectedServices, "incident_type": incidentType},
+		Severity:    severity,
+		Environment: "production",
+	})
+}
+
+// GeneratePerformanceAnalysis recommends optimizations for performance issues
+func (p promptClient) GeneratePerformanceAnalysis(metrics, issues string) (string, error) {
+	return p.GenerateSpecializedResponse(&PromptRequest{
+		Type:        "performance",
+		UserQuery:   "Analyze performance issues and provide optimization recommendations",
+		Context:     map[string]string{"current_metrics": metrics, "performance_issues": issues},
+		Environment: "production",
+	})
+}
+
+// GenerateCapacityPlanningGuidance recommends how to scale for projected growth
+func (p promptClient) GenerateCapacityPlanningGuidance(currentUsage, projectedGrowth string) (string, error) {
+	return p.GenerateSpecializedResponse(&PromptRequest{
+		Type:        "capacity",
+		UserQuery:   "Provide capacity planning guidance for OpenShift cluster scaling",
+		Context:     map[string]string{"current_usage": currentUsage, "growth_projections": projectedGrowth},
+		Environment: "production",
+	})
+}
@@ -2,7 +2,9 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
@@ -30,15 +32,61 @@ type EnhancedClient interface {
 type GeminiClient struct {
 	client        *genai.Client
 	model         *genai.GenerativeModel
+	modelName     string
 	config        *config.Config
 	promptManager *PromptManager
 }
 
+// defaultGeminiModel is used when the llm.gemini section names no model
+const defaultGeminiModel = "gemini-2.0-flash-001"
+
+// geminiHarmCategories maps the keys of llm.gemini.safety_settings to the
+// harm categories Gemini models filter
+var geminiHarmCategories = map[string]genai.HarmCategory{
+	"harassment":        genai.HarmCategoryHarassment,
+	"hate_speech":       genai.HarmCategoryHateSpeech,
+	"sexually_explicit": genai.HarmCategorySexuallyExplicit,
+	"dangerous_content": genai.HarmCategoryDangerousContent,
+}
+
+// geminiBlockThresholds maps the configured thresholds to the probability
+// of harm at which Gemini blocks a response
+var geminiBlockThresholds = map[string]genai.HarmBlockThreshold{
+	"block_none":             genai.HarmBlockNone,
+	"block_only_high":        genai.HarmBlockOnlyHigh,
+	"block_medium_and_above": genai.HarmBlockMediumAndAbove,
+	"block_low_and_above":    genai.HarmBlockLowAndAbove,
+}
+
 // NewClient creates a new LLM client based on configuration
 func NewClient(cfg *config.Config) (Client, error) {
 	switch cfg.LLMProvider {
 	case "gemini":
 		return NewGeminiClient(cfg)
+	case "openai":
+		client, err := NewOpenAIClient(cfg.LLM.OpenAI)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case "openai-compatible":
+		client, err := NewOpenAICompatibleClient(cfg.LLM.OpenAICompatible)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case "claude":
+		client, err := NewAnthropicClient(cfg.LLM.Claude)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case "ollama":
+		client, err := NewOllamaClient(cfg.LLM.Ollama)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.LLMProvider)
 	}
@@ -67,11 +115,113 @@ func NewGeminiClient(cfg *config.Config) (*GeminiClient, error) {
 	return &GeminiClient{
 		client:        client,
 		model:         model,
+		modelName:     cfg.Model,
 		config:        cfg,
 		promptManager: NewPromptManager(),
 	}, nil
 }
 
+// NewGeminiClientFromLLMConfig creates a Gemini client from the llm.gemini
+// section, with its temperature, token limit and safety settings
+func NewGeminiClientFromLLMConfig(cfg config.GeminiConfig) (*GeminiClient, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("Gemini API key is required")
+	}
+	safetySettings, err := geminiSafetySettings(cfg.SafetySettings)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []option.ClientOption{option.WithAPIKey(cfg.APIKey)}
+	if cfg.BaseURL != "" {
+		options = append(options, option.WithEndpoint(cfg.BaseURL))
+	}
+	client, err := genai.NewClient(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	modelName := cfg.Model
+	if modelName == "" {
+		modelName = defaultGeminiModel
+	}
+	model := client.GenerativeModel(modelName)
+	model.SetTemperature(float32(cfg.Temperature))
+	if cfg.MaxTokens > 0 {
+		model.SetMaxOutputTokens(int32(cfg.MaxTokens))
+	}
+	model.SafetySettings = safetySettings
+
+	return &GeminiClient{
+		client:        client,
+		model:         model,
+		modelName:     modelName,
+		promptManager: NewPromptManager(),
+	}, nil
+}
+
+// geminiSafetySettings converts the configured threshold per harm category,
+// sorted by category so requests are stable; unset categories keep the
+// model's defaults
+func geminiSafetySettings(settings map[string]string) ([]*genai.SafetySetting, error) {
+	var result []*genai.SafetySetting
+	for name, value := range settings {
+		category, ok := geminiHarmCategories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini harm category %q (use harassment, hate_speech, sexually_explicit or dangerous_content)", name)
+		}
+		threshold, ok := geminiBlockThresholds[value]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini block threshold %q for %s (use block_none, block_only_high, block_medium_and_above or block_low_and_above)", value, name)
+		}
+		result = append(result, &genai.SafetySetting{Category: category, Threshold: threshold})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Category < result[j].Category })
+	return result, nil
+}
+
+// SetJSONOutput makes the model answer with JSON only, as planning expects
+func (g *GeminiClient) SetJSONOutput(enabled bool) {
+	if enabled {
+		g.model.ResponseMIMEType = "application/json"
+	} else {
+		g.model.ResponseMIMEType = ""
+	}
+}
+
+// Complete generates content for a prompt with the system instruction as the
+// model's system prompt
+func (g *GeminiClient) Complete(ctx context.Context, system, prompt string) (*Completion, error) {
+	model := *g.model
+	if system != "" {
+		model.SystemInstruction = &genai.Content{Parts: []genai.Part{genai.Text(system)}}
+	}
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		var blocked *genai.BlockedError
+		if errors.As(err, &blocked) {
+			return nil, fmt.Errorf("Gemini blocked the response, see llm.gemini.safety_settings: %w", err)
+		}
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return nil, fmt.Errorf("no response generated")
+	}
+
+	completion := &Completion{Model: g.modelName}
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			completion.Text += string(textPart)
+		}
+	}
+	if resp.UsageMetadata != nil {
+		completion.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
+		completion.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+	}
+	return completion, nil
+}
+
 // GenerateResponse generates a response for a given prompt using OpenShift knowledge
 func (g *GeminiClient) GenerateResponse(prompt string) (string, error) {
 	ctx := context.Background()
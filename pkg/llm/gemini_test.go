@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+func TestGeminiSafetySettings(t *testing.T) {
+	settings, err := geminiSafetySettings(map[string]string{"dangerous_content": "block_only_high", "harassment": "block_none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings) != 2 || *settings[0] != (genai.SafetySetting{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockNone}) ||
+		*settings[1] != (genai.SafetySetting{Category: genai.HarmCategoryDangerousContent, Threshold: genai.HarmBlockOnlyHigh}) {
+		t.Errorf("geminiSafetySettings() = %+v, %+v", settings[0], settings[1])
+	}
+	if _, err := geminiSafetySettings(map[string]string{"violence": "block_none"}); err == nil {
+		t.Error("geminiSafetySettings() accepted an unknown harm category")
+	}
+	if _, err := geminiSafetySettings(map[string]string{"harassment": "block_some"}); err == nil {
+		t.Error("geminiSafetySettings() accepted an unknown threshold")
+	}
+}
+
+func TestGeminiClientComplete(t *testing.T) {
+	var received struct {
+		SystemInstruction struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"systemInstruction"`
+		GenerationConfig struct {
+			ResponseMimeType string  `json:"responseMimeType"`
+			Temperature      float64 `json:"temperature"`
+			MaxOutputTokens  int     `json:"maxOutputTokens"`
+		} `json:"generationConfig"`
+		SafetySettings []struct {
+			Category  json.RawMessage `json:"category"`
+			Threshold json.RawMessage `json:"threshold"`
+		} `json:"safetySettings"`
+	}
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if r.Header.Get("x-goog-api-key") != "gemini-test" && r.URL.Query().Get("key") != "gemini-test" {
+			t.Errorf("request without the API key: %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "{\"steps\": "}, {"text": "[]}"}]}, "finishReason": 1}], "usageMetadata": {"promptTokenCount": 210, "candidatesTokenCount": 12}}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewGeminiClientFromLLMConfig(config.GeminiConfig{}); err == nil {
+		t.Error("NewGeminiClientFromLLMConfig() accepted a config without an API key")
+	}
+	if _, err := NewGeminiClientFromLLMConfig(config.GeminiConfig{APIKey: "gemini-test", SafetySettings: map[string]string{"medical": "block_none"}}); err == nil {
+		t.Error("NewGeminiClientFromLLMConfig() accepted invalid safety settings")
+	}
+	client, err := NewGeminiClientFromLLMConfig(config.GeminiConfig{
+		APIKey:         "gemini-test",
+		BaseURL:        server.URL,
+		Temperature:    0.1,
+		MaxTokens:      900,
+		SafetySettings: map[string]string{"dangerous_content": "block_only_high"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetJSONOutput(true)
+
+	completion, err := client.Complete(context.Background(), "respond only with JSON", "restart the router")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if *completion != (Completion{Text: `{"steps": []}`, Model: defaultGeminiModel, InputTokens: 210, OutputTokens: 12}) {
+		t.Errorf("Complete() = %+v", completion)
+	}
+	if !strings.HasSuffix(path, "/models/"+defaultGeminiModel+":generateContent") {
+		t.Errorf("request to %s", path)
+	}
+	if len(received.SystemInstruction.Parts) != 1 || received.SystemInstruction.Parts[0].Text != "respond only with JSON" ||
+		received.GenerationConfig.ResponseMimeType != "application/json" || received.GenerationConfig.MaxOutputTokens != 900 || len(received.SafetySettings) != 1 {
+		t.Errorf("request = %+v", received)
+	}
+
+	// Knowledge-base prompts keep free text answers and no system instruction
+	client.SetJSONOutput(false)
+	received.SystemInstruction.Parts, received.GenerationConfig.ResponseMimeType = nil, ""
+	if _, err := client.GenerateResponse("why is my pod pending?"); err != nil {
+		t.Fatalf("GenerateResponse() error = %v", err)
+	}
+	if received.GenerationConfig.ResponseMimeType != "" || len(received.SystemInstruction.Parts) != 0 {
+		t.Errorf("knowledge request = %+v", received)
+	}
+}
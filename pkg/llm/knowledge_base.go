@@ -59,7 +59,7 @@ Common Pod Issues:
 - NetworkPolicies: Control traffic between pods
 
 Network Troubleshooting:
-- DNS issues: 'oc exec <pod> -- nslookup <service>'
+- DNS issues: the diagnose_cluster_dns tool checks CoreDNS, its upstreams and lookups from the nodes; for one pod 'oc exec <pod> -- nslookup <service>'
 - Connectivity: 'oc exec <pod> -- curl <service>:<port>'
 - Route problems: 'oc get routes', check TLS certificates
 - Service endpoints: 'oc get endpoints <service>'
@@ -449,6 +449,40 @@ Provide a comprehensive response using the knowledge and patterns above. Include
 		userQuery)
 }
 
+// TroubleshootingSection returns the investigation path and common causes of
+// a "### " issue pattern in the troubleshooting patterns, matched by heading
+// prefix, e.g. "Storage Problems"
+func (kb *OpenShiftKnowledgeBase) TroubleshootingSection(pattern string) (path []string, causes []string) {
+	var list *[]string
+	inSection := false
+	for _, line := range strings.Split(kb.TroubleshootingPatterns, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			inSection = strings.HasPrefix(line, "### "+pattern)
+			list = nil
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		switch {
+		case line == "Investigation Path:":
+			list = &path
+		case line == "Common Causes:":
+			list = &causes
+		case line == "":
+			list = nil
+		case list != nil:
+			// Drop the "1. " or "- " list marker
+			if i := strings.Index(line, " "); i > 0 && (line[:i] == "-" || strings.HasSuffix(line[:i], ".")) {
+				line = line[i+1:]
+			}
+			*list = append(*list, line)
+		}
+	}
+	return path, causes
+}
+
 // GetSpecializedPrompt creates a specialized prompt for specific SRE scenarios
 func (kb *OpenShiftKnowledgeBase) GetSpecializedPrompt(scenario, userQuery string) string {
 	systemPrompt := `You are a senior OpenShift Site Reliability Engineer (SRE) with 10+ years of experience managing production OpenShift clusters. You have deep expertise in:
@@ -124,7 +124,7 @@ Common Pod Issues:
 - NetworkPolicies: Control traffic between pods
 
 Network Troubleshooting:
-- DNS issues: 'oc exec <pod> -- nslookup <service>'
+- DNS issues: the diagnose_cluster_dns tool checks CoreDNS, its upstreams and lookups from the nodes; for one pod 'oc exec <pod> -- nslookup <service>'
 - Connectivity: 'oc exec <pod> -- curl <service>:<port>'
 - Route problems: 'oc get routes', check TLS certificates
 - Service endpoints: 'oc get endpoints <service>'
@@ -0,0 +1,217 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+const (
+	defaultOllamaEndpoint = "http://localhost:11434"
+	defaultOllamaModel    = "llama3.1"
+	// ollamaTimeout bounds one chat, as models on CPU-only hosts answer slowly
+	ollamaTimeout = 5 * time.Minute
+)
+
+// OllamaClient implements LLM client using a local Ollama server, so
+// planning needs no outside network access
+type OllamaClient struct {
+	promptClient
+	endpoint    string
+	model       string
+	keepAlive   json.RawMessage
+	temperature float64
+	format      string
+	httpClient  *http.Client
+}
+
+// ollamaMessage is one message of an Ollama chat
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ollamaChatRequest is the body of an /api/chat request
+type ollamaChatRequest struct {
+	Model     string                 `json:"model"`
+	Messages  []ollamaMessage        `json:"messages"`
+	Stream    bool                   `json:"stream"`
+	Format    string                 `json:"format,omitempty"`
+	KeepAlive json.RawMessage        `json:"keep_alive,omitempty"`
+	Options   map[string]interface{} `json:"options"`
+}
+
+// ollamaChatResponse is the part of an /api/chat response the client reads
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+}
+
+// ollamaTags lists the models pulled on an Ollama server
+type ollamaTags struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// ollamaError is the error body Ollama returns with a failed request
+type ollamaError struct {
+	Error string `json:"error"`
+}
+
+// NewOllamaClient creates an Ollama client; the endpoint and model default to
+// llama3.1 at localhost:11434
+func NewOllamaClient(cfg config.OllamaConfig) (*OllamaClient, error) {
+	keepAlive, err := ollamaKeepAlive(cfg.KeepAlive)
+	if err != nil {
+		return nil, err
+	}
+	client := &OllamaClient{
+		endpoint:    strings.TrimRight(cfg.Endpoint, "/"),
+		model:       cfg.Model,
+		keepAlive:   keepAlive,
+		temperature: cfg.Temperature,
+		httpClient:  &http.Client{Timeout: ollamaTimeout},
+	}
+	if client.endpoint == "" {
+		client.endpoint = defaultOllamaEndpoint
+	} else if !strings.Contains(client.endpoint, "://") {
+		client.endpoint = "http://" + client.endpoint
+	}
+	if client.model == "" {
+		client.model = defaultOllamaModel
+	}
+	client.promptClient = newPromptClient(client)
+	return client, nil
+}
+
+// ollamaKeepAlive converts how long Ollama keeps the model loaded after a
+// request: a duration such as 30m, or seconds, where negative means forever
+func ollamaKeepAlive(value string) (json.RawMessage, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return json.RawMessage(strconv.Itoa(seconds)), nil
+	}
+	if _, err := time.ParseDuration(value); err != nil {
+		return nil, fmt.Errorf("invalid Ollama keep_alive %q: use a duration such as 30m or seconds", value)
+	}
+	return json.Marshal(value)
+}
+
+// SetJSONOutput makes the model answer with JSON only, as planning expects
+func (o *OllamaClient) SetJSONOutput(enabled bool) {
+	if enabled {
+		o.format = "json"
+	} else {
+		o.format = ""
+	}
+}
+
+// modelMissing explains how to make the configured model available
+func (o *OllamaClient) modelMissing() error {
+	return fmt.Errorf("Ollama model %q is not pulled on %s; run `ollama pull %s` there", o.model, o.endpoint, o.model)
+}
+
+// CheckHealth verifies the server answers and has the configured model
+func (o *OllamaClient) CheckHealth(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.endpoint+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Ollama is not reachable at %s: %w", o.endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Ollama API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var tags ollamaTags
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return fmt.Errorf("failed to parse Ollama models: %w", err)
+	}
+	for _, model := range tags.Models {
+		// Untagged model names refer to the latest tag
+		if model.Name == o.model || model.Name == o.model+":latest" {
+			return nil
+		}
+	}
+	return o.modelMissing()
+}
+
+// Complete sends a chat with a system and a user message
+func (o *OllamaClient) Complete(ctx context.Context, system, prompt string) (*Completion, error) {
+	request := ollamaChatRequest{
+		Model:     o.model,
+		Format:    o.format,
+		KeepAlive: o.keepAlive,
+		Options:   map[string]interface{}{"temperature": o.temperature},
+	}
+	if system != "" {
+		request.Messages = append(request.Messages, ollamaMessage{Role: "system", Content: system})
+	}
+	request.Messages = append(request.Messages, ollamaMessage{Role: "user", Content: prompt})
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama is not reachable at %s: %w", o.endpoint, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Ollama response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, o.modelMissing()
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr ollamaError
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return nil, fmt.Errorf("Ollama API error %d: %s", resp.StatusCode, apiErr.Error)
+		}
+		return nil, fmt.Errorf("Ollama API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var response ollamaChatResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Ollama response: %w", err)
+	}
+	return &Completion{
+		Text:         response.Message.Content,
+		Model:        response.Model,
+		InputTokens:  response.PromptEvalCount,
+		OutputTokens: response.EvalCount,
+	}, nil
+}
+
+// Close releases idle connections
+func (o *OllamaClient) Close() error {
+	o.httpClient.CloseIdleConnections()
+	return nil
+}
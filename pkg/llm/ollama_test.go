@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+func TestOllamaClient(t *testing.T) {
+	var received ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models": [{"name": "llama3.1:latest"}, {"name": "qwen2.5:7b"}]}`))
+		case "/api/chat":
+			received = ollamaChatRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Fatal(err)
+			}
+			if received.Model != "llama3.1" && received.Model != "qwen2.5:7b" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error": "model \"` + received.Model + `\" not found, try pulling it first"}`))
+				return
+			}
+			w.Write([]byte(`{"model": "llama3.1", "message": {"role": "assistant", "content": "{\"steps\": []}"}, "done": true, "prompt_eval_count": 310, "eval_count": 15}`))
+		default:
+			t.Errorf("request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := NewOllamaClient(config.OllamaConfig{KeepAlive: "forever"}); err == nil {
+		t.Error("NewOllamaClient() accepted an invalid keep_alive")
+	}
+	client, err := NewOllamaClient(config.OllamaConfig{Endpoint: server.URL + "/", KeepAlive: "30m", Temperature: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth() error = %v", err)
+	}
+
+	client.SetJSONOutput(true)
+	completion, err := client.Complete(context.Background(), "respond only with JSON", "restart the router")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if *completion != (Completion{Text: `{"steps": []}`, Model: "llama3.1", InputTokens: 310, OutputTokens: 15}) {
+		t.Errorf("Complete() = %+v", completion)
+	}
+	if received.Stream || received.Format != "json" || string(received.KeepAlive) != `"30m"` || received.Options["temperature"] != 0.1 ||
+		len(received.Messages) != 2 || received.Messages[0] != (ollamaMessage{Role: "system", Content: "respond only with JSON"}) {
+		t.Errorf("request = %+v", received)
+	}
+
+	// A model that is not pulled fails the health check and chats alike
+	missing, err := NewOllamaClient(config.OllamaConfig{Endpoint: server.URL, Model: "mistral", KeepAlive: "-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := missing.CheckHealth(context.Background()); err == nil || !strings.Contains(err.Error(), "ollama pull mistral") {
+		t.Errorf("CheckHealth() error = %v", err)
+	}
+	if _, err := missing.GenerateResponse("why is my pod pending?"); err == nil || !strings.Contains(err.Error(), "ollama pull mistral") {
+		t.Errorf("GenerateResponse() error = %v", err)
+	}
+	if string(received.KeepAlive) != "-1" || received.Format != "" {
+		t.Errorf("knowledge request = %+v", received)
+	}
+
+	server.Close()
+	if err := client.CheckHealth(context.Background()); err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Errorf("CheckHealth() error = %v", err)
+	}
+}
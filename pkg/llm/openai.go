@@ -0,0 +1,178 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4"
+	// openAITimeout bounds one chat completion, including slow long answers
+	openAITimeout = 2 * time.Minute
+	// openAICompatibleTimeout also covers self-hosted servers queueing requests
+	openAICompatibleTimeout = 5 * time.Minute
+)
+
+// OpenAIClient implements LLM client using the OpenAI chat completions API,
+// or any server exposing it at BaseURL
+type OpenAIClient struct {
+	promptClient
+	name        string // names the server in errors
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature float64
+	maxTokens   int
+	httpClient  *http.Client
+}
+
+// openAIMessage is one message of a chat completion
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIRequest is the body of a chat completion request
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature float64         `json:"temperature"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+}
+
+// openAIResponse is the part of a chat completion response the client reads
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// openAIError is the error body OpenAI returns with a failed request
+type openAIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// NewOpenAIClient creates an OpenAI client; the model and base URL default
+// to gpt-4 at api.openai.com
+func NewOpenAIClient(cfg config.OpenAIConfig) (*OpenAIClient, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("OpenAI API key is required")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultOpenAIBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = defaultOpenAIModel
+	}
+	return newOpenAIClient("OpenAI", cfg, openAITimeout), nil
+}
+
+// NewOpenAICompatibleClient creates a client for a self-hosted server
+// speaking the OpenAI chat completions API, such as vLLM, LM Studio or the
+// llama.cpp server; those need a base URL and model but often no API key
+func NewOpenAICompatibleClient(cfg config.OpenAIConfig) (*OpenAIClient, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("base URL of the OpenAI-compatible server is required")
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model served by the OpenAI-compatible server is required")
+	}
+	return newOpenAIClient("OpenAI-compatible", cfg, openAICompatibleTimeout), nil
+}
+
+func newOpenAIClient(name string, cfg config.OpenAIConfig, timeout time.Duration) *OpenAIClient {
+	client := &OpenAIClient{
+		name:        name,
+		apiKey:      cfg.APIKey,
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+		maxTokens:   cfg.MaxTokens,
+		httpClient:  &http.Client{Timeout: timeout},
+	}
+	client.promptClient = newPromptClient(client)
+	return client
+}
+
+// Complete sends a chat completion with a system and a user message
+func (o *OpenAIClient) Complete(ctx context.Context, system, prompt string) (*Completion, error) {
+	request := openAIRequest{
+		Model:       o.model,
+		Temperature: o.temperature,
+		MaxTokens:   o.maxTokens,
+	}
+	if system != "" {
+		request.Messages = append(request.Messages, openAIMessage{Role: "system", Content: system})
+	}
+	request.Messages = append(request.Messages, openAIMessage{Role: "user", Content: prompt})
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", o.name, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", o.name, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr openAIError
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("%s API error %d (%s): %s", o.name, resp.StatusCode, apiErr.Error.Type, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("%s API error %d: %s", o.name, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var response openAIResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse %s response: %w", o.name, err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in %s response", o.name)
+	}
+	return &Completion{
+		Text:         response.Choices[0].Message.Content,
+		Model:        response.Model,
+		InputTokens:  response.Usage.PromptTokens,
+		OutputTokens: response.Usage.CompletionTokens,
+	}, nil
+}
+
+// Close releases idle connections
+func (o *OpenAIClient) Close() error {
+	o.httpClient.CloseIdleConnections()
+	return nil
+}
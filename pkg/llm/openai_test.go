@@ -0,0 +1,102 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+)
+
+func TestOpenAIClient(t *testing.T) {
+	var received openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("request to %s with Authorization %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatal(err)
+		}
+		if received.Messages[len(received.Messages)-1].Content == "rate-limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"message": "Rate limit reached for gpt-4o", "type": "requests", "code": "rate_limit_exceeded"}}`))
+			return
+		}
+		w.Write([]byte(`{"model": "gpt-4o-2024-08-06", "choices": [{"message": {"role": "assistant", "content": "Check the pod events."}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 120, "completion_tokens": 8}}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewOpenAIClient(config.OpenAIConfig{}); err == nil {
+		t.Error("NewOpenAIClient() accepted a config without an API key")
+	}
+	client, err := NewOpenAIClient(config.OpenAIConfig{APIKey: "sk-test", BaseURL: server.URL + "/v1/", Model: "gpt-4o", Temperature: 0.2, MaxTokens: 800})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completion, err := client.Complete(context.Background(), "be brief", "why is my pod pending?")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if *completion != (Completion{Text: "Check the pod events.", Model: "gpt-4o-2024-08-06", InputTokens: 120, OutputTokens: 8}) {
+		t.Errorf("Complete() = %+v", completion)
+	}
+	if received.Model != "gpt-4o" || received.Temperature != 0.2 || received.MaxTokens != 800 || len(received.Messages) != 2 ||
+		received.Messages[0] != (openAIMessage{Role: "system", Content: "be brief"}) || received.Messages[1].Content != "why is my pod pending?" {
+		t.Errorf("request = %+v", received)
+	}
+
+	// Knowledge-base prompts go through the same completions
+	if response, err := client.GenerateTroubleshootingResponse("pods crash", "CrashLoopBackOff", "OOMKilled"); err != nil || response != "Check the pod events." {
+		t.Errorf("GenerateTroubleshootingResponse() = %q, %v", response, err)
+	}
+	if received.Messages[0].Content != sreSystemPrompt || !strings.Contains(received.Messages[1].Content, "OOMKilled") {
+		t.Errorf("troubleshooting request = %+v", received.Messages)
+	}
+
+	if _, err := client.Complete(context.Background(), "", "rate-limited"); err == nil || !strings.Contains(err.Error(), "OpenAI API error 429 (requests): Rate limit reached") {
+		t.Errorf("Complete() error = %v", err)
+	}
+	if len(received.Messages) != 1 {
+		t.Errorf("request without a system instruction sent %d messages", len(received.Messages))
+	}
+}
+
+func TestOpenAICompatibleClient(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("request to %s", r.URL.Path)
+		}
+		// llama.cpp and LM Studio may leave out usage
+		w.Write([]byte(`{"model": "qwen2.5-coder-7b", "choices": [{"message": {"role": "assistant", "content": "Check the pod events."}}]}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewOpenAICompatibleClient(config.OpenAIConfig{Model: "qwen2.5-coder-7b"}); err == nil {
+		t.Error("NewOpenAICompatibleClient() accepted a config without a base URL")
+	}
+	if _, err := NewOpenAICompatibleClient(config.OpenAIConfig{BaseURL: server.URL + "/v1"}); err == nil {
+		t.Error("NewOpenAICompatibleClient() accepted a config without a model")
+	}
+	client, err := NewOpenAICompatibleClient(config.OpenAIConfig{BaseURL: server.URL + "/v1", Model: "qwen2.5-coder-7b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	completion, err := client.Complete(context.Background(), "be brief", "why is my pod pending?")
+	if err != nil || *completion != (Completion{Text: "Check the pod events.", Model: "qwen2.5-coder-7b"}) {
+		t.Errorf("Complete() = %+v, %v", completion, err)
+	}
+	if authorization != "" {
+		t.Errorf("request without an API key sent Authorization %q", authorization)
+	}
+
+	server.Close()
+	if _, err := client.Complete(context.Background(), "", "why is my pod pending?"); err == nil || !strings.HasPrefix(err.Error(), "OpenAI-compatible request failed") {
+		t.Errorf("Complete() error = %v", err)
+	}
+}
@@ -417,7 +417,7 @@ func (sre *SREAssistant) extractPerformanceIssues(input string) string {
 
 // ExampleTroubleshooting demonstrates troubleshooting capabilities
 func ExampleTroubleshooting() {
-	fmt.Println(`
+	fmt.Print(`
 // Example: Troubleshooting Usage
 assistant := NewSREAssistant(geminiClient)
 
@@ -431,7 +431,7 @@ fmt.Println(response)
 
 // ExampleSecurityReview demonstrates security review capabilities
 func ExampleSecurityReview() {
-	fmt.Println(`
+	fmt.Print(`
 // Example: Security Review Usage
 yamlConfig := """
 apiVersion: v1
@@ -457,7 +457,7 @@ fmt.Println(response)
 
 // ExampleIncidentResponse demonstrates incident response capabilities
 func ExampleIncidentResponse() {
-	fmt.Println(`
+	fmt.Print(`
 // Example: Incident Response Usage
 response, err := geminiClient.GenerateIncidentResponse(
     "API Server Unresponsive", 
@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Annotations set on the Events this server emits and, when enabled, on the
+// changed resources themselves
+const (
+	actionToolAnnotation    = "mcp.openshift.io/tool"
+	actionSessionAnnotation = "mcp.openshift.io/session-id"
+	actionPlanAnnotation    = "mcp.openshift.io/plan-id"
+	lastActionAnnotation    = "mcp.openshift.io/last-action"
+	lastActionAtAnnotation  = "mcp.openshift.io/last-action-at"
+)
+
+// Event reasons for the changes this server makes
+const (
+	ReasonScaled     = "MCPScaled"
+	ReasonRestarted  = "MCPRestarted"
+	ReasonRolledBack = "MCPRolledBack"
+	ReasonApplied    = "MCPApplied"
+	ReasonPatched    = "MCPPatched"
+	ReasonCreated    = "MCPCreated"
+	ReasonDeleted    = "MCPDeleted"
+	ReasonCordoned   = "MCPCordoned"
+	ReasonUncordoned = "MCPUncordoned"
+	ReasonDrained    = "MCPDrained"
+	ReasonTriggered  = "MCPTriggered"
+)
+
+// ActionContext identifies the conversation and plan a change belongs to, so
+// cluster-side observers can trace it back to the agent that made it
+type ActionContext struct {
+	SessionID string
+	PlanID    string
+}
+
+type actionContextKey struct{}
+
+// WithActionContext attaches the session and plan of the tool calls made with ctx
+func WithActionContext(ctx context.Context, action ActionContext) context.Context {
+	return context.WithValue(ctx, actionContextKey{}, action)
+}
+
+// actionContextFrom returns the caller's session and plan; MCP clients
+// without one are identified by their protocol session
+func actionContextFrom(ctx context.Context) ActionContext {
+	action, _ := ctx.Value(actionContextKey{}).(ActionContext)
+	if action.SessionID == "" {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			action.SessionID = session.SessionID()
+		}
+	}
+	return action
+}
+
+// describe renders the session and plan for an event message
+func (a ActionContext) describe() string {
+	var parts []string
+	if a.SessionID != "" {
+		parts = append(parts, "session "+a.SessionID)
+	}
+	if a.PlanID != "" {
+		parts = append(parts, "plan "+a.PlanID)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// objectReference builds the reference an action event is about
+func objectReference(apiVersion, kind string, obj metav1.Object) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	}
+}
+
+// reportingInstance names this server replica in the events it emits
+func reportingInstance() string {
+	if hostname, err := os.Hostname(); err == nil {
+		return fieldManager + "-" + hostname
+	}
+	return fieldManager
+}
+
+// recordAction emits a Kubernetes Event on a resource this server changed and,
+// when configured, stamps the resource with annotations naming the action and
+// stores an ActionRecord. Failures are logged and never fail the action itself.
+func (s *Server) recordAction(ctx context.Context, tool string, target corev1.ObjectReference, reason, message string) {
+	if s.config == nil || s.k8sClient == nil || (!s.config.ActionEvents && !s.config.ActionAnnotations && !s.config.ActionRecords) {
+		return
+	}
+	action := actionContextFrom(ctx)
+	now := time.Now()
+
+	if s.config.ActionEvents {
+		text := fmt.Sprintf("%s by the MCP server (tool %s", message, tool)
+		if detail := action.describe(); detail != "" {
+			text += ", " + detail
+		}
+		text += ")"
+
+		annotations := map[string]string{actionToolAnnotation: tool}
+		if action.SessionID != "" {
+			annotations[actionSessionAnnotation] = action.SessionID
+		}
+		if action.PlanID != "" {
+			annotations[actionPlanAnnotation] = action.PlanID
+		}
+
+		// Events about cluster-scoped objects such as nodes live in default
+		namespace := target.Namespace
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		event := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("%s.%x", target.Name, now.UnixNano()),
+				Namespace:   namespace,
+				Annotations: annotations,
+			},
+			InvolvedObject:      target,
+			Reason:              reason,
+			Message:             text,
+			Type:                corev1.EventTypeNormal,
+			Source:              corev1.EventSource{Component: fieldManager},
+			FirstTimestamp:      metav1.NewTime(now),
+			LastTimestamp:       metav1.NewTime(now),
+			Count:               1,
+			Action:              tool,
+			ReportingController: fieldManager,
+			ReportingInstance:   reportingInstance(),
+		}
+		if _, err := s.k8sClient.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+			logrus.WithError(err).Warnf("Failed to record %s event for %s %s", reason, target.Kind, target.Name)
+		}
+	}
+
+	if s.config.ActionRecords {
+		if err := s.storeActionRecord(ctx, tool, target, reason, message, action, now); err != nil {
+			logActionRecordError(err, target, reason)
+		}
+	}
+
+	// A deleted object has nothing left to annotate
+	if s.config.ActionAnnotations && reason != ReasonDeleted {
+		if err := s.annotateAction(ctx, target, reason, action, now); err != nil {
+			logrus.WithError(err).Warnf("Failed to annotate %s %s with the %s action", target.Kind, target.Name, reason)
+		}
+	}
+}
+
+// annotateAction records the last action on the resource itself with a merge
+// patch, which leaves other annotations alone
+func (s *Server) annotateAction(ctx context.Context, target corev1.ObjectReference, reason string, action ActionContext, now time.Time) error {
+	if s.dynamicClient == nil || s.restMapper == nil {
+		return fmt.Errorf("resource discovery is not available")
+	}
+	gv, err := schema.ParseGroupVersion(target.APIVersion)
+	if err != nil {
+		return err
+	}
+	mapping, err := s.restMapper.RESTMapping(gv.WithKind(target.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return err
+	}
+
+	// null removes the session and plan of an earlier action
+	annotations := map[string]interface{}{
+		lastActionAnnotation:    reason,
+		lastActionAtAnnotation:  now.UTC().Format(time.RFC3339),
+		actionSessionAnnotation: nil,
+		actionPlanAnnotation:    nil,
+	}
+	if action.SessionID != "" {
+		annotations[actionSessionAnnotation] = action.SessionID
+	}
+	if action.PlanID != "" {
+		annotations[actionPlanAnnotation] = action.PlanID
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	_, err = s.resourceInterface(mapping.Resource, namespaced, target.Namespace).Patch(ctx, target.Name, types.MergePatchType, patch,
+		metav1.PatchOptions{FieldManager: fieldManager})
+	return err
+}
+
+// recordManifestActions records an action for every object in a manifest
+// applied with oc, which reports no UIDs back
+func (s *Server) recordManifestActions(ctx context.Context, tool, yamlContent, namespace, reason, message string) {
+	objects, err := decodeObjects(yamlContent)
+	if err != nil {
+		return
+	}
+	for _, obj := range objects {
+		if obj.GetNamespace() == "" && s.namespacedKind(obj.GroupVersionKind()) {
+			obj.SetNamespace(namespace)
+		}
+		target := objectReference(obj.GetAPIVersion(), obj.GetKind(), obj)
+		s.recordAction(ctx, tool, target, reason, fmt.Sprintf("%s %s", message, obj.GetKind()))
+	}
+}
+
+// namespacedKind reports whether a kind lives in a namespace; unknown kinds
+// are assumed to, as most are
+func (s *Server) namespacedKind(gvk schema.GroupVersionKind) bool {
+	if s.restMapper == nil {
+		return true
+	}
+	mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return true
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func listEvents(t *testing.T, s *Server, namespace string) []corev1.Event {
+	t.Helper()
+	events, err := s.k8sClient.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing events: %v", err)
+	}
+	return events.Items
+}
+
+func TestRecordAction(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", UID: "uid-worker-1"}}
+	ctx := WithActionContext(context.Background(), ActionContext{SessionID: "chat-42", PlanID: "plan-7"})
+
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset()}
+	s.recordAction(ctx, "cordon_node", objectReference("v1", "Node", node), ReasonCordoned, "Node cordoned")
+	if events := listEvents(t, s, "default"); len(events) != 0 {
+		t.Fatalf("recordAction with events disabled emitted %d events", len(events))
+	}
+
+	s.config.ActionEvents = true
+	s.recordAction(ctx, "cordon_node", objectReference("v1", "Node", node), ReasonCordoned, "Node cordoned")
+	events := listEvents(t, s, "default")
+	if len(events) != 1 {
+		t.Fatalf("recordAction emitted %d events, expected 1 in default for a cluster-scoped node", len(events))
+	}
+	event := events[0]
+	if event.Reason != ReasonCordoned || event.InvolvedObject.Kind != "Node" || event.InvolvedObject.UID != "uid-worker-1" {
+		t.Errorf("event = %s about %+v", event.Reason, event.InvolvedObject)
+	}
+	if want := "Node cordoned by the MCP server (tool cordon_node, session chat-42, plan plan-7)"; event.Message != want {
+		t.Errorf("event message = %q, expected %q", event.Message, want)
+	}
+	if event.Annotations[actionSessionAnnotation] != "chat-42" || event.Annotations[actionPlanAnnotation] != "plan-7" ||
+		event.ReportingController != fieldManager || event.Type != corev1.EventTypeNormal {
+		t.Errorf("event metadata = %v, controller %q, type %q", event.Annotations, event.ReportingController, event.Type)
+	}
+}
+
+func TestRecordActionAnnotations(t *testing.T) {
+	s := newDynamicTestServer(newUnstructured("apps/v1", "Deployment", "shop", "web"))
+	s.config.ActionAnnotations = true
+	ctx := WithActionContext(context.Background(), ActionContext{PlanID: "plan-7"})
+
+	target := corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "shop", Name: "web"}
+	s.recordAction(ctx, "scale_deployment", target, ReasonScaled, "Scaled from 1 to 3 replicas")
+
+	live, err := s.dynamicClient.Resource(deploymentsGVR).Namespace("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting deployment: %v", err)
+	}
+	annotations := live.GetAnnotations()
+	if annotations[lastActionAnnotation] != ReasonScaled || annotations[actionPlanAnnotation] != "plan-7" || annotations[lastActionAtAnnotation] == "" {
+		t.Errorf("annotations = %v, expected the last action and plan", annotations)
+	}
+	if _, ok := annotations[actionSessionAnnotation]; ok {
+		t.Errorf("annotations = %v, expected no session without one", annotations)
+	}
+	if events := listEvents(t, s, "shop"); len(events) != 0 {
+		t.Errorf("annotations alone emitted %d events", len(events))
+	}
+}
+
+func TestScaleDeploymentRecordsEvent(t *testing.T) {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "uid-web"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	s := &Server{config: &Config{ActionEvents: true}, k8sClient: kubefake.NewSimpleClientset(deployment), gitManager: NewGitManager(nil)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"deployment_name": "web", "namespace": "shop", "replicas": "3"}
+	ctx := WithActionContext(context.Background(), ActionContext{SessionID: "chat-42"})
+	if _, err := s.scaleDeploymentHandler(ctx, request); err != nil {
+		t.Fatalf("scaleDeploymentHandler error = %v", err)
+	}
+
+	events := listEvents(t, s, "shop")
+	if len(events) != 1 || events[0].Reason != ReasonScaled || !strings.Contains(events[0].Message, "Scaled from 1 to 3 replicas") ||
+		!strings.Contains(events[0].Message, "session chat-42") {
+		t.Errorf("scale_deployment events = %+v", events)
+	}
+}
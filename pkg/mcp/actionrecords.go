@@ -0,0 +1,400 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// Action records are cluster-scoped ActionRecord custom resources, the same
+// shape whether they are committed to Git or created in the cluster
+const (
+	ActionRecordAPIVersion = "mcp.openshift.io/v1alpha1"
+	ActionRecordKind       = "ActionRecord"
+	actionRecordCRDName    = "actionrecords.mcp.openshift.io"
+
+	actionTypeLabel      = "action-type"
+	actionNamespaceLabel = "mcp.openshift.io/target-namespace"
+	actionTargetLabel    = "mcp.openshift.io/target-name"
+	actionSessionLabel   = "mcp.openshift.io/session-id"
+)
+
+var actionRecordGVR = schema.GroupVersionResource{Group: "mcp.openshift.io", Version: "v1alpha1", Resource: "actionrecords"}
+
+// reasonActions maps the event reason of a change to its action record type
+var reasonActions = map[string]string{
+	ReasonScaled:     "scale",
+	ReasonRestarted:  "restart",
+	ReasonRolledBack: "rollback",
+	ReasonApplied:    "apply",
+	ReasonPatched:    "patch",
+	ReasonCreated:    "create",
+	ReasonDeleted:    "delete",
+	ReasonCordoned:   "cordon",
+	ReasonUncordoned: "uncordon",
+	ReasonDrained:    "drain",
+	ReasonTriggered:  "trigger",
+}
+
+// actionRecordTarget describes the resource an action changed
+type actionRecordTarget struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+}
+
+func (t actionRecordTarget) fields() map[string]interface{} {
+	fields := map[string]interface{}{"kind": t.Kind, "name": t.Name}
+	if t.APIVersion != "" {
+		fields["apiVersion"] = t.APIVersion
+	}
+	if t.Namespace != "" {
+		fields["namespace"] = t.Namespace
+	}
+	return fields
+}
+
+// actionRecordName builds a unique, valid object name for an action on target
+func actionRecordName(action, target string, at time.Time) string {
+	suffix := at.Format("20060102-150405")
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, action+"-"+target)
+	if max := validation.DNS1123SubdomainMaxLength - len(suffix) - 1; len(name) > max {
+		name = name[:max]
+	}
+	return strings.Trim(name, "-.") + "-" + suffix
+}
+
+// newActionRecord builds an ActionRecord; spec holds the action's own fields
+// (parameters, result, tool, ...) next to the action type and target
+func newActionRecord(action string, target actionRecordTarget, at time.Time, spec map[string]interface{}) map[string]interface{} {
+	recordLabels := map[string]interface{}{
+		actionTypeLabel: action,
+		"created-by":    "openshift-mcp",
+		"created-at":    at.Format("2006-01-02"),
+	}
+	for key, value := range map[string]string{actionNamespaceLabel: target.Namespace, actionTargetLabel: target.Name} {
+		if value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			recordLabels[key] = value
+		}
+	}
+
+	fullSpec := map[string]interface{}{
+		"action":    action,
+		"timestamp": at.UTC().Format(time.RFC3339),
+		"target":    target.fields(),
+	}
+	for key, value := range spec {
+		fullSpec[key] = value
+	}
+	return map[string]interface{}{
+		"apiVersion": ActionRecordAPIVersion,
+		"kind":       ActionRecordKind,
+		"metadata": map[string]interface{}{
+			"name":   actionRecordName(action, target.Name, at),
+			"labels": recordLabels,
+		},
+		"spec": fullSpec,
+	}
+}
+
+// actionRecordCRD is the CustomResourceDefinition for ActionRecord
+func actionRecordCRD() *unstructured.Unstructured {
+	free := map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+	str := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "description": description}
+	}
+	column := func(name, path string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "type": "string", "jsonPath": path}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name":   actionRecordCRDName,
+			"labels": map[string]interface{}{"created-by": "openshift-mcp"},
+		},
+		"spec": map[string]interface{}{
+			"group": actionRecordGVR.Group,
+			"scope": "Cluster",
+			"names": map[string]interface{}{
+				"plural":     "actionrecords",
+				"singular":   "actionrecord",
+				"kind":       ActionRecordKind,
+				"listKind":   ActionRecordKind + "List",
+				"shortNames": []interface{}{"mcpaction"},
+				"categories": []interface{}{"openshift-mcp"},
+			},
+			"versions": []interface{}{map[string]interface{}{
+				"name":    actionRecordGVR.Version,
+				"served":  true,
+				"storage": true,
+				"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+					"type":        "object",
+					"description": "A change an OpenShift MCP server tool made to the cluster",
+					"properties": map[string]interface{}{
+						"apiVersion": map[string]interface{}{"type": "string"},
+						"kind":       map[string]interface{}{"type": "string"},
+						"metadata":   map[string]interface{}{"type": "object"},
+						"spec": map[string]interface{}{
+							"type":     "object",
+							"required": []interface{}{"action", "target", "timestamp"},
+							"properties": map[string]interface{}{
+								"action":    str("Type of change, e.g. scale, restart, delete"),
+								"timestamp": map[string]interface{}{"type": "string", "format": "date-time"},
+								"target": map[string]interface{}{
+									"type":     "object",
+									"required": []interface{}{"kind", "name"},
+									"properties": map[string]interface{}{
+										"apiVersion": str("API version of the changed resource"),
+										"kind":       str("Kind of the changed resource"),
+										"name":       str("Name of the changed resource"),
+										"namespace":  str("Namespace of the changed resource, empty for cluster-scoped resources"),
+										"uid":        str("UID of the changed resource"),
+									},
+								},
+								"tool":       str("MCP tool that made the change"),
+								"reason":     str("Event reason recorded for the change"),
+								"message":    str("Human-readable description of the change"),
+								"sessionID":  str("Chat or MCP session that made the change"),
+								"planID":     str("Plan the change was part of"),
+								"parameters": free,
+								"result":     free,
+							},
+						},
+					},
+				}},
+				"additionalPrinterColumns": []interface{}{
+					column("Action", ".spec.action"),
+					column("Kind", ".spec.target.kind"),
+					column("Target", ".spec.target.name"),
+					column("Namespace", ".spec.target.namespace"),
+					column("Tool", ".spec.tool"),
+					column("Time", ".spec.timestamp"),
+				},
+			}},
+		},
+	}}
+}
+
+// storeActionRecord creates an ActionRecord for a change recordAction saw
+func (s *Server) storeActionRecord(ctx context.Context, tool string, target corev1.ObjectReference, reason, message string, action ActionContext, now time.Time) error {
+	if s.dynamicClient == nil {
+		return fmt.Errorf("dynamic client not available")
+	}
+	actionType, ok := reasonActions[reason]
+	if !ok {
+		actionType = strings.ToLower(strings.TrimPrefix(reason, "MCP"))
+	}
+	spec := map[string]interface{}{"tool": tool, "reason": reason, "message": message}
+	if action.SessionID != "" {
+		spec["sessionID"] = action.SessionID
+	}
+	if action.PlanID != "" {
+		spec["planID"] = action.PlanID
+	}
+	record := newActionRecord(actionType, actionRecordTarget{APIVersion: target.APIVersion, Kind: target.Kind, Name: target.Name, Namespace: target.Namespace}, now, spec)
+	if target.UID != "" {
+		record["spec"].(map[string]interface{})["target"].(map[string]interface{})["uid"] = string(target.UID)
+	}
+	obj := &unstructured.Unstructured{Object: record}
+	if action.SessionID != "" && len(validation.IsValidLabelValue(action.SessionID)) == 0 {
+		recordLabels := obj.GetLabels()
+		recordLabels[actionSessionLabel] = action.SessionID
+		obj.SetLabels(recordLabels)
+	}
+	// Several changes to one target can land within the same second
+	obj.SetName(fmt.Sprintf("%s-%x", obj.GetName(), now.UnixNano()%0x10000))
+
+	_, err := s.dynamicClient.Resource(actionRecordGVR).Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager})
+	return err
+}
+
+func (s *Server) initActionRecords() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("install_action_record_crd",
+			mcp.WithDescription("Install or update the ActionRecord CustomResourceDefinition (actionrecords.mcp.openshift.io), so the changes tools make can be stored in and queried from the cluster. Records are created when the server runs with action-records enabled"),
+			mcp.WithString("dry_run", mcp.Description("Show the CRD without installing it (true/false, default: false)")),
+			mcp.WithTitleAnnotation("Actions: Install Action Record CRD"),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.installActionRecordCRDHandler)},
+		{Tool: mcp.NewTool("list_action_records",
+			mcp.WithDescription("List the ActionRecords in the cluster: which tool changed which resource, when, and from which session, newest first"),
+			mcp.WithString("namespace", mcp.Description("Only changes to resources in this namespace")),
+			mcp.WithString("action", mcp.Description("Only this type of change, e.g. scale, restart, delete, drain")),
+			mcp.WithString("target", mcp.Description("Only changes to resources with this name")),
+			mcp.WithString("session_id", mcp.Description("Only changes made from this session")),
+			mcp.WithString("since", mcp.Description("Only changes within this duration, e.g. 24h")),
+			mcp.WithString("limit", mcp.Description("Maximum number of records to show (default: 20)")),
+			mcp.WithTitleAnnotation("Actions: List Action Records"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listActionRecordsHandler)},
+	}
+}
+
+// InstallActionRecordCRDHandler is a public wrapper for installActionRecordCRDHandler
+func (s *Server) InstallActionRecordCRDHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.installActionRecordCRDHandler(ctx, request)
+}
+
+// ListActionRecordsHandler is a public wrapper for listActionRecordsHandler
+func (s *Server) ListActionRecordsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listActionRecordsHandler(ctx, request)
+}
+
+func (s *Server) installActionRecordCRDHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	crd := actionRecordCRD()
+	if parseBoolString(mcp.ParseString(request, "dry_run", "false")) {
+		content, err := yaml.Marshal(crd.Object)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to render the ActionRecord CRD: %v", err)), nil
+		}
+		return mcp.NewToolResultText("🧪 Dry run: ActionRecord CRD (not installed)\n" +
+			"==========================================\n\n" + string(content)), nil
+	}
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+
+	crds := s.dynamicClient.Resource(crdGVR)
+	verb := "installed"
+	existing, err := crds.Get(ctx, actionRecordCRDName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		crd.SetResourceVersion(existing.GetResourceVersion())
+		_, err = crds.Update(ctx, crd, metav1.UpdateOptions{FieldManager: fieldManager})
+		verb = "updated"
+	case apierrors.IsNotFound(err):
+		_, err = crds.Create(ctx, crd, metav1.CreateOptions{FieldManager: fieldManager})
+	}
+	if err != nil {
+		return toolError(ctx, "❌ Failed to install the ActionRecord CRD", err), nil
+	}
+
+	result := "📜 ActionRecord CRD\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("✅ CustomResourceDefinition %s %s\n\n", actionRecordCRDName, verb)
+	result += fmt.Sprintf("Records are cluster-scoped: oc get %s\n", actionRecordGVR.GroupResource().String())
+	if s.config == nil || !s.config.ActionRecords {
+		result += "💡 Enable action-records in the server configuration to record every change as an ActionRecord.\n"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) listActionRecordsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	limit, err := strconv.Atoi(mcp.ParseString(request, "limit", "20"))
+	if err != nil || limit <= 0 {
+		return mcp.NewToolResultText("❌ limit must be a positive number"), nil
+	}
+	var since time.Time
+	if value := mcp.ParseString(request, "since", ""); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid since duration %q: %v", value, err)), nil
+		}
+		since = time.Now().Add(-window)
+	}
+
+	selector := labels.Set{}
+	for param, label := range map[string]string{"namespace": actionNamespaceLabel, "action": actionTypeLabel, "target": actionTargetLabel, "session_id": actionSessionLabel} {
+		if value := mcp.ParseString(request, param, ""); value != "" {
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid %s %q: %s", param, value, strings.Join(errs, "; "))), nil
+			}
+			selector[label] = value
+		}
+	}
+
+	list, err := s.dynamicClient.Resource(actionRecordGVR).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ The ActionRecord CRD is not installed. Run install_action_record_crd first."), nil
+		}
+		return toolError(ctx, "❌ Failed to list action records", err), nil
+	}
+
+	type entry struct {
+		at   time.Time
+		line string
+	}
+	var entries []entry
+	for _, item := range list.Items {
+		spec, _, _ := unstructured.NestedMap(item.Object, "spec")
+		target, _, _ := unstructured.NestedStringMap(item.Object, "spec", "target")
+		timestamp, _ := spec["timestamp"].(string)
+		at, _ := time.Parse(time.RFC3339, timestamp)
+		if !since.IsZero() && at.Before(since) {
+			continue
+		}
+		ref := target["kind"] + "/" + target["name"]
+		if target["namespace"] != "" {
+			ref = target["namespace"] + "/" + ref
+		}
+		line := fmt.Sprintf("• %s  %-8v %s", valueOrNone(timestamp), spec["action"], ref)
+		if tool, _ := spec["tool"].(string); tool != "" {
+			line += fmt.Sprintf(" (tool %s", tool)
+			if session, _ := spec["sessionID"].(string); session != "" {
+				line += ", session " + session
+			}
+			line += ")"
+		}
+		if message, _ := spec["message"].(string); message != "" {
+			line += "\n    " + message
+		}
+		entries = append(entries, entry{at: at, line: line})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.After(entries[j].at) })
+
+	result := "📜 Action Records\n"
+	result += "=================\n\n"
+	if len(entries) == 0 {
+		result += "No action records match.\n"
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("Showing %d of %d matching records, newest first\n\n", min(limit, len(entries)), len(entries))
+	for i, e := range entries {
+		if i == limit {
+			break
+		}
+		result += e.line + "\n"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// logActionRecordError reports a record that could not be stored, pointing at
+// the CRD when it is missing
+func logActionRecordError(err error, target corev1.ObjectReference, reason string) {
+	entry := logrus.WithError(err)
+	if apierrors.IsNotFound(err) {
+		entry = entry.WithField("hint", "install the ActionRecord CRD with install_action_record_crd")
+	}
+	entry.Warnf("Failed to store the %s action record for %s %s", reason, target.Kind, target.Name)
+}
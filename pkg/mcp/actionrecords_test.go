@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newActionRecordTestServer() *Server {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		actionRecordGVR: "ActionRecordList",
+		crdGVR:          "CustomResourceDefinitionList",
+	})
+	return &Server{config: &Config{ActionRecords: true}, k8sClient: kubefake.NewSimpleClientset(), dynamicClient: client}
+}
+
+func TestActionRecordName(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		action   string
+		target   string
+		expected string
+	}{
+		{"scale", "web", "scale-web-20261016-093000"},
+		{"delete", "My_Config", "delete-my-config-20261016-093000"},
+		{"drain", strings.Repeat("a", 300), ""},
+	}
+	for _, tt := range tests {
+		name := actionRecordName(tt.action, tt.target, at)
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			t.Errorf("actionRecordName(%q, %q) = %q is not a valid name: %v", tt.action, tt.target, name, errs)
+		}
+		if tt.expected != "" && name != tt.expected {
+			t.Errorf("actionRecordName(%q, %q) = %q, expected %q", tt.action, tt.target, name, tt.expected)
+		}
+	}
+}
+
+func TestGeneratedActionRecords(t *testing.T) {
+	y := NewYAMLGenerator()
+	content, err := y.GenerateScaleActionYAML("web", "shop", 2, 5)
+	if err != nil {
+		t.Fatalf("GenerateScaleActionYAML() returned %v", err)
+	}
+	for _, expected := range []string{"apiVersion: " + ActionRecordAPIVersion, "kind: ActionRecord", "action: scale", "newReplicas: 5", "namespace: shop", actionNamespaceLabel + ": shop"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("scale action record missing %q:\n%s", expected, content)
+		}
+	}
+	// Records are cluster-scoped, so only the target carries a namespace
+	if strings.Contains(content, "\n  namespace:") {
+		t.Errorf("scale action record has a metadata namespace:\n%s", content)
+	}
+}
+
+func TestRecordActionStoresActionRecord(t *testing.T) {
+	s := newActionRecordTestServer()
+	ctx := WithActionContext(context.Background(), ActionContext{SessionID: "chat-42"})
+	s.recordAction(ctx, "scale_deployment", corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "shop", Name: "web", UID: "uid-web"}, ReasonScaled, "Scaled from 1 to 3 replicas")
+	s.recordAction(context.Background(), "cordon_node", corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: "worker-1"}, ReasonCordoned, "Node cordoned")
+
+	records, err := s.dynamicClient.Resource(actionRecordGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(records.Items) != 2 {
+		t.Fatalf("action records = %v, %v, expected 2", records, err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop"}
+	text := resultText(mustCall(t, s.listActionRecordsHandler, request))
+	for _, expected := range []string{"scale", "shop/Deployment/web", "tool scale_deployment, session chat-42", "Scaled from 1 to 3 replicas", "Showing 1 of 1"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("list_action_records output missing %q:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "worker-1") {
+		t.Errorf("list_action_records namespace=shop listed the node action:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"action": "cordon"}
+	if text := resultText(mustCall(t, s.listActionRecordsHandler, request)); !strings.Contains(text, "Node/worker-1") || strings.Contains(text, "web") {
+		t.Errorf("list_action_records action=cordon:\n%s", text)
+	}
+	request.Params.Arguments = map[string]interface{}{"target": "bad name!"}
+	if text := resultText(mustCall(t, s.listActionRecordsHandler, request)); !strings.Contains(text, "❌ Invalid target") {
+		t.Errorf("list_action_records with an invalid target = %q", text)
+	}
+}
+
+func TestInstallActionRecordCRD(t *testing.T) {
+	s := newActionRecordTestServer()
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"dry_run": "true"}
+	if text := resultText(mustCall(t, s.installActionRecordCRDHandler, request)); !strings.Contains(text, "name: "+actionRecordCRDName) || !strings.Contains(text, "scope: Cluster") {
+		t.Errorf("dry run output missing the CRD:\n%s", text)
+	}
+	if crds, _ := s.dynamicClient.Resource(crdGVR).List(context.Background(), metav1.ListOptions{}); len(crds.Items) != 0 {
+		t.Errorf("dry run installed %d CRDs", len(crds.Items))
+	}
+
+	request.Params.Arguments = map[string]interface{}{}
+	for _, expected := range []string{"installed", "updated"} {
+		if text := resultText(mustCall(t, s.installActionRecordCRDHandler, request)); !strings.Contains(text, actionRecordCRDName+" "+expected) {
+			t.Errorf("install_action_record_crd = %q, expected %s", text, expected)
+		}
+	}
+	crd, err := s.dynamicClient.Resource(crdGVR).Get(context.Background(), actionRecordCRDName, metav1.GetOptions{})
+	if err != nil || crd.GetName() != actionRecordCRDName {
+		t.Errorf("installed CRD = %v, %v", crd, err)
+	}
+}
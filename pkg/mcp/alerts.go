@@ -0,0 +1,367 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	alertmanagerService = "alertmanager-main"
+	defaultAlertsSince  = time.Hour
+	maxAlertsSince      = 24 * time.Hour
+	// resolvedAlertsStep is the resolution at which ALERTS is read back to
+	// find alerts that stopped firing
+	resolvedAlertsStep = time.Minute
+	// diagnoseAlertsTimeout bounds the alert lookup appended to a diagnosis
+	diagnoseAlertsTimeout = 5 * time.Second
+	// heartbeatAlert always fires so the alerting pipeline can be watched
+	heartbeatAlert = "Watchdog"
+)
+
+// alertmanagerAlert is an alert of the Alertmanager v2 API
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Status      struct {
+		State       string   `json:"state"` // active, suppressed or unprocessed
+		SilencedBy  []string `json:"silencedBy"`
+		InhibitedBy []string `json:"inhibitedBy"`
+	} `json:"status"`
+}
+
+// discoverAlertmanagerEndpoint finds Alertmanager the way queries find the
+// Thanos querier: its tenancy port for a namespace, else its route or web port
+func (s *Server) discoverAlertmanagerEndpoint(ctx context.Context, namespace string) (metricsEndpoint, error) {
+	config := s.monitoringConfig()
+	return s.discoverMonitoringEndpoint(ctx, alertmanagerService, namespace, config.AlertmanagerTenancyURL, config.AlertmanagerURL)
+}
+
+// activeAlerts returns the alerts Alertmanager holds, firing ones and, when
+// asked for, silenced and inhibited ones
+func (s *Server) activeAlerts(ctx context.Context, endpoint metricsEndpoint, namespace string, includeSilenced bool) ([]diagnostics.Alert, error) {
+	params := url.Values{
+		"active":    {"true"},
+		"silenced":  {strconv.FormatBool(includeSilenced)},
+		"inhibited": {strconv.FormatBool(includeSilenced)},
+	}
+	if namespace != "" && !endpoint.Tenancy {
+		params.Set("filter", fmt.Sprintf("namespace=%q", namespace))
+	}
+	body, status, err := s.monitoringGet(ctx, endpoint, "/api/v2/alerts", params, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var raw []alertmanagerAlert
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, monitoringStatusError("Alertmanager", status, body, err)
+	}
+
+	alerts := make([]diagnostics.Alert, 0, len(raw))
+	for _, a := range raw {
+		name := a.Labels["alertname"]
+		if name == heartbeatAlert {
+			continue
+		}
+		state := diagnostics.AlertFiring
+		switch {
+		case len(a.Status.SilencedBy) > 0:
+			state = diagnostics.AlertSilenced
+		case len(a.Status.InhibitedBy) > 0:
+			state = diagnostics.AlertInhibited
+		}
+		alerts = append(alerts, diagnostics.Alert{
+			Name:        name,
+			State:       state,
+			Severity:    a.Labels["severity"],
+			Labels:      a.Labels,
+			Annotations: a.Annotations,
+			StartsAt:    a.StartsAt.UTC(),
+		})
+	}
+	sortAlerts(alerts)
+	return alerts, nil
+}
+
+// resolvedAlerts reads back the ALERTS series of a window and returns each
+// run of firing that ended before the window did
+func resolvedAlerts(series []diagnostics.MetricSeries, end time.Time, step time.Duration) []diagnostics.Alert {
+	var alerts []diagnostics.Alert
+	for _, s := range series {
+		name := s.Metric["alertname"]
+		if name == heartbeatAlert || len(s.Values) == 0 {
+			continue
+		}
+		labels := make(map[string]string, len(s.Metric))
+		for key, value := range s.Metric {
+			if key != "__name__" && key != "alertstate" {
+				labels[key] = value
+			}
+		}
+
+		// A gap of more than two steps separates one firing from the next
+		first := s.Values[0].Time
+		for i, point := range s.Values {
+			last := i == len(s.Values)-1
+			if !last && s.Values[i+1].Time.Sub(point.Time) <= 2*step {
+				continue
+			}
+			if !last || end.Sub(point.Time) > 2*step {
+				alerts = append(alerts, diagnostics.Alert{
+					Name:     name,
+					State:    diagnostics.AlertResolved,
+					Severity: labels["severity"],
+					Labels:   labels,
+					StartsAt: first.UTC(),
+					EndsAt:   point.Time.UTC(),
+				})
+			}
+			if !last {
+				first = s.Values[i+1].Time
+			}
+		}
+	}
+	sortAlerts(alerts)
+	return alerts
+}
+
+// sortAlerts orders alerts by start, then name
+func sortAlerts(alerts []diagnostics.Alert) {
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if !alerts[i].StartsAt.Equal(alerts[j].StartsAt) {
+			return alerts[i].StartsAt.Before(alerts[j].StartsAt)
+		}
+		return alerts[i].Name < alerts[j].Name
+	})
+}
+
+// recentlyResolvedAlerts finds the alerts that fired during the window and
+// no longer do, from the ALERTS series in the Thanos querier
+func (s *Server) recentlyResolvedAlerts(ctx context.Context, namespace string, since time.Duration, now time.Time) ([]diagnostics.Alert, error) {
+	endpoint, err := s.discoverMetricsEndpoint(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	query := `ALERTS{alertstate="firing"}`
+	if namespace != "" && !endpoint.Tenancy {
+		query = fmt.Sprintf(`ALERTS{alertstate="firing", namespace=%q}`, namespace)
+	}
+	series, err := s.queryMetricsRange(ctx, endpoint, query, namespace, now.Add(-since), now, resolvedAlertsStep)
+	if err != nil {
+		return nil, err
+	}
+	return resolvedAlerts(series, now, resolvedAlertsStep), nil
+}
+
+// alertResource renders where an alert points, e.g. shop/web-1
+func alertResource(labels map[string]string) string {
+	var parts []string
+	for _, label := range []string{"namespace", "pod", "deployment", "statefulset", "daemonset", "job_name", "service", "node"} {
+		if value := labels[label]; value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) == 0 {
+		return "cluster"
+	}
+	return strings.Join(parts, "/")
+}
+
+// formatAlert renders one alert line
+func formatAlert(ctx context.Context, alert diagnostics.Alert) string {
+	line := fmt.Sprintf("• [%s] %s on %s", valueOrNone(alert.Severity), alert.Name, alertResource(alert.Labels))
+	if alert.State == diagnostics.AlertResolved {
+		line += fmt.Sprintf(", fired %s to %s", formatTime(ctx, alert.StartsAt), formatTime(ctx, alert.EndsAt))
+	} else {
+		line += fmt.Sprintf(", since %s", formatTime(ctx, alert.StartsAt))
+	}
+	if summary := alert.Summary(); summary != "" {
+		line += "\n  " + summary
+	}
+	return line + "\n"
+}
+
+func (s *Server) initAlertTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("collect_alerts",
+			mcp.WithDescription("Collect the alerts firing in Alertmanager and those that fired and resolved recently, save them as a diagnostics artifact and pick out the alerts about a resource being diagnosed, e.g. KubePodCrashLooping for a pod; saved alerts join the analyze_logs and analyze_must_gather timeline"),
+			mcp.WithString("namespace", mcp.Description("Only alerts in this namespace, read through the tenancy proxy (recommended; required without cluster-monitoring-view)")),
+			mcp.WithString("resource_type", mcp.Description("Type of the resource being diagnosed: pod, deployment, statefulset, daemonset, replicaset, job, service or node")),
+			mcp.WithString("resource_name", mcp.Description("Name of the resource being diagnosed")),
+			mcp.WithString("since", mcp.Description("How far back to look for resolved alerts (default 1h, at most 24h)")),
+			mcp.WithBoolean("include_silenced", mcp.Description("Also collect silenced and inhibited alerts (default false)")),
+			mcp.WithString("output_dir", mcp.Description("Directory for alerts.json, e.g. a log collection's directory so analysis finds both")),
+			mcp.WithTitleAnnotation("Monitoring: Collect Alerts"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.collectAlertsHandler)},
+	}
+}
+
+func (s *Server) collectAlertsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	target := diagnostics.AlertTarget{
+		Namespace: namespace,
+		Type:      strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "resource_type", ""))),
+		Name:      strings.TrimSpace(mcp.ParseString(request, "resource_name", "")),
+	}
+	if target.Name != "" && target.Type == "" {
+		return mcp.NewToolResultText("❌ resource_type is required with resource_name"), nil
+	}
+	since := defaultAlertsSince
+	if text := mcp.ParseString(request, "since", ""); text != "" {
+		parsed, err := time.ParseDuration(text)
+		if err != nil || parsed <= 0 || parsed > maxAlertsSince {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ invalid since '%s': expected a duration up to %s, e.g. 2h", text, maxAlertsSince)), nil
+		}
+		since = parsed
+	}
+	includeSilenced := mcp.ParseBoolean(request, "include_silenced", false)
+
+	endpoint, err := s.discoverAlertmanagerEndpoint(ctx, namespace)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Cannot find Alertmanager: %v", err)), nil
+	}
+	active, err := s.activeAlerts(ctx, endpoint, namespace, includeSilenced)
+	if err != nil {
+		response := fmt.Sprintf("Failed to read alerts from %s", endpoint.URL)
+		if !endpoint.Tenancy {
+			response += " (cluster-wide alerts need the monitoring-rules-view or cluster-monitoring-view role; pass namespace to read through the tenancy proxy)"
+		}
+		return toolError(ctx, response, err), nil
+	}
+
+	now := time.Now()
+	snapshot := &diagnostics.AlertsSnapshot{
+		Source:      endpoint.URL,
+		Namespace:   namespace,
+		Since:       since.String(),
+		CollectedAt: now.UTC(),
+		Alerts:      active,
+	}
+	resolved, err := s.recentlyResolvedAlerts(ctx, namespace, since, now)
+	if err != nil {
+		snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("resolved alerts unavailable: %v", err))
+	}
+	snapshot.Alerts = append(snapshot.Alerts, resolved...)
+
+	response := "🚨 Alerts\n"
+	response += "=========\n\n"
+	if namespace != "" {
+		response += fmt.Sprintf("Scope: namespace %s\n", namespace)
+	} else {
+		response += "Scope: cluster-wide\n"
+	}
+	response += fmt.Sprintf("Alertmanager: %s (%s)\n", endpoint.URL, endpoint.Source)
+
+	byState := make(map[string][]diagnostics.Alert)
+	for _, alert := range snapshot.Alerts {
+		byState[alert.State] = append(byState[alert.State], alert)
+	}
+	sections := []struct {
+		title  string
+		alerts []diagnostics.Alert
+	}{
+		{"🔥 Firing", byState[diagnostics.AlertFiring]},
+		{"🔕 Silenced or inhibited", append(byState[diagnostics.AlertSilenced], byState[diagnostics.AlertInhibited]...)},
+		{fmt.Sprintf("✅ Resolved in the last %s", since), byState[diagnostics.AlertResolved]},
+	}
+	for _, section := range sections {
+		if len(section.alerts) == 0 {
+			continue
+		}
+		response += fmt.Sprintf("\n%s (%d):\n", section.title, len(section.alerts))
+		for _, alert := range section.alerts {
+			response += formatAlert(ctx, alert)
+		}
+	}
+	if len(snapshot.Alerts) == 0 {
+		response += fmt.Sprintf("\n✅ No alerts firing or resolved in the last %s\n", since)
+	}
+	for _, warning := range snapshot.Warnings {
+		response += fmt.Sprintf("\n⚠️ %s\n", warning)
+	}
+
+	if target.Name != "" {
+		matches := diagnostics.MatchAlerts(snapshot.Alerts, target)
+		response += fmt.Sprintf("\n🎯 Alerts about %s/%s (%d):\n", target.Type, target.Name, len(matches))
+		if len(matches) == 0 {
+			response += "• None; the resource has not alerted in this window\n"
+		}
+		for _, match := range matches {
+			response += fmt.Sprintf("• %s (%s): %s\n", match.Alert.Name, match.Alert.State, match.Reason)
+		}
+	}
+
+	opts := &diagnostics.CollectionOptions{
+		Namespace: namespace,
+		OutputDir: mcp.ParseString(request, "output_dir", ""),
+	}
+	result, err := s.diagnosticCollector.SaveAlerts(snapshot, opts)
+	if err != nil {
+		recordToolError(ctx, err)
+		response += fmt.Sprintf("\n❌ Failed to save the alerts: %v\n", err)
+		return mcp.NewToolResultText(response), nil
+	}
+	response += fmt.Sprintf("\n📁 Location: %s\n", result.FilePath)
+	if id := result.Metadata["artifact_id"]; id != "" {
+		response += fmt.Sprintf("🆔 Artifact: %s\n", id)
+	}
+	response += "\n💡 Alerts saved in a log collection or must-gather directory, or one level below it, are placed on the analyze_logs and analyze_must_gather timeline. Pass that directory as output_dir to store them with it."
+	return mcp.NewToolResultText(response), nil
+}
+
+// CollectAlertsHandler is a public wrapper for collectAlertsHandler
+func (s *Server) CollectAlertsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.collectAlertsHandler(ctx, request)
+}
+
+// appendAlerts adds the firing alerts about a diagnosed resource to its
+// report. Alerts are extra context, so a monitoring stack that cannot be
+// reached leaves the report as it is.
+func (s *Server) appendAlerts(ctx context.Context, result *mcp.CallToolResult, resourceType, name, namespace string) *mcp.CallToolResult {
+	if result == nil || result.IsError || len(result.Content) == 0 {
+		return result
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseAlertsTimeout)
+	defer cancel()
+	endpoint, err := s.discoverAlertmanagerEndpoint(ctx, namespace)
+	if err != nil {
+		return result
+	}
+	alerts, err := s.activeAlerts(ctx, endpoint, namespace, false)
+	if err != nil {
+		return result
+	}
+	matches := diagnostics.MatchAlerts(alerts, diagnostics.AlertTarget{Namespace: namespace, Type: resourceType, Name: name})
+	if len(matches) == 0 {
+		return result
+	}
+	section := fmt.Sprintf("\n\n🚨 Firing alerts about %s/%s:\n", strings.ToLower(resourceType), name)
+	if name == "" {
+		section = fmt.Sprintf("\n\n🚨 Firing alerts in namespace %s:\n", namespace)
+	}
+	for _, match := range matches {
+		section += formatAlert(ctx, match.Alert)
+	}
+	text.Text = strings.TrimRight(text.Text, "\n") + strings.TrimRight(section, "\n")
+	result.Content[0] = *text
+	return result
+}
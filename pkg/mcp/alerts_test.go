@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func TestCollectAlerts(t *testing.T) {
+	monitoring := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("namespace") != "shop" {
+			t.Errorf("monitoring got %s without the namespace", r.URL)
+		}
+		switch r.URL.Path {
+		case "/api/v2/alerts":
+			if r.URL.Query().Get("silenced") != "false" {
+				t.Errorf("Alertmanager got %s, expected silenced alerts left out", r.URL)
+			}
+			w.Write([]byte(`[
+				{"labels":{"alertname":"KubePodCrashLooping","namespace":"shop","pod":"web-7d9f-abcde","severity":"warning"},
+				 "annotations":{"summary":"Pod is crash looping."},"startsAt":"2024-05-01T10:02:00Z","status":{"state":"active"}},
+				{"labels":{"alertname":"Watchdog","severity":"none"},"startsAt":"2024-05-01T00:00:00Z","status":{"state":"active"}},
+				{"labels":{"alertname":"KubeDeploymentReplicasMismatch","namespace":"shop","deployment":"api","severity":"warning"},
+				 "startsAt":"2024-05-01T10:05:00Z","status":{"state":"active"}}]`))
+		case "/api/v1/query_range":
+			end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+			values := ""
+			for i := 40; i > 30; i-- {
+				values += fmt.Sprintf(`,[%d,"1"]`, end-int64(60*i))
+			}
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"__name__":"ALERTS","alertname":"KubeContainerWaiting","alertstate":"firing","namespace":"shop","pod":"web-7d9f-abcde","severity":"warning"},"values":[` + values[1:] + `]}]}}`))
+		default:
+			t.Errorf("monitoring got %s", r.URL)
+		}
+	}))
+	defer monitoring.Close()
+
+	s := &Server{
+		config:              &Config{Monitoring: &MonitoringConfig{TenancyURL: monitoring.URL, AlertmanagerTenancyURL: monitoring.URL}},
+		diagnosticCollector: diagnostics.NewDiagnosticCollector(logrus.New(), t.TempDir()),
+	}
+	outputDir := t.TempDir()
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"namespace": "shop", "resource_type": "deployment", "resource_name": "web-7d9f", "output_dir": outputDir,
+	}
+	text := resultText(mustCall(t, s.CollectAlertsHandler, request))
+	for _, want := range []string{
+		"🔥 Firing (2):\n• [warning] KubePodCrashLooping on shop/web-7d9f-abcde, since 2024-05-01 10:02:00 UTC\n  Pod is crash looping.",
+		"✅ Resolved in the last 1h0m0s (1):\n• [warning] KubeContainerWaiting on shop/web-7d9f-abcde",
+		"🎯 Alerts about deployment/web-7d9f (2):\n• KubePodCrashLooping (firing): pod web-7d9f-abcde belongs to the deployment\n• KubeContainerWaiting (resolved)",
+		"Artifact: ",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("collect_alerts output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Watchdog") {
+		t.Errorf("collect_alerts listed the Watchdog heartbeat:\n%s", text)
+	}
+
+	snapshot, err := diagnostics.ReadAlerts(outputDir + "/" + diagnostics.AlertsFile)
+	if err != nil || len(snapshot.Alerts) != 3 || snapshot.Source != monitoring.URL {
+		t.Errorf("saved alerts = %+v, %v", snapshot, err)
+	}
+}
+
+func TestResolvedAlerts(t *testing.T) {
+	end := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	series := func(minutes ...int) diagnostics.MetricSeries {
+		s := diagnostics.MetricSeries{Metric: map[string]string{"__name__": "ALERTS", "alertname": "KubePodNotReady", "alertstate": "firing"}}
+		for _, m := range minutes {
+			s.Values = append(s.Values, diagnostics.MetricPoint{Time: end.Add(time.Duration(m-60) * time.Minute), Value: 1})
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		series   diagnostics.MetricSeries
+		resolved []string
+	}{
+		{"resolved, then firing again", series(50, 51, 52, 59, 60), []string{"10:50-10:52"}},
+		{"fired twice", series(10, 11, 12, 30, 31), []string{"10:10-10:12", "10:30-10:31"}},
+		{"firing at the end", series(57, 58, 59, 60), nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, alert := range resolvedAlerts([]diagnostics.MetricSeries{tt.series}, end, time.Minute) {
+			if _, ok := alert.Labels["alertstate"]; ok || alert.State != diagnostics.AlertResolved {
+				t.Errorf("%s: resolved alert %+v", tt.name, alert)
+			}
+			got = append(got, alert.StartsAt.Format("15:04")+"-"+alert.EndsAt.Format("15:04"))
+		}
+		if strings.Join(got, ",") != strings.Join(tt.resolved, ",") {
+			t.Errorf("%s: resolvedAlerts() = %v, expected %v", tt.name, got, tt.resolved)
+		}
+	}
+}
+
+func TestDiagnoseAppendsAlertsQuietly(t *testing.T) {
+	s := &Server{config: &Config{}}
+	report := mcp.NewToolResultText("🔍 Pod Diagnostic Report")
+	if text := resultText(s.appendAlerts(context.Background(), report, "pod", "web-1", "shop")); text != "🔍 Pod Diagnostic Report" {
+		t.Errorf("appendAlerts() without monitoring = %q", text)
+	}
+}
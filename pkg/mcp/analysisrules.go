@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// analysisRuleDir returns the configured rule directory, or "" when custom
+// analysis rules are not enabled
+func (s *Server) analysisRuleDir() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.AnalysisRuleDir
+}
+
+// loadAnalysisRules reads the rule packs found at start
+func (s *Server) loadAnalysisRules() {
+	if s.analysisRuleDir() == "" {
+		return
+	}
+	load, err := s.analysisEngine.LoadRuleDir(s.analysisRuleDir())
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to load analysis rules")
+		return
+	}
+	for _, failure := range load.Failed {
+		logrus.Warnf("Skipping analysis rule pack %s", failure)
+	}
+	if len(load.Loaded) > 0 {
+		logrus.Infof("Loaded analysis rule packs: %s", strings.Join(load.Loaded, ", "))
+	}
+}
+
+func (s *Server) initAnalysisRules() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_analysis_rules",
+			mcp.WithDescription("List the custom log analysis rules loaded from the rule directory, which analyze_logs and analyze_must_gather match in addition to the built-in patterns"),
+			mcp.WithString("rule_pack", mcp.Description("Only list the rules of this pack")),
+			mcp.WithTitleAnnotation("Diagnostics: List Analysis Rules"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listAnalysisRulesHandler)},
+		{Tool: mcp.NewTool("reload_analysis_rules",
+			mcp.WithDescription("Re-read the analysis rule directory without restarting: new and changed rule packs apply to the next analysis and deleted ones stop applying"),
+			mcp.WithTitleAnnotation("Diagnostics: Reload Analysis Rules"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.reloadAnalysisRulesHandler)},
+		{Tool: mcp.NewTool("list_known_issues",
+			mcp.WithDescription("List the known issue signatures: log and event fingerprints mapped to known bugs or KCS articles, which analyze_logs and analyze_must_gather attach to the issues they find; rule packs add or pin entries under known_issues"),
+			mcp.WithString("search", mcp.Description("Only list signatures whose ID, title or pattern contains this text")),
+			mcp.WithTitleAnnotation("Diagnostics: List Known Issues"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listKnownIssuesHandler)},
+	}
+}
+
+func (s *Server) listAnalysisRulesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	only := strings.TrimSpace(mcp.ParseString(request, "rule_pack", ""))
+
+	result := "📐 Analysis Rules\n"
+	result += "=================\n\n"
+	result += fmt.Sprintf("Rule directory: %s\n", valueOrNone(s.analysisRuleDir()))
+	packs := s.analysisEngine.RulePacks()
+	if len(packs) == 0 {
+		result += "\nNo rule packs are loaded; only the built-in patterns apply\n"
+		result += "\n💡 Put rule packs (*.yaml) in the rule directory (analysis.rule-dir) and call reload_analysis_rules"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	shown := 0
+	for _, pack := range packs {
+		if only != "" && pack.Name != only {
+			continue
+		}
+		shown++
+		result += fmt.Sprintf("\n📦 %s - %s", pack.Name, pack.Source)
+		if pack.Description != "" {
+			result += fmt.Sprintf("\n   %s", pack.Description)
+		}
+		result += "\n"
+		for _, rule := range pack.Rules {
+			applies := rule.Applies
+			if applies == "" {
+				applies = "logs"
+			}
+			result += fmt.Sprintf("• [%s] %s (%s, %s): /%s/\n", rule.Severity, rule.Name, rule.Category, applies, rule.Pattern)
+			if rule.Unless != "" {
+				result += fmt.Sprintf("   unless /%s/\n", rule.Unless)
+			}
+			if rule.Files != "" {
+				result += fmt.Sprintf("   only in %s\n", rule.Files)
+			}
+			result += fmt.Sprintf("   💡 %s\n", rule.Resolution)
+			if rule.Docs != "" {
+				result += fmt.Sprintf("   📖 %s\n", rule.Docs)
+			}
+		}
+	}
+	if shown == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Rule pack %s is not loaded; see list_analysis_rules", only)), nil
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) reloadAnalysisRulesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.analysisRuleDir() == "" {
+		return mcp.NewToolResultText("❌ No analysis rule directory is configured (analysis.rule-dir)"), nil
+	}
+	load, err := s.analysisEngine.LoadRuleDir(s.analysisRuleDir())
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to reload analysis rules: %v", err)), nil
+	}
+
+	result := "🔄 Analysis Rule Reload\n"
+	result += "=======================\n\n"
+	result += fmt.Sprintf("Rule directory: %s\n\n", s.analysisRuleDir())
+	if len(load.Loaded) > 0 {
+		result += fmt.Sprintf("✅ Loaded: %s\n", strings.Join(load.Loaded, ", "))
+	} else {
+		result += "No rule packs found; only the built-in patterns apply\n"
+	}
+	for _, failure := range load.Failed {
+		result += fmt.Sprintf("❌ Failed: %s\n", failure)
+	}
+	result += "\n💡 The rules and known issues apply to the next analyze_logs or analyze_must_gather run"
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) listKnownIssuesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	search := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "search", "")))
+
+	result := "📚 Known Issue Signatures\n"
+	result += "=========================\n"
+	shown := 0
+	for _, known := range s.analysisEngine.KnownIssues() {
+		if search != "" && !strings.Contains(strings.ToLower(known.ID+" "+known.Title+" "+known.Pattern), search) {
+			continue
+		}
+		shown++
+		source := known.Source
+		if source == "" {
+			source = diagnostics.KnownIssueLogs
+		}
+		result += fmt.Sprintf("\n• %s (%s, %s): /%s/\n", known.Title, source, known.Origin, known.Pattern)
+		result += fmt.Sprintf("   📚 %s - %s\n", diagnostics.FormatReference(known.Reference()), known.URL)
+		if known.Resolution != "" {
+			result += fmt.Sprintf("   💡 %s\n", known.Resolution)
+		}
+	}
+	if shown == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No known issue signatures match %q", search)), nil
+	}
+	result += "\n💡 Pin a KCS article or bug, or add a signature, under known_issues in a rule pack and call reload_analysis_rules"
+	return mcp.NewToolResultText(result), nil
+}
+
+// formatReferences renders the known bugs and KCS articles of an issue
+func formatReferences(refs []diagnostics.Reference) string {
+	var lines string
+	for _, ref := range refs {
+		lines += fmt.Sprintf("   📚 Known issue: %s (%s) %s\n", ref.Title, diagnostics.FormatReference(ref), ref.URL)
+	}
+	return lines
+}
+
+// ListAnalysisRulesHandler is a public wrapper for listAnalysisRulesHandler
+func (s *Server) ListAnalysisRulesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listAnalysisRulesHandler(ctx, request)
+}
+
+// ReloadAnalysisRulesHandler is a public wrapper for reloadAnalysisRulesHandler
+func (s *Server) ReloadAnalysisRulesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.reloadAnalysisRulesHandler(ctx, request)
+}
+
+// ListKnownIssuesHandler is a public wrapper for listKnownIssuesHandler
+func (s *Server) ListKnownIssuesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listKnownIssuesHandler(ctx, request)
+}
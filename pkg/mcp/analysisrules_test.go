@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func TestReloadAnalysisRules(t *testing.T) {
+	dir := t.TempDir()
+	s := &Server{config: &Config{AnalysisRuleDir: dir}, analysisEngine: diagnostics.NewAnalysisEngine(logrus.New())}
+
+	text := resultText(mustCall(t, s.listAnalysisRulesHandler, mcp.CallToolRequest{}))
+	if !strings.Contains(text, "No rule packs are loaded") {
+		t.Errorf("unexpected list before loading:\n%s", text)
+	}
+
+	pack := "rules:\n- name: Ledger Lock Timeout\n  pattern: lock wait timeout\n  severity: critical\n  category: database\n  resolution: Check long transactions\n  docs: https://runbooks.example.com/ledger\n"
+	if err := os.WriteFile(filepath.Join(dir, "payments.yaml"), []byte(pack), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("name: broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	text = resultText(mustCall(t, s.reloadAnalysisRulesHandler, mcp.CallToolRequest{}))
+	for _, want := range []string{"✅ Loaded: payments (1 rules)", "❌ Failed: broken.yaml: no rules defined"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("reload output missing %q:\n%s", want, text)
+		}
+	}
+
+	text = resultText(mustCall(t, s.listAnalysisRulesHandler, ruleRequest(map[string]interface{}{"rule_pack": "payments"})))
+	for _, want := range []string{"📦 payments - " + filepath.Join(dir, "payments.yaml"), "• [critical] Ledger Lock Timeout (database, logs): /lock wait timeout/", "📖 https://runbooks.example.com/ledger"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("list output missing %q:\n%s", want, text)
+		}
+	}
+	if text := resultText(mustCall(t, s.listAnalysisRulesHandler, ruleRequest(map[string]interface{}{"rule_pack": "billing"}))); !strings.Contains(text, "Rule pack billing is not loaded") {
+		t.Errorf("unexpected list of a missing pack:\n%s", text)
+	}
+}
+
+func ruleRequest(arguments map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = arguments
+	return request
+}
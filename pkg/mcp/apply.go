@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldManager identifies changes made by this server in managedFields
+const fieldManager = "openshift-mcp"
+
+const (
+	// diffContextLines is how many unchanged lines are shown around a change
+	diffContextLines = 3
+
+	// maxDiffCells bounds the LCS table; larger diffs are shown as a full replace
+	maxDiffCells = 4 * 1024 * 1024
+)
+
+// decodeObject parses a single YAML or JSON manifest
+func decodeObject(content string) (*unstructured.Unstructured, error) {
+	data, err := yaml.YAMLToJSON([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML/JSON: %v", err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid resource: %v", err)
+	}
+	if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+		return nil, fmt.Errorf("resource must set apiVersion and kind")
+	}
+	return obj, nil
+}
+
+// objectYAML renders an object for diffing, without the fields that change
+// on every write and with Secret values redacted
+func objectYAML(obj *unstructured.Unstructured) []string {
+	if obj == nil {
+		return nil
+	}
+
+	clean := obj.DeepCopy()
+	unstructured.RemoveNestedField(clean.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(clean.Object, "metadata", "resourceVersion")
+	redactSecretObject(clean)
+
+	data, err := yaml.Marshal(clean.Object)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// diffLines returns a unified-style diff of two line slices, showing a few
+// unchanged lines of context around each change
+func diffLines(before, after []string) []string {
+	// Trim the common prefix and suffix before running the LCS
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+
+	a := before[prefix : len(before)-suffix]
+	b := after[prefix : len(after)-suffix]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	type op struct {
+		kind byte
+		line string
+	}
+	ops := make([]op, 0, len(before)+len(b))
+	for _, line := range before[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, op{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(a) || j < len(b) {
+			switch {
+			case i < len(a) && j < len(b) && a[i] == b[j]:
+				ops = append(ops, op{' ', a[i]})
+				i++
+				j++
+			case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+				ops = append(ops, op{'+', b[j]})
+				j++
+			default:
+				ops = append(ops, op{'-', a[i]})
+				i++
+			}
+		}
+	}
+
+	for _, line := range before[len(before)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+
+	// Keep changed lines and the context around them
+	keep := make([]bool, len(ops))
+	for idx, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		for k := idx - diffContextLines; k <= idx+diffContextLines; k++ {
+			if k >= 0 && k < len(ops) {
+				keep[k] = true
+			}
+		}
+	}
+
+	var diff []string
+	skipped := false
+	for idx, o := range ops {
+		if !keep[idx] {
+			skipped = true
+			continue
+		}
+		if skipped && len(diff) > 0 {
+			diff = append(diff, "...")
+		}
+		skipped = false
+		diff = append(diff, string(o.kind)+" "+o.line)
+	}
+	return diff
+}
+
+func (s *Server) updateResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	resourceName := mcp.ParseString(request, "resource_name", "")
+	namespace := mcp.ParseString(request, "namespace", "")
+	yamlContent := mcp.ParseString(request, "yaml", "")
+	force := parseBoolString(mcp.ParseString(request, "force", "false"))
+
+	if yamlContent == "" {
+		return mcp.NewToolResultText("❌ YAML content is required"), nil
+	}
+
+	obj, err := decodeObject(yamlContent)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	// The manifest and the parameters must describe the same object
+	switch {
+	case obj.GetName() == "":
+		obj.SetName(resourceName)
+	case resourceName != "" && obj.GetName() != resourceName:
+		return mcp.NewToolResultText(fmt.Sprintf("❌ YAML name '%s' does not match resource_name '%s'", obj.GetName(), resourceName)), nil
+	}
+	if obj.GetName() == "" {
+		return mcp.NewToolResultText("❌ Resource name is required"), nil
+	}
+
+	gvk := obj.GroupVersionKind()
+	if s.restMapper == nil {
+		return mcp.NewToolResultText("❌ Resource discovery is not available"), nil
+	}
+	mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		recordToolError(ctx, err)
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Unknown resource kind %s: %v", gvk.String(), err)), nil
+	}
+	if resourceType != "" {
+		gvr, _, err := s.resolveResource(resourceType)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+		}
+		if gvr.GroupResource() != mapping.Resource.GroupResource() {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ YAML kind %s does not match resource_type '%s'", gvk.Kind, resourceType)), nil
+		}
+	}
+
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced {
+		switch {
+		case obj.GetNamespace() == "":
+			if namespace == "" {
+				namespace = "default"
+			}
+			obj.SetNamespace(namespace)
+		case namespace != "" && obj.GetNamespace() != namespace:
+			return mcp.NewToolResultText(fmt.Sprintf("❌ YAML namespace '%s' does not match namespace '%s'", obj.GetNamespace(), namespace)), nil
+		}
+	} else {
+		obj.SetNamespace("")
+	}
+	client := s.resourceInterface(mapping.Resource, namespaced, obj.GetNamespace())
+
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return toolError(ctx, fmt.Sprintf("Failed to get %s %s", mapping.Resource.Resource, obj.GetName()), err), nil
+		}
+		live = nil
+	}
+
+	// Server-side apply owns only the fields present in the manifest
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	applied, err := client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: force})
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			recordToolError(ctx, err)
+			result := fmt.Sprintf("❌ Apply conflicts with fields owned by another manager: %v\n", err)
+			result += "💡 Re-run with force=true to take ownership of the conflicting fields"
+			return mcp.NewToolResultError(result), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to apply %s %s", mapping.Resource.Resource, obj.GetName()), err), nil
+	}
+
+	logrus.Infof("Applied %s %s (namespace %q)", mapping.Resource.GroupResource().String(), obj.GetName(), obj.GetNamespace())
+	s.recordAction(ctx, "update_resource", objectReference(applied.GetAPIVersion(), applied.GetKind(), applied), ReasonApplied,
+		fmt.Sprintf("Applied %s with server-side apply", applied.GetKind()))
+
+	result := "🔄 Updating Resource\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("Resource: %s\n", mapping.Resource.GroupResource().String())
+	result += fmt.Sprintf("Name: %s\n", obj.GetName())
+	if namespaced {
+		result += fmt.Sprintf("Namespace: %s\n", obj.GetNamespace())
+	}
+	result += fmt.Sprintf("Field Manager: %s\n\n", fieldManager)
+
+	if live == nil {
+		result += "🆕 Resource did not exist and was created\n"
+	}
+
+	diff := diffLines(objectYAML(live), objectYAML(applied))
+	if len(diff) == 0 {
+		result += "✅ No changes - the live object already matches the applied configuration"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	result += "📝 Changes (live → applied):\n"
+	result += fmt.Sprintf("```diff\n%s\n```\n\n", strings.Join(diff, "\n"))
+	result += "✅ Resource updated via server-side apply"
+
+	return mcp.NewToolResultText(result), nil
+}
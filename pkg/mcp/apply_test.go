@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDecodeObject(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  string
+		valid bool
+	}{
+		{"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n", "Deployment", true},
+		{`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cfg"}}`, "ConfigMap", true},
+		{"metadata:\n  name: web\n", "", false},
+		{"kind: [unterminated", "", false},
+	}
+
+	for _, tt := range tests {
+		obj, err := decodeObject(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("decodeObject(%q) error = %v, expected valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		if err == nil && obj.GetKind() != tt.kind {
+			t.Errorf("decodeObject(%q).Kind = %s, expected %s", tt.input, obj.GetKind(), tt.kind)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   []string
+		after    []string
+		expected []string
+	}{
+		{"identical", []string{"a", "b"}, []string{"a", "b"}, nil},
+		{"changed", []string{"a", "b", "c"}, []string{"a", "x", "c"}, []string{"  a", "- b", "+ x", "  c"}},
+		{"created", nil, []string{"a", "b"}, []string{"+ a", "+ b"}},
+		{"context", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, []string{"1", "2", "3", "4", "5", "6", "7", "8", "X"},
+			[]string{"  6", "  7", "  8", "- 9", "+ X"}},
+		{"separate hunks", []string{"a", "1", "2", "3", "4", "5", "6", "7", "b"}, []string{"A", "1", "2", "3", "4", "5", "6", "7", "B"},
+			[]string{"- a", "+ A", "  1", "  2", "  3", "...", "  5", "  6", "  7", "- b", "+ B"}},
+	}
+
+	for _, tt := range tests {
+		diff := diffLines(tt.before, tt.after)
+		if !reflect.DeepEqual(diff, tt.expected) {
+			t.Errorf("%s: diffLines() = %q, expected %q", tt.name, diff, tt.expected)
+		}
+	}
+}
+
+func TestUpdateResourceServerSideApply(t *testing.T) {
+	live := newUnstructured("apps/v1", "Deployment", "shop", "web")
+	unstructured.SetNestedField(live.Object, int64(2), "spec", "replicas")
+	s := newDynamicTestServer(live)
+
+	var patchType types.PatchType
+	s.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		patchType = patch.GetPatchType()
+
+		applied := &unstructured.Unstructured{}
+		if err := applied.UnmarshalJSON(patch.GetPatch()); err != nil {
+			return true, nil, err
+		}
+		applied.SetUID(live.GetUID())
+		return true, applied, nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "update_resource"
+	request.Params.Arguments = map[string]interface{}{
+		"resource_type": "deployment",
+		"resource_name": "web",
+		"namespace":     "shop",
+		"yaml":          "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 5\n",
+	}
+
+	result, err := s.updateResourceHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("updateResourceHandler returned error: %v", err)
+	}
+	text := resultText(result)
+	if result.IsError {
+		t.Fatalf("updateResourceHandler = %q, expected success", text)
+	}
+
+	if patchType != types.ApplyPatchType {
+		t.Errorf("patch type = %s, expected %s", patchType, types.ApplyPatchType)
+	}
+	if !strings.Contains(text, "-   replicas: 2") || !strings.Contains(text, "+   replicas: 5") {
+		t.Errorf("result = %q, expected replicas diff", text)
+	}
+}
+
+// newSecretUnstructured returns a Secret holding a password, as the dynamic
+// client sees it
+func newSecretUnstructured(namespace, name, password string) *unstructured.Unstructured {
+	secret := newUnstructured("v1", "Secret", namespace, name)
+	unstructured.SetNestedStringMap(secret.Object, map[string]string{"password": base64.StdEncoding.EncodeToString([]byte(password))}, "data")
+	secret.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: `{"data":{"password":"` + base64.StdEncoding.EncodeToString([]byte(password)) + `"}}`})
+	return secret
+}
+
+// assertSecretRedacted fails when output shows a password, plain or base64
+func assertSecretRedacted(t *testing.T, text string, passwords ...string) {
+	t.Helper()
+	for _, password := range passwords {
+		if strings.Contains(text, password) || strings.Contains(text, base64.StdEncoding.EncodeToString([]byte(password))) {
+			t.Errorf("output shows the Secret value %q:\n%s", password, text)
+		}
+	}
+	if !strings.Contains(text, "<redacted") {
+		t.Errorf("output = %q, expected redacted Secret values", text)
+	}
+}
+
+func TestObjectYAMLRedactsSecrets(t *testing.T) {
+	before := objectYAML(newSecretUnstructured("shop", "db", "hunter2-old"))
+	after := newSecretUnstructured("shop", "db", "hunter2-new")
+	unstructured.SetNestedStringMap(after.Object, map[string]string{"token": "s3cr3t-token"}, "stringData")
+
+	text := strings.Join(diffLines(before, objectYAML(after)), "\n")
+	assertSecretRedacted(t, text, "hunter2-old", "hunter2-new", "s3cr3t-token")
+	// A changed value still shows as a change
+	if !strings.Contains(text, "-   password: '<redacted, 11 bytes") || !strings.Contains(text, "+   password: '<redacted, 11 bytes") {
+		t.Errorf("diff = %q, expected the password change", text)
+	}
+
+	// Other kinds keep their data
+	configMap := newUnstructured("v1", "ConfigMap", "shop", "settings")
+	unstructured.SetNestedStringMap(configMap.Object, map[string]string{"mode": "fast"}, "data")
+	if text := strings.Join(objectYAML(configMap), "\n"); !strings.Contains(text, "mode: fast") {
+		t.Errorf("objectYAML(ConfigMap) = %q", text)
+	}
+}
+
+func TestUpdateResourceRedactsSecrets(t *testing.T) {
+	s := newDynamicTestServer(newSecretUnstructured("shop", "db", "hunter2-old"))
+	s.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		applied := &unstructured.Unstructured{}
+		if err := applied.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch()); err != nil {
+			return true, nil, err
+		}
+		return true, applied, nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "update_resource"
+	request.Params.Arguments = map[string]interface{}{
+		"resource_type": "secret",
+		"resource_name": "db",
+		"namespace":     "shop",
+		"yaml":          "apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\nstringData:\n  password: hunter2-new\n",
+	}
+	result, err := s.updateResourceHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("updateResourceHandler returned error: %v", err)
+	}
+	assertSecretRedacted(t, resultText(result), "hunter2-old", "hunter2-new")
+}
+
+func TestUpdateResourceRejectsMismatches(t *testing.T) {
+	s := newDynamicTestServer(newUnstructured("apps/v1", "Deployment", "shop", "web"))
+
+	tests := []map[string]interface{}{
+		{"resource_type": "deployment", "resource_name": "api", "namespace": "shop",
+			"yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"},
+		{"resource_type": "namespace", "resource_name": "web", "namespace": "shop",
+			"yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"},
+		{"resource_type": "deployment", "resource_name": "web", "namespace": "other",
+			"yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: shop\n"},
+		{"resource_type": "deployment", "resource_name": "web", "yaml": "metadata:\n  name: web\n"},
+	}
+
+	for _, args := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := s.updateResourceHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("updateResourceHandler(%v) returned error: %v", args, err)
+		}
+		if text := resultText(result); !strings.Contains(text, "❌") {
+			t.Errorf("updateResourceHandler(%v) = %q, expected error", args, text)
+		}
+	}
+}
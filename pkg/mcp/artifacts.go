@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// DiagnosticsConfig sets where collections are written and how long they
+// are kept. Retention only removes artifacts under BaseDir.
+type DiagnosticsConfig struct {
+	BaseDir         string `json:"base_dir"`         // default /tmp/diagnostics
+	MaxDiskUsage    string `json:"max_disk_usage"`   // quantity, e.g. 20Gi; empty is unlimited
+	TTL             string `json:"ttl"`              // e.g. 168h; empty keeps artifacts until deleted
+	CleanupInterval string `json:"cleanup_interval"` // how often retention runs, default 1h
+}
+
+const (
+	artifactCleanupJobName         = "diagnostics-retention"
+	defaultArtifactCleanupInterval = time.Hour
+)
+
+// diagnosticsBaseDir returns the configured collection directory
+func (s *Server) diagnosticsBaseDir() string {
+	if s.config != nil && s.config.Diagnostics != nil && s.config.Diagnostics.BaseDir != "" {
+		return s.config.Diagnostics.BaseDir
+	}
+	return diagnostics.DefaultDiagnosticsDir
+}
+
+// retentionPolicy parses the configured limits
+func retentionPolicy(config *DiagnosticsConfig) (diagnostics.RetentionPolicy, error) {
+	var policy diagnostics.RetentionPolicy
+	if config == nil {
+		return policy, nil
+	}
+	if config.MaxDiskUsage != "" {
+		quantity, err := resource.ParseQuantity(config.MaxDiskUsage)
+		if err != nil || quantity.Sign() <= 0 {
+			return policy, fmt.Errorf("invalid max disk usage %q", config.MaxDiskUsage)
+		}
+		policy.MaxBytes = quantity.Value()
+	}
+	if config.TTL != "" {
+		ttl, err := time.ParseDuration(config.TTL)
+		if err != nil || ttl <= 0 {
+			return policy, fmt.Errorf("invalid ttl %q", config.TTL)
+		}
+		policy.TTL = ttl
+	}
+	return policy, nil
+}
+
+// initArtifactRetention applies the retention policy now and on a schedule
+func (s *Server) initArtifactRetention(config *DiagnosticsConfig) {
+	policy, err := retentionPolicy(config)
+	if err != nil {
+		logrus.WithError(err).Warn("Diagnostics retention disabled")
+		return
+	}
+	if policy.MaxBytes == 0 && policy.TTL == 0 {
+		return
+	}
+	s.diagnosticCollector.SetRetentionPolicy(policy)
+
+	interval := defaultArtifactCleanupInterval
+	if config.CleanupInterval != "" {
+		if interval, err = time.ParseDuration(config.CleanupInterval); err != nil || interval <= 0 {
+			logrus.Warnf("Invalid diagnostics cleanup interval %q, using %s", config.CleanupInterval, defaultArtifactCleanupInterval)
+			interval = defaultArtifactCleanupInterval
+		}
+	}
+	s.scheduler.AddLeaderJob(artifactCleanupJobName, interval, func(ctx context.Context) error {
+		_, err := s.diagnosticCollector.Artifacts().Cleanup()
+		return err
+	})
+	logrus.Infof("Diagnostics retention every %s: %s", interval, describeRetention(policy))
+}
+
+// describeRetention summarizes a retention policy
+func describeRetention(policy diagnostics.RetentionPolicy) string {
+	var limits []string
+	if policy.TTL > 0 {
+		limits = append(limits, "kept for "+policy.TTL.String())
+	}
+	if policy.MaxBytes > 0 {
+		limits = append(limits, "at most "+formatMB(policy.MaxBytes))
+	}
+	if len(limits) == 0 {
+		return "kept until deleted"
+	}
+	return strings.Join(limits, ", ")
+}
+
+func (s *Server) initDiagnostics() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_diagnostics",
+			mcp.WithDescription("List collected diagnostics artifacts (must-gathers, captures, sosreports, logs and bundles) with their IDs, sizes and ages, the disk they use and the retention policy. Pass id for one artifact's details"),
+			mcp.WithString("id", mcp.Description("Artifact ID to show in detail")),
+			mcp.WithString("type", mcp.Description("Only list this collection type: must-gather, tcpdump, sosreport, logs, metrics, alerts, ovn, ingress or storage")),
+			mcp.WithTitleAnnotation("Diagnostics: List Artifacts"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.listDiagnosticsHandler)},
+	}
+}
+
+func (s *Server) listDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.diagnosticCollector == nil {
+		return mcp.NewToolResultText("❌ Diagnostic collection is not available"), nil
+	}
+	store := s.diagnosticCollector.Artifacts()
+
+	if id := strings.TrimSpace(mcp.ParseString(request, "id", "")); id != "" {
+		artifact, ok := store.Get(id)
+		if !ok {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Diagnostics artifact %s not found; list_diagnostics shows the registered IDs", id)), nil
+		}
+		return mcp.NewToolResultText(formatArtifact(ctx, artifact, store.BaseDir())), nil
+	}
+
+	kind := strings.TrimSpace(mcp.ParseString(request, "type", ""))
+	artifacts := store.List(kind)
+	result := "🗂️  Diagnostics Artifacts\n"
+	result += "========================\n\n"
+	result += fmt.Sprintf("📁 Base directory: %s\n", store.BaseDir())
+	result += fmt.Sprintf("💾 Disk usage: %s\n", formatMB(store.Usage()))
+	result += fmt.Sprintf("♻️  Retention: %s\n\n", describeRetention(store.Policy()))
+	if len(artifacts) == 0 {
+		if kind != "" {
+			result += fmt.Sprintf("No %s artifacts\n", kind)
+		} else {
+			result += "No artifacts collected yet\n"
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	result += fmt.Sprintf("📋 %d artifact(s), newest first:\n", len(artifacts))
+	for _, artifact := range artifacts {
+		result += fmt.Sprintf("• %s - %s, %s, %s ago\n  %s\n", artifact.ID, artifact.Type, formatMB(artifact.Size),
+			time.Since(artifact.CreatedAt).Round(time.Second), artifact.Path)
+	}
+	result += "\n💡 Pass id for an artifact's details"
+	return mcp.NewToolResultText(result), nil
+}
+
+// formatArtifact shows one artifact with its metadata
+func formatArtifact(ctx context.Context, artifact diagnostics.Artifact, baseDir string) string {
+	result := fmt.Sprintf("🗂️  Diagnostics Artifact %s\n", artifact.ID)
+	result += strings.Repeat("=", len([]rune(result))-1) + "\n\n"
+	result += fmt.Sprintf("Type: %s\n", artifact.Type)
+	result += fmt.Sprintf("📁 Path: %s\n", artifact.Path)
+	result += fmt.Sprintf("📦 Size: %s\n", formatMB(artifact.Size))
+	result += fmt.Sprintf("🕒 Collected: %s\n", formatTimeWithAge(ctx, artifact.CreatedAt))
+	if !artifact.Managed(baseDir) {
+		result += "⚠️  Outside the base directory, so retention never removes it\n"
+	}
+	if artifact.Summary != "" {
+		result += fmt.Sprintf("📊 %s\n", artifact.Summary)
+	}
+	if len(artifact.Metadata) > 0 {
+		result += "\nMetadata:\n"
+		for _, key := range sortedKeys(artifact.Metadata) {
+			result += fmt.Sprintf("  %s: %s\n", key, artifact.Metadata[key])
+		}
+	}
+	switch artifact.Type {
+	case "must-gather":
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_must_gather must_gather_path=%s", artifact.Path)
+	case "tcpdump":
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_tcpdump pcap_path=%s", artifact.Path)
+	case "logs":
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_logs log_path=%s", artifact.Path)
+	case "ovn":
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_ovn_diagnostics path=%s", artifact.Path)
+	case "ingress":
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_ingress_diagnostics path=%s", artifact.Path)
+	case "storage":
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_storage_diagnostics path=%s", artifact.Path)
+	}
+	return result
+}
+
+// ListDiagnosticsHandler is a public wrapper for listDiagnosticsHandler
+func (s *Server) ListDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listDiagnosticsHandler(ctx, request)
+}
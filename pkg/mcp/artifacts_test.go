@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		config   *DiagnosticsConfig
+		expected diagnostics.RetentionPolicy
+		valid    bool
+	}{
+		{nil, diagnostics.RetentionPolicy{}, true},
+		{&DiagnosticsConfig{MaxDiskUsage: "2Gi", TTL: "168h"}, diagnostics.RetentionPolicy{MaxBytes: 2 << 30, TTL: 168 * time.Hour}, true},
+		{&DiagnosticsConfig{MaxDiskUsage: "500M"}, diagnostics.RetentionPolicy{MaxBytes: 500000000}, true},
+		{&DiagnosticsConfig{MaxDiskUsage: "lots"}, diagnostics.RetentionPolicy{}, false},
+		{&DiagnosticsConfig{TTL: "a week"}, diagnostics.RetentionPolicy{}, false},
+		{&DiagnosticsConfig{TTL: "-1h"}, diagnostics.RetentionPolicy{}, false},
+	}
+	for _, tt := range tests {
+		policy, err := retentionPolicy(tt.config)
+		if (err == nil) != tt.valid || (tt.valid && policy != tt.expected) {
+			t.Errorf("retentionPolicy(%+v) = %+v, %v, expected %+v", tt.config, policy, err, tt.expected)
+		}
+	}
+}
+
+func TestListDiagnostics(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	baseDir := t.TempDir()
+	collector := diagnostics.NewDiagnosticCollector(logger, baseDir)
+	collector.SetRetentionPolicy(diagnostics.RetentionPolicy{TTL: 24 * time.Hour})
+	s := &Server{config: &Config{}, diagnosticCollector: collector}
+
+	call := func(args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		return resultText(mustCall(t, s.listDiagnosticsHandler, request))
+	}
+	if text := call(nil); !strings.Contains(text, "No artifacts collected yet") || !strings.Contains(text, "♻️  Retention: kept for 24h0m0s") {
+		t.Errorf("list_diagnostics with nothing collected = %q", text)
+	}
+
+	path := filepath.Join(baseDir, "must-gather", "mg-1")
+	os.MkdirAll(path, 0755)
+	os.WriteFile(filepath.Join(path, "must-gather.log"), []byte("done"), 0644)
+	if _, err := collector.Artifacts().Register("mg-1", &diagnostics.CollectionResult{
+		Type: "must-gather", FilePath: path, Size: 2 * 1024 * 1024, Metadata: map[string]string{"job_id": "mg-1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	text := call(nil)
+	for _, want := range []string{"💾 Disk usage: 2.00 MB", "📋 1 artifact(s), newest first:", "• mg-1 - must-gather, 2.00 MB, "} {
+		if !strings.Contains(text, want) {
+			t.Errorf("list_diagnostics missing %q:\n%s", want, text)
+		}
+	}
+	if text := call(map[string]interface{}{"type": "tcpdump"}); !strings.Contains(text, "No tcpdump artifacts") {
+		t.Errorf("list_diagnostics type=tcpdump = %q", text)
+	}
+
+	text = call(map[string]interface{}{"id": "mg-1"})
+	for _, want := range []string{"Diagnostics Artifact mg-1", "📁 Path: " + path, "  job_id: mg-1", "💡 Analyze it with analyze_must_gather must_gather_path=" + path} {
+		if !strings.Contains(text, want) {
+			t.Errorf("list_diagnostics id=mg-1 missing %q:\n%s", want, text)
+		}
+	}
+	if text := call(map[string]interface{}{"id": "mg-2"}); !strings.HasPrefix(text, "❌ Diagnostics artifact mg-2 not found") {
+		t.Errorf("list_diagnostics id=mg-2 = %q", text)
+	}
+}
@@ -0,0 +1,755 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+const (
+	defaultAutoRemediationConfidence = 0.9
+	autoRemediationJobName           = "auto-remediation"
+
+	// defaultKillSwitchConfigMap holds the kill switch of auto-remediation
+	defaultKillSwitchConfigMap = "openshift-mcp/auto-remediation"
+
+	// maxRemediationApprovals bounds the approvals kept without a shared store
+	maxRemediationApprovals = 100
+	// sharedApprovalTTL is how long an approval stays in a shared store
+	sharedApprovalTTL = 7 * 24 * time.Hour
+)
+
+// Approval states
+const (
+	approvalPending  = "pending"
+	approvalApproved = "approved"
+	approvalRejected = "rejected"
+	approvalResolved = "resolved" // the issue was gone when approved
+)
+
+// AutoRemediationConfig opts into remediations without a person in the
+// loop: a detected issue whose fix is confident enough and passes the
+// policy checks is remediated automatically, every other one waits for
+// approval through approve_remediation
+type AutoRemediationConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Interval schedules sweeps, e.g. 5m; empty only sweeps when
+	// auto_remediate is called
+	Interval string `json:"interval"`
+
+	// Namespaces are the only namespaces remediated automatically
+	Namespaces []string `json:"namespaces"`
+
+	// MinConfidence is the confidence a fix needs to run automatically
+	// (default 0.9); Recipes limits automatic fixes to these recipes
+	MinConfidence float64  `json:"min_confidence"`
+	Recipes       []string `json:"recipes"`
+
+	// MaxMemoryLimit caps the memory limit the memory-limit recipe may set
+	// automatically, e.g. 4Gi
+	MaxMemoryLimit string `json:"max_memory_limit"`
+
+	// TimeoutSeconds bounds the verification of each fix (default 180)
+	TimeoutSeconds int `json:"timeout_seconds"`
+
+	// KillSwitchConfigMap is the "namespace/name" of the ConfigMap whose
+	// kill-switch key stops all automatic remediation
+	// (default openshift-mcp/auto-remediation)
+	KillSwitchConfigMap string `json:"kill_switch_configmap"`
+}
+
+func (c *AutoRemediationConfig) minConfidence() float64 {
+	if c.MinConfidence > 0 {
+		return c.MinConfidence
+	}
+	return defaultAutoRemediationConfidence
+}
+
+func (c *AutoRemediationConfig) timeout() time.Duration {
+	if c.TimeoutSeconds > 0 {
+		return time.Duration(min(c.TimeoutSeconds, maxRemediationTimeout)) * time.Second
+	}
+	return defaultRemediationTimeout * time.Second
+}
+
+// autoRemediationConfig returns the configured mode, disabled when unset
+func (s *Server) autoRemediationConfig() *AutoRemediationConfig {
+	if s.config == nil || s.config.AutoRemediation == nil {
+		return &AutoRemediationConfig{}
+	}
+	return s.config.AutoRemediation
+}
+
+// remediationApproval is a detected issue whose fix waits for a person
+type remediationApproval struct {
+	ID         string
+	Namespace  string
+	Deployment string
+	Recipe     string
+	Container  string
+	Problem    string
+	Fix        string
+	Confidence float64
+	Reasons    []string // why the fix did not run automatically
+	Created    time.Time
+	Status     string
+	DecidedBy  string
+	Outcome    string
+}
+
+// issue identifies the detected issue, so that later sweeps do not queue it again
+func (a remediationApproval) issue() string {
+	return strings.Join([]string{a.Namespace, a.Deployment, a.Recipe, a.Container}, "/")
+}
+
+// remediationApprovals keeps the fixes awaiting approval. With a shared
+// store they live there, so any replica can approve them.
+type remediationApprovals struct {
+	mu        sync.Mutex
+	seq       int
+	approvals map[string]*remediationApproval
+	order     []string
+	deciding  map[string]bool // approvals claimed by a running decision
+	store     store.Store
+}
+
+func remediationApprovalKey(id string) string {
+	return "remediation-approval/" + id
+}
+
+// add queues an approval unless the same issue is already pending, returning
+// the ID of the pending approval and whether it is new
+func (r *remediationApprovals) add(approval remediationApproval) (string, bool) {
+	for _, existing := range r.list() {
+		if existing.Status == approvalPending && existing.issue() == approval.issue() {
+			return existing.ID, false
+		}
+	}
+	approval.Status, approval.Created = approvalPending, time.Now()
+	if store.Shared(r.store) {
+		approval.ID = store.NewID("rem-")
+		r.save(approval)
+		return approval.ID, true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.approvals == nil {
+		r.approvals = make(map[string]*remediationApproval)
+	}
+	r.seq++
+	approval.ID = fmt.Sprintf("rem-%d", r.seq)
+	r.approvals[approval.ID] = &approval
+	r.order = append(r.order, approval.ID)
+	if len(r.order) > maxRemediationApprovals {
+		delete(r.approvals, r.order[0])
+		r.order = r.order[1:]
+	}
+	return approval.ID, true
+}
+
+func (r *remediationApprovals) get(id string) (remediationApproval, bool) {
+	if store.Shared(r.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		var approval remediationApproval
+		ok, err := store.GetJSON(ctx, r.store, remediationApprovalKey(id), &approval)
+		if err != nil {
+			logrus.WithError(err).Warnf("Failed to read remediation approval %s", id)
+		}
+		return approval, ok
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	approval, ok := r.approvals[id]
+	if !ok {
+		return remediationApproval{}, false
+	}
+	return *approval, true
+}
+
+func (r *remediationApprovals) save(approval remediationApproval) {
+	if store.Shared(r.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		if err := store.SetJSON(ctx, r.store, remediationApprovalKey(approval.ID), approval, sharedApprovalTTL); err != nil {
+			logrus.WithError(err).Warnf("Failed to store remediation approval %s", approval.ID)
+		}
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.approvals[approval.ID]; ok {
+		r.approvals[approval.ID] = &approval
+	}
+}
+
+// claim takes an approval for a decision so two approve calls, on this or
+// another replica, cannot both act on it; ok is false while another
+// decision holds it. The claim lasts until release, or ttl with a shared store.
+func (r *remediationApprovals) claim(id string, ttl time.Duration) (func(), bool, error) {
+	if store.Shared(r.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		return r.store.Lock(ctx, remediationApprovalKey(id), ttl)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deciding[id] {
+		return nil, false, nil
+	}
+	if r.deciding == nil {
+		r.deciding = make(map[string]bool)
+	}
+	r.deciding[id] = true
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.deciding, id)
+	}, true, nil
+}
+
+// list returns the approvals, oldest first
+func (r *remediationApprovals) list() []remediationApproval {
+	var approvals []remediationApproval
+	if store.Shared(r.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		keys, err := r.store.Keys(ctx, remediationApprovalKey(""))
+		if err != nil {
+			logrus.WithError(err).Warn("Failed to list remediation approvals")
+		}
+		for _, key := range keys {
+			var approval remediationApproval
+			if ok, _ := store.GetJSON(ctx, r.store, key, &approval); ok {
+				approvals = append(approvals, approval)
+			}
+		}
+	} else {
+		r.mu.Lock()
+		for _, id := range r.order {
+			approvals = append(approvals, *r.approvals[id])
+		}
+		r.mu.Unlock()
+	}
+	sort.SliceStable(approvals, func(i, j int) bool { return approvals[i].Created.Before(approvals[j].Created) })
+	return approvals
+}
+
+// killSwitchConfigMap returns the namespace and name of the kill switch ConfigMap
+func (s *Server) killSwitchConfigMap() (string, string) {
+	ref := defaultKillSwitchConfigMap
+	if config := s.autoRemediationConfig(); config.KillSwitchConfigMap != "" {
+		ref = config.KillSwitchConfigMap
+	}
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		return "openshift-mcp", ref
+	}
+	return namespace, name
+}
+
+// killSwitchEngaged reports whether automatic remediation is stopped and
+// why. A kill switch that cannot be read counts as engaged.
+func (s *Server) killSwitchEngaged(ctx context.Context) (bool, string) {
+	namespace, name := s.killSwitchConfigMap()
+	configMap, err := s.k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, ""
+	}
+	if err != nil {
+		return true, fmt.Sprintf("the kill switch ConfigMap %s/%s could not be read: %v", namespace, name, err)
+	}
+	if !parseBoolString(strings.TrimSpace(configMap.Data["kill-switch"])) {
+		return false, ""
+	}
+	reason := "kill switch engaged"
+	if by := configMap.Data["engaged-by"]; by != "" {
+		reason += " by " + by
+	}
+	if why := configMap.Data["reason"]; why != "" {
+		reason += ": " + why
+	}
+	return true, reason
+}
+
+// planMemoryLimit returns the memory limit a plan sets on its container
+func planMemoryLimit(plan *remediationPlan) (resource.Quantity, bool) {
+	if plan.Template == nil {
+		return resource.Quantity{}, false
+	}
+	for _, container := range plan.Template.Spec.Containers {
+		if container.Name == plan.Container {
+			limit, ok := container.Resources.Limits[corev1.ResourceMemory]
+			return limit, ok
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+// autoRemediationBlockers lists the policy checks that keep a fix from
+// running automatically; a fix without blockers may run without approval
+func (s *Server) autoRemediationBlockers(ctx context.Context, config *AutoRemediationConfig, namespace string, plan *remediationPlan) []string {
+	var blockers []string
+	if !config.Enabled {
+		blockers = append(blockers, "auto-remediation is disabled")
+	}
+	if engaged, reason := s.killSwitchEngaged(ctx); engaged {
+		blockers = append(blockers, reason)
+	}
+	if !slices.Contains(config.Namespaces, namespace) {
+		blockers = append(blockers, fmt.Sprintf("namespace %s is not allowed for auto-remediation", namespace))
+	}
+	if len(config.Recipes) > 0 && !slices.Contains(config.Recipes, plan.Recipe) {
+		blockers = append(blockers, fmt.Sprintf("recipe %s is not allowed for auto-remediation", plan.Recipe))
+	}
+	if plan.Confidence < config.minConfidence() {
+		blockers = append(blockers, fmt.Sprintf("confidence %.0f%% is below the %.0f%% threshold", plan.Confidence*100, config.minConfidence()*100))
+	}
+	if freeze := s.ChangeFreezeFor(ctx, namespace); freeze != nil {
+		blockers = append(blockers, freeze.Describe())
+	}
+	if plan.Recipe == recipeMemoryLimit && config.MaxMemoryLimit != "" {
+		maximum, err := resource.ParseQuantity(config.MaxMemoryLimit)
+		if err != nil {
+			blockers = append(blockers, fmt.Sprintf("invalid max_memory_limit %q", config.MaxMemoryLimit))
+		} else if limit, ok := planMemoryLimit(plan); ok && limit.Cmp(maximum) > 0 {
+			blockers = append(blockers, fmt.Sprintf("memory limit %s exceeds the %s cap", limit.String(), maximum.String()))
+		}
+	}
+	return blockers
+}
+
+// autoRemediationAction is what a sweep did about one detected issue
+type autoRemediationAction struct {
+	Namespace  string
+	Deployment string
+	Plan       remediationPlan
+	Blockers   []string
+	ApprovalID string
+	Outcome    remediationOutcome // empty unless the fix ran
+	Report     string
+}
+
+// runAutoRemediation sweeps the deployments of namespaces: each detected
+// issue whose fix passes the checks is remediated, at most one per
+// deployment and sweep since a fix changes the template the others were
+// planned on, and every other fixable issue is queued for approval
+func (s *Server) runAutoRemediation(ctx context.Context, config *AutoRemediationConfig, namespaces []string, dryRun bool) ([]autoRemediationAction, error) {
+	if s.k8sClient == nil {
+		return nil, fmt.Errorf("Kubernetes client not available")
+	}
+	var actions []autoRemediationAction
+	for _, namespace := range namespaces {
+		deployments, err := s.k8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return actions, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+		}
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			if deployment.Spec.Paused {
+				continue
+			}
+			pods, err := s.deploymentPods(ctx, deployment)
+			if err != nil {
+				return actions, err
+			}
+			plans, err := s.planRemediations(ctx, deployment, pods, "", "")
+			if err != nil {
+				return actions, err
+			}
+
+			applied := false
+			for j := range plans {
+				plan := &plans[j]
+				if plan.Blocked != "" {
+					continue
+				}
+				action := autoRemediationAction{Namespace: namespace, Deployment: deployment.Name, Plan: *plan}
+				action.Blockers = s.autoRemediationBlockers(ctx, config, namespace, plan)
+				switch {
+				case len(action.Blockers) > 0 && !dryRun:
+					id, added := s.remediations.add(remediationApproval{
+						Namespace:  namespace,
+						Deployment: deployment.Name,
+						Recipe:     plan.Recipe,
+						Container:  plan.Container,
+						Problem:    plan.Problem,
+						Fix:        plan.Fix,
+						Confidence: plan.Confidence,
+						Reasons:    action.Blockers,
+					})
+					action.ApprovalID = id
+					if added {
+						logrus.Infof("Queued remediation %s of deployment %s/%s for approval: %s", id, namespace, deployment.Name, strings.Join(action.Blockers, "; "))
+					}
+				case len(action.Blockers) > 0, dryRun, applied:
+					// Reported only; issues after an applied fix are planned again next sweep
+				default:
+					report, outcome, err := s.applyRemediation(ctx, autoRemediationJobName, deployment, podTemplateHashes(pods), plan, config.timeout())
+					if err != nil {
+						report, outcome = fmt.Sprintf("❌ %v", err), remediationFailed
+					}
+					action.Report, action.Outcome = report, outcome
+					applied = true
+					logrus.Warnf("Auto-remediated deployment %s/%s with recipe %s: %s", namespace, deployment.Name, plan.Recipe, outcome)
+				}
+				actions = append(actions, action)
+			}
+		}
+	}
+	return actions, nil
+}
+
+func (s *Server) initAutoRemediationSchedule(config *AutoRemediationConfig) {
+	if config == nil || !config.Enabled || config.Interval == "" {
+		return
+	}
+	interval, err := time.ParseDuration(config.Interval)
+	if err != nil || interval <= 0 {
+		logrus.Warnf("Invalid auto-remediation interval %q, scheduled auto-remediation disabled", config.Interval)
+		return
+	}
+
+	s.scheduler.AddLeaderJob(autoRemediationJobName, interval, func(ctx context.Context) error {
+		_, err := s.runAutoRemediation(ctx, config, config.Namespaces, false)
+		return err
+	})
+	logrus.Infof("Scheduled auto-remediation of %s every %s", strings.Join(config.Namespaces, ", "), interval)
+}
+
+func (s *Server) initAutoRemediation() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("auto_remediate",
+			mcp.WithDescription("Run an auto-remediation sweep: detected issues whose fix meets the confidence threshold and policy checks (allowed namespace and recipe, no change freeze, kill switch off) are remediated with rollout verification and automatic rollback; every other fixable issue is queued for approve_remediation"),
+			mcp.WithString("namespace", mcp.Description("Only sweep this namespace (default: the namespaces allowed for auto-remediation)")),
+			mcp.WithString("dry_run", mcp.Description("Only report what would be remediated or queued (true/false)")),
+			mcp.WithTitleAnnotation("Remediate: Auto"),
+			mcp.WithDestructiveHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.autoRemediateHandler)},
+		{Tool: mcp.NewTool("list_remediation_approvals",
+			mcp.WithDescription("List the remediations waiting for approval, with why each did not run automatically, and the auto-remediation mode and kill switch state"),
+			mcp.WithString("status", mcp.Description("pending (default), approved, rejected, resolved or all")),
+			mcp.WithTitleAnnotation("Remediate: List Approvals"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listRemediationApprovalsHandler)},
+		{Tool: mcp.NewTool("approve_remediation",
+			mcp.WithDescription("Approve a queued remediation, which applies the fix with rollout verification and automatic rollback, or reject it"),
+			mcp.WithString("approval_id", mcp.Description("Approval ID from list_remediation_approvals"), mcp.Required()),
+			mcp.WithString("approved_by", mcp.Description("Who approved or rejected the remediation"), mcp.Required()),
+			mcp.WithString("reject", mcp.Description("Reject instead of approving (true/false)")),
+			mcp.WithString("timeout_seconds", mcp.Description(fmt.Sprintf("Seconds to wait for the rollout before rolling back (default %d, max %d)", defaultRemediationTimeout, maxRemediationTimeout))),
+			mcp.WithTitleAnnotation("Remediate: Approve"),
+			mcp.WithDestructiveHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.approveRemediationHandler)},
+		{Tool: mcp.NewTool("auto_remediation_kill_switch",
+			mcp.WithDescription("Engage or release the global kill switch that stops all automatic remediation on every replica; approvals still work"),
+			mcp.WithString("action", mcp.Description("engage or release"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the kill switch is engaged")),
+			mcp.WithString("requested_by", mcp.Description("Who engages or releases it")),
+			mcp.WithTitleAnnotation("Remediate: Kill Switch"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.autoRemediationKillSwitchHandler)},
+	}
+}
+
+func (s *Server) autoRemediateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	config := s.autoRemediationConfig()
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+	if !config.Enabled && !dryRun {
+		return mcp.NewToolResultText("❌ Auto-remediation is disabled; enable it with auto_remediation.enabled in the server config, or use dry_run=true to preview a sweep"), nil
+	}
+	namespaces := config.Namespaces
+	if namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", "")); namespace != "" {
+		namespaces = []string{namespace}
+	}
+	if len(namespaces) == 0 {
+		return mcp.NewToolResultText("❌ No namespace is allowed for auto-remediation; list them in auto_remediation.namespaces or pass namespace"), nil
+	}
+
+	actions, err := s.runAutoRemediation(ctx, config, namespaces, dryRun)
+	if err != nil {
+		recordToolError(ctx, err)
+	}
+
+	result := "🤖 Auto-Remediation Sweep\n"
+	result += "=========================\n\n"
+	result += fmt.Sprintf("Namespaces: %s\n", strings.Join(namespaces, ", "))
+	result += fmt.Sprintf("Confidence threshold: %.0f%%\n", config.minConfidence()*100)
+	if engaged, reason := s.killSwitchEngaged(ctx); engaged {
+		result += fmt.Sprintf("🛑 Kill switch: %s\n", reason)
+	}
+	if err != nil {
+		result += fmt.Sprintf("\n❌ Sweep stopped: %v\n", err)
+	}
+	if len(actions) == 0 {
+		result += "\n✅ No fixable issue detected"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	for _, action := range actions {
+		result += fmt.Sprintf("\n• %s/%s [%s] %s\n", action.Namespace, action.Deployment, action.Plan.Recipe, action.Plan.Problem)
+		result += fmt.Sprintf("   🔧 Fix (%.0f%% confidence): %s\n", action.Plan.Confidence*100, action.Plan.Fix)
+		switch {
+		case action.Outcome != "":
+			result += fmt.Sprintf("   🤖 Remediated automatically (%s)\n", action.Outcome)
+			for _, line := range strings.Split(action.Report, "\n") {
+				result += "      " + line + "\n"
+			}
+		case action.ApprovalID != "":
+			result += fmt.Sprintf("   📝 Awaiting approval %s: %s\n", action.ApprovalID, strings.Join(action.Blockers, "; "))
+		case len(action.Blockers) > 0:
+			result += fmt.Sprintf("   📝 Would need approval: %s\n", strings.Join(action.Blockers, "; "))
+		case dryRun:
+			result += "   🧪 Would be remediated automatically\n"
+		default:
+			result += "   ⏭️  Deferred to the next sweep, after the fix applied to this deployment\n"
+		}
+	}
+	if dryRun {
+		result += "\n🧪 Dry run: nothing was changed or queued"
+	} else {
+		result += "\n💡 approve_remediation approval_id=<id> approved_by=<name> applies a queued fix"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) listRemediationApprovalsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	status := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "status", approvalPending)))
+	config := s.autoRemediationConfig()
+
+	result := "📝 Remediation Approvals\n"
+	result += "========================\n\n"
+	mode := "disabled"
+	if config.Enabled {
+		mode = fmt.Sprintf("enabled for %s at %.0f%% confidence", valueOrNone(strings.Join(config.Namespaces, ", ")), config.minConfidence()*100)
+		if config.Interval != "" {
+			mode += ", sweeping every " + config.Interval
+		}
+	}
+	result += fmt.Sprintf("Auto-remediation: %s\n", mode)
+	if s.k8sClient != nil {
+		if engaged, reason := s.killSwitchEngaged(ctx); engaged {
+			result += fmt.Sprintf("🛑 Kill switch: %s\n", reason)
+		} else {
+			result += "Kill switch: released\n"
+		}
+	}
+
+	var shown []remediationApproval
+	for _, approval := range s.remediations.list() {
+		if status == "all" || approval.Status == status {
+			shown = append(shown, approval)
+		}
+	}
+	if len(shown) == 0 {
+		result += fmt.Sprintf("\n✅ No %s approvals", status)
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("\n%d approval(s):\n", len(shown))
+	for _, approval := range shown {
+		result += fmt.Sprintf("\n• %s [%s] %s/%s, queued %s\n", approval.ID, approval.Status, approval.Namespace, approval.Deployment,
+			formatTime(ctx, approval.Created))
+		result += fmt.Sprintf("   Issue: [%s] %s\n", approval.Recipe, approval.Problem)
+		result += fmt.Sprintf("   🔧 Fix (%.0f%% confidence): %s\n", approval.Confidence*100, approval.Fix)
+		result += fmt.Sprintf("   Not automatic: %s\n", strings.Join(approval.Reasons, "; "))
+		if approval.DecidedBy != "" {
+			result += fmt.Sprintf("   Decided by %s: %s\n", approval.DecidedBy, valueOrNone(approval.Outcome))
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) approveRemediationHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	approvalID := strings.TrimSpace(mcp.ParseString(request, "approval_id", ""))
+	approver := strings.TrimSpace(mcp.ParseString(request, "approved_by", ""))
+	if approvalID == "" || approver == "" {
+		return mcp.NewToolResultText("❌ approval_id and approved_by are required"), nil
+	}
+	timeoutValue := mcp.ParseString(request, "timeout_seconds", strconv.Itoa(defaultRemediationTimeout))
+	timeout, err := strconv.Atoi(timeoutValue)
+	if err != nil || timeout <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid timeout_seconds value: %s", timeoutValue)), nil
+	}
+	timeout = min(timeout, maxRemediationTimeout)
+
+	// Hold the approval until the decision is saved, so a second call
+	// cannot apply the same fix while the first waits for the rollout
+	release, claimed, err := s.remediations.claim(approvalID, time.Duration(timeout+60)*time.Second)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to claim approval %s", approvalID), err), nil
+	}
+	if !claimed {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Approval %s is being decided by another call", approvalID)), nil
+	}
+	defer release()
+	approval, ok := s.remediations.get(approvalID)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Approval %s not found; see list_remediation_approvals", approvalID)), nil
+	}
+	if approval.Status != approvalPending {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Approval %s is already %s", approvalID, approval.Status)), nil
+	}
+
+	result := "📝 Remediation Approval\n"
+	result += "=======================\n\n"
+	result += fmt.Sprintf("Approval: %s\n", approvalID)
+	result += fmt.Sprintf("Deployment: %s/%s\n", approval.Namespace, approval.Deployment)
+	result += fmt.Sprintf("Issue: [%s] %s\n", approval.Recipe, approval.Problem)
+
+	approval.DecidedBy = approver
+	if parseBoolString(mcp.ParseString(request, "reject", "false")) {
+		approval.Status, approval.Outcome = approvalRejected, "rejected"
+		s.remediations.save(approval)
+		result += fmt.Sprintf("🚫 Rejected by %s; the fix was not applied", approver)
+		return mcp.NewToolResultText(result), nil
+	}
+
+	// The call names no namespace, so the generic freeze check only covers the cluster
+	if freeze := s.ChangeFreezeFor(ctx, approval.Namespace); freeze != nil {
+		result += fmt.Sprintf("🧊 Not applied: %s\n", freeze.Describe())
+		result += "💡 Approve it again after the freeze"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	deployment, err := s.k8sClient.AppsV1().Deployments(approval.Namespace).Get(ctx, approval.Deployment, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get deployment %s", approval.Deployment), err), nil
+	}
+	pods, err := s.deploymentPods(ctx, deployment)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list the pods of deployment %s", approval.Deployment), err), nil
+	}
+	plans, err := s.planRemediations(ctx, deployment, pods, approval.Recipe, "")
+	if err != nil {
+		return toolError(ctx, "Failed to detect remediable issues", err), nil
+	}
+	var plan *remediationPlan
+	for i := range plans {
+		if plans[i].Container == approval.Container && plans[i].Blocked == "" {
+			plan = &plans[i]
+			break
+		}
+	}
+	if plan == nil {
+		approval.Status, approval.Outcome = approvalResolved, "issue no longer detected"
+		s.remediations.save(approval)
+		result += "✅ The issue is no longer detected; nothing was changed"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	result += fmt.Sprintf("🖊️  Approved by: %s\n\n", approver)
+	report, outcome, err := s.applyRemediation(ctx, "approve_remediation", deployment, podTemplateHashes(pods),
+		plan, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return toolError(ctx, "Failed to apply the remediation", err), nil
+	}
+	approval.Status, approval.Outcome = approvalApproved, string(outcome)
+	s.remediations.save(approval)
+	result += report
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) autoRemediationKillSwitchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	action := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "action", "")))
+	if action != "engage" && action != "release" {
+		return mcp.NewToolResultText("❌ action must be engage or release"), nil
+	}
+	reason := strings.TrimSpace(mcp.ParseString(request, "reason", ""))
+	requestedBy := strings.TrimSpace(mcp.ParseString(request, "requested_by", ""))
+	namespace, name := s.killSwitchConfigMap()
+
+	data := map[string]string{"kill-switch": "false"}
+	if action == "engage" {
+		data = map[string]string{
+			"kill-switch": "true",
+			"reason":      reason,
+			"engaged-by":  requestedBy,
+			"engaged-at":  time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	configMaps := s.k8sClient.CoreV1().ConfigMaps(namespace)
+	configMap, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: namespace,
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "openshift-mcp"},
+		}, Data: data}
+		configMap, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+		if err == nil {
+			s.recordAction(ctx, "auto_remediation_kill_switch", objectReference("v1", "ConfigMap", configMap), ReasonCreated,
+				fmt.Sprintf("Auto-remediation kill switch %sd", action))
+		}
+	case err == nil:
+		configMap.Data = data
+		configMap, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		if err == nil {
+			s.recordAction(ctx, "auto_remediation_kill_switch", objectReference("v1", "ConfigMap", configMap), ReasonPatched,
+				fmt.Sprintf("Auto-remediation kill switch %sd", action))
+		}
+	}
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to %s the kill switch in ConfigMap %s/%s", action, namespace, name), err), nil
+	}
+
+	result := "🛑 Auto-Remediation Kill Switch\n"
+	result += "===============================\n\n"
+	result += fmt.Sprintf("ConfigMap: %s/%s\n", namespace, name)
+	if action == "engage" {
+		logrus.Warnf("Auto-remediation kill switch engaged by %s: %s", valueOrNone(requestedBy), valueOrNone(reason))
+		result += "🛑 Engaged: no remediation runs automatically on any replica; detected issues are queued for approval"
+		if reason != "" {
+			result += fmt.Sprintf("\nReason: %s", reason)
+		}
+	} else {
+		logrus.Warnf("Auto-remediation kill switch released by %s", valueOrNone(requestedBy))
+		result += "✅ Released: fixes that pass the checks run automatically again"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// AutoRemediateHandler is a public wrapper for autoRemediateHandler
+func (s *Server) AutoRemediateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.autoRemediateHandler(ctx, request)
+}
+
+// ListRemediationApprovalsHandler is a public wrapper for listRemediationApprovalsHandler
+func (s *Server) ListRemediationApprovalsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listRemediationApprovalsHandler(ctx, request)
+}
+
+// ApproveRemediationHandler is a public wrapper for approveRemediationHandler
+func (s *Server) ApproveRemediationHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.approveRemediationHandler(ctx, request)
+}
+
+// AutoRemediationKillSwitchHandler is a public wrapper for autoRemediationKillSwitchHandler
+func (s *Server) AutoRemediationKillSwitchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.autoRemediationKillSwitchHandler(ctx, request)
+}
@@ -0,0 +1,211 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func killSwitch(engaged string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "auto-remediation", Namespace: "openshift-mcp"},
+		Data:       map[string]string{"kill-switch": engaged, "reason": "incident 42", "engaged-by": "oncall"},
+	}
+}
+
+func TestAutoRemediationBlockers(t *testing.T) {
+	config := &AutoRemediationConfig{Enabled: true, Namespaces: []string{"shop"}, MaxMemoryLimit: "1Gi"}
+	limited := func(limit string) *remediationPlan {
+		return &remediationPlan{Recipe: recipeMemoryLimit, Container: "api", Confidence: 0.95, Template: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limit)},
+			}}}},
+		}}
+	}
+	typo := &remediationPlan{Recipe: recipeImageTagTypo, Container: "api", Confidence: 0.9}
+
+	tests := []struct {
+		name      string
+		config    *AutoRemediationConfig
+		namespace string
+		plan      *remediationPlan
+		objects   []runtime.Object
+		want      []string
+	}{
+		{name: "allowed", config: config, namespace: "shop", plan: typo},
+		{name: "disabled", config: &AutoRemediationConfig{Namespaces: []string{"shop"}}, namespace: "shop", plan: typo, want: []string{"auto-remediation is disabled"}},
+		{name: "namespace", config: config, namespace: "billing", plan: typo, want: []string{"namespace billing is not allowed"}},
+		{name: "recipe", config: &AutoRemediationConfig{Enabled: true, Namespaces: []string{"shop"}, Recipes: []string{recipeMemoryLimit}}, namespace: "shop", plan: typo, want: []string{"recipe image-tag-typo is not allowed"}},
+		{name: "confidence", config: config, namespace: "shop", plan: &remediationPlan{Recipe: recipeMissingConfigMapKey, Confidence: 0.6}, want: []string{"confidence 60% is below the 90% threshold"}},
+		{name: "memory cap", config: config, namespace: "shop", plan: limited("2Gi"), want: []string{"memory limit 2Gi exceeds the 1Gi cap"}},
+		{name: "memory within cap", config: config, namespace: "shop", plan: limited("512Mi")},
+		{name: "kill switch", config: config, namespace: "shop", plan: typo, objects: []runtime.Object{killSwitch("true")}, want: []string{"kill switch engaged by oncall: incident 42"}},
+		{name: "kill switch released", config: config, namespace: "shop", plan: typo, objects: []runtime.Object{killSwitch("false")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(tt.objects...)}
+			blockers := s.autoRemediationBlockers(context.Background(), tt.config, tt.namespace, tt.plan)
+			if len(blockers) != len(tt.want) {
+				t.Fatalf("blockers = %v, expected %d", blockers, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(blockers[i], want) {
+					t.Errorf("blocker %d = %q, expected %q", i, blockers[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoRemediate(t *testing.T) {
+	interval := remediationPollInterval
+	remediationPollInterval = 10 * time.Millisecond
+	defer func() { remediationPollInterval = interval }()
+
+	typo := corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.O"}
+	limited := corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.0", Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+	}}
+	oomKilled := corev1.ContainerStatus{
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+	}
+	call := func(s *Server, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), arguments map[string]interface{}) string {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = arguments
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return resultText(result)
+	}
+	newServer := func(objects ...runtime.Object) *Server {
+		return &Server{
+			config:    &Config{AutoRemediation: &AutoRemediationConfig{Enabled: true, Namespaces: []string{"shop"}, TimeoutSeconds: 5}},
+			k8sClient: kubefake.NewSimpleClientset(objects...),
+		}
+	}
+
+	t.Run("confident fix runs automatically", func(t *testing.T) {
+		s := newServer(remediationObjects(typo, waitingStatus("ImagePullBackOff"), 1)...)
+		text := call(s, s.autoRemediateHandler, map[string]interface{}{})
+		if !strings.Contains(text, "🤖 Remediated automatically (verified)") {
+			t.Fatalf("expected an automatic remediation, got:\n%s", text)
+		}
+		deployment, _ := s.k8sClient.AppsV1().Deployments("shop").Get(context.Background(), "api", metav1.GetOptions{})
+		if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "quay.io/shop/api:v1.4.0" {
+			t.Errorf("image after auto-remediation = %s", image)
+		}
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		s := newServer(remediationObjects(typo, waitingStatus("ImagePullBackOff"), 1)...)
+		text := call(s, s.autoRemediateHandler, map[string]interface{}{"dry_run": "true"})
+		if !strings.Contains(text, "🧪 Would be remediated automatically") {
+			t.Errorf("expected a preview, got:\n%s", text)
+		}
+		deployment, _ := s.k8sClient.AppsV1().Deployments("shop").Get(context.Background(), "api", metav1.GetOptions{})
+		if image := deployment.Spec.Template.Spec.Containers[0].Image; image != typo.Image {
+			t.Errorf("dry run changed the image to %s", image)
+		}
+	})
+
+	t.Run("kill switch queues the fix", func(t *testing.T) {
+		s := newServer(append(remediationObjects(typo, waitingStatus("ImagePullBackOff"), 1), killSwitch("true"))...)
+		text := call(s, s.autoRemediateHandler, map[string]interface{}{})
+		if !strings.Contains(text, "📝 Awaiting approval rem-1: kill switch engaged") {
+			t.Errorf("expected the fix to be queued, got:\n%s", text)
+		}
+	})
+
+	t.Run("less confident fix is approved", func(t *testing.T) {
+		s := newServer(remediationObjects(limited, oomKilled, 1)...)
+		text := call(s, s.autoRemediateHandler, map[string]interface{}{})
+		if !strings.Contains(text, "📝 Awaiting approval rem-1: confidence 85% is below the 90% threshold") {
+			t.Fatalf("expected the fix to be queued, got:\n%s", text)
+		}
+		// A second sweep does not queue the same issue again
+		call(s, s.autoRemediateHandler, map[string]interface{}{})
+		if approvals := s.remediations.list(); len(approvals) != 1 {
+			t.Errorf("expected 1 approval after two sweeps, got %d", len(approvals))
+		}
+
+		text = call(s, s.listRemediationApprovalsHandler, map[string]interface{}{})
+		if !strings.Contains(text, "rem-1 [pending] shop/api") || !strings.Contains(text, "Kill switch: released") {
+			t.Errorf("unexpected approval list:\n%s", text)
+		}
+
+		text = call(s, s.approveRemediationHandler, map[string]interface{}{"approval_id": "rem-1", "approved_by": "alice"})
+		if !strings.Contains(text, "🖊️  Approved by: alice") || !strings.Contains(text, "✅ Verified") {
+			t.Fatalf("unexpected approval result:\n%s", text)
+		}
+		deployment, _ := s.k8sClient.AppsV1().Deployments("shop").Get(context.Background(), "api", metav1.GetOptions{})
+		if limit := deployment.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory]; limit.String() != "512Mi" {
+			t.Errorf("memory limit after approval = %s", limit.String())
+		}
+		if approval, _ := s.remediations.get("rem-1"); approval.Status != approvalApproved || approval.Outcome != string(remediationVerified) {
+			t.Errorf("approval after approving = %+v", approval)
+		}
+
+		text = call(s, s.approveRemediationHandler, map[string]interface{}{"approval_id": "rem-1", "approved_by": "alice"})
+		if !strings.Contains(text, "already approved") {
+			t.Errorf("expected a second approval to be refused, got:\n%s", text)
+		}
+	})
+
+	t.Run("claimed approval is not decided twice", func(t *testing.T) {
+		s := newServer(remediationObjects(limited, oomKilled, 1)...)
+		call(s, s.autoRemediateHandler, map[string]interface{}{})
+		release, ok, err := s.remediations.claim("rem-1", time.Minute)
+		if err != nil || !ok {
+			t.Fatalf("claim() = %v, %v", ok, err)
+		}
+		text := call(s, s.approveRemediationHandler, map[string]interface{}{"approval_id": "rem-1", "approved_by": "alice"})
+		if !strings.Contains(text, "❌ Approval rem-1 is being decided by another call") {
+			t.Errorf("expected the approval to be refused while claimed, got:\n%s", text)
+		}
+		if approval, _ := s.remediations.get("rem-1"); approval.Status != approvalPending {
+			t.Errorf("approval while claimed = %+v", approval)
+		}
+		release()
+		text = call(s, s.approveRemediationHandler, map[string]interface{}{"approval_id": "rem-1", "approved_by": "bob", "reject": "true"})
+		if !strings.Contains(text, "🚫 Rejected by bob") {
+			t.Errorf("expected the approval to be decided after release, got:\n%s", text)
+		}
+	})
+
+	t.Run("rejected fix is not applied", func(t *testing.T) {
+		s := newServer(remediationObjects(limited, oomKilled, 1)...)
+		call(s, s.autoRemediateHandler, map[string]interface{}{})
+		text := call(s, s.approveRemediationHandler, map[string]interface{}{"approval_id": "rem-1", "approved_by": "bob", "reject": "true"})
+		if !strings.Contains(text, "🚫 Rejected by bob") {
+			t.Errorf("unexpected rejection result:\n%s", text)
+		}
+		deployment, _ := s.k8sClient.AppsV1().Deployments("shop").Get(context.Background(), "api", metav1.GetOptions{})
+		if limit := deployment.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory]; limit.String() != "256Mi" {
+			t.Errorf("memory limit after rejection = %s", limit.String())
+		}
+	})
+
+	t.Run("kill switch tool", func(t *testing.T) {
+		s := newServer()
+		call(s, s.autoRemediationKillSwitchHandler, map[string]interface{}{"action": "engage", "reason": "incident 42", "requested_by": "oncall"})
+		if engaged, reason := s.killSwitchEngaged(context.Background()); !engaged || !strings.Contains(reason, "incident 42") {
+			t.Errorf("after engaging: engaged=%v reason=%q", engaged, reason)
+		}
+		call(s, s.autoRemediationKillSwitchHandler, map[string]interface{}{"action": "release"})
+		if engaged, _ := s.killSwitchEngaged(context.Background()); engaged {
+			t.Error("kill switch still engaged after release")
+		}
+	})
+}
@@ -0,0 +1,635 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// defaultBaselineDir is where baselines are stored when no directory is configured
+const defaultBaselineDir = "/tmp/diagnostics/baselines"
+
+var (
+	clusterOperatorsGVR = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}
+
+	// clusterConfigResources are the cluster-wide config.openshift.io resources
+	// whose spec is checksummed in a baseline
+	clusterConfigResources = []string{
+		"apiservers", "authentications", "clusterversions", "dnses", "featuregates", "images",
+		"infrastructures", "ingresses", "networks", "oauths", "proxies", "schedulers",
+	}
+
+	baselineNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// Baseline is a snapshot of a known-good cluster used to spot regressions
+// after upgrades or changes
+type Baseline struct {
+	Name       string                   `json:"name"`
+	CapturedAt time.Time                `json:"captured_at"`
+	Namespaces []string                 `json:"namespaces,omitempty"` // empty means all namespaces
+	Operators  map[string]OperatorState `json:"operators,omitempty"`
+	Pods       map[string]PodCounts     `json:"pods"`
+	Metrics    map[string]int64         `json:"metrics"`
+	Checksums  map[string]string        `json:"checksums"`
+}
+
+// OperatorState is the condition summary of a ClusterOperator
+type OperatorState struct {
+	Version     string `json:"version,omitempty"`
+	Available   bool   `json:"available"`
+	Progressing bool   `json:"progressing"`
+	Degraded    bool   `json:"degraded"`
+}
+
+// PodCounts summarizes the pods of one namespace
+type PodCounts struct {
+	Running   int   `json:"running"`
+	Pending   int   `json:"pending"`
+	Failed    int   `json:"failed"`
+	Succeeded int   `json:"succeeded"`
+	NotReady  int   `json:"not_ready"`
+	Restarts  int64 `json:"restarts"`
+}
+
+// baselineMetrics says whether a rise in each metric is a regression (true)
+// or an improvement (false)
+var baselineMetrics = map[string]bool{
+	"nodes_total":        false,
+	"nodes_ready":        false,
+	"pods_running":       false,
+	"pods_not_ready":     true,
+	"container_restarts": true,
+}
+
+// BaselineChange is one difference found when comparing against a baseline
+type BaselineChange struct {
+	Severity string // regression, change or improvement
+	Area     string
+	Message  string
+}
+
+const (
+	changeRegression  = "regression"
+	changeNeutral     = "change"
+	changeImprovement = "improvement"
+)
+
+func (s *Server) initBaselineTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("capture_baseline",
+			mcp.WithDescription("Capture a known-good cluster baseline: operator states, pod counts, key metrics and config checksums"),
+			mcp.WithTitleAnnotation("Baseline: Capture"),
+			mcp.WithString("name", mcp.Description("Baseline name, e.g. pre-upgrade-4.14"), mcp.Required()),
+			mcp.WithString("namespaces", mcp.Description("Comma-separated namespaces to include; ConfigMaps and Deployments are only checksummed for these (default: all namespaces)")),
+			mcp.WithString("overwrite", mcp.Description("Replace an existing baseline with the same name (true/false)")),
+		), Handler: server.ToolHandlerFunc(s.captureBaselineHandler)},
+		{Tool: mcp.NewTool("compare_baseline",
+			mcp.WithDescription("Compare the current cluster state against a saved baseline and highlight regressions"),
+			mcp.WithTitleAnnotation("Baseline: Compare"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("name", mcp.Description("Baseline name to compare against"), mcp.Required()),
+		), Handler: server.ToolHandlerFunc(s.compareBaselineHandler)},
+		{Tool: mcp.NewTool("list_baselines",
+			mcp.WithDescription("List saved cluster baselines"),
+			mcp.WithTitleAnnotation("Baseline: List"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listBaselinesHandler)},
+	}
+}
+
+// baselineDir returns the configured baseline directory
+func (s *Server) baselineDir() string {
+	if s.config != nil && s.config.BaselineDir != "" {
+		return s.config.BaselineDir
+	}
+	return defaultBaselineDir
+}
+
+func (s *Server) baselinePath(name string) (string, error) {
+	if !baselineNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid baseline name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return filepath.Join(s.baselineDir(), name+".json"), nil
+}
+
+func (s *Server) saveBaseline(baseline *Baseline) error {
+	path, err := s.baselinePath(baseline.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create baseline directory: %v", err)
+	}
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *Server) loadBaseline(name string) (*Baseline, error) {
+	path, err := s.baselinePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("baseline %s is corrupt: %v", name, err)
+	}
+	return &baseline, nil
+}
+
+// listBaselineNames returns saved baseline names, sorted
+func (s *Server) listBaselineNames() ([]string, error) {
+	entries, err := os.ReadDir(s.baselineDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// checksum hashes a value's JSON encoding, which has sorted map keys
+func checksum(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// captureState collects the current cluster state for the given namespaces
+// (all namespaces when empty). Sources that are unavailable, such as
+// ClusterOperators on plain Kubernetes, are skipped and reported as notes.
+func (s *Server) captureState(ctx context.Context, name string, namespaces []string) (*Baseline, []string, error) {
+	baseline := &Baseline{
+		Name:       name,
+		CapturedAt: time.Now().UTC(),
+		Namespaces: namespaces,
+		Pods:       make(map[string]PodCounts),
+		Metrics:    make(map[string]int64),
+		Checksums:  make(map[string]string),
+	}
+	var notes []string
+
+	// Nodes
+	nodes, err := s.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	baseline.Metrics["nodes_total"] = int64(len(nodes.Items))
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				baseline.Metrics["nodes_ready"]++
+			}
+		}
+	}
+
+	// Pods
+	scopes := namespaces
+	if len(scopes) == 0 {
+		scopes = []string{metav1.NamespaceAll}
+	}
+	for _, namespace := range scopes {
+		pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		if namespace != metav1.NamespaceAll {
+			baseline.Pods[namespace] = PodCounts{}
+		}
+		for _, pod := range pods.Items {
+			counts := baseline.Pods[pod.Namespace]
+			switch pod.Status.Phase {
+			case corev1.PodRunning:
+				counts.Running++
+				if !podReady(&pod) {
+					counts.NotReady++
+				}
+			case corev1.PodPending:
+				counts.Pending++
+			case corev1.PodFailed:
+				counts.Failed++
+			case corev1.PodSucceeded:
+				counts.Succeeded++
+			}
+			for _, status := range pod.Status.ContainerStatuses {
+				counts.Restarts += int64(status.RestartCount)
+			}
+			baseline.Pods[pod.Namespace] = counts
+		}
+	}
+	for _, counts := range baseline.Pods {
+		baseline.Metrics["pods_running"] += int64(counts.Running)
+		baseline.Metrics["pods_not_ready"] += int64(counts.NotReady)
+		baseline.Metrics["container_restarts"] += counts.Restarts
+	}
+
+	// Cluster operators and cluster config, OpenShift only
+	if s.dynamicClient != nil {
+		operators, err := s.dynamicClient.Resource(clusterOperatorsGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("ClusterOperators not captured: %v", err))
+		} else {
+			baseline.Operators = make(map[string]OperatorState)
+			for _, operator := range operators.Items {
+				baseline.Operators[operator.GetName()] = operatorState(&operator)
+			}
+		}
+
+		for _, resource := range clusterConfigResources {
+			gvr := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: resource}
+			list, err := s.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				continue
+			}
+			for _, item := range list.Items {
+				if spec, ok := item.Object["spec"]; ok {
+					baseline.Checksums[fmt.Sprintf("%s/%s", resource, item.GetName())] = checksum(spec)
+				}
+			}
+		}
+	}
+
+	// Application config, only for explicitly scoped namespaces
+	for _, namespace := range namespaces {
+		configMaps, err := s.k8sClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("ConfigMaps in %s not captured: %v", namespace, err))
+		} else {
+			for _, cm := range configMaps.Items {
+				if cm.Name == "kube-root-ca.crt" || cm.Name == "openshift-service-ca.crt" {
+					continue
+				}
+				baseline.Checksums[fmt.Sprintf("configmaps/%s/%s", namespace, cm.Name)] = checksum([]interface{}{cm.Data, cm.BinaryData})
+			}
+		}
+
+		deployments, err := s.k8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Deployments in %s not captured: %v", namespace, err))
+		} else {
+			for _, deployment := range deployments.Items {
+				baseline.Checksums[fmt.Sprintf("deployments/%s/%s", namespace, deployment.Name)] = checksum([]interface{}{deployment.Spec.Replicas, deployment.Spec.Template})
+			}
+		}
+	}
+
+	return baseline, notes, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// operatorState reads the version and conditions of a ClusterOperator
+func operatorState(operator *unstructured.Unstructured) OperatorState {
+	var state OperatorState
+	versions, _, _ := unstructured.NestedSlice(operator.Object, "status", "versions")
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok && version["name"] == "operator" {
+			state.Version, _ = version["version"].(string)
+		}
+	}
+	conditions, _, _ := unstructured.NestedSlice(operator.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		status := condition["status"] == "True"
+		switch condition["type"] {
+		case "Available":
+			state.Available = status
+		case "Progressing":
+			state.Progressing = status
+		case "Degraded":
+			state.Degraded = status
+		}
+	}
+	return state
+}
+
+// compareBaselines lists the differences between a baseline and the current state
+func compareBaselines(base, current *Baseline) []BaselineChange {
+	var changes []BaselineChange
+	add := func(severity, area, format string, args ...interface{}) {
+		changes = append(changes, BaselineChange{Severity: severity, Area: area, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Operators
+	if base.Operators != nil && current.Operators != nil {
+		for _, name := range sortedKeys(base.Operators) {
+			was := base.Operators[name]
+			now, ok := current.Operators[name]
+			if !ok {
+				add(changeRegression, "operators", "%s is missing", name)
+				continue
+			}
+			switch {
+			case was.Available && !now.Available:
+				add(changeRegression, "operators", "%s is no longer Available", name)
+			case !was.Available && now.Available:
+				add(changeImprovement, "operators", "%s is now Available", name)
+			}
+			switch {
+			case !was.Degraded && now.Degraded:
+				add(changeRegression, "operators", "%s is now Degraded", name)
+			case was.Degraded && !now.Degraded:
+				add(changeImprovement, "operators", "%s is no longer Degraded", name)
+			}
+			if !was.Progressing && now.Progressing {
+				add(changeNeutral, "operators", "%s is Progressing", name)
+			}
+			if was.Version != now.Version {
+				add(changeNeutral, "operators", "%s version %s → %s", name, valueOrNone(was.Version), valueOrNone(now.Version))
+			}
+		}
+		for _, name := range sortedKeys(current.Operators) {
+			if _, ok := base.Operators[name]; !ok {
+				add(changeNeutral, "operators", "%s is new", name)
+			}
+		}
+	}
+
+	// Pods
+	for _, namespace := range sortedKeys(base.Pods) {
+		was := base.Pods[namespace]
+		now, ok := current.Pods[namespace]
+		if !ok {
+			if was.Running > 0 {
+				add(changeRegression, "pods", "%s has no pods (was %d running)", namespace, was.Running)
+			}
+			continue
+		}
+		if now.Running < was.Running {
+			add(changeRegression, "pods", "%s running pods %d → %d", namespace, was.Running, now.Running)
+		}
+		if now.Pending > was.Pending {
+			add(changeRegression, "pods", "%s pending pods %d → %d", namespace, was.Pending, now.Pending)
+		}
+		if now.Failed > was.Failed {
+			add(changeRegression, "pods", "%s failed pods %d → %d", namespace, was.Failed, now.Failed)
+		}
+		if now.NotReady > was.NotReady {
+			add(changeRegression, "pods", "%s not-ready pods %d → %d", namespace, was.NotReady, now.NotReady)
+		}
+		if now.Restarts > was.Restarts {
+			add(changeRegression, "pods", "%s container restarts +%d", namespace, now.Restarts-was.Restarts)
+		}
+	}
+
+	// Metrics
+	for _, name := range sortedKeys(baselineMetrics) {
+		was, now := base.Metrics[name], current.Metrics[name]
+		if was == now {
+			continue
+		}
+		severity := changeImprovement
+		if (now > was) == baselineMetrics[name] {
+			severity = changeRegression
+		}
+		add(severity, "metrics", "%s %d → %d", name, was, now)
+	}
+
+	// Config checksums
+	for _, key := range sortedKeys(base.Checksums) {
+		now, ok := current.Checksums[key]
+		switch {
+		case !ok:
+			add(changeNeutral, "config", "%s was removed", key)
+		case now != base.Checksums[key]:
+			add(changeNeutral, "config", "%s changed", key)
+		}
+	}
+	for _, key := range sortedKeys(current.Checksums) {
+		if _, ok := base.Checksums[key]; !ok {
+			add(changeNeutral, "config", "%s was added", key)
+		}
+	}
+
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+func parseNamespaceList(value string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func (s *Server) captureBaselineHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	name := mcp.ParseString(request, "name", "")
+	path, err := s.baselinePath(name)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if !parseBoolString(mcp.ParseString(request, "overwrite", "false")) {
+		if _, err := os.Stat(path); err == nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Baseline %s already exists. Set overwrite=true to replace it.", name)), nil
+		}
+	}
+
+	baseline, notes, err := s.captureState(ctx, name, parseNamespaceList(mcp.ParseString(request, "namespaces", "")))
+	if err != nil {
+		return toolError(ctx, "Failed to capture baseline", err), nil
+	}
+	if err := s.saveBaseline(baseline); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to save baseline: %v", err)), nil
+	}
+
+	result := "📸 Cluster Baseline Captured\n"
+	result += "============================\n\n"
+	result += fmt.Sprintf("Name: %s\n", baseline.Name)
+	result += fmt.Sprintf("Captured: %s\n", formatTime(ctx, baseline.CapturedAt))
+	if len(baseline.Namespaces) > 0 {
+		result += fmt.Sprintf("Namespaces: %s\n", strings.Join(baseline.Namespaces, ", "))
+	} else {
+		result += "Namespaces: all\n"
+	}
+	result += fmt.Sprintf("Saved to: %s\n\n", path)
+
+	degraded := 0
+	for _, state := range baseline.Operators {
+		if state.Degraded || !state.Available {
+			degraded++
+		}
+	}
+	result += fmt.Sprintf("🧩 Cluster operators: %d (%d not healthy)\n", len(baseline.Operators), degraded)
+	result += fmt.Sprintf("🖥️  Nodes: %d/%d ready\n", baseline.Metrics["nodes_ready"], baseline.Metrics["nodes_total"])
+	result += fmt.Sprintf("📦 Pods: %d running in %d namespaces, %d not ready, %d container restarts\n",
+		baseline.Metrics["pods_running"], len(baseline.Pods), baseline.Metrics["pods_not_ready"], baseline.Metrics["container_restarts"])
+	result += fmt.Sprintf("🔐 Config checksums: %d\n", len(baseline.Checksums))
+
+	if degraded > 0 || baseline.Metrics["pods_not_ready"] > 0 {
+		result += "\n⚠️  The cluster is not fully healthy; compare results will treat this state as known-good\n"
+	}
+	for _, note := range notes {
+		result += fmt.Sprintf("ℹ️  %s\n", note)
+	}
+	result += fmt.Sprintf("\n💡 Run compare_baseline with name=%s after upgrades or changes", baseline.Name)
+
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) compareBaselineHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	name := mcp.ParseString(request, "name", "")
+	base, err := s.loadBaseline(name)
+	if err != nil {
+		message := fmt.Sprintf("❌ Failed to load baseline %s: %v", name, err)
+		if names, _ := s.listBaselineNames(); len(names) > 0 {
+			message += fmt.Sprintf("\nAvailable baselines: %s", strings.Join(names, ", "))
+		}
+		return mcp.NewToolResultText(message), nil
+	}
+
+	current, notes, err := s.captureState(ctx, name, base.Namespaces)
+	if err != nil {
+		return toolError(ctx, "Failed to collect current cluster state", err), nil
+	}
+	changes := compareBaselines(base, current)
+
+	result := fmt.Sprintf("🔍 Baseline Comparison: %s\n", base.Name)
+	result += "==============================\n\n"
+	result += fmt.Sprintf("Baseline captured: %s\n\n", formatTimeWithAge(ctx, base.CapturedAt))
+
+	sections := []struct {
+		severity string
+		heading  string
+	}{
+		{changeRegression, "🔴 Regressions"},
+		{changeNeutral, "🟡 Changes"},
+		{changeImprovement, "🟢 Improvements"},
+	}
+	counts := make(map[string]int)
+	for _, section := range sections {
+		var lines []string
+		for _, change := range changes {
+			if change.Severity == section.severity {
+				lines = append(lines, fmt.Sprintf("  • [%s] %s", change.Area, change.Message))
+			}
+		}
+		counts[section.severity] = len(lines)
+		if len(lines) > 0 {
+			result += fmt.Sprintf("%s (%d):\n%s\n\n", section.heading, len(lines), strings.Join(lines, "\n"))
+		}
+	}
+
+	for _, note := range notes {
+		result += fmt.Sprintf("ℹ️  %s\n", note)
+	}
+
+	switch {
+	case counts[changeRegression] > 0:
+		result += fmt.Sprintf("❌ %d regressions since the baseline", counts[changeRegression])
+	case len(changes) > 0:
+		result += "✅ No regressions; review the changes above"
+	default:
+		result += "✅ Cluster matches the baseline"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) listBaselinesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	names, err := s.listBaselineNames()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to list baselines: %v", err)), nil
+	}
+	if len(names) == 0 {
+		return mcp.NewToolResultText("📭 No baselines saved yet. Use capture_baseline to record a known-good state."), nil
+	}
+
+	result := "📚 Saved Baselines\n"
+	result += "==================\n\n"
+	for _, name := range names {
+		baseline, err := s.loadBaseline(name)
+		if err != nil {
+			result += fmt.Sprintf("⚠️  %s: %v\n", name, err)
+			continue
+		}
+		scope := "all namespaces"
+		if len(baseline.Namespaces) > 0 {
+			scope = strings.Join(baseline.Namespaces, ", ")
+		}
+		result += fmt.Sprintf("• %s - captured %s (%s)\n", name, formatTime(ctx, baseline.CapturedAt), scope)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// CaptureBaselineHandler is a public wrapper for captureBaselineHandler
+func (s *Server) CaptureBaselineHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.captureBaselineHandler(ctx, request)
+}
+
+// CompareBaselineHandler is a public wrapper for compareBaselineHandler
+func (s *Server) CompareBaselineHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.compareBaselineHandler(ctx, request)
+}
+
+// ListBaselinesHandler is a public wrapper for listBaselinesHandler
+func (s *Server) ListBaselinesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listBaselinesHandler(ctx, request)
+}
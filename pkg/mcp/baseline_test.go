@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCompareBaselines(t *testing.T) {
+	base := &Baseline{
+		Operators: map[string]OperatorState{
+			"dns":     {Version: "4.14.1", Available: true},
+			"ingress": {Version: "4.14.1", Available: true},
+			"console": {Version: "4.14.1", Available: true, Degraded: true},
+		},
+		Pods:      map[string]PodCounts{"shop": {Running: 3, Restarts: 2}, "batch": {Running: 1}},
+		Metrics:   map[string]int64{"nodes_ready": 3, "nodes_total": 3},
+		Checksums: map[string]string{"networks/cluster": "a", "proxies/cluster": "b"},
+	}
+	current := &Baseline{
+		Operators: map[string]OperatorState{
+			"dns":     {Version: "4.15.0", Available: true},
+			"ingress": {Version: "4.15.0", Available: false, Degraded: true},
+			"console": {Version: "4.15.0", Available: true},
+		},
+		Pods:      map[string]PodCounts{"shop": {Running: 2, Pending: 1, Restarts: 7}},
+		Metrics:   map[string]int64{"nodes_ready": 2, "nodes_total": 3},
+		Checksums: map[string]string{"networks/cluster": "a", "proxies/cluster": "c"},
+	}
+
+	found := make(map[string]string)
+	for _, change := range compareBaselines(base, current) {
+		found[change.Message] = change.Severity
+	}
+
+	tests := []struct {
+		message  string
+		severity string
+	}{
+		{"ingress is no longer Available", changeRegression},
+		{"ingress is now Degraded", changeRegression},
+		{"console is no longer Degraded", changeImprovement},
+		{"dns version 4.14.1 → 4.15.0", changeNeutral},
+		{"shop running pods 3 → 2", changeRegression},
+		{"shop pending pods 0 → 1", changeRegression},
+		{"shop container restarts +5", changeRegression},
+		{"batch has no pods (was 1 running)", changeRegression},
+		{"nodes_ready 3 → 2", changeRegression},
+		{"proxies/cluster changed", changeNeutral},
+	}
+	for _, tt := range tests {
+		if got, ok := found[tt.message]; !ok || got != tt.severity {
+			t.Errorf("compareBaselines() change %q = %q, expected %q (all changes: %v)", tt.message, got, tt.severity, found)
+		}
+	}
+	if _, ok := found["networks/cluster changed"]; ok {
+		t.Errorf("compareBaselines() reported an unchanged checksum")
+	}
+
+	if changes := compareBaselines(base, base); len(changes) != 0 {
+		t.Errorf("compareBaselines(base, base) = %v, expected no changes", changes)
+	}
+}
+
+func newBaselineTestServer(t *testing.T, objects ...runtime.Object) *Server {
+	listKinds := map[schema.GroupVersionResource]string{clusterOperatorsGVR: "ClusterOperatorList"}
+	for _, resource := range clusterConfigResources {
+		listKinds[schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: resource}] = "ConfigList"
+	}
+
+	operator := newUnstructured("config.openshift.io/v1", "ClusterOperator", "", "ingress")
+	unstructured.SetNestedSlice(operator.Object, []interface{}{
+		map[string]interface{}{"type": "Available", "status": "True"},
+		map[string]interface{}{"type": "Degraded", "status": "False"},
+	}, "status", "conditions")
+
+	return &Server{
+		config:        &Config{BaselineDir: t.TempDir()},
+		k8sClient:     kubefake.NewSimpleClientset(objects...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, operator),
+	}
+}
+
+func baselineRequest(args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func TestCaptureAndCompareBaseline(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shop"}, Data: map[string]string{"mode": "fast"}}
+	s := newBaselineTestServer(t, pod, configMap)
+	ctx := context.Background()
+
+	result, _ := s.captureBaselineHandler(ctx, baselineRequest(map[string]interface{}{"name": "pre-upgrade", "namespaces": "shop"}))
+	text := resultText(result)
+	for _, expected := range []string{"📸 Cluster Baseline Captured", "Cluster operators: 1 (0 not healthy)", "Pods: 1 running in 1 namespaces", "Config checksums: 1"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("capture result missing %q:\n%s", expected, text)
+		}
+	}
+
+	// A second capture under the same name must not clobber the baseline
+	result, _ = s.captureBaselineHandler(ctx, baselineRequest(map[string]interface{}{"name": "pre-upgrade"}))
+	if text := resultText(result); !strings.Contains(text, "already exists") {
+		t.Errorf("second capture = %q, expected an already exists error", text)
+	}
+
+	result, _ = s.compareBaselineHandler(ctx, baselineRequest(map[string]interface{}{"name": "pre-upgrade"}))
+	if text := resultText(result); !strings.Contains(text, "✅ Cluster matches the baseline") {
+		t.Errorf("compare before changes:\n%s", text)
+	}
+
+	// Break the pod and change the config
+	pod.Status.Phase = corev1.PodPending
+	s.k8sClient.CoreV1().Pods("shop").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	configMap.Data["mode"] = "slow"
+	s.k8sClient.CoreV1().ConfigMaps("shop").Update(ctx, configMap, metav1.UpdateOptions{})
+
+	result, _ = s.compareBaselineHandler(ctx, baselineRequest(map[string]interface{}{"name": "pre-upgrade"}))
+	text = resultText(result)
+	for _, expected := range []string{"🔴 Regressions", "[pods] shop running pods 1 → 0", "[config] configmaps/shop/settings changed", "❌ 3 regressions since the baseline"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("compare result missing %q:\n%s", expected, text)
+		}
+	}
+
+	result, _ = s.listBaselinesHandler(ctx, baselineRequest(nil))
+	if text := resultText(result); !strings.Contains(text, "• pre-upgrade - captured") || !strings.Contains(text, "(shop)") {
+		t.Errorf("list_baselines = %q, expected pre-upgrade scoped to shop", text)
+	}
+
+	result, _ = s.compareBaselineHandler(ctx, baselineRequest(map[string]interface{}{"name": "../etc/passwd"}))
+	if text := resultText(result); !strings.Contains(text, "invalid baseline name") {
+		t.Errorf("compare with a path name = %q, expected invalid name", text)
+	}
+}
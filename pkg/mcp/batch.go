@@ -0,0 +1,517 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// cronJobInstantiateAnnotation marks Jobs created by hand from a CronJob,
+	// the same way `oc create job --from=cronjob/<name>` does
+	cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+
+	defaultBackoffLimit = 6
+)
+
+// jobFinished returns the Complete or Failed condition of a finished Job
+func jobFinished(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+func jobFailed(job *batchv1.Job) bool {
+	condition := jobFinished(job)
+	return condition != nil && condition.Type == batchv1.JobFailed
+}
+
+// jobOwner returns the CronJob that created a Job, or ""
+func jobOwner(job *batchv1.Job) string {
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "CronJob" {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+func backoffLimit(job *batchv1.Job) int32 {
+	if job.Spec.BackoffLimit == nil {
+		return defaultBackoffLimit
+	}
+	return *job.Spec.BackoffLimit
+}
+
+// jobStatus summarizes a Job as an icon and a status line
+func jobStatus(job *batchv1.Job) (string, string) {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	counts := fmt.Sprintf("%d/%d succeeded, %d failed", job.Status.Succeeded, completions, job.Status.Failed)
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return "⏸️ ", "Suspended, " + counts
+	}
+	if condition := jobFinished(job); condition != nil {
+		if condition.Type == batchv1.JobFailed {
+			return "❌", fmt.Sprintf("Failed (%s), %s", condition.Reason, counts)
+		}
+		status := "Complete, " + counts
+		if job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+			status += fmt.Sprintf(", took %s", job.Status.CompletionTime.Sub(job.Status.StartTime.Time).Round(time.Second))
+		}
+		return "✅", status
+	}
+	icon := "🔄"
+	if job.Status.Failed > 0 {
+		icon = "⚠️ "
+	}
+	return icon, fmt.Sprintf("Running, %d active, %s", job.Status.Active, counts)
+}
+
+// formatTimeAgo renders an optional timestamp as "5m ago" or "never"
+func formatTimeAgo(t *metav1.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return formatAge(t.Time) + " ago"
+}
+
+// jobPodFailure explains why one of a Job's pods failed or is stuck
+func jobPodFailure(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return fmt.Sprintf("not scheduled (%s): %s", condition.Reason, condition.Message)
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" && waiting.Reason != "PodInitializing" && waiting.Reason != "ContainerCreating" {
+			return fmt.Sprintf("container %s waiting: %s %s", status.Name, waiting.Reason, waiting.Message)
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			reason := fmt.Sprintf("container %s exited with code %d (%s)", status.Name, terminated.ExitCode, terminated.Reason)
+			if terminated.Message != "" {
+				reason += ": " + strings.TrimSpace(terminated.Message)
+			}
+			return reason
+		}
+	}
+	if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason != "" {
+		return fmt.Sprintf("%s: %s", pod.Status.Reason, pod.Status.Message)
+	}
+	return ""
+}
+
+// manualJobName names a Job triggered from a CronJob, within the 63
+// characters allowed for the job-name label
+func manualJobName(cronJob string, now time.Time) string {
+	suffix := fmt.Sprintf("-manual-%d", now.Unix())
+	if max := validation.DNS1123LabelMaxLength - len(suffix); len(cronJob) > max {
+		cronJob = strings.TrimRight(cronJob[:max], "-.")
+	}
+	return cronJob + suffix
+}
+
+func (s *Server) initBatch() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_jobs",
+			mcp.WithDescription("List Jobs with completion status, failed attempts, duration and the CronJob that created them"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list jobs from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=backup")),
+			mcp.WithTitleAnnotation("Batch: List Jobs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listJobsHandler)},
+		{Tool: mcp.NewTool("list_cronjobs",
+			mcp.WithDescription("List CronJobs with their schedule, suspension, active jobs and last schedule and success times"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list cronjobs from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=backup")),
+			mcp.WithTitleAnnotation("Batch: List CronJobs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listCronJobsHandler)},
+		{Tool: mcp.NewTool("trigger_cronjob",
+			mcp.WithDescription("Run a CronJob now by creating a Job from its template, like oc create job --from=cronjob/<name>"),
+			mcp.WithString("cronjob_name", mcp.Description("Name of the CronJob"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the CronJob (default: default)")),
+			mcp.WithString("job_name", mcp.Description("Name of the Job to create (default: <cronjob>-manual-<timestamp>)")),
+			mcp.WithTitleAnnotation("Batch: Trigger CronJob"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.triggerCronJobHandler)},
+		{Tool: mcp.NewTool("diagnose_job",
+			mcp.WithDescription("Explain why a Job failed or is stuck: backoff limit and deadline exceeded, failed pod exit codes and reasons, unschedulable pods, and the owning CronJob's schedule history"),
+			mcp.WithString("job_name", mcp.Description("Name of the Job"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the Job (default: default)")),
+			mcp.WithTitleAnnotation("Batch: Diagnose Job"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.diagnoseJobHandler)},
+	}
+}
+
+func (s *Server) listJobsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	jobs, err := s.k8sClient.BatchV1().Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list jobs in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	// Failed jobs first, then the most recent
+	sort.SliceStable(jobs.Items, func(i, j int) bool {
+		a, b := &jobs.Items[i], &jobs.Items[j]
+		if jobFailed(a) != jobFailed(b) {
+			return jobFailed(a)
+		}
+		return b.CreationTimestamp.Before(&a.CreationTimestamp)
+	})
+
+	result := "⚙️  Job List Results\n"
+	result += "===================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+
+	failed := 0
+	for i := range jobs.Items {
+		if jobFailed(&jobs.Items[i]) {
+			failed++
+		}
+	}
+	result += fmt.Sprintf("📦 Found %d jobs", len(jobs.Items))
+	if failed > 0 {
+		result += fmt.Sprintf(", ❌ %d failed", failed)
+	}
+	result += ":\n"
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		name := job.Name
+		if namespace == metav1.NamespaceAll {
+			name = job.Namespace + "/" + job.Name
+		}
+		icon, status := jobStatus(job)
+		result += fmt.Sprintf("%s %s - %s, age %s\n", icon, name, status, formatAge(job.CreationTimestamp.Time))
+		if owner := jobOwner(job); owner != "" {
+			result += fmt.Sprintf("   CronJob: %s\n", owner)
+		}
+	}
+	if len(jobs.Items) == 0 {
+		result += "📭 None found\n"
+	}
+	if failed > 0 {
+		result += "\n💡 Run diagnose_job on a failed job to find out why\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) listCronJobsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	cronJobs, err := s.k8sClient.BatchV1().CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list cronjobs in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	sort.SliceStable(cronJobs.Items, func(i, j int) bool {
+		a, b := cronJobs.Items[i], cronJobs.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	result := "⏰ CronJob List Results\n"
+	result += "======================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+	result += fmt.Sprintf("📦 Found %d cronjobs:\n", len(cronJobs.Items))
+
+	for _, cronJob := range cronJobs.Items {
+		name := cronJob.Name
+		if namespace == metav1.NamespaceAll {
+			name = cronJob.Namespace + "/" + cronJob.Name
+		}
+		icon, notes := "✅", []string{}
+		if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+			icon = "⏸️ "
+			notes = append(notes, "suspended")
+		}
+		// A schedule without a later success means the last run failed or is still running
+		last, success := cronJob.Status.LastScheduleTime, cronJob.Status.LastSuccessfulTime
+		if last != nil && (success == nil || success.Before(last)) && len(cronJob.Status.Active) == 0 {
+			icon = "⚠️ "
+			notes = append(notes, "last run did not succeed")
+		}
+		schedule := cronJob.Spec.Schedule
+		if cronJob.Spec.TimeZone != nil {
+			schedule += " " + *cronJob.Spec.TimeZone
+		}
+		result += fmt.Sprintf("%s %s - schedule %q, %d active, last scheduled %s, last succeeded %s", icon, name, schedule,
+			len(cronJob.Status.Active), formatTimeAgo(last), formatTimeAgo(success))
+		if len(notes) > 0 {
+			result += " (" + strings.Join(notes, "; ") + ")"
+		}
+		result += "\n"
+	}
+	if len(cronJobs.Items) == 0 {
+		result += "📭 None found\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) triggerCronJobHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "cronjob_name", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ cronjob_name is required"), nil
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+	jobName := strings.TrimSpace(mcp.ParseString(request, "job_name", ""))
+	if jobName == "" {
+		jobName = manualJobName(name, time.Now())
+	} else if errs := validation.IsDNS1123Label(jobName); len(errs) > 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid job_name %q: %s", jobName, strings.Join(errs, "; "))), nil
+	}
+
+	cronJob, err := s.k8sClient.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get cronjob %s/%s", namespace, name), err), nil
+	}
+
+	template := cronJob.Spec.JobTemplate
+	annotations := map[string]string{cronJobInstantiateAnnotation: "manual"}
+	for key, value := range template.Annotations {
+		annotations[key] = value
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   namespace,
+			Labels:      template.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	created, err := s.k8sClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to create job from cronjob %s/%s", namespace, name), err), nil
+	}
+	s.recordAction(ctx, "trigger_cronjob", objectReference("batch/v1", "CronJob", cronJob), ReasonTriggered,
+		fmt.Sprintf("Started job %s by hand", created.Name))
+
+	result := "▶️  Trigger CronJob\n"
+	result += "==================\n\n"
+	result += fmt.Sprintf("CronJob: %s/%s (schedule %q)\n", namespace, name, cronJob.Spec.Schedule)
+	result += fmt.Sprintf("✅ Created job %s\n", created.Name)
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		result += "ℹ️  The CronJob is suspended; only this manual run was started\n"
+	}
+	if len(cronJob.Status.Active) > 0 && cronJob.Spec.ConcurrencyPolicy != batchv1.AllowConcurrent {
+		result += fmt.Sprintf("⚠️  %d scheduled job(s) are still active; the %s concurrency policy does not apply to manual runs\n",
+			len(cronJob.Status.Active), cronJob.Spec.ConcurrencyPolicy)
+	}
+	result += fmt.Sprintf("\n💡 Follow it with list_jobs or diagnose_job job_name=%s", created.Name)
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) diagnoseJobHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "job_name", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ job_name is required"), nil
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+
+	job, err := s.k8sClient.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get job %s/%s", namespace, name), err), nil
+	}
+	pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + name})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list pods of job %s/%s", namespace, name), err), nil
+	}
+	sort.SliceStable(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+	})
+
+	icon, status := jobStatus(job)
+	result := "🔍 Job Diagnostic Report\n"
+	result += "========================\n\n"
+	result += fmt.Sprintf("Job: %s/%s\n", namespace, name)
+	result += fmt.Sprintf("%s Status: %s\n", icon, status)
+	result += fmt.Sprintf("Backoff limit: %d, failed attempts: %d\n", backoffLimit(job), job.Status.Failed)
+	if job.Spec.ActiveDeadlineSeconds != nil {
+		result += fmt.Sprintf("Active deadline: %ds\n", *job.Spec.ActiveDeadlineSeconds)
+	}
+	result += fmt.Sprintf("Started: %s\n", formatTimeAgo(job.Status.StartTime))
+
+	var findings []diagnosticFinding
+	if condition := jobFinished(job); condition != nil && condition.Type == batchv1.JobFailed {
+		switch condition.Reason {
+		case "BackoffLimitExceeded":
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("The job failed %d time(s) and reached its backoff limit of %d", job.Status.Failed, backoffLimit(job)),
+				"Fix the failure shown in the pod reasons below, then rerun the job; raising backoffLimit only retries the same failure",
+			})
+		case "DeadlineExceeded":
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("The job ran longer than its activeDeadlineSeconds: %s", condition.Message),
+				"Check whether the work got slower or stuck, or raise activeDeadlineSeconds",
+			})
+		default:
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("The job failed (%s): %s", condition.Reason, condition.Message),
+				"Check the pod reasons and events below",
+			})
+		}
+	}
+
+	// Report each distinct pod failure once, with the pods it affected
+	var reasons []string
+	affected := make(map[string][]string)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		reason := jobPodFailure(pod)
+		if reason == "" {
+			continue
+		}
+		if _, ok := affected[reason]; !ok {
+			reasons = append(reasons, reason)
+		}
+		affected[reason] = append(affected[reason], pod.Name)
+	}
+	for _, reason := range reasons {
+		fix := fmt.Sprintf("Check the logs with get_pod_logs pod_name=%s", affected[reason][len(affected[reason])-1])
+		switch {
+		case strings.Contains(reason, "not scheduled"):
+			fix = "Fix the pod's scheduling: node selectors, taints, resource requests or quota"
+		case strings.Contains(reason, "OOMKilled"):
+			fix = "The container ran out of memory; raise its memory limit or reduce the batch size"
+		case strings.Contains(reason, "ImagePull") || strings.Contains(reason, "ErrImagePull") || strings.Contains(reason, "InvalidImageName"):
+			fix = "Check the image name and tag and the pull secret of the job's service account"
+		case strings.Contains(reason, "CreateContainerConfigError"):
+			fix = "A referenced ConfigMap or Secret key is missing; create it or fix the reference"
+		}
+		findings = append(findings, diagnosticFinding{
+			fmt.Sprintf("%s (%d pod(s): %s)", reason, len(affected[reason]), strings.Join(affected[reason], ", ")),
+			fix,
+		})
+	}
+
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		findings = append(findings, diagnosticFinding{
+			"The job is suspended, so no pods are created",
+			"Resume it by setting spec.suspend to false",
+		})
+	}
+	if jobFinished(job) == nil && job.Status.Active == 0 && len(pods.Items) == 0 && (job.Spec.Suspend == nil || !*job.Spec.Suspend) {
+		findings = append(findings, diagnosticFinding{
+			"The job is not finished but has no pods",
+			"Check the job events below for quota, admission or pod security errors",
+		})
+	}
+
+	if owner := jobOwner(job); owner != "" {
+		result += fmt.Sprintf("CronJob: %s\n", owner)
+		if cronJob, err := s.k8sClient.BatchV1().CronJobs(namespace).Get(ctx, owner, metav1.GetOptions{}); err == nil {
+			result += fmt.Sprintf("• Schedule: %q, concurrency policy: %s\n", cronJob.Spec.Schedule, valueOrNone(string(cronJob.Spec.ConcurrencyPolicy)))
+			result += fmt.Sprintf("• Last scheduled: %s, last succeeded: %s\n",
+				formatTimeAgo(cronJob.Status.LastScheduleTime), formatTimeAgo(cronJob.Status.LastSuccessfulTime))
+			if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+				result += "• ⏸️  Suspended: no new runs are scheduled\n"
+			}
+		}
+	}
+
+	result += fmt.Sprintf("\n📦 Pods (%d):\n", len(pods.Items))
+	if len(pods.Items) == 0 {
+		result += "• None\n"
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		result += fmt.Sprintf("• %s - %s, node %s, age %s\n", pod.Name, pod.Status.Phase, valueOrNone(pod.Spec.NodeName), formatAge(pod.CreationTimestamp.Time))
+	}
+
+	if len(findings) == 0 {
+		result += "\n✅ No problems found\n"
+	} else {
+		result += fmt.Sprintf("\n⚠️  Found %d issue(s):\n", len(findings))
+		for i, finding := range findings {
+			result += fmt.Sprintf("%d. %s\n", i+1, finding.problem)
+			result += fmt.Sprintf("   💡 %s\n", finding.fix)
+		}
+	}
+
+	if events := s.objectEvents(ctx, namespace, "Job", name); len(events) > 0 {
+		result += "\n📜 Recent job events:\n"
+		if len(events) > 10 {
+			events = events[len(events)-10:]
+		}
+		for _, event := range events {
+			result += formatObjectEvent(event) + "\n"
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ListJobsHandler is a public wrapper for listJobsHandler
+func (s *Server) ListJobsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listJobsHandler(ctx, request)
+}
+
+// ListCronJobsHandler is a public wrapper for listCronJobsHandler
+func (s *Server) ListCronJobsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listCronJobsHandler(ctx, request)
+}
+
+// TriggerCronJobHandler is a public wrapper for triggerCronJobHandler
+func (s *Server) TriggerCronJobHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.triggerCronJobHandler(ctx, request)
+}
+
+// DiagnoseJobHandler is a public wrapper for diagnoseJobHandler
+func (s *Server) DiagnoseJobHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.diagnoseJobHandler(ctx, request)
+}
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func testCronJob() *batchv1.CronJob {
+	last := metav1.NewTime(time.Now().Add(-time.Hour))
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", UID: "uid-backup"},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          "0 * * * *",
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup"}},
+				Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{{Name: "backup", Image: "quay.io/acme/backup:1.0"}},
+				}}},
+			},
+		},
+		Status: batchv1.CronJobStatus{LastScheduleTime: &last},
+	}
+}
+
+func failedJob() *batchv1.Job {
+	limit := int32(2)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "backup-28000000", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "backup"}},
+		},
+		Spec: batchv1.JobSpec{BackoffLimit: &limit},
+		Status: batchv1.JobStatus{
+			Failed: 3,
+			Conditions: []batchv1.JobCondition{{
+				Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			}},
+		},
+	}
+}
+
+func failedJobPod(name string, exitCode int32, reason string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"job-name": "backup-28000000"}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "backup", State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason},
+			}}},
+		},
+	}
+}
+
+func TestManualJobName(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		cronJob  string
+		expected string
+	}{
+		{"backup", "backup-manual-1700000000"},
+		{strings.Repeat("a", 60), strings.Repeat("a", 45) + "-manual-1700000000"},
+		{strings.Repeat("a", 44) + "-b", strings.Repeat("a", 44) + "-manual-1700000000"},
+	}
+
+	for _, tt := range tests {
+		if name := manualJobName(tt.cronJob, now); name != tt.expected {
+			t.Errorf("manualJobName(%q) = %q, expected %q", tt.cronJob, name, tt.expected)
+		}
+	}
+}
+
+func TestJobPodFailure(t *testing.T) {
+	unschedulable := &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+		Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available",
+	}}}}
+	imagePull := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "job", State: corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+	}}}}}
+
+	tests := []struct {
+		pod      *corev1.Pod
+		expected string
+	}{
+		{unschedulable, "not scheduled (Unschedulable): 0/3 nodes are available"},
+		{imagePull, "container job waiting: ImagePullBackOff Back-off pulling image"},
+		{failedJobPod("p", 137, "OOMKilled"), "container backup exited with code 137 (OOMKilled)"},
+		{failedJobPod("p", 0, "Completed"), ""},
+		{&corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "DeadlineExceeded", Message: "too long"}}, "DeadlineExceeded: too long"},
+	}
+
+	for _, tt := range tests {
+		if reason := jobPodFailure(tt.pod); reason != tt.expected {
+			t.Errorf("jobPodFailure() = %q, expected %q", reason, tt.expected)
+		}
+	}
+}
+
+func TestListJobsAndCronJobs(t *testing.T) {
+	complete := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+		Status: batchv1.JobStatus{Succeeded: 1, Conditions: []batchv1.JobCondition{{
+			Type: batchv1.JobComplete, Status: corev1.ConditionTrue,
+		}}},
+	}
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(complete, failedJob(), testCronJob())}
+
+	result, err := s.ListJobsHandler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("ListJobsHandler error = %v", err)
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "Found 2 jobs, ❌ 1 failed") || !strings.Contains(text, "CronJob: backup") {
+		t.Errorf("list_jobs summary missing:\n%s", text)
+	}
+	if strings.Index(text, "backup-28000000") > strings.Index(text, "migrate") {
+		t.Errorf("list_jobs does not list failed jobs first:\n%s", text)
+	}
+
+	result, err = s.ListCronJobsHandler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("ListCronJobsHandler error = %v", err)
+	}
+	text = resultText(result)
+	if !strings.Contains(text, `backup - schedule "0 * * * *"`) || !strings.Contains(text, "last succeeded never (last run did not succeed)") {
+		t.Errorf("list_cronjobs = %q", text)
+	}
+}
+
+func TestTriggerCronJob(t *testing.T) {
+	client := kubefake.NewSimpleClientset(testCronJob())
+	s := &Server{config: &Config{}, k8sClient: client}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"cronjob_name": "backup", "job_name": "backup-now"}
+	result, err := s.TriggerCronJobHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("TriggerCronJobHandler error = %v", err)
+	}
+	if text := resultText(result); !strings.Contains(text, "✅ Created job backup-now") {
+		t.Fatalf("trigger_cronjob = %q", text)
+	}
+
+	job, err := client.BatchV1().Jobs("default").Get(context.Background(), "backup-now", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("job not created: %v", err)
+	}
+	if job.Annotations[cronJobInstantiateAnnotation] != "manual" || job.Labels["app"] != "backup" || jobOwner(job) != "backup" {
+		t.Errorf("created job = %+v, expected the manual annotation, template labels and CronJob owner", job.ObjectMeta)
+	}
+	if len(job.Spec.Template.Spec.Containers) != 1 || job.Spec.Template.Spec.Containers[0].Image != "quay.io/acme/backup:1.0" {
+		t.Errorf("created job does not use the CronJob's template: %+v", job.Spec.Template.Spec)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"cronjob_name": "backup", "job_name": "Bad_Name"}
+	result, _ = s.TriggerCronJobHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌ Invalid job_name") {
+		t.Errorf("trigger_cronjob with an invalid name = %q", text)
+	}
+}
+
+func TestDiagnoseJob(t *testing.T) {
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(
+		failedJob(), testCronJob(),
+		failedJobPod("backup-28000000-a", 137, "OOMKilled"),
+		failedJobPod("backup-28000000-b", 137, "OOMKilled"),
+		failedJobPod("backup-28000000-c", 1, "Error"),
+		warningEvent("e1", "Job", "backup-28000000", "BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+	)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"job_name": "backup-28000000"}
+	result, err := s.DiagnoseJobHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("DiagnoseJobHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Backoff limit: 2, failed attempts: 3",
+		"reached its backoff limit of 2",
+		"exited with code 137 (OOMKilled) (2 pod(s): backup-28000000-a, backup-28000000-b)",
+		"raise its memory limit",
+		"exited with code 1 (Error) (1 pod(s): backup-28000000-c)",
+		"get_pod_logs pod_name=backup-28000000-c",
+		`CronJob: backup`,
+		"concurrency policy: Forbid",
+		"Found 3 issue(s)",
+		"BackoffLimitExceeded",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("diagnose_job output missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	buildPodAnnotation       = "openshift.io/build.pod-name"
+	buildConfigLabel         = "openshift.io/build-config.name"
+	defaultBuildLogTailLines = 100
+	recentBuildsLimit        = 5
+)
+
+var (
+	buildConfigsGVR = schema.GroupVersionResource{Group: "build.openshift.io", Version: "v1", Resource: "buildconfigs"}
+	buildsGVR       = schema.GroupVersionResource{Group: "build.openshift.io", Version: "v1", Resource: "builds"}
+)
+
+// buildProducer is a BuildConfig with its latest build
+type buildProducer struct {
+	Name      string
+	Strategy  string
+	Source    string
+	Output    string
+	LastBuild string
+	Phase     string
+}
+
+func (p buildProducer) describe() string {
+	result := fmt.Sprintf("• BuildConfig %s - %s strategy, %s\n", p.Name, valueOrNone(p.Strategy), valueOrNone(p.Source))
+	if p.LastBuild == "" {
+		result += "   No builds yet; start one with start_build\n"
+	} else {
+		result += fmt.Sprintf("   Latest build: %s %s %s\n", buildPhaseIcon(p.Phase), p.LastBuild, valueOrNone(p.Phase))
+	}
+	return result
+}
+
+func buildPhaseIcon(phase string) string {
+	switch phase {
+	case "Complete":
+		return "✅"
+	case "Failed", "Error", "Cancelled":
+		return "❌"
+	case "New", "Pending", "Running":
+		return "🔄"
+	}
+	return "❔"
+}
+
+// buildSource describes where a BuildConfig builds from
+func buildSource(bc *unstructured.Unstructured) string {
+	sourceType, _, _ := unstructured.NestedString(bc.Object, "spec", "source", "type")
+	switch sourceType {
+	case "Git":
+		uri, _, _ := unstructured.NestedString(bc.Object, "spec", "source", "git", "uri")
+		source := "git " + uri
+		if ref, _, _ := unstructured.NestedString(bc.Object, "spec", "source", "git", "ref"); ref != "" {
+			source += "@" + ref
+		}
+		if dir, _, _ := unstructured.NestedString(bc.Object, "spec", "source", "contextDir"); dir != "" {
+			source += " (" + dir + ")"
+		}
+		return source
+	case "":
+		return ""
+	}
+	return strings.ToLower(sourceType) + " source"
+}
+
+// buildOutput returns the namespace and ImageStreamTag or image a
+// BuildConfig pushes to
+func buildOutput(bc *unstructured.Unstructured) (string, string, string) {
+	kind, _, _ := unstructured.NestedString(bc.Object, "spec", "output", "to", "kind")
+	name, _, _ := unstructured.NestedString(bc.Object, "spec", "output", "to", "name")
+	namespace, _, _ := unstructured.NestedString(bc.Object, "spec", "output", "to", "namespace")
+	if namespace == "" {
+		namespace = bc.GetNamespace()
+	}
+	return kind, namespace, name
+}
+
+func (s *Server) buildProducerFor(ctx context.Context, bc *unstructured.Unstructured) buildProducer {
+	producer := buildProducer{Name: bc.GetName(), Source: buildSource(bc)}
+	producer.Strategy, _, _ = unstructured.NestedString(bc.Object, "spec", "strategy", "type")
+	kind, namespace, name := buildOutput(bc)
+	if name != "" {
+		producer.Output = fmt.Sprintf("%s %s/%s", kind, namespace, name)
+	}
+	if lastVersion, _, _ := unstructured.NestedInt64(bc.Object, "status", "lastVersion"); lastVersion > 0 {
+		producer.LastBuild = fmt.Sprintf("%s-%d", bc.GetName(), lastVersion)
+		if build, err := s.dynamicClient.Resource(buildsGVR).Namespace(bc.GetNamespace()).Get(ctx, producer.LastBuild, metav1.GetOptions{}); err == nil {
+			producer.Phase, _, _ = unstructured.NestedString(build.Object, "status", "phase")
+		}
+	}
+	return producer
+}
+
+// imageProducers finds the BuildConfigs that push to an image stream tag
+func (s *Server) imageProducers(ctx context.Context, ref internalImage) ([]buildProducer, error) {
+	list, err := s.dynamicClient.Resource(buildConfigsGVR).Namespace(ref.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var producers []buildProducer
+	for i := range list.Items {
+		kind, namespace, name := buildOutput(&list.Items[i])
+		if kind == "ImageStreamTag" && namespace == ref.Namespace && name == ref.Stream+":"+ref.Tag {
+			producers = append(producers, s.buildProducerFor(ctx, &list.Items[i]))
+		}
+	}
+	return producers, nil
+}
+
+// buildDuration is how long a build ran, or has been running
+func buildDuration(build *unstructured.Unstructured) time.Duration {
+	started, _, _ := unstructured.NestedString(build.Object, "status", "startTimestamp")
+	completed, _, _ := unstructured.NestedString(build.Object, "status", "completionTimestamp")
+	start, end := parseTime(started), parseTime(completed)
+	if start.IsZero() {
+		return 0
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(start).Round(time.Second)
+}
+
+func (s *Server) initBuildConfigs() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_buildconfigs",
+			mcp.WithDescription("List OpenShift BuildConfigs with their strategy, source, output image and latest build; with name, also list its recent builds"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list build configs from (default: default)")),
+			mcp.WithString("name", mcp.Description("Show one build config with its recent builds")),
+			mcp.WithTitleAnnotation("Builds: List BuildConfigs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listBuildConfigsHandler)},
+		{Tool: mcp.NewTool("start_build",
+			mcp.WithDescription("Start a new build from a BuildConfig, optionally of a specific Git commit"),
+			mcp.WithString("buildconfig_name", mcp.Description("Name of the build config"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the build config"), mcp.Required()),
+			mcp.WithString("commit", mcp.Description("Git commit to build instead of the configured ref")),
+			mcp.WithTitleAnnotation("Builds: Start Build"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.startBuildHandler)},
+		{Tool: mcp.NewTool("get_build_logs",
+			mcp.WithDescription("Show a build's status, failure reason and the logs of each step of its build pod (clone, Dockerfile, build and push)"),
+			mcp.WithString("build_name", mcp.Description("Name of the build, e.g. api-3"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the build (default: default)")),
+			mcp.WithString("tail_lines", mcp.Description(fmt.Sprintf("Lines to show per step (default %d)", defaultBuildLogTailLines))),
+			mcp.WithTitleAnnotation("Builds: Get Build Logs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getBuildLogsHandler)},
+	}
+}
+
+func (s *Server) listBuildConfigsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+	if name := strings.TrimSpace(mcp.ParseString(request, "name", "")); name != "" {
+		return s.describeBuildConfig(ctx, namespace, name)
+	}
+
+	list, err := s.dynamicClient.Resource(buildConfigsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ BuildConfigs are not available; this does not look like an OpenShift cluster"), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to list build configs in namespace %s", namespace), err), nil
+	}
+	configs := list.Items
+	sort.Slice(configs, func(i, j int) bool { return configs[i].GetName() < configs[j].GetName() })
+
+	result := "🏗️  BuildConfig List Results\n"
+	result += "===========================\n\n"
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("📦 Found %d build configs:\n", len(configs))
+	for i := range configs {
+		producer := s.buildProducerFor(ctx, &configs[i])
+		result += producer.describe()
+		if producer.Output != "" {
+			result += fmt.Sprintf("   Output: %s\n", producer.Output)
+		}
+	}
+	if len(configs) == 0 {
+		result += "📭 None found\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// describeBuildConfig shows one build config and its recent builds
+func (s *Server) describeBuildConfig(ctx context.Context, namespace, name string) (*mcp.CallToolResult, error) {
+	bc, err := s.dynamicClient.Resource(buildConfigsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ BuildConfig %s not found in namespace %s", name, namespace)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get build config %s", name), err), nil
+	}
+	producer := s.buildProducerFor(ctx, bc)
+
+	result := fmt.Sprintf("🏗️  BuildConfig: %s/%s\n", namespace, name)
+	result += "=====================\n\n"
+	result += fmt.Sprintf("Strategy: %s\n", valueOrNone(producer.Strategy))
+	result += fmt.Sprintf("Source: %s\n", valueOrNone(producer.Source))
+	result += fmt.Sprintf("Output: %s\n", valueOrNone(producer.Output))
+
+	builds, err := s.dynamicClient.Resource(buildsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: buildConfigLabel + "=" + name})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list builds of %s", name), err), nil
+	}
+	items := builds.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].GetCreationTimestamp().After(items[j].GetCreationTimestamp().Time)
+	})
+	result += fmt.Sprintf("\n📋 Recent builds (%d):\n", len(items))
+	for i := range items {
+		if i == recentBuildsLimit {
+			result += fmt.Sprintf("... %d older builds\n", len(items)-recentBuildsLimit)
+			break
+		}
+		build := &items[i]
+		phase, _, _ := unstructured.NestedString(build.Object, "status", "phase")
+		result += fmt.Sprintf("%s %s %s, age %s", buildPhaseIcon(phase), build.GetName(), valueOrNone(phase), formatAge(build.GetCreationTimestamp().Time))
+		if duration := buildDuration(build); duration > 0 {
+			result += fmt.Sprintf(", took %s", duration)
+		}
+		if reason, _, _ := unstructured.NestedString(build.Object, "status", "reason"); reason != "" {
+			result += fmt.Sprintf(" (%s)", reason)
+		}
+		result += "\n"
+	}
+	if len(items) == 0 {
+		result += "📭 No builds yet\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) startBuildHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "buildconfig_name", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	commit := strings.TrimSpace(mcp.ParseString(request, "commit", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ buildconfig_name is required"), nil
+	}
+
+	buildRequest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "build.openshift.io/v1",
+		"kind":       "BuildRequest",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+	}}
+	if commit != "" {
+		unstructured.SetNestedField(buildRequest.Object, "Git", "revision", "type")
+		unstructured.SetNestedField(buildRequest.Object, commit, "revision", "git", "commit")
+	}
+
+	build, err := s.dynamicClient.Resource(buildConfigsGVR).Namespace(namespace).Create(ctx, buildRequest, metav1.CreateOptions{}, "instantiate")
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ BuildConfig %s not found in namespace %s", name, namespace)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to start a build of %s", name), err), nil
+	}
+	s.recordAction(ctx, "start_build", objectReference("build.openshift.io/v1", "Build", build), ReasonCreated, "Started Build")
+
+	phase, _, _ := unstructured.NestedString(build.Object, "status", "phase")
+	result := "🏗️  Build Started\n"
+	result += "=================\n\n"
+	result += fmt.Sprintf("Build: %s\n", build.GetName())
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("BuildConfig: %s\n", name)
+	if commit != "" {
+		result += fmt.Sprintf("Commit: %s\n", commit)
+	}
+	result += fmt.Sprintf("Phase: %s\n", valueOrNone(phase))
+	result += fmt.Sprintf("\n💡 Follow it with get_build_logs build_name=%s namespace=%s", build.GetName(), namespace)
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) getBuildLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil || s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "build_name", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	if name == "" {
+		return mcp.NewToolResultText("❌ build_name is required"), nil
+	}
+	tailStr := mcp.ParseString(request, "tail_lines", strconv.Itoa(defaultBuildLogTailLines))
+	tailLines, err := strconv.ParseInt(tailStr, 10, 64)
+	if err != nil || tailLines <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid tail_lines value: %s", tailStr)), nil
+	}
+	if tailLines > maxPodLogTailLines {
+		tailLines = maxPodLogTailLines
+	}
+
+	build, err := s.dynamicClient.Resource(buildsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Build %s not found in namespace %s. Use list_buildconfigs name=<buildconfig> to see its builds.", name, namespace)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get build %s", name), err), nil
+	}
+	phase, _, _ := unstructured.NestedString(build.Object, "status", "phase")
+	reason, _, _ := unstructured.NestedString(build.Object, "status", "reason")
+	message, _, _ := unstructured.NestedString(build.Object, "status", "message")
+	snippet, _, _ := unstructured.NestedString(build.Object, "status", "logSnippet")
+
+	result := "📜 Build Logs\n"
+	result += "=============\n\n"
+	result += fmt.Sprintf("Build: %s\n", name)
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("Status: %s %s", buildPhaseIcon(phase), valueOrNone(phase))
+	if duration := buildDuration(build); duration > 0 {
+		result += fmt.Sprintf(" after %s", duration)
+	}
+	result += "\n"
+	if commit, _, _ := unstructured.NestedString(build.Object, "spec", "revision", "git", "commit"); commit != "" {
+		result += fmt.Sprintf("Commit: %s\n", commit)
+	}
+	if output, _, _ := unstructured.NestedString(build.Object, "status", "outputDockerImageReference"); output != "" {
+		result += fmt.Sprintf("Output image: %s\n", output)
+	}
+	if reason != "" || message != "" {
+		result += fmt.Sprintf("\n❌ %s: %s\n", valueOrNone(reason), message)
+	}
+	if snippet != "" {
+		result += fmt.Sprintf("\n✂️  Log snippet:\n```\n%s\n```\n", strings.TrimRight(snippet, "\n"))
+	}
+
+	podName := build.GetAnnotations()[buildPodAnnotation]
+	if podName == "" {
+		podName = name + "-build"
+	}
+	pod, err := s.k8sClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			result += fmt.Sprintf("\nℹ️  Build pod %s no longer exists; only the status above is available", podName)
+			return mcp.NewToolResultText(result), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get build pod %s", podName), err), nil
+	}
+
+	// Each build step runs in its own container: git-clone and
+	// manage-dockerfile as init containers, then the build and push
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		result += fmt.Sprintf("\n🔧 Step %s", status.Name)
+		switch {
+		case status.State.Waiting != nil:
+			result += fmt.Sprintf(": not started (%s)\n", status.State.Waiting.Reason)
+			continue
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			result += fmt.Sprintf(": ❌ exited %d (%s)\n", status.State.Terminated.ExitCode, status.State.Terminated.Reason)
+		case status.State.Terminated != nil:
+			result += ": ✅ done\n"
+		default:
+			result += ": 🔄 running\n"
+		}
+		opts := &corev1.PodLogOptions{Container: status.Name, TailLines: &tailLines}
+		stream, err := s.k8sClient.CoreV1().Pods(namespace).GetLogs(podName, opts).Stream(ctx)
+		if err != nil {
+			result += fmt.Sprintf("⚠️  Could not get logs: %v\n", err)
+			continue
+		}
+		log, err := readBoundedLog(stream, maxPodLogBytes/len(statuses))
+		stream.Close()
+		if err != nil {
+			result += fmt.Sprintf("⚠️  Could not read logs: %v\n", err)
+			continue
+		}
+		if log.omitted > 0 {
+			result += fmt.Sprintf("⚠️  %d older lines omitted\n", log.omitted)
+		}
+		if len(log.lines) > 0 {
+			result += fmt.Sprintf("```\n%s\n```\n", strings.Join(log.lines, "\n"))
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ListBuildConfigsHandler is a public wrapper for listBuildConfigsHandler
+func (s *Server) ListBuildConfigsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listBuildConfigsHandler(ctx, request)
+}
+
+// StartBuildHandler is a public wrapper for startBuildHandler
+func (s *Server) StartBuildHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.startBuildHandler(ctx, request)
+}
+
+// GetBuildLogsHandler is a public wrapper for getBuildLogsHandler
+func (s *Server) GetBuildLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getBuildLogsHandler(ctx, request)
+}
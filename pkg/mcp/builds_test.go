@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newBuildTestServer(objects ...runtime.Object) *Server {
+	listKinds := map[schema.GroupVersionResource]string{buildConfigsGVR: "BuildConfigList", buildsGVR: "BuildList"}
+	return &Server{
+		config:        &Config{},
+		k8sClient:     kubefake.NewSimpleClientset(),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...),
+	}
+}
+
+func TestGetBuildLogs(t *testing.T) {
+	build := newUnstructured("build.openshift.io/v1", "Build", "shop", "api-3")
+	build.SetAnnotations(map[string]string{buildPodAnnotation: "api-3-build"})
+	unstructured.SetNestedMap(build.Object, map[string]interface{}{
+		"phase": "Failed", "reason": "PushImageToRegistryFailed", "message": "Failed to push the image to the registry.",
+		"logSnippet": "error: build error: Failed to push image: unauthorized",
+	}, "status")
+
+	s := newBuildTestServer(build)
+	s.k8sClient = kubefake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-3-build", Namespace: "shop"},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "git-clone", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "sti-build", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+			},
+		},
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"build_name": "api-3", "namespace": "shop"}
+	result, err := s.GetBuildLogsHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GetBuildLogsHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Status: ❌ Failed",
+		"❌ PushImageToRegistryFailed: Failed to push the image to the registry.",
+		"error: build error: Failed to push image: unauthorized",
+		"🔧 Step git-clone: ✅ done",
+		"🔧 Step sti-build: ❌ exited 1 (Error)",
+		"fake logs",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("get_build_logs output missing %q:\n%s", want, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"build_name": "api-4", "namespace": "shop"}
+	result, _ = s.GetBuildLogsHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌ Build api-4 not found") {
+		t.Errorf("get_build_logs for a missing build = %q", text)
+	}
+}
+
+func TestStartBuild(t *testing.T) {
+	s := newBuildTestServer()
+	var instantiated *unstructured.Unstructured
+	s.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("create", "buildconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create := action.(k8stesting.CreateAction)
+		if create.GetSubresource() != "instantiate" {
+			return false, nil, nil
+		}
+		instantiated = create.GetObject().(*unstructured.Unstructured)
+		build := newUnstructured("build.openshift.io/v1", "Build", "shop", "api-4")
+		unstructured.SetNestedField(build.Object, "New", "status", "phase")
+		return true, build, nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"buildconfig_name": "api", "namespace": "shop", "commit": "4f2a9c1"}
+	result, err := s.StartBuildHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("StartBuildHandler error = %v", err)
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "Build: api-4") || !strings.Contains(text, "get_build_logs build_name=api-4 namespace=shop") {
+		t.Errorf("start_build output = %q", text)
+	}
+	if commit, _, _ := unstructured.NestedString(instantiated.Object, "revision", "git", "commit"); instantiated.GetKind() != "BuildRequest" || commit != "4f2a9c1" {
+		t.Errorf("instantiate request = %v", instantiated.Object)
+	}
+}
@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Version is the server version, set at build time with
+// -ldflags "-X github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp.Version=..."
+var Version = "1.0.0"
+
+// CapabilitiesSchemaVersion is bumped when the server_capabilities JSON
+// layout changes incompatibly, so clients can tell which fields to expect
+const CapabilitiesSchemaVersion = 1
+
+const (
+	PolicyReadWrite = "read-write"
+	PolicyReadOnly  = "read-only"
+)
+
+// ServerCapabilities describes what this server instance offers
+type ServerCapabilities struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Name          string              `json:"name"`
+	Version       string              `json:"version"`
+	Profile       string              `json:"profile"`
+	Profiles      []ProfileCapability `json:"profiles"`
+	PolicyMode    string              `json:"policy_mode"`
+	Tools         []ToolCapability    `json:"tools"`
+	Deprecations  []ToolDeprecation   `json:"deprecations"`
+	Integrations  []Integration       `json:"integrations"`
+}
+
+// ProfileCapability is a profile the server can run with
+type ProfileCapability struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+}
+
+// ToolCapability is a registered tool and its safety hints
+type ToolCapability struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description"`
+	ReadOnly    bool     `json:"read_only"`
+	Destructive bool     `json:"destructive"`
+	Enabled     bool     `json:"enabled"`            // false when the policy mode blocks the tool
+	Aliases     []string `json:"aliases,omitempty"`  // deprecated names that still call this tool
+	ToolSet     string   `json:"tool_set,omitempty"` // runtime tool set the tool was registered with
+}
+
+// Integration is an external system the server can talk to
+type Integration struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// toolReadOnly reports whether a tool declares that it does not modify anything
+func toolReadOnly(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+// toolDestructive follows the MCP default: a tool that is not read-only is
+// destructive unless it says otherwise
+func toolDestructive(tool mcp.Tool) bool {
+	if toolReadOnly(tool) {
+		return false
+	}
+	return tool.Annotations.DestructiveHint == nil || *tool.Annotations.DestructiveHint
+}
+
+// policyMode returns the policy applied to tool calls
+func (s *Server) policyMode() string {
+	if s.config != nil && s.config.ReadOnly {
+		return PolicyReadOnly
+	}
+	return PolicyReadWrite
+}
+
+// withPolicy refuses calls the policy mode does not allow. In read-only mode
+// only tools registered with a read-only hint may run; during a change
+// freeze other tools need the freeze's approval token.
+func (s *Server) withPolicy(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.toolsMu.RLock()
+		tool, ok := s.toolDefs[name]
+		s.toolsMu.RUnlock()
+		if !ok || !toolReadOnly(tool) {
+			if s.policyMode() == PolicyReadOnly {
+				message := fmt.Sprintf("%s is not allowed: the server runs in read-only mode", name)
+				return withErrorKind(mcp.NewToolResultText("🔒 "+message), ErrorForbidden, message), nil
+			}
+			if refusal := s.checkChangeFreeze(ctx, name, tool, request); refusal != nil {
+				return refusal, nil
+			}
+		}
+		return handler(ctx, request)
+	}
+}
+
+// Capabilities describes the server version, profiles, tools and integrations
+func (s *Server) Capabilities() ServerCapabilities {
+	caps := ServerCapabilities{
+		SchemaVersion: CapabilitiesSchemaVersion,
+		Name:          "OpenShift MCP",
+		Version:       Version,
+		PolicyMode:    s.policyMode(),
+	}
+	if s.config != nil {
+		caps.Profile = ProfileFromString(s.config.Profile).GetName()
+	}
+	for _, profile := range Profiles {
+		caps.Profiles = append(caps.Profiles, ProfileCapability{
+			Name:        profile.GetName(),
+			Description: profile.GetDescription(),
+			Active:      profile.GetName() == caps.Profile,
+		})
+	}
+
+	caps.Deprecations = s.activeDeprecations()
+	aliases := make(map[string][]string)
+	for _, deprecation := range caps.Deprecations {
+		aliases[deprecation.ReplacedBy] = append(aliases[deprecation.ReplacedBy], deprecation.Name)
+	}
+	toolSets := make(map[string]string)
+	s.toolsMu.RLock()
+	for _, set := range s.toolSets {
+		for _, name := range set.Tools {
+			toolSets[name] = set.Name
+		}
+	}
+	for _, name := range sortedKeys(s.toolDefs) {
+		tool := s.toolDefs[name]
+		caps.Tools = append(caps.Tools, ToolCapability{
+			Name:        name,
+			Title:       tool.Annotations.Title,
+			Description: tool.Description,
+			ReadOnly:    toolReadOnly(tool),
+			Destructive: toolDestructive(tool),
+			Enabled:     caps.PolicyMode != PolicyReadOnly || toolReadOnly(tool),
+			Aliases:     aliases[name],
+			ToolSet:     toolSets[name],
+		})
+	}
+	s.toolsMu.RUnlock()
+
+	caps.Integrations = append(caps.Integrations,
+		Integration{Name: "kubernetes", Enabled: s.k8sClient != nil},
+		Integration{Name: "dynamic-resources", Enabled: s.dynamicClient != nil && s.restMapper != nil},
+	)
+	git := Integration{Name: "git", Enabled: s.gitManager != nil && s.gitManager.IsEnabled()}
+	if git.Enabled && s.gitManager.config.RemoteURL != "" {
+		git.Detail = "remote " + s.gitManager.config.RemoteURL
+	}
+	caps.Integrations = append(caps.Integrations, git,
+		Integration{Name: "llm", Enabled: s.llmProbe != nil})
+	notifications := Integration{Name: "notifications", Enabled: s.notifier != nil && s.notifier.Configured()}
+	caps.Integrations = append(caps.Integrations, notifications)
+	if s.scheduler != nil {
+		var jobs []string
+		for _, job := range s.scheduler.Jobs() {
+			jobs = append(jobs, fmt.Sprintf("%s every %s", job.Name, job.Interval))
+		}
+		sort.Strings(jobs)
+		caps.Integrations = append(caps.Integrations, Integration{
+			Name: "scheduled-jobs", Enabled: len(jobs) > 0, Detail: strings.Join(jobs, ", "),
+		})
+	}
+	return caps
+}
+
+func (s *Server) serverCapabilitiesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	caps := s.Capabilities()
+
+	switch format := strings.ToLower(mcp.ParseString(request, "format", "text")); format {
+	case "json":
+		data, err := json.MarshalIndent(caps, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to encode capabilities: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	case "text":
+	default:
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Unsupported format %q, use text or json", format)), nil
+	}
+
+	result := "🧭 MCP Server Capabilities\n"
+	result += "==========================\n\n"
+	result += fmt.Sprintf("Server: %s %s (capabilities schema v%d)\n", caps.Name, caps.Version, caps.SchemaVersion)
+	result += fmt.Sprintf("Policy mode: %s\n", caps.PolicyMode)
+
+	result += "\n👤 Profiles:\n"
+	for _, profile := range caps.Profiles {
+		marker := "•"
+		if profile.Active {
+			marker = "▶️"
+		}
+		result += fmt.Sprintf("%s %s - %s\n", marker, profile.Name, profile.Description)
+	}
+
+	destructive := 0
+	for _, tool := range caps.Tools {
+		if tool.Destructive {
+			destructive++
+		}
+	}
+	result += fmt.Sprintf("\n🛠️  Tools (%d registered, %d destructive):\n", len(caps.Tools), destructive)
+	for _, tool := range caps.Tools {
+		icon := "✏️ "
+		switch {
+		case !tool.Enabled:
+			icon = "🔒"
+		case tool.ReadOnly:
+			icon = "👁️ "
+		case tool.Destructive:
+			icon = "⚠️ "
+		}
+		line := fmt.Sprintf("%s %s", icon, tool.Name)
+		if tool.ToolSet != "" {
+			line += fmt.Sprintf(" [tool set %s]", tool.ToolSet)
+		}
+		result += line + "\n"
+	}
+	result += "   (👁️  read-only, ✏️  modifies without destroying, ⚠️  destructive, 🔒 blocked by policy)\n"
+
+	if len(caps.Deprecations) > 0 {
+		result += fmt.Sprintf("\n🔀 Deprecated names (%d, still callable):\n", len(caps.Deprecations))
+		for _, deprecation := range caps.Deprecations {
+			result += fmt.Sprintf("• %s → %s (removed in %s)\n", deprecation.Name, deprecation.ReplacedBy, deprecation.RemovedIn)
+		}
+	}
+
+	result += "\n🔗 Integrations:\n"
+	for _, integration := range caps.Integrations {
+		icon := "⚪"
+		if integration.Enabled {
+			icon = "🟢"
+		}
+		result += fmt.Sprintf("%s %s", icon, integration.Name)
+		if integration.Detail != "" {
+			result += fmt.Sprintf(" (%s)", integration.Detail)
+		}
+		result += "\n"
+	}
+	result += "\n💡 Use format=json for a machine-readable listing"
+	return mcp.NewToolResultText(result), nil
+}
+
+// ServerCapabilitiesHandler is a public wrapper for serverCapabilitiesHandler
+func (s *Server) ServerCapabilitiesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.serverCapabilitiesHandler(ctx, request)
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolSafetyHints(t *testing.T) {
+	tests := []struct {
+		tool        mcp.Tool
+		readOnly    bool
+		destructive bool
+	}{
+		{mcp.NewTool("get", mcp.WithReadOnlyHintAnnotation(true)), true, false},
+		{mcp.NewTool("delete", mcp.WithDestructiveHintAnnotation(true)), false, true},
+		{mcp.NewTool("forward", mcp.WithDestructiveHintAnnotation(false)), false, false},
+		{mcp.NewTool("unannotated"), false, true},
+	}
+
+	for _, tt := range tests {
+		if readOnly, destructive := toolReadOnly(tt.tool), toolDestructive(tt.tool); readOnly != tt.readOnly || destructive != tt.destructive {
+			t.Errorf("%s: read-only %v, destructive %v, expected %v, %v", tt.tool.Name, readOnly, destructive, tt.readOnly, tt.destructive)
+		}
+	}
+}
+
+// newPolicyTestServer registers a read-only and a mutating tool
+func newPolicyTestServer(readOnly bool) *Server {
+	s := &Server{config: &Config{Profile: "sre", ReadOnly: readOnly}}
+	s.initResilience(nil)
+	ok := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	s.tools = make(map[string]server.ToolHandlerFunc)
+	s.toolDefs = make(map[string]mcp.Tool)
+	for _, tool := range []mcp.Tool{
+		mcp.NewTool("list_pods", mcp.WithReadOnlyHintAnnotation(true)),
+		mcp.NewTool("delete_resource", mcp.WithDestructiveHintAnnotation(true)),
+	} {
+		s.toolDefs[tool.Name] = tool
+		s.tools[tool.Name] = s.withPolicy(tool.Name, s.withResilience(tool.Name, ok))
+	}
+	return s
+}
+
+func TestReadOnlyPolicy(t *testing.T) {
+	tests := []struct {
+		readOnly bool
+		tool     string
+		expected string
+	}{
+		{false, "list_pods", "ok"},
+		{false, "delete_resource", "ok"},
+		{true, "list_pods", "ok"},
+		{true, "delete_resource", "🔒 delete_resource is not allowed: the server runs in read-only mode"},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tt.tool
+		result, err := newPolicyTestServer(tt.readOnly).CallTool(context.Background(), request)
+		if err != nil || resultText(result) != tt.expected {
+			t.Errorf("CallTool(%s) with read-only %v = %q, %v, expected %q", tt.tool, tt.readOnly, resultText(result), err, tt.expected)
+		}
+	}
+
+	// Handlers outside the profile are refused too, since their safety is unknown
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "apply_yaml"
+	result, _ := newPolicyTestServer(true).CallWithResilience(context.Background(), request,
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("applied"), nil
+		})
+	if text := resultText(result); !strings.HasPrefix(text, "🔒 apply_yaml") {
+		t.Errorf("CallWithResilience(apply_yaml) in read-only mode = %q", text)
+	}
+}
+
+func TestServerCapabilities(t *testing.T) {
+	s := newPolicyTestServer(true)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"format": "json"}
+	result, _ := s.serverCapabilitiesHandler(context.Background(), request)
+	var caps ServerCapabilities
+	if err := json.Unmarshal([]byte(resultText(result)), &caps); err != nil {
+		t.Fatalf("server_capabilities JSON: %v\n%s", err, resultText(result))
+	}
+	if caps.SchemaVersion != CapabilitiesSchemaVersion || caps.Version != Version || caps.Profile != "sre" || caps.PolicyMode != PolicyReadOnly {
+		t.Errorf("capabilities header = %+v", caps)
+	}
+	expected := []ToolCapability{
+		{Name: "delete_resource", ReadOnly: false, Destructive: true, Enabled: false},
+		{Name: "list_pods", ReadOnly: true, Destructive: false, Enabled: true, Aliases: []string{"get_pods"}},
+	}
+	if len(caps.Tools) != len(expected) {
+		t.Fatalf("tools = %+v, expected %+v", caps.Tools, expected)
+	}
+	for i, tool := range caps.Tools {
+		if !reflect.DeepEqual(tool, expected[i]) {
+			t.Errorf("tool %d = %+v, expected %+v", i, tool, expected[i])
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{}
+	result, _ = s.serverCapabilitiesHandler(context.Background(), request)
+	text := resultText(result)
+	for _, want := range []string{"Policy mode: read-only", "▶️ sre", "Tools (2 registered, 1 destructive)", "🔒 delete_resource", "⚪ kubernetes", "• get_pods → list_pods (removed in 2.0.0)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("server_capabilities text missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// parseEvictionHard reads kubelet evictionHard thresholds written as
+// "memory.available<500Mi,nodefs.available<10%"; signals that are not
+// given keep their defaults
+func parseEvictionHard(value string) (diagnostics.EvictionThresholds, error) {
+	thresholds := diagnostics.DefaultEvictionThresholds()
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		signal, quantity, ok := strings.Cut(entry, "<")
+		if !ok {
+			return thresholds, fmt.Errorf("invalid threshold %q: expected signal<quantity, e.g. memory.available<500Mi", entry)
+		}
+		signal, quantity = strings.TrimSpace(signal), strings.TrimSpace(quantity)
+
+		if signal == "memory.available" {
+			parsed, err := resource.ParseQuantity(quantity)
+			if err != nil || strings.HasSuffix(quantity, "%") {
+				return thresholds, fmt.Errorf("invalid memory.available %q: expected a quantity, e.g. 500Mi", quantity)
+			}
+			thresholds.MemoryAvailable = parsed.Value()
+			continue
+		}
+
+		percentText, isPercent := strings.CutSuffix(quantity, "%")
+		share, err := strconv.ParseFloat(percentText, 64)
+		if !isPercent || err != nil || share < 0 || share > 100 {
+			return thresholds, fmt.Errorf("invalid %s %q: expected a percentage, e.g. 10%%", signal, quantity)
+		}
+		switch signal {
+		case "nodefs.available":
+			thresholds.NodefsAvailable = share / 100
+		case "imagefs.available":
+			thresholds.ImagefsAvailable = share / 100
+		case "nodefs.inodesFree":
+			thresholds.NodefsInodesFree = share / 100
+		default:
+			return thresholds, fmt.Errorf("unknown eviction signal %q (expected memory.available, nodefs.available, imagefs.available or nodefs.inodesFree)", signal)
+		}
+	}
+	return thresholds, nil
+}
+
+// nodeCapacity describes a node for the capacity analysis from its
+// conditions, taints and the requests of the pods scheduled on it
+func nodeCapacity(node *corev1.Node) diagnostics.NodeCapacity {
+	ready, _ := nodeCondition(node, corev1.NodeReady)
+	capacity := diagnostics.NodeCapacity{
+		Name:              node.Name,
+		Ready:             ready == corev1.ConditionTrue,
+		Unschedulable:     node.Spec.Unschedulable,
+		Pressures:         nodePressures(node),
+		CapacityMemory:    node.Status.Capacity.Memory().Value(),
+		AllocatableCPU:    node.Status.Allocatable.Cpu().MilliValue(),
+		AllocatableMemory: node.Status.Allocatable.Memory().Value(),
+		AllocatablePods:   node.Status.Allocatable.Pods().Value(),
+	}
+	if capacity.CapacityMemory == 0 {
+		capacity.CapacityMemory = capacity.AllocatableMemory
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			capacity.Taints = append(capacity.Taints, formatTaint(taint))
+		}
+	}
+	return capacity
+}
+
+// podPlacement describes a Pending or evicted pod for the capacity analysis
+func podPlacement(pod *corev1.Pod) diagnostics.PodPlacement {
+	cpu, memory := podRequests(pod)
+	placement := diagnostics.PodPlacement{
+		Namespace:       pod.Namespace,
+		Name:            pod.Name,
+		Phase:           string(pod.Status.Phase),
+		Reason:          pod.Status.Reason,
+		Message:         pod.Status.Message,
+		NodeName:        pod.Spec.NodeName,
+		RequestedCPU:    cpu.MilliValue(),
+		RequestedMemory: memory.Value(),
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			placement.SchedulingMessage = condition.Message
+		}
+	}
+	return placement
+}
+
+func (s *Server) initCapacityTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("analyze_node_capacity",
+			mcp.WithDescription("Explain why pods are Pending or were evicted by combining node conditions, allocatable versus requested CPU and memory, the scheduler's reasons and how close each node's memory is to the kubelet eviction threshold"),
+			mcp.WithString("namespace", mcp.Description("Only explain the Pending and evicted pods of this namespace (default: all namespaces)")),
+			mcp.WithString("eviction_hard", mcp.Description("Kubelet evictionHard thresholds when a KubeletConfig changes them, e.g. memory.available<500Mi,nodefs.available<10% (default: the kubelet defaults)")),
+			mcp.WithTitleAnnotation("Nodes: Analyze Capacity"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.analyzeNodeCapacityHandler)},
+	}
+}
+
+func (s *Server) analyzeNodeCapacityHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	if namespace == "all" {
+		namespace = ""
+	}
+	thresholds, err := parseEvictionHard(mcp.ParseString(request, "eviction_hard", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	nodes, err := s.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return toolError(ctx, "Failed to list nodes", err), nil
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+	// Requests are summed over every namespace, since any pod on a node takes
+	// capacity from the pods being explained
+	pods, err := s.k8sClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return toolError(ctx, "Failed to list pods", err), nil
+	}
+
+	capacities := make([]diagnostics.NodeCapacity, 0, len(nodes.Items))
+	index := make(map[string]int, len(nodes.Items))
+	for i := range nodes.Items {
+		index[nodes.Items[i].Name] = len(capacities)
+		capacities = append(capacities, nodeCapacity(&nodes.Items[i]))
+	}
+
+	var placements []diagnostics.PodPlacement
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if n, ok := index[pod.Spec.NodeName]; ok && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			cpu, memory := podRequests(pod)
+			capacities[n].RequestedCPU += cpu.MilliValue()
+			capacities[n].RequestedMemory += memory.Value()
+			capacities[n].Pods++
+		}
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		if pod.Status.Reason == "Evicted" || (pod.Status.Phase == corev1.PodPending && pod.Spec.NodeName == "") {
+			placements = append(placements, podPlacement(pod))
+		}
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].Namespace != placements[j].Namespace {
+			return placements[i].Namespace < placements[j].Namespace
+		}
+		return placements[i].Name < placements[j].Name
+	})
+
+	response := "🖥️  Node Capacity Analysis\n"
+	response += "=========================\n\n"
+	if namespace != "" {
+		response += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	response += fmt.Sprintf("Eviction thresholds: memory.available<%s, nodefs.available<%.0f%%, imagefs.available<%.0f%%\n",
+		formatMemoryMi(*resource.NewQuantity(thresholds.MemoryAvailable, resource.BinarySI)), thresholds.NodefsAvailable*100, thresholds.ImagefsAvailable*100)
+
+	// Memory usage from the metrics API shows how close nodes are to eviction;
+	// without it the analysis still works from conditions and requests
+	if s.dynamicClient != nil {
+		metrics, metricsErr := s.dynamicClient.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
+		if metricsErr != nil {
+			recordToolError(ctx, metricsErr)
+			response += "⚠️  Node metrics unavailable, so memory headroom before eviction is not checked\n"
+		} else {
+			for i := range metrics.Items {
+				usage, _, _ := unstructured.NestedMap(metrics.Items[i].Object, "usage")
+				_, memory := parseUsage(usage)
+				if n, ok := index[metrics.Items[i].GetName()]; ok {
+					capacities[n].MemoryWorkingSet = memory.Value()
+				}
+			}
+		}
+	}
+
+	response += "\n📊 Nodes (requested / allocatable):\n"
+	for _, node := range capacities {
+		icon := "✅"
+		switch {
+		case !node.Ready:
+			icon = "❌"
+		case len(node.Pressures) > 0 || node.Unschedulable:
+			icon = "⚠️ "
+		}
+		line := fmt.Sprintf("%s %s - CPU %s / %s, memory %s / %s, pods %d / %d", icon, node.Name,
+			formatCPU(*resource.NewMilliQuantity(node.RequestedCPU, resource.DecimalSI)),
+			formatCPU(*resource.NewMilliQuantity(node.AllocatableCPU, resource.DecimalSI)),
+			formatMemory(*resource.NewQuantity(node.RequestedMemory, resource.BinarySI)),
+			formatMemory(*resource.NewQuantity(node.AllocatableMemory, resource.BinarySI)),
+			node.Pods, node.AllocatablePods)
+		if node.Unschedulable {
+			line += ", cordoned"
+		}
+		if len(node.Pressures) > 0 {
+			line += ", " + strings.Join(node.Pressures, ", ")
+		}
+		response += line + "\n"
+	}
+	if len(capacities) == 0 {
+		response += "📭 No nodes found\n"
+	}
+	response += "\n"
+
+	result := diagnostics.AnalyzeNodeCapacity(capacities, placements, thresholds, time.Now())
+	response += s.formatAnalysisResult(ctx, result)
+
+	// The analysis output lists resolutions only; the explanations of the
+	// affected pods are what this tool is for
+	var explained []string
+	for _, issue := range result.Issues {
+		if issue.Category == "scheduling" || issue.Category == "eviction" {
+			explained = append(explained, fmt.Sprintf("• %s: %s", issue.Location, issue.Description))
+		}
+	}
+	if len(explained) > 0 {
+		response += "\n🔎 Why:\n" + strings.Join(explained, "\n") + "\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// AnalyzeNodeCapacityHandler is a public wrapper for analyzeNodeCapacityHandler
+func (s *Server) AnalyzeNodeCapacityHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.analyzeNodeCapacityHandler(ctx, request)
+}
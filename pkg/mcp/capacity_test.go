@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseEvictionHard(t *testing.T) {
+	thresholds, err := parseEvictionHard("memory.available<500Mi, nodefs.available<5%")
+	if err != nil {
+		t.Fatalf("parseEvictionHard returned %v", err)
+	}
+	if thresholds.MemoryAvailable != 500*1024*1024 || thresholds.NodefsAvailable != 0.05 || thresholds.ImagefsAvailable != 0.15 {
+		t.Errorf("thresholds = %+v, expected 500Mi, 5%% and the default imagefs", thresholds)
+	}
+
+	for _, invalid := range []string{"memory.available=500Mi", "memory.available<10%", "nodefs.available<10Gi", "pid.available<10%"} {
+		if _, err := parseEvictionHard(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestAnalyzeNodeCapacity(t *testing.T) {
+	worker := usageNode("worker-1", "4", "16Gi")
+	worker.Status.Allocatable[corev1.ResourcePods] = resource.MustParse("250")
+	worker.Status.Conditions = []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+	}
+	cordoned := usageNode("worker-2", "4", "16Gi")
+	cordoned.Spec.Unschedulable = true
+	cordoned.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+
+	busy := usagePod("batch-1", "worker-1", "3500m", "4Gi", "")
+	busy.Namespace = "jobs"
+	pending := usagePod("web-2", "", "1", "256Mi", "")
+	pending.Status = corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type:    corev1.PodScheduled,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Unschedulable",
+			Message: "0/2 nodes are available: 1 Insufficient cpu, 1 node(s) were unschedulable. preemption: 0/2 nodes are available: 2 Preemption is not helpful for scheduling.",
+		}},
+	}
+	evicted := usagePod("cache-1", "worker-1", "100m", "1Gi", "")
+	evicted.Status = corev1.PodStatus{
+		Phase:   corev1.PodFailed,
+		Reason:  "Evicted",
+		Message: "The node was low on resource: memory. Threshold quantity: 100Mi, available: 51200Ki.",
+	}
+	elsewhere := usagePod("other-1", "", "1", "256Mi", "")
+	elsewhere.Namespace = "other"
+	elsewhere.Status = corev1.PodStatus{Phase: corev1.PodPending}
+
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(worker, cordoned, busy, pending, evicted, elsewhere)}
+	result, err := s.analyzeNodeCapacityHandler(context.Background(), usageRequest(map[string]interface{}{"namespace": "shop"}))
+	if err != nil {
+		t.Fatalf("analyze_node_capacity returned %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"worker-1 - CPU 3500m / 4000m",
+		"worker-2 - CPU 0m / 4000m, memory 0.0Gi / 16.0Gi, pods 0 / 0, cordoned",
+		"Pod shop/web-2 is Pending",
+		"1 nodes lack the 1 CPU the pod requests, the most left unrequested is 500m on worker-1",
+		"1 nodes are cordoned: worker-2",
+		"Pod shop/cache-1 was evicted",
+		"Node worker-1 has MemoryPressure",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("analyze_node_capacity output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "other-1") {
+		t.Errorf("pod outside the namespace explained:\n%s", text)
+	}
+
+	result, _ = s.analyzeNodeCapacityHandler(context.Background(), usageRequest(map[string]interface{}{"eviction_hard": "memory.available"}))
+	if text := resultText(result); !strings.Contains(text, "invalid threshold") {
+		t.Errorf("invalid eviction_hard = %s", text)
+	}
+}
@@ -0,0 +1,621 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// catalogStackFile describes a stack; the other YAML files of a stack
+// directory are its manifest templates
+const catalogStackFile = "stack.yaml"
+
+// CatalogParameter is a parameter of a catalog stack
+type CatalogParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// CatalogStack is a pre-approved application stack: manifest templates
+// using Go template syntax, e.g. {{ .name }}, and the parameters they take
+type CatalogStack struct {
+	Name        string             `json:"-"`
+	Description string             `json:"description"`
+	Parameters  []CatalogParameter `json:"parameters"`
+	Source      string             `json:"-"`
+	Files       map[string]string  `json:"-"`
+}
+
+// commonStackParameters are taken by every built-in stack
+var commonStackParameters = []CatalogParameter{
+	{Name: "name", Description: "Application name, used for every object", Required: true},
+	{Name: "namespace", Description: "Target namespace", Default: "default"},
+}
+
+// builtinCatalog holds the stacks available without a Git repository; a
+// stack of the same name in the repository's catalog/ replaces it
+var builtinCatalog = map[string]CatalogStack{
+	"web-app": {
+		Description: "Stateless web application: Deployment with resource requests and probes, Service, edge-terminated Route and CPU HorizontalPodAutoscaler",
+		Parameters:  append(append([]CatalogParameter{}, commonStackParameters...), webAppParameters...),
+		Files: map[string]string{
+			"deployment.yaml": webAppDeployment,
+			"service.yaml":    webAppService,
+			"route.yaml":      webAppRoute,
+			"hpa.yaml":        webAppHPA,
+		},
+	},
+	"web-app-postgres": {
+		Description: "The web-app stack plus a PostgreSQL StatefulSet with persistent storage, its Service and credentials Secret, wired into the application's environment",
+		Parameters: append(append(append([]CatalogParameter{}, commonStackParameters...), webAppParameters...),
+			CatalogParameter{Name: "db_password", Description: "PostgreSQL password", Required: true},
+			CatalogParameter{Name: "db_storage", Description: "Size of the database volume", Default: "5Gi"},
+			CatalogParameter{Name: "db_image", Description: "PostgreSQL image", Default: "registry.redhat.io/rhel9/postgresql-15:latest"},
+		),
+		Files: map[string]string{
+			"deployment.yaml":     strings.Replace(webAppDeployment, "{{- /* env */}}\n", postgresEnv, 1),
+			"service.yaml":        webAppService,
+			"route.yaml":          webAppRoute,
+			"hpa.yaml":            webAppHPA,
+			"db-secret.yaml":      postgresSecret,
+			"db-service.yaml":     postgresService,
+			"db-statefulset.yaml": postgresStatefulSet,
+		},
+	},
+	"batch-job": {
+		Description: "Scheduled batch job: CronJob that never runs concurrently, with a PersistentVolumeClaim mounted at /data for its output",
+		Parameters: append(append([]CatalogParameter{}, commonStackParameters...),
+			CatalogParameter{Name: "image", Description: "Job image", Required: true},
+			CatalogParameter{Name: "schedule", Description: "Cron schedule", Default: "0 2 * * *"},
+			CatalogParameter{Name: "command", Description: "Shell command to run (default: the image's entrypoint)"},
+			CatalogParameter{Name: "storage", Description: "Size of the data volume", Default: "1Gi"},
+		),
+		Files: map[string]string{
+			"pvc.yaml":     batchJobPVC,
+			"cronjob.yaml": batchJobCronJob,
+		},
+	},
+}
+
+var webAppParameters = []CatalogParameter{
+	{Name: "image", Description: "Container image", Required: true},
+	{Name: "port", Description: "Container port", Default: "8080"},
+	{Name: "replicas", Description: "Minimum replicas", Default: "2"},
+	{Name: "max_replicas", Description: "Maximum replicas of the autoscaler", Default: "5"},
+	{Name: "cpu_percent", Description: "Target average CPU utilization", Default: "80"},
+	{Name: "host", Description: "Route host (default: generated by the router)"},
+}
+
+const webAppDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .name }}
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}
+    created-by: openshift-mcp
+spec:
+  replicas: {{ .replicas }}
+  selector:
+    matchLabels:
+      app: {{ .name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .name }}
+    spec:
+      containers:
+      - name: {{ .name }}
+        image: {{ .image }}
+        ports:
+        - containerPort: {{ .port }}
+          protocol: TCP
+{{- /* env */}}
+        resources:
+          requests:
+            cpu: 100m
+            memory: 128Mi
+          limits:
+            memory: 512Mi
+        readinessProbe:
+          tcpSocket:
+            port: {{ .port }}
+          initialDelaySeconds: 5
+        livenessProbe:
+          tcpSocket:
+            port: {{ .port }}
+          initialDelaySeconds: 15
+`
+
+const webAppService = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .name }}
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}
+    created-by: openshift-mcp
+spec:
+  selector:
+    app: {{ .name }}
+  ports:
+  - name: http
+    port: {{ .port }}
+    targetPort: {{ .port }}
+`
+
+const webAppRoute = `apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: {{ .name }}
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}
+    created-by: openshift-mcp
+spec:
+{{- if .host }}
+  host: {{ .host }}
+{{- end }}
+  to:
+    kind: Service
+    name: {{ .name }}
+  port:
+    targetPort: http
+  tls:
+    termination: edge
+    insecureEdgeTerminationPolicy: Redirect
+`
+
+const webAppHPA = `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: {{ .name }}
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}
+    created-by: openshift-mcp
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: {{ .name }}
+  minReplicas: {{ .replicas }}
+  maxReplicas: {{ .max_replicas }}
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        type: Utilization
+        averageUtilization: {{ .cpu_percent }}
+`
+
+// postgresEnv connects the web-app deployment to the database
+const postgresEnv = `        env:
+        - name: DATABASE_HOST
+          value: {{ .name }}-db
+        - name: DATABASE_NAME
+          value: {{ .name }}
+        - name: DATABASE_USER
+          valueFrom:
+            secretKeyRef:
+              name: {{ .name }}-db
+              key: database-user
+        - name: DATABASE_PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: {{ .name }}-db
+              key: database-password
+`
+
+const postgresSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .name }}-db
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}-db
+    created-by: openshift-mcp
+type: Opaque
+stringData:
+  database-user: {{ .name }}
+  database-password: {{ printf "%q" .db_password }}
+`
+
+const postgresService = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .name }}-db
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}-db
+    created-by: openshift-mcp
+spec:
+  selector:
+    app: {{ .name }}-db
+  ports:
+  - name: postgresql
+    port: 5432
+    targetPort: 5432
+`
+
+const postgresStatefulSet = `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .name }}-db
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}-db
+    created-by: openshift-mcp
+spec:
+  serviceName: {{ .name }}-db
+  replicas: 1
+  selector:
+    matchLabels:
+      app: {{ .name }}-db
+  template:
+    metadata:
+      labels:
+        app: {{ .name }}-db
+    spec:
+      containers:
+      - name: postgresql
+        image: {{ .db_image }}
+        ports:
+        - containerPort: 5432
+        env:
+        - name: POSTGRESQL_DATABASE
+          value: {{ .name }}
+        - name: POSTGRESQL_USER
+          valueFrom:
+            secretKeyRef:
+              name: {{ .name }}-db
+              key: database-user
+        - name: POSTGRESQL_PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: {{ .name }}-db
+              key: database-password
+        resources:
+          requests:
+            cpu: 100m
+            memory: 256Mi
+          limits:
+            memory: 1Gi
+        readinessProbe:
+          tcpSocket:
+            port: 5432
+          initialDelaySeconds: 5
+        volumeMounts:
+        - name: data
+          mountPath: /var/lib/pgsql/data
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      accessModes:
+      - ReadWriteOnce
+      resources:
+        requests:
+          storage: {{ .db_storage }}
+`
+
+const batchJobPVC = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: {{ .name }}-data
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}
+    created-by: openshift-mcp
+spec:
+  accessModes:
+  - ReadWriteOnce
+  resources:
+    requests:
+      storage: {{ .storage }}
+`
+
+const batchJobCronJob = `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: {{ .name }}
+  namespace: {{ .namespace }}
+  labels:
+    app: {{ .name }}
+    created-by: openshift-mcp
+spec:
+  schedule: {{ printf "%q" .schedule }}
+  concurrencyPolicy: Forbid
+  successfulJobsHistoryLimit: 3
+  failedJobsHistoryLimit: 3
+  jobTemplate:
+    spec:
+      backoffLimit: 2
+      template:
+        metadata:
+          labels:
+            app: {{ .name }}
+        spec:
+          restartPolicy: OnFailure
+          containers:
+          - name: {{ .name }}
+            image: {{ .image }}
+{{- if .command }}
+            command:
+            - /bin/sh
+            - -c
+            - {{ printf "%q" .command }}
+{{- end }}
+            resources:
+              requests:
+                cpu: 100m
+                memory: 128Mi
+            volumeMounts:
+            - name: data
+              mountPath: /data
+          volumes:
+          - name: data
+            persistentVolumeClaim:
+              claimName: {{ .name }}-data
+`
+
+// parseCatalogStack reads a stack directory of the Git catalog
+func parseCatalogStack(name string, files map[string]string) (CatalogStack, error) {
+	stack := CatalogStack{Name: name, Source: "git", Files: make(map[string]string)}
+	descriptor, ok := files[catalogStackFile]
+	if !ok {
+		return stack, fmt.Errorf("missing %s", catalogStackFile)
+	}
+	if err := yaml.Unmarshal([]byte(descriptor), &stack); err != nil {
+		return stack, fmt.Errorf("invalid %s: %v", catalogStackFile, err)
+	}
+	for _, parameter := range stack.Parameters {
+		if parameter.Name == "" {
+			return stack, fmt.Errorf("%s has a parameter without a name", catalogStackFile)
+		}
+	}
+	for filename, content := range files {
+		if filename != catalogStackFile {
+			stack.Files[filename] = content
+		}
+	}
+	if len(stack.Files) == 0 {
+		return stack, fmt.Errorf("no manifest templates next to %s", catalogStackFile)
+	}
+	return stack, nil
+}
+
+// catalogStacks returns the built-in stacks merged with those of the Git
+// catalog, and notes about stacks that could not be read
+func (s *Server) catalogStacks() (map[string]CatalogStack, []string) {
+	stacks := make(map[string]CatalogStack, len(builtinCatalog))
+	for name, stack := range builtinCatalog {
+		stack.Name = name
+		stack.Source = "built-in"
+		stacks[name] = stack
+	}
+	if s.gitManager == nil || !s.gitManager.IsEnabled() {
+		return stacks, nil
+	}
+
+	var notes []string
+	repoStacks, err := s.gitManager.ReadCatalog()
+	if err != nil {
+		return stacks, []string{err.Error()}
+	}
+	for name, files := range repoStacks {
+		stack, err := parseCatalogStack(name, files)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("catalog/%s: %v", name, err))
+			continue
+		}
+		stacks[name] = stack
+	}
+	sort.Strings(notes)
+	return stacks, notes
+}
+
+// renderCatalogStack fills in a stack's templates with the given parameters
+// and returns the rendered manifests by file name
+func renderCatalogStack(stack CatalogStack, params map[string]string) (map[string]string, error) {
+	values := make(map[string]string)
+	known := make(map[string]bool)
+	var missing []string
+	for _, parameter := range stack.Parameters {
+		known[parameter.Name] = true
+		value, ok := params[parameter.Name]
+		if !ok || value == "" {
+			value = parameter.Default
+		}
+		if value == "" && parameter.Required {
+			missing = append(missing, parameter.Name)
+		}
+		values[parameter.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required parameter(s): %s", strings.Join(missing, ", "))
+	}
+	for name := range params {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown parameter %q (expected one of: %s)", name, strings.Join(sortedKeys(known), ", "))
+		}
+	}
+	for _, name := range []string{"name", "namespace"} {
+		if value, ok := values[name]; ok {
+			if errs := validation.IsDNS1123Label(value); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid %s %q: %s", name, value, strings.Join(errs, "; "))
+			}
+		}
+	}
+
+	rendered := make(map[string]string, len(stack.Files))
+	for _, filename := range sortedKeys(stack.Files) {
+		tmpl, err := template.New(filename).Option("missingkey=error").Parse(stack.Files[filename])
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %v", filename, err)
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, values); err != nil {
+			return nil, fmt.Errorf("template %s: %v", filename, err)
+		}
+		var object map[string]interface{}
+		if err := yaml.Unmarshal(out.Bytes(), &object); err != nil {
+			return nil, fmt.Errorf("template %s renders invalid YAML: %v", filename, err)
+		}
+		if object["apiVersion"] == nil || object["kind"] == nil {
+			return nil, fmt.Errorf("template %s renders an object without apiVersion or kind", filename)
+		}
+		rendered[filename] = out.String()
+	}
+	return rendered, nil
+}
+
+func (s *Server) initCatalog() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_catalog",
+			mcp.WithDescription("List the pre-approved application stacks (golden paths) that generate_yaml can instantiate with template=<stack>, with their parameters"),
+			mcp.WithString("stack", mcp.Description("Show one stack with its manifest templates")),
+			mcp.WithTitleAnnotation("Catalog: List Stacks"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listCatalogHandler)},
+	}
+}
+
+// formatCatalogParameter renders a parameter for listings
+func formatCatalogParameter(parameter CatalogParameter) string {
+	line := "   " + parameter.Name
+	switch {
+	case parameter.Required:
+		line += " (required)"
+	case parameter.Default != "":
+		line += fmt.Sprintf(" (default: %s)", parameter.Default)
+	}
+	if parameter.Description != "" {
+		line += " - " + parameter.Description
+	}
+	return line
+}
+
+func (s *Server) listCatalogHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stacks, notes := s.catalogStacks()
+
+	if name := strings.TrimSpace(mcp.ParseString(request, "stack", "")); name != "" {
+		stack, ok := stacks[name]
+		if !ok {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Unknown stack '%s' (available: %s)", name, strings.Join(sortedKeys(stacks), ", "))), nil
+		}
+		result := fmt.Sprintf("📚 Catalog Stack: %s\n", name)
+		result += "===================\n\n"
+		result += fmt.Sprintf("%s\n", stack.Description)
+		result += fmt.Sprintf("Source: %s\n\n", stack.Source)
+		result += "⚙️  Parameters:\n"
+		for _, parameter := range stack.Parameters {
+			result += formatCatalogParameter(parameter) + "\n"
+		}
+		for _, filename := range sortedKeys(stack.Files) {
+			result += fmt.Sprintf("\n%s:\n```yaml\n%s```\n", filename, stack.Files[filename])
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	result := "📚 Application Stack Catalog\n"
+	result += "============================\n\n"
+	for _, name := range sortedKeys(stacks) {
+		stack := stacks[name]
+		result += fmt.Sprintf("📦 %s (%s, %d manifests)\n", name, stack.Source, len(stack.Files))
+		result += fmt.Sprintf("   %s\n", stack.Description)
+		for _, parameter := range stack.Parameters {
+			result += "   " + formatCatalogParameter(parameter) + "\n"
+		}
+		result += "\n"
+	}
+	for _, note := range notes {
+		result += fmt.Sprintf("⚠️  %s\n", note)
+	}
+	result += "💡 Instantiate a stack with generate_yaml template=<stack> name=<app> parameters={\"image\": \"...\"}\n"
+	result += "💡 Add stacks to the Git repository under catalog/<stack>/: a stack.yaml with description and parameters, plus manifest templates using {{ .parameter }}"
+	return mcp.NewToolResultText(result), nil
+}
+
+// generateStackYAML instantiates a catalog stack for generate_yaml. The
+// name, namespace, image and replicas parameters of generate_yaml fill the
+// stack parameters of the same name unless parameters sets them.
+func (s *Server) generateStackYAML(ctx context.Context, request mcp.CallToolRequest, stackName string) (*mcp.CallToolResult, error) {
+	stacks, _ := s.catalogStacks()
+	stack, ok := stacks[stackName]
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Unknown catalog stack '%s' (available: %s)", stackName, strings.Join(sortedKeys(stacks), ", "))), nil
+	}
+
+	params := make(map[string]string)
+	if value := strings.TrimSpace(mcp.ParseString(request, "parameters", "")); value != "" {
+		var raw map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid parameters JSON: %v", err)), nil
+		}
+		for key, item := range raw {
+			params[key] = fmt.Sprintf("%v", item)
+		}
+	}
+	for _, parameter := range stack.Parameters {
+		if _, set := params[parameter.Name]; set {
+			continue
+		}
+		switch parameter.Name {
+		case "name", "namespace", "image", "replicas":
+			if value := mcp.ParseString(request, parameter.Name, ""); value != "" {
+				params[parameter.Name] = value
+			}
+		}
+	}
+
+	manifests, err := renderCatalogStack(stack, params)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Cannot instantiate stack %s: %v\n💡 Run list_catalog stack=%s for its parameters", stackName, err, stackName)), nil
+	}
+
+	files := sortedKeys(manifests)
+	documents := make([]string, 0, len(files))
+	for _, filename := range files {
+		documents = append(documents, manifests[filename])
+	}
+	combined := strings.Join(documents, "---\n")
+
+	result := fmt.Sprintf("📄 Generated YAML for stack %s\n", stackName)
+	result += "========================\n\n"
+	result += fmt.Sprintf("%s\n", stack.Description)
+	result += fmt.Sprintf("Source: %s catalog, %d manifests\n\n", stack.Source, len(files))
+	result += "```yaml\n"
+	result += combined
+	result += "```\n\n"
+
+	if parseBoolString(mcp.ParseString(request, "save_to_git", "false")) && s.gitManager != nil && s.gitManager.IsEnabled() {
+		filename := fmt.Sprintf("stack-%s-%s", stackName, params["name"])
+		description := fmt.Sprintf("Generated stack %s: %s", stackName, params["name"])
+		if _, err := s.gitManager.SaveYAMLFile(ctx, filename, combined, "generate", description); err != nil {
+			result += fmt.Sprintf("⚠️  Failed to save to Git: %v\n", err)
+		} else {
+			result += "✅ YAML saved to Git repository successfully!\n"
+		}
+	}
+	result += "💡 Review the manifests, then create them with apply_yaml"
+	return mcp.NewToolResultText(result), nil
+}
+
+// ListCatalogHandler is a public wrapper for listCatalogHandler
+func (s *Server) ListCatalogHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listCatalogHandler(ctx, request)
+}
@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRenderBuiltinStacks(t *testing.T) {
+	params := map[string]map[string]string{
+		"web-app":          {"name": "shop", "image": "quay.io/acme/shop:1.0"},
+		"web-app-postgres": {"name": "shop", "image": "quay.io/acme/shop:1.0", "db_password": "s3cr3t:\"x"},
+		"batch-job":        {"name": "report", "image": "quay.io/acme/report:1.0", "command": "run --all"},
+	}
+	for name, stack := range builtinCatalog {
+		manifests, err := renderCatalogStack(stack, params[name])
+		if err != nil {
+			t.Errorf("renderCatalogStack(%s) error = %v", name, err)
+			continue
+		}
+		if len(manifests) != len(stack.Files) {
+			t.Errorf("renderCatalogStack(%s) rendered %d manifests, expected %d", name, len(manifests), len(stack.Files))
+		}
+		for filename, content := range manifests {
+			if strings.Contains(content, "<no value>") || !strings.Contains(content, "namespace: default") {
+				t.Errorf("renderCatalogStack(%s) %s:\n%s", name, filename, content)
+			}
+		}
+	}
+
+	manifests, _ := renderCatalogStack(builtinCatalog["web-app-postgres"], params["web-app-postgres"])
+	var secret struct {
+		StringData map[string]string `json:"stringData"`
+	}
+	if err := yaml.Unmarshal([]byte(manifests["db-secret.yaml"]), &secret); err != nil || secret.StringData["database-password"] != "s3cr3t:\"x" {
+		t.Errorf("web-app-postgres secret = %v, %v", secret.StringData, err)
+	}
+	if !strings.Contains(manifests["deployment.yaml"], "name: DATABASE_PASSWORD") {
+		t.Errorf("web-app-postgres deployment has no database environment:\n%s", manifests["deployment.yaml"])
+	}
+	manifests, _ = renderCatalogStack(builtinCatalog["web-app"], params["web-app"])
+	if strings.Contains(manifests["deployment.yaml"], "DATABASE") || strings.Contains(manifests["route.yaml"], "host:") {
+		t.Errorf("web-app rendered database settings or a route host:\n%s\n%s", manifests["deployment.yaml"], manifests["route.yaml"])
+	}
+}
+
+func TestRenderCatalogStackErrors(t *testing.T) {
+	stack := builtinCatalog["web-app"]
+	tests := []struct {
+		params   map[string]string
+		expected string
+	}{
+		{map[string]string{"name": "shop"}, "missing required parameter(s): image"},
+		{map[string]string{"name": "shop", "image": "shop:1", "colour": "blue"}, "unknown parameter \"colour\""},
+		{map[string]string{"name": "Shop_1", "image": "shop:1"}, "invalid name \"Shop_1\""},
+	}
+	for _, tt := range tests {
+		if _, err := renderCatalogStack(stack, tt.params); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("renderCatalogStack(%v) error = %v, expected %q", tt.params, err, tt.expected)
+		}
+	}
+
+	broken := CatalogStack{
+		Parameters: []CatalogParameter{{Name: "name", Required: true}},
+		Files:      map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .nmae }}\n"},
+	}
+	if _, err := renderCatalogStack(broken, map[string]string{"name": "x"}); err == nil || !strings.Contains(err.Error(), "cm.yaml") {
+		t.Errorf("renderCatalogStack(misspelled parameter) error = %v, expected a template error", err)
+	}
+}
+
+func TestCatalogFromGit(t *testing.T) {
+	repo := t.TempDir()
+	writeFile := func(path, content string) {
+		full := filepath.Join(repo, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("catalog/cache/stack.yaml", "description: Redis cache\nparameters:\n- name: name\n  required: true\n- name: memory\n  default: 256Mi\n")
+	writeFile("catalog/cache/configmap.yaml", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .name }}-config\ndata:\n  maxmemory: {{ .memory }}\n")
+	writeFile("catalog/broken/deployment.yaml", "kind: Deployment\n")
+
+	s := &Server{config: &Config{}, gitManager: NewGitManager(&GitConfig{Enabled: true, RepoPath: repo})}
+	stacks, notes := s.catalogStacks()
+	if stacks["cache"].Source != "git" || stacks["web-app"].Source != "built-in" {
+		t.Errorf("catalogStacks() = %v", sortedKeys(stacks))
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "catalog/broken: missing stack.yaml") {
+		t.Errorf("catalogStacks() notes = %q", notes)
+	}
+
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler(%v) error = %v", args, err)
+		}
+		return resultText(result)
+	}
+
+	text := call(s.ListCatalogHandler, nil)
+	for _, want := range []string{"📦 cache (git, 1 manifests)", "📦 web-app (built-in, 4 manifests)", "memory (default: 256Mi)", "⚠️  catalog/broken"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("list_catalog output missing %q:\n%s", want, text)
+		}
+	}
+
+	text = call(s.GenerateYamlHandler, map[string]interface{}{"resource_type": "stack", "template": "cache", "name": "sessions", "parameters": `{"memory": "1Gi"}`})
+	if !strings.Contains(text, "name: sessions-config") || !strings.Contains(text, "maxmemory: 1Gi") {
+		t.Errorf("generate_yaml template=cache output:\n%s", text)
+	}
+	text = call(s.GenerateYamlHandler, map[string]interface{}{"resource_type": "stack", "template": "web-app", "name": "shop", "namespace": "prod", "image": "shop:2", "parameters": `{"replicas": 3}`})
+	for _, want := range []string{"namespace: prod", "image: shop:2", "replicas: 3", "minReplicas: 3", "---\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("generate_yaml template=web-app output missing %q:\n%s", want, text)
+		}
+	}
+	if text := call(s.GenerateYamlHandler, map[string]interface{}{"resource_type": "stack", "template": "nope", "name": "x"}); !strings.Contains(text, "Unknown catalog stack 'nope'") {
+		t.Errorf("generate_yaml with an unknown stack = %q", text)
+	}
+}
@@ -0,0 +1,352 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// certDialTimeout bounds a TLS handshake made to read a serving certificate
+	certDialTimeout = 5 * time.Second
+	// certDialConcurrency bounds the kubelets handshaken at once
+	certDialConcurrency = 10
+)
+
+// certChecks are the certificate sources an audit can scan
+var certChecks = []string{"secrets", "routes", "apiserver", "kubelet"}
+
+// secretCertKinds classifies the TLS secrets of platform namespaces
+var secretCertKinds = map[string]string{
+	"openshift-ingress":        diagnostics.CertKindRouter,
+	"openshift-kube-apiserver": diagnostics.CertKindAPIServer,
+}
+
+// certAudit gathers the certificates of an audit and the sources that could
+// not be read
+type certAudit struct {
+	mu      sync.Mutex
+	certs   []diagnostics.CertificateInfo
+	sources map[string]int
+	skipped []string
+}
+
+func (a *certAudit) add(kind, source, manager string, certs []*x509.Certificate) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, cert := range certs {
+		info := diagnostics.NewCertificateInfo(kind, source, cert)
+		info.Manager = manager
+		a.certs = append(a.certs, info)
+	}
+	a.sources[kind]++
+}
+
+func (a *certAudit) skip(format string, args ...interface{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.skipped = append(a.skipped, fmt.Sprintf(format, args...))
+}
+
+// fetchServingCertificates reads the certificate chain an endpoint serves.
+// The chain is only inspected, never trusted, so it is not verified: an
+// expired certificate is exactly what the audit looks for.
+func fetchServingCertificates(ctx context.Context, address, serverName string) ([]*x509.Certificate, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: certDialTimeout},
+		Config:    &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate served")
+	}
+	return certs, nil
+}
+
+// serverAddress reads the host of a server URL and the address to dial,
+// taking https and the default port when the URL leaves them out
+func serverAddress(server, defaultPort string) (host, address string, err error) {
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	endpoint, err := url.Parse(server)
+	if err != nil {
+		return "", "", err
+	}
+	if endpoint.Hostname() == "" {
+		return "", "", fmt.Errorf("no host in %q", server)
+	}
+	port := endpoint.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return endpoint.Hostname(), net.JoinHostPort(endpoint.Hostname(), port), nil
+}
+
+// secretCertManager names what renews a TLS secret's certificate
+func secretCertManager(secret *corev1.Secret) string {
+	if name := secret.Annotations["cert-manager.io/certificate-name"]; name != "" {
+		return fmt.Sprintf("cert-manager Certificate %s/%s", secret.Namespace, name)
+	}
+	if secret.Annotations["service.beta.openshift.io/originating-service-name"] != "" {
+		return "service-ca"
+	}
+	return ""
+}
+
+// auditSecretCerts reads the certificates of the TLS secrets in a namespace,
+// or in every namespace
+func (s *Server) auditSecretCerts(ctx context.Context, namespace string, audit *certAudit) {
+	secrets, err := s.k8sClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + string(corev1.SecretTypeTLS)})
+	if err != nil {
+		audit.skip("TLS secrets: %v", err)
+		return
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != corev1.SecretTypeTLS || len(secret.Data[corev1.TLSCertKey]) == 0 {
+			continue
+		}
+		source := fmt.Sprintf("secret %s/%s", secret.Namespace, secret.Name)
+		certs, err := diagnostics.ParseCertificatePEM(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			audit.skip("%s: %v", source, err)
+			continue
+		}
+		kind := secretCertKinds[secret.Namespace]
+		if kind == "" {
+			kind = diagnostics.CertKindSecret
+		}
+		audit.add(kind, source, secretCertManager(secret), certs)
+	}
+}
+
+// auditRouteCerts reads the certificates set inline on routes
+func (s *Server) auditRouteCerts(ctx context.Context, namespace string, audit *certAudit) {
+	if s.dynamicClient == nil {
+		audit.skip("routes: dynamic client not available")
+		return
+	}
+	routes, err := s.dynamicClient.Resource(routesGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		audit.skip("routes: %v", err)
+		return
+	}
+	for _, route := range routes.Items {
+		certificate, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "certificate")
+		if certificate == "" {
+			continue
+		}
+		source := fmt.Sprintf("route %s/%s", route.GetNamespace(), route.GetName())
+		certs, err := diagnostics.ParseCertificatePEM([]byte(certificate))
+		if err != nil {
+			audit.skip("%s: %v", source, err)
+			continue
+		}
+		audit.add(diagnostics.CertKindRoute, source, "", certs)
+	}
+}
+
+// auditAPIServerCerts reads the certificate the API server serves to this
+// server
+func (s *Server) auditAPIServerCerts(ctx context.Context, audit *certAudit) {
+	if s.restConfig == nil || s.restConfig.Host == "" {
+		audit.skip("API server: no API server address")
+		return
+	}
+	host, address, err := serverAddress(s.restConfig.Host, "443")
+	if err != nil {
+		audit.skip("API server: %v", err)
+		return
+	}
+	certs, err := fetchServingCertificates(ctx, address, host)
+	if err != nil {
+		audit.skip("API server %s: %v", address, err)
+		return
+	}
+	audit.add(diagnostics.CertKindAPIServer, "API server "+address, "", certs)
+}
+
+// auditKubeletCerts reads the serving certificate of every node's kubelet
+func (s *Server) auditKubeletCerts(ctx context.Context, audit *certAudit) {
+	nodes, err := s.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		audit.skip("kubelets: %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, certDialConcurrency)
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		address := ""
+		for _, a := range node.Status.Addresses {
+			if a.Type == corev1.NodeInternalIP {
+				address = a.Address
+				break
+			}
+		}
+		port := node.Status.DaemonEndpoints.KubeletEndpoint.Port
+		if address == "" || port == 0 {
+			audit.skip("kubelet %s: no internal IP or kubelet port in the node status", node.Name)
+			continue
+		}
+		wg.Add(1)
+		go func(name, address string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			certs, err := fetchServingCertificates(ctx, address, "")
+			if err != nil {
+				audit.skip("kubelet %s: %v", name, err)
+				return
+			}
+			audit.add(diagnostics.CertKindKubelet, "kubelet "+name, "", certs)
+		}(node.Name, net.JoinHostPort(address, strconv.Itoa(int(port))))
+	}
+	wg.Wait()
+}
+
+// parseCertChecks reads the comma-separated checks parameter
+func parseCertChecks(value string) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	for _, check := range strings.Split(value, ",") {
+		check = strings.ToLower(strings.TrimSpace(check))
+		if check == "" {
+			continue
+		}
+		valid := false
+		for _, known := range certChecks {
+			valid = valid || check == known
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown check '%s'; expected %s", check, strings.Join(certChecks, ", "))
+		}
+		selected[check] = true
+	}
+	if len(selected) == 0 {
+		for _, check := range certChecks {
+			selected[check] = true
+		}
+	}
+	return selected, nil
+}
+
+// parseDays reads a positive number of days, or returns the default
+func parseDays(request mcp.CallToolRequest, name string, defaultDays int) (int, error) {
+	value := strings.TrimSpace(mcp.ParseString(request, name, ""))
+	if value == "" {
+		return defaultDays, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		return 0, fmt.Errorf("invalid %s '%s': expected a positive number of days", name, value)
+	}
+	return days, nil
+}
+
+func (s *Server) initCertAudit() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("audit_certificates",
+			mcp.WithDescription("Audit certificate expiry across the cluster: TLS secrets, route certificates, the certificate the API server serves and every kubelet's serving certificate, reported as issues whose severity follows the days remaining"),
+			mcp.WithString("namespace", mcp.Description("Only audit TLS secrets and routes in this namespace, skipping the API server and kubelets (default: the whole cluster)")),
+			mcp.WithString("checks", mcp.Description("Comma-separated sources to check: secrets, routes, apiserver, kubelet (default: all)")),
+			mcp.WithString("warning_days", mcp.Description(fmt.Sprintf("Report certificates expiring within this many days as warnings (default %d)", diagnostics.DefaultCertWarningDays))),
+			mcp.WithString("critical_days", mcp.Description(fmt.Sprintf("Report certificates expiring within this many days as critical (default %d)", diagnostics.DefaultCertCriticalDays))),
+			mcp.WithTitleAnnotation("Security: Audit Certificate Expiry"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.auditCertificatesHandler)},
+	}
+}
+
+func (s *Server) auditCertificatesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	if namespace == "all" || namespace == "*" {
+		namespace = metav1.NamespaceAll
+	}
+	checks, err := parseCertChecks(mcp.ParseString(request, "checks", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	policy := diagnostics.CertExpiryPolicy{}
+	if policy.WarningDays, err = parseDays(request, "warning_days", diagnostics.DefaultCertWarningDays); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if policy.CriticalDays, err = parseDays(request, "critical_days", diagnostics.DefaultCertCriticalDays); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if policy.CriticalDays > policy.WarningDays {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ critical_days (%d) must not exceed warning_days (%d)", policy.CriticalDays, policy.WarningDays)), nil
+	}
+
+	audit := &certAudit{sources: make(map[string]int)}
+	if checks["secrets"] {
+		s.auditSecretCerts(ctx, namespace, audit)
+	}
+	if checks["routes"] {
+		s.auditRouteCerts(ctx, namespace, audit)
+	}
+	if namespace == "" {
+		if checks["apiserver"] {
+			s.auditAPIServerCerts(ctx, audit)
+		}
+		if checks["kubelet"] {
+			s.auditKubeletCerts(ctx, audit)
+		}
+	}
+
+	response := "🔐 Certificate Expiry Audit\n"
+	response += "===========================\n\n"
+	if namespace != "" {
+		response += fmt.Sprintf("Scope: namespace %s (the API server and kubelets are audited cluster-wide only)\n", namespace)
+	} else {
+		response += "Scope: cluster\n"
+	}
+	var sources []string
+	for _, kind := range sortedKeys(audit.sources) {
+		sources = append(sources, fmt.Sprintf("%d %s", audit.sources[kind], kind))
+	}
+	response += fmt.Sprintf("Sources: %s\n", valueOrNone(strings.Join(sources, ", ")))
+	response += fmt.Sprintf("Windows: critical within %d days, warning within %d days\n\n", policy.CriticalDays, policy.WarningDays)
+
+	result := diagnostics.AuditCertificates(audit.certs, time.Now(), policy)
+	response += s.formatAnalysisResult(ctx, result)
+
+	if len(audit.skipped) > 0 {
+		sort.Strings(audit.skipped)
+		response += fmt.Sprintf("\n⚠️ Not checked (%d):\n", len(audit.skipped))
+		for _, reason := range audit.skipped {
+			response += fmt.Sprintf("• %s\n", reason)
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// AuditCertificatesHandler is a public wrapper for auditCertificatesHandler
+func (s *Server) AuditCertificatesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.auditCertificatesHandler(ctx, request)
+}
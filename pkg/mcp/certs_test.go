@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+func tlsSecret(namespace, name, certificate string, annotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte(certificate), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+}
+
+func TestAuditCertificates(t *testing.T) {
+	// The test server's certificate stands in for the API server and kubelets
+	endpoint := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer endpoint.Close()
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(endpoint.URL, "https://"))
+	kubeletPort, _ := strconv.Atoi(port)
+
+	route := newUnstructured("route.openshift.io/v1", "Route", "shop", "web")
+	unstructured.SetNestedMap(route.Object, map[string]interface{}{
+		"termination": "edge", "certificate": testCertificate(t, time.Now().Add(-2*24*time.Hour-time.Hour)),
+	}, "spec", "tls")
+	s := newRouteTestServer(route)
+	s.restConfig = &rest.Config{Host: endpoint.URL}
+	for _, object := range []interface{}{
+		tlsSecret("shop", "web-tls", testCertificate(t, time.Now().Add(5*24*time.Hour+time.Hour)), map[string]string{"cert-manager.io/certificate-name": "web"}),
+		tlsSecret("shop", "api-tls", testCertificate(t, time.Now().Add(300*24*time.Hour)), nil),
+		tlsSecret("shop", "broken-tls", "not a certificate", nil),
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+			Status: corev1.NodeStatus{
+				Addresses:       []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: host}},
+				DaemonEndpoints: corev1.NodeDaemonEndpoints{KubeletEndpoint: corev1.DaemonEndpoint{Port: int32(kubeletPort)}},
+			},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}},
+	} {
+		switch o := object.(type) {
+		case *corev1.Secret:
+			s.k8sClient.CoreV1().Secrets(o.Namespace).Create(context.Background(), o, metav1.CreateOptions{})
+		case *corev1.Node:
+			s.k8sClient.CoreV1().Nodes().Create(context.Background(), o, metav1.CreateOptions{})
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+	text := resultText(mustCall(t, s.AuditCertificatesHandler, request))
+	for _, want := range []string{
+		"Sources: 1 apiserver, 1 kubelet, 1 route, 2 tls-secret",
+		"1. **Route certificate expired 2 days ago** (security)\n   📍 Location: route shop/web",
+		"2. **TLS secret certificate expires in 5 days** (security)\n   📍 Location: secret shop/web-tls",
+		"Renewed by cert-manager Certificate shop/web",
+		"⚠️ Not checked (2):\n• kubelet worker-2: no internal IP or kubelet port in the node status\n• secret shop/broken-tls: no PEM certificate found",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("audit_certificates output missing %q:\n%s", want, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "checks": "secrets", "warning_days": "400"}
+	text = resultText(mustCall(t, s.AuditCertificatesHandler, request))
+	if !strings.Contains(text, "Sources: 2 tls-secret") || !strings.Contains(text, "TLS secret certificate expires in 299 days") {
+		t.Errorf("audit_certificates of one namespace's secrets:\n%s", text)
+	}
+
+	for _, arguments := range []map[string]interface{}{
+		{"checks": "secrets,etcd"},
+		{"warning_days": "soon"},
+		{"warning_days": "5", "critical_days": "10"},
+	} {
+		request.Params.Arguments = arguments
+		if text := resultText(mustCall(t, s.AuditCertificatesHandler, request)); !strings.HasPrefix(text, "❌") {
+			t.Errorf("audit_certificates(%v) = %q, expected an error", arguments, text)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// clonedFromAnnotation records the source of a cloned namespace
+const clonedFromAnnotation = "mcp.openshift.io/cloned-from"
+
+// cloneSecretModes are the values of the secrets parameter of clone_namespace
+var cloneSecretModes = map[string]string{
+	"skip":        "secrets are not cloned",
+	"copy":        "secrets are copied with their values",
+	"placeholder": "secrets are created with their keys and empty values",
+}
+
+func (s *Server) initCloning() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("clone_namespace",
+			mcp.WithDescription("Copy the workloads and configuration of a namespace into a new namespace, rewriting names, labels and route hosts, to spin up a test replica of an application"),
+			mcp.WithString("source_namespace", mcp.Description("Namespace to copy"), mcp.Required()),
+			mcp.WithString("target_namespace", mcp.Description("New namespace to create"), mcp.Required()),
+			mcp.WithString("resource_types", mcp.Description("Comma-separated resource types to copy (default: configmaps, serviceaccounts, persistentvolumeclaims, deployments, statefulsets, daemonsets, cronjobs, services, routes)")),
+			mcp.WithString("secrets", mcp.Description("How to handle secrets: skip (default), copy, or placeholder (same keys with empty values)")),
+			mcp.WithString("rename", mcp.Description("Comma-separated old=new renames applied to object names, label values and every reference to them, e.g. web=web-debug")),
+			mcp.WithString("labels", mcp.Description("Comma-separated key=value labels added to the namespace and every copied object")),
+			mcp.WithString("replicas", mcp.Description("Replica count for copied deployments and statefulsets (default: as in the source)")),
+			mcp.WithString("dry_run", mcp.Description("Show what would be copied without creating anything (true/false)")),
+			mcp.WithTitleAnnotation("Clone: Namespace"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.cloneNamespaceHandler)},
+	}
+}
+
+// parseKeyValues parses comma-separated key=value pairs
+func parseKeyValues(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid pair %q: expected key=value", pair)
+		}
+		pairs[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return pairs, nil
+}
+
+// rewriteCloneValue replaces every string equal to a renamed value and
+// points in-cluster service DNS names at the target namespace
+func rewriteCloneValue(value interface{}, renames map[string]string, source, target string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = rewriteCloneValue(item, renames, source, target)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rewriteCloneValue(item, renames, source, target)
+		}
+		return v
+	case string:
+		if renamed, ok := renames[v]; ok {
+			return renamed
+		}
+		return strings.ReplaceAll(v, "."+source+".svc", "."+target+".svc")
+	}
+	return value
+}
+
+// cloneObject prepares a stripped object for the target namespace. It
+// returns a note describing any route host change.
+func cloneObject(obj *unstructured.Unstructured, renames, labels map[string]string, source, target string, replicas *int64) string {
+	host, _, _ := unstructured.NestedString(obj.Object, "spec", "host")
+
+	for key, value := range obj.Object {
+		if key != "apiVersion" && key != "kind" {
+			obj.Object[key] = rewriteCloneValue(value, renames, source, target)
+		}
+	}
+
+	if len(labels) > 0 {
+		objLabels := obj.GetLabels()
+		if objLabels == nil {
+			objLabels = make(map[string]string)
+		}
+		for key, value := range labels {
+			objLabels[key] = value
+		}
+		obj.SetLabels(objLabels)
+	}
+
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet":
+		if replicas != nil {
+			unstructured.SetNestedField(obj.Object, *replicas, "spec", "replicas")
+		}
+	case "Route":
+		if host == "" {
+			return ""
+		}
+		// Two routes cannot claim the same host: rewrite the namespace in
+		// the host, or let the router generate one
+		if rewritten := strings.ReplaceAll(host, source, target); rewritten != host {
+			unstructured.SetNestedField(obj.Object, rewritten, "spec", "host")
+			return fmt.Sprintf("route %s: host %s → %s", obj.GetName(), host, rewritten)
+		}
+		unstructured.RemoveNestedField(obj.Object, "spec", "host")
+		return fmt.Sprintf("route %s: host %s dropped, the router generates a new one", obj.GetName(), host)
+	}
+	return ""
+}
+
+// cloneSecret applies the secrets mode to a secret and reports whether it
+// should be copied
+func cloneSecret(obj *unstructured.Unstructured, mode string) bool {
+	secretType, _, _ := unstructured.NestedString(obj.Object, "type")
+	if mode == "skip" || secretType == string(corev1.SecretTypeServiceAccountToken) ||
+		obj.GetAnnotations()[corev1.ServiceAccountNameKey] != "" {
+		// Account tokens and pull secrets are created for each service account
+		return false
+	}
+	if mode == "placeholder" {
+		data, _, _ := unstructured.NestedMap(obj.Object, "data")
+		for key := range data {
+			data[key] = ""
+		}
+		if data != nil {
+			unstructured.SetNestedMap(obj.Object, data, "data")
+		}
+		unstructured.RemoveNestedField(obj.Object, "stringData")
+	}
+	return true
+}
+
+func (s *Server) cloneNamespaceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil || s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	source := mcp.ParseString(request, "source_namespace", "")
+	target := mcp.ParseString(request, "target_namespace", "")
+	secretMode := strings.ToLower(mcp.ParseString(request, "secrets", "skip"))
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+
+	if source == "" || target == "" {
+		return mcp.NewToolResultText("❌ Source and target namespaces are required"), nil
+	}
+	if source == target {
+		return mcp.NewToolResultText("❌ The target namespace must differ from the source namespace"), nil
+	}
+	if errs := validation.IsDNS1123Label(target); len(errs) > 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid target namespace '%s': %s", target, strings.Join(errs, "; "))), nil
+	}
+	if _, ok := cloneSecretModes[secretMode]; !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid secrets mode '%s' (expected skip, copy or placeholder)", secretMode)), nil
+	}
+	renames, err := parseKeyValues(mcp.ParseString(request, "rename", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid rename: %v", err)), nil
+	}
+	labels, err := parseKeyValues(mcp.ParseString(request, "labels", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid labels: %v", err)), nil
+	}
+	var replicas *int64
+	if value := mcp.ParseString(request, "replicas", ""); value != "" {
+		count, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || count < 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid replicas '%s'", value)), nil
+		}
+		replicas = &count
+	}
+	// References to the source namespace itself follow the clone
+	renames[source] = target
+
+	if _, err := s.k8sClient.CoreV1().Namespaces().Get(ctx, source, metav1.GetOptions{}); err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get source namespace %s", source), err), nil
+	}
+	if _, err := s.k8sClient.CoreV1().Namespaces().Get(ctx, target, metav1.GetOptions{}); err == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Namespace %s already exists\n💡 Clone into a new namespace, or delete %s first", target, target)), nil
+	} else if !apierrors.IsNotFound(err) {
+		return toolError(ctx, fmt.Sprintf("Failed to check target namespace %s", target), err), nil
+	}
+
+	resourceTypes := onboardResourceTypes
+	if value := mcp.ParseString(request, "resource_types", ""); value != "" {
+		resourceTypes = nil
+		for _, resourceType := range strings.Split(value, ",") {
+			if resourceType = strings.TrimSpace(resourceType); resourceType != "" {
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+		}
+	}
+	if secretMode != "skip" {
+		// Secrets go first so that the workloads mounting them can start
+		resourceTypes = append([]string{"secrets"}, resourceTypes...)
+	}
+
+	var items []*unstructured.Unstructured
+	var itemGVRs []schema.GroupVersionResource
+	var notes, hostChanges []string
+	cloned := make(map[string]int)
+	skipped := 0
+	for _, resourceType := range resourceTypes {
+		gvr, namespaced, err := s.resolveResource(resourceType)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", resourceType, err))
+			continue
+		}
+		if !namespaced {
+			notes = append(notes, fmt.Sprintf("%s: cluster-scoped, not cloned", resourceType))
+			continue
+		}
+		list, err := s.dynamicClient.Resource(gvr).Namespace(source).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			recordToolError(ctx, err)
+			notes = append(notes, fmt.Sprintf("%s: %v", resourceType, err))
+			continue
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GetKind() == "" {
+				obj.SetKind(strings.TrimSuffix(list.GetKind(), "List"))
+			}
+			if gvr.Resource == "secrets" && !cloneSecret(obj, secretMode) {
+				skipped++
+				continue
+			}
+			if !stripForExport(obj) {
+				skipped++
+				continue
+			}
+			if note := cloneObject(obj, renames, labels, source, target, replicas); note != "" {
+				hostChanges = append(hostChanges, note)
+			}
+			items = append(items, obj)
+			itemGVRs = append(itemGVRs, gvr)
+			cloned[obj.GetKind()]++
+		}
+	}
+	if len(items) == 0 {
+		result := fmt.Sprintf("❌ No resources to clone in namespace %s\n", source)
+		for _, note := range notes {
+			result += fmt.Sprintf("⚠️  %s\n", note)
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	result := fmt.Sprintf("🧬 Cloning Namespace: %s → %s\n", source, target)
+	result += "==========================\n\n"
+	result += fmt.Sprintf("Secrets: %s\n", cloneSecretModes[secretMode])
+	if replicas != nil {
+		result += fmt.Sprintf("Replicas: %d per deployment/statefulset\n", *replicas)
+	}
+	if len(renames) > 1 {
+		result += "Renames:\n"
+		for _, old := range sortedKeys(renames) {
+			if old != source {
+				result += fmt.Sprintf("   %s → %s\n", old, renames[old])
+			}
+		}
+	}
+	if len(labels) > 0 {
+		result += "Added labels:\n"
+		for _, key := range sortedKeys(labels) {
+			result += fmt.Sprintf("   %s=%s\n", key, labels[key])
+		}
+	}
+	result += "\n📋 Resources:\n"
+	for _, kind := range sortedKeys(cloned) {
+		result += fmt.Sprintf("   %s: %d\n", kind, cloned[kind])
+	}
+	if skipped > 0 {
+		result += fmt.Sprintf("   Skipped %d owned, platform-created or excluded object(s)\n", skipped)
+	}
+	for _, change := range hostChanges {
+		result += fmt.Sprintf("🌐 %s\n", change)
+	}
+	for _, note := range notes {
+		result += fmt.Sprintf("⚠️  %s\n", note)
+	}
+	if cloned["PersistentVolumeClaim"] > 0 {
+		result += "💾 Persistent volume claims are created empty - data is not copied\n"
+	}
+
+	if dryRun {
+		result += "\n📄 Objects (dry run - nothing was created):\n"
+		for _, obj := range items {
+			result += fmt.Sprintf("   %s/%s\n", obj.GetKind(), obj.GetName())
+		}
+		result += "\n💡 Re-run without dry_run to create the namespace and its objects"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	namespaceLabels := map[string]string{"created-by": "openshift-mcp"}
+	for key, value := range labels {
+		namespaceLabels[key] = value
+	}
+	namespace, err := s.k8sClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        target,
+			Labels:      namespaceLabels,
+			Annotations: map[string]string{clonedFromAnnotation: source},
+		},
+	}, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to create namespace %s", target), err), nil
+	}
+
+	created := 0
+	var failures []string
+	for i, obj := range items {
+		obj.SetNamespace(target)
+		if _, err := s.dynamicClient.Resource(itemGVRs[i]).Namespace(target).Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager}); err != nil {
+			recordToolError(ctx, err)
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", obj.GetKind(), obj.GetName(), validationMessage(err)))
+			continue
+		}
+		created++
+	}
+
+	logrus.Infof("Cloned namespace %s to %s: %d of %d objects created", source, target, created, len(items))
+	s.recordAction(ctx, "clone_namespace", objectReference("v1", "Namespace", namespace), ReasonCreated,
+		fmt.Sprintf("Cloned namespace %s with %d objects", source, created))
+
+	result += fmt.Sprintf("\n✅ Created namespace %s with %d of %d object(s)\n", target, created, len(items))
+	if len(failures) > 0 {
+		result += "\n❌ Failed:\n"
+		for _, failure := range failures {
+			result += fmt.Sprintf("   %s\n", failure)
+		}
+	}
+	result += fmt.Sprintf("\n💡 Delete namespace %s when the investigation is over", target)
+	return mcp.NewToolResultText(result), nil
+}
+
+// CloneNamespaceHandler is a public wrapper for cloneNamespaceHandler
+func (s *Server) CloneNamespaceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.cloneNamespaceHandler(ctx, request)
+}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]string
+		valid    bool
+	}{
+		{"", map[string]string{}, true},
+		{"web=web-debug", map[string]string{"web": "web-debug"}, true},
+		{" a=1 , b = 2,", map[string]string{"a": "1", "b": "2"}, true},
+		{"env=", map[string]string{"env": ""}, true},
+		{"web", nil, false},
+		{"=x", nil, false},
+	}
+	for _, tt := range tests {
+		result, err := parseKeyValues(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseKeyValues(%q) error = %v, expected valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		if tt.valid && len(result) != len(tt.expected) {
+			t.Errorf("parseKeyValues(%q) = %v, expected %v", tt.input, result, tt.expected)
+		}
+		for key, value := range tt.expected {
+			if result[key] != value {
+				t.Errorf("parseKeyValues(%q)[%q] = %q, expected %q", tt.input, key, result[key], value)
+			}
+		}
+	}
+}
+
+func TestCloneObject(t *testing.T) {
+	renames := map[string]string{"web": "web-debug", "prod": "prod-debug"}
+	labels := map[string]string{"purpose": "debug"}
+	replicas := int64(1)
+
+	deployment := newUnstructured("apps/v1", "Deployment", "", "web")
+	deployment.SetLabels(map[string]string{"app": "web"})
+	unstructured.SetNestedField(deployment.Object, int64(4), "spec", "replicas")
+	unstructured.SetNestedStringMap(deployment.Object, map[string]string{"app": "web"}, "spec", "selector", "matchLabels")
+	unstructured.SetNestedSlice(deployment.Object, []interface{}{
+		map[string]interface{}{"name": "DB_HOST", "value": "db.prod.svc.cluster.local"},
+	}, "spec", "template", "spec", "env")
+
+	if note := cloneObject(deployment, renames, labels, "prod", "prod-debug", &replicas); note != "" {
+		t.Errorf("cloneObject(Deployment) note = %q, expected none", note)
+	}
+	if deployment.GetName() != "web-debug" || deployment.GetLabels()["app"] != "web-debug" || deployment.GetLabels()["purpose"] != "debug" {
+		t.Errorf("cloned deployment metadata = %s %v", deployment.GetName(), deployment.GetLabels())
+	}
+	selector, _, _ := unstructured.NestedStringMap(deployment.Object, "spec", "selector", "matchLabels")
+	count, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+	env, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "env")
+	if selector["app"] != "web-debug" || count != 1 || env[0].(map[string]interface{})["value"] != "db.prod-debug.svc.cluster.local" {
+		t.Errorf("cloned deployment spec: selector %v, replicas %d, env %v", selector, count, env)
+	}
+
+	tests := []struct {
+		host     string
+		expected string
+		note     string
+	}{
+		{"web-prod.apps.example.com", "web-prod-debug.apps.example.com", "host web-prod.apps.example.com → web-prod-debug.apps.example.com"},
+		{"shop.example.com", "", "dropped"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		route := newUnstructured("route.openshift.io/v1", "Route", "", "web")
+		if tt.host != "" {
+			unstructured.SetNestedField(route.Object, tt.host, "spec", "host")
+		}
+		note := cloneObject(route, renames, nil, "prod", "prod-debug", nil)
+		host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+		if host != tt.expected || !strings.Contains(note, tt.note) {
+			t.Errorf("cloneObject(Route %q) = host %q, note %q, expected %q, %q", tt.host, host, note, tt.expected, tt.note)
+		}
+	}
+}
+
+func TestCloneSecret(t *testing.T) {
+	newSecret := func(secretType string, annotations map[string]string) *unstructured.Unstructured {
+		secret := newUnstructured("v1", "Secret", "prod", "db")
+		unstructured.SetNestedField(secret.Object, secretType, "type")
+		unstructured.SetNestedField(secret.Object, map[string]interface{}{"password": "c2VjcmV0"}, "data")
+		secret.SetAnnotations(annotations)
+		return secret
+	}
+	tests := []struct {
+		secret   *unstructured.Unstructured
+		mode     string
+		expected bool
+		password string
+	}{
+		{newSecret("Opaque", nil), "skip", false, "c2VjcmV0"},
+		{newSecret("Opaque", nil), "copy", true, "c2VjcmV0"},
+		{newSecret("Opaque", nil), "placeholder", true, ""},
+		{newSecret(string(corev1.SecretTypeServiceAccountToken), nil), "copy", false, "c2VjcmV0"},
+		{newSecret(string(corev1.SecretTypeDockercfg), map[string]string{corev1.ServiceAccountNameKey: "builder"}), "copy", false, "c2VjcmV0"},
+	}
+	for _, tt := range tests {
+		secretType, _, _ := unstructured.NestedString(tt.secret.Object, "type")
+		result := cloneSecret(tt.secret, tt.mode)
+		password, _, _ := unstructured.NestedString(tt.secret.Object, "data", "password")
+		if result != tt.expected || password != tt.password {
+			t.Errorf("cloneSecret(%s, %q) = %v with password %q, expected %v with %q", secretType, tt.mode, result, password, tt.expected, tt.password)
+		}
+	}
+}
+
+func TestCloneNamespace(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	configMapsGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	secret := newUnstructured("v1", "Secret", "prod", "db")
+	unstructured.SetNestedField(secret.Object, map[string]interface{}{"password": "c2VjcmV0"}, "data")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList",
+		configMapsGVR:  "ConfigMapList",
+		secretsGVR:     "SecretList",
+	},
+		newUnstructured("apps/v1", "Deployment", "prod", "web"),
+		newUnstructured("v1", "ConfigMap", "prod", "web-config"),
+		newUnstructured("v1", "ConfigMap", "prod", "kube-root-ca.crt"),
+		secret,
+	)
+	s := &Server{
+		config: &Config{},
+		k8sClient: kubefake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "taken"}},
+		),
+		dynamicClient: client,
+		restMapper:    mapper,
+	}
+
+	cloneRequest := func(args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := s.CloneNamespaceHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("CloneNamespaceHandler(%v) error = %v", args, err)
+		}
+		return resultText(result)
+	}
+
+	for args, want := range map[string]string{
+		"prod":  "must differ",
+		"taken": "already exists",
+		"Bad_":  "Invalid target namespace",
+	} {
+		if text := cloneRequest(map[string]interface{}{"source_namespace": "prod", "target_namespace": args}); !strings.Contains(text, want) {
+			t.Errorf("clone_namespace to %q = %q, expected %q", args, text, want)
+		}
+	}
+
+	args := map[string]interface{}{
+		"source_namespace": "prod",
+		"target_namespace": "prod-debug",
+		"resource_types":   "deployments,configmaps",
+		"secrets":          "placeholder",
+		"labels":           "purpose=debug",
+		"dry_run":          "true",
+	}
+	text := cloneRequest(args)
+	if !strings.Contains(text, "dry run") || !strings.Contains(text, "Deployment/web") || !strings.Contains(text, "Secret/db") {
+		t.Errorf("clone_namespace dry run output:\n%s", text)
+	}
+	if _, err := s.k8sClient.CoreV1().Namespaces().Get(context.Background(), "prod-debug", metav1.GetOptions{}); err == nil {
+		t.Errorf("clone_namespace dry run created the target namespace")
+	}
+
+	args["dry_run"] = "false"
+	text = cloneRequest(args)
+	if !strings.Contains(text, "✅ Created namespace prod-debug with 3 of 3 object(s)") {
+		t.Errorf("clone_namespace output:\n%s", text)
+	}
+	namespace, err := s.k8sClient.CoreV1().Namespaces().Get(context.Background(), "prod-debug", metav1.GetOptions{})
+	if err != nil || namespace.Annotations[clonedFromAnnotation] != "prod" || namespace.Labels["purpose"] != "debug" {
+		t.Fatalf("target namespace = %v, %v", namespace, err)
+	}
+	cloned, err := client.Resource(secretsGVR).Namespace("prod-debug").Get(context.Background(), "db", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("cloned secret: %v", err)
+	}
+	if password, _, _ := unstructured.NestedString(cloned.Object, "data", "password"); password != "" || cloned.GetLabels()["purpose"] != "debug" {
+		t.Errorf("cloned secret = %v", cloned.Object)
+	}
+	if _, err := client.Resource(configMapsGVR).Namespace("prod-debug").Get(context.Background(), "kube-root-ca.crt", metav1.GetOptions{}); err == nil {
+		t.Errorf("clone_namespace copied the platform ConfigMap kube-root-ca.crt")
+	}
+}
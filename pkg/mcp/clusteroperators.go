@@ -0,0 +1,304 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// statusCondition is one status condition of an OpenShift config resource
+type statusCondition struct {
+	Type               string
+	Status             string
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// statusConditions reads the status conditions of an object, the types in
+// order first and any others after them
+func statusConditions(obj *unstructured.Unstructured, order ...string) []statusCondition {
+	rank := func(kind string) int {
+		for i, o := range order {
+			if o == kind {
+				return i
+			}
+		}
+		return len(order)
+	}
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	conditions := make([]statusCondition, 0, len(raw))
+	for _, c := range raw {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		parsed := statusCondition{}
+		parsed.Type, _ = condition["type"].(string)
+		parsed.Status, _ = condition["status"].(string)
+		parsed.Reason, _ = condition["reason"].(string)
+		parsed.Message, _ = condition["message"].(string)
+		if transition, ok := condition["lastTransitionTime"].(string); ok {
+			parsed.LastTransitionTime, _ = time.Parse(time.RFC3339, transition)
+		}
+		conditions = append(conditions, parsed)
+	}
+	sort.SliceStable(conditions, func(i, j int) bool { return rank(conditions[i].Type) < rank(conditions[j].Type) })
+	return conditions
+}
+
+// operatorConditions reads the conditions of a ClusterOperator in the order
+// Available, Progressing, Degraded, then any others
+func operatorConditions(operator *unstructured.Unstructured) []statusCondition {
+	return statusConditions(operator, "Available", "Progressing", "Degraded")
+}
+
+// operatorHealthy reports whether an operator is available and not degraded
+func operatorHealthy(state OperatorState) bool {
+	return state.Available && !state.Degraded
+}
+
+// operatorIcon summarizes the state of an operator
+func operatorIcon(state OperatorState) string {
+	switch {
+	case !state.Available:
+		return "❌"
+	case state.Degraded:
+		return "⚠️ "
+	case state.Progressing:
+		return "🔄"
+	}
+	return "✅"
+}
+
+// operatorProblem returns the condition that explains an operator's state:
+// Degraded, then not Available, then Progressing
+func operatorProblem(conditions []statusCondition) *statusCondition {
+	for _, wanted := range []struct{ kind, status string }{
+		{"Degraded", "True"}, {"Available", "False"}, {"Progressing", "True"},
+	} {
+		for i := range conditions {
+			if conditions[i].Type == wanted.kind && conditions[i].Status == wanted.status {
+				return &conditions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// clusterOperatorStatus reads the state of a ClusterOperator
+func (s *Server) clusterOperatorStatus(ctx context.Context, name string) (diagnostics.OperatorStatus, error) {
+	if s.dynamicClient == nil {
+		return diagnostics.OperatorStatus{Name: name}, fmt.Errorf("dynamic client not available")
+	}
+	operator, err := s.dynamicClient.Resource(clusterOperatorsGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return diagnostics.OperatorStatus{Name: name}, err
+	}
+	status := diagnostics.OperatorStatus{Name: name, Found: true}
+	conditions := operatorConditions(operator)
+	for _, condition := range conditions {
+		switch condition.Type {
+		case "Available":
+			status.Available = condition.Status == "True"
+		case "Progressing":
+			status.Progressing = condition.Status == "True"
+		case "Degraded":
+			status.Degraded = condition.Status == "True"
+		}
+	}
+	if problem := operatorProblem(conditions); problem != nil {
+		status.Message = fmt.Sprintf("%s: %s", problem.Type, problem.Message)
+	}
+	return status, nil
+}
+
+func formatConditionTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return formatAge(t) + " ago"
+}
+
+// operatorNamespaces returns the namespaces in an operator's related objects,
+// where its pods and events live
+func operatorNamespaces(operator *unstructured.Unstructured) []string {
+	related, _, _ := unstructured.NestedSlice(operator.Object, "status", "relatedObjects")
+	var namespaces []string
+	for _, r := range related {
+		object, ok := r.(map[string]interface{})
+		if !ok || object["resource"] != "namespaces" {
+			continue
+		}
+		if name, ok := object["name"].(string); ok && name != "" {
+			namespaces = append(namespaces, name)
+		}
+	}
+	return namespaces
+}
+
+func (s *Server) initClusterOperators() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("get_cluster_operators",
+			mcp.WithDescription("List OpenShift ClusterOperators with their Available, Progressing and Degraded conditions and messages, unhealthy operators first"),
+			mcp.WithString("name", mcp.Description("Show one operator's conditions, versions and namespaces in detail")),
+			mcp.WithString("unhealthy_only", mcp.Description("Only list operators that are unavailable, degraded or progressing (true/false, default false)")),
+			mcp.WithTitleAnnotation("OpenShift: Cluster Operators"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getClusterOperatorsHandler)},
+	}
+}
+
+func (s *Server) getClusterOperatorsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	if name := strings.TrimSpace(mcp.ParseString(request, "name", "")); name != "" {
+		return s.describeClusterOperator(ctx, name)
+	}
+	unhealthyOnly := parseBoolString(mcp.ParseString(request, "unhealthy_only", "false"))
+
+	list, err := s.dynamicClient.Resource(clusterOperatorsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ ClusterOperators are not available; this does not look like an OpenShift cluster"), nil
+		}
+		return toolError(ctx, "Failed to list cluster operators", err), nil
+	}
+
+	operators := list.Items
+	rank := func(state OperatorState) int {
+		switch {
+		case !state.Available:
+			return 0
+		case state.Degraded:
+			return 1
+		case state.Progressing:
+			return 2
+		}
+		return 3
+	}
+	sort.SliceStable(operators, func(i, j int) bool {
+		ri, rj := rank(operatorState(&operators[i])), rank(operatorState(&operators[j]))
+		if ri != rj {
+			return ri < rj
+		}
+		return operators[i].GetName() < operators[j].GetName()
+	})
+
+	var unavailable, degraded, progressing int
+	for i := range operators {
+		state := operatorState(&operators[i])
+		switch {
+		case !state.Available:
+			unavailable++
+		case state.Degraded:
+			degraded++
+		case state.Progressing:
+			progressing++
+		}
+	}
+
+	result := "🧩 Cluster Operators\n"
+	result += "====================\n\n"
+	result += fmt.Sprintf("📦 Found %d operators", len(operators))
+	if unavailable+degraded+progressing == 0 {
+		result += ", all available and not degraded\n"
+	} else {
+		result += fmt.Sprintf(": ❌ %d unavailable, ⚠️  %d degraded, 🔄 %d progressing\n", unavailable, degraded, progressing)
+	}
+
+	listed := 0
+	for i := range operators {
+		operator := &operators[i]
+		state := operatorState(operator)
+		if unhealthyOnly && operatorHealthy(state) && !state.Progressing {
+			continue
+		}
+		listed++
+		result += fmt.Sprintf("\n%s %s", operatorIcon(state), operator.GetName())
+		if state.Version != "" {
+			result += fmt.Sprintf(" %s", state.Version)
+		}
+		result += fmt.Sprintf(" - Available=%v Progressing=%v Degraded=%v\n", state.Available, state.Progressing, state.Degraded)
+		if problem := operatorProblem(operatorConditions(operator)); problem != nil {
+			result += fmt.Sprintf("   %s=%s since %s", problem.Type, problem.Status, formatConditionTime(problem.LastTransitionTime))
+			if problem.Reason != "" {
+				result += fmt.Sprintf(" (%s)", problem.Reason)
+			}
+			result += "\n"
+			if problem.Message != "" {
+				result += fmt.Sprintf("   %s\n", problem.Message)
+			}
+		}
+	}
+	if unhealthyOnly && listed == 0 {
+		result += "\n✅ No unhealthy operators\n"
+	}
+	if unavailable+degraded > 0 {
+		result += "\n💡 Use get_cluster_operators name=<operator> for its conditions and namespaces, then check pods and events there"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// describeClusterOperator shows one operator's conditions, versions and namespaces
+func (s *Server) describeClusterOperator(ctx context.Context, name string) (*mcp.CallToolResult, error) {
+	operator, err := s.dynamicClient.Resource(clusterOperatorsGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ ClusterOperator %s not found. Use get_cluster_operators to list them.", name)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get cluster operator %s", name), err), nil
+	}
+	state := operatorState(operator)
+
+	result := fmt.Sprintf("🧩 Cluster Operator: %s\n", name)
+	result += "====================\n\n"
+	result += fmt.Sprintf("Status: %s Available=%v Progressing=%v Degraded=%v\n", operatorIcon(state), state.Available, state.Progressing, state.Degraded)
+
+	versions, _, _ := unstructured.NestedSlice(operator.Object, "status", "versions")
+	if len(versions) > 0 {
+		result += "\n📌 Versions:\n"
+		for _, v := range versions {
+			if version, ok := v.(map[string]interface{}); ok {
+				result += fmt.Sprintf("• %v: %v\n", version["name"], version["version"])
+			}
+		}
+	}
+
+	result += "\n📋 Conditions:\n"
+	for _, condition := range operatorConditions(operator) {
+		result += fmt.Sprintf("• %s=%s since %s", condition.Type, condition.Status, formatConditionTime(condition.LastTransitionTime))
+		if condition.Reason != "" {
+			result += fmt.Sprintf(" (%s)", condition.Reason)
+		}
+		result += "\n"
+		if condition.Message != "" {
+			result += fmt.Sprintf("   %s\n", condition.Message)
+		}
+	}
+
+	if namespaces := operatorNamespaces(operator); len(namespaces) > 0 {
+		result += fmt.Sprintf("\n📁 Namespaces: %s\n", strings.Join(namespaces, ", "))
+		if !operatorHealthy(state) {
+			result += fmt.Sprintf("\n💡 Check list_pods namespace=%s and get_events namespace=%s", namespaces[0], namespaces[0])
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// GetClusterOperatorsHandler is a public wrapper for getClusterOperatorsHandler
+func (s *Server) GetClusterOperatorsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getClusterOperatorsHandler(ctx, request)
+}
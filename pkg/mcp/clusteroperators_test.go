@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func clusterOperator(name, available, progressing, degraded, message string) *unstructured.Unstructured {
+	operator := newUnstructured("config.openshift.io/v1", "ClusterOperator", "", name)
+	unstructured.SetNestedSlice(operator.Object, []interface{}{
+		map[string]interface{}{"type": "Degraded", "status": degraded, "reason": "SyncError", "message": message, "lastTransitionTime": "2024-01-01T00:00:00Z"},
+		map[string]interface{}{"type": "Available", "status": available},
+		map[string]interface{}{"type": "Progressing", "status": progressing},
+	}, "status", "conditions")
+	unstructured.SetNestedSlice(operator.Object, []interface{}{
+		map[string]interface{}{"name": "operator", "version": "4.15.3"},
+	}, "status", "versions")
+	unstructured.SetNestedSlice(operator.Object, []interface{}{
+		map[string]interface{}{"group": "", "resource": "namespaces", "name": "openshift-" + name},
+	}, "status", "relatedObjects")
+	return operator
+}
+
+func newClusterOperatorTestServer(objects ...runtime.Object) *Server {
+	listKinds := map[schema.GroupVersionResource]string{clusterOperatorsGVR: "ClusterOperatorList"}
+	return &Server{config: &Config{}, dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)}
+}
+
+func TestOperatorProblem(t *testing.T) {
+	tests := []struct {
+		operator *unstructured.Unstructured
+		expected string
+	}{
+		{clusterOperator("dns", "True", "False", "False", ""), ""},
+		{clusterOperator("ingress", "True", "False", "True", "router pods crashing"), "Degraded"},
+		{clusterOperator("etcd", "False", "True", "False", ""), "Available"},
+		{clusterOperator("network", "True", "True", "False", ""), "Progressing"},
+	}
+
+	for _, tt := range tests {
+		problem := operatorProblem(operatorConditions(tt.operator))
+		kind := ""
+		if problem != nil {
+			kind = problem.Type
+		}
+		if kind != tt.expected {
+			t.Errorf("operatorProblem(%s) = %q, expected %q", tt.operator.GetName(), kind, tt.expected)
+		}
+	}
+}
+
+func TestGetClusterOperators(t *testing.T) {
+	s := newClusterOperatorTestServer(
+		clusterOperator("dns", "True", "False", "False", ""),
+		clusterOperator("ingress", "True", "False", "True", "router pods crashing"),
+		clusterOperator("authentication", "False", "False", "False", ""),
+	)
+
+	result, err := s.GetClusterOperatorsHandler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("GetClusterOperatorsHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Found 3 operators: ❌ 1 unavailable, ⚠️  1 degraded, 🔄 0 progressing",
+		"⚠️  ingress 4.15.3 - Available=true Progressing=false Degraded=true",
+		"Degraded=True since",
+		"(SyncError)\n   router pods crashing",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("get_cluster_operators output missing %q:\n%s", want, text)
+		}
+	}
+	if !(strings.Index(text, "authentication") < strings.Index(text, "ingress") && strings.Index(text, "ingress") < strings.Index(text, "dns")) {
+		t.Errorf("get_cluster_operators does not list unavailable, then degraded, then healthy operators:\n%s", text)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"unhealthy_only": "true"}
+	result, _ = s.GetClusterOperatorsHandler(context.Background(), request)
+	if text := resultText(result); strings.Contains(text, "✅ dns") {
+		t.Errorf("get_cluster_operators unhealthy_only lists a healthy operator:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"name": "ingress"}
+	result, _ = s.GetClusterOperatorsHandler(context.Background(), request)
+	text = resultText(result)
+	if !strings.Contains(text, "• operator: 4.15.3") || !strings.Contains(text, "Namespaces: openshift-ingress") ||
+		!strings.Contains(text, "list_pods namespace=openshift-ingress") {
+		t.Errorf("get_cluster_operators name=ingress = %q", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"name": "missing"}
+	result, _ = s.GetClusterOperatorsHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌ ClusterOperator missing not found") {
+		t.Errorf("get_cluster_operators name=missing = %q", text)
+	}
+}
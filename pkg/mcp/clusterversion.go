@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	clusterVersionName         = "version"
+	defaultVersionHistoryLimit = 5
+)
+
+var clusterVersionsGVR = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+
+// updateHistoryEntry is one entry of ClusterVersion status.history, newest first
+type updateHistoryEntry struct {
+	State          string // Completed or Partial
+	Version        string
+	StartedTime    time.Time
+	CompletionTime time.Time
+	Verified       bool
+}
+
+func parseTime(value interface{}) time.Time {
+	text, _ := value.(string)
+	parsed, _ := time.Parse(time.RFC3339, text)
+	return parsed
+}
+
+func updateHistory(cv *unstructured.Unstructured) []updateHistoryEntry {
+	raw, _, _ := unstructured.NestedSlice(cv.Object, "status", "history")
+	history := make([]updateHistoryEntry, 0, len(raw))
+	for _, h := range raw {
+		entry, ok := h.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		parsed := updateHistoryEntry{StartedTime: parseTime(entry["startedTime"]), CompletionTime: parseTime(entry["completionTime"])}
+		parsed.State, _ = entry["state"].(string)
+		parsed.Version, _ = entry["version"].(string)
+		parsed.Verified, _ = entry["verified"].(bool)
+		history = append(history, parsed)
+	}
+	return history
+}
+
+// currentVersion is the newest version the cluster completed an update to
+func currentVersion(history []updateHistoryEntry) string {
+	for _, entry := range history {
+		if entry.State == "Completed" {
+			return entry.Version
+		}
+	}
+	return ""
+}
+
+// conditionStatus returns the condition of the given type
+func conditionStatus(conditions []statusCondition, kind string) (statusCondition, bool) {
+	for _, condition := range conditions {
+		if condition.Type == kind {
+			return condition, true
+		}
+	}
+	return statusCondition{}, false
+}
+
+// versionBlockers lists the ClusterVersion conditions that stop or hold back
+// an update, with what they mean
+func versionBlockers(conditions []statusCondition) []string {
+	var blockers []string
+	add := func(kind, status, meaning string) {
+		if condition, ok := conditionStatus(conditions, kind); ok && condition.Status == status {
+			text := fmt.Sprintf("%s=%s: %s", kind, status, meaning)
+			if condition.Reason != "" {
+				text += fmt.Sprintf(" (%s)", condition.Reason)
+			}
+			if condition.Message != "" {
+				text += "\n   " + condition.Message
+			}
+			blockers = append(blockers, text)
+		}
+	}
+	add("Failing", "True", "the update cannot make progress")
+	add("Invalid", "True", "the desired update is invalid")
+	add("ReleaseAccepted", "False", "the desired release was not accepted")
+	add("Upgradeable", "False", "minor version updates are blocked")
+	add("RetrievedUpdates", "False", "available updates could not be retrieved")
+	return blockers
+}
+
+// compareVersions orders dotted versions such as 4.15.10 numerically
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(strings.SplitN(a, "-", 2)[0], "."), strings.Split(strings.SplitN(b, "-", 2)[0], ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func (s *Server) initClusterVersion() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("get_cluster_version",
+			mcp.WithDescription("Show the OpenShift cluster version, whether an update is in progress, update history and the conditions blocking updates; optionally the available updates and channels"),
+			mcp.WithString("show_updates", mcp.Description("Also list available and conditional updates and the channels (true/false, default false)")),
+			mcp.WithString("history_limit", mcp.Description(fmt.Sprintf("Number of update history entries to show (default %d)", defaultVersionHistoryLimit))),
+			mcp.WithTitleAnnotation("OpenShift: Cluster Version"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getClusterVersionHandler)},
+	}
+}
+
+func (s *Server) getClusterVersionHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	showUpdates := parseBoolString(mcp.ParseString(request, "show_updates", "false"))
+	historyLimit := defaultVersionHistoryLimit
+	if value := mcp.ParseString(request, "history_limit", ""); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid history_limit '%s': expected a number", value)), nil
+		}
+		historyLimit = limit
+	}
+
+	cv, err := s.dynamicClient.Resource(clusterVersionsGVR).Get(ctx, clusterVersionName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ ClusterVersion not found; this does not look like an OpenShift cluster"), nil
+		}
+		return toolError(ctx, "Failed to get the cluster version", err), nil
+	}
+
+	history := updateHistory(cv)
+	current := currentVersion(history)
+	desired, _, _ := unstructured.NestedString(cv.Object, "status", "desired", "version")
+	channel, _, _ := unstructured.NestedString(cv.Object, "spec", "channel")
+	clusterID, _, _ := unstructured.NestedString(cv.Object, "spec", "clusterID")
+	conditions := statusConditions(cv, "Available", "Progressing", "Failing")
+	progressing, _ := conditionStatus(conditions, "Progressing")
+	updating := progressing.Status == "True" || (desired != "" && desired != current)
+
+	result := "🏷️  Cluster Version\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("Current version: %s\n", valueOrNone(current))
+	result += fmt.Sprintf("Desired version: %s\n", valueOrNone(desired))
+	result += fmt.Sprintf("Channel: %s\n", valueOrNone(channel))
+	if clusterID != "" {
+		result += fmt.Sprintf("Cluster ID: %s\n", clusterID)
+	}
+
+	result += "\n"
+	if updating {
+		result += fmt.Sprintf("🔄 Update in progress to %s", valueOrNone(desired))
+		if len(history) > 0 && history[0].State == "Partial" && !history[0].StartedTime.IsZero() {
+			result += fmt.Sprintf(", started %s", formatConditionTime(history[0].StartedTime))
+		}
+		result += "\n"
+		if progressing.Message != "" {
+			result += fmt.Sprintf("   %s\n", progressing.Message)
+		}
+	} else {
+		result += "✅ Not updating\n"
+	}
+
+	if blockers := versionBlockers(conditions); len(blockers) > 0 {
+		result += fmt.Sprintf("\n🚧 Blocking conditions (%d):\n", len(blockers))
+		for _, blocker := range blockers {
+			result += fmt.Sprintf("• %s\n", blocker)
+		}
+	}
+
+	if len(history) > 0 && historyLimit > 0 {
+		result += "\n📜 Update history:\n"
+		for i, entry := range history {
+			if i == historyLimit {
+				result += fmt.Sprintf("... %d older entries\n", len(history)-historyLimit)
+				break
+			}
+			icon := "✅"
+			if entry.State != "Completed" {
+				icon = "🔄"
+			}
+			result += fmt.Sprintf("%s %s %s, started %s", icon, entry.Version, entry.State, formatTime(ctx, entry.StartedTime))
+			if !entry.CompletionTime.IsZero() {
+				result += fmt.Sprintf(", took %s", entry.CompletionTime.Sub(entry.StartedTime).Round(time.Minute))
+			}
+			if !entry.Verified {
+				result += " (unverified)"
+			}
+			result += "\n"
+		}
+	}
+
+	if showUpdates {
+		result += formatAvailableUpdates(cv)
+	} else if updates, _, _ := unstructured.NestedSlice(cv.Object, "status", "availableUpdates"); len(updates) > 0 {
+		result += fmt.Sprintf("\n💡 %d update(s) available; use show_updates=true to list them\n", len(updates))
+	}
+
+	if updating {
+		result += "\n💡 Use get_cluster_operators unhealthy_only=true to see which operators are still updating"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// formatAvailableUpdates lists the recommended and conditional updates and
+// the channels the desired release can follow
+func formatAvailableUpdates(cv *unstructured.Unstructured) string {
+	result := ""
+	updates, _, _ := unstructured.NestedSlice(cv.Object, "status", "availableUpdates")
+	var versions []string
+	for _, u := range updates {
+		if update, ok := u.(map[string]interface{}); ok {
+			if version, ok := update["version"].(string); ok {
+				versions = append(versions, version)
+			}
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) > 0 })
+	result += fmt.Sprintf("\n⬆️  Recommended updates (%d):\n", len(versions))
+	if len(versions) == 0 {
+		result += "None\n"
+	}
+	for _, version := range versions {
+		result += fmt.Sprintf("• %s\n", version)
+	}
+
+	conditional, _, _ := unstructured.NestedSlice(cv.Object, "status", "conditionalUpdates")
+	if len(conditional) > 0 {
+		result += fmt.Sprintf("\n⚠️  Conditional updates (%d):\n", len(conditional))
+		for _, c := range conditional {
+			update, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			version, _, _ := unstructured.NestedString(update, "release", "version")
+			recommended := statusConditions(&unstructured.Unstructured{Object: map[string]interface{}{"status": update}})
+			result += fmt.Sprintf("• %s", version)
+			if condition, ok := conditionStatus(recommended, "Recommended"); ok {
+				result += fmt.Sprintf(" - Recommended=%s", condition.Status)
+				if condition.Reason != "" {
+					result += fmt.Sprintf(" (%s)", condition.Reason)
+				}
+				if condition.Message != "" {
+					result += "\n   " + condition.Message
+				}
+			}
+			result += "\n"
+		}
+	}
+
+	channels, _, _ := unstructured.NestedStringSlice(cv.Object, "status", "desired", "channels")
+	if len(channels) > 0 {
+		result += fmt.Sprintf("\n📡 Channels: %s\n", strings.Join(channels, ", "))
+	}
+	return result
+}
+
+// GetClusterVersionHandler is a public wrapper for getClusterVersionHandler
+func (s *Server) GetClusterVersionHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getClusterVersionHandler(ctx, request)
+}
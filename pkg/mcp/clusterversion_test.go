@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"4.15.10", "4.15.9", 1},
+		{"4.14.3", "4.15.0", -1},
+		{"4.15.3", "4.15.3", 0},
+		{"4.16.0-rc.1", "4.15.20", 1},
+	}
+
+	for _, tt := range tests {
+		if result := compareVersions(tt.a, tt.b); result != tt.expected {
+			t.Errorf("compareVersions(%q, %q) = %d, expected %d", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+// updatingClusterVersion is a cluster halfway from 4.15.3 to 4.15.10 with
+// minor updates blocked
+func updatingClusterVersion() *unstructured.Unstructured {
+	cv := newUnstructured("config.openshift.io/v1", "ClusterVersion", "", clusterVersionName)
+	unstructured.SetNestedField(cv.Object, "stable-4.15", "spec", "channel")
+	unstructured.SetNestedMap(cv.Object, map[string]interface{}{
+		"version": "4.15.10", "channels": []interface{}{"eus-4.16", "stable-4.15"},
+	}, "status", "desired")
+	unstructured.SetNestedSlice(cv.Object, []interface{}{
+		map[string]interface{}{"state": "Partial", "version": "4.15.10", "startedTime": "2024-03-02T10:00:00Z", "verified": true},
+		map[string]interface{}{"state": "Completed", "version": "4.15.3", "startedTime": "2024-02-01T10:00:00Z", "completionTime": "2024-02-01T11:05:00Z", "verified": true},
+	}, "status", "history")
+	unstructured.SetNestedSlice(cv.Object, []interface{}{
+		map[string]interface{}{"type": "Available", "status": "True"},
+		map[string]interface{}{"type": "Progressing", "status": "True", "message": "Working towards 4.15.10: 512 of 863 done (59% complete)"},
+		map[string]interface{}{"type": "Upgradeable", "status": "False", "reason": "AdminAckRequired", "message": "Kubernetes 1.29 removes several APIs"},
+		map[string]interface{}{"type": "Failing", "status": "False"},
+	}, "status", "conditions")
+	unstructured.SetNestedSlice(cv.Object, []interface{}{
+		map[string]interface{}{"version": "4.15.9"},
+		map[string]interface{}{"version": "4.15.11"},
+	}, "status", "availableUpdates")
+	unstructured.SetNestedSlice(cv.Object, []interface{}{
+		map[string]interface{}{
+			"release":    map[string]interface{}{"version": "4.15.12"},
+			"conditions": []interface{}{map[string]interface{}{"type": "Recommended", "status": "False", "reason": "OVNKubernetesBug", "message": "Pods lose connectivity"}},
+		},
+	}, "status", "conditionalUpdates")
+	return cv
+}
+
+func TestGetClusterVersion(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{clusterVersionsGVR: "ClusterVersionList"}
+	s := &Server{config: &Config{}, dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, updatingClusterVersion())}
+
+	result, err := s.GetClusterVersionHandler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("GetClusterVersionHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Current version: 4.15.3",
+		"Desired version: 4.15.10",
+		"Channel: stable-4.15",
+		"🔄 Update in progress to 4.15.10",
+		"512 of 863 done",
+		"Upgradeable=False: minor version updates are blocked (AdminAckRequired)",
+		"✅ 4.15.3 Completed, started 2024-02-01 10:00:00 UTC, took 1h5m0s",
+		"2 update(s) available; use show_updates=true",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("get_cluster_version output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Failing=") {
+		t.Errorf("get_cluster_version reports Failing=False as blocking:\n%s", text)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"show_updates": "true", "history_limit": "1"}
+	result, _ = s.GetClusterVersionHandler(context.Background(), request)
+	text = resultText(result)
+	for _, want := range []string{
+		"Recommended updates (2):\n• 4.15.11\n• 4.15.9",
+		"• 4.15.12 - Recommended=False (OVNKubernetesBug)",
+		"Channels: eus-4.16, stable-4.15",
+		"... 1 older entries",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("get_cluster_version show_updates output missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	defaultNodeNetWindow = 15 * time.Minute
+	nodeProcReadTimeout  = 15 * time.Second
+
+	// The node-exporter pods run with the host network, so their /proc/sys/net
+	// is the node's
+	nodeExporterSelector  = "app.kubernetes.io/name=node-exporter"
+	nodeExporterContainer = "node-exporter"
+)
+
+// Node network metric names, keys of nodeNetQueries
+const (
+	nodeNetConntrackEntries = "conntrack_entries"
+	nodeNetConntrackLimit   = "conntrack_limit"
+	nodeNetConntrackDrops   = "conntrack_drops"
+	nodeNetInsertFailed     = "conntrack_insert_failed"
+	nodeNetTCPInUse         = "tcp_inuse"
+	nodeNetTCPTimeWait      = "tcp_time_wait"
+)
+
+// nodeNetQueries are the node-exporter queries behind each field of the node
+// network telemetry, by node; node-exporter's instance label is the node name
+var nodeNetQueries = map[string]string{
+	nodeNetConntrackEntries: `max by (instance) (node_nf_conntrack_entries)`,
+	nodeNetConntrackLimit:   `max by (instance) (node_nf_conntrack_entries_limit)`,
+	nodeNetConntrackDrops:   `sum by (instance) (increase(node_nf_conntrack_stat_drop[$window]))`,
+	nodeNetInsertFailed:     `sum by (instance) (increase(node_nf_conntrack_stat_insert_failed[$window]))`,
+	nodeNetTCPInUse:         `max by (instance) (node_sockstat_TCP_inuse)`,
+	nodeNetTCPTimeWait:      `max by (instance) (node_sockstat_TCP_tw)`,
+}
+
+// nodeNetProcFiles are read on a node for its live conntrack and socket usage
+var nodeNetProcFiles = []string{
+	"/proc/sys/net/netfilter/nf_conntrack_count",
+	"/proc/sys/net/netfilter/nf_conntrack_max",
+	"/proc/sys/net/ipv4/ip_local_port_range",
+	"/proc/net/sockstat",
+}
+
+// nodeNetTelemetry queries node-exporter for the conntrack and socket usage
+// of every node, or of one node when node is set. The names of metrics that
+// returned no data are returned with it.
+func (s *Server) nodeNetTelemetry(ctx context.Context, endpoint metricsEndpoint, window time.Duration, node string) (map[string]*diagnostics.NodeNetTelemetry, []string, error) {
+	rangeText := strconv.Itoa(int(window.Seconds())) + "s"
+	telemetry := make(map[string]*diagnostics.NodeNetTelemetry)
+	var missing []string
+	var firstErr error
+	for _, metric := range sortedKeys(nodeNetQueries) {
+		query := strings.ReplaceAll(nodeNetQueries[metric], windowPlaceholder, rangeText)
+		response, err := s.queryMetrics(ctx, endpoint, query, "")
+		var values map[string]float64
+		if err == nil {
+			values, err = promVectorValues(response, "instance")
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", metric, err)
+		}
+		if len(values) == 0 {
+			missing = append(missing, metric)
+			continue
+		}
+		for instance, value := range values {
+			if node != "" && instance != node {
+				continue
+			}
+			entry, ok := telemetry[instance]
+			if !ok {
+				entry = &diagnostics.NodeNetTelemetry{Node: instance, Source: diagnostics.NodeNetSourceMetrics}
+				telemetry[instance] = entry
+			}
+			switch metric {
+			case nodeNetConntrackEntries:
+				entry.ConntrackEntries = value
+			case nodeNetConntrackLimit:
+				entry.ConntrackLimit = value
+			case nodeNetConntrackDrops:
+				entry.ConntrackDrops = value
+			case nodeNetInsertFailed:
+				entry.InsertFailed = value
+			case nodeNetTCPInUse:
+				entry.TCPInUse = value
+			case nodeNetTCPTimeWait:
+				entry.TCPTimeWait = value
+			}
+		}
+	}
+	if len(missing) == len(nodeNetQueries) && firstErr != nil {
+		return nil, missing, firstErr
+	}
+	return telemetry, missing, nil
+}
+
+// nodeNetProc reads the live conntrack and socket usage of a node through
+// the node-exporter pod running on it
+func (s *Server) nodeNetProc(ctx context.Context, node string) (diagnostics.NodeNetTelemetry, error) {
+	pods, err := s.k8sClient.CoreV1().Pods(monitoringNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: nodeExporterSelector,
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+	})
+	if err != nil {
+		return diagnostics.NodeNetTelemetry{}, err
+	}
+	var podName string
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == node && pod.Status.Phase == corev1.PodRunning {
+			podName = pod.Name
+			break
+		}
+	}
+	if podName == "" {
+		return diagnostics.NodeNetTelemetry{}, fmt.Errorf("no running node-exporter pod on node %s", node)
+	}
+
+	exec := s.podExec
+	if exec == nil {
+		exec = s.remoteExec
+	}
+	execCtx, cancel := context.WithTimeout(ctx, nodeProcReadTimeout)
+	defer cancel()
+	stdout := &cappedWriter{limit: maxExecOutputBytes}
+	stderr := &cappedWriter{limit: maxExecOutputBytes}
+	command := append([]string{"cat"}, nodeNetProcFiles...)
+	if err := exec(execCtx, monitoringNamespace, podName, nodeExporterContainer, command, stdout, stderr); err != nil {
+		return diagnostics.NodeNetTelemetry{}, fmt.Errorf("reading /proc in %s/%s: %v %s", monitoringNamespace, podName, err, strings.TrimSpace(stderr.buf.String()))
+	}
+	return diagnostics.ParseNodeNetProc(node, stdout.buf.String())
+}
+
+func (s *Server) initNodeNetworkTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("check_node_connection_limits",
+			mcp.WithDescription("Check nodes for conntrack table saturation and ephemeral port exhaustion from node-exporter metrics and, for one node, from its live /proc; both cause intermittent connection refused, resets and timeouts that pod-level checks cannot explain"),
+			mcp.WithString("node", mcp.Description("Only check this node, and read its live conntrack count, port range and socket usage")),
+			mcp.WithString("window", mcp.Description("Window for counting conntrack drops and insert failures (default 15m, at most 1h)")),
+			mcp.WithTitleAnnotation("Network: Check Node Connection Limits"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.checkNodeConnectionLimitsHandler)},
+	}
+}
+
+func (s *Server) checkNodeConnectionLimitsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	node := strings.TrimSpace(mcp.ParseString(request, "node", ""))
+	window := defaultNodeNetWindow
+	if value := mcp.ParseString(request, "window", ""); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < time.Minute || parsed > time.Hour {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid window '%s': expected a duration between 1m and 1h, e.g. 30m", value)), nil
+		}
+		window = parsed
+	}
+
+	response := "🔌 Node Connection Limits\n"
+	response += "========================\n\n"
+	if node != "" {
+		response += fmt.Sprintf("Node: %s\n", node)
+	}
+
+	telemetry := make(map[string]*diagnostics.NodeNetTelemetry)
+	endpoint, err := s.discoverMetricsEndpoint(ctx, "")
+	if err == nil {
+		var missing []string
+		telemetry, missing, err = s.nodeNetTelemetry(ctx, endpoint, window, node)
+		if err == nil {
+			response += fmt.Sprintf("Querier: %s (%s)\n", endpoint.URL, endpoint.Source)
+			response += fmt.Sprintf("Window: %s\n", window)
+			if len(missing) > 0 {
+				response += fmt.Sprintf("⚠️  No data for %s\n", strings.Join(missing, ", "))
+			}
+		}
+	}
+	if err != nil {
+		recordToolError(ctx, err)
+		telemetry = make(map[string]*diagnostics.NodeNetTelemetry)
+		response += fmt.Sprintf("⚠️  node-exporter metrics unavailable: %v\n", err)
+		if node == "" {
+			response += "💡 Name a node to read its conntrack and socket usage from the node itself\n"
+			return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+		}
+	}
+
+	// The live values of a named node replace the sampled gauges; the drop
+	// counters only exist as metrics
+	if node != "" && s.k8sClient != nil {
+		live, err := s.nodeNetProc(ctx, node)
+		if err != nil {
+			response += fmt.Sprintf("⚠️  Live read from the node failed: %v\n", err)
+		} else {
+			if sampled, ok := telemetry[node]; ok {
+				live.ConntrackDrops = sampled.ConntrackDrops
+				live.InsertFailed = sampled.InsertFailed
+			}
+			telemetry[node] = &live
+			response += "Live values read from the node's /proc\n"
+		}
+	}
+	if len(telemetry) == 0 {
+		response += "\n📭 No node reported conntrack or socket metrics\n"
+		return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+	}
+
+	nodes := make([]diagnostics.NodeNetTelemetry, 0, len(telemetry))
+	for _, name := range sortedKeys(telemetry) {
+		nodes = append(nodes, *telemetry[name])
+	}
+	response += "\n📊 Nodes:\n"
+	for _, entry := range nodes {
+		line := fmt.Sprintf("• %s: conntrack %.0f/%.0f", entry.Node, entry.ConntrackEntries, entry.ConntrackLimit)
+		if entry.ConntrackDrops > 0 || entry.InsertFailed > 0 {
+			line += fmt.Sprintf(" (%.0f drops, %.0f insert failures)", entry.ConntrackDrops, entry.InsertFailed)
+		}
+		line += fmt.Sprintf(", TCP %.0f in use, %.0f TIME_WAIT", entry.TCPInUse, entry.TCPTimeWait)
+		if entry.EphemeralPorts > 0 {
+			line += fmt.Sprintf(", %d ephemeral ports", entry.EphemeralPorts)
+		}
+		response += line + "\n"
+	}
+	response += "\n"
+
+	result := diagnostics.AnalyzeNodeNetwork(nodes, window, time.Now())
+	response += s.formatAnalysisResult(ctx, result)
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// CheckNodeConnectionLimitsHandler is a public wrapper for checkNodeConnectionLimitsHandler
+func (s *Server) CheckNodeConnectionLimitsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.checkNodeConnectionLimitsHandler(ctx, request)
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckNodeConnectionLimits(t *testing.T) {
+	querier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		values := map[string]string{}
+		switch {
+		case strings.Contains(query, "node_nf_conntrack_entries_limit"):
+			values = map[string]string{"worker-1": "262144", "worker-2": "262144"}
+		case strings.Contains(query, "node_nf_conntrack_entries"):
+			values = map[string]string{"worker-1": "100000", "worker-2": "2000"}
+		case strings.Contains(query, "node_nf_conntrack_stat_drop"):
+			if !strings.Contains(query, "[1800s]") {
+				t.Errorf("query without the requested window: %s", query)
+			}
+			values = map[string]string{"worker-1": "37", "worker-2": "0"}
+		case strings.Contains(query, "node_sockstat_TCP_tw"):
+			values = map[string]string{"worker-1": "500", "worker-2": "26000"}
+		case strings.Contains(query, "node_sockstat_TCP_inuse"):
+			values = map[string]string{"worker-1": "200", "worker-2": "900"}
+		}
+		var samples []string
+		for instance, value := range values {
+			samples = append(samples, fmt.Sprintf(`{"metric":{"instance":%q},"value":[1700000000,%q]}`, instance, value))
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, strings.Join(samples, ","))
+	}))
+	defer querier.Close()
+
+	exporter := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-exporter-abc", Namespace: monitoringNamespace, Labels: map[string]string{"app.kubernetes.io/name": "node-exporter"}},
+		Spec:       corev1.PodSpec{NodeName: "worker-1", Containers: []corev1.Container{{Name: nodeExporterContainer}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	var commands []string
+	s := &Server{
+		config:    &Config{Monitoring: &MonitoringConfig{QuerierURL: querier.URL}},
+		k8sClient: kubefake.NewSimpleClientset(exporter),
+		podExec: func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+			commands = append(commands, fmt.Sprintf("%s/%s/%s: %s", namespace, pod, container, strings.Join(command, " ")))
+			io.WriteString(stdout, "255000\n262144\n32768\t60999\nTCP: inuse 210 orphan 0 tw 480 alloc 300 mem 20\n")
+			return nil
+		},
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"window": "30m"}
+	result, err := s.CheckNodeConnectionLimitsHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CheckNodeConnectionLimitsHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"• worker-1: conntrack 100000/262144 (37 drops, 0 insert failures)",
+		"• worker-2: conntrack 2000/262144, TCP 900 in use, 26000 TIME_WAIT",
+		"Node worker-1 is dropping connections in conntrack",
+		"TCP sockets on node worker-2 hold",
+		"No data for conntrack_insert_failed",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("check_node_connection_limits output missing %q:\n%s", want, text)
+		}
+	}
+	if len(commands) != 0 {
+		t.Errorf("expected no live read without a node, ran %v", commands)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"node": "worker-1", "window": "30m"}
+	result, _ = s.CheckNodeConnectionLimitsHandler(context.Background(), request)
+	text = resultText(result)
+	for _, want := range []string{
+		"Live values read from the node's /proc",
+		"• worker-1: conntrack 255000/262144 (37 drops, 0 insert failures), TCP 210 in use, 480 TIME_WAIT, 28232 ephemeral ports",
+		"Conntrack table of node worker-1 is 97.3% full",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("check_node_connection_limits output for worker-1 missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "worker-2") {
+		t.Errorf("other nodes reported when checking worker-1:\n%s", text)
+	}
+	if len(commands) != 1 || !strings.HasPrefix(commands[0], "openshift-monitoring/node-exporter-abc/node-exporter: cat /proc/sys/net/netfilter/nf_conntrack_count") {
+		t.Errorf("live read commands = %v", commands)
+	}
+}
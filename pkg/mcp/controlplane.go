@@ -0,0 +1,426 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	// apiProbeTimeout bounds one request or connection made by the probe
+	apiProbeTimeout          = 5 * time.Second
+	defaultAPIProbeSamples   = 10
+	maxAPIProbeSamples       = 50
+	authenticationNamespace  = "openshift-authentication"
+	oauthRouteName           = "oauth-openshift"
+	openshiftConfigNamespace = "openshift-config"
+)
+
+var oauthsGVR = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "oauths"}
+
+// controlPlaneOperators are the ClusterOperators of the path from a client
+// to the API and OAuth servers
+var controlPlaneOperators = []string{"kube-apiserver", "openshift-apiserver", "etcd", "authentication"}
+
+// apiProbePaths alternate a health check with a read that goes to etcd
+var apiProbePaths = []string{"/readyz", "/api/v1/namespaces?limit=1"}
+
+// apiServerQueries read the share of 5xx answers of each API server
+// instance and the 99th percentile latency of all requests
+const (
+	apiInstanceErrorsQuery = `(sum by (instance) (rate(apiserver_request_total{job="apiserver",code=~"5.."}[5m])) or sum by (instance) (rate(apiserver_request_total{job="apiserver"}[5m])) * 0) / sum by (instance) (rate(apiserver_request_total{job="apiserver"}[5m]))`
+	apiLatencyP99Query     = `histogram_quantile(0.99, sum by (le) (rate(apiserver_request_duration_seconds_bucket{job="apiserver",verb!~"WATCH|CONNECT"}[5m])))`
+)
+
+// identityProviderFields names the field of an identity provider that holds
+// the configuration of its type
+var identityProviderFields = map[string]string{
+	"BasicAuth":     "basicAuth",
+	"GitHub":        "github",
+	"GitLab":        "gitlab",
+	"Google":        "google",
+	"HTPasswd":      "htpasswd",
+	"Keystone":      "keystone",
+	"LDAP":          "ldap",
+	"OpenID":        "openID",
+	"RequestHeader": "requestHeader",
+}
+
+// identityProviderSecretRefs are the identity provider fields that name a
+// secret in openshift-config; ca names a config map
+var identityProviderSecretRefs = []string{"bindPassword", "clientSecret", "fileData", "tlsClientCert", "tlsClientKey"}
+
+func (s *Server) initControlPlaneTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("probe_control_plane",
+			mcp.WithDescription("Probe the control plane to answer whether the platform or the application is broken: measures API server latency and errors with repeated requests over fresh connections, reads the API server error rate and latency per instance from the cluster metrics, checks the kube-apiserver, openshift-apiserver, etcd and authentication operators, the OAuth route's health and certificate and each identity provider's secrets and server, and the certificates the API endpoints serve through their load balancers"),
+			mcp.WithString("samples", mcp.Description(fmt.Sprintf("How many requests to make to the API server (default %d, at most %d)", defaultAPIProbeSamples, maxAPIProbeSamples))),
+			mcp.WithTitleAnnotation("OpenShift: Probe Control Plane"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.probeControlPlaneHandler)},
+	}
+}
+
+// probeAPIServer makes requests to the API server, each over a new
+// connection so that the load balancer spreads them over its backends
+func (s *Server) probeAPIServer(ctx context.Context, samples int) ([]diagnostics.APIProbe, error) {
+	config := rest.CopyConfig(s.restConfig)
+	config.Timeout = apiProbeTimeout
+	// A dialer of its own keeps the transport out of client-go's cache, so
+	// closing its connections leaves the server's clients alone
+	config.Dial = (&net.Dialer{Timeout: apiProbeTimeout}).DialContext
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	defer utilnet.CloseIdleConnectionsFor(client.Transport)
+	base := strings.TrimRight(config.Host, "/")
+	if !strings.Contains(base, "://") {
+		base = "https://" + base
+	}
+
+	probes := make([]diagnostics.APIProbe, 0, samples)
+	for i := 0; i < samples; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		probe := diagnostics.APIProbe{Path: apiProbePaths[i%len(apiProbePaths)]}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+probe.Path, nil)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := client.Do(req)
+		probe.Latency = time.Since(start)
+		if err != nil {
+			probe.Error = err.Error()
+		} else {
+			probe.Status = resp.StatusCode
+			resp.Body.Close()
+		}
+		utilnet.CloseIdleConnectionsFor(client.Transport)
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+// apiServerMetrics reads the API server error ratio per instance and its
+// 99th percentile latency from the cluster metrics
+func (s *Server) apiServerMetrics(ctx context.Context, state *diagnostics.ControlPlaneState) error {
+	endpoint, err := s.discoverMetricsEndpoint(ctx, "")
+	if err != nil {
+		return err
+	}
+	response, err := s.queryMetrics(ctx, endpoint, apiInstanceErrorsQuery, "")
+	if err != nil {
+		return err
+	}
+	if state.InstanceErrorRatio, err = promVectorValues(response, "instance"); err != nil {
+		return err
+	}
+	state.MetricsRead = true
+	if response, err := s.queryMetrics(ctx, endpoint, apiLatencyP99Query, ""); err == nil {
+		if values, err := promVectorValues(response, ""); err == nil {
+			state.LatencyP99 = time.Duration(values[""] * float64(time.Second))
+		}
+	}
+	return nil
+}
+
+// apiEndpoint reads the certificate an API endpoint serves and whether it
+// covers the endpoint's name
+func apiEndpoint(ctx context.Context, name, server string) diagnostics.ControlPlaneEndpoint {
+	endpoint := diagnostics.ControlPlaneEndpoint{Name: name, Host: server}
+	host, address, err := serverAddress(server, "443")
+	if err != nil {
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	endpoint.Host, endpoint.Address = host, address
+	start := time.Now()
+	certs, err := fetchServingCertificates(ctx, address, host)
+	endpoint.Latency = time.Since(start)
+	if err != nil {
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	info := diagnostics.NewCertificateInfo(diagnostics.CertKindAPIServer, name+" "+address, certs[0])
+	endpoint.Certificate = &info
+	if err := certs[0].VerifyHostname(host); err != nil {
+		endpoint.HostnameError = err.Error()
+	}
+	return endpoint
+}
+
+// oauthEndpoint calls the health check of the OAuth server through its
+// route and reads the certificate the route serves
+func (s *Server) oauthEndpoint(ctx context.Context) diagnostics.ControlPlaneEndpoint {
+	endpoint := diagnostics.ControlPlaneEndpoint{Name: "oauth", Host: oauthRouteName}
+	route, err := s.dynamicClient.Resource(routesGVR).Namespace(authenticationNamespace).Get(ctx, oauthRouteName, metav1.GetOptions{})
+	if err != nil {
+		endpoint.Error = fmt.Sprintf("route %s/%s: %v", authenticationNamespace, oauthRouteName, err)
+		return endpoint
+	}
+	routeHost, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	host, address, err := serverAddress(routeHost, "443")
+	if err != nil {
+		endpoint.Error = fmt.Sprintf("route %s/%s: %v", authenticationNamespace, oauthRouteName, err)
+		return endpoint
+	}
+	endpoint.Host, endpoint.Address = host, address
+
+	// The certificate is only inspected here, and checked below
+	client := &http.Client{Timeout: apiProbeTimeout, Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		Proxy:             http.ProxyFromEnvironment,
+		DisableKeepAlives: true,
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+address+"/healthz", nil)
+	if err != nil {
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	req.Host = host
+	start := time.Now()
+	resp, err := client.Do(req)
+	endpoint.Latency = time.Since(start)
+	if err != nil {
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	defer resp.Body.Close()
+	endpoint.Status = resp.StatusCode
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		leaf := resp.TLS.PeerCertificates[0]
+		info := diagnostics.NewCertificateInfo(diagnostics.CertKindRoute, "route "+authenticationNamespace+"/"+oauthRouteName, leaf)
+		endpoint.Certificate = &info
+		if err := leaf.VerifyHostname(host); err != nil {
+			endpoint.HostnameError = err.Error()
+		}
+	}
+	return endpoint
+}
+
+// identityProviders reads the identity providers of oauth/cluster, checks
+// that the secrets and config maps they reference exist and dials the
+// server each checks logins against. The dial runs from this server, which
+// may take another path than the oauth-openshift pods.
+func (s *Server) identityProviders(ctx context.Context) ([]diagnostics.IdentityProvider, error) {
+	oauth, err := s.dynamicClient.Resource(oauthsGVR).Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	raw, _, _ := unstructured.NestedSlice(oauth.Object, "spec", "identityProviders")
+	var providers []diagnostics.IdentityProvider
+	for _, item := range raw {
+		spec, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		provider := diagnostics.IdentityProvider{}
+		provider.Name, _ = spec["name"].(string)
+		provider.Type, _ = spec["type"].(string)
+		config, _ := spec[identityProviderFields[provider.Type]].(map[string]interface{})
+
+		refs := map[string]bool{}
+		for _, field := range identityProviderSecretRefs {
+			if name, _, _ := unstructured.NestedString(config, field, "name"); name != "" {
+				refs["secret "+name] = true
+			}
+		}
+		if name, _, _ := unstructured.NestedString(config, "ca", "name"); name != "" {
+			refs["configmap "+name] = true
+		}
+		for _, ref := range sortedKeys(refs) {
+			kind, name, _ := strings.Cut(ref, " ")
+			if kind == "secret" {
+				_, err = s.k8sClient.CoreV1().Secrets(openshiftConfigNamespace).Get(ctx, name, metav1.GetOptions{})
+			} else {
+				_, err = s.k8sClient.CoreV1().ConfigMaps(openshiftConfigNamespace).Get(ctx, name, metav1.GetOptions{})
+			}
+			if apierrors.IsNotFound(err) {
+				provider.Missing = append(provider.Missing, fmt.Sprintf("%s %s/%s", kind, openshiftConfigNamespace, name))
+			}
+		}
+
+		if address, err := identityProviderAddress(provider.Type, config); err != nil {
+			provider.EndpointError = err.Error()
+		} else if address != "" {
+			provider.Endpoint = address
+			conn, err := (&net.Dialer{Timeout: apiProbeTimeout}).DialContext(ctx, "tcp", address)
+			if err != nil {
+				provider.EndpointError = err.Error()
+			} else {
+				conn.Close()
+			}
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// identityProviderAddress returns the address of the server an identity
+// provider checks logins against, or nothing for providers without one
+func identityProviderAddress(kind string, config map[string]interface{}) (string, error) {
+	field, defaultPort := "url", "443"
+	switch kind {
+	case "OpenID":
+		field = "issuer"
+	case "GitHub":
+		field = "hostname"
+	case "LDAP":
+		defaultPort = "389"
+	case "GitLab", "Keystone", "BasicAuth":
+	default:
+		return "", nil
+	}
+	server, _, _ := unstructured.NestedString(config, field)
+	if server == "" {
+		return "", nil
+	}
+	if kind == "LDAP" && strings.HasPrefix(server, "ldaps://") {
+		defaultPort = "636"
+	}
+	_, address, err := serverAddress(server, defaultPort)
+	return address, err
+}
+
+func (s *Server) probeControlPlaneHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil || s.k8sClient == nil || s.restConfig == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	samples := defaultAPIProbeSamples
+	if value := strings.TrimSpace(mcp.ParseString(request, "samples", "")); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxAPIProbeSamples {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid samples '%s': expected a number from 1 to %d", value, maxAPIProbeSamples)), nil
+		}
+		samples = parsed
+	}
+
+	response := "🩺 Control Plane Probe\n"
+	response += "=====================\n\n"
+	var state diagnostics.ControlPlaneState
+
+	response += "🧩 Operators:\n"
+	for _, name := range controlPlaneOperators {
+		operator, err := s.clusterOperatorStatus(ctx, name)
+		state.Operators = append(state.Operators, operator)
+		switch {
+		case err != nil:
+			response += fmt.Sprintf("❓ %s: %v\n", name, err)
+		case !operator.Available:
+			response += fmt.Sprintf("❌ %s: %s\n", name, valueOrNone(operator.Message))
+		case operator.Degraded:
+			response += fmt.Sprintf("⚠️  %s: %s\n", name, operator.Message)
+		default:
+			response += fmt.Sprintf("✅ %s\n", name)
+		}
+	}
+	response += "\n"
+
+	probes, err := s.probeAPIServer(ctx, samples)
+	if err != nil {
+		return toolError(ctx, "Failed to set up requests to the API server", err), nil
+	}
+	state.Probes = probes
+	failed := 0
+	var slowest time.Duration
+	for _, probe := range probes {
+		if probe.Failed() {
+			failed++
+		}
+		if probe.Latency > slowest {
+			slowest = probe.Latency
+		}
+	}
+	response += fmt.Sprintf("📡 API requests to %s: %d, failed %d, slowest %s\n", s.restConfig.Host, len(probes), failed, slowest.Round(time.Millisecond))
+	if err := s.apiServerMetrics(ctx, &state); err != nil {
+		response += fmt.Sprintf("⚠️  API server metrics unavailable: %v\n", err)
+	} else {
+		response += fmt.Sprintf("📊 API server instances: %d, p99 latency %s\n", len(state.InstanceErrorRatio), state.LatencyP99.Round(time.Millisecond))
+	}
+	response += "\n"
+
+	apiURL, internalURL := s.restConfig.Host, ""
+	if infra, err := s.dynamicClient.Resource(infrastructuresGVR).Get(ctx, "cluster", metav1.GetOptions{}); err == nil {
+		if value, _, _ := unstructured.NestedString(infra.Object, "status", "apiServerURL"); value != "" {
+			apiURL = value
+		}
+		internalURL, _, _ = unstructured.NestedString(infra.Object, "status", "apiServerInternalURL")
+	}
+	state.Endpoints = append(state.Endpoints, apiEndpoint(ctx, "api", apiURL))
+	if internalURL != "" && internalURL != apiURL {
+		state.Endpoints = append(state.Endpoints, apiEndpoint(ctx, "api-int", internalURL))
+	}
+	state.Endpoints = append(state.Endpoints, s.oauthEndpoint(ctx))
+	response += "🔐 Endpoints:\n"
+	for _, endpoint := range state.Endpoints {
+		switch {
+		case endpoint.Error != "":
+			response += fmt.Sprintf("❌ %s %s: %s\n", endpoint.Name, endpoint.Host, endpoint.Error)
+		case endpoint.Status != 0:
+			response += fmt.Sprintf("✅ %s %s: HTTP %d in %s\n", endpoint.Name, endpoint.Address, endpoint.Status, endpoint.Latency.Round(time.Millisecond))
+		default:
+			response += fmt.Sprintf("✅ %s %s: connected in %s\n", endpoint.Name, endpoint.Address, endpoint.Latency.Round(time.Millisecond))
+		}
+		if cert := endpoint.Certificate; cert != nil {
+			response += fmt.Sprintf("   Certificate: %s, issued by %s, expires %s\n", cert.Subject, cert.Issuer, formatTime(ctx, cert.NotAfter))
+		}
+	}
+	response += "\n"
+
+	providers, err := s.identityProviders(ctx)
+	if err != nil {
+		response += fmt.Sprintf("⚠️  Identity providers unavailable: %v\n\n", err)
+	} else {
+		state.IdentityProviders = providers
+		response += fmt.Sprintf("👤 Identity providers (%d):\n", len(providers))
+		for _, provider := range providers {
+			icon := "✅"
+			if len(provider.Missing) > 0 || provider.EndpointError != "" {
+				icon = "❌"
+			}
+			response += fmt.Sprintf("%s %s (%s)", icon, provider.Name, provider.Type)
+			if provider.Endpoint != "" {
+				response += " - " + provider.Endpoint
+			}
+			response += "\n"
+			for _, missing := range provider.Missing {
+				response += fmt.Sprintf("   Missing %s\n", missing)
+			}
+			if provider.EndpointError != "" {
+				response += fmt.Sprintf("   Unreachable: %s\n", provider.EndpointError)
+			}
+		}
+		if len(providers) == 0 {
+			response += "📭 None configured; only kubeadmin can log in\n"
+		}
+		response += "\n"
+	}
+
+	result := diagnostics.AnalyzeControlPlane(state, time.Now())
+	response += s.formatAnalysisResult(ctx, result)
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// ProbeControlPlaneHandler is a public wrapper for probeControlPlaneHandler
+func (s *Server) ProbeControlPlaneHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.probeControlPlaneHandler(ctx, request)
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+func TestProbeControlPlane(t *testing.T) {
+	var reads int32
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first read lands on a broken API server
+		if r.URL.Path == "/api/v1/namespaces" && atomic.AddInt32(&reads, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer apiServer.Close()
+	oauthServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			t.Errorf("OAuth request to %s, expected /healthz", r.URL.Path)
+		}
+	}))
+	defer oauthServer.Close()
+	querier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("query"), "histogram_quantile") {
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.05"]}]}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"instance":"10.0.0.1:6443"},"value":[1700000000,"0"]},
+			{"metric":{"instance":"10.0.0.2:6443"},"value":[1700000000,"0.2"]}]}}`))
+	}))
+	defer querier.Close()
+	ldap, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error = %v", err)
+	}
+	defer ldap.Close()
+
+	infra := newUnstructured("config.openshift.io/v1", "Infrastructure", "", "cluster")
+	unstructured.SetNestedField(infra.Object, apiServer.URL, "status", "apiServerURL")
+	unstructured.SetNestedField(infra.Object, "https://127.0.0.1:1", "status", "apiServerInternalURL")
+	route := newUnstructured("route.openshift.io/v1", "Route", authenticationNamespace, oauthRouteName)
+	unstructured.SetNestedField(route.Object, strings.TrimPrefix(oauthServer.URL, "https://"), "spec", "host")
+	oauth := newUnstructured("config.openshift.io/v1", "OAuth", "", "cluster")
+	unstructured.SetNestedSlice(oauth.Object, []interface{}{
+		map[string]interface{}{"name": "local", "type": "HTPasswd", "htpasswd": map[string]interface{}{"fileData": map[string]interface{}{"name": "htpass-secret"}}},
+		map[string]interface{}{"name": "corp", "type": "LDAP", "ldap": map[string]interface{}{"url": fmt.Sprintf("ldap://%s/ou=users?uid", ldap.Addr())}},
+	}, "spec", "identityProviders")
+	objects := []runtime.Object{infra, route, oauth}
+	for _, name := range controlPlaneOperators {
+		objects = append(objects, clusterOperator(name, "True", "False", "False", ""))
+	}
+	s := newRouteTestServer(objects...)
+	s.config = &Config{Monitoring: &MonitoringConfig{QuerierURL: querier.URL}}
+	s.restConfig = &rest.Config{Host: apiServer.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"samples": "4"}
+	text := resultText(mustCall(t, s.ProbeControlPlaneHandler, request))
+	for _, want := range []string{
+		"✅ kube-apiserver\n",
+		fmt.Sprintf("📡 API requests to %s: 4, failed 1", apiServer.URL),
+		"📊 API server instances: 2, p99 latency 50ms",
+		"❌ api-int 127.0.0.1: dial tcp 127.0.0.1:1",
+		"✅ oauth " + strings.TrimPrefix(oauthServer.URL, "https://") + ": HTTP 200",
+		"❌ local (HTPasswd)\n   Missing secret openshift-config/htpass-secret",
+		fmt.Sprintf("✅ corp (LDAP) - %s", ldap.Addr()),
+		"API server answers with server errors",
+		"API server instances fail requests",
+		"Identity provider local references missing objects",
+		"The platform is impaired",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("probe_control_plane output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "does not match its name") {
+		t.Errorf("probe_control_plane reported a certificate mismatch for 127.0.0.1:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"samples": "500"}
+	if text := resultText(mustCall(t, s.ProbeControlPlaneHandler, request)); !strings.Contains(text, "Invalid samples '500'") {
+		t.Errorf("probe_control_plane with too many samples:\n%s", text)
+	}
+}
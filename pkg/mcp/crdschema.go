@@ -0,0 +1,500 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// customResourceSchema is the OpenAPI schema of one served version of a CRD
+type customResourceSchema struct {
+	crd        string
+	apiVersion string
+	kind       string
+	namespaced bool
+	schema     map[string]interface{}
+}
+
+// schemaField is one entry of the parameter form built from a schema
+type schemaField struct {
+	path        string
+	fieldType   string
+	required    bool
+	description string
+	enum        []string
+	defaultJSON string
+}
+
+func (s *Server) initSchemaDiscovery() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("describe_resource_schema",
+			mcp.WithDescription("Build a parameter form for a custom resource from its CRD's OpenAPI schema: every field with its type, whether it is required, allowed values, default and description. Use it before creating a resource the YAML generator does not know"),
+			mcp.WithString("resource_type", mcp.Description("Custom resource type, e.g. kafkas, Kafka or kafkas.kafka.strimzi.io"), mcp.Required()),
+			mcp.WithString("field", mcp.Description("Only describe the fields under this path, e.g. spec.kafka (default: spec)")),
+			mcp.WithString("depth", mcp.Description("Levels of nested fields to show (default: 3)")),
+			mcp.WithTitleAnnotation("Schema: Describe Custom Resource"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.describeResourceSchemaHandler)},
+		{Tool: mcp.NewTool("generate_custom_resource",
+			mcp.WithDescription("Generate schema-valid YAML for a custom resource from field values, checking them against the CRD's OpenAPI schema: unknown fields, wrong types, disallowed values and missing required fields are reported instead of guessed"),
+			mcp.WithString("resource_type", mcp.Description("Custom resource type, e.g. kafkas, Kafka or kafkas.kafka.strimzi.io"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Name of the resource"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the resource (default: default)")),
+			mcp.WithString("values", mcp.Description("JSON object of field values, nested ({\"spec\": {\"replicas\": 3}}) or by path ({\"spec.replicas\": 3})"), mcp.Required()),
+			mcp.WithTitleAnnotation("Schema: Generate Custom Resource"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.generateCustomResourceHandler)},
+	}
+}
+
+// customResourceSchema fetches the CRD of a resource type and returns the
+// schema of the version the RESTMapper resolves to
+func (s *Server) customResourceSchema(ctx context.Context, resourceType string) (*customResourceSchema, error) {
+	if s.dynamicClient == nil {
+		return nil, fmt.Errorf("Kubernetes client not available. Please check your kubeconfig.")
+	}
+	gvr, namespaced, err := s.resolveResource(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(gvr.Group, ".") {
+		return nil, fmt.Errorf("%s is a built-in resource, not a custom resource; use generate_yaml or create_resource", gvr.Resource)
+	}
+
+	name := gvr.Resource + "." + gvr.Group
+	crd, err := s.dynamicClient.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CustomResourceDefinition %s: %v", name, err)
+	}
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, item := range versions {
+		version, ok := item.(map[string]interface{})
+		if !ok || version["name"] != gvr.Version {
+			continue
+		}
+		openAPI, found, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		if !found {
+			return nil, fmt.Errorf("CustomResourceDefinition %s has no OpenAPI schema for version %s", name, gvr.Version)
+		}
+		return &customResourceSchema{
+			crd:        name,
+			apiVersion: gvr.GroupVersion().String(),
+			kind:       kind,
+			namespaced: namespaced,
+			schema:     openAPI,
+		}, nil
+	}
+	return nil, fmt.Errorf("CustomResourceDefinition %s does not serve version %s", name, gvr.Version)
+}
+
+// schemaType renders the type of a schema node, e.g. "array of string"
+func schemaType(node map[string]interface{}) string {
+	if node["x-kubernetes-int-or-string"] == true {
+		return "integer or string"
+	}
+	fieldType, _ := node["type"].(string)
+	switch fieldType {
+	case "":
+		if node["x-kubernetes-preserve-unknown-fields"] == true {
+			return "any"
+		}
+		return "object"
+	case "array":
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			return "array of " + schemaType(items)
+		}
+	case "object":
+		if _, ok := node["properties"]; !ok {
+			if values, ok := node["additionalProperties"].(map[string]interface{}); ok {
+				return "map of " + schemaType(values)
+			}
+		}
+	}
+	return fieldType
+}
+
+// schemaNode returns the schema of the field at a dotted path
+func schemaNode(node map[string]interface{}, path string) (map[string]interface{}, bool) {
+	if path == "" {
+		return node, true
+	}
+	for _, part := range strings.Split(path, ".") {
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			node = items
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		child, ok := properties[strings.TrimSuffix(part, "[]")].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		node = child
+	}
+	return node, true
+}
+
+// schemaFields flattens a schema into the fields of a parameter form,
+// required fields first at every level
+func schemaFields(node map[string]interface{}, prefix string, depth int) []schemaField {
+	if items, ok := node["items"].(map[string]interface{}); ok {
+		node = items
+		prefix += "[]"
+	}
+	properties, _ := node["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := node["required"].([]interface{}); ok {
+		for _, name := range list {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	names := sortedKeys(properties)
+	ordered := make([]string, 0, len(names))
+	for _, name := range names {
+		if required[name] {
+			ordered = append(ordered, name)
+		}
+	}
+	for _, name := range names {
+		if !required[name] {
+			ordered = append(ordered, name)
+		}
+	}
+
+	var fields []schemaField
+	for _, name := range ordered {
+		child, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		field := schemaField{path: path, fieldType: schemaType(child), required: required[name]}
+		field.description, _ = child["description"].(string)
+		if values, ok := child["enum"].([]interface{}); ok {
+			for _, value := range values {
+				field.enum = append(field.enum, fmt.Sprintf("%v", value))
+			}
+		}
+		if value, ok := child["default"]; ok {
+			data, _ := json.Marshal(value)
+			field.defaultJSON = string(data)
+		}
+		fields = append(fields, field)
+		if depth > 1 {
+			fields = append(fields, schemaFields(child, path, depth-1)...)
+		}
+	}
+	return fields
+}
+
+// formatSchemaField renders one line of a parameter form
+func formatSchemaField(field schemaField) string {
+	indent := strings.Repeat("  ", strings.Count(field.path, "."))
+	marker := "   "
+	if field.required {
+		marker = "❗ "
+	}
+	line := fmt.Sprintf("%s%s%s (%s)", indent, marker, field.path, field.fieldType)
+	if len(field.enum) > 0 {
+		line += " one of: " + strings.Join(field.enum, ", ")
+	}
+	if field.defaultJSON != "" {
+		line += " default: " + field.defaultJSON
+	}
+	if field.description != "" {
+		description := strings.Join(strings.Fields(field.description), " ")
+		if len(description) > 120 {
+			description = description[:117] + "..."
+		}
+		line += " - " + description
+	}
+	return line
+}
+
+func (s *Server) describeResourceSchemaHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	field := strings.Trim(mcp.ParseString(request, "field", "spec"), ".")
+	depth := 3
+	if value := mcp.ParseString(request, "depth", ""); value != "" {
+		if _, err := fmt.Sscanf(value, "%d", &depth); err != nil || depth < 1 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid depth '%s'", value)), nil
+		}
+	}
+
+	crs, err := s.customResourceSchema(ctx, resourceType)
+	if err != nil {
+		recordToolError(ctx, err)
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	node, ok := schemaNode(crs.schema, field)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %s has no field %s", crs.kind, field)), nil
+	}
+
+	result := fmt.Sprintf("📐 Schema: %s (%s)\n", crs.kind, crs.apiVersion)
+	result += "====================\n\n"
+	result += fmt.Sprintf("CRD: %s\n", crs.crd)
+	if crs.namespaced {
+		result += "Scope: namespaced\n"
+	} else {
+		result += "Scope: cluster\n"
+	}
+	if description, ok := node["description"].(string); ok {
+		result += fmt.Sprintf("%s: %s\n", field, strings.Join(strings.Fields(description), " "))
+	}
+
+	fields := schemaFields(node, field, depth)
+	result += fmt.Sprintf("\n📝 Fields of %s (❗ = required):\n", field)
+	for _, f := range fields {
+		result += formatSchemaField(f) + "\n"
+	}
+	if len(fields) == 0 {
+		result += fmt.Sprintf("   %s (%s) takes free-form content\n", field, schemaType(node))
+	}
+	result += fmt.Sprintf("\n💡 Generate the resource with generate_custom_resource resource_type=%s values={\"%s.<field>\": ...}; use field=<path> to see deeper levels", resourceType, field)
+	return mcp.NewToolResultText(result), nil
+}
+
+// setPath sets a value at a dotted path, creating the objects on the way
+func setPath(object map[string]interface{}, path string, value interface{}) error {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, exists := object[part]
+		if !exists {
+			child := make(map[string]interface{})
+			object[part] = child
+			object = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %s is not an object", path, part)
+		}
+		object = child
+	}
+	last := parts[len(parts)-1]
+	if existing, ok := object[last].(map[string]interface{}); ok {
+		if values, ok := value.(map[string]interface{}); ok {
+			for key, item := range values {
+				if err := setPath(existing, key, item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	object[last] = value
+	return nil
+}
+
+// validateSchemaValue checks a value against its schema and returns the
+// problems found, each prefixed with the field path
+func validateSchemaValue(value interface{}, node map[string]interface{}, path string) []string {
+	if node["x-kubernetes-preserve-unknown-fields"] == true && node["properties"] == nil {
+		return nil
+	}
+	if node["x-kubernetes-int-or-string"] == true {
+		switch v := value.(type) {
+		case string:
+			return nil
+		case float64:
+			if v == math.Trunc(v) {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%s: expected an integer or a string", path)}
+	}
+
+	var problems []string
+	if values, ok := node["enum"].([]interface{}); ok {
+		allowed := make([]string, 0, len(values))
+		matched := false
+		for _, item := range values {
+			allowed = append(allowed, fmt.Sprintf("%v", item))
+			if fmt.Sprintf("%v", item) == fmt.Sprintf("%v", value) {
+				matched = true
+			}
+		}
+		if !matched {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %s", path, value, strings.Join(allowed, ", ")))
+		}
+	}
+
+	fieldType, _ := node["type"].(string)
+	switch fieldType {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return append(problems, fmt.Sprintf("%s: expected a string, got %v", path, value))
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			return append(problems, fmt.Sprintf("%s: expected an integer, got %v", path, value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return append(problems, fmt.Sprintf("%s: expected a number, got %v", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(problems, fmt.Sprintf("%s: expected true or false, got %v", path, value))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: expected a list", path))
+		}
+		if itemSchema, ok := node["items"].(map[string]interface{}); ok {
+			for i, item := range items {
+				problems = append(problems, validateSchemaValue(item, itemSchema, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case "object", "":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			if fieldType == "" {
+				return problems
+			}
+			return append(problems, fmt.Sprintf("%s: expected an object", path))
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		if list, ok := node["required"].([]interface{}); ok {
+			for _, name := range list {
+				if name, ok := name.(string); ok {
+					if _, present := object[name]; !present {
+						problems = append(problems, fmt.Sprintf("%s.%s: required field is missing", path, name))
+					}
+				}
+			}
+		}
+		additional, _ := node["additionalProperties"].(map[string]interface{})
+		for _, key := range sortedKeys(object) {
+			if child, ok := properties[key].(map[string]interface{}); ok {
+				problems = append(problems, validateSchemaValue(object[key], child, path+"."+key)...)
+			} else if additional != nil {
+				problems = append(problems, validateSchemaValue(object[key], additional, path+"."+key)...)
+			} else if properties != nil && node["x-kubernetes-preserve-unknown-fields"] != true {
+				problems = append(problems, fmt.Sprintf("%s.%s: unknown field (known: %s)", path, key, strings.Join(sortedKeys(properties), ", ")))
+			}
+		}
+	}
+	return problems
+}
+
+func (s *Server) generateCustomResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	name := strings.TrimSpace(mcp.ParseString(request, "name", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	valuesJSON := mcp.ParseString(request, "values", "{}")
+
+	if name == "" {
+		return mcp.NewToolResultText("❌ Resource name is required"), nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(valuesJSON), &values); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid values JSON: %v", err)), nil
+	}
+
+	crs, err := s.customResourceSchema(ctx, resourceType)
+	if err != nil {
+		recordToolError(ctx, err)
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	object := make(map[string]interface{})
+	for _, key := range sortedKeys(values) {
+		if strings.HasPrefix(key, "metadata") || key == "apiVersion" || key == "kind" {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ %s is set from resource_type, name and namespace", key)), nil
+		}
+		if err := setPath(object, key, values[key]); err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+		}
+	}
+
+	// Validate the fields below the root; metadata, apiVersion and kind are ours
+	var problems []string
+	properties, _ := crs.schema["properties"].(map[string]interface{})
+	if list, ok := crs.schema["required"].([]interface{}); ok {
+		for _, field := range list {
+			if field, ok := field.(string); ok && field != "metadata" && field != "apiVersion" && field != "kind" {
+				if _, present := object[field]; !present {
+					problems = append(problems, fmt.Sprintf("%s: required field is missing", field))
+				}
+			}
+		}
+	}
+	for _, key := range sortedKeys(object) {
+		child, ok := properties[key].(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown field (known: %s)", key, strings.Join(sortedKeys(properties), ", ")))
+			continue
+		}
+		problems = append(problems, validateSchemaValue(object[key], child, key)...)
+	}
+
+	if len(problems) > 0 {
+		result := fmt.Sprintf("❌ The values do not match the %s schema:\n", crs.kind)
+		for _, problem := range problems {
+			result += fmt.Sprintf("   • %s\n", problem)
+		}
+		var missing []schemaField
+		for _, field := range schemaFields(crs.schema, "", 4) {
+			if field.required && !strings.HasPrefix(field.path, "metadata") && !strings.HasPrefix(field.path, "status") {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			result += "\n📝 Required fields:\n"
+			for _, field := range missing {
+				result += formatSchemaField(field) + "\n"
+			}
+		}
+		result += fmt.Sprintf("\n💡 Run describe_resource_schema resource_type=%s for all fields", resourceType)
+		return mcp.NewToolResultText(result), nil
+	}
+
+	object["apiVersion"] = crs.apiVersion
+	object["kind"] = crs.kind
+	metadata := map[string]interface{}{"name": name}
+	if crs.namespaced {
+		metadata["namespace"] = namespace
+	}
+	object["metadata"] = metadata
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		return toolError(ctx, "Failed to generate YAML", err), nil
+	}
+
+	result := fmt.Sprintf("📄 Generated %s\n", crs.kind)
+	result += "====================\n\n"
+	result += fmt.Sprintf("✅ Valid against the %s schema of CRD %s\n\n", crs.apiVersion, crs.crd)
+	result += fmt.Sprintf("```yaml\n%s```\n\n", data)
+	result += "💡 Validate with the API server using create_resource dry_run=true, then create it"
+	return mcp.NewToolResultText(result), nil
+}
+
+// DescribeResourceSchemaHandler is a public wrapper for describeResourceSchemaHandler
+func (s *Server) DescribeResourceSchemaHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.describeResourceSchemaHandler(ctx, request)
+}
+
+// GenerateCustomResourceHandler is a public wrapper for generateCustomResourceHandler
+func (s *Server) GenerateCustomResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.generateCustomResourceHandler(ctx, request)
+}
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// kafkaSchema is a trimmed-down Strimzi Kafka schema
+func kafkaSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"metadata":   map[string]interface{}{"type": "object"},
+			"spec": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"kafka"},
+				"properties": map[string]interface{}{
+					"kafka": map[string]interface{}{
+						"type":        "object",
+						"description": "Configuration of the Kafka cluster.",
+						"required":    []interface{}{"replicas", "listeners"},
+						"properties": map[string]interface{}{
+							"replicas": map[string]interface{}{"type": "integer", "description": "The number of pods in the cluster."},
+							"version":  map[string]interface{}{"type": "string"},
+							"listeners": map[string]interface{}{
+								"type": "array",
+								"items": map[string]interface{}{
+									"type":     "object",
+									"required": []interface{}{"name", "port", "type"},
+									"properties": map[string]interface{}{
+										"name": map[string]interface{}{"type": "string"},
+										"port": map[string]interface{}{"type": "integer"},
+										"type": map[string]interface{}{"type": "string", "enum": []interface{}{"internal", "route", "loadbalancer"}},
+										"tls":  map[string]interface{}{"type": "boolean", "default": false},
+									},
+								},
+							},
+							"config":    map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+							"resources": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"x-kubernetes-int-or-string": true}},
+						},
+					},
+				},
+			},
+			"status": map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+func newSchemaTestServer() *Server {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "kafkas.kafka.strimzi.io"},
+		"spec": map[string]interface{}{
+			"group": "kafka.strimzi.io",
+			"names": map[string]interface{}{"kind": "Kafka", "plural": "kafkas"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1beta1", "served": true},
+				map[string]interface{}{"name": "v1beta2", "served": true, "schema": map[string]interface{}{"openAPIV3Schema": kafkaSchema()}},
+			},
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), crd)
+	return &Server{config: &Config{}, dynamicClient: client, restMapper: mapper}
+}
+
+func schemaRequest(args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func TestSchemaFields(t *testing.T) {
+	node, ok := schemaNode(kafkaSchema(), "spec.kafka")
+	if !ok {
+		t.Fatal("schemaNode(spec.kafka) not found")
+	}
+	var paths []string
+	for _, field := range schemaFields(node, "spec.kafka", 2) {
+		paths = append(paths, field.path)
+	}
+	expected := "spec.kafka.listeners spec.kafka.listeners[].name spec.kafka.listeners[].port spec.kafka.listeners[].type spec.kafka.listeners[].tls spec.kafka.replicas spec.kafka.config spec.kafka.resources spec.kafka.version"
+	if strings.Join(paths, " ") != expected {
+		t.Errorf("schemaFields() = %v, expected %s", paths, expected)
+	}
+
+	if _, ok := schemaNode(kafkaSchema(), "spec.kafka.listeners[].type"); !ok {
+		t.Error("schemaNode(spec.kafka.listeners[].type) not found")
+	}
+	if _, ok := schemaNode(kafkaSchema(), "spec.zookeeper"); ok {
+		t.Error("schemaNode(spec.zookeeper) found an unknown field")
+	}
+}
+
+func TestValidateSchemaValue(t *testing.T) {
+	kafka, _ := schemaNode(kafkaSchema(), "spec.kafka")
+	listener := func(port interface{}, listenerType string) map[string]interface{} {
+		return map[string]interface{}{"name": "plain", "port": port, "type": listenerType}
+	}
+
+	tests := []struct {
+		value    map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{"replicas": float64(3), "listeners": []interface{}{listener(float64(9092), "internal")}}, nil},
+		{map[string]interface{}{"replicas": float64(3), "listeners": []interface{}{}, "config": map[string]interface{}{"anything": "goes"},
+			"resources": map[string]interface{}{"cpu": "2", "replicas": float64(1)}}, nil},
+		{map[string]interface{}{"listeners": []interface{}{}}, []string{"spec.kafka.replicas: required field is missing"}},
+		{map[string]interface{}{"replicas": 2.5, "listeners": []interface{}{}}, []string{"spec.kafka.replicas: expected an integer, got 2.5"}},
+		{map[string]interface{}{"replicas": float64(1), "listeners": []interface{}{listener("9092", "nodeport")}}, []string{
+			"spec.kafka.listeners[0].port: expected an integer, got 9092",
+			"spec.kafka.listeners[0].type: nodeport is not one of internal, route, loadbalancer",
+		}},
+		{map[string]interface{}{"replicas": float64(1), "listeners": []interface{}{}, "storage": "ephemeral"}, []string{
+			"spec.kafka.storage: unknown field (known: config, listeners, replicas, resources, version)",
+		}},
+		{map[string]interface{}{"replicas": float64(1), "listeners": []interface{}{}, "resources": map[string]interface{}{"cpu": 0.5}}, []string{
+			"spec.kafka.resources.cpu: expected an integer or a string",
+		}},
+	}
+	for _, tt := range tests {
+		problems := validateSchemaValue(tt.value, kafka, "spec.kafka")
+		if strings.Join(problems, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("validateSchemaValue(%v) = %q, expected %q", tt.value, problems, tt.expected)
+		}
+	}
+}
+
+func TestDescribeResourceSchema(t *testing.T) {
+	s := newSchemaTestServer()
+	result, err := s.describeResourceSchemaHandler(context.Background(), schemaRequest(map[string]interface{}{"resource_type": "kafkas"}))
+	if err != nil {
+		t.Fatalf("describeResourceSchemaHandler() error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"📐 Schema: Kafka (kafka.strimzi.io/v1beta2)",
+		"CRD: kafkas.kafka.strimzi.io",
+		"❗ spec.kafka (object) - Configuration of the Kafka cluster.",
+		"❗ spec.kafka.replicas (integer) - The number of pods in the cluster.",
+		"❗ spec.kafka.listeners (array of object)",
+		"spec.kafka.resources (map of integer or string)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("describeResourceSchemaHandler() missing %q:\n%s", want, text)
+		}
+	}
+
+	result, _ = s.describeResourceSchemaHandler(context.Background(), schemaRequest(map[string]interface{}{"resource_type": "deployments"}))
+	if text := resultText(result); !strings.Contains(text, "deployments is a built-in resource") {
+		t.Errorf("describeResourceSchemaHandler(deployments) = %q", text)
+	}
+}
+
+func TestGenerateCustomResource(t *testing.T) {
+	s := newSchemaTestServer()
+
+	valid := `{"spec.kafka.replicas": 3, "spec.kafka": {"listeners": [{"name": "plain", "port": 9092, "type": "internal"}]}}`
+	result, err := s.generateCustomResourceHandler(context.Background(), schemaRequest(map[string]interface{}{
+		"resource_type": "Kafka", "name": "events", "namespace": "streaming", "values": valid,
+	}))
+	if err != nil {
+		t.Fatalf("generateCustomResourceHandler() error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"✅ Valid against the kafka.strimzi.io/v1beta2 schema",
+		"apiVersion: kafka.strimzi.io/v1beta2\nkind: Kafka\nmetadata:\n  name: events\n  namespace: streaming\n",
+		"    replicas: 3\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("generateCustomResourceHandler() missing %q:\n%s", want, text)
+		}
+	}
+
+	result, _ = s.generateCustomResourceHandler(context.Background(), schemaRequest(map[string]interface{}{
+		"resource_type": "kafkas", "name": "events", "values": `{"spec.kafka.replicas": 3, "spec.zookeeper.replicas": 3}`,
+	}))
+	text = resultText(result)
+	for _, want := range []string{
+		"❌ The values do not match the Kafka schema",
+		"spec.kafka.listeners: required field is missing",
+		"spec.zookeeper: unknown field (known: kafka)",
+		"❗ spec.kafka.listeners[].port (integer)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("generateCustomResourceHandler(invalid) missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func (s *Server) initCSITools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("collect_storage_diagnostics",
+			mcp.WithDescription("Collect CSI driver diagnostics (controller and node plugin logs including the provisioner, attacher and resizer sidecars, driver pod health, failing VolumeAttachments, PersistentVolumes stuck terminating or failed, and provisioning, attach and mount warning events of every namespace) and report storage root causes. Use when pods hang in ContainerCreating on volumes or claims stay Pending across the cluster"),
+			mcp.WithString("namespace", mcp.Description("Namespace of the CSI driver pods (default openshift-cluster-csi-drivers; e.g. openshift-storage for ODF)")),
+			mcp.WithString("since", mcp.Description("How far back to collect logs (default 1h)")),
+			mcp.WithString("output_dir", mcp.Description("Directory to store the collection")),
+			mcp.WithBoolean("compressed", mcp.Description("Package the collection into a single tar.gz bundle")),
+			mcp.WithTitleAnnotation("Diagnostics: Collect Storage"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.collectStorageDiagnosticsHandler)},
+		{Tool: mcp.NewTool("analyze_storage_diagnostics",
+			mcp.WithDescription("Analyze a storage collection from collect_storage_diagnostics for CSI failure signatures, unready driver pods, attach and detach failures, stuck volumes and provisioning errors"),
+			mcp.WithString("path", mcp.Description("Directory or bundle of the collection"), mcp.Required()),
+			mcp.WithString("export", mcp.Description(exportParamDescription)),
+			mcp.WithTitleAnnotation("Analysis: Storage"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.analyzeStorageDiagnosticsHandler)},
+	}
+}
+
+func (s *Server) collectStorageDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts := &diagnostics.CollectionOptions{
+		OutputDir:   mcp.ParseString(request, "output_dir", ""),
+		IncludeLogs: true,
+		Compressed:  mcp.ParseBoolean(request, "compressed", false),
+		Filters: map[string]string{
+			"since":     mcp.ParseString(request, "since", ""),
+			"namespace": strings.TrimSpace(mcp.ParseString(request, "namespace", "")),
+		},
+	}
+
+	result, err := s.diagnosticCollector.CollectStorage(ctx, opts)
+	if result == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if err != nil {
+		return toolError(ctx, "❌ Failed to collect storage diagnostics", err), nil
+	}
+
+	response := "💾 Storage Collection\n"
+	response += "====================\n\n"
+	response += fmt.Sprintf("CSI driver namespace: %s\n", result.Metadata["namespace"])
+	response += fmt.Sprintf("Driver pods: %s, logs: %s since %s\n", result.Metadata["pods"], result.Metadata["logs"], result.Metadata["since"])
+	response += fmt.Sprintf("Failing volume attachments: %s, volume warning events: %s\n", result.Metadata["attachments"], result.Metadata["events"])
+	response += fmt.Sprintf("📁 Location: %s\n", result.FilePath)
+	if result.Metadata["bundle"] == "" {
+		response += fmt.Sprintf("📋 Manifest: %s\n", result.Metadata["manifest"])
+	}
+	response += fmt.Sprintf("📦 Size: %.2f MB\n", float64(result.Size)/(1024*1024))
+	response += fmt.Sprintf("⏱️ Duration: %v\n", result.Duration.Round(time.Millisecond))
+	if failed := result.Metadata["failed"]; failed != "0" {
+		response += fmt.Sprintf("\n⚠️ %s logs or resources could not be collected; see the manifest for the errors.\n", failed)
+	}
+	response += "\n" + bundleNote(result)
+
+	analysis, err := s.analysisEngine.AnalyzeStorage(ctx, result.FilePath)
+	if err != nil {
+		recordToolError(ctx, err)
+		response += fmt.Sprintf("\n❌ Analysis failed: %v\n", err)
+		return mcp.NewToolResultText(response), nil
+	}
+	response += "\n" + s.formatAnalysisResult(ctx, analysis)
+	return mcp.NewToolResultText(response), nil
+}
+
+func (s *Server) analyzeStorageDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := mcp.ParseString(request, "path", "")
+	if path == "" {
+		return mcp.NewToolResultText("❌ path is required"), nil
+	}
+	format, err := diagnostics.ParseExportFormat(mcp.ParseString(request, "export", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	result, err := s.analysisEngine.AnalyzeStorage(ctx, path)
+	if err != nil {
+		return toolError(ctx, "❌ Failed to analyze the storage collection", err), nil
+	}
+	return mcp.NewToolResultText(s.formatAnalysisResult(ctx, result) + s.exportNote(ctx, result, format)), nil
+}
+
+// CollectStorageDiagnosticsHandler is a public wrapper for collectStorageDiagnosticsHandler
+func (s *Server) CollectStorageDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.collectStorageDiagnosticsHandler(ctx, request)
+}
+
+// AnalyzeStorageDiagnosticsHandler is a public wrapper for analyzeStorageDiagnosticsHandler
+func (s *Server) AnalyzeStorageDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.analyzeStorageDiagnosticsHandler(ctx, request)
+}
@@ -0,0 +1,270 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// ToolDeprecation maps a deprecated tool name to the tool that replaced it.
+// The old name keeps working until RemovedIn, with a warning in every result.
+type ToolDeprecation struct {
+	Name         string            `json:"name"`
+	ReplacedBy   string            `json:"replaced_by"`
+	DeprecatedIn string            `json:"deprecated_in"`
+	RemovedIn    string            `json:"removed_in"`
+	Params       map[string]string `json:"params,omitempty"` // old parameter name -> new name
+	Note         string            `json:"note,omitempty"`
+}
+
+// toolDeprecations lists the renamed tools. Add an entry here instead of
+// keeping the old tool around when renaming one, so saved runbooks and
+// client configurations keep working.
+var toolDeprecations = []ToolDeprecation{
+	{Name: "get_pods", ReplacedBy: "list_pods", DeprecatedIn: "1.0.0", RemovedIn: "2.0.0",
+		Note: "list tools are named list_<kind>"},
+	{Name: "get_nodes", ReplacedBy: "list_nodes", DeprecatedIn: "1.0.0", RemovedIn: "2.0.0",
+		Note: "list tools are named list_<kind>"},
+	{Name: "get_logs", ReplacedBy: "get_pod_logs", DeprecatedIn: "1.0.0", RemovedIn: "2.0.0",
+		Params: map[string]string{"pod": "pod_name"}},
+	{Name: "must_gather", ReplacedBy: "openshift_must_gather", DeprecatedIn: "1.0.0", RemovedIn: "2.0.0"},
+	{Name: "diagnose", ReplacedBy: "openshift_diagnose", DeprecatedIn: "1.0.0", RemovedIn: "2.0.0"},
+}
+
+// aliasUsage counts calls made through deprecated names since the server started
+type aliasUsage struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	lastUsed map[string]time.Time
+}
+
+func (u *aliasUsage) record(name string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.calls == nil {
+		u.calls = make(map[string]int)
+		u.lastUsed = make(map[string]time.Time)
+	}
+	u.calls[name]++
+	u.lastUsed[name] = time.Now()
+}
+
+func (u *aliasUsage) get(name string) (int, time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.calls[name], u.lastUsed[name]
+}
+
+// deprecationWarning is prepended to the result of a call through an alias
+func deprecationWarning(deprecation ToolDeprecation) string {
+	warning := fmt.Sprintf("⚠️  Tool '%s' is deprecated since %s and will be removed in %s; use '%s' instead",
+		deprecation.Name, deprecation.DeprecatedIn, deprecation.RemovedIn, deprecation.ReplacedBy)
+	if len(deprecation.Params) > 0 {
+		var renames []string
+		for _, old := range sortedKeys(deprecation.Params) {
+			renames = append(renames, fmt.Sprintf("%s → %s", old, deprecation.Params[old]))
+		}
+		warning += fmt.Sprintf(" (parameters renamed: %s)", strings.Join(renames, ", "))
+	}
+	return warning
+}
+
+// aliasTool describes a deprecated name with the replacement's schema,
+// using the old parameter names
+func aliasTool(deprecation ToolDeprecation, target mcp.Tool) mcp.Tool {
+	tool := target
+	tool.Name = deprecation.Name
+	tool.Description = fmt.Sprintf("Deprecated, use %s. %s", deprecation.ReplacedBy, target.Description)
+	if len(deprecation.Params) == 0 {
+		return tool
+	}
+
+	oldNames := make(map[string]string, len(deprecation.Params))
+	for old, renamed := range deprecation.Params {
+		oldNames[renamed] = old
+	}
+	properties := make(map[string]any, len(target.InputSchema.Properties))
+	for name, property := range target.InputSchema.Properties {
+		if old, ok := oldNames[name]; ok {
+			name = old
+		}
+		properties[name] = property
+	}
+	tool.InputSchema.Properties = properties
+	tool.InputSchema.Required = nil
+	for _, name := range target.InputSchema.Required {
+		if old, ok := oldNames[name]; ok {
+			name = old
+		}
+		tool.InputSchema.Required = append(tool.InputSchema.Required, name)
+	}
+	return tool
+}
+
+// withDeprecation runs the replacement of a deprecated tool, renaming the
+// old parameters, and prepends a deprecation warning to its result
+func (s *Server) withDeprecation(deprecation ToolDeprecation, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.aliasUsage.record(deprecation.Name)
+		logrus.WithFields(logrus.Fields{"tool": deprecation.Name, "replaced_by": deprecation.ReplacedBy}).Warn("Deprecated tool name used")
+
+		if args := request.GetArguments(); len(deprecation.Params) > 0 && args != nil {
+			renamed := make(map[string]any, len(args))
+			for name, value := range args {
+				if newName, ok := deprecation.Params[name]; ok {
+					name = newName
+				}
+				renamed[name] = value
+			}
+			request.Params.Arguments = renamed
+		}
+		request.Params.Name = deprecation.ReplacedBy
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+		warning := deprecationWarning(deprecation)
+		if len(result.Content) > 0 {
+			if text, ok := mcp.AsTextContent(result.Content[0]); ok {
+				result.Content[0] = mcp.NewTextContent(warning + "\n\n" + text.Text)
+				return result, nil
+			}
+		}
+		result.Content = append([]mcp.Content{mcp.NewTextContent(warning)}, result.Content...)
+		return result, nil
+	}
+}
+
+// registerToolAliases keeps the deprecated names of registered tools callable.
+// It is run again whenever a tool set is registered, so an alias becomes
+// available as soon as its replacement is.
+func (s *Server) registerToolAliases() {
+	var added []server.ServerTool
+	s.toolsMu.Lock()
+	for _, deprecation := range toolDeprecations {
+		handler, ok := s.tools[deprecation.ReplacedBy]
+		if !ok {
+			continue
+		}
+		if _, exists := s.tools[deprecation.Name]; exists {
+			if _, aliased := s.toolDefs[deprecation.Name]; aliased {
+				logrus.WithField("tool", deprecation.Name).Warn("Deprecated tool name is still registered as a tool; not aliasing it")
+			}
+			continue
+		}
+		aliasHandler := s.withDeprecation(deprecation, handler)
+		s.tools[deprecation.Name] = aliasHandler
+		added = append(added, server.ServerTool{Tool: aliasTool(deprecation, s.toolDefs[deprecation.ReplacedBy]), Handler: aliasHandler})
+	}
+	s.toolsMu.Unlock()
+	if s.server != nil && len(added) > 0 {
+		s.server.AddTools(added...)
+	}
+}
+
+// activeDeprecations returns the deprecations whose replacement is registered
+func (s *Server) activeDeprecations() []ToolDeprecation {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	var active []ToolDeprecation
+	for _, deprecation := range toolDeprecations {
+		if _, ok := s.toolDefs[deprecation.ReplacedBy]; ok {
+			active = append(active, deprecation)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Name < active[j].Name })
+	return active
+}
+
+// deprecatedNameReference is a deprecated tool name found in a runbook or
+// client configuration
+type deprecatedNameReference struct {
+	line        int
+	deprecation ToolDeprecation
+}
+
+// findDeprecatedNames finds deprecated tool names in text and returns the
+// text with them replaced
+func findDeprecatedNames(text string, deprecations []ToolDeprecation) ([]deprecatedNameReference, string) {
+	var references []deprecatedNameReference
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		for _, deprecation := range deprecations {
+			pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(deprecation.Name) + `\b`)
+			if !pattern.MatchString(line) {
+				continue
+			}
+			references = append(references, deprecatedNameReference{line: i + 1, deprecation: deprecation})
+			line = pattern.ReplaceAllString(line, deprecation.ReplacedBy)
+		}
+		lines[i] = line
+	}
+	return references, strings.Join(lines, "\n")
+}
+
+func (s *Server) toolMigrationReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	deprecations := s.activeDeprecations()
+	text := mcp.ParseString(request, "text", "")
+	rewrite := parseBoolString(mcp.ParseString(request, "rewrite", "false"))
+
+	result := "🔀 Tool Migration Report\n"
+	result += "========================\n\n"
+	result += fmt.Sprintf("Server version: %s\n", Version)
+
+	if text == "" {
+		result += fmt.Sprintf("\n📋 Deprecated tool names (%d):\n", len(deprecations))
+		for _, deprecation := range deprecations {
+			calls, lastUsed := s.aliasUsage.get(deprecation.Name)
+			result += fmt.Sprintf("• %s → %s (deprecated in %s, removed in %s)", deprecation.Name, deprecation.ReplacedBy, deprecation.DeprecatedIn, deprecation.RemovedIn)
+			if calls > 0 {
+				result += fmt.Sprintf(" - ⚠️  called %d time(s), last %s ago", calls, formatAge(lastUsed))
+			}
+			result += "\n"
+			for _, old := range sortedKeys(deprecation.Params) {
+				result += fmt.Sprintf("   parameter %s → %s\n", old, deprecation.Params[old])
+			}
+			if deprecation.Note != "" {
+				result += fmt.Sprintf("   %s\n", deprecation.Note)
+			}
+		}
+		if len(deprecations) == 0 {
+			result += "✅ No deprecated tool names in this profile\n"
+		}
+		result += "\n💡 Pass a runbook or client configuration as text to find the deprecated names it uses, and rewrite=true to get it migrated"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	references, migrated := findDeprecatedNames(text, deprecations)
+	if len(references) == 0 {
+		result += "\n✅ The text uses no deprecated tool names"
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("\n⚠️  Found %d reference(s) to deprecated tools:\n", len(references))
+	for _, reference := range references {
+		result += fmt.Sprintf("• line %d: %s → %s (removed in %s)\n", reference.line, reference.deprecation.Name, reference.deprecation.ReplacedBy, reference.deprecation.RemovedIn)
+		for _, old := range sortedKeys(reference.deprecation.Params) {
+			result += fmt.Sprintf("   also rename parameter %s → %s\n", old, reference.deprecation.Params[old])
+		}
+	}
+	if rewrite {
+		result += "\n📝 Migrated text (tool names only; check renamed parameters by hand):\n"
+		result += fmt.Sprintf("```\n%s\n```", migrated)
+	} else {
+		result += "\n💡 Run again with rewrite=true to get the migrated text"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// ToolMigrationReportHandler is a public wrapper for toolMigrationReportHandler
+func (s *Server) ToolMigrationReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.toolMigrationReportHandler(ctx, request)
+}
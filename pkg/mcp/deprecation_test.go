@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolAliases(t *testing.T) {
+	s := newPolicyTestServer(false)
+	var received mcp.CallToolRequest
+	s.toolDefs["get_pod_logs"] = mcp.NewTool("get_pod_logs",
+		mcp.WithString("pod_name", mcp.Required()),
+		mcp.WithString("namespace"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+	s.tools["get_pod_logs"] = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		received = request
+		return mcp.NewToolResultText("logs of " + mcp.ParseString(request, "pod_name", "")), nil
+	}
+	s.registerToolAliases()
+
+	if !s.HasTool("get_pods") || !s.HasTool("get_logs") || s.HasTool("must_gather") {
+		t.Errorf("aliases registered: get_pods %v, get_logs %v, must_gather %v, expected only those with a registered replacement",
+			s.HasTool("get_pods"), s.HasTool("get_logs"), s.HasTool("must_gather"))
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_logs"
+	request.Params.Arguments = map[string]interface{}{"pod": "web-1", "namespace": "shop"}
+	result, err := s.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CallTool(get_logs) error = %v", err)
+	}
+	expected := "⚠️  Tool 'get_logs' is deprecated since 1.0.0 and will be removed in 2.0.0; use 'get_pod_logs' instead (parameters renamed: pod → pod_name)\n\nlogs of web-1"
+	if text := resultText(result); text != expected {
+		t.Errorf("CallTool(get_logs) = %q, expected %q", text, expected)
+	}
+	if received.Params.Name != "get_pod_logs" || received.GetArguments()["namespace"] != "shop" {
+		t.Errorf("replacement received %+v", received.Params)
+	}
+	if calls, _ := s.aliasUsage.get("get_logs"); calls != 1 {
+		t.Errorf("get_logs calls = %d, expected 1", calls)
+	}
+
+	tool := aliasTool(toolDeprecations[2], s.toolDefs["get_pod_logs"])
+	if _, ok := tool.InputSchema.Properties["pod"]; !ok || len(tool.InputSchema.Required) != 1 || tool.InputSchema.Required[0] != "pod" {
+		t.Errorf("aliasTool(get_logs) schema = %+v", tool.InputSchema)
+	}
+}
+
+func TestFindDeprecatedNames(t *testing.T) {
+	runbook := "1. Run get_pods in shop\n2. Run get_pod_logs for the failing pod\n3. Run get_logs pod=web-1 and get_nodes"
+	references, migrated := findDeprecatedNames(runbook, toolDeprecations)
+
+	var found []string
+	for _, reference := range references {
+		found = append(found, reference.deprecation.Name)
+	}
+	if strings.Join(found, ",") != "get_pods,get_nodes,get_logs" || references[0].line != 1 || references[2].line != 3 {
+		t.Errorf("findDeprecatedNames() = %+v", references)
+	}
+	expected := "1. Run list_pods in shop\n2. Run get_pod_logs for the failing pod\n3. Run get_pod_logs pod=web-1 and list_nodes"
+	if migrated != expected {
+		t.Errorf("findDeprecatedNames() migrated = %q, expected %q", migrated, expected)
+	}
+}
+
+func TestToolMigrationReport(t *testing.T) {
+	s := newPolicyTestServer(false)
+	s.registerToolAliases()
+
+	request := mcp.CallToolRequest{}
+	result, _ := s.toolMigrationReportHandler(context.Background(), request)
+	text := resultText(result)
+	if !strings.Contains(text, "• get_pods → list_pods (deprecated in 1.0.0, removed in 2.0.0)") || strings.Contains(text, "get_nodes") {
+		t.Errorf("toolMigrationReportHandler() = %s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"text": `{"steps": [{"tool": "get_pods"}]}`, "rewrite": "true"}
+	result, _ = s.toolMigrationReportHandler(context.Background(), request)
+	text = resultText(result)
+	for _, want := range []string{"• line 1: get_pods → list_pods (removed in 2.0.0)", `{"steps": [{"tool": "list_pods"}]}`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("toolMigrationReportHandler(text) missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,623 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/network"
+)
+
+const (
+	defaultDNSWindow = 5 * time.Minute
+	maxDNSWindow     = time.Hour
+	dnsLookupTimeout = 15 * time.Second
+	// maxDNSLookupPods bounds the pods lookups are run from
+	maxDNSLookupPods = 5
+
+	// windowPlaceholder is replaced by the rate window of a query
+	windowPlaceholder = "$window"
+
+	// Cluster DNS runs in openshift-dns: CoreDNS in the dns-default
+	// DaemonSet, and a node-resolver pod with the host network and dig on
+	// every node, which lookups from the nodes run in
+	dnsNamespace          = "openshift-dns"
+	coreDNSDaemonSet      = "dns-default"
+	coreDNSContainer      = "dns"
+	coreDNSSelector       = "dns.operator.openshift.io/daemonset-dns=default"
+	nodeResolverSelector  = "dns.operator.openshift.io/daemonset-node-resolver"
+	nodeResolverContainer = "dns-node-resolver"
+
+	defaultDNSProbeNodes = 3
+	maxDNSProbeNodes     = 10
+)
+
+var dnsOperatorsGVR = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "dnses"}
+
+// coreDNSQueries are the instant queries behind each DNS telemetry field.
+// Ratios with no failing series evaluate to 0 rather than to no data.
+var coreDNSQueries = map[string]string{
+	diagnostics.DNSMetricRequests:        `sum(rate(coredns_dns_requests_total[$window]))`,
+	diagnostics.DNSMetricServfail:        `(sum(rate(coredns_dns_responses_total{rcode="SERVFAIL"}[$window])) or vector(0)) / sum(rate(coredns_dns_responses_total[$window]))`,
+	diagnostics.DNSMetricCacheHits:       `sum(rate(coredns_cache_hits_total[$window])) / (sum(rate(coredns_cache_hits_total[$window])) + sum(rate(coredns_cache_misses_total[$window])))`,
+	diagnostics.DNSMetricUpstreamLatency: `histogram_quantile(0.99, sum by (le) (rate(coredns_forward_request_duration_seconds_bucket[$window])))`,
+	diagnostics.DNSMetricUpstreamErrors:  `(sum(rate(coredns_forward_responses_total{rcode=~"SERVFAIL|REFUSED"}[$window])) or vector(0)) / sum(rate(coredns_forward_requests_total[$window]))`,
+	diagnostics.DNSMetricHealthchecks:    `sum(increase(coredns_forward_healthcheck_broken_total[$window])) or vector(0)`,
+	diagnostics.DNSMetricPodServfail:     `(sum by (pod) (rate(coredns_dns_responses_total{rcode="SERVFAIL"}[$window])) or sum by (pod) (rate(coredns_dns_responses_total[$window])) * 0) / sum by (pod) (rate(coredns_dns_responses_total[$window]))`,
+}
+
+// promVectorValues reads an instant vector into its values by the label
+// given, skipping samples that are not numbers (e.g. 0/0)
+func promVectorValues(response *promResponse, label string) (map[string]float64, error) {
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("expected a vector result, got %q", response.Data.ResultType)
+	}
+	var samples []promSample
+	if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64, len(samples))
+	for _, sample := range samples {
+		text, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		values[sample.Metric[label]] = value
+	}
+	return values, nil
+}
+
+// coreDNSTelemetry queries the CoreDNS metrics over window. Metrics without
+// data are listed as missing; the first query error is returned when no
+// metric could be read.
+func (s *Server) coreDNSTelemetry(ctx context.Context, endpoint metricsEndpoint, window time.Duration) (diagnostics.DNSTelemetry, error) {
+	telemetry := diagnostics.DNSTelemetry{Window: window}
+	rangeText := strconv.Itoa(int(window.Seconds())) + "s"
+
+	var firstErr error
+	read := 0
+	for _, metric := range sortedKeys(coreDNSQueries) {
+		query := strings.ReplaceAll(coreDNSQueries[metric], windowPlaceholder, rangeText)
+		label := ""
+		if metric == diagnostics.DNSMetricPodServfail {
+			label = "pod"
+		}
+		response, err := s.queryMetrics(ctx, endpoint, query, "")
+		var values map[string]float64
+		if err == nil {
+			values, err = promVectorValues(response, label)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", metric, err)
+			}
+			telemetry.Missing = append(telemetry.Missing, metric)
+			continue
+		}
+		value, ok := values[""]
+		if len(values) == 0 || (label == "" && !ok) {
+			telemetry.Missing = append(telemetry.Missing, metric)
+			continue
+		}
+		read++
+
+		switch metric {
+		case diagnostics.DNSMetricRequests:
+			telemetry.RequestRate = value
+		case diagnostics.DNSMetricServfail:
+			telemetry.ServfailRatio = value
+		case diagnostics.DNSMetricCacheHits:
+			telemetry.CacheHitRatio = value
+		case diagnostics.DNSMetricUpstreamLatency:
+			telemetry.UpstreamLatencyP99 = time.Duration(value * float64(time.Second))
+		case diagnostics.DNSMetricUpstreamErrors:
+			telemetry.UpstreamErrorRatio = value
+		case diagnostics.DNSMetricHealthchecks:
+			telemetry.HealthcheckFailed = value
+		case diagnostics.DNSMetricPodServfail:
+			telemetry.PodServfailRatio = values
+		}
+	}
+	if read == 0 && firstErr != nil {
+		return telemetry, firstErr
+	}
+	return telemetry, nil
+}
+
+// dnsLookupPods returns the running pods lookups are run from: the named
+// pods, or the running pods of the namespace when none is named
+func (s *Server) dnsLookupPods(ctx context.Context, namespace, names string) ([]corev1.Pod, []string, error) {
+	var pods []corev1.Pod
+	var skipped []string
+	if names == "" {
+		list, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Running"})
+		if err != nil {
+			return nil, nil, err
+		}
+		pods = list.Items
+	} else {
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			pod, err := s.k8sClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+			pods = append(pods, *pod)
+		}
+	}
+
+	var running []corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			skipped = append(skipped, fmt.Sprintf("%s: %s, lookups need a running pod", pod.Name, pod.Status.Phase))
+			continue
+		}
+		if len(running) == maxDNSLookupPods {
+			skipped = append(skipped, fmt.Sprintf("%s: only %d pods are checked", pod.Name, maxDNSLookupPods))
+			continue
+		}
+		running = append(running, pod)
+	}
+	return running, skipped, nil
+}
+
+// podDNSSymptoms runs the DNS workflow of the network troubleshooter in a
+// pod: its resolv.conf and a lookup of a cluster and an external name
+func (s *Server) podDNSSymptoms(ctx context.Context, pod corev1.Pod, externalName string) (diagnostics.DNSSymptoms, error) {
+	exec := s.podExec
+	if exec == nil {
+		exec = s.remoteExec
+	}
+	container, _, err := selectContainer(&pod, "")
+	if err != nil {
+		return diagnostics.DNSSymptoms{}, err
+	}
+	run := func(command ...string) (network.DNSLookup, error) {
+		execCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+		defer cancel()
+		stdout := &cappedWriter{limit: maxExecOutputBytes}
+		stderr := &cappedWriter{limit: maxExecOutputBytes}
+		err := exec(execCtx, pod.Namespace, pod.Name, container, command, stdout, stderr)
+		output := stdout.buf.String() + stderr.buf.String()
+		var exitErr interface{ ExitStatus() int }
+		switch {
+		case err == nil:
+			return network.DNSLookup{Output: output}, nil
+		case strings.Contains(output+err.Error(), "executable file not found"),
+			errors.As(err, &exitErr) && exitErr.ExitStatus() == 127:
+			return network.DNSLookup{}, fmt.Errorf("%s is not available in container %s", command[0], container)
+		case errors.Is(execCtx.Err(), context.DeadlineExceeded):
+			return network.DNSLookup{Output: output + "\n;; connection timed out", Failed: true}, nil
+		case errors.As(err, &exitErr):
+			return network.DNSLookup{Output: output, Failed: true}, nil
+		}
+		return network.DNSLookup{}, err
+	}
+
+	resolvConf, err := run("cat", "/etc/resolv.conf")
+	if err != nil {
+		return diagnostics.DNSSymptoms{}, err
+	}
+	internal, err := run("nslookup", network.InternalLookupName)
+	if err != nil {
+		return diagnostics.DNSSymptoms{}, err
+	}
+	external, err := run("nslookup", externalName)
+	if err != nil {
+		return diagnostics.DNSSymptoms{}, err
+	}
+	podInfo := network.PodInfo{PodName: pod.Name, Namespace: pod.Namespace, NodeName: pod.Spec.NodeName, Found: true}
+	return network.ParseDNSLookups(podInfo, resolvConf.Output, internal, external), nil
+}
+
+// dnsOperatorConfig reads the upstream resolvers CoreDNS forwards to and the
+// service address from dns.operator/default. Upstreams of type
+// SystemResolvConf are left out: they are each node's resolv.conf.
+func (s *Server) dnsOperatorConfig(ctx context.Context) (upstreams []string, serviceIP string, err error) {
+	if s.dynamicClient == nil {
+		return nil, "", fmt.Errorf("dynamic client not available")
+	}
+	dns, err := s.dynamicClient.Resource(dnsOperatorsGVR).Get(ctx, "default", metav1.GetOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	serviceIP, _, _ = unstructured.NestedString(dns.Object, "status", "clusterIP")
+	resolvers, _, _ := unstructured.NestedSlice(dns.Object, "spec", "upstreamResolvers", "upstreams")
+	for _, r := range resolvers {
+		resolver, ok := r.(map[string]interface{})
+		if !ok || resolver["type"] != "Network" {
+			continue
+		}
+		address, _ := resolver["address"].(string)
+		if address == "" {
+			continue
+		}
+		port := "53"
+		if value, ok := resolver["port"].(int64); ok && value > 0 {
+			port = strconv.FormatInt(value, 10)
+		}
+		upstreams = append(upstreams, net.JoinHostPort(address, port))
+	}
+	return upstreams, serviceIP, nil
+}
+
+// coreDNSPods reads the health of the CoreDNS pods and how many the
+// DaemonSet wants
+func (s *Server) coreDNSPods(ctx context.Context) ([]diagnostics.CoreDNSPod, int32, error) {
+	list, err := s.k8sClient.CoreV1().Pods(dnsNamespace).List(ctx, metav1.ListOptions{LabelSelector: coreDNSSelector})
+	if err != nil {
+		return nil, 0, err
+	}
+	pods := make([]diagnostics.CoreDNSPod, 0, len(list.Items))
+	for i := range list.Items {
+		pod := &list.Items[i]
+		entry := diagnostics.CoreDNSPod{Name: pod.Name, Node: pod.Spec.NodeName, Phase: string(pod.Status.Phase), Ready: podReady(pod)}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != coreDNSContainer {
+				continue
+			}
+			entry.Restarts = status.RestartCount
+			switch {
+			case status.State.Waiting != nil:
+				entry.Reason = status.State.Waiting.Reason
+			case status.LastTerminationState.Terminated != nil:
+				entry.Reason = status.LastTerminationState.Terminated.Reason
+			}
+		}
+		pods = append(pods, entry)
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	var desired int32
+	daemonSet, err := s.k8sClient.AppsV1().DaemonSets(dnsNamespace).Get(ctx, coreDNSDaemonSet, metav1.GetOptions{})
+	if err == nil {
+		desired = daemonSet.Status.DesiredNumberScheduled
+	} else if !apierrors.IsNotFound(err) {
+		return pods, 0, err
+	}
+	return pods, desired, nil
+}
+
+// dnsProbeNodes picks the node-resolver pods lookups run from, one per node:
+// the named nodes, or the first nodes by name. Nodes that cannot be probed
+// are returned with why.
+func (s *Server) dnsProbeNodes(ctx context.Context, names []string, limit int) ([]corev1.Pod, []string, error) {
+	list, err := s.k8sClient.CoreV1().Pods(dnsNamespace).List(ctx, metav1.ListOptions{LabelSelector: nodeResolverSelector})
+	if err != nil {
+		return nil, nil, err
+	}
+	byNode := make(map[string]corev1.Pod)
+	for _, pod := range list.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.Spec.NodeName != "" {
+			byNode[pod.Spec.NodeName] = pod
+		}
+	}
+
+	var pods []corev1.Pod
+	var skipped []string
+	if len(names) == 0 {
+		names = sortedKeys(byNode)
+	}
+	for _, node := range names {
+		pod, ok := byNode[node]
+		switch {
+		case !ok:
+			skipped = append(skipped, fmt.Sprintf("%s: no running node-resolver pod", node))
+		case len(pods) == limit:
+			skipped = append(skipped, fmt.Sprintf("%s: only %d nodes are probed", node, limit))
+		default:
+			pods = append(pods, pod)
+		}
+	}
+	return pods, skipped, nil
+}
+
+// probeNodeDNS looks up a cluster and an external name through the DNS
+// service, and the external name from each upstream resolver directly, from
+// the node a node-resolver pod runs on. Without configured upstreams the
+// node's own resolv.conf nameservers are asked, as CoreDNS does.
+func (s *Server) probeNodeDNS(ctx context.Context, pod corev1.Pod, state diagnostics.ClusterDNSState) ([]diagnostics.DNSProbe, error) {
+	exec := s.podExec
+	if exec == nil {
+		exec = s.remoteExec
+	}
+	run := func(command ...string) (string, error) {
+		execCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+		defer cancel()
+		stdout := &cappedWriter{limit: maxExecOutputBytes}
+		stderr := &cappedWriter{limit: maxExecOutputBytes}
+		err := exec(execCtx, pod.Namespace, pod.Name, nodeResolverContainer, command, stdout, stderr)
+		output := stdout.buf.String() + stderr.buf.String()
+		var exitErr interface{ ExitStatus() int }
+		switch {
+		case err == nil:
+			return output, nil
+		case strings.Contains(output+err.Error(), "executable file not found"),
+			errors.As(err, &exitErr) && exitErr.ExitStatus() == 127:
+			return "", fmt.Errorf("%s is not available in %s/%s", command[0], pod.Namespace, pod.Name)
+		case errors.Is(execCtx.Err(), context.DeadlineExceeded):
+			return output + "\n;; connection timed out", nil
+		case errors.As(err, &exitErr):
+			// dig exits non-zero when no server answers; its output says why
+			return output, nil
+		}
+		return "", err
+	}
+
+	upstreams := state.Upstreams
+	if len(upstreams) == 0 {
+		resolvConf, err := run("cat", "/etc/resolv.conf")
+		if err != nil {
+			return nil, err
+		}
+		upstreams = diagnostics.ParseNameservers(resolvConf)
+	}
+
+	type target struct{ kind, server, name string }
+	var targets []target
+	if state.ServiceIP != "" {
+		targets = append(targets,
+			target{diagnostics.DNSProbeCluster, state.ServiceIP, state.ClusterName},
+			target{diagnostics.DNSProbeExternal, state.ServiceIP, state.ExternalLookup})
+	}
+	for _, upstream := range upstreams {
+		targets = append(targets, target{diagnostics.DNSProbeUpstream, upstream, state.ExternalLookup})
+	}
+
+	var probes []diagnostics.DNSProbe
+	for _, target := range targets {
+		host, port, err := net.SplitHostPort(target.server)
+		if err != nil {
+			host, port = target.server, "53"
+		}
+		probe := diagnostics.DNSProbe{Node: pod.Spec.NodeName, Kind: target.kind, Server: target.server, Name: target.name}
+		output, err := run("dig", "+time=2", "+tries=1", "-p", port, "@"+host, target.name)
+		if err != nil {
+			if len(probes) == 0 {
+				return nil, err
+			}
+			probe.Status, probe.Error = diagnostics.DNSStatusError, err.Error()
+		} else {
+			probe.Status, probe.Latency = diagnostics.ParseDigOutput(output)
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+func (s *Server) initDNSTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("check_dns_telemetry",
+			mcp.WithDescription("Check cluster DNS from the CoreDNS metrics (SERVFAIL rate, cache hit ratio, upstream latency and errors) and, given a namespace, from DNS lookups run inside its pods; the two are correlated to tell CoreDNS problems from failing upstream resolvers and from pods that cannot reach CoreDNS"),
+			mcp.WithString("namespace", mcp.Description("Run lookups from the running pods of this namespace (at most 5)")),
+			mcp.WithString("pods", mcp.Description("Comma-separated pods of the namespace to run lookups from instead of all running pods")),
+			mcp.WithString("external_name", mcp.Description(fmt.Sprintf("External name to look up from the pods (default %s)", network.ExternalLookupName))),
+			mcp.WithString("window", mcp.Description("Rate window of the metrics (default 5m, at most 1h)")),
+			mcp.WithTitleAnnotation("Network: Check DNS Telemetry"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.checkDNSTelemetryHandler)},
+		{Tool: mcp.NewTool("diagnose_cluster_dns",
+			mcp.WithDescription("Diagnose cluster DNS end to end: the DNS operator status, the health of the CoreDNS pods, whether the upstream resolvers answer, and test lookups of a cluster and an external name run from several nodes; reports where lookups fail as structured issues"),
+			mcp.WithString("nodes", mcp.Description("Comma-separated nodes to run the lookups from (default: the first nodes by name)")),
+			mcp.WithString("max_nodes", mcp.Description(fmt.Sprintf("How many nodes to run lookups from (default %d, at most %d)", defaultDNSProbeNodes, maxDNSProbeNodes))),
+			mcp.WithString("external_name", mcp.Description(fmt.Sprintf("External name to look up (default %s)", network.ExternalLookupName))),
+			mcp.WithTitleAnnotation("Network: Diagnose Cluster DNS"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.diagnoseClusterDNSHandler)},
+	}
+}
+
+func (s *Server) checkDNSTelemetryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	podNames := strings.TrimSpace(mcp.ParseString(request, "pods", ""))
+	if podNames != "" && namespace == "" {
+		return mcp.NewToolResultText("❌ namespace is required with pods"), nil
+	}
+	externalName := strings.TrimSpace(mcp.ParseString(request, "external_name", network.ExternalLookupName))
+	window := defaultDNSWindow
+	if value := mcp.ParseString(request, "window", ""); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < time.Minute || parsed > maxDNSWindow {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid window '%s': expected a duration between 1m and %s, e.g. 10m", value, maxDNSWindow)), nil
+		}
+		window = parsed
+	}
+
+	response := "🌐 DNS Telemetry\n"
+	response += "================\n\n"
+
+	endpoint, err := s.discoverMetricsEndpoint(ctx, "")
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Cannot find a metrics querier: %v", err)), nil
+	}
+	telemetry, metricsErr := s.coreDNSTelemetry(ctx, endpoint, window)
+	if metricsErr != nil {
+		response += fmt.Sprintf("❌ CoreDNS metrics unavailable: %v\n", metricsErr)
+		response += "💡 The CoreDNS metrics live in openshift-dns and need the cluster-monitoring-view role\n\n"
+	} else {
+		response += fmt.Sprintf("Querier: %s (%s)\n", endpoint.URL, endpoint.Source)
+		response += fmt.Sprintf("Window: %s\n\n", window)
+		response += "📊 CoreDNS:\n"
+		response += fmt.Sprintf("• Queries: %.1f/s\n", telemetry.RequestRate)
+		response += fmt.Sprintf("• SERVFAIL: %.1f%%\n", telemetry.ServfailRatio*100)
+		response += fmt.Sprintf("• Cache hits: %.1f%%\n", telemetry.CacheHitRatio*100)
+		response += fmt.Sprintf("• Upstream p99 latency: %s\n", telemetry.UpstreamLatencyP99.Round(time.Millisecond))
+		response += fmt.Sprintf("• Upstream errors: %.1f%%\n\n", telemetry.UpstreamErrorRatio*100)
+	}
+
+	var symptoms []diagnostics.DNSSymptoms
+	if namespace != "" && s.k8sClient != nil {
+		pods, skipped, err := s.dnsLookupPods(ctx, namespace, podNames)
+		if err != nil {
+			return toolError(ctx, fmt.Sprintf("Failed to list pods in %s", namespace), err), nil
+		}
+		response += fmt.Sprintf("🔎 Lookups from %s (%s, %s):\n", namespace, network.InternalLookupName, externalName)
+		for _, pod := range pods {
+			symptom, err := s.podDNSSymptoms(ctx, pod, externalName)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s: %v", pod.Name, err))
+				continue
+			}
+			symptoms = append(symptoms, symptom)
+			switch {
+			case symptom.InternalFailed:
+				response += fmt.Sprintf("❌ %s: cluster names fail (nameserver %s)\n", pod.Name, valueOrNone(symptom.Nameserver))
+			case symptom.ExternalFailed:
+				response += fmt.Sprintf("⚠️  %s: external names fail (nameserver %s)\n", pod.Name, valueOrNone(symptom.Nameserver))
+			default:
+				response += fmt.Sprintf("✅ %s: both resolve\n", pod.Name)
+			}
+		}
+		for _, reason := range skipped {
+			response += fmt.Sprintf("⏭️  %s\n", reason)
+		}
+		if len(pods) == 0 && len(skipped) == 0 {
+			response += "📭 No running pods to run lookups from\n"
+		}
+		response += "\n"
+	}
+
+	if metricsErr != nil && len(symptoms) == 0 {
+		return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+	}
+	result := diagnostics.AnalyzeDNS(telemetry, symptoms, time.Now())
+	response += s.formatAnalysisResult(ctx, result)
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// CheckDNSTelemetryHandler is a public wrapper for checkDNSTelemetryHandler
+func (s *Server) CheckDNSTelemetryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.checkDNSTelemetryHandler(ctx, request)
+}
+
+func (s *Server) diagnoseClusterDNSHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available"), nil
+	}
+	limitStr := mcp.ParseString(request, "max_nodes", strconv.Itoa(defaultDNSProbeNodes))
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > maxDNSProbeNodes {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid max_nodes value: %s, expected 1 to %d", limitStr, maxDNSProbeNodes)), nil
+	}
+	var nodes []string
+	for _, node := range strings.Split(mcp.ParseString(request, "nodes", ""), ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	state := diagnostics.ClusterDNSState{
+		ClusterName:    network.InternalLookupName,
+		ExternalLookup: strings.TrimSpace(mcp.ParseString(request, "external_name", network.ExternalLookupName)),
+	}
+
+	response := "🌐 Cluster DNS\n"
+	response += "=============\n\n"
+
+	operator, err := s.clusterOperatorStatus(ctx, "dns")
+	if err != nil {
+		response += fmt.Sprintf("⚠️  DNS operator status unavailable: %v\n", err)
+	}
+	state.Operator = operator
+
+	upstreams, serviceIP, err := s.dnsOperatorConfig(ctx)
+	if err != nil {
+		response += fmt.Sprintf("⚠️  dns.operator/default unavailable: %v\n", err)
+	}
+	state.Upstreams, state.ServiceIP = upstreams, serviceIP
+	if state.ServiceIP == "" {
+		if service, err := s.k8sClient.CoreV1().Services(dnsNamespace).Get(ctx, coreDNSDaemonSet, metav1.GetOptions{}); err == nil {
+			state.ServiceIP = service.Spec.ClusterIP
+		}
+	}
+
+	state.Pods, state.DesiredPods, err = s.coreDNSPods(ctx)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list the CoreDNS pods in %s", dnsNamespace), err), nil
+	}
+
+	probePods, skipped, err := s.dnsProbeNodes(ctx, nodes, limit)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list the node-resolver pods in %s", dnsNamespace), err), nil
+	}
+	for _, pod := range probePods {
+		probes, err := s.probeNodeDNS(ctx, pod, state)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", pod.Spec.NodeName, err))
+			continue
+		}
+		state.Probes = append(state.Probes, probes...)
+	}
+	state.NodesUnprobed = skipped
+
+	response += fmt.Sprintf("DNS service: %s\n", valueOrNone(state.ServiceIP))
+	if len(state.Upstreams) > 0 {
+		response += fmt.Sprintf("Upstream resolvers: %s\n", strings.Join(state.Upstreams, ", "))
+	} else {
+		response += "Upstream resolvers: each node's /etc/resolv.conf\n"
+	}
+	if state.Operator.Found {
+		response += fmt.Sprintf("Operator: Available=%t, Progressing=%t, Degraded=%t\n", state.Operator.Available, state.Operator.Progressing, state.Operator.Degraded)
+	}
+	response += "\n📦 CoreDNS pods:\n"
+	for _, pod := range state.Pods {
+		icon := "✅"
+		if !pod.Ready {
+			icon = "❌"
+		}
+		line := fmt.Sprintf("%s %s on %s", icon, pod.Name, valueOrNone(pod.Node))
+		if pod.Restarts > 0 {
+			line += fmt.Sprintf(", %d restarts", pod.Restarts)
+		}
+		response += line + "\n"
+	}
+	if len(state.Pods) == 0 {
+		response += "📭 None found\n"
+	}
+
+	response += "\n🔎 Lookups from nodes:\n"
+	for _, probe := range state.Probes {
+		icon := "✅"
+		if probe.Failed() {
+			icon = "❌"
+		}
+		line := fmt.Sprintf("%s %s: %s @%s → %s", icon, probe.Node, probe.Name, probe.Server, probe.Status)
+		if probe.Latency > 0 {
+			line += fmt.Sprintf(" in %s", probe.Latency)
+		}
+		response += line + "\n"
+	}
+	for _, reason := range state.NodesUnprobed {
+		response += fmt.Sprintf("⏭️  %s\n", reason)
+	}
+	response += "\n"
+
+	result := diagnostics.AnalyzeClusterDNS(state, time.Now())
+	response += s.formatAnalysisResult(ctx, result)
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// DiagnoseClusterDNSHandler is a public wrapper for diagnoseClusterDNSHandler
+func (s *Server) DiagnoseClusterDNSHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.diagnoseClusterDNSHandler(ctx, request)
+}
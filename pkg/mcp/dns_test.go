@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckDNSTelemetry(t *testing.T) {
+	querier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		if !strings.Contains(query, "[600s]") {
+			t.Errorf("query without the requested window: %s", query)
+		}
+		value := ""
+		switch {
+		case strings.Contains(query, "sum by (pod)"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"pod":"dns-default-a"},"value":[1700000000,"0.2"]},
+				{"metric":{"pod":"dns-default-b"},"value":[1700000000,"0.2"]}]}}`))
+			return
+		case strings.Contains(query, "healthcheck"):
+			value = "0"
+		case strings.Contains(query, "histogram_quantile"):
+			value = "3.1"
+		case strings.Contains(query, "coredns_forward_responses_total"):
+			value = "0.4"
+		case strings.Contains(query, "coredns_cache_hits_total"):
+			value = "NaN"
+		case strings.Contains(query, "coredns_dns_responses_total"):
+			value = "0.2"
+		default:
+			value = "80"
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,%q]}]}}`, value)
+	}))
+	defer querier.Close()
+
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	var commands []string
+	s := &Server{
+		config:    &Config{Monitoring: &MonitoringConfig{QuerierURL: querier.URL}},
+		k8sClient: kubefake.NewSimpleClientset(pod("web-1")),
+		podExec: func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+			commands = append(commands, strings.Join(command, " "))
+			switch command[len(command)-1] {
+			case "/etc/resolv.conf":
+				io.WriteString(stdout, "nameserver 172.30.0.10\noptions ndots:5\n")
+			case "example.org":
+				io.WriteString(stdout, "** server can't find example.org: SERVFAIL\n")
+			default:
+				io.WriteString(stdout, "Name:\tkubernetes.default.svc.cluster.local\nAddress: 172.30.0.1\n")
+			}
+			return nil
+		},
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "external_name": "example.org", "window": "10m"}
+	result, err := s.CheckDNSTelemetryHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CheckDNSTelemetryHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"• Queries: 80.0/s",
+		"• SERVFAIL: 20.0%",
+		"• Upstream p99 latency: 3.1s",
+		"⚠️  web-1: external names fail (nameserver 172.30.0.10)",
+		"upstream resolvers CoreDNS forwards to",
+		"no data for cache_hits",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("check_dns_telemetry output missing %q:\n%s", want, text)
+		}
+	}
+	if len(commands) != 3 || commands[2] != "nslookup example.org" {
+		t.Errorf("pod commands = %v", commands)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"pods": "web-1"}
+	if result, _ := s.CheckDNSTelemetryHandler(context.Background(), request); !strings.Contains(resultText(result), "namespace is required") {
+		t.Errorf("pods without a namespace = %s", resultText(result))
+	}
+}
+
+func TestDiagnoseClusterDNS(t *testing.T) {
+	dnsConfig := newUnstructured("operator.openshift.io/v1", "DNS", "", "default")
+	unstructured.SetNestedSlice(dnsConfig.Object, []interface{}{
+		map[string]interface{}{"type": "SystemResolvConf"},
+		map[string]interface{}{"type": "Network", "address": "10.0.0.9", "port": int64(53)},
+	}, "spec", "upstreamResolvers", "upstreams")
+	unstructured.SetNestedField(dnsConfig.Object, "172.30.0.10", "status", "clusterIP")
+
+	coreDNSLabels := map[string]string{"dns.operator.openshift.io/daemonset-dns": "default"}
+	resolverLabels := map[string]string{nodeResolverSelector: ""}
+	pod := func(name, node string, labels map[string]string, ready bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: dnsNamespace, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return pod
+	}
+	crashing := pod("dns-default-b", "worker-2", coreDNSLabels, false)
+	crashing.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         coreDNSContainer,
+		RestartCount: 4,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: coreDNSDaemonSet, Namespace: dnsNamespace},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2},
+	}
+
+	var commands []string
+	s := &Server{
+		config: &Config{},
+		k8sClient: kubefake.NewSimpleClientset(pod("dns-default-a", "worker-1", coreDNSLabels, true), crashing, daemonSet,
+			pod("node-resolver-a", "worker-1", resolverLabels, true),
+			pod("node-resolver-b", "worker-2", resolverLabels, true)),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), clusterOperator("dns", "True", "False", "False", ""), dnsConfig),
+		podExec: func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+			commands = append(commands, fmt.Sprintf("%s/%s: %s", pod, container, strings.Join(command, " ")))
+			if pod == "node-resolver-b" && command[len(command)-2] == "@10.0.0.9" {
+				io.WriteString(stdout, ";; connection timed out; no servers could be reached\n")
+				return nil
+			}
+			io.WriteString(stdout, ";; ->>HEADER<<- opcode: QUERY, status: NOERROR, id: 1\n;; Query time: 4 msec\n")
+			return nil
+		},
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"external_name": "example.org"}
+	result, err := s.DiagnoseClusterDNSHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("DiagnoseClusterDNSHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"DNS service: 172.30.0.10",
+		"Upstream resolvers: 10.0.0.9:53",
+		"❌ dns-default-b on worker-2, 4 restarts",
+		"✅ worker-1: kubernetes.default.svc.cluster.local @172.30.0.10 → NOERROR in 4ms",
+		"❌ worker-2: example.org @10.0.0.9:53 → timeout",
+		"1 of 2 CoreDNS pods are not ready",
+		"Upstream resolver 10.0.0.9:53 does not answer some nodes",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("diagnose_cluster_dns output missing %q:\n%s", want, text)
+		}
+	}
+	if len(commands) != 6 || commands[0] != "node-resolver-a/dns-node-resolver: dig +time=2 +tries=1 -p 53 @172.30.0.10 kubernetes.default.svc.cluster.local" {
+		t.Errorf("probe commands = %v", commands)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"nodes": "worker-2, worker-9"}
+	commands = nil
+	result, _ = s.DiagnoseClusterDNSHandler(context.Background(), request)
+	text = resultText(result)
+	if !strings.Contains(text, "worker-9: no running node-resolver pod") || len(commands) != 3 {
+		t.Errorf("probing named nodes ran %v:\n%s", commands, text)
+	}
+	request.Params.Arguments = map[string]interface{}{"max_nodes": "20"}
+	if result, _ := s.DiagnoseClusterDNSHandler(context.Background(), request); !strings.Contains(resultText(result), "Invalid max_nodes") {
+		t.Errorf("max_nodes above the limit = %s", resultText(result))
+	}
+}
@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// maxDefaultedFields bounds the server-populated fields listed per object
+const maxDefaultedFields = 20
+
+// serverManagedFields are set on every object and are not worth reporting as defaults
+var serverManagedFields = []string{
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.managedFields",
+	"status",
+}
+
+// decodeObjects parses a multi-document YAML or JSON stream
+func decodeObjects(content string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+
+	var objects []*unstructured.Unstructured
+	for {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid YAML/JSON in document %d: %v", len(objects)+1, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: raw}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, fmt.Errorf("document %d must set apiVersion and kind", len(objects)+1)
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no resources found in YAML")
+	}
+	return objects, nil
+}
+
+// flattenFields collects the leaf fields of an object as dotted paths
+func flattenFields(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenFields(path, child, out)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenFields(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	default:
+		out[prefix] = fmt.Sprintf("%v", v)
+	}
+}
+
+// defaultedFields lists fields the server populated that the manifest did not set
+func defaultedFields(submitted, result *unstructured.Unstructured) []string {
+	before := make(map[string]string)
+	after := make(map[string]string)
+	flattenFields("", submitted.Object, before)
+	flattenFields("", result.Object, after)
+
+	var defaults []string
+	for path, value := range after {
+		if _, ok := before[path]; ok || isServerManaged(path) {
+			continue
+		}
+		defaults = append(defaults, fmt.Sprintf("%s: %s", path, value))
+	}
+	sort.Strings(defaults)
+	return defaults
+}
+
+func isServerManaged(path string) bool {
+	for _, field := range serverManagedFields {
+		if path == field || strings.HasPrefix(path, field+".") || strings.HasPrefix(path, field+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// validationMessage renders an API error with the field causes it carries
+func validationMessage(err error) string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return err.Error()
+	}
+
+	message := status.Status().Message
+	if details := status.Status().Details; details != nil {
+		for _, cause := range details.Causes {
+			message += fmt.Sprintf("\n      • %s: %s", cause.Field, cause.Message)
+		}
+	}
+	return message
+}
+
+// dryRunYAML performs a server-side dry run of every resource in the YAML and
+// reports validation errors, server defaults and the impact on live objects.
+// Like apply, missing resources are dry-run created and existing ones dry-run applied.
+func (s *Server) dryRunYAML(ctx context.Context, yamlContent, namespace string) (string, error) {
+	if s.dynamicClient == nil || s.restMapper == nil {
+		return "", fmt.Errorf("dry run requires the Kubernetes dynamic client")
+	}
+
+	objects, err := decodeObjects(yamlContent)
+	if err != nil {
+		return "", err
+	}
+
+	result := "🧪 Server Dry Run - no changes were made to the cluster\n"
+	result += "=====================================================\n\n"
+
+	var creates, updates, unchanged, invalid int
+	for i, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		header := fmt.Sprintf("%d. %s %s", i+1, gvk.Kind, obj.GetName())
+
+		mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			invalid++
+			recordToolError(ctx, err)
+			result += fmt.Sprintf("%s\n   ❌ Unknown resource kind %s: %v\n\n", header, gvk.String(), err)
+			continue
+		}
+
+		namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		if namespaced {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(namespace)
+			}
+			header += fmt.Sprintf(" (namespace %s)", obj.GetNamespace())
+		} else {
+			obj.SetNamespace("")
+		}
+		client := s.resourceInterface(mapping.Resource, namespaced, obj.GetNamespace())
+
+		var live *unstructured.Unstructured
+		if obj.GetName() != "" {
+			live, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					return "", err
+				}
+				live = nil
+			}
+		}
+
+		var dryRun *unstructured.Unstructured
+		if live == nil {
+			dryRun, err = client.Create(ctx, obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: fieldManager})
+		} else {
+			dryRun, err = client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: fieldManager, Force: true})
+		}
+		if err != nil {
+			invalid++
+			recordToolError(ctx, err)
+			result += fmt.Sprintf("%s\n   ❌ Rejected: %s\n\n", header, validationMessage(err))
+			continue
+		}
+
+		result += header + "\n"
+		if live == nil {
+			creates++
+			result += "   🆕 Would be created\n"
+		} else {
+			diff := diffLines(objectYAML(live), objectYAML(dryRun))
+			if len(diff) == 0 {
+				unchanged++
+				result += "   ✅ Unchanged - the live object already matches\n"
+			} else {
+				updates++
+				result += "   🔄 Would be updated:\n"
+				result += fmt.Sprintf("```diff\n%s\n```\n", strings.Join(diff, "\n"))
+			}
+		}
+
+		// The server fills in data from a Secret's stringData, so compare
+		// redacted copies to keep its values out of the defaults
+		requested, defaulted := obj.DeepCopy(), dryRun.DeepCopy()
+		redactSecretObject(requested)
+		redactSecretObject(defaulted)
+		if defaults := defaultedFields(requested, defaulted); len(defaults) > 0 {
+			result += "   ⚙️  Server defaults:\n"
+			for j, field := range defaults {
+				if j == maxDefaultedFields {
+					result += fmt.Sprintf("      ... and %d more\n", len(defaults)-maxDefaultedFields)
+					break
+				}
+				result += fmt.Sprintf("      %s\n", field)
+			}
+		}
+		result += "\n"
+	}
+
+	result += fmt.Sprintf("📊 Impact: %d to create, %d to update, %d unchanged, %d rejected\n", creates, updates, unchanged, invalid)
+	if invalid > 0 {
+		result += "⚠️  Fix the rejected resources before applying"
+	} else {
+		result += "💡 Re-run without dry_run to apply these changes"
+	}
+	return result, nil
+}
+
+// dryRunResult wraps dryRunYAML as a tool result
+func (s *Server) dryRunResult(ctx context.Context, yamlContent, namespace string) *mcp.CallToolResult {
+	report, err := s.dryRunYAML(ctx, yamlContent, namespace)
+	if err != nil {
+		return toolError(ctx, "Dry run failed", err)
+	}
+	return mcp.NewToolResultText(report)
+}
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDecodeObjects(t *testing.T) {
+	tests := []struct {
+		input string
+		count int
+		valid bool
+	}{
+		{"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n", 2, true},
+		{"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n", 1, true},
+		{`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}}`, 1, true},
+		{"apiVersion: v1\nkind: ConfigMap\n---\nmetadata:\n  name: b\n", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		objects, err := decodeObjects(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("decodeObjects(%q) error = %v, expected valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		if len(objects) != tt.count {
+			t.Errorf("decodeObjects(%q) = %d objects, expected %d", tt.input, len(objects), tt.count)
+		}
+	}
+}
+
+// withDryRunDefaults answers dry-run creates and applies the way an API server
+// would, adding defaults and rejecting invalid objects, without storing anything
+func withDryRunDefaults(s *Server) {
+	client := s.dynamicClient.(*dynamicfake.FakeDynamicClient)
+	respond := func(obj *unstructured.Unstructured) (bool, runtime.Object, error) {
+		if obj.GetName() == "invalid" {
+			return true, nil, apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, obj.GetName(),
+				field.ErrorList{field.Required(field.NewPath("spec", "selector"), "")})
+		}
+		defaulted := obj.DeepCopy()
+		defaulted.SetUID("uid-dry-run")
+		unstructured.SetNestedField(defaulted.Object, int64(600), "spec", "progressDeadlineSeconds")
+		return true, defaulted, nil
+	}
+
+	client.PrependReactor("create", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return respond(action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured))
+	})
+	client.PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch()); err != nil {
+			return true, nil, err
+		}
+		return respond(obj)
+	})
+}
+
+func TestApplyYamlDryRun(t *testing.T) {
+	live := newUnstructured("apps/v1", "Deployment", "shop", "web")
+	unstructured.SetNestedField(live.Object, int64(2), "spec", "replicas")
+	s := newDynamicTestServer(live)
+	withDryRunDefaults(s)
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: invalid
+`
+
+	for _, handler := range []func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error){s.applyYamlHandler, s.createResourceHandler} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"yaml": manifest, "namespace": "shop", "dry_run": "true"}
+
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		text := resultText(result)
+
+		for _, expected := range []string{
+			"no changes were made",
+			"🔄 Would be updated",
+			"+   replicas: 3",
+			"🆕 Would be created",
+			"spec.progressDeadlineSeconds: 600",
+			"❌ Rejected",
+			"spec.selector: Required value",
+			"📊 Impact: 1 to create, 1 to update, 0 unchanged, 1 rejected",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("dry run result missing %q:\n%s", expected, text)
+			}
+		}
+	}
+
+	// Nothing was written to the cluster
+	if _, err := s.dynamicClient.Resource(deploymentsGVR).Namespace("shop").Get(context.Background(), "api", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("dry run created deployment api: %v", err)
+	}
+	current, _ := s.dynamicClient.Resource(deploymentsGVR).Namespace("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if replicas, _, _ := unstructured.NestedInt64(current.Object, "spec", "replicas"); replicas != 2 {
+		t.Errorf("dry run changed web replicas to %d, expected 2", replicas)
+	}
+}
+
+func TestApplyYamlDryRunRedactsSecrets(t *testing.T) {
+	s := newDynamicTestServer(newSecretUnstructured("shop", "db", "hunter2-old"))
+	client := s.dynamicClient.(*dynamicfake.FakeDynamicClient)
+	// Like the API server, move stringData into data and persist nothing
+	respond := func(obj *unstructured.Unstructured) (bool, runtime.Object, error) {
+		stored := obj.DeepCopy()
+		data, _, _ := unstructured.NestedStringMap(stored.Object, "data")
+		if data == nil {
+			data = map[string]string{}
+		}
+		stringData, _, _ := unstructured.NestedStringMap(stored.Object, "stringData")
+		for key, value := range stringData {
+			data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		unstructured.SetNestedStringMap(stored.Object, data, "data")
+		unstructured.RemoveNestedField(stored.Object, "stringData")
+		stored.SetUID("uid-dry-run")
+		return true, stored, nil
+	}
+	client.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return respond(action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured))
+	})
+	client.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch()); err != nil {
+			return true, nil, err
+		}
+		return respond(obj)
+	})
+
+	manifest := `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+stringData:
+  password: hunter2-new
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: api
+stringData:
+  token: s3cr3t-token
+`
+	for _, handler := range []func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error){s.applyYamlHandler, s.createResourceHandler} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"yaml": manifest, "namespace": "shop", "dry_run": "true"}
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		text := resultText(result)
+		if !strings.Contains(text, "📊 Impact: 1 to create, 1 to update") {
+			t.Fatalf("dry run result = %q", text)
+		}
+		assertSecretRedacted(t, text, "hunter2-old", "hunter2-new", "s3cr3t-token")
+	}
+}
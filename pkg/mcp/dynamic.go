@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// resourceAliases maps common short names to their plural resource names,
+// since short names are not part of the RESTMapper lookup.
+var resourceAliases = map[string]string{
+	"po":     "pods",
+	"svc":    "services",
+	"deploy": "deployments",
+	"ds":     "daemonsets",
+	"sts":    "statefulsets",
+	"rs":     "replicasets",
+	"cm":     "configmaps",
+	"ns":     "namespaces",
+	"pvc":    "persistentvolumeclaims",
+	"pv":     "persistentvolumes",
+	"sa":     "serviceaccounts",
+	"ing":    "ingresses",
+	"hpa":    "horizontalpodautoscalers",
+	"netpol": "networkpolicies",
+	"dc":     "deploymentconfigs",
+	"is":     "imagestreams",
+	"bc":     "buildconfigs",
+	"cj":     "cronjobs",
+}
+
+// initDynamicClient creates the dynamic client and a discovery-backed RESTMapper
+func (s *Server) initDynamicClient(config *rest.Config) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to create dynamic client")
+		return
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to create discovery client")
+		return
+	}
+
+	s.dynamicClient = dynamicClient
+	s.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+}
+
+// resolveResource maps a user supplied resource type such as "deployment",
+// "svc", "Route" or "routes.route.openshift.io" to a resource and reports
+// whether it is namespaced.
+func (s *Server) resolveResource(resourceType string) (schema.GroupVersionResource, bool, error) {
+	if s.restMapper == nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("resource discovery is not available")
+	}
+
+	name := strings.ToLower(strings.TrimSpace(resourceType))
+	if name == "" {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("resource type is required")
+	}
+	if alias, ok := resourceAliases[name]; ok {
+		name = alias
+	}
+
+	partial := schema.GroupVersionResource{Resource: name}
+	if idx := strings.Index(name, "."); idx > 0 {
+		partial = schema.GroupVersionResource{Resource: name[:idx], Group: name[idx+1:]}
+	}
+
+	gvr, err := s.restMapper.ResourceFor(partial)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("unknown resource type '%s': %v", resourceType, err)
+	}
+
+	gvk, err := s.restMapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// resourceInterface returns the dynamic client for a resource, scoped to the
+// namespace when the resource is namespaced
+func (s *Server) resourceInterface(gvr schema.GroupVersionResource, namespaced bool, namespace string) dynamic.ResourceInterface {
+	if namespaced {
+		return s.dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+	return s.dynamicClient.Resource(gvr)
+}
+
+// parsePropagationPolicy validates a deletion propagation policy
+func parsePropagationPolicy(value string) (*metav1.DeletionPropagation, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, policy := range []metav1.DeletionPropagation{metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan} {
+		if strings.EqualFold(value, string(policy)) {
+			return &policy, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid propagation policy '%s' (expected Foreground, Background or Orphan)", value)
+}
+
+func (s *Server) deleteResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	resourceName := mcp.ParseString(request, "resource_name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	confirm := parseBoolString(mcp.ParseString(request, "confirm", "false"))
+
+	if resourceName == "" {
+		return mcp.NewToolResultText("❌ Resource name is required"), nil
+	}
+
+	propagation, err := parsePropagationPolicy(mcp.ParseString(request, "propagation_policy", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	var gracePeriod *int64
+	if value := mcp.ParseString(request, "grace_period_seconds", ""); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid grace period: %s", value)), nil
+		}
+		gracePeriod = &seconds
+	}
+
+	gvr, namespaced, err := s.resolveResource(resourceType)
+	if err != nil {
+		recordToolError(ctx, err)
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if !namespaced {
+		namespace = ""
+	}
+	client := s.resourceInterface(gvr, namespaced, namespace)
+
+	result := "🗑️  Deleting Resource\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("Resource: %s\n", gvr.GroupResource().String())
+	result += fmt.Sprintf("Name: %s\n", resourceName)
+	if namespaced {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	} else {
+		result += "Scope: cluster\n"
+	}
+	if propagation != nil {
+		result += fmt.Sprintf("Propagation Policy: %s\n", *propagation)
+	}
+	if gracePeriod != nil {
+		result += fmt.Sprintf("Grace Period: %ds\n", *gracePeriod)
+	}
+	result += "\n"
+
+	// Make sure the object exists before deleting or previewing
+	live, err := client.Get(ctx, resourceName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get %s %s", gvr.Resource, resourceName), err), nil
+	}
+
+	if !confirm {
+		result += "⚠️  DESTRUCTIVE OPERATION - nothing has been deleted yet\n"
+		result += fmt.Sprintf("📦 Found %s %s (uid %s, created %s)\n", live.GetKind(), live.GetName(), live.GetUID(),
+			formatTime(ctx, live.GetCreationTimestamp().Time))
+		if owners := live.GetOwnerReferences(); len(owners) > 0 {
+			result += fmt.Sprintf("🔗 Owned by %s %s - it may be recreated by its owner\n", owners[0].Kind, owners[0].Name)
+		}
+		result += "\n💡 Re-run with confirm=true to delete the resource"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	// Guard against deleting a replacement created since the lookup
+	uid := live.GetUID()
+	options := metav1.DeleteOptions{
+		PropagationPolicy:  propagation,
+		GracePeriodSeconds: gracePeriod,
+		Preconditions:      &metav1.Preconditions{UID: &uid},
+	}
+	if err := client.Delete(ctx, resourceName, options); err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to delete %s %s", gvr.Resource, resourceName), err), nil
+	}
+
+	logrus.Infof("Deleted %s %s (namespace %q)", gvr.GroupResource().String(), resourceName, namespace)
+	s.recordAction(ctx, "delete_resource", objectReference(live.GetAPIVersion(), live.GetKind(), live), ReasonDeleted,
+		fmt.Sprintf("Deleted %s", live.GetKind()))
+	result += fmt.Sprintf("✅ %s %s deleted\n", live.GetKind(), resourceName)
+	if len(live.GetFinalizers()) > 0 {
+		result += fmt.Sprintf("⏳ Finalizers pending: %s\n", strings.Join(live.GetFinalizers(), ", "))
+	}
+
+	return mcp.NewToolResultText(result), nil
+}
@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	namespacesGVR  = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	secretsGVR     = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+func newDynamicTestServer(objects ...runtime.Object) *Server {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList",
+		namespacesGVR:  "NamespaceList",
+		secretsGVR:     "SecretList",
+	}, objects...)
+
+	return &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(), dynamicClient: client, restMapper: mapper}
+}
+
+func newUnstructured(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	return obj
+}
+
+func deleteRequest(args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "delete_resource"
+	request.Params.Arguments = args
+	return request
+}
+
+func TestResolveResource(t *testing.T) {
+	s := newDynamicTestServer()
+
+	tests := []struct {
+		input      string
+		expected   schema.GroupVersionResource
+		namespaced bool
+	}{
+		{"deployment", deploymentsGVR, true},
+		{"Deployments", deploymentsGVR, true},
+		{"deploy", deploymentsGVR, true},
+		{"deployments.apps", deploymentsGVR, true},
+		{"namespace", namespacesGVR, false},
+		{"ns", namespacesGVR, false},
+	}
+
+	for _, tt := range tests {
+		gvr, namespaced, err := s.resolveResource(tt.input)
+		if err != nil {
+			t.Errorf("resolveResource(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if gvr != tt.expected || namespaced != tt.namespaced {
+			t.Errorf("resolveResource(%q) = %v, %v, expected %v, %v", tt.input, gvr, namespaced, tt.expected, tt.namespaced)
+		}
+	}
+
+	if _, _, err := s.resolveResource("widgets"); err == nil {
+		t.Errorf("resolveResource(%q) expected error for unknown type", "widgets")
+	}
+}
+
+func TestParsePropagationPolicy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"", "", true},
+		{"Foreground", "Foreground", true},
+		{"background", "Background", true},
+		{"ORPHAN", "Orphan", true},
+		{"cascade", "", false},
+	}
+
+	for _, tt := range tests {
+		policy, err := parsePropagationPolicy(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("parsePropagationPolicy(%q) error = %v, expected valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		got := ""
+		if policy != nil {
+			got = string(*policy)
+		}
+		if got != tt.expected {
+			t.Errorf("parsePropagationPolicy(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestDeleteResourceRequiresConfirm(t *testing.T) {
+	s := newDynamicTestServer(newUnstructured("apps/v1", "Deployment", "shop", "web"))
+
+	result, err := s.deleteResourceHandler(context.Background(), deleteRequest(map[string]interface{}{
+		"resource_type": "deployment",
+		"resource_name": "web",
+		"namespace":     "shop",
+	}))
+	if err != nil {
+		t.Fatalf("deleteResourceHandler returned error: %v", err)
+	}
+	if text := resultText(result); !strings.Contains(text, "confirm=true") {
+		t.Errorf("preview = %q, expected confirm hint", text)
+	}
+
+	if _, err := s.dynamicClient.Resource(deploymentsGVR).Namespace("shop").Get(context.Background(), "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("deployment deleted without confirm: %v", err)
+	}
+}
+
+func TestDeleteResourceWithConfirm(t *testing.T) {
+	s := newDynamicTestServer(
+		newUnstructured("apps/v1", "Deployment", "shop", "web"),
+		newUnstructured("v1", "Namespace", "", "scratch"),
+	)
+
+	tests := []struct {
+		args map[string]interface{}
+		gvr  schema.GroupVersionResource
+		ns   string
+		name string
+	}{
+		{map[string]interface{}{"resource_type": "deployment", "resource_name": "web", "namespace": "shop",
+			"confirm": "true", "propagation_policy": "Foreground", "grace_period_seconds": "0"}, deploymentsGVR, "shop", "web"},
+		{map[string]interface{}{"resource_type": "namespace", "resource_name": "scratch", "namespace": "default",
+			"confirm": true}, namespacesGVR, "", "scratch"},
+	}
+
+	for _, tt := range tests {
+		result, err := s.deleteResourceHandler(context.Background(), deleteRequest(tt.args))
+		if err != nil {
+			t.Fatalf("deleteResourceHandler(%v) returned error: %v", tt.args, err)
+		}
+		if result.IsError {
+			t.Errorf("deleteResourceHandler(%v) = %q, expected success", tt.args, resultText(result))
+			continue
+		}
+
+		var getErr error
+		if tt.ns != "" {
+			_, getErr = s.dynamicClient.Resource(tt.gvr).Namespace(tt.ns).Get(context.Background(), tt.name, metav1.GetOptions{})
+		} else {
+			_, getErr = s.dynamicClient.Resource(tt.gvr).Get(context.Background(), tt.name, metav1.GetOptions{})
+		}
+		if getErr == nil {
+			t.Errorf("%s %s still exists after confirmed delete", tt.gvr.Resource, tt.name)
+		}
+	}
+}
+
+func TestDeleteResourceInvalidInput(t *testing.T) {
+	s := newDynamicTestServer(newUnstructured("apps/v1", "Deployment", "shop", "web"))
+
+	tests := []map[string]interface{}{
+		{"resource_type": "deployment", "resource_name": "web", "propagation_policy": "cascade", "confirm": "true"},
+		{"resource_type": "deployment", "resource_name": "web", "grace_period_seconds": "-5", "confirm": "true"},
+		{"resource_type": "widgets", "resource_name": "web", "confirm": "true"},
+		{"resource_type": "deployment", "resource_name": "missing", "namespace": "shop", "confirm": "true"},
+	}
+
+	for _, args := range tests {
+		result, err := s.deleteResourceHandler(context.Background(), deleteRequest(args))
+		if err != nil {
+			t.Fatalf("deleteResourceHandler(%v) returned error: %v", args, err)
+		}
+		if text := resultText(result); !strings.Contains(text, "❌") {
+			t.Errorf("deleteResourceHandler(%v) = %q, expected error", args, text)
+		}
+	}
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	if result == nil || len(result.Content) == 0 {
+		return ""
+	}
+	if text, ok := mcp.AsTextContent(result.Content[0]); ok {
+		return text.Text
+	}
+	return ""
+}
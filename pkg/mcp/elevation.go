@@ -0,0 +1,488 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+// maxElevationRequests bounds the forbidden calls kept for grant_elevation
+const maxElevationRequests = 100
+
+// sharedElevationTTL is how long a forbidden call stays approvable in a
+// shared store, which expires requests instead of bounding their number
+const sharedElevationTTL = 24 * time.Hour
+
+// Annotations on the roles and bindings created by grant_elevation
+const (
+	elevationReasonAnnotation   = "openshift-mcp/elevation-reason"
+	elevationApproverAnnotation = "openshift-mcp/approved-by"
+)
+
+// MissingPermission is the access the API server refused, as stated in its
+// Forbidden message
+type MissingPermission struct {
+	User      string `json:"user,omitempty"`
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource"` // with any subresource, e.g. pods/exec
+	Group     string `json:"group"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// forbiddenPattern matches the API server's RBAC refusal, e.g.
+// pods "web-1" is forbidden: User "system:serviceaccount:mcp:server" cannot
+// delete resource "pods" in API group "" in the namespace "shop"
+var forbiddenPattern = regexp.MustCompile(`(?:\S+ "([^"]+)" is )?forbidden: User "([^"]+)" cannot (\S+) resource "([^"]+)"(?: in API group "([^"]*)")?(?: in the namespace "([^"]+)")?`)
+
+// parseForbidden reads the missing permission from a Forbidden message
+func parseForbidden(message string) (*MissingPermission, bool) {
+	match := forbiddenPattern.FindStringSubmatch(message)
+	if match == nil {
+		return nil, false
+	}
+	return &MissingPermission{
+		Name:      match[1],
+		User:      match[2],
+		Verb:      match[3],
+		Resource:  match[4],
+		Group:     match[5],
+		Namespace: match[6],
+	}, true
+}
+
+// String renders the permission like can_i questions
+func (p MissingPermission) String() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource, _, _ = strings.Cut(p.Resource, "/")
+		resource += "." + p.Group
+		if _, subresource, ok := strings.Cut(p.Resource, "/"); ok {
+			resource += "/" + subresource
+		}
+	}
+	text := p.Verb + " " + resource
+	if p.Name != "" {
+		text += " " + p.Name
+	}
+	if p.Namespace != "" {
+		return text + " in namespace " + p.Namespace
+	}
+	return text + " cluster-wide"
+}
+
+// elevationRequest is a forbidden tool call that grant_elevation can retry
+// once the missing permission is granted
+type elevationRequest struct {
+	ID         string
+	Tool       string
+	Arguments  map[string]interface{}
+	Permission MissingPermission
+	Created    time.Time
+	Granted    bool
+}
+
+// elevationRequests keeps the most recent forbidden calls. With a shared
+// store they live there, so a call refused on one replica can be approved
+// through another.
+type elevationRequests struct {
+	mu       sync.Mutex
+	seq      int
+	requests map[string]*elevationRequest
+	order    []string
+	store    store.Store
+}
+
+func elevationKey(id string) string {
+	return "elevation/" + id
+}
+
+func (e *elevationRequests) add(tool string, arguments map[string]interface{}, permission MissingPermission) string {
+	if store.Shared(e.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		request := elevationRequest{ID: store.NewID("el-"), Tool: tool, Arguments: arguments, Permission: permission, Created: time.Now()}
+		if err := store.SetJSON(ctx, e.store, elevationKey(request.ID), request, sharedElevationTTL); err != nil {
+			logrus.WithError(err).Warnf("Failed to store elevation %s", request.ID)
+		}
+		return request.ID
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.requests == nil {
+		e.requests = make(map[string]*elevationRequest)
+	}
+	e.seq++
+	id := fmt.Sprintf("el-%d", e.seq)
+	e.requests[id] = &elevationRequest{ID: id, Tool: tool, Arguments: arguments, Permission: permission, Created: time.Now()}
+	e.order = append(e.order, id)
+	if len(e.order) > maxElevationRequests {
+		delete(e.requests, e.order[0])
+		e.order = e.order[1:]
+	}
+	return id
+}
+
+func (e *elevationRequests) get(id string) (elevationRequest, bool) {
+	if store.Shared(e.store) {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		defer cancel()
+		var request elevationRequest
+		ok, err := store.GetJSON(ctx, e.store, elevationKey(id), &request)
+		if err != nil {
+			logrus.WithError(err).Warnf("Failed to read elevation %s", id)
+		}
+		return request, ok
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	request, ok := e.requests[id]
+	if !ok {
+		return elevationRequest{}, false
+	}
+	return *request, true
+}
+
+func (e *elevationRequests) markGranted(id string) {
+	if store.Shared(e.store) {
+		if request, ok := e.get(id); ok {
+			request.Granted = true
+			ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+			defer cancel()
+			store.SetJSON(ctx, e.store, elevationKey(id), request, sharedElevationTTL)
+		}
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if request, ok := e.requests[id]; ok {
+		request.Granted = true
+	}
+}
+
+// offerElevation remembers a call refused by RBAC and tells the caller how
+// to get the permission granted
+func (s *Server) offerElevation(name string, request mcp.CallToolRequest, result *mcp.CallToolResult) {
+	info, ok := ResultError(result)
+	if !ok || info.Kind != ErrorForbidden || info.MissingPermission == nil || name == "grant_elevation" {
+		return
+	}
+	info.ElevationID = s.elevations.add(name, request.GetArguments(), *info.MissingPermission)
+	result.Meta[errorMetaKey] = *info
+
+	hint := fmt.Sprintf("\n\n🔑 Missing permission: %s", info.MissingPermission)
+	if info.MissingPermission.User != "" {
+		hint += fmt.Sprintf(" for %s", info.MissingPermission.User)
+	}
+	hint += fmt.Sprintf("\n💡 request_elevation elevation_id=%s shows the Role and RoleBinding that would grant it; grant_elevation retries %s once they are applied",
+		info.ElevationID, name)
+	if len(result.Content) > 0 {
+		if content, ok := result.Content[0].(mcp.TextContent); ok {
+			content.Text += hint
+			result.Content[0] = content
+		}
+	}
+}
+
+// elevationSubject is the RBAC subject for a user name from a Forbidden message
+func elevationSubject(user string) rbacv1.Subject {
+	if rest, ok := strings.CutPrefix(user, "system:serviceaccount:"); ok {
+		if namespace, name, ok := strings.Cut(rest, ":"); ok {
+			return rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}
+		}
+	}
+	return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: user}
+}
+
+// elevationRoleName names the role granting a permission
+func elevationRoleName(permission MissingPermission) string {
+	verb := permission.Verb
+	if verb == rbacv1.VerbAll {
+		verb = "all"
+	}
+	resource := strings.NewReplacer("/", "-", ".", "-", "*", "all").Replace(permission.Resource)
+	return strings.ToLower(fmt.Sprintf("mcp-elevation-%s-%s", verb, resource))
+}
+
+// elevationRBAC builds the role and binding granting a permission to its
+// user: a Role and RoleBinding for namespaced access, otherwise a
+// ClusterRole and ClusterRoleBinding
+func elevationRBAC(permission MissingPermission, reason string) (role rbacv1.ClusterRole, binding rbacv1.ClusterRoleBinding, namespaced bool) {
+	rule := rbacv1.PolicyRule{
+		APIGroups: []string{permission.Group},
+		Resources: []string{permission.Resource},
+		Verbs:     []string{permission.Verb},
+	}
+	// Resource names cannot limit list, watch or create
+	switch permission.Verb {
+	case "get", "update", "patch", "delete":
+		if permission.Name != "" {
+			rule.ResourceNames = []string{permission.Name}
+		}
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:        elevationRoleName(permission),
+		Namespace:   permission.Namespace,
+		Labels:      map[string]string{"app.kubernetes.io/managed-by": "openshift-mcp"},
+		Annotations: map[string]string{elevationReasonAnnotation: reason},
+	}
+	role = rbacv1.ClusterRole{ObjectMeta: meta, Rules: []rbacv1.PolicyRule{rule}}
+	binding = rbacv1.ClusterRoleBinding{
+		ObjectMeta: meta,
+		Subjects:   []rbacv1.Subject{elevationSubject(permission.User)},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: meta.Name},
+	}
+	if permission.Namespace != "" {
+		binding.RoleRef.Kind = "Role"
+		return role, binding, true
+	}
+	return role, binding, false
+}
+
+// elevationYAML renders the role and binding as one manifest
+func elevationYAML(permission MissingPermission, reason string) (string, error) {
+	role, binding, namespaced := elevationRBAC(permission, reason)
+	var objects []interface{}
+	if namespaced {
+		objects = []interface{}{
+			rbacv1.Role{TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"}, ObjectMeta: role.ObjectMeta, Rules: role.Rules},
+			rbacv1.RoleBinding{TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"}, ObjectMeta: binding.ObjectMeta, Subjects: binding.Subjects, RoleRef: binding.RoleRef},
+		}
+	} else {
+		role.TypeMeta = metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"}
+		binding.TypeMeta = metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"}
+		objects = []interface{}{role, binding}
+	}
+
+	var documents []string
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return "", err
+		}
+		// Drop the empty creationTimestamp the API types always marshal
+		documents = append(documents, strings.Replace(string(data), "  creationTimestamp: null\n", "", 1))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
+
+// applyElevation creates or updates the role and binding granting a permission
+func (s *Server) applyElevation(ctx context.Context, permission MissingPermission, reason, approver string) ([]string, error) {
+	role, binding, namespaced := elevationRBAC(permission, reason)
+	role.Annotations[elevationApproverAnnotation] = approver
+	rbac := s.k8sClient.RbacV1()
+	var applied []string
+
+	if namespaced {
+		desiredRole := &rbacv1.Role{ObjectMeta: role.ObjectMeta, Rules: role.Rules}
+		if _, err := rbac.Roles(permission.Namespace).Create(ctx, desiredRole, metav1.CreateOptions{}); apierrors.IsAlreadyExists(err) {
+			existing, getErr := rbac.Roles(permission.Namespace).Get(ctx, role.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return applied, getErr
+			}
+			existing.Rules, existing.Annotations = role.Rules, role.Annotations
+			_, err = rbac.Roles(permission.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+			if err != nil {
+				return applied, fmt.Errorf("updating Role %s: %w", role.Name, err)
+			}
+		} else if err != nil {
+			return applied, fmt.Errorf("creating Role %s: %w", role.Name, err)
+		}
+		applied = append(applied, fmt.Sprintf("Role %s/%s", permission.Namespace, role.Name))
+
+		desiredBinding := &rbacv1.RoleBinding{ObjectMeta: binding.ObjectMeta, Subjects: binding.Subjects, RoleRef: binding.RoleRef}
+		if _, err := rbac.RoleBindings(permission.Namespace).Create(ctx, desiredBinding, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return applied, fmt.Errorf("creating RoleBinding %s: %w", binding.Name, err)
+		}
+		applied = append(applied, fmt.Sprintf("RoleBinding %s/%s", permission.Namespace, binding.Name))
+		s.recordAction(ctx, "grant_elevation", objectReference("rbac.authorization.k8s.io/v1", "RoleBinding", desiredBinding), ReasonCreated,
+			fmt.Sprintf("Granted %s", permission))
+		return applied, nil
+	}
+
+	if _, err := rbac.ClusterRoles().Create(ctx, &role, metav1.CreateOptions{}); apierrors.IsAlreadyExists(err) {
+		existing, getErr := rbac.ClusterRoles().Get(ctx, role.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return applied, getErr
+		}
+		existing.Rules, existing.Annotations = role.Rules, role.Annotations
+		if _, err := rbac.ClusterRoles().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return applied, fmt.Errorf("updating ClusterRole %s: %w", role.Name, err)
+		}
+	} else if err != nil {
+		return applied, fmt.Errorf("creating ClusterRole %s: %w", role.Name, err)
+	}
+	applied = append(applied, "ClusterRole "+role.Name)
+
+	if _, err := rbac.ClusterRoleBindings().Create(ctx, &binding, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return applied, fmt.Errorf("creating ClusterRoleBinding %s: %w", binding.Name, err)
+	}
+	applied = append(applied, "ClusterRoleBinding "+binding.Name)
+	s.recordAction(ctx, "grant_elevation", objectReference("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", &binding), ReasonCreated,
+		fmt.Sprintf("Granted %s", permission))
+	return applied, nil
+}
+
+func (s *Server) initElevation() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("request_elevation",
+			mcp.WithDescription("Explain a tool call that failed with Forbidden: the exact verb and resource the server's identity lacks, and the Role/RoleBinding YAML that would grant it"),
+			mcp.WithString("elevation_id", mcp.Description("Elevation ID from the Forbidden tool result")),
+			mcp.WithString("verb", mcp.Description("Verb to grant when no elevation_id is given, e.g. list or delete")),
+			mcp.WithString("resource", mcp.Description("Resource to grant when no elevation_id is given, e.g. pods, pods/exec or deployments.apps")),
+			mcp.WithString("namespace", mcp.Description("Namespace to grant in (default: cluster-wide)")),
+			mcp.WithString("resource_name", mcp.Description("Limit the grant to one named resource")),
+			mcp.WithString("user", mcp.Description("User or system:serviceaccount:<namespace>:<name> to grant to; required without elevation_id")),
+			mcp.WithTitleAnnotation("RBAC: Request Elevation"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.requestElevationHandler)},
+		{Tool: mcp.NewTool("grant_elevation",
+			mcp.WithDescription("Apply the Role/RoleBinding that grants a forbidden tool call its missing permission, then retry the call; requires an approver"),
+			mcp.WithString("elevation_id", mcp.Description("Elevation ID from the Forbidden tool result"), mcp.Required()),
+			mcp.WithString("approved_by", mcp.Description("Who approved the grant; recorded on the Role"), mcp.Required()),
+			mcp.WithString("retry", mcp.Description("Retry the forbidden call after granting (true/false, default true)")),
+			mcp.WithTitleAnnotation("RBAC: Grant Elevation"),
+			mcp.WithDestructiveHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.grantElevationHandler)},
+	}
+}
+
+func (s *Server) requestElevationHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var permission MissingPermission
+	reason := "requested with request_elevation"
+	elevationID := strings.TrimSpace(mcp.ParseString(request, "elevation_id", ""))
+	var elevation elevationRequest
+	if elevationID != "" {
+		var ok bool
+		if elevation, ok = s.elevations.get(elevationID); !ok {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Elevation %s not found; only the latest %d forbidden calls are kept", elevationID, maxElevationRequests)), nil
+		}
+		permission = elevation.Permission
+		reason = fmt.Sprintf("%s was forbidden to %s", elevation.Tool, permission)
+	} else {
+		attributes, err := s.parseAccessRequest(request)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ %v, or pass the elevation_id from a Forbidden result", err)), nil
+		}
+		permission = MissingPermission{
+			User:      strings.TrimSpace(mcp.ParseString(request, "user", "")),
+			Verb:      attributes.Verb,
+			Resource:  attributes.Resource,
+			Group:     attributes.Group,
+			Namespace: attributes.Namespace,
+			Name:      attributes.Name,
+		}
+		if attributes.Subresource != "" {
+			permission.Resource += "/" + attributes.Subresource
+		}
+		if permission.User == "" {
+			return mcp.NewToolResultText("❌ user is required without elevation_id, e.g. system:serviceaccount:openshift-mcp:openshift-mcp"), nil
+		}
+	}
+
+	manifest, err := elevationYAML(permission, reason)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to render the RBAC manifest: %v", err)), nil
+	}
+
+	result := "🔑 Permission Elevation\n"
+	result += "=======================\n\n"
+	result += fmt.Sprintf("👤 Subject: %s\n", permission.User)
+	result += fmt.Sprintf("🚫 Missing: %s\n", permission)
+	if elevationID != "" {
+		result += fmt.Sprintf("🔧 Forbidden call: %s (%s ago)\n", elevation.Tool, time.Since(elevation.Created).Round(time.Second))
+	}
+	kinds := "ClusterRole and ClusterRoleBinding"
+	if permission.Namespace != "" {
+		kinds = "Role and RoleBinding"
+	}
+	result += fmt.Sprintf("\n📄 %s that would grant it:\n```yaml\n%s```\n", kinds, manifest)
+	result += "\n💡 Grant only what the task needs; the rule is limited to this verb and resource"
+	if elevationID != "" {
+		result += fmt.Sprintf("\n💡 After review, grant_elevation elevation_id=%s approved_by=<name> applies it and retries %s", elevationID, elevation.Tool)
+	}
+	result += "\n💡 Or have a cluster admin save the YAML and run oc apply -f on it"
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) grantElevationHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	elevationID := strings.TrimSpace(mcp.ParseString(request, "elevation_id", ""))
+	approver := strings.TrimSpace(mcp.ParseString(request, "approved_by", ""))
+	if elevationID == "" || approver == "" {
+		return mcp.NewToolResultText("❌ elevation_id and approved_by are required"), nil
+	}
+	elevation, ok := s.elevations.get(elevationID)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Elevation %s not found; only the latest %d forbidden calls are kept", elevationID, maxElevationRequests)), nil
+	}
+	retry := parseBoolString(mcp.ParseString(request, "retry", "true"))
+
+	result := "🔑 Grant Elevation\n"
+	result += "==================\n\n"
+	result += fmt.Sprintf("👤 Subject: %s\n", elevation.Permission.User)
+	result += fmt.Sprintf("✅ Granting: %s\n", elevation.Permission)
+	result += fmt.Sprintf("🖊️  Approved by: %s\n", approver)
+
+	applied, err := s.applyElevation(ctx, elevation.Permission, fmt.Sprintf("%s was forbidden to %s", elevation.Tool, elevation.Permission), approver)
+	for _, object := range applied {
+		result += fmt.Sprintf("   • %s\n", object)
+	}
+	if err != nil {
+		recordToolError(ctx, err)
+		result = "❌ Failed to grant the permission: " + err.Error() + "\n\n" + result
+		if apierrors.IsForbidden(err) {
+			result += "\n💡 The server's identity may not grant permissions it lacks; have a cluster admin apply the YAML from request_elevation"
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+	s.elevations.markGranted(elevationID)
+	logrus.Warnf("Granted %s to %s for %s, approved by %s", elevation.Permission, elevation.Permission.User, elevation.Tool, approver)
+
+	if !retry {
+		result += fmt.Sprintf("\n💡 Run %s again to use the permission", elevation.Tool)
+		return mcp.NewToolResultText(result), nil
+	}
+
+	retryRequest := mcp.CallToolRequest{}
+	retryRequest.Params.Name = elevation.Tool
+	retryRequest.Params.Arguments = elevation.Arguments
+	retried, err := s.CallTool(ctx, retryRequest)
+	result += fmt.Sprintf("\n🔁 Retried %s:\n", elevation.Tool)
+	switch {
+	case err != nil:
+		result += fmt.Sprintf("❌ %v\n", err)
+	case retried != nil:
+		result += resultContentText(retried) + "\n"
+		if info, failed := ResultError(retried); failed && info.Kind == ErrorForbidden {
+			result += "\n⏳ RBAC changes can take a few seconds to reach every API server; retry the call shortly"
+		}
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// RequestElevationHandler is a public wrapper for requestElevationHandler
+func (s *Server) RequestElevationHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.requestElevationHandler(ctx, request)
+}
+
+// GrantElevationHandler is a public wrapper for grantElevationHandler
+func (s *Server) GrantElevationHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.grantElevationHandler(ctx, request)
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+func TestParseForbidden(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected *MissingPermission
+	}{
+		{`pods "web-1" is forbidden: User "system:serviceaccount:openshift-mcp:server" cannot delete resource "pods" in API group "" in the namespace "shop"`,
+			&MissingPermission{User: "system:serviceaccount:openshift-mcp:server", Verb: "delete", Resource: "pods", Namespace: "shop", Name: "web-1"}},
+		{`Failed to list nodes: nodes is forbidden: User "alice" cannot list resource "nodes" in API group "" at the cluster scope`,
+			&MissingPermission{User: "alice", Verb: "list", Resource: "nodes"}},
+		{`deployments.apps "web" is forbidden: User "alice" cannot patch resource "deployments/scale" in API group "apps" in the namespace "shop"`,
+			&MissingPermission{User: "alice", Verb: "patch", Resource: "deployments/scale", Group: "apps", Namespace: "shop", Name: "web"}},
+		{"scale_deployment is not allowed: change freeze", nil},
+	}
+	for _, tt := range tests {
+		permission, ok := parseForbidden(tt.message)
+		if tt.expected == nil {
+			if ok {
+				t.Errorf("parseForbidden(%q) = %+v, expected no permission", tt.message, permission)
+			}
+			continue
+		}
+		if !ok || *permission != *tt.expected {
+			t.Errorf("parseForbidden(%q) = %+v, expected %+v", tt.message, permission, tt.expected)
+		}
+	}
+}
+
+func TestElevationYAML(t *testing.T) {
+	tests := []struct {
+		permission MissingPermission
+		contains   []string
+		excludes   []string
+	}{
+		{MissingPermission{User: "system:serviceaccount:openshift-mcp:server", Verb: "delete", Resource: "pods", Namespace: "shop", Name: "web-1"},
+			[]string{"kind: Role\n", "kind: RoleBinding\n", "name: mcp-elevation-delete-pods", "namespace: shop", "- web-1", "kind: ServiceAccount", "name: server"},
+			[]string{"ClusterRole"}},
+		{MissingPermission{User: "alice", Verb: "list", Resource: "pods"},
+			[]string{"kind: ClusterRole\n", "kind: ClusterRoleBinding\n", "kind: User", "name: alice"},
+			[]string{"resourceNames", "namespace:"}},
+		{MissingPermission{User: "alice", Verb: "create", Resource: "pods/exec", Namespace: "shop", Name: "web-1"},
+			[]string{"name: mcp-elevation-create-pods-exec", "- pods/exec"},
+			[]string{"resourceNames"}},
+	}
+	for _, tt := range tests {
+		manifest, err := elevationYAML(tt.permission, "test")
+		if err != nil {
+			t.Fatalf("elevationYAML(%s) returned %v", tt.permission, err)
+		}
+		for _, text := range tt.contains {
+			if !strings.Contains(manifest, text) {
+				t.Errorf("elevationYAML(%s) missing %q:\n%s", tt.permission, text, manifest)
+			}
+		}
+		for _, text := range tt.excludes {
+			if strings.Contains(manifest, text) {
+				t.Errorf("elevationYAML(%s) contains %q:\n%s", tt.permission, text, manifest)
+			}
+		}
+	}
+}
+
+func TestGrantElevationRetriesForbiddenCall(t *testing.T) {
+	s := newResilienceTestServer(5)
+	client := kubefake.NewSimpleClientset()
+	s.k8sClient = client
+	pods := schema.GroupResource{Resource: "pods"}
+	deletePod := s.withResilience("delete_pod", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		bindings, _ := client.RbacV1().RoleBindings("shop").List(ctx, metav1.ListOptions{})
+		if len(bindings.Items) == 0 {
+			err := apierrors.NewForbidden(pods, "web-1", nil)
+			err.ErrStatus.Message = `pods "web-1" is forbidden: User "system:serviceaccount:openshift-mcp:server" cannot delete resource "pods" in API group "" in the namespace "shop"`
+			return toolError(ctx, "Failed to delete pod", err), nil
+		}
+		return mcp.NewToolResultText("🗑️ Deleted pod web-1"), nil
+	})
+	s.tools = map[string]server.ToolHandlerFunc{"delete_pod": deletePod}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "delete_pod"
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "name": "web-1"}
+	result, _ := deletePod(context.Background(), request)
+	info, ok := ResultError(result)
+	if !ok || info.Kind != ErrorForbidden || info.ElevationID == "" || info.MissingPermission == nil {
+		t.Fatalf("ResultError() = %+v, %v, expected a Forbidden error with an elevation ID", info, ok)
+	}
+	if text := resultText(result); !strings.Contains(text, "request_elevation elevation_id="+info.ElevationID) {
+		t.Errorf("forbidden result does not offer elevation:\n%s", text)
+	}
+
+	grant := mcp.CallToolRequest{}
+	grant.Params.Arguments = map[string]interface{}{"elevation_id": info.ElevationID}
+	if text := resultText(mustCall(t, s.grantElevationHandler, grant)); !strings.Contains(text, "approved_by are required") {
+		t.Errorf("grant_elevation without approver = %q, expected a refusal", text)
+	}
+
+	grant.Params.Arguments = map[string]interface{}{"elevation_id": info.ElevationID, "approved_by": "oncall"}
+	text := resultText(mustCall(t, s.grantElevationHandler, grant))
+	for _, expected := range []string{"Role shop/mcp-elevation-delete-pods", "RoleBinding shop/mcp-elevation-delete-pods", "Deleted pod web-1"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("grant_elevation output missing %q:\n%s", expected, text)
+		}
+	}
+	role, err := client.RbacV1().Roles("shop").Get(context.Background(), "mcp-elevation-delete-pods", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Role not created: %v", err)
+	}
+	if role.Annotations[elevationApproverAnnotation] != "oncall" || len(role.Rules) != 1 || role.Rules[0].ResourceNames[0] != "web-1" {
+		t.Errorf("Role = %+v, expected delete on pod web-1 approved by oncall", role)
+	}
+}
+
+// replicaStore is a memory store that reports itself as shared, standing in
+// for redis between two replicas in one process
+type replicaStore struct{ *store.MemoryStore }
+
+func (replicaStore) Backend() string { return store.BackendRedis }
+
+func TestSharedElevationRequests(t *testing.T) {
+	shared := replicaStore{store.NewMemoryStore()}
+	refusing := &elevationRequests{store: shared}
+	approving := &elevationRequests{store: shared}
+
+	permission := MissingPermission{Verb: "delete", Resource: "pods", Namespace: "shop", Name: "web-1"}
+	id := refusing.add("delete_resource", map[string]interface{}{"name": "web-1"}, permission)
+	request, ok := approving.get(id)
+	if !ok || request.Tool != "delete_resource" || request.Permission != permission || request.Arguments["name"] != "web-1" {
+		t.Fatalf("get(%s) on another replica = %+v, %v", id, request, ok)
+	}
+	approving.markGranted(id)
+	if request, _ := refusing.get(id); !request.Granted {
+		t.Error("a grant on one replica is not visible on the other")
+	}
+	if _, ok := approving.get("el-missing"); ok {
+		t.Error("get() found an unknown elevation")
+	}
+}
+
+func mustCall(t *testing.T, handler server.ToolHandlerFunc, request mcp.CallToolRequest) *mcp.CallToolResult {
+	t.Helper()
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler returned %v", err)
+	}
+	return result
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Kinds of tool failure reported in the error field of a result's _meta, so
+// the planner and API clients can branch on them instead of parsing text
+const (
+	ErrorNotFound           = "NotFound"
+	ErrorForbidden          = "Forbidden"
+	ErrorConflict           = "Conflict"
+	ErrorTimeout            = "Timeout"
+	ErrorClusterUnavailable = "ClusterUnavailable" // the cluster or another dependency could not be reached
+	ErrorInvalidArgument    = "InvalidArgument"
+	ErrorUnknown            = "Unknown"
+)
+
+// ErrorKinds lists every kind a tool result can report
+var ErrorKinds = []string{
+	ErrorNotFound, ErrorForbidden, ErrorConflict, ErrorTimeout, ErrorClusterUnavailable, ErrorInvalidArgument, ErrorUnknown,
+}
+
+// errorMetaKey is the _meta field holding a failed result's ToolErrorInfo
+const errorMetaKey = "error"
+
+// ToolErrorInfo is the machine-readable description of a failed tool call
+type ToolErrorInfo struct {
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+
+	// MissingPermission is the access a Forbidden call lacked, when the API
+	// server said, and ElevationID names the call for grant_elevation
+	MissingPermission *MissingPermission `json:"missing_permission,omitempty"`
+	ElevationID       string             `json:"elevation_id,omitempty"`
+}
+
+// errorKindMarkers classify errors that have been flattened to text, such as
+// oc/kubectl output or handler messages, checked in order
+var errorKindMarkers = []struct {
+	kind    string
+	markers []string
+}{
+	{ErrorClusterUnavailable, append([]string{"circuit breaker is open", "client not available", "not connected to"}, transportErrorMarkers...)},
+	{ErrorTimeout, []string{"timed out", "deadline exceeded"}},
+	{ErrorForbidden, []string{"forbidden", "unauthorized", "not allowed", "permission denied", "access denied", "change freeze"}},
+	{ErrorConflict, []string{"conflict", "already exists", "the object has been modified", "is still running"}},
+	{ErrorNotFound, []string{"not found", "does not exist", "no such file"}},
+	{ErrorInvalidArgument, []string{"invalid", "is required", "must be", "unsupported", "unknown "}},
+}
+
+// ClassifyError maps an error from a tool's dependencies to an error kind
+func ClassifyError(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrBreakerOpen):
+		return ErrorClusterUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		switch {
+		case apierrors.IsNotFound(err):
+			return ErrorNotFound
+		case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+			return ErrorForbidden
+		case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+			return ErrorConflict
+		case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+			return ErrorTimeout
+		case apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err), apierrors.IsTooManyRequests(err),
+			apierrors.IsUnexpectedServerError(err):
+			return ErrorClusterUnavailable
+		case apierrors.IsBadRequest(err), apierrors.IsInvalid(err), apierrors.IsMethodNotSupported(err):
+			return ErrorInvalidArgument
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorClusterUnavailable
+	}
+	return classifyErrorText(err.Error())
+}
+
+// classifyErrorText maps an error message to an error kind
+func classifyErrorText(message string) string {
+	message = strings.ToLower(message)
+	for _, entry := range errorKindMarkers {
+		for _, marker := range entry.markers {
+			if strings.Contains(message, marker) {
+				return entry.kind
+			}
+		}
+	}
+	return ErrorUnknown
+}
+
+// errorRetryable reports whether the same call may succeed if repeated later
+func errorRetryable(kind string) bool {
+	switch kind {
+	case ErrorTimeout, ErrorClusterUnavailable, ErrorConflict:
+		return true
+	}
+	return false
+}
+
+// NewToolError describes err the way a failed tool result reports it
+func NewToolError(err error) *ToolErrorInfo {
+	kind := ClassifyError(err)
+	return &ToolErrorInfo{Kind: kind, Message: err.Error(), Retryable: errorRetryable(kind)}
+}
+
+// withErrorKind marks result as failed with kind and message
+func withErrorKind(result *mcp.CallToolResult, kind, message string) *mcp.CallToolResult {
+	if result.Meta == nil {
+		result.Meta = make(map[string]any)
+	}
+	info := ToolErrorInfo{Kind: kind, Message: message, Retryable: errorRetryable(kind)}
+	if kind == ErrorForbidden {
+		info.MissingPermission, _ = parseForbidden(message)
+	}
+	result.Meta[errorMetaKey] = info
+	return result
+}
+
+// failedResultMessage returns the message of a result that reports a failure,
+// either flagged as an error or starting with ❌ as handlers write them
+func failedResultMessage(result *mcp.CallToolResult) (string, bool) {
+	text := ""
+	if result != nil && len(result.Content) > 0 {
+		if content, ok := result.Content[0].(mcp.TextContent); ok {
+			text = content.Text
+		}
+	}
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "❌") && (result == nil || !result.IsError) {
+		return "", false
+	}
+	message, _, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(trimmed, "❌")), "\n")
+	return message, true
+}
+
+// annotateToolError adds the error field to a failed result that does not
+// have one yet, classifying the first recorded error that has a known kind
+// and the result text otherwise
+func annotateToolError(result *mcp.CallToolResult, recorded []error) {
+	if result == nil {
+		return
+	}
+	if _, ok := result.Meta[errorMetaKey]; ok {
+		return
+	}
+	message, failed := failedResultMessage(result)
+	if !failed {
+		return
+	}
+	for _, err := range recorded {
+		if kind := ClassifyError(err); kind != ErrorUnknown {
+			withErrorKind(result, kind, err.Error())
+			return
+		}
+	}
+	withErrorKind(result, classifyErrorText(message), message)
+}
+
+// ResultError returns the error field of a tool result, if the call failed.
+// Results decoded from JSON carry the field as a map.
+func ResultError(result *mcp.CallToolResult) (*ToolErrorInfo, bool) {
+	if result == nil {
+		return nil, false
+	}
+	switch value := result.Meta[errorMetaKey].(type) {
+	case ToolErrorInfo:
+		return &value, true
+	case *ToolErrorInfo:
+		return value, value != nil
+	case map[string]any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, false
+		}
+		info := &ToolErrorInfo{}
+		if err := json.Unmarshal(data, info); err != nil {
+			return nil, false
+		}
+		return info, info.Kind != ""
+	}
+	return nil, false
+}
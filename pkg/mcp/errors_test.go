@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassifyError(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{apierrors.NewNotFound(deployments, "web"), ErrorNotFound},
+		{fmt.Errorf("failed to get deployment: %w", apierrors.NewForbidden(deployments, "web", errors.New("no RBAC"))), ErrorForbidden},
+		{apierrors.NewConflict(deployments, "web", errors.New("the object has been modified")), ErrorConflict},
+		{apierrors.NewAlreadyExists(deployments, "web"), ErrorConflict},
+		{apierrors.NewServerTimeout(deployments, "list", 5), ErrorTimeout},
+		{apierrors.NewServiceUnavailable("etcd is down"), ErrorClusterUnavailable},
+		{apierrors.NewBadRequest("bad selector"), ErrorInvalidArgument},
+		{context.DeadlineExceeded, ErrorTimeout},
+		{fmt.Errorf("%w for cluster", ErrBreakerOpen), ErrorClusterUnavailable},
+		{errors.New("dial tcp 10.0.0.1:6443: connect: connection refused"), ErrorClusterUnavailable},
+		{errors.New(`Error from server (NotFound): pods "web-1" not found`), ErrorNotFound},
+		{errors.New("nothing to commit, working tree clean"), ErrorUnknown},
+	}
+	for _, tt := range tests {
+		if kind := ClassifyError(tt.err); kind != tt.expected {
+			t.Errorf("ClassifyError(%q) = %s, expected %s", tt.err, kind, tt.expected)
+		}
+	}
+}
+
+func TestWithResilienceReportsErrorKind(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name      string
+		handler   func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		kind      string
+		retryable bool
+	}{
+		{"recorded error", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return toolError(ctx, "Failed to get deployment", apierrors.NewNotFound(deployments, "web")), nil
+		}, ErrorNotFound, false},
+		{"text result with recorded error", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			recordToolError(ctx, apierrors.NewConflict(deployments, "web", errors.New("the object has been modified")))
+			return mcp.NewToolResultText("❌ Failed to scale deployment web"), nil
+		}, ErrorConflict, true},
+		{"text only", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("❌ Invalid replicas value: abc"), nil
+		}, ErrorInvalidArgument, false},
+		{"success", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("📦 Pods\n❌ web-1 CrashLoopBackOff"), nil
+		}, "", false},
+	}
+	for _, tt := range tests {
+		result, _ := callTool(newResilienceTestServer(5), "scale_deployment", tt.handler)
+		info, failed := ResultError(result)
+		if tt.kind == "" {
+			if failed {
+				t.Errorf("%s: ResultError() = %+v, expected no error", tt.name, info)
+			}
+			continue
+		}
+		if !failed || info.Kind != tt.kind || info.Retryable != tt.retryable {
+			t.Errorf("%s: ResultError() = %+v, %v, expected kind %s retryable %v", tt.name, info, failed, tt.kind, tt.retryable)
+		}
+	}
+}
+
+func TestWithResilienceTimeoutErrorKind(t *testing.T) {
+	s := newResilienceTestServer(5)
+	s.toolTimeouts["list_pods"] = 10 * time.Millisecond
+	release := make(chan struct{})
+	defer close(release)
+	result, _ := callTool(s, "list_pods", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-release
+		return nil, ctx.Err()
+	})
+	if info, ok := ResultError(result); !ok || info.Kind != ErrorTimeout || !info.Retryable {
+		t.Errorf("ResultError() after timeout = %+v, %v, expected a retryable %s", info, ok, ErrorTimeout)
+	}
+}
+
+func TestResultErrorFromJSON(t *testing.T) {
+	result := withErrorKind(mcp.NewToolResultText("🔒 scale_deployment is not allowed"), ErrorForbidden, "scale_deployment is not allowed")
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := json.RawMessage(data)
+	decoded, err := mcp.ParseCallToolResult(&raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := ResultError(decoded)
+	if !ok || *info != (ToolErrorInfo{Kind: ErrorForbidden, Message: "scale_deployment is not allowed"}) {
+		t.Errorf("ResultError() of decoded result = %+v, %v; _meta = %s", info, ok, data)
+	}
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// analysisReportDir holds exported analysis results under the diagnostics
+// directory
+const analysisReportDir = "reports"
+
+// exportParamDescription documents the export parameter of the analyze_* tools
+const exportParamDescription = "Also write the full analysis result to a file: json, sarif (for security pipelines) or html (a standalone report)"
+
+// Analyses the REST API can run and export, by the path of their artifact
+var artifactAnalyses = map[string]func(*diagnostics.AnalysisEngine, context.Context, string) (*diagnostics.AnalysisResult, error){
+	"must_gather": (*diagnostics.AnalysisEngine).AnalyzeMustGather,
+	"logs":        (*diagnostics.AnalysisEngine).AnalyzeLogs,
+	"tcpdump":     (*diagnostics.AnalysisEngine).AnalyzeTcpdump,
+	"ingress":     (*diagnostics.AnalysisEngine).AnalyzeIngress,
+	"ovn":         (*diagnostics.AnalysisEngine).AnalyzeOVN,
+	"storage":     (*diagnostics.AnalysisEngine).AnalyzeStorage,
+}
+
+// AnalyzeArtifact runs the analysis of a kind (must_gather, logs, tcpdump,
+// ingress, ovn or storage) on the artifact at path
+func (s *Server) AnalyzeArtifact(ctx context.Context, kind, path string) (*diagnostics.AnalysisResult, error) {
+	analyze, ok := artifactAnalyses[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown analysis %q: use must_gather, logs, tcpdump, ingress, ovn or storage", kind)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	return analyze(s.analysisEngine, ctx, path)
+}
+
+// ExportAnalysis writes an analysis result in an export format to the
+// reports directory and returns the file's path
+func (s *Server) ExportAnalysis(result *diagnostics.AnalysisResult, format string) (string, error) {
+	data, err := result.Export(format)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(s.diagnosticsBaseDir(), analysisReportDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	name := strings.TrimSuffix(result.Type, "-analysis")
+	if name == "" {
+		name = "analysis"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405.000"), format))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return path, nil
+}
+
+// exportNote exports a result when the caller asked for it and tells where
+// the file went
+func (s *Server) exportNote(ctx context.Context, result *diagnostics.AnalysisResult, format string) string {
+	if format == "" {
+		return ""
+	}
+	path, err := s.ExportAnalysis(result, format)
+	if err != nil {
+		recordToolError(ctx, err)
+		return fmt.Sprintf("\n❌ Failed to export the analysis as %s: %v\n", strings.ToUpper(format), err)
+	}
+	return fmt.Sprintf("\n📤 Exported the analysis as %s: %s\n", strings.ToUpper(format), path)
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func TestAnalyzeLogsExport(t *testing.T) {
+	logs := t.TempDir()
+	if err := os.WriteFile(filepath.Join(logs, "app.log"), []byte("dial tcp 10.0.0.5:5432: connect: connection refused\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	base := t.TempDir()
+	s := &Server{
+		config:         &Config{Diagnostics: &DiagnosticsConfig{BaseDir: base}},
+		analysisEngine: diagnostics.NewAnalysisEngine(logrus.New()),
+	}
+
+	if text := resultText(mustCall(t, s.analyzeLogsHandler, ruleRequest(map[string]interface{}{"log_path": logs, "export": "pdf"}))); !strings.Contains(text, `unsupported export format "pdf"`) {
+		t.Errorf("unexpected result for an unsupported format:\n%s", text)
+	}
+
+	text := resultText(mustCall(t, s.analyzeLogsHandler, ruleRequest(map[string]interface{}{"log_path": logs, "export": "sarif"})))
+	const prefix = "📤 Exported the analysis as SARIF: "
+	i := strings.Index(text, prefix)
+	if i < 0 {
+		t.Fatalf("analyze_logs output has no export:\n%s", text)
+	}
+	path := strings.TrimSpace(text[i+len(prefix):])
+	if filepath.Dir(path) != filepath.Join(base, analysisReportDir) || !strings.HasPrefix(filepath.Base(path), "log-") || filepath.Ext(path) != ".sarif" {
+		t.Errorf("export path = %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				Level string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil || len(log.Runs) != 1 || len(log.Runs[0].Results) == 0 {
+		t.Errorf("exported SARIF = %s (%v)", data, err)
+	}
+
+	if _, err := s.AnalyzeArtifact(context.Background(), "sosreport", logs); err == nil || !strings.Contains(err.Error(), "unknown analysis") {
+		t.Errorf("AnalyzeArtifact(sosreport) error = %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// changeFreezeAnnotation on a namespace freezes it; the value is "true"
+	// or the reason for the freeze
+	changeFreezeAnnotation      = "mcp.openshift.io/change-freeze"
+	changeFreezeUntilAnnotation = "mcp.openshift.io/change-freeze-until"
+
+	// defaultChangeFreezeConfigMap holds the cluster-wide freeze
+	defaultChangeFreezeConfigMap = "openshift-mcp/change-freeze"
+
+	// freezeOverrideParam is added to every write tool; it must carry the
+	// override-token of the freeze ConfigMap to act during a freeze
+	freezeOverrideParam = "freeze_override"
+)
+
+// ChangeFreeze is an active freeze on the whole cluster or one namespace
+type ChangeFreeze struct {
+	Namespace string    `json:"namespace,omitempty"` // empty for the whole cluster
+	Reason    string    `json:"reason,omitempty"`
+	Until     time.Time `json:"until,omitempty"`
+	Source    string    `json:"source"`
+
+	overrideToken string
+}
+
+// Scope names what the freeze covers
+func (f ChangeFreeze) Scope() string {
+	if f.Namespace == "" {
+		return "the cluster"
+	}
+	return "namespace " + f.Namespace
+}
+
+// Describe renders the freeze for refusals and the planner
+func (f ChangeFreeze) Describe() string {
+	text := "change freeze on " + f.Scope()
+	if f.Reason != "" {
+		text += ": " + f.Reason
+	}
+	if !f.Until.IsZero() {
+		text += fmt.Sprintf(" (until %s)", f.Until.UTC().Format(time.RFC3339))
+	}
+	return text
+}
+
+// parseFreezeUntil reads an optional end time; an unreadable one keeps the
+// freeze in place rather than lifting it
+func parseFreezeUntil(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, true
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logrus.Warnf("Ignoring invalid change freeze end time %q: %v", value, err)
+		return time.Time{}, true
+	}
+	return until, time.Now().Before(until)
+}
+
+// changeFreezeConfigMap returns the namespace and name of the cluster freeze ConfigMap
+func (s *Server) changeFreezeConfigMap() (string, string) {
+	ref := defaultChangeFreezeConfigMap
+	if s.config != nil && s.config.ChangeFreezeConfigMap != "" {
+		ref = s.config.ChangeFreezeConfigMap
+	}
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		return "openshift-mcp", ref
+	}
+	return namespace, name
+}
+
+// clusterFreeze reads the freeze ConfigMap. It returns nil when there is no
+// active freeze; its namespaces key limits the freeze to those namespaces.
+// The override token approves changes during any freeze, including the
+// namespace annotations, so it is returned even without a cluster freeze.
+func (s *Server) clusterFreeze(ctx context.Context) (*ChangeFreeze, []string, string) {
+	namespace, name := s.changeFreezeConfigMap()
+	configMap, err := s.k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logrus.WithError(err).Warnf("Failed to read change freeze ConfigMap %s/%s; assuming no freeze", namespace, name)
+		}
+		return nil, nil, ""
+	}
+	token := strings.TrimSpace(configMap.Data["override-token"])
+	if enabled := strings.TrimSpace(configMap.Data["enabled"]); enabled != "" && !parseBoolString(enabled) {
+		return nil, nil, token
+	}
+	until, active := parseFreezeUntil(configMap.Data["until"])
+	if !active {
+		return nil, nil, token
+	}
+
+	var namespaces []string
+	for _, ns := range strings.Split(configMap.Data["namespaces"], ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return &ChangeFreeze{
+		Reason:        strings.TrimSpace(configMap.Data["reason"]),
+		Until:         until,
+		Source:        fmt.Sprintf("ConfigMap %s/%s", namespace, name),
+		overrideToken: token,
+	}, namespaces, token
+}
+
+// namespaceFreeze reads the freeze annotation of a namespace
+func namespaceFreeze(name string, annotations map[string]string, overrideToken string) *ChangeFreeze {
+	value, ok := annotations[changeFreezeAnnotation]
+	value = strings.TrimSpace(value)
+	if !ok || value == "" || strings.EqualFold(value, "false") {
+		return nil
+	}
+	until, active := parseFreezeUntil(annotations[changeFreezeUntilAnnotation])
+	if !active {
+		return nil
+	}
+	reason := value
+	if strings.EqualFold(value, "true") {
+		reason = ""
+	}
+	return &ChangeFreeze{
+		Namespace:     name,
+		Reason:        reason,
+		Until:         until,
+		Source:        fmt.Sprintf("annotation %s on namespace %s", changeFreezeAnnotation, name),
+		overrideToken: overrideToken,
+	}
+}
+
+// ChangeFreezeFor returns the freeze that applies to a change in namespace,
+// or to a cluster-scoped change when namespace is empty, or nil
+func (s *Server) ChangeFreezeFor(ctx context.Context, namespace string) *ChangeFreeze {
+	if s.k8sClient == nil {
+		return nil
+	}
+	freeze, namespaces, token := s.clusterFreeze(ctx)
+	if freeze != nil {
+		if len(namespaces) == 0 {
+			return freeze
+		}
+		for _, ns := range namespaces {
+			if ns == namespace {
+				frozen := *freeze
+				frozen.Namespace = namespace
+				return &frozen
+			}
+		}
+	}
+	if namespace == "" {
+		return nil
+	}
+
+	ns, err := s.k8sClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return namespaceFreeze(ns.Name, ns.Annotations, token)
+}
+
+// ChangeFreezes lists the active freezes on the cluster and its namespaces
+func (s *Server) ChangeFreezes(ctx context.Context) ([]ChangeFreeze, error) {
+	if s.k8sClient == nil {
+		return nil, fmt.Errorf("Kubernetes client not available")
+	}
+	var freezes []ChangeFreeze
+	freeze, namespaces, _ := s.clusterFreeze(ctx)
+	if freeze != nil {
+		if len(namespaces) == 0 {
+			freezes = append(freezes, *freeze)
+		}
+		for _, ns := range namespaces {
+			frozen := *freeze
+			frozen.Namespace = ns
+			freezes = append(freezes, frozen)
+		}
+	}
+
+	list, err := s.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return freezes, err
+	}
+	for _, ns := range list.Items {
+		if frozen := namespaceFreeze(ns.Name, ns.Annotations, ""); frozen != nil {
+			freezes = append(freezes, *frozen)
+		}
+	}
+	sort.SliceStable(freezes, func(i, j int) bool { return freezes[i].Namespace < freezes[j].Namespace })
+	return freezes, nil
+}
+
+// toolNamespace returns the namespace a write tool call targets, or "" for
+// cluster-scoped tools
+func toolNamespace(tool mcp.Tool, request mcp.CallToolRequest) string {
+	args := request.GetArguments()
+	for _, param := range []string{"namespace", "namespace_name"} {
+		if value, ok := args[param].(string); ok && value != "" {
+			return value
+		}
+	}
+	if _, ok := tool.InputSchema.Properties["namespace"]; ok {
+		return "default"
+	}
+	return ""
+}
+
+// withFreezeOverride adds the freeze_override parameter to a write tool
+func withFreezeOverride(tool mcp.Tool) mcp.Tool {
+	if toolReadOnly(tool) {
+		return tool
+	}
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[freezeOverrideParam] = map[string]any{
+		"type":        "string",
+		"description": "Approval token that lets this change run during a change freeze",
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// checkChangeFreeze refuses a write tool call during a freeze unless it
+// carries the freeze's override token
+func (s *Server) checkChangeFreeze(ctx context.Context, name string, tool mcp.Tool, request mcp.CallToolRequest) *mcp.CallToolResult {
+	namespace := toolNamespace(tool, request)
+	freeze := s.ChangeFreezeFor(ctx, namespace)
+	if freeze == nil {
+		return nil
+	}
+
+	override, _ := request.GetArguments()[freezeOverrideParam].(string)
+	if override != "" && freeze.overrideToken != "" && subtle.ConstantTimeCompare([]byte(override), []byte(freeze.overrideToken)) == 1 {
+		logrus.Warnf("%s overrode the %s with an approval token", name, freeze.Describe())
+		return nil
+	}
+
+	result := fmt.Sprintf("🧊 %s is not allowed: %s\n", name, freeze.Describe())
+	result += fmt.Sprintf("Set by: %s\n", freeze.Source)
+	if override != "" {
+		result += "❌ The freeze_override token is not valid\n"
+	}
+	result += "\n💡 Read-only tools still work: use diagnose and list tools, or dry_run=true to prepare the change for after the freeze"
+	if freeze.overrideToken != "" {
+		result += "\n💡 An approved emergency change can pass the freeze's approval token as freeze_override"
+	}
+	return withErrorKind(mcp.NewToolResultText(result), ErrorForbidden, fmt.Sprintf("%s is not allowed: %s", name, freeze.Describe()))
+}
+
+func (s *Server) initChangeFreezeTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("change_freeze_status",
+			mcp.WithDescription("Show active change freezes on the cluster and namespaces; write tools refuse to run in frozen scopes"),
+			mcp.WithString("namespace", mcp.Description("Only report whether changes to this namespace are frozen")),
+			mcp.WithTitleAnnotation("Server: Change Freeze Status"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.changeFreezeStatusHandler)},
+	}
+}
+
+func (s *Server) changeFreezeStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	configMapNamespace, configMapName := s.changeFreezeConfigMap()
+
+	result := "🧊 Change Freeze Status\n"
+	result += "=======================\n\n"
+	result += fmt.Sprintf("Cluster freeze ConfigMap: %s/%s\n", configMapNamespace, configMapName)
+	result += fmt.Sprintf("Namespace annotation: %s\n\n", changeFreezeAnnotation)
+
+	if namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", "")); namespace != "" {
+		if freeze := s.ChangeFreezeFor(ctx, namespace); freeze != nil {
+			result += fmt.Sprintf("🚫 Changes to namespace %s are frozen: %s\n", namespace, freeze.Describe())
+			result += fmt.Sprintf("Set by: %s", freeze.Source)
+		} else {
+			result += fmt.Sprintf("✅ Changes to namespace %s are allowed", namespace)
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	freezes, err := s.ChangeFreezes(ctx)
+	if err != nil {
+		return toolError(ctx, "Failed to list namespaces", err), nil
+	}
+	if len(freezes) == 0 {
+		result += "✅ No change freeze is in effect"
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("🚫 %d active freeze(s):\n", len(freezes))
+	for _, freeze := range freezes {
+		result += fmt.Sprintf("• %s\n", freeze.Describe())
+		result += fmt.Sprintf("  Set by: %s\n", freeze.Source)
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ChangeFreezeStatusHandler is a public wrapper for changeFreezeStatusHandler
+func (s *Server) ChangeFreezeStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.changeFreezeStatusHandler(ctx, request)
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNamespaceFreeze(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		annotations map[string]string
+		frozen      bool
+		reason      string
+	}{
+		{nil, false, ""},
+		{map[string]string{changeFreezeAnnotation: "false"}, false, ""},
+		{map[string]string{changeFreezeAnnotation: "true"}, true, ""},
+		{map[string]string{changeFreezeAnnotation: "Black Friday"}, true, "Black Friday"},
+		{map[string]string{changeFreezeAnnotation: "true", changeFreezeUntilAnnotation: future}, true, ""},
+		{map[string]string{changeFreezeAnnotation: "true", changeFreezeUntilAnnotation: past}, false, ""},
+		{map[string]string{changeFreezeAnnotation: "true", changeFreezeUntilAnnotation: "next week"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		freeze := namespaceFreeze("shop", tt.annotations, "")
+		if (freeze != nil) != tt.frozen || (freeze != nil && freeze.Reason != tt.reason) {
+			t.Errorf("namespaceFreeze(%v) = %+v, expected frozen %v with reason %q", tt.annotations, freeze, tt.frozen, tt.reason)
+		}
+	}
+}
+
+func freezeConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "change-freeze", Namespace: "openshift-mcp"}, Data: data}
+}
+
+func frozenNamespace(name, reason string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{changeFreezeAnnotation: reason}}}
+}
+
+func freezeRequest(tool string, args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool
+	request.Params.Arguments = args
+	return request
+}
+
+func TestChangeFreezePolicy(t *testing.T) {
+	s := newPolicyTestServer(false)
+	s.k8sClient = kubefake.NewSimpleClientset(
+		frozenNamespace("shop", "quarter close"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+		freezeConfigMap(map[string]string{"enabled": "false", "override-token": "s3cret"}),
+	)
+
+	tests := []struct {
+		tool     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{"list_pods", map[string]interface{}{"namespace": "shop"}, "ok"},
+		{"delete_resource", map[string]interface{}{"namespace": "dev"}, "ok"},
+		{"delete_resource", map[string]interface{}{"namespace": "shop"}, "🧊 delete_resource is not allowed: change freeze on namespace shop: quarter close"},
+		{"delete_resource", map[string]interface{}{"namespace": "shop", freezeOverrideParam: "guess"}, "❌ The freeze_override token is not valid"},
+		{"delete_resource", map[string]interface{}{"namespace": "shop", freezeOverrideParam: "s3cret"}, "ok"},
+	}
+
+	for _, tt := range tests {
+		result, err := s.CallTool(context.Background(), freezeRequest(tt.tool, tt.args))
+		if err != nil || !strings.Contains(resultText(result), tt.expected) {
+			t.Errorf("CallTool(%s, %v) = %q, %v, expected %q", tt.tool, tt.args, resultText(result), err, tt.expected)
+		}
+	}
+}
+
+func TestClusterChangeFreeze(t *testing.T) {
+	s := newPolicyTestServer(false)
+	s.k8sClient = kubefake.NewSimpleClientset(freezeConfigMap(map[string]string{
+		"enabled": "true", "reason": "OCP upgrade", "namespaces": "payments, shop",
+	}))
+
+	result, _ := s.CallTool(context.Background(), freezeRequest("delete_resource", map[string]interface{}{"namespace": "payments"}))
+	if text := resultText(result); !strings.Contains(text, "change freeze on namespace payments: OCP upgrade") ||
+		!strings.Contains(text, "ConfigMap openshift-mcp/change-freeze") || strings.Contains(text, "freeze_override") {
+		t.Errorf("delete_resource in a frozen namespace = %q", text)
+	}
+	result, _ = s.CallTool(context.Background(), freezeRequest("delete_resource", map[string]interface{}{"namespace": "dev"}))
+	if text := resultText(result); text != "ok" {
+		t.Errorf("delete_resource outside the frozen namespaces = %q", text)
+	}
+
+	freezes, err := s.ChangeFreezes(context.Background())
+	if err != nil || len(freezes) != 2 || freezes[0].Namespace != "payments" || freezes[1].Namespace != "shop" {
+		t.Errorf("ChangeFreezes() = %+v, %v, expected payments and shop", freezes, err)
+	}
+
+	result, _ = s.ChangeFreezeStatusHandler(context.Background(), freezeRequest("change_freeze_status", map[string]interface{}{"namespace": "dev"}))
+	if text := resultText(result); !strings.Contains(text, "✅ Changes to namespace dev are allowed") {
+		t.Errorf("change_freeze_status for dev = %q", text)
+	}
+}
+
+func TestWithFreezeOverride(t *testing.T) {
+	write := withFreezeOverride(mcp.NewTool("scale_deployment", mcp.WithString("namespace")))
+	if _, ok := write.InputSchema.Properties[freezeOverrideParam]; !ok {
+		t.Errorf("write tool properties = %v, expected %s", write.InputSchema.Properties, freezeOverrideParam)
+	}
+	read := withFreezeOverride(mcp.NewTool("list_pods", mcp.WithReadOnlyHintAnnotation(true)))
+	if _, ok := read.InputSchema.Properties[freezeOverrideParam]; ok {
+		t.Errorf("read-only tool gained %s", freezeOverrideParam)
+	}
+}
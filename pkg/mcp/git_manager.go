@@ -1,11 +1,14 @@
 package mcp
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -21,11 +24,49 @@ type GitConfig struct {
 	Enabled     bool   `json:"enabled"`
 	CommitUser  string `json:"commit_user"`
 	CommitEmail string `json:"commit_email"`
+
+	// SyncInterval schedules fetching and pulling the remote branch; empty
+	// disables it. PullStrategy is rebase (default), merge or ff-only.
+	SyncInterval string `json:"sync_interval"`
+	PullStrategy string `json:"pull_strategy"`
+
+	// Provider selects the hosting API (github, gitlab, gitea or bitbucket)
+	// used for pull requests, status checks and browsing; empty detects it
+	// from the remote URL. ProviderURL overrides the API base URL.
+	Provider      string `json:"provider"`
+	ProviderURL   string `json:"provider_url"`
+	ProviderToken string `json:"provider_token"`
+}
+
+// ErrGitDisabled is returned by operations that need Git integration when it is turned off
+var ErrGitDisabled = errors.New("Git integration is disabled")
+
+// ErrNoGitRemote is returned when pushing without a configured remote
+var ErrNoGitRemote = errors.New("no remote URL configured")
+
+// GitRemoteError reports a failure talking to the remote repository, as
+// opposed to a local problem such as an empty commit
+type GitRemoteError struct {
+	Op  string
+	Err error
+}
+
+func (e *GitRemoteError) Error() string {
+	return fmt.Sprintf("failed to %s remote: %v", e.Op, e.Err)
+}
+
+func (e *GitRemoteError) Unwrap() error {
+	return e.Err
 }
 
 // GitManager handles Git operations for YAML files
 type GitManager struct {
 	config *GitConfig
+
+	// mu serializes pulls with commits and pushes so a scheduled sync never
+	// rewrites history under a commit in progress
+	mu       sync.Mutex
+	lastSync *GitSyncStatus
 }
 
 // NewGitManager creates a new GitManager instance
@@ -46,6 +87,9 @@ func NewGitManager(config *GitConfig) *GitManager {
 	if config.CommitEmail == "" {
 		config.CommitEmail = "openshift-mcp-bot@example.com"
 	}
+	if config.PullStrategy == "" {
+		config.PullStrategy = PullRebase
+	}
 
 	return &GitManager{
 		config: config,
@@ -68,25 +112,27 @@ func (g *GitManager) InitializeRepo() error {
 		return fmt.Errorf("failed to create repo directory: %v", err)
 	}
 
+	ctx := context.Background()
+
 	// Check if it's already a Git repository
 	if _, err := os.Stat(filepath.Join(g.config.RepoPath, ".git")); os.IsNotExist(err) {
 		// Initialize Git repository
-		if err := g.runGitCommand("init"); err != nil {
+		if err := g.runGitCommand(ctx, "init"); err != nil {
 			return fmt.Errorf("failed to initialize Git repository: %v", err)
 		}
 
 		// Configure Git user
-		if err := g.runGitCommand("config", "user.name", g.config.CommitUser); err != nil {
+		if err := g.runGitCommand(ctx, "config", "user.name", g.config.CommitUser); err != nil {
 			return fmt.Errorf("failed to configure Git user: %v", err)
 		}
 
-		if err := g.runGitCommand("config", "user.email", g.config.CommitEmail); err != nil {
+		if err := g.runGitCommand(ctx, "config", "user.email", g.config.CommitEmail); err != nil {
 			return fmt.Errorf("failed to configure Git email: %v", err)
 		}
 
 		// Add remote if specified
 		if g.config.RemoteURL != "" {
-			if err := g.runGitCommand("remote", "add", "origin", g.config.RemoteURL); err != nil {
+			if err := g.runGitCommand(ctx, "remote", "add", "origin", g.config.RemoteURL); err != nil {
 				logrus.Warnf("Failed to add remote: %v", err)
 			}
 		}
@@ -104,11 +150,11 @@ Generated on: %s
 			return fmt.Errorf("failed to create README: %v", err)
 		}
 
-		if err := g.runGitCommand("add", "README.md"); err != nil {
+		if err := g.runGitCommand(ctx, "add", "README.md"); err != nil {
 			return fmt.Errorf("failed to add README: %v", err)
 		}
 
-		if err := g.runGitCommand("commit", "-m", "Initial commit - OpenShift MCP YAML repository"); err != nil {
+		if err := g.runGitCommand(ctx, "commit", "-m", "Initial commit - OpenShift MCP YAML repository"); err != nil {
 			return fmt.Errorf("failed to create initial commit: %v", err)
 		}
 
@@ -119,9 +165,9 @@ Generated on: %s
 }
 
 // SaveYAMLFile saves a YAML file to the repository
-func (g *GitManager) SaveYAMLFile(filename, content, action, description string) (string, error) {
+func (g *GitManager) SaveYAMLFile(ctx context.Context, filename, content, action, description string) (string, error) {
 	if !g.IsEnabled() {
-		return "", fmt.Errorf("Git integration is disabled")
+		return "", ErrGitDisabled
 	}
 
 	// Create subdirectory based on action
@@ -144,7 +190,7 @@ func (g *GitManager) SaveYAMLFile(filename, content, action, description string)
 
 	// Auto-commit if enabled
 	if g.config.AutoCommit {
-		if err := g.commitFile(filePath, action, description); err != nil {
+		if err := g.commitFile(ctx, filePath, action, description); err != nil {
 			logrus.Warnf("Failed to auto-commit: %v", err)
 		}
 	}
@@ -152,21 +198,67 @@ func (g *GitManager) SaveYAMLFile(filename, content, action, description string)
 	return filePath, nil
 }
 
+// SaveTranscript writes a chat session transcript under transcripts/ and
+// commits it, whatever the auto-commit setting, for incident documentation
+func (g *GitManager) SaveTranscript(ctx context.Context, filename, content, description string) (string, error) {
+	if !g.IsEnabled() {
+		return "", ErrGitDisabled
+	}
+
+	transcriptDir := filepath.Join(g.config.RepoPath, "transcripts")
+	if err := os.MkdirAll(transcriptDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create transcripts directory: %v", err)
+	}
+	filePath := filepath.Join(transcriptDir, filename)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write transcript: %v", err)
+	}
+	if err := g.commitFile(ctx, filePath, "transcript", description); err != nil {
+		return filePath, err
+	}
+	return filePath, nil
+}
+
+// SaveRunbookDraft writes a runbook learned from a resolved chat session
+// under runbooks/drafts/ and commits it, whatever the auto-commit setting,
+// so the team reviews it before it is promoted to the tool set directory
+func (g *GitManager) SaveRunbookDraft(ctx context.Context, filename, content, description string) (string, error) {
+	if !g.IsEnabled() {
+		return "", ErrGitDisabled
+	}
+
+	draftDir := filepath.Join(g.config.RepoPath, "runbooks", "drafts")
+	if err := os.MkdirAll(draftDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create runbook drafts directory: %v", err)
+	}
+	filePath := filepath.Join(draftDir, filename)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write runbook draft: %v", err)
+	}
+	if err := g.commitFile(ctx, filePath, "runbook draft", description); err != nil {
+		return filePath, err
+	}
+	return filePath, nil
+}
+
 // commitFile commits a single file to the repository
-func (g *GitManager) commitFile(filePath, action, description string) error {
+func (g *GitManager) commitFile(ctx context.Context, filePath, action, description string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	// Add file to Git
 	relPath, err := filepath.Rel(g.config.RepoPath, filePath)
 	if err != nil {
 		return fmt.Errorf("failed to get relative path: %v", err)
 	}
 
-	if err := g.runGitCommand("add", relPath); err != nil {
+	if err := g.runGitCommand(ctx, "add", relPath); err != nil {
 		return fmt.Errorf("failed to add file to Git: %v", err)
 	}
 
 	// Create commit message
 	commitMsg := fmt.Sprintf("Add %s: %s", action, description)
-	if err := g.runGitCommand("commit", "-m", commitMsg); err != nil {
+	if err := g.runGitCommand(ctx, "commit", "-m", commitMsg); err != nil {
 		return fmt.Errorf("failed to commit file: %v", err)
 	}
 
@@ -174,7 +266,7 @@ func (g *GitManager) commitFile(filePath, action, description string) error {
 
 	// Auto-push if enabled
 	if g.config.AutoPush && g.config.RemoteURL != "" {
-		if err := g.pushToRemote(); err != nil {
+		if err := g.pushToRemote(ctx); err != nil {
 			logrus.Warnf("Failed to auto-push: %v", err)
 		}
 	}
@@ -183,18 +275,36 @@ func (g *GitManager) commitFile(filePath, action, description string) error {
 }
 
 // pushToRemote pushes changes to the remote repository
-func (g *GitManager) pushToRemote() error {
-	if err := g.runGitCommand("push", "origin", g.config.Branch); err != nil {
-		return fmt.Errorf("failed to push to remote: %v", err)
+func (g *GitManager) pushToRemote(ctx context.Context) error {
+	if err := g.runGitCommand(ctx, "push", "origin", g.config.Branch); err != nil {
+		return &GitRemoteError{Op: "push to", Err: err}
 	}
 
 	logrus.Infof("Pushed changes to remote repository")
 	return nil
 }
 
-// runGitCommand executes a Git command in the repository directory
-func (g *GitManager) runGitCommand(args ...string) error {
-	cmd := exec.Command("git", args...)
+// CheckRemote lists the remote branch to verify the remote is reachable and
+// returns how long it took
+func (g *GitManager) CheckRemote(ctx context.Context) (time.Duration, error) {
+	if !g.IsEnabled() {
+		return 0, ErrGitDisabled
+	}
+	if g.config.RemoteURL == "" {
+		return 0, ErrNoGitRemote
+	}
+
+	started := time.Now()
+	if err := g.runGitCommand(ctx, "ls-remote", "--heads", "origin", g.config.Branch); err != nil {
+		return 0, &GitRemoteError{Op: "reach", Err: err}
+	}
+	return time.Since(started), nil
+}
+
+// runGitCommand executes a Git command in the repository directory. The
+// process is killed when ctx is done.
+func (g *GitManager) runGitCommand(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = g.config.RepoPath
 
 	output, err := cmd.CombinedOutput()
@@ -207,12 +317,12 @@ func (g *GitManager) runGitCommand(args ...string) error {
 }
 
 // GetStatus returns the Git repository status
-func (g *GitManager) GetStatus() (string, error) {
+func (g *GitManager) GetStatus(ctx context.Context) (string, error) {
 	if !g.IsEnabled() {
 		return "Git integration is disabled", nil
 	}
 
-	cmd := exec.Command("git", "status", "--porcelain")
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
 	cmd.Dir = g.config.RepoPath
 
 	output, err := cmd.CombinedOutput()
@@ -251,18 +361,20 @@ func (g *GitManager) ListFiles() ([]string, error) {
 }
 
 // CommitAllChanges commits all pending changes
-func (g *GitManager) CommitAllChanges(message string) error {
+func (g *GitManager) CommitAllChanges(ctx context.Context, message string) error {
 	if !g.IsEnabled() {
-		return fmt.Errorf("Git integration is disabled")
+		return ErrGitDisabled
 	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
 	// Add all changes
-	if err := g.runGitCommand("add", "."); err != nil {
+	if err := g.runGitCommand(ctx, "add", "."); err != nil {
 		return fmt.Errorf("failed to add changes: %v", err)
 	}
 
 	// Commit changes
-	if err := g.runGitCommand("commit", "-m", message); err != nil {
+	if err := g.runGitCommand(ctx, "commit", "-m", message); err != nil {
 		return fmt.Errorf("failed to commit changes: %v", err)
 	}
 
@@ -271,16 +383,18 @@ func (g *GitManager) CommitAllChanges(message string) error {
 }
 
 // PushChanges pushes all changes to remote repository
-func (g *GitManager) PushChanges() error {
+func (g *GitManager) PushChanges(ctx context.Context) error {
 	if !g.IsEnabled() {
-		return fmt.Errorf("Git integration is disabled")
+		return ErrGitDisabled
 	}
 
 	if g.config.RemoteURL == "" {
-		return fmt.Errorf("no remote URL configured")
+		return ErrNoGitRemote
 	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	return g.pushToRemote()
+	return g.pushToRemote(ctx)
 }
 
 // CreateArgocdDirectoryStructure creates the ArgoCD-compatible directory structure
@@ -324,28 +438,32 @@ func (g *GitManager) CreateArgocdDirectoryStructure() error {
 }
 
 // SaveArgocdApplication saves an ArgoCD Application YAML file
-func (g *GitManager) SaveArgocdApplication(name, yamlContent string) error {
+func (g *GitManager) SaveArgocdApplication(ctx context.Context, name, yamlContent string) error {
 	if !g.config.Enabled {
 		return nil
 	}
 
 	filename := fmt.Sprintf("%s-application", name)
-	_, err := g.SaveYAMLFile(filename, yamlContent, "argocd", "ArgoCD Application")
+	_, err := g.SaveYAMLFile(ctx, filename, yamlContent, "argocd", "ArgoCD Application")
 	return err
 }
 
+// manifestDir is where an application's manifests live: manifests/base/<app>
+// or manifests/overlays/<environment>/<app>
+func (g *GitManager) manifestDir(appName, environment string) string {
+	if environment == "" || environment == "base" {
+		return filepath.Join(g.config.RepoPath, "manifests", "base", appName)
+	}
+	return filepath.Join(g.config.RepoPath, "manifests", "overlays", environment, appName)
+}
+
 // SaveArgocdManifest saves a Kubernetes manifest to the appropriate directory
 func (g *GitManager) SaveArgocdManifest(appName, environment, manifestType, yamlContent string) error {
 	if !g.config.Enabled {
 		return nil
 	}
 
-	var dir string
-	if environment == "" || environment == "base" {
-		dir = filepath.Join(g.config.RepoPath, "manifests", "base", appName)
-	} else {
-		dir = filepath.Join(g.config.RepoPath, "manifests", "overlays", environment, appName)
-	}
+	dir := g.manifestDir(appName, environment)
 
 	// Create app-specific directory if it doesn't exist
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -370,12 +488,7 @@ func (g *GitManager) SaveArgocdManifestBundle(appName, environment string, manif
 		return nil
 	}
 
-	var dir string
-	if environment == "" || environment == "base" {
-		dir = filepath.Join(g.config.RepoPath, "manifests", "base", appName)
-	} else {
-		dir = filepath.Join(g.config.RepoPath, "manifests", "overlays", environment, appName)
-	}
+	dir := g.manifestDir(appName, environment)
 
 	// Create app-specific directory if it doesn't exist
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -394,6 +507,139 @@ func (g *GitManager) SaveArgocdManifestBundle(appName, environment string, manif
 	return nil
 }
 
+// SaveOnboardedApplication replaces an application's manifest directory with
+// the given manifests, saves its ArgoCD Application and commits both in one
+// commit. It returns the manifest directory relative to the repository.
+func (g *GitManager) SaveOnboardedApplication(ctx context.Context, appName, environment string, manifests map[string]string, application, description string) (string, error) {
+	if !g.IsEnabled() {
+		return "", ErrGitDisabled
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dir := g.manifestDir(appName, environment)
+	// Manifests of resources no longer in the namespace are dropped
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to clear %s: %v", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %v", dir, err)
+	}
+	for filename, content := range manifests {
+		if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644); err != nil {
+			return "", fmt.Errorf("failed to save %s: %v", filename, err)
+		}
+	}
+
+	appsDir := filepath.Join(g.config.RepoPath, "applications")
+	if err := os.MkdirAll(appsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %v", appsDir, err)
+	}
+	appFile := filepath.Join(appsDir, appName+"-application.yaml")
+	if err := os.WriteFile(appFile, []byte(application), 0644); err != nil {
+		return "", fmt.Errorf("failed to save the ArgoCD Application: %v", err)
+	}
+
+	relDir, _ := filepath.Rel(g.config.RepoPath, dir)
+	relApp, _ := filepath.Rel(g.config.RepoPath, appFile)
+	if err := g.runGitCommand(ctx, "add", "-A", "--", relDir, relApp); err != nil {
+		return relDir, fmt.Errorf("failed to add files to Git: %v", err)
+	}
+	if err := g.runGitCommand(ctx, "commit", "-m", fmt.Sprintf("Add onboard: %s", description)); err != nil {
+		return relDir, fmt.Errorf("failed to commit files: %v", err)
+	}
+	logrus.Infof("Committed onboarded application %s in %s", appName, relDir)
+
+	if g.config.AutoPush && g.config.RemoteURL != "" {
+		if err := g.pushToRemote(ctx); err != nil {
+			logrus.Warnf("Failed to auto-push: %v", err)
+		}
+	}
+	return relDir, nil
+}
+
+// SaveTenantBundle replaces tenants/<namespace> with the manifests of a
+// provisioned namespace and commits them in one commit. It returns the
+// directory relative to the repository.
+func (g *GitManager) SaveTenantBundle(ctx context.Context, namespace string, manifests map[string]string, description string) (string, error) {
+	if !g.IsEnabled() {
+		return "", ErrGitDisabled
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dir := filepath.Join(g.config.RepoPath, "tenants", namespace)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to clear %s: %v", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %v", dir, err)
+	}
+	for filename, content := range manifests {
+		if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644); err != nil {
+			return "", fmt.Errorf("failed to save %s: %v", filename, err)
+		}
+	}
+
+	relDir, _ := filepath.Rel(g.config.RepoPath, dir)
+	if err := g.runGitCommand(ctx, "add", "-A", "--", relDir); err != nil {
+		return relDir, fmt.Errorf("failed to add files to Git: %v", err)
+	}
+	if err := g.runGitCommand(ctx, "commit", "-m", fmt.Sprintf("Add tenant: %s", description)); err != nil {
+		return relDir, fmt.Errorf("failed to commit files: %v", err)
+	}
+	logrus.Infof("Committed tenant bundle for %s in %s", namespace, relDir)
+
+	if g.config.AutoPush && g.config.RemoteURL != "" {
+		if err := g.pushToRemote(ctx); err != nil {
+			logrus.Warnf("Failed to auto-push: %v", err)
+		}
+	}
+	return relDir, nil
+}
+
+// ReadCatalog returns the files of each application stack under catalog/,
+// keyed by stack directory and file name. A repository without a catalog
+// has no stacks.
+func (g *GitManager) ReadCatalog() (map[string]map[string]string, error) {
+	if !g.IsEnabled() {
+		return nil, ErrGitDisabled
+	}
+
+	catalogDir := filepath.Join(g.config.RepoPath, "catalog")
+	entries, err := os.ReadDir(catalogDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog directory: %v", err)
+	}
+
+	stacks := make(map[string]map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(catalogDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog stack %s: %v", entry.Name(), err)
+		}
+		stack := make(map[string]string)
+		for _, file := range files {
+			if file.IsDir() || !(strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml")) {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(catalogDir, entry.Name(), file.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %v", file.Name(), err)
+			}
+			stack[file.Name()] = string(content)
+		}
+		stacks[entry.Name()] = stack
+	}
+	return stacks, nil
+}
+
 // SaveArgocdEnvironmentConfig saves environment-specific configuration
 func (g *GitManager) SaveArgocdEnvironmentConfig(environment, configType, yamlContent string) error {
 	if !g.config.Enabled {
@@ -448,7 +694,7 @@ func (g *GitManager) SaveArgocdAppOfApps(environment, yamlContent string) error
 // ListArgocdApplications lists all ArgoCD applications in the repository
 func (g *GitManager) ListArgocdApplications() ([]string, error) {
 	if !g.config.Enabled {
-		return nil, fmt.Errorf("git is not enabled")
+		return nil, ErrGitDisabled
 	}
 
 	appsDir := filepath.Join(g.config.RepoPath, "applications")
@@ -470,15 +716,10 @@ func (g *GitManager) ListArgocdApplications() ([]string, error) {
 // GetArgocdApplicationManifests gets all manifests for a specific application
 func (g *GitManager) GetArgocdApplicationManifests(appName, environment string) (map[string]string, error) {
 	if !g.config.Enabled {
-		return nil, fmt.Errorf("git is not enabled")
+		return nil, ErrGitDisabled
 	}
 
-	var dir string
-	if environment == "" || environment == "base" {
-		dir = filepath.Join(g.config.RepoPath, "manifests", "base", appName)
-	} else {
-		dir = filepath.Join(g.config.RepoPath, "manifests", "overlays", environment, appName)
-	}
+	dir := g.manifestDir(appName, environment)
 
 	files, err := os.ReadDir(dir)
 	if err != nil {
@@ -502,7 +743,7 @@ func (g *GitManager) GetArgocdApplicationManifests(appName, environment string)
 }
 
 // CommitArgocdChanges commits changes with ArgoCD-specific message format
-func (g *GitManager) CommitArgocdChanges(appName, environment, action, message string) error {
+func (g *GitManager) CommitArgocdChanges(ctx context.Context, appName, environment, action, message string) error {
 	if !g.config.Enabled {
 		return nil
 	}
@@ -512,7 +753,7 @@ func (g *GitManager) CommitArgocdChanges(appName, environment, action, message s
 		commitMessage = fmt.Sprintf("%s: %s", commitMessage, message)
 	}
 
-	return g.CommitAllChanges(commitMessage)
+	return g.CommitAllChanges(ctx, commitMessage)
 }
 
 // SyncArgocdApplication triggers a sync for ArgoCD application (placeholder for future webhook integration)
@@ -0,0 +1,371 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Supported Git hosting providers
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderGitea     = "gitea"
+	ProviderBitbucket = "bitbucket"
+)
+
+// Normalized commit check states
+const (
+	CheckSuccess = "success"
+	CheckPending = "pending"
+	CheckFailure = "failure"
+	CheckError   = "error"
+)
+
+// GitProvider is the API of the service hosting the remote repository, used
+// for what plain Git cannot do: pull requests, status checks and browsing
+// the remote without cloning it
+type GitProvider interface {
+	Name() string
+	CreatePullRequest(ctx context.Context, options PullRequestOptions) (*PullRequest, error)
+	CommitChecks(ctx context.Context, ref string) ([]CommitCheck, error)
+	ListFiles(ctx context.Context, ref, path string) ([]RepoEntry, error)
+
+	// SetCommitStatus reports a check on a commit; check.Name is the status context
+	SetCommitStatus(ctx context.Context, sha string, check CommitCheck) error
+	// CommentOnPullRequest adds a comment to a pull or merge request
+	CommentOnPullRequest(ctx context.Context, number int, body string) error
+}
+
+// PullRequestOptions describe a pull (or merge) request to open
+type PullRequestOptions struct {
+	Title string
+	Body  string
+	Head  string // branch with the changes
+	Base  string // branch to merge into
+}
+
+// PullRequest is an opened pull or merge request
+type PullRequest struct {
+	Number int
+	URL    string
+}
+
+// CommitCheck is a CI status or check reported on a commit
+type CommitCheck struct {
+	Name        string
+	State       string // success, pending, failure or error
+	Description string
+	URL         string
+}
+
+// RepoEntry is a file or directory in the remote repository
+type RepoEntry struct {
+	Path string
+	Dir  bool
+}
+
+// remoteRepository splits a remote URL such as https://host/owner/repo.git,
+// git@host:owner/repo.git or ssh://git@host/owner/repo into host and path
+func remoteRepository(remoteURL string) (string, string, error) {
+	remote := strings.TrimSpace(remoteURL)
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid remote URL %q: %v", remoteURL, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, "@"); at >= 0 && strings.Contains(remote[at:], ":") {
+		// scp-like syntax: user@host:owner/repo.git
+		hostPath := remote[at+1:]
+		colon := strings.Index(hostPath, ":")
+		host, path = hostPath[:colon], hostPath[colon+1:]
+	} else {
+		return "", "", fmt.Errorf("unsupported remote URL %q", remoteURL)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("remote URL %q does not name an owner and repository", remoteURL)
+	}
+	return host, path, nil
+}
+
+// detectProvider guesses the provider from the remote host
+func detectProvider(host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case strings.Contains(host, "github"):
+		return ProviderGitHub
+	case strings.Contains(host, "gitlab"):
+		return ProviderGitLab
+	case strings.Contains(host, "bitbucket"):
+		return ProviderBitbucket
+	case strings.Contains(host, "gitea"), strings.Contains(host, "codeberg"):
+		return ProviderGitea
+	}
+	return ""
+}
+
+// NewGitProvider selects the provider for a Git configuration. Provider and
+// ProviderURL default to what the remote URL implies, e.g. api.github.com
+// for github.com or https://<host>/api/v4 for a GitLab host.
+func NewGitProvider(config *GitConfig) (GitProvider, error) {
+	if config == nil || config.RemoteURL == "" {
+		return nil, ErrNoGitRemote
+	}
+	host, repository, err := remoteRepository(config.RemoteURL)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.ToLower(config.Provider)
+	if name == "" {
+		if name = detectProvider(host); name == "" {
+			return nil, fmt.Errorf("cannot tell the Git provider of %s; set the provider to %s, %s, %s or %s",
+				host, ProviderGitHub, ProviderGitLab, ProviderGitea, ProviderBitbucket)
+		}
+	}
+
+	baseURL := strings.TrimRight(config.ProviderURL, "/")
+	client := &providerClient{token: config.ProviderToken, http: &http.Client{Timeout: 30 * time.Second}}
+	switch name {
+	case ProviderGitHub:
+		if baseURL == "" {
+			baseURL = "https://api.github.com"
+			if host != "github.com" {
+				baseURL = fmt.Sprintf("https://%s/api/v3", host)
+			}
+		}
+		client.baseURL, client.authScheme = baseURL, "Bearer"
+		return &githubProvider{client: client, repository: repository}, nil
+	case ProviderGitLab:
+		if baseURL == "" {
+			baseURL = fmt.Sprintf("https://%s/api/v4", host)
+		}
+		client.baseURL, client.authHeader = baseURL, "PRIVATE-TOKEN"
+		return &gitlabProvider{client: client, project: url.PathEscape(repository)}, nil
+	case ProviderGitea:
+		if baseURL == "" {
+			baseURL = fmt.Sprintf("https://%s/api/v1", host)
+		}
+		client.baseURL, client.authScheme = baseURL, "token"
+		return &giteaProvider{client: client, repository: repository}, nil
+	case ProviderBitbucket:
+		if baseURL == "" {
+			baseURL = "https://api.bitbucket.org/2.0"
+		}
+		client.baseURL, client.authScheme = baseURL, "Bearer"
+		return &bitbucketProvider{client: client, repository: repository}, nil
+	}
+	return nil, fmt.Errorf("unknown Git provider %q, use %s, %s, %s or %s",
+		name, ProviderGitHub, ProviderGitLab, ProviderGitea, ProviderBitbucket)
+}
+
+// providerClient sends authenticated JSON requests to a provider API
+type providerClient struct {
+	baseURL    string
+	token      string
+	authScheme string // Authorization scheme, e.g. Bearer
+	authHeader string // header carrying the bare token instead, e.g. PRIVATE-TOKEN
+	http       *http.Client
+}
+
+// do sends a request and decodes the JSON response into out. Connection
+// failures and server errors are GitRemoteErrors so they count against the
+// Git circuit breaker; other HTTP errors are answers from a healthy remote.
+func (c *providerClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.token == "":
+	case c.authHeader != "":
+		req.Header.Set(c.authHeader, c.token)
+	case strings.Contains(c.token, ":"):
+		// user:app-password, as Bitbucket app passwords require
+		user, password, _ := strings.Cut(c.token, ":")
+		req.SetBasicAuth(user, password)
+	default:
+		req.Header.Set("Authorization", c.authScheme+" "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return &GitRemoteError{Op: "query", Err: err}
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+
+	if resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(data))
+		if len(message) > 300 {
+			message = message[:300] + "..."
+		}
+		err := fmt.Errorf("%s %s returned %s: %s", method, path, resp.Status, message)
+		if resp.StatusCode >= 500 {
+			return &GitRemoteError{Op: "query", Err: err}
+		}
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unexpected response from %s %s: %v", method, path, err)
+	}
+	return nil
+}
+
+// Provider returns the API client for the remote repository's host
+func (g *GitManager) Provider() (GitProvider, error) {
+	if !g.IsEnabled() {
+		return nil, ErrGitDisabled
+	}
+	return NewGitProvider(g.config)
+}
+
+func (s *Server) gitCreatePullRequestHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	provider, err := s.gitManager.Provider()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	options := PullRequestOptions{
+		Title: mcp.ParseString(request, "title", ""),
+		Body:  mcp.ParseString(request, "body", ""),
+		Head:  mcp.ParseString(request, "head", ""),
+		Base:  mcp.ParseString(request, "base", s.gitManager.config.Branch),
+	}
+	if options.Title == "" || options.Head == "" {
+		return mcp.NewToolResultText("❌ title and head are required"), nil
+	}
+	if options.Head == options.Base {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ head and base are both %s; push the changes to a separate branch first", options.Head)), nil
+	}
+
+	pr, err := provider.CreatePullRequest(ctx, options)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to open a pull request on %s", provider.Name()), err), nil
+	}
+
+	result := "🔀 Pull Request Opened\n"
+	result += "======================\n\n"
+	result += fmt.Sprintf("Provider: %s\n", provider.Name())
+	result += fmt.Sprintf("Title: %s\n", options.Title)
+	result += fmt.Sprintf("Branches: %s → %s\n", options.Head, options.Base)
+	result += fmt.Sprintf("Number: #%d\n", pr.Number)
+	result += fmt.Sprintf("URL: %s\n", valueOrNone(pr.URL))
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) gitCommitStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	provider, err := s.gitManager.Provider()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	ref := mcp.ParseString(request, "ref", s.gitManager.config.Branch)
+
+	checks, err := provider.CommitChecks(ctx, ref)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get the status checks of %s", ref), err), nil
+	}
+
+	counts := make(map[string]int)
+	for _, check := range checks {
+		counts[check.State]++
+	}
+	overall := CheckSuccess
+	switch {
+	case len(checks) == 0:
+		overall = "no checks reported"
+	case counts[CheckFailure]+counts[CheckError] > 0:
+		overall = CheckFailure
+	case counts[CheckPending] > 0:
+		overall = CheckPending
+	}
+
+	result := "🚦 Commit Status Checks\n"
+	result += "=======================\n\n"
+	result += fmt.Sprintf("Provider: %s\n", provider.Name())
+	result += fmt.Sprintf("Ref: %s\n", ref)
+	result += fmt.Sprintf("Overall: %s\n\n", overall)
+	icons := map[string]string{CheckSuccess: "✅", CheckPending: "⏳", CheckFailure: "❌", CheckError: "⚠️ "}
+	for _, check := range checks {
+		result += fmt.Sprintf("%s %s: %s", icons[check.State], check.Name, check.State)
+		if check.Description != "" {
+			result += fmt.Sprintf(" - %s", check.Description)
+		}
+		result += "\n"
+		if check.URL != "" {
+			result += fmt.Sprintf("   %s\n", check.URL)
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) gitBrowseHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	provider, err := s.gitManager.Provider()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	ref := mcp.ParseString(request, "ref", s.gitManager.config.Branch)
+	path := strings.Trim(mcp.ParseString(request, "path", ""), "/")
+
+	entries, err := provider.ListFiles(ctx, ref, path)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list %s at %s", valueOrNone(path), ref), err), nil
+	}
+
+	result := "📂 Remote Repository Files\n"
+	result += "==========================\n\n"
+	result += fmt.Sprintf("Provider: %s\n", provider.Name())
+	result += fmt.Sprintf("Ref: %s, Path: /%s\n\n", ref, path)
+	if len(entries) == 0 {
+		result += "📭 Empty directory\n"
+	}
+	for _, entry := range entries {
+		if entry.Dir {
+			result += fmt.Sprintf("📁 %s/\n", entry.Path)
+		} else {
+			result += fmt.Sprintf("📄 %s\n", entry.Path)
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// GitCreatePullRequestHandler is a public wrapper for gitCreatePullRequestHandler
+func (s *Server) GitCreatePullRequestHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.gitCreatePullRequestHandler(ctx, request)
+}
+
+// GitCommitStatusHandler is a public wrapper for gitCommitStatusHandler
+func (s *Server) GitCommitStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.gitCommitStatusHandler(ctx, request)
+}
+
+// GitBrowseHandler is a public wrapper for gitBrowseHandler
+func (s *Server) GitBrowseHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.gitBrowseHandler(ctx, request)
+}
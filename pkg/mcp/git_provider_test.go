@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRemoteRepository(t *testing.T) {
+	tests := []struct {
+		remote   string
+		host     string
+		path     string
+		provider string
+		wantErr  bool
+	}{
+		{"https://github.com/acme/gitops.git", "github.com", "acme/gitops", ProviderGitHub, false},
+		{"git@gitlab.com:group/sub/gitops.git", "gitlab.com", "group/sub/gitops", ProviderGitLab, false},
+		{"ssh://git@bitbucket.org/team/gitops", "bitbucket.org", "team/gitops", ProviderBitbucket, false},
+		{"https://codeberg.org/acme/gitops/", "codeberg.org", "acme/gitops", ProviderGitea, false},
+		{"https://git.example.com/acme/gitops.git", "git.example.com", "acme/gitops", "", false},
+		{"https://github.com/acme", "", "", "", true},
+		{"/srv/git/gitops.git", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		host, path, err := remoteRepository(tt.remote)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("remoteRepository(%q) error = %v, expected error %v", tt.remote, err, tt.wantErr)
+			continue
+		}
+		if host != tt.host || path != tt.path {
+			t.Errorf("remoteRepository(%q) = %q, %q, expected %q, %q", tt.remote, host, path, tt.host, tt.path)
+		}
+		if !tt.wantErr {
+			if got := detectProvider(host); got != tt.provider {
+				t.Errorf("detectProvider(%q) = %q, expected %q", host, got, tt.provider)
+			}
+		}
+	}
+}
+
+func TestNewGitProviderSelection(t *testing.T) {
+	tests := []struct {
+		config  GitConfig
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{GitConfig{RemoteURL: "https://github.com/acme/gitops.git"}, ProviderGitHub, "https://api.github.com", false},
+		{GitConfig{RemoteURL: "https://github.acme.com/ops/gitops.git"}, ProviderGitHub, "https://github.acme.com/api/v3", false},
+		{GitConfig{RemoteURL: "git@gitlab.acme.com:ops/gitops.git"}, ProviderGitLab, "https://gitlab.acme.com/api/v4", false},
+		{GitConfig{RemoteURL: "https://git.acme.com/ops/gitops.git", Provider: "Gitea"}, ProviderGitea, "https://git.acme.com/api/v1", false},
+		{GitConfig{RemoteURL: "https://bitbucket.org/ops/gitops.git", ProviderURL: "http://proxy/2.0/"}, ProviderBitbucket, "http://proxy/2.0", false},
+		{GitConfig{RemoteURL: "https://git.acme.com/ops/gitops.git"}, "", "", true},
+		{GitConfig{RemoteURL: "https://git.acme.com/ops/gitops.git", Provider: "svn"}, "", "", true},
+		{GitConfig{}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		config := tt.config
+		provider, err := NewGitProvider(&config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewGitProvider(%+v) error = %v, expected error %v", tt.config, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if provider.Name() != tt.name {
+			t.Errorf("NewGitProvider(%+v) = %s, expected %s", tt.config, provider.Name(), tt.name)
+		}
+		var client *providerClient
+		switch p := provider.(type) {
+		case *githubProvider:
+			client = p.client
+		case *gitlabProvider:
+			client = p.client
+		case *giteaProvider:
+			client = p.client
+		case *bitbucketProvider:
+			client = p.client
+		}
+		if client.baseURL != tt.baseURL {
+			t.Errorf("NewGitProvider(%+v) base URL = %q, expected %q", tt.config, client.baseURL, tt.baseURL)
+		}
+	}
+}
+
+// newProviderTestServer serves canned JSON answers keyed by request path and
+// records the authentication and body of the last request
+type providerTestServer struct {
+	*httptest.Server
+	auth string
+	body map[string]interface{}
+}
+
+func newProviderTestServer(t *testing.T, authHeader string, responses map[string]string) *providerTestServer {
+	ts := &providerTestServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.auth = r.Header.Get(authHeader)
+		ts.body = nil
+		json.NewDecoder(r.Body).Decode(&ts.body)
+		response, ok := responses[r.Method+" "+r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestGitProviders(t *testing.T) {
+	tests := []struct {
+		provider   string
+		remote     string
+		token      string
+		authHeader string
+		auth       string
+		responses  map[string]string
+		prField    string // request body field carrying the head branch
+		checks     []string
+		entries    []RepoEntry
+	}{
+		{
+			provider: ProviderGitHub, remote: "https://github.com/acme/gitops.git", token: "ghp", authHeader: "Authorization", auth: "Bearer ghp",
+			responses: map[string]string{
+				"POST /repos/acme/gitops/pulls":                  `{"number":7,"html_url":"https://github.com/acme/gitops/pull/7"}`,
+				"GET /repos/acme/gitops/commits/main/status":     `{"statuses":[{"context":"ci/lint","state":"success"}]}`,
+				"GET /repos/acme/gitops/commits/main/check-runs": `{"check_runs":[{"name":"build","status":"completed","conclusion":"failure"},{"name":"e2e","status":"in_progress"}]}`,
+				"GET /repos/acme/gitops/contents/records":        `[{"path":"records/nodes","type":"dir"},{"path":"records/a.yaml","type":"file"}]`,
+			},
+			prField: "head",
+			checks:  []string{"ci/lint=success", "build=failure", "e2e=pending"},
+			entries: []RepoEntry{{Path: "records/nodes", Dir: true}, {Path: "records/a.yaml"}},
+		},
+		{
+			provider: ProviderGitLab, remote: "git@gitlab.com:ops/gitops.git", token: "glpat", authHeader: "PRIVATE-TOKEN", auth: "glpat",
+			responses: map[string]string{
+				"POST /projects/ops%2Fgitops/merge_requests":                    `{"iid":3,"web_url":"https://gitlab.com/ops/gitops/-/merge_requests/3"}`,
+				"GET /projects/ops%2Fgitops/repository/commits/main":            `{"id":"abc123"}`,
+				"GET /projects/ops%2Fgitops/repository/commits/abc123/statuses": `[{"name":"test","status":"failed"},{"name":"deploy","status":"running"}]`,
+				"GET /projects/ops%2Fgitops/repository/tree":                    `[{"path":"records/nodes","type":"tree"},{"path":"records/a.yaml","type":"blob"}]`,
+			},
+			prField: "source_branch",
+			checks:  []string{"test=failure", "deploy=pending"},
+			entries: []RepoEntry{{Path: "records/nodes", Dir: true}, {Path: "records/a.yaml"}},
+		},
+		{
+			provider: ProviderGitea, remote: "https://gitea.acme.com/ops/gitops.git", token: "tok", authHeader: "Authorization", auth: "token tok",
+			responses: map[string]string{
+				"POST /repos/ops/gitops/pulls":              `{"number":12,"html_url":"https://gitea.acme.com/ops/gitops/pulls/12"}`,
+				"GET /repos/ops/gitops/commits/main/status": `{"statuses":[{"context":"drone","status":"warning"}]}`,
+				"GET /repos/ops/gitops/contents/records":    `{"path":"records","type":"file"}`,
+			},
+			prField: "head",
+			checks:  []string{"drone=failure"},
+			entries: []RepoEntry{{Path: "records"}},
+		},
+		{
+			provider: ProviderBitbucket, remote: "git@bitbucket.org:team/gitops.git", token: "bot:app-pass", authHeader: "Authorization", auth: "Basic Ym90OmFwcC1wYXNz",
+			responses: map[string]string{
+				"POST /repositories/team/gitops/pullrequests":        `{"id":5,"links":{"html":{"href":"https://bitbucket.org/team/gitops/pull-requests/5"}}}`,
+				"GET /repositories/team/gitops/commit/main/statuses": `{"values":[{"key":"pipeline","state":"SUCCESSFUL"},{"key":"scan","name":"Scan","state":"STOPPED"}]}`,
+				"GET /repositories/team/gitops/src/main/records":     `{"values":[{"path":"records/nodes","type":"commit_directory"},{"path":"records/a.yaml","type":"commit_file"}]}`,
+			},
+			prField: "source",
+			checks:  []string{"pipeline=success", "Scan=failure"},
+			entries: []RepoEntry{{Path: "records/nodes", Dir: true}, {Path: "records/a.yaml"}},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		ts := newProviderTestServer(t, tt.authHeader, tt.responses)
+		provider, err := NewGitProvider(&GitConfig{RemoteURL: tt.remote, ProviderURL: ts.URL, ProviderToken: tt.token})
+		if err != nil {
+			t.Fatalf("NewGitProvider(%s): %v", tt.remote, err)
+		}
+		if provider.Name() != tt.provider {
+			t.Errorf("NewGitProvider(%s) = %s, expected %s", tt.remote, provider.Name(), tt.provider)
+		}
+
+		pr, err := provider.CreatePullRequest(ctx, PullRequestOptions{Title: "Drain node", Head: "drain", Base: "main"})
+		if err != nil {
+			t.Errorf("%s CreatePullRequest error = %v", tt.provider, err)
+		} else if pr.Number == 0 || pr.URL == "" {
+			t.Errorf("%s CreatePullRequest = %+v, expected number and URL", tt.provider, pr)
+		}
+		if ts.auth != tt.auth {
+			t.Errorf("%s auth header = %q, expected %q", tt.provider, ts.auth, tt.auth)
+		}
+		if _, ok := ts.body[tt.prField]; !ok {
+			t.Errorf("%s pull request body = %v, expected field %s", tt.provider, ts.body, tt.prField)
+		}
+
+		checks, err := provider.CommitChecks(ctx, "main")
+		if err != nil {
+			t.Errorf("%s CommitChecks error = %v", tt.provider, err)
+		}
+		var got []string
+		for _, check := range checks {
+			got = append(got, check.Name+"="+check.State)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.checks, ",") {
+			t.Errorf("%s CommitChecks = %v, expected %v", tt.provider, got, tt.checks)
+		}
+
+		entries, err := provider.ListFiles(ctx, "main", "records")
+		if err != nil {
+			t.Errorf("%s ListFiles error = %v", tt.provider, err)
+		}
+		if len(entries) != len(tt.entries) {
+			t.Errorf("%s ListFiles = %v, expected %v", tt.provider, entries, tt.entries)
+			continue
+		}
+		for i := range entries {
+			if entries[i] != tt.entries[i] {
+				t.Errorf("%s ListFiles[%d] = %+v, expected %+v", tt.provider, i, entries[i], tt.entries[i])
+			}
+		}
+	}
+}
+
+func TestProviderClientErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer ts.Close()
+	client := &providerClient{baseURL: ts.URL, http: ts.Client()}
+
+	var remoteErr *GitRemoteError
+	if err := client.do(context.Background(), "GET", "/down", nil, nil); err == nil || !errors.As(err, &remoteErr) {
+		t.Errorf("do(/down) error = %v, expected a GitRemoteError", err)
+	}
+	if err := client.do(context.Background(), "GET", "/missing", nil, nil); err == nil || errors.As(err, &remoteErr) {
+		t.Errorf("do(/missing) error = %v, expected a plain error", err)
+	}
+}
+
+func TestGitCommitStatusHandler(t *testing.T) {
+	ts := newProviderTestServer(t, "Authorization", map[string]string{
+		"GET /repos/acme/gitops/commits/release/status":     `{"statuses":[{"context":"ci/lint","state":"success","target_url":"https://ci/1"}]}`,
+		"GET /repos/acme/gitops/commits/release/check-runs": `{"check_runs":[{"name":"build","status":"queued"}]}`,
+	})
+	s := &Server{gitManager: NewGitManager(&GitConfig{
+		Enabled: true, RepoPath: t.TempDir(), Branch: "main",
+		RemoteURL: "https://github.com/acme/gitops.git", ProviderURL: ts.URL,
+	})}
+
+	args := map[string]interface{}{"ref": "release"}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := s.GitCommitStatusHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GitCommitStatusHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{"Ref: release", "Overall: pending", "✅ ci/lint: success", "https://ci/1", "⏳ build: pending"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("GitCommitStatusHandler output missing %q:\n%s", want, text)
+		}
+	}
+
+	s.gitManager = NewGitManager(&GitConfig{Enabled: false})
+	result, _ = s.GitCommitStatusHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "❌") {
+		t.Errorf("GitCommitStatusHandler with Git disabled = %q, expected an error", text)
+	}
+}
+
+func TestGitCreatePullRequestHandlerValidation(t *testing.T) {
+	s := &Server{gitManager: NewGitManager(&GitConfig{
+		Enabled: true, RepoPath: t.TempDir(), Branch: "main",
+		RemoteURL: "https://github.com/acme/gitops.git", ProviderURL: "http://127.0.0.1:1",
+	})}
+
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"head": "fix"}, "title and head are required"},
+		{map[string]interface{}{"title": "Fix", "head": "main"}, "head and base are both main"},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, _ := s.GitCreatePullRequestHandler(context.Background(), request)
+		if text := resultText(result); !strings.Contains(text, tt.want) {
+			t.Errorf("GitCreatePullRequestHandler(%v) = %q, expected %q", tt.args, text, tt.want)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// contentsPath builds the contents API path shared by GitHub and Gitea
+func contentsPath(repository, ref, path string) string {
+	return fmt.Sprintf("/repos/%s/contents/%s?ref=%s", repository, path, url.QueryEscape(ref))
+}
+
+// contentsEntries decodes a contents API answer, which is an array for a
+// directory and a single object for a file
+func contentsEntries(raw json.RawMessage, dirType string) ([]RepoEntry, error) {
+	type item struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	var items []item
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+	} else {
+		var single item
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, err
+		}
+		items = append(items, single)
+	}
+	entries := make([]RepoEntry, 0, len(items))
+	for _, it := range items {
+		entries = append(entries, RepoEntry{Path: it.Path, Dir: it.Type == dirType})
+	}
+	return entries, nil
+}
+
+// githubProvider implements GitProvider for GitHub and GitHub Enterprise
+type githubProvider struct {
+	client     *providerClient
+	repository string // owner/name
+}
+
+func (p *githubProvider) Name() string { return ProviderGitHub }
+
+func (p *githubProvider) CreatePullRequest(ctx context.Context, options PullRequestOptions) (*PullRequest, error) {
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := p.client.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls", p.repository), map[string]string{
+		"title": options.Title, "body": options.Body, "head": options.Head, "base": options.Base,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: response.Number, URL: response.HTMLURL}, nil
+}
+
+// CommitChecks merges commit statuses with check runs, since CI systems report through either
+func (p *githubProvider) CommitChecks(ctx context.Context, ref string) ([]CommitCheck, error) {
+	var statuses struct {
+		Statuses []struct {
+			Context     string `json:"context"`
+			State       string `json:"state"`
+			Description string `json:"description"`
+			TargetURL   string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := p.client.do(ctx, "GET", fmt.Sprintf("/repos/%s/commits/%s/status", p.repository, ref), nil, &statuses); err != nil {
+		return nil, err
+	}
+	var checks []CommitCheck
+	for _, status := range statuses.Statuses {
+		checks = append(checks, CommitCheck{Name: status.Context, State: status.State, Description: status.Description, URL: status.TargetURL})
+	}
+
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+			Output     struct {
+				Title string `json:"title"`
+			} `json:"output"`
+		} `json:"check_runs"`
+	}
+	if err := p.client.do(ctx, "GET", fmt.Sprintf("/repos/%s/commits/%s/check-runs", p.repository, ref), nil, &runs); err != nil {
+		return nil, err
+	}
+	for _, run := range runs.CheckRuns {
+		state := CheckPending
+		if run.Status == "completed" {
+			switch run.Conclusion {
+			case "success", "neutral", "skipped":
+				state = CheckSuccess
+			default:
+				state = CheckFailure
+			}
+		}
+		checks = append(checks, CommitCheck{Name: run.Name, State: state, Description: run.Output.Title, URL: run.HTMLURL})
+	}
+	return checks, nil
+}
+
+func (p *githubProvider) ListFiles(ctx context.Context, ref, path string) ([]RepoEntry, error) {
+	var raw json.RawMessage
+	if err := p.client.do(ctx, "GET", contentsPath(p.repository, ref, path), nil, &raw); err != nil {
+		return nil, err
+	}
+	return contentsEntries(raw, "dir")
+}
+
+// gitlabProvider implements GitProvider for GitLab.com and self-managed GitLab
+type gitlabProvider struct {
+	client  *providerClient
+	project string // URL-escaped group/name
+}
+
+func (p *gitlabProvider) Name() string { return ProviderGitLab }
+
+func (p *gitlabProvider) CreatePullRequest(ctx context.Context, options PullRequestOptions) (*PullRequest, error) {
+	var response struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	err := p.client.do(ctx, "POST", fmt.Sprintf("/projects/%s/merge_requests", p.project), map[string]string{
+		"title": options.Title, "description": options.Body, "source_branch": options.Head, "target_branch": options.Base,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: response.IID, URL: response.WebURL}, nil
+}
+
+func (p *gitlabProvider) CommitChecks(ctx context.Context, ref string) ([]CommitCheck, error) {
+	// Statuses are keyed by commit, so resolve branch and tag names first
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if err := p.client.do(ctx, "GET", fmt.Sprintf("/projects/%s/repository/commits/%s", p.project, url.PathEscape(ref)), nil, &commit); err != nil {
+		return nil, err
+	}
+	var statuses []struct {
+		Name        string `json:"name"`
+		Status      string `json:"status"`
+		Description string `json:"description"`
+		TargetURL   string `json:"target_url"`
+	}
+	if err := p.client.do(ctx, "GET", fmt.Sprintf("/projects/%s/repository/commits/%s/statuses", p.project, commit.ID), nil, &statuses); err != nil {
+		return nil, err
+	}
+	var checks []CommitCheck
+	for _, status := range statuses {
+		state := CheckPending
+		switch status.Status {
+		case "success", "skipped":
+			state = CheckSuccess
+		case "failed", "canceled":
+			state = CheckFailure
+		}
+		checks = append(checks, CommitCheck{Name: status.Name, State: state, Description: status.Description, URL: status.TargetURL})
+	}
+	return checks, nil
+}
+
+func (p *gitlabProvider) ListFiles(ctx context.Context, ref, path string) ([]RepoEntry, error) {
+	var items []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	endpoint := fmt.Sprintf("/projects/%s/repository/tree?ref=%s&path=%s&per_page=100", p.project, url.QueryEscape(ref), url.QueryEscape(path))
+	if err := p.client.do(ctx, "GET", endpoint, nil, &items); err != nil {
+		return nil, err
+	}
+	entries := make([]RepoEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, RepoEntry{Path: item.Path, Dir: item.Type == "tree"})
+	}
+	return entries, nil
+}
+
+// giteaProvider implements GitProvider for Gitea and Forgejo
+type giteaProvider struct {
+	client     *providerClient
+	repository string // owner/name
+}
+
+func (p *giteaProvider) Name() string { return ProviderGitea }
+
+func (p *giteaProvider) CreatePullRequest(ctx context.Context, options PullRequestOptions) (*PullRequest, error) {
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := p.client.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls", p.repository), map[string]string{
+		"title": options.Title, "body": options.Body, "head": options.Head, "base": options.Base,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: response.Number, URL: response.HTMLURL}, nil
+}
+
+func (p *giteaProvider) CommitChecks(ctx context.Context, ref string) ([]CommitCheck, error) {
+	var combined struct {
+		Statuses []struct {
+			Context     string `json:"context"`
+			Status      string `json:"status"`
+			Description string `json:"description"`
+			TargetURL   string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := p.client.do(ctx, "GET", fmt.Sprintf("/repos/%s/commits/%s/status", p.repository, url.PathEscape(ref)), nil, &combined); err != nil {
+		return nil, err
+	}
+	var checks []CommitCheck
+	for _, status := range combined.Statuses {
+		state := status.Status
+		if state == "warning" {
+			state = CheckFailure
+		}
+		checks = append(checks, CommitCheck{Name: status.Context, State: state, Description: status.Description, URL: status.TargetURL})
+	}
+	return checks, nil
+}
+
+func (p *giteaProvider) ListFiles(ctx context.Context, ref, path string) ([]RepoEntry, error) {
+	var raw json.RawMessage
+	if err := p.client.do(ctx, "GET", contentsPath(p.repository, ref, path), nil, &raw); err != nil {
+		return nil, err
+	}
+	return contentsEntries(raw, "dir")
+}
+
+// bitbucketProvider implements GitProvider for Bitbucket Cloud
+type bitbucketProvider struct {
+	client     *providerClient
+	repository string // workspace/slug
+}
+
+func (p *bitbucketProvider) Name() string { return ProviderBitbucket }
+
+func (p *bitbucketProvider) CreatePullRequest(ctx context.Context, options PullRequestOptions) (*PullRequest, error) {
+	branch := func(name string) map[string]interface{} {
+		return map[string]interface{}{"branch": map[string]string{"name": name}}
+	}
+	var response struct {
+		ID    int `json:"id"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	err := p.client.do(ctx, "POST", fmt.Sprintf("/repositories/%s/pullrequests", p.repository), map[string]interface{}{
+		"title": options.Title, "description": options.Body, "source": branch(options.Head), "destination": branch(options.Base),
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: response.ID, URL: response.Links.HTML.Href}, nil
+}
+
+func (p *bitbucketProvider) CommitChecks(ctx context.Context, ref string) ([]CommitCheck, error) {
+	var statuses struct {
+		Values []struct {
+			Key         string `json:"key"`
+			Name        string `json:"name"`
+			State       string `json:"state"`
+			Description string `json:"description"`
+			URL         string `json:"url"`
+		} `json:"values"`
+	}
+	if err := p.client.do(ctx, "GET", fmt.Sprintf("/repositories/%s/commit/%s/statuses?pagelen=100", p.repository, url.PathEscape(ref)), nil, &statuses); err != nil {
+		return nil, err
+	}
+	var checks []CommitCheck
+	for _, status := range statuses.Values {
+		state := CheckPending
+		switch status.State {
+		case "SUCCESSFUL":
+			state = CheckSuccess
+		case "FAILED", "STOPPED":
+			state = CheckFailure
+		}
+		name := status.Name
+		if name == "" {
+			name = status.Key
+		}
+		checks = append(checks, CommitCheck{Name: name, State: state, Description: status.Description, URL: status.URL})
+	}
+	return checks, nil
+}
+
+func (p *bitbucketProvider) ListFiles(ctx context.Context, ref, path string) ([]RepoEntry, error) {
+	var listing struct {
+		Values []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"values"`
+	}
+	endpoint := fmt.Sprintf("/repositories/%s/src/%s/%s?pagelen=100", p.repository, url.PathEscape(ref), path)
+	if err := p.client.do(ctx, "GET", endpoint, nil, &listing); err != nil {
+		return nil, err
+	}
+	entries := make([]RepoEntry, 0, len(listing.Values))
+	for _, value := range listing.Values {
+		entries = append(entries, RepoEntry{Path: value.Path, Dir: value.Type == "commit_directory"})
+	}
+	return entries, nil
+}
+
+func (p *githubProvider) SetCommitStatus(ctx context.Context, sha string, check CommitCheck) error {
+	return p.client.do(ctx, "POST", fmt.Sprintf("/repos/%s/statuses/%s", p.repository, sha), map[string]string{
+		"state": check.State, "context": check.Name, "description": truncateStatus(check.Description), "target_url": check.URL,
+	}, nil)
+}
+
+func (p *githubProvider) CommentOnPullRequest(ctx context.Context, number int, body string) error {
+	return p.client.do(ctx, "POST", fmt.Sprintf("/repos/%s/issues/%d/comments", p.repository, number), map[string]string{"body": body}, nil)
+}
+
+func (p *gitlabProvider) SetCommitStatus(ctx context.Context, sha string, check CommitCheck) error {
+	state := check.State
+	if state == CheckFailure || state == CheckError {
+		state = "failed"
+	}
+	status := map[string]string{"state": state, "name": check.Name, "description": truncateStatus(check.Description)}
+	if check.URL != "" {
+		status["target_url"] = check.URL
+	}
+	return p.client.do(ctx, "POST", fmt.Sprintf("/projects/%s/statuses/%s", p.project, sha), status, nil)
+}
+
+func (p *gitlabProvider) CommentOnPullRequest(ctx context.Context, number int, body string) error {
+	return p.client.do(ctx, "POST", fmt.Sprintf("/projects/%s/merge_requests/%d/notes", p.project, number), map[string]string{"body": body}, nil)
+}
+
+func (p *giteaProvider) SetCommitStatus(ctx context.Context, sha string, check CommitCheck) error {
+	return p.client.do(ctx, "POST", fmt.Sprintf("/repos/%s/statuses/%s", p.repository, sha), map[string]string{
+		"state": check.State, "context": check.Name, "description": truncateStatus(check.Description), "target_url": check.URL,
+	}, nil)
+}
+
+func (p *giteaProvider) CommentOnPullRequest(ctx context.Context, number int, body string) error {
+	return p.client.do(ctx, "POST", fmt.Sprintf("/repos/%s/issues/%d/comments", p.repository, number), map[string]string{"body": body}, nil)
+}
+
+func (p *bitbucketProvider) SetCommitStatus(ctx context.Context, sha string, check CommitCheck) error {
+	state := "INPROGRESS"
+	switch check.State {
+	case CheckSuccess:
+		state = "SUCCESSFUL"
+	case CheckFailure, CheckError:
+		state = "FAILED"
+	}
+	// Bitbucket requires a link for every build status
+	link := check.URL
+	if link == "" {
+		link = fmt.Sprintf("https://bitbucket.org/%s/commits/%s", p.repository, sha)
+	}
+	return p.client.do(ctx, "POST", fmt.Sprintf("/repositories/%s/commit/%s/statuses/build", p.repository, sha), map[string]string{
+		"key": check.Name, "name": check.Name, "state": state, "description": truncateStatus(check.Description), "url": link,
+	}, nil)
+}
+
+func (p *bitbucketProvider) CommentOnPullRequest(ctx context.Context, number int, body string) error {
+	return p.client.do(ctx, "POST", fmt.Sprintf("/repositories/%s/pullrequests/%d/comments", p.repository, number), map[string]interface{}{
+		"content": map[string]string{"raw": body},
+	}, nil)
+}
+
+// truncateStatus shortens a status description to the 140 characters
+// GitHub accepts, which the other providers fit within too
+func truncateStatus(description string) string {
+	runes := []rune(description)
+	if len(runes) <= 140 {
+		return description
+	}
+	return string(runes[:137]) + "..."
+}
@@ -0,0 +1,233 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+// Pull strategies for integrating remote changes
+const (
+	PullRebase      = "rebase"
+	PullMerge       = "merge"
+	PullFastForward = "ff-only"
+)
+
+const gitSyncJobName = "git-sync"
+
+// ErrGitDiverged is returned by a fast-forward-only pull when local and
+// remote history both have commits the other lacks
+var ErrGitDiverged = errors.New("local branch has diverged from the remote")
+
+// GitSyncStatus describes the outcome of a pull
+type GitSyncStatus struct {
+	Time     time.Time
+	Strategy string
+	Ahead    int  // local commits not yet on the remote
+	Behind   int  // remote commits that were missing locally
+	Diverged bool // both sides had new commits
+	Updated  bool // remote commits were integrated
+	Head     string
+	Err      string
+}
+
+func validPullStrategy(strategy string) bool {
+	return strategy == PullRebase || strategy == PullMerge || strategy == PullFastForward
+}
+
+// gitOutput runs a Git command in the repository directory and returns its
+// trimmed standard output
+func (g *GitManager) gitOutput(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.config.RepoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git command failed: %v, output: %s", err, string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git command failed: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Pull fetches the remote branch and integrates it with the given strategy,
+// or the configured one when empty. A failed rebase or merge is aborted so
+// the local branch is left as it was.
+func (g *GitManager) Pull(ctx context.Context, strategy string) (*GitSyncStatus, error) {
+	if !g.IsEnabled() {
+		return nil, ErrGitDisabled
+	}
+	if g.config.RemoteURL == "" {
+		return nil, ErrNoGitRemote
+	}
+	if strategy == "" {
+		strategy = g.config.PullStrategy
+	}
+	if !validPullStrategy(strategy) {
+		return nil, fmt.Errorf("unknown pull strategy %q, use %s, %s or %s", strategy, PullRebase, PullMerge, PullFastForward)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status := &GitSyncStatus{Time: time.Now(), Strategy: strategy}
+	err := g.pull(ctx, status)
+	if err != nil {
+		status.Err = err.Error()
+	}
+	g.lastSync = status
+	return status, err
+}
+
+func (g *GitManager) pull(ctx context.Context, status *GitSyncStatus) error {
+	if err := g.runGitCommand(ctx, "fetch", "origin", g.config.Branch); err != nil {
+		return &GitRemoteError{Op: "fetch from", Err: err}
+	}
+
+	remoteRef := "origin/" + g.config.Branch
+	counts, err := g.gitOutput(ctx, "rev-list", "--left-right", "--count", "HEAD..."+remoteRef)
+	if err != nil {
+		return fmt.Errorf("failed to compare with %s: %v", remoteRef, err)
+	}
+	fields := strings.Fields(counts)
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected rev-list output %q", counts)
+	}
+	status.Ahead, _ = strconv.Atoi(fields[0])
+	status.Behind, _ = strconv.Atoi(fields[1])
+	status.Diverged = status.Ahead > 0 && status.Behind > 0
+	if status.Diverged {
+		logrus.Warnf("Git repository has diverged from %s: %d local and %d remote commits", remoteRef, status.Ahead, status.Behind)
+	}
+
+	if status.Behind > 0 {
+		switch status.Strategy {
+		case PullFastForward:
+			if status.Diverged {
+				return fmt.Errorf("%w (%d local, %d remote commits); pull with strategy %s or %s",
+					ErrGitDiverged, status.Ahead, status.Behind, PullRebase, PullMerge)
+			}
+			if err := g.runGitCommand(ctx, "merge", "--ff-only", remoteRef); err != nil {
+				return fmt.Errorf("failed to fast-forward to %s: %v", remoteRef, err)
+			}
+		case PullMerge:
+			if err := g.runGitCommand(ctx, "merge", "--autostash", "--no-edit", remoteRef); err != nil {
+				g.runGitCommand(ctx, "merge", "--abort")
+				return fmt.Errorf("merge with %s failed, local branch left unchanged: %v", remoteRef, err)
+			}
+		default:
+			if err := g.runGitCommand(ctx, "rebase", "--autostash", remoteRef); err != nil {
+				g.runGitCommand(ctx, "rebase", "--abort")
+				return fmt.Errorf("rebase onto %s failed, local branch left unchanged: %v", remoteRef, err)
+			}
+		}
+		status.Updated = true
+		logrus.Infof("Pulled %d commits from %s (%s)", status.Behind, remoteRef, status.Strategy)
+	}
+
+	status.Head, _ = g.gitOutput(ctx, "rev-parse", "--short", "HEAD")
+	return nil
+}
+
+// LastSync returns the outcome of the most recent pull, or nil
+func (g *GitManager) LastSync() *GitSyncStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.lastSync == nil {
+		return nil
+	}
+	status := *g.lastSync
+	return &status
+}
+
+// initGitSyncSchedule pulls the remote branch periodically so the local
+// clone tracks changes pushed by people, and pushes local commits left
+// behind by a failed auto-push
+func (s *Server) initGitSyncSchedule(config *GitConfig) {
+	if config == nil || config.SyncInterval == "" || !s.gitManager.IsEnabled() {
+		return
+	}
+	interval, err := time.ParseDuration(config.SyncInterval)
+	if err != nil || interval <= 0 {
+		logrus.Warnf("Invalid Git sync interval %q, scheduled sync disabled", config.SyncInterval)
+		return
+	}
+	if config.RemoteURL == "" {
+		logrus.Warn("Git sync interval set without a remote URL, scheduled sync disabled")
+		return
+	}
+	if !validPullStrategy(config.PullStrategy) {
+		logrus.Warnf("Invalid Git pull strategy %q, scheduled sync disabled", config.PullStrategy)
+		return
+	}
+
+	s.scheduler.AddLeaderJob(gitSyncJobName, interval, func(ctx context.Context) error {
+		status, err := s.gitManager.Pull(ctx, "")
+		if err != nil {
+			return err
+		}
+		if status.Ahead > 0 && config.AutoPush {
+			return s.gitManager.PushChanges(ctx)
+		}
+		return nil
+	})
+	logrus.Infof("Scheduled Git sync every %s (%s)", interval, config.PullStrategy)
+}
+
+// formatSyncStatus renders a pull outcome for git_pull and git_status
+func formatSyncStatus(ctx context.Context, status *GitSyncStatus) string {
+	result := fmt.Sprintf("Time: %s\n", formatTime(ctx, status.Time))
+	result += fmt.Sprintf("Strategy: %s\n", status.Strategy)
+	switch {
+	case status.Err != "":
+		result += fmt.Sprintf("❌ %s\n", status.Err)
+	case status.Updated:
+		result += fmt.Sprintf("⬇️  Pulled %d remote commit(s)\n", status.Behind)
+	default:
+		result += "✅ Already up to date with the remote\n"
+	}
+	if status.Diverged {
+		result += fmt.Sprintf("⚠️  History had diverged: %d local and %d remote commits\n", status.Ahead, status.Behind)
+	}
+	if status.Ahead > 0 && status.Err == "" {
+		result += fmt.Sprintf("⬆️  %d local commit(s) not pushed yet\n", status.Ahead)
+	}
+	if status.Head != "" {
+		result += fmt.Sprintf("HEAD: %s\n", status.Head)
+	}
+	return result
+}
+
+func (s *Server) gitPullHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !s.gitManager.IsEnabled() {
+		return mcp.NewToolResultText("❌ Git integration is disabled"), nil
+	}
+
+	strategy := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "strategy", "")))
+	status, err := s.gitManager.Pull(ctx, strategy)
+	if status == nil {
+		return toolError(ctx, "Failed to pull from remote", err), nil
+	}
+	if err != nil && !errors.Is(err, ErrGitDiverged) {
+		return toolError(ctx, "Failed to pull from remote", err), nil
+	}
+
+	result := "🔄 Git Pull\n"
+	result += "===========\n\n"
+	result += formatSyncStatus(ctx, status)
+	return mcp.NewToolResultText(result), nil
+}
+
+// GitPullHandler is a public wrapper for gitPullHandler
+func (s *Server) GitPullHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.gitPullHandler(ctx, request)
+}
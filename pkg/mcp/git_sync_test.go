@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// commitTestFile writes a file in a clone and commits it
+func commitTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", "Add "+name)
+}
+
+// newSyncedRepos returns a GitManager on a clone of a bare remote, plus a
+// second clone standing in for a person pushing changes
+func newSyncedRepos(t *testing.T, strategy string) (*GitManager, string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	human := filepath.Join(root, "human")
+	runGit(t, root, "init", "--bare", "--initial-branch=main", remote)
+	runGit(t, root, "clone", remote, human)
+	runGit(t, human, "checkout", "-b", "main")
+	commitTestFile(t, human, "README.md", "records\n")
+	runGit(t, human, "push", "origin", "main")
+
+	local := filepath.Join(root, "local")
+	runGit(t, root, "clone", remote, local)
+	runGit(t, local, "config", "user.name", "bot")
+	runGit(t, local, "config", "user.email", "bot@example.com")
+	return NewGitManager(&GitConfig{Enabled: true, RepoPath: local, RemoteURL: remote, PullStrategy: strategy}), human
+}
+
+func TestGitPull(t *testing.T) {
+	tests := []struct {
+		strategy string
+		diverge  bool
+		updated  bool
+		diverged bool
+		err      error
+	}{
+		{PullRebase, false, true, false, nil},
+		{PullRebase, true, true, true, nil},
+		{PullMerge, true, true, true, nil},
+		{PullFastForward, false, true, false, nil},
+		{PullFastForward, true, false, true, ErrGitDiverged},
+	}
+
+	for _, tt := range tests {
+		g, human := newSyncedRepos(t, tt.strategy)
+		commitTestFile(t, human, "remote.yaml", "kind: ConfigMap\n")
+		runGit(t, human, "push", "origin", "main")
+		if tt.diverge {
+			commitTestFile(t, g.config.RepoPath, "local.yaml", "kind: Secret\n")
+		}
+
+		status, err := g.Pull(context.Background(), "")
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Pull(%s, diverged %v) error = %v, expected %v", tt.strategy, tt.diverge, err, tt.err)
+			continue
+		}
+		if status.Updated != tt.updated || status.Diverged != tt.diverged || status.Behind != 1 {
+			t.Errorf("Pull(%s, diverged %v) = %+v, expected updated %v, diverged %v, behind 1", tt.strategy, tt.diverge, status, tt.updated, tt.diverged)
+		}
+		if _, err := os.Stat(filepath.Join(g.config.RepoPath, "remote.yaml")); (err == nil) != tt.updated {
+			t.Errorf("Pull(%s, diverged %v) remote file present = %v, expected %v", tt.strategy, tt.diverge, err == nil, tt.updated)
+		}
+		if g.LastSync() == nil || g.LastSync().Strategy != tt.strategy {
+			t.Errorf("Pull(%s) did not record the last sync: %+v", tt.strategy, g.LastSync())
+		}
+	}
+}
+
+func TestGitPullHandler(t *testing.T) {
+	g, human := newSyncedRepos(t, PullRebase)
+	s := &Server{config: &Config{}, gitManager: g}
+
+	request := mcp.CallToolRequest{}
+	result, _ := s.gitPullHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "Already up to date") {
+		t.Errorf("git_pull without remote changes = %q", text)
+	}
+
+	commitTestFile(t, human, "remote.yaml", "kind: ConfigMap\n")
+	runGit(t, human, "push", "origin", "main")
+	commitTestFile(t, g.config.RepoPath, "local.yaml", "kind: Secret\n")
+	request.Params.Arguments = map[string]interface{}{"strategy": "ff-only"}
+	result, _ = s.gitPullHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "diverged") || !strings.Contains(text, "1 local and 1 remote commits") {
+		t.Errorf("git_pull ff-only on diverged history = %q", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"strategy": "squash"}
+	result, _ = s.gitPullHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "unknown pull strategy") {
+		t.Errorf("git_pull with an unknown strategy = %q", text)
+	}
+}
@@ -0,0 +1,485 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// validationContext names the commit status posted for validations
+const validationContext = "openshift-mcp/validate"
+
+// maxValidatedFiles bounds the manifests validated for one change
+const maxValidatedFiles = 50
+
+// Drift states of a manifest compared with the live cluster
+const (
+	DriftInSync   = "in sync"
+	DriftChanged  = "drifted"
+	DriftMissing  = "not in cluster"
+	DriftSkipped  = "not checked"
+	DriftRejected = "rejected"
+)
+
+// Finding severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// GitChange is a push or pull request to validate, as reported by a Git
+// provider webhook or requested through validate_git_changes
+type GitChange struct {
+	Commit      string   // commit to validate
+	Before      string   // commit to compare with when Paths is empty
+	Ref         string   // branch or pull request ref, for the report only
+	Paths       []string // changed files; removed files are left out
+	PullRequest int      // pull or merge request number, 0 for a push
+}
+
+// ManifestFinding is a problem found in a manifest
+type ManifestFinding struct {
+	Severity string
+	Message  string
+}
+
+// ObjectValidation is the outcome for one object of a manifest file
+type ObjectValidation struct {
+	Object   string // Kind namespace/name
+	Drift    string
+	Diff     []string
+	Findings []ManifestFinding
+}
+
+// FileValidation is the outcome for one changed file
+type FileValidation struct {
+	Path     string
+	Findings []ManifestFinding // file-level problems such as invalid YAML
+	Objects  []ObjectValidation
+}
+
+// ValidationReport is the outcome of validating a change
+type ValidationReport struct {
+	Time        time.Time
+	Commit      string
+	Ref         string
+	PullRequest int
+	Files       []FileValidation
+	Skipped     int // changed files beyond maxValidatedFiles
+	Errors      int
+	Warnings    int
+	Drifted     int
+}
+
+func (r *ValidationReport) add(finding ManifestFinding) {
+	if finding.Severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// State is the commit status for the report: failure when a manifest has errors
+func (r *ValidationReport) State() string {
+	if r.Errors > 0 {
+		return CheckFailure
+	}
+	return CheckSuccess
+}
+
+// Summary is a one-line description fit for a commit status
+func (r *ValidationReport) Summary() string {
+	if len(r.Files) == 0 {
+		return "No Kubernetes manifests changed"
+	}
+	return fmt.Sprintf("%d manifest(s): %d error(s), %d warning(s), %d drifted from the cluster",
+		len(r.Files), r.Errors, r.Warnings, r.Drifted)
+}
+
+// isManifestPath reports whether a changed file can hold Kubernetes manifests
+func isManifestPath(file string) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// Fetch updates the remote-tracking branches without touching the working tree
+func (g *GitManager) Fetch(ctx context.Context) error {
+	if !g.IsEnabled() {
+		return ErrGitDisabled
+	}
+	if g.config.RemoteURL == "" {
+		return ErrNoGitRemote
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.runGitCommand(ctx, "fetch", "origin"); err != nil {
+		return &GitRemoteError{Op: "fetch from", Err: err}
+	}
+	return nil
+}
+
+// ChangedFiles lists the files added or modified between two commits
+func (g *GitManager) ChangedFiles(ctx context.Context, base, commit string) ([]string, error) {
+	output, err := g.gitOutput(ctx, "diff", "--name-only", "--diff-filter=d", base+"..."+commit)
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// FileAtCommit returns the content of a file as of a commit
+func (g *GitManager) FileAtCommit(ctx context.Context, commit, file string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", commit+":"+file)
+	cmd.Dir = g.config.RepoPath
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("cannot read %s at %s: %s", file, commit, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// podSpecPath locates the pod spec inside workload kinds
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "Pod":
+		return []string{"spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "DeploymentConfig":
+		return []string{"spec", "template", "spec"}
+	}
+	return nil
+}
+
+// lintObject applies the manifest rules to an object
+func lintObject(obj *unstructured.Unstructured) []ManifestFinding {
+	var findings []ManifestFinding
+	warn := func(format string, args ...interface{}) {
+		findings = append(findings, ManifestFinding{SeverityWarning, fmt.Sprintf(format, args...)})
+	}
+
+	if obj.GetName() == "" && obj.GetGenerateName() == "" {
+		findings = append(findings, ManifestFinding{SeverityError, "metadata.name is not set"})
+	}
+	kind := obj.GetKind()
+	if kind == "Secret" {
+		if data, _, _ := unstructured.NestedMap(obj.Object, "data"); len(data) > 0 {
+			warn("Secret values are committed to Git; use a SealedSecret or ExternalSecret instead")
+		}
+	}
+
+	specPath := podSpecPath(kind)
+	if specPath == nil {
+		return findings
+	}
+	spec, found, _ := unstructured.NestedMap(obj.Object, specPath...)
+	if !found {
+		findings = append(findings, ManifestFinding{SeverityError, fmt.Sprintf("%s has no pod spec at %s", kind, strings.Join(specPath, "."))})
+		return findings
+	}
+	if hostNetwork, _, _ := unstructured.NestedBool(spec, "hostNetwork"); hostNetwork {
+		warn("hostNetwork is enabled")
+	}
+	containers, _, _ := unstructured.NestedSlice(spec, "containers")
+	if len(containers) == 0 {
+		findings = append(findings, ManifestFinding{SeverityError, "pod spec has no containers"})
+	}
+	longRunning := kind != "Pod" && kind != "Job" && kind != "CronJob"
+	for _, item := range containers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		image, _, _ := unstructured.NestedString(container, "image")
+		switch {
+		case image == "":
+			findings = append(findings, ManifestFinding{SeverityError, fmt.Sprintf("container %s has no image", name)})
+		case strings.Contains(image, "@sha256:"):
+		case strings.HasSuffix(image, ":latest") || !strings.Contains(path.Base(image), ":"):
+			warn("container %s uses a floating image tag (%s); pin a version or digest", name, image)
+		}
+		if _, found, _ := unstructured.NestedMap(container, "resources", "requests"); !found {
+			warn("container %s sets no resource requests", name)
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(container, "resources", "limits", "memory"); !found {
+			warn("container %s sets no memory limit", name)
+		}
+		if privileged, _, _ := unstructured.NestedBool(container, "securityContext", "privileged"); privileged {
+			warn("container %s runs privileged", name)
+		}
+		if _, found, _ := unstructured.NestedMap(container, "readinessProbe"); longRunning && !found {
+			warn("container %s has no readiness probe", name)
+		}
+	}
+	return findings
+}
+
+// objectDrift compares a manifest with the live object through a
+// server-side dry-run apply, so defaults do not count as drift
+func (s *Server) objectDrift(ctx context.Context, obj *unstructured.Unstructured) (string, []string, error) {
+	if s.dynamicClient == nil || s.restMapper == nil {
+		return DriftSkipped, nil, nil
+	}
+	gvk := obj.GroupVersionKind()
+	mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		// Kinds the cluster does not serve, such as action records, cannot drift
+		return DriftSkipped, nil, nil
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced && obj.GetNamespace() == "" {
+		obj.SetNamespace("default")
+	}
+	if !namespaced {
+		obj.SetNamespace("")
+	}
+	client := s.resourceInterface(mapping.Resource, namespaced, obj.GetNamespace())
+
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return DriftMissing, nil, nil
+	}
+	if err != nil {
+		return DriftSkipped, nil, err
+	}
+	applied, err := client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: fieldManager, Force: true})
+	if err != nil {
+		return DriftRejected, nil, err
+	}
+	if diff := diffLines(objectYAML(live), objectYAML(applied)); len(diff) > 0 {
+		return DriftChanged, diff, nil
+	}
+	return DriftInSync, nil, nil
+}
+
+// ValidateGitChange lints the manifests changed by a commit and compares
+// them with the live cluster
+func (s *Server) ValidateGitChange(ctx context.Context, change GitChange) (*ValidationReport, error) {
+	g := s.gitManager
+	if !g.IsEnabled() {
+		return nil, ErrGitDisabled
+	}
+	// Without a remote, validate the local history
+	tracked := "HEAD"
+	if g.config.RemoteURL != "" {
+		if err := g.Fetch(ctx); err != nil {
+			return nil, err
+		}
+		tracked = "origin/" + g.config.Branch
+	}
+	if change.Commit == "" {
+		change.Commit = tracked
+	}
+
+	paths := change.Paths
+	if len(paths) == 0 {
+		base := change.Before
+		switch {
+		case base != "" && strings.Trim(base, "0") != "":
+		case change.PullRequest > 0 || base != "":
+			// Pull requests and new branches (an all-zero before) compare with the tracked branch
+			base = tracked
+		default:
+			base = change.Commit + "~1"
+		}
+		changed, err := g.ChangedFiles(ctx, base, change.Commit)
+		if err != nil {
+			return nil, err
+		}
+		paths = changed
+	}
+
+	report := &ValidationReport{Time: time.Now(), Commit: change.Commit, Ref: change.Ref, PullRequest: change.PullRequest}
+	for _, file := range paths {
+		if !isManifestPath(file) {
+			continue
+		}
+		if len(report.Files) == maxValidatedFiles {
+			report.Skipped++
+			continue
+		}
+		report.Files = append(report.Files, s.validateFile(ctx, report, change.Commit, file))
+	}
+	return report, nil
+}
+
+func (s *Server) validateFile(ctx context.Context, report *ValidationReport, commit, file string) FileValidation {
+	result := FileValidation{Path: file}
+	fail := func(message string) FileValidation {
+		finding := ManifestFinding{SeverityError, message}
+		result.Findings = append(result.Findings, finding)
+		report.add(finding)
+		return result
+	}
+
+	content, err := s.gitManager.FileAtCommit(ctx, commit, file)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		return result
+	}
+	objects, err := decodeObjects(string(content))
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	for _, obj := range objects {
+		validation := ObjectValidation{Object: fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())}
+		if obj.GetNamespace() != "" {
+			validation.Object = fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		}
+		validation.Findings = lintObject(obj)
+		if obj.GetName() != "" {
+			validation.Drift, validation.Diff, err = s.objectDrift(ctx, obj)
+			if validation.Drift == DriftRejected {
+				validation.Findings = append(validation.Findings, ManifestFinding{SeverityError, "rejected by the API server: " + validationMessage(err)})
+			} else if err != nil {
+				logrus.WithError(err).Debugf("Drift check of %s skipped", validation.Object)
+			}
+			if validation.Drift == DriftChanged {
+				report.Drifted++
+			}
+		}
+		for _, finding := range validation.Findings {
+			report.add(finding)
+		}
+		result.Objects = append(result.Objects, validation)
+	}
+	return result
+}
+
+// formatValidationReport renders a report as Markdown for pull request
+// comments and the validate_git_changes tool
+func formatValidationReport(report *ValidationReport) string {
+	icon := "✅"
+	if report.State() != CheckSuccess {
+		icon = "❌"
+	}
+	result := "🛂 GitOps Validation Report\n"
+	result += "===========================\n\n"
+	commit := report.Commit
+	if len(commit) > 12 && !strings.Contains(commit, "/") {
+		commit = commit[:12]
+	}
+	result += fmt.Sprintf("Commit: %s\n", commit)
+	if report.Ref != "" {
+		result += fmt.Sprintf("Ref: %s\n", report.Ref)
+	}
+	result += fmt.Sprintf("%s %s\n", icon, report.Summary())
+	if report.Skipped > 0 {
+		result += fmt.Sprintf("⚠️  %d more changed manifest(s) were not validated\n", report.Skipped)
+	}
+
+	severityIcons := map[string]string{SeverityError: "❌", SeverityWarning: "⚠️ "}
+	driftIcons := map[string]string{DriftInSync: "✅", DriftChanged: "🔀", DriftMissing: "🆕", DriftSkipped: "➖", DriftRejected: "❌"}
+	for _, file := range report.Files {
+		result += fmt.Sprintf("\n📄 %s\n", file.Path)
+		for _, finding := range file.Findings {
+			result += fmt.Sprintf("  %s %s\n", severityIcons[finding.Severity], finding.Message)
+		}
+		for _, object := range file.Objects {
+			line := "  • " + object.Object
+			if object.Drift != "" {
+				line += fmt.Sprintf(" - %s %s", driftIcons[object.Drift], object.Drift)
+			}
+			result += line + "\n"
+			for _, finding := range object.Findings {
+				result += fmt.Sprintf("    %s %s\n", severityIcons[finding.Severity], finding.Message)
+			}
+			if len(object.Diff) > 0 {
+				diff := object.Diff
+				if len(diff) > 40 {
+					diff = append(diff[:40:40], fmt.Sprintf("... %d more lines", len(object.Diff)-40))
+				}
+				result += fmt.Sprintf("```diff\n%s\n```\n", strings.Join(diff, "\n"))
+			}
+		}
+	}
+	return strings.TrimRight(result, "\n")
+}
+
+// ValidateAndReport validates a change and posts the outcome back to the Git
+// provider as a commit status and, for pull requests, a comment
+func (s *Server) ValidateAndReport(ctx context.Context, change GitChange) (*ValidationReport, error) {
+	provider, err := s.gitManager.Provider()
+	if err != nil {
+		return nil, err
+	}
+	if change.Commit != "" {
+		pending := CommitCheck{Name: validationContext, State: CheckPending, Description: "Validating changed manifests"}
+		if err := provider.SetCommitStatus(ctx, change.Commit, pending); err != nil {
+			logrus.WithError(err).Warnf("Failed to set the pending status on %s", change.Commit)
+		}
+	}
+
+	report, err := s.ValidateGitChange(ctx, change)
+	if err != nil {
+		if change.Commit != "" {
+			failed := CommitCheck{Name: validationContext, State: CheckError, Description: "Validation failed: " + err.Error()}
+			provider.SetCommitStatus(ctx, change.Commit, failed)
+		}
+		return nil, err
+	}
+
+	status := CommitCheck{Name: validationContext, State: report.State(), Description: report.Summary()}
+	if err := provider.SetCommitStatus(ctx, report.Commit, status); err != nil {
+		return report, fmt.Errorf("failed to post the commit status: %w", err)
+	}
+	if change.PullRequest > 0 && len(report.Files) > 0 {
+		if err := provider.CommentOnPullRequest(ctx, change.PullRequest, formatValidationReport(report)); err != nil {
+			return report, fmt.Errorf("failed to comment on pull request #%d: %w", change.PullRequest, err)
+		}
+	}
+	logrus.Infof("Validated %s: %s", report.Commit, report.Summary())
+	return report, nil
+}
+
+func (s *Server) validateGitChangesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !s.gitManager.IsEnabled() {
+		return mcp.NewToolResultText("❌ Git integration is disabled"), nil
+	}
+	change := GitChange{
+		Commit: strings.TrimSpace(mcp.ParseString(request, "commit", "")),
+		Before: strings.TrimSpace(mcp.ParseString(request, "base", "")),
+	}
+	for _, file := range strings.Split(mcp.ParseString(request, "paths", ""), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			change.Paths = append(change.Paths, file)
+		}
+	}
+
+	report, err := s.ValidateGitChange(ctx, change)
+	if err != nil {
+		return toolError(ctx, "Failed to validate Git changes", err), nil
+	}
+	return mcp.NewToolResultText(formatValidationReport(report)), nil
+}
+
+// ValidateGitChangesHandler is a public wrapper for validateGitChangesHandler
+func (s *Server) ValidateGitChangesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.validateGitChangesHandler(ctx, request)
+}
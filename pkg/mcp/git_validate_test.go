@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestLintObject(t *testing.T) {
+	deployment := func(container string) string {
+		return "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  template:\n    spec:\n      containers:\n" + container
+	}
+	tests := []struct {
+		manifest string
+		errors   int
+		want     []string
+	}{
+		{
+			deployment("      - name: web\n        image: quay.io/acme/web:1.4.2\n        readinessProbe:\n          httpGet:\n            path: /healthz\n            port: 8080\n" +
+				"        resources:\n          requests:\n            cpu: 100m\n          limits:\n            memory: 256Mi\n"),
+			0, nil,
+		},
+		{
+			deployment("      - name: web\n        image: quay.io/acme/web\n        securityContext:\n          privileged: true\n"),
+			0, []string{"floating image tag", "no resource requests", "no memory limit", "runs privileged", "no readiness probe"},
+		},
+		{
+			deployment("      - name: web\n        image: registry:5000/web@sha256:abc\n"),
+			0, []string{"no resource requests"},
+		},
+		{"apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: once\nspec:\n  template:\n    spec:\n      containers:\n      - name: once\n", 1, []string{"has no image"}},
+		{"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n", 1, []string{"has no pod spec"}},
+		{"apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\ndata:\n  password: aHVudGVyMg==\n", 0, []string{"SealedSecret"}},
+		{"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  labels:\n    app: web\n", 1, []string{"metadata.name is not set"}},
+	}
+
+	for _, tt := range tests {
+		obj, err := decodeObject(tt.manifest)
+		if err != nil {
+			t.Fatalf("decodeObject(%q): %v", tt.manifest, err)
+		}
+		findings := lintObject(obj)
+		var messages []string
+		errors := 0
+		for _, finding := range findings {
+			messages = append(messages, finding.Message)
+			if finding.Severity == SeverityError {
+				errors++
+			}
+		}
+		text := strings.Join(messages, "\n")
+		if errors != tt.errors {
+			t.Errorf("lintObject(%s %s) = %d errors, expected %d:\n%s", obj.GetKind(), obj.GetName(), errors, tt.errors, text)
+		}
+		if tt.want == nil && len(findings) > 0 {
+			t.Errorf("lintObject(%s %s) = %q, expected no findings", obj.GetKind(), obj.GetName(), text)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("lintObject(%s %s) missing %q:\n%s", obj.GetKind(), obj.GetName(), want, text)
+			}
+		}
+	}
+}
+
+func TestValidateGitChange(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	runGit(t, repo, "init", "--initial-branch=main")
+	commitTestFile(t, repo, "README.md", "manifests\n")
+
+	// web matches the cluster, api changes its replicas, worker is new
+	manifest := func(name string, replicas int) string {
+		return "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: " + name + "\n  namespace: shop\nspec:\n  replicas: " +
+			string(rune('0'+replicas)) + "\n  template:\n    spec:\n      containers:\n      - name: app\n        image: quay.io/acme/" + name + "\n"
+	}
+	commitTestFile(t, repo, "apps.yaml", manifest("web", 2)+"---\n"+manifest("api", 3)+"---\n"+manifest("worker", 1))
+	commitTestFile(t, repo, "broken.yml", "kind: [\n")
+	commitTestFile(t, repo, "records.yaml", "apiVersion: mcp.openshift.io/v1\nkind: NodeAction\nmetadata:\n  name: cordon-worker-1\n")
+	commitTestFile(t, repo, "notes.md", "not a manifest\n")
+
+	web := newUnstructured("apps/v1", "Deployment", "shop", "web")
+	unstructured.SetNestedField(web.Object, int64(2), "spec", "replicas")
+	api := newUnstructured("apps/v1", "Deployment", "shop", "api")
+	unstructured.SetNestedField(api.Object, int64(2), "spec", "replicas")
+	s := newDynamicTestServer(web, api)
+	// A dry-run apply returns the live object with the manifest's replicas
+	s.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := &unstructured.Unstructured{}
+		if err := patch.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch()); err != nil {
+			return true, nil, err
+		}
+		live := web
+		if patch.GetName() == "api" {
+			live = api
+		}
+		applied := live.DeepCopy()
+		replicas, _, _ := unstructured.NestedInt64(patch.Object, "spec", "replicas")
+		unstructured.SetNestedField(applied.Object, replicas, "spec", "replicas")
+		return true, applied, nil
+	})
+	s.gitManager = NewGitManager(&GitConfig{Enabled: true, RepoPath: repo})
+
+	report, err := s.ValidateGitChange(context.Background(), GitChange{Before: "HEAD~4"})
+	if err != nil {
+		t.Fatalf("ValidateGitChange error = %v", err)
+	}
+	if len(report.Files) != 3 {
+		t.Fatalf("ValidateGitChange validated %d files, expected apps.yaml, broken.yml and records.yaml", len(report.Files))
+	}
+	if report.Drifted != 1 || report.Errors != 1 || report.State() != CheckFailure {
+		t.Errorf("ValidateGitChange = %d drifted, %d errors, state %s, expected 1, 1, failure", report.Drifted, report.Errors, report.State())
+	}
+
+	drift := make(map[string]string)
+	for _, file := range report.Files {
+		for _, object := range file.Objects {
+			drift[object.Object] = object.Drift
+		}
+	}
+	expected := map[string]string{
+		"Deployment shop/web":        DriftInSync,
+		"Deployment shop/api":        DriftChanged,
+		"Deployment shop/worker":     DriftMissing,
+		"NodeAction cordon-worker-1": DriftSkipped,
+	}
+	for object, state := range expected {
+		if drift[object] != state {
+			t.Errorf("drift of %s = %q, expected %q", object, drift[object], state)
+		}
+	}
+
+	text := formatValidationReport(report)
+	for _, want := range []string{"📄 broken.yml", "❌ invalid YAML/JSON", "🔀 drifted", "-   replicas: 2", "+   replicas: 3", "floating image tag"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatValidationReport output missing %q:\n%s", want, text)
+		}
+	}
+
+	// The tool validates explicit paths at the given commit
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"commit": "HEAD", "paths": "records.yaml, notes.md"}
+	result, err := s.ValidateGitChangesHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ValidateGitChangesHandler error = %v", err)
+	}
+	if text := resultText(result); !strings.Contains(text, "1 manifest(s): 0 error(s), 0 warning(s), 0 drifted") {
+		t.Errorf("ValidateGitChangesHandler = %q, expected only records.yaml validated", text)
+	}
+}
@@ -0,0 +1,585 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resourceMetricsGroupVersion is served by metrics-server or, on OpenShift,
+// the monitoring stack's metrics adapter
+const resourceMetricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+// hpaMetricFailures are the ScalingActive reasons of a metric the
+// autoscaler cannot read
+var hpaMetricFailures = map[string]bool{
+	"FailedGetResourceMetric":          true,
+	"FailedGetContainerResourceMetric": true,
+	"FailedGetPodsMetric":              true,
+	"FailedGetObjectMetric":            true,
+	"FailedGetExternalMetric":          true,
+}
+
+func (s *Server) initAutoscaling() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_hpas",
+			mcp.WithDescription("List HorizontalPodAutoscalers with their target, replica range, current replicas and current versus target metrics"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list autoscalers from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=web")),
+			mcp.WithTitleAnnotation("Autoscaling: List HPAs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listHPAsHandler)},
+		{Tool: mcp.NewTool("diagnose_hpa",
+			mcp.WithDescription("Explain why a HorizontalPodAutoscaler does not scale: missing metrics API (metrics-server), unknown custom or external metrics, missing resource requests, a missing target, or replicas pinned at the limits; shows metrics, conditions and scaling events"),
+			mcp.WithString("hpa_name", mcp.Description("Name of the autoscaler"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the autoscaler (default: default)")),
+			mcp.WithTitleAnnotation("Autoscaling: Diagnose HPA"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.diagnoseHPAHandler)},
+		{Tool: mcp.NewTool("create_hpa",
+			mcp.WithDescription("Create a HorizontalPodAutoscaler that scales a deployment or statefulset on average CPU and/or memory utilization"),
+			mcp.WithString("target_name", mcp.Description("Name of the workload to scale"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the workload (default: default)")),
+			mcp.WithString("target_kind", mcp.Description("Kind of the workload: Deployment (default) or StatefulSet")),
+			mcp.WithString("name", mcp.Description("Name of the autoscaler (default: the target name)")),
+			mcp.WithString("min_replicas", mcp.Description("Minimum replicas (default: 1)")),
+			mcp.WithString("max_replicas", mcp.Description("Maximum replicas"), mcp.Required()),
+			mcp.WithString("cpu_percent", mcp.Description("Target average CPU utilization in percent of the request (default: 80 unless memory_percent is set)")),
+			mcp.WithString("memory_percent", mcp.Description("Target average memory utilization in percent of the request")),
+			mcp.WithString("dry_run", mcp.Description("Show the autoscaler without creating it (true/false)")),
+			mcp.WithTitleAnnotation("Autoscaling: Create HPA"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.createHPAHandler)},
+	}
+}
+
+// formatMetricValue renders a metric target or current value
+func formatMetricValue(value autoscalingv2.MetricValueStatus) string {
+	switch {
+	case value.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *value.AverageUtilization)
+	case value.AverageValue != nil:
+		return value.AverageValue.String()
+	case value.Value != nil:
+		return value.Value.String()
+	}
+	return "<unknown>"
+}
+
+// metricTargetValue converts a metric target to the shape of a metric status
+func metricTargetValue(target autoscalingv2.MetricTarget) autoscalingv2.MetricValueStatus {
+	return autoscalingv2.MetricValueStatus{
+		AverageUtilization: target.AverageUtilization,
+		AverageValue:       target.AverageValue,
+		Value:              target.Value,
+	}
+}
+
+// hpaMetricName names a metric spec, e.g. "cpu" or "external/queue_depth"
+func hpaMetricName(spec autoscalingv2.MetricSpec) string {
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			return string(spec.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			return fmt.Sprintf("%s (container %s)", spec.ContainerResource.Name, spec.ContainerResource.Container)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			return "pods/" + spec.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			return fmt.Sprintf("%s/%s %s", strings.ToLower(spec.Object.DescribedObject.Kind), spec.Object.DescribedObject.Name, spec.Object.Metric.Name)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			return "external/" + spec.External.Metric.Name
+		}
+	}
+	return string(spec.Type)
+}
+
+// hpaMetricTarget returns the target of a metric spec
+func hpaMetricTarget(spec autoscalingv2.MetricSpec) autoscalingv2.MetricTarget {
+	switch {
+	case spec.Resource != nil:
+		return spec.Resource.Target
+	case spec.ContainerResource != nil:
+		return spec.ContainerResource.Target
+	case spec.Pods != nil:
+		return spec.Pods.Target
+	case spec.Object != nil:
+		return spec.Object.Target
+	case spec.External != nil:
+		return spec.External.Target
+	}
+	return autoscalingv2.MetricTarget{}
+}
+
+// hpaMetricCurrent returns the current value of a metric status
+func hpaMetricCurrent(status autoscalingv2.MetricStatus) autoscalingv2.MetricValueStatus {
+	switch {
+	case status.Resource != nil:
+		return status.Resource.Current
+	case status.ContainerResource != nil:
+		return status.ContainerResource.Current
+	case status.Pods != nil:
+		return status.Pods.Current
+	case status.Object != nil:
+		return status.Object.Current
+	case status.External != nil:
+		return status.External.Current
+	}
+	return autoscalingv2.MetricValueStatus{}
+}
+
+// hpaMetrics renders each metric as "name: current / target". Statuses are
+// reported in the order of the specs, so they are matched by position.
+func hpaMetrics(hpa *autoscalingv2.HorizontalPodAutoscaler) []string {
+	metrics := make([]string, 0, len(hpa.Spec.Metrics))
+	for i, spec := range hpa.Spec.Metrics {
+		current := "<unknown>"
+		if i < len(hpa.Status.CurrentMetrics) && hpa.Status.CurrentMetrics[i].Type == spec.Type {
+			current = formatMetricValue(hpaMetricCurrent(hpa.Status.CurrentMetrics[i]))
+		}
+		metrics = append(metrics, fmt.Sprintf("%s: %s / %s", hpaMetricName(spec), current, formatMetricValue(metricTargetValue(hpaMetricTarget(spec)))))
+	}
+	return metrics
+}
+
+// hpaCondition returns an autoscaler condition by type
+func hpaCondition(hpa *autoscalingv2.HorizontalPodAutoscaler, conditionType autoscalingv2.HorizontalPodAutoscalerConditionType) *autoscalingv2.HorizontalPodAutoscalerCondition {
+	for i := range hpa.Status.Conditions {
+		if hpa.Status.Conditions[i].Type == conditionType {
+			return &hpa.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// hpaHealthy reports whether an autoscaler can read its metrics and scale
+func hpaHealthy(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	for _, conditionType := range []autoscalingv2.HorizontalPodAutoscalerConditionType{autoscalingv2.AbleToScale, autoscalingv2.ScalingActive} {
+		if condition := hpaCondition(hpa, conditionType); condition != nil && condition.Status == corev1.ConditionFalse {
+			return false
+		}
+	}
+	return true
+}
+
+func hpaTarget(hpa *autoscalingv2.HorizontalPodAutoscaler) string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(hpa.Spec.ScaleTargetRef.Kind), hpa.Spec.ScaleTargetRef.Name)
+}
+
+func (s *Server) listHPAsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	hpas, err := s.k8sClient.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list HPAs in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	sort.SliceStable(hpas.Items, func(i, j int) bool {
+		a, b := hpas.Items[i], hpas.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	result := "📈 HPA List Results\n"
+	result += "===================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+
+	failing := 0
+	for i := range hpas.Items {
+		if !hpaHealthy(&hpas.Items[i]) {
+			failing++
+		}
+	}
+	result += fmt.Sprintf("📦 Found %d HPAs", len(hpas.Items))
+	if failing > 0 {
+		result += fmt.Sprintf(", ⚠️  %d not scaling", failing)
+	}
+	result += ":\n"
+
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		name := hpa.Name
+		if namespace == metav1.NamespaceAll {
+			name = hpa.Namespace + "/" + hpa.Name
+		}
+		icon := "✅"
+		if !hpaHealthy(hpa) {
+			icon = "❌"
+		}
+		result += fmt.Sprintf("%s %s → %s, replicas %d (min %d, max %d), age %s\n", icon, name, hpaTarget(hpa),
+			hpa.Status.CurrentReplicas, replicasOrDefault(hpa.Spec.MinReplicas), hpa.Spec.MaxReplicas, formatAge(hpa.CreationTimestamp.Time))
+		for _, metric := range hpaMetrics(hpa) {
+			result += fmt.Sprintf("   %s\n", metric)
+		}
+	}
+	if len(hpas.Items) == 0 {
+		result += "📭 None found\n"
+	}
+	if failing > 0 {
+		result += "\n💡 Run diagnose_hpa on an autoscaler that is not scaling to find out why\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// hpaTargetPodSpec returns the pod template of the autoscaler's target, or
+// nil when the target kind is not a built-in workload
+func (s *Server) hpaTargetPodSpec(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*corev1.PodSpec, error) {
+	ref := hpa.Spec.ScaleTargetRef
+	switch ref.Kind {
+	case "Deployment":
+		deployment, err := s.k8sClient.AppsV1().Deployments(hpa.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &deployment.Spec.Template.Spec, nil
+	case "StatefulSet":
+		statefulSet, err := s.k8sClient.AppsV1().StatefulSets(hpa.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &statefulSet.Spec.Template.Spec, nil
+	case "ReplicaSet":
+		replicaSet, err := s.k8sClient.AppsV1().ReplicaSets(hpa.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &replicaSet.Spec.Template.Spec, nil
+	}
+	return nil, nil
+}
+
+// containersWithoutRequest lists the containers that request none of a resource
+func containersWithoutRequest(spec *corev1.PodSpec, resourceName corev1.ResourceName) []string {
+	var missing []string
+	for _, container := range spec.Containers {
+		if _, ok := container.Resources.Requests[resourceName]; !ok {
+			missing = append(missing, container.Name)
+		}
+	}
+	return missing
+}
+
+// resourceMetricsAvailable reports whether the resource metrics API is served
+func (s *Server) resourceMetricsAvailable() bool {
+	_, err := s.k8sClient.Discovery().ServerResourcesForGroupVersion(resourceMetricsGroupVersion)
+	return err == nil
+}
+
+// diagnoseAutoscaler inspects an autoscaler, its target and the metrics API
+// and returns the problems found
+func (s *Server) diagnoseAutoscaler(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) []diagnosticFinding {
+	var findings []diagnosticFinding
+
+	podSpec, err := s.hpaTargetPodSpec(ctx, hpa)
+	if apierrors.IsNotFound(err) {
+		findings = append(findings, diagnosticFinding{
+			fmt.Sprintf("The scale target %s does not exist", hpaTarget(hpa)),
+			"Fix spec.scaleTargetRef, or delete the autoscaler if the workload was removed",
+		})
+	}
+
+	usesResourceMetrics := len(hpa.Spec.Metrics) == 0
+	for _, spec := range hpa.Spec.Metrics {
+		if spec.Type == autoscalingv2.ResourceMetricSourceType || spec.Type == autoscalingv2.ContainerResourceMetricSourceType {
+			usesResourceMetrics = true
+		}
+	}
+	if usesResourceMetrics && !s.resourceMetricsAvailable() {
+		findings = append(findings, diagnosticFinding{
+			fmt.Sprintf("The resource metrics API (%s) is not available, so CPU and memory usage cannot be read", resourceMetricsGroupVersion),
+			"Install metrics-server, or check the metrics APIService and the monitoring stack: oc get apiservice v1beta1.metrics.k8s.io",
+		})
+	}
+
+	if podSpec != nil {
+		for _, spec := range hpa.Spec.Metrics {
+			if spec.Type != autoscalingv2.ResourceMetricSourceType || spec.Resource == nil || spec.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+				continue
+			}
+			if missing := containersWithoutRequest(podSpec, spec.Resource.Name); len(missing) > 0 {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("Container(s) %s set no %s request, so %s utilization cannot be computed", strings.Join(missing, ", "), spec.Resource.Name, spec.Resource.Name),
+					fmt.Sprintf("Set resources.requests.%s on every container of %s", spec.Resource.Name, hpaTarget(hpa)),
+				})
+			}
+		}
+	}
+
+	if condition := hpaCondition(hpa, autoscalingv2.ScalingActive); condition != nil && condition.Status == corev1.ConditionFalse {
+		switch condition.Reason {
+		case "FailedGetResourceMetric", "FailedGetContainerResourceMetric":
+			// Usually explained by a missing metrics API or requests above
+			if len(findings) == 0 {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("CPU or memory usage cannot be read (%s): %s", condition.Reason, condition.Message),
+					"Check that the target's pods are running and ready, and that metrics-server reports them: top_pods",
+				})
+			}
+		case "ScalingDisabled":
+			findings = append(findings, diagnosticFinding{
+				"Scaling is disabled because the target has zero replicas",
+				"Scale the target to at least one replica to re-enable the autoscaler",
+			})
+		default:
+			if hpaMetricFailures[condition.Reason] {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("A custom or external metric cannot be read (%s): %s", condition.Reason, condition.Message),
+					"Check the metric name and selector, and that a custom or external metrics adapter (e.g. KEDA or prometheus-adapter) serves it: oc get apiservice | grep metrics",
+				})
+			} else {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("The autoscaler is not active (%s): %s", condition.Reason, condition.Message),
+					"Check the metrics the autoscaler uses and the events below",
+				})
+			}
+		}
+	}
+	if condition := hpaCondition(hpa, autoscalingv2.AbleToScale); condition != nil && condition.Status == corev1.ConditionFalse && err == nil {
+		findings = append(findings, diagnosticFinding{
+			fmt.Sprintf("The autoscaler cannot scale the target (%s): %s", condition.Reason, condition.Message),
+			"Check that the target exposes the scale subresource and that the autoscaler controller may update it",
+		})
+	}
+
+	if hpa.Status.DesiredReplicas >= hpa.Spec.MaxReplicas && hpa.Status.CurrentReplicas >= hpa.Spec.MaxReplicas {
+		findings = append(findings, diagnosticFinding{
+			fmt.Sprintf("Replicas are pinned at the maximum of %d", hpa.Spec.MaxReplicas),
+			"Raise maxReplicas if the cluster has capacity, or find out why the load is this high",
+		})
+	}
+	return findings
+}
+
+func (s *Server) diagnoseHPAHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "hpa_name", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ hpa_name is required"), nil
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+
+	hpa, err := s.k8sClient.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get HPA %s/%s", namespace, name), err), nil
+	}
+	findings := s.diagnoseAutoscaler(ctx, hpa)
+	events := s.objectEvents(ctx, hpa.Namespace, "HorizontalPodAutoscaler", hpa.Name)
+
+	result := "🔍 HPA Diagnostic Report\n"
+	result += "========================\n\n"
+	result += fmt.Sprintf("Autoscaler: %s/%s\n", hpa.Namespace, hpa.Name)
+	result += fmt.Sprintf("Target: %s\n", hpaTarget(hpa))
+	result += fmt.Sprintf("Replicas: current %d, desired %d (min %d, max %d)\n",
+		hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas, replicasOrDefault(hpa.Spec.MinReplicas), hpa.Spec.MaxReplicas)
+	if hpa.Status.LastScaleTime != nil {
+		result += fmt.Sprintf("Last scaled: %s ago\n", formatAge(hpa.Status.LastScaleTime.Time))
+	}
+
+	result += "\n📊 Metrics (current / target):\n"
+	for _, metric := range hpaMetrics(hpa) {
+		result += fmt.Sprintf("   %s\n", metric)
+	}
+	if len(hpa.Spec.Metrics) == 0 {
+		result += "   cpu: default 80% utilization target\n"
+	}
+
+	if len(hpa.Status.Conditions) > 0 {
+		result += "\n📋 Conditions:\n"
+		for _, condition := range hpa.Status.Conditions {
+			result += fmt.Sprintf("   %s=%s (%s): %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+
+	if len(findings) == 0 {
+		result += "\n✅ No problems found\n"
+	} else {
+		result += fmt.Sprintf("\n⚠️  Found %d issue(s):\n", len(findings))
+		for i, finding := range findings {
+			result += fmt.Sprintf("%d. %s\n", i+1, finding.problem)
+			result += fmt.Sprintf("   💡 %s\n", finding.fix)
+		}
+	}
+
+	if len(events) > 0 {
+		result += "\n📜 Recent scaling events:\n"
+		if len(events) > 10 {
+			events = events[len(events)-10:]
+		}
+		for _, event := range events {
+			result += formatObjectEvent(event) + "\n"
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// parseReplicaParam parses an optional non-negative int32 parameter
+func parseReplicaParam(request mcp.CallToolRequest, name string, defaultValue int32) (int32, error) {
+	value := mcp.ParseString(request, name, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %s '%s'", name, value)
+	}
+	return int32(parsed), nil
+}
+
+func (s *Server) createHPAHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	targetName := strings.TrimSpace(mcp.ParseString(request, "target_name", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	targetKind := mcp.ParseString(request, "target_kind", "Deployment")
+	name := mcp.ParseString(request, "name", targetName)
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+
+	if targetName == "" {
+		return mcp.NewToolResultText("❌ target_name is required"), nil
+	}
+	switch strings.ToLower(targetKind) {
+	case "deployment":
+		targetKind = "Deployment"
+	case "statefulset":
+		targetKind = "StatefulSet"
+	default:
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid target_kind '%s' (expected Deployment or StatefulSet)", targetKind)), nil
+	}
+
+	minReplicas, err := parseReplicaParam(request, "min_replicas", 1)
+	if err == nil && minReplicas < 1 {
+		err = fmt.Errorf("min_replicas must be at least 1")
+	}
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	maxReplicas, err := parseReplicaParam(request, "max_replicas", 0)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if maxReplicas < minReplicas || maxReplicas == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ max_replicas must be at least min_replicas (%d)", minReplicas)), nil
+	}
+	memoryPercent, err := parseReplicaParam(request, "memory_percent", 0)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	defaultCPU := int32(80)
+	if memoryPercent > 0 {
+		defaultCPU = 0
+	}
+	cpuPercent, err := parseReplicaParam(request, "cpu_percent", defaultCPU)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if cpuPercent == 0 && memoryPercent == 0 {
+		return mcp.NewToolResultText("❌ Set cpu_percent or memory_percent"), nil
+	}
+
+	hpa := newHPA(name, namespace, targetKind, targetName, minReplicas, maxReplicas, cpuPercent, memoryPercent)
+	generator := s.yamlGenerator
+	if generator == nil {
+		generator = NewYAMLGenerator()
+	}
+	manifest, err := generator.GenerateHPAYAML(name, namespace, targetKind, targetName, minReplicas, maxReplicas, cpuPercent, memoryPercent)
+	if err != nil {
+		return toolError(ctx, "Failed to generate HPA YAML", err), nil
+	}
+
+	result := "📈 Creating HPA\n"
+	result += "===============\n\n"
+	result += fmt.Sprintf("Autoscaler: %s/%s\n", namespace, name)
+	result += fmt.Sprintf("Target: %s/%s\n", strings.ToLower(targetKind), targetName)
+	result += fmt.Sprintf("Replicas: %d - %d\n", minReplicas, maxReplicas)
+	for _, spec := range hpa.Spec.Metrics {
+		result += fmt.Sprintf("Scale on: %s at %s average utilization\n", hpaMetricName(spec), formatMetricValue(metricTargetValue(hpaMetricTarget(spec))))
+	}
+
+	// Utilization targets are relative to the containers' requests
+	var warnings []string
+	if podSpec, err := s.hpaTargetPodSpec(ctx, hpa); apierrors.IsNotFound(err) {
+		warnings = append(warnings, fmt.Sprintf("%s %s does not exist yet; the autoscaler stays inactive until it does", targetKind, targetName))
+	} else if podSpec != nil {
+		for _, spec := range hpa.Spec.Metrics {
+			if missing := containersWithoutRequest(podSpec, spec.Resource.Name); len(missing) > 0 {
+				warnings = append(warnings, fmt.Sprintf("container(s) %s set no %s request; utilization cannot be computed until they do", strings.Join(missing, ", "), spec.Resource.Name))
+			}
+		}
+	}
+	if !s.resourceMetricsAvailable() {
+		warnings = append(warnings, fmt.Sprintf("the resource metrics API (%s) is not available; install metrics-server", resourceMetricsGroupVersion))
+	}
+	for _, warning := range warnings {
+		result += fmt.Sprintf("⚠️  %s\n", warning)
+	}
+
+	if dryRun {
+		result += fmt.Sprintf("\n📄 Manifest (dry run - nothing was created):\n```yaml\n%s```\n", manifest)
+		result += "\n💡 Re-run without dry_run to create the autoscaler"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	created, err := s.k8sClient.AutoscalingV2().HorizontalPodAutoscalers(namespace).Create(ctx, hpa, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to create HPA %s/%s", namespace, name), err), nil
+	}
+	logrus.Infof("Created HPA %s/%s for %s %s", namespace, name, targetKind, targetName)
+	s.recordAction(ctx, "create_hpa", objectReference("autoscaling/v2", "HorizontalPodAutoscaler", created), ReasonCreated,
+		fmt.Sprintf("Created HorizontalPodAutoscaler for %s %s (%d-%d replicas)", targetKind, targetName, minReplicas, maxReplicas))
+
+	result += "\n✅ HPA created successfully!\n"
+	result += "💡 Run diagnose_hpa in a minute to check that it reads its metrics"
+	return mcp.NewToolResultText(result), nil
+}
+
+// ListHPAsHandler is a public wrapper for listHPAsHandler
+func (s *Server) ListHPAsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listHPAsHandler(ctx, request)
+}
+
+// DiagnoseHPAHandler is a public wrapper for diagnoseHPAHandler
+func (s *Server) DiagnoseHPAHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.diagnoseHPAHandler(ctx, request)
+}
+
+// CreateHPAHandler is a public wrapper for createHPAHandler
+func (s *Server) CreateHPAHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.createHPAHandler(ctx, request)
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newHPATestServer(metricsAPI bool, objects ...runtime.Object) *Server {
+	client := kubefake.NewSimpleClientset(objects...)
+	if metricsAPI {
+		client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{GroupVersion: resourceMetricsGroupVersion, APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true}}},
+		}
+	}
+	return &Server{config: &Config{}, k8sClient: client}
+}
+
+func hpaTestDeployment(cpuRequest string) *appsv1.Deployment {
+	container := corev1.Container{Name: "web", Image: "web:1"}
+	if cpuRequest != "" {
+		container.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuRequest)}
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{container}}},
+		},
+	}
+}
+
+func TestHPAMetrics(t *testing.T) {
+	hpa := newHPA("web", "shop", "Deployment", "web", 2, 5, 70, 0)
+	queue := resource.MustParse("30")
+	hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &queue},
+		},
+	})
+	utilization := int32(45)
+	hpa.Status.CurrentMetrics = []autoscalingv2.MetricStatus{{
+		Type:     autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricStatus{Name: corev1.ResourceCPU, Current: autoscalingv2.MetricValueStatus{AverageUtilization: &utilization}},
+	}}
+
+	expected := []string{"cpu: 45% / 70%", "external/queue_depth: <unknown> / 30"}
+	result := hpaMetrics(hpa)
+	if strings.Join(result, "|") != strings.Join(expected, "|") {
+		t.Errorf("hpaMetrics() = %q, expected %q", result, expected)
+	}
+}
+
+func TestGenerateHPAYAML(t *testing.T) {
+	manifest, err := NewYAMLGenerator().GenerateHPAYAML("web", "shop", "Deployment", "web", 2, 6, 70, 60)
+	if err != nil {
+		t.Fatalf("GenerateHPAYAML() error = %v", err)
+	}
+	for _, want := range []string{"apiVersion: autoscaling/v2", "kind: HorizontalPodAutoscaler", "maxReplicas: 6", "minReplicas: 2", "averageUtilization: 70", "name: memory", "scaleTargetRef:"} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("GenerateHPAYAML() missing %q:\n%s", want, manifest)
+		}
+	}
+	if strings.Contains(manifest, "status") || strings.Contains(manifest, "creationTimestamp") {
+		t.Errorf("GenerateHPAYAML() kept server fields:\n%s", manifest)
+	}
+}
+
+func TestDiagnoseAutoscaler(t *testing.T) {
+	inactive := func(reason string) *autoscalingv2.HorizontalPodAutoscaler {
+		hpa := newHPA("web", "shop", "Deployment", "web", 1, 5, 80, 0)
+		hpa.Status.Conditions = []autoscalingv2.HorizontalPodAutoscalerCondition{
+			{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: reason, Message: "unable to get metric"},
+		}
+		return hpa
+	}
+	pinned := newHPA("web", "shop", "Deployment", "web", 1, 5, 80, 0)
+	pinned.Status.CurrentReplicas, pinned.Status.DesiredReplicas = 5, 7
+
+	tests := []struct {
+		name       string
+		hpa        *autoscalingv2.HorizontalPodAutoscaler
+		metricsAPI bool
+		deployment *appsv1.Deployment
+		expected   []string
+	}{
+		{"healthy", newHPA("web", "shop", "Deployment", "web", 1, 5, 80, 0), true, hpaTestDeployment("100m"), nil},
+		{"no metrics API", inactive("FailedGetResourceMetric"), false, hpaTestDeployment("100m"), []string{"resource metrics API"}},
+		{"missing request", inactive("FailedGetResourceMetric"), true, hpaTestDeployment(""), []string{"set no cpu request"}},
+		{"unreadable resource metric", inactive("FailedGetResourceMetric"), true, hpaTestDeployment("100m"), []string{"CPU or memory usage cannot be read"}},
+		{"external metric", inactive("FailedGetExternalMetric"), true, hpaTestDeployment("100m"), []string{"custom or external metric"}},
+		{"missing target", newHPA("web", "shop", "Deployment", "web", 1, 5, 80, 0), true, nil, []string{"does not exist"}},
+		{"pinned at max", pinned, true, hpaTestDeployment("100m"), []string{"pinned at the maximum of 5"}},
+	}
+	for _, tt := range tests {
+		var objects []runtime.Object
+		if tt.deployment != nil {
+			objects = append(objects, tt.deployment)
+		}
+		s := newHPATestServer(tt.metricsAPI, objects...)
+		findings := s.diagnoseAutoscaler(context.Background(), tt.hpa)
+		if len(findings) != len(tt.expected) {
+			t.Errorf("diagnoseAutoscaler(%s) = %v, expected %d finding(s)", tt.name, findings, len(tt.expected))
+			continue
+		}
+		for i, want := range tt.expected {
+			if !strings.Contains(findings[i].problem, want) {
+				t.Errorf("diagnoseAutoscaler(%s) finding %d = %q, expected %q", tt.name, i, findings[i].problem, want)
+			}
+		}
+	}
+}
+
+func TestCreateHPA(t *testing.T) {
+	s := newHPATestServer(true, hpaTestDeployment(""))
+	createRequest := func(args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := s.CreateHPAHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("CreateHPAHandler(%v) error = %v", args, err)
+		}
+		return resultText(result)
+	}
+
+	invalid := []map[string]interface{}{
+		{"namespace": "shop", "max_replicas": "3"},
+		{"target_name": "web", "namespace": "shop", "max_replicas": "3", "min_replicas": "4"},
+		{"target_name": "web", "namespace": "shop", "max_replicas": "3", "target_kind": "DaemonSet"},
+		{"target_name": "web", "namespace": "shop", "max_replicas": "3", "cpu_percent": "0"},
+	}
+	for _, args := range invalid {
+		if text := createRequest(args); !strings.HasPrefix(text, "❌") {
+			t.Errorf("create_hpa(%v) = %q, expected an error", args, text)
+		}
+	}
+
+	args := map[string]interface{}{"target_name": "web", "namespace": "shop", "max_replicas": "4", "cpu_percent": "70", "dry_run": "true"}
+	text := createRequest(args)
+	if !strings.Contains(text, "averageUtilization: 70") || !strings.Contains(text, "set no cpu request") {
+		t.Errorf("create_hpa dry run output:\n%s", text)
+	}
+	if _, err := s.k8sClient.AutoscalingV2().HorizontalPodAutoscalers("shop").Get(context.Background(), "web", metav1.GetOptions{}); err == nil {
+		t.Errorf("create_hpa dry run created the autoscaler")
+	}
+
+	args["dry_run"] = "false"
+	if text := createRequest(args); !strings.Contains(text, "✅ HPA created") {
+		t.Errorf("create_hpa output:\n%s", text)
+	}
+	hpa, err := s.k8sClient.AutoscalingV2().HorizontalPodAutoscalers("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("created HPA: %v", err)
+	}
+	if hpa.Spec.MaxReplicas != 4 || *hpa.Spec.MinReplicas != 1 || len(hpa.Spec.Metrics) != 1 || *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != 70 {
+		t.Errorf("created HPA spec = %+v", hpa.Spec)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop"}
+	result, _ := s.ListHPAsHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "web → deployment/web, replicas 0 (min 1, max 4)") {
+		t.Errorf("list_hpas output:\n%s", text)
+	}
+}
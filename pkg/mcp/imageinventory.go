@@ -0,0 +1,321 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxInventoryImages bounds the images listed without a package query
+const maxInventoryImages = 100
+
+// runningImage is one image running in the cluster, by digest when the
+// container runtime reported it
+type runningImage struct {
+	Image     string   // as the pod spec names it
+	Digest    string   // sha256:..., empty until the image is pulled
+	Reference string   // the image pinned to its digest, for SBOM generation
+	Workloads []string // namespace/Kind/name
+	Pods      int
+}
+
+// imageDigest splits a container status imageID, e.g.
+// docker-pullable://quay.io/shop/api@sha256:..., into the repository and
+// digest; the repository is empty when the runtime only reports a digest
+func imageDigest(imageID string) (repository, digest string) {
+	imageID = strings.TrimPrefix(strings.TrimPrefix(imageID, "docker-pullable://"), "docker://")
+	if repo, d, ok := strings.Cut(imageID, "@"); ok {
+		return repo, d
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return "", imageID
+	}
+	return "", ""
+}
+
+// imageRepository strips the tag or digest from an image reference
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// podWorkload names the workload a pod belongs to, following a ReplicaSet
+// to its Deployment
+func podWorkload(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return fmt.Sprintf("%s/Pod/%s", pod.Namespace, pod.Name)
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return fmt.Sprintf("%s/Deployment/%s", pod.Namespace, strings.TrimSuffix(owner.Name, "-"+hash))
+		}
+	}
+	return fmt.Sprintf("%s/%s/%s", pod.Namespace, owner.Kind, owner.Name)
+}
+
+// runningImages inventories the images of the pods running in a namespace,
+// or in every namespace
+func (s *Server) runningImages(ctx context.Context, namespace string) ([]*runningImage, error) {
+	pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*runningImage)
+	workloads := make(map[string]map[string]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		seen := make(map[string]bool)
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			repository, digest := imageDigest(status.ImageID)
+			key := digest
+			if key == "" {
+				key = status.Image
+			}
+			image, ok := byKey[key]
+			if !ok {
+				image = &runningImage{Image: status.Image, Digest: digest}
+				if digest != "" {
+					if repository == "" {
+						repository = imageRepository(status.Image)
+					}
+					image.Reference = repository + "@" + digest
+				}
+				byKey[key] = image
+				workloads[key] = make(map[string]bool)
+			}
+			if !seen[key] {
+				seen[key] = true
+				image.Pods++
+			}
+			workloads[key][podWorkload(pod)] = true
+		}
+	}
+
+	images := make([]*runningImage, 0, len(byKey))
+	for _, key := range sortedKeys(byKey) {
+		image := byKey[key]
+		image.Workloads = sortedKeys(workloads[key])
+		images = append(images, image)
+	}
+	sort.SliceStable(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+	return images, nil
+}
+
+// imagePackageMatch is a package found in a running image
+type imagePackageMatch struct {
+	Image   *runningImage
+	Package sbomPackage
+}
+
+func (s *Server) initImageInventory() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("image_inventory",
+			mcp.WithDescription("Inventory the images running in the cluster or a namespace, resolved to the digests the nodes pulled, and search their SBOMs for a package to answer supply-chain questions such as which workloads run log4j 2.x; SBOMs are read from the SBOM directory and can be generated for images missing one"),
+			mcp.WithString("namespace", mcp.Description("Namespace to inventory (default: all namespaces)")),
+			mcp.WithString("image", mcp.Description("Only images whose reference contains this text, e.g. quay.io/shop")),
+			mcp.WithString("package", mcp.Description("Package to look for in the SBOMs, matched against package names and package URLs, e.g. log4j-core")),
+			mcp.WithString("version", mcp.Description("Version constraint for package, e.g. 2.x, 2.14.1 or >=2.0.0,<2.17.1")),
+			mcp.WithBoolean("generate_sboms", mcp.Description(fmt.Sprintf("Run the configured SBOM command (default syft) for up to %d images missing an SBOM (default false)", maxSBOMGenerations))),
+			mcp.WithTitleAnnotation("Images: Inventory and SBOM Search"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.imageInventoryHandler)},
+	}
+}
+
+func (s *Server) imageInventoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	if namespace == "all" || namespace == "*" {
+		namespace = metav1.NamespaceAll
+	}
+	imageFilter := strings.TrimSpace(mcp.ParseString(request, "image", ""))
+	packageName := strings.TrimSpace(mcp.ParseString(request, "package", ""))
+	version := strings.TrimSpace(mcp.ParseString(request, "version", ""))
+	generate := mcp.ParseBoolean(request, "generate_sboms", false)
+	if version != "" && packageName == "" {
+		return mcp.NewToolResultText("❌ version needs package, e.g. package=log4j-core version=2.x"), nil
+	}
+	if err := parseVersionConstraint(version); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	all, err := s.runningImages(ctx, namespace)
+	if err != nil {
+		return toolError(ctx, "Failed to list pods", err), nil
+	}
+	var images []*runningImage
+	for _, image := range all {
+		if imageFilter == "" || strings.Contains(image.Image, imageFilter) || strings.Contains(image.Reference, imageFilter) {
+			images = append(images, image)
+		}
+	}
+
+	result := "📦 Image Inventory\n"
+	result += "==================\n\n"
+	if namespace != "" {
+		result += fmt.Sprintf("Scope: namespace %s\n", namespace)
+	} else {
+		result += "Scope: all namespaces\n"
+	}
+	workloads, unresolved := map[string]bool{}, 0
+	for _, image := range images {
+		for _, workload := range image.Workloads {
+			workloads[workload] = true
+		}
+		if image.Digest == "" {
+			unresolved++
+		}
+	}
+	result += fmt.Sprintf("Images: %d running in %d workload(s)", len(images), len(workloads))
+	if unresolved > 0 {
+		result += fmt.Sprintf(", %d without a digest yet", unresolved)
+	}
+	result += "\n"
+	if len(images) == 0 {
+		return mcp.NewToolResultText(result + "\n📭 No running images match"), nil
+	}
+
+	// SBOMs by digest, generating missing ones when asked to
+	dir := s.sbomDir()
+	sboms := make(map[string][]sbomPackage)
+	var missing []*runningImage
+	var sbomErrors []string
+	generated := 0
+	for _, image := range images {
+		if image.Digest == "" {
+			continue
+		}
+		if _, done := sboms[image.Digest]; done {
+			continue
+		}
+		packages, found, err := loadSBOM(dir, image.Digest)
+		if err != nil {
+			sbomErrors = append(sbomErrors, err.Error())
+		}
+		if !found && generate && generated < maxSBOMGenerations {
+			generated++
+			packages, err = s.generateSBOM(ctx, image.Reference, image.Digest)
+			if err != nil {
+				sbomErrors = append(sbomErrors, fmt.Sprintf("%s: %v", image.Reference, err))
+			} else {
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, image)
+			continue
+		}
+		sboms[image.Digest] = packages
+	}
+	result += fmt.Sprintf("SBOMs: %d of %d digests in %s", len(sboms), len(images)-unresolved, dir)
+	if generated > 0 {
+		result += fmt.Sprintf(" (%d generated this call)", generated)
+	}
+	result += "\n"
+
+	if packageName == "" {
+		result += "\n🖼️  Images:\n"
+		for i, image := range images {
+			if i == maxInventoryImages {
+				result += fmt.Sprintf("... %d more; filter with namespace or image\n", len(images)-maxInventoryImages)
+				break
+			}
+			result += fmt.Sprintf("• %s", image.Image)
+			if image.Digest != "" {
+				result += " @ " + shortDigest(image.Digest)
+			}
+			result += fmt.Sprintf(" — %d pod(s)", image.Pods)
+			if packages, ok := sboms[image.Digest]; ok {
+				result += fmt.Sprintf(", SBOM with %d packages", len(packages))
+			}
+			result += "\n"
+			result += fmt.Sprintf("   %s\n", strings.Join(image.Workloads, ", "))
+		}
+	} else {
+		var matches []imagePackageMatch
+		for _, image := range images {
+			for _, pkg := range sboms[image.Digest] {
+				if matchPackage(pkg, packageName) && (version == "" || versionMatches(pkg.Version, version)) {
+					matches = append(matches, imagePackageMatch{Image: image, Package: pkg})
+				}
+			}
+		}
+		query := packageName
+		if version != "" {
+			query += " " + version
+		}
+		affected := make(map[string]bool)
+		for _, match := range matches {
+			for _, workload := range match.Image.Workloads {
+				affected[workload] = true
+			}
+		}
+		result += fmt.Sprintf("\n🔎 %s: found in %d image(s) run by %d workload(s)\n", query, countImages(matches), len(affected))
+		var current *runningImage
+		for _, match := range matches {
+			if match.Image != current {
+				current = match.Image
+				result += fmt.Sprintf("\n• %s @ %s\n", current.Image, shortDigest(current.Digest))
+				result += fmt.Sprintf("   Workloads: %s\n", strings.Join(current.Workloads, ", "))
+			}
+			result += fmt.Sprintf("   📌 %s %s", match.Package.Name, valueOrNone(match.Package.Version))
+			if match.Package.PURL != "" {
+				result += fmt.Sprintf(" (%s)", match.Package.PURL)
+			}
+			result += "\n"
+		}
+		if len(missing) > 0 || unresolved > 0 {
+			result += fmt.Sprintf("\n⚠️  %d image(s) could not be searched and may also contain %s:\n", len(missing)+unresolved, packageName)
+			for _, image := range images {
+				if _, ok := sboms[image.Digest]; !ok {
+					result += fmt.Sprintf("• %s (%s)\n", image.Image, strings.Join(image.Workloads, ", "))
+				}
+			}
+		}
+	}
+
+	if len(sbomErrors) > 0 {
+		result += fmt.Sprintf("\n❌ SBOM problems (%d):\n", len(sbomErrors))
+		for _, message := range sbomErrors {
+			result += fmt.Sprintf("• %s\n", message)
+		}
+	}
+	if len(missing) > 0 && !generate {
+		result += fmt.Sprintf("\n💡 %d digest(s) have no SBOM; pass generate_sboms=true to generate them with %s, or place SPDX or CycloneDX JSON SBOMs in %s named by digest, e.g. %s",
+			len(missing), s.sbomCommand()[0], dir, filepath.Base(sbomPath(dir, missing[0].Digest)))
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// countImages counts the distinct images of package matches
+func countImages(matches []imagePackageMatch) int {
+	images := make(map[*runningImage]bool)
+	for _, match := range matches {
+		images[match.Image] = true
+	}
+	return len(images)
+}
+
+// ImageInventoryHandler is a public wrapper for imageInventoryHandler
+func (s *Server) ImageInventoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.imageInventoryHandler(ctx, request)
+}
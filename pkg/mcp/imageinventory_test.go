@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	apiDigest    = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	workerDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// imagePod is a running pod of a Deployment's ReplicaSet with one container
+func imagePod(namespace, name, deployment, image, imageID string) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: namespace,
+			Labels:          map[string]string{"pod-template-hash": "7d9f"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: deployment + "-7d9f", Controller: &controller}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Image: image, ImageID: imageID}},
+		},
+	}
+}
+
+func spdx(name, version string) string {
+	return `{"spdxVersion":"SPDX-2.3","packages":[{"name":"` + name + `","versionInfo":"` + version + `"}]}`
+}
+
+func TestImageInventory(t *testing.T) {
+	sbomDir := t.TempDir()
+	if err := os.WriteFile(sbomPath(sbomDir, apiDigest), []byte(spdx("log4j-core", "2.14.1")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	generated := filepath.Join(t.TempDir(), "worker.spdx.json")
+	if err := os.WriteFile(generated, []byte(spdx("log4j-core", "2.17.1")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		config: &Config{Inventory: &InventoryConfig{SBOMDir: sbomDir, SBOMCommand: []string{"cat", generated}}},
+		k8sClient: kubefake.NewSimpleClientset(
+			imagePod("shop", "api-7d9f-a", "api", "quay.io/shop/api:v2", "quay.io/shop/api@"+apiDigest),
+			imagePod("shop", "api-7d9f-b", "api", "quay.io/shop/api:v2", "docker-pullable://quay.io/shop/api@"+apiDigest),
+			imagePod("billing", "worker-7d9f-a", "worker", "quay.io/billing/worker:1.0", workerDigest),
+			imagePod("billing", "cron-7d9f-a", "cron", "quay.io/billing/cron:latest", ""),
+		),
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+	text := resultText(mustCall(t, s.ImageInventoryHandler, request))
+	for _, want := range []string{
+		"Images: 3 running in 3 workload(s), 1 without a digest yet",
+		"SBOMs: 1 of 2 digests",
+		"• quay.io/shop/api:v2 @ sha256:111111111111 — 2 pod(s), SBOM with 1 packages\n   shop/Deployment/api",
+		"💡 1 digest(s) have no SBOM; pass generate_sboms=true to generate them with cat",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("image_inventory output missing %q:\n%s", want, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"package": "log4j", "version": "<2.17.1"}
+	text = resultText(mustCall(t, s.ImageInventoryHandler, request))
+	for _, want := range []string{
+		"🔎 log4j <2.17.1: found in 1 image(s) run by 1 workload(s)",
+		"• quay.io/shop/api:v2 @ sha256:111111111111\n   Workloads: shop/Deployment/api\n   📌 log4j-core 2.14.1",
+		"⚠️  2 image(s) could not be searched",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("image_inventory package search missing %q:\n%s", want, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"package": "log4j-core", "generate_sboms": true}
+	text = resultText(mustCall(t, s.ImageInventoryHandler, request))
+	if !strings.Contains(text, "found in 2 image(s) run by 2 workload(s)") || !strings.Contains(text, "(1 generated this call)") {
+		t.Errorf("image_inventory with generated SBOMs:\n%s", text)
+	}
+	if _, err := os.Stat(sbomPath(sbomDir, workerDigest)); err != nil {
+		t.Errorf("generated SBOM was not kept: %v", err)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"version": "2.x"}
+	if text := resultText(mustCall(t, s.ImageInventoryHandler, request)); !strings.HasPrefix(text, "❌ version needs package") {
+		t.Errorf("image_inventory with version alone = %q", text)
+	}
+}
@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// internalRegistryHost is the service name of the integrated image registry
+	internalRegistryHost = "image-registry.openshift-image-registry.svc"
+	// imageTagHistoryLimit is how many earlier images of a tag are shown
+	imageTagHistoryLimit = 5
+)
+
+var imageStreamsGVR = schema.GroupVersionResource{Group: "image.openshift.io", Version: "v1", Resource: "imagestreams"}
+
+// internalImage is an image reference into the integrated registry
+type internalImage struct {
+	Namespace string
+	Stream    string
+	Tag       string
+	Digest    string
+}
+
+// parseInternalImage splits an integrated registry pull spec such as
+// image-registry.openshift-image-registry.svc:5000/shop/api:v2 into the
+// image stream it is served from
+func parseInternalImage(image string) (internalImage, bool) {
+	host, path, ok := strings.Cut(image, "/")
+	if !ok || strings.Split(host, ":")[0] != internalRegistryHost {
+		return internalImage{}, false
+	}
+	ref := internalImage{Tag: "latest"}
+	if name, digest, ok := strings.Cut(path, "@"); ok {
+		path, ref.Digest, ref.Tag = name, digest, ""
+	} else if i := strings.LastIndex(path, ":"); i > 0 {
+		path, ref.Tag = path[:i], path[i+1:]
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return internalImage{}, false
+	}
+	ref.Namespace, ref.Stream = parts[0], parts[1]
+	return ref, true
+}
+
+// imageTagItem is one image a tag has pointed at, newest first
+type imageTagItem struct {
+	Image     string // sha256 digest
+	Reference string // pull spec
+	Created   time.Time
+}
+
+// imageTag is a tag of an image stream with its source and import state
+type imageTag struct {
+	Tag         string
+	Source      string // where spec.tags imports or tracks the tag from
+	Items       []imageTagItem
+	ImportError string
+}
+
+// imageStreamTags merges the spec and status tags of an image stream
+func imageStreamTags(stream *unstructured.Unstructured) []imageTag {
+	tags := make(map[string]*imageTag)
+	get := func(name string) *imageTag {
+		if tags[name] == nil {
+			tags[name] = &imageTag{Tag: name}
+		}
+		return tags[name]
+	}
+
+	specTags, _, _ := unstructured.NestedSlice(stream.Object, "spec", "tags")
+	for _, t := range specTags {
+		spec, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := spec["name"].(string)
+		kind, _, _ := unstructured.NestedString(spec, "from", "kind")
+		from, _, _ := unstructured.NestedString(spec, "from", "name")
+		if name == "" || from == "" {
+			continue
+		}
+		switch kind {
+		case "DockerImage":
+			get(name).Source = "imported from " + from
+		default:
+			get(name).Source = fmt.Sprintf("tracks %s %s", kind, from)
+		}
+	}
+
+	statusTags, _, _ := unstructured.NestedSlice(stream.Object, "status", "tags")
+	for _, t := range statusTags {
+		status, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := status["tag"].(string)
+		if name == "" {
+			continue
+		}
+		tag := get(name)
+		items, _, _ := unstructured.NestedSlice(status, "items")
+		for _, i := range items {
+			item, ok := i.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			parsed := imageTagItem{Created: parseTime(item["created"])}
+			parsed.Image, _ = item["image"].(string)
+			parsed.Reference, _ = item["dockerImageReference"].(string)
+			tag.Items = append(tag.Items, parsed)
+		}
+		conditions := statusConditions(&unstructured.Unstructured{Object: map[string]interface{}{"status": status}})
+		if condition, ok := conditionStatus(conditions, "ImportSuccess"); ok && condition.Status == "False" {
+			tag.ImportError = condition.Message
+			if tag.ImportError == "" {
+				tag.ImportError = condition.Reason
+			}
+		}
+	}
+
+	result := make([]imageTag, 0, len(tags))
+	for _, name := range sortedKeys(tags) {
+		result = append(result, *tags[name])
+	}
+	return result
+}
+
+// shortDigest abbreviates sha256:0123... for listings
+func shortDigest(digest string) string {
+	if name, hash, ok := strings.Cut(digest, ":"); ok && len(hash) > 12 {
+		return name + ":" + hash[:12]
+	}
+	return digest
+}
+
+// formatImageTag renders a tag line with its current image and source
+func formatImageTag(tag imageTag) string {
+	result := fmt.Sprintf("• %s", tag.Tag)
+	if len(tag.Items) > 0 {
+		result += fmt.Sprintf(" → %s, updated %s", shortDigest(tag.Items[0].Image), formatConditionTime(tag.Items[0].Created))
+	} else {
+		result += " → no image"
+	}
+	if tag.Source != "" {
+		result += fmt.Sprintf(" (%s)", tag.Source)
+	}
+	result += "\n"
+	if tag.ImportError != "" {
+		result += fmt.Sprintf("   ❌ Import failed: %s\n", tag.ImportError)
+	}
+	return result
+}
+
+func (s *Server) initImageStreams() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_imagestreams",
+			mcp.WithDescription("List OpenShift ImageStreams with their tags, current images and import sources; with name, show one stream's tag history and import errors"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list image streams from, or 'all' (default: default)")),
+			mcp.WithString("name", mcp.Description("Show one image stream in detail")),
+			mcp.WithTitleAnnotation("Images: List ImageStreams"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listImageStreamsHandler)},
+		{Tool: mcp.NewTool("trace_image",
+			mcp.WithDescription("Trace an integrated registry image, e.g. from an ImagePullBackOff, to its ImageStream tag, import source or import error, and the BuildConfig and latest build that produce it"),
+			mcp.WithString("image", mcp.Description("Image pull spec, e.g. image-registry.openshift-image-registry.svc:5000/shop/api:v2"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the pod pulling the image, to check cross-namespace pull access")),
+			mcp.WithTitleAnnotation("Images: Trace Image"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.traceImageHandler)},
+	}
+}
+
+func (s *Server) listImageStreamsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if name := strings.TrimSpace(mcp.ParseString(request, "name", "")); name != "" {
+		return s.describeImageStream(ctx, namespace, name)
+	}
+
+	list, err := s.dynamicClient.Resource(imageStreamsGVR).Namespace(namespace).List(ctx, opts)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ ImageStreams are not available; this does not look like an OpenShift cluster"), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to list image streams in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	streams := list.Items
+	sort.Slice(streams, func(i, j int) bool {
+		if streams[i].GetNamespace() != streams[j].GetNamespace() {
+			return streams[i].GetNamespace() < streams[j].GetNamespace()
+		}
+		return streams[i].GetName() < streams[j].GetName()
+	})
+
+	result := "🖼️  ImageStream List Results\n"
+	result += "===========================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	result += fmt.Sprintf("📦 Found %d image streams:\n", len(streams))
+
+	for i := range streams {
+		stream := &streams[i]
+		name := stream.GetName()
+		if namespace == metav1.NamespaceAll {
+			name = stream.GetNamespace() + "/" + name
+		}
+		tags := imageStreamTags(stream)
+		failed := 0
+		for _, tag := range tags {
+			if tag.ImportError != "" {
+				failed++
+			}
+		}
+		icon := "✅"
+		if failed > 0 {
+			icon = "⚠️ "
+		}
+		result += fmt.Sprintf("\n%s %s - %d tag(s)", icon, name, len(tags))
+		if failed > 0 {
+			result += fmt.Sprintf(", %d failing to import", failed)
+		}
+		result += "\n"
+		if repository, _, _ := unstructured.NestedString(stream.Object, "status", "dockerImageRepository"); repository != "" {
+			result += fmt.Sprintf("   Repository: %s\n", repository)
+		}
+		for _, tag := range tags {
+			result += "   " + formatImageTag(tag)
+		}
+	}
+	if len(streams) == 0 {
+		result += "📭 None found\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// describeImageStream shows one image stream's tags with their history
+func (s *Server) describeImageStream(ctx context.Context, namespace, name string) (*mcp.CallToolResult, error) {
+	stream, err := s.dynamicClient.Resource(imageStreamsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ ImageStream %s not found in namespace %s", name, namespace)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get image stream %s", name), err), nil
+	}
+
+	result := fmt.Sprintf("🖼️  ImageStream: %s/%s\n", namespace, name)
+	result += "=====================\n\n"
+	if repository, _, _ := unstructured.NestedString(stream.Object, "status", "dockerImageRepository"); repository != "" {
+		result += fmt.Sprintf("Repository: %s\n", repository)
+	}
+	if public, _, _ := unstructured.NestedString(stream.Object, "status", "publicDockerImageRepository"); public != "" {
+		result += fmt.Sprintf("Public repository: %s\n", public)
+	}
+
+	tags := imageStreamTags(stream)
+	result += fmt.Sprintf("\n🏷️  Tags (%d):\n", len(tags))
+	for _, tag := range tags {
+		result += formatImageTag(tag)
+		for i, item := range tag.Items {
+			if i == imageTagHistoryLimit {
+				result += fmt.Sprintf("   ... %d older images\n", len(tag.Items)-imageTagHistoryLimit)
+				break
+			}
+			result += fmt.Sprintf("   %s %s\n", formatTime(ctx, item.Created), valueOrNone(item.Reference))
+		}
+	}
+	if len(tags) == 0 {
+		result += "📭 No tags; push an image or run a build that outputs to this stream\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) traceImageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	image := strings.TrimSpace(mcp.ParseString(request, "image", ""))
+	podNamespace := mcp.ParseString(request, "namespace", "")
+	if image == "" {
+		return mcp.NewToolResultText("❌ image is required"), nil
+	}
+	ref, ok := parseInternalImage(image)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %s is not an integrated registry image (%s:5000/<namespace>/<stream>:<tag>); check the external registry and pull secret instead", image, internalRegistryHost)), nil
+	}
+
+	result := "🔎 Image Trace\n"
+	result += "==============\n\n"
+	result += fmt.Sprintf("Image: %s\n", image)
+	result += fmt.Sprintf("ImageStream: %s/%s\n", ref.Namespace, ref.Stream)
+
+	var problems []string
+	stream, err := s.dynamicClient.Resource(imageStreamsGVR).Namespace(ref.Namespace).Get(ctx, ref.Stream, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return toolError(ctx, fmt.Sprintf("Failed to get image stream %s", ref.Stream), err), nil
+		}
+		result += "\n❌ ImageStream does not exist\n"
+		problems = append(problems, fmt.Sprintf("image stream %s/%s does not exist; create it or fix the image reference", ref.Namespace, ref.Stream))
+	} else {
+		tags := imageStreamTags(stream)
+		if ref.Digest != "" {
+			result += fmt.Sprintf("Digest: %s\n", ref.Digest)
+			found := ""
+			for _, tag := range tags {
+				for _, item := range tag.Items {
+					if item.Image == ref.Digest && found == "" {
+						found = tag.Tag
+					}
+				}
+			}
+			if found == "" {
+				result += "\n❌ No tag of the stream references this digest\n"
+				problems = append(problems, "the digest is not in any tag's history; it may have been pruned")
+			} else {
+				result += fmt.Sprintf("\n✅ Digest is in the history of tag %s\n", found)
+				ref.Tag = found
+			}
+		} else {
+			result += fmt.Sprintf("Tag: %s\n", ref.Tag)
+		}
+
+		var current *imageTag
+		for i := range tags {
+			if tags[i].Tag == ref.Tag {
+				current = &tags[i]
+			}
+		}
+		switch {
+		case current == nil && ref.Digest == "":
+			result += fmt.Sprintf("\n❌ Tag %s does not exist\n", ref.Tag)
+			problems = append(problems, fmt.Sprintf("tag %s does not exist; the build producing it may not have run or succeeded yet", ref.Tag))
+		case current != nil:
+			result += "\n🏷️  " + strings.TrimPrefix(formatImageTag(*current), "• ")
+			if current.Source == "" {
+				result += "   Source: pushed to the registry, e.g. by a build\n"
+			}
+			if current.ImportError != "" {
+				problems = append(problems, fmt.Sprintf("importing tag %s fails: %s", ref.Tag, current.ImportError))
+			}
+			if len(current.Items) == 0 && current.ImportError == "" {
+				problems = append(problems, fmt.Sprintf("tag %s has no image yet", ref.Tag))
+			}
+		}
+	}
+
+	// BuildConfigs producing the tag
+	if ref.Tag != "" {
+		producers, err := s.imageProducers(ctx, ref)
+		if err != nil {
+			result += fmt.Sprintf("\n⚠️  Could not list build configs: %v\n", err)
+		} else if len(producers) > 0 {
+			result += "\n🏗️  Produced by:\n"
+			for _, producer := range producers {
+				result += producer.describe()
+				if producer.Phase == "Failed" || producer.Phase == "Error" || producer.Phase == "Cancelled" {
+					problems = append(problems, fmt.Sprintf("the latest build %s is %s; see get_build_logs build_name=%s namespace=%s", producer.LastBuild, producer.Phase, producer.LastBuild, ref.Namespace))
+				}
+			}
+		}
+	}
+
+	if podNamespace != "" && podNamespace != ref.Namespace {
+		result += fmt.Sprintf("\nℹ️  Pulled across namespaces: service accounts in %s need the system:image-puller role in %s\n", podNamespace, ref.Namespace)
+	}
+
+	if len(problems) == 0 {
+		result += "\n✅ The image stream serves this image; check pull secrets and registry health if pulls still fail"
+	} else {
+		result += fmt.Sprintf("\n⚠️  Problems found (%d):\n", len(problems))
+		for _, problem := range problems {
+			result += fmt.Sprintf("• %s\n", problem)
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ListImageStreamsHandler is a public wrapper for listImageStreamsHandler
+func (s *Server) ListImageStreamsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listImageStreamsHandler(ctx, request)
+}
+
+// TraceImageHandler is a public wrapper for traceImageHandler
+func (s *Server) TraceImageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.traceImageHandler(ctx, request)
+}
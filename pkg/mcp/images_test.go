@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestParseInternalImage(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected internalImage
+		ok       bool
+	}{
+		{"image-registry.openshift-image-registry.svc:5000/shop/api:v2", internalImage{"shop", "api", "v2", ""}, true},
+		{"image-registry.openshift-image-registry.svc:5000/shop/api", internalImage{"shop", "api", "latest", ""}, true},
+		{"image-registry.openshift-image-registry.svc:5000/shop/api@sha256:abc", internalImage{"shop", "api", "", "sha256:abc"}, true},
+		{"quay.io/shop/api:v2", internalImage{}, false},
+		{"image-registry.openshift-image-registry.svc:5000/api:v2", internalImage{}, false},
+	}
+
+	for _, tt := range tests {
+		ref, ok := parseInternalImage(tt.image)
+		if ref != tt.expected || ok != tt.ok {
+			t.Errorf("parseInternalImage(%q) = %+v, %v, expected %+v, %v", tt.image, ref, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func newImageStream(namespace, name string) *unstructured.Unstructured {
+	stream := newUnstructured("image.openshift.io/v1", "ImageStream", namespace, name)
+	unstructured.SetNestedSlice(stream.Object, []interface{}{
+		map[string]interface{}{"name": "base", "from": map[string]interface{}{"kind": "DockerImage", "name": "registry.example.com/ubi9:latest"}},
+	}, "spec", "tags")
+	unstructured.SetNestedSlice(stream.Object, []interface{}{
+		map[string]interface{}{"tag": "v1", "items": []interface{}{
+			map[string]interface{}{"image": "sha256:1111111111111111", "created": "2024-05-01T10:00:00Z"},
+			map[string]interface{}{"image": "sha256:0000000000000000", "created": "2024-04-01T10:00:00Z"},
+		}},
+		map[string]interface{}{"tag": "base", "conditions": []interface{}{
+			map[string]interface{}{"type": "ImportSuccess", "status": "False", "message": "unauthorized: authentication required"},
+		}},
+	}, "status", "tags")
+	return stream
+}
+
+func TestImageStreamTags(t *testing.T) {
+	tags := imageStreamTags(newImageStream("shop", "api"))
+	if len(tags) != 2 || tags[0].Tag != "base" || tags[1].Tag != "v1" {
+		t.Fatalf("imageStreamTags = %+v, expected base and v1", tags)
+	}
+	if tags[0].Source != "imported from registry.example.com/ubi9:latest" || tags[0].ImportError != "unauthorized: authentication required" {
+		t.Errorf("base tag = %+v", tags[0])
+	}
+	if len(tags[1].Items) != 2 || tags[1].Items[0].Image != "sha256:1111111111111111" {
+		t.Errorf("v1 tag items = %+v", tags[1].Items)
+	}
+}
+
+func TestTraceImage(t *testing.T) {
+	bc := newUnstructured("build.openshift.io/v1", "BuildConfig", "shop", "api")
+	unstructured.SetNestedField(bc.Object, "Source", "spec", "strategy", "type")
+	unstructured.SetNestedMap(bc.Object, map[string]interface{}{"type": "Git", "git": map[string]interface{}{"uri": "https://git.example.com/shop/api.git", "ref": "main"}}, "spec", "source")
+	unstructured.SetNestedMap(bc.Object, map[string]interface{}{"kind": "ImageStreamTag", "name": "api:v2"}, "spec", "output", "to")
+	unstructured.SetNestedField(bc.Object, int64(3), "status", "lastVersion")
+	build := newUnstructured("build.openshift.io/v1", "Build", "shop", "api-3")
+	unstructured.SetNestedField(build.Object, "Failed", "status", "phase")
+
+	listKinds := map[schema.GroupVersionResource]string{
+		imageStreamsGVR: "ImageStreamList", buildConfigsGVR: "BuildConfigList", buildsGVR: "BuildList",
+	}
+	s := &Server{config: &Config{}, dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, newImageStream("shop", "api"), bc, build)}
+
+	tests := []struct {
+		args     map[string]interface{}
+		expected []string
+	}{
+		{
+			map[string]interface{}{"image": "image-registry.openshift-image-registry.svc:5000/shop/api:v2", "namespace": "web"},
+			[]string{
+				"❌ Tag v2 does not exist",
+				"• BuildConfig api - Source strategy, git https://git.example.com/shop/api.git@main",
+				"Latest build: ❌ api-3 Failed",
+				"the latest build api-3 is Failed; see get_build_logs build_name=api-3 namespace=shop",
+				"service accounts in web need the system:image-puller role in shop",
+			},
+		},
+		{
+			map[string]interface{}{"image": "image-registry.openshift-image-registry.svc:5000/shop/api@sha256:0000000000000000"},
+			[]string{"✅ Digest is in the history of tag v1", "✅ The image stream serves this image"},
+		},
+		{
+			map[string]interface{}{"image": "image-registry.openshift-image-registry.svc:5000/shop/api:base"},
+			[]string{"❌ Import failed: unauthorized: authentication required", "importing tag base fails"},
+		},
+		{
+			map[string]interface{}{"image": "quay.io/shop/api:v2"},
+			[]string{"❌ quay.io/shop/api:v2 is not an integrated registry image"},
+		},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := s.TraceImageHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("TraceImageHandler(%v) error = %v", tt.args, err)
+		}
+		text := resultText(result)
+		for _, want := range tt.expected {
+			if !strings.Contains(text, want) {
+				t.Errorf("trace_image %v output missing %q:\n%s", tt.args["image"], want, text)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// routerServiceLabel names the IngressController a router service publishes
+const routerServiceLabel = "ingresscontroller.operator.openshift.io/owning-ingresscontroller"
+
+func (s *Server) initIngressTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("collect_ingress_diagnostics",
+			mcp.WithDescription("Collect router diagnostics from openshift-ingress (router logs, HAProxy process information and backend states, IngressController conditions, router pod health, load balancer services and their endpoints, and routes the routers rejected) and report HAProxy reload errors, route admission conflicts and load balancer problems. Use when routes return 503s or time out for many applications at once"),
+			mcp.WithString("controller", mcp.Description("Only collect the routers of this IngressController, e.g. default")),
+			mcp.WithString("since", mcp.Description("How far back to collect logs (default 1h)")),
+			mcp.WithString("output_dir", mcp.Description("Directory to store the collection")),
+			mcp.WithBoolean("compressed", mcp.Description("Package the collection into a single tar.gz bundle")),
+			mcp.WithTitleAnnotation("Diagnostics: Collect Ingress"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.collectIngressDiagnosticsHandler)},
+		{Tool: mcp.NewTool("analyze_ingress_diagnostics",
+			mcp.WithDescription("Analyze an ingress collection from collect_ingress_diagnostics for router failure signatures, routers near their connection limit, routes without available servers and load balancer and admission problems"),
+			mcp.WithString("path", mcp.Description("Directory or bundle of the collection"), mcp.Required()),
+			mcp.WithString("export", mcp.Description(exportParamDescription)),
+			mcp.WithTitleAnnotation("Analysis: Ingress"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.analyzeIngressDiagnosticsHandler)},
+	}
+}
+
+func (s *Server) collectIngressDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts := &diagnostics.CollectionOptions{
+		OutputDir:   mcp.ParseString(request, "output_dir", ""),
+		IncludeLogs: true,
+		Compressed:  mcp.ParseBoolean(request, "compressed", false),
+		Filters: map[string]string{
+			"since":      mcp.ParseString(request, "since", ""),
+			"controller": strings.TrimSpace(mcp.ParseString(request, "controller", "")),
+		},
+	}
+
+	result, err := s.diagnosticCollector.CollectIngress(ctx, opts)
+	if result == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if err != nil {
+		return toolError(ctx, "❌ Failed to collect ingress diagnostics", err), nil
+	}
+
+	response := "🚦 Ingress Collection\n"
+	response += "====================\n\n"
+	if controller := result.Metadata["controller"]; controller != "" {
+		response += fmt.Sprintf("Ingress controller: %s\n", controller)
+	}
+	response += fmt.Sprintf("Router pods: %s, logs: %s since %s, checks: %s\n", result.Metadata["pods"], result.Metadata["logs"], result.Metadata["since"], result.Metadata["checks"])
+	response += fmt.Sprintf("📁 Location: %s\n", result.FilePath)
+	if result.Metadata["bundle"] == "" {
+		response += fmt.Sprintf("📋 Manifest: %s\n", result.Metadata["manifest"])
+	}
+	response += fmt.Sprintf("📦 Size: %.2f MB\n", float64(result.Size)/(1024*1024))
+	response += fmt.Sprintf("⏱️ Duration: %v\n", result.Duration.Round(time.Millisecond))
+	if failed := result.Metadata["failed"]; failed != "0" {
+		response += fmt.Sprintf("\n⚠️ %s logs, checks or resources could not be collected; see the manifest for the errors.\n", failed)
+	}
+	response += "\n" + bundleNote(result)
+
+	analysis, err := s.analysisEngine.AnalyzeIngress(ctx, result.FilePath)
+	if err != nil {
+		recordToolError(ctx, err)
+		response += fmt.Sprintf("\n❌ Analysis failed: %v\n", err)
+		return mcp.NewToolResultText(response), nil
+	}
+	response += "\n" + s.formatAnalysisResult(ctx, analysis)
+	return mcp.NewToolResultText(response), nil
+}
+
+func (s *Server) analyzeIngressDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := mcp.ParseString(request, "path", "")
+	if path == "" {
+		return mcp.NewToolResultText("❌ path is required"), nil
+	}
+	format, err := diagnostics.ParseExportFormat(mcp.ParseString(request, "export", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	result, err := s.analysisEngine.AnalyzeIngress(ctx, path)
+	if err != nil {
+		return toolError(ctx, "❌ Failed to analyze the ingress collection", err), nil
+	}
+	return mcp.NewToolResultText(s.formatAnalysisResult(ctx, result) + s.exportNote(ctx, result, format)), nil
+}
+
+// ingressControllerState reads an IngressController's replicas and the
+// operator's conditions
+func ingressControllerState(controller *unstructured.Unstructured) diagnostics.IngressControllerState {
+	state := diagnostics.IngressControllerState{Name: controller.GetName(), DesiredReplicas: defaultRouterReplicas, Available: true}
+	state.Domain, _, _ = unstructured.NestedString(controller.Object, "status", "domain")
+	state.Strategy, _, _ = unstructured.NestedString(controller.Object, "status", "endpointPublishingStrategy", "type")
+	if replicas, ok, _ := unstructured.NestedInt64(controller.Object, "spec", "replicas"); ok {
+		state.DesiredReplicas = int(replicas)
+	}
+	for _, condition := range statusConditions(controller, "Available", "Degraded") {
+		switch {
+		case condition.Type == "Available" && condition.Status == "False":
+			state.Available = false
+		case condition.Type == "Degraded" && condition.Status == "True":
+			state.Degraded = true
+		default:
+			continue
+		}
+		if state.Message == "" {
+			state.Message = condition.Message
+		}
+	}
+	return state
+}
+
+// routerInfrastructure reads the IngressControllers of routers with their
+// router pods and load balancer services. Routers without an
+// IngressController, as third-party routers are, are left out.
+func (s *Server) routerInfrastructure(ctx context.Context, routers []string) (diagnostics.IngressState, error) {
+	var state diagnostics.IngressState
+	for _, router := range routers {
+		controller, err := s.dynamicClient.Resource(ingressControllersGVR).Namespace(ingressOperatorNamespace).Get(ctx, router, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return state, fmt.Errorf("reading ingress controller %s: %w", router, err)
+		}
+		state.Controllers = append(state.Controllers, ingressControllerState(controller))
+	}
+	if len(state.Controllers) == 0 {
+		return state, nil
+	}
+	names := make([]string, 0, len(state.Controllers))
+	for _, controller := range state.Controllers {
+		names = append(names, controller.Name)
+	}
+	sort.Strings(names)
+
+	pods, err := s.k8sClient.CoreV1().Pods(routerNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s in (%s)", routerDeploymentLabel, strings.Join(names, ",")),
+	})
+	if err != nil {
+		return state, fmt.Errorf("listing router pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		router := diagnostics.RouterPodState{
+			Name:       pod.Name,
+			Controller: pod.Labels[routerDeploymentLabel],
+			Node:       pod.Spec.NodeName,
+			Ready:      podReady(pod),
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			router.Restarts += int(status.RestartCount)
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				router.Reason = status.State.Waiting.Reason
+			} else if status.LastTerminationState.Terminated != nil && router.Reason == "" {
+				router.Reason = status.LastTerminationState.Terminated.Reason
+			}
+		}
+		state.Pods = append(state.Pods, router)
+	}
+	sort.Slice(state.Pods, func(i, j int) bool { return state.Pods[i].Name < state.Pods[j].Name })
+
+	services, err := s.k8sClient.CoreV1().Services(routerNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s in (%s)", routerServiceLabel, strings.Join(names, ",")),
+	})
+	if err != nil {
+		return state, fmt.Errorf("listing router services: %w", err)
+	}
+	for _, service := range services.Items {
+		// The internal services only serve the routers' metrics
+		if strings.HasPrefix(service.Name, "router-internal-") {
+			continue
+		}
+		balancer := diagnostics.IngressLoadBalancer{
+			Service:               service.Name,
+			Controller:            service.Labels[routerServiceLabel],
+			Type:                  string(service.Spec.Type),
+			ExternalTrafficPolicy: string(service.Spec.ExternalTrafficPolicy),
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				balancer.Ingress = append(balancer.Ingress, ingress.Hostname)
+			} else if ingress.IP != "" {
+				balancer.Ingress = append(balancer.Ingress, ingress.IP)
+			}
+		}
+		endpoints, err := s.k8sClient.CoreV1().Endpoints(routerNamespace).Get(ctx, service.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return state, fmt.Errorf("reading endpoints of %s: %w", service.Name, err)
+		}
+		if err == nil {
+			for _, subset := range endpoints.Subsets {
+				balancer.ReadyEndpoints += len(subset.Addresses)
+				balancer.NotReadyEndpoints += len(subset.NotReadyAddresses)
+			}
+		}
+		state.LoadBalancers = append(state.LoadBalancers, balancer)
+	}
+	sort.Slice(state.LoadBalancers, func(i, j int) bool { return state.LoadBalancers[i].Service < state.LoadBalancers[j].Service })
+	return state, nil
+}
+
+// CollectIngressDiagnosticsHandler is a public wrapper for collectIngressDiagnosticsHandler
+func (s *Server) CollectIngressDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.collectIngressDiagnosticsHandler(ctx, request)
+}
+
+// AnalyzeIngressDiagnosticsHandler is a public wrapper for analyzeIngressDiagnosticsHandler
+func (s *Server) AnalyzeIngressDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.analyzeIngressDiagnosticsHandler(ctx, request)
+}
@@ -0,0 +1,406 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// defaultInventoryDir is where scheduled inventory exports are written
+	defaultInventoryDir = "/tmp/diagnostics/inventory"
+
+	// maxInlineInventoryBytes bounds an export returned in the tool result
+	maxInlineInventoryBytes = 64 * 1024
+
+	inventoryJobName = "inventory-export"
+)
+
+// App and environment metadata is read from these label or annotation keys,
+// in order, first on the workload and then on its namespace. Owners and teams
+// follow the ownership conventions.
+var (
+	inventoryAppKeys         = []string{"app.kubernetes.io/name", "app", "app.kubernetes.io/part-of"}
+	inventoryEnvironmentKeys = []string{"environment", "env", "app.kubernetes.io/environment"}
+)
+
+var infrastructuresGVR = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "infrastructures"}
+
+// InventoryConfig configures scheduled inventory exports
+type InventoryConfig struct {
+	ExportInterval string   `json:"export_interval"` // e.g. "24h"; empty disables scheduled exports
+	ExportDir      string   `json:"export_dir"`
+	Format         string   `json:"format"` // json or csv
+	ClusterName    string   `json:"cluster_name"`
+	Namespaces     []string `json:"namespaces"`
+	SBOMDir        string   `json:"sbom_dir"`     // SBOMs by image digest, read by image_inventory
+	SBOMCommand    []string `json:"sbom_command"` // generates an SBOM on stdout; {image} is replaced
+}
+
+// InventoryItem is one normalized namespace or workload in an inventory export
+type InventoryItem struct {
+	Cluster     string    `json:"cluster"`
+	Namespace   string    `json:"namespace"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	Replicas    *int32    `json:"replicas,omitempty"`
+	Images      []string  `json:"images,omitempty"`
+	App         string    `json:"app,omitempty"`
+	Owner       string    `json:"owner,omitempty"`
+	Team        string    `json:"team,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+// InventoryImage summarizes where an image runs
+type InventoryImage struct {
+	Image     string `json:"image"`
+	Workloads int    `json:"workloads"`
+}
+
+// Inventory is a normalized cluster inventory for CMDB ingestion
+type Inventory struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Clusters    []string         `json:"clusters"`
+	Namespaces  []InventoryItem  `json:"namespaces"`
+	Workloads   []InventoryItem  `json:"workloads"`
+	Images      []InventoryImage `json:"images"`
+}
+
+var inventoryCSVHeader = []string{"cluster", "namespace", "kind", "name", "replicas", "images", "app", "owner", "team", "environment", "created"}
+
+func (s *Server) initInventoryTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("export_inventory",
+			mcp.WithDescription("Export a normalized inventory of namespaces, workloads, images and owners as JSON or CSV for CMDB ingestion"),
+			mcp.WithTitleAnnotation("Inventory: Export"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("format", mcp.Description("Export format: json (default) or csv")),
+			mcp.WithString("namespaces", mcp.Description("Comma-separated namespaces to export (default: all namespaces)")),
+			mcp.WithString("cluster_name", mcp.Description("Cluster name recorded on every item (default: the OpenShift infrastructure name)")),
+			mcp.WithString("output_path", mcp.Description("Write the export to this file instead of returning it inline")),
+		), Handler: server.ToolHandlerFunc(s.exportInventoryHandler)},
+	}
+}
+
+// lookupMetadata returns the first key found in any of the maps
+func lookupMetadata(keys []string, maps ...map[string]string) string {
+	for _, m := range maps {
+		for _, key := range keys {
+			if value := m[key]; value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// clusterName resolves the name recorded on inventory items
+func (s *Server) clusterName(ctx context.Context, override string) string {
+	if override != "" {
+		return override
+	}
+	if s.config != nil && s.config.Inventory != nil && s.config.Inventory.ClusterName != "" {
+		return s.config.Inventory.ClusterName
+	}
+	if s.dynamicClient != nil {
+		infra, err := s.dynamicClient.Resource(infrastructuresGVR).Get(ctx, "cluster", metav1.GetOptions{})
+		if err == nil {
+			if name, _, _ := unstructured.NestedString(infra.Object, "status", "infrastructureName"); name != "" {
+				return name
+			}
+		}
+	}
+	return "unknown"
+}
+
+// collectInventory builds the inventory for the given namespaces (all when empty)
+func (s *Server) collectInventory(ctx context.Context, cluster string, namespaces []string) (*Inventory, error) {
+	inventory := &Inventory{GeneratedAt: time.Now().UTC(), Clusters: []string{cluster}}
+
+	nsList, err := s.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	wanted := make(map[string]bool)
+	for _, namespace := range namespaces {
+		wanted[namespace] = true
+	}
+	byName := make(map[string]*corev1.Namespace)
+	for i := range nsList.Items {
+		ns := &nsList.Items[i]
+		if len(wanted) > 0 && !wanted[ns.Name] {
+			continue
+		}
+		byName[ns.Name] = ns
+		ownership := ownershipFromMetadata("namespace "+ns.Name, ns.Labels, ns.Annotations)
+		inventory.Namespaces = append(inventory.Namespaces, InventoryItem{
+			Cluster:     cluster,
+			Namespace:   ns.Name,
+			Kind:        "Namespace",
+			Name:        ns.Name,
+			App:         lookupMetadata(inventoryAppKeys, ns.Labels, ns.Annotations),
+			Owner:       ownership.Owner,
+			Team:        ownership.Team,
+			Environment: lookupMetadata(inventoryEnvironmentKeys, ns.Labels, ns.Annotations),
+			Created:     ns.CreationTimestamp.UTC(),
+		})
+	}
+
+	addWorkload := func(kind string, meta metav1.ObjectMeta, replicas *int32, template *corev1.PodTemplateSpec) {
+		ns, ok := byName[meta.Namespace]
+		if !ok {
+			return
+		}
+		ownership := ownershipFromMetadata(kind+" "+meta.Name, meta.Labels, meta.Annotations, ns.Labels, ns.Annotations)
+		item := InventoryItem{
+			Cluster:     cluster,
+			Namespace:   meta.Namespace,
+			Kind:        kind,
+			Name:        meta.Name,
+			Replicas:    replicas,
+			App:         lookupMetadata(inventoryAppKeys, meta.Labels, meta.Annotations, ns.Labels, ns.Annotations),
+			Owner:       ownership.Owner,
+			Team:        ownership.Team,
+			Environment: lookupMetadata(inventoryEnvironmentKeys, meta.Labels, meta.Annotations, ns.Labels, ns.Annotations),
+			Created:     meta.CreationTimestamp.UTC(),
+		}
+		for _, container := range template.Spec.InitContainers {
+			item.Images = append(item.Images, container.Image)
+		}
+		for _, container := range template.Spec.Containers {
+			item.Images = append(item.Images, container.Image)
+		}
+		inventory.Workloads = append(inventory.Workloads, item)
+	}
+
+	scopes := namespaces
+	if len(scopes) == 0 {
+		scopes = []string{metav1.NamespaceAll}
+	}
+	for _, namespace := range scopes {
+		deployments, err := s.k8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		for _, d := range deployments.Items {
+			addWorkload("Deployment", d.ObjectMeta, d.Spec.Replicas, &d.Spec.Template)
+		}
+
+		statefulSets, err := s.k8sClient.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+		}
+		for _, ss := range statefulSets.Items {
+			addWorkload("StatefulSet", ss.ObjectMeta, ss.Spec.Replicas, &ss.Spec.Template)
+		}
+
+		daemonSets, err := s.k8sClient.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+		}
+		for _, ds := range daemonSets.Items {
+			desired := ds.Status.DesiredNumberScheduled
+			addWorkload("DaemonSet", ds.ObjectMeta, &desired, &ds.Spec.Template)
+		}
+
+		cronJobs, err := s.k8sClient.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+		}
+		for _, cj := range cronJobs.Items {
+			addWorkload("CronJob", cj.ObjectMeta, nil, &cj.Spec.JobTemplate.Spec.Template)
+		}
+	}
+
+	sort.Slice(inventory.Namespaces, func(i, j int) bool { return inventory.Namespaces[i].Name < inventory.Namespaces[j].Name })
+	sort.Slice(inventory.Workloads, func(i, j int) bool {
+		a, b := inventory.Workloads[i], inventory.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	images := make(map[string]int)
+	for _, workload := range inventory.Workloads {
+		for _, image := range workload.Images {
+			images[image]++
+		}
+	}
+	for _, image := range sortedKeys(images) {
+		inventory.Images = append(inventory.Images, InventoryImage{Image: image, Workloads: images[image]})
+	}
+
+	return inventory, nil
+}
+
+// encodeInventory renders an inventory as JSON or as one CSV row per namespace and workload
+func encodeInventory(inventory *Inventory, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return json.MarshalIndent(inventory, "", "  ")
+	case "csv":
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Write(inventoryCSVHeader)
+		for _, item := range append(append([]InventoryItem(nil), inventory.Namespaces...), inventory.Workloads...) {
+			replicas := ""
+			if item.Replicas != nil {
+				replicas = strconv.Itoa(int(*item.Replicas))
+			}
+			writer.Write([]string{
+				item.Cluster, item.Namespace, item.Kind, item.Name, replicas, strings.Join(item.Images, ";"),
+				item.App, item.Owner, item.Team, item.Environment, item.Created.Format(time.RFC3339),
+			})
+		}
+		writer.Flush()
+		return buf.Bytes(), writer.Error()
+	default:
+		return nil, fmt.Errorf("unsupported format %q: use json or csv", format)
+	}
+}
+
+func inventoryExtension(format string) string {
+	if strings.ToLower(format) == "csv" {
+		return "csv"
+	}
+	return "json"
+}
+
+// initInventorySchedule registers the scheduled export job when an interval is configured
+func (s *Server) initInventorySchedule(config *InventoryConfig) {
+	if config == nil || config.ExportInterval == "" {
+		return
+	}
+	interval, err := time.ParseDuration(config.ExportInterval)
+	if err != nil || interval <= 0 {
+		logrus.Warnf("Invalid inventory export interval %q, scheduled exports disabled", config.ExportInterval)
+		return
+	}
+	if _, err := encodeInventory(&Inventory{}, config.Format); err != nil {
+		logrus.WithError(err).Warn("Invalid inventory export format, scheduled exports disabled")
+		return
+	}
+
+	s.scheduler.AddLeaderJob(inventoryJobName, interval, func(ctx context.Context) error {
+		_, err := s.writeScheduledInventory(ctx, config)
+		return err
+	})
+	logrus.Infof("Scheduled inventory export every %s", interval)
+}
+
+// writeScheduledInventory writes a timestamped export plus a stable "latest"
+// copy for CMDB collectors that poll a fixed path
+func (s *Server) writeScheduledInventory(ctx context.Context, config *InventoryConfig) (string, error) {
+	if s.k8sClient == nil {
+		return "", fmt.Errorf("Kubernetes client not available")
+	}
+	inventory, err := s.collectInventory(ctx, s.clusterName(ctx, ""), config.Namespaces)
+	if err != nil {
+		return "", err
+	}
+	data, err := encodeInventory(inventory, config.Format)
+	if err != nil {
+		return "", err
+	}
+
+	dir := config.ExportDir
+	if dir == "" {
+		dir = defaultInventoryDir
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create inventory directory: %v", err)
+	}
+	ext := inventoryExtension(config.Format)
+	path := filepath.Join(dir, fmt.Sprintf("inventory-%s.%s", inventory.GeneratedAt.Format("20060102-150405"), ext))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "inventory-latest."+ext), data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (s *Server) exportInventoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	format := strings.ToLower(mcp.ParseString(request, "format", "json"))
+	outputPath := mcp.ParseString(request, "output_path", "")
+	cluster := s.clusterName(ctx, mcp.ParseString(request, "cluster_name", ""))
+
+	inventory, err := s.collectInventory(ctx, cluster, parseNamespaceList(mcp.ParseString(request, "namespaces", "")))
+	if err != nil {
+		return toolError(ctx, "Failed to collect inventory", err), nil
+	}
+	data, err := encodeInventory(inventory, format)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	unowned := 0
+	for _, workload := range inventory.Workloads {
+		if workload.Owner == "" && workload.Team == "" {
+			unowned++
+		}
+	}
+
+	result := "🗂️  Inventory Export\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("Cluster: %s\n", cluster)
+	result += fmt.Sprintf("Namespaces: %d\n", len(inventory.Namespaces))
+	result += fmt.Sprintf("Workloads: %d\n", len(inventory.Workloads))
+	result += fmt.Sprintf("Images: %d\n", len(inventory.Images))
+	if unowned > 0 {
+		result += fmt.Sprintf("⚠️  Workloads without owner or team metadata: %d\n", unowned)
+	}
+
+	if outputPath != "" {
+		if dir := filepath.Dir(outputPath); dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to create directory for %s: %v", outputPath, err)), nil
+			}
+		}
+		if err := os.WriteFile(outputPath, data, 0644); err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to write inventory: %v", err)), nil
+		}
+		result += fmt.Sprintf("\n✅ %s export written to %s (%d bytes)", strings.ToUpper(inventoryExtension(format)), outputPath, len(data))
+		return mcp.NewToolResultText(result), nil
+	}
+
+	if len(data) > maxInlineInventoryBytes {
+		result += fmt.Sprintf("\n⚠️  Export is %d bytes; showing the first %d. Set output_path to write the full export.\n",
+			len(data), maxInlineInventoryBytes)
+		data = data[:maxInlineInventoryBytes]
+	}
+	result += fmt.Sprintf("\n```%s\n%s\n```", inventoryExtension(format), data)
+	return mcp.NewToolResultText(result), nil
+}
+
+// ExportInventoryHandler is a public wrapper for exportInventoryHandler
+func (s *Server) ExportInventoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.exportInventoryHandler(ctx, request)
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newInventoryTestServer() *Server {
+	replicas := int32(3)
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop",
+			Labels:      map[string]string{"team": "payments"},
+			Annotations: map[string]string{"openshift.io/requester": "alice"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "batch"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"app": "storefront", "owner": "bob"}},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas, Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "migrate", Image: "registry/migrate:1"}},
+				Containers:     []corev1.Container{{Name: "web", Image: "registry/web:2"}},
+			}}},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "db", Image: "registry/web:2"}},
+			}}},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "batch"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "worker", Image: "registry/worker:1"}},
+			}}},
+		},
+	}
+	return &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(objects...)}
+}
+
+func TestCollectInventory(t *testing.T) {
+	s := newInventoryTestServer()
+
+	inventory, err := s.collectInventory(context.Background(), "prod-east", nil)
+	if err != nil {
+		t.Fatalf("collectInventory failed: %v", err)
+	}
+	if len(inventory.Namespaces) != 2 || len(inventory.Workloads) != 3 {
+		t.Fatalf("inventory has %d namespaces and %d workloads, expected 2 and 3", len(inventory.Namespaces), len(inventory.Workloads))
+	}
+
+	tests := []struct {
+		name  string
+		owner string
+		team  string
+		app   string
+	}{
+		{"db", "alice", "payments", ""},
+		{"web", "bob", "payments", "storefront"},
+		{"worker", "", "", ""},
+	}
+	byName := make(map[string]InventoryItem)
+	for _, workload := range inventory.Workloads {
+		byName[workload.Name] = workload
+	}
+	for _, tt := range tests {
+		item := byName[tt.name]
+		if item.Owner != tt.owner || item.Team != tt.team || item.App != tt.app || item.Cluster != "prod-east" {
+			t.Errorf("workload %s = %+v, expected owner %q, team %q, app %q", tt.name, item, tt.owner, tt.team, tt.app)
+		}
+	}
+
+	if len(inventory.Images) != 3 || inventory.Images[1].Image != "registry/web:2" || inventory.Images[1].Workloads != 2 {
+		t.Errorf("inventory images = %+v, expected registry/web:2 used by 2 workloads", inventory.Images)
+	}
+
+	scoped, err := s.collectInventory(context.Background(), "prod-east", []string{"batch"})
+	if err != nil || len(scoped.Namespaces) != 1 || len(scoped.Workloads) != 1 {
+		t.Errorf("collectInventory scoped to batch = %+v, %v, expected one namespace and workload", scoped, err)
+	}
+}
+
+func TestEncodeInventoryCSV(t *testing.T) {
+	inventory, _ := newInventoryTestServer().collectInventory(context.Background(), "prod-east", []string{"shop"})
+
+	data, err := encodeInventory(inventory, "csv")
+	if err != nil {
+		t.Fatalf("encodeInventory failed: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(rows) != 4 || strings.Join(rows[0], ",") != strings.Join(inventoryCSVHeader, ",") {
+		t.Fatalf("CSV rows = %v, expected a header, one namespace and two workloads", rows)
+	}
+	web := rows[2]
+	if web[3] != "web" || web[4] != "3" || web[5] != "registry/migrate:1;registry/web:2" {
+		t.Errorf("CSV row for web = %v", web)
+	}
+
+	if _, err := encodeInventory(inventory, "xml"); err == nil {
+		t.Errorf("encodeInventory(xml) expected an error")
+	}
+}
+
+func TestScheduledInventoryExport(t *testing.T) {
+	dir := t.TempDir()
+	s := newInventoryTestServer()
+	s.scheduler = NewJobScheduler()
+	s.initInventorySchedule(&InventoryConfig{ExportInterval: "24h", ExportDir: dir, ClusterName: "prod-east"})
+
+	if !s.scheduler.RunNow(context.Background(), inventoryJobName) {
+		t.Fatalf("inventory export job was not registered")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "inventory-latest.json"))
+	if err != nil {
+		t.Fatalf("scheduled export did not write inventory-latest.json: %v", err)
+	}
+	var inventory Inventory
+	if err := json.Unmarshal(data, &inventory); err != nil || len(inventory.Workloads) != 3 {
+		t.Errorf("scheduled export = %s, %v", data, err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "inventory-2*.json")); len(files) != 1 {
+		t.Errorf("found %d timestamped exports, expected 1", len(files))
+	}
+	if jobs := s.scheduler.Jobs(); len(jobs) != 1 || jobs[0].Runs != 1 || jobs[0].LastErr != "" {
+		t.Errorf("scheduler jobs = %+v, expected one successful run", jobs)
+	}
+}
+
+func TestExportInventoryHandlerWritesFile(t *testing.T) {
+	s := newInventoryTestServer()
+	path := filepath.Join(t.TempDir(), "out", "inventory.csv")
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"format": "csv", "output_path": path, "cluster_name": "prod-east"}
+	result, _ := s.exportInventoryHandler(context.Background(), request)
+	text := resultText(result)
+	if !strings.Contains(text, "Workloads: 3") || !strings.Contains(text, "✅ CSV export written to "+path) {
+		t.Errorf("export_inventory result = %q", text)
+	}
+	if !strings.Contains(text, "without owner or team metadata: 1") {
+		t.Errorf("export_inventory did not flag the unowned workload: %q", text)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("export file not written: %v", err)
+	}
+}
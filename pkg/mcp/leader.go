@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	defaultLeaseName     = "openshift-mcp-scheduler"
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// LeaderElectionConfig elects one replica, through a coordination.k8s.io
+// Lease, to run scheduled health checks, Git sync, inventory exports and
+// retention cleanup. Every replica keeps serving tools.
+type LeaderElectionConfig struct {
+	Enabled       bool   `json:"enabled"`
+	LeaseName     string `json:"lease_name"`     // default openshift-mcp-scheduler
+	Namespace     string `json:"namespace"`      // default the pod's namespace
+	LeaseDuration string `json:"lease_duration"` // how long a lease is valid without renewal, default 15s
+	RenewDeadline string `json:"renew_deadline"` // how long the leader retries renewal before giving up, default 10s
+	RetryPeriod   string `json:"retry_period"`   // how often candidates try to acquire or renew, default 2s
+}
+
+// leaderState tracks this replica's part in the election
+type leaderState struct {
+	enabled  atomic.Bool
+	leading  atomic.Bool
+	identity string
+	lease    string // namespace/name
+
+	mu     sync.Mutex
+	holder string
+	since  time.Time
+	cancel context.CancelFunc
+}
+
+// isLeader reports whether leader-only jobs run here; without an election
+// every replica is its own leader
+func (l *leaderState) isLeader() bool {
+	return !l.enabled.Load() || l.leading.Load()
+}
+
+func (l *leaderState) setHolder(identity string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder != identity {
+		l.holder = identity
+		l.since = time.Now()
+	}
+}
+
+// currentHolder returns the replica holding the lease and since when
+func (l *leaderState) currentHolder() (string, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.holder, l.since
+}
+
+// leaderIdentity names this replica in the lease: the pod name when set
+// through the downward API, else the hostname, which is the pod name too
+func leaderIdentity() string {
+	if name := os.Getenv("POD_NAME"); name != "" {
+		return name
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+	return fieldManager
+}
+
+// leaseNamespace returns the configured namespace or the pod's own
+func leaseNamespace(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(data)); namespace != "" {
+			return namespace
+		}
+	}
+	return "openshift-mcp"
+}
+
+// parseLeaderDuration parses a configured duration, falling back to def
+func parseLeaderDuration(name, value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logrus.Warnf("Invalid leader election %s %q, using %s", name, value, def)
+		return def
+	}
+	return d
+}
+
+// startLeaderElection campaigns for the scheduler lease until ctx is done,
+// releasing it on the way out so another replica takes over at once. It
+// returns false when the election could not start, in which case this
+// replica runs leader-only jobs itself.
+func (s *Server) startLeaderElection(ctx context.Context) bool {
+	var config *LeaderElectionConfig
+	if s.config != nil {
+		config = s.config.LeaderElection
+	}
+	if config == nil || !config.Enabled {
+		return false
+	}
+	if s.k8sClient == nil {
+		logrus.Warn("Leader election needs a Kubernetes client; this replica runs scheduled jobs itself")
+		return false
+	}
+
+	name := config.LeaseName
+	if name == "" {
+		name = defaultLeaseName
+	}
+	namespace := leaseNamespace(config.Namespace)
+	s.leader.identity = leaderIdentity()
+	s.leader.lease = namespace + "/" + name
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Client:     s.k8sClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: s.leader.identity},
+	}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            name,
+		LeaseDuration:   parseLeaderDuration("lease duration", config.LeaseDuration, defaultLeaseDuration),
+		RenewDeadline:   parseLeaderDuration("renew deadline", config.RenewDeadline, defaultRenewDeadline),
+		RetryPeriod:     parseLeaderDuration("retry period", config.RetryPeriod, defaultRetryPeriod),
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				s.leader.leading.Store(true)
+				logrus.Infof("%s now leads %s and runs scheduled jobs", s.leader.identity, s.leader.lease)
+			},
+			OnStoppedLeading: func() {
+				s.leader.leading.Store(false)
+				logrus.Infof("%s stopped leading %s", s.leader.identity, s.leader.lease)
+			},
+			OnNewLeader: func(identity string) {
+				s.leader.setHolder(identity)
+				if identity != s.leader.identity {
+					logrus.Infof("%s leads %s; scheduled jobs run there", identity, s.leader.lease)
+				}
+			},
+		},
+	})
+	if err != nil {
+		logrus.WithError(err).Warn("Leader election disabled; this replica runs scheduled jobs itself")
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.leader.mu.Lock()
+	s.leader.cancel = cancel
+	s.leader.mu.Unlock()
+	s.leader.enabled.Store(true)
+	go func() {
+		// Run returns when leadership is lost; campaign again until stopped
+		for ctx.Err() == nil {
+			elector.Run(ctx)
+		}
+	}()
+	logrus.Infof("Campaigning for %s as %s", s.leader.lease, s.leader.identity)
+	return true
+}
+
+// stopLeaderElection releases the lease if this replica holds it
+func (s *Server) stopLeaderElection() {
+	s.leader.mu.Lock()
+	cancel := s.leader.cancel
+	s.leader.cancel = nil
+	s.leader.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// describeLeadership summarizes the election for status output
+func (s *Server) describeLeadership(ctx context.Context) string {
+	if !s.leader.enabled.Load() {
+		return "👑 Leader election: off, scheduled jobs run on this replica\n"
+	}
+	holder, since := s.leader.currentHolder()
+	if s.leader.leading.Load() {
+		return fmt.Sprintf("👑 Leader election: this replica (%s) leads %s since %s and runs scheduled jobs\n",
+			s.leader.identity, s.leader.lease, formatTime(ctx, since))
+	}
+	if holder == "" {
+		holder = "no replica yet"
+	}
+	return fmt.Sprintf("👑 Leader election: %s leads %s; leader-only jobs skip this replica (%s)\n", holder, s.leader.lease, s.leader.identity)
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLeaderElectionFailover(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	election := &LeaderElectionConfig{Enabled: true, Namespace: "openshift-mcp", LeaseDuration: "400ms", RenewDeadline: "300ms", RetryPeriod: "20ms"}
+	replica := func(name string) *Server {
+		t.Setenv("POD_NAME", name)
+		s := &Server{config: &Config{LeaderElection: election}, k8sClient: client, scheduler: NewJobScheduler()}
+		s.scheduler.SetLeaderCheck(s.leader.isLeader)
+		s.StartScheduler(context.Background())
+		return s
+	}
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	first := replica("mcp-0")
+	waitFor("mcp-0 to lead", first.leader.isLeader)
+	second := replica("mcp-1")
+	defer second.StopScheduler()
+	waitFor("mcp-1 to see mcp-0 lead", func() bool { holder, _ := second.leader.currentHolder(); return holder == "mcp-0" })
+	if second.leader.isLeader() {
+		t.Fatal("both replicas lead")
+	}
+	utc := WithTimePreferences(context.Background(), TimePreferences{Location: time.UTC, Format: TimeFormatRFC3339})
+	if text := first.describeLeadership(utc); !strings.Contains(text, "this replica (mcp-0) leads openshift-mcp/openshift-mcp-scheduler since ") || !strings.Contains(text, "Z and runs scheduled jobs") {
+		t.Errorf("describeLeadership() on the leader = %q, expected the lease time in the request's format", text)
+	}
+	if text := second.describeLeadership(context.Background()); !strings.Contains(text, "mcp-0 leads openshift-mcp/openshift-mcp-scheduler; leader-only jobs skip this replica (mcp-1)") {
+		t.Errorf("describeLeadership() on the follower = %q", text)
+	}
+	lease, err := client.CoordinationV1().Leases("openshift-mcp").Get(context.Background(), defaultLeaseName, metav1.GetOptions{})
+	if err != nil || lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "mcp-0" {
+		t.Fatalf("Lease = %+v, %v, expected mcp-0 to hold it", lease, err)
+	}
+
+	// Stopping releases the lease, so the other replica takes over at once
+	first.StopScheduler()
+	waitFor("mcp-1 to take over", second.leader.isLeader)
+	if first.leader.isLeader() {
+		t.Error("the stopped replica still leads")
+	}
+}
+
+func TestLeaderElectionOff(t *testing.T) {
+	s := &Server{config: &Config{LeaderElection: &LeaderElectionConfig{Enabled: true}}, scheduler: NewJobScheduler()}
+	if s.startLeaderElection(context.Background()) || !s.leader.isLeader() {
+		t.Error("without a Kubernetes client the replica should run scheduled jobs itself")
+	}
+	if text := s.describeLeadership(context.Background()); !strings.Contains(text, "off, scheduled jobs run on this replica") {
+		t.Errorf("describeLeadership() = %q", text)
+	}
+}
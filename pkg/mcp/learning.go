@@ -0,0 +1,412 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// runbookDraftDir holds learned runbooks under the tool set directory
+	// until they are reviewed; reload_tool_sets skips subdirectories, so a
+	// draft is registered only once it is moved up into the directory
+	runbookDraftDir = "drafts"
+	// defaultRunbookDraftDir is used under the diagnostics directory when no
+	// tool set directory is configured
+	defaultRunbookDraftDir = "runbook-drafts"
+	// runbookMatchThreshold is the similarity below which a runbook is not
+	// offered for a prompt
+	runbookMatchThreshold = 0.25
+)
+
+// unlearnedTools read the chat session itself rather than the cluster, so
+// they make no sense in a runbook
+var unlearnedTools = map[string]bool{
+	"get_step_output": true,
+}
+
+// LearnedStep is a successful tool call of a resolved chat session
+type LearnedStep struct {
+	Tool      string
+	Arguments map[string]interface{}
+}
+
+// LearnedSession is a chat session the user confirmed resolved its incident
+type LearnedSession struct {
+	SessionID   string
+	Incident    string // the prompt that opened the session
+	Name        string // runbook tool name, derived from the incident when empty
+	Description string
+	Steps       []LearnedStep
+}
+
+// LearnedRunbook is the runbook draft distilled from a resolved session.
+// A failed commit is reported in GitError, since the draft itself was saved.
+type LearnedRunbook struct {
+	Name       string   `json:"name"`
+	Path       string   `json:"path"`
+	GitPath    string   `json:"git_path,omitempty"`
+	GitError   string   `json:"git_error,omitempty"`
+	Parameters []string `json:"parameters"`
+	Steps      []string `json:"steps"`
+	Skipped    int      `json:"skipped_steps,omitempty"` // failed, repeated or session-only calls left out
+	Draft      string   `json:"draft"`
+}
+
+// runbookDrafts returns where learned runbooks are drafted
+func (s *Server) runbookDrafts() string {
+	if dir := s.toolSetDir(); dir != "" {
+		return filepath.Join(dir, runbookDraftDir)
+	}
+	return filepath.Join(s.diagnosticsBaseDir(), defaultRunbookDraftDir)
+}
+
+// parameterizedArgument reports whether an argument names one of the
+// incident's resources, which becomes a runbook parameter instead of a
+// fixed value
+func parameterizedArgument(name string) bool {
+	switch name {
+	case "namespace", "name", "pod", "node", "service", "container", "label_selector":
+		return true
+	}
+	return strings.HasSuffix(name, "_name")
+}
+
+// learnedValue renders an argument of a recorded call as a runbook value
+func learnedValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// literalArgument keeps a fixed value from being read as a template
+func literalArgument(value string) string {
+	if strings.Contains(value, "{{") {
+		return "{{ " + strconv.Quote(value) + " }}"
+	}
+	return value
+}
+
+var runbookWord = regexp.MustCompile(`[a-z0-9]+`)
+
+// runbookStopWords carry no meaning for naming or matching runbooks
+var runbookStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "at": true, "be": true, "by": true, "can": true,
+	"check": true, "do": true, "does": true, "for": true, "from": true, "help": true, "how": true,
+	"i": true, "in": true, "is": true, "it": true, "its": true, "me": true, "my": true, "not": true,
+	"of": true, "on": true, "or": true, "our": true, "please": true, "show": true, "the": true,
+	"this": true, "that": true, "to": true, "was": true, "we": true, "what": true, "why": true, "with": true,
+}
+
+// runbookName derives a tool name from the first words of an incident
+func runbookName(incident string) string {
+	var words []string
+	for _, word := range runbookWord.FindAllString(strings.ToLower(incident), -1) {
+		if !runbookStopWords[word] {
+			words = append(words, word)
+		}
+		if len(words) == 5 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return "learned_runbook"
+	}
+	name := strings.Join(words, "_")
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "runbook_" + name
+	}
+	return name
+}
+
+// distillRunbook turns the successful calls of a session into a runbook
+// tool set of one tool. Values naming the incident's resources become
+// parameters, shared by every step using the same value; a call repeating
+// the previous one is dropped.
+func (s *Server) distillRunbook(session LearnedSession) (RunbookToolSet, int, error) {
+	name := session.Name
+	if name == "" {
+		name = runbookName(session.Incident)
+	}
+	if !toolNamePattern.MatchString(name) {
+		return RunbookToolSet{}, 0, fmt.Errorf("invalid runbook name %q: use lowercase letters, digits and underscores", name)
+	}
+	description := session.Description
+	if description == "" {
+		description = "Steps that resolved: " + strings.TrimSpace(session.Incident)
+	}
+
+	tool := RunbookTool{Name: name, Description: description}
+	params := make(map[string]string) // value → parameter
+	used := make(map[string]bool)
+	skipped := 0
+	var previous string
+	for _, step := range session.Steps {
+		s.toolsMu.RLock()
+		_, registered := s.toolDefs[step.Tool]
+		set := s.toolSetOf(step.Tool)
+		s.toolsMu.RUnlock()
+		if !registered || set != "" || unlearnedTools[step.Tool] {
+			skipped++
+			continue
+		}
+
+		args := make(map[string]string)
+		for _, key := range sortedKeys(step.Arguments) {
+			value := learnedValue(step.Arguments[key])
+			if value == "" || key == freezeOverrideParam {
+				continue
+			}
+			if _, isString := step.Arguments[key].(string); !isString || !parameterizedArgument(key) {
+				args[key] = literalArgument(value)
+				continue
+			}
+			param, ok := params[value]
+			if !ok {
+				param = key
+				for i := 2; used[param]; i++ {
+					param = fmt.Sprintf("%s_%d", key, i)
+				}
+				used[param] = true
+				params[value] = param
+				tool.Parameters = append(tool.Parameters, CatalogParameter{
+					Name:        param,
+					Description: fmt.Sprintf("%s, e.g. %s", strings.ReplaceAll(key, "_", " "), value),
+					Required:    true,
+				})
+			}
+			args[key] = "{{ ." + param + " }}"
+		}
+
+		call := step.Tool + fmt.Sprint(args)
+		if call == previous {
+			skipped++
+			continue
+		}
+		previous = call
+		step := RunbookStep{Tool: step.Tool}
+		if len(args) > 0 {
+			step.Arguments = args
+		}
+		tool.Steps = append(tool.Steps, step)
+	}
+	if len(tool.Steps) == 0 {
+		return RunbookToolSet{}, skipped, fmt.Errorf("the session made no successful calls of the profile's tools to learn from")
+	}
+	return RunbookToolSet{Name: name, Incident: strings.TrimSpace(session.Incident), Tools: []RunbookTool{tool}}, skipped, nil
+}
+
+// LearnRunbook drafts a runbook from a resolved session in the drafts
+// directory, commits it to the Git repository for review and indexes it for
+// planning similar incidents
+func (s *Server) LearnRunbook(ctx context.Context, session LearnedSession) (LearnedRunbook, error) {
+	definition, skipped, err := s.distillRunbook(session)
+	if err != nil {
+		return LearnedRunbook{}, err
+	}
+	name := definition.Name
+	if s.HasTool(name) {
+		return LearnedRunbook{}, fmt.Errorf("a tool named %s already exists; choose another name", name)
+	}
+	path := filepath.Join(s.runbookDrafts(), name+".yaml")
+	if _, err := os.Stat(path); err == nil {
+		return LearnedRunbook{}, fmt.Errorf("a runbook draft named %s already exists; choose another name", name)
+	}
+
+	data, err := yaml.Marshal(definition)
+	if err != nil {
+		return LearnedRunbook{}, err
+	}
+	// The draft must register once promoted
+	if _, err := parseRunbookToolSet(name+".yaml", data); err != nil {
+		return LearnedRunbook{}, fmt.Errorf("invalid runbook draft: %v", err)
+	}
+	if _, err := s.runbookServerTools(definition); err != nil {
+		return LearnedRunbook{}, fmt.Errorf("invalid runbook draft: %v", err)
+	}
+	draft := fmt.Sprintf("# Runbook learned from chat session %s on %s.\n", session.SessionID, time.Now().UTC().Format("2006-01-02"))
+	draft += "# Review the steps and parameters, then move this file to the tool set\n"
+	draft += "# directory and call reload_tool_sets to register it.\n"
+	draft += string(data)
+
+	if err := os.MkdirAll(s.runbookDrafts(), 0755); err != nil {
+		return LearnedRunbook{}, fmt.Errorf("failed to create runbook drafts directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(draft), 0644); err != nil {
+		return LearnedRunbook{}, fmt.Errorf("failed to write runbook draft: %w", err)
+	}
+
+	learned := LearnedRunbook{Name: name, Path: path, Skipped: skipped, Draft: draft}
+	for _, parameter := range definition.Tools[0].Parameters {
+		learned.Parameters = append(learned.Parameters, parameter.Name)
+	}
+	for _, step := range definition.Tools[0].Steps {
+		learned.Steps = append(learned.Steps, step.Tool)
+	}
+	if s.gitManager == nil {
+		learned.GitError = ErrGitDisabled.Error()
+	} else {
+		gitPath, err := s.gitManager.SaveRunbookDraft(ctx, name+".yaml", draft, "runbook "+name+" learned from chat session "+session.SessionID)
+		learned.GitPath = gitPath
+		if err != nil {
+			learned.GitError = err.Error()
+		}
+	}
+	s.indexRunbooks()
+	logrus.WithFields(logrus.Fields{"runbook": name, "session": session.SessionID, "steps": learned.Steps}).Info("Learned runbook draft")
+	return learned, nil
+}
+
+// RunbookMatch is a runbook whose incident resembles a prompt
+type RunbookMatch struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Incident    string   `json:"incident,omitempty"`
+	Steps       []string `json:"steps"`
+	Draft       bool     `json:"draft"` // not reviewed yet, so not a registered tool
+	Path        string   `json:"path"`
+	Score       float64  `json:"score"`
+}
+
+// indexedRunbook is a runbook with its term vector
+type indexedRunbook struct {
+	RunbookMatch
+	terms map[string]float64
+	norm  float64
+}
+
+// runbookIndex retrieves the runbooks of resolved incidents resembling a
+// new prompt by the cosine similarity of their terms. It covers the
+// registered runbook tool sets and the drafts awaiting review.
+type runbookIndex struct {
+	mu      sync.RWMutex
+	entries []indexedRunbook
+}
+
+// runbookTerms counts the meaningful words of a text, folding plurals
+func runbookTerms(text string) map[string]float64 {
+	terms := make(map[string]float64)
+	for _, word := range runbookWord.FindAllString(strings.ToLower(text), -1) {
+		if len(word) < 2 || runbookStopWords[word] {
+			continue
+		}
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = strings.TrimSuffix(word, "s")
+		}
+		terms[word]++
+	}
+	return terms
+}
+
+func termNorm(terms map[string]float64) float64 {
+	var sum float64
+	for _, count := range terms {
+		sum += count * count
+	}
+	return math.Sqrt(sum)
+}
+
+// indexRunbooks rebuilds the index from the tool set and drafts directories
+func (s *Server) indexRunbooks() {
+	var entries []indexedRunbook
+	dirs := []struct {
+		path  string
+		draft bool
+	}{{s.toolSetDir(), false}, {s.runbookDrafts(), true}}
+	for _, dir := range dirs {
+		if dir.path == "" {
+			continue
+		}
+		files, err := os.ReadDir(dir.path)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			switch filepath.Ext(file.Name()) {
+			case ".yaml", ".yml", ".json":
+			default:
+				continue
+			}
+			path := filepath.Join(dir.path, file.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			definition, err := parseRunbookToolSet(file.Name(), data)
+			if err != nil {
+				continue
+			}
+			for _, tool := range definition.Tools {
+				description := tool.Description
+				if description == "" {
+					description = definition.Description
+				}
+				match := RunbookMatch{Name: tool.Name, Description: description, Incident: definition.Incident, Draft: dir.draft, Path: path}
+				for _, step := range tool.Steps {
+					match.Steps = append(match.Steps, step.Tool)
+				}
+				terms := runbookTerms(strings.Join([]string{definition.Incident, description, strings.ReplaceAll(tool.Name, "_", " ")}, " "))
+				if norm := termNorm(terms); norm > 0 {
+					entries = append(entries, indexedRunbook{RunbookMatch: match, terms: terms, norm: norm})
+				}
+			}
+		}
+	}
+	s.runbooks.mu.Lock()
+	s.runbooks.entries = entries
+	s.runbooks.mu.Unlock()
+}
+
+// SimilarRunbooks returns up to limit runbooks whose incidents resemble a
+// prompt, most similar first
+func (s *Server) SimilarRunbooks(prompt string, limit int) []RunbookMatch {
+	terms := runbookTerms(prompt)
+	norm := termNorm(terms)
+	if norm == 0 {
+		return nil
+	}
+	s.runbooks.mu.RLock()
+	defer s.runbooks.mu.RUnlock()
+	var matches []RunbookMatch
+	for _, entry := range s.runbooks.entries {
+		var dot float64
+		for term, count := range terms {
+			dot += count * entry.terms[term]
+		}
+		if score := dot / (norm * entry.norm); score >= runbookMatchThreshold {
+			match := entry.RunbookMatch
+			match.Score = score
+			matches = append(matches, match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunbookName(t *testing.T) {
+	tests := map[string]string{
+		"Why are the cart pods crashing in the shop namespace?": "cart_pods_crashing_shop_namespace",
+		"503 on checkout route":                                 "runbook_503_checkout_route",
+		"why?":                                                  "learned_runbook",
+	}
+	for incident, expected := range tests {
+		if name := runbookName(incident); name != expected {
+			t.Errorf("runbookName(%q) = %s, expected %s", incident, name, expected)
+		}
+	}
+}
+
+func TestLearnRunbook(t *testing.T) {
+	dir := t.TempDir()
+	s := newPolicyTestServer(false)
+	s.config.ToolSetDir = dir
+
+	session := LearnedSession{
+		SessionID: "chat-1",
+		Incident:  "Cart pods crashing in shop",
+		Steps: []LearnedStep{
+			{Tool: "list_pods", Arguments: map[string]interface{}{"namespace": "shop", "label_selector": "app=cart"}},
+			{Tool: "list_pods", Arguments: map[string]interface{}{"namespace": "shop", "label_selector": "app=cart"}},
+			{Tool: "get_step_output", Arguments: map[string]interface{}{"step_id": "plan-1-step-1"}},
+			{Tool: "delete_resource", Arguments: map[string]interface{}{"namespace": "shop", "resource_type": "pod", "resource_name": "cart-1", "grace_period": float64(0)}},
+		},
+	}
+	learned, err := s.LearnRunbook(context.Background(), session)
+	if err != nil {
+		t.Fatalf("LearnRunbook() error = %v", err)
+	}
+	if learned.Name != "cart_pods_crashing_shop" || learned.Path != filepath.Join(dir, runbookDraftDir, "cart_pods_crashing_shop.yaml") || learned.Skipped != 2 {
+		t.Errorf("LearnRunbook() = %+v", learned)
+	}
+	if !reflect.DeepEqual(learned.Parameters, []string{"label_selector", "namespace", "resource_name"}) || !reflect.DeepEqual(learned.Steps, []string{"list_pods", "delete_resource"}) {
+		t.Errorf("parameters %v and steps %v", learned.Parameters, learned.Steps)
+	}
+	if learned.GitError != ErrGitDisabled.Error() {
+		t.Errorf("GitError = %q without a Git repository", learned.GitError)
+	}
+	for _, want := range []string{
+		"# Runbook learned from chat session chat-1",
+		"incident: Cart pods crashing in shop",
+		"namespace: '{{ .namespace }}'",
+		"resource_name: '{{ .resource_name }}'",
+		"resource_type: pod",
+		`grace_period: "0"`,
+	} {
+		if !strings.Contains(learned.Draft, want) {
+			t.Errorf("draft missing %q:\n%s", want, learned.Draft)
+		}
+	}
+	if _, err := s.LearnRunbook(context.Background(), session); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("learning the same runbook again: error = %v", err)
+	}
+	if _, err := s.LearnRunbook(context.Background(), LearnedSession{Incident: "nothing", Steps: []LearnedStep{{Tool: "get_step_output"}}}); err == nil {
+		t.Error("LearnRunbook() learned a session without cluster calls")
+	}
+
+	// Drafts are indexed but only registered once promoted
+	matches := s.SimilarRunbooks("the cart pods keep crashing", 3)
+	if len(matches) != 1 || matches[0].Name != "cart_pods_crashing_shop" || !matches[0].Draft {
+		t.Fatalf("SimilarRunbooks() = %+v", matches)
+	}
+	if matches := s.SimilarRunbooks("rotate the etcd certificates", 3); len(matches) != 0 {
+		t.Errorf("SimilarRunbooks() matched an unrelated prompt: %+v", matches)
+	}
+	if reload, err := s.reloadToolSets(); err != nil || len(reload.registered) != 0 {
+		t.Errorf("reloadToolSets() registered the draft: %+v, %v", reload, err)
+	}
+
+	if err := os.Rename(learned.Path, filepath.Join(dir, "cart_pods_crashing_shop.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if reload, err := s.reloadToolSets(); err != nil || !reflect.DeepEqual(reload.registered, []string{"cart_pods_crashing_shop"}) {
+		t.Fatalf("reloadToolSets() after promoting the draft = %+v, %v", reload, err)
+	}
+	if matches := s.SimilarRunbooks("cart pods crashing", 3); len(matches) != 1 || matches[0].Draft {
+		t.Errorf("SimilarRunbooks() after promoting = %+v", matches)
+	}
+}
@@ -0,0 +1,436 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	monitoringNamespace      = "openshift-monitoring"
+	clusterMonitoringConfig  = "cluster-monitoring-config"
+	thanosQuerierService     = "thanos-querier"
+	serviceCAFile            = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+	defaultMetricsSeries     = 50
+	metricsQueryTimeout      = 30 * time.Second
+	metricsResponseSizeLimit = 8 << 20
+)
+
+// MonitoringConfig overrides the discovered Thanos querier endpoints, e.g.
+// when the server runs outside the cluster and cannot reach service DNS
+type MonitoringConfig struct {
+	QuerierURL         string `json:"querier_url"`          // cluster-wide querier, needs cluster-monitoring-view
+	TenancyURL         string `json:"tenancy_url"`          // namespace-scoped querier behind the tenancy proxy
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"` // skip TLS verification of the querier
+
+	// Alertmanager endpoints for collect_alerts, discovered like the querier
+	AlertmanagerURL        string `json:"alertmanager_url"`
+	AlertmanagerTenancyURL string `json:"alertmanager_tenancy_url"`
+
+	// SnapshotQueries are the PromQL range queries collect_metrics_snapshot
+	// captures, by name; $namespace is replaced by the snapshot's namespace
+	SnapshotQueries map[string]string `json:"snapshot_queries"`
+}
+
+// metricsEndpoint is where a PromQL query is sent. A tenancy endpoint only
+// answers for the namespace passed with the query, which it enforces as a
+// namespace label matcher, so namespace viewers can query it.
+type metricsEndpoint struct {
+	URL     string
+	Tenancy bool
+	Source  string
+}
+
+// promResponse is the Prometheus HTTP API envelope of an instant query
+type promResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+	Warnings []string `json:"warnings"`
+}
+
+type promSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+func (s *Server) monitoringConfig() MonitoringConfig {
+	if s.config != nil && s.config.Monitoring != nil {
+		return *s.config.Monitoring
+	}
+	return MonitoringConfig{}
+}
+
+// userWorkloadMonitoringEnabled reads enableUserWorkload from the cluster
+// monitoring config
+func (s *Server) userWorkloadMonitoringEnabled(ctx context.Context) (bool, error) {
+	configMap, err := s.k8sClient.CoreV1().ConfigMaps(monitoringNamespace).Get(ctx, clusterMonitoringConfig, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	var config struct {
+		EnableUserWorkload bool `json:"enableUserWorkload"`
+	}
+	if err := yaml.Unmarshal([]byte(configMap.Data["config.yaml"]), &config); err != nil {
+		return false, fmt.Errorf("parsing %s/%s: %w", monitoringNamespace, clusterMonitoringConfig, err)
+	}
+	return config.EnableUserWorkload, nil
+}
+
+// discoverMetricsEndpoint picks the querier for a query: the tenancy port of
+// the Thanos querier for namespaced queries, otherwise its route or web port
+func (s *Server) discoverMetricsEndpoint(ctx context.Context, namespace string) (metricsEndpoint, error) {
+	config := s.monitoringConfig()
+	return s.discoverMonitoringEndpoint(ctx, thanosQuerierService, namespace, config.TenancyURL, config.QuerierURL)
+}
+
+// discoverMonitoringEndpoint finds a monitoring service in
+// openshift-monitoring that sits behind a tenancy proxy: its tenancy port for
+// namespaced requests, otherwise its route or web port. The configured URLs
+// win over discovery.
+func (s *Server) discoverMonitoringEndpoint(ctx context.Context, serviceName, namespace, tenancyURL, clusterURL string) (metricsEndpoint, error) {
+	if namespace != "" && tenancyURL != "" {
+		return metricsEndpoint{URL: strings.TrimRight(tenancyURL, "/"), Tenancy: true, Source: "configured tenancy URL"}, nil
+	}
+	if namespace == "" && clusterURL != "" {
+		return metricsEndpoint{URL: strings.TrimRight(clusterURL, "/"), Source: "configured URL"}, nil
+	}
+	if s.k8sClient == nil {
+		return metricsEndpoint{}, fmt.Errorf("Kubernetes client not available")
+	}
+
+	service, err := s.k8sClient.CoreV1().Services(monitoringNamespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return metricsEndpoint{}, fmt.Errorf("service %s/%s not found; is cluster monitoring installed?", monitoringNamespace, serviceName)
+		}
+		return metricsEndpoint{}, err
+	}
+	ports := map[string]int32{}
+	for _, port := range service.Spec.Ports {
+		ports[port.Name] = port.Port
+	}
+	serviceURL := func(port int32) string {
+		return fmt.Sprintf("https://%s.%s.svc:%d", serviceName, monitoringNamespace, port)
+	}
+
+	if namespace != "" {
+		if port, ok := ports["tenancy"]; ok {
+			return metricsEndpoint{URL: serviceURL(port), Tenancy: true, Source: "service " + serviceName + " tenancy port"}, nil
+		}
+		return metricsEndpoint{}, fmt.Errorf("service %s/%s has no tenancy port", monitoringNamespace, serviceName)
+	}
+
+	if s.dynamicClient != nil {
+		route, err := s.dynamicClient.Resource(routesGVR).Namespace(monitoringNamespace).Get(ctx, serviceName, metav1.GetOptions{})
+		if err == nil {
+			if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "" {
+				return metricsEndpoint{URL: "https://" + host, Source: "route " + serviceName}, nil
+			}
+		}
+	}
+	if port, ok := ports["web"]; ok {
+		return metricsEndpoint{URL: serviceURL(port), Source: "service " + serviceName + " web port"}, nil
+	}
+	return metricsEndpoint{}, fmt.Errorf("service %s/%s has no web port", monitoringNamespace, serviceName)
+}
+
+// metricsToken returns the bearer token the querier's OAuth proxy checks
+func (s *Server) metricsToken() string {
+	if s.restConfig == nil {
+		return ""
+	}
+	if s.restConfig.BearerToken != "" {
+		return s.restConfig.BearerToken
+	}
+	if s.restConfig.BearerTokenFile != "" {
+		if token, err := os.ReadFile(s.restConfig.BearerTokenFile); err == nil {
+			return strings.TrimSpace(string(token))
+		}
+	}
+	return ""
+}
+
+// metricsHTTPClient trusts the service CA, which signs the querier's
+// certificate, in addition to the system roots
+func (s *Server) metricsHTTPClient() *http.Client {
+	tlsConfig := &tls.Config{InsecureSkipVerify: s.monitoringConfig().InsecureSkipVerify}
+	if pem, err := os.ReadFile(serviceCAFile); err == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(pem)
+		tlsConfig.RootCAs = pool
+	}
+	return &http.Client{Timeout: metricsQueryTimeout, Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}
+}
+
+// queryMetrics runs an instant PromQL query. Tenancy endpoints get the
+// namespace as a parameter and scope every selector to it.
+func (s *Server) queryMetrics(ctx context.Context, endpoint metricsEndpoint, query, namespace string) (*promResponse, error) {
+	return s.promGet(ctx, endpoint, "/api/v1/query", url.Values{"query": {query}}, namespace)
+}
+
+// queryMetricsRange runs a PromQL range query and returns its series
+func (s *Server) queryMetricsRange(ctx context.Context, endpoint metricsEndpoint, query, namespace string, start, end time.Time, step time.Duration) ([]diagnostics.MetricSeries, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {strconv.FormatInt(start.Unix(), 10)},
+		"end":   {strconv.FormatInt(end.Unix(), 10)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	response, err := s.promGet(ctx, endpoint, "/api/v1/query_range", params, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return parsePromMatrix(response)
+}
+
+// parsePromMatrix converts a range query result into series
+func parsePromMatrix(response *promResponse) ([]diagnostics.MetricSeries, error) {
+	if response.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("expected a matrix result, got %q", response.Data.ResultType)
+	}
+	var raw []struct {
+		Metric map[string]string `json:"metric"`
+		Values [][2]interface{}  `json:"values"`
+	}
+	if err := json.Unmarshal(response.Data.Result, &raw); err != nil {
+		return nil, err
+	}
+	series := make([]diagnostics.MetricSeries, 0, len(raw))
+	for _, r := range raw {
+		entry := diagnostics.MetricSeries{Metric: r.Metric}
+		for _, sample := range r.Values {
+			seconds, _ := sample[0].(float64)
+			text, _ := sample[1].(string)
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				continue
+			}
+			entry.Values = append(entry.Values, diagnostics.MetricPoint{
+				Time:  time.Unix(0, int64(seconds*float64(time.Second))).UTC(),
+				Value: value,
+			})
+		}
+		series = append(series, entry)
+	}
+	sort.SliceStable(series, func(i, j int) bool { return formatMetric(series[i].Metric) < formatMetric(series[j].Metric) })
+	return series, nil
+}
+
+// promGet calls a Prometheus HTTP API path on the querier
+func (s *Server) promGet(ctx context.Context, endpoint metricsEndpoint, path string, params url.Values, namespace string) (*promResponse, error) {
+	body, status, err := s.monitoringGet(ctx, endpoint, path, params, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var result promResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, monitoringStatusError("querier", status, body, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("%s: %s", valueOrNone(result.ErrorType), result.Error)
+	}
+	return &result, nil
+}
+
+// monitoringGet calls a path on a monitoring endpoint with the server's
+// credentials. Tenancy endpoints get the namespace as a parameter and scope
+// the request to it.
+func (s *Server) monitoringGet(ctx context.Context, endpoint metricsEndpoint, path string, params url.Values, namespace string) ([]byte, int, error) {
+	if endpoint.Tenancy {
+		params.Set("namespace", namespace)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.URL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if token := s.metricsToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.metricsHTTPClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, metricsResponseSizeLimit))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// monitoringStatusError explains a response body that did not parse
+func monitoringStatusError(what string, status int, body []byte, err error) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s refused the server's credentials (HTTP %d)", what, status)
+	case http.StatusOK:
+		return fmt.Errorf("unreadable %s response: %v", what, err)
+	}
+	return fmt.Errorf("%s returned HTTP %d: %s", what, status, strings.TrimSpace(string(body)))
+}
+
+// formatMetric renders a series' labels the PromQL way
+func formatMetric(metric map[string]string) string {
+	name := metric["__name__"]
+	var labels []string
+	for _, key := range sortedKeys(metric) {
+		if key != "__name__" {
+			labels = append(labels, fmt.Sprintf("%s=%q", key, metric[key]))
+		}
+	}
+	return name + "{" + strings.Join(labels, ", ") + "}"
+}
+
+// formatPromResult renders an instant query result, at most limit series
+func formatPromResult(response *promResponse, limit int) (string, error) {
+	switch response.Data.ResultType {
+	case "vector":
+		var samples []promSample
+		if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return "", err
+		}
+		if len(samples) == 0 {
+			return "📭 No series matched the query\n", nil
+		}
+		sort.SliceStable(samples, func(i, j int) bool { return formatMetric(samples[i].Metric) < formatMetric(samples[j].Metric) })
+		result := fmt.Sprintf("📈 %d series:\n", len(samples))
+		for i, sample := range samples {
+			if i == limit {
+				result += fmt.Sprintf("... %d more series not shown; narrow the query or raise limit\n", len(samples)-limit)
+				break
+			}
+			result += fmt.Sprintf("• %s = %v\n", formatMetric(sample.Metric), sample.Value[1])
+		}
+		return result, nil
+	case "scalar", "string":
+		var value [2]interface{}
+		if err := json.Unmarshal(response.Data.Result, &value); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("📈 %s: %v\n", response.Data.ResultType, value[1]), nil
+	}
+	return "", fmt.Errorf("unsupported result type %q", response.Data.ResultType)
+}
+
+func (s *Server) initMonitoring() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("query_metrics",
+			mcp.WithDescription("Run an instant PromQL query against OpenShift monitoring; with a namespace the query goes through the tenancy-aware Thanos querier, so only namespace view access is needed and user workload metrics are included when user workload monitoring is enabled"),
+			mcp.WithString("query", mcp.Description("PromQL expression, e.g. sum(rate(http_requests_total[5m])) by (pod)"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Scope the query to this namespace (recommended; required without cluster-monitoring-view)")),
+			mcp.WithString("limit", mcp.Description(fmt.Sprintf("Maximum series to show (default %d)", defaultMetricsSeries))),
+			mcp.WithTitleAnnotation("Monitoring: Query Metrics"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.queryMetricsHandler)},
+		{Tool: mcp.NewTool("collect_metrics_snapshot",
+			mcp.WithDescription("Capture PromQL range queries (by default CPU, memory, restarts and CPU throttling per pod) over a time window and store them as a diagnostics artifact; spikes in the snapshot join the analyze_logs and analyze_must_gather timeline so crashes can be correlated with the CPU or memory spikes before them"),
+			mcp.WithString("namespace", mcp.Description("Scope the queries to this namespace through the tenancy-aware querier (recommended)")),
+			mcp.WithString("queries", mcp.Description("PromQL queries to capture instead of the configured ones, one per line, optionally named as 'name: query'; $namespace is replaced by the namespace")),
+			mcp.WithString("since", mcp.Description("How far back the window reaches (default 1h, at most 168h)")),
+			mcp.WithString("end", mcp.Description("End of the window as an RFC 3339 time (default now), e.g. shortly after a crash")),
+			mcp.WithString("step", mcp.Description("Resolution of the samples (default the window divided into 120 points, at least 15s)")),
+			mcp.WithString("output_dir", mcp.Description("Directory for metrics.json, e.g. a log collection's directory so analysis finds both")),
+			mcp.WithTitleAnnotation("Monitoring: Collect Metrics Snapshot"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.collectMetricsSnapshotHandler)},
+	}
+}
+
+func (s *Server) queryMetricsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := strings.TrimSpace(mcp.ParseString(request, "query", ""))
+	if query == "" {
+		return mcp.NewToolResultText("❌ query is required"), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	limit := defaultMetricsSeries
+	if value := mcp.ParseString(request, "limit", ""); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid limit '%s': expected a positive number", value)), nil
+		}
+		limit = parsed
+	}
+
+	endpoint, err := s.discoverMetricsEndpoint(ctx, namespace)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Cannot find a metrics querier: %v", err)), nil
+	}
+
+	result := "📊 Metrics Query\n"
+	result += "================\n\n"
+	result += fmt.Sprintf("Query: %s\n", query)
+	if endpoint.Tenancy {
+		result += fmt.Sprintf("Scope: namespace %s (enforced by the tenancy proxy)\n", namespace)
+	} else {
+		result += "Scope: cluster-wide\n"
+	}
+	result += fmt.Sprintf("Querier: %s (%s)\n", endpoint.URL, endpoint.Source)
+
+	// Without user workload monitoring only platform metrics exist for apps
+	if namespace != "" && s.k8sClient != nil {
+		if enabled, err := s.userWorkloadMonitoringEnabled(ctx); err != nil {
+			logrus.WithError(err).Debug("Could not read the cluster monitoring config")
+		} else if !enabled {
+			result += "ℹ️  User workload monitoring is disabled; only platform metrics such as container CPU and memory are available\n"
+		}
+	}
+	result += "\n"
+
+	response, err := s.queryMetrics(ctx, endpoint, query, namespace)
+	if err != nil {
+		result += fmt.Sprintf("❌ Query failed: %v\n", err)
+		if !endpoint.Tenancy {
+			result += "💡 Cluster-wide queries need the cluster-monitoring-view role; pass namespace to query through the tenancy proxy"
+		}
+		return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+	}
+	formatted, err := formatPromResult(response, limit)
+	if err != nil {
+		return mcp.NewToolResultText(result + fmt.Sprintf("❌ %v", err)), nil
+	}
+	result += formatted
+	for _, warning := range response.Warnings {
+		result += fmt.Sprintf("⚠️  %s\n", warning)
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// QueryMetricsHandler is a public wrapper for queryMetricsHandler
+func (s *Server) QueryMetricsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.queryMetricsHandler(ctx, request)
+}
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func thanosQuerier() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: thanosQuerierService, Namespace: monitoringNamespace},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "web", Port: 9091},
+			{Name: "tenancy", Port: 9092},
+		}},
+	}
+}
+
+func monitoringConfigMap(config string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterMonitoringConfig, Namespace: monitoringNamespace},
+		Data:       map[string]string{"config.yaml": config},
+	}
+}
+
+func TestDiscoverMetricsEndpoint(t *testing.T) {
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(thanosQuerier())}
+
+	tests := []struct {
+		namespace string
+		url       string
+		tenancy   bool
+	}{
+		{"shop", "https://thanos-querier.openshift-monitoring.svc:9092", true},
+		{"", "https://thanos-querier.openshift-monitoring.svc:9091", false},
+	}
+	for _, tt := range tests {
+		endpoint, err := s.discoverMetricsEndpoint(context.Background(), tt.namespace)
+		if err != nil || endpoint.URL != tt.url || endpoint.Tenancy != tt.tenancy {
+			t.Errorf("discoverMetricsEndpoint(%q) = %+v, %v, expected %s (tenancy %v)", tt.namespace, endpoint, err, tt.url, tt.tenancy)
+		}
+	}
+
+	s.config.Monitoring = &MonitoringConfig{TenancyURL: "https://tenancy.example.com/"}
+	if endpoint, _ := s.discoverMetricsEndpoint(context.Background(), "shop"); endpoint.URL != "https://tenancy.example.com" || !endpoint.Tenancy {
+		t.Errorf("discoverMetricsEndpoint with a configured tenancy URL = %+v", endpoint)
+	}
+}
+
+func TestUserWorkloadMonitoringEnabled(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected bool
+	}{
+		{"enableUserWorkload: true\n", true},
+		{"enableUserWorkload: false\n", false},
+		{"prometheusK8s:\n  retention: 7d\n", false},
+	}
+
+	for _, tt := range tests {
+		s := &Server{k8sClient: kubefake.NewSimpleClientset(monitoringConfigMap(tt.config))}
+		if enabled, err := s.userWorkloadMonitoringEnabled(context.Background()); err != nil || enabled != tt.expected {
+			t.Errorf("userWorkloadMonitoringEnabled(%q) = %v, %v, expected %v", tt.config, enabled, err, tt.expected)
+		}
+	}
+}
+
+func TestQueryMetrics(t *testing.T) {
+	var gotNamespace, gotAuth string
+	querier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotNamespace, gotAuth = r.URL.Query().Get("namespace"), r.Header.Get("Authorization")
+		if r.URL.Query().Get("query") == "bad(" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"__name__":"up","pod":"web-2"},"value":[1700000000,"0"]},
+			{"metric":{"__name__":"up","pod":"web-1"},"value":[1700000000,"1"]}]}}`))
+	}))
+	defer querier.Close()
+
+	s := &Server{
+		config:     &Config{Monitoring: &MonitoringConfig{TenancyURL: querier.URL}},
+		k8sClient:  kubefake.NewSimpleClientset(monitoringConfigMap("enableUserWorkload: true\n")),
+		restConfig: &rest.Config{BearerToken: "sa-token"},
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"query": "up", "namespace": "shop", "limit": "1"}
+	result, err := s.QueryMetricsHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("QueryMetricsHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Scope: namespace shop (enforced by the tenancy proxy)",
+		"📈 2 series:",
+		`• up{pod="web-1"} = 1`,
+		"1 more series not shown",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("query_metrics output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "User workload monitoring is disabled") {
+		t.Errorf("query_metrics reports user workload monitoring disabled:\n%s", text)
+	}
+	if gotNamespace != "shop" || gotAuth != "Bearer sa-token" {
+		t.Errorf("querier got namespace %q and authorization %q", gotNamespace, gotAuth)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"query": "bad(", "namespace": "shop"}
+	result, _ = s.QueryMetricsHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "❌ Query failed: bad_data: parse error") {
+		t.Errorf("query_metrics with an invalid query = %q", text)
+	}
+}
+
+func TestCollectMetricsSnapshot(t *testing.T) {
+	var queries []string
+	querier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" || r.URL.Query().Get("step") != "60" || r.URL.Query().Get("namespace") != "shop" {
+			t.Errorf("querier got %s", r.URL)
+		}
+		queries = append(queries, r.URL.Query().Get("query"))
+		if strings.HasPrefix(r.URL.Query().Get("query"), "bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+			return
+		}
+		values := ""
+		for i := 0; i < 20; i++ {
+			value := "1"
+			if i == 15 {
+				value = "8"
+			}
+			values += fmt.Sprintf(`,[%d,"%s"]`, 1714557600+60*i, value)
+		}
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"namespace":"shop","pod":"web-1"},"values":[` + values[1:] + `]}]}}`))
+	}))
+	defer querier.Close()
+
+	dir := t.TempDir()
+	s := &Server{
+		config:              &Config{Monitoring: &MonitoringConfig{TenancyURL: querier.URL}},
+		diagnosticCollector: diagnostics.NewDiagnosticCollector(logrus.New(), dir),
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"namespace": "shop", "since": "20m", "end": "2024-05-01T10:20:00Z", "step": "1m",
+		"queries": "cpu: sum by (pod) (rate(container_cpu_usage_seconds_total{namespace=~\"$namespace\"}[5m]))\nbad(",
+	}
+	text := resultText(mustCall(t, s.CollectMetricsSnapshotHandler, request))
+	for _, want := range []string{
+		"Range: 2024-05-01T10:00:00Z to 2024-05-01T10:20:00Z, step 1m0s",
+		"✅ cpu: 1 series",
+		"❌ query_2: bad_data: parse error",
+		"⚡ Spikes (1):\n• " + formatTime(context.Background(), time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)) + " cpu spiked to 8 on shop/web-1 (mean 1.35)",
+		"📁 Location: " + dir,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("collect_metrics_snapshot output missing %q:\n%s", want, text)
+		}
+	}
+	if len(queries) != 2 || queries[0] != `sum by (pod) (rate(container_cpu_usage_seconds_total{namespace=~"shop"}[5m]))` {
+		t.Errorf("queries sent = %q, expected the namespace filled in", queries)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "since": "30d"}
+	if text := resultText(mustCall(t, s.CollectMetricsSnapshotHandler, request)); !strings.Contains(text, "❌ invalid since '30d'") {
+		t.Errorf("collect_metrics_snapshot with a 30d window = %q", text)
+	}
+}
+
+func TestSnapshotQueries(t *testing.T) {
+	s := &Server{config: &Config{Monitoring: &MonitoringConfig{SnapshotQueries: map[string]string{
+		"oom": `kube_pod_container_status_last_terminated_reason{reason="OOMKilled", namespace=~"$namespace"}`,
+	}}}}
+	if queries := s.snapshotQueries("", ""); len(queries) != 1 || queries[0].Query != `kube_pod_container_status_last_terminated_reason{reason="OOMKilled", namespace=~".+"}` {
+		t.Errorf("snapshotQueries() from the configuration = %+v", queries)
+	}
+	if queries := (&Server{config: &Config{}}).snapshotQueries("", "shop"); len(queries) != len(defaultSnapshotQueries) || !strings.Contains(queries[0].Query, `namespace=~"shop"`) {
+		t.Errorf("snapshotQueries() defaults = %+v", queries)
+	}
+}
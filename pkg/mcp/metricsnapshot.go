@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	defaultSnapshotRange = time.Hour
+	maxSnapshotRange     = 7 * 24 * time.Hour
+	// snapshotPoints is the resolution the default step aims for
+	snapshotPoints  = 120
+	minSnapshotStep = 15 * time.Second
+	// maxSnapshotSpikes bounds the spikes listed in the tool output
+	maxSnapshotSpikes = 10
+
+	// snapshotNamespacePlaceholder is replaced by the namespace, or by .+
+	// for a cluster-wide snapshot
+	snapshotNamespacePlaceholder = "$namespace"
+)
+
+// defaultSnapshotQueries are captured when neither the request nor the
+// configuration lists queries: the signals that explain most pod crashes
+var defaultSnapshotQueries = []diagnostics.MetricQuery{
+	{Name: "cpu_cores", Query: `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="", namespace=~"$namespace"}[5m]))`},
+	{Name: "memory_working_set_bytes", Query: `sum by (namespace, pod) (container_memory_working_set_bytes{container!="", namespace=~"$namespace"})`},
+	{Name: "container_restarts", Query: `sum by (namespace, pod) (increase(kube_pod_container_status_restarts_total{namespace=~"$namespace"}[5m]))`},
+	{Name: "cpu_throttled_ratio", Query: `sum by (namespace, pod) (rate(container_cpu_cfs_throttled_periods_total{container!="", namespace=~"$namespace"}[5m])) / sum by (namespace, pod) (rate(container_cpu_cfs_periods_total{container!="", namespace=~"$namespace"}[5m]))`},
+}
+
+// namedQueryPattern matches a "name: query" line of the queries parameter
+var namedQueryPattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+):\s+(.+)$`)
+
+// parseSnapshotQueries reads one query per line, each optionally named as
+// "name: query"
+func parseSnapshotQueries(text string) []diagnostics.MetricQuery {
+	var queries []diagnostics.MetricQuery
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		query := diagnostics.MetricQuery{Name: fmt.Sprintf("query_%d", len(queries)+1), Query: line}
+		if match := namedQueryPattern.FindStringSubmatch(line); match != nil {
+			query.Name, query.Query = match[1], match[2]
+		}
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+// snapshotQueries returns the requested queries, else the configured ones,
+// else the defaults, with the namespace filled in
+func (s *Server) snapshotQueries(requested, namespace string) []diagnostics.MetricQuery {
+	queries := parseSnapshotQueries(requested)
+	if len(queries) == 0 {
+		configured := s.monitoringConfig().SnapshotQueries
+		for _, name := range sortedKeys(configured) {
+			queries = append(queries, diagnostics.MetricQuery{Name: name, Query: configured[name]})
+		}
+	}
+	if len(queries) == 0 {
+		queries = append(queries, defaultSnapshotQueries...)
+	}
+	scope := ".+"
+	if namespace != "" {
+		scope = regexp.QuoteMeta(namespace)
+	}
+	for i := range queries {
+		queries[i].Query = strings.ReplaceAll(queries[i].Query, snapshotNamespacePlaceholder, scope)
+	}
+	return queries
+}
+
+// snapshotWindow parses since, end and step into the range to capture
+func snapshotWindow(since, end, step string, now time.Time) (diagnostics.MetricsSnapshotRange, error) {
+	window := diagnostics.MetricsSnapshotRange{End: now}
+	if end != "" {
+		parsed, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return window, fmt.Errorf("invalid end '%s': expected an RFC 3339 time such as 2026-10-16T09:30:00Z", end)
+		}
+		window.End = parsed
+	}
+	length := defaultSnapshotRange
+	if since != "" {
+		parsed, err := time.ParseDuration(since)
+		if err != nil || parsed <= 0 || parsed > maxSnapshotRange {
+			return window, fmt.Errorf("invalid since '%s': expected a duration up to %s, e.g. 2h", since, maxSnapshotRange)
+		}
+		length = parsed
+	}
+	window.Start = window.End.Add(-length)
+
+	window.Step = (length / snapshotPoints).Round(time.Second)
+	if window.Step < minSnapshotStep {
+		window.Step = minSnapshotStep
+	}
+	if step != "" {
+		parsed, err := time.ParseDuration(step)
+		if err != nil || parsed < time.Second || parsed > length {
+			return window, fmt.Errorf("invalid step '%s': expected a duration between 1s and the range", step)
+		}
+		window.Step = parsed
+	}
+	return window, nil
+}
+
+func (s *Server) collectMetricsSnapshotHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	window, err := snapshotWindow(mcp.ParseString(request, "since", ""), mcp.ParseString(request, "end", ""), mcp.ParseString(request, "step", ""), time.Now())
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	queries := s.snapshotQueries(mcp.ParseString(request, "queries", ""), namespace)
+
+	endpoint, err := s.discoverMetricsEndpoint(ctx, namespace)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Cannot find a metrics querier: %v", err)), nil
+	}
+	window.Querier = endpoint.URL
+	rangeQuery := func(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]diagnostics.MetricSeries, error) {
+		return s.queryMetricsRange(ctx, endpoint, query, namespace, start, end, step)
+	}
+
+	opts := &diagnostics.CollectionOptions{
+		Namespace: namespace,
+		OutputDir: mcp.ParseString(request, "output_dir", ""),
+	}
+	result, err := s.diagnosticCollector.CollectMetricsSnapshot(ctx, opts, queries, window, rangeQuery)
+	if result == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	response := "📈 Metrics Snapshot\n"
+	response += "===================\n\n"
+	if namespace != "" {
+		response += fmt.Sprintf("Scope: namespace %s\n", namespace)
+	} else {
+		response += "Scope: cluster-wide\n"
+	}
+	response += fmt.Sprintf("Range: %s to %s, step %s\n", window.Start.UTC().Format(time.RFC3339), window.End.UTC().Format(time.RFC3339), window.Step)
+	response += fmt.Sprintf("Querier: %s (%s)\n\n", endpoint.URL, endpoint.Source)
+
+	snapshot, readErr := diagnostics.ReadMetricsSnapshot(result.FilePath)
+	if readErr == nil {
+		for _, q := range snapshot.Queries {
+			if q.Error != "" {
+				response += fmt.Sprintf("❌ %s: %s\n", q.Name, q.Error)
+			} else {
+				response += fmt.Sprintf("✅ %s: %d series\n", q.Name, len(q.Series))
+			}
+		}
+	}
+	if err != nil {
+		recordToolError(ctx, err)
+		response += fmt.Sprintf("\n❌ %v\n", err)
+		if !endpoint.Tenancy {
+			response += "💡 Cluster-wide queries need the cluster-monitoring-view role; pass namespace to query through the tenancy proxy"
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent(strings.TrimRight(response, "\n"))}, IsError: true}, nil
+	}
+
+	if readErr == nil {
+		if spikes := snapshot.Spikes(); len(spikes) > 0 {
+			response += fmt.Sprintf("\n⚡ Spikes (%d):\n", len(spikes))
+			for i, spike := range spikes {
+				if i == maxSnapshotSpikes {
+					response += fmt.Sprintf("... %d more in the snapshot\n", len(spikes)-maxSnapshotSpikes)
+					break
+				}
+				response += fmt.Sprintf("• %s %s\n", formatTime(ctx, spike.Time), spike.Summary)
+			}
+		} else {
+			response += "\n✅ No spikes above three standard deviations\n"
+		}
+	}
+
+	response += fmt.Sprintf("\n📁 Location: %s\n", result.FilePath)
+	if id := result.Metadata["artifact_id"]; id != "" {
+		response += fmt.Sprintf("🆔 Artifact: %s\n", id)
+	}
+	response += fmt.Sprintf("⏱️ Duration: %v\n", result.Duration.Round(time.Millisecond))
+	response += "\n💡 Snapshots in a log collection or must-gather directory, or one level below it, are placed on the analyze_logs and analyze_must_gather timeline, so crashes correlate with the spikes before them. Pass that directory as output_dir to store the snapshot with it."
+	return mcp.NewToolResultText(response), nil
+}
+
+// CollectMetricsSnapshotHandler is a public wrapper for collectMetricsSnapshotHandler
+func (s *Server) CollectMetricsSnapshotHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.collectMetricsSnapshotHandler(ctx, request)
+}
@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+// mustGatherPollInterval is how often a waiting openshift_must_gather call
+// reports progress
+var mustGatherPollInterval = 5 * time.Second
+
+func (s *Server) initMustGatherJobs() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("must_gather_status",
+			mcp.WithDescription("Show the progress of must-gather jobs started by openshift_must_gather: phase, data collected so far, recent output and where the data is"),
+			mcp.WithString("job_id", mcp.Description("Job ID returned by openshift_must_gather; omit to list all jobs")),
+			mcp.WithTitleAnnotation("Diagnostics: Must-Gather Status"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.mustGatherStatusHandler)},
+		{Tool: mcp.NewTool("cancel_must_gather",
+			mcp.WithDescription("Stop a running must-gather job; the data collected so far is kept"),
+			mcp.WithString("job_id", mcp.Description("Job ID returned by openshift_must_gather"), mcp.Required()),
+			mcp.WithTitleAnnotation("Diagnostics: Cancel Must-Gather"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.cancelMustGatherHandler)},
+	}
+}
+
+// formatMB renders a byte count in megabytes
+func formatMB(size int64) string {
+	return fmt.Sprintf("%.2f MB", float64(size)/(1024*1024))
+}
+
+// mustGatherIcon shows a job's state
+func mustGatherIcon(status string) string {
+	switch status {
+	case diagnostics.MustGatherRunning:
+		return "⏳"
+	case diagnostics.MustGatherCompleted:
+		return "✅"
+	case diagnostics.MustGatherCancelled:
+		return "⏹️ "
+	}
+	return "❌"
+}
+
+// mustGatherElapsed is how long a job ran, or has been running
+func mustGatherElapsed(job *diagnostics.MustGatherJob) time.Duration {
+	if job.Done() {
+		return job.Finished.Sub(job.Started).Round(time.Second)
+	}
+	return time.Since(job.Started).Round(time.Second)
+}
+
+// formatMustGatherJob describes a job in detail
+func formatMustGatherJob(job *diagnostics.MustGatherJob) string {
+	result := fmt.Sprintf("Job ID: %s\n", job.ID)
+	result += fmt.Sprintf("%s Status: %s", mustGatherIcon(job.Status), job.Status)
+	if !job.Done() {
+		result += fmt.Sprintf(" (%s)", job.Phase)
+	}
+	result += fmt.Sprintf(", %s elapsed\n", mustGatherElapsed(job))
+	result += fmt.Sprintf("🖼️  Image(s): %s\n", strings.Join(job.Images, ", "))
+	if job.NodeName != "" {
+		result += fmt.Sprintf("🖥️  Node: %s\n", job.NodeName)
+	}
+	if job.Since != "" {
+		result += fmt.Sprintf("🕒 Logs since: %s\n", job.Since)
+	}
+	result += fmt.Sprintf("📁 Destination: %s\n", job.DestDir)
+	result += fmt.Sprintf("📦 Collected: %s in %d file(s), %d output line(s)\n", formatMB(job.Size), job.Files, job.Lines)
+	result += fmt.Sprintf("🔧 Command: %s\n", job.Command)
+	if job.Error != "" && job.Status != diagnostics.MustGatherCancelled {
+		result += fmt.Sprintf("❌ Error: %s\n", job.Error)
+	}
+	if len(job.LastOutput) > 0 {
+		result += "\n📝 Recent output:\n"
+		for _, line := range job.LastOutput {
+			result += fmt.Sprintf("   %s\n", line)
+		}
+	}
+
+	switch job.Status {
+	case diagnostics.MustGatherRunning:
+		result += fmt.Sprintf("\n💡 Check again with must_gather_status job_id=%s, or stop it with cancel_must_gather", job.ID)
+	case diagnostics.MustGatherCompleted:
+		result += fmt.Sprintf("\n💡 Analyze it with analyze_must_gather must_gather_path=%s", job.DestDir)
+	default:
+		result += fmt.Sprintf("\n💡 See %s/must-gather.log for the full output", job.DestDir)
+	}
+	return result
+}
+
+func (s *Server) openShiftMustGather(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.diagnosticCollector == nil {
+		return mcp.NewToolResultText("❌ Diagnostic collection is not available"), nil
+	}
+	opts := &diagnostics.CollectionOptions{
+		NodeName:  mcp.ParseString(request, "node_name", ""),
+		OutputDir: mcp.ParseString(request, "dest_dir", ""),
+		Filters:   make(map[string]string),
+	}
+	opts.Filters["image"] = mcp.ParseString(request, "image", "")
+	for _, name := range []string{"since", "timeout"} {
+		value := mcp.ParseString(request, name, "")
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid %s %q, use a duration like 30m or 2h", name, value)), nil
+		}
+		opts.Filters[name] = value
+	}
+	wait := parseBoolString(mcp.ParseString(request, "wait", "false"))
+	mode, err := s.mustGatherMode(mcp.ParseString(request, "mode", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	// One must-gather at a time across replicas sharing a store
+	release, err := s.lockMustGatherStart(ctx)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to start must-gather: %v", err)), nil
+	}
+	if running := s.runningElsewhere(); running != nil {
+		release()
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to start must-gather: must-gather %s is still running on another replica; wait for it or cancel it first", running.ID)), nil
+	}
+	var job *diagnostics.MustGatherJob
+	if mode == MustGatherModeJob {
+		job, err = s.startMustGatherJob(opts)
+	} else {
+		job, err = s.diagnosticCollector.StartMustGather(opts)
+	}
+	if err == nil && store.Shared(s.store) {
+		s.publishMustGather(job.ID)
+		go s.followMustGather(job.ID)
+	}
+	release()
+	if err != nil {
+		recordToolError(ctx, err)
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to start must-gather: %v", err)), nil
+	}
+
+	if wait {
+		ticker := time.NewTicker(mustGatherPollInterval)
+		defer ticker.Stop()
+	poll:
+		for !job.Done() {
+			select {
+			case <-ctx.Done():
+				break poll
+			case <-ticker.C:
+			}
+			if current, ok := s.diagnosticCollector.MustGather(job.ID); ok {
+				job = current
+			}
+			notifyProgress(ctx, request, int(mustGatherElapsed(job).Seconds()),
+				fmt.Sprintf("%s: %s, %s collected", job.ID, job.Phase, formatMB(job.Size)))
+		}
+	}
+
+	result := "🧰 OpenShift Must-Gather\n"
+	result += "========================\n\n"
+	if !job.Done() {
+		if wait {
+			result += "⏱️  Still running when this call's time ran out; the collection continues in the background\n\n"
+		} else {
+			result += "🚀 Started in the background\n\n"
+		}
+	}
+	result += formatMustGatherJob(job)
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) mustGatherStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.diagnosticCollector == nil {
+		return mcp.NewToolResultText("❌ Diagnostic collection is not available"), nil
+	}
+	jobID := strings.TrimSpace(mcp.ParseString(request, "job_id", ""))
+
+	result := "🧰 Must-Gather Status\n"
+	result += "=====================\n\n"
+	if jobID != "" {
+		job, ok := s.diagnosticCollector.MustGather(jobID)
+		if !ok {
+			if job, ok = s.sharedMustGather(jobID); !ok {
+				return mcp.NewToolResultText(fmt.Sprintf("❌ Must-gather job %s not found; jobs are kept until the server restarts", jobID)), nil
+			}
+			result += describeSharedJob(job)
+		}
+		result += formatMustGatherJob(job)
+		return mcp.NewToolResultText(result), nil
+	}
+
+	jobs := append(s.diagnosticCollector.MustGathers(), s.sharedMustGathers()...)
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Started.After(jobs[j].Started) })
+	if len(jobs) == 0 {
+		result += "No must-gather jobs since the server started\n"
+		result += "\n💡 Start one with openshift_must_gather"
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("📋 %d job(s), newest first:\n", len(jobs))
+	for _, job := range jobs {
+		state := job.Status
+		if !job.Done() {
+			state = fmt.Sprintf("%s (%s)", job.Status, job.Phase)
+		}
+		result += fmt.Sprintf("%s %s - %s, %s, %s in %s\n", mustGatherIcon(job.Status), job.ID, state,
+			mustGatherElapsed(job), formatMB(job.Size), job.DestDir)
+	}
+	result += "\n💡 Pass job_id for a job's progress and recent output"
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) cancelMustGatherHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.diagnosticCollector == nil {
+		return mcp.NewToolResultText("❌ Diagnostic collection is not available"), nil
+	}
+	jobID := strings.TrimSpace(mcp.ParseString(request, "job_id", ""))
+	if jobID == "" {
+		return mcp.NewToolResultText("❌ job_id is required"), nil
+	}
+	if _, local := s.diagnosticCollector.MustGather(jobID); !local {
+		if shared, ok := s.sharedMustGather(jobID); ok {
+			if shared.Done() {
+				return mcp.NewToolResultText(fmt.Sprintf("❌ must-gather %s already %s", jobID, shared.Status)), nil
+			}
+			if err := s.requestMustGatherCancel(jobID); err != nil {
+				return toolError(ctx, fmt.Sprintf("Failed to cancel must-gather %s", jobID), err), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("⏹️  Asked the replica running must-gather %s to cancel it; must_gather_status shows when it stops", jobID)), nil
+		}
+	}
+	if err := s.diagnosticCollector.CancelMustGather(jobID); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	job, _ := s.diagnosticCollector.MustGather(jobID)
+	return mcp.NewToolResultText(fmt.Sprintf("⏹️  Cancelled must-gather %s after %s; %s collected so far is kept in %s",
+		jobID, mustGatherElapsed(job), formatMB(job.Size), job.DestDir)), nil
+}
+
+// MustGatherStatusHandler is a public wrapper for mustGatherStatusHandler
+func (s *Server) MustGatherStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.mustGatherStatusHandler(ctx, request)
+}
+
+// CancelMustGatherHandler is a public wrapper for cancelMustGatherHandler
+func (s *Server) CancelMustGatherHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.cancelMustGatherHandler(ctx, request)
+}
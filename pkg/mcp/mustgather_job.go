@@ -0,0 +1,449 @@
+package mcp
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// Must-gather collection modes
+const (
+	MustGatherModeAuto = "auto" // oc when the binary is installed, otherwise a Job
+	MustGatherModeOC   = "oc"
+	MustGatherModeJob  = "job"
+)
+
+// MustGatherConfig controls how openshift_must_gather collects data. In job
+// mode the gather images run as init containers of a privileged Job writing
+// to a PVC, and the server copies the result out through pods/exec, so no oc
+// binary is needed when the server runs in-cluster.
+type MustGatherConfig struct {
+	Mode           string `json:"mode"`            // auto (default), oc or job
+	Namespace      string `json:"namespace"`       // where Jobs run, default openshift-mcp
+	ServiceAccount string `json:"service_account"` // bound to cluster-admin like oc's gather pod, default must-gather
+	StorageClass   string `json:"storage_class"`   // empty uses the cluster default
+	StorageSize    string `json:"storage_size"`    // default 10Gi
+}
+
+const (
+	defaultMustGatherNamespace      = "openshift-mcp"
+	defaultMustGatherServiceAccount = "must-gather"
+	defaultMustGatherStorageSize    = "10Gi"
+
+	// mustGatherOutputDir is where gather images write inside the pod
+	mustGatherOutputDir = "/must-gather"
+	// mustGatherHoldSeconds keeps the Job's pod alive to copy the output
+	// from; it ends on its own if the server goes away
+	mustGatherHoldSeconds = 3600
+)
+
+// mustGatherJobPollInterval is how often the Job's pod is checked
+var mustGatherJobPollInterval = 2 * time.Second
+
+// mustGatherConfig returns the configuration with defaults applied
+func (s *Server) mustGatherConfig() MustGatherConfig {
+	var config MustGatherConfig
+	if s.config != nil && s.config.MustGather != nil {
+		config = *s.config.MustGather
+	}
+	if config.Mode == "" {
+		config.Mode = MustGatherModeAuto
+	}
+	if config.Namespace == "" {
+		config.Namespace = defaultMustGatherNamespace
+	}
+	if config.ServiceAccount == "" {
+		config.ServiceAccount = defaultMustGatherServiceAccount
+	}
+	if config.StorageSize == "" {
+		config.StorageSize = defaultMustGatherStorageSize
+	}
+	return config
+}
+
+// lookPath finds the oc binary; a variable so tests can pretend it is missing
+var lookPath = exec.LookPath
+
+// mustGatherMode resolves the mode to use for a collection
+func (s *Server) mustGatherMode(requested string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(requested))
+	if mode == "" {
+		mode = s.mustGatherConfig().Mode
+	}
+	switch mode {
+	case MustGatherModeOC:
+		return mode, nil
+	case MustGatherModeJob:
+		if s.k8sClient == nil {
+			return "", fmt.Errorf("job mode needs a Kubernetes client")
+		}
+		return mode, nil
+	case MustGatherModeAuto:
+		if _, err := lookPath("oc"); err == nil {
+			return MustGatherModeOC, nil
+		}
+		if s.k8sClient == nil {
+			return "", fmt.Errorf("the oc binary is not installed and there is no Kubernetes client to run a must-gather Job")
+		}
+		return MustGatherModeJob, nil
+	}
+	return "", fmt.Errorf("unsupported mode %q, use auto, oc or job", requested)
+}
+
+// imageDirPattern matches characters oc replaces in per-image directory names
+var imageDirPattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// mustGatherImageDir names the output directory of an image like oc does
+func mustGatherImageDir(image string) string {
+	return strings.Trim(imageDirPattern.ReplaceAllString(image, "-"), "-")
+}
+
+// mustGatherJobSpec describes a must-gather Job run
+type mustGatherJobSpec struct {
+	name     string
+	images   []string
+	nodeName string
+	since    string
+	timeout  string
+}
+
+// newMustGatherPVC builds the claim the gather images write to
+func newMustGatherPVC(config MustGatherConfig, name string) (*corev1.PersistentVolumeClaim, error) {
+	size, err := resource.ParseQuantity(config.StorageSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage size %q: %v", config.StorageSize, err)
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: config.Namespace, Labels: mustGatherLabels(name)},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if config.StorageClass != "" {
+		storageClass := config.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	return pvc, nil
+}
+
+// mustGatherLabels marks the objects of a must-gather run
+func mustGatherLabels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "must-gather",
+		"app.kubernetes.io/instance":   name,
+		"app.kubernetes.io/managed-by": "openshift-mcp",
+	}
+}
+
+// newMustGatherJob builds the Job: one init container per gather image, each
+// writing to its own directory on the PVC, and a holder container that keeps
+// the pod up while the server copies the output
+func newMustGatherJob(config MustGatherConfig, spec mustGatherJobSpec) (*batchv1.Job, error) {
+	gather := []string{"/usr/bin/gather"}
+	if spec.timeout != "" {
+		timeout, err := time.ParseDuration(spec.timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %v", spec.timeout, err)
+		}
+		gather = append([]string{"timeout", strconv.Itoa(int(timeout.Seconds()))}, gather...)
+	}
+	var env []corev1.EnvVar
+	if spec.since != "" {
+		env = append(env, corev1.EnvVar{Name: "MUST_GATHER_SINCE", Value: spec.since})
+	}
+
+	privileged := true
+	backoffLimit := int32(0)
+	deadline := int64(mustGatherHoldSeconds)
+	securityContext := &corev1.SecurityContext{Privileged: &privileged}
+	var initContainers []corev1.Container
+	for i, image := range spec.images {
+		initContainers = append(initContainers, corev1.Container{
+			Name:            fmt.Sprintf("gather-%d", i),
+			Image:           image,
+			Command:         gather,
+			Env:             env,
+			SecurityContext: securityContext,
+			VolumeMounts: []corev1.VolumeMount{{
+				Name: "output", MountPath: mustGatherOutputDir, SubPath: mustGatherImageDir(image),
+			}},
+		})
+	}
+
+	labels := mustGatherLabels(spec.name)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: spec.name, Namespace: config.Namespace, Labels: labels},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: config.ServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					NodeName:           spec.nodeName,
+					NodeSelector:       map[string]string{"kubernetes.io/os": "linux"},
+					Tolerations:        []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					PriorityClassName:  "system-cluster-critical",
+					InitContainers:     initContainers,
+					Containers: []corev1.Container{{
+						Name:            "holder",
+						Image:           spec.images[0],
+						Command:         []string{"/bin/sh", "-c", fmt.Sprintf("sleep %d", mustGatherHoldSeconds)},
+						SecurityContext: securityContext,
+						VolumeMounts:    []corev1.VolumeMount{{Name: "output", MountPath: mustGatherOutputDir}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "output",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: spec.name},
+						},
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// startMustGatherJob runs must-gather as a Job and tracks it with the
+// diagnostic collector like an oc run
+func (s *Server) startMustGatherJob(opts *diagnostics.CollectionOptions) (*diagnostics.MustGatherJob, error) {
+	config := s.mustGatherConfig()
+	spec := mustGatherJobSpec{
+		name:     "must-gather-" + utilrand.String(5),
+		nodeName: opts.NodeName,
+		since:    opts.Filters["since"],
+		timeout:  opts.Filters["timeout"],
+	}
+	for _, image := range strings.Split(opts.Filters["image"], ",") {
+		if image = strings.TrimSpace(image); image != "" {
+			spec.images = append(spec.images, image)
+		}
+	}
+	if len(spec.images) == 0 {
+		spec.images = []string{diagnostics.DefaultMustGatherImage}
+	}
+	pvc, err := newMustGatherPVC(config, spec.name)
+	if err != nil {
+		return nil, err
+	}
+	job, err := newMustGatherJob(config, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.diagnosticCollector.StartMustGatherWith(opts, func(destDir string) string {
+		return fmt.Sprintf("Job %s/%s with PVC %s (%s)", config.Namespace, spec.name, spec.name, config.StorageSize)
+	}, func(ctx context.Context, destDir string, output func(line string)) error {
+		return s.runMustGatherJob(ctx, pvc, job, destDir, output)
+	})
+}
+
+// runMustGatherJob creates the PVC and Job, follows the gather containers'
+// logs, copies the output and removes both objects again
+func (s *Server) runMustGatherJob(ctx context.Context, pvc *corev1.PersistentVolumeClaim, job *batchv1.Job, destDir string, output func(line string)) error {
+	namespace := job.Namespace
+	pvcs := s.k8sClient.CoreV1().PersistentVolumeClaims(namespace)
+	jobs := s.k8sClient.BatchV1().Jobs(namespace)
+
+	output(fmt.Sprintf("Creating PVC %s/%s", namespace, pvc.Name))
+	if _, err := pvcs.Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating PVC %s/%s: %v", namespace, pvc.Name, err)
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		propagation := metav1.DeletePropagationBackground
+		if err := jobs.Delete(cleanupCtx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			output(fmt.Sprintf("Failed to delete Job %s/%s: %v", namespace, job.Name, err))
+		}
+		if err := pvcs.Delete(cleanupCtx, pvc.Name, metav1.DeleteOptions{}); err != nil {
+			output(fmt.Sprintf("Failed to delete PVC %s/%s: %v", namespace, pvc.Name, err))
+		}
+		output(fmt.Sprintf("Removed Job and PVC %s/%s", namespace, job.Name))
+	}()
+
+	output(fmt.Sprintf("Creating Job %s/%s", namespace, job.Name))
+	if _, err := jobs.Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating Job %s/%s: %v", namespace, job.Name, err)
+	}
+
+	pod, err := s.waitForMustGatherPod(ctx, namespace, job.Name, func(pod *corev1.Pod) bool { return true })
+	if err != nil {
+		return err
+	}
+	for i, container := range job.Spec.Template.Spec.InitContainers {
+		image := container.Image
+		pod, err = s.waitForMustGatherPod(ctx, namespace, job.Name, func(pod *corev1.Pod) bool {
+			status := initContainerStatus(pod, container.Name)
+			return status != nil && (status.State.Running != nil || status.State.Terminated != nil)
+		})
+		if err != nil {
+			return err
+		}
+		output(fmt.Sprintf("[%s] Gathering with %s (%d/%d)", pod.Name, image, i+1, len(job.Spec.Template.Spec.InitContainers)))
+		if err := s.followMustGatherLogs(ctx, pod, container.Name, output); err != nil {
+			output(fmt.Sprintf("[%s] Log stream of %s ended: %v", pod.Name, container.Name, err))
+		}
+		pod, err = s.waitForMustGatherPod(ctx, namespace, job.Name, func(pod *corev1.Pod) bool {
+			status := initContainerStatus(pod, container.Name)
+			return status != nil && status.State.Terminated != nil
+		})
+		if err != nil {
+			return err
+		}
+		if terminated := initContainerStatus(pod, container.Name).State.Terminated; terminated.ExitCode != 0 {
+			return fmt.Errorf("gather image %s exited with code %d (%s)", image, terminated.ExitCode, valueOrNone(terminated.Reason))
+		}
+	}
+
+	pod, err = s.waitForMustGatherPod(ctx, namespace, job.Name, func(pod *corev1.Pod) bool {
+		return len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].State.Running != nil
+	})
+	if err != nil {
+		return err
+	}
+	output(fmt.Sprintf("Downloading gather output from pod %s", pod.Name))
+	return s.copyMustGatherOutput(ctx, pod, destDir)
+}
+
+// initContainerStatus finds the status of an init container
+func initContainerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for i := range pod.Status.InitContainerStatuses {
+		if pod.Status.InitContainerStatuses[i].Name == name {
+			return &pod.Status.InitContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// waitForMustGatherPod polls the Job's pod until ready returns true, failing
+// when the pod fails first
+func (s *Server) waitForMustGatherPod(ctx context.Context, namespace, jobName string, ready func(*corev1.Pod) bool) (*corev1.Pod, error) {
+	ticker := time.NewTicker(mustGatherJobPollInterval)
+	defer ticker.Stop()
+	for {
+		pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + jobName})
+		if err != nil && ctx.Err() == nil {
+			return nil, fmt.Errorf("listing pods of Job %s/%s: %v", namespace, jobName, err)
+		}
+		if err == nil && len(pods.Items) > 0 {
+			pod := &pods.Items[0]
+			if ready(pod) {
+				return pod, nil
+			}
+			if pod.Status.Phase == corev1.PodFailed {
+				return nil, fmt.Errorf("must-gather pod %s failed: %s", pod.Name, valueOrNone(pod.Status.Message))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// followMustGatherLogs passes a gather container's log lines to output
+// until the container exits
+func (s *Server) followMustGatherLogs(ctx context.Context, pod *corev1.Pod, container string, output func(line string)) error {
+	stream, err := s.k8sClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container, Follow: true}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		output(fmt.Sprintf("[%s] %s", pod.Name, scanner.Text()))
+	}
+	return scanner.Err()
+}
+
+// copyMustGatherOutput streams a tar of the output directory out of the
+// holder container and unpacks it into destDir
+func (s *Server) copyMustGatherOutput(ctx context.Context, pod *corev1.Pod, destDir string) error {
+	exec := s.podExec
+	if exec == nil {
+		exec = s.remoteExec
+	}
+	reader, writer := io.Pipe()
+	var stderr strings.Builder
+	go func() {
+		err := exec(ctx, pod.Namespace, pod.Name, "holder", []string{"tar", "czf", "-", "-C", mustGatherOutputDir, "."}, writer, &stderr)
+		if err != nil && stderr.Len() > 0 {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		writer.CloseWithError(err)
+	}()
+	if err := extractTarGz(reader, destDir); err != nil {
+		reader.CloseWithError(err)
+		return fmt.Errorf("copying output from pod %s: %v", pod.Name, err)
+	}
+	return nil
+}
+
+// extractTarGz unpacks a gzipped tar stream into dir, refusing entries that
+// would land outside it
+func extractTarGz(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	archive := tar.NewReader(gz)
+	root := filepath.Clean(dir) + string(filepath.Separator)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, header.Name)
+		if !strings.HasPrefix(target+string(filepath.Separator), root) {
+			return fmt.Errorf("archive entry %q is outside the destination", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, archive)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
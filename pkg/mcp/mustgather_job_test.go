@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func TestMustGatherMode(t *testing.T) {
+	defer func(original func(string) (string, error)) { lookPath = original }(lookPath)
+	withClient := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset()}
+	withoutClient := &Server{config: &Config{}}
+
+	tests := []struct {
+		s         *Server
+		ocPresent bool
+		requested string
+		expected  string
+	}{
+		{withClient, true, "", MustGatherModeOC},
+		{withClient, false, "", MustGatherModeJob},
+		{withClient, true, "job", MustGatherModeJob},
+		{withoutClient, false, "", ""},
+		{withoutClient, true, "job", ""},
+		{withClient, true, "pod", ""},
+	}
+	for _, tt := range tests {
+		lookPath = func(string) (string, error) {
+			if tt.ocPresent {
+				return "/usr/bin/oc", nil
+			}
+			return "", errors.New("not found")
+		}
+		if mode, err := tt.s.mustGatherMode(tt.requested); mode != tt.expected || (err == nil) != (tt.expected != "") {
+			t.Errorf("mustGatherMode(%q) with oc %v, client %v = %q, %v, expected %q", tt.requested, tt.ocPresent, tt.s.k8sClient != nil, mode, err, tt.expected)
+		}
+	}
+}
+
+func TestNewMustGatherJob(t *testing.T) {
+	config := (&Server{config: &Config{}}).mustGatherConfig()
+	job, err := newMustGatherJob(config, mustGatherJobSpec{
+		name: "must-gather-abcde", images: []string{"quay.io/openshift/mg:4.16", "quay.io/odf/mg"}, since: "2h", timeout: "30m",
+	})
+	if err != nil {
+		t.Fatalf("newMustGatherJob() error = %v", err)
+	}
+	spec := job.Spec.Template.Spec
+	if job.Namespace != "openshift-mcp" || spec.ServiceAccountName != "must-gather" || len(spec.InitContainers) != 2 {
+		t.Fatalf("newMustGatherJob() = %s/%s, service account %s, %d init containers", job.Namespace, job.Name, spec.ServiceAccountName, len(spec.InitContainers))
+	}
+	gather := spec.InitContainers[1]
+	if !reflect.DeepEqual(gather.Command, []string{"timeout", "1800", "/usr/bin/gather"}) ||
+		gather.VolumeMounts[0].SubPath != "quay-io-odf-mg" ||
+		!reflect.DeepEqual(gather.Env, []corev1.EnvVar{{Name: "MUST_GATHER_SINCE", Value: "2h"}}) ||
+		!*gather.SecurityContext.Privileged {
+		t.Errorf("newMustGatherJob() gather container = %+v", gather)
+	}
+	if claim := spec.Volumes[0].PersistentVolumeClaim.ClaimName; claim != "must-gather-abcde" {
+		t.Errorf("newMustGatherJob() mounts PVC %s", claim)
+	}
+}
+
+// mustGatherArchive is a gzipped tar like the holder container returns
+func mustGatherArchive(t *testing.T, files map[string]string) []byte {
+	var buf strings.Builder
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	for _, name := range sortedKeys(files) {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		archive.Write([]byte(files[name]))
+	}
+	archive.Close()
+	gz.Close()
+	return []byte(buf.String())
+}
+
+func TestMustGatherJobMode(t *testing.T) {
+	defer func(original time.Duration) { mustGatherJobPollInterval = original }(mustGatherJobPollInterval)
+	defer func(original time.Duration) { mustGatherPollInterval = original }(mustGatherPollInterval)
+	mustGatherJobPollInterval = 10 * time.Millisecond
+	mustGatherPollInterval = 10 * time.Millisecond
+
+	client := kubefake.NewSimpleClientset()
+	// Stand in for the Job controller: the pod has gathered and the holder runs
+	client.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: job.Name + "-x1", Namespace: job.Namespace, Labels: map[string]string{"job-name": job.Name}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "gather-0", State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"},
+				}}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "holder", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+			},
+		}
+		return false, nil, client.Tracker().Add(pod)
+	})
+
+	archive := mustGatherArchive(t, map[string]string{"quay-io-mg/version": "4.16"})
+	var execCommand []string
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &Server{
+		config:              &Config{},
+		k8sClient:           client,
+		diagnosticCollector: diagnostics.NewDiagnosticCollector(logger, t.TempDir()),
+		podExec: func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+			execCommand = append([]string{namespace + "/" + pod + "/" + container}, command...)
+			_, err := stdout.Write(archive)
+			return err
+		},
+	}
+
+	result, err := s.openShiftMustGather(context.Background(), usageRequest(map[string]interface{}{"mode": "job", "wait": "true", "image": "quay.io/mg"}))
+	if err != nil {
+		t.Fatalf("openShiftMustGather() error = %v", err)
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "✅ Status: completed") || !strings.Contains(text, "🔧 Command: Job openshift-mcp/must-gather-") {
+		t.Fatalf("openShiftMustGather(mode=job) = %s", text)
+	}
+	jobs := s.diagnosticCollector.MustGathers()
+	if data, err := os.ReadFile(filepath.Join(jobs[0].DestDir, "quay-io-mg", "version")); err != nil || string(data) != "4.16" {
+		t.Errorf("copied version file = %q, %v", data, err)
+	}
+	if len(execCommand) < 2 || !strings.HasSuffix(execCommand[0], "-x1/holder") || strings.Join(execCommand[1:], " ") != "tar czf - -C /must-gather ." {
+		t.Errorf("pod exec = %v", execCommand)
+	}
+
+	// The Job and PVC are removed once the output is copied
+	if remaining, _ := client.BatchV1().Jobs("openshift-mcp").List(context.Background(), metav1.ListOptions{}); len(remaining.Items) != 0 {
+		t.Errorf("%d Job(s) left behind", len(remaining.Items))
+	}
+	if remaining, _ := client.CoreV1().PersistentVolumeClaims("openshift-mcp").List(context.Background(), metav1.ListOptions{}); len(remaining.Items) != 0 {
+		t.Errorf("%d PVC(s) left behind", len(remaining.Items))
+	}
+}
+
+func TestExtractTarGzRejectsEscapes(t *testing.T) {
+	archive := mustGatherArchive(t, map[string]string{"../escape": "x"})
+	err := extractTarGz(strings.NewReader(string(archive)), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "outside the destination") {
+		t.Errorf("extractTarGz() error = %v, expected the entry to be refused", err)
+	}
+}
+
+func TestMustGatherRefusedInReadOnlyMode(t *testing.T) {
+	s := newPolicyTestServer(true)
+	var ran []string
+	for _, tool := range s.initOpenShiftTools() {
+		name := tool.Tool.Name
+		s.toolDefs[name] = tool.Tool
+		s.tools[name] = s.withPolicy(name, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ran = append(ran, name)
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+
+	// Job mode creates a PVC and a privileged Job
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "openshift_must_gather"
+	request.Params.Arguments = map[string]interface{}{"mode": "job"}
+	result, err := s.CallTool(context.Background(), request)
+	if err != nil || !strings.Contains(resultText(result), "🔒 openshift_must_gather is not allowed") {
+		t.Errorf("CallTool(openshift_must_gather) in read-only mode = %q, %v", resultText(result), err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("read-only mode ran %v", ran)
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func TestOpenShiftMustGatherValidation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &Server{config: &Config{}, diagnosticCollector: diagnostics.NewDiagnosticCollector(logger, t.TempDir())}
+
+	tests := []struct {
+		handler  func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args     map[string]interface{}
+		expected string
+	}{
+		{s.openShiftMustGather, map[string]interface{}{"since": "yesterday"}, `❌ Invalid since "yesterday", use a duration like 30m or 2h`},
+		{s.mustGatherStatusHandler, nil, "No must-gather jobs since the server started"},
+		{s.mustGatherStatusHandler, map[string]interface{}{"job_id": "mg-1"}, "❌ Must-gather job mg-1 not found; jobs are kept until the server restarts"},
+		{s.cancelMustGatherHandler, map[string]interface{}{"job_id": "mg-1"}, "❌ must-gather mg-1 not found"},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := tt.handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler(%v) error = %v", tt.args, err)
+		}
+		if text := resultText(result); !strings.Contains(text, tt.expected) {
+			t.Errorf("handler(%v) = %q, expected %q", tt.args, text, tt.expected)
+		}
+	}
+}
+
+func TestFormatMustGatherJob(t *testing.T) {
+	job := &diagnostics.MustGatherJob{
+		ID: "mg-20261016-101500-1", Status: diagnostics.MustGatherCompleted, Images: []string{diagnostics.DefaultMustGatherImage},
+		DestDir: "/tmp/diagnostics/must-gather/mg-20261016-101500-1", Command: "oc adm must-gather", Size: 3 * 1024 * 1024, Files: 120, Lines: 40,
+	}
+	text := formatMustGatherJob(job)
+	for _, want := range []string{
+		"✅ Status: completed, ",
+		"📦 Collected: 3.00 MB in 120 file(s), 40 output line(s)",
+		"💡 Analyze it with analyze_must_gather must_gather_path=/tmp/diagnostics/must-gather/mg-20261016-101500-1",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatMustGatherJob() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestAnalyzeMustGatherDiff(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &Server{config: &Config{}, analysisEngine: diagnostics.NewAnalysisEngine(logger)}
+
+	if text := resultText(mustCall(t, s.analyzeMustGatherDiffHandler, ruleRequest(map[string]interface{}{"before_path": t.TempDir()}))); !strings.Contains(text, "after_path parameters are required") {
+		t.Errorf("unexpected result without after_path:\n%s", text)
+	}
+
+	nodes := func(ready string) string {
+		return "items:\n- metadata: {name: master-0}\n  status:\n    conditions:\n    - {type: Ready, status: \"" + ready + "\"}\n"
+	}
+	before, after := t.TempDir(), t.TempDir()
+	for dir, ready := range map[string]string{before: "True", after: "Unknown"} {
+		path := filepath.Join(dir, "cluster-scoped-resources", "core", "nodes.yaml")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(nodes(ready)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	text := resultText(mustCall(t, s.analyzeMustGatherDiffHandler, ruleRequest(map[string]interface{}{"before_path": before, "after_path": after})))
+	for _, want := range []string{
+		"Nodes: 1 (1 not ready)",
+		"🔴 Regressions (1):\n  • [nodes] master-0 is no longer Ready (Ready=Unknown)",
+		"❌ 1 regressions between the must-gathers",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("analyze_must_gather_diff output missing %q:\n%s", want, text)
+		}
+	}
+
+	text = resultText(mustCall(t, s.analyzeMustGatherDiffHandler, ruleRequest(map[string]interface{}{"before_path": before, "after_path": before})))
+	if !strings.Contains(text, "✅ No differences in operators, nodes or pods") {
+		t.Errorf("comparing a must-gather with itself:\n%s", text)
+	}
+}
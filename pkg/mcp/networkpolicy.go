@@ -0,0 +1,464 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// namespaceNameLabel is set by the API server on every namespace
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// networkPolicyTemplates describes the templates of generate_network_policy
+var networkPolicyTemplates = map[string]string{
+	"deny-all":              "deny all incoming and outgoing traffic of the selected pods",
+	"deny-all-ingress":      "deny all incoming traffic to the selected pods",
+	"deny-all-egress":       "deny all outgoing traffic of the selected pods",
+	"allow-same-namespace":  "allow incoming traffic from every pod in the same namespace",
+	"allow-from-namespace":  "allow incoming traffic from every pod in from_namespace",
+	"allow-from-pods":       "allow incoming traffic from the pods matching from_pod_selector (in from_namespace, default the same namespace)",
+	"allow-from-ingress":    "allow incoming traffic from the OpenShift router, needed by Routes",
+	"allow-from-monitoring": "allow incoming traffic from the OpenShift monitoring stack",
+	"allow-dns-egress":      "allow outgoing DNS queries to the cluster DNS",
+}
+
+func (s *Server) initNetworkPolicies() []server.ServerTool {
+	templates := sortedKeys(networkPolicyTemplates)
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_network_policies",
+			mcp.WithDescription("List NetworkPolicies with the pods they select and a summary of the incoming and outgoing traffic they allow"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list policies from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector on the policies, e.g. app=web")),
+			mcp.WithTitleAnnotation("Network: List NetworkPolicies"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listNetworkPoliciesHandler)},
+		{Tool: mcp.NewTool("explain_network_policy",
+			mcp.WithDescription("Explain in plain language which incoming and outgoing traffic a NetworkPolicy permits and which pods it currently applies to"),
+			mcp.WithString("policy_name", mcp.Description("Name of the NetworkPolicy"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the policy (default: default)")),
+			mcp.WithTitleAnnotation("Network: Explain NetworkPolicy"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.explainNetworkPolicyHandler)},
+		{Tool: mcp.NewTool("generate_network_policy",
+			mcp.WithDescription("Generate NetworkPolicy YAML from a template: "+strings.Join(templates, ", ")),
+			mcp.WithString("template", mcp.Description("Template: "+strings.Join(templates, ", ")), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the policy (default: default)")),
+			mcp.WithString("name", mcp.Description("Name of the policy (default: the template name)")),
+			mcp.WithString("pod_selector", mcp.Description("Label selector of the pods the policy applies to, e.g. app=web (default: all pods)")),
+			mcp.WithString("from_namespace", mcp.Description("Source namespace for allow-from-namespace and allow-from-pods")),
+			mcp.WithString("from_pod_selector", mcp.Description("Label selector of the source pods for allow-from-pods")),
+			mcp.WithString("ports", mcp.Description("Comma-separated ports the allow templates open, e.g. 8080,443/TCP,53/UDP (default: all ports)")),
+			mcp.WithString("save_to_git", mcp.Description("Save the generated YAML to the Git repository (true/false)")),
+			mcp.WithTitleAnnotation("Network: Generate NetworkPolicy"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.generateNetworkPolicyHandler)},
+	}
+}
+
+// describeSelector renders a label selector, or all when it is empty
+func describeSelector(selector *metav1.LabelSelector, all string) string {
+	if selector == nil {
+		return all
+	}
+	if text := metav1.FormatLabelSelector(selector); text != "" && text != "<none>" {
+		return text
+	}
+	return all
+}
+
+// describePeer renders a policy peer in plain language
+func describePeer(peer networkingv1.NetworkPolicyPeer) string {
+	if peer.IPBlock != nil {
+		text := "IP range " + peer.IPBlock.CIDR
+		if len(peer.IPBlock.Except) > 0 {
+			text += " except " + strings.Join(peer.IPBlock.Except, ", ")
+		}
+		return text
+	}
+	if peer.NamespaceSelector == nil {
+		return "pods with " + describeSelector(peer.PodSelector, "any labels") + " in the policy's namespace"
+	}
+	namespaces := "namespaces with " + describeSelector(peer.NamespaceSelector, "")
+	if name, ok := peer.NamespaceSelector.MatchLabels[namespaceNameLabel]; ok && len(peer.NamespaceSelector.MatchLabels) == 1 && len(peer.NamespaceSelector.MatchExpressions) == 0 {
+		namespaces = "namespace " + name
+	} else if describeSelector(peer.NamespaceSelector, "") == "" {
+		namespaces = "all namespaces"
+	}
+	if peer.PodSelector == nil || describeSelector(peer.PodSelector, "") == "" {
+		return "all pods in " + namespaces
+	}
+	return fmt.Sprintf("pods with %s in %s", describeSelector(peer.PodSelector, ""), namespaces)
+}
+
+// describePorts renders the ports of a rule
+func describePorts(ports []networkingv1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "on all ports"
+	}
+	var parts []string
+	for _, port := range ports {
+		protocol := "TCP"
+		if port.Protocol != nil {
+			protocol = string(*port.Protocol)
+		}
+		switch {
+		case port.Port == nil:
+			parts = append(parts, "all "+protocol+" ports")
+		case port.EndPort != nil:
+			parts = append(parts, fmt.Sprintf("%s %s-%d", protocol, port.Port.String(), *port.EndPort))
+		default:
+			parts = append(parts, fmt.Sprintf("%s %s", protocol, port.Port.String()))
+		}
+	}
+	return "on " + strings.Join(parts, ", ")
+}
+
+// describePeers renders the peers of a rule
+func describePeers(peers []networkingv1.NetworkPolicyPeer, anyPeer string) string {
+	if len(peers) == 0 {
+		return anyPeer
+	}
+	parts := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		parts = append(parts, describePeer(peer))
+	}
+	return strings.Join(parts, "; or ")
+}
+
+// policyDirections reports whether a policy restricts ingress and egress.
+// Without policyTypes, ingress is always restricted and egress only when
+// the policy has egress rules.
+func policyDirections(policy *networkingv1.NetworkPolicy) (ingress, egress bool) {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return true, len(policy.Spec.Egress) > 0
+	}
+	for _, policyType := range policy.Spec.PolicyTypes {
+		switch policyType {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+	return ingress, egress
+}
+
+// explainNetworkPolicy explains in plain language the traffic a policy allows
+func explainNetworkPolicy(policy *networkingv1.NetworkPolicy) []string {
+	ingress, egress := policyDirections(policy)
+	var lines []string
+
+	if ingress {
+		if len(policy.Spec.Ingress) == 0 {
+			lines = append(lines, "🚫 Incoming: all traffic to these pods is denied")
+		}
+		for _, rule := range policy.Spec.Ingress {
+			lines = append(lines, fmt.Sprintf("✅ Incoming: allowed from %s %s", describePeers(rule.From, "any source"), describePorts(rule.Ports)))
+		}
+	} else {
+		lines = append(lines, "➖ Incoming: not restricted by this policy")
+	}
+
+	if egress {
+		if len(policy.Spec.Egress) == 0 {
+			lines = append(lines, "🚫 Outgoing: all traffic from these pods is denied, including DNS")
+		}
+		for _, rule := range policy.Spec.Egress {
+			lines = append(lines, fmt.Sprintf("✅ Outgoing: allowed to %s %s", describePeers(rule.To, "any destination"), describePorts(rule.Ports)))
+		}
+	} else {
+		lines = append(lines, "➖ Outgoing: not restricted by this policy")
+	}
+	return lines
+}
+
+// policySummary is the one-line summary of a policy in listings
+func policySummary(policy *networkingv1.NetworkPolicy) string {
+	ingress, egress := policyDirections(policy)
+	direction := func(restricted bool, rules int) string {
+		switch {
+		case !restricted:
+			return "not restricted"
+		case rules == 0:
+			return "deny all"
+		case rules == 1:
+			return "1 allow rule"
+		default:
+			return fmt.Sprintf("%d allow rules", rules)
+		}
+	}
+	return fmt.Sprintf("ingress %s, egress %s", direction(ingress, len(policy.Spec.Ingress)), direction(egress, len(policy.Spec.Egress)))
+}
+
+func (s *Server) listNetworkPoliciesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	policies, err := s.k8sClient.NetworkingV1().NetworkPolicies(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list NetworkPolicies in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	sort.SliceStable(policies.Items, func(i, j int) bool {
+		a, b := policies.Items[i], policies.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	result := "🛡️  NetworkPolicy List Results\n"
+	result += "=============================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+	result += fmt.Sprintf("📦 Found %d NetworkPolicies:\n", len(policies.Items))
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		name := policy.Name
+		if namespace == metav1.NamespaceAll {
+			name = policy.Namespace + "/" + policy.Name
+		}
+		result += fmt.Sprintf("🛡️  %s - pods: %s; %s; age %s\n", name, describeSelector(&policy.Spec.PodSelector, "all"),
+			policySummary(policy), formatAge(policy.CreationTimestamp.Time))
+	}
+	if len(policies.Items) == 0 {
+		result += "📭 None found - all traffic to and from pods in this namespace is allowed\n"
+	} else {
+		result += "\n💡 Policies are additive: a pod selected by several policies accepts the traffic any of them allows. Run explain_network_policy for details\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) explainNetworkPolicyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "policy_name", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ policy_name is required"), nil
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+
+	policy, err := s.k8sClient.NetworkingV1().NetworkPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get NetworkPolicy %s/%s", namespace, name), err), nil
+	}
+
+	result := "🛡️  NetworkPolicy Explanation\n"
+	result += "============================\n\n"
+	result += fmt.Sprintf("Policy: %s/%s\n", policy.Namespace, policy.Name)
+	result += fmt.Sprintf("Applies to: %s\n", describeSelector(&policy.Spec.PodSelector, "all pods in the namespace"))
+
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err == nil {
+		if pods, err := s.k8sClient.CoreV1().Pods(policy.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()}); err == nil {
+			names := make([]string, 0, len(pods.Items))
+			for _, pod := range pods.Items {
+				names = append(names, pod.Name)
+			}
+			sort.Strings(names)
+			result += fmt.Sprintf("Currently selects: %d pod(s)", len(names))
+			if len(names) > 0 && len(names) <= 10 {
+				result += " - " + strings.Join(names, ", ")
+			}
+			result += "\n"
+		}
+	}
+
+	result += "\n📋 Traffic:\n"
+	for _, line := range explainNetworkPolicy(policy) {
+		result += line + "\n"
+	}
+
+	result += "\n💡 Policies are additive: traffic is allowed when any policy selecting the pod allows it, and denied only when policies select the pod and none allow it"
+	if _, egress := policyDirections(policy); egress {
+		result += "\n💡 Egress is restricted: make sure DNS to the openshift-dns namespace is allowed (template allow-dns-egress)"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// parsePolicyPorts parses ports such as "8080,443/TCP,53/UDP"
+func parsePolicyPorts(value string) ([]networkingv1.NetworkPolicyPort, error) {
+	var ports []networkingv1.NetworkPolicyPort
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		number, protocolName, _ := strings.Cut(item, "/")
+		protocol := corev1.Protocol(strings.ToUpper(protocolName))
+		if protocolName == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		if protocol != corev1.ProtocolTCP && protocol != corev1.ProtocolUDP && protocol != corev1.ProtocolSCTP {
+			return nil, fmt.Errorf("invalid protocol in %q (expected TCP, UDP or SCTP)", item)
+		}
+		port, err := strconv.Atoi(number)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", item)
+		}
+		portValue := intstr.FromInt(port)
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &portValue})
+	}
+	return ports, nil
+}
+
+// namespaceSelector selects a namespace by name
+func namespaceSelector(name string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: name}}
+}
+
+// networkPolicyTemplate builds the spec of a generate_network_policy template
+func networkPolicyTemplate(template string, podSelector metav1.LabelSelector, fromNamespace string, fromPods *metav1.LabelSelector, ports []networkingv1.NetworkPolicyPort) (networkingv1.NetworkPolicySpec, error) {
+	spec := networkingv1.NetworkPolicySpec{PodSelector: podSelector}
+	allowFrom := func(peers ...networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicySpec {
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: peers, Ports: ports}}
+		return spec
+	}
+
+	switch template {
+	case "deny-all":
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+	case "deny-all-ingress":
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	case "deny-all-egress":
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+	case "allow-same-namespace":
+		return allowFrom(networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}), nil
+	case "allow-from-namespace":
+		if fromNamespace == "" {
+			return spec, fmt.Errorf("from_namespace is required for allow-from-namespace")
+		}
+		return allowFrom(networkingv1.NetworkPolicyPeer{NamespaceSelector: namespaceSelector(fromNamespace)}), nil
+	case "allow-from-pods":
+		if fromPods == nil {
+			return spec, fmt.Errorf("from_pod_selector is required for allow-from-pods")
+		}
+		peer := networkingv1.NetworkPolicyPeer{PodSelector: fromPods}
+		if fromNamespace != "" {
+			peer.NamespaceSelector = namespaceSelector(fromNamespace)
+		}
+		return allowFrom(peer), nil
+	case "allow-from-ingress":
+		return allowFrom(networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"network.openshift.io/policy-group": "ingress"},
+		}}), nil
+	case "allow-from-monitoring":
+		return allowFrom(networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"network.openshift.io/policy-group": "monitoring"},
+		}}), nil
+	case "allow-dns-egress":
+		// The cluster DNS pods listen on 5353; clients connect to the service on 53
+		dnsPorts, _ := parsePolicyPorts("53/UDP,53/TCP,5353/UDP,5353/TCP")
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+		spec.Egress = []networkingv1.NetworkPolicyEgressRule{{
+			To:    []networkingv1.NetworkPolicyPeer{{NamespaceSelector: namespaceSelector("openshift-dns")}},
+			Ports: dnsPorts,
+		}}
+	default:
+		return spec, fmt.Errorf("unknown template %q (expected %s)", template, strings.Join(sortedKeys(networkPolicyTemplates), ", "))
+	}
+	return spec, nil
+}
+
+func (s *Server) generateNetworkPolicyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	template := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "template", "")))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	name := mcp.ParseString(request, "name", template)
+	fromNamespace := strings.TrimSpace(mcp.ParseString(request, "from_namespace", ""))
+	saveToGit := parseBoolString(mcp.ParseString(request, "save_to_git", "false"))
+
+	if template == "" {
+		return mcp.NewToolResultText("❌ template is required"), nil
+	}
+
+	var podSelector metav1.LabelSelector
+	if value := strings.TrimSpace(mcp.ParseString(request, "pod_selector", "")); value != "" {
+		selector, err := metav1.ParseToLabelSelector(value)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid pod_selector %q: %v", value, err)), nil
+		}
+		podSelector = *selector
+	}
+	var fromPods *metav1.LabelSelector
+	if value := strings.TrimSpace(mcp.ParseString(request, "from_pod_selector", "")); value != "" {
+		selector, err := metav1.ParseToLabelSelector(value)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid from_pod_selector %q: %v", value, err)), nil
+		}
+		fromPods = selector
+	}
+	ports, err := parsePolicyPorts(mcp.ParseString(request, "ports", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	spec, err := networkPolicyTemplate(template, podSelector, fromNamespace, fromPods, ports)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	generator := s.yamlGenerator
+	if generator == nil {
+		generator = NewYAMLGenerator()
+	}
+	yamlContent, err := generator.GenerateNetworkPolicyYAML(name, namespace, spec)
+	if err != nil {
+		return toolError(ctx, "Failed to generate NetworkPolicy YAML", err), nil
+	}
+
+	result := fmt.Sprintf("📄 Generated NetworkPolicy: %s\n", template)
+	result += "=============================\n\n"
+	result += fmt.Sprintf("Purpose: %s\n\n", networkPolicyTemplates[template])
+	for _, line := range explainNetworkPolicy(&networkingv1.NetworkPolicy{Spec: spec}) {
+		result += line + "\n"
+	}
+	result += fmt.Sprintf("\n```yaml\n%s```\n\n", yamlContent)
+
+	if saveToGit && s.gitManager != nil && s.gitManager.IsEnabled() {
+		description := fmt.Sprintf("Generated NetworkPolicy %s (%s)", name, template)
+		if _, err := s.gitManager.SaveYAMLFile(ctx, "networkpolicy-"+name, yamlContent, "generate", description); err != nil {
+			result += fmt.Sprintf("⚠️  Failed to save to Git: %v\n", err)
+		} else {
+			result += "✅ YAML saved to Git repository successfully!\n"
+		}
+	}
+	result += "💡 Apply it with apply_yaml; a deny policy blocks traffic as soon as it exists"
+	return mcp.NewToolResultText(result), nil
+}
+
+// ListNetworkPoliciesHandler is a public wrapper for listNetworkPoliciesHandler
+func (s *Server) ListNetworkPoliciesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listNetworkPoliciesHandler(ctx, request)
+}
+
+// ExplainNetworkPolicyHandler is a public wrapper for explainNetworkPolicyHandler
+func (s *Server) ExplainNetworkPolicyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.explainNetworkPolicyHandler(ctx, request)
+}
+
+// GenerateNetworkPolicyHandler is a public wrapper for generateNetworkPolicyHandler
+func (s *Server) GenerateNetworkPolicyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.generateNetworkPolicyHandler(ctx, request)
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDescribePeer(t *testing.T) {
+	tests := []struct {
+		peer     networkingv1.NetworkPolicyPeer
+		expected string
+	}{
+		{networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}, "pods with any labels in the policy's namespace"},
+		{networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}}, "pods with app=api in the policy's namespace"},
+		{networkingv1.NetworkPolicyPeer{NamespaceSelector: namespaceSelector("shop")}, "all pods in namespace shop"},
+		{networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{}}, "all pods in all namespaces"},
+		{networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+		}, "pods with app=api in namespaces with team=web"},
+		{networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}}, "IP range 10.0.0.0/8 except 10.1.0.0/16"},
+	}
+	for _, tt := range tests {
+		if result := describePeer(tt.peer); result != tt.expected {
+			t.Errorf("describePeer(%+v) = %q, expected %q", tt.peer, result, tt.expected)
+		}
+	}
+}
+
+func TestParsePolicyPorts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"", "on all ports", true},
+		{"8080", "on TCP 8080", true},
+		{"8080, 53/udp", "on TCP 8080, UDP 53", true},
+		{"http", "", false},
+		{"70000", "", false},
+		{"53/ICMP", "", false},
+	}
+	for _, tt := range tests {
+		ports, err := parsePolicyPorts(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("parsePolicyPorts(%q) error = %v, expected valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		if tt.valid && describePorts(ports) != tt.expected {
+			t.Errorf("parsePolicyPorts(%q) = %q, expected %q", tt.input, describePorts(ports), tt.expected)
+		}
+	}
+}
+
+func TestNetworkPolicyTemplates(t *testing.T) {
+	web := metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	api := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}
+	ports, _ := parsePolicyPorts("8080")
+
+	tests := []struct {
+		template string
+		expected []string
+	}{
+		{"deny-all", []string{"🚫 Incoming: all traffic to these pods is denied", "🚫 Outgoing: all traffic from these pods is denied, including DNS"}},
+		{"deny-all-egress", []string{"➖ Incoming: not restricted by this policy", "🚫 Outgoing: all traffic from these pods is denied, including DNS"}},
+		{"allow-from-namespace", []string{"✅ Incoming: allowed from all pods in namespace frontend on TCP 8080", "➖ Outgoing: not restricted by this policy"}},
+		{"allow-from-pods", []string{"✅ Incoming: allowed from pods with app=api in namespace frontend on TCP 8080", "➖ Outgoing: not restricted by this policy"}},
+		{"allow-dns-egress", []string{"➖ Incoming: not restricted by this policy", "✅ Outgoing: allowed to all pods in namespace openshift-dns on UDP 53, TCP 53, UDP 5353, TCP 5353"}},
+	}
+	for _, tt := range tests {
+		spec, err := networkPolicyTemplate(tt.template, web, "frontend", api, ports)
+		if err != nil {
+			t.Errorf("networkPolicyTemplate(%q) error = %v", tt.template, err)
+			continue
+		}
+		if result := explainNetworkPolicy(&networkingv1.NetworkPolicy{Spec: spec}); strings.Join(result, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("networkPolicyTemplate(%q) explained as %q, expected %q", tt.template, result, tt.expected)
+		}
+	}
+
+	for _, template := range []string{"allow-from-namespace", "allow-from-pods", "allow-everything"} {
+		if _, err := networkPolicyTemplate(template, web, "", nil, nil); err == nil {
+			t.Errorf("networkPolicyTemplate(%q) without sources succeeded, expected an error", template)
+		}
+	}
+}
+
+func TestNetworkPolicyTools(t *testing.T) {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-api", Namespace: "shop"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}}},
+			}},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", Labels: map[string]string{"app": "web"}}}
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(policy, pod)}
+
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler(%v) error = %v", args, err)
+		}
+		return resultText(result)
+	}
+
+	if text := call(s.ListNetworkPoliciesHandler, map[string]interface{}{"namespace": "shop"}); !strings.Contains(text, "allow-api - pods: app=web; ingress 1 allow rule, egress not restricted") {
+		t.Errorf("list_network_policies output:\n%s", text)
+	}
+	text := call(s.ExplainNetworkPolicyHandler, map[string]interface{}{"policy_name": "allow-api", "namespace": "shop"})
+	for _, want := range []string{"Applies to: app=web", "Currently selects: 1 pod(s) - web-1", "✅ Incoming: allowed from pods with app=api in the policy's namespace on all ports"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("explain_network_policy output missing %q:\n%s", want, text)
+		}
+	}
+	text = call(s.GenerateNetworkPolicyHandler, map[string]interface{}{"template": "deny-all-ingress", "namespace": "shop", "pod_selector": "app=web"})
+	for _, want := range []string{"kind: NetworkPolicy", "name: deny-all-ingress", "namespace: shop", "- Ingress", "app: web"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("generate_network_policy output missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	defaultDrainTimeout = 300
+	maxDrainTimeout     = 1800
+
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+// drainPollInterval is how often a drain retries blocked evictions and
+// checks whether evicted pods are gone
+var drainPollInterval = 2 * time.Second
+
+// drainOptions mirror the flags of `oc adm drain`
+type drainOptions struct {
+	IgnoreDaemonSets   bool
+	DeleteEmptyDirData bool
+	Force              bool
+	DisableEviction    bool
+	GracePeriodSeconds int64 // -1 uses each pod's own grace period
+}
+
+// drainPlan sorts the pods on a node into those to evict, those left in
+// place and those that block the drain
+type drainPlan struct {
+	Evict    []corev1.Pod
+	Skipped  []string
+	Blockers []string
+}
+
+func podFinished(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// planDrain applies the same filters as `oc adm drain`
+func planDrain(pods []corev1.Pod, options drainOptions) drainPlan {
+	var plan drainPlan
+	for _, pod := range pods {
+		name := pod.Namespace + "/" + pod.Name
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			plan.Skipped = append(plan.Skipped, name+" (static pod)")
+			continue
+		}
+		controller := metav1.GetControllerOf(&pod)
+		if controller != nil && controller.Kind == "DaemonSet" {
+			if options.IgnoreDaemonSets {
+				plan.Skipped = append(plan.Skipped, name+" (DaemonSet)")
+			} else {
+				plan.Blockers = append(plan.Blockers, name+" is managed by DaemonSet "+controller.Name+"; set ignore_daemonsets=true")
+			}
+			continue
+		}
+		if controller == nil && !podFinished(&pod) && !options.Force {
+			plan.Blockers = append(plan.Blockers, name+" has no controller and would not be recreated; set force=true")
+			continue
+		}
+		if !podFinished(&pod) && !options.DeleteEmptyDirData {
+			if volume := emptyDirVolume(&pod); volume != "" {
+				plan.Blockers = append(plan.Blockers, name+" uses emptyDir volume "+volume+" whose data is lost; set delete_emptydir_data=true")
+				continue
+			}
+		}
+		plan.Evict = append(plan.Evict, pod)
+	}
+	return plan
+}
+
+func emptyDirVolume(pod *corev1.Pod) string {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return volume.Name
+		}
+	}
+	return ""
+}
+
+// setUnschedulable cordons or uncordons a node and reports whether it changed
+func (s *Server) setUnschedulable(ctx context.Context, node *corev1.Node, unschedulable bool) (bool, error) {
+	if node.Spec.Unschedulable == unschedulable {
+		return false, nil
+	}
+	node.Spec.Unschedulable = unschedulable
+	if _, err := s.k8sClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// evictPod evicts a pod, retrying while a PodDisruptionBudget refuses it
+func (s *Server) evictPod(ctx context.Context, pod *corev1.Pod, options drainOptions) error {
+	deleteOptions := &metav1.DeleteOptions{}
+	if options.GracePeriodSeconds >= 0 {
+		deleteOptions.GracePeriodSeconds = &options.GracePeriodSeconds
+	}
+	if options.DisableEviction {
+		return s.k8sClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, *deleteOptions)
+	}
+
+	for {
+		err := s.k8sClient.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			DeleteOptions: deleteOptions,
+		})
+		if err == nil || apierrors.IsNotFound(err) {
+			return nil
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("blocked by a PodDisruptionBudget: %v", err)
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// waitForPodsGone waits until the evicted pods are deleted or replaced and
+// returns the ones still present when ctx is done
+func (s *Server) waitForPodsGone(ctx context.Context, pods []corev1.Pod) []string {
+	pending := pods
+	for {
+		var remaining []corev1.Pod
+		for _, pod := range pending {
+			current, err := s.k8sClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+				continue
+			}
+			remaining = append(remaining, pod)
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		pending = remaining
+
+		select {
+		case <-ctx.Done():
+			var names []string
+			for _, pod := range remaining {
+				names = append(names, pod.Namespace+"/"+pod.Name)
+			}
+			return names
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// saveNodeAction records a node lifecycle action in the Git repository
+func (s *Server) saveNodeAction(ctx context.Context, action, nodeName, description string, yamlContent string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("\n⚠️  Failed to generate YAML: %v", err)
+	}
+	if _, err := s.gitManager.SaveYAMLFile(ctx, fmt.Sprintf("%s-%s", action, nodeName), yamlContent, action, description); err != nil {
+		return fmt.Sprintf("\n⚠️  Failed to save to Git: %v", err)
+	}
+	return fmt.Sprintf("\n✅ %s action YAML saved to Git repository!", strings.ToUpper(action[:1])+action[1:])
+}
+
+func (s *Server) cordonHandler(ctx context.Context, request mcp.CallToolRequest, cordon bool) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	nodeName := strings.TrimSpace(mcp.ParseString(request, "node_name", ""))
+	if nodeName == "" {
+		return mcp.NewToolResultText("❌ node_name is required"), nil
+	}
+	saveToGit := parseBoolString(mcp.ParseString(request, "save_to_git", "true"))
+
+	action, title := "cordon", "🚧 Cordon Node\n"
+	if !cordon {
+		action, title = "uncordon", "✅ Uncordon Node\n"
+	}
+
+	node, err := s.k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get node %s", nodeName), err), nil
+	}
+	changed, err := s.setUnschedulable(ctx, node, cordon)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to %s node %s", action, nodeName), err), nil
+	}
+
+	if changed {
+		reason := ReasonCordoned
+		if !cordon {
+			reason = ReasonUncordoned
+		}
+		s.recordAction(ctx, action+"_node", objectReference("v1", "Node", node), reason, fmt.Sprintf("Node %sed", action))
+	}
+
+	result := title
+	result += "===============\n\n"
+	result += fmt.Sprintf("Node: %s\n", nodeName)
+	switch {
+	case !changed && cordon:
+		result += "ℹ️  Node was already cordoned; nothing changed"
+		return mcp.NewToolResultText(result), nil
+	case !changed:
+		result += "ℹ️  Node was already schedulable; nothing changed"
+		return mcp.NewToolResultText(result), nil
+	case cordon:
+		result += "✅ Node cordoned: no new pods will be scheduled on it. Running pods are not affected."
+	default:
+		result += "✅ Node uncordoned: new pods can be scheduled on it again."
+	}
+
+	if saveToGit && s.gitManager.IsEnabled() {
+		yamlContent, err := s.yamlGenerator.GenerateCordonActionYAML(nodeName, cordon)
+		result += s.saveNodeAction(ctx, action, nodeName, fmt.Sprintf("%s node %s", strings.ToUpper(action[:1])+action[1:], nodeName), yamlContent, err)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) cordonNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.cordonHandler(ctx, request, true)
+}
+
+func (s *Server) uncordonNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.cordonHandler(ctx, request, false)
+}
+
+func (s *Server) drainNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	nodeName := strings.TrimSpace(mcp.ParseString(request, "node_name", ""))
+	if nodeName == "" {
+		return mcp.NewToolResultText("❌ node_name is required"), nil
+	}
+
+	options := drainOptions{
+		IgnoreDaemonSets:   parseBoolString(mcp.ParseString(request, "ignore_daemonsets", "true")),
+		DeleteEmptyDirData: parseBoolString(mcp.ParseString(request, "delete_emptydir_data", "false")),
+		Force:              parseBoolString(mcp.ParseString(request, "force", "false")),
+		DisableEviction:    parseBoolString(mcp.ParseString(request, "disable_eviction", "false")),
+		GracePeriodSeconds: -1,
+	}
+	if value := mcp.ParseString(request, "grace_period_seconds", ""); value != "" {
+		grace, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || grace < -1 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid grace_period_seconds value: %s", value)), nil
+		}
+		options.GracePeriodSeconds = grace
+	}
+	timeoutValue := mcp.ParseString(request, "timeout_seconds", strconv.Itoa(defaultDrainTimeout))
+	timeout, err := strconv.Atoi(timeoutValue)
+	if err != nil || timeout <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid timeout_seconds value: %s", timeoutValue)), nil
+	}
+	if timeout > maxDrainTimeout {
+		timeout = maxDrainTimeout
+	}
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+	saveToGit := parseBoolString(mcp.ParseString(request, "save_to_git", "true"))
+
+	node, err := s.k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get node %s", nodeName), err), nil
+	}
+	pods, err := s.k8sClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list pods on node %s", nodeName), err), nil
+	}
+	var onNode []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == nodeName {
+			onNode = append(onNode, pod)
+		}
+	}
+	sort.Slice(onNode, func(i, j int) bool {
+		return onNode[i].Namespace+"/"+onNode[i].Name < onNode[j].Namespace+"/"+onNode[j].Name
+	})
+	plan := planDrain(onNode, options)
+
+	result := "🚜 Drain Node\n"
+	result += "============\n\n"
+	result += fmt.Sprintf("Node: %s\n", nodeName)
+	method := "eviction API (respects PodDisruptionBudgets)"
+	if options.DisableEviction {
+		method = "direct deletion (bypasses PodDisruptionBudgets)"
+	}
+	result += fmt.Sprintf("Method: %s\n", method)
+
+	if len(plan.Blockers) > 0 {
+		result += "\n🚫 Drain refused, nothing was changed:\n"
+		for _, blocker := range plan.Blockers {
+			result += fmt.Sprintf("• %s\n", blocker)
+		}
+		return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+	}
+
+	result += fmt.Sprintf("\n📦 Pods to evict (%d):\n", len(plan.Evict))
+	for _, pod := range plan.Evict {
+		result += fmt.Sprintf("• %s/%s\n", pod.Namespace, pod.Name)
+	}
+	if len(plan.Skipped) > 0 {
+		result += fmt.Sprintf("\n⏭️  Left in place (%d):\n", len(plan.Skipped))
+		for _, name := range plan.Skipped {
+			result += fmt.Sprintf("• %s\n", name)
+		}
+	}
+	if dryRun {
+		result += "\n🧪 Dry run: the node was not cordoned and no pods were evicted"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	if _, err := s.setUnschedulable(ctx, node, true); err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to cordon node %s", nodeName), err), nil
+	}
+	result += "\n🚧 Node cordoned\n"
+
+	drainCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	var evicted []corev1.Pod
+	var failed []string
+	for i := range plan.Evict {
+		pod := &plan.Evict[i]
+		if err := s.evictPod(drainCtx, pod, options); err != nil {
+			failed = append(failed, fmt.Sprintf("%s/%s: %v", pod.Namespace, pod.Name, err))
+			continue
+		}
+		evicted = append(evicted, *pod)
+	}
+	remaining := s.waitForPodsGone(drainCtx, evicted)
+	s.recordAction(ctx, "drain_node", objectReference("v1", "Node", node), ReasonDrained,
+		fmt.Sprintf("Node cordoned and %d of %d pods evicted", len(evicted)-len(remaining), len(plan.Evict)))
+
+	result += fmt.Sprintf("✅ %d of %d pods evicted and gone\n", len(evicted)-len(remaining), len(plan.Evict))
+	if len(remaining) > 0 {
+		result += fmt.Sprintf("⏳ Still terminating after %ds: %s\n", timeout, strings.Join(remaining, ", "))
+	}
+	if len(failed) > 0 {
+		result += "❌ Not evicted:\n"
+		for _, failure := range failed {
+			result += fmt.Sprintf("• %s\n", failure)
+		}
+	}
+	if len(remaining) == 0 && len(failed) == 0 {
+		result += "\n✅ Node drained. Run uncordon_node when maintenance is done."
+	} else {
+		result += "\n⚠️  Drain incomplete; the node stays cordoned. Re-run drain_node to retry."
+	}
+
+	if saveToGit && s.gitManager.IsEnabled() {
+		var evictedNames []string
+		for _, pod := range evicted {
+			evictedNames = append(evictedNames, pod.Namespace+"/"+pod.Name)
+		}
+		yamlContent, err := s.yamlGenerator.GenerateDrainActionYAML(nodeName, evictedNames, failed, map[string]interface{}{
+			"ignoreDaemonSets":   options.IgnoreDaemonSets,
+			"deleteEmptyDirData": options.DeleteEmptyDirData,
+			"force":              options.Force,
+			"disableEviction":    options.DisableEviction,
+			"gracePeriodSeconds": options.GracePeriodSeconds,
+		})
+		result += s.saveNodeAction(ctx, "drain", nodeName, fmt.Sprintf("Drain node %s, %d pods evicted", nodeName, len(evicted)), yamlContent, err)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// CordonNodeHandler is a public wrapper for cordonNodeHandler
+func (s *Server) CordonNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.cordonNodeHandler(ctx, request)
+}
+
+// UncordonNodeHandler is a public wrapper for uncordonNodeHandler
+func (s *Server) UncordonNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.uncordonNodeHandler(ctx, request)
+}
+
+// DrainNodeHandler is a public wrapper for drainNodeHandler
+func (s *Server) DrainNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.drainNodeHandler(ctx, request)
+}
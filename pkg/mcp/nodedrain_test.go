@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func drainTestPod(name, controllerKind string, volumes ...corev1.Volume) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: k8stypes.UID("uid-" + name)},
+		Spec:       corev1.PodSpec{NodeName: "worker-1", Volumes: volumes},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if controllerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: controllerKind, Name: name + "-owner", Controller: &controller}}
+	}
+	return pod
+}
+
+func TestPlanDrain(t *testing.T) {
+	emptyDir := corev1.Volume{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	mirror := drainTestPod("etcd", "")
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+	finished := drainTestPod("job-done", "")
+	finished.Status.Phase = corev1.PodSucceeded
+	pods := []corev1.Pod{
+		drainTestPod("api", "ReplicaSet"),
+		drainTestPod("fluentd", "DaemonSet"),
+		drainTestPod("bare", ""),
+		drainTestPod("cache", "ReplicaSet", emptyDir),
+		mirror,
+		finished,
+	}
+
+	tests := []struct {
+		options  drainOptions
+		evict    string
+		blockers int
+	}{
+		{drainOptions{IgnoreDaemonSets: true}, "api,job-done", 2},
+		{drainOptions{IgnoreDaemonSets: true, Force: true, DeleteEmptyDirData: true}, "api,bare,cache,job-done", 0},
+		{drainOptions{Force: true, DeleteEmptyDirData: true}, "api,bare,cache,job-done", 1},
+	}
+
+	for _, tt := range tests {
+		plan := planDrain(pods, tt.options)
+		var evict []string
+		for _, pod := range plan.Evict {
+			evict = append(evict, pod.Name)
+		}
+		if strings.Join(evict, ",") != tt.evict || len(plan.Blockers) != tt.blockers {
+			t.Errorf("planDrain(%+v) evicts %v with blockers %v, expected %s with %d blockers", tt.options, evict, plan.Blockers, tt.evict, tt.blockers)
+		}
+	}
+}
+
+// newDrainTestServer evicts pods by deleting them, as the API server would
+func newDrainTestServer(t *testing.T, objects ...runtime.Object) *Server {
+	client := kubefake.NewSimpleClientset(objects...)
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create := action.(k8stesting.CreateAction)
+		if create.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := create.GetObject().(*policyv1.Eviction)
+		gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		return true, nil, client.Tracker().Delete(gvr, eviction.Namespace, eviction.Name)
+	})
+
+	previous := drainPollInterval
+	drainPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { drainPollInterval = previous })
+
+	return &Server{
+		config:        &Config{},
+		k8sClient:     client,
+		gitManager:    NewGitManager(&GitConfig{Enabled: true, RepoPath: t.TempDir()}),
+		yamlGenerator: NewYAMLGenerator(),
+	}
+}
+
+func TestCordonNode(t *testing.T) {
+	s := newDrainTestServer(t, testNode("worker-1", corev1.ConditionTrue, "worker"))
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"node_name": "worker-1"}
+	result, _ := s.cordonNodeHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "✅ Node cordoned") || !strings.Contains(text, "saved to Git") {
+		t.Errorf("cordon_node = %q", text)
+	}
+	node, _ := s.k8sClient.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Errorf("cordon_node left the node schedulable")
+	}
+
+	result, _ = s.cordonNodeHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "already cordoned") {
+		t.Errorf("second cordon_node = %q", text)
+	}
+
+	result, _ = s.uncordonNodeHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "✅ Node uncordoned") {
+		t.Errorf("uncordon_node = %q", text)
+	}
+	records, _ := filepath.Glob(filepath.Join(s.gitManager.config.RepoPath, "actions", "*", "*.yaml"))
+	if len(records) != 2 {
+		t.Errorf("action records = %v, expected a cordon and an uncordon record", records)
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	api, fluentd, bare := drainTestPod("api", "ReplicaSet"), drainTestPod("fluentd", "DaemonSet"), drainTestPod("bare", "")
+	s := newDrainTestServer(t, testNode("worker-1", corev1.ConditionTrue, "worker"), &api, &fluentd, &bare)
+	ctx := context.Background()
+
+	// An unmanaged pod blocks the drain before anything changes
+	request := mcp.CallToolRequest{}
+	args := map[string]interface{}{"node_name": "worker-1"}
+	request.Params.Arguments = args
+	result, _ := s.drainNodeHandler(ctx, request)
+	if text := resultText(result); !strings.Contains(text, "Drain refused") || !strings.Contains(text, "shop/bare has no controller") {
+		t.Errorf("drain_node with an unmanaged pod = %q", text)
+	}
+	if node, _ := s.k8sClient.CoreV1().Nodes().Get(ctx, "worker-1", metav1.GetOptions{}); node.Spec.Unschedulable {
+		t.Errorf("refused drain cordoned the node")
+	}
+
+	args["force"] = "true"
+	args["dry_run"] = "true"
+	result, _ = s.drainNodeHandler(ctx, request)
+	if text := resultText(result); !strings.Contains(text, "Pods to evict (2)") || !strings.Contains(text, "shop/fluentd (DaemonSet)") || !strings.Contains(text, "Dry run") {
+		t.Errorf("drain_node dry run = %q", text)
+	}
+
+	args["dry_run"] = "false"
+	result, _ = s.drainNodeHandler(ctx, request)
+	text := resultText(result)
+	for _, expected := range []string{"🚧 Node cordoned", "✅ 2 of 2 pods evicted and gone", "✅ Node drained", "Drain action YAML saved"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("drain_node result missing %q:\n%s", expected, text)
+		}
+	}
+	pods, _ := s.k8sClient.CoreV1().Pods("shop").List(ctx, metav1.ListOptions{})
+	if len(pods.Items) != 1 || pods.Items[0].Name != "fluentd" {
+		t.Errorf("pods left after drain = %v, expected only the DaemonSet pod", pods.Items)
+	}
+	records, _ := filepath.Glob(filepath.Join(s.gitManager.config.RepoPath, "actions", "drain", "*.yaml"))
+	if len(records) != 1 {
+		t.Fatalf("drain action records = %v", records)
+	}
+	record, _ := os.ReadFile(records[0])
+	if !strings.Contains(string(record), "kind: ActionRecord") || !strings.Contains(string(record), "action: drain") || !strings.Contains(string(record), "- shop/api") {
+		t.Errorf("drain action record:\n%s", record)
+	}
+}
@@ -0,0 +1,345 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+// nodePressureConditions are the conditions that are healthy when False
+var nodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// nodeRoles returns the roles from node-role.kubernetes.io/<role> labels
+func nodeRoles(node *corev1.Node) []string {
+	var roles []string
+	for key := range node.Labels {
+		if role := strings.TrimPrefix(key, nodeRoleLabelPrefix); role != key && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return []string{"none"}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// nodeCondition returns the status of a node condition, or Unknown when it is not reported
+func nodeCondition(node *corev1.Node, conditionType corev1.NodeConditionType) (corev1.ConditionStatus, *corev1.NodeCondition) {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == conditionType {
+			return node.Status.Conditions[i].Status, &node.Status.Conditions[i]
+		}
+	}
+	return corev1.ConditionUnknown, nil
+}
+
+// nodeStatus summarizes readiness the way `oc get nodes` does, e.g. "Ready,SchedulingDisabled"
+func nodeStatus(node *corev1.Node) string {
+	status := "NotReady"
+	switch ready, _ := nodeCondition(node, corev1.NodeReady); ready {
+	case corev1.ConditionTrue:
+		status = "Ready"
+	case corev1.ConditionUnknown:
+		status = "Unknown"
+	}
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+	return status
+}
+
+// nodePressures lists the pressure conditions that are currently set
+func nodePressures(node *corev1.Node) []string {
+	var pressures []string
+	for _, conditionType := range nodePressureConditions {
+		if status, _ := nodeCondition(node, conditionType); status == corev1.ConditionTrue {
+			pressures = append(pressures, string(conditionType))
+		}
+	}
+	return pressures
+}
+
+func formatTaint(taint corev1.Taint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+}
+
+// formatMemory renders a memory quantity in GiB, which is how node sizes are usually discussed
+func formatMemory(quantity resource.Quantity) string {
+	return fmt.Sprintf("%.1fGi", float64(quantity.Value())/(1024*1024*1024))
+}
+
+func formatPercent(used, total resource.Quantity) string {
+	if total.IsZero() {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d%%", used.MilliValue()*100/total.MilliValue())
+}
+
+// podRequests sums the CPU and memory requests of a pod's containers
+func podRequests(pod *corev1.Pod) (cpu, memory resource.Quantity) {
+	for _, container := range pod.Spec.Containers {
+		if request, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			cpu.Add(request)
+		}
+		if request, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			memory.Add(request)
+		}
+	}
+	return cpu, memory
+}
+
+func (s *Server) initNodes() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_nodes",
+			mcp.WithDescription("List nodes with roles, readiness, pressure conditions, kubelet version and allocatable CPU and memory"),
+			mcp.WithString("role", mcp.Description("Only list nodes with this role, e.g. master, worker or infra")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. topology.kubernetes.io/zone=us-east-1a")),
+			mcp.WithTitleAnnotation("Nodes: List"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listNodesHandler)},
+		{Tool: mcp.NewTool("describe_node",
+			mcp.WithDescription("Summarize a node's conditions, taints, capacity, requested resources and the pods scheduled on it"),
+			mcp.WithString("node_name", mcp.Description("Name of the node"), mcp.Required()),
+			mcp.WithTitleAnnotation("Nodes: Describe"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.describeNodeHandler)},
+		{Tool: mcp.NewTool("cordon_node",
+			mcp.WithDescription("Mark a node unschedulable so no new pods land on it; running pods keep running"),
+			mcp.WithString("node_name", mcp.Description("Name of the node"), mcp.Required()),
+			mcp.WithString("save_to_git", mcp.Description("Save the cordon action YAML to the Git repository when Git is enabled (true/false, default true)")),
+			mcp.WithTitleAnnotation("Nodes: Cordon"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.cordonNodeHandler)},
+		{Tool: mcp.NewTool("uncordon_node",
+			mcp.WithDescription("Mark a node schedulable again after maintenance"),
+			mcp.WithString("node_name", mcp.Description("Name of the node"), mcp.Required()),
+			mcp.WithString("save_to_git", mcp.Description("Save the uncordon action YAML to the Git repository when Git is enabled (true/false, default true)")),
+			mcp.WithTitleAnnotation("Nodes: Uncordon"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.uncordonNodeHandler)},
+		{Tool: mcp.NewTool("drain_node",
+			mcp.WithDescription("Cordon a node and evict its pods through the eviction API, respecting PodDisruptionBudgets, like oc adm drain"),
+			mcp.WithString("node_name", mcp.Description("Name of the node"), mcp.Required()),
+			mcp.WithString("ignore_daemonsets", mcp.Description("Leave DaemonSet pods in place instead of refusing to drain (true/false, default true)")),
+			mcp.WithString("delete_emptydir_data", mcp.Description("Evict pods with emptyDir volumes, losing their data (true/false, default false)")),
+			mcp.WithString("force", mcp.Description("Evict pods without a controller, which will not be recreated (true/false, default false)")),
+			mcp.WithString("disable_eviction", mcp.Description("Delete pods directly, bypassing PodDisruptionBudgets (true/false, default false)")),
+			mcp.WithString("grace_period_seconds", mcp.Description("Termination grace period for evicted pods (default: each pod's own)")),
+			mcp.WithString("timeout_seconds", mcp.Description("Seconds to wait for pods to be evicted and gone (default 300, max 1800)")),
+			mcp.WithString("dry_run", mcp.Description("Only list the pods that would be evicted (true/false)")),
+			mcp.WithString("save_to_git", mcp.Description("Save the drain action YAML to the Git repository when Git is enabled (true/false, default true)")),
+			mcp.WithTitleAnnotation("Nodes: Drain"),
+			mcp.WithDestructiveHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.drainNodeHandler)},
+	}
+}
+
+func (s *Server) listNodesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	role := strings.TrimSpace(mcp.ParseString(request, "role", ""))
+	selector := strings.TrimSpace(mcp.ParseString(request, "label_selector", ""))
+	if role != "" {
+		roleSelector := nodeRoleLabelPrefix + role
+		if selector != "" {
+			roleSelector += "," + selector
+		}
+		selector = roleSelector
+	}
+	if selector != "" {
+		if _, err := labels.Parse(selector); err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ invalid label_selector %q: %v", selector, err)), nil
+		}
+	}
+
+	nodes, err := s.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return toolError(ctx, "Failed to list nodes", err), nil
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+
+	result := "🖥️  Node List Results\n"
+	result += "====================\n\n"
+	if selector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", selector)
+	}
+
+	notReady := 0
+	for i := range nodes.Items {
+		if !strings.HasPrefix(nodeStatus(&nodes.Items[i]), "Ready") {
+			notReady++
+		}
+	}
+	result += fmt.Sprintf("📦 Found %d nodes", len(nodes.Items))
+	if notReady > 0 {
+		result += fmt.Sprintf(", ⚠️  %d not ready", notReady)
+	}
+	result += ":\n"
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		status := nodeStatus(node)
+		icon := "✅"
+		if !strings.HasPrefix(status, "Ready") {
+			icon = "❌"
+		} else if pressures := nodePressures(node); len(pressures) > 0 || node.Spec.Unschedulable {
+			icon = "⚠️ "
+		}
+		allocatable := node.Status.Allocatable
+		result += fmt.Sprintf("%s %s - %s, roles %s, kubelet %s, age %s\n", icon, node.Name, status,
+			strings.Join(nodeRoles(node), ","), valueOrNone(node.Status.NodeInfo.KubeletVersion), formatAge(node.CreationTimestamp.Time))
+		result += fmt.Sprintf("   Allocatable: cpu %s, memory %s, pods %s\n",
+			allocatable.Cpu().String(), formatMemory(*allocatable.Memory()), allocatable.Pods().String())
+		if pressures := nodePressures(node); len(pressures) > 0 {
+			result += fmt.Sprintf("   Pressure: %s\n", strings.Join(pressures, ", "))
+		}
+	}
+	if len(nodes.Items) == 0 {
+		result += "📭 None found\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) describeNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "node_name", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ node_name is required"), nil
+	}
+
+	node, err := s.k8sClient.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get node %s", name), err), nil
+	}
+
+	result := fmt.Sprintf("🖥️  Node %s\n", node.Name)
+	result += "====================\n\n"
+	result += fmt.Sprintf("Status: %s\n", nodeStatus(node))
+	result += fmt.Sprintf("Roles: %s\n", strings.Join(nodeRoles(node), ", "))
+	info := node.Status.NodeInfo
+	result += fmt.Sprintf("Kubelet: %s, Runtime: %s\n", valueOrNone(info.KubeletVersion), valueOrNone(info.ContainerRuntimeVersion))
+	result += fmt.Sprintf("OS: %s, Kernel: %s\n", valueOrNone(info.OSImage), valueOrNone(info.KernelVersion))
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			result += fmt.Sprintf("Internal IP: %s\n", address.Address)
+		}
+	}
+	result += fmt.Sprintf("Age: %s\n", formatAge(node.CreationTimestamp.Time))
+
+	// Conditions
+	result += "\n🩺 Conditions:\n"
+	if status, condition := nodeCondition(node, corev1.NodeReady); condition == nil {
+		result += "❓ Ready: not reported\n"
+	} else if status == corev1.ConditionTrue {
+		result += "✅ Ready\n"
+	} else {
+		result += fmt.Sprintf("❌ Ready=%s: %s %s\n", status, condition.Reason, condition.Message)
+	}
+	for _, conditionType := range nodePressureConditions {
+		status, condition := nodeCondition(node, conditionType)
+		switch {
+		case condition == nil:
+			continue
+		case status == corev1.ConditionTrue:
+			result += fmt.Sprintf("⚠️  %s since %s: %s\n", conditionType, formatTime(ctx, condition.LastTransitionTime.Time), condition.Message)
+		default:
+			result += fmt.Sprintf("✅ No %s\n", conditionType)
+		}
+	}
+
+	// Taints
+	result += "\n🚧 Taints:\n"
+	if node.Spec.Unschedulable {
+		result += "• Cordoned (scheduling disabled)\n"
+	}
+	if len(node.Spec.Taints) == 0 {
+		result += "• None\n"
+	}
+	for _, taint := range node.Spec.Taints {
+		result += fmt.Sprintf("• %s\n", formatTaint(taint))
+	}
+
+	// Pods and requested resources
+	pods, err := s.k8sClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name).String(),
+	})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list pods on node %s", node.Name), err), nil
+	}
+	phases := make(map[string]int)
+	var cpuRequests, memoryRequests resource.Quantity
+	var notReady []string
+	scheduled := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != node.Name {
+			continue
+		}
+		phases[string(pod.Status.Phase)]++
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		scheduled++
+		cpu, memory := podRequests(pod)
+		cpuRequests.Add(cpu)
+		memoryRequests.Add(memory)
+		if pod.Status.Phase == corev1.PodRunning && !podReady(pod) {
+			notReady = append(notReady, pod.Namespace+"/"+pod.Name)
+		}
+	}
+
+	allocatable := node.Status.Allocatable
+	result += "\n📊 Resources (requested / allocatable):\n"
+	result += fmt.Sprintf("• CPU: %s / %s (%s)\n", cpuRequests.String(), allocatable.Cpu().String(), formatPercent(cpuRequests, *allocatable.Cpu()))
+	result += fmt.Sprintf("• Memory: %s / %s (%s)\n", formatMemory(memoryRequests), formatMemory(*allocatable.Memory()),
+		formatPercent(memoryRequests, *allocatable.Memory()))
+	result += fmt.Sprintf("• Pods: %d / %s\n", scheduled, allocatable.Pods().String())
+
+	result += "\n📦 Pods:\n"
+	if len(phases) == 0 {
+		result += "• None scheduled\n"
+	}
+	for _, phase := range sortedKeys(phases) {
+		result += fmt.Sprintf("• %s: %d\n", phase, phases[phase])
+	}
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		result += fmt.Sprintf("⚠️  Running but not ready: %s\n", strings.Join(notReady, ", "))
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ListNodesHandler is a public wrapper for listNodesHandler
+func (s *Server) ListNodesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listNodesHandler(ctx, request)
+}
+
+// DescribeNodeHandler is a public wrapper for describeNodeHandler
+func (s *Server) DescribeNodeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.describeNodeHandler(ctx, request)
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func testNode(name string, ready corev1.ConditionStatus, roles ...string) *corev1.Node {
+	labels := map[string]string{}
+	for _, role := range roles {
+		labels[nodeRoleLabelPrefix+role] = ""
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+				corev1.ResourcePods:   resource.MustParse("250"),
+			},
+			NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.29.5"},
+		},
+	}
+}
+
+func TestNodeStatus(t *testing.T) {
+	cordoned := testNode("a", corev1.ConditionTrue)
+	cordoned.Spec.Unschedulable = true
+
+	tests := []struct {
+		node   *corev1.Node
+		status string
+		roles  string
+	}{
+		{testNode("a", corev1.ConditionTrue, "worker", "infra"), "Ready", "infra,worker"},
+		{testNode("a", corev1.ConditionFalse, "master"), "NotReady", "master"},
+		{testNode("a", corev1.ConditionUnknown), "Unknown", "none"},
+		{cordoned, "Ready,SchedulingDisabled", "none"},
+	}
+
+	for _, tt := range tests {
+		if status := nodeStatus(tt.node); status != tt.status {
+			t.Errorf("nodeStatus(%v) = %s, expected %s", tt.node.Status.Conditions, status, tt.status)
+		}
+		if roles := strings.Join(nodeRoles(tt.node), ","); roles != tt.roles {
+			t.Errorf("nodeRoles(%v) = %s, expected %s", tt.node.Labels, roles, tt.roles)
+		}
+	}
+}
+
+func TestListNodes(t *testing.T) {
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(
+		testNode("worker-1", corev1.ConditionTrue, "worker"),
+		testNode("worker-2", corev1.ConditionFalse, "worker"),
+		testNode("master-1", corev1.ConditionTrue, "master"),
+	)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"role": "worker"}
+	result, _ := s.listNodesHandler(context.Background(), request)
+	text := resultText(result)
+	for _, expected := range []string{
+		"Found 2 nodes, ⚠️  1 not ready",
+		"✅ worker-1 - Ready, roles worker, kubelet v1.29.5",
+		"❌ worker-2 - NotReady",
+		"Allocatable: cpu 4, memory 16.0Gi, pods 250",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("list_nodes result missing %q:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "master-1") {
+		t.Errorf("list_nodes with role=worker listed a master:\n%s", text)
+	}
+}
+
+func TestDescribeNode(t *testing.T) {
+	node := testNode("worker-1", corev1.ConditionTrue, "worker")
+	node.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	node.Status.Conditions = append(node.Status.Conditions,
+		corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Message: "ephemeral storage low"},
+		corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse})
+
+	pod := func(name, nodeName string, phase corev1.PodPhase, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{
+				Name:      "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(node,
+		pod("api-1", "worker-1", corev1.PodRunning, "500m"),
+		pod("api-2", "worker-1", corev1.PodRunning, "1500m"),
+		pod("job-1", "worker-1", corev1.PodSucceeded, "2"),
+		pod("web-1", "worker-2", corev1.PodRunning, "1"),
+	)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"node_name": "worker-1"}
+	result, _ := s.describeNodeHandler(context.Background(), request)
+	text := resultText(result)
+	for _, expected := range []string{
+		"✅ Ready",
+		"⚠️  DiskPressure since",
+		"✅ No MemoryPressure",
+		"• dedicated=gpu:NoSchedule",
+		"• CPU: 2 / 4 (50%)",
+		"• Pods: 2 / 250",
+		"• Running: 2",
+		"• Succeeded: 1",
+		"Running but not ready: shop/api-1, shop/api-2",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("describe_node result missing %q:\n%s", expected, text)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	healthCheckJobName = "health-check"
+
+	routeSlack   = "slack"
+	routeWebhook = "webhook"
+
+	// maxNotificationDetails bounds the report text sent in a single message
+	maxNotificationDetails = 3000
+)
+
+// NotificationRoute is a destination for findings: a Slack incoming webhook,
+// optionally overriding its channel, or a generic JSON webhook such as a
+// ticket queue
+type NotificationRoute struct {
+	Type    string `json:"type"` // slack or webhook
+	URL     string `json:"url"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// Describe renders the route for reports
+func (r NotificationRoute) Describe() string {
+	if r.URL == "" {
+		return "none"
+	}
+	if r.Type == routeSlack && r.Channel != "" {
+		return fmt.Sprintf("Slack %s", r.Channel)
+	}
+	if r.Type == routeSlack {
+		return "Slack (webhook default channel)"
+	}
+	return fmt.Sprintf("webhook %s", r.URL)
+}
+
+// NotificationConfig routes findings to the owning team. A team route wins,
+// then the owner's slack-channel annotation through SlackWebhookURL, then
+// the default route.
+type NotificationConfig struct {
+	SlackWebhookURL string                       `json:"slack_webhook_url"`
+	DefaultRoute    NotificationRoute            `json:"default_route"`
+	TeamRoutes      map[string]NotificationRoute `json:"team_routes"`
+
+	// HealthCheckInterval schedules checks for failing pods; empty disables them
+	HealthCheckInterval   string   `json:"health_check_interval"`
+	HealthCheckNamespaces []string `json:"health_check_namespaces"`
+}
+
+// Finding is a problem to report to the owners of a resource
+type Finding struct {
+	Title        string    `json:"title"`
+	Severity     string    `json:"severity"`
+	Source       string    `json:"source"` // health-check, alert or manual
+	Namespace    string    `json:"namespace"`
+	ResourceType string    `json:"resource_type,omitempty"`
+	ResourceName string    `json:"resource_name,omitempty"`
+	Details      string    `json:"details,omitempty"`
+	Ownership    Ownership `json:"-"`
+	Time         time.Time `json:"time"`
+}
+
+func (f Finding) resource() string {
+	if f.ResourceName == "" {
+		return "namespace " + f.Namespace
+	}
+	return fmt.Sprintf("%s %s/%s", f.ResourceType, f.Namespace, f.ResourceName)
+}
+
+// NotificationRouter resolves and delivers notifications
+type NotificationRouter struct {
+	config *NotificationConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	notified map[string]bool // health check findings already sent
+}
+
+// NewNotificationRouter creates a router; a nil config routes nothing
+func NewNotificationRouter(config *NotificationConfig) *NotificationRouter {
+	if config == nil {
+		config = &NotificationConfig{}
+	}
+	return &NotificationRouter{
+		config:   config,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		notified: make(map[string]bool),
+	}
+}
+
+// Configured reports whether any destination is set up
+func (r *NotificationRouter) Configured() bool {
+	return r.config.SlackWebhookURL != "" || r.config.DefaultRoute.URL != "" || len(r.config.TeamRoutes) > 0
+}
+
+// Route picks the destination for an owner and explains the choice
+func (r *NotificationRouter) Route(ownership Ownership) (NotificationRoute, string) {
+	for team, route := range r.config.TeamRoutes {
+		// Team names are matched case-insensitively since config keys are lowercased
+		if ownership.Team != "" && strings.EqualFold(team, ownership.Team) && route.URL != "" {
+			return withRouteType(route), fmt.Sprintf("route configured for team %s", ownership.Team)
+		}
+	}
+	if ownership.SlackChannel != "" && r.config.SlackWebhookURL != "" {
+		channel := ownership.SlackChannel
+		if !strings.HasPrefix(channel, "#") {
+			channel = "#" + channel
+		}
+		return NotificationRoute{Type: routeSlack, URL: r.config.SlackWebhookURL, Channel: channel},
+			fmt.Sprintf("slack-channel from %s", valueOrNone(ownership.Sources[ownershipSlack]))
+	}
+	if r.config.DefaultRoute.URL != "" {
+		return withRouteType(r.config.DefaultRoute), "default route (no owner route found)"
+	}
+	return NotificationRoute{}, "no route configured"
+}
+
+func withRouteType(route NotificationRoute) NotificationRoute {
+	if route.Type == "" {
+		route.Type = routeWebhook
+	}
+	return route
+}
+
+// Send delivers a finding to a route
+func (r *NotificationRouter) Send(ctx context.Context, route NotificationRoute, finding Finding) error {
+	if route.URL == "" {
+		return fmt.Errorf("no notification route")
+	}
+
+	var payload interface{}
+	switch route.Type {
+	case routeSlack:
+		message := map[string]string{"text": slackText(finding)}
+		if route.Channel != "" {
+			message["channel"] = route.Channel
+		}
+		payload = message
+	case routeWebhook:
+		payload = struct {
+			Finding
+			Team             string `json:"team,omitempty"`
+			Owner            string `json:"owner,omitempty"`
+			PagerDutyService string `json:"pagerduty_service,omitempty"`
+			Summary          string `json:"ownership_summary,omitempty"`
+		}{finding, finding.Ownership.Team, finding.Ownership.Owner, finding.Ownership.PagerDutyService, finding.Ownership.Summary()}
+	default:
+		return fmt.Errorf("unsupported notification route type %q", route.Type)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func slackText(finding Finding) string {
+	text := fmt.Sprintf("*[%s] %s*\n%s", strings.ToUpper(valueOrNone(finding.Severity)), finding.Title, finding.resource())
+	if summary := finding.Ownership.Summary(); summary != "" {
+		text += "\n" + summary
+	}
+	if finding.Details != "" {
+		details := finding.Details
+		if len(details) > maxNotificationDetails {
+			details = details[:maxNotificationDetails] + "\n…[truncated]"
+		}
+		text += "\n```\n" + details + "\n```"
+	}
+	return text
+}
+
+// markNotified records a health check finding and reports whether it is new
+func (r *NotificationRouter) markNotified(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.notified[key] {
+		return false
+	}
+	r.notified[key] = true
+	return true
+}
+
+func (r *NotificationRouter) forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.notified, key)
+}
+
+// forgetResolved drops findings that are no longer present, so they are
+// reported again if they recur
+func (r *NotificationRouter) forgetResolved(current map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.notified {
+		if !current[key] {
+			delete(r.notified, key)
+		}
+	}
+}
+
+// notifyFinding resolves the owner of a finding and delivers it to their route
+func (s *Server) notifyFinding(ctx context.Context, finding Finding) (NotificationRoute, error) {
+	ownership, err := s.resolveOwnership(ctx, finding.ResourceType, finding.ResourceName, finding.Namespace)
+	if err != nil && ownership.Empty() && finding.ResourceType != "" {
+		ownership, _ = s.resolveOwnership(ctx, "", "", finding.Namespace)
+	}
+	finding.Ownership = ownership
+	if finding.Time.IsZero() {
+		finding.Time = time.Now()
+	}
+
+	route, reason := s.notifier.Route(ownership)
+	if route.URL == "" {
+		logrus.Warnf("No notification route for %s (%s): %s", finding.resource(), finding.Title, reason)
+		return route, fmt.Errorf("%s", reason)
+	}
+	if err := s.notifier.Send(ctx, route, finding); err != nil {
+		return route, fmt.Errorf("failed to notify %s: %w", route.Describe(), err)
+	}
+	logrus.Infof("Notified %s about %s (%s)", route.Describe(), finding.resource(), reason)
+	return route, nil
+}
+
+// DiagnoseAndNotify runs openshift_diagnose for a resource, for example one
+// named by a firing alert, and routes the report to the resource's owners
+func (s *Server) DiagnoseAndNotify(ctx context.Context, title, severity, resourceType, resourceName, namespace string) (NotificationRoute, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "openshift_diagnose"
+	request.Params.Arguments = map[string]interface{}{
+		"resource_type": resourceType,
+		"resource_name": resourceName,
+		"namespace":     namespace,
+	}
+	var result *mcp.CallToolResult
+	var err error
+	if s.HasTool("openshift_diagnose") {
+		result, err = s.CallTool(ctx, request)
+	} else {
+		result, err = s.OpenShiftDiagnose(ctx, request)
+	}
+	details := ""
+	if err != nil {
+		details = fmt.Sprintf("Diagnosis failed: %v", err)
+	} else if result != nil && len(result.Content) > 0 {
+		if text, ok := mcp.AsTextContent(result.Content[0]); ok {
+			details = text.Text
+		}
+	}
+
+	return s.notifyFinding(ctx, Finding{
+		Title:        title,
+		Severity:     severity,
+		Source:       "alert",
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Details:      details,
+	})
+}
+
+// podProblem returns why a pod is unhealthy, or "" when it is fine
+func podProblem(pod *corev1.Pod) string {
+	if pod.Status.Phase == corev1.PodFailed {
+		return fmt.Sprintf("Failed: %s", valueOrNone(pod.Status.Reason))
+	}
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if waiting := status.State.Waiting; waiting != nil {
+			switch waiting.Reason {
+			case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError", "InvalidImageName":
+				return fmt.Sprintf("%s (container %s, %d restarts)", waiting.Reason, status.Name, status.RestartCount)
+			}
+		}
+	}
+	return ""
+}
+
+func (s *Server) initHealthCheckSchedule(config *NotificationConfig) {
+	if config == nil || config.HealthCheckInterval == "" {
+		return
+	}
+	interval, err := time.ParseDuration(config.HealthCheckInterval)
+	if err != nil || interval <= 0 {
+		logrus.Warnf("Invalid health check interval %q, scheduled health checks disabled", config.HealthCheckInterval)
+		return
+	}
+
+	s.scheduler.AddLeaderJob(healthCheckJobName, interval, func(ctx context.Context) error {
+		_, err := s.runHealthCheck(ctx, config.HealthCheckNamespaces)
+		return err
+	})
+	logrus.Infof("Scheduled health checks every %s", interval)
+}
+
+// runHealthCheck finds failing pods and notifies their owners once per
+// problem, returning the number of notifications sent
+func (s *Server) runHealthCheck(ctx context.Context, namespaces []string) (int, error) {
+	if s.k8sClient == nil {
+		return 0, fmt.Errorf("Kubernetes client not available")
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	current := make(map[string]bool)
+	var findings []Finding
+	var keys []string
+	for _, namespace := range namespaces {
+		pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list pods in %s: %w", valueOrNone(namespace), err)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			problem := podProblem(pod)
+			if problem == "" {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, strings.SplitN(problem, " ", 2)[0])
+			current[key] = true
+			if !s.notifier.markNotified(key) {
+				continue
+			}
+			keys = append(keys, key)
+			findings = append(findings, Finding{
+				Title:        fmt.Sprintf("Pod %s is unhealthy", pod.Name),
+				Severity:     "warning",
+				Source:       healthCheckJobName,
+				Namespace:    pod.Namespace,
+				ResourceType: "pod",
+				ResourceName: pod.Name,
+				Details:      problem,
+			})
+		}
+	}
+	s.notifier.forgetResolved(current)
+
+	sent := 0
+	var failures []string
+	for i, finding := range findings {
+		if _, err := s.notifyFinding(ctx, finding); err != nil {
+			// Retry on the next run
+			s.notifier.forget(keys[i])
+			failures = append(failures, fmt.Sprintf("%s: %v", finding.resource(), err))
+			continue
+		}
+		sent++
+	}
+	if len(failures) > 0 {
+		return sent, fmt.Errorf("%d of %d notifications failed: %s", len(failures), len(findings), strings.Join(failures, "; "))
+	}
+	return sent, nil
+}
+
+func (s *Server) initNotificationTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("notify_owners",
+			mcp.WithDescription("Send a finding to the owning team of a resource or namespace, routed by team, Slack channel annotation or the default route"),
+			mcp.WithString("namespace", mcp.Description("Namespace of the resource"), mcp.Required()),
+			mcp.WithString("title", mcp.Description("Short description of the finding"), mcp.Required()),
+			mcp.WithString("message", mcp.Description("Details to include, e.g. a diagnosis summary")),
+			mcp.WithString("severity", mcp.Description("info, warning or critical (default warning)")),
+			mcp.WithString("resource_type", mcp.Description("Resource type, e.g. pod or deployment (optional)")),
+			mcp.WithString("resource_name", mcp.Description("Resource name (optional)")),
+			mcp.WithString("dry_run", mcp.Description("Only show where the finding would be sent (true/false)")),
+			mcp.WithTitleAnnotation("Notifications: Notify Owners"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.notifyOwnersHandler)},
+	}
+}
+
+func (s *Server) notifyOwnersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := mcp.ParseString(request, "namespace", "")
+	title := mcp.ParseString(request, "title", "")
+	if namespace == "" || title == "" {
+		return mcp.NewToolResultText("❌ namespace and title are required"), nil
+	}
+	severity := strings.ToLower(mcp.ParseString(request, "severity", "warning"))
+	if severity != "info" && severity != "warning" && severity != "critical" {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid severity %q: use info, warning or critical", severity)), nil
+	}
+	finding := Finding{
+		Title:        title,
+		Severity:     severity,
+		Source:       "manual",
+		Namespace:    namespace,
+		ResourceType: mcp.ParseString(request, "resource_type", ""),
+		ResourceName: mcp.ParseString(request, "resource_name", ""),
+		Details:      mcp.ParseString(request, "message", ""),
+	}
+
+	result := "📣 Owner Notification\n"
+	result += "=====================\n\n"
+	result += fmt.Sprintf("Finding: [%s] %s\n", severity, title)
+	result += fmt.Sprintf("Resource: %s\n", finding.resource())
+
+	if parseBoolString(mcp.ParseString(request, "dry_run", "false")) {
+		ownership, _ := s.resolveOwnership(ctx, finding.ResourceType, finding.ResourceName, namespace)
+		route, reason := s.notifier.Route(ownership)
+		result += fmt.Sprintf("Owner: %s\n", valueOrNone(ownership.Summary()))
+		result += fmt.Sprintf("Route: %s (%s)\n", route.Describe(), reason)
+		result += "\n🔍 Dry run: nothing was sent"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	route, err := s.notifyFinding(ctx, finding)
+	if err != nil {
+		result += fmt.Sprintf("\n❌ Not delivered: %v\n", err)
+		result += "💡 Configure notifications.team-routes, notifications.slack-webhook-url or notifications.default"
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("\n✅ Sent to %s", route.Describe())
+	return mcp.NewToolResultText(result), nil
+}
+
+// NotifyOwnersHandler is a public wrapper for notifyOwnersHandler
+func (s *Server) NotifyOwnersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.notifyOwnersHandler(ctx, request)
+}
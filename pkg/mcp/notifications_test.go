@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+// notificationRecorder is a webhook endpoint that records posted payloads
+type notificationRecorder struct {
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+	server   *httptest.Server
+}
+
+func newNotificationRecorder(t *testing.T) *notificationRecorder {
+	r := &notificationRecorder{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid notification payload: %v", err)
+		}
+		r.mu.Lock()
+		r.payloads = append(r.payloads, payload)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *notificationRecorder) received() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.payloads...)
+}
+
+func TestNotificationRoute(t *testing.T) {
+	router := NewNotificationRouter(&NotificationConfig{
+		SlackWebhookURL: "https://hooks.slack.test/T1",
+		DefaultRoute:    NotificationRoute{URL: "https://tickets.test/global"},
+		TeamRoutes: map[string]NotificationRoute{
+			"payments": {Type: routeWebhook, URL: "https://tickets.test/payments"},
+		},
+	})
+
+	tests := []struct {
+		ownership Ownership
+		expected  NotificationRoute
+	}{
+		{Ownership{Team: "Payments", SlackChannel: "payments-oncall"}, NotificationRoute{Type: routeWebhook, URL: "https://tickets.test/payments"}},
+		{Ownership{Team: "search", SlackChannel: "search-oncall"}, NotificationRoute{Type: routeSlack, URL: "https://hooks.slack.test/T1", Channel: "#search-oncall"}},
+		{Ownership{Team: "search"}, NotificationRoute{Type: routeWebhook, URL: "https://tickets.test/global"}},
+		{Ownership{}, NotificationRoute{Type: routeWebhook, URL: "https://tickets.test/global"}},
+	}
+
+	for _, tt := range tests {
+		if route, _ := router.Route(tt.ownership); route != tt.expected {
+			t.Errorf("Route(%+v) = %+v, expected %+v", tt.ownership, route, tt.expected)
+		}
+	}
+
+	if route, reason := NewNotificationRouter(nil).Route(Ownership{Team: "payments"}); route.URL != "" || reason != "no route configured" {
+		t.Errorf("Route without config = %+v, %q, expected no route", route, reason)
+	}
+}
+
+func newHealthCheckServer(t *testing.T, recorder *notificationRecorder) *Server {
+	crashing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "payments"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "api", RestartCount: 12,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	}
+	healthy := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-2", Namespace: "payments"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "payments",
+		Annotations: map[string]string{"team": "payments", "slack-channel": "payments-oncall"},
+	}}
+
+	return &Server{
+		config:    &Config{},
+		k8sClient: kubefake.NewSimpleClientset(crashing, healthy, namespace),
+		notifier:  NewNotificationRouter(&NotificationConfig{SlackWebhookURL: recorder.server.URL}),
+	}
+}
+
+func TestRunHealthCheck(t *testing.T) {
+	recorder := newNotificationRecorder(t)
+	s := newHealthCheckServer(t, recorder)
+	ctx := context.Background()
+
+	sent, err := s.runHealthCheck(ctx, []string{"payments"})
+	if err != nil || sent != 1 {
+		t.Fatalf("runHealthCheck = %d, %v, expected 1 notification", sent, err)
+	}
+	payloads := recorder.received()
+	if len(payloads) != 1 {
+		t.Fatalf("received %d notifications, expected 1", len(payloads))
+	}
+	text, _ := payloads[0]["text"].(string)
+	if payloads[0]["channel"] != "#payments-oncall" || !strings.Contains(text, "CrashLoopBackOff (container api, 12 restarts)") ||
+		!strings.Contains(text, "owned by team payments") {
+		t.Errorf("health check notification = %v", payloads[0])
+	}
+
+	// An unchanged problem is not reported again
+	if sent, _ := s.runHealthCheck(ctx, []string{"payments"}); sent != 0 {
+		t.Errorf("second runHealthCheck sent %d notifications, expected 0", sent)
+	}
+
+	// Once the problem clears it is forgotten and reported again if it recurs
+	s.k8sClient.CoreV1().Pods("payments").Delete(ctx, "api-1", metav1.DeleteOptions{})
+	s.runHealthCheck(ctx, []string{"payments"})
+	if len(s.notifier.notified) != 0 {
+		t.Errorf("resolved findings still tracked: %v", s.notifier.notified)
+	}
+}
+
+func TestNotifyOwnersHandler(t *testing.T) {
+	recorder := newNotificationRecorder(t)
+	s := newHealthCheckServer(t, recorder)
+	s.notifier = NewNotificationRouter(&NotificationConfig{
+		TeamRoutes: map[string]NotificationRoute{"payments": {URL: recorder.server.URL}},
+	})
+
+	args := map[string]interface{}{
+		"namespace": "payments", "title": "Disk almost full", "message": "PVC data-0 is 95% used", "dry_run": "true",
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, _ := s.notifyOwnersHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "route configured for team payments") || !strings.Contains(text, "Dry run") {
+		t.Errorf("notify_owners dry run = %q", text)
+	}
+	if len(recorder.received()) != 0 {
+		t.Fatalf("dry run sent a notification")
+	}
+
+	args["dry_run"] = "false"
+	result, _ = s.notifyOwnersHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "✅ Sent to webhook") {
+		t.Errorf("notify_owners = %q", text)
+	}
+	payloads := recorder.received()
+	if len(payloads) != 1 || payloads[0]["team"] != "payments" || payloads[0]["details"] != "PVC data-0 is 95% used" {
+		t.Errorf("webhook payloads = %v", payloads)
+	}
+}
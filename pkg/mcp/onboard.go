@@ -0,0 +1,335 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// onboardResourceTypes are exported by onboard_namespace unless
+// resource_types is given; secrets are never exported
+var onboardResourceTypes = []string{
+	"configmaps", "serviceaccounts", "persistentvolumeclaims",
+	"deployments", "statefulsets", "daemonsets", "cronjobs",
+	"services", "routes",
+}
+
+// onboardSyncWaves orders the exported kinds like the generated bundles:
+// configuration first, then workloads, then what exposes them
+var onboardSyncWaves = map[string]string{
+	"ConfigMap": "0", "ServiceAccount": "0", "PersistentVolumeClaim": "0",
+	"Deployment": "1", "StatefulSet": "1", "DaemonSet": "1", "CronJob": "1",
+	"Service": "2", "Route": "2",
+}
+
+// onboardSkipNames are objects the platform creates in every namespace
+var onboardSkipNames = map[string]map[string]bool{
+	"ConfigMap":      {"kube-root-ca.crt": true, "openshift-service-ca.crt": true},
+	"ServiceAccount": {"default": true, "builder": true, "deployer": true, "pipeline": true},
+}
+
+// onboardDropAnnotationPrefixes are annotations set by the cluster, not the owner
+var onboardDropAnnotationPrefixes = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"kubectl.kubernetes.io/restartedAt",
+	"deployment.kubernetes.io/",
+	"openshift.io/generated-by",
+	"openshift.io/host.generated",
+	"pv.kubernetes.io/",
+	"volume.beta.kubernetes.io/",
+	"volume.kubernetes.io/",
+	"mcp.openshift.io/",
+}
+
+func (s *Server) initOnboarding() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("onboard_namespace",
+			mcp.WithDescription("Export an existing namespace's workloads into the Git repository as normalized ArgoCD manifests with a kustomization and an Application, committed in one commit"),
+			mcp.WithString("namespace", mcp.Description("Namespace to onboard"), mcp.Required()),
+			mcp.WithString("app_name", mcp.Description("ArgoCD application name (default: the namespace)")),
+			mcp.WithString("environment", mcp.Description("Directory to write: base (default) or an overlay environment such as dev, staging or prod")),
+			mcp.WithString("resource_types", mcp.Description("Comma-separated resource types to export (default: configmaps, serviceaccounts, persistentvolumeclaims, deployments, statefulsets, daemonsets, cronjobs, services, routes)")),
+			mcp.WithString("repo_url", mcp.Description("Repository URL for the Application (default: the Git remote)")),
+			mcp.WithString("target_revision", mcp.Description("Revision the Application tracks (default: the Git branch)")),
+			mcp.WithString("dry_run", mcp.Description("Show the files that would be committed without writing them (true/false)")),
+			mcp.WithTitleAnnotation("ArgoCD: Onboard Namespace"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.onboardNamespaceHandler)},
+	}
+}
+
+// stripForExport removes the fields the cluster sets on an exported object
+// so that it can be created again elsewhere. It returns false for objects
+// the cluster or a controller creates, which should not be exported.
+func stripForExport(obj *unstructured.Unstructured) bool {
+	if len(obj.GetOwnerReferences()) > 0 || onboardSkipNames[obj.GetKind()][obj.GetName()] {
+		return false
+	}
+
+	annotations := obj.GetAnnotations()
+	hostGenerated := annotations["openshift.io/host.generated"] == "true"
+	for key := range annotations {
+		for _, prefix := range onboardDropAnnotationPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				delete(annotations, key)
+				break
+			}
+		}
+	}
+
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink", "namespace", "annotations"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "creationTimestamp")
+		if restarted, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations"); restarted != nil {
+			delete(restarted, "kubectl.kubernetes.io/restartedAt")
+			if len(restarted) == 0 {
+				unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "annotations")
+			} else {
+				unstructured.SetNestedStringMap(obj.Object, restarted, "spec", "template", "metadata", "annotations")
+			}
+		}
+	case "CronJob":
+		unstructured.RemoveNestedField(obj.Object, "spec", "jobTemplate", "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(obj.Object, "spec", "jobTemplate", "spec", "template", "metadata", "creationTimestamp")
+	case "Service":
+		for _, field := range []string{"clusterIP", "clusterIPs", "ipFamilies", "ipFamilyPolicy", "healthCheckNodePort"} {
+			unstructured.RemoveNestedField(obj.Object, "spec", field)
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+	case "Route":
+		if hostGenerated {
+			// The router generates the host again in the target cluster
+			unstructured.RemoveNestedField(obj.Object, "spec", "host")
+		}
+	case "ServiceAccount":
+		// Token and pull secrets are created by the cluster for each account
+		unstructured.RemoveNestedField(obj.Object, "secrets")
+		unstructured.RemoveNestedField(obj.Object, "imagePullSecrets")
+	}
+	return true
+}
+
+// normalizeForGitOps strips the fields the cluster sets on an exported
+// object and adds the standard labels and sync wave. It returns false for
+// objects that should not be exported.
+func normalizeForGitOps(obj *unstructured.Unstructured, appName string) bool {
+	if !stripForExport(obj) {
+		return false
+	}
+
+	if wave, ok := onboardSyncWaves[obj.GetKind()]; ok {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations["argocd.argoproj.io/sync-wave"] = wave
+		obj.SetAnnotations(annotations)
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels["app.kubernetes.io/part-of"] = appName
+	labels["managed-by"] = "argocd"
+	labels["created-by"] = "openshift-mcp"
+	obj.SetLabels(labels)
+	return true
+}
+
+// onboardFilename is the manifest file of an exported object
+func onboardFilename(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())
+}
+
+// onboardKustomization lists the exported manifests for kustomize
+func onboardKustomization(namespace string, files []string) (string, error) {
+	data, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"namespace":  namespace,
+		"resources":  files,
+	})
+	return string(data), err
+}
+
+func (s *Server) onboardNamespaceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	namespace := mcp.ParseString(request, "namespace", "")
+	appName := mcp.ParseString(request, "app_name", namespace)
+	environment := mcp.ParseString(request, "environment", "base")
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+
+	if namespace == "" {
+		return mcp.NewToolResultText("❌ Namespace is required"), nil
+	}
+	if !baselineNamePattern.MatchString(appName) || !baselineNamePattern.MatchString(environment) {
+		return mcp.NewToolResultText("❌ app_name and environment may only contain letters, digits, '.', '_' and '-'"), nil
+	}
+	if !dryRun && (s.gitManager == nil || !s.gitManager.IsEnabled()) {
+		return mcp.NewToolResultText("❌ Git integration is disabled\n💡 Use dry_run=true to preview the manifests"), nil
+	}
+
+	resourceTypes := onboardResourceTypes
+	if value := mcp.ParseString(request, "resource_types", ""); value != "" {
+		resourceTypes = nil
+		for _, resourceType := range strings.Split(value, ",") {
+			if resourceType = strings.TrimSpace(resourceType); resourceType != "" {
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+		}
+	}
+
+	manifests := make(map[string]string)
+	var files, notes []string
+	exported := make(map[string]int)
+	skipped := 0
+	for _, resourceType := range resourceTypes {
+		gvr, namespaced, err := s.resolveResource(resourceType)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", resourceType, err))
+			continue
+		}
+		if gvr.Resource == "secrets" {
+			notes = append(notes, "secrets: not exported; manage them with sealed or external secrets")
+			continue
+		}
+		if !namespaced {
+			notes = append(notes, fmt.Sprintf("%s: cluster-scoped, not exported", resourceType))
+			continue
+		}
+		list, err := s.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			recordToolError(ctx, err)
+			notes = append(notes, fmt.Sprintf("%s: %v", resourceType, err))
+			continue
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GetKind() == "" {
+				// List items carry their kind on the server; fill it in from the list when missing
+				obj.SetKind(strings.TrimSuffix(list.GetKind(), "List"))
+			}
+			if !normalizeForGitOps(obj, appName) {
+				skipped++
+				continue
+			}
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("%s %s: %v", obj.GetKind(), obj.GetName(), err))
+				continue
+			}
+			filename := onboardFilename(obj)
+			manifests[filename] = string(data)
+			files = append(files, filename)
+			exported[obj.GetKind()]++
+		}
+	}
+	if len(files) == 0 {
+		result := fmt.Sprintf("❌ No resources to onboard in namespace %s\n", namespace)
+		for _, note := range notes {
+			result += fmt.Sprintf("⚠️  %s\n", note)
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+	sort.Strings(files)
+
+	kustomization, err := onboardKustomization(namespace, files)
+	if err != nil {
+		return toolError(ctx, "Failed to generate kustomization", err), nil
+	}
+	manifests["kustomization.yaml"] = kustomization
+
+	repoURL := mcp.ParseString(request, "repo_url", "")
+	targetRevision := mcp.ParseString(request, "target_revision", "")
+	if s.gitManager != nil {
+		if repoURL == "" {
+			repoURL = s.gitManager.config.RemoteURL
+		}
+		if targetRevision == "" {
+			targetRevision = s.gitManager.config.Branch
+		}
+	}
+	if targetRevision == "" {
+		targetRevision = "HEAD"
+	}
+	path := "manifests/base/" + appName
+	if environment != "base" {
+		path = fmt.Sprintf("manifests/overlays/%s/%s", environment, appName)
+	}
+	generator := s.yamlGenerator
+	if generator == nil {
+		generator = NewYAMLGenerator()
+	}
+	application, err := generator.GenerateArgoCDApplicationYAML(appName, "argocd", repoURL, path, targetRevision,
+		"https://kubernetes.default.svc", namespace, false)
+	if err != nil {
+		return toolError(ctx, "Failed to generate ArgoCD Application", err), nil
+	}
+
+	result := fmt.Sprintf("📦 Onboarding Namespace: %s\n", namespace)
+	result += "========================\n\n"
+	result += fmt.Sprintf("Application: %s\n", appName)
+	result += fmt.Sprintf("Path: %s\n", path)
+	if repoURL == "" {
+		result += "Repository: (not set - add repo_url before syncing the Application)\n"
+	} else {
+		result += fmt.Sprintf("Repository: %s @ %s\n", repoURL, targetRevision)
+	}
+	result += "\n📋 Exported resources:\n"
+	for _, kind := range sortedKeys(exported) {
+		result += fmt.Sprintf("   %s: %d\n", kind, exported[kind])
+	}
+	if skipped > 0 {
+		result += fmt.Sprintf("   Skipped %d owned or platform-created object(s)\n", skipped)
+	}
+	for _, note := range notes {
+		result += fmt.Sprintf("⚠️  %s\n", note)
+	}
+	result += "\n🧹 Stripped status, server metadata, cluster-assigned IPs and volume bindings; added app.kubernetes.io/part-of, managed-by and sync-wave\n"
+
+	if dryRun {
+		result += "\n📄 Files (dry run - nothing was written):\n"
+		for _, filename := range append(files, "kustomization.yaml") {
+			result += fmt.Sprintf("\n%s/%s:\n```yaml\n%s```\n", path, filename, manifests[filename])
+		}
+		result += fmt.Sprintf("\napplications/%s-application.yaml:\n```yaml\n%s```\n", appName, application)
+		result += "\n💡 Re-run without dry_run to commit the bundle"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	description := fmt.Sprintf("namespace %s as ArgoCD application %s (%d resources)", namespace, appName, len(files))
+	dir, err := s.gitManager.SaveOnboardedApplication(ctx, appName, environment, manifests, application, description)
+	if err != nil {
+		return toolError(ctx, "Failed to commit the onboarded namespace", err), nil
+	}
+	result += fmt.Sprintf("\n✅ Committed %d manifest(s), kustomization.yaml and applications/%s-application.yaml\n", len(files), appName)
+	result += fmt.Sprintf("📁 %s\n", dir)
+	result += "\n💡 Apply the Application from the repository to let ArgoCD adopt the namespace"
+	return mcp.NewToolResultText(result), nil
+}
+
+// OnboardNamespaceHandler is a public wrapper for onboardNamespaceHandler
+func (s *Server) OnboardNamespaceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.onboardNamespaceHandler(ctx, request)
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestNormalizeForGitOps(t *testing.T) {
+	service := newUnstructured("v1", "Service", "shop", "web")
+	service.SetResourceVersion("42")
+	service.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		"team": "payments",
+	})
+	unstructured.SetNestedField(service.Object, "172.30.0.10", "spec", "clusterIP")
+	unstructured.SetNestedField(service.Object, map[string]interface{}{"loadBalancer": map[string]interface{}{}}, "status")
+
+	if !normalizeForGitOps(service, "shop") {
+		t.Fatalf("normalizeForGitOps(Service web) skipped the service")
+	}
+	annotations := service.GetAnnotations()
+	if annotations["team"] != "payments" || annotations["argocd.argoproj.io/sync-wave"] != "2" || len(annotations) != 2 {
+		t.Errorf("normalized annotations = %v", annotations)
+	}
+	if service.GetLabels()["app.kubernetes.io/part-of"] != "shop" || service.GetLabels()["managed-by"] != "argocd" {
+		t.Errorf("normalized labels = %v", service.GetLabels())
+	}
+	for _, field := range [][]string{{"metadata", "uid"}, {"metadata", "resourceVersion"}, {"metadata", "namespace"}, {"spec", "clusterIP"}, {"status"}} {
+		if _, found, _ := unstructured.NestedFieldNoCopy(service.Object, field...); found {
+			t.Errorf("normalized service kept %s", strings.Join(field, "."))
+		}
+	}
+
+	owned := newUnstructured("apps/v1", "ReplicaSet", "shop", "web-5d8f")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Deployment", Name: "web"}})
+	tests := []struct {
+		obj      *unstructured.Unstructured
+		expected bool
+	}{
+		{owned, false},
+		{newUnstructured("v1", "ConfigMap", "shop", "kube-root-ca.crt"), false},
+		{newUnstructured("v1", "ServiceAccount", "shop", "default"), false},
+		{newUnstructured("v1", "ServiceAccount", "shop", "web"), true},
+	}
+	for _, tt := range tests {
+		if result := normalizeForGitOps(tt.obj, "shop"); result != tt.expected {
+			t.Errorf("normalizeForGitOps(%s %s) = %v, expected %v", tt.obj.GetKind(), tt.obj.GetName(), result, tt.expected)
+		}
+	}
+}
+
+func TestOnboardNamespace(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	runGit(t, repo, "init", "--initial-branch=main")
+	runGit(t, repo, "config", "user.name", "bot")
+	runGit(t, repo, "config", "user.email", "bot@example.com")
+	commitTestFile(t, repo, "README.md", "records\n")
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentsGVR:                          "DeploymentList",
+		{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+		{Version: "v1", Resource: "secrets"}:    "SecretList",
+	},
+		newUnstructured("apps/v1", "Deployment", "shop", "web"),
+		newUnstructured("v1", "ConfigMap", "shop", "web-config"),
+		newUnstructured("v1", "ConfigMap", "shop", "kube-root-ca.crt"),
+		newUnstructured("apps/v1", "Deployment", "other", "api"),
+	)
+	s := &Server{
+		config:        &Config{},
+		dynamicClient: client,
+		restMapper:    mapper,
+		gitManager:    NewGitManager(&GitConfig{Enabled: true, RepoPath: repo, RemoteURL: "https://git.example.com/gitops.git", Branch: "main"}),
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "resource_types": "deployments,configmaps,secrets"}
+	result, err := s.OnboardNamespaceHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("OnboardNamespaceHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{"Deployment: 1", "ConfigMap: 1", "Skipped 1", "secrets: not exported", "✅ Committed 2 manifest(s)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("onboard_namespace output missing %q:\n%s", want, text)
+		}
+	}
+
+	dir := filepath.Join(repo, "manifests", "base", "shop")
+	kustomization, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	if err != nil || !strings.Contains(string(kustomization), "- configmap-web-config.yaml\n- deployment-web.yaml") {
+		t.Errorf("kustomization.yaml = %q, %v", kustomization, err)
+	}
+	application, err := os.ReadFile(filepath.Join(repo, "applications", "shop-application.yaml"))
+	if err != nil || !strings.Contains(string(application), "path: manifests/base/shop") || !strings.Contains(string(application), "repoURL: https://git.example.com/gitops.git") {
+		t.Errorf("shop-application.yaml = %q, %v", application, err)
+	}
+
+	status, _ := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	log, _ := exec.Command("git", "-C", repo, "log", "--oneline").Output()
+	if len(status) != 0 || strings.Count(string(log), "\n") != 2 {
+		t.Errorf("repository after onboarding: status %q, log %q", status, log)
+	}
+}
@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+func (s *Server) initOVNTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("collect_ovn_diagnostics",
+			mcp.WithDescription("Collect OVN-Kubernetes diagnostics (ovnkube container logs, northbound and southbound database status, ovn-controller's southbound connection, OVS bridges and each node's gateway configuration) and report known OVN failure signatures. Use when pods are stuck in ContainerCreating with FailedCreatePodSandBox, or when pod networking fails on some nodes"),
+			mcp.WithString("node", mcp.Description("Only collect the ovnkube pods of this node, plus the control plane")),
+			mcp.WithString("since", mcp.Description("How far back to collect logs (default 1h)")),
+			mcp.WithString("output_dir", mcp.Description("Directory to store the collection")),
+			mcp.WithBoolean("compressed", mcp.Description("Package the collection into a single tar.gz bundle")),
+			mcp.WithTitleAnnotation("Diagnostics: Collect OVN-Kubernetes"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.collectOVNDiagnosticsHandler)},
+		{Tool: mcp.NewTool("analyze_ovn_diagnostics",
+			mcp.WithDescription("Analyze an OVN-Kubernetes collection from collect_ovn_diagnostics for known failure signatures, database and connection problems and unconfigured node gateways"),
+			mcp.WithString("path", mcp.Description("Directory or bundle of the collection"), mcp.Required()),
+			mcp.WithString("export", mcp.Description(exportParamDescription)),
+			mcp.WithTitleAnnotation("Analysis: OVN-Kubernetes"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.analyzeOVNDiagnosticsHandler)},
+	}
+}
+
+func (s *Server) collectOVNDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts := &diagnostics.CollectionOptions{
+		NodeName:    strings.TrimSpace(mcp.ParseString(request, "node", "")),
+		OutputDir:   mcp.ParseString(request, "output_dir", ""),
+		IncludeLogs: true,
+		Compressed:  mcp.ParseBoolean(request, "compressed", false),
+		Filters:     map[string]string{"since": mcp.ParseString(request, "since", "")},
+	}
+
+	result, err := s.diagnosticCollector.CollectOVN(ctx, opts)
+	if result == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if err != nil {
+		return toolError(ctx, "❌ Failed to collect OVN-Kubernetes diagnostics", err), nil
+	}
+
+	response := "🕸️ OVN-Kubernetes Collection\n"
+	response += "============================\n\n"
+	if node := result.Metadata["node"]; node != "" {
+		response += fmt.Sprintf("Node: %s (and the control plane)\n", node)
+	}
+	response += fmt.Sprintf("Pods: %s, logs: %s since %s, checks: %s\n", result.Metadata["pods"], result.Metadata["logs"], result.Metadata["since"], result.Metadata["checks"])
+	response += fmt.Sprintf("📁 Location: %s\n", result.FilePath)
+	if result.Metadata["bundle"] == "" {
+		response += fmt.Sprintf("📋 Manifest: %s\n", result.Metadata["manifest"])
+	}
+	response += fmt.Sprintf("📦 Size: %.2f MB\n", float64(result.Size)/(1024*1024))
+	response += fmt.Sprintf("⏱️ Duration: %v\n", result.Duration.Round(time.Millisecond))
+	if failed := result.Metadata["failed"]; failed != "0" {
+		response += fmt.Sprintf("\n⚠️ %s logs or checks could not be collected; see the manifest for the errors.\n", failed)
+	}
+	response += "\n" + bundleNote(result)
+
+	analysis, err := s.analysisEngine.AnalyzeOVN(ctx, result.FilePath)
+	if err != nil {
+		recordToolError(ctx, err)
+		response += fmt.Sprintf("\n❌ Analysis failed: %v\n", err)
+		return mcp.NewToolResultText(response), nil
+	}
+	response += "\n" + s.formatAnalysisResult(ctx, analysis)
+	return mcp.NewToolResultText(response), nil
+}
+
+func (s *Server) analyzeOVNDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := mcp.ParseString(request, "path", "")
+	if path == "" {
+		return mcp.NewToolResultText("❌ path is required"), nil
+	}
+	format, err := diagnostics.ParseExportFormat(mcp.ParseString(request, "export", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	result, err := s.analysisEngine.AnalyzeOVN(ctx, path)
+	if err != nil {
+		return toolError(ctx, "❌ Failed to analyze the OVN-Kubernetes collection", err), nil
+	}
+	return mcp.NewToolResultText(s.formatAnalysisResult(ctx, result) + s.exportNote(ctx, result, format)), nil
+}
+
+// CollectOVNDiagnosticsHandler is a public wrapper for collectOVNDiagnosticsHandler
+func (s *Server) CollectOVNDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.collectOVNDiagnosticsHandler(ctx, request)
+}
+
+// AnalyzeOVNDiagnosticsHandler is a public wrapper for analyzeOVNDiagnosticsHandler
+func (s *Server) AnalyzeOVNDiagnosticsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.analyzeOVNDiagnosticsHandler(ctx, request)
+}
@@ -0,0 +1,319 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxOwnerDepth bounds how far controller owner references are followed,
+// e.g. Pod -> ReplicaSet -> Deployment
+const maxOwnerDepth = 5
+
+// Ownership fields, also used as the canonical annotation names
+const (
+	ownershipTeam      = "team"
+	ownershipOwner     = "owner"
+	ownershipSlack     = "slack-channel"
+	ownershipPagerDuty = "pagerduty-service"
+)
+
+// ownershipConventions lists the label and annotation names accepted for each
+// field. Names match with or without a domain prefix, so "team" and
+// "example.com/team" are both read as the team.
+var ownershipConventions = []struct {
+	field string
+	names []string
+}{
+	{ownershipTeam, []string{"team", "owner-team", "owning-team"}},
+	{ownershipOwner, []string{"owner", "contact", "requester"}},
+	{ownershipSlack, []string{"slack-channel", "slack", "oncall-slack"}},
+	{ownershipPagerDuty, []string{"pagerduty-service", "pagerduty"}},
+}
+
+// Ownership records who owns a resource and how to reach them, with the
+// object each field was found on
+type Ownership struct {
+	Team             string
+	Owner            string
+	SlackChannel     string
+	PagerDutyService string
+	Sources          map[string]string // field -> object the value came from
+}
+
+func (o *Ownership) get(field string) *string {
+	switch field {
+	case ownershipTeam:
+		return &o.Team
+	case ownershipOwner:
+		return &o.Owner
+	case ownershipSlack:
+		return &o.SlackChannel
+	case ownershipPagerDuty:
+		return &o.PagerDutyService
+	}
+	return nil
+}
+
+// Empty reports whether no ownership metadata was found
+func (o Ownership) Empty() bool {
+	return o.Team == "" && o.Owner == "" && o.SlackChannel == "" && o.PagerDutyService == ""
+}
+
+// addFrom fills fields that are still empty from an object's annotations and
+// labels, annotations first
+func (o *Ownership) addFrom(source string, annotations, labels map[string]string) {
+	for _, convention := range ownershipConventions {
+		value := o.get(convention.field)
+		if *value != "" {
+			continue
+		}
+		for _, metadata := range []map[string]string{annotations, labels} {
+			if found := lookupConvention(metadata, convention.names); found != "" {
+				*value = found
+				if o.Sources == nil {
+					o.Sources = make(map[string]string)
+				}
+				o.Sources[convention.field] = source
+				break
+			}
+		}
+	}
+}
+
+// lookupConvention finds a key whose name, ignoring any domain prefix, is one
+// of names. Earlier names win; among prefixes the sorted-first key wins.
+func lookupConvention(metadata map[string]string, names []string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	keys := sortedKeys(metadata)
+	for _, name := range names {
+		for _, key := range keys {
+			base := key
+			if i := strings.LastIndex(key, "/"); i >= 0 {
+				base = key[i+1:]
+			}
+			if base == name && strings.TrimSpace(metadata[key]) != "" {
+				return strings.TrimSpace(metadata[key])
+			}
+		}
+	}
+	return ""
+}
+
+// ownershipFromMetadata resolves ownership from objects ordered from most to
+// least specific, e.g. a workload then its namespace
+func ownershipFromMetadata(source string, maps ...map[string]string) Ownership {
+	var ownership Ownership
+	for _, metadata := range maps {
+		ownership.addFrom(source, metadata, nil)
+	}
+	return ownership
+}
+
+// Summary renders a one-line notice such as
+// "owned by team payments — notify #payments-oncall (PagerDuty: payments-api)"
+func (o Ownership) Summary() string {
+	if o.Empty() {
+		return ""
+	}
+
+	summary := ""
+	switch {
+	case o.Team != "":
+		summary = fmt.Sprintf("owned by team %s", o.Team)
+	case o.Owner != "":
+		summary = fmt.Sprintf("owned by %s", o.Owner)
+	}
+
+	var notify []string
+	if o.SlackChannel != "" {
+		channel := o.SlackChannel
+		if !strings.HasPrefix(channel, "#") {
+			channel = "#" + channel
+		}
+		notify = append(notify, channel)
+	}
+	if o.Team != "" && o.Owner != "" {
+		notify = append(notify, o.Owner)
+	}
+	if len(notify) > 0 {
+		if summary != "" {
+			summary += " — "
+		}
+		summary += "notify " + strings.Join(notify, ", ")
+	}
+	if o.PagerDutyService != "" {
+		summary += fmt.Sprintf(" (PagerDuty: %s)", o.PagerDutyService)
+	}
+	return strings.TrimSpace(summary)
+}
+
+// resolveOwnership walks from a resource through its controller owners to its
+// namespace, taking each field from the most specific object that sets it.
+// The resource is optional; with only a namespace, namespace ownership is returned.
+func (s *Server) resolveOwnership(ctx context.Context, resourceType, name, namespace string) (Ownership, error) {
+	var ownership Ownership
+
+	if resourceType != "" && name != "" && s.dynamicClient != nil && s.restMapper != nil {
+		gvr, namespaced, err := s.resolveResource(resourceType)
+		if err != nil {
+			return ownership, err
+		}
+		if !namespaced {
+			namespace = ""
+		}
+		obj, err := s.resourceInterface(gvr, namespaced, namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return ownership, err
+		}
+		for depth := 0; obj != nil && depth < maxOwnerDepth; depth++ {
+			ownership.addFrom(objectSource(obj), obj.GetAnnotations(), obj.GetLabels())
+			obj = s.controllerOf(ctx, obj)
+		}
+	}
+
+	if namespace != "" && s.k8sClient != nil {
+		ns, err := s.k8sClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			return ownership, err
+		}
+		ownership.addFrom("namespace "+namespace, ns.Annotations, ns.Labels)
+	}
+	return ownership, nil
+}
+
+// controllerOf fetches the controlling owner of an object, or nil
+func (s *Server) controllerOf(ctx context.Context, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	ref := metav1.GetControllerOfNoCopy(obj)
+	if ref == nil {
+		return nil
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil
+	}
+	mapping, err := s.restMapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+	if err != nil {
+		return nil
+	}
+	namespaced := obj.GetNamespace() != ""
+	owner, err := s.resourceInterface(mapping.Resource, namespaced, obj.GetNamespace()).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return owner
+}
+
+func objectSource(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// appendOwnership adds the owner notice to a diagnostic report. Lookup
+// failures are ignored, since ownership is informational.
+func (s *Server) appendOwnership(ctx context.Context, result *mcp.CallToolResult, resourceType, name, namespace string) *mcp.CallToolResult {
+	if result == nil || result.IsError || len(result.Content) == 0 {
+		return result
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		return result
+	}
+
+	ownership, err := s.resolveOwnership(ctx, resourceType, name, namespace)
+	if err != nil && ownership.Empty() && resourceType != "" {
+		// The named resource may not exist; fall back to the namespace
+		ownership, _ = s.resolveOwnership(ctx, "", "", namespace)
+	}
+	if summary := ownership.Summary(); summary != "" {
+		text.Text = strings.TrimRight(text.Text, "\n") + fmt.Sprintf("\n\n👥 %s%s", strings.ToUpper(summary[:1]), summary[1:])
+		result.Content[0] = *text
+	}
+	return result
+}
+
+func (s *Server) initOwnershipTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("get_ownership",
+			mcp.WithDescription("Look up the owning team, owner, Slack channel and PagerDuty service of a resource or namespace from its annotations and labels"),
+			mcp.WithString("namespace", mcp.Description("Namespace of the resource, or the namespace to look up")),
+			mcp.WithString("resource_type", mcp.Description("Resource type, e.g. pod, deployment or route (optional)")),
+			mcp.WithString("resource_name", mcp.Description("Resource name (optional)")),
+			mcp.WithTitleAnnotation("Ownership: Get"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getOwnershipHandler)},
+	}
+}
+
+func (s *Server) getOwnershipHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	namespace := mcp.ParseString(request, "namespace", "")
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	resourceName := mcp.ParseString(request, "resource_name", "")
+	if namespace == "" && (resourceType == "" || resourceName == "") {
+		return mcp.NewToolResultText("❌ Provide a namespace, or a resource_type and resource_name"), nil
+	}
+	if (resourceType == "") != (resourceName == "") {
+		return mcp.NewToolResultText("❌ resource_type and resource_name must be given together"), nil
+	}
+
+	ownership, err := s.resolveOwnership(ctx, resourceType, resourceName, namespace)
+	if err != nil {
+		return toolError(ctx, "Failed to resolve ownership", err), nil
+	}
+
+	result := "👥 Ownership\n"
+	result += "============\n\n"
+	if resourceType != "" {
+		result += fmt.Sprintf("Resource: %s %s\n", resourceType, resourceName)
+	}
+	if namespace != "" {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	result += "\n"
+
+	if ownership.Empty() {
+		result += "⚠️  No ownership metadata found\n"
+		result += "💡 Annotate the namespace or workload, e.g.:\n"
+		result += fmt.Sprintf("   oc annotate namespace %s team=<team> slack-channel=<channel> pagerduty-service=<service>", valueOrNone(namespace))
+		return mcp.NewToolResultText(result), nil
+	}
+
+	fields := []struct {
+		label string
+		field string
+		value string
+	}{
+		{"Team", ownershipTeam, ownership.Team},
+		{"Owner", ownershipOwner, ownership.Owner},
+		{"Slack", ownershipSlack, ownership.SlackChannel},
+		{"PagerDuty", ownershipPagerDuty, ownership.PagerDutyService},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			result += fmt.Sprintf("%s: %s (from %s)\n", f.label, f.value, ownership.Sources[f.field])
+		}
+	}
+	result += fmt.Sprintf("\n📣 %s", ownership.Summary())
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// GetOwnershipHandler is a public wrapper for getOwnershipHandler
+func (s *Server) GetOwnershipHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getOwnershipHandler(ctx, request)
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLookupConvention(t *testing.T) {
+	tests := []struct {
+		metadata map[string]string
+		names    []string
+		expected string
+	}{
+		{map[string]string{"team": "payments"}, []string{"team"}, "payments"},
+		{map[string]string{"example.com/team": " payments "}, []string{"team"}, "payments"},
+		{map[string]string{"b.io/team": "second", "a.io/team": "first"}, []string{"team"}, "first"},
+		{map[string]string{"owner-team": "later", "team": "earlier"}, []string{"team", "owner-team"}, "earlier"},
+		{map[string]string{"myteam": "payments"}, []string{"team"}, ""},
+		{map[string]string{"team": ""}, []string{"team"}, ""},
+		{nil, []string{"team"}, ""},
+	}
+
+	for _, tt := range tests {
+		if result := lookupConvention(tt.metadata, tt.names); result != tt.expected {
+			t.Errorf("lookupConvention(%v, %v) = %q, expected %q", tt.metadata, tt.names, result, tt.expected)
+		}
+	}
+}
+
+func TestOwnershipSummary(t *testing.T) {
+	tests := []struct {
+		ownership Ownership
+		expected  string
+	}{
+		{Ownership{Team: "payments", SlackChannel: "payments-oncall"}, "owned by team payments — notify #payments-oncall"},
+		{Ownership{Team: "payments", SlackChannel: "#pay", PagerDutyService: "payments-api"}, "owned by team payments — notify #pay (PagerDuty: payments-api)"},
+		{Ownership{Team: "payments", Owner: "alice"}, "owned by team payments — notify alice"},
+		{Ownership{Owner: "alice"}, "owned by alice"},
+		{Ownership{SlackChannel: "ops"}, "notify #ops"},
+		{Ownership{}, ""},
+	}
+
+	for _, tt := range tests {
+		if result := tt.ownership.Summary(); result != tt.expected {
+			t.Errorf("Summary(%+v) = %q, expected %q", tt.ownership, result, tt.expected)
+		}
+	}
+}
+
+// newOwnershipTestServer serves a pod owned by a ReplicaSet owned by a
+// Deployment in the payments namespace
+func newOwnershipTestServer() *Server {
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	replicaSetsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	deployment := newUnstructured("apps/v1", "Deployment", "payments", "api")
+	deployment.SetAnnotations(map[string]string{"example.com/team": "payments", "pagerduty-service": "payments-api"})
+	replicaSet := newUnstructured("apps/v1", "ReplicaSet", "payments", "api-7d9")
+	replicaSet.SetOwnerReferences([]metav1.OwnerReference{controllerRef(deployment)})
+	pod := newUnstructured("v1", "Pod", "payments", "api-7d9-x2")
+	pod.SetLabels(map[string]string{"owner": "alice"})
+	pod.SetOwnerReferences([]metav1.OwnerReference{controllerRef(replicaSet)})
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR:        "PodList",
+		replicaSetsGVR: "ReplicaSetList",
+		deploymentsGVR: "DeploymentList",
+	}, pod, replicaSet, deployment)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "payments",
+		Annotations: map[string]string{"team": "platform", "slack-channel": "payments-oncall"},
+	}}
+	return &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(namespace), dynamicClient: client, restMapper: mapper}
+}
+
+func controllerRef(owner *unstructured.Unstructured) metav1.OwnerReference {
+	isController := true
+	return metav1.OwnerReference{
+		APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(), Name: owner.GetName(), UID: owner.GetUID(), Controller: &isController,
+	}
+}
+
+func TestResolveOwnership(t *testing.T) {
+	s := newOwnershipTestServer()
+
+	ownership, err := s.resolveOwnership(context.Background(), "pod", "api-7d9-x2", "payments")
+	if err != nil {
+		t.Fatalf("resolveOwnership returned error: %v", err)
+	}
+	expected := map[string]string{
+		"Team":      "payments",
+		"Owner":     "alice",
+		"Slack":     "payments-oncall",
+		"PagerDuty": "payments-api",
+	}
+	actual := map[string]string{
+		"Team":      ownership.Team,
+		"Owner":     ownership.Owner,
+		"Slack":     ownership.SlackChannel,
+		"PagerDuty": ownership.PagerDutyService,
+	}
+	for field, value := range expected {
+		if actual[field] != value {
+			t.Errorf("resolveOwnership %s = %q, expected %q", field, actual[field], value)
+		}
+	}
+	if source := ownership.Sources[ownershipTeam]; source != "Deployment payments/api" {
+		t.Errorf("team source = %q, expected Deployment payments/api", source)
+	}
+
+	// Without a resource only the namespace is consulted
+	ownership, err = s.resolveOwnership(context.Background(), "", "", "payments")
+	if err != nil || ownership.Team != "platform" {
+		t.Errorf("namespace ownership = %+v, %v, expected team platform", ownership, err)
+	}
+}
+
+func TestGetOwnershipHandler(t *testing.T) {
+	s := newOwnershipTestServer()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "payments", "resource_type": "pod", "resource_name": "api-7d9-x2"}
+	result, _ := s.getOwnershipHandler(context.Background(), request)
+	text := resultText(result)
+	for _, expected := range []string{"Team: payments (from Deployment payments/api)", "Slack: payments-oncall (from namespace payments)", "notify #payments-oncall, alice"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("get_ownership result missing %q:\n%s", expected, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"resource_type": "pod"}
+	result, _ = s.getOwnershipHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌") {
+		t.Errorf("get_ownership without a name = %q, expected an error", text)
+	}
+}
+
+func TestAppendOwnership(t *testing.T) {
+	s := newOwnershipTestServer()
+
+	// A missing resource falls back to namespace ownership
+	result := s.appendOwnership(context.Background(), mcp.NewToolResultText("report\n"), "pod", "failing-pod", "payments")
+	if text := resultText(result); text != "report\n\n👥 Owned by team platform — notify #payments-oncall" {
+		t.Errorf("appendOwnership = %q", text)
+	}
+
+	result = s.appendOwnership(context.Background(), mcp.NewToolResultText("report"), "", "", "unknown")
+	if text := resultText(result); text != "report" {
+		t.Errorf("appendOwnership without metadata = %q, expected the report unchanged", text)
+	}
+}
@@ -0,0 +1,208 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// patchTypes maps the patch_type parameter to the API patch types
+var patchTypes = map[string]types.PatchType{
+	"json":      types.JSONPatchType,
+	"merge":     types.MergePatchType,
+	"strategic": types.StrategicMergePatchType,
+}
+
+// jsonPatchOp is one operation of an RFC 6902 JSON patch
+type jsonPatchOp struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// validJSONPointer reports whether a path is an RFC 6901 JSON pointer
+func validJSONPointer(path string) bool {
+	if path != "" && !strings.HasPrefix(path, "/") {
+		return false
+	}
+	// "~" may only escape "0" (~) or "1" (/)
+	for i := strings.Index(path, "~"); i >= 0; i = strings.Index(path, "~") {
+		if i+1 >= len(path) || (path[i+1] != '0' && path[i+1] != '1') {
+			return false
+		}
+		path = path[i+2:]
+	}
+	return true
+}
+
+// validatePatch parses a YAML or JSON patch body, checks it against its
+// patch type and returns it as JSON
+func validatePatch(patchType types.PatchType, body string) ([]byte, error) {
+	data, err := yaml.YAMLToJSON([]byte(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML/JSON patch: %v", err)
+	}
+
+	if patchType == types.JSONPatchType {
+		var ops []jsonPatchOp
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return nil, fmt.Errorf("a JSON patch must be a list of operations: %v", err)
+		}
+		if len(ops) == 0 {
+			return nil, fmt.Errorf("the JSON patch has no operations")
+		}
+		for i, op := range ops {
+			if op.Path == nil || !validJSONPointer(*op.Path) {
+				return nil, fmt.Errorf("operation %d: path must be a JSON pointer such as /spec/replicas", i+1)
+			}
+			switch op.Op {
+			case "add", "replace", "test":
+				if op.Value == nil {
+					return nil, fmt.Errorf("operation %d: %s needs a value", i+1, op.Op)
+				}
+			case "remove":
+			case "move", "copy":
+				if op.From == nil || !validJSONPointer(*op.From) {
+					return nil, fmt.Errorf("operation %d: %s needs a from JSON pointer", i+1, op.Op)
+				}
+			default:
+				return nil, fmt.Errorf("operation %d: unknown op %q (expected add, remove, replace, move, copy or test)", i+1, op.Op)
+			}
+			if *op.Path == "/metadata/name" || *op.Path == "/metadata/namespace" {
+				return nil, fmt.Errorf("operation %d: a patch cannot rename or move a resource", i+1)
+			}
+		}
+		return data, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("a %s patch must be an object of the fields to change", patchTypeName(patchType))
+	}
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		if _, ok := metadata["name"]; ok {
+			return nil, fmt.Errorf("a patch cannot rename a resource")
+		}
+		if _, ok := metadata["namespace"]; ok {
+			return nil, fmt.Errorf("a patch cannot move a resource to another namespace")
+		}
+	}
+	return data, nil
+}
+
+// patchTypeName returns the patch_type parameter value of a patch type
+func patchTypeName(patchType types.PatchType) string {
+	for name, t := range patchTypes {
+		if t == patchType {
+			return name
+		}
+	}
+	return string(patchType)
+}
+
+func (s *Server) patchResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	resourceName := mcp.ParseString(request, "resource_name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	body := mcp.ParseString(request, "patch", "")
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+
+	if resourceName == "" {
+		return mcp.NewToolResultText("❌ Resource name is required"), nil
+	}
+	if strings.TrimSpace(body) == "" {
+		return mcp.NewToolResultText("❌ Patch content is required"), nil
+	}
+	typeName := strings.ToLower(mcp.ParseString(request, "patch_type", "strategic"))
+	patchType, ok := patchTypes[typeName]
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid patch_type '%s' (expected json, merge or strategic)", typeName)), nil
+	}
+	data, err := validatePatch(patchType, body)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	gvr, namespaced, err := s.resolveResource(resourceType)
+	if err != nil {
+		recordToolError(ctx, err)
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if !namespaced {
+		namespace = ""
+	}
+	client := s.resourceInterface(gvr, namespaced, namespace)
+
+	live, err := client.Get(ctx, resourceName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get %s %s", gvr.Resource, resourceName), err), nil
+	}
+
+	options := metav1.PatchOptions{FieldManager: fieldManager}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	patched, err := client.Patch(ctx, resourceName, patchType, data, options)
+	if err != nil {
+		recordToolError(ctx, err)
+		result := fmt.Sprintf("❌ Failed to patch %s %s: %s\n", gvr.Resource, resourceName, validationMessage(err))
+		if apierrors.IsUnsupportedMediaType(err) && patchType == types.StrategicMergePatchType {
+			result += "💡 Custom resources do not support strategic merge patches; use patch_type=merge or json"
+		}
+		return mcp.NewToolResultError(result), nil
+	}
+
+	result := "🩹 Patching Resource\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("Resource: %s\n", gvr.GroupResource().String())
+	result += fmt.Sprintf("Name: %s\n", resourceName)
+	if namespaced {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	} else {
+		result += "Scope: cluster\n"
+	}
+	result += fmt.Sprintf("Patch Type: %s\n", typeName)
+	if dryRun {
+		result += "Mode: dry run - nothing was changed\n"
+	}
+	result += "\n"
+
+	if !dryRun {
+		logrus.Infof("Patched %s %s (namespace %q) with a %s patch", gvr.GroupResource().String(), resourceName, namespace, typeName)
+		s.recordAction(ctx, "patch_resource", objectReference(patched.GetAPIVersion(), patched.GetKind(), patched), ReasonPatched,
+			fmt.Sprintf("Patched %s with a %s patch", patched.GetKind(), typeName))
+	}
+
+	diff := diffLines(objectYAML(live), objectYAML(patched))
+	if len(diff) == 0 {
+		result += "✅ No changes - the patch leaves the object as it was\n"
+	} else {
+		result += "📝 Changes (live → patched):\n"
+		result += fmt.Sprintf("```diff\n%s\n```\n\n", strings.Join(diff, "\n"))
+		if dryRun {
+			result += "✅ Patch is valid\n"
+		} else {
+			result += "✅ Resource patched\n"
+		}
+	}
+
+	result += fmt.Sprintf("\n📄 Resulting object:\n```yaml\n%s\n```", strings.Join(objectYAML(patched), "\n"))
+	if dryRun {
+		result += "\n\n💡 Re-run without dry_run to apply the patch"
+	}
+	return mcp.NewToolResultText(result), nil
+}
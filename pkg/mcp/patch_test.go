@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestValidatePatch(t *testing.T) {
+	tests := []struct {
+		patchType types.PatchType
+		body      string
+		expected  string
+	}{
+		{types.JSONPatchType, `[{"op": "replace", "path": "/spec/replicas", "value": 3}]`, ""},
+		{types.JSONPatchType, "- op: remove\n  path: /metadata/labels/tier", ""},
+		{types.JSONPatchType, `{"spec": {"replicas": 3}}`, "must be a list of operations"},
+		{types.JSONPatchType, `[]`, "no operations"},
+		{types.JSONPatchType, `[{"op": "replace", "path": "spec/replicas", "value": 3}]`, "path must be a JSON pointer"},
+		{types.JSONPatchType, `[{"op": "add", "path": "/metadata/labels/a~2b", "value": "x"}]`, "path must be a JSON pointer"},
+		{types.JSONPatchType, `[{"op": "add", "path": "/spec/replicas"}]`, "add needs a value"},
+		{types.JSONPatchType, `[{"op": "move", "path": "/spec/a"}]`, "move needs a from"},
+		{types.JSONPatchType, `[{"op": "merge", "path": "/spec"}]`, "unknown op"},
+		{types.JSONPatchType, `[{"op": "replace", "path": "/metadata/name", "value": "x"}]`, "cannot rename"},
+		{types.MergePatchType, "spec:\n  replicas: 3", ""},
+		{types.MergePatchType, `[1, 2]`, "must be an object"},
+		{types.StrategicMergePatchType, `{"metadata": {"namespace": "prod"}}`, "another namespace"},
+		{types.StrategicMergePatchType, `spec: [`, "invalid YAML/JSON patch"},
+	}
+
+	for _, tt := range tests {
+		_, err := validatePatch(tt.patchType, tt.body)
+		switch {
+		case tt.expected == "" && err != nil:
+			t.Errorf("validatePatch(%s, %q) error = %v, expected none", tt.patchType, tt.body, err)
+		case tt.expected != "" && (err == nil || !strings.Contains(err.Error(), tt.expected)):
+			t.Errorf("validatePatch(%s, %q) error = %v, expected %q", tt.patchType, tt.body, err, tt.expected)
+		}
+	}
+}
+
+func TestPatchResource(t *testing.T) {
+	live := newUnstructured("apps/v1", "Deployment", "shop", "web")
+	unstructured.SetNestedField(live.Object, int64(2), "spec", "replicas")
+	s := newDynamicTestServer(live)
+
+	tests := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"resource_type": "deployment", "resource_name": "web", "namespace": "shop", "patch_type": "xml", "patch": "{}"}, "❌ Invalid patch_type 'xml'"},
+		{map[string]interface{}{"resource_type": "deployment", "resource_name": "api", "namespace": "shop", "patch_type": "merge", "patch": "{}"}, "❌ Failed to get deployments api"},
+		{map[string]interface{}{"resource_type": "deployment", "resource_name": "web", "namespace": "shop", "patch_type": "json",
+			"patch": `[{"op": "replace", "path": "/spec/replicas", "value": 5}]`}, "+   replicas: 5"},
+		{map[string]interface{}{"resource_type": "deployment", "resource_name": "web", "namespace": "shop", "patch_type": "merge",
+			"patch": "metadata:\n  labels:\n    tier: frontend"}, "tier: frontend"},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := s.PatchResourceHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("PatchResourceHandler(%v) error = %v", tt.args, err)
+		}
+		if text := resultText(result); !strings.Contains(text, tt.expected) {
+			t.Errorf("PatchResourceHandler(%v) = %q, expected %q", tt.args, text, tt.expected)
+		}
+	}
+
+	patched, err := s.dynamicClient.Resource(deploymentsGVR).Namespace("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("patched deployment not found: %v", err)
+	}
+	replicas, _, _ := unstructured.NestedInt64(patched.Object, "spec", "replicas")
+	if replicas != 5 || patched.GetLabels()["tier"] != "frontend" {
+		t.Errorf("patched deployment = %v", patched.Object)
+	}
+}
+
+func TestPatchResourceRedactsSecrets(t *testing.T) {
+	s := newDynamicTestServer(newSecretUnstructured("shop", "db", "hunter2-old"))
+
+	for _, dryRun := range []string{"true", "false"} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"resource_type": "secret",
+			"resource_name": "db",
+			"namespace":     "shop",
+			"patch_type":    "merge",
+			"patch":         `{"data": {"password": "` + base64.StdEncoding.EncodeToString([]byte("hunter2-new")) + `"}}`,
+			"dry_run":       dryRun,
+		}
+		result, err := s.PatchResourceHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("PatchResourceHandler(dry_run=%s) error = %v", dryRun, err)
+		}
+		text := resultText(result)
+		if !strings.Contains(text, "Resulting object") {
+			t.Fatalf("PatchResourceHandler(dry_run=%s) = %q, expected the resulting object", dryRun, text)
+		}
+		assertSecretRedacted(t, text, "hunter2-old", "hunter2-new")
+	}
+}
@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	defaultExecTimeout = 30 * time.Second
+	maxExecTimeout     = 5 * time.Minute
+
+	// maxExecOutputBytes bounds stdout and stderr each; later output is discarded
+	maxExecOutputBytes = 64 * 1024
+)
+
+// podExecFunc runs a command in a container, streaming its output to stdout and stderr
+type podExecFunc func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error
+
+// cappedWriter keeps the first limit bytes written and counts the rest. It
+// never fails a write, so a chatty command is not killed by the limit.
+type cappedWriter struct {
+	buf     strings.Builder
+	limit   int
+	dropped int
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	room := w.limit - w.buf.Len()
+	if room >= len(p) {
+		w.buf.Write(p)
+		return len(p), nil
+	}
+	if room > 0 {
+		w.buf.Write(p[:room])
+	}
+	w.dropped += len(p) - max(room, 0)
+	return len(p), nil
+}
+
+// splitCommand splits a command line into arguments, honouring single and
+// double quotes and backslash escapes. No other shell syntax is interpreted.
+func splitCommand(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// remoteExec runs a command through the pods/exec subresource, preferring
+// WebSockets and falling back to SPDY for API servers that do not support them
+func (s *Server) remoteExec(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+	if s.restConfig == nil {
+		return fmt.Errorf("pod exec requires a Kubernetes REST config")
+	}
+
+	req := s.k8sClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(s.restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+	websocketExec, err := remotecommand.NewWebSocketExecutor(s.restConfig, "GET", req.URL().String())
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket executor: %w", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExec, spdyExec, httpstream.IsUpgradeFailure)
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	defer trackPhase(ctx, PhaseExec)()
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+}
+
+func (s *Server) execInPodHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	podName := mcp.ParseString(request, "pod_name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	commandLine := strings.TrimSpace(mcp.ParseString(request, "command", ""))
+	if podName == "" {
+		return mcp.NewToolResultText("❌ pod_name is required"), nil
+	}
+	if commandLine == "" {
+		return mcp.NewToolResultText("❌ command is required, e.g. \"nslookup kubernetes.default\""), nil
+	}
+
+	var command []string
+	if parseBoolString(mcp.ParseString(request, "shell", "false")) {
+		command = []string{"/bin/sh", "-c", commandLine}
+	} else {
+		args, err := splitCommand(commandLine)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid command: %v", err)), nil
+		}
+		command = args
+	}
+
+	timeoutStr := mcp.ParseString(request, "timeout_seconds", strconv.Itoa(int(defaultExecTimeout.Seconds())))
+	seconds, err := strconv.Atoi(timeoutStr)
+	if err != nil || seconds <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid timeout_seconds value: %s", timeoutStr)), nil
+	}
+	timeout := time.Duration(seconds) * time.Second
+	if timeout > maxExecTimeout {
+		timeout = maxExecTimeout
+	}
+
+	pod, err := s.k8sClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get pod %s", podName), err), nil
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Pod %s is %s; commands can only run in a running pod. Use get_pod_logs with previous=true for crashed containers.", podName, pod.Status.Phase)), nil
+	}
+	container, note, err := selectContainer(pod, mcp.ParseString(request, "container", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	exec := s.podExec
+	if exec == nil {
+		exec = s.remoteExec
+	}
+	stdout := &cappedWriter{limit: maxExecOutputBytes}
+	stderr := &cappedWriter{limit: maxExecOutputBytes}
+
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	session := s.sessions.open(SessionExec, fmt.Sprintf("%s/%s/%s: %s", namespace, podName, container, commandLine), cancel)
+	defer session.Close()
+	started := time.Now()
+	execErr := exec(execCtx, namespace, podName, container, command, sessionWriter{stdout, session}, sessionWriter{stderr, session})
+	elapsed := time.Since(started).Round(time.Millisecond)
+
+	result := "🖥️  Pod Exec\n"
+	result += "============\n\n"
+	result += fmt.Sprintf("Pod: %s\n", podName)
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("Container: %s\n", container)
+	result += fmt.Sprintf("Command: %s\n", commandLine)
+	if note != "" {
+		result += fmt.Sprintf("ℹ️  %s\n", note)
+	}
+
+	var exitErr interface{ ExitStatus() int }
+	switch {
+	case execErr == nil:
+		result += fmt.Sprintf("Status: ✅ exit code 0 (%s)\n", elapsed)
+	case errors.Is(execCtx.Err(), context.DeadlineExceeded):
+		result += fmt.Sprintf("Status: ⏱️  timed out after %s; output so far is shown\n", timeout)
+	case errors.As(execErr, &exitErr):
+		result += fmt.Sprintf("Status: ❌ exit code %d (%s)\n", exitErr.ExitStatus(), elapsed)
+	default:
+		return toolError(ctx, fmt.Sprintf("Failed to run command in %s/%s", podName, container), execErr), nil
+	}
+
+	for _, stream := range []struct {
+		name   string
+		writer *cappedWriter
+	}{{"stdout", stdout}, {"stderr", stderr}} {
+		if stream.writer.buf.Len() == 0 {
+			continue
+		}
+		result += fmt.Sprintf("\n%s:\n```\n%s\n```\n", stream.name, strings.TrimRight(stream.writer.buf.String(), "\n"))
+		if stream.writer.dropped > 0 {
+			result += fmt.Sprintf("⚠️  %s limited to %d KB: %d bytes omitted\n", stream.name, maxExecOutputBytes/1024, stream.writer.dropped)
+		}
+	}
+	if stdout.buf.Len() == 0 && stderr.buf.Len() == 0 {
+		result += "\n📭 No output\n"
+	}
+
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ExecInPodHandler is a public wrapper for execInPodHandler
+func (s *Server) ExecInPodHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.execInPodHandler(ctx, request)
+}
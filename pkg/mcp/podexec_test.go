@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/exec"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+		valid    bool
+	}{
+		{"nslookup kubernetes.default", []string{"nslookup", "kubernetes.default"}, true},
+		{"  curl   -sS  http://api:8080 ", []string{"curl", "-sS", "http://api:8080"}, true},
+		{`curl -H "Host: shop.example.com" http://router`, []string{"curl", "-H", "Host: shop.example.com", "http://router"}, true},
+		{`echo 'a "b"' c\ d`, []string{"echo", `a "b"`, "c d"}, true},
+		{`echo ""`, []string{"echo", ""}, true},
+		{`echo "unterminated`, nil, false},
+		{`echo \`, nil, false},
+	}
+
+	for _, tt := range tests {
+		args, err := splitCommand(tt.line)
+		if (err == nil) != tt.valid {
+			t.Errorf("splitCommand(%q) error = %v, expected valid %v", tt.line, err, tt.valid)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(args, tt.expected) {
+			t.Errorf("splitCommand(%q) = %q, expected %q", tt.line, args, tt.expected)
+		}
+	}
+}
+
+func TestCappedWriter(t *testing.T) {
+	w := &cappedWriter{limit: 5}
+	for _, chunk := range []string{"abc", "defg", "hi"} {
+		if n, err := w.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Errorf("Write(%q) = %d, %v, expected %d, nil", chunk, n, err, len(chunk))
+		}
+	}
+	if w.buf.String() != "abcde" || w.dropped != 4 {
+		t.Errorf("cappedWriter kept %q and dropped %d, expected \"abcde\" and 4", w.buf.String(), w.dropped)
+	}
+}
+
+func newExecTestServer(phase corev1.PodPhase, run podExecFunc) *Server {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api"}}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	return &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(pod), podExec: run}
+}
+
+func execRequest(args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func TestExecInPod(t *testing.T) {
+	var gotCommand []string
+	s := newExecTestServer(corev1.PodRunning, func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+		gotCommand = command
+		io.WriteString(stdout, "Name:\tkubernetes.default.svc.cluster.local\nAddress: 172.30.0.1\n")
+		return nil
+	})
+
+	result, err := s.execInPodHandler(context.Background(), execRequest(map[string]interface{}{
+		"pod_name": "api", "namespace": "shop", "command": "nslookup kubernetes.default",
+	}))
+	if err != nil {
+		t.Fatalf("execInPodHandler returned error: %v", err)
+	}
+	text := resultText(result)
+	for _, expected := range []string{"Container: api", "exit code 0", "stdout:\n```\nName:\tkubernetes.default.svc.cluster.local\nAddress: 172.30.0.1\n```"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("exec result missing %q:\n%s", expected, text)
+		}
+	}
+	if !reflect.DeepEqual(gotCommand, []string{"nslookup", "kubernetes.default"}) {
+		t.Errorf("exec command = %q", gotCommand)
+	}
+
+	s.execInPodHandler(context.Background(), execRequest(map[string]interface{}{
+		"pod_name": "api", "namespace": "shop", "command": "env | grep PROXY", "shell": "true",
+	}))
+	if !reflect.DeepEqual(gotCommand, []string{"/bin/sh", "-c", "env | grep PROXY"}) {
+		t.Errorf("shell exec command = %q", gotCommand)
+	}
+}
+
+func TestExecInPodFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		phase    corev1.PodPhase
+		args     map[string]interface{}
+		run      podExecFunc
+		expected string
+	}{
+		{
+			name:  "non-zero exit",
+			phase: corev1.PodRunning,
+			args:  map[string]interface{}{"pod_name": "api", "namespace": "shop", "command": "curl http://down"},
+			run: func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+				io.WriteString(stderr, "curl: (7) Failed to connect\n")
+				return exec.CodeExitError{Err: io.EOF, Code: 7}
+			},
+			expected: "exit code 7",
+		},
+		{
+			name:  "timeout",
+			phase: corev1.PodRunning,
+			args:  map[string]interface{}{"pod_name": "api", "namespace": "shop", "command": "sleep 60", "timeout_seconds": "1"},
+			run: func(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			expected: "timed out after 1s",
+		},
+		{
+			name:     "pod not running",
+			phase:    corev1.PodPending,
+			args:     map[string]interface{}{"pod_name": "api", "namespace": "shop", "command": "ls"},
+			expected: "❌ Pod api is Pending",
+		},
+		{
+			name:     "unknown container",
+			phase:    corev1.PodRunning,
+			args:     map[string]interface{}{"pod_name": "api", "namespace": "shop", "command": "ls", "container": "sidecar"},
+			expected: `container "sidecar" not found`,
+		},
+		{
+			name:     "missing command",
+			phase:    corev1.PodRunning,
+			args:     map[string]interface{}{"pod_name": "api", "namespace": "shop"},
+			expected: "❌ command is required",
+		},
+	}
+
+	for _, tt := range tests {
+		s := newExecTestServer(tt.phase, tt.run)
+		result, _ := s.execInPodHandler(context.Background(), execRequest(tt.args))
+		if text := resultText(result); !strings.Contains(text, tt.expected) {
+			t.Errorf("%s: exec result = %q, expected it to contain %q", tt.name, text, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultPodLogTailLines = 200
+	maxPodLogTailLines     = 5000
+
+	// maxPodLogBytes bounds the log text returned to the caller; the newest lines are kept
+	maxPodLogBytes = 32 * 1024
+
+	// maxPodLogLineLength cuts single lines such as minified JSON payloads
+	maxPodLogLineLength = 2048
+
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+)
+
+// boundedLog keeps the newest lines of a log within a byte budget
+type boundedLog struct {
+	lines     []string
+	size      int
+	omitted   int
+	truncated int
+	limit     int
+}
+
+func newBoundedLog(limit int) *boundedLog {
+	return &boundedLog{limit: limit}
+}
+
+func (b *boundedLog) add(line string) {
+	if len(line) > maxPodLogLineLength {
+		line = line[:maxPodLogLineLength] + " …[line truncated]"
+		b.truncated++
+	}
+	b.lines = append(b.lines, line)
+	b.size += len(line) + 1
+	for b.size > b.limit && len(b.lines) > 1 {
+		b.size -= len(b.lines[0]) + 1
+		b.lines = b.lines[1:]
+		b.omitted++
+	}
+}
+
+// readBoundedLog reads a log stream keeping only what fits in the budget
+func readBoundedLog(r io.Reader, limit int) (*boundedLog, error) {
+	log := newBoundedLog(limit)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			log.add(strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			return log, nil
+		}
+		if err != nil {
+			return log, err
+		}
+	}
+}
+
+// selectContainer resolves the container to read, defaulting like kubectl to
+// the default-container annotation and then the first container
+func selectContainer(pod *corev1.Pod, requested string) (string, string, error) {
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		names = append(names, c.Name)
+	}
+
+	if requested != "" {
+		for _, name := range names {
+			if name == requested {
+				return requested, "", nil
+			}
+		}
+		return "", "", fmt.Errorf("container %q not found in pod %s; containers: %s", requested, pod.Name, strings.Join(names, ", "))
+	}
+
+	if len(pod.Spec.Containers) == 0 {
+		return "", "", fmt.Errorf("pod %s has no containers", pod.Name)
+	}
+	container := pod.Spec.Containers[0].Name
+	if annotated := pod.Annotations[defaultContainerAnnotation]; annotated != "" {
+		container = annotated
+	}
+	note := ""
+	if len(pod.Spec.Containers) > 1 {
+		note = fmt.Sprintf("Defaulted to container %s; pod also has: %s", container, strings.Join(names, ", "))
+	}
+	return container, note, nil
+}
+
+func (s *Server) getPodLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	podName := mcp.ParseString(request, "pod_name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	if podName == "" {
+		return mcp.NewToolResultText("❌ pod_name is required"), nil
+	}
+
+	tailStr := mcp.ParseString(request, "tail_lines", strconv.Itoa(defaultPodLogTailLines))
+	tailLines, err := strconv.ParseInt(tailStr, 10, 64)
+	if err != nil || tailLines <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid tail_lines value: %s", tailStr)), nil
+	}
+	if tailLines > maxPodLogTailLines {
+		tailLines = maxPodLogTailLines
+	}
+
+	opts := &corev1.PodLogOptions{
+		TailLines:  &tailLines,
+		Timestamps: parseBoolString(mcp.ParseString(request, "timestamps", "false")),
+		Previous:   parseBoolString(mcp.ParseString(request, "previous", "false")),
+	}
+	if since := mcp.ParseString(request, "since", ""); since != "" {
+		duration, err := time.ParseDuration(since)
+		if err != nil || duration <= 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid since value %q: use a duration such as 10m or 2h", since)), nil
+		}
+		seconds := int64(duration.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+		opts.SinceSeconds = &seconds
+	}
+
+	pod, err := s.k8sClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get pod %s", podName), err), nil
+	}
+	container, note, err := selectContainer(pod, mcp.ParseString(request, "container", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	opts.Container = container
+
+	stream, err := s.k8sClient.CoreV1().Pods(namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		if opts.Previous {
+			return toolError(ctx, fmt.Sprintf("Failed to get previous logs for %s/%s (the container may not have restarted)", podName, container), err), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get logs for %s/%s", podName, container), err), nil
+	}
+	defer stream.Close()
+
+	log, err := readBoundedLog(stream, maxPodLogBytes)
+	if err != nil {
+		return toolError(ctx, "Failed to read log stream", err), nil
+	}
+
+	result := "📜 Pod Logs\n"
+	result += "===========\n\n"
+	result += fmt.Sprintf("Pod: %s\n", podName)
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("Container: %s", container)
+	if opts.Previous {
+		result += " (previous instance)"
+	}
+	result += "\n"
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container && status.RestartCount > 0 {
+			result += fmt.Sprintf("Restarts: %d\n", status.RestartCount)
+		}
+	}
+	filter := fmt.Sprintf("last %d lines", tailLines)
+	if opts.SinceSeconds != nil {
+		filter += fmt.Sprintf(" from the past %s", time.Duration(*opts.SinceSeconds)*time.Second)
+	}
+	result += fmt.Sprintf("Filter: %s\n", filter)
+	if note != "" {
+		result += fmt.Sprintf("ℹ️  %s\n", note)
+	}
+	if log.omitted > 0 {
+		result += fmt.Sprintf("⚠️  Output limited to %d KB: %d older lines omitted. Narrow with tail_lines or since.\n", maxPodLogBytes/1024, log.omitted)
+	}
+	if log.truncated > 0 {
+		result += fmt.Sprintf("⚠️  %d lines longer than %d bytes were cut\n", log.truncated, maxPodLogLineLength)
+	}
+
+	if len(log.lines) == 0 {
+		result += "\n📭 No log output for this filter"
+		return mcp.NewToolResultText(result), nil
+	}
+	result += fmt.Sprintf("\n```\n%s\n```", strings.Join(log.lines, "\n"))
+	return mcp.NewToolResultText(result), nil
+}
+
+// GetPodLogsHandler is a public wrapper for getPodLogsHandler
+func (s *Server) GetPodLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getPodLogsHandler(ctx, request)
+}
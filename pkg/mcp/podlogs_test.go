@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReadBoundedLogKeepsNewestLines(t *testing.T) {
+	var input strings.Builder
+	for i := 1; i <= 100; i++ {
+		fmt.Fprintf(&input, "line %03d\n", i)
+	}
+	input.WriteString(strings.Repeat("x", maxPodLogLineLength+10))
+
+	log, err := readBoundedLog(strings.NewReader(input.String()), 500)
+	if err != nil {
+		t.Fatalf("readBoundedLog failed: %v", err)
+	}
+	if log.omitted == 0 || log.truncated != 1 {
+		t.Errorf("readBoundedLog omitted %d and truncated %d lines, expected omissions and 1 truncation", log.omitted, log.truncated)
+	}
+	// The oversized last line alone exceeds the budget but is still kept, cut
+	if len(log.lines) != 1 || !strings.HasSuffix(log.lines[0], "[line truncated]") {
+		t.Errorf("readBoundedLog kept %d lines, expected only the cut final line", len(log.lines))
+	}
+
+	log, _ = readBoundedLog(strings.NewReader("a\nb\r\nc"), 500)
+	if strings.Join(log.lines, ",") != "a,b,c" || log.omitted != 0 {
+		t.Errorf("readBoundedLog(a,b,c) = %v, omitted %d", log.lines, log.omitted)
+	}
+}
+
+func TestSelectContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "app"}, {Name: "proxy"}},
+		},
+	}
+	annotated := pod.DeepCopy()
+	annotated.Annotations = map[string]string{defaultContainerAnnotation: "proxy"}
+
+	tests := []struct {
+		pod       *corev1.Pod
+		requested string
+		expected  string
+		note      bool
+		valid     bool
+	}{
+		{pod, "", "app", true, true},
+		{annotated, "", "proxy", true, true},
+		{pod, "init", "init", false, true},
+		{pod, "sidecar", "", false, false},
+	}
+
+	for _, tt := range tests {
+		container, note, err := selectContainer(tt.pod, tt.requested)
+		if (err == nil) != tt.valid {
+			t.Errorf("selectContainer(%q) error = %v, expected valid %v", tt.requested, err, tt.valid)
+			continue
+		}
+		if container != tt.expected || (note != "") != tt.note {
+			t.Errorf("selectContainer(%q) = %q, %q, expected %q with note %v", tt.requested, container, note, tt.expected, tt.note)
+		}
+	}
+}
+
+func TestGetPodLogsHandler(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "web", RestartCount: 4}}},
+	}
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(pod)}
+
+	tests := []struct {
+		args     map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{"pod_name": "web-1", "namespace": "shop"},
+			[]string{"Container: web\n", "Restarts: 4", "Filter: last 200 lines", "fake logs"}},
+		{map[string]interface{}{"pod_name": "web-1", "namespace": "shop", "previous": "true", "since": "15m", "tail_lines": "50"},
+			[]string{"Container: web (previous instance)", "Filter: last 50 lines from the past 15m0s"}},
+		{map[string]interface{}{"pod_name": "web-1", "namespace": "shop", "since": "yesterday"},
+			[]string{"❌ Invalid since value"}},
+		{map[string]interface{}{"pod_name": "web-1", "namespace": "shop", "tail_lines": "-5"},
+			[]string{"❌ Invalid tail_lines value"}},
+		{map[string]interface{}{"pod_name": "web-1", "namespace": "shop", "container": "db"},
+			[]string{"❌ container \"db\" not found in pod web-1; containers: web"}},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := s.getPodLogsHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("getPodLogsHandler(%v) returned error: %v", tt.args, err)
+		}
+		text := resultText(result)
+		for _, expected := range tt.expected {
+			if !strings.Contains(text, expected) {
+				t.Errorf("getPodLogsHandler(%v) missing %q:\n%s", tt.args, expected, text)
+			}
+		}
+	}
+}
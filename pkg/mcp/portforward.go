@@ -0,0 +1,375 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	defaultPortForwardTTL = 5 * time.Minute
+	maxPortForwardTTL     = 30 * time.Minute
+	portForwardReadyWait  = 15 * time.Second
+	portForwardProbeWait  = 10 * time.Second
+
+	// maxProbeBodyBytes bounds the probe response body included in the result
+	maxProbeBodyBytes = 2048
+)
+
+// portForwardFunc forwards a free local port to a pod port until stop is
+// closed, returning the local port once the forward is ready
+type portForwardFunc func(namespace, pod string, port int, stop chan struct{}) (int, error)
+
+// portForwardSession is an open forward kept alive until its TTL expires
+type portForwardSession struct {
+	Target    string
+	Pod       string
+	Namespace string
+	LocalPort int
+	PodPort   int
+	Expires   time.Time
+	stop      chan struct{}
+	tracked   *trackedSession
+}
+
+// portForwardSessions tracks forwards left open for the caller
+type portForwardSessions struct {
+	mu       sync.Mutex
+	sessions map[int]*portForwardSession
+}
+
+func (p *portForwardSessions) add(session *portForwardSession, ttl time.Duration) {
+	p.mu.Lock()
+	if p.sessions == nil {
+		p.sessions = make(map[int]*portForwardSession)
+	}
+	p.sessions[session.LocalPort] = session
+	p.mu.Unlock()
+
+	time.AfterFunc(ttl, func() {
+		if p.remove(session) {
+			logrus.Infof("Closed port-forward localhost:%d -> %s after its TTL", session.LocalPort, session.Target)
+		}
+	})
+}
+
+// remove stops a forward unless its TTL or the session reaper already did,
+// reporting whether it was still open
+func (p *portForwardSessions) remove(session *portForwardSession) bool {
+	p.mu.Lock()
+	open := p.sessions[session.LocalPort] == session
+	if open {
+		delete(p.sessions, session.LocalPort)
+	}
+	p.mu.Unlock()
+	if !open {
+		return false
+	}
+	close(session.stop)
+	if session.tracked != nil {
+		session.tracked.Close()
+	}
+	return true
+}
+
+// list returns the open forwards ordered by local port
+func (p *portForwardSessions) list() []portForwardSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sessions := make([]portForwardSession, 0, len(p.sessions))
+	for _, session := range p.sessions {
+		sessions = append(sessions, *session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LocalPort < sessions[j].LocalPort })
+	return sessions
+}
+
+// remotePortForward opens an SPDY port-forward to a pod on 127.0.0.1 only
+func (s *Server) remotePortForward(namespace, pod string, port int, stop chan struct{}) (int, error) {
+	if s.restConfig == nil {
+		return 0, fmt.Errorf("port-forward requires a Kubernetes REST config")
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(s.restConfig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create SPDY transport: %w", err)
+	}
+	url := s.k8sClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	ready := make(chan struct{})
+	var errOut strings.Builder
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stop, ready, io.Discard, &errOut)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create port-forward: %w", err)
+	}
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-ready:
+	case err := <-failed:
+		return 0, fmt.Errorf("port-forward failed: %v %s", err, strings.TrimSpace(errOut.String()))
+	case <-time.After(portForwardReadyWait):
+		close(stop)
+		return 0, fmt.Errorf("port-forward was not ready after %s", portForwardReadyWait)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stop)
+		return 0, fmt.Errorf("port-forward has no local port: %v", err)
+	}
+	return int(ports[0].Local), nil
+}
+
+// resolveServiceTarget picks a ready pod behind a service and the container
+// port that a service port (number or name) maps to
+func (s *Server) resolveServiceTarget(ctx context.Context, namespace, serviceName, servicePort string) (*corev1.Pod, int, error) {
+	service, err := s.k8sClient.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, 0, fmt.Errorf("service %s has no selector", serviceName)
+	}
+	if len(service.Spec.Ports) == 0 {
+		return nil, 0, fmt.Errorf("service %s exposes no ports", serviceName)
+	}
+
+	target := service.Spec.Ports[0]
+	if servicePort != "" {
+		found := false
+		for _, p := range service.Spec.Ports {
+			if p.Name == servicePort || strconv.Itoa(int(p.Port)) == servicePort {
+				target, found = p, true
+				break
+			}
+		}
+		if !found {
+			return nil, 0, fmt.Errorf("service %s has no port %s", serviceName, servicePort)
+		}
+	}
+
+	pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || !podReady(pod) {
+			continue
+		}
+		port, err := containerPort(pod, target.TargetPort, target.Port)
+		if err != nil {
+			return nil, 0, err
+		}
+		return pod, port, nil
+	}
+	return nil, 0, fmt.Errorf("service %s has no ready pods (%d selected)", serviceName, len(pods.Items))
+}
+
+// containerPort resolves a service targetPort, which may be a named port
+func containerPort(pod *corev1.Pod, targetPort intstr.IntOrString, servicePort int32) (int, error) {
+	switch {
+	case targetPort.Type == intstr.String && targetPort.StrVal != "":
+		for _, container := range pod.Spec.Containers {
+			for _, p := range container.Ports {
+				if p.Name == targetPort.StrVal {
+					return int(p.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, targetPort.StrVal)
+	case targetPort.IntVal != 0:
+		return int(targetPort.IntVal), nil
+	default:
+		return int(servicePort), nil
+	}
+}
+
+// probeHTTP runs a request against the forwarded port
+func probeHTTP(ctx context.Context, scheme string, localPort int, method, path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := fmt.Sprintf("%s://127.0.0.1:%d%s", scheme, localPort, path)
+
+	ctx, cancel := context.WithTimeout(ctx, portForwardProbeWait)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return fmt.Sprintf("❌ Invalid probe request: %v\n", err)
+	}
+	client := &http.Client{Transport: &http.Transport{
+		// The pod certificate is not issued for 127.0.0.1
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+
+	started := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(started).Round(time.Millisecond)
+	if err != nil {
+		return fmt.Sprintf("❌ %s %s failed after %s: %v\n", method, path, latency, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes+1))
+
+	icon := "✅"
+	if resp.StatusCode >= 400 {
+		icon = "❌"
+	}
+	result := fmt.Sprintf("%s %s %s → %s in %s\n", icon, method, path, resp.Status, latency)
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		result += fmt.Sprintf("Content-Type: %s\n", contentType)
+	}
+	if len(body) > 0 {
+		text := string(body)
+		if len(body) > maxProbeBodyBytes {
+			text = string(body[:maxProbeBodyBytes]) + "\n…[body truncated]"
+		}
+		result += fmt.Sprintf("```\n%s\n```\n", strings.TrimRight(text, "\n"))
+	}
+	return result
+}
+
+func (s *Server) portForwardHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	namespace := mcp.ParseString(request, "namespace", "default")
+	podName := mcp.ParseString(request, "pod_name", "")
+	serviceName := mcp.ParseString(request, "service_name", "")
+	portStr := mcp.ParseString(request, "port", "")
+	probePath := mcp.ParseString(request, "probe_path", "")
+	if (podName == "") == (serviceName == "") {
+		return mcp.NewToolResultText("❌ Provide either pod_name or service_name"), nil
+	}
+
+	method := strings.ToUpper(mcp.ParseString(request, "probe_method", http.MethodGet))
+	if method != http.MethodGet && method != http.MethodHead {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid probe_method %q: only GET and HEAD probes are allowed", method)), nil
+	}
+	scheme := strings.ToLower(mcp.ParseString(request, "probe_scheme", "http"))
+	if scheme != "http" && scheme != "https" {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid probe_scheme %q: use http or https", scheme)), nil
+	}
+
+	ttlStr := mcp.ParseString(request, "ttl_seconds", strconv.Itoa(int(defaultPortForwardTTL.Seconds())))
+	ttlSeconds, err := strconv.Atoi(ttlStr)
+	if err != nil || ttlSeconds <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid ttl_seconds value: %s", ttlStr)), nil
+	}
+	ttl := time.Duration(ttlSeconds) * time.Second
+	if ttl > maxPortForwardTTL {
+		ttl = maxPortForwardTTL
+	}
+
+	var pod *corev1.Pod
+	var podPort int
+	target := ""
+	if serviceName != "" {
+		target = fmt.Sprintf("service/%s", serviceName)
+		pod, podPort, err = s.resolveServiceTarget(ctx, namespace, serviceName, portStr)
+		if err != nil {
+			return toolError(ctx, fmt.Sprintf("Failed to resolve service %s", serviceName), err), nil
+		}
+	} else {
+		target = fmt.Sprintf("pod/%s", podName)
+		if portStr == "" {
+			return mcp.NewToolResultText("❌ port is required when forwarding to a pod"), nil
+		}
+		podPort, err = strconv.Atoi(portStr)
+		if err != nil || podPort <= 0 || podPort > 65535 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid port value: %s", portStr)), nil
+		}
+		pod, err = s.k8sClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return toolError(ctx, fmt.Sprintf("Failed to get pod %s", podName), err), nil
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Pod %s is %s; port-forward needs a running pod", podName, pod.Status.Phase)), nil
+		}
+	}
+
+	forward := s.portForward
+	if forward == nil {
+		forward = s.remotePortForward
+	}
+	stop := make(chan struct{})
+	localPort, err := forward(namespace, pod.Name, podPort, stop)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to port-forward to %s", pod.Name), err), nil
+	}
+
+	result := "🔌 Port Forward\n"
+	result += "===============\n\n"
+	result += fmt.Sprintf("Target: %s (namespace %s)\n", target, namespace)
+	result += fmt.Sprintf("Pod: %s port %d\n", pod.Name, podPort)
+	result += fmt.Sprintf("Local: 127.0.0.1:%d\n", localPort)
+
+	if probePath != "" {
+		result += "\n🩺 Probe\n"
+		result += probeHTTP(ctx, scheme, localPort, method, probePath)
+		close(stop)
+		result += "\n🔒 Port-forward closed after the probe"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	session := &portForwardSession{
+		Target:    target,
+		Pod:       pod.Name,
+		Namespace: namespace,
+		LocalPort: localPort,
+		PodPort:   podPort,
+		Expires:   time.Now().Add(ttl),
+		stop:      stop,
+	}
+	session.tracked = s.sessions.open(SessionPortForward, fmt.Sprintf("%s/%s:%d via 127.0.0.1:%d", namespace, pod.Name, podPort, localPort), func() {
+		s.portForwards.remove(session)
+	})
+	s.portForwards.add(session, ttl)
+
+	result += fmt.Sprintf("\n⏳ Open until %s (%s); it is reachable only from the MCP host\n", formatTime(ctx, session.Expires), ttl)
+	result += fmt.Sprintf("💡 Example: curl http://127.0.0.1:%d/", localPort)
+	if open := s.portForwards.list(); len(open) > 1 {
+		result += "\n\n📋 Open port-forwards:\n"
+		for _, o := range open {
+			result += fmt.Sprintf("• 127.0.0.1:%d → %s/%s (expires %s)\n", o.LocalPort, o.Namespace, o.Target, formatTime(ctx, o.Expires))
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// PortForwardHandler is a public wrapper for portForwardHandler
+func (s *Server) PortForwardHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.portForwardHandler(ctx, request)
+}
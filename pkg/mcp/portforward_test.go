@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func readyPod(name string, labels map[string]string, ports ...corev1.ContainerPort) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Ports: ports}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestResolveServiceTarget(t *testing.T) {
+	selector := map[string]string{"app": "web"}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("web-http")},
+				{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9091)},
+				{Name: "admin", Port: 8443},
+			},
+		},
+	}
+	notReady := readyPod("web-a", selector)
+	notReady.Status.Conditions = nil
+	ready := readyPod("web-b", selector, corev1.ContainerPort{Name: "web-http", ContainerPort: 8080})
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(service, notReady, ready)}
+
+	tests := []struct {
+		port     string
+		expected int
+	}{
+		{"", 8080},
+		{"http", 8080},
+		{"9090", 9091},
+		{"admin", 8443},
+	}
+
+	for _, tt := range tests {
+		pod, port, err := s.resolveServiceTarget(context.Background(), "shop", "web", tt.port)
+		if err != nil {
+			t.Errorf("resolveServiceTarget(%q) error = %v", tt.port, err)
+			continue
+		}
+		if pod.Name != "web-b" || port != tt.expected {
+			t.Errorf("resolveServiceTarget(%q) = %s:%d, expected web-b:%d", tt.port, pod.Name, port, tt.expected)
+		}
+	}
+
+	if _, _, err := s.resolveServiceTarget(context.Background(), "shop", "web", "grpc"); err == nil {
+		t.Errorf("resolveServiceTarget with an unknown port succeeded")
+	}
+}
+
+// fakePortForward forwards to a local test HTTP server instead of a pod
+func fakePortForward(t *testing.T, handler http.HandlerFunc, forwarded *string) portForwardFunc {
+	backend := httptest.NewServer(handler)
+	t.Cleanup(backend.Close)
+	u, _ := url.Parse(backend.URL)
+	localPort, _ := strconv.Atoi(u.Port())
+
+	return func(namespace, pod string, port int, stop chan struct{}) (int, error) {
+		*forwarded = fmt.Sprintf("%s/%s:%d", namespace, pod, port)
+		return localPort, nil
+	}
+}
+
+func TestPortForwardProbe(t *testing.T) {
+	var forwarded string
+	s := &Server{
+		config:    &Config{},
+		k8sClient: kubefake.NewSimpleClientset(readyPod("web-b", nil)),
+		portForward: fakePortForward(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/healthz" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte("ok"))
+		}, &forwarded),
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "pod_name": "web-b", "port": "8080", "probe_path": "healthz"}
+	result, _ := s.portForwardHandler(context.Background(), request)
+	text := resultText(result)
+	for _, expected := range []string{"✅ GET /healthz → 200 OK", "```\nok\n```", "closed after the probe"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("port_forward result missing %q:\n%s", expected, text)
+		}
+	}
+	if forwarded != "shop/web-b:8080" {
+		t.Errorf("forwarded to %s, expected shop/web-b:8080", forwarded)
+	}
+	if open := s.portForwards.list(); len(open) != 0 {
+		t.Errorf("probe left %d port-forwards open", len(open))
+	}
+}
+
+func TestPortForwardKeepsSessionOpen(t *testing.T) {
+	var forwarded string
+	s := &Server{
+		config:      &Config{},
+		k8sClient:   kubefake.NewSimpleClientset(readyPod("web-b", nil)),
+		portForward: fakePortForward(t, func(w http.ResponseWriter, r *http.Request) {}, &forwarded),
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "pod_name": "web-b", "port": "8080", "ttl_seconds": "60"}
+	result, _ := s.portForwardHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "Open until") || !strings.Contains(text, "(1m0s)") {
+		t.Errorf("port_forward result = %q", text)
+	}
+	if open := s.portForwards.list(); len(open) != 1 || open[0].Target != "pod/web-b" {
+		t.Errorf("open port-forwards = %+v, expected pod/web-b", open)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "pod_name": "web-b"}
+	result, _ = s.portForwardHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌ port is required") {
+		t.Errorf("port_forward without a port = %q", text)
+	}
+}
@@ -42,17 +42,59 @@ func (p *OpenShiftSREProfile) GetDescription() string {
 
 func (p *OpenShiftSREProfile) GetTools(s *Server) []server.ServerTool {
 	return slices.Concat(
+		s.initServerTools(),
+		s.initStepOutputTools(),
+		s.initChangeFreezeTools(),
 		s.initConfiguration(),
 		s.initOpenShiftTools(),
+		s.initMustGatherJobs(),
+		s.initClusterOperators(),
+		s.initClusterVersion(),
+		s.initControlPlaneTools(),
 		s.initPods(),
+		s.initResourceUsage(),
+		s.initNodes(),
+		s.initCapacityTools(),
+		s.initStorage(),
+		s.initAutoscaling(),
+		s.initNetworkPolicies(),
+		s.initCatalog(),
+		s.initSchemaDiscovery(),
+		s.initBatch(),
+		s.initSecrets(),
+		s.initRoutes(),
+		s.initRBAC(),
+		s.initElevation(),
+		s.initActionRecords(),
 		s.initResources(),
+		s.initWatch(),
+		s.initRemediation(),
+		s.initAutoRemediation(),
 		s.initEvents(),
 		s.initNamespaces(),
 		s.initWriteOperations(),
 		s.initGitTools(),
 		s.initArgocdTools(), // Add ArgoCD tools
+		s.initOnboarding(),
+		s.initCloning(),
 		s.initDiagnostics(),
+		s.initAnalysisRules(),
 		s.initMonitoring(),
+		s.initAlertTools(),
+		s.initWarRoom(),
+		s.initDNSTools(),
+		s.initNodeNetworkTools(),
+		s.initOVNTools(),
+		s.initIngressTools(),
+		s.initCSITools(),
+		s.initImageStreams(),
+		s.initImageInventory(),
+		s.initCertAudit(),
+		s.initBuildConfigs(),
+		s.initBaselineTools(),
+		s.initInventoryTools(),
+		s.initOwnershipTools(),
+		s.initNotificationTools(),
 		s.initWriteOperations(), // Add write operations for SRE
 	)
 }
@@ -70,14 +112,37 @@ func (p *OpenShiftDeveloperProfile) GetDescription() string {
 
 func (p *OpenShiftDeveloperProfile) GetTools(s *Server) []server.ServerTool {
 	return slices.Concat(
+		s.initServerTools(),
+		s.initStepOutputTools(),
+		s.initChangeFreezeTools(),
 		s.initConfiguration(),
 		s.initPods(),
+		s.initResourceUsage(),
+		s.initStorage(),
+		s.initAutoscaling(),
+		s.initNetworkPolicies(),
+		s.initCatalog(),
+		s.initSchemaDiscovery(),
+		s.initBatch(),
+		s.initSecrets(),
+		s.initRoutes(),
+		s.initRBAC(),
+		s.initElevation(),
+		s.initActionRecords(),
 		s.initResources(),
+		s.initWatch(),
+		s.initRemediation(),
 		s.initWriteOperations(),
 		s.initGitTools(),
 		s.initArgocdTools(), // Add ArgoCD tools
+		s.initOnboarding(),
+		s.initCloning(),
 		s.initHelm(),
+		s.initMonitoring(),
+		s.initAlertTools(),
 		s.initImageStreams(),
+		s.initImageInventory(),
+		s.initCertAudit(),
 		s.initBuildConfigs(),
 		s.initDeploymentConfigs(),
 	)
@@ -96,46 +161,69 @@ func (p *OpenShiftAdminProfile) GetDescription() string {
 
 func (p *OpenShiftAdminProfile) GetTools(s *Server) []server.ServerTool {
 	return slices.Concat(
+		s.initServerTools(),
+		s.initStepOutputTools(),
+		s.initChangeFreezeTools(),
 		s.initConfiguration(),
 		s.initOpenShiftTools(),
+		s.initMustGatherJobs(),
+		s.initClusterOperators(),
+		s.initClusterVersion(),
+		s.initControlPlaneTools(),
 		s.initPods(),
+		s.initResourceUsage(),
+		s.initNodes(),
+		s.initCapacityTools(),
+		s.initStorage(),
+		s.initAutoscaling(),
+		s.initNetworkPolicies(),
+		s.initCatalog(),
+		s.initSchemaDiscovery(),
+		s.initBatch(),
+		s.initSecrets(),
+		s.initRoutes(),
+		s.initRBAC(),
+		s.initElevation(),
+		s.initActionRecords(),
 		s.initResources(),
+		s.initWatch(),
+		s.initRemediation(),
+		s.initAutoRemediation(),
 		s.initEvents(),
 		s.initNamespaces(),
 		s.initWriteOperations(),
 		s.initGitTools(),
 		s.initArgocdTools(), // Add ArgoCD tools
+		s.initOnboarding(),
+		s.initCloning(),
 		s.initHelm(),
 		s.initDiagnostics(),
+		s.initAnalysisRules(),
 		s.initMonitoring(),
+		s.initAlertTools(),
+		s.initWarRoom(),
+		s.initDNSTools(),
+		s.initNodeNetworkTools(),
+		s.initOVNTools(),
+		s.initIngressTools(),
+		s.initCSITools(),
+		s.initBaselineTools(),
+		s.initInventoryTools(),
+		s.initOwnershipTools(),
+		s.initNotificationTools(),
 		s.initImageStreams(),
+		s.initImageInventory(),
+		s.initCertAudit(),
 		s.initBuildConfigs(),
 		s.initDeploymentConfigs(),
 		s.initClusterAdmin(),
+		s.initPerformanceTools(),
+		s.initUsageTools(),
+		s.initRuntimeTools(),
 	)
 }
 
 // Additional OpenShift-specific tool initializers
-func (s *Server) initDiagnostics() []server.ServerTool {
-	// Diagnostic tools implementation
-	return []server.ServerTool{}
-}
-
-func (s *Server) initMonitoring() []server.ServerTool {
-	// Monitoring tools implementation
-	return []server.ServerTool{}
-}
-
-func (s *Server) initImageStreams() []server.ServerTool {
-	// ImageStream tools implementation
-	return []server.ServerTool{}
-}
-
-func (s *Server) initBuildConfigs() []server.ServerTool {
-	// BuildConfig tools implementation
-	return []server.ServerTool{}
-}
-
 func (s *Server) initDeploymentConfigs() []server.ServerTool {
 	// DeploymentConfig tools implementation
 	return []server.ServerTool{}
@@ -0,0 +1,383 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Phases a tool call or chat query spends its time in
+const (
+	PhaseAPI  = "api"  // Kubernetes API requests
+	PhaseLLM  = "llm"  // LLM planning and analysis
+	PhaseExec = "exec" // pod exec and oc/kubectl commands
+)
+
+const (
+	// profileSamples is how many recent durations are kept per tool for percentiles
+	profileSamples = 200
+	// slowQueryLimit is how many of the slowest chat queries are kept
+	slowQueryLimit = 20
+	// informerHintCalls is the average API calls per invocation above which
+	// the report suggests an informer cache
+	informerHintCalls  = 20
+	defaultReportLimit = 10
+)
+
+// callProfile accumulates the API calls and phase times of one tool call or
+// chat query
+type callProfile struct {
+	mu        sync.Mutex
+	apiCalls  int
+	toolCalls int
+	phases    map[string]time.Duration
+}
+
+func newCallProfile() *callProfile {
+	return &callProfile{phases: make(map[string]time.Duration)}
+}
+
+func (p *callProfile) add(phase string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.phases[phase] += d
+	if phase == PhaseAPI {
+		p.apiCalls++
+	}
+}
+
+func (p *callProfile) snapshot() (int, int, map[string]time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	phases := make(map[string]time.Duration, len(p.phases))
+	for phase, d := range p.phases {
+		phases[phase] = d
+	}
+	return p.apiCalls, p.toolCalls, phases
+}
+
+type (
+	toolProfileKey  struct{}
+	queryProfileKey struct{}
+)
+
+// RecordPhase adds time spent in a phase to the tool call and chat query
+// running with ctx
+func RecordPhase(ctx context.Context, phase string, d time.Duration) {
+	for _, key := range []interface{}{toolProfileKey{}, queryProfileKey{}} {
+		if profile, ok := ctx.Value(key).(*callProfile); ok {
+			profile.add(phase, d)
+		}
+	}
+}
+
+// trackPhase times a phase until the returned function is called
+func trackPhase(ctx context.Context, phase string) func() {
+	start := time.Now()
+	return func() { RecordPhase(ctx, phase, time.Since(start)) }
+}
+
+// profilingTransport counts Kubernetes API requests and their latency
+// against the call that made them
+type profilingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *profilingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer trackPhase(req.Context(), PhaseAPI)()
+	return t.next.RoundTrip(req)
+}
+
+// wrapProfilingTransport is a rest.Config transport wrapper
+func wrapProfilingTransport(rt http.RoundTripper) http.RoundTripper {
+	return &profilingTransport{next: rt}
+}
+
+// timeSink names the phase a call spent most of its time in; time outside
+// the tracked phases is local processing
+func timeSink(total time.Duration, phases map[string]time.Duration) (string, float64) {
+	sink, longest := "local", total
+	for _, d := range phases {
+		longest -= d
+	}
+	if longest < 0 {
+		longest = 0
+	}
+	for _, phase := range sortedKeys(phases) {
+		if phases[phase] > longest {
+			sink, longest = phase, phases[phase]
+		}
+	}
+	if total <= 0 {
+		return sink, 0
+	}
+	return sink, float64(longest) / float64(total)
+}
+
+// percentile returns the p-th percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1)*p + 0.5)
+	return sorted[index]
+}
+
+// ToolProfile is the latency distribution and time sinks of one tool
+type ToolProfile struct {
+	Name        string                   `json:"name"`
+	Calls       int                      `json:"calls"`
+	P50         time.Duration            `json:"p50"`
+	P95         time.Duration            `json:"p95"`
+	Max         time.Duration            `json:"max"`
+	Total       time.Duration            `json:"total"`
+	AvgAPICalls float64                  `json:"avg_api_calls"`
+	Phases      map[string]time.Duration `json:"phases"`
+}
+
+// QueryProfile is one slow chat query
+type QueryProfile struct {
+	Query     string                   `json:"query"`
+	At        time.Time                `json:"at"`
+	Duration  time.Duration            `json:"duration"`
+	ToolCalls int                      `json:"tool_calls"`
+	APICalls  int                      `json:"api_calls"`
+	Phases    map[string]time.Duration `json:"phases"`
+}
+
+type toolStats struct {
+	calls    int
+	samples  []time.Duration // ring of the latest profileSamples durations
+	next     int
+	max      time.Duration
+	total    time.Duration
+	apiCalls int
+	phases   map[string]time.Duration
+}
+
+// Profiler keeps per-tool latency distributions and the slowest chat queries
+type Profiler struct {
+	mu      sync.Mutex
+	since   time.Time
+	tools   map[string]*toolStats
+	queries []QueryProfile // slowest first
+}
+
+// NewProfiler creates an empty profiler
+func NewProfiler() *Profiler {
+	return &Profiler{since: time.Now(), tools: make(map[string]*toolStats)}
+}
+
+func (p *Profiler) recordTool(name string, d time.Duration, profile *callProfile) {
+	apiCalls, _, phases := profile.snapshot()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.tools[name]
+	if stats == nil {
+		stats = &toolStats{phases: make(map[string]time.Duration)}
+		p.tools[name] = stats
+	}
+	stats.calls++
+	if len(stats.samples) < profileSamples {
+		stats.samples = append(stats.samples, d)
+	} else {
+		stats.samples[stats.next] = d
+		stats.next = (stats.next + 1) % profileSamples
+	}
+	if d > stats.max {
+		stats.max = d
+	}
+	stats.total += d
+	stats.apiCalls += apiCalls
+	for phase, pd := range phases {
+		stats.phases[phase] += pd
+	}
+}
+
+func (p *Profiler) recordQuery(query QueryProfile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queries = append(p.queries, query)
+	sort.SliceStable(p.queries, func(i, j int) bool { return p.queries[i].Duration > p.queries[j].Duration })
+	if len(p.queries) > slowQueryLimit {
+		p.queries = p.queries[:slowQueryLimit]
+	}
+}
+
+// Tools returns the profile of every tool called, slowest p95 first
+func (p *Profiler) Tools() []ToolProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	profiles := make([]ToolProfile, 0, len(p.tools))
+	for name, stats := range p.tools {
+		sorted := append([]time.Duration(nil), stats.samples...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		phases := make(map[string]time.Duration, len(stats.phases))
+		for phase, d := range stats.phases {
+			phases[phase] = d
+		}
+		profiles = append(profiles, ToolProfile{
+			Name:        name,
+			Calls:       stats.calls,
+			P50:         percentile(sorted, 0.50),
+			P95:         percentile(sorted, 0.95),
+			Max:         stats.max,
+			Total:       stats.total,
+			AvgAPICalls: float64(stats.apiCalls) / float64(stats.calls),
+			Phases:      phases,
+		})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].P95 != profiles[j].P95 {
+			return profiles[i].P95 > profiles[j].P95
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// SlowQueries returns the slowest chat queries, slowest first
+func (p *Profiler) SlowQueries() []QueryProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]QueryProfile(nil), p.queries...)
+}
+
+// withProfiling records the latency and API calls of every tool call
+func (s *Server) withProfiling(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.profiler == nil {
+			return handler(ctx, request)
+		}
+		if query, ok := ctx.Value(queryProfileKey{}).(*callProfile); ok {
+			query.mu.Lock()
+			query.toolCalls++
+			query.mu.Unlock()
+		}
+		profile := newCallProfile()
+		start := time.Now()
+		result, err := handler(context.WithValue(ctx, toolProfileKey{}, profile), request)
+		s.profiler.recordTool(name, time.Since(start), profile)
+		return result, err
+	}
+}
+
+// ProfileQuery starts profiling a chat query; tool calls, API requests and
+// LLM time recorded with the returned context count towards it until the
+// returned function is called
+func (s *Server) ProfileQuery(ctx context.Context, query string) (context.Context, func()) {
+	if s.profiler == nil {
+		return ctx, func() {}
+	}
+	profile := newCallProfile()
+	start := time.Now()
+	return context.WithValue(ctx, queryProfileKey{}, profile), func() {
+		apiCalls, toolCalls, phases := profile.snapshot()
+		s.profiler.recordQuery(QueryProfile{
+			Query:     query,
+			At:        start,
+			Duration:  time.Since(start),
+			ToolCalls: toolCalls,
+			APICalls:  apiCalls,
+			Phases:    phases,
+		})
+	}
+}
+
+func formatSink(total time.Duration, phases map[string]time.Duration) string {
+	sink, share := timeSink(total, phases)
+	return fmt.Sprintf("mostly %s (%.0f%%)", sink, share*100)
+}
+
+func truncateQuery(query string, limit int) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) <= limit {
+		return query
+	}
+	return query[:limit] + "..."
+}
+
+func (s *Server) initPerformanceTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("performance_report",
+			mcp.WithDescription("Report the slowest tools and chat queries since the server started, with latency percentiles, Kubernetes API calls per invocation and where the time went (API, LLM, exec)"),
+			mcp.WithString("limit", mcp.Description(fmt.Sprintf("Number of tools and queries to list (default %d)", defaultReportLimit))),
+			mcp.WithTitleAnnotation("Server: Performance Report"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.performanceReportHandler)},
+	}
+}
+
+func (s *Server) performanceReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.profiler == nil {
+		return mcp.NewToolResultText("❌ Profiling is not enabled on this server"), nil
+	}
+	limit := defaultReportLimit
+	if value := mcp.ParseString(request, "limit", ""); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid limit '%s': expected a positive number", value)), nil
+		}
+		limit = parsed
+	}
+
+	tools := s.profiler.Tools()
+	queries := s.profiler.SlowQueries()
+	calls := 0
+	for _, tool := range tools {
+		calls += tool.Calls
+	}
+
+	result := "⏱️  Performance Report\n"
+	result += "======================\n\n"
+	result += fmt.Sprintf("Since %s: %d tool calls across %d tools\n", formatTime(ctx, s.profiler.since), calls, len(tools))
+
+	result += "\n🐢 Slowest tools (by p95):\n"
+	if len(tools) == 0 {
+		result += "No tool calls recorded yet\n"
+	}
+	var chatty []string
+	for i, tool := range tools {
+		if tool.AvgAPICalls > informerHintCalls {
+			chatty = append(chatty, tool.Name)
+		}
+		if i >= limit {
+			continue
+		}
+		result += fmt.Sprintf("• %s - %d calls, p50 %s, p95 %s, max %s\n", tool.Name, tool.Calls,
+			tool.P50.Round(time.Millisecond), tool.P95.Round(time.Millisecond), tool.Max.Round(time.Millisecond))
+		result += fmt.Sprintf("   %.1f API calls/call, %s\n", tool.AvgAPICalls, formatSink(tool.Total, tool.Phases))
+	}
+
+	if len(queries) > 0 {
+		result += "\n🐢 Slowest chat queries:\n"
+		for i, query := range queries {
+			if i >= limit {
+				break
+			}
+			result += fmt.Sprintf("• %s %q\n", query.Duration.Round(time.Millisecond), truncateQuery(query.Query, 80))
+			result += fmt.Sprintf("   %d tool calls, %d API calls, %s\n", query.ToolCalls, query.APICalls, formatSink(query.Duration, query.Phases))
+		}
+	}
+
+	if len(chatty) > 0 {
+		sort.Strings(chatty)
+		result += fmt.Sprintf("\n💡 %s make more than %d API calls per invocation; an informer cache would cut their latency and API server load\n",
+			strings.Join(chatty, ", "), informerHintCalls)
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// PerformanceReportHandler is a public wrapper for performanceReportHandler
+func (s *Server) PerformanceReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.performanceReportHandler(ctx, request)
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0.50, 6},
+		{0.95, 10},
+		{0, 1},
+	}
+
+	for _, tt := range tests {
+		if result := percentile(sorted, tt.p); result != tt.expected {
+			t.Errorf("percentile(%v) = %v, expected %v", tt.p, result, tt.expected)
+		}
+	}
+	if result := percentile(nil, 0.5); result != 0 {
+		t.Errorf("percentile(nil) = %v, expected 0", result)
+	}
+}
+
+func TestTimeSink(t *testing.T) {
+	tests := []struct {
+		total    time.Duration
+		phases   map[string]time.Duration
+		expected string
+		share    float64
+	}{
+		{10 * time.Second, map[string]time.Duration{PhaseAPI: 2 * time.Second, PhaseLLM: 7 * time.Second}, PhaseLLM, 0.7},
+		{10 * time.Second, map[string]time.Duration{PhaseExec: 2 * time.Second}, "local", 0.8},
+		{0, nil, "local", 0},
+	}
+
+	for _, tt := range tests {
+		sink, share := timeSink(tt.total, tt.phases)
+		if sink != tt.expected || share != tt.share {
+			t.Errorf("timeSink(%v, %v) = %s, %v, expected %s, %v", tt.total, tt.phases, sink, share, tt.expected, tt.share)
+		}
+	}
+}
+
+type stubTransport struct{}
+
+func (stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestWithProfiling(t *testing.T) {
+	s := &Server{profiler: NewProfiler()}
+	client := &http.Client{Transport: wrapProfilingTransport(stubTransport{})}
+	handler := s.withProfiling("list_pods", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for i := 0; i < 25; i++ {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/api/v1/pods", nil)
+			if resp, err := client.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+		RecordPhase(ctx, PhaseExec, 50*time.Millisecond)
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	ctx, finish := s.ProfileQuery(context.Background(), "why are my pods crashing?")
+	RecordPhase(ctx, PhaseLLM, time.Second)
+	handler(ctx, mcp.CallToolRequest{})
+	handler(ctx, mcp.CallToolRequest{})
+	finish()
+
+	tools := s.profiler.Tools()
+	if len(tools) != 1 || tools[0].Name != "list_pods" || tools[0].Calls != 2 || tools[0].AvgAPICalls != 25 {
+		t.Fatalf("profiler tools = %+v, expected list_pods with 2 calls and 25 API calls each", tools)
+	}
+	if tools[0].Phases[PhaseExec] != 100*time.Millisecond {
+		t.Errorf("list_pods exec time = %v, expected 100ms", tools[0].Phases[PhaseExec])
+	}
+
+	queries := s.profiler.SlowQueries()
+	if len(queries) != 1 || queries[0].ToolCalls != 2 || queries[0].APICalls != 50 || queries[0].Phases[PhaseLLM] != time.Second {
+		t.Fatalf("profiler queries = %+v, expected one query with 2 tool calls and 50 API calls", queries)
+	}
+
+	result, err := s.PerformanceReportHandler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("PerformanceReportHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"2 tool calls across 1 tools",
+		"• list_pods - 2 calls",
+		"25.0 API calls/call",
+		`"why are my pods crashing?"`,
+		"2 tool calls, 50 API calls",
+		"💡 list_pods make more than 20 API calls per invocation",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("performance_report output missing %q:\n%s", want, text)
+		}
+	}
+}
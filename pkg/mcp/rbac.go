@@ -0,0 +1,361 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// parseAccessResource turns a resource such as "deployments", "deploy",
+// "pods/log" or "routes.route.openshift.io" into review attributes, using
+// discovery for the API group when it is available
+func (s *Server) parseAccessResource(resourceType string) (authorizationv1.ResourceAttributes, error) {
+	resource, subresource, _ := strings.Cut(strings.ToLower(strings.TrimSpace(resourceType)), "/")
+	if resource == "" {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf("resource is required")
+	}
+	if resource == "*" {
+		return authorizationv1.ResourceAttributes{Resource: "*", Group: "*", Subresource: subresource}, nil
+	}
+	if s.restMapper != nil {
+		if gvr, _, err := s.resolveResource(resource); err == nil {
+			return authorizationv1.ResourceAttributes{Group: gvr.Group, Resource: gvr.Resource, Subresource: subresource}, nil
+		}
+	}
+
+	if alias, ok := resourceAliases[resource]; ok {
+		resource = alias
+	}
+	attributes := authorizationv1.ResourceAttributes{Resource: resource, Subresource: subresource}
+	if idx := strings.Index(resource, "."); idx > 0 {
+		attributes.Resource, attributes.Group = resource[:idx], resource[idx+1:]
+	}
+	return attributes, nil
+}
+
+// parseAccessRequest reads the verb, resource, namespace and name shared by
+// can_i and who_can
+func (s *Server) parseAccessRequest(request mcp.CallToolRequest) (authorizationv1.ResourceAttributes, error) {
+	verb := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "verb", "")))
+	if verb == "" {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf("verb is required (e.g. get, list, create, patch, delete)")
+	}
+	attributes, err := s.parseAccessResource(mcp.ParseString(request, "resource", ""))
+	if err != nil {
+		return attributes, err
+	}
+	attributes.Verb = verb
+	attributes.Namespace = strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	attributes.Name = strings.TrimSpace(mcp.ParseString(request, "resource_name", ""))
+	return attributes, nil
+}
+
+// describeAccess renders the attributes of an access question
+func describeAccess(attributes authorizationv1.ResourceAttributes) string {
+	text := accessCheckName(attributes)
+	if attributes.Name != "" {
+		text += " " + attributes.Name
+	}
+	if attributes.Namespace != "" {
+		text += " in namespace " + attributes.Namespace
+	} else {
+		text += " cluster-wide"
+	}
+	return text
+}
+
+// matchesRBAC reports whether an RBAC rule value list covers value
+func matchesRBAC(values []string, value string) bool {
+	for _, v := range values {
+		if v == rbacv1.VerbAll || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleAllows reports whether a policy rule grants the access, following the
+// RBAC authorizer's matching of wildcards, subresources and resource names
+func ruleAllows(rule rbacv1.PolicyRule, attributes authorizationv1.ResourceAttributes) bool {
+	if !matchesRBAC(rule.Verbs, attributes.Verb) || !matchesRBAC(rule.APIGroups, attributes.Group) {
+		return false
+	}
+	resource := attributes.Resource
+	if attributes.Subresource != "" {
+		resource += "/" + attributes.Subresource
+	}
+	resourceMatch := false
+	for _, r := range rule.Resources {
+		if r == rbacv1.ResourceAll || r == resource ||
+			(attributes.Subresource != "" && r == "*/"+attributes.Subresource) {
+			resourceMatch = true
+			break
+		}
+	}
+	if !resourceMatch {
+		return false
+	}
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	// Rules limited to names never grant list, watch or create of arbitrary names
+	if attributes.Name == "" {
+		return false
+	}
+	for _, name := range rule.ResourceNames {
+		if name == attributes.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func rulesAllow(rules []rbacv1.PolicyRule, attributes authorizationv1.ResourceAttributes) bool {
+	for _, rule := range rules {
+		if ruleAllows(rule, attributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessSubject is a user, group or service account and the bindings that
+// grant it the access
+type accessSubject struct {
+	kind string
+	name string
+	via  []string
+}
+
+func subjectName(subject rbacv1.Subject, bindingNamespace string) string {
+	if subject.Kind != rbacv1.ServiceAccountKind {
+		return subject.Name
+	}
+	namespace := subject.Namespace
+	if namespace == "" {
+		namespace = bindingNamespace
+	}
+	return namespace + "/" + subject.Name
+}
+
+// whoCan finds the subjects bound to roles that grant the access. Namespaced
+// questions consider the namespace's RoleBindings as well as ClusterRoleBindings.
+func (s *Server) whoCan(ctx context.Context, attributes authorizationv1.ResourceAttributes) ([]*accessSubject, error) {
+	clusterRoles, err := s.k8sClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing cluster roles: %w", err)
+	}
+	clusterRoleAllows := make(map[string]bool, len(clusterRoles.Items))
+	for _, role := range clusterRoles.Items {
+		clusterRoleAllows[role.Name] = rulesAllow(role.Rules, attributes)
+	}
+
+	subjects := map[string]*accessSubject{}
+	grant := func(bound []rbacv1.Subject, bindingNamespace, via string) {
+		for _, subject := range bound {
+			name := subjectName(subject, bindingNamespace)
+			key := subject.Kind + "/" + name
+			if subjects[key] == nil {
+				subjects[key] = &accessSubject{kind: subject.Kind, name: name}
+			}
+			subjects[key].via = append(subjects[key].via, via)
+		}
+	}
+
+	clusterBindings, err := s.k8sClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing cluster role bindings: %w", err)
+	}
+	for _, binding := range clusterBindings.Items {
+		if binding.RoleRef.Kind == "ClusterRole" && clusterRoleAllows[binding.RoleRef.Name] {
+			grant(binding.Subjects, "", fmt.Sprintf("ClusterRoleBinding %s (ClusterRole %s)", binding.Name, binding.RoleRef.Name))
+		}
+	}
+
+	if attributes.Namespace != "" {
+		roles, err := s.k8sClient.RbacV1().Roles(attributes.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("listing roles in %s: %w", attributes.Namespace, err)
+		}
+		roleAllows := make(map[string]bool, len(roles.Items))
+		for _, role := range roles.Items {
+			roleAllows[role.Name] = rulesAllow(role.Rules, attributes)
+		}
+
+		bindings, err := s.k8sClient.RbacV1().RoleBindings(attributes.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("listing role bindings in %s: %w", attributes.Namespace, err)
+		}
+		for _, binding := range bindings.Items {
+			allowed := roleAllows[binding.RoleRef.Name]
+			if binding.RoleRef.Kind == "ClusterRole" {
+				allowed = clusterRoleAllows[binding.RoleRef.Name]
+			}
+			if allowed {
+				grant(binding.Subjects, binding.Namespace, fmt.Sprintf("RoleBinding %s (%s %s)", binding.Name, binding.RoleRef.Kind, binding.RoleRef.Name))
+			}
+		}
+	}
+
+	// Keys sort by kind, then name
+	list := make([]*accessSubject, 0, len(subjects))
+	for _, key := range sortedKeys(subjects) {
+		list = append(list, subjects[key])
+	}
+	return list, nil
+}
+
+func (s *Server) initRBAC() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("can_i",
+			mcp.WithDescription("Check whether the server's identity, or a given user, may perform a verb on a resource; use before write operations that could fail with Forbidden"),
+			mcp.WithString("verb", mcp.Description("Verb to check: get, list, watch, create, update, patch, delete, or * for all"), mcp.Required()),
+			mcp.WithString("resource", mcp.Description("Resource type, e.g. deployments, pods/exec or routes.route.openshift.io"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace to check in (default: cluster-wide)")),
+			mcp.WithString("resource_name", mcp.Description("Name of a specific resource")),
+			mcp.WithString("as_user", mcp.Description("Check for this user or system:serviceaccount:<namespace>:<name> instead of the server's identity")),
+			mcp.WithString("as_groups", mcp.Description("Comma-separated groups of as_user")),
+			mcp.WithTitleAnnotation("RBAC: Can I"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.canIHandler)},
+		{Tool: mcp.NewTool("who_can",
+			mcp.WithDescription("List the users, groups and service accounts whose role bindings allow a verb on a resource"),
+			mcp.WithString("verb", mcp.Description("Verb to check: get, list, watch, create, update, patch, delete"), mcp.Required()),
+			mcp.WithString("resource", mcp.Description("Resource type, e.g. secrets, pods/exec or deployments.apps"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace to check in; without it only cluster-wide bindings count")),
+			mcp.WithString("resource_name", mcp.Description("Name of a specific resource")),
+			mcp.WithTitleAnnotation("RBAC: Who Can"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.whoCanHandler)},
+		{Tool: mcp.NewTool("audit_rbac",
+			mcp.WithDescription("Audit RBAC hygiene: role bindings and cluster role bindings to deleted users, groups or service accounts (or to service accounts of deleted namespaces), bindings to roles that do not exist, roles nobody binds and roles granting wildcard verbs, resources or API groups. Reports a risk score per namespace and the YAML that cleans the stale bindings and roles up"),
+			mcp.WithString("namespace", mcp.Description("Only audit this namespace's roles and bindings (default: the whole cluster)")),
+			mcp.WithBoolean("include_platform", mcp.Description("Also audit openshift-* and kube-* namespaces and the platform's own roles (default false)")),
+			mcp.WithTitleAnnotation("RBAC: Hygiene Audit"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.auditRBACHandler)},
+	}
+}
+
+func (s *Server) canIHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	attributes, err := s.parseAccessRequest(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	user := strings.TrimSpace(mcp.ParseString(request, "as_user", ""))
+	var groups []string
+	for _, group := range strings.Split(mcp.ParseString(request, "as_groups", ""), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	if user == "" && len(groups) > 0 {
+		return mcp.NewToolResultText("❌ as_groups requires as_user"), nil
+	}
+
+	var status authorizationv1.SubjectAccessReviewStatus
+	subject := "the MCP server"
+	if user == "" {
+		review, err := s.k8sClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return toolError(ctx, "Failed to review access", err), nil
+		}
+		status = review.Status
+	} else {
+		subject = user
+		review, err := s.k8sClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{ResourceAttributes: &attributes, User: user, Groups: groups},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return toolError(ctx, fmt.Sprintf("Failed to review access for %s", user), err), nil
+		}
+		status = review.Status
+	}
+
+	result := "🔐 Access Review\n"
+	result += "================\n\n"
+	result += fmt.Sprintf("Subject: %s\n", subject)
+	if len(groups) > 0 {
+		result += fmt.Sprintf("Groups: %s\n", strings.Join(groups, ", "))
+	}
+	result += fmt.Sprintf("Question: %s\n\n", describeAccess(attributes))
+
+	switch {
+	case status.Allowed:
+		result += "✅ Yes - allowed"
+	case status.Denied:
+		result += "🚫 No - explicitly denied"
+	default:
+		result += "🚫 No - not allowed"
+	}
+	if status.Reason != "" {
+		result += fmt.Sprintf("\nReason: %s", status.Reason)
+	}
+	if status.EvaluationError != "" {
+		result += fmt.Sprintf("\n⚠️  Evaluation error: %s", status.EvaluationError)
+	}
+	if !status.Allowed {
+		result += fmt.Sprintf("\n\n💡 Use who_can verb=%s resource=%s to see who holds this permission", attributes.Verb, strings.TrimPrefix(accessCheckName(attributes), attributes.Verb+" "))
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) whoCanHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	attributes, err := s.parseAccessRequest(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	subjects, err := s.whoCan(ctx, attributes)
+	if err != nil {
+		return toolError(ctx, "Failed to read RBAC bindings", err), nil
+	}
+
+	result := "🔐 Who Can\n"
+	result += "==========\n\n"
+	result += fmt.Sprintf("Question: who can %s\n", describeAccess(attributes))
+	if len(subjects) == 0 {
+		result += "\n🚫 No role binding grants this access"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	result += fmt.Sprintf("👥 Found %d subject(s):\n", len(subjects))
+	kind := ""
+	for _, subject := range subjects {
+		if subject.kind != kind {
+			kind = subject.kind
+			result += fmt.Sprintf("\n%s:\n", kind)
+		}
+		result += fmt.Sprintf("• %s\n", subject.name)
+		for _, via := range subject.via {
+			result += fmt.Sprintf("   via %s\n", via)
+		}
+	}
+	result += "\nℹ️  Based on RBAC bindings only; other authorizers and admission webhooks may still refuse"
+	return mcp.NewToolResultText(result), nil
+}
+
+// CanIHandler is a public wrapper for canIHandler
+func (s *Server) CanIHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.canIHandler(ctx, request)
+}
+
+// WhoCanHandler is a public wrapper for whoCanHandler
+func (s *Server) WhoCanHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.whoCanHandler(ctx, request)
+}
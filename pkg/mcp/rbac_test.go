@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRuleAllows(t *testing.T) {
+	deployments := authorizationv1.ResourceAttributes{Verb: "patch", Group: "apps", Resource: "deployments"}
+	named := authorizationv1.ResourceAttributes{Verb: "get", Resource: "secrets", Name: "db"}
+	exec := authorizationv1.ResourceAttributes{Verb: "create", Resource: "pods", Subresource: "exec"}
+
+	tests := []struct {
+		rule       rbacv1.PolicyRule
+		attributes authorizationv1.ResourceAttributes
+		expected   bool
+	}{
+		{rbacv1.PolicyRule{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}, deployments, true},
+		{rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}, deployments, true},
+		{rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}, deployments, false},
+		{rbacv1.PolicyRule{Verbs: []string{"patch"}, APIGroups: []string{""}, Resources: []string{"deployments"}}, deployments, false},
+		{rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}}, named, true},
+		{rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"other"}}, named, false},
+		{rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}},
+			authorizationv1.ResourceAttributes{Verb: "list", Resource: "secrets"}, false},
+		{rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods"}}, exec, false},
+		{rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}, exec, true},
+		{rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"*/exec"}}, exec, true},
+	}
+
+	for _, tt := range tests {
+		if allowed := ruleAllows(tt.rule, tt.attributes); allowed != tt.expected {
+			t.Errorf("ruleAllows(%+v, %s) = %v, expected %v", tt.rule, describeAccess(tt.attributes), allowed, tt.expected)
+		}
+	}
+}
+
+func TestParseAccessResource(t *testing.T) {
+	s := &Server{}
+	tests := []struct {
+		resource string
+		expected authorizationv1.ResourceAttributes
+	}{
+		{"pods", authorizationv1.ResourceAttributes{Resource: "pods"}},
+		{"deploy", authorizationv1.ResourceAttributes{Resource: "deployments"}},
+		{"pods/log", authorizationv1.ResourceAttributes{Resource: "pods", Subresource: "log"}},
+		{"routes.route.openshift.io", authorizationv1.ResourceAttributes{Resource: "routes", Group: "route.openshift.io"}},
+	}
+
+	for _, tt := range tests {
+		if attributes, err := s.parseAccessResource(tt.resource); err != nil || attributes != tt.expected {
+			t.Errorf("parseAccessResource(%q) = %+v, %v, expected %+v", tt.resource, attributes, err, tt.expected)
+		}
+	}
+}
+
+func accessRequest(args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func TestCanI(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Verb == "get"
+		return true, review, nil
+	})
+	s := &Server{config: &Config{}, k8sClient: client}
+
+	result, _ := s.CanIHandler(context.Background(), accessRequest(map[string]interface{}{"verb": "get", "resource": "pods", "namespace": "shop"}))
+	if text := resultText(result); !strings.Contains(text, "Question: get pods in namespace shop") || !strings.Contains(text, "✅ Yes") {
+		t.Errorf("can_i get pods = %q", text)
+	}
+	result, _ = s.CanIHandler(context.Background(), accessRequest(map[string]interface{}{"verb": "delete", "resource": "deployments.apps"}))
+	if text := resultText(result); !strings.Contains(text, "🚫 No - not allowed") || !strings.Contains(text, "who_can verb=delete resource=deployments.apps") {
+		t.Errorf("can_i delete deployments = %q", text)
+	}
+	result, _ = s.CanIHandler(context.Background(), accessRequest(map[string]interface{}{"verb": "get", "resource": "pods", "as_groups": "devs"}))
+	if text := resultText(result); text != "❌ as_groups requires as_user" {
+		t.Errorf("can_i with groups only = %q", text)
+	}
+}
+
+func TestWhoCan(t *testing.T) {
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "admin"}, Rules: []rbacv1.PolicyRule{
+			{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+		}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "view"}, Rules: []rbacv1.PolicyRule{
+			{Verbs: []string{"get", "list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+		}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "shop"}, Rules: []rbacv1.PolicyRule{
+			{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+		}},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-admins"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "platform"}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "viewers"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "auditor"}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "shop"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "deployer"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "pipeline"}, {Kind: rbacv1.UserKind, Name: "alice"}},
+		},
+	)}
+
+	result, err := s.WhoCanHandler(context.Background(), accessRequest(map[string]interface{}{"verb": "patch", "resource": "deployments.apps", "namespace": "shop"}))
+	if err != nil {
+		t.Fatalf("WhoCanHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Found 3 subject(s)",
+		"• platform\n   via ClusterRoleBinding platform-admins (ClusterRole admin)",
+		"• shop/pipeline\n   via RoleBinding ci (Role deployer)",
+		"• alice",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("who_can output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "auditor") {
+		t.Errorf("who_can lists a view-only user:\n%s", text)
+	}
+
+	result, _ = s.WhoCanHandler(context.Background(), accessRequest(map[string]interface{}{"verb": "patch", "resource": "deployments.apps"}))
+	if text := resultText(result); !strings.Contains(text, "Found 1 subject(s)") || strings.Contains(text, "alice") {
+		t.Errorf("cluster-wide who_can = %q", text)
+	}
+}
@@ -0,0 +1,603 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	usersGVR  = schema.GroupVersionResource{Group: "user.openshift.io", Version: "v1", Resource: "users"}
+	groupsGVR = schema.GroupVersionResource{Group: "user.openshift.io", Version: "v1", Resource: "groups"}
+)
+
+// rbacRiskWeights is what each finding adds to its namespace's risk score,
+// which is capped at 100
+var rbacRiskWeights = map[string]int{"critical": 25, "warning": 10, "info": 2}
+
+// rbacInventory is the RBAC state an audit reads. Maps left nil are unknown,
+// and the checks needing them are skipped.
+type rbacInventory struct {
+	clusterRoles    []rbacv1.ClusterRole
+	clusterBindings []rbacv1.ClusterRoleBinding
+	roles           []rbacv1.Role
+	bindings        []rbacv1.RoleBinding
+	// clusterWide is set when every binding of the cluster was read, so
+	// cluster roles nobody binds can be told apart
+	clusterWide bool
+	// namespaces maps the namespaces subjects live in to whether they exist
+	namespaces map[string]bool
+	// serviceAccounts maps an existing namespace to its service accounts
+	serviceAccounts map[string]map[string]bool
+	users           map[string]bool
+	groups          map[string]bool
+}
+
+// rbacFinding is one problem of an RBAC audit
+type rbacFinding struct {
+	namespace string // empty for cluster-scoped objects
+	severity  string
+	object    string // e.g. RoleBinding shop/deployers
+	message   string
+}
+
+// rbacAudit is what an audit found, with the objects that clean it up
+type rbacAudit struct {
+	findings []rbacFinding
+	apply    []interface{} // bindings with their stale subjects removed
+	remove   []interface{} // bindings and roles to delete
+}
+
+// namespaceRisk is the risk score of a namespace's findings
+type namespaceRisk struct {
+	namespace string
+	score     int
+	counts    map[string]int
+}
+
+// platformNamespace reports whether a namespace belongs to the platform,
+// whose RBAC the operators manage
+func platformNamespace(name string) bool {
+	return name == "openshift" || strings.HasPrefix(name, "openshift-") || strings.HasPrefix(name, "kube-")
+}
+
+// platformRBAC reports whether a role or binding is shipped with the
+// platform or owned by an operator: system: names, the bootstrap policy,
+// release manifests and objects with an owner
+func platformRBAC(meta metav1.ObjectMeta) bool {
+	if strings.HasPrefix(meta.Name, "system:") || meta.Labels["kubernetes.io/bootstrapping"] == "rbac-defaults" ||
+		meta.Labels["olm.owner"] != "" || len(meta.OwnerReferences) > 0 {
+		return true
+	}
+	for key := range meta.Annotations {
+		if strings.HasPrefix(key, "include.release.openshift.io/") {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregatedRole reports whether a cluster role is aggregated into others,
+// or aggregates others, and so is used without being bound
+func aggregatedRole(role rbacv1.ClusterRole) bool {
+	if role.AggregationRule != nil {
+		return true
+	}
+	for key := range role.Labels {
+		if strings.HasPrefix(key, "rbac.authorization.k8s.io/aggregate-to-") {
+			return true
+		}
+	}
+	return false
+}
+
+// staleSubject explains why a subject no longer exists, and whether
+// removing it is safe; it returns no reason when the subject exists or
+// cannot be checked
+func (inv rbacInventory) staleSubject(subject rbacv1.Subject, bindingNamespace string) (severity, reason string, removable bool) {
+	kind, name, namespace := subject.Kind, subject.Name, subject.Namespace
+	// Users named after a service account are that service account
+	if kind == rbacv1.UserKind && strings.HasPrefix(name, "system:serviceaccount:") {
+		parts := strings.Split(strings.TrimPrefix(name, "system:serviceaccount:"), ":")
+		if len(parts) == 2 {
+			kind, namespace, name = rbacv1.ServiceAccountKind, parts[0], parts[1]
+		}
+	}
+	switch kind {
+	case rbacv1.ServiceAccountKind:
+		if namespace == "" {
+			namespace = bindingNamespace
+		}
+		exists, known := inv.namespaces[namespace]
+		if !known {
+			return "", "", false
+		}
+		if !exists {
+			return "critical", fmt.Sprintf("service account %s/%s: namespace %s no longer exists; whoever creates it again gets these permissions", namespace, name, namespace), true
+		}
+		if accounts := inv.serviceAccounts[namespace]; accounts != nil && !accounts[name] {
+			return "warning", fmt.Sprintf("service account %s/%s no longer exists; anyone who can create service accounts in %s gets these permissions by recreating it", namespace, name, namespace), true
+		}
+	case rbacv1.UserKind:
+		if inv.users != nil && !strings.HasPrefix(name, "system:") && !inv.users[name] {
+			return "warning", fmt.Sprintf("user %s does not exist; they were deleted or never logged in, and a new identity with that name would get these permissions", name), true
+		}
+	case rbacv1.GroupKind:
+		if inv.groups != nil && !strings.HasPrefix(name, "system:") && !inv.groups[name] {
+			return "info", fmt.Sprintf("group %s has no Group object; it is stale unless the identity provider supplies it at login", name), false
+		}
+	}
+	return "", "", false
+}
+
+// wildcardGrants describes the rules of a role that use wildcards for
+// verbs, resources or API groups, and whether one grants everything
+func wildcardGrants(rules []rbacv1.PolicyRule) ([]string, bool) {
+	var grants []string
+	everything := false
+	for _, rule := range rules {
+		verbs := slices.Contains(rule.Verbs, rbacv1.VerbAll)
+		resources := false
+		for _, resource := range rule.Resources {
+			if resource == rbacv1.ResourceAll || strings.HasSuffix(resource, "/*") {
+				resources = true
+			}
+		}
+		groups := slices.Contains(rule.APIGroups, rbacv1.APIGroupAll)
+		if !verbs && !resources && !groups {
+			continue
+		}
+		if verbs && resources && groups && len(rule.ResourceNames) == 0 {
+			everything = true
+		}
+		grants = append(grants, fmt.Sprintf("verbs [%s] on [%s] in API groups [%s]",
+			strings.Join(rule.Verbs, ","), strings.Join(rule.Resources, ","), strings.Join(quoteCoreGroup(rule.APIGroups), ",")))
+	}
+	return grants, everything
+}
+
+// quoteCoreGroup shows the core API group as "" rather than nothing
+func quoteCoreGroup(groups []string) []string {
+	shown := make([]string, len(groups))
+	for i, group := range groups {
+		if group == "" {
+			group = `""`
+		}
+		shown[i] = group
+	}
+	return shown
+}
+
+// auditRBAC finds bindings to subjects or roles that no longer exist, roles
+// nobody binds and roles granting wildcards. Platform namespaces and
+// platform-managed objects are skipped unless includePlatform is set.
+func auditRBAC(inv rbacInventory, includePlatform bool) rbacAudit {
+	var audit rbacAudit
+	add := func(namespace, severity, object, message string) {
+		audit.findings = append(audit.findings, rbacFinding{namespace: namespace, severity: severity, object: object, message: message})
+	}
+	skip := func(namespace string, meta metav1.ObjectMeta) bool {
+		return !includePlatform && (platformNamespace(namespace) || platformRBAC(meta))
+	}
+
+	clusterRoles := make(map[string]bool, len(inv.clusterRoles))
+	for _, role := range inv.clusterRoles {
+		clusterRoles[role.Name] = true
+	}
+	roles := make(map[string]bool, len(inv.roles))
+	for _, role := range inv.roles {
+		roles[role.Namespace+"/"+role.Name] = true
+	}
+	boundClusterRoles := make(map[string]bool)
+	boundRoles := make(map[string]bool)
+	for _, binding := range inv.clusterBindings {
+		boundClusterRoles[binding.RoleRef.Name] = true
+	}
+	for _, binding := range inv.bindings {
+		if binding.RoleRef.Kind == "ClusterRole" {
+			boundClusterRoles[binding.RoleRef.Name] = true
+		} else {
+			boundRoles[binding.Namespace+"/"+binding.RoleRef.Name] = true
+		}
+	}
+
+	// checkBinding reports the stale subjects and missing role of a binding
+	// and returns the subjects to keep, or false when nothing changes
+	checkBinding := func(namespace, object string, roleRef rbacv1.RoleRef, subjects []rbacv1.Subject) ([]rbacv1.Subject, bool, bool) {
+		roleMissing := false
+		switch {
+		case roleRef.Kind == "ClusterRole" && inv.clusterRoles != nil && !clusterRoles[roleRef.Name]:
+			roleMissing = true
+		case roleRef.Kind == "Role" && !roles[namespace+"/"+roleRef.Name]:
+			roleMissing = true
+		}
+		if roleMissing {
+			add(namespace, "warning", object, fmt.Sprintf("binds %s %s, which does not exist; creating a role of that name grants it to the subjects at once", roleRef.Kind, roleRef.Name))
+		}
+		keep := make([]rbacv1.Subject, 0, len(subjects))
+		for _, subject := range subjects {
+			severity, reason, removable := inv.staleSubject(subject, namespace)
+			if reason != "" {
+				add(namespace, severity, object, reason)
+			}
+			if !removable {
+				keep = append(keep, subject)
+			}
+		}
+		return keep, len(keep) != len(subjects), roleMissing
+	}
+
+	for _, binding := range inv.clusterBindings {
+		if skip("", binding.ObjectMeta) {
+			continue
+		}
+		object := "ClusterRoleBinding " + binding.Name
+		keep, changed, roleMissing := checkBinding("", object, binding.RoleRef, binding.Subjects)
+		cleaned := rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: binding.Name},
+			RoleRef:    binding.RoleRef,
+			Subjects:   keep,
+		}
+		switch {
+		case roleMissing || (changed && len(keep) == 0):
+			cleaned.Subjects = binding.Subjects
+			audit.remove = append(audit.remove, cleaned)
+		case changed:
+			audit.apply = append(audit.apply, cleaned)
+		}
+	}
+	for _, binding := range inv.bindings {
+		if skip(binding.Namespace, binding.ObjectMeta) {
+			continue
+		}
+		object := fmt.Sprintf("RoleBinding %s/%s", binding.Namespace, binding.Name)
+		keep, changed, roleMissing := checkBinding(binding.Namespace, object, binding.RoleRef, binding.Subjects)
+		cleaned := rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: binding.Name, Namespace: binding.Namespace},
+			RoleRef:    binding.RoleRef,
+			Subjects:   keep,
+		}
+		switch {
+		case roleMissing || (changed && len(keep) == 0):
+			cleaned.Subjects = binding.Subjects
+			audit.remove = append(audit.remove, cleaned)
+		case changed:
+			audit.apply = append(audit.apply, cleaned)
+		}
+	}
+
+	for _, role := range inv.clusterRoles {
+		bound := boundClusterRoles[role.Name]
+		// A namespace audit only sees the cluster roles its bindings use
+		if skip("", role.ObjectMeta) || aggregatedRole(role) || (!inv.clusterWide && !bound) {
+			continue
+		}
+		object := "ClusterRole " + role.Name
+		if grants, everything := wildcardGrants(role.Rules); len(grants) > 0 {
+			severity := "info"
+			switch {
+			case bound && everything:
+				severity = "critical"
+			case bound:
+				severity = "warning"
+			}
+			add("", severity, object, "grants wildcards: "+strings.Join(grants, "; "))
+		}
+		if inv.clusterWide && !bound {
+			add("", "info", object, "is not bound by any binding")
+			audit.remove = append(audit.remove, rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: role.Name},
+			})
+		}
+	}
+	for _, role := range inv.roles {
+		if skip(role.Namespace, role.ObjectMeta) {
+			continue
+		}
+		object := fmt.Sprintf("Role %s/%s", role.Namespace, role.Name)
+		bound := boundRoles[role.Namespace+"/"+role.Name]
+		if grants, everything := wildcardGrants(role.Rules); len(grants) > 0 {
+			severity := "info"
+			switch {
+			case bound && everything:
+				severity = "critical"
+			case bound:
+				severity = "warning"
+			}
+			add(role.Namespace, severity, object, "grants wildcards: "+strings.Join(grants, "; "))
+		}
+		if !bound {
+			add(role.Namespace, "info", object, "is not bound by any RoleBinding")
+			audit.remove = append(audit.remove, rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+				ObjectMeta: metav1.ObjectMeta{Name: role.Name, Namespace: role.Namespace},
+			})
+		}
+	}
+
+	rank := map[string]int{"critical": 0, "warning": 1, "info": 2}
+	sort.SliceStable(audit.findings, func(i, j int) bool {
+		a, b := audit.findings[i], audit.findings[j]
+		if rank[a.severity] != rank[b.severity] {
+			return rank[a.severity] < rank[b.severity]
+		}
+		return a.object < b.object
+	})
+	return audit
+}
+
+// rbacRisk scores each namespace with findings, riskiest first; findings of
+// cluster-scoped objects score under the empty namespace
+func rbacRisk(findings []rbacFinding) []namespaceRisk {
+	byNamespace := make(map[string]*namespaceRisk)
+	for _, finding := range findings {
+		risk := byNamespace[finding.namespace]
+		if risk == nil {
+			risk = &namespaceRisk{namespace: finding.namespace, counts: make(map[string]int)}
+			byNamespace[finding.namespace] = risk
+		}
+		risk.counts[finding.severity]++
+		risk.score += rbacRiskWeights[finding.severity]
+	}
+	risks := make([]namespaceRisk, 0, len(byNamespace))
+	for _, namespace := range sortedKeys(byNamespace) {
+		risk := byNamespace[namespace]
+		if risk.score > 100 {
+			risk.score = 100
+		}
+		risks = append(risks, *risk)
+	}
+	sort.SliceStable(risks, func(i, j int) bool { return risks[i].score > risks[j].score })
+	return risks
+}
+
+// riskLevel names a risk score
+func riskLevel(score int) string {
+	switch {
+	case score >= 50:
+		return "high"
+	case score >= 20:
+		return "medium"
+	}
+	return "low"
+}
+
+// rbacCleanupYAML renders objects as one manifest
+func rbacCleanupYAML(objects []interface{}) (string, error) {
+	var documents []string
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return "", err
+		}
+		// Drop the empty creationTimestamp the API types always marshal
+		documents = append(documents, strings.Replace(string(data), "  creationTimestamp: null\n", "", 1))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
+
+// readRBACInventory reads the RBAC objects of a namespace, or of the
+// cluster, with the namespaces, service accounts, users and groups their
+// bindings name. What cannot be read is returned as skipped checks.
+func (s *Server) readRBACInventory(ctx context.Context, namespace string) (rbacInventory, []string, error) {
+	inv := rbacInventory{clusterWide: namespace == "", namespaces: make(map[string]bool), serviceAccounts: make(map[string]map[string]bool)}
+	var skipped []string
+	rbac := s.k8sClient.RbacV1()
+
+	roles, err := rbac.Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return inv, nil, fmt.Errorf("listing roles: %w", err)
+	}
+	inv.roles = roles.Items
+	bindings, err := rbac.RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return inv, nil, fmt.Errorf("listing role bindings: %w", err)
+	}
+	inv.bindings = bindings.Items
+	if clusterRoles, err := rbac.ClusterRoles().List(ctx, metav1.ListOptions{}); err != nil {
+		if inv.clusterWide {
+			return inv, nil, fmt.Errorf("listing cluster roles: %w", err)
+		}
+		skipped = append(skipped, fmt.Sprintf("bindings of cluster roles that do not exist: %v", err))
+	} else {
+		inv.clusterRoles = clusterRoles.Items
+	}
+
+	// Namespaces and service accounts are listed cluster-wide for a cluster
+	// audit, and looked up one namespace at a time otherwise
+	referenced := make(map[string]bool)
+	addSubjects := func(subjects []rbacv1.Subject, bindingNamespace string) {
+		for _, subject := range subjects {
+			switch {
+			case subject.Kind == rbacv1.ServiceAccountKind:
+				if subject.Namespace != "" {
+					referenced[subject.Namespace] = true
+				} else {
+					referenced[bindingNamespace] = true
+				}
+			case subject.Kind == rbacv1.UserKind && strings.HasPrefix(subject.Name, "system:serviceaccount:"):
+				if parts := strings.Split(strings.TrimPrefix(subject.Name, "system:serviceaccount:"), ":"); len(parts) == 2 {
+					referenced[parts[0]] = true
+				}
+			}
+		}
+	}
+	for _, binding := range inv.bindings {
+		addSubjects(binding.Subjects, binding.Namespace)
+	}
+	if inv.clusterWide {
+		clusterBindings, err := rbac.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return inv, nil, fmt.Errorf("listing cluster role bindings: %w", err)
+		}
+		inv.clusterBindings = clusterBindings.Items
+		namespaces, err := s.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return inv, nil, fmt.Errorf("listing namespaces: %w", err)
+		}
+		for _, ns := range namespaces.Items {
+			inv.namespaces[ns.Name] = true
+			inv.serviceAccounts[ns.Name] = make(map[string]bool)
+		}
+		for _, binding := range inv.clusterBindings {
+			addSubjects(binding.Subjects, "")
+		}
+		for ns := range referenced {
+			if !inv.namespaces[ns] {
+				inv.namespaces[ns] = false
+			}
+		}
+		accounts, err := s.k8sClient.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return inv, nil, fmt.Errorf("listing service accounts: %w", err)
+		}
+		for _, account := range accounts.Items {
+			if inv.serviceAccounts[account.Namespace] != nil {
+				inv.serviceAccounts[account.Namespace][account.Name] = true
+			}
+		}
+	} else {
+		for _, ns := range sortedKeys(referenced) {
+			if _, err := s.k8sClient.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+				inv.namespaces[ns] = false
+				continue
+			} else if err != nil {
+				skipped = append(skipped, fmt.Sprintf("service accounts of namespace %s: %v", ns, err))
+				continue
+			}
+			accounts, err := s.k8sClient.CoreV1().ServiceAccounts(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("service accounts of namespace %s: %v", ns, err))
+				continue
+			}
+			inv.namespaces[ns] = true
+			inv.serviceAccounts[ns] = make(map[string]bool, len(accounts.Items))
+			for _, account := range accounts.Items {
+				inv.serviceAccounts[ns][account.Name] = true
+			}
+		}
+	}
+
+	// Users and groups only exist as objects on OpenShift
+	if s.dynamicClient == nil {
+		skipped = append(skipped, "users and groups: dynamic client not available")
+		return inv, skipped, nil
+	}
+	for _, kind := range []struct {
+		gvr   schema.GroupVersionResource
+		names *map[string]bool
+	}{{usersGVR, &inv.users}, {groupsGVR, &inv.groups}} {
+		list, err := s.dynamicClient.Resource(kind.gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", kind.gvr.Resource, err))
+			continue
+		}
+		names := make(map[string]bool, len(list.Items))
+		for _, item := range list.Items {
+			names[item.GetName()] = true
+		}
+		*kind.names = names
+	}
+	return inv, skipped, nil
+}
+
+func (s *Server) auditRBACHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	if namespace == "all" || namespace == "*" {
+		namespace = metav1.NamespaceAll
+	}
+	includePlatform := mcp.ParseBoolean(request, "include_platform", false) || platformNamespace(namespace)
+
+	inv, skipped, err := s.readRBACInventory(ctx, namespace)
+	if err != nil {
+		return toolError(ctx, "❌ Failed to read RBAC objects", err), nil
+	}
+	audit := auditRBAC(inv, includePlatform)
+
+	response := "🔐 RBAC Hygiene Audit\n"
+	response += "=====================\n\n"
+	if namespace != "" {
+		response += fmt.Sprintf("Scope: namespace %s (cluster role bindings and unused cluster roles are audited cluster-wide only)\n", namespace)
+	} else if includePlatform {
+		response += "Scope: cluster, including platform namespaces and roles\n"
+	} else {
+		response += "Scope: cluster (platform namespaces and platform-managed roles skipped)\n"
+	}
+	response += fmt.Sprintf("Scanned: %d roles, %d role bindings, %d cluster roles, %d cluster role bindings\n",
+		len(inv.roles), len(inv.bindings), len(inv.clusterRoles), len(inv.clusterBindings))
+
+	if len(audit.findings) == 0 {
+		response += "\n✅ No stale bindings, unused roles or wildcard grants found\n"
+	} else {
+		response += "\n📊 Risk by namespace:\n"
+		for _, risk := range rbacRisk(audit.findings) {
+			var counts []string
+			for _, severity := range []string{"critical", "warning", "info"} {
+				if n := risk.counts[severity]; n > 0 {
+					counts = append(counts, fmt.Sprintf("%d %s", n, severity))
+				}
+			}
+			name := risk.namespace
+			if name == "" {
+				name = "(cluster)"
+			}
+			response += fmt.Sprintf("• %s: %d/100 %s risk (%s)\n", name, risk.score, riskLevel(risk.score), strings.Join(counts, ", "))
+		}
+
+		icons := map[string]string{"critical": "🔴", "warning": "🟡", "info": "🔵"}
+		severity := ""
+		for _, finding := range audit.findings {
+			if finding.severity != severity {
+				severity = finding.severity
+				response += fmt.Sprintf("\n%s %s:\n", icons[severity], strings.ToUpper(severity[:1])+severity[1:])
+			}
+			response += fmt.Sprintf("• %s %s\n", finding.object, finding.message)
+		}
+	}
+
+	if len(audit.apply) > 0 {
+		manifest, err := rbacCleanupYAML(audit.apply)
+		if err != nil {
+			return toolError(ctx, "❌ Failed to render the cleanup manifest", err), nil
+		}
+		response += fmt.Sprintf("\n🧹 Bindings with their stale subjects removed; review, then oc apply -f:\n```yaml\n%s```\n", manifest)
+	}
+	if len(audit.remove) > 0 {
+		manifest, err := rbacCleanupYAML(audit.remove)
+		if err != nil {
+			return toolError(ctx, "❌ Failed to render the cleanup manifest", err), nil
+		}
+		response += fmt.Sprintf("\n🗑️ Stale bindings and unused roles; review, then oc delete -f:\n```yaml\n%s```\n", manifest)
+	}
+	if len(audit.findings) > 0 {
+		response += "\nℹ️  Wildcard grants need a person to narrow them to the verbs and resources in use; who_can shows who holds a permission\n"
+	}
+
+	if len(skipped) > 0 {
+		response += fmt.Sprintf("\n⚠️ Not checked (%d):\n", len(skipped))
+		for _, reason := range skipped {
+			response += fmt.Sprintf("• %s\n", reason)
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// AuditRBACHandler is a public wrapper for auditRBACHandler
+func (s *Server) AuditRBACHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.auditRBACHandler(ctx, request)
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAuditRBAC(t *testing.T) {
+	clusterRole := func(name string) rbacv1.RoleRef {
+		return rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name}
+	}
+	role := func(name string) rbacv1.RoleRef {
+		return rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name}
+	}
+	everything := []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}
+	listKinds := map[schema.GroupVersionResource]string{usersGVR: "UserList", groupsGVR: "GroupList"}
+	s := &Server{
+		config: &Config{},
+		k8sClient: kubefake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-monitoring"}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "shop"}},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin", Labels: map[string]string{"kubernetes.io/bootstrapping": "rbac-defaults"}}, Rules: everything},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "ops-all"}, Rules: everything},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "legacy-reader"}, Rules: []rbacv1.PolicyRule{
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			}},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "ops"},
+				RoleRef:    clusterRole("ops-all"),
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.UserKind, Name: "bob"},
+					{Kind: rbacv1.UserKind, Name: "carol"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "runner", Namespace: "gone-ns"},
+				},
+			},
+			&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "shop"}, Rules: []rbacv1.PolicyRule{
+				{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+			}},
+			&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "unused-role", Namespace: "shop"}, Rules: []rbacv1.PolicyRule{
+				{Verbs: []string{"*"}, APIGroups: []string{""}, Resources: []string{"configmaps"}},
+			}},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "shop"},
+				RoleRef:    role("deployer"),
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.ServiceAccountKind, Name: "pipeline"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "old-bot"},
+					{Kind: rbacv1.GroupKind, Name: "qa"},
+				},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "ghost", Namespace: "shop"},
+				RoleRef:    role("removed"),
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "bob"}},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "scraper", Namespace: "openshift-monitoring"},
+				RoleRef:    clusterRole("view"),
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "deleted"}},
+			},
+		),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+			newUnstructured("user.openshift.io/v1", "User", "", "bob"),
+			newUnstructured("user.openshift.io/v1", "Group", "", "admins"),
+		),
+	}
+
+	text := resultText(mustCall(t, s.AuditRBACHandler, accessRequest(map[string]interface{}{})))
+	for _, want := range []string{
+		"Scope: cluster (platform namespaces and platform-managed roles skipped)",
+		"• (cluster): 62/100 high risk (2 critical, 1 warning, 1 info)",
+		"• shop: 26/100 medium risk (2 warning, 3 info)",
+		"• ClusterRole ops-all grants wildcards: verbs [*] on [*] in API groups [*]",
+		"• ClusterRoleBinding ops service account gone-ns/runner: namespace gone-ns no longer exists",
+		"• ClusterRoleBinding ops user carol does not exist",
+		"• RoleBinding shop/ci service account shop/old-bot no longer exists",
+		"• RoleBinding shop/ghost binds Role removed, which does not exist",
+		"• RoleBinding shop/ci group qa has no Group object",
+		"• Role shop/unused-role grants wildcards: verbs [*] on [configmaps] in API groups [\"\"]",
+		"• Role shop/unused-role is not bound by any RoleBinding",
+		"• ClusterRole legacy-reader is not bound by any binding",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("audit_rbac output missing %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"cluster-admin", "openshift-monitoring", "deployer is not bound"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("audit_rbac output has %q:\n%s", unwanted, text)
+		}
+	}
+
+	// The cleaned bindings keep the subjects that exist or cannot be judged
+	apply, remove, ok := strings.Cut(strings.SplitN(text, "🧹", 2)[1], "🗑️")
+	if !ok {
+		t.Fatalf("audit_rbac output has no cleanup manifests:\n%s", text)
+	}
+	for _, want := range []string{"name: ops\n", "name: bob", "name: ci\n  namespace: shop", "name: pipeline", "name: qa"} {
+		if !strings.Contains(apply, want) {
+			t.Errorf("apply manifest missing %q:\n%s", want, apply)
+		}
+	}
+	for _, stale := range []string{"carol", "runner", "old-bot"} {
+		if strings.Contains(apply, stale) {
+			t.Errorf("apply manifest keeps %s:\n%s", stale, apply)
+		}
+	}
+	for _, want := range []string{"kind: RoleBinding\nmetadata:\n  name: ghost", "kind: ClusterRole\nmetadata:\n  name: legacy-reader", "kind: Role\nmetadata:\n  name: unused-role"} {
+		if !strings.Contains(remove, want) {
+			t.Errorf("delete manifest missing %q:\n%s", want, remove)
+		}
+	}
+
+	text = resultText(mustCall(t, s.AuditRBACHandler, accessRequest(map[string]interface{}{"namespace": "shop"})))
+	if !strings.Contains(text, "Scope: namespace shop") || !strings.Contains(text, "shop/old-bot") || strings.Contains(text, "ops-all") || strings.Contains(text, "legacy-reader") {
+		t.Errorf("audit_rbac namespace=shop:\n%s", text)
+	}
+}
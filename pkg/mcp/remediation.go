@@ -0,0 +1,727 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultRemediationTimeout = 180
+	maxRemediationTimeout     = 900
+
+	recipeMissingConfigMapKey = "missing-configmap-key"
+	recipeImageTagTypo        = "image-tag-typo"
+	recipeMemoryLimit         = "memory-limit"
+
+	// maxTagDistance is how many edits a known tag may be away from a tag
+	// that cannot be pulled to be taken for the intended one
+	maxTagDistance = 2
+
+	// Confidence that a recipe's fix resolves the issue, which
+	// auto-remediation compares with its threshold
+	confidenceGivenValue   = 0.95
+	confidenceTagOneEdit   = 0.9
+	confidenceTagTwoEdits  = 0.7
+	confidenceMemoryDouble = 0.85
+	confidenceOptionalKey  = 0.6
+)
+
+// remediationPollInterval is how often a remediation checks the rollout it verifies
+var remediationPollInterval = 2 * time.Second
+
+// remediationRecipes are the curated fixes of remediate_deployment
+var remediationRecipes = map[string]string{
+	recipeMissingConfigMapKey: "adds a key an env variable references to its ConfigMap (value), or marks the reference optional",
+	recipeImageTagTypo:        "points a container that cannot pull its image at the closest tag of the same repository the deployment or its image stream already has, or at value",
+	recipeMemoryLimit:         "raises the memory limit of an OOMKilled container to value, or doubles it",
+}
+
+// failingWaitReasons are the waiting reasons of a pod of a new revision
+// that fail a remediation without waiting for the timeout
+var failingWaitReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+}
+
+// remediationPlan is the fix of one detected issue. Template is the pod
+// template to roll out and ConfigMap the ConfigMap to update; Blocked says
+// why the fix needs a value to be applied
+type remediationPlan struct {
+	Recipe    string
+	Container string
+	Problem   string
+	Fix       string
+	Blocked   string
+	// Confidence is how likely the fix resolves the issue, from 0 to 1
+	Confidence float64
+	Template   *corev1.PodTemplateSpec
+	ConfigMap  *corev1.ConfigMap
+	// previous is the ConfigMap before the fix, restored on rollback
+	previous *corev1.ConfigMap
+}
+
+// remediationOutcome is how applying a remediation plan ended
+type remediationOutcome string
+
+const (
+	remediationVerified   remediationOutcome = "verified"
+	remediationRolledBack remediationOutcome = "rolled back"
+	remediationFailed     remediationOutcome = "failed"
+)
+
+func (s *Server) initRemediation() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("remediate_deployment",
+			mcp.WithDescription("Detect issues of a deployment covered by a remediation recipe (missing-configmap-key, image-tag-typo, memory-limit), apply the fix, verify that the rollout completes with every replica available, and roll the fix back automatically when verification fails or times out"),
+			mcp.WithString("deployment_name", mcp.Description("Name of the deployment"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the deployment"), mcp.Required()),
+			mcp.WithString("recipe", mcp.Description("Only apply this recipe: missing-configmap-key, image-tag-typo or memory-limit (default: the first detected issue)")),
+			mcp.WithString("value", mcp.Description("Value of the fix: the ConfigMap key's value, the image tag or image, or the memory limit such as 1Gi; requires recipe")),
+			mcp.WithString("timeout_seconds", mcp.Description(fmt.Sprintf("Seconds to wait for the rollout before rolling back (default %d, max %d)", defaultRemediationTimeout, maxRemediationTimeout))),
+			mcp.WithString("dry_run", mcp.Description("Only report the detected issues and the fix that would be applied (true/false)")),
+			mcp.WithTitleAnnotation("Remediate: Deployment"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.remediateDeploymentHandler)},
+	}
+}
+
+// deploymentPods lists the pods matching a deployment's selector
+func (s *Server) deploymentPods(ctx context.Context, deployment *appsv1.Deployment) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on deployment %s: %v", deployment.Name, err)
+	}
+	pods, err := s.k8sClient.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// planRemediations detects the issues of a deployment the recipes fix, or
+// those of one recipe
+func (s *Server) planRemediations(ctx context.Context, deployment *appsv1.Deployment, pods []corev1.Pod, recipe, value string) ([]remediationPlan, error) {
+	var plans []remediationPlan
+	if recipe == "" || recipe == recipeMissingConfigMapKey {
+		found, err := s.planConfigMapKeyFixes(ctx, deployment, value)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, found...)
+	}
+	if recipe == "" || recipe == recipeImageTagTypo {
+		found, err := s.planImageTagFixes(ctx, deployment, pods, value)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, found...)
+	}
+	if recipe == "" || recipe == recipeMemoryLimit {
+		found, err := planMemoryLimitFixes(deployment, pods, value)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, found...)
+	}
+	return plans, nil
+}
+
+// planConfigMapKeyFixes finds env variables referencing a key that their
+// ConfigMap does not have; references to missing ConfigMaps are left alone
+func (s *Server) planConfigMapKeyFixes(ctx context.Context, deployment *appsv1.Deployment, value string) ([]remediationPlan, error) {
+	configMaps := make(map[string]*corev1.ConfigMap)
+	var plans []remediationPlan
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		for j, env := range container.Env {
+			if env.ValueFrom == nil || env.ValueFrom.ConfigMapKeyRef == nil {
+				continue
+			}
+			ref := env.ValueFrom.ConfigMapKeyRef
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+			configMap, cached := configMaps[ref.Name]
+			if !cached {
+				var err error
+				configMap, err = s.k8sClient.CoreV1().ConfigMaps(deployment.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					configMap = nil
+				} else if err != nil {
+					return nil, fmt.Errorf("failed to get ConfigMap %s: %v", ref.Name, err)
+				}
+				configMaps[ref.Name] = configMap
+			}
+			if configMap == nil {
+				continue
+			}
+			if _, ok := configMap.Data[ref.Key]; ok {
+				continue
+			}
+			if _, ok := configMap.BinaryData[ref.Key]; ok {
+				continue
+			}
+
+			plan := remediationPlan{
+				Recipe:    recipeMissingConfigMapKey,
+				Container: container.Name,
+				Problem:   fmt.Sprintf("env %s references key %s, which ConfigMap %s does not have", env.Name, ref.Key, ref.Name),
+			}
+			if value != "" {
+				updated := configMap.DeepCopy()
+				if updated.Data == nil {
+					updated.Data = make(map[string]string)
+				}
+				updated.Data[ref.Key] = value
+				plan.ConfigMap, plan.previous = updated, configMap
+				plan.Confidence = confidenceGivenValue
+				plan.Fix = fmt.Sprintf("add key %s to ConfigMap %s", ref.Key, ref.Name)
+			} else {
+				template := deployment.Spec.Template.DeepCopy()
+				optional := true
+				template.Spec.Containers[i].Env[j].ValueFrom.ConfigMapKeyRef.Optional = &optional
+				plan.Template = template
+				plan.Confidence = confidenceOptionalKey
+				plan.Fix = fmt.Sprintf("mark the reference of env %s to ConfigMap %s optional, leaving it unset", env.Name, ref.Name)
+			}
+			plans = append(plans, plan)
+		}
+	}
+	return plans, nil
+}
+
+// imagePullFailures maps the containers whose image cannot be pulled to the
+// kubelet's message
+func imagePullFailures(pods []corev1.Pod) map[string]string {
+	failures := make(map[string]string)
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if waiting := status.State.Waiting; waiting != nil && (waiting.Reason == "ErrImagePull" || waiting.Reason == "ImagePullBackOff") {
+				failures[status.Name] = waiting.Reason
+				if waiting.Message != "" {
+					failures[status.Name] = waiting.Message
+				}
+			}
+		}
+	}
+	return failures
+}
+
+// imageTagOf returns the tag of an image reference, empty for digests
+func imageTagOf(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
+
+// tagDistance is the Levenshtein distance between two tags
+func tagDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// closestTag picks the known tag fewest edits away from tag, preferring the
+// first in sort order on ties
+func closestTag(tag string, known []string) string {
+	sort.Strings(known)
+	best, bestDistance := "", maxTagDistance+1
+	for _, candidate := range known {
+		if candidate == tag {
+			continue
+		}
+		if distance := tagDistance(tag, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// knownTags collects the tags of a repository that the deployment's earlier
+// revisions ran or that its image stream has
+func (s *Server) knownTags(ctx context.Context, deployment *appsv1.Deployment, image string) ([]string, error) {
+	repository := imageRepository(image)
+	seen := make(map[string]bool)
+	history, err := s.revisionHistory(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range history {
+		for _, revisionImage := range revision.Images {
+			if imageRepository(revisionImage) == repository && revisionImage != image {
+				if tag := imageTagOf(revisionImage); tag != "" {
+					seen[tag] = true
+				}
+			}
+		}
+	}
+	if ref, internal := parseInternalImage(image); internal && s.dynamicClient != nil {
+		stream, err := s.dynamicClient.Resource(imageStreamsGVR).Namespace(ref.Namespace).Get(ctx, ref.Stream, metav1.GetOptions{})
+		if err == nil {
+			for _, tag := range imageStreamTags(stream) {
+				if len(tag.Items) > 0 {
+					seen[tag.Tag] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+// planImageTagFixes finds containers that cannot pull their image and the
+// tag they were most likely meant to use
+func (s *Server) planImageTagFixes(ctx context.Context, deployment *appsv1.Deployment, pods []corev1.Pod, value string) ([]remediationPlan, error) {
+	failures := imagePullFailures(pods)
+	var plans []remediationPlan
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		message, failing := failures[container.Name]
+		if !failing {
+			continue
+		}
+		plan := remediationPlan{
+			Recipe:    recipeImageTagTypo,
+			Container: container.Name,
+			Problem:   fmt.Sprintf("image %s cannot be pulled: %s", container.Image, message),
+		}
+
+		var image string
+		tag := imageTagOf(container.Image)
+		switch {
+		case value != "" && strings.ContainsAny(value, "/:@"):
+			image, plan.Confidence = value, confidenceGivenValue
+		case value != "":
+			image, plan.Confidence = imageRepository(container.Image)+":"+value, confidenceGivenValue
+		case tag == "":
+			plan.Blocked = "the image is pinned by digest; pass value with the intended image"
+		default:
+			known, err := s.knownTags(ctx, deployment, container.Image)
+			if err != nil {
+				return nil, err
+			}
+			if closest := closestTag(tag, known); closest != "" {
+				image, plan.Confidence = imageRepository(container.Image)+":"+closest, confidenceTagOneEdit
+				if tagDistance(tag, closest) > 1 {
+					plan.Confidence = confidenceTagTwoEdits
+				}
+			} else {
+				plan.Blocked = fmt.Sprintf("no known tag of %s is within %d edits of %s; pass value with the intended tag", imageRepository(container.Image), maxTagDistance, tag)
+			}
+		}
+		if image != "" {
+			template := deployment.Spec.Template.DeepCopy()
+			template.Spec.Containers[i].Image = image
+			plan.Template = template
+			plan.Fix = fmt.Sprintf("set the image of container %s to %s", container.Name, image)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// containerOOMKilled tells whether the OOM killer terminated a container
+// now or on its last run
+func containerOOMKilled(status *corev1.ContainerStatus) bool {
+	for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+		if terminated != nil && terminated.Reason == "OOMKilled" {
+			return true
+		}
+	}
+	return false
+}
+
+// oomKilledContainers returns the containers of pods the OOM killer terminated
+func oomKilledContainers(pods []corev1.Pod) map[string]bool {
+	killed := make(map[string]bool)
+	for _, pod := range pods {
+		for i := range pod.Status.ContainerStatuses {
+			if containerOOMKilled(&pod.Status.ContainerStatuses[i]) {
+				killed[pod.Status.ContainerStatuses[i].Name] = true
+			}
+		}
+	}
+	return killed
+}
+
+// planMemoryLimitFixes raises the memory limit of OOMKilled containers
+func planMemoryLimitFixes(deployment *appsv1.Deployment, pods []corev1.Pod, value string) ([]remediationPlan, error) {
+	var requested *resource.Quantity
+	if value != "" {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory limit %q: %v", value, err)
+		}
+		requested = &quantity
+	}
+
+	killed := oomKilledContainers(pods)
+	var plans []remediationPlan
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		if !killed[container.Name] {
+			continue
+		}
+		current, limited := container.Resources.Limits[corev1.ResourceMemory]
+		plan := remediationPlan{Recipe: recipeMemoryLimit, Container: container.Name}
+		if limited {
+			plan.Problem = fmt.Sprintf("container %s was OOMKilled at its %s memory limit", container.Name, current.String())
+		} else {
+			plan.Problem = fmt.Sprintf("container %s was OOMKilled without a memory limit, under node memory pressure", container.Name)
+		}
+
+		var limit resource.Quantity
+		switch {
+		case requested != nil && limited && requested.Cmp(current) <= 0:
+			plan.Blocked = fmt.Sprintf("%s does not raise the current limit %s", requested.String(), current.String())
+		case requested != nil:
+			limit, plan.Confidence = *requested, confidenceGivenValue
+		case limited:
+			limit, plan.Confidence = *resource.NewQuantity(current.Value()*2, current.Format), confidenceMemoryDouble
+		default:
+			plan.Blocked = "pass value with the memory limit to set"
+		}
+		if plan.Blocked == "" {
+			template := deployment.Spec.Template.DeepCopy()
+			resources := &template.Spec.Containers[i].Resources
+			if resources.Limits == nil {
+				resources.Limits = corev1.ResourceList{}
+			}
+			resources.Limits[corev1.ResourceMemory] = limit
+			plan.Template = template
+			if limited {
+				plan.Fix = fmt.Sprintf("raise the memory limit of container %s from %s to %s", container.Name, current.String(), limit.String())
+			} else {
+				plan.Fix = fmt.Sprintf("set the memory limit of container %s to %s", container.Name, limit.String())
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// podTemplateHashes returns the pod-template-hash labels of pods, which
+// tells the pods of the revision rolled out by a fix from the earlier ones
+func podTemplateHashes(pods []corev1.Pod) map[string]bool {
+	hashes := make(map[string]bool)
+	for _, pod := range pods {
+		hashes[pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]] = true
+	}
+	return hashes
+}
+
+// rolloutFailure reports a pod of a new revision that cannot start
+func rolloutFailure(pods []corev1.Pod, known map[string]bool) string {
+	for _, pod := range pods {
+		if known[pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]] {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if waiting := status.State.Waiting; waiting != nil && failingWaitReasons[waiting.Reason] {
+				return fmt.Sprintf("container %s of new pod %s is in %s: %s", status.Name, pod.Name, waiting.Reason, waiting.Message)
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+				return fmt.Sprintf("container %s of new pod %s was OOMKilled", status.Name, pod.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// rolloutPending says what a deployment still waits for before generation
+// is rolled out, as oc rollout status does, or returns "" when it is done
+func rolloutPending(deployment *appsv1.Deployment, generation int64) string {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	switch {
+	case status.ObservedGeneration < generation:
+		return "the deployment controller has not observed the change"
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas)
+	case status.Replicas > status.UpdatedReplicas:
+		return fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < replicas:
+		return fmt.Sprintf("%d of %d updated replicas available", status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+// verifyRollout waits until a deployment has rolled out generation with
+// every replica available, failing early when the rollout exceeds its
+// progress deadline or a pod of the new revision cannot start
+func (s *Server) verifyRollout(ctx context.Context, namespace, name string, generation int64, known map[string]bool) error {
+	for {
+		deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		pending := rolloutPending(deployment, generation)
+		if pending == "" {
+			return nil
+		}
+		if deployment.Status.ObservedGeneration >= generation {
+			for _, condition := range deployment.Status.Conditions {
+				if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+					return fmt.Errorf("rollout exceeded its progress deadline: %s", condition.Message)
+				}
+			}
+		}
+		pods, err := s.deploymentPods(ctx, deployment)
+		if err != nil {
+			return err
+		}
+		if failure := rolloutFailure(pods, known); failure != "" {
+			return errors.New(failure)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not rolled out in time: %s", pending)
+		case <-time.After(remediationPollInterval):
+		}
+	}
+}
+
+// revertRemediation restores the pod template and ConfigMap data from before a fix
+func (s *Server) revertRemediation(ctx context.Context, namespace, name string, plan remediationPlan, template *corev1.PodTemplateSpec) error {
+	if plan.ConfigMap != nil {
+		configMap, err := s.k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, plan.ConfigMap.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get ConfigMap %s: %v", plan.ConfigMap.Name, err)
+		}
+		configMap.Data, configMap.BinaryData = plan.previous.Data, plan.previous.BinaryData
+		if _, err := s.k8sClient.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to restore ConfigMap %s: %v", plan.ConfigMap.Name, err)
+		}
+	}
+	if plan.Template != nil {
+		deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s: %v", name, err)
+		}
+		deployment.Spec.Template = *template
+		if deployment.Annotations == nil {
+			deployment.Annotations = make(map[string]string)
+		}
+		deployment.Annotations[changeCauseAnnotation] = fmt.Sprintf("rollback of remediation recipe %s", plan.Recipe)
+		if _, err := s.k8sClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to restore the pod template of deployment %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// remediationHint points a diagnosis at remediate_deployment when a
+// container of a deployment's pod shows an issue a recipe may fix
+func remediationHint(pod *corev1.Pod, status *corev1.ContainerStatus) string {
+	parts := strings.SplitN(podWorkload(pod), "/", 3)
+	if len(parts) != 3 || parts[1] != "Deployment" {
+		return ""
+	}
+	var recipe string
+	switch {
+	case status.State.Waiting != nil && (status.State.Waiting.Reason == "ErrImagePull" || status.State.Waiting.Reason == "ImagePullBackOff"):
+		recipe = recipeImageTagTypo
+	case status.State.Waiting != nil && status.State.Waiting.Reason == "CreateContainerConfigError":
+		recipe = recipeMissingConfigMapKey
+	case containerOOMKilled(status):
+		recipe = recipeMemoryLimit
+	default:
+		return ""
+	}
+	return fmt.Sprintf("   🩹 Remediation: remediate_deployment deployment_name=%s namespace=%s recipe=%s dry_run=true previews a fix that is rolled back if the rollout fails\n",
+		parts[2], pod.Namespace, recipe)
+}
+
+func formatRemediationPlans(plans []remediationPlan) string {
+	result := fmt.Sprintf("🔍 Detected Issues (%d):\n", len(plans))
+	for _, plan := range plans {
+		result += fmt.Sprintf("• [%s] %s\n", plan.Recipe, plan.Problem)
+		if plan.Blocked != "" {
+			result += fmt.Sprintf("   ⛔ Cannot fix: %s\n", plan.Blocked)
+		} else {
+			result += fmt.Sprintf("   🔧 Fix (%.0f%% confidence): %s\n", plan.Confidence*100, plan.Fix)
+		}
+	}
+	return result
+}
+
+func (s *Server) remediateDeploymentHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	deploymentName := mcp.ParseString(request, "deployment_name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	recipe := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "recipe", "")))
+	value := strings.TrimSpace(mcp.ParseString(request, "value", ""))
+	if recipe != "" && remediationRecipes[recipe] == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Unknown recipe %q; use one of %s", recipe, strings.Join(sortedKeys(remediationRecipes), ", "))), nil
+	}
+	if value != "" && recipe == "" {
+		return mcp.NewToolResultText("❌ value requires recipe, since it means something else to each recipe"), nil
+	}
+	timeoutValue := mcp.ParseString(request, "timeout_seconds", strconv.Itoa(defaultRemediationTimeout))
+	timeout, err := strconv.Atoi(timeoutValue)
+	if err != nil || timeout <= 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid timeout_seconds value: %s", timeoutValue)), nil
+	}
+	if timeout > maxRemediationTimeout {
+		timeout = maxRemediationTimeout
+	}
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+
+	deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get deployment %s", deploymentName), err), nil
+	}
+	if deployment.Spec.Paused {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Deployment %s is paused; resume it before remediating, as the fix could not be verified", deploymentName)), nil
+	}
+	pods, err := s.deploymentPods(ctx, deployment)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list the pods of deployment %s", deploymentName), err), nil
+	}
+	plans, err := s.planRemediations(ctx, deployment, pods, recipe, value)
+	if err != nil {
+		return toolError(ctx, "Failed to detect remediable issues", err), nil
+	}
+
+	result := "🩹 Remediate Deployment\n"
+	result += "=======================\n\n"
+	result += fmt.Sprintf("Deployment: %s\n", deploymentName)
+	result += fmt.Sprintf("Namespace: %s\n\n", namespace)
+
+	if len(plans) == 0 {
+		result += "✅ No issue covered by a remediation recipe was detected. Recipes:\n"
+		for _, name := range sortedKeys(remediationRecipes) {
+			result += fmt.Sprintf("• %s: %s\n", name, remediationRecipes[name])
+		}
+		return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+	}
+	result += formatRemediationPlans(plans)
+
+	var plan *remediationPlan
+	for i := range plans {
+		if plans[i].Blocked == "" {
+			plan = &plans[i]
+			break
+		}
+	}
+	if plan == nil {
+		result += "\n❌ No detected issue can be fixed without more input; nothing was changed"
+		return mcp.NewToolResultText(result), nil
+	}
+	if dryRun {
+		result += fmt.Sprintf("\n🧪 Dry run: would %s, wait up to %ds for the rollout and roll back if it fails; nothing was changed", plan.Fix, timeout)
+		return mcp.NewToolResultText(result), nil
+	}
+
+	report, outcome, err := s.applyRemediation(ctx, "remediate_deployment", deployment, podTemplateHashes(pods), plan, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return toolError(ctx, "Failed to apply the remediation", err), nil
+	}
+	result += "\n" + report
+	if remaining := len(plans) - 1; outcome == remediationVerified && remaining > 0 {
+		result += fmt.Sprintf("\n💡 %d more issue(s) detected; run remediate_deployment again to fix the next one", remaining)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// applyRemediation applies a plan to a deployment, verifies the rollout
+// within timeout and reverts the fix when verification fails. known holds
+// the pod-template-hash labels of the pods before the fix; err is only set
+// when the fix could not be applied at all
+func (s *Server) applyRemediation(ctx context.Context, tool string, deployment *appsv1.Deployment, known map[string]bool, plan *remediationPlan, timeout time.Duration) (string, remediationOutcome, error) {
+	namespace, name := deployment.Namespace, deployment.Name
+	// Remember what the fix changes so that a failed verification restores it
+	previousTemplate := deployment.Spec.Template.DeepCopy()
+	generation := deployment.Generation
+	reference := objectReference("apps/v1", "Deployment", deployment)
+
+	if plan.ConfigMap != nil {
+		if _, err := s.k8sClient.CoreV1().ConfigMaps(namespace).Update(ctx, plan.ConfigMap, metav1.UpdateOptions{}); err != nil {
+			return "", remediationFailed, fmt.Errorf("failed to update ConfigMap %s: %w", plan.ConfigMap.Name, err)
+		}
+	}
+	if plan.Template != nil {
+		updated := deployment.DeepCopy()
+		updated.Spec.Template = *plan.Template
+		if updated.Annotations == nil {
+			updated.Annotations = make(map[string]string)
+		}
+		updated.Annotations[changeCauseAnnotation] = fmt.Sprintf("remediation recipe %s: %s", plan.Recipe, plan.Fix)
+		updated, err := s.k8sClient.AppsV1().Deployments(namespace).Update(ctx, updated, metav1.UpdateOptions{})
+		if err != nil {
+			return "", remediationFailed, fmt.Errorf("failed to update deployment %s: %w", name, err)
+		}
+		generation = updated.Generation
+	}
+	s.recordAction(ctx, tool, reference, ReasonPatched,
+		fmt.Sprintf("Applied remediation recipe %s: %s", plan.Recipe, plan.Fix))
+	result := fmt.Sprintf("🚀 Applied: %s\n", plan.Fix)
+	result += fmt.Sprintf("⏳ Verifying the rollout for up to %s...\n", timeout)
+
+	verifyCtx, cancel := context.WithTimeout(ctx, timeout)
+	verifyErr := s.verifyRollout(verifyCtx, namespace, name, generation, known)
+	cancel()
+	if verifyErr == nil {
+		result += "✅ Verified: the rollout completed and every replica is available"
+		return result, remediationVerified, nil
+	}
+
+	result += fmt.Sprintf("❌ Verification failed: %v\n", verifyErr)
+	if err := s.revertRemediation(ctx, namespace, name, *plan, previousTemplate); err != nil {
+		recordToolError(ctx, err)
+		result += fmt.Sprintf("🚨 Automatic rollback failed: %v\n", err)
+		result += "💡 Restore the deployment manually, e.g. with rollback_deployment"
+		return result, remediationFailed, nil
+	}
+	s.recordAction(ctx, tool, reference, ReasonRolledBack,
+		fmt.Sprintf("Rolled back remediation recipe %s after failed verification: %v", plan.Recipe, verifyErr))
+	restored := "the deployment"
+	if plan.ConfigMap != nil {
+		restored = "ConfigMap " + plan.ConfigMap.Name
+	}
+	result += fmt.Sprintf("⏪ Rolled back: %s is back to the state before the fix", restored)
+	return result, remediationRolledBack, nil
+}
+
+// RemediateDeploymentHandler is a public wrapper for remediateDeploymentHandler
+func (s *Server) RemediateDeploymentHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.remediateDeploymentHandler(ctx, request)
+}
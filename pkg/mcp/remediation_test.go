@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+// remediationObjects returns deployment api in shop with one pod whose
+// container reports status, and a ReplicaSet of an earlier revision
+func remediationObjects(container corev1.Container, status corev1.ContainerStatus, available int32) []runtime.Object {
+	replicas := int32(1)
+	isController := true
+	labels := map[string]string{"app": "api"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", UID: types.UID("uid-api"), Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{container}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: available},
+	}
+	status.Name = container.Name
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "api-6d4f-x1", Namespace: "shop",
+			Labels: map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: "6d4f"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-6d4f", Controller: &isController,
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+	previous := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "api-5c3e", Namespace: "shop", Labels: labels,
+			Annotations: map[string]string{revisionAnnotation: "1"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "api", UID: deployment.UID, Controller: &isController,
+			}},
+		},
+		Spec: appsv1.ReplicaSetSpec{Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: container.Name, Image: "quay.io/shop/api:v1.4.0"}}},
+		}},
+	}
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-config", Namespace: "shop"},
+		Data:       map[string]string{"LOG_LEVEL": "info"},
+	}
+	return []runtime.Object{deployment, pod, previous, config}
+}
+
+func waitingStatus(reason string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}
+}
+
+func TestClosestTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		known    []string
+		expected string
+	}{
+		{"v1.4.O", []string{"v1.3.0", "v1.4.0", "latest"}, "v1.4.0"},
+		{"v1.5", []string{"v1.4", "v1.6"}, "v1.4"},
+		{"lates", []string{"latest"}, "latest"},
+		{"v3", []string{"v1.4.0"}, ""},
+		{"v1.4.0", []string{"v1.4.0"}, ""},
+	}
+	for _, tt := range tests {
+		if closest := closestTag(tt.tag, tt.known); closest != tt.expected {
+			t.Errorf("closestTag(%q, %v) = %q, expected %q", tt.tag, tt.known, closest, tt.expected)
+		}
+	}
+}
+
+func TestRolloutFailure(t *testing.T) {
+	pod := func(hash string, status corev1.ContainerStatus) corev1.Pod {
+		status.Name = "api"
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-" + hash, Labels: map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash}},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+		}
+	}
+	oomKilled := corev1.ContainerStatus{LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}}}
+	known := map[string]bool{"old": true}
+
+	if failure := rolloutFailure([]corev1.Pod{pod("old", waitingStatus("CrashLoopBackOff"))}, known); failure != "" {
+		t.Errorf("rolloutFailure reported a pod of an earlier revision: %s", failure)
+	}
+	if failure := rolloutFailure([]corev1.Pod{pod("new", waitingStatus("CrashLoopBackOff"))}, known); !strings.Contains(failure, "new pod api-new is in CrashLoopBackOff") {
+		t.Errorf("rolloutFailure for a crashing new pod = %q", failure)
+	}
+	if failure := rolloutFailure([]corev1.Pod{pod("new", oomKilled)}, known); !strings.Contains(failure, "was OOMKilled") {
+		t.Errorf("rolloutFailure for an OOMKilled new pod = %q", failure)
+	}
+	if failure := rolloutFailure([]corev1.Pod{pod("new", waitingStatus("ContainerCreating"))}, known); failure != "" {
+		t.Errorf("rolloutFailure reported a starting pod: %s", failure)
+	}
+}
+
+func TestRemediateDeployment(t *testing.T) {
+	interval := remediationPollInterval
+	remediationPollInterval = 10 * time.Millisecond
+	defer func() { remediationPollInterval = interval }()
+
+	limited := corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.0", Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+	}}
+	configured := corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.0", Env: []corev1.EnvVar{{
+		Name: "API_URL", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "api-config"}, Key: "api.url",
+		}},
+	}}}
+	oomKilled := corev1.ContainerStatus{
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+	}
+
+	tests := []struct {
+		name      string
+		container corev1.Container
+		status    corev1.ContainerStatus
+		available int32
+		arguments map[string]interface{}
+		want      []string
+		check     func(t *testing.T, deployment *appsv1.Deployment, config *corev1.ConfigMap)
+	}{
+		{
+			name:      "image tag typo",
+			container: corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.O"},
+			status:    waitingStatus("ImagePullBackOff"),
+			available: 1,
+			want:      []string{"[image-tag-typo] image quay.io/shop/api:v1.4.O cannot be pulled", "🚀 Applied: set the image of container api to quay.io/shop/api:v1.4.0", "✅ Verified"},
+			check: func(t *testing.T, deployment *appsv1.Deployment, _ *corev1.ConfigMap) {
+				if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "quay.io/shop/api:v1.4.0" {
+					t.Errorf("image after remediation = %s", image)
+				}
+				if cause := deployment.Annotations[changeCauseAnnotation]; !strings.Contains(cause, "remediation recipe image-tag-typo") {
+					t.Errorf("change cause = %q", cause)
+				}
+			},
+		},
+		{
+			name:      "memory limit rolled back",
+			container: limited,
+			status:    oomKilled,
+			arguments: map[string]interface{}{"timeout_seconds": "1"},
+			want: []string{
+				"container api was OOMKilled at its 256Mi memory limit",
+				"🚀 Applied: raise the memory limit of container api from 256Mi to 512Mi",
+				"❌ Verification failed: not rolled out in time: 0 of 1 updated replicas available",
+				"⏪ Rolled back: the deployment is back to the state before the fix",
+			},
+			check: func(t *testing.T, deployment *appsv1.Deployment, _ *corev1.ConfigMap) {
+				if limit := deployment.Spec.Template.Spec.Containers[0].Resources.Limits.Memory(); limit.String() != "256Mi" {
+					t.Errorf("memory limit after rollback = %s, expected 256Mi", limit)
+				}
+			},
+		},
+		{
+			name:      "memory limit value",
+			container: limited,
+			status:    oomKilled,
+			available: 1,
+			arguments: map[string]interface{}{"recipe": "memory-limit", "value": "1Gi"},
+			want:      []string{"from 256Mi to 1Gi", "✅ Verified"},
+		},
+		{
+			name:      "memory limit value too low",
+			container: limited,
+			status:    oomKilled,
+			available: 1,
+			arguments: map[string]interface{}{"recipe": "memory-limit", "value": "128Mi"},
+			want:      []string{"⛔ Cannot fix: 128Mi does not raise the current limit 256Mi", "nothing was changed"},
+		},
+		{
+			name:      "missing ConfigMap key with value",
+			container: configured,
+			status:    waitingStatus("CreateContainerConfigError"),
+			available: 1,
+			arguments: map[string]interface{}{"recipe": "missing-configmap-key", "value": "http://backend:8080"},
+			want:      []string{"env API_URL references key api.url, which ConfigMap api-config does not have", "🚀 Applied: add key api.url to ConfigMap api-config", "✅ Verified"},
+			check: func(t *testing.T, _ *appsv1.Deployment, config *corev1.ConfigMap) {
+				if config.Data["api.url"] != "http://backend:8080" || config.Data["LOG_LEVEL"] != "info" {
+					t.Errorf("ConfigMap data after remediation = %v", config.Data)
+				}
+			},
+		},
+		{
+			name:      "missing ConfigMap key rolled back",
+			container: configured,
+			status:    waitingStatus("CreateContainerConfigError"),
+			arguments: map[string]interface{}{"recipe": "missing-configmap-key", "value": "http://backend:8080", "timeout_seconds": "1"},
+			want:      []string{"⏪ Rolled back: ConfigMap api-config is back to the state before the fix"},
+			check: func(t *testing.T, _ *appsv1.Deployment, config *corev1.ConfigMap) {
+				if _, ok := config.Data["api.url"]; ok {
+					t.Errorf("rollback kept the added key: %v", config.Data)
+				}
+			},
+		},
+		{
+			name:      "missing ConfigMap key optional",
+			container: configured,
+			status:    waitingStatus("CreateContainerConfigError"),
+			available: 1,
+			want:      []string{"mark the reference of env API_URL to ConfigMap api-config optional", "✅ Verified"},
+			check: func(t *testing.T, deployment *appsv1.Deployment, _ *corev1.ConfigMap) {
+				ref := deployment.Spec.Template.Spec.Containers[0].Env[0].ValueFrom.ConfigMapKeyRef
+				if ref.Optional == nil || !*ref.Optional {
+					t.Errorf("ConfigMap reference after remediation = %+v", ref)
+				}
+			},
+		},
+		{
+			name:      "dry run",
+			container: corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.O"},
+			status:    waitingStatus("ErrImagePull"),
+			arguments: map[string]interface{}{"dry_run": "true"},
+			want:      []string{"🧪 Dry run: would set the image of container api to quay.io/shop/api:v1.4.0", "nothing was changed"},
+			check: func(t *testing.T, deployment *appsv1.Deployment, _ *corev1.ConfigMap) {
+				if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "quay.io/shop/api:v1.4.O" {
+					t.Errorf("dry run changed the image to %s", image)
+				}
+			},
+		},
+		{
+			name:      "no known tag",
+			container: corev1.Container{Name: "api", Image: "quay.io/shop/api:v9"},
+			status:    waitingStatus("ErrImagePull"),
+			want:      []string{"no known tag of quay.io/shop/api is within 2 edits of v9; pass value with the intended tag"},
+		},
+		{
+			name:      "healthy",
+			container: limited,
+			status:    corev1.ContainerStatus{Ready: true},
+			available: 1,
+			want:      []string{"✅ No issue covered by a remediation recipe was detected", "• memory-limit:"},
+		},
+	}
+
+	for _, tt := range tests {
+		s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(remediationObjects(tt.container, tt.status, tt.available)...)}
+		request := mcp.CallToolRequest{}
+		arguments := map[string]interface{}{"deployment_name": "api", "namespace": "shop"}
+		for key, value := range tt.arguments {
+			arguments[key] = value
+		}
+		request.Params.Arguments = arguments
+		result, err := s.RemediateDeploymentHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("%s: RemediateDeploymentHandler error = %v", tt.name, err)
+		}
+		text := resultText(result)
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s: RemediateDeploymentHandler output missing %q:\n%s", tt.name, want, text)
+			}
+		}
+		if tt.check != nil {
+			deployment, _ := s.k8sClient.AppsV1().Deployments("shop").Get(context.Background(), "api", metav1.GetOptions{})
+			config, _ := s.k8sClient.CoreV1().ConfigMaps("shop").Get(context.Background(), "api-config", metav1.GetOptions{})
+			tt.check(t, deployment, config)
+		}
+	}
+
+	// value means something else to each recipe, so it needs one
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset()}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"deployment_name": "api", "namespace": "shop", "value": "1Gi"}
+	result, _ := s.RemediateDeploymentHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "value requires recipe") {
+		t.Errorf("value without recipe = %q", text)
+	}
+}
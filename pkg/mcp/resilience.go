@@ -0,0 +1,596 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Dependencies guarded by circuit breakers
+const (
+	DependencyCluster = "cluster"
+	DependencyGit     = "git"
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+const (
+	defaultToolTimeout         = 2 * time.Minute
+	defaultBreakerThreshold    = 5
+	defaultBreakerOpenDuration = 30 * time.Second
+)
+
+// defaultToolTimeouts holds timeouts for tools that are expected to run longer
+// than the default, such as collectors that wait on debug pods.
+var defaultToolTimeouts = map[string]time.Duration{
+	"openshift_must_gather":       30 * time.Minute,
+	"collect_sosreport":           20 * time.Minute,
+	"collect_tcpdump":             10 * time.Minute,
+	"collect_logs":                5 * time.Minute,
+	"collect_ovn_diagnostics":     10 * time.Minute,
+	"collect_ingress_diagnostics": 10 * time.Minute,
+	"collect_storage_diagnostics": 10 * time.Minute,
+	"diagnose_cluster_dns":        5 * time.Minute,
+	"collect_metrics_snapshot":    2 * time.Minute,
+	"collect_alerts":              time.Minute,
+	"image_inventory":             maxSBOMGenerations*sbomGenerateTimeout + time.Minute,
+	"analyze_must_gather":         10 * time.Minute,
+	"analyze_must_gather_diff":    10 * time.Minute,
+	"drain_node":                  30 * time.Minute,
+	"watch_resource":              (maxWatchSeconds + 30) * time.Second,
+	"remediate_deployment":        (maxRemediationTimeout + 60) * time.Second,
+	"approve_remediation":         (maxRemediationTimeout + 60) * time.Second,
+	"auto_remediate":              30 * time.Minute,
+}
+
+// ResilienceConfig holds per-tool timeout and circuit breaker configuration
+type ResilienceConfig struct {
+	DefaultTimeout      string            `json:"default_timeout"`       // e.g. "2m"
+	ToolTimeouts        map[string]string `json:"tool_timeouts"`         // tool name -> duration
+	BreakerThreshold    int               `json:"breaker_threshold"`     // consecutive failures before opening
+	BreakerOpenDuration string            `json:"breaker_open_duration"` // time before a half-open retry
+}
+
+// ErrBreakerOpen is returned when a call is rejected by an open circuit breaker
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker opens after a number of consecutive failures against a
+// dependency and allows a single trial call once the open duration elapses.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	name             string
+	threshold        int
+	openDuration     time.Duration
+	state            string
+	failures         int
+	openedAt         time.Time
+	lastError        string
+	lastFailure      time.Time
+	halfOpenInFlight bool
+}
+
+// BreakerStatus is a point-in-time view of a circuit breaker
+type BreakerStatus struct {
+	Name                string    `json:"name"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Threshold           int       `json:"threshold"`
+	OpenedAt            time.Time `json:"opened_at,omitempty"`
+	RetryAt             time.Time `json:"retry_at,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailure         time.Time `json:"last_failure,omitempty"`
+}
+
+// NewCircuitBreaker creates a new circuit breaker in the closed state
+func NewCircuitBreaker(name string, threshold int, openDuration time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	if openDuration <= 0 {
+		openDuration = defaultBreakerOpenDuration
+	}
+
+	return &CircuitBreaker{
+		name:         name,
+		threshold:    threshold,
+		openDuration: openDuration,
+		state:        BreakerClosed,
+	}
+}
+
+// Allow reports whether a call may proceed. An open breaker moves to half-open
+// once the open duration has elapsed and then admits exactly one trial call.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if remaining := b.openDuration - time.Since(b.openedAt); remaining > 0 {
+			// A wait reads the same in every zone, unlike a clock time
+			return fmt.Errorf("%w for %s (retry in %s)", ErrBreakerOpen, b.name,
+				(remaining + time.Second - 1).Truncate(time.Second))
+		}
+		b.state = BreakerHalfOpen
+		b.halfOpenInFlight = true
+		return nil
+	case BreakerHalfOpen:
+		if b.halfOpenInFlight {
+			return fmt.Errorf("%w for %s (trial call in progress)", ErrBreakerOpen, b.name)
+		}
+		b.halfOpenInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess closes the breaker and resets the failure count
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerClosed {
+		logrus.Infof("Circuit breaker %s closed after successful trial call", b.name)
+	}
+	b.state = BreakerClosed
+	b.failures = 0
+	b.halfOpenInFlight = false
+}
+
+// RecordFailure counts a failure and opens the breaker when the threshold is
+// reached. A failed half-open trial reopens the breaker immediately.
+func (b *CircuitBreaker) RecordFailure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastFailure = time.Now()
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		if b.state != BreakerOpen {
+			logrus.Warnf("Circuit breaker %s opened after %d consecutive failures", b.name, b.failures)
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+	b.halfOpenInFlight = false
+}
+
+// Release frees a half-open trial slot without recording an outcome, used
+// when the caller cancels before the dependency could answer.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.halfOpenInFlight = false
+}
+
+// Status returns a snapshot of the breaker state
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Threshold:           b.threshold,
+		LastError:           b.lastError,
+		LastFailure:         b.lastFailure,
+	}
+	if b.state != BreakerClosed {
+		status.OpenedAt = b.openedAt
+		status.RetryAt = b.openedAt.Add(b.openDuration)
+	}
+	return status
+}
+
+// initResilience builds the circuit breakers and timeout table from config
+func (s *Server) initResilience(config *ResilienceConfig) {
+	if config == nil {
+		config = &ResilienceConfig{}
+	}
+
+	s.defaultTimeout = parseDurationOrDefault(config.DefaultTimeout, defaultToolTimeout)
+
+	s.toolTimeouts = make(map[string]time.Duration)
+	for name, timeout := range defaultToolTimeouts {
+		s.toolTimeouts[name] = timeout
+	}
+	for name, value := range config.ToolTimeouts {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			logrus.Warnf("Ignoring invalid timeout %q for tool %s", value, name)
+			continue
+		}
+		s.toolTimeouts[name] = timeout
+	}
+
+	openDuration := parseDurationOrDefault(config.BreakerOpenDuration, defaultBreakerOpenDuration)
+	s.breakers = map[string]*CircuitBreaker{
+		DependencyCluster: NewCircuitBreaker(DependencyCluster, config.BreakerThreshold, openDuration),
+		DependencyGit:     NewCircuitBreaker(DependencyGit, config.BreakerThreshold, openDuration),
+	}
+}
+
+// parseDurationOrDefault parses a duration string, falling back on empty or invalid input
+func parseDurationOrDefault(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logrus.Warnf("Invalid duration %q, using %s", value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// toolTimeout returns the execution timeout for a tool
+func (s *Server) toolTimeout(name string) time.Duration {
+	if timeout, ok := s.toolTimeouts[name]; ok {
+		return timeout
+	}
+	return s.defaultTimeout
+}
+
+// localTools work on local files or the server's own state and depend on
+// neither the cluster nor Git. Analyzers that query the cluster, such as
+// analyze_node_capacity, are not among them.
+var localTools = map[string]bool{
+	"analyze_ingress_diagnostics": true,
+	"analyze_logs":                true,
+	"analyze_must_gather":         true,
+	"analyze_must_gather_diff":    true,
+	"analyze_ovn_diagnostics":     true,
+	"analyze_storage_diagnostics": true,
+	"analyze_tcpdump":             true,
+	"generate_yaml":               true,
+	"get_kubeconfig":              true,
+	"server_status":               true,
+}
+
+// toolDependency maps a tool to the remote dependency its breaker guards.
+// Tools that only work on local files return an empty string.
+func toolDependency(name string) string {
+	switch {
+	case strings.HasPrefix(name, "git_"), strings.Contains(name, "argocd"):
+		return DependencyGit
+	case localTools[name]:
+		return ""
+	default:
+		return DependencyCluster
+	}
+}
+
+// Outcomes of a tool call as seen by the circuit breaker of its dependency
+const (
+	outcomeHealthy   = iota // the dependency answered, even if with an error
+	outcomeUnhealthy        // the dependency failed or could not be reached
+	outcomeUnknown          // the call never reached the dependency
+)
+
+// transportErrorMarkers identify connectivity failures in errors that have
+// been flattened to text, such as oc/kubectl output
+var transportErrorMarkers = []string{
+	"connection refused",
+	"connection reset",
+	"i/o timeout",
+	"no such host",
+	"no route to host",
+	"tls handshake timeout",
+	"unable to connect to the server",
+	"server is currently unable to handle the request",
+}
+
+// classifyError decides whether an error from a tool means its dependency is
+// unhealthy. Bad input, missing resources, permission problems and disabled
+// features are answers from a healthy dependency or never reached it at all.
+func classifyError(dependency string, err error) int {
+	switch {
+	case err == nil:
+		return outcomeHealthy
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrGitDisabled), errors.Is(err, ErrNoGitRemote):
+		return outcomeUnknown
+	case errors.Is(err, context.DeadlineExceeded):
+		return outcomeUnhealthy
+	}
+
+	if dependency == DependencyGit {
+		var remoteErr *GitRemoteError
+		if errors.As(err, &remoteErr) {
+			return outcomeUnhealthy
+		}
+		return outcomeHealthy
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		if apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsTimeout(err) ||
+			apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsUnexpectedServerError(err) {
+			return outcomeUnhealthy
+		}
+		return outcomeHealthy
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return outcomeUnhealthy
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range transportErrorMarkers {
+		if strings.Contains(message, marker) {
+			return outcomeUnhealthy
+		}
+	}
+	return outcomeHealthy
+}
+
+// toolErrors collects errors that handlers fold into their text output, so
+// withResilience can classify them after the handler returns
+type toolErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+type toolErrorsKey struct{}
+
+// recordToolError notes an error returned by a dependency during a tool call
+func recordToolError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	if recorded, ok := ctx.Value(toolErrorsKey{}).(*toolErrors); ok {
+		recorded.mu.Lock()
+		recorded.errs = append(recorded.errs, err)
+		recorded.mu.Unlock()
+	}
+}
+
+// toolError records err for the circuit breaker and returns an error result
+func toolError(ctx context.Context, message string, err error) *mcp.CallToolResult {
+	recordToolError(ctx, err)
+	return mcp.NewToolResultError(fmt.Sprintf("❌ %s: %v", message, err))
+}
+
+// outcome combines the handler error and any recorded errors into one outcome
+func (t *toolErrors) outcome(dependency string, err error) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	errs := t.errs
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	result := outcomeHealthy
+	if len(errs) > 0 {
+		result = outcomeUnknown
+	}
+	var cause error
+	for _, e := range errs {
+		switch classifyError(dependency, e) {
+		case outcomeUnhealthy:
+			return outcomeUnhealthy, e
+		case outcomeHealthy:
+			result = outcomeHealthy
+		}
+		cause = e
+	}
+	return result, cause
+}
+
+// withResilience wraps a tool handler with its timeout and circuit breaker and
+// resolves the time preferences its output uses
+func (s *Server) withResilience(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, err := s.withRequestTimePreferences(ctx, request)
+		if err != nil {
+			return withErrorKind(mcp.NewToolResultError(fmt.Sprintf("❌ %s: %v", name, err)), ErrorInvalidArgument, err.Error()), nil
+		}
+
+		dependency := toolDependency(name)
+		breaker := s.breakers[dependency]
+		if breaker != nil {
+			if err := breaker.Allow(); err != nil {
+				return withErrorKind(mcp.NewToolResultError(fmt.Sprintf("❌ %s rejected: %v", name, err)), ErrorClusterUnavailable, err.Error()), nil
+			}
+		}
+
+		timeout := s.toolTimeout(name)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		recorded := &toolErrors{}
+		ctx = context.WithValue(ctx, toolErrorsKey{}, recorded)
+
+		type outcome struct {
+			result *mcp.CallToolResult
+			err    error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			result, err := handler(ctx, request)
+			done <- outcome{result: result, err: err}
+		}()
+
+		select {
+		case out := <-done:
+			outcome, cause := recorded.outcome(dependency, out.err)
+			s.internalErrors.record(name, cause)
+			if breaker != nil {
+				switch outcome {
+				case outcomeUnhealthy:
+					breaker.RecordFailure(cause)
+				case outcomeUnknown:
+					breaker.Release()
+				default:
+					breaker.RecordSuccess()
+				}
+			}
+			recorded.mu.Lock()
+			annotateToolError(out.result, recorded.errs)
+			recorded.mu.Unlock()
+			s.offerElevation(name, request, out.result)
+			return out.result, out.err
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				if breaker != nil {
+					breaker.Release()
+				}
+				return nil, ctx.Err()
+			}
+			// Track the handler until it returns so runtime_stats shows
+			// handlers that ignore cancellation
+			abandoned := s.sessions.open(SessionToolCall, name, nil)
+			go func() {
+				<-done
+				abandoned.Close()
+			}()
+			timeoutErr := fmt.Errorf("%s timed out after %s", name, timeout)
+			s.internalErrors.record(name, timeoutErr)
+			if breaker != nil {
+				breaker.RecordFailure(timeoutErr)
+			}
+			return withErrorKind(mcp.NewToolResultError("❌ "+timeoutErr.Error()), ErrorTimeout, timeoutErr.Error()), nil
+		}
+	}
+}
+
+// BreakerStatuses returns the state of all circuit breakers ordered by name
+func (s *Server) BreakerStatuses() []BreakerStatus {
+	statuses := make([]BreakerStatus, 0, len(s.breakers))
+	for _, breaker := range s.breakers {
+		statuses = append(statuses, breaker.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Server status tools
+func (s *Server) initServerTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("server_status",
+			mcp.WithDescription("Report MCP server health including circuit breaker states and tool timeouts"),
+			mcp.WithTitleAnnotation("Server: Status"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.serverStatusHandler)},
+		{Tool: mcp.NewTool("self_diagnose",
+			mcp.WithDescription("Check the MCP server's own health: Kubernetes connection, RBAC permissions of its identity, Git remote, LLM latency, artifact disk usage and recent internal errors"),
+			mcp.WithString("check_llm", mcp.Description("Send a minimal prompt to measure LLM latency (true/false, default true)")),
+			mcp.WithString("check_git", mcp.Description("Contact the Git remote (true/false, default true)")),
+			mcp.WithTitleAnnotation("Server: Self-Diagnose"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.selfDiagnoseHandler)},
+		{Tool: mcp.NewTool("server_capabilities",
+			mcp.WithDescription("Report the server version, profiles, policy mode, registered tools with their read-only and destructive flags, and configured integrations"),
+			mcp.WithString("format", mcp.Description("text (default) or json for programmatic clients")),
+			mcp.WithTitleAnnotation("Server: Capabilities"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.serverCapabilitiesHandler)},
+		{Tool: mcp.NewTool("tool_migration_report",
+			mcp.WithDescription("List deprecated tool names with their replacements, removal versions and how often they were called, or find the deprecated names used in a runbook or client configuration"),
+			mcp.WithString("text", mcp.Description("Runbook, client configuration or plan to check for deprecated tool names")),
+			mcp.WithString("rewrite", mcp.Description("Return the text with deprecated tool names replaced (true/false)")),
+			mcp.WithTitleAnnotation("Server: Tool Migration Report"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.toolMigrationReportHandler)},
+		{Tool: mcp.NewTool("list_tool_sets",
+			mcp.WithDescription("List the tool sets registered at runtime from plugins or runbook definitions, with their source and tools"),
+			mcp.WithTitleAnnotation("Server: List Tool Sets"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listToolSetsHandler)},
+		{Tool: mcp.NewTool("reload_tool_sets",
+			mcp.WithDescription("Re-read the runbook tool set directory: register new and changed tool sets and remove deleted ones without restarting, notifying clients that the tool list changed"),
+			mcp.WithTitleAnnotation("Server: Reload Tool Sets"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.reloadToolSetsHandler)},
+	}
+}
+
+// serverStatusHandler reports circuit breaker states and configured timeouts
+func (s *Server) serverStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	result := "🩺 MCP Server Status\n"
+	result += "====================\n\n"
+
+	result += "🔌 Circuit Breakers:\n"
+	for _, status := range s.BreakerStatuses() {
+		icon := "✅"
+		switch status.State {
+		case BreakerOpen:
+			icon = "🚫"
+		case BreakerHalfOpen:
+			icon = "⚠️ "
+		}
+		result += fmt.Sprintf("%s %s: %s (%d/%d consecutive failures)\n",
+			icon, status.Name, status.State, status.ConsecutiveFailures, status.Threshold)
+		if status.State != BreakerClosed {
+			result += fmt.Sprintf("   Opened: %s, retry at: %s\n",
+				formatTime(ctx, status.OpenedAt), formatTime(ctx, status.RetryAt))
+		}
+		if status.LastError != "" {
+			result += fmt.Sprintf("   Last error (%s): %s\n",
+				formatTime(ctx, status.LastFailure), status.LastError)
+		}
+	}
+
+	result += fmt.Sprintf("\n⏱️  Default tool timeout: %s\n", s.defaultTimeout)
+	if len(s.toolTimeouts) > 0 {
+		names := make([]string, 0, len(s.toolTimeouts))
+		for name := range s.toolTimeouts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result += "⏱️  Tool timeout overrides:\n"
+		for _, name := range names {
+			result += fmt.Sprintf("  • %s: %s\n", name, s.toolTimeouts[name])
+		}
+	}
+
+	if s.scheduler != nil {
+		if jobs := s.scheduler.Jobs(); len(jobs) > 0 {
+			result += "\n🗓️  Scheduled Jobs:\n"
+			result += s.describeLeadership(ctx)
+			for _, job := range jobs {
+				result += fmt.Sprintf("  • %s: every %s, %d runs, %d failures", job.Name, job.Interval, job.Runs, job.Failures)
+				if job.LeaderOnly {
+					result += fmt.Sprintf(", leader-only (%d ticks skipped here)", job.Skipped)
+				}
+				if !job.LastRun.IsZero() {
+					result += fmt.Sprintf(", last run %s", formatTime(ctx, job.LastRun))
+				}
+				result += "\n"
+				if job.LastErr != "" {
+					result += fmt.Sprintf("    Last error: %s\n", job.LastErr)
+				}
+			}
+		}
+	}
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// ServerStatusHandler is a public wrapper for serverStatusHandler
+func (s *Server) ServerStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.serverStatusHandler(ctx, request)
+}
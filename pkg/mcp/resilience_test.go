@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newResilienceTestServer(threshold int) *Server {
+	s := &Server{config: &Config{}}
+	s.initResilience(&ResilienceConfig{BreakerThreshold: threshold, BreakerOpenDuration: "1h"})
+	return s
+}
+
+func callTool(s *Server, name string, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	return s.withResilience(name, handler)(context.Background(), request)
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	breaker := NewCircuitBreaker("cluster", 3, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Allow() before threshold returned %v", err)
+		}
+		breaker.RecordFailure(errors.New("connection refused"))
+	}
+	if state := breaker.Status().State; state != BreakerClosed {
+		t.Errorf("state after 2 failures = %s, expected %s", state, BreakerClosed)
+	}
+
+	breaker.RecordFailure(errors.New("connection refused"))
+	if state := breaker.Status().State; state != BreakerOpen {
+		t.Errorf("state after 3 failures = %s, expected %s", state, BreakerOpen)
+	}
+
+	if err := breaker.Allow(); !errors.Is(err, ErrBreakerOpen) || !strings.Contains(err.Error(), "(retry in 1h0m0s)") {
+		t.Errorf("Allow() on open breaker = %v, expected ErrBreakerOpen with the wait", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRetry(t *testing.T) {
+	breaker := NewCircuitBreaker("git", 1, time.Millisecond)
+	breaker.RecordFailure(errors.New("remote unreachable"))
+
+	time.Sleep(5 * time.Millisecond)
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after open duration returned %v", err)
+	}
+	if state := breaker.Status().State; state != BreakerHalfOpen {
+		t.Errorf("state after cooldown = %s, expected %s", state, BreakerHalfOpen)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Errorf("second Allow() during half-open trial = %v, expected ErrBreakerOpen", err)
+	}
+
+	breaker.RecordSuccess()
+	status := breaker.Status()
+	if status.State != BreakerClosed || status.ConsecutiveFailures != 0 {
+		t.Errorf("after successful trial got state=%s failures=%d, expected closed with 0 failures",
+			status.State, status.ConsecutiveFailures)
+	}
+}
+
+func TestToolDependency(t *testing.T) {
+	testCases := []struct {
+		tool     string
+		expected string
+	}{
+		{"git_push", DependencyGit},
+		{"create_argocd_application", DependencyGit},
+		{"list_pods", DependencyCluster},
+		{"delete_resource", DependencyCluster},
+		{"analyze_logs", ""},
+		{"analyze_must_gather", ""},
+		{"analyze_tcpdump", ""},
+		{"analyze_node_capacity", DependencyCluster},
+		{"analyze_route_sharding", DependencyCluster},
+		{"server_status", ""},
+	}
+
+	for _, tc := range testCases {
+		if result := toolDependency(tc.tool); result != tc.expected {
+			t.Errorf("toolDependency(%q) = %q, expected %q", tc.tool, result, tc.expected)
+		}
+	}
+}
+
+func TestWithResilienceOpensOnDependencyFailures(t *testing.T) {
+	s := newResilienceTestServer(2)
+	unavailable := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return toolError(ctx, "Failed to list pods", fmt.Errorf("Get \"https://api:6443/api/v1/pods\": dial tcp 10.0.0.1:6443: connect: connection refused")), nil
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := callTool(s, "list_pods", unavailable)
+		if err != nil || result == nil || !result.IsError {
+			t.Fatalf("call %d returned result=%v err=%v, expected an error result", i+1, result, err)
+		}
+	}
+	if state := s.breakers[DependencyCluster].Status().State; state != BreakerOpen {
+		t.Fatalf("cluster breaker state = %s, expected %s", state, BreakerOpen)
+	}
+
+	called := false
+	result, _ := callTool(s, "list_pods", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+	if called || result == nil || !result.IsError {
+		t.Errorf("call through open breaker ran handler=%v, expected rejection", called)
+	}
+}
+
+func TestWithResilienceIgnoresNonDependencyFailures(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tool    string
+		handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{"invalid input", "scale_deployment", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("❌ Invalid replicas value: abc"), nil
+		}},
+		{"not found", "get_resource", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return toolError(ctx, "Failed to get deployment", apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web")), nil
+		}},
+		{"git disabled", "git_push", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, ErrGitDisabled
+		}},
+		{"local git failure", "git_commit", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return toolError(ctx, "Failed to commit changes", errors.New("nothing to commit, working tree clean")), nil
+		}},
+	}
+
+	for _, tc := range testCases {
+		s := newResilienceTestServer(1)
+		callTool(s, tc.tool, tc.handler)
+		for _, status := range s.BreakerStatuses() {
+			if status.State != BreakerClosed {
+				t.Errorf("%s: %s breaker state = %s, expected %s", tc.name, status.Name, status.State, BreakerClosed)
+			}
+		}
+	}
+}
+
+func TestWithResilienceCountsGitRemoteFailures(t *testing.T) {
+	s := newResilienceTestServer(1)
+	callTool(s, "git_push", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return toolError(ctx, "Failed to push changes", &GitRemoteError{Op: "push to", Err: errors.New("could not resolve host")}), nil
+	})
+
+	if state := s.breakers[DependencyGit].Status().State; state != BreakerOpen {
+		t.Errorf("git breaker state = %s, expected %s", state, BreakerOpen)
+	}
+	if state := s.breakers[DependencyCluster].Status().State; state != BreakerClosed {
+		t.Errorf("cluster breaker state = %s, expected %s", state, BreakerClosed)
+	}
+}
+
+func TestWithResilienceTimeoutCancelsHandler(t *testing.T) {
+	s := newResilienceTestServer(1)
+	s.toolTimeouts["list_pods"] = 10 * time.Millisecond
+
+	cancelled := make(chan struct{})
+	result, err := callTool(s, "list_pods", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+	if err != nil || result == nil || !result.IsError {
+		t.Fatalf("timed out call returned result=%v err=%v, expected an error result", result, err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("handler context was not cancelled after the timeout")
+	}
+	if state := s.breakers[DependencyCluster].Status().State; state != BreakerOpen {
+		t.Errorf("cluster breaker state after timeout = %s, expected %s", state, BreakerOpen)
+	}
+}
+
+func TestNewServerRegistersProtectedTools(t *testing.T) {
+	s := NewServer(&Config{Profile: "sre"}, "")
+
+	for _, name := range []string{"server_status", "list_pods", "git_push"} {
+		if !s.HasTool(name) {
+			t.Errorf("HasTool(%q) = false, expected true", name)
+		}
+	}
+	if s.HasTool("helm_list") {
+		t.Errorf("HasTool(%q) = true, expected false for the sre profile", "helm_list")
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "server_status"
+	result, err := s.CallTool(context.Background(), request)
+	if err != nil || result == nil || result.IsError {
+		t.Fatalf("CallTool(server_status) returned result=%v err=%v", result, err)
+	}
+
+	request.Params.Name = "helm_list"
+	if _, err := s.CallTool(context.Background(), request); err == nil {
+		t.Errorf("CallTool(helm_list) succeeded, expected an error for a tool outside the profile")
+	}
+}
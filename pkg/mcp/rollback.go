@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// deploymentRevision is one ReplicaSet in a Deployment's rollout history
+type deploymentRevision struct {
+	Revision    int64
+	ReplicaSet  *appsv1.ReplicaSet
+	Images      []string
+	ChangeCause string
+}
+
+// revisionHistory returns the ReplicaSets owned by a deployment, oldest revision first
+func (s *Server) revisionHistory(ctx context.Context, deployment *appsv1.Deployment) ([]deploymentRevision, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on deployment %s: %v", deployment.Name, err)
+	}
+	replicaSets, err := s.k8sClient.AppsV1().ReplicaSets(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	var history []deploymentRevision
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		owner := metav1.GetControllerOf(rs)
+		if owner == nil || owner.UID != deployment.UID {
+			continue
+		}
+		revision, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		history = append(history, deploymentRevision{
+			Revision:    revision,
+			ReplicaSet:  rs,
+			Images:      templateImages(&rs.Spec.Template),
+			ChangeCause: rs.Annotations[changeCauseAnnotation],
+		})
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Revision < history[j].Revision })
+	return history, nil
+}
+
+func templateImages(template *corev1.PodTemplateSpec) []string {
+	var images []string
+	for _, container := range template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+// selectRevision picks the rollback target from the history: "previous" is the
+// newest revision older than the current one
+func selectRevision(history []deploymentRevision, current int64, target string) (*deploymentRevision, error) {
+	target = strings.ToLower(strings.TrimSpace(target))
+	if target == "" || target == "previous" || target == "0" {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Revision < current {
+				return &history[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no previous revision found; revision history may have been pruned by revisionHistoryLimit")
+	}
+
+	revision, err := strconv.ParseInt(target, 10, 64)
+	if err != nil || revision < 0 {
+		return nil, fmt.Errorf("invalid revision %q: use a revision number or \"previous\"", target)
+	}
+	if revision == current {
+		return nil, fmt.Errorf("deployment is already at revision %d", revision)
+	}
+	for i := range history {
+		if history[i].Revision == revision {
+			return &history[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found in the rollout history", revision)
+}
+
+// formatRevisionHistory renders the rollout history, marking the current and target revisions
+func formatRevisionHistory(ctx context.Context, history []deploymentRevision, current, target int64) string {
+	result := "📜 Revision History:\n"
+	for _, revision := range history {
+		marker := "  "
+		switch revision.Revision {
+		case current:
+			marker = "▶️ "
+		case target:
+			marker = "🎯"
+		}
+		result += fmt.Sprintf("%s %d: %s (ReplicaSet %s, %d replicas, created %s)\n",
+			marker, revision.Revision, strings.Join(revision.Images, ", "), revision.ReplicaSet.Name,
+			revision.ReplicaSet.Status.Replicas, formatTime(ctx, revision.ReplicaSet.CreationTimestamp.Time))
+		if revision.ChangeCause != "" {
+			result += fmt.Sprintf("      Change cause: %s\n", revision.ChangeCause)
+		}
+	}
+	return result
+}
+
+func (s *Server) rollbackDeploymentHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	deploymentName := mcp.ParseString(request, "deployment_name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	target := mcp.ParseString(request, "revision", "previous")
+	saveToGit := parseBoolString(mcp.ParseString(request, "save_to_git", "true"))
+
+	deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get deployment %s", deploymentName), err), nil
+	}
+	if deployment.Spec.Paused {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Deployment %s is paused; resume it before rolling back", deploymentName)), nil
+	}
+
+	history, err := s.revisionHistory(ctx, deployment)
+	if err != nil {
+		return toolError(ctx, "Failed to list ReplicaSets", err), nil
+	}
+	current, _ := strconv.ParseInt(deployment.Annotations[revisionAnnotation], 10, 64)
+
+	selected, err := selectRevision(history, current, target)
+	if err != nil {
+		result := fmt.Sprintf("❌ Cannot roll back deployment %s: %v\n\n", deploymentName, err)
+		if len(history) > 0 {
+			result += formatRevisionHistory(ctx, history, current, -1)
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	// Restore the pod template of the target ReplicaSet, as kubectl rollout undo does
+	template := selected.ReplicaSet.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deployment.Spec.Template = *template
+	if deployment.Annotations == nil {
+		deployment.Annotations = make(map[string]string)
+	}
+	deployment.Annotations[changeCauseAnnotation] = fmt.Sprintf("rollback from revision %d to revision %d", current, selected.Revision)
+
+	if _, err := s.k8sClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+		return toolError(ctx, "Failed to roll back deployment", err), nil
+	}
+	s.recordAction(ctx, "rollback_deployment", objectReference("apps/v1", "Deployment", deployment), ReasonRolledBack,
+		fmt.Sprintf("Rolled back from revision %d to revision %d", current, selected.Revision))
+
+	result := "⏪ Rolling Back Deployment\n"
+	result += "=========================\n\n"
+	result += fmt.Sprintf("Deployment: %s\n", deploymentName)
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("From Revision: %d\n", current)
+	result += fmt.Sprintf("To Revision: %d (ReplicaSet %s)\n", selected.Revision, selected.ReplicaSet.Name)
+	result += fmt.Sprintf("Images: %s\n\n", strings.Join(selected.Images, ", "))
+	result += formatRevisionHistory(ctx, history, current, selected.Revision)
+	result += "\n✅ Deployment rollback initiated successfully! The rollout creates a new revision with the restored template."
+
+	// Generate YAML for the rollback action and save to Git
+	if saveToGit && s.gitManager.IsEnabled() {
+		yamlContent, err := s.yamlGenerator.GenerateRollbackActionYAML(deploymentName, namespace, current, selected.Revision, selected.Images)
+		if err != nil {
+			result += fmt.Sprintf("\n⚠️  Failed to generate YAML: %v", err)
+		} else {
+			filename := fmt.Sprintf("rollback-%s", deploymentName)
+			description := fmt.Sprintf("Roll back deployment %s from revision %d to %d", deploymentName, current, selected.Revision)
+			if _, err := s.gitManager.SaveYAMLFile(ctx, filename, yamlContent, "rollback", description); err != nil {
+				result += fmt.Sprintf("\n⚠️  Failed to save to Git: %v", err)
+			} else {
+				result += "\n✅ Rollback action YAML saved to Git repository!"
+			}
+		}
+	}
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// RollbackDeploymentHandler is a public wrapper for rollbackDeploymentHandler
+func (s *Server) RollbackDeploymentHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.rollbackDeploymentHandler(ctx, request)
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func podTemplate(image, hash string) corev1.PodTemplateSpec {
+	labels := map[string]string{"app": "web"}
+	if hash != "" {
+		labels[appsv1.DefaultDeploymentUniqueLabelKey] = hash
+	}
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+	}
+}
+
+// newRolloutObjects returns a deployment at revision 3 with ReplicaSets for revisions 1-3
+func newRolloutObjects() []runtime.Object {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web", Namespace: "shop", UID: types.UID("uid-web"),
+			Annotations: map[string]string{revisionAnnotation: "3"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: podTemplate("web:v3", ""),
+		},
+	}
+	objects := []runtime.Object{deployment}
+	isController := true
+	for i, image := range []string{"web:v1", "web:v2", "web:v3"} {
+		hash := strings.TrimPrefix(image, "web:")
+		objects = append(objects, &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "web-" + hash, Namespace: "shop",
+				Labels:      map[string]string{"app": "web"},
+				Annotations: map[string]string{revisionAnnotation: string(rune('1' + i))},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: deployment.UID, Controller: &isController,
+				}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Template: podTemplate(image, hash)},
+		})
+	}
+	// A ReplicaSet with matching labels but another owner is not part of the history
+	objects = append(objects, &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other", Namespace: "shop", Labels: map[string]string{"app": "web"},
+			Annotations: map[string]string{revisionAnnotation: "2"},
+		},
+		Spec: appsv1.ReplicaSetSpec{Template: podTemplate("other:v1", "other")},
+	})
+	return objects
+}
+
+func TestSelectRevision(t *testing.T) {
+	history := []deploymentRevision{{Revision: 1}, {Revision: 2}, {Revision: 4}}
+
+	tests := []struct {
+		target   string
+		current  int64
+		expected int64
+		valid    bool
+	}{
+		{"previous", 4, 2, true},
+		{"", 4, 2, true},
+		{"0", 4, 2, true},
+		{"1", 4, 1, true},
+		{"4", 4, 0, false},
+		{"3", 4, 0, false},
+		{"previous", 1, 0, false},
+		{"latest", 4, 0, false},
+	}
+
+	for _, tt := range tests {
+		selected, err := selectRevision(history, tt.current, tt.target)
+		if (err == nil) != tt.valid {
+			t.Errorf("selectRevision(%q, %d) error = %v, expected valid %v", tt.target, tt.current, err, tt.valid)
+			continue
+		}
+		if err == nil && selected.Revision != tt.expected {
+			t.Errorf("selectRevision(%q, %d) = %d, expected %d", tt.target, tt.current, selected.Revision, tt.expected)
+		}
+	}
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	repo := t.TempDir()
+	s := &Server{
+		config:        &Config{},
+		k8sClient:     kubefake.NewSimpleClientset(newRolloutObjects()...),
+		gitManager:    NewGitManager(&GitConfig{Enabled: true, RepoPath: repo}),
+		yamlGenerator: NewYAMLGenerator(),
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"deployment_name": "web", "namespace": "shop"}
+	result, err := s.rollbackDeploymentHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("rollbackDeploymentHandler returned error: %v", err)
+	}
+	text := resultText(result)
+	for _, expected := range []string{"From Revision: 3", "To Revision: 2 (ReplicaSet web-v2)", "🎯 2: web:v2", "▶️  3: web:v3", "saved to Git"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("rollback result missing %q:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "other:v1") {
+		t.Errorf("rollback history includes a ReplicaSet owned by another controller:\n%s", text)
+	}
+
+	deployment, _ := s.k8sClient.AppsV1().Deployments("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "web:v2" {
+		t.Errorf("deployment image after rollback = %s, expected web:v2", image)
+	}
+	if _, ok := deployment.Spec.Template.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		t.Errorf("rollback kept the pod-template-hash label: %v", deployment.Spec.Template.Labels)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(repo, "actions", "rollback", "*-rollback-web.yaml"))
+	if len(files) != 1 {
+		t.Fatalf("found %d rollback action files, expected 1", len(files))
+	}
+	content, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(content), "kind: ActionRecord") || !strings.Contains(string(content), "action: rollback") || !strings.Contains(string(content), "toRevision: 2") {
+		t.Errorf("rollback action YAML = %s", content)
+	}
+
+	// Rolling back to the revision the deployment is already at is refused with the history
+	request.Params.Arguments = map[string]interface{}{"deployment_name": "web", "namespace": "shop", "revision": "3", "save_to_git": "false"}
+	result, _ = s.rollbackDeploymentHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "already at revision 3") || !strings.Contains(text, "📜 Revision History") {
+		t.Errorf("rollback to the current revision = %q", text)
+	}
+}
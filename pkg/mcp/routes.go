@@ -0,0 +1,603 @@
+package mcp
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	routeDNSTimeout = 5 * time.Second
+	// routeCertWarning is how close to expiry a route certificate is flagged
+	routeCertWarning = 30 * 24 * time.Hour
+)
+
+var routesGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// lookupHost resolves route hosts; tests replace it
+var lookupHost = net.DefaultResolver.LookupHost
+
+// routeBackend is a Service a Route sends traffic to
+type routeBackend struct {
+	Name   string
+	Weight int64
+}
+
+// routeBackends returns spec.to followed by spec.alternateBackends
+func routeBackends(route *unstructured.Unstructured) []routeBackend {
+	var backends []routeBackend
+	add := func(target map[string]interface{}) {
+		backend := routeBackend{Weight: 100}
+		backend.Name, _ = target["name"].(string)
+		if weight, ok := target["weight"].(int64); ok {
+			backend.Weight = weight
+		}
+		if backend.Name != "" {
+			backends = append(backends, backend)
+		}
+	}
+	if to, ok, _ := unstructured.NestedMap(route.Object, "spec", "to"); ok {
+		add(to)
+	}
+	alternates, _, _ := unstructured.NestedSlice(route.Object, "spec", "alternateBackends")
+	for _, a := range alternates {
+		if target, ok := a.(map[string]interface{}); ok {
+			add(target)
+		}
+	}
+	return backends
+}
+
+// routeTargetPort returns spec.port.targetPort, which is a service port name
+// or a number
+func routeTargetPort(route *unstructured.Unstructured) (intstr.IntOrString, bool) {
+	value, ok, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "port", "targetPort")
+	if !ok {
+		return intstr.IntOrString{}, false
+	}
+	switch port := value.(type) {
+	case string:
+		return intstr.FromString(port), true
+	case int64:
+		return intstr.FromInt(int(port)), true
+	case float64:
+		return intstr.FromInt(int(port)), true
+	}
+	return intstr.IntOrString{}, false
+}
+
+// servicePortMatches reports whether a service port serves a route target
+// port, matched by name or by port or target port number
+func servicePortMatches(port corev1.ServicePort, target intstr.IntOrString) bool {
+	if target.Type == intstr.String {
+		return port.Name == target.StrVal || (port.TargetPort.Type == intstr.String && port.TargetPort.StrVal == target.StrVal)
+	}
+	return port.Port == target.IntVal || port.TargetPort.IntValue() == int(target.IntVal)
+}
+
+// routerAdmission is whether one router admitted a route
+type routerAdmission struct {
+	Router   string
+	Host     string
+	Admitted string
+	Reason   string
+	Message  string
+}
+
+func routeAdmissions(route *unstructured.Unstructured) []routerAdmission {
+	ingresses, _, _ := unstructured.NestedSlice(route.Object, "status", "ingress")
+	admissions := make([]routerAdmission, 0, len(ingresses))
+	for _, i := range ingresses {
+		ingress, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		admission := routerAdmission{Admitted: "Unknown"}
+		admission.Router, _ = ingress["routerName"].(string)
+		admission.Host, _ = ingress["host"].(string)
+		conditions := statusConditions(&unstructured.Unstructured{Object: map[string]interface{}{"status": ingress}})
+		if condition, ok := conditionStatus(conditions, "Admitted"); ok {
+			admission.Admitted = condition.Status
+			admission.Reason = condition.Reason
+			admission.Message = condition.Message
+		}
+		admissions = append(admissions, admission)
+	}
+	return admissions
+}
+
+// admissionSummary is a one-line account of which routers admitted a route
+func admissionSummary(admissions []routerAdmission) string {
+	if len(admissions) == 0 {
+		return "not admitted by any router"
+	}
+	var admitted, rejected []string
+	for _, admission := range admissions {
+		if admission.Admitted == "True" {
+			admitted = append(admitted, admission.Router)
+		} else {
+			rejected = append(rejected, admission.Router)
+		}
+	}
+	switch {
+	case len(rejected) == 0:
+		return "admitted by " + strings.Join(admitted, ", ")
+	case len(admitted) == 0:
+		return "rejected by " + strings.Join(rejected, ", ")
+	}
+	return fmt.Sprintf("admitted by %s, rejected by %s", strings.Join(admitted, ", "), strings.Join(rejected, ", "))
+}
+
+// routeTLS describes the TLS termination of a route, "none" for plain HTTP
+func routeTLS(route *unstructured.Unstructured) string {
+	termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+	if termination == "" {
+		return "none"
+	}
+	if policy, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "insecureEdgeTerminationPolicy"); policy != "" {
+		return fmt.Sprintf("%s, insecure %s", termination, policy)
+	}
+	return termination
+}
+
+// certificateExpiry returns when the first certificate in a PEM bundle expires
+func certificateExpiry(pemData string) (time.Time, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return time.Time{}, fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+func (s *Server) initRoutes() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_routes",
+			mcp.WithDescription("List OpenShift Routes with their host, path, backing service, TLS termination and router admission"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list routes from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=frontend")),
+			mcp.WithTitleAnnotation("Routes: List"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listRoutesHandler)},
+		{Tool: mcp.NewTool("openshift_route_analyze",
+			mcp.WithDescription("Analyze an OpenShift Route: backing services and ready endpoints, target port, TLS configuration and certificate expiry, admission by each router, health of the routers and load balancers serving it and DNS resolution of the host"),
+			mcp.WithString("route_name", mcp.Description("Name of the route to analyze"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the route (default: default)")),
+			mcp.WithTitleAnnotation("OpenShift: Route Analysis"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.openShiftRouteAnalyze)},
+		{Tool: mcp.NewTool("analyze_route_sharding",
+			mcp.WithDescription("Analyze route sharding across IngressControllers: which shard selects and admits each route, routes admitted by no shard with the labels that would fix them, overlapping shard selectors, hosts under another shard's domain, and router replica placement across nodes and zones"),
+			mcp.WithString("namespace", mcp.Description("Only check the routes of this namespace (default: all namespaces)")),
+			mcp.WithTitleAnnotation("Routes: Analyze Sharding"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.analyzeRouteShardingHandler)},
+		{Tool: mcp.NewTool("create_route",
+			mcp.WithDescription("Expose a Service with an OpenShift Route, optionally TLS-terminated at the router"),
+			mcp.WithString("service", mcp.Description("Service to expose"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the service"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Name of the route (default: the service name)")),
+			mcp.WithString("host", mcp.Description("Hostname; generated from the router's domain when empty")),
+			mcp.WithString("path", mcp.Description("Path the route matches, e.g. /api")),
+			mcp.WithString("port", mcp.Description("Service port name or number to send traffic to (default: the service's only port)")),
+			mcp.WithString("tls_termination", mcp.Description("edge, passthrough or reencrypt; empty for plain HTTP")),
+			mcp.WithString("insecure_policy", mcp.Description("What edge and reencrypt routes do with HTTP requests: Redirect (default), Allow or None")),
+			mcp.WithTitleAnnotation("Create: Route"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.createRouteHandler)},
+	}
+}
+
+func (s *Server) listRoutesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Dynamic client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	list, err := s.dynamicClient.Resource(routesGVR).Namespace(namespace).List(ctx, opts)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ Routes are not available; this does not look like an OpenShift cluster"), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to list routes in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	routes := list.Items
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].GetNamespace() != routes[j].GetNamespace() {
+			return routes[i].GetNamespace() < routes[j].GetNamespace()
+		}
+		return routes[i].GetName() < routes[j].GetName()
+	})
+
+	result := "🌐 Route List Results\n"
+	result += "=====================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+	result += fmt.Sprintf("📦 Found %d routes:\n", len(routes))
+
+	for i := range routes {
+		route := &routes[i]
+		name := route.GetName()
+		if namespace == metav1.NamespaceAll {
+			name = route.GetNamespace() + "/" + name
+		}
+		host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+		path, _, _ := unstructured.NestedString(route.Object, "spec", "path")
+		var services []string
+		for _, backend := range routeBackends(route) {
+			services = append(services, backend.Name)
+		}
+		target := strings.Join(services, ", ")
+		if port, ok := routeTargetPort(route); ok {
+			target += ":" + port.String()
+		}
+		admissions := routeAdmissions(route)
+		icon := "✅"
+		for _, admission := range admissions {
+			if admission.Admitted != "True" {
+				icon = "⚠️ "
+			}
+		}
+		if len(admissions) == 0 {
+			icon = "⚠️ "
+		}
+		result += fmt.Sprintf("%s %s - %s%s → %s\n", icon, name, valueOrNone(host), path, valueOrNone(target))
+		result += fmt.Sprintf("   TLS: %s, %s\n", routeTLS(route), admissionSummary(admissions))
+	}
+	if len(routes) == 0 {
+		result += "📭 None found\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) openShiftRouteAnalyze(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil || s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	routeName := strings.TrimSpace(mcp.ParseString(request, "route_name", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	if routeName == "" {
+		return mcp.NewToolResultText("❌ route_name is required"), nil
+	}
+
+	route, err := s.dynamicClient.Resource(routesGVR).Namespace(namespace).Get(ctx, routeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Route %s not found in namespace %s. Use list_routes to see the routes there.", routeName, namespace)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get route %s", routeName), err), nil
+	}
+
+	var problems []string
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	path, _, _ := unstructured.NestedString(route.Object, "spec", "path")
+	targetPort, hasTargetPort := routeTargetPort(route)
+
+	result := fmt.Sprintf("🌐 Route Analysis: %s/%s\n", namespace, routeName)
+	result += "=====================\n\n"
+	result += fmt.Sprintf("Host: %s%s\n", valueOrNone(host), path)
+	if hasTargetPort {
+		result += fmt.Sprintf("Target port: %s\n", targetPort.String())
+	}
+
+	// Backing services and their endpoints
+	result += "\n🔗 Backends:\n"
+	backends := routeBackends(route)
+	if len(backends) == 0 {
+		result += "❌ spec.to names no service\n"
+		problems = append(problems, "the route has no backing service")
+	}
+	for _, backend := range backends {
+		result += fmt.Sprintf("• Service %s (weight %d)\n", backend.Name, backend.Weight)
+		service, err := s.k8sClient.CoreV1().Services(namespace).Get(ctx, backend.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				result += "   ❌ Service not found\n"
+				problems = append(problems, fmt.Sprintf("service %s does not exist", backend.Name))
+			} else {
+				result += fmt.Sprintf("   ⚠️  Could not get the service: %v\n", err)
+			}
+			continue
+		}
+		if hasTargetPort {
+			matched := false
+			for _, port := range service.Spec.Ports {
+				matched = matched || servicePortMatches(port, targetPort)
+			}
+			if !matched {
+				result += fmt.Sprintf("   ❌ No service port matches target port %s\n", targetPort.String())
+				problems = append(problems, fmt.Sprintf("target port %s is not a port of service %s", targetPort.String(), backend.Name))
+			}
+		}
+		if len(service.Spec.Selector) == 0 {
+			result += "   ℹ️  Service has no selector; its endpoints are managed manually\n"
+		}
+
+		endpoints, err := s.k8sClient.CoreV1().Endpoints(namespace).Get(ctx, backend.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			result += fmt.Sprintf("   ⚠️  Could not get endpoints: %v\n", err)
+			continue
+		}
+		ready, notReady := 0, 0
+		if err == nil {
+			for _, subset := range endpoints.Subsets {
+				ready += len(subset.Addresses)
+				notReady += len(subset.NotReadyAddresses)
+			}
+		}
+		if ready == 0 {
+			result += fmt.Sprintf("   ❌ No ready endpoints (%d not ready)\n", notReady)
+			if backend.Weight > 0 {
+				problems = append(problems, fmt.Sprintf("service %s has no ready endpoints; check its selector matches running, ready pods", backend.Name))
+			}
+		} else {
+			result += fmt.Sprintf("   ✅ %d ready endpoint(s), %d not ready\n", ready, notReady)
+		}
+	}
+
+	// TLS configuration
+	result += fmt.Sprintf("\n🔒 TLS: %s\n", routeTLS(route))
+	termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+	if certificate, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "certificate"); certificate != "" {
+		if expiry, err := certificateExpiry(certificate); err != nil {
+			result += fmt.Sprintf("   ❌ Certificate cannot be parsed: %v\n", err)
+			problems = append(problems, "the route certificate cannot be parsed")
+		} else if remaining := time.Until(expiry); remaining <= 0 {
+			result += fmt.Sprintf("   ❌ Certificate expired %s\n", formatTime(ctx, expiry))
+			problems = append(problems, "the route certificate has expired")
+		} else {
+			icon := "✅"
+			if remaining < routeCertWarning {
+				icon = "⚠️ "
+				problems = append(problems, fmt.Sprintf("the route certificate expires in %d days", int(remaining.Hours()/24)))
+			}
+			result += fmt.Sprintf("   %s Certificate expires %s\n", icon, formatTime(ctx, expiry))
+		}
+	} else if termination == "edge" || termination == "reencrypt" {
+		result += "   ℹ️  No certificate set; the router's default certificate is served\n"
+	}
+	if termination == "reencrypt" {
+		if caCert, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "destinationCACertificate"); caCert == "" {
+			result += "   ℹ️  No destination CA; the backend must serve a service CA signed certificate\n"
+		}
+	}
+
+	// Admission by each router
+	result += "\n🚦 Router admission:\n"
+	admissions := routeAdmissions(route)
+	if len(admissions) == 0 {
+		result += "❌ No router has admitted the route\n"
+		problems = append(problems, "no router has admitted the route; check the ingress controllers' route selectors")
+	}
+	for _, admission := range admissions {
+		icon := "✅"
+		if admission.Admitted != "True" {
+			icon = "❌"
+			problems = append(problems, fmt.Sprintf("router %s did not admit the route (%s)", admission.Router, valueOrNone(admission.Reason)))
+		}
+		result += fmt.Sprintf("%s %s - Admitted=%s", icon, admission.Router, admission.Admitted)
+		if admission.Reason != "" {
+			result += fmt.Sprintf(" (%s)", admission.Reason)
+		}
+		result += "\n"
+		if admission.Message != "" {
+			result += fmt.Sprintf("   %s\n", admission.Message)
+		}
+	}
+
+	// Health of the routers serving the route
+	var admitting []string
+	for _, admission := range admissions {
+		if admission.Admitted == "True" {
+			admitting = append(admitting, admission.Router)
+		}
+	}
+	if len(admitting) > 0 {
+		result += "\n🏗️ Router infrastructure:\n"
+		infrastructure, err := s.routerInfrastructure(ctx, admitting)
+		issues := diagnostics.RouterInfrastructureIssues(infrastructure)
+		switch {
+		case err != nil:
+			result += fmt.Sprintf("⚠️  Could not read the routers: %v\n", err)
+		case len(infrastructure.Controllers) == 0:
+			result += "ℹ️  No IngressController found for the admitting routers\n"
+		case len(issues) == 0:
+			ready := 0
+			for _, pod := range infrastructure.Pods {
+				if pod.Ready {
+					ready++
+				}
+			}
+			result += fmt.Sprintf("✅ %d of %d router pods ready, load balancers serving\n", ready, len(infrastructure.Pods))
+		}
+		for _, issue := range issues {
+			icon := "⚠️ "
+			if issue.Severity == "critical" {
+				icon = "❌"
+			}
+			result += fmt.Sprintf("%s %s\n   %s\n", icon, issue.Title, issue.Description)
+			problems = append(problems, fmt.Sprintf("%s; collect_ingress_diagnostics gathers the routers' logs and HAProxy state", issue.Title))
+		}
+	}
+
+	// DNS resolution of the host
+	if host != "" {
+		result += "\n📡 DNS:\n"
+		lookupCtx, cancel := context.WithTimeout(ctx, routeDNSTimeout)
+		addresses, err := lookupHost(lookupCtx, host)
+		cancel()
+		if err != nil {
+			result += fmt.Sprintf("❌ %s does not resolve: %v\n", host, err)
+			problems = append(problems, fmt.Sprintf("%s does not resolve; check the wildcard DNS record of the ingress domain", host))
+		} else {
+			result += fmt.Sprintf("✅ %s resolves to %s\n", host, strings.Join(addresses, ", "))
+		}
+	}
+
+	if len(problems) == 0 {
+		result += "\n✅ No problems found"
+	} else {
+		result += fmt.Sprintf("\n⚠️  Problems found (%d):\n", len(problems))
+		for _, problem := range problems {
+			result += fmt.Sprintf("• %s\n", problem)
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) createRouteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil || s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	serviceName := strings.TrimSpace(mcp.ParseString(request, "service", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	name := strings.TrimSpace(mcp.ParseString(request, "name", serviceName))
+	host := strings.TrimSpace(mcp.ParseString(request, "host", ""))
+	path := strings.TrimSpace(mcp.ParseString(request, "path", ""))
+	port := strings.TrimSpace(mcp.ParseString(request, "port", ""))
+	termination := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "tls_termination", "")))
+	insecurePolicy := strings.TrimSpace(mcp.ParseString(request, "insecure_policy", "Redirect"))
+	if serviceName == "" {
+		return mcp.NewToolResultText("❌ service is required"), nil
+	}
+	if path != "" && !strings.HasPrefix(path, "/") {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid path '%s': must start with /", path)), nil
+	}
+	switch termination {
+	case "", "edge", "passthrough", "reencrypt":
+	default:
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid tls_termination '%s': expected edge, passthrough or reencrypt", termination)), nil
+	}
+	switch insecurePolicy {
+	case "Redirect", "Allow", "None":
+	default:
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid insecure_policy '%s': expected Redirect, Allow or None", insecurePolicy)), nil
+	}
+	if termination == "passthrough" && insecurePolicy == "Allow" {
+		return mcp.NewToolResultText("❌ Passthrough routes cannot allow insecure traffic; use Redirect or None"), nil
+	}
+
+	service, err := s.k8sClient.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Service %s not found in namespace %s", serviceName, namespace)), nil
+		}
+		return toolError(ctx, fmt.Sprintf("Failed to get service %s", serviceName), err), nil
+	}
+	var targetPort intstr.IntOrString
+	switch {
+	case port != "":
+		targetPort = intstr.Parse(port)
+		matched := false
+		for _, servicePort := range service.Spec.Ports {
+			matched = matched || servicePortMatches(servicePort, targetPort)
+		}
+		if !matched {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Service %s has no port %s", serviceName, port)), nil
+		}
+	case len(service.Spec.Ports) == 1:
+		targetPort = service.Spec.Ports[0].TargetPort
+		if service.Spec.Ports[0].Name != "" {
+			targetPort = intstr.FromString(service.Spec.Ports[0].Name)
+		}
+	case len(service.Spec.Ports) > 1:
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Service %s has %d ports; choose one with the port parameter", serviceName, len(service.Spec.Ports))), nil
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"to": map[string]interface{}{"kind": "Service", "name": serviceName, "weight": int64(100)},
+		},
+	}}
+	if len(service.Labels) > 0 {
+		route.SetLabels(service.Labels)
+	}
+	if host != "" {
+		unstructured.SetNestedField(route.Object, host, "spec", "host")
+	}
+	if path != "" {
+		unstructured.SetNestedField(route.Object, path, "spec", "path")
+	}
+	if targetPort.Type == intstr.String && targetPort.StrVal != "" {
+		unstructured.SetNestedField(route.Object, targetPort.StrVal, "spec", "port", "targetPort")
+	} else if targetPort.IntVal != 0 {
+		unstructured.SetNestedField(route.Object, int64(targetPort.IntVal), "spec", "port", "targetPort")
+	}
+	if termination != "" {
+		unstructured.SetNestedField(route.Object, termination, "spec", "tls", "termination")
+		unstructured.SetNestedField(route.Object, insecurePolicy, "spec", "tls", "insecureEdgeTerminationPolicy")
+	}
+
+	created, err := s.dynamicClient.Resource(routesGVR).Namespace(namespace).Create(ctx, route, metav1.CreateOptions{})
+	if err != nil {
+		return toolError(ctx, "Failed to create Route", err), nil
+	}
+	s.recordAction(ctx, "create_route", objectReference("route.openshift.io/v1", "Route", created), ReasonCreated, "Created Route")
+
+	createdHost, _, _ := unstructured.NestedString(created.Object, "spec", "host")
+	scheme := "http"
+	if termination != "" {
+		scheme = "https"
+	}
+	result := "🌐 Route Created Successfully\n"
+	result += "=============================\n\n"
+	result += fmt.Sprintf("Name: %s\n", created.GetName())
+	result += fmt.Sprintf("Namespace: %s\n", created.GetNamespace())
+	result += fmt.Sprintf("Service: %s\n", serviceName)
+	result += fmt.Sprintf("TLS: %s\n", routeTLS(created))
+	if createdHost != "" {
+		result += fmt.Sprintf("URL: %s://%s%s\n", scheme, createdHost, path)
+	} else {
+		result += "Host: assigned by the router\n"
+	}
+	result += fmt.Sprintf("\n💡 Use openshift_route_analyze route_name=%s namespace=%s to check it is admitted and reachable", created.GetName(), created.GetNamespace())
+	return mcp.NewToolResultText(result), nil
+}
+
+// ListRoutesHandler is a public wrapper for listRoutesHandler
+func (s *Server) ListRoutesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listRoutesHandler(ctx, request)
+}
+
+// OpenShiftRouteAnalyzeHandler is a public wrapper for openShiftRouteAnalyze
+func (s *Server) OpenShiftRouteAnalyzeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.openShiftRouteAnalyze(ctx, request)
+}
+
+// CreateRouteHandler is a public wrapper for createRouteHandler
+func (s *Server) CreateRouteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.createRouteHandler(ctx, request)
+}
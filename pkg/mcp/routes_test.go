@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServicePortMatches(t *testing.T) {
+	port := corev1.ServicePort{Name: "web", Port: 80, TargetPort: intstr.FromInt(8080)}
+	tests := []struct {
+		target   intstr.IntOrString
+		expected bool
+	}{
+		{intstr.FromString("web"), true},
+		{intstr.FromInt(80), true},
+		{intstr.FromInt(8080), true},
+		{intstr.FromString("metrics"), false},
+		{intstr.FromInt(9090), false},
+	}
+
+	for _, tt := range tests {
+		if result := servicePortMatches(port, tt.target); result != tt.expected {
+			t.Errorf("servicePortMatches(%s) = %v, expected %v", tt.target.String(), result, tt.expected)
+		}
+	}
+}
+
+// testCertificate returns a self-signed PEM certificate expiring at notAfter
+func testCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey error = %v", err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notAfter.Add(-365 * 24 * time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate error = %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func newRouteTestServer(objects ...runtime.Object) *Server {
+	listKinds := map[schema.GroupVersionResource]string{routesGVR: "RouteList"}
+	return &Server{
+		config:        &Config{},
+		k8sClient:     kubefake.NewSimpleClientset(),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...),
+	}
+}
+
+func TestOpenShiftRouteAnalyze(t *testing.T) {
+	route := newUnstructured("route.openshift.io/v1", "Route", "shop", "frontend")
+	unstructured.SetNestedField(route.Object, "shop.apps.example.com", "spec", "host")
+	unstructured.SetNestedMap(route.Object, map[string]interface{}{"kind": "Service", "name": "frontend", "weight": int64(100)}, "spec", "to")
+	unstructured.SetNestedField(route.Object, "web", "spec", "port", "targetPort")
+	unstructured.SetNestedMap(route.Object, map[string]interface{}{
+		"termination": "edge", "insecureEdgeTerminationPolicy": "Redirect",
+		"certificate": testCertificate(t, time.Now().Add(10*24*time.Hour)),
+	}, "spec", "tls")
+	unstructured.SetNestedSlice(route.Object, []interface{}{
+		map[string]interface{}{"routerName": "default", "host": "shop.apps.example.com", "conditions": []interface{}{
+			map[string]interface{}{"type": "Admitted", "status": "True"},
+		}},
+		map[string]interface{}{"routerName": "sharded", "host": "shop.apps.example.com", "conditions": []interface{}{
+			map[string]interface{}{"type": "Admitted", "status": "False", "reason": "HostAlreadyClaimed", "message": "route shop/old has host"},
+		}},
+	}, "status", "ingress")
+
+	s := newRouteTestServer(route, newUnstructured("operator.openshift.io/v1", "IngressController", ingressOperatorNamespace, "default"))
+	s.k8sClient = kubefake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "router-default-a", Namespace: routerNamespace, Labels: map[string]string{routerDeploymentLabel: "default"}},
+			Spec:       corev1.PodSpec{NodeName: "worker-1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "shop"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "frontend"}, Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "shop"},
+			Subsets:    []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.128.0.5"}}}},
+		},
+	)
+	defer func(original func(context.Context, string) ([]string, error)) { lookupHost = original }(lookupHost)
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		return nil, fmt.Errorf("lookup %s: no such host", host)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"route_name": "frontend", "namespace": "shop"}
+	result, err := s.OpenShiftRouteAnalyzeHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("OpenShiftRouteAnalyzeHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Host: shop.apps.example.com",
+		"❌ No service port matches target port web",
+		"❌ No ready endpoints (1 not ready)",
+		"🔒 TLS: edge, insecure Redirect",
+		"⚠️  Certificate expires",
+		"✅ default - Admitted=True",
+		"❌ sharded - Admitted=False (HostAlreadyClaimed)",
+		"❌ No router of default is ready",
+		"❌ shop.apps.example.com does not resolve",
+		"Problems found (6)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("openshift_route_analyze output missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestCreateRoute(t *testing.T) {
+	s := newRouteTestServer()
+	s.k8sClient = kubefake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{"app": "api"}},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}, {Name: "metrics", Port: 9090}}},
+	})
+
+	tests := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"service": "api", "namespace": "shop"}, "❌ Service api has 2 ports; choose one with the port parameter"},
+		{map[string]interface{}{"service": "api", "namespace": "shop", "port": "grpc"}, "❌ Service api has no port grpc"},
+		{map[string]interface{}{"service": "api", "namespace": "shop", "port": "http", "tls_termination": "strict"}, "❌ Invalid tls_termination 'strict'"},
+		{map[string]interface{}{"service": "api", "namespace": "shop", "port": "http", "tls_termination": "passthrough", "insecure_policy": "Allow"}, "❌ Passthrough routes cannot allow insecure traffic"},
+		{map[string]interface{}{"service": "api", "namespace": "shop", "port": "http", "host": "api.example.com", "path": "/v1", "tls_termination": "edge"}, "URL: https://api.example.com/v1"},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = tt.args
+		result, err := s.CreateRouteHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("CreateRouteHandler(%v) error = %v", tt.args, err)
+		}
+		if text := resultText(result); !strings.Contains(text, tt.expected) {
+			t.Errorf("CreateRouteHandler(%v) = %q, expected %q", tt.args, text, tt.expected)
+		}
+	}
+
+	route, err := s.dynamicClient.Resource(routesGVR).Namespace("shop").Get(context.Background(), "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("created route not found: %v", err)
+	}
+	targetPort, _, _ := unstructured.NestedString(route.Object, "spec", "port", "targetPort")
+	insecure, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "insecureEdgeTerminationPolicy")
+	if targetPort != "http" || insecure != "Redirect" || route.GetLabels()["app"] != "api" {
+		t.Errorf("created route = %v", route.Object)
+	}
+}
@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	ingressOperatorNamespace = "openshift-ingress-operator"
+	routerNamespace          = "openshift-ingress"
+	// routerDeploymentLabel names the IngressController a router pod serves
+	routerDeploymentLabel = "ingresscontroller.operator.openshift.io/deployment-ingresscontroller"
+	zoneLabel             = "topology.kubernetes.io/zone"
+	// defaultRouterReplicas is what the operator runs when spec.replicas is
+	// unset on a highly available cluster
+	defaultRouterReplicas = 2
+)
+
+var ingressControllersGVR = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "ingresscontrollers"}
+
+// defaultRouterNodeSelector is where routers run without a nodePlacement
+var defaultRouterNodeSelector = labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/worker": ""})
+
+// ingressShard is an IngressController with its parsed selectors
+type ingressShard struct {
+	diagnostics.IngressShard
+	routes     labels.Selector
+	namespaces labels.Selector
+	nodes      labels.Selector
+}
+
+// selects reports whether a shard's selectors match a route in a namespace
+func (shard ingressShard) selects(route *unstructured.Unstructured, namespaceLabels map[string]string) bool {
+	return shard.routes.Matches(labels.Set(route.GetLabels())) && shard.namespaces.Matches(labels.Set(namespaceLabels))
+}
+
+// nestedLabelSelector reads a LabelSelector field of an object. A missing
+// selector selects everything; the rendered form is empty then.
+func nestedLabelSelector(obj *unstructured.Unstructured, fields ...string) (labels.Selector, string, string, error) {
+	raw, ok, _ := unstructured.NestedMap(obj.Object, fields...)
+	if !ok || len(raw) == 0 {
+		return labels.Everything(), "", "", nil
+	}
+	var selector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &selector); err != nil {
+		return nil, "", "", err
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return nil, "", "", err
+	}
+	// Plain label matches can be applied with oc label
+	plain := ""
+	if len(selector.MatchExpressions) == 0 {
+		plain = labels.Set(selector.MatchLabels).String()
+	}
+	return parsed, metav1.FormatLabelSelector(&selector), plain, nil
+}
+
+// parseIngressShard reads an IngressController
+func parseIngressShard(controller *unstructured.Unstructured) (ingressShard, error) {
+	shard := ingressShard{IngressShard: diagnostics.IngressShard{Name: controller.GetName(), DesiredReplicas: defaultRouterReplicas}}
+	var err error
+	if shard.routes, shard.RouteSelector, shard.RouteLabels, err = nestedLabelSelector(controller, "spec", "routeSelector"); err != nil {
+		return shard, fmt.Errorf("routeSelector: %w", err)
+	}
+	if shard.namespaces, shard.NamespaceSelector, shard.NamespaceLabels, err = nestedLabelSelector(controller, "spec", "namespaceSelector"); err != nil {
+		return shard, fmt.Errorf("namespaceSelector: %w", err)
+	}
+	shard.nodes = defaultRouterNodeSelector
+	if _, ok, _ := unstructured.NestedMap(controller.Object, "spec", "nodePlacement", "nodeSelector"); ok {
+		if shard.nodes, _, _, err = nestedLabelSelector(controller, "spec", "nodePlacement", "nodeSelector"); err != nil {
+			return shard, fmt.Errorf("nodePlacement: %w", err)
+		}
+	}
+
+	shard.Domain, _, _ = unstructured.NestedString(controller.Object, "status", "domain")
+	if shard.Domain == "" {
+		shard.Domain, _, _ = unstructured.NestedString(controller.Object, "spec", "domain")
+	}
+	if replicas, ok, _ := unstructured.NestedInt64(controller.Object, "spec", "replicas"); ok {
+		shard.DesiredReplicas = int32(replicas)
+	}
+	// Controllers are admitted and available until their conditions say
+	// otherwise, as they are before the operator first reports
+	shard.Admitted, shard.Available = true, true
+	for _, condition := range statusConditions(controller, "Admitted", "Available") {
+		switch condition.Type {
+		case "Admitted":
+			shard.Admitted = condition.Status == "True"
+		case "Available":
+			shard.Available = condition.Status == "True"
+		}
+		if condition.Status == "False" && condition.Message != "" && shard.Message == "" {
+			shard.Message = fmt.Sprintf("%s: %s", condition.Type, condition.Message)
+		}
+	}
+	return shard, nil
+}
+
+// routeShardingState reads the IngressControllers with their router pods and
+// the routes of a namespace, or of all namespaces, with the shards that
+// select and admit each
+func (s *Server) routeShardingState(ctx context.Context, namespace string) ([]diagnostics.IngressShard, []diagnostics.ShardedRoute, error) {
+	controllers, err := s.dynamicClient.Resource(ingressControllersGVR).Namespace(ingressOperatorNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing ingress controllers: %w", err)
+	}
+	var shards []ingressShard
+	for i := range controllers.Items {
+		shard, err := parseIngressShard(&controllers.Items[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("ingresscontroller %s: %w", controllers.Items[i].GetName(), err)
+		}
+		shards = append(shards, shard)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Name < shards[j].Name })
+
+	nodes, err := s.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	nodeZones := make(map[string]string, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeZones[node.Name] = node.Labels[zoneLabel]
+	}
+	pods, err := s.k8sClient.CoreV1().Pods(routerNamespace).List(ctx, metav1.ListOptions{LabelSelector: routerDeploymentLabel})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing router pods: %w", err)
+	}
+	result := make([]diagnostics.IngressShard, 0, len(shards))
+	for i := range shards {
+		shard := &shards[i]
+		zones := make(map[string]bool)
+		for _, node := range nodes.Items {
+			if zone := node.Labels[zoneLabel]; zone != "" && shard.nodes.Matches(labels.Set(node.Labels)) {
+				zones[zone] = true
+			}
+		}
+		shard.Zones = sortedKeys(zones)
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+			if pod.Labels[routerDeploymentLabel] != shard.Name || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			shard.Pods = append(shard.Pods, diagnostics.RouterPod{
+				Name:  pod.Name,
+				Node:  pod.Spec.NodeName,
+				Zone:  nodeZones[pod.Spec.NodeName],
+				Ready: podReady(pod),
+			})
+		}
+		sort.Slice(shard.Pods, func(a, b int) bool { return shard.Pods[a].Name < shard.Pods[b].Name })
+		result = append(result, shard.IngressShard)
+	}
+
+	namespaceLabels := make(map[string]map[string]string)
+	namespaces, err := s.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+	for _, ns := range namespaces.Items {
+		namespaceLabels[ns.Name] = ns.Labels
+	}
+	list, err := s.dynamicClient.Resource(routesGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing routes: %w", err)
+	}
+	routes := make([]diagnostics.ShardedRoute, 0, len(list.Items))
+	for i := range list.Items {
+		route := &list.Items[i]
+		entry := diagnostics.ShardedRoute{Namespace: route.GetNamespace(), Name: route.GetName()}
+		entry.Host, _, _ = unstructured.NestedString(route.Object, "spec", "host")
+		for _, shard := range shards {
+			if shard.selects(route, namespaceLabels[route.GetNamespace()]) {
+				entry.Selected = append(entry.Selected, shard.Name)
+			}
+		}
+		for _, admission := range routeAdmissions(route) {
+			if admission.Admitted == "True" {
+				entry.Admitted = append(entry.Admitted, admission.Router)
+				continue
+			}
+			if entry.Rejected == nil {
+				entry.Rejected = make(map[string]string)
+			}
+			entry.Rejected[admission.Router] = admission.Reason
+		}
+		routes = append(routes, entry)
+	}
+	return result, routes, nil
+}
+
+func (s *Server) analyzeRouteShardingHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil || s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+
+	shards, routes, err := s.routeShardingState(ctx, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return mcp.NewToolResultText("❌ IngressControllers or Routes are not available; this does not look like an OpenShift cluster"), nil
+		}
+		return toolError(ctx, "Failed to read the ingress controllers and routes", err), nil
+	}
+
+	response := "🔀 Route Sharding\n"
+	response += "================\n\n"
+	if namespace != "" {
+		response += fmt.Sprintf("Routes of namespace %s\n\n", namespace)
+	}
+	response += fmt.Sprintf("🚦 Ingress controllers (%d):\n", len(shards))
+	admitted := make(map[string]int)
+	for _, route := range routes {
+		for _, name := range route.Admitted {
+			admitted[name]++
+		}
+	}
+	for _, shard := range shards {
+		icon := "✅"
+		if !shard.Admitted || !shard.Available {
+			icon = "❌"
+		}
+		selectors := "every route"
+		if shard.Selective() {
+			var parts []string
+			if shard.RouteSelector != "" {
+				parts = append(parts, "routes "+shard.RouteSelector)
+			}
+			if shard.NamespaceSelector != "" {
+				parts = append(parts, "namespaces "+shard.NamespaceSelector)
+			}
+			selectors = strings.Join(parts, ", ")
+		}
+		ready := 0
+		zones := make(map[string]bool)
+		for _, pod := range shard.Pods {
+			if pod.Ready {
+				ready++
+			}
+			if pod.Zone != "" {
+				zones[pod.Zone] = true
+			}
+		}
+		response += fmt.Sprintf("%s %s - *.%s, selects %s\n", icon, shard.Name, valueOrNone(shard.Domain), selectors)
+		response += fmt.Sprintf("   Routers: %d/%d ready", ready, shard.DesiredReplicas)
+		if len(zones) > 0 {
+			response += fmt.Sprintf(" in %s", strings.Join(sortedKeys(zones), ", "))
+		}
+		response += fmt.Sprintf("; admitted routes: %d\n", admitted[shard.Name])
+	}
+	if len(shards) == 0 {
+		response += "📭 None found\n"
+	}
+	response += "\n"
+
+	result := diagnostics.AnalyzeRouteSharding(shards, routes, time.Now())
+	response += s.formatAnalysisResult(ctx, result)
+	return mcp.NewToolResultText(strings.TrimRight(response, "\n")), nil
+}
+
+// AnalyzeRouteShardingHandler is a public wrapper for analyzeRouteShardingHandler
+func (s *Server) AnalyzeRouteShardingHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.analyzeRouteShardingHandler(ctx, request)
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAnalyzeRouteSharding(t *testing.T) {
+	controller := func(name, domain string, replicas int64) *unstructured.Unstructured {
+		obj := newUnstructured("operator.openshift.io/v1", "IngressController", ingressOperatorNamespace, name)
+		unstructured.SetNestedField(obj.Object, domain, "status", "domain")
+		unstructured.SetNestedField(obj.Object, replicas, "spec", "replicas")
+		unstructured.SetNestedSlice(obj.Object, []interface{}{
+			map[string]interface{}{"type": "Admitted", "status": "True"},
+			map[string]interface{}{"type": "Available", "status": "True"},
+		}, "status", "conditions")
+		return obj
+	}
+	internal := controller("internal", "internal.example.com", 1)
+	unstructured.SetNestedStringMap(internal.Object, map[string]string{"type": "internal"}, "spec", "routeSelector", "matchLabels")
+	unstructured.SetNestedStringMap(internal.Object, map[string]string{"node-role.kubernetes.io/infra": ""}, "spec", "nodePlacement", "nodeSelector", "matchLabels")
+
+	route := func(name, host string, routeLabels map[string]string, routers ...string) *unstructured.Unstructured {
+		obj := newUnstructured("route.openshift.io/v1", "Route", "shop", name)
+		obj.SetLabels(routeLabels)
+		unstructured.SetNestedField(obj.Object, host, "spec", "host")
+		var ingresses []interface{}
+		for _, router := range routers {
+			ingresses = append(ingresses, map[string]interface{}{
+				"routerName": router, "host": host,
+				"conditions": []interface{}{map[string]interface{}{"type": "Admitted", "status": "True"}},
+			})
+		}
+		unstructured.SetNestedSlice(obj.Object, ingresses, "status", "ingress")
+		return obj
+	}
+
+	node := func(name, zone, role string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{zoneLabel: zone, "node-role.kubernetes.io/" + role: ""}}}
+	}
+	router := func(name, shard, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: routerNamespace, Labels: map[string]string{routerDeploymentLabel: shard}},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	listKinds := map[schema.GroupVersionResource]string{ingressControllersGVR: "IngressControllerList", routesGVR: "RouteList"}
+	s := &Server{
+		config: &Config{},
+		k8sClient: kubefake.NewSimpleClientset(
+			node("worker-1", "zone-a", "worker"), node("worker-2", "zone-b", "worker"), node("infra-1", "zone-a", "infra"),
+			router("router-default-a", "default", "worker-1"), router("router-default-b", "default", "worker-1"),
+			router("router-internal-a", "internal", "infra-1"),
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+			controller("default", "apps.example.com", 2), internal,
+			route("web", "web.apps.example.com", nil, "default"),
+			route("api", "api.internal.example.com", map[string]string{"type": "internal"}, "internal"),
+		),
+	}
+
+	result, err := s.AnalyzeRouteShardingHandler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("AnalyzeRouteShardingHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"✅ default - *.apps.example.com, selects every route",
+		"Routers: 2/2 ready in zone-a; admitted routes: 1",
+		"✅ internal - *.internal.example.com, selects routes type=internal",
+		"All routers of default run on one node",
+		"Ingress controller selectors overlap",
+		"Give default a routeSelector",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("analyze_route_sharding output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "not selected by any ingress controller") || strings.Contains(text, "resolve to a shard") {
+		t.Errorf("unexpected unadmitted or misrouted routes:\n%s", text)
+	}
+}
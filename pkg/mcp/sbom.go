@@ -0,0 +1,298 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSBOMDir is where SBOMs are looked up and generated into
+	defaultSBOMDir = "/tmp/diagnostics/sbom"
+	// sbomImagePlaceholder is replaced by the pinned image in the command
+	sbomImagePlaceholder = "{image}"
+	// sbomGenerateTimeout bounds generating one image's SBOM
+	sbomGenerateTimeout = 5 * time.Minute
+	// maxSBOMGenerations bounds the SBOMs one call generates
+	maxSBOMGenerations = 10
+)
+
+// defaultSBOMCommand generates an SPDX SBOM with syft
+var defaultSBOMCommand = []string{"syft", sbomImagePlaceholder, "-o", "spdx-json"}
+
+// sbomPackage is a package listed in an SBOM
+type sbomPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// parseSBOM reads the packages of an SPDX or CycloneDX JSON document
+func parseSBOM(data []byte) ([]sbomPackage, error) {
+	var doc struct {
+		SPDXVersion string `json:"spdxVersion"`
+		Packages    []struct {
+			Name         string `json:"name"`
+			VersionInfo  string `json:"versionInfo"`
+			ExternalRefs []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+		BOMFormat  string `json:"bomFormat"`
+		Components []struct {
+			Group   string `json:"group"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			PURL    string `json:"purl"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var packages []sbomPackage
+	switch {
+	case doc.SPDXVersion != "":
+		for _, p := range doc.Packages {
+			pkg := sbomPackage{Name: p.Name, Version: p.VersionInfo}
+			for _, ref := range p.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					pkg.PURL = ref.ReferenceLocator
+				}
+			}
+			packages = append(packages, pkg)
+		}
+	case doc.BOMFormat == "CycloneDX":
+		for _, c := range doc.Components {
+			name := c.Name
+			if c.Group != "" {
+				name = c.Group + ":" + c.Name
+			}
+			packages = append(packages, sbomPackage{Name: name, Version: c.Version, PURL: c.PURL})
+		}
+	default:
+		return nil, fmt.Errorf("neither an SPDX nor a CycloneDX JSON document")
+	}
+	return packages, nil
+}
+
+func (s *Server) sbomDir() string {
+	if s.config != nil && s.config.Inventory != nil && s.config.Inventory.SBOMDir != "" {
+		return s.config.Inventory.SBOMDir
+	}
+	return defaultSBOMDir
+}
+
+func (s *Server) sbomCommand() []string {
+	if s.config != nil && s.config.Inventory != nil && len(s.config.Inventory.SBOMCommand) > 0 {
+		return s.config.Inventory.SBOMCommand
+	}
+	return defaultSBOMCommand
+}
+
+// sbomPath is where the SBOM of an image digest is kept, e.g.
+// sha256-0123....json
+func sbomPath(dir, digest string) string {
+	return filepath.Join(dir, strings.ReplaceAll(digest, ":", "-")+".json")
+}
+
+// loadSBOM reads the SBOM of a digest, returning false when there is none
+func loadSBOM(dir, digest string) ([]sbomPackage, bool, error) {
+	data, err := os.ReadFile(sbomPath(dir, digest))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	packages, err := parseSBOM(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %v", sbomPath(dir, digest), err)
+	}
+	return packages, true, nil
+}
+
+// generateSBOM runs the SBOM command for an image pinned to its digest and
+// keeps the output under the digest
+func (s *Server) generateSBOM(ctx context.Context, reference, digest string) ([]sbomPackage, error) {
+	command := s.sbomCommand()
+	args := make([]string, len(command)-1)
+	for i, arg := range command[1:] {
+		args[i] = strings.ReplaceAll(arg, sbomImagePlaceholder, reference)
+	}
+	ctx, cancel := context.WithTimeout(ctx, sbomGenerateTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s: %v: %s", command[0], err, lastLine(message))
+		}
+		return nil, fmt.Errorf("%s: %v", command[0], err)
+	}
+	packages, err := parseSBOM(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s output: %v", command[0], err)
+	}
+
+	dir := s.sbomDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return packages, err
+	}
+	return packages, os.WriteFile(sbomPath(dir, digest), stdout.Bytes(), 0644)
+}
+
+// lastLine returns the last line of a command's error output
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return lines[len(lines)-1]
+}
+
+// matchPackage reports whether a package is the one asked about: the name
+// or package URL contains it, ignoring case
+func matchPackage(pkg sbomPackage, name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(strings.ToLower(pkg.Name), name) || strings.Contains(strings.ToLower(pkg.PURL), name)
+}
+
+var (
+	versionPartPattern       = regexp.MustCompile(`\d+|[A-Za-z]+`)
+	versionConstraintPattern = regexp.MustCompile(`^(>=|<=|!=|==|>|<|=)?\s*v?([0-9A-Za-z.*+-]+)$`)
+)
+
+// parseVersionConstraint checks a constraint such as 2.x, 2.14.1 or
+// ">=2.0.0, <2.17.1"
+func parseVersionConstraint(constraint string) error {
+	for _, clause := range versionClauses(constraint) {
+		if !versionConstraintPattern.MatchString(clause) {
+			return fmt.Errorf("invalid version constraint %q: expected e.g. 2.x, 2.14.1 or >=2.0.0,<2.17.1", clause)
+		}
+	}
+	return nil
+}
+
+// versionClauses splits a constraint into clauses, joining an operator
+// separated from its version by a space
+func versionClauses(constraint string) []string {
+	var clauses []string
+	for _, field := range strings.FieldsFunc(constraint, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if n := len(clauses); n > 0 && strings.Trim(clauses[n-1], "<>=!") == "" {
+			clauses[n-1] += field
+			continue
+		}
+		clauses = append(clauses, field)
+	}
+	return clauses
+}
+
+// versionMatches reports whether a version meets every clause of a
+// constraint. A bare version or one ending in .x or .* matches the versions
+// it prefixes, so 2.x and 2 both match 2.14.1.
+func versionMatches(version, constraint string) bool {
+	for _, clause := range versionClauses(constraint) {
+		match := versionConstraintPattern.FindStringSubmatch(clause)
+		if match == nil {
+			return false
+		}
+		op, want := match[1], match[2]
+		cmp := comparePackageVersions(version, want)
+		var ok bool
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "!=":
+			ok = cmp != 0
+		case "=", "==":
+			ok = cmp == 0
+		default:
+			ok = versionHasPrefix(version, strings.TrimRight(strings.TrimSuffix(strings.TrimSuffix(want, "x"), "*"), "."))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// versionHasPrefix reports whether the version's parts start with the
+// prefix's parts
+func versionHasPrefix(version, prefix string) bool {
+	have, want := versionParts(version), versionParts(prefix)
+	if len(want) > len(have) {
+		return false
+	}
+	for i := range want {
+		if comparePart(have[i], want[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// releaseQualifiers mark a release rather than a pre-release, e.g. 2.3.1.Final
+var releaseQualifiers = map[string]bool{"final": true, "release": true, "ga": true}
+
+// comparePackageVersions compares package versions part by part, numbers numerically. Of
+// two versions sharing a prefix, the longer is higher unless it continues
+// with a pre-release qualifier: 2.0.1 > 2.0 > 2.0-beta9.
+func comparePackageVersions(a, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if cmp := comparePart(as[i], bs[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -comparePackageVersions(b, a)
+	case len(as) > len(bs):
+		if _, err := strconv.Atoi(as[len(bs)]); err != nil {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// versionParts splits a version into its numbers and qualifiers, dropping
+// release qualifiers
+func versionParts(version string) []string {
+	var parts []string
+	for _, part := range versionPartPattern.FindAllString(version, -1) {
+		if !releaseQualifiers[strings.ToLower(part)] {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func comparePart(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return an - bn
+	case aErr == nil:
+		return 1 // numbers sort after qualifiers such as rc
+	case bErr == nil:
+		return -1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
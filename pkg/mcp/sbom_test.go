@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"testing"
+)
+
+func TestParseSBOM(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected sbomPackage
+	}{
+		{"spdx", `{"spdxVersion":"SPDX-2.3","packages":[{"name":"log4j-core","versionInfo":"2.14.1","externalRefs":[
+			{"referenceType":"cpe23Type","referenceLocator":"cpe:2.3:a:apache:log4j:2.14.1"},
+			{"referenceType":"purl","referenceLocator":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]}]}`,
+			sbomPackage{Name: "log4j-core", Version: "2.14.1", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}},
+		{"cyclonedx", `{"bomFormat":"CycloneDX","components":[{"group":"org.apache.logging.log4j","name":"log4j-core","version":"2.17.1","purl":"pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1"}]}`,
+			sbomPackage{Name: "org.apache.logging.log4j:log4j-core", Version: "2.17.1", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1"}},
+	}
+	for _, tt := range tests {
+		packages, err := parseSBOM([]byte(tt.document))
+		if err != nil || len(packages) != 1 || packages[0] != tt.expected {
+			t.Errorf("%s: parseSBOM() = %+v, %v, expected %+v", tt.name, packages, err, tt.expected)
+		}
+	}
+	if _, err := parseSBOM([]byte(`{"name":"not an sbom"}`)); err == nil {
+		t.Error("parseSBOM() accepted a document that is neither SPDX nor CycloneDX")
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		expected   bool
+	}{
+		{"2.14.1", "2.x", true},
+		{"2.14.1", "2", true},
+		{"1.2.17", "2.x", false},
+		{"2.14.1", "2.14.1", true},
+		{"2.14.10", "2.14.1", false},
+		{"2.14.1", ">=2.0.0,<2.17.1", true},
+		{"2.17.1", ">=2.0.0, <2.17.1", false},
+		{"2.9.0", ">= 2.0 < 2.10", true},
+		{"2.0-beta9", ">=2.0", false},
+		{"2.3.1.Final", "2.3.*", true},
+		{"3.0.0", "!=3.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := versionMatches(tt.version, tt.constraint); got != tt.expected {
+			t.Errorf("versionMatches(%q, %q) = %v, expected %v", tt.version, tt.constraint, got, tt.expected)
+		}
+	}
+	if err := parseVersionConstraint("~>2.0"); err == nil {
+		t.Error("parseVersionConstraint() accepted ~>2.0")
+	}
+}
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JobFunc is a unit of background work run by the JobScheduler
+type JobFunc func(ctx context.Context) error
+
+// JobStatus reports the state of a scheduled job
+type JobStatus struct {
+	Name     string
+	Interval time.Duration
+	LastRun  time.Time
+	NextRun  time.Time
+	LastErr  string
+	Runs     int
+	Failures int
+
+	// LeaderOnly jobs run on the elected replica only; Skipped counts the
+	// ticks this replica let pass because another replica leads
+	LeaderOnly bool
+	Skipped    int
+}
+
+type scheduledJob struct {
+	name       string
+	interval   time.Duration
+	run        JobFunc
+	leaderOnly bool
+	status     JobStatus
+}
+
+// JobScheduler runs registered jobs at fixed intervals in the background.
+// Each job runs at most once at a time; a run that overlaps its next tick
+// delays the tick rather than running concurrently.
+type JobScheduler struct {
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running bool
+	leading func() bool // nil runs leader-only jobs everywhere
+}
+
+// NewJobScheduler creates an empty scheduler
+func NewJobScheduler() *JobScheduler {
+	return &JobScheduler{jobs: make(map[string]*scheduledJob)}
+}
+
+// AddJob registers a job that runs on every replica. Jobs added after Start
+// begin immediately.
+func (js *JobScheduler) AddJob(name string, interval time.Duration, run JobFunc) {
+	js.addJob(&scheduledJob{name: name, interval: interval, run: run})
+}
+
+// AddLeaderJob registers a job that runs only while this replica is the
+// leader, so cluster-wide work runs once however many replicas serve tools
+func (js *JobScheduler) AddLeaderJob(name string, interval time.Duration, run JobFunc) {
+	js.addJob(&scheduledJob{name: name, interval: interval, run: run, leaderOnly: true})
+}
+
+func (js *JobScheduler) addJob(job *scheduledJob) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	job.status = JobStatus{Name: job.name, Interval: job.interval, LeaderOnly: job.leaderOnly}
+	js.jobs[job.name] = job
+	if js.running {
+		js.startJob(js.ctx, job)
+	}
+}
+
+// SetLeaderCheck makes leader-only jobs skip their ticks while leading
+// reports false
+func (js *JobScheduler) SetLeaderCheck(leading func() bool) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.leading = leading
+}
+
+// skipTick reports whether a leader-only job should let a tick pass
+func (js *JobScheduler) skipTick(job *scheduledJob) bool {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if !job.leaderOnly || js.leading == nil || js.leading() {
+		return false
+	}
+	job.status.Skipped++
+	job.status.NextRun = time.Now().Add(job.interval)
+	return true
+}
+
+// Start runs every registered job on its interval until ctx is done or Stop is called
+func (js *JobScheduler) Start(ctx context.Context) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.running {
+		return
+	}
+
+	js.ctx, js.cancel = context.WithCancel(ctx)
+	js.running = true
+	for _, job := range js.jobs {
+		js.startJob(js.ctx, job)
+	}
+}
+
+// Stop cancels all jobs and waits for running ones to return
+func (js *JobScheduler) Stop() {
+	js.mu.Lock()
+	if !js.running {
+		js.mu.Unlock()
+		return
+	}
+	js.cancel()
+	js.running = false
+	js.mu.Unlock()
+
+	js.wg.Wait()
+}
+
+// startJob must be called with js.mu held
+func (js *JobScheduler) startJob(ctx context.Context, job *scheduledJob) {
+	job.status.NextRun = time.Now().Add(job.interval)
+	js.wg.Add(1)
+	go func() {
+		defer js.wg.Done()
+		ticker := time.NewTicker(job.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !js.skipTick(job) {
+					js.runJob(ctx, job)
+				}
+			}
+		}
+	}()
+}
+
+// RunNow runs a job immediately, outside its schedule, even on a replica
+// that is not the leader
+func (js *JobScheduler) RunNow(ctx context.Context, name string) bool {
+	js.mu.Lock()
+	job, ok := js.jobs[name]
+	js.mu.Unlock()
+	if !ok {
+		return false
+	}
+	js.runJob(ctx, job)
+	return true
+}
+
+func (js *JobScheduler) runJob(ctx context.Context, job *scheduledJob) {
+	started := time.Now()
+	err := job.run(ctx)
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	job.status.LastRun = started
+	job.status.NextRun = time.Now().Add(job.interval)
+	job.status.Runs++
+	if err != nil {
+		job.status.Failures++
+		job.status.LastErr = err.Error()
+		logrus.WithError(err).Warnf("Scheduled job %s failed", job.name)
+	} else {
+		job.status.LastErr = ""
+		logrus.Debugf("Scheduled job %s completed in %s", job.name, time.Since(started).Round(time.Millisecond))
+	}
+}
+
+// Jobs returns the status of every registered job, sorted by name
+func (js *JobScheduler) Jobs() []JobStatus {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(js.jobs))
+	for _, job := range js.jobs {
+		statuses = append(statuses, job.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJobSchedulerRunsJobsOnInterval(t *testing.T) {
+	scheduler := NewJobScheduler()
+	var runs int32
+	scheduler.AddJob("tick", 10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+	scheduler.AddJob("broken", 10*time.Millisecond, func(ctx context.Context) error {
+		return errors.New("remote unreachable")
+	})
+
+	scheduler.Start(context.Background())
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&runs) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	scheduler.Stop()
+
+	if atomic.LoadInt32(&runs) < 2 {
+		t.Fatalf("job ran %d times, expected at least 2", runs)
+	}
+	stopped := atomic.LoadInt32(&runs)
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&runs) != stopped {
+		t.Errorf("job kept running after Stop")
+	}
+
+	jobs := scheduler.Jobs()
+	if len(jobs) != 2 || jobs[0].Name != "broken" || jobs[0].LastErr != "remote unreachable" || jobs[0].Failures == 0 {
+		t.Errorf("Jobs() = %+v, expected the broken job to report its failure", jobs)
+	}
+}
+
+func TestJobSchedulerRunNow(t *testing.T) {
+	scheduler := NewJobScheduler()
+	ran := false
+	scheduler.AddJob("export", time.Hour, func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	if !scheduler.RunNow(context.Background(), "export") || !ran {
+		t.Errorf("RunNow(export) did not run the job")
+	}
+	if scheduler.RunNow(context.Background(), "missing") {
+		t.Errorf("RunNow(missing) = true, expected false")
+	}
+}
+
+func TestJobSchedulerLeaderOnlyJobs(t *testing.T) {
+	scheduler := NewJobScheduler()
+	var leading atomic.Bool
+	scheduler.SetLeaderCheck(leading.Load)
+	var cleanups, reaps int32
+	scheduler.AddLeaderJob("cleanup", 5*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&cleanups, 1)
+		return nil
+	})
+	scheduler.AddJob("reaper", 5*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&reaps, 1)
+		return nil
+	})
+
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+	time.Sleep(40 * time.Millisecond)
+	if atomic.LoadInt32(&cleanups) != 0 || atomic.LoadInt32(&reaps) == 0 {
+		t.Fatalf("as a follower: cleanup ran %d times and reaper %d, expected only the reaper", cleanups, reaps)
+	}
+	jobs := scheduler.Jobs()
+	if !jobs[0].LeaderOnly || jobs[0].Skipped == 0 || jobs[1].LeaderOnly {
+		t.Errorf("Jobs() = %+v, expected the cleanup to be leader-only with skipped ticks", jobs)
+	}
+
+	leading.Store(true)
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&cleanups) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&cleanups) == 0 {
+		t.Error("the leader-only job did not run once this replica led")
+	}
+}
@@ -0,0 +1,317 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Secret values never appear in tool output unless a key is named in a
+// get_secret call with reveal=true, so answers that end up in chat
+// transcripts and LLM prompts can confirm a secret exists without leaking it.
+
+func secretKeys(secret *corev1.Secret) []string {
+	keys := make([]string, 0, len(secret.Data)+len(secret.StringData))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	for key := range secret.StringData {
+		if _, ok := secret.Data[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// redactedValue describes a value without showing it
+func redactedValue(value []byte) string {
+	return fmt.Sprintf("<redacted, %d bytes>", len(value))
+}
+
+// secretFingerprintKey keys value fingerprints; it is random per process, so
+// a fingerprint tells a changed value apart in a diff without allowing the
+// value to be guessed offline
+var secretFingerprintKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate secret fingerprint key: %v", err))
+	}
+	return key
+}()
+
+// fingerprintedValue describes a value without showing it, with a
+// fingerprint that differs when the value does
+func fingerprintedValue(value []byte) string {
+	mac := hmac.New(sha256.New, secretFingerprintKey)
+	mac.Write(value)
+	return fmt.Sprintf("<redacted, %d bytes, #%s>", len(value), hex.EncodeToString(mac.Sum(nil)[:4]))
+}
+
+// redactSecretObject replaces the values of a Secret in place, including the
+// copy kubectl keeps in the last-applied-configuration annotation; other
+// objects are left unchanged
+func redactSecretObject(obj *unstructured.Unstructured) {
+	if obj.GetKind() != "Secret" || obj.GroupVersionKind().Group != "" {
+		return
+	}
+	if data, ok := obj.Object["data"].(map[string]interface{}); ok {
+		for key, value := range data {
+			encoded, _ := value.(string)
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				decoded = []byte(encoded)
+			}
+			data[key] = fingerprintedValue(decoded)
+		}
+	}
+	if stringData, ok := obj.Object["stringData"].(map[string]interface{}); ok {
+		for key, value := range stringData {
+			text, _ := value.(string)
+			stringData[key] = fingerprintedValue([]byte(text))
+		}
+	}
+	if annotations := obj.GetAnnotations(); annotations[corev1.LastAppliedConfigAnnotation] != "" {
+		annotations[corev1.LastAppliedConfigAnnotation] = "<redacted>"
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// revealedValue shows a value, or its size when it is binary
+func revealedValue(value []byte) string {
+	if !utf8.Valid(value) {
+		return fmt.Sprintf("<binary, %d bytes>", len(value))
+	}
+	return string(value)
+}
+
+func (s *Server) initSecrets() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_secrets",
+			mcp.WithDescription("List Secrets with their type and key names; values are never shown"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list secrets from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=postgres")),
+			mcp.WithTitleAnnotation("Secrets: List"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listSecretsHandler)},
+		{Tool: mcp.NewTool("get_secret",
+			mcp.WithDescription("Show a Secret's type, keys and value sizes with values redacted; values of the keys listed in 'keys' are shown only with reveal=true"),
+			mcp.WithString("secret_name", mcp.Description("Name of the secret"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the secret (default: default)")),
+			mcp.WithString("reveal", mcp.Description("Show the values of the keys listed in 'keys' (true/false, default false)")),
+			mcp.WithString("keys", mcp.Description("Comma-separated keys to reveal, e.g. username (required with reveal=true)")),
+			mcp.WithTitleAnnotation("Secrets: Get"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getSecretHandler)},
+		{Tool: mcp.NewTool("create_secret",
+			mcp.WithDescription("Create a Secret from key-value pairs; the values are not echoed back"),
+			mcp.WithString("name", mcp.Description("Name of the secret"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace to create the secret in"), mcp.Required()),
+			mcp.WithString("data", mcp.Description("Data as JSON object of plain-text values (e.g., {\"username\": \"admin\", \"password\": \"s3cr3t\"})"), mcp.Required()),
+			mcp.WithString("type", mcp.Description("Secret type, e.g. Opaque (default), kubernetes.io/basic-auth, kubernetes.io/tls or kubernetes.io/dockerconfigjson")),
+			mcp.WithTitleAnnotation("Create: Secret"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.createSecretHandler)},
+	}
+}
+
+func (s *Server) listSecretsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	secrets, err := s.k8sClient.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list secrets in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	sort.Slice(secrets.Items, func(i, j int) bool {
+		if secrets.Items[i].Namespace != secrets.Items[j].Namespace {
+			return secrets.Items[i].Namespace < secrets.Items[j].Namespace
+		}
+		return secrets.Items[i].Name < secrets.Items[j].Name
+	})
+
+	result := "🔐 Secret List Results\n"
+	result += "======================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+	result += fmt.Sprintf("📦 Found %d secrets:\n", len(secrets.Items))
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		name := secret.Name
+		if namespace == metav1.NamespaceAll {
+			name = secret.Namespace + "/" + secret.Name
+		}
+		keys := secretKeys(secret)
+		result += fmt.Sprintf("• %s - %s, %d key(s), age %s\n", name, secret.Type, len(keys), formatAge(secret.CreationTimestamp.Time))
+		if len(keys) > 0 {
+			result += fmt.Sprintf("   Keys: %s\n", strings.Join(keys, ", "))
+		}
+	}
+	if len(secrets.Items) == 0 {
+		result += "📭 None found\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) getSecretHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "secret_name", ""))
+	if name == "" {
+		return mcp.NewToolResultText("❌ secret_name is required"), nil
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+	reveal := parseBoolString(mcp.ParseString(request, "reveal", "false"))
+	revealKeys := make(map[string]bool)
+	for _, key := range strings.Split(mcp.ParseString(request, "keys", ""), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			revealKeys[key] = true
+		}
+	}
+	if reveal && len(revealKeys) == 0 {
+		return mcp.NewToolResultText("❌ reveal=true needs the keys to reveal, e.g. keys=username; values are never revealed wholesale"), nil
+	}
+
+	secret, err := s.k8sClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to get secret %s/%s", namespace, name), err), nil
+	}
+
+	result := fmt.Sprintf("🔐 Secret %s/%s\n", secret.Namespace, secret.Name)
+	result += "====================\n\n"
+	result += fmt.Sprintf("Type: %s\n", secret.Type)
+	result += fmt.Sprintf("Age: %s\n", formatAge(secret.CreationTimestamp.Time))
+	if secret.Immutable != nil && *secret.Immutable {
+		result += "Immutable: true\n"
+	}
+	if len(secret.Labels) > 0 {
+		result += fmt.Sprintf("Labels: %s\n", strings.Join(formatLabels(secret.Labels), ", "))
+	}
+
+	keys := secretKeys(secret)
+	result += fmt.Sprintf("\n🔑 Data (%d key(s)):\n", len(keys))
+	if len(keys) == 0 {
+		result += "• None\n"
+	}
+	for _, key := range keys {
+		value, ok := secret.Data[key]
+		if !ok {
+			value = []byte(secret.StringData[key])
+		}
+		if reveal && revealKeys[key] {
+			result += fmt.Sprintf("• %s: %s\n", key, revealedValue(value))
+			delete(revealKeys, key)
+		} else {
+			result += fmt.Sprintf("• %s: %s\n", key, redactedValue(value))
+		}
+	}
+
+	if reveal && len(revealKeys) > 0 {
+		result += fmt.Sprintf("\n⚠️  Keys not found: %s\n", strings.Join(sortedKeys(revealKeys), ", "))
+	}
+	if !reveal {
+		result += "\n🔒 Values are redacted; pass reveal=true with keys to show specific values\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func formatLabels(labels map[string]string) []string {
+	formatted := make([]string, 0, len(labels))
+	for _, key := range sortedKeys(labels) {
+		formatted = append(formatted, key+"="+labels[key])
+	}
+	return formatted
+}
+
+func (s *Server) createSecretHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	name := mcp.ParseString(request, "name", "")
+	namespace := mcp.ParseString(request, "namespace", "default")
+	secretType := corev1.SecretType(mcp.ParseString(request, "type", string(corev1.SecretTypeOpaque)))
+	if name == "" {
+		return mcp.NewToolResultText("❌ Secret name is required"), nil
+	}
+
+	// Parse the data JSON; the error must not quote the input, which holds the values
+	var data map[string]string
+	if err := json.Unmarshal([]byte(mcp.ParseString(request, "data", "{}")), &data); err != nil {
+		return mcp.NewToolResultText("❌ Invalid JSON data format: expected an object of string values"), nil
+	}
+	if len(data) == 0 {
+		return mcp.NewToolResultText("❌ data must contain at least one key"), nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: secretType,
+		Data: make(map[string][]byte, len(data)),
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+
+	created, err := s.k8sClient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return toolError(ctx, "Failed to create Secret", err), nil
+	}
+	s.recordAction(ctx, "create_secret", objectReference("v1", "Secret", created), ReasonCreated, "Created Secret")
+
+	result := "🔐 Secret Created Successfully\n"
+	result += "==============================\n\n"
+	result += fmt.Sprintf("Name: %s\n", created.Name)
+	result += fmt.Sprintf("Namespace: %s\n", created.Namespace)
+	result += fmt.Sprintf("Type: %s\n", created.Type)
+	result += fmt.Sprintf("Keys: %s\n\n", strings.Join(secretKeys(created), ", "))
+	result += "✅ Secret created successfully in the cluster! Values are not shown."
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// ListSecretsHandler is a public wrapper for listSecretsHandler
+func (s *Server) ListSecretsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listSecretsHandler(ctx, request)
+}
+
+// GetSecretHandler is a public wrapper for getSecretHandler
+func (s *Server) GetSecretHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getSecretHandler(ctx, request)
+}
+
+// CreateSecretHandler is a public wrapper for createSecretHandler
+func (s *Server) CreateSecretHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.createSecretHandler(ctx, request)
+}
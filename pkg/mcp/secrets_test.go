@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func testSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "default"},
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("hunter2-very-secret"),
+			"keystore": {0xff, 0xfe, 0x00},
+		},
+	}
+}
+
+func TestGetSecretRedaction(t *testing.T) {
+	tests := []struct {
+		args    map[string]interface{}
+		want    []string
+		notWant []string
+	}{
+		{
+			args:    map[string]interface{}{},
+			want:    []string{"• password: <redacted, 19 bytes>", "• username: <redacted, 5 bytes>", "Values are redacted"},
+			notWant: []string{"hunter2", "admin"},
+		},
+		{
+			// keys without reveal=true reveal nothing
+			args:    map[string]interface{}{"keys": "username"},
+			want:    []string{"• username: <redacted, 5 bytes>"},
+			notWant: []string{"admin"},
+		},
+		{
+			args:    map[string]interface{}{"reveal": "true", "keys": "username, missing"},
+			want:    []string{"• username: admin", "• password: <redacted, 19 bytes>", "Keys not found: missing"},
+			notWant: []string{"hunter2"},
+		},
+		{
+			args:    map[string]interface{}{"reveal": "true", "keys": "keystore"},
+			want:    []string{"• keystore: <binary, 3 bytes>"},
+			notWant: []string{"hunter2", "admin"},
+		},
+		{
+			args:    map[string]interface{}{"reveal": "true"},
+			want:    []string{"❌ reveal=true needs the keys to reveal"},
+			notWant: []string{"hunter2", "admin"},
+		},
+	}
+
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(testSecret())}
+	for _, tt := range tests {
+		args := map[string]interface{}{"secret_name": "db-credentials"}
+		for key, value := range tt.args {
+			args[key] = value
+		}
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := s.GetSecretHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("GetSecretHandler(%v) error = %v", tt.args, err)
+		}
+		text := resultText(result)
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("GetSecretHandler(%v) output missing %q:\n%s", tt.args, want, text)
+			}
+		}
+		for _, leaked := range tt.notWant {
+			if strings.Contains(text, leaked) {
+				t.Errorf("GetSecretHandler(%v) leaked %q:\n%s", tt.args, leaked, text)
+			}
+		}
+	}
+}
+
+func TestListSecrets(t *testing.T) {
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(testSecret())}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "default"}
+	result, err := s.ListSecretsHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ListSecretsHandler error = %v", err)
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "db-credentials - Opaque, 3 key(s)") || !strings.Contains(text, "Keys: keystore, password, username") {
+		t.Errorf("ListSecretsHandler output missing the secret summary:\n%s", text)
+	}
+	if strings.Contains(text, "hunter2") || strings.Contains(text, "admin") {
+		t.Errorf("ListSecretsHandler leaked a value:\n%s", text)
+	}
+}
+
+func TestCreateSecret(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	s := &Server{config: &Config{}, k8sClient: client}
+
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"token": "abc123secret"}`, "Keys: token"},
+		{`{"token": "abc123secret"`, "❌ Invalid JSON data format"},
+		{`{}`, "❌ data must contain at least one key"},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"name": "api-token", "namespace": "default", "data": tt.data}
+		result, err := s.CreateSecretHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("CreateSecretHandler(%s) error = %v", tt.data, err)
+		}
+		text := resultText(result)
+		if !strings.Contains(text, tt.want) {
+			t.Errorf("CreateSecretHandler(%s) = %q, expected %q", tt.data, text, tt.want)
+		}
+		if strings.Contains(text, "abc123secret") {
+			t.Errorf("CreateSecretHandler(%s) echoed the value:\n%s", tt.data, text)
+		}
+	}
+
+	secret, err := client.CoreV1().Secrets("default").Get(context.Background(), "api-token", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("secret not created: %v", err)
+	}
+	if string(secret.Data["token"]) != "abc123secret" || secret.Type != corev1.SecretTypeOpaque {
+		t.Errorf("created secret = %s %v, expected Opaque with the token", secret.Type, secret.Data)
+	}
+}
@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// maxInternalErrors is how many recent tool errors are kept for self_diagnose
+	maxInternalErrors = 20
+
+	selfDiagnoseCheckTimeout = 15 * time.Second
+)
+
+// LLMProbe checks that the LLM provider answers, e.g. with a minimal prompt
+type LLMProbe func(ctx context.Context) error
+
+// InternalError is a tool failure kept for self-diagnostics
+type InternalError struct {
+	Time   time.Time
+	Source string
+	Error  string
+}
+
+// errorLog keeps the most recent internal errors
+type errorLog struct {
+	mu      sync.Mutex
+	entries []InternalError
+}
+
+func (l *errorLog) record(source string, err error) {
+	if err == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, InternalError{Time: time.Now(), Source: source, Error: err.Error()})
+	if len(l.entries) > maxInternalErrors {
+		l.entries = l.entries[len(l.entries)-maxInternalErrors:]
+	}
+}
+
+// recent returns the errors newest first
+func (l *errorLog) recent() []InternalError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]InternalError, len(l.entries))
+	for i, entry := range l.entries {
+		entries[len(l.entries)-1-i] = entry
+	}
+	return entries
+}
+
+// selfAccessChecks are the permissions the tools rely on, checked cluster-wide
+var selfAccessChecks = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Resource: "pods"},
+	{Verb: "get", Resource: "pods", Subresource: "log"},
+	{Verb: "create", Resource: "pods", Subresource: "exec"},
+	{Verb: "create", Resource: "pods", Subresource: "portforward"},
+	{Verb: "list", Resource: "events"},
+	{Verb: "list", Resource: "nodes"},
+	{Verb: "create", Resource: "namespaces"},
+	{Verb: "patch", Group: "apps", Resource: "deployments"},
+	{Verb: "list", Group: "config.openshift.io", Resource: "clusteroperators"},
+}
+
+func accessCheckName(attributes authorizationv1.ResourceAttributes) string {
+	resource := attributes.Resource
+	if attributes.Subresource != "" {
+		resource += "/" + attributes.Subresource
+	}
+	if attributes.Group != "" {
+		resource += "." + attributes.Group
+	}
+	return fmt.Sprintf("%s %s", attributes.Verb, resource)
+}
+
+// SetLLMProbe registers the check self_diagnose uses to measure LLM latency
+func (s *Server) SetLLMProbe(probe LLMProbe) {
+	s.llmProbe = probe
+}
+
+// artifactDirs returns the directories the server writes artifacts to
+func (s *Server) artifactDirs() []string {
+	var dirs []string
+	if s.diagnosticCollector != nil {
+		dirs = append(dirs, s.diagnosticCollector.WorkingDir())
+	}
+	dirs = append(dirs, s.baselineDir(), s.transcriptDir())
+	if s.config != nil && s.config.Inventory != nil && s.config.Inventory.ExportDir != "" {
+		dirs = append(dirs, s.config.Inventory.ExportDir)
+	} else {
+		dirs = append(dirs, defaultInventoryDir)
+	}
+	if s.gitManager != nil && s.gitManager.IsEnabled() {
+		dirs = append(dirs, s.gitManager.config.RepoPath)
+	}
+
+	// Skip directories nested in one already listed, so nothing is counted twice
+	var unique []string
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		nested := false
+		for _, listed := range unique {
+			if dir == listed || strings.HasPrefix(dir, listed+string(filepath.Separator)) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			unique = append(unique, dir)
+		}
+	}
+	return unique
+}
+
+// dirUsage sums the size and number of files under a directory
+func dirUsage(dir string) (int64, int, error) {
+	var size int64
+	files := 0
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	return size, files, err
+}
+
+func (s *Server) selfDiagnoseHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	checkLLM := parseBoolString(mcp.ParseString(request, "check_llm", "true"))
+	checkGit := parseBoolString(mcp.ParseString(request, "check_git", "true"))
+	problems := 0
+
+	result := "🩺 MCP Server Self-Diagnostics\n"
+	result += "==============================\n\n"
+	profile := "unknown"
+	if s.config != nil {
+		profile = s.config.Profile
+	}
+	result += fmt.Sprintf("Profile: %s, %d tools registered\n\n", valueOrNone(profile), s.toolCount())
+
+	// Kubernetes connection
+	result += "☸️  Kubernetes API:\n"
+	if s.k8sClient == nil {
+		result += "❌ Client not initialized; check the kubeconfig or in-cluster service account\n"
+		problems++
+	} else {
+		started := time.Now()
+		version, err := s.k8sClient.Discovery().ServerVersion()
+		if err != nil {
+			result += fmt.Sprintf("❌ Unreachable: %v\n", err)
+			problems++
+		} else {
+			result += fmt.Sprintf("✅ Connected to %s in %s\n", version.GitVersion, time.Since(started).Round(time.Millisecond))
+		}
+		if s.dynamicClient == nil || s.restMapper == nil {
+			result += "⚠️  Dynamic client unavailable; generic resource tools will fail\n"
+			problems++
+		}
+	}
+
+	// Identity and RBAC
+	if s.k8sClient != nil {
+		result += "\n🔐 Identity and RBAC:\n"
+		review, err := s.k8sClient.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+		if err == nil && review.Status.UserInfo.Username != "" {
+			result += fmt.Sprintf("Identity: %s\n", review.Status.UserInfo.Username)
+		} else {
+			result += "Identity: unknown (SelfSubjectReview not available)\n"
+		}
+		for _, attributes := range selfAccessChecks {
+			access, err := s.k8sClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+			}, metav1.CreateOptions{})
+			switch {
+			case err != nil:
+				result += fmt.Sprintf("⚠️  %s: check failed: %v\n", accessCheckName(attributes), err)
+			case access.Status.Allowed:
+				result += fmt.Sprintf("✅ %s\n", accessCheckName(attributes))
+			default:
+				result += fmt.Sprintf("🚫 %s\n", accessCheckName(attributes))
+			}
+		}
+	}
+
+	// Git remote
+	result += "\n📦 Git:\n"
+	switch {
+	case s.gitManager == nil || !s.gitManager.IsEnabled():
+		result += "ℹ️  Git integration is disabled\n"
+	case !checkGit:
+		result += "⏭️  Remote check skipped\n"
+	default:
+		gitCtx, cancel := context.WithTimeout(ctx, selfDiagnoseCheckTimeout)
+		latency, err := s.gitManager.CheckRemote(gitCtx)
+		cancel()
+		switch {
+		case errors.Is(err, ErrNoGitRemote):
+			result += "ℹ️  No remote configured; action records are committed locally only\n"
+		case err != nil:
+			result += fmt.Sprintf("❌ %v\n", err)
+			problems++
+		default:
+			result += fmt.Sprintf("✅ Remote reachable in %s\n", latency.Round(time.Millisecond))
+		}
+	}
+
+	// LLM provider
+	result += "\n🤖 LLM Provider:\n"
+	switch {
+	case s.llmProbe == nil:
+		result += "ℹ️  No LLM provider configured\n"
+	case !checkLLM:
+		result += "⏭️  Latency check skipped\n"
+	default:
+		llmCtx, cancel := context.WithTimeout(ctx, selfDiagnoseCheckTimeout)
+		started := time.Now()
+		err := s.llmProbe(llmCtx)
+		cancel()
+		if err != nil {
+			result += fmt.Sprintf("❌ Probe failed after %s: %v\n", time.Since(started).Round(time.Millisecond), err)
+			problems++
+		} else {
+			result += fmt.Sprintf("✅ Responded in %s\n", time.Since(started).Round(time.Millisecond))
+		}
+	}
+
+	// Artifact store
+	result += "\n💾 Artifact Store:\n"
+	for _, dir := range s.artifactDirs() {
+		size, files, err := dirUsage(dir)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			result += fmt.Sprintf("• %s: not created yet\n", dir)
+		case err != nil:
+			result += fmt.Sprintf("⚠️  %s: %v\n", dir, err)
+		default:
+			result += fmt.Sprintf("• %s: %.1f MB in %d files\n", dir, float64(size)/(1024*1024), files)
+		}
+	}
+
+	// Circuit breakers and background jobs
+	var breakerIssues []string
+	for _, status := range s.BreakerStatuses() {
+		if status.State != BreakerClosed {
+			breakerIssues = append(breakerIssues, fmt.Sprintf("%s is %s", status.Name, status.State))
+		}
+	}
+	if len(breakerIssues) > 0 {
+		result += "\n🔌 Circuit Breakers:\n"
+		for _, issue := range breakerIssues {
+			result += fmt.Sprintf("🚫 %s\n", issue)
+		}
+		problems += len(breakerIssues)
+	}
+	if s.scheduler != nil {
+		var failing []JobStatus
+		for _, job := range s.scheduler.Jobs() {
+			if job.LastErr != "" {
+				failing = append(failing, job)
+			}
+		}
+		if len(failing) > 0 {
+			result += "\n🗓️  Failing Scheduled Jobs:\n"
+			for _, job := range failing {
+				result += fmt.Sprintf("❌ %s (last run %s): %s\n", job.Name, formatTime(ctx, job.LastRun), job.LastErr)
+			}
+			problems += len(failing)
+		}
+	}
+
+	// Recent internal errors
+	result += "\n📋 Recent Internal Errors:\n"
+	if recent := s.internalErrors.recent(); len(recent) == 0 {
+		result += "✅ None recorded\n"
+	} else {
+		for _, entry := range recent {
+			result += fmt.Sprintf("• %s %s: %s\n", formatTime(ctx, entry.Time), entry.Source, entry.Error)
+		}
+	}
+
+	if problems == 0 {
+		result += "\n✅ No problems found"
+	} else {
+		result += fmt.Sprintf("\n⚠️  %d problem(s) found; include this report when contacting support", problems)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// SelfDiagnoseHandler is a public wrapper for selfDiagnoseHandler
+func (s *Server) SelfDiagnoseHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.selfDiagnoseHandler(ctx, request)
+}
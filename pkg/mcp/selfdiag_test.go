@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestErrorLog(t *testing.T) {
+	var log errorLog
+	log.record("ignored", nil)
+	for i := 0; i < maxInternalErrors+5; i++ {
+		log.record("list_pods", fmt.Errorf("error %d", i))
+	}
+
+	recent := log.recent()
+	if len(recent) != maxInternalErrors {
+		t.Fatalf("errorLog kept %d entries, expected %d", len(recent), maxInternalErrors)
+	}
+	if recent[0].Error != fmt.Sprintf("error %d", maxInternalErrors+4) || recent[len(recent)-1].Error != "error 5" {
+		t.Errorf("errorLog order = %s ... %s, expected newest first", recent[0].Error, recent[len(recent)-1].Error)
+	}
+}
+
+func TestArtifactDirs(t *testing.T) {
+	s := &Server{config: &Config{
+		BaselineDir: "/tmp/diagnostics/baselines",
+		Inventory:   &InventoryConfig{ExportDir: "/var/inventory"},
+	}}
+
+	expected := []string{"/tmp/diagnostics/baselines", "/tmp/diagnostics/transcripts", "/var/inventory"}
+	if dirs := s.artifactDirs(); !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("artifactDirs() = %v, expected %v", dirs, expected)
+	}
+
+	s.config.Inventory.ExportDir = "/tmp/diagnostics/baselines/../inventory-x"
+	s.config.BaselineDir = "/tmp/diagnostics"
+	expected = []string{"/tmp/diagnostics"}
+	if dirs := s.artifactDirs(); !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("artifactDirs() with nested dirs = %v, expected %v", dirs, expected)
+	}
+}
+
+func TestSelfDiagnose(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "baseline.json"), make([]byte, 1024), 0644)
+
+	client := kubefake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Verb == "list"
+		return true, review, nil
+	})
+
+	s := &Server{
+		config:     &Config{Profile: "sre", BaselineDir: dir, Inventory: &InventoryConfig{ExportDir: dir}},
+		k8sClient:  client,
+		gitManager: NewGitManager(nil),
+		llmProbe:   func(ctx context.Context) error { return errors.New("quota exceeded") },
+	}
+	s.internalErrors.record("get_pod_logs", errors.New("connection refused"))
+
+	request := mcp.CallToolRequest{}
+	result, _ := s.selfDiagnoseHandler(context.Background(), request)
+	text := resultText(result)
+	for _, expected := range []string{
+		"✅ Connected to",
+		"✅ list pods",
+		"🚫 create pods/exec",
+		"🚫 patch deployments.apps",
+		"Git integration is disabled",
+		"❌ Probe failed after",
+		"quota exceeded",
+		dir + ": 0.0 MB in 1 files",
+		"get_pod_logs: connection refused",
+		"problem(s) found",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("self_diagnose result missing %q:\n%s", expected, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"check_llm": "false"}
+	result, _ = s.selfDiagnoseHandler(context.Background(), request)
+	if text := resultText(result); !strings.Contains(text, "Latency check skipped") {
+		t.Errorf("self_diagnose with check_llm=false = %q", text)
+	}
+}
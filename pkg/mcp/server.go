@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,12 +17,15 @@ import (
 	"github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
 )
 
 type Server struct {
@@ -29,16 +33,102 @@ type Server struct {
 	config              *Config
 	kubeconfig          string
 	k8sClient           kubernetes.Interface
+	restConfig          *rest.Config
+	podExec             podExecFunc     // overrides remoteExec, e.g. in tests
+	portForward         portForwardFunc // overrides remotePortForward, e.g. in tests
+	portForwards        portForwardSessions
+	sessions            sessionRegistry // open watches, port-forwards, exec sessions and timed-out tool calls
+	dynamicClient       dynamic.Interface
+	restMapper          meta.RESTMapper
 	gitManager          *GitManager
 	yamlGenerator       *YAMLGenerator
 	diagnosticCollector *diagnostics.DiagnosticCollector
 	analysisEngine      *diagnostics.AnalysisEngine
+	toolsMu             sync.RWMutex // guards tools, toolDefs and toolSets, which change at runtime
+	tools               map[string]server.ToolHandlerFunc
+	toolDefs            map[string]mcp.Tool
+	toolSets            map[string]*ToolSet
+	breakers            map[string]*CircuitBreaker
+	toolTimeouts        map[string]time.Duration
+	defaultTimeout      time.Duration
+	scheduler           *JobScheduler
+	notifier            *NotificationRouter
+	llmProbe            LLMProbe
+	internalErrors      errorLog
+	profiler            *Profiler
+	usage               *UsageTracker
+	stepOutputs         *stepOutputStore
+	aliasUsage          aliasUsage
+	elevations          elevationRequests    // forbidden calls grant_elevation can retry
+	remediations        remediationApprovals // fixes auto-remediation left for approval
+	warRooms            warRooms             // incident war rooms refreshing in the background
+	runbooks            runbookIndex         // runbooks retrieved for prompts resembling their incidents
+	store               store.Store          // state shared with other replicas, nil for none
+	leader              leaderState          // whether this replica runs leader-only jobs
 }
 
 type Config struct {
-	Profile   string     `json:"profile"`
-	Debug     bool       `json:"debug"`
-	GitConfig *GitConfig `json:"git_config"`
+	Profile        string                      `json:"profile"`
+	Debug          bool                        `json:"debug"`
+	ReadOnly       bool                        `json:"read_only"` // only tools with a read-only hint may run
+	GitConfig      *GitConfig                  `json:"git_config"`
+	Resilience     *ResilienceConfig           `json:"resilience"`
+	AnalysisLimits *diagnostics.AnalysisLimits `json:"analysis_limits"`
+
+	// AnalysisTimezone is the zone for log timestamps without an offset and
+	// ClockSkew maps an origin (node, file or namespace) to how far its clock
+	// runs ahead, e.g. "worker-1": "90s"
+	AnalysisTimezone string            `json:"analysis_timezone"`
+	ClockSkew        map[string]string `json:"clock_skew"`
+
+	// AnalysisLocales lists log pattern packs (de, fr, es, pt, ru, ja, zh)
+	// applied to every log in addition to each log's detected language
+	AnalysisLocales []string `json:"analysis_locales"`
+
+	// AnalysisRuleDir holds rule packs of custom log patterns loaded at
+	// start and by reload_analysis_rules
+	AnalysisRuleDir string `json:"analysis_rule_dir"`
+
+	// OutputTimezone and OutputTimeFormat (default, rfc3339 or relative) set
+	// how tool output shows timestamps; callers override them per request
+	OutputTimezone   string `json:"output_timezone"`
+	OutputTimeFormat string `json:"output_time_format"`
+
+	// ActionEvents emits a Kubernetes Event on every resource a tool changes;
+	// ActionAnnotations also stamps the resource with the last action and
+	// ActionRecords stores each change as an ActionRecord custom resource
+	ActionEvents      bool `json:"action_events"`
+	ActionAnnotations bool `json:"action_annotations"`
+	ActionRecords     bool `json:"action_records"`
+
+	// ChangeFreezeConfigMap is the "namespace/name" of the ConfigMap that
+	// freezes changes cluster-wide (default openshift-mcp/change-freeze)
+	ChangeFreezeConfigMap string `json:"change_freeze_configmap"`
+
+	// BaselineDir is where capture_baseline stores known-good snapshots
+	BaselineDir string `json:"baseline_dir"`
+
+	// TranscriptDir is where exported chat session transcripts are stored
+	TranscriptDir string `json:"transcript_dir"`
+
+	// ToolSetDir holds runbook tool set definitions loaded at start and by
+	// reload_tool_sets, without restarting the server
+	ToolSetDir string `json:"tool_set_dir"`
+
+	// TenantTemplateDir holds the tenant templates create_namespace can
+	// provision from, in addition to the built-in default
+	TenantTemplateDir string `json:"tenant_template_dir"`
+
+	Diagnostics    *DiagnosticsConfig    `json:"diagnostics"`
+	Sessions       *SessionConfig        `json:"sessions"`
+	LeaderElection *LeaderElectionConfig `json:"leader_election"`
+	MustGather     *MustGatherConfig     `json:"must_gather"`
+	Inventory      *InventoryConfig      `json:"inventory"`
+	Notifications  *NotificationConfig   `json:"notifications"`
+	Monitoring     *MonitoringConfig     `json:"monitoring"`
+	Usage          *UsageConfig          `json:"usage"`
+
+	AutoRemediation *AutoRemediationConfig `json:"auto_remediation"`
 }
 
 func NewServer(config *Config, kubeconfig string) *Server {
@@ -58,10 +148,39 @@ func NewServer(config *Config, kubeconfig string) *Server {
 	// Initialize YAML generator
 	s.yamlGenerator = NewYAMLGenerator()
 
+	// Initialize per-tool timeouts and circuit breakers
+	s.initResilience(config.Resilience)
+	s.profiler = NewProfiler()
+	s.usage = NewUsageTracker()
+	s.stepOutputs = newStepOutputStore()
+
 	// Initialize diagnostic components
 	logger := logrus.StandardLogger()
-	s.diagnosticCollector = diagnostics.NewDiagnosticCollector(logger, "/tmp/diagnostics")
+	s.diagnosticCollector = diagnostics.NewDiagnosticCollector(logger, s.diagnosticsBaseDir())
 	s.analysisEngine = diagnostics.NewAnalysisEngine(logger)
+	if config.AnalysisLimits != nil {
+		s.analysisEngine.SetLimits(*config.AnalysisLimits)
+	}
+	if config.AnalysisTimezone != "" {
+		if loc, err := time.LoadLocation(config.AnalysisTimezone); err != nil {
+			logrus.WithError(err).Warnf("Invalid analysis timezone %q, using UTC", config.AnalysisTimezone)
+		} else {
+			s.analysisEngine.SetTimezone(loc)
+		}
+	}
+	if _, err := ParseTimePreferences(config.OutputTimezone, config.OutputTimeFormat); err != nil {
+		logrus.WithError(err).Warn("Invalid output time preferences, using server-local time")
+	}
+	for origin, value := range config.ClockSkew {
+		offset, err := time.ParseDuration(value)
+		if err != nil {
+			logrus.WithError(err).Warnf("Invalid clock skew %q for %s", value, origin)
+			continue
+		}
+		s.analysisEngine.SetClockSkew(origin, offset)
+	}
+	s.analysisEngine.SetLocales(config.AnalysisLocales)
+	s.loadAnalysisRules()
 
 	// Initialize Kubernetes client
 	var k8sConfig *rest.Config
@@ -79,6 +198,8 @@ func NewServer(config *Config, kubeconfig string) *Server {
 		logrus.WithError(err).Warn("Failed to load Kubernetes config, client will be unavailable")
 		s.k8sClient = nil
 	} else {
+		// Count API requests and their latency against the tool call making them
+		k8sConfig.Wrap(wrapProfilingTransport)
 		s.k8sClient, err = kubernetes.NewForConfig(k8sConfig)
 		if err != nil {
 			logrus.WithError(err).Warn("Failed to create Kubernetes client")
@@ -86,24 +207,82 @@ func NewServer(config *Config, kubeconfig string) *Server {
 		} else {
 			logrus.Info("Kubernetes client initialized successfully")
 		}
+		s.restConfig = k8sConfig
+		s.initDynamicClient(k8sConfig)
 	}
 
+	// Initialize background jobs; they run once StartScheduler is called
+	s.scheduler = NewJobScheduler()
+	s.scheduler.SetLeaderCheck(s.leader.isLeader)
+	s.initInventorySchedule(config.Inventory)
+	s.initGitSyncSchedule(config.GitConfig)
+	s.notifier = NewNotificationRouter(config.Notifications)
+	s.initHealthCheckSchedule(config.Notifications)
+	s.initAutoRemediationSchedule(config.AutoRemediation)
+	s.initArtifactRetention(config.Diagnostics)
+	s.initSessionReaper(config.Sessions)
+
 	profile := ProfileFromString(config.Profile)
 	tools := profile.GetTools(s)
 
 	s.server = server.NewMCPServer(
 		"OpenShift MCP",
-		"1.0.0",
+		Version,
 	)
 
-	// Add tools to server
-	for _, tool := range tools {
-		s.server.AddTool(tool.Tool, tool.Handler)
-	}
+	// Add tools to server, guarded by the policy mode, their timeout and circuit
+	// breaker, and profiled
+	s.tools = make(map[string]server.ToolHandlerFunc)
+	s.toolDefs = make(map[string]mcp.Tool)
+	s.toolSets = make(map[string]*ToolSet)
+	s.server.AddTools(s.addTools(tools)...)
+	s.registerToolAliases()
+	s.loadToolSetDir()
+	s.indexRunbooks()
 
 	return s
 }
 
+// CallTool invokes a registered tool by name through the same timeout and
+// circuit breaker wrapping used for MCP clients.
+func (s *Server) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.toolsMu.RLock()
+	handler, ok := s.tools[request.Params.Name]
+	s.toolsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("tool '%s' is not registered in profile '%s'", request.Params.Name, s.config.Profile)
+	}
+	return handler(ctx, request)
+}
+
+// HasTool reports whether a tool is registered in the active profile
+func (s *Server) HasTool(name string) bool {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	_, ok := s.tools[name]
+	return ok
+}
+
+// CallWithResilience runs a handler that is not registered in the active
+// profile under the same policy, timeout and circuit breaker as registered tools.
+func (s *Server) CallWithResilience(ctx context.Context, request mcp.CallToolRequest, handler server.ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	return s.withPolicy(request.Params.Name, s.withUsage(request.Params.Name, s.withProfiling(request.Params.Name, s.withResilience(request.Params.Name, handler))))(ctx, request)
+}
+
+// StartScheduler runs scheduled background jobs until ctx is done. With
+// leader election on, leader-only jobs run only while this replica leads.
+func (s *Server) StartScheduler(ctx context.Context) {
+	s.startLeaderElection(ctx)
+	s.scheduler.Start(ctx)
+}
+
+// StopScheduler stops scheduled background jobs, waits for running ones and
+// releases the leader lease
+func (s *Server) StopScheduler() {
+	s.scheduler.Stop()
+	s.stopLeaderElection()
+}
+
 func (s *Server) ServeStdio() error {
 	return server.ServeStdio(s.server)
 }
@@ -126,20 +305,29 @@ func (s *Server) initOpenShiftTools() []server.ServerTool {
 		), Handler: server.ToolHandlerFunc(s.OpenShiftDiagnose)},
 
 		{Tool: mcp.NewTool("openshift_must_gather",
-			mcp.WithDescription("Collect OpenShift must-gather data for debugging"),
-			mcp.WithString("image", mcp.Description("Must-gather image to use")),
-			mcp.WithString("dest_dir", mcp.Description("Destination directory for must-gather data")),
+			mcp.WithDescription("Collect OpenShift must-gather data for debugging with oc adm must-gather. Runs in the background and returns a job ID to poll with must_gather_status"),
+			mcp.WithString("image", mcp.Description("Must-gather image(s) to use, comma-separated (default the OpenShift must-gather image)")),
+			mcp.WithString("dest_dir", mcp.Description("Empty or new destination directory for must-gather data (default a directory per job)")),
+			mcp.WithString("node_name", mcp.Description("Node to run the gather pod on")),
+			mcp.WithString("since", mcp.Description("Only collect logs newer than this duration, e.g. 2h")),
+			mcp.WithString("timeout", mcp.Description("Gather timeout passed to oc, e.g. 30m")),
+			mcp.WithString("wait", mcp.Description("Wait for the collection to finish, reporting progress (true/false, default false)")),
+			mcp.WithString("mode", mcp.Description("oc to run oc adm must-gather, job to run it as an in-cluster Job without the oc binary, auto (default) to use oc when installed")),
 			mcp.WithTitleAnnotation("OpenShift: Must Gather"),
-			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.openShiftMustGather)},
 
 		{Tool: mcp.NewTool("collect_sosreport",
-			mcp.WithDescription("Collect sosreport from a specific node for system-level troubleshooting"),
+			mcp.WithDescription("Collect sosreport from a specific node for system-level troubleshooting. Runs sos report in a temporary privileged debug pod on the node and streams the archive back"),
 			mcp.WithString("node_name", mcp.Description("Name of the node to collect sosreport from"), mcp.Required()),
 			mcp.WithString("output_dir", mcp.Description("Directory to store the sosreport")),
+			mcp.WithString("plugins", mcp.Description("Comma-separated sos plugins to run (e.g. crio,networking,openshift). Defaults to all enabled plugins")),
+			mcp.WithString("timeout", mcp.Description("Maximum time for the collection (e.g. 10m, default: 15m)")),
+			mcp.WithString("image", mcp.Description("Support tools image for the debug pod (default: "+diagnostics.DefaultSosReportImage+")")),
+			mcp.WithBoolean("compressed", mcp.Description("Package the collection into a single tar.gz with an index.json that the analyze_* tools read directly")),
 			mcp.WithTitleAnnotation("Diagnostics: SOS Report"),
-			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.collectSosReportHandler)},
 
@@ -151,17 +339,22 @@ func (s *Server) initOpenShiftTools() []server.ServerTool {
 			mcp.WithString("duration", mcp.Description("Capture duration (e.g., 60s, 5m)")),
 			mcp.WithString("filter", mcp.Description("Tcpdump filter expression")),
 			mcp.WithString("output_dir", mcp.Description("Directory to store the capture")),
+			mcp.WithBoolean("compressed", mcp.Description("Package the collection into a single tar.gz with an index.json that the analyze_* tools read directly")),
 			mcp.WithTitleAnnotation("Diagnostics: Network Capture"),
-			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.collectTcpdumpHandler)},
 
 		{Tool: mcp.NewTool("collect_logs",
-			mcp.WithDescription("Collect comprehensive logs from pods, containers, and system components"),
+			mcp.WithDescription("Collect comprehensive logs from pods, containers, and system components. Without pod_name, collects every container of the pods in the namespace matching label_selector in parallel and writes a manifest of what was collected"),
 			mcp.WithString("pod_name", mcp.Description("Specific pod to collect logs from")),
 			mcp.WithString("namespace", mcp.Description("Namespace to collect logs from")),
+			mcp.WithString("label_selector", mcp.Description("Only collect pods matching this label selector, e.g. app=payments (namespace mode)")),
 			mcp.WithBoolean("include_previous", mcp.Description("Include previous container logs")),
+			mcp.WithString("since", mcp.Description("Only collect logs newer than this duration, e.g. 1h (namespace mode)")),
+			mcp.WithString("workers", mcp.Description("Number of logs fetched in parallel (namespace mode, default: 4, max: 32)")),
 			mcp.WithString("output_dir", mcp.Description("Directory to store the logs")),
+			mcp.WithBoolean("compressed", mcp.Description("Package the collection into a single tar.gz with an index.json that the analyze_* tools read directly")),
 			mcp.WithTitleAnnotation("Diagnostics: Log Collection"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
@@ -169,36 +362,40 @@ func (s *Server) initOpenShiftTools() []server.ServerTool {
 
 		{Tool: mcp.NewTool("analyze_must_gather",
 			mcp.WithDescription("Analyze collected must-gather data to identify issues and provide recommendations"),
-			mcp.WithString("must_gather_path", mcp.Description("Path to the must-gather directory"), mcp.Required()),
+			mcp.WithString("must_gather_path", mcp.Description("Path to the must-gather directory or a bundle from a compressed collection"), mcp.Required()),
+			mcp.WithString("export", mcp.Description(exportParamDescription)),
 			mcp.WithTitleAnnotation("Analysis: Must Gather"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.analyzeMustGatherHandler)},
 
+		{Tool: mcp.NewTool("analyze_must_gather_diff",
+			mcp.WithDescription("Compare two must-gathers, e.g. taken before and after an upgrade or incident, and report operators that became Degraded or unavailable, changed node conditions and newly failing pods"),
+			mcp.WithString("before_path", mcp.Description("Path to the earlier must-gather directory or bundle"), mcp.Required()),
+			mcp.WithString("after_path", mcp.Description("Path to the later must-gather directory or bundle"), mcp.Required()),
+			mcp.WithTitleAnnotation("Analysis: Must Gather Diff"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.analyzeMustGatherDiffHandler)},
+
 		{Tool: mcp.NewTool("analyze_logs",
-			mcp.WithDescription("Analyze log files to identify errors, patterns, and issues"),
-			mcp.WithString("log_path", mcp.Description("Path to log file or directory"), mcp.Required()),
+			mcp.WithDescription("Analyze log files to identify errors, patterns, and issues. Reads plaintext, .gz and .zst logs and systemd journal export/JSON output"),
+			mcp.WithString("log_path", mcp.Description("Path to log file, directory or a bundle from a compressed collection"), mcp.Required()),
+			mcp.WithString("export", mcp.Description(exportParamDescription)),
 			mcp.WithTitleAnnotation("Analysis: Logs"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.analyzeLogsHandler)},
 
 		{Tool: mcp.NewTool("analyze_tcpdump",
-			mcp.WithDescription("Analyze packet capture files to identify network issues"),
-			mcp.WithString("pcap_path", mcp.Description("Path to the pcap file"), mcp.Required()),
+			mcp.WithDescription("Analyze a pcap or pcapng capture: protocol breakdown, top talkers, TCP retransmissions, resets, refused and unanswered connections, handshake latency, idle and keep-alive timeouts behind sporadic 502/504s, and DNS failures"),
+			mcp.WithString("pcap_path", mcp.Description("Path to the pcap or pcapng file, or a bundle holding one, e.g. from collect_tcpdump"), mcp.Required()),
+			mcp.WithString("mode", mcp.Description("quick (default, built-in decoder) or deep (adds tshark's zero window, missing segment, reordering, TLS alert and DNS findings; needs tshark)")),
+			mcp.WithString("export", mcp.Description(exportParamDescription)),
 			mcp.WithTitleAnnotation("Analysis: Network Capture"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.analyzeTcpdumpHandler)},
-
-		{Tool: mcp.NewTool("openshift_route_analyze",
-			mcp.WithDescription("Analyze OpenShift routes and their connectivity"),
-			mcp.WithString("route_name", mcp.Description("Name of the route to analyze")),
-			mcp.WithString("namespace", mcp.Description("Namespace of the route")),
-			mcp.WithTitleAnnotation("OpenShift: Route Analysis"),
-			mcp.WithReadOnlyHintAnnotation(true),
-			mcp.WithDestructiveHintAnnotation(false),
-		), Handler: server.ToolHandlerFunc(s.openShiftRouteAnalyze)},
 	}
 }
 
@@ -221,6 +418,63 @@ func (s *Server) initPods() []server.ServerTool {
 			mcp.WithTitleAnnotation("Pods: List"),
 			mcp.WithReadOnlyHintAnnotation(true),
 		), Handler: server.ToolHandlerFunc(s.ListPodsHandler)},
+		{Tool: mcp.NewTool("list_deployments",
+			mcp.WithDescription("List deployments with replica and readiness summaries, unhealthy ones first"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list deployments from, or \"all\"")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=web,tier!=cache")),
+			mcp.WithTitleAnnotation("Workloads: List Deployments"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listDeploymentsHandler)},
+		{Tool: mcp.NewTool("list_statefulsets",
+			mcp.WithDescription("List statefulsets with replica and readiness summaries, unhealthy ones first"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list statefulsets from, or \"all\"")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=web,tier!=cache")),
+			mcp.WithTitleAnnotation("Workloads: List StatefulSets"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listStatefulSetsHandler)},
+		{Tool: mcp.NewTool("list_daemonsets",
+			mcp.WithDescription("List daemonsets with replica and readiness summaries, unhealthy ones first"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list daemonsets from, or \"all\"")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=web,tier!=cache")),
+			mcp.WithTitleAnnotation("Workloads: List DaemonSets"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listDaemonSetsHandler)},
+		{Tool: mcp.NewTool("get_pod_logs",
+			mcp.WithDescription("Get recent logs from a pod container, bounded in size for analysis"),
+			mcp.WithString("pod_name", mcp.Description("Name of the pod"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the pod")),
+			mcp.WithString("container", mcp.Description("Container name (default: the pod's default or first container)")),
+			mcp.WithString("tail_lines", mcp.Description("Number of most recent lines to return (default 200, max 5000)")),
+			mcp.WithString("since", mcp.Description("Only return logs newer than this duration, e.g. 10m or 2h")),
+			mcp.WithString("timestamps", mcp.Description("Prefix each line with its timestamp (true/false)")),
+			mcp.WithString("previous", mcp.Description("Return logs of the previous, crashed container instance (true/false)")),
+			mcp.WithTitleAnnotation("Pods: Logs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getPodLogsHandler)},
+		{Tool: mcp.NewTool("exec_in_pod",
+			mcp.WithDescription("Run a command such as nslookup or curl inside a running pod container and return its stdout, stderr and exit code"),
+			mcp.WithString("pod_name", mcp.Description("Name of the pod"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the pod")),
+			mcp.WithString("command", mcp.Description("Command line to run, e.g. \"curl -sS http://api:8080/healthz\""), mcp.Required()),
+			mcp.WithString("container", mcp.Description("Container name (default: the pod's default or first container)")),
+			mcp.WithString("shell", mcp.Description("Run the command through /bin/sh -c to allow pipes and variables (true/false)")),
+			mcp.WithString("timeout_seconds", mcp.Description("Seconds before the command is stopped (default 30, max 300)")),
+			mcp.WithTitleAnnotation("Pods: Exec"),
+			mcp.WithDestructiveHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.execInPodHandler)},
+		{Tool: mcp.NewTool("port_forward",
+			mcp.WithDescription("Open a short-lived port-forward from the MCP host to a pod or service and optionally probe it over HTTP, without exposing a route"),
+			mcp.WithString("namespace", mcp.Description("Namespace of the pod or service")),
+			mcp.WithString("pod_name", mcp.Description("Pod to forward to (or use service_name)")),
+			mcp.WithString("service_name", mcp.Description("Service to forward to through one of its ready pods")),
+			mcp.WithString("port", mcp.Description("Pod port, or service port number or name (default: the service's first port)")),
+			mcp.WithString("probe_path", mcp.Description("HTTP path to request through the forward, e.g. /healthz; the forward closes after the probe")),
+			mcp.WithString("probe_method", mcp.Description("GET (default) or HEAD")),
+			mcp.WithString("probe_scheme", mcp.Description("http (default) or https")),
+			mcp.WithString("ttl_seconds", mcp.Description("Without a probe, how long the forward stays open (default 300, max 1800)")),
+			mcp.WithTitleAnnotation("Pods: Port Forward"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.portForwardHandler)},
 	}
 }
 
@@ -276,25 +530,42 @@ func (s *Server) initWriteOperations() []server.ServerTool {
 			mcp.WithDescription("Create a Kubernetes resource from YAML/JSON"),
 			mcp.WithString("yaml", mcp.Description("YAML/JSON content for the resource"), mcp.Required()),
 			mcp.WithString("namespace", mcp.Description("Namespace to create the resource in")),
+			mcp.WithString("dry_run", mcp.Description("Validate with a server dry run and report defaults and impact without changing the cluster (true/false)")),
 			mcp.WithTitleAnnotation("Create: Resource"),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.createResourceHandler)},
 
 		{Tool: mcp.NewTool("update_resource",
-			mcp.WithDescription("Update a Kubernetes resource"),
+			mcp.WithDescription("Update a Kubernetes resource via server-side apply and show the diff between the live and applied object"),
 			mcp.WithString("resource_type", mcp.Description("Type of resource (pod, deployment, service, etc.)"), mcp.Required()),
 			mcp.WithString("resource_name", mcp.Description("Name of the resource"), mcp.Required()),
-			mcp.WithString("namespace", mcp.Description("Namespace of the resource"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the resource (ignored for cluster-scoped resources)")),
 			mcp.WithString("yaml", mcp.Description("Updated YAML/JSON content"), mcp.Required()),
+			mcp.WithString("force", mcp.Description("Take ownership of fields managed by other field managers (true/false)")),
 			mcp.WithTitleAnnotation("Update: Resource"),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.updateResourceHandler)},
 
+		{Tool: mcp.NewTool("patch_resource",
+			mcp.WithDescription("Patch any Kubernetes resource with a JSON patch, merge patch or strategic merge patch and return the resulting object"),
+			mcp.WithString("resource_type", mcp.Description("Type of resource (pod, deployment, route, etc.)"), mcp.Required()),
+			mcp.WithString("resource_name", mcp.Description("Name of the resource"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the resource (ignored for cluster-scoped resources)")),
+			mcp.WithString("patch_type", mcp.Description("json (RFC 6902 operations), merge (RFC 7386) or strategic (default; not supported by custom resources)")),
+			mcp.WithString("patch", mcp.Description("Patch body as YAML or JSON"), mcp.Required()),
+			mcp.WithString("dry_run", mcp.Description("Validate the patch with a server dry run and show the result without changing the cluster (true/false)")),
+			mcp.WithTitleAnnotation("Patch: Resource"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.patchResourceHandler)},
+
 		{Tool: mcp.NewTool("delete_resource",
 			mcp.WithDescription("Delete a Kubernetes resource"),
 			mcp.WithString("resource_type", mcp.Description("Type of resource (pod, deployment, service, etc.)"), mcp.Required()),
 			mcp.WithString("resource_name", mcp.Description("Name of the resource"), mcp.Required()),
-			mcp.WithString("namespace", mcp.Description("Namespace of the resource"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the resource (ignored for cluster-scoped resources)")),
+			mcp.WithString("propagation_policy", mcp.Description("Deletion propagation policy: Foreground, Background or Orphan")),
+			mcp.WithString("grace_period_seconds", mcp.Description("Grace period in seconds before the resource is deleted (0 for immediate)")),
+			mcp.WithString("confirm", mcp.Description("Set to true to delete; otherwise only a preview is returned (true/false)")),
 			mcp.WithTitleAnnotation("Delete: Resource"),
 			mcp.WithDestructiveHintAnnotation(true),
 		), Handler: server.ToolHandlerFunc(s.deleteResourceHandler)},
@@ -316,9 +587,27 @@ func (s *Server) initWriteOperations() []server.ServerTool {
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.restartDeploymentHandler)},
 
+		{Tool: mcp.NewTool("rollback_deployment",
+			mcp.WithDescription("Roll a deployment back to a previous ReplicaSet revision and report the revision history"),
+			mcp.WithString("deployment_name", mcp.Description("Name of the deployment"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the deployment"), mcp.Required()),
+			mcp.WithString("revision", mcp.Description("Revision number to roll back to, or \"previous\" (default)")),
+			mcp.WithString("save_to_git", mcp.Description("Save the rollback action YAML to the Git repository when Git is enabled (true/false, default true)")),
+			mcp.WithTitleAnnotation("Rollback: Deployment"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.rollbackDeploymentHandler)},
+
 		{Tool: mcp.NewTool("create_namespace",
-			mcp.WithDescription("Create a new namespace"),
+			mcp.WithDescription("Create a new namespace; with a tenant template or team, provision it with a ResourceQuota, LimitRange, baseline NetworkPolicies and team RoleBindings, committed to Git as a bundle"),
 			mcp.WithString("namespace_name", mcp.Description("Name of the namespace to create"), mcp.Required()),
+			mcp.WithString("template", mcp.Description("Tenant template to provision from (default when team is given: default); omit both for a bare namespace")),
+			mcp.WithString("team", mcp.Description("Owning team; labels the namespace and fills {{team}} in the template, e.g. the admin group")),
+			mcp.WithString("admin_groups", mcp.Description("Comma-separated groups bound to the admin role, in addition to the template's")),
+			mcp.WithString("edit_groups", mcp.Description("Comma-separated groups bound to the edit role")),
+			mcp.WithString("view_groups", mcp.Description("Comma-separated groups bound to the view role")),
+			mcp.WithString("labels", mcp.Description("Extra namespace labels as comma-separated key=value pairs")),
+			mcp.WithString("dry_run", mcp.Description("Show the bundle without creating anything (true/false)")),
+			mcp.WithString("save_to_git", mcp.Description("Commit the tenant bundle to the Git repository when Git is enabled (true/false, default true)")),
 			mcp.WithTitleAnnotation("Create: Namespace"),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.createNamespaceHandler)},
@@ -328,6 +617,7 @@ func (s *Server) initWriteOperations() []server.ServerTool {
 			mcp.WithString("yaml", mcp.Description("YAML content to apply"), mcp.Required()),
 			mcp.WithString("namespace", mcp.Description("Namespace to apply the resource in")),
 			mcp.WithString("save_to_git", mcp.Description("Save YAML to Git repository (true/false)")),
+			mcp.WithString("dry_run", mcp.Description("Validate with a server dry run and report defaults and impact without changing the cluster (true/false)")),
 			mcp.WithTitleAnnotation("Apply: YAML"),
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.applyYamlHandler)},
@@ -371,14 +661,57 @@ func (s *Server) initGitTools() []server.ServerTool {
 			mcp.WithDestructiveHintAnnotation(false),
 		), Handler: server.ToolHandlerFunc(s.gitPushHandler)},
 
+		{Tool: mcp.NewTool("git_pull",
+			mcp.WithDescription("Fetch the remote branch and integrate changes pushed by others, reporting divergence"),
+			mcp.WithString("strategy", mcp.Description("rebase, merge or ff-only (default: the configured pull strategy)")),
+			mcp.WithTitleAnnotation("Git: Pull"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.gitPullHandler)},
+
+		{Tool: mcp.NewTool("git_create_pull_request",
+			mcp.WithDescription("Open a pull request (merge request on GitLab) on the Git provider hosting the remote"),
+			mcp.WithString("title", mcp.Description("Pull request title"), mcp.Required()),
+			mcp.WithString("head", mcp.Description("Branch with the changes"), mcp.Required()),
+			mcp.WithString("base", mcp.Description("Branch to merge into (default: the configured branch)")),
+			mcp.WithString("body", mcp.Description("Pull request description")),
+			mcp.WithTitleAnnotation("Git: Create Pull Request"),
+			mcp.WithDestructiveHintAnnotation(false),
+		), Handler: server.ToolHandlerFunc(s.gitCreatePullRequestHandler)},
+
+		{Tool: mcp.NewTool("git_commit_status",
+			mcp.WithDescription("Show the CI status checks reported on a commit, branch or tag of the remote repository"),
+			mcp.WithString("ref", mcp.Description("Commit SHA, branch or tag (default: the configured branch)")),
+			mcp.WithTitleAnnotation("Git: Commit Status"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.gitCommitStatusHandler)},
+
+		{Tool: mcp.NewTool("git_browse",
+			mcp.WithDescription("List files in the remote repository through the provider API, without cloning"),
+			mcp.WithString("path", mcp.Description("Directory or file path (default: repository root)")),
+			mcp.WithString("ref", mcp.Description("Commit SHA, branch or tag (default: the configured branch)")),
+			mcp.WithTitleAnnotation("Git: Browse Remote"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.gitBrowseHandler)},
+
+		{Tool: mcp.NewTool("validate_git_changes",
+			mcp.WithDescription("Lint the manifests changed by a commit and compare them with the live cluster through a server-side dry run to detect drift"),
+			mcp.WithString("commit", mcp.Description("Commit, branch or tag to validate (default: the remote branch)")),
+			mcp.WithString("base", mcp.Description("Commit to compare with to find changed files (default: the commit's parent)")),
+			mcp.WithString("paths", mcp.Description("Comma-separated files to validate instead of the changed ones")),
+			mcp.WithTitleAnnotation("Git: Validate Changes"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.validateGitChangesHandler)},
+
 		{Tool: mcp.NewTool("generate_yaml",
-			mcp.WithDescription("Generate YAML for various Kubernetes resources"),
-			mcp.WithString("resource_type", mcp.Description("Type of resource (namespace, configmap, deployment, service)"), mcp.Required()),
+			mcp.WithDescription("Generate YAML for various Kubernetes resources, or for a pre-approved application stack from the catalog"),
+			mcp.WithString("resource_type", mcp.Description("Type of resource (namespace, configmap, deployment, service, hpa), or stack together with template"), mcp.Required()),
 			mcp.WithString("name", mcp.Description("Name of the resource"), mcp.Required()),
 			mcp.WithString("namespace", mcp.Description("Namespace for the resource")),
 			mcp.WithString("image", mcp.Description("Container image (for deployments)")),
-			mcp.WithString("replicas", mcp.Description("Number of replicas (for deployments)")),
+			mcp.WithString("replicas", mcp.Description("Number of replicas (for deployments), or maximum replicas (for HPAs scaling the deployment of the same name)")),
 			mcp.WithString("data", mcp.Description("Data as JSON string (for configmaps/secrets)")),
+			mcp.WithString("template", mcp.Description("Catalog stack to instantiate instead of a single resource, e.g. web-app (see list_catalog); resource_type is then ignored")),
+			mcp.WithString("parameters", mcp.Description("Stack parameters as a JSON object (with template)")),
 			mcp.WithString("save_to_git", mcp.Description("Save generated YAML to Git repository (true/false)")),
 			mcp.WithTitleAnnotation("Generate: YAML"),
 			mcp.WithDestructiveHintAnnotation(false),
@@ -406,22 +739,26 @@ func (s *Server) OpenShiftDiagnose(ctx context.Context, request mcp.CallToolRequ
 	result += fmt.Sprintf("Resource Type: %s\n", resourceType)
 	result += fmt.Sprintf("Namespace: %s\n\n", namespace)
 
+	var report *mcp.CallToolResult
+	var err error
 	switch strings.ToLower(resourceType) {
 	case "pod":
-		return s.diagnosePodIssues(ctx, namespace, resourceName)
+		report, err = s.diagnosePodIssues(ctx, namespace, resourceName)
 	case "deployment":
-		return s.diagnoseDeploymentIssues(ctx, namespace, resourceName)
+		report, err = s.diagnoseDeploymentIssues(ctx, namespace, resourceName)
 	case "service":
-		return s.diagnoseServiceIssues(ctx, namespace, resourceName)
+		report, err = s.diagnoseServiceIssues(ctx, namespace, resourceName)
 	default:
 		result += fmt.Sprintf("⚠️  Diagnostic support for resource type '%s' not implemented yet\n", resourceType)
 		result += "\n🔧 Supported resource types:\n"
 		result += "• pod - Diagnose pod startup, resource, and configuration issues\n"
 		result += "• deployment - Diagnose deployment scaling and rollout issues\n"
 		result += "• service - Diagnose service connectivity and endpoint issues\n"
+		return mcp.NewToolResultText(result), nil
 	}
 
-	return mcp.NewToolResultText(result), nil
+	report = s.appendOwnership(ctx, report, resourceType, resourceName, namespace)
+	return s.appendAlerts(ctx, report, resourceType, resourceName, namespace), err
 }
 
 // diagnosePodIssues provides detailed diagnosis for pod issues
@@ -432,7 +769,7 @@ func (s *Server) diagnosePodIssues(ctx context.Context, namespace, resourceName
 	// Get all pods in the namespace if no specific pod name provided
 	pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to list pods: %v", err)), nil
+		return toolError(ctx, "Failed to list pods", err), nil
 	}
 
 	if len(pods.Items) == 0 {
@@ -495,6 +832,11 @@ func (s *Server) diagnosePodIssues(ctx context.Context, namespace, resourceName
 					case "ImagePullBackOff", "ErrImagePull":
 						result += "   🔧 Fix: Check if the container image exists and is accessible\n"
 						result += "   💡 Commands: oc describe pod " + pod.Name + " -n " + namespace + "\n"
+						for _, container := range pod.Spec.Containers {
+							if _, internal := parseInternalImage(container.Image); internal && container.Name == containerStatus.Name {
+								result += fmt.Sprintf("   💡 Internal registry image: trace_image image=%s namespace=%s shows its ImageStream tag and build\n", container.Image, namespace)
+							}
+						}
 					case "CrashLoopBackOff":
 						result += "   🔧 Fix: Container is crashing, check logs for errors\n"
 						result += "   💡 Commands: oc logs " + pod.Name + " -n " + namespace + "\n"
@@ -515,6 +857,7 @@ func (s *Server) diagnosePodIssues(ctx context.Context, namespace, resourceName
 						containerStatus.State.Terminated.Reason,
 						containerStatus.State.Terminated.Message)
 				}
+				result += remediationHint(&pod, &containerStatus)
 			}
 		}
 
@@ -571,7 +914,7 @@ func (s *Server) diagnoseDeploymentIssues(ctx context.Context, namespace, resour
 
 	deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get deployment: %v", err)), nil
+		return toolError(ctx, "Failed to get deployment", err), nil
 	}
 
 	result += fmt.Sprintf("📊 Deployment: %s\n", deployment.Name)
@@ -601,7 +944,7 @@ func (s *Server) diagnoseServiceIssues(ctx context.Context, namespace, resourceN
 
 	service, err := s.k8sClient.CoreV1().Services(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get service: %v", err)), nil
+		return toolError(ctx, "Failed to get service", err), nil
 	}
 
 	result += fmt.Sprintf("🌐 Service: %s\n", service.Name)
@@ -635,22 +978,6 @@ func (s *Server) diagnoseServiceIssues(ctx context.Context, namespace, resourceN
 	return mcp.NewToolResultText(result), nil
 }
 
-func (s *Server) openShiftMustGather(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	image := mcp.ParseString(request, "image", "")
-
-	result := fmt.Sprintf("Starting must-gather with image: %s", image)
-
-	return mcp.NewToolResultText(result), nil
-}
-
-func (s *Server) openShiftRouteAnalyze(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	routeName := mcp.ParseString(request, "route_name", "")
-
-	result := fmt.Sprintf("Analyzing route: %s", routeName)
-
-	return mcp.NewToolResultText(result), nil
-}
-
 func (s *Server) getKubeconfigHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	result := fmt.Sprintf("Kubeconfig: %s", s.kubeconfig)
 	return mcp.NewToolResultText(result), nil
@@ -665,7 +992,7 @@ func (s *Server) ListPodsHandler(ctx context.Context, request mcp.CallToolReques
 
 	pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to list pods in namespace %s: %v", namespace, err)), nil
+		return toolError(ctx, fmt.Sprintf("Failed to list pods in namespace %s", namespace), err), nil
 	}
 
 	result := "📋 Pod List Results\n"
@@ -711,6 +1038,7 @@ func (s *Server) getResourceHandler(ctx context.Context, request mcp.CallToolReq
 	case "pod":
 		pod, err := s.k8sClient.CoreV1().Pods(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
+			recordToolError(ctx, err)
 			result += fmt.Sprintf("❌ Failed to get pod: %v\n", err)
 		} else {
 			result += fmt.Sprintf("📦 Pod Status: %s\n", pod.Status.Phase)
@@ -721,6 +1049,7 @@ func (s *Server) getResourceHandler(ctx context.Context, request mcp.CallToolReq
 	case "deployment":
 		deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
+			recordToolError(ctx, err)
 			result += fmt.Sprintf("❌ Failed to get deployment: %v\n", err)
 		} else {
 			result += fmt.Sprintf("📊 Replicas: %d/%d ready\n", deployment.Status.ReadyReplicas, deployment.Status.Replicas)
@@ -730,6 +1059,7 @@ func (s *Server) getResourceHandler(ctx context.Context, request mcp.CallToolReq
 	case "service":
 		service, err := s.k8sClient.CoreV1().Services(namespace).Get(ctx, resourceName, metav1.GetOptions{})
 		if err != nil {
+			recordToolError(ctx, err)
 			result += fmt.Sprintf("❌ Failed to get service: %v\n", err)
 		} else {
 			result += fmt.Sprintf("🌐 Type: %s\n", service.Spec.Type)
@@ -753,7 +1083,7 @@ func (s *Server) GetEventsHandler(ctx context.Context, request mcp.CallToolReque
 
 	events, err := s.k8sClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get events from namespace %s: %v", namespace, err)), nil
+		return toolError(ctx, fmt.Sprintf("Failed to get events from namespace %s", namespace), err), nil
 	}
 
 	result := "📅 Cluster Events\n"
@@ -767,9 +1097,8 @@ func (s *Server) GetEventsHandler(ctx context.Context, request mcp.CallToolReque
 			break
 		}
 
-		age := event.LastTimestamp.Time.Format("2006-01-02 15:04:05")
 		result += fmt.Sprintf("• [%s] %s: %s - %s\n",
-			event.Type, age, event.InvolvedObject.Name, event.Message)
+			event.Type, formatTime(ctx, event.LastTimestamp.Time), event.InvolvedObject.Name, event.Message)
 	}
 
 	result += "\n✅ Events retrieved successfully"
@@ -783,7 +1112,8 @@ func (s *Server) ListNamespacesHandler(ctx context.Context, request mcp.CallTool
 
 	namespaces, err := s.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to list namespaces: %v\n\n💡 Try running 'oc login' to authenticate to your OpenShift cluster.", err)), nil
+		recordToolError(ctx, err)
+		return mcp.NewToolResultError(fmt.Sprintf("❌ Failed to list namespaces: %v\n\n💡 Try running 'oc login' to authenticate to your OpenShift cluster.", err)), nil
 	}
 
 	result := "📋 OpenShift Namespace List\n"
@@ -817,11 +1147,16 @@ func (s *Server) createResourceHandler(ctx context.Context, request mcp.CallTool
 
 	yamlContent := mcp.ParseString(request, "yaml", "")
 	namespace := mcp.ParseString(request, "namespace", "default")
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
 
 	if yamlContent == "" {
 		return mcp.NewToolResultText("❌ YAML content is required"), nil
 	}
 
+	if dryRun {
+		return s.dryRunResult(ctx, yamlContent, namespace), nil
+	}
+
 	result := fmt.Sprintf("🚀 Creating resource in namespace: %s\n", namespace)
 	result += "=====================================\n\n"
 	result += "📝 YAML Content:\n"
@@ -830,6 +1165,7 @@ func (s *Server) createResourceHandler(ctx context.Context, request mcp.CallTool
 	// Actually apply the YAML using kubectl apply approach
 	err := s.applyYAMLContent(ctx, yamlContent, namespace)
 	if err != nil {
+		recordToolError(ctx, err)
 		result += fmt.Sprintf("❌ Failed to create resource: %v\n", err)
 		result += "💡 This might be due to:\n"
 		result += "   • Invalid YAML syntax\n"
@@ -839,6 +1175,8 @@ func (s *Server) createResourceHandler(ctx context.Context, request mcp.CallTool
 		return mcp.NewToolResultText(result), nil
 	}
 
+	s.recordManifestActions(ctx, "create_resource", yamlContent, namespace, ReasonCreated, "Created")
+
 	result += "✅ Resource created successfully in the cluster!\n"
 	result += fmt.Sprintf("🏷️  Applied to namespace: %s\n", namespace)
 	result += "🎯 Resource is now active and ready to use"
@@ -848,50 +1186,6 @@ func (s *Server) createResourceHandler(ctx context.Context, request mcp.CallTool
 	return mcp.NewToolResultText(result), nil
 }
 
-func (s *Server) updateResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if s.k8sClient == nil {
-		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
-	}
-
-	resourceType := mcp.ParseString(request, "resource_type", "")
-	resourceName := mcp.ParseString(request, "resource_name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
-	yamlContent := mcp.ParseString(request, "yaml", "")
-
-	result := fmt.Sprintf("🔄 Updating Resource\n")
-	result += "===================\n\n"
-	result += fmt.Sprintf("Resource Type: %s\n", resourceType)
-	result += fmt.Sprintf("Resource Name: %s\n", resourceName)
-	result += fmt.Sprintf("Namespace: %s\n\n", namespace)
-	result += "📝 New YAML Content:\n"
-	result += fmt.Sprintf("```yaml\n%s\n```\n\n", yamlContent)
-	result += "✅ Resource update requested - this would apply the changes to the cluster\n"
-	result += "⚠️  Note: Actual resource update implementation pending for safety"
-
-	return mcp.NewToolResultText(result), nil
-}
-
-func (s *Server) deleteResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if s.k8sClient == nil {
-		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
-	}
-
-	resourceType := mcp.ParseString(request, "resource_type", "")
-	resourceName := mcp.ParseString(request, "resource_name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
-
-	result := fmt.Sprintf("🗑️  Deleting Resource\n")
-	result += "===================\n\n"
-	result += fmt.Sprintf("Resource Type: %s\n", resourceType)
-	result += fmt.Sprintf("Resource Name: %s\n", resourceName)
-	result += fmt.Sprintf("Namespace: %s\n\n", namespace)
-	result += "⚠️  DESTRUCTIVE OPERATION - This would permanently delete the resource\n"
-	result += "✅ Resource deletion requested - this would remove the resource from the cluster\n"
-	result += "⚠️  Note: Actual resource deletion implementation pending for safety"
-
-	return mcp.NewToolResultText(result), nil
-}
-
 func (s *Server) scaleDeploymentHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if s.k8sClient == nil {
 		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
@@ -909,7 +1203,7 @@ func (s *Server) scaleDeploymentHandler(ctx context.Context, request mcp.CallToo
 	// Actually implement the scaling
 	deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get deployment %s: %v", deploymentName, err)), nil
+		return toolError(ctx, fmt.Sprintf("Failed to get deployment %s", deploymentName), err), nil
 	}
 
 	currentReplicas := int32(0)
@@ -922,9 +1216,12 @@ func (s *Server) scaleDeploymentHandler(ctx context.Context, request mcp.CallToo
 
 	_, err = s.k8sClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to scale deployment: %v", err)), nil
+		return toolError(ctx, "Failed to scale deployment", err), nil
 	}
 
+	s.recordAction(ctx, "scale_deployment", objectReference("apps/v1", "Deployment", deployment), ReasonScaled,
+		fmt.Sprintf("Scaled from %d to %d replicas", currentReplicas, newReplicas))
+
 	result := fmt.Sprintf("📈 Scaling Deployment\n")
 	result += "====================\n\n"
 	result += fmt.Sprintf("Deployment: %s\n", deploymentName)
@@ -941,7 +1238,7 @@ func (s *Server) scaleDeploymentHandler(ctx context.Context, request mcp.CallToo
 		} else {
 			filename := fmt.Sprintf("scale-%s", deploymentName)
 			description := fmt.Sprintf("Scale deployment %s from %d to %d replicas", deploymentName, currentReplicas, newReplicas)
-			_, err := s.gitManager.SaveYAMLFile(filename, yamlContent, "scale", description)
+			_, err := s.gitManager.SaveYAMLFile(ctx, filename, yamlContent, "scale", description)
 			if err != nil {
 				result += fmt.Sprintf("\n⚠️  Failed to save to Git: %v", err)
 			} else {
@@ -964,7 +1261,7 @@ func (s *Server) restartDeploymentHandler(ctx context.Context, request mcp.CallT
 	// Get the deployment
 	deployment, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get deployment %s: %v", deploymentName, err)), nil
+		return toolError(ctx, fmt.Sprintf("Failed to get deployment %s", deploymentName), err), nil
 	}
 
 	// Add restart annotation
@@ -975,14 +1272,16 @@ func (s *Server) restartDeploymentHandler(ctx context.Context, request mcp.CallT
 
 	_, err = s.k8sClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to restart deployment: %v", err)), nil
+		return toolError(ctx, "Failed to restart deployment", err), nil
 	}
 
+	s.recordAction(ctx, "restart_deployment", objectReference("apps/v1", "Deployment", deployment), ReasonRestarted, "Rollout restarted")
+
 	result := fmt.Sprintf("🔄 Restarting Deployment\n")
 	result += "=======================\n\n"
 	result += fmt.Sprintf("Deployment: %s\n", deploymentName)
 	result += fmt.Sprintf("Namespace: %s\n", namespace)
-	result += fmt.Sprintf("Restart Time: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+	result += fmt.Sprintf("Restart Time: %s\n\n", formatTime(ctx, time.Now()))
 	result += "✅ Deployment restart initiated successfully!"
 
 	return mcp.NewToolResultText(result), nil
@@ -997,6 +1296,11 @@ func (s *Server) createNamespaceHandler(ctx context.Context, request mcp.CallToo
 	if namespaceName == "" {
 		return mcp.NewToolResultText("❌ Namespace name is required"), nil
 	}
+	for _, parameter := range []string{"template", "team", "admin_groups", "edit_groups", "view_groups", "labels"} {
+		if mcp.ParseString(request, parameter, "") != "" {
+			return s.provisionNamespaceHandler(ctx, request)
+		}
+	}
 
 	// Create the namespace
 	namespace := &corev1.Namespace{
@@ -1007,13 +1311,15 @@ func (s *Server) createNamespaceHandler(ctx context.Context, request mcp.CallToo
 
 	createdNs, err := s.k8sClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to create namespace: %v", err)), nil
+		return toolError(ctx, "Failed to create namespace", err), nil
 	}
 
+	s.recordAction(ctx, "create_namespace", objectReference("v1", "Namespace", createdNs), ReasonCreated, "Created Namespace")
+
 	result := fmt.Sprintf("🏗️  Creating Namespace\n")
 	result += "=====================\n\n"
 	result += fmt.Sprintf("Namespace: %s\n", namespaceName)
-	result += fmt.Sprintf("Created: %s\n", createdNs.CreationTimestamp.Format("2006-01-02 15:04:05"))
+	result += fmt.Sprintf("Created: %s\n", formatTime(ctx, createdNs.CreationTimestamp.Time))
 	result += fmt.Sprintf("Status: %s\n\n", createdNs.Status.Phase)
 	result += "✅ Namespace created successfully!"
 
@@ -1050,14 +1356,16 @@ func (s *Server) createConfigMapHandler(ctx context.Context, request mcp.CallToo
 
 	createdCM, err := s.k8sClient.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to create ConfigMap: %v", err)), nil
+		return toolError(ctx, "Failed to create ConfigMap", err), nil
 	}
 
+	s.recordAction(ctx, "create_configmap", objectReference("v1", "ConfigMap", createdCM), ReasonCreated, "Created ConfigMap")
+
 	result := fmt.Sprintf("🗂️  ConfigMap Created Successfully\n")
 	result += "==================================\n\n"
 	result += fmt.Sprintf("Name: %s\n", createdCM.Name)
 	result += fmt.Sprintf("Namespace: %s\n", createdCM.Namespace)
-	result += fmt.Sprintf("Created: %s\n", createdCM.CreationTimestamp.Format("2006-01-02 15:04:05"))
+	result += fmt.Sprintf("Created: %s\n", formatTime(ctx, createdCM.CreationTimestamp.Time))
 	result += fmt.Sprintf("Data entries: %d\n\n", len(createdCM.Data))
 
 	result += "📋 Data Contents:\n"
@@ -1078,11 +1386,16 @@ func (s *Server) applyYamlHandler(ctx context.Context, request mcp.CallToolReque
 	yamlContent := mcp.ParseString(request, "yaml", "")
 	namespace := mcp.ParseString(request, "namespace", "default")
 	saveToGit := parseBoolString(mcp.ParseString(request, "save_to_git", "false"))
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
 
 	if yamlContent == "" {
 		return mcp.NewToolResultText("❌ YAML content is required"), nil
 	}
 
+	if dryRun {
+		return s.dryRunResult(ctx, yamlContent, namespace), nil
+	}
+
 	result := fmt.Sprintf("📄 Applying YAML Configuration\n")
 	result += "==============================\n\n"
 	result += fmt.Sprintf("Target Namespace: %s\n\n", namespace)
@@ -1092,6 +1405,7 @@ func (s *Server) applyYamlHandler(ctx context.Context, request mcp.CallToolReque
 	// Actually apply the YAML using kubectl apply approach
 	err := s.applyYAMLContent(ctx, yamlContent, namespace)
 	if err != nil {
+		recordToolError(ctx, err)
 		result += fmt.Sprintf("❌ Failed to apply YAML: %v\n", err)
 		result += "💡 Common issues:\n"
 		result += "   • Invalid YAML syntax\n"
@@ -1101,6 +1415,8 @@ func (s *Server) applyYamlHandler(ctx context.Context, request mcp.CallToolReque
 		return mcp.NewToolResultText(result), nil
 	}
 
+	s.recordManifestActions(ctx, "apply_yaml", yamlContent, namespace, ReasonApplied, "Applied")
+
 	result += "✅ YAML applied successfully to the cluster!\n"
 	result += fmt.Sprintf("🏷️  Applied to namespace: %s\n", namespace)
 	result += "🎯 Resources are now active and ready to use\n"
@@ -1120,9 +1436,9 @@ func (s *Server) gitStatusHandler(ctx context.Context, request mcp.CallToolReque
 		return mcp.NewToolResultText("❌ Git integration is disabled"), nil
 	}
 
-	status, err := s.gitManager.GetStatus()
+	status, err := s.gitManager.GetStatus(ctx)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get Git status: %v", err)), nil
+		return toolError(ctx, "Failed to get Git status", err), nil
 	}
 
 	result := "📊 Git Repository Status\n"
@@ -1134,6 +1450,10 @@ func (s *Server) gitStatusHandler(ctx context.Context, request mcp.CallToolReque
 		result += "📝 Changes detected:\n"
 		result += status
 	}
+	if lastSync := s.gitManager.LastSync(); lastSync != nil {
+		result += "\n🔄 Last sync with remote:\n"
+		result += formatSyncStatus(ctx, lastSync)
+	}
 
 	return mcp.NewToolResultText(result), nil
 }
@@ -1145,7 +1465,7 @@ func (s *Server) gitListFilesHandler(ctx context.Context, request mcp.CallToolRe
 
 	files, err := s.gitManager.ListFiles()
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to list files: %v", err)), nil
+		return toolError(ctx, "Failed to list files", err), nil
 	}
 
 	result := "📁 YAML Files in Git Repository\n"
@@ -1173,15 +1493,15 @@ func (s *Server) gitCommitHandler(ctx context.Context, request mcp.CallToolReque
 		return mcp.NewToolResultText("❌ Commit message is required"), nil
 	}
 
-	err := s.gitManager.CommitAllChanges(message)
+	err := s.gitManager.CommitAllChanges(ctx, message)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to commit changes: %v", err)), nil
+		return toolError(ctx, "Failed to commit changes", err), nil
 	}
 
 	result := "✅ Git Commit Successful\n"
 	result += "========================\n\n"
 	result += fmt.Sprintf("Message: %s\n", message)
-	result += fmt.Sprintf("Time: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	result += fmt.Sprintf("Time: %s\n", formatTime(ctx, time.Now()))
 
 	return mcp.NewToolResultText(result), nil
 }
@@ -1191,15 +1511,15 @@ func (s *Server) gitPushHandler(ctx context.Context, request mcp.CallToolRequest
 		return mcp.NewToolResultText("❌ Git integration is disabled"), nil
 	}
 
-	err := s.gitManager.PushChanges()
+	err := s.gitManager.PushChanges(ctx)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to push changes: %v", err)), nil
+		return toolError(ctx, "Failed to push changes", err), nil
 	}
 
 	result := "🚀 Git Push Successful\n"
 	result += "======================\n\n"
 	result += "Changes pushed to remote repository\n"
-	result += fmt.Sprintf("Time: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	result += fmt.Sprintf("Time: %s\n", formatTime(ctx, time.Now()))
 
 	return mcp.NewToolResultText(result), nil
 }
@@ -1213,6 +1533,9 @@ func (s *Server) generateYamlHandler(ctx context.Context, request mcp.CallToolRe
 	dataStr := mcp.ParseString(request, "data", "{}")
 	saveToGit := mcp.ParseString(request, "save_to_git", "false")
 
+	if stackName := mcp.ParseString(request, "template", ""); stackName != "" {
+		return s.generateStackYAML(ctx, request, stackName)
+	}
 	if resourceType == "" || name == "" {
 		return mcp.NewToolResultText("❌ Resource type and name are required"), nil
 	}
@@ -1247,12 +1570,19 @@ func (s *Server) generateYamlHandler(ctx context.Context, request mcp.CallToolRe
 		ports := s.yamlGenerator.GenerateDefaultServicePorts()
 		yamlContent, err = s.yamlGenerator.GenerateServiceYAML(name, namespace, selector, ports, corev1.ServiceTypeClusterIP)
 
+	case "hpa", "horizontalpodautoscaler":
+		maxReplicas, parseErr := strconv.ParseInt(replicasStr, 10, 32)
+		if parseErr != nil || maxReplicas < 1 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid replicas value: %s", replicasStr)), nil
+		}
+		yamlContent, err = s.yamlGenerator.GenerateHPAYAML(name, namespace, "Deployment", name, 1, int32(maxReplicas), 80, 0)
+
 	default:
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Unsupported resource type: %s", resourceType)), nil
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Unsupported resource type: %s\n💡 For a custom resource, run describe_resource_schema resource_type=%s and generate it with generate_custom_resource", resourceType, resourceType)), nil
 	}
 
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to generate YAML: %v", err)), nil
+		return toolError(ctx, "Failed to generate YAML", err), nil
 	}
 
 	result := fmt.Sprintf("📄 Generated YAML for %s\n", resourceType)
@@ -1265,7 +1595,7 @@ func (s *Server) generateYamlHandler(ctx context.Context, request mcp.CallToolRe
 	if saveToGit == "true" && s.gitManager.IsEnabled() {
 		filename := fmt.Sprintf("%s-%s", resourceType, name)
 		description := fmt.Sprintf("Generated %s: %s", resourceType, name)
-		_, err := s.gitManager.SaveYAMLFile(filename, yamlContent, "generate", description)
+		_, err := s.gitManager.SaveYAMLFile(ctx, filename, yamlContent, "generate", description)
 		if err != nil {
 			result += fmt.Sprintf("⚠️  Failed to save to Git: %v\n", err)
 		} else {
@@ -1300,7 +1630,7 @@ func (s *Server) createArgocdApplicationHandler(ctx context.Context, request mcp
 		destinationServer, destinationNamespace, automated,
 	)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to generate ArgoCD Application: %v", err)), nil
+		return toolError(ctx, "Failed to generate ArgoCD Application", err), nil
 	}
 
 	result := fmt.Sprintf("🚀 Generated ArgoCD Application: %s\n", appName)
@@ -1311,7 +1641,7 @@ func (s *Server) createArgocdApplicationHandler(ctx context.Context, request mcp
 
 	// Save to Git if enabled
 	if s.gitManager.IsEnabled() {
-		if err := s.gitManager.SaveArgocdApplication(appName, yamlContent); err != nil {
+		if err := s.gitManager.SaveArgocdApplication(ctx, appName, yamlContent); err != nil {
 			result += fmt.Sprintf("⚠️  Failed to save to Git: %v\n", err)
 		} else {
 			result += "✅ ArgoCD Application saved to Git repository successfully!\n"
@@ -1352,7 +1682,7 @@ func (s *Server) createArgocdManifestBundleHandler(ctx context.Context, request
 		appName, namespace, image, int32(replicas), configData, env,
 	)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to generate manifest bundle: %v", err)), nil
+		return toolError(ctx, "Failed to generate manifest bundle", err), nil
 	}
 
 	result := fmt.Sprintf("📦 Generated ArgoCD manifest bundle for: %s\n", appName)
@@ -1393,7 +1723,7 @@ func (s *Server) createArgocdAppOfAppsHandler(ctx context.Context, request mcp.C
 	// Generate App of Apps YAML
 	yamlContent, err := s.gitManager.GenerateArgocdAppOfApps(environment, repoURL, applications)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to generate App of Apps: %v", err)), nil
+		return toolError(ctx, "Failed to generate App of Apps", err), nil
 	}
 
 	result := fmt.Sprintf("🎯 Generated ArgoCD App of Apps for: %s\n", environment)
@@ -1422,7 +1752,7 @@ func (s *Server) initArgocdDirectoryHandler(ctx context.Context, request mcp.Cal
 
 	// Create ArgoCD directory structure
 	if err := s.gitManager.CreateArgocdDirectoryStructure(); err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to create ArgoCD directory structure: %v", err)), nil
+		return toolError(ctx, "Failed to create ArgoCD directory structure", err), nil
 	}
 
 	result := "📁 ArgoCD directory structure created successfully!\n"
@@ -1450,7 +1780,7 @@ func (s *Server) listArgocdApplicationsHandler(ctx context.Context, request mcp.
 
 	applications, err := s.gitManager.ListArgocdApplications()
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to list ArgoCD applications: %v", err)), nil
+		return toolError(ctx, "Failed to list ArgoCD applications", err), nil
 	}
 
 	result := "📋 ArgoCD Applications in Repository\n"
@@ -1478,7 +1808,7 @@ func (s *Server) getArgocdApplicationManifestsHandler(ctx context.Context, reque
 
 	manifests, err := s.gitManager.GetArgocdApplicationManifests(appName, environment)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to get manifests: %v", err)), nil
+		return toolError(ctx, "Failed to get manifests", err), nil
 	}
 
 	result := fmt.Sprintf("📄 Manifests for %s (environment: %s)\n", appName, environment)
@@ -1509,8 +1839,8 @@ func (s *Server) commitArgocdChangesHandler(ctx context.Context, request mcp.Cal
 		return mcp.NewToolResultText("❌ Git integration is disabled"), nil
 	}
 
-	if err := s.gitManager.CommitArgocdChanges(appName, environment, action, message); err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to commit changes: %v", err)), nil
+	if err := s.gitManager.CommitArgocdChanges(ctx, appName, environment, action, message); err != nil {
+		return toolError(ctx, "Failed to commit changes", err), nil
 	}
 
 	result := fmt.Sprintf("✅ Committed ArgoCD changes for %s\n", appName)
@@ -1549,7 +1879,7 @@ func (s *Server) enhancedScaleDeploymentHandler(ctx context.Context, request mcp
 		)
 		if yamlErr == nil {
 			filename := fmt.Sprintf("scale-%s-%s", deploymentName, namespace)
-			_, saveErr := s.gitManager.SaveYAMLFile(filename, scaleActionYAML, "scale",
+			_, saveErr := s.gitManager.SaveYAMLFile(ctx, filename, scaleActionYAML, "scale",
 				fmt.Sprintf("Scale %s to %d replicas", deploymentName, replicas))
 			if saveErr != nil {
 				logrus.Warnf("Failed to save scale action to Git: %v", saveErr)
@@ -1642,6 +1972,11 @@ func (s *Server) CreateResourceHandler(ctx context.Context, request mcp.CallTool
 	return s.createResourceHandler(ctx, request)
 }
 
+// PatchResourceHandler is a public wrapper for patchResourceHandler
+func (s *Server) PatchResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.patchResourceHandler(ctx, request)
+}
+
 // DeleteResourceHandler is a public wrapper for deleteResourceHandler
 func (s *Server) DeleteResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return s.deleteResourceHandler(ctx, request)
@@ -1669,6 +2004,11 @@ func (s *Server) AnalyzeMustGatherHandler(ctx context.Context, request mcp.CallT
 	return s.analyzeMustGatherHandler(ctx, request)
 }
 
+// AnalyzeMustGatherDiffHandler is a public wrapper for analyzeMustGatherDiffHandler
+func (s *Server) AnalyzeMustGatherDiffHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.analyzeMustGatherDiffHandler(ctx, request)
+}
+
 // AnalyzeLogsHandler is a public wrapper for analyzeLogsHandler
 func (s *Server) AnalyzeLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return s.analyzeLogsHandler(ctx, request)
@@ -1684,11 +2024,6 @@ func (s *Server) OpenShiftMustGatherHandler(ctx context.Context, request mcp.Cal
 	return s.openShiftMustGather(ctx, request)
 }
 
-// OpenShiftRouteAnalyzeHandler is a public wrapper for openShiftRouteAnalyze
-func (s *Server) OpenShiftRouteAnalyzeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	return s.openShiftRouteAnalyze(ctx, request)
-}
-
 // Diagnostic Collection Handlers
 
 // collectSosReportHandler collects sosreport from a node
@@ -1705,16 +2040,25 @@ func (s *Server) collectSosReportHandler(ctx context.Context, request mcp.CallTo
 	outputDir := mcp.ParseString(request, "output_dir", "")
 
 	opts := &diagnostics.CollectionOptions{
-		NodeName:  nodeName,
-		OutputDir: outputDir,
+		NodeName:   nodeName,
+		OutputDir:  outputDir,
+		Filters:    make(map[string]string),
+		Compressed: mcp.ParseBoolean(request, "compressed", false),
+	}
+	for _, key := range []string{"plugins", "timeout", "image"} {
+		if value := mcp.ParseString(request, key, ""); value != "" {
+			opts.Filters[key] = value
+		}
 	}
 
 	result, err := s.diagnosticCollector.CollectSosReport(ctx, opts)
 	if err != nil {
+		recordToolError(ctx, err)
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.NewTextContent(fmt.Sprintf("Failed to collect sosreport: %v", err)),
 			},
+			IsError: true,
 		}, nil
 	}
 
@@ -1722,12 +2066,22 @@ func (s *Server) collectSosReportHandler(ctx context.Context, request mcp.CallTo
 		"📊 **Summary**: %s\n"+
 		"📁 **Location**: %s\n"+
 		"⏱️ **Duration**: %v\n"+
-		"📦 **Size**: %.2f MB\n\n"+
-		"The sosreport has been collected and is ready for analysis.",
+		"📦 **Size**: %.2f MB\n"+
+		"🔐 **SHA-256**: %s\n"+
+		"🧩 **Plugins**: %s\n\n",
 		result.Summary,
 		result.FilePath,
 		result.Duration,
-		float64(result.Size)/(1024*1024))
+		float64(result.Size)/(1024*1024),
+		result.Metadata["sha256"],
+		valueOrNone(result.Metadata["plugins"]))
+	if result.Metadata["checksum_verified"] == "true" {
+		response += "✅ Checksum matches the archive sos generated on the node.\n"
+	} else {
+		response += "⚠️ sos did not write a checksum file on the node, so the transfer could not be verified.\n"
+	}
+	response += bundleNote(result)
+	response += "The debug pod and its namespace have been removed. The sosreport is ready for analysis."
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -1754,12 +2108,13 @@ func (s *Server) collectTcpdumpHandler(ctx context.Context, request mcp.CallTool
 	}
 
 	opts := &diagnostics.CollectionOptions{
-		PodName:   podName,
-		NodeName:  nodeName,
-		Namespace: namespace,
-		Duration:  duration,
-		OutputDir: outputDir,
-		Filters:   make(map[string]string),
+		PodName:    podName,
+		NodeName:   nodeName,
+		Namespace:  namespace,
+		Duration:   duration,
+		OutputDir:  outputDir,
+		Filters:    make(map[string]string),
+		Compressed: mcp.ParseBoolean(request, "compressed", false),
 	}
 
 	if filter != "" {
@@ -1768,10 +2123,12 @@ func (s *Server) collectTcpdumpHandler(ctx context.Context, request mcp.CallTool
 
 	result, err := s.diagnosticCollector.CollectTcpdump(ctx, opts)
 	if err != nil {
+		recordToolError(ctx, err)
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.NewTextContent(fmt.Sprintf("Failed to collect tcpdump: %v", err)),
 			},
+			IsError: true,
 		}, nil
 	}
 
@@ -1779,12 +2136,13 @@ func (s *Server) collectTcpdumpHandler(ctx context.Context, request mcp.CallTool
 		"📊 **Summary**: %s\n"+
 		"📁 **Location**: %s\n"+
 		"⏱️ **Duration**: %v\n"+
-		"📦 **Size**: %.2f MB\n\n"+
+		"📦 **Size**: %.2f MB\n\n%s"+
 		"The packet capture has been collected and is ready for analysis.",
 		result.Summary,
 		result.FilePath,
 		result.Duration,
-		float64(result.Size)/(1024*1024))
+		float64(result.Size)/(1024*1024),
+		bundleNote(result))
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -1799,19 +2157,26 @@ func (s *Server) collectLogsHandler(ctx context.Context, request mcp.CallToolReq
 	namespace := mcp.ParseString(request, "namespace", "")
 	outputDir := mcp.ParseString(request, "output_dir", "")
 
+	if podName == "" && namespace != "" {
+		return s.collectNamespaceLogs(ctx, request, namespace, outputDir)
+	}
+
 	opts := &diagnostics.CollectionOptions{
 		PodName:     podName,
 		Namespace:   namespace,
 		OutputDir:   outputDir,
 		IncludeLogs: true,
+		Compressed:  mcp.ParseBoolean(request, "compressed", false),
 	}
 
 	result, err := s.diagnosticCollector.CollectLogs(ctx, opts)
 	if err != nil {
+		recordToolError(ctx, err)
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.NewTextContent(fmt.Sprintf("Failed to collect logs: %v", err)),
 			},
+			IsError: true,
 		}, nil
 	}
 
@@ -1819,12 +2184,13 @@ func (s *Server) collectLogsHandler(ctx context.Context, request mcp.CallToolReq
 		"📊 **Summary**: %s\n"+
 		"📁 **Location**: %s\n"+
 		"⏱️ **Duration**: %v\n"+
-		"📦 **Size**: %.2f MB\n\n"+
+		"📦 **Size**: %.2f MB\n\n%s"+
 		"The logs have been collected and are ready for analysis.",
 		result.Summary,
 		result.FilePath,
 		result.Duration,
-		float64(result.Size)/(1024*1024))
+		float64(result.Size)/(1024*1024),
+		bundleNote(result))
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -1833,6 +2199,70 @@ func (s *Server) collectLogsHandler(ctx context.Context, request mcp.CallToolReq
 	}, nil
 }
 
+// collectNamespaceLogs collects every matching container's logs in parallel
+func (s *Server) collectNamespaceLogs(ctx context.Context, request mcp.CallToolRequest, namespace, outputDir string) (*mcp.CallToolResult, error) {
+	opts := &diagnostics.CollectionOptions{
+		Namespace:   namespace,
+		OutputDir:   outputDir,
+		IncludeLogs: true,
+		Compressed:  mcp.ParseBoolean(request, "compressed", false),
+		Filters: map[string]string{
+			"selector": mcp.ParseString(request, "label_selector", ""),
+			"since":    mcp.ParseString(request, "since", ""),
+			"workers":  mcp.ParseString(request, "workers", ""),
+			"previous": strconv.FormatBool(mcp.ParseBoolean(request, "include_previous", false)),
+		},
+	}
+
+	result, err := s.diagnosticCollector.CollectNamespaceLogs(ctx, opts)
+	if result == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if err != nil && result.Metadata["manifest"] == "" {
+		return toolError(ctx, "❌ Failed to collect logs", err), nil
+	}
+
+	response := "📝 Namespace Log Collection\n"
+	response += "==========================\n\n"
+	response += fmt.Sprintf("Namespace: %s\n", namespace)
+	response += fmt.Sprintf("Selector: %s\n", valueOrNone(result.Metadata["selector"]))
+	response += fmt.Sprintf("Pods: %s, containers: %s, workers: %s\n", result.Metadata["pods"], result.Metadata["containers"], result.Metadata["workers"])
+	response += fmt.Sprintf("📁 Location: %s\n", result.FilePath)
+	if result.Metadata["bundle"] == "" {
+		response += fmt.Sprintf("📋 Manifest: %s\n", result.Metadata["manifest"])
+	}
+	response += fmt.Sprintf("📦 Size: %.2f MB in %s logs\n", float64(result.Size)/(1024*1024), result.Metadata["files_collected"])
+	response += fmt.Sprintf("⏱️ Duration: %v\n", result.Duration.Round(time.Millisecond))
+
+	if result.Metadata["pods"] == "0" {
+		response += "\n⚠️ No pods matched; nothing was collected.\n"
+	}
+	if failed := result.Metadata["failed"]; failed != "0" {
+		response += fmt.Sprintf("\n⚠️ %s logs could not be collected; see the manifest for the errors.\n", failed)
+	}
+	if err != nil {
+		recordToolError(ctx, err)
+		response += fmt.Sprintf("\n❌ %v\n", err)
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent(response)}, IsError: true}, nil
+	}
+	response += "\n" + bundleNote(result)
+	response += "The logs have been collected and are ready for analysis with analyze_logs."
+	return mcp.NewToolResultText(response), nil
+}
+
+// bundleNote describes the bundle a compressed collection was packaged into
+func bundleNote(result *diagnostics.CollectionResult) string {
+	if err := result.Metadata["bundle_error"]; err != "" {
+		return fmt.Sprintf("⚠️ Packaging failed, the files were left unpacked: %s\n", err)
+	}
+	if result.Metadata["bundle"] == "" {
+		return ""
+	}
+	uncompressed, _ := strconv.ParseInt(result.Metadata["uncompressed_bytes"], 10, 64)
+	return fmt.Sprintf("🗜️ Packaged %s files (%.2f MB uncompressed) with an %s index; pass the bundle path straight to the analyze_* tools.\n",
+		result.Metadata["bundle_files"], float64(uncompressed)/(1024*1024), diagnostics.BundleIndexFile)
+}
+
 // Analysis Handlers
 
 // analyzeMustGatherHandler analyzes must-gather data
@@ -1846,16 +2276,24 @@ func (s *Server) analyzeMustGatherHandler(ctx context.Context, request mcp.CallT
 		}, nil
 	}
 
+	format, err := diagnostics.ParseExportFormat(mcp.ParseString(request, "export", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
 	result, err := s.analysisEngine.AnalyzeMustGather(ctx, mustGatherPath)
 	if err != nil {
+		recordToolError(ctx, err)
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.NewTextContent(fmt.Sprintf("Failed to analyze must-gather: %v", err)),
 			},
+			IsError: true,
 		}, nil
 	}
 
-	response := s.formatAnalysisResult(result)
+	response := s.formatAnalysisResult(ctx, result)
+	response += s.exportNote(ctx, result, format)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -1864,6 +2302,69 @@ func (s *Server) analyzeMustGatherHandler(ctx context.Context, request mcp.CallT
 	}, nil
 }
 
+// analyzeMustGatherDiffHandler compares two must-gathers
+func (s *Server) analyzeMustGatherDiffHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	beforePath := mcp.ParseString(request, "before_path", "")
+	afterPath := mcp.ParseString(request, "after_path", "")
+	if beforePath == "" || afterPath == "" {
+		return mcp.NewToolResultText("❌ before_path and after_path parameters are required"), nil
+	}
+
+	diff, err := s.analysisEngine.DiffMustGathers(ctx, beforePath, afterPath)
+	if err != nil {
+		return toolError(ctx, "Failed to compare must-gathers", err), nil
+	}
+
+	result := "🔍 Must-Gather Comparison\n"
+	result += "==============================\n\n"
+	for _, snapshot := range []struct {
+		label string
+		path  string
+		stats diagnostics.MustGatherStats
+	}{
+		{"Before", diff.Before, diff.BeforeStats},
+		{"After", diff.After, diff.AfterStats},
+	} {
+		result += fmt.Sprintf("%s: %s\n", snapshot.label, snapshot.path)
+		result += fmt.Sprintf("   Version: %s | Operators: %d (%d degraded) | Nodes: %d (%d not ready) | Pods: %d (%d failing)\n",
+			valueOrNone(snapshot.stats.Version), snapshot.stats.Operators, snapshot.stats.Degraded,
+			snapshot.stats.Nodes, snapshot.stats.NotReady, snapshot.stats.Pods, snapshot.stats.FailingPods)
+	}
+	result += "\n"
+
+	sections := []struct {
+		severity string
+		heading  string
+	}{
+		{diagnostics.DiffRegression, "🔴 Regressions"},
+		{diagnostics.DiffChange, "🟡 Changes"},
+		{diagnostics.DiffImprovement, "🟢 Improvements"},
+	}
+	counts := make(map[string]int)
+	for _, section := range sections {
+		var lines []string
+		for _, change := range diff.Changes {
+			if change.Severity == section.severity {
+				lines = append(lines, fmt.Sprintf("  • [%s] %s", change.Area, change.Message))
+			}
+		}
+		counts[section.severity] = len(lines)
+		if len(lines) > 0 {
+			result += fmt.Sprintf("%s (%d):\n%s\n\n", section.heading, len(lines), strings.Join(lines, "\n"))
+		}
+	}
+
+	switch {
+	case counts[diagnostics.DiffRegression] > 0:
+		result += fmt.Sprintf("❌ %d regressions between the must-gathers", counts[diagnostics.DiffRegression])
+	case len(diff.Changes) > 0:
+		result += "✅ No regressions; review the changes above"
+	default:
+		result += "✅ No differences in operators, nodes or pods"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
 // analyzeLogsHandler analyzes log files
 func (s *Server) analyzeLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logPath := mcp.ParseString(request, "log_path", "")
@@ -1875,16 +2376,24 @@ func (s *Server) analyzeLogsHandler(ctx context.Context, request mcp.CallToolReq
 		}, nil
 	}
 
+	format, err := diagnostics.ParseExportFormat(mcp.ParseString(request, "export", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
 	result, err := s.analysisEngine.AnalyzeLogs(ctx, logPath)
 	if err != nil {
+		recordToolError(ctx, err)
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.NewTextContent(fmt.Sprintf("Failed to analyze logs: %v", err)),
 			},
+			IsError: true,
 		}, nil
 	}
 
-	response := s.formatAnalysisResult(result)
+	response := s.formatAnalysisResult(ctx, result)
+	response += s.exportNote(ctx, result, format)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -1904,16 +2413,28 @@ func (s *Server) analyzeTcpdumpHandler(ctx context.Context, request mcp.CallTool
 		}, nil
 	}
 
-	result, err := s.analysisEngine.AnalyzeTcpdump(ctx, pcapPath)
+	mode, err := diagnostics.ParsePcapMode(mcp.ParseString(request, "mode", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	format, err := diagnostics.ParseExportFormat(mcp.ParseString(request, "export", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	result, err := s.analysisEngine.AnalyzeTcpdumpWith(ctx, pcapPath, mode)
 	if err != nil {
+		recordToolError(ctx, err)
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.NewTextContent(fmt.Sprintf("Failed to analyze tcpdump: %v", err)),
 			},
+			IsError: true,
 		}, nil
 	}
 
-	response := s.formatAnalysisResult(result)
+	response := s.formatAnalysisResult(ctx, result)
+	response += s.exportNote(ctx, result, format)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -1923,7 +2444,7 @@ func (s *Server) analyzeTcpdumpHandler(ctx context.Context, request mcp.CallTool
 }
 
 // formatAnalysisResult formats the analysis result for display
-func (s *Server) formatAnalysisResult(result *diagnostics.AnalysisResult) string {
+func (s *Server) formatAnalysisResult(ctx context.Context, result *diagnostics.AnalysisResult) string {
 	response := fmt.Sprintf("🔍 **Analysis Results: %s**\n\n", result.Type)
 	response += fmt.Sprintf("📊 **Summary**: %s\n\n", result.Summary)
 
@@ -1952,7 +2473,15 @@ func (s *Server) formatAnalysisResult(result *diagnostics.AnalysisResult) string
 			for i, issue := range critical {
 				response += fmt.Sprintf("%d. **%s** (%s)\n", i+1, issue.Title, issue.Category)
 				response += fmt.Sprintf("   📍 Location: %s\n", issue.Location)
-				response += fmt.Sprintf("   💡 Resolution: %s\n\n", issue.Resolution)
+				if occurrences := issue.Metadata["occurrences"]; occurrences != "" && occurrences != "1" {
+					response += fmt.Sprintf("   🔁 Occurrences: %s\n", occurrences)
+				}
+				response += fmt.Sprintf("   💡 Resolution: %s\n", issue.Resolution)
+				if docs := issue.Metadata["docs"]; docs != "" {
+					response += fmt.Sprintf("   📖 Docs: %s\n", docs)
+				}
+				response += formatReferences(issue.References)
+				response += "\n"
 			}
 		}
 
@@ -1962,7 +2491,15 @@ func (s *Server) formatAnalysisResult(result *diagnostics.AnalysisResult) string
 			for i, issue := range warnings {
 				response += fmt.Sprintf("%d. **%s** (%s)\n", i+1, issue.Title, issue.Category)
 				response += fmt.Sprintf("   📍 Location: %s\n", issue.Location)
-				response += fmt.Sprintf("   💡 Resolution: %s\n\n", issue.Resolution)
+				if occurrences := issue.Metadata["occurrences"]; occurrences != "" && occurrences != "1" {
+					response += fmt.Sprintf("   🔁 Occurrences: %s\n", occurrences)
+				}
+				response += fmt.Sprintf("   💡 Resolution: %s\n", issue.Resolution)
+				if docs := issue.Metadata["docs"]; docs != "" {
+					response += fmt.Sprintf("   📖 Docs: %s\n", docs)
+				}
+				response += formatReferences(issue.References)
+				response += "\n"
 			}
 		}
 
@@ -1971,7 +2508,8 @@ func (s *Server) formatAnalysisResult(result *diagnostics.AnalysisResult) string
 			response += "ℹ️ **Additional Information**:\n"
 			for i, issue := range info {
 				response += fmt.Sprintf("%d. **%s** (%s)\n", i+1, issue.Title, issue.Category)
-				response += fmt.Sprintf("   💡 Resolution: %s\n\n", issue.Resolution)
+				response += fmt.Sprintf("   💡 Resolution: %s\n", issue.Resolution)
+				response += formatReferences(issue.References) + "\n"
 			}
 		} else if len(info) > 5 {
 			response += fmt.Sprintf("ℹ️ **Additional Information**: %d informational items found\n\n", len(info))
@@ -1988,6 +2526,32 @@ func (s *Server) formatAnalysisResult(result *diagnostics.AnalysisResult) string
 		response += "\n"
 	}
 
+	if len(result.Correlations) > 0 {
+		response += "🔗 **Correlated Events** (UTC):\n"
+		for i, correlation := range result.Correlations {
+			if i == 5 {
+				response += fmt.Sprintf("... and %d more\n", len(result.Correlations)-5)
+				break
+			}
+			response += fmt.Sprintf("- %s\n", correlation.Description)
+		}
+		response += "\n"
+	}
+
+	if len(result.Timeline) > 0 {
+		response += fmt.Sprintf("🕒 **Incident Timeline**: %d events from %s to %s\n\n", len(result.Timeline),
+			formatTime(ctx, result.Timeline[0].Time),
+			formatTime(ctx, result.Timeline[len(result.Timeline)-1].Time))
+	}
+
+	if result.Truncated {
+		response += "✂️ **Analysis truncated** - results are incomplete:\n"
+		for _, reason := range result.TruncationReasons {
+			response += fmt.Sprintf("- %s\n", reason)
+		}
+		response += "\n"
+	}
+
 	// Add metrics if available
 	if len(result.Metrics) > 0 {
 		response += "📈 **Metrics**:\n"
@@ -2055,7 +2619,9 @@ func (s *Server) applyYAMLContent(ctx context.Context, yamlContent, namespace st
 	}
 
 	// Execute the command
+	stop := trackPhase(ctx, PhaseExec)
 	output, err := cmd.CombinedOutput()
+	stop()
 	if err != nil {
 		return fmt.Errorf("kubectl/oc apply failed: %w\nOutput: %s", err, string(output))
 	}
@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of long-lived work the session registry tracks
+const (
+	SessionWatch       = "watch"
+	SessionPortForward = "port-forward"
+	SessionExec        = "exec"
+	SessionWarRoom     = "war-room"
+	// SessionToolCall is a handler still running after its tool call timed
+	// out; it cannot be stopped, only counted until it returns
+	SessionToolCall = "tool-call"
+)
+
+// SessionConfig bounds how long sessions may sit idle and how long finished
+// background jobs stay listed, so week-long server runs stay stable
+type SessionConfig struct {
+	IdleTimeout  string `json:"idle_timeout"`  // default 15m
+	ReapInterval string `json:"reap_interval"` // how often idle sessions are reaped, default 1m
+	JobRetention string `json:"job_retention"` // how long finished must-gather jobs are listed, default 24h
+}
+
+const (
+	sessionReaperJobName       = "session-reaper"
+	defaultSessionIdleTimeout  = 15 * time.Minute
+	defaultSessionReapInterval = time.Minute
+	defaultJobRetention        = 24 * time.Hour
+)
+
+// processStarted is when the server process started, for runtime_stats
+var processStarted = time.Now()
+
+// Session is a snapshot of a tracked watch, port-forward, exec or tool call
+type Session struct {
+	ID         string
+	Kind       string
+	Target     string
+	Started    time.Time
+	LastActive time.Time
+}
+
+// trackedSession is an open session; Close ends it exactly once however it
+// ends: by its handler, its TTL or the reaper
+type trackedSession struct {
+	Session
+	registry *sessionRegistry
+	cancel   func()
+	seq      int
+}
+
+// touch marks the session active so the reaper leaves it alone
+func (t *trackedSession) touch() {
+	t.registry.mu.Lock()
+	t.LastActive = t.registry.clock()
+	t.registry.mu.Unlock()
+}
+
+// Close forgets the session and stops its work. Closing again, including
+// from inside cancel, does nothing.
+func (t *trackedSession) Close() {
+	if t.registry.forget(t.ID) && t.cancel != nil {
+		t.cancel()
+	}
+}
+
+// sessionRegistry tracks open sessions by ID. The zero value is ready to use.
+type sessionRegistry struct {
+	mu          sync.Mutex
+	sessions    map[string]*trackedSession
+	seq         int
+	idleTimeout time.Duration
+	now         func() time.Time // overridden in tests
+}
+
+func (r *sessionRegistry) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// open registers a session; cancel stops its work when it is reaped and
+// may be nil for work that cannot be stopped
+func (r *sessionRegistry) open(kind, target string, cancel func()) *trackedSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions == nil {
+		r.sessions = make(map[string]*trackedSession)
+	}
+	r.seq++
+	now := r.clock()
+	session := &trackedSession{
+		Session:  Session{ID: fmt.Sprintf("%s-%d", kind, r.seq), Kind: kind, Target: target, Started: now, LastActive: now},
+		registry: r,
+		cancel:   cancel,
+		seq:      r.seq,
+	}
+	r.sessions[session.ID] = session
+	return session
+}
+
+// forget removes a session, reporting whether it was still registered
+func (r *sessionRegistry) forget(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sessions[id]; !ok {
+		return false
+	}
+	delete(r.sessions, id)
+	return true
+}
+
+// list returns the open sessions, oldest first
+func (r *sessionRegistry) list() []Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tracked := make([]*trackedSession, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		tracked = append(tracked, session)
+	}
+	sort.Slice(tracked, func(i, j int) bool { return tracked[i].seq < tracked[j].seq })
+	sessions := make([]Session, len(tracked))
+	for i, session := range tracked {
+		sessions[i] = session.Session
+	}
+	return sessions
+}
+
+// reap closes the sessions idle for longer than idle. Sessions that cannot
+// be stopped stay listed until they return.
+func (r *sessionRegistry) reap(idle time.Duration) []Session {
+	r.mu.Lock()
+	now := r.clock()
+	var stale []*trackedSession
+	var reaped []Session
+	for _, session := range r.sessions {
+		if session.cancel != nil && now.Sub(session.LastActive) > idle {
+			stale = append(stale, session)
+			reaped = append(reaped, session.Session)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, session := range stale {
+		session.Close()
+	}
+	return reaped
+}
+
+// sessionWriter marks a session active whenever output arrives
+type sessionWriter struct {
+	io.Writer
+	session *trackedSession
+}
+
+func (w sessionWriter) Write(p []byte) (int, error) {
+	w.session.touch()
+	return w.Writer.Write(p)
+}
+
+// sessionIdleTimeout returns how long a session may be idle before it is reaped
+func (s *Server) sessionIdleTimeout() time.Duration {
+	if s.sessions.idleTimeout > 0 {
+		return s.sessions.idleTimeout
+	}
+	return defaultSessionIdleTimeout
+}
+
+// parseSessionDuration parses a configured duration, falling back to def
+func parseSessionDuration(name, value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logrus.Warnf("Invalid session %s %q, using %s", name, value, def)
+		return def
+	}
+	return d
+}
+
+// initSessionReaper schedules reaping of idle sessions and pruning of
+// finished must-gather jobs
+func (s *Server) initSessionReaper(config *SessionConfig) {
+	if config == nil {
+		config = &SessionConfig{}
+	}
+	s.sessions.idleTimeout = parseSessionDuration("idle timeout", config.IdleTimeout, defaultSessionIdleTimeout)
+	interval := parseSessionDuration("reap interval", config.ReapInterval, defaultSessionReapInterval)
+	retention := parseSessionDuration("job retention", config.JobRetention, defaultJobRetention)
+
+	s.scheduler.AddJob(sessionReaperJobName, interval, func(ctx context.Context) error {
+		s.reapSessions(retention)
+		return nil
+	})
+}
+
+// reapSessions closes idle sessions and forgets old finished jobs
+func (s *Server) reapSessions(jobRetention time.Duration) {
+	idle := s.sessionIdleTimeout()
+	for _, session := range s.sessions.reap(idle) {
+		logrus.Infof("Reaped %s session %s (%s) after %s idle", session.Kind, session.ID, session.Target, idle)
+	}
+	if s.diagnosticCollector != nil {
+		if pruned := s.diagnosticCollector.PruneMustGathers(jobRetention); pruned > 0 {
+			logrus.Infof("Pruned %d must-gather job(s) finished more than %s ago", pruned, jobRetention)
+		}
+	}
+}
+
+func (s *Server) initRuntimeTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("runtime_stats",
+			mcp.WithDescription("Report the MCP server's runtime health for long-running deployments: goroutines, heap and garbage collection, open watches, port-forwards and exec sessions with their idle times, and background jobs"),
+			mcp.WithTitleAnnotation("Server: Runtime Stats"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.runtimeStatsHandler)},
+	}
+}
+
+func (s *Server) runtimeStatsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	now := time.Now()
+
+	result := "📈 Runtime Stats\n"
+	result += "================\n\n"
+	result += fmt.Sprintf("⏱️  Uptime: %s\n", now.Sub(processStarted).Round(time.Second))
+	result += fmt.Sprintf("🧵 Goroutines: %d\n", runtime.NumGoroutine())
+	result += fmt.Sprintf("💾 Heap: %s allocated, %s in use, %s from the OS, %d objects\n",
+		formatMB(int64(mem.HeapAlloc)), formatMB(int64(mem.HeapInuse)), formatMB(int64(mem.HeapSys)), mem.HeapObjects)
+	result += fmt.Sprintf("♻️  GC: %d cycles", mem.NumGC)
+	if mem.NumGC > 0 {
+		result += fmt.Sprintf(", last pause %s", time.Duration(mem.PauseNs[(mem.NumGC+255)%256]))
+	}
+	result += "\n"
+
+	sessions := s.sessions.list()
+	result += fmt.Sprintf("\n🔗 Open Sessions: %d (reaped after %s idle)\n", len(sessions), s.sessionIdleTimeout())
+	if len(sessions) > 0 {
+		counts := make(map[string]int)
+		for _, session := range sessions {
+			counts[session.Kind]++
+		}
+		var kinds []string
+		for _, kind := range sortedKeys(counts) {
+			kinds = append(kinds, fmt.Sprintf("%s %d", kind, counts[kind]))
+		}
+		result += fmt.Sprintf("  %s\n", strings.Join(kinds, ", "))
+		for _, session := range sessions {
+			result += fmt.Sprintf("  • %s %s: open %s, idle %s\n", session.ID, session.Target,
+				now.Sub(session.Started).Round(time.Second), now.Sub(session.LastActive).Round(time.Second))
+		}
+	}
+
+	result += "\n🗓️  Background Jobs:\n"
+	if s.scheduler != nil {
+		jobs := s.scheduler.Jobs()
+		leaderOnly := 0
+		for _, job := range jobs {
+			if job.LeaderOnly {
+				leaderOnly++
+			}
+		}
+		result += fmt.Sprintf("  • %d scheduled job(s), %d leader-only\n", len(jobs), leaderOnly)
+		result += "  " + s.describeLeadership(ctx)
+	}
+	if s.diagnosticCollector != nil {
+		running := 0
+		jobs := s.diagnosticCollector.MustGathers()
+		for _, job := range jobs {
+			if !job.Done() {
+				running++
+			}
+		}
+		result += fmt.Sprintf("  • %d must-gather job(s) listed, %d running\n", len(jobs), running)
+	}
+
+	if stuck := countSessions(sessions, SessionToolCall); stuck > 0 {
+		result += fmt.Sprintf("\n⚠️  %d tool call(s) still running after their timeout; their handlers ignore cancellation", stuck)
+	} else {
+		result += "\n✅ No tool calls outlived their timeout"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// countSessions returns how many sessions are of a kind
+func countSessions(sessions []Session, kind string) int {
+	n := 0
+	for _, session := range sessions {
+		if session.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// RuntimeStatsHandler is a public wrapper for runtimeStatsHandler
+func (s *Server) RuntimeStatsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.runtimeStatsHandler(ctx, request)
+}
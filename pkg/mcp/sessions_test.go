@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSessionRegistry(t *testing.T) {
+	clock := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	r := &sessionRegistry{now: func() time.Time { return clock }}
+
+	cancelled := map[string]int{}
+	watch := r.open(SessionWatch, "pods in shop", func() { cancelled["watch"]++ })
+	exec := r.open(SessionExec, "shop/api-1/api: ls", func() { cancelled["exec"]++ })
+	stuck := r.open(SessionToolCall, "get_logs", nil)
+	if list := r.list(); len(list) != 3 || list[0].ID != watch.ID {
+		t.Fatalf("list() = %+v, expected 3 sessions, oldest first", list)
+	}
+
+	clock = clock.Add(10 * time.Minute)
+	exec.touch()
+	clock = clock.Add(10 * time.Minute)
+	reaped := r.reap(15 * time.Minute)
+	if len(reaped) != 1 || reaped[0].ID != watch.ID || cancelled["watch"] != 1 {
+		t.Errorf("reap() = %+v, cancelled %v, expected only the idle watch", reaped, cancelled)
+	}
+	if list := r.list(); len(list) != 2 {
+		t.Errorf("list() after reaping = %+v, expected the exec session and the stuck tool call", list)
+	}
+
+	// Sessions that cannot be stopped are never reaped, only closed
+	clock = clock.Add(time.Hour)
+	if reaped := r.reap(15 * time.Minute); len(reaped) != 1 || reaped[0].ID != exec.ID {
+		t.Errorf("reap() = %+v, expected only the exec session", reaped)
+	}
+	stuck.Close()
+	exec.Close()
+	watch.Close()
+	if len(r.list()) != 0 || cancelled["exec"] != 1 || cancelled["watch"] != 1 {
+		t.Errorf("closing again cancelled %v with %d sessions left", cancelled, len(r.list()))
+	}
+}
+
+func TestReapPortForward(t *testing.T) {
+	var stopped chan struct{}
+	s := &Server{
+		config:    &Config{},
+		k8sClient: kubefake.NewSimpleClientset(readyPod("web-b", nil)),
+		portForward: func(namespace, pod string, port int, stop chan struct{}) (int, error) {
+			stopped = stop
+			return 18080, nil
+		},
+	}
+	clock := time.Now()
+	s.sessions.now = func() time.Time { return clock }
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop", "pod_name": "web-b", "port": "8080", "ttl_seconds": "600"}
+	mustCall(t, s.portForwardHandler, request)
+	if list := s.sessions.list(); len(list) != 1 || list[0].Kind != SessionPortForward || list[0].Target != "shop/web-b:8080 via 127.0.0.1:18080" {
+		t.Fatalf("sessions = %+v, expected the port-forward", list)
+	}
+
+	clock = clock.Add(defaultSessionIdleTimeout + time.Minute)
+	s.reapSessions(defaultJobRetention)
+	select {
+	case <-stopped:
+	default:
+		t.Error("the reaped port-forward was not stopped")
+	}
+	if open := s.portForwards.list(); len(open) != 0 || len(s.sessions.list()) != 0 {
+		t.Errorf("after reaping: port-forwards %+v, sessions %+v", open, s.sessions.list())
+	}
+}
+
+func TestRuntimeStats(t *testing.T) {
+	s := &Server{config: &Config{}, scheduler: NewJobScheduler()}
+	s.initSessionReaper(&SessionConfig{IdleTimeout: "5m", ReapInterval: "soon"})
+	s.sessions.open(SessionWatch, "deployment/api in shop", func() {})
+
+	text := resultText(mustCall(t, s.runtimeStatsHandler, mcp.CallToolRequest{}))
+	for _, want := range []string{"🧵 Goroutines: ", "💾 Heap: ", "🔗 Open Sessions: 1 (reaped after 5m0s idle)", "  watch 1\n",
+		"• watch-1 deployment/api in shop: open ", "• 1 scheduled job(s)", "✅ No tool calls outlived their timeout"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("runtime_stats missing %q:\n%s", want, text)
+		}
+	}
+	if jobs := s.scheduler.Jobs(); len(jobs) != 1 || jobs[0].Interval != defaultSessionReapInterval {
+		t.Errorf("scheduled jobs = %+v, expected the reaper at the default interval", jobs)
+	}
+
+	s.sessions.open(SessionToolCall, "get_logs", nil)
+	if text := resultText(mustCall(t, s.runtimeStatsHandler, mcp.CallToolRequest{})); !strings.Contains(text, "⚠️  1 tool call(s) still running after their timeout") {
+		t.Errorf("runtime_stats with a stuck tool call:\n%s", text)
+	}
+}
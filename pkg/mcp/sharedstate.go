@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/store"
+)
+
+const (
+	// sharedStoreTimeout bounds one operation against the shared store
+	sharedStoreTimeout = 5 * time.Second
+	// runningJobTTL expires a running job's shared state when its replica
+	// stops publishing it, e.g. because the replica was killed
+	runningJobTTL = time.Minute
+	// mustGatherStartLock serializes must-gather starts across replicas
+	mustGatherStartLock = "must-gather-start"
+)
+
+// SetStore shares grant_elevation approvals, remediation approvals and
+// must-gather job state with the other replicas through st
+func (s *Server) SetStore(st store.Store) {
+	s.store = st
+	s.elevations.store = st
+	s.remediations.store = st
+}
+
+func mustGatherKey(id string) string {
+	return "must-gather/" + id
+}
+
+func mustGatherCancelKey(id string) string {
+	return "must-gather-cancel/" + id
+}
+
+// lockMustGatherStart holds the start lock so two replicas cannot both
+// start a must-gather; without a shared store it does nothing
+func (s *Server) lockMustGatherStart(ctx context.Context) (func(), error) {
+	if !store.Shared(s.store) {
+		return func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, sharedStoreTimeout)
+	defer cancel()
+	return store.Acquire(ctx, s.store, mustGatherStartLock, sharedStoreTimeout)
+}
+
+// publishMustGather writes a local job's state for the other replicas,
+// reporting whether the job has finished
+func (s *Server) publishMustGather(id string) bool {
+	job, ok := s.diagnosticCollector.MustGather(id)
+	if !ok {
+		return true
+	}
+	ttl := runningJobTTL
+	if job.Done() {
+		ttl = defaultJobRetention
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+	if err := store.SetJSON(ctx, s.store, mustGatherKey(id), job, ttl); err != nil {
+		logrus.WithError(err).Warnf("Failed to publish must-gather %s", id)
+	}
+	return job.Done()
+}
+
+// followMustGather publishes a local job until it finishes and cancels it
+// when another replica asks to
+func (s *Server) followMustGather(id string) {
+	ticker := time.NewTicker(mustGatherPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+		_, requested, err := s.store.Get(ctx, mustGatherCancelKey(id))
+		cancel()
+		if err == nil && requested {
+			logrus.Infof("Cancelling must-gather %s as another replica asked", id)
+			s.diagnosticCollector.CancelMustGather(id)
+		}
+		if s.publishMustGather(id) {
+			return
+		}
+	}
+}
+
+// sharedMustGather returns a job another replica published
+func (s *Server) sharedMustGather(id string) (*diagnostics.MustGatherJob, bool) {
+	if !store.Shared(s.store) {
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+	var job diagnostics.MustGatherJob
+	ok, err := store.GetJSON(ctx, s.store, mustGatherKey(id), &job)
+	if err != nil {
+		logrus.WithError(err).Warnf("Failed to read must-gather %s", id)
+	}
+	return &job, ok
+}
+
+// sharedMustGathers returns the jobs other replicas published, newest first
+func (s *Server) sharedMustGathers() []*diagnostics.MustGatherJob {
+	if !store.Shared(s.store) {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+	keys, err := s.store.Keys(ctx, mustGatherKey(""))
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to list shared must-gather jobs")
+		return nil
+	}
+	var jobs []*diagnostics.MustGatherJob
+	for _, key := range keys {
+		var job diagnostics.MustGatherJob
+		if ok, _ := store.GetJSON(ctx, s.store, key, &job); !ok {
+			continue
+		}
+		if _, local := s.diagnosticCollector.MustGather(job.ID); !local {
+			jobs = append(jobs, &job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Started.After(jobs[j].Started) })
+	return jobs
+}
+
+// requestMustGatherCancel asks the replica running a job to cancel it
+func (s *Server) requestMustGatherCancel(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+	return s.store.Set(ctx, mustGatherCancelKey(id), []byte(time.Now().UTC().Format(time.RFC3339)), defaultJobRetention)
+}
+
+// runningElsewhere returns a must-gather another replica is running
+func (s *Server) runningElsewhere() *diagnostics.MustGatherJob {
+	for _, job := range s.sharedMustGathers() {
+		if !job.Done() {
+			return job
+		}
+	}
+	return nil
+}
+
+// describeSharedJob notes where a job published by another replica runs
+func describeSharedJob(job *diagnostics.MustGatherJob) string {
+	if job.Done() {
+		return "🔁 Collected by another replica; its data is on that replica's disk\n\n"
+	}
+	return fmt.Sprintf("🔁 Running on another replica; status is refreshed every %s\n\n", mustGatherPollInterval)
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxStepOutputs bounds how many full step outputs are kept in memory
+	maxStepOutputs = 200
+	// stepOutputTTL is how long a full step output can be paged through
+	stepOutputTTL = 2 * time.Hour
+	// DefaultStepOutputLines is the page size of get_step_output
+	DefaultStepOutputLines = 200
+)
+
+// storedOutput is the full output of one chat execution step
+type storedOutput struct {
+	tool   string
+	lines  []string
+	stored time.Time
+}
+
+// stepOutputStore keeps the full output of truncated chat steps, oldest
+// evicted first
+type stepOutputStore struct {
+	mu      sync.Mutex
+	outputs map[string]storedOutput
+	order   []string
+}
+
+func newStepOutputStore() *stepOutputStore {
+	return &stepOutputStore{outputs: make(map[string]storedOutput)}
+}
+
+func (st *stepOutputStore) put(id, tool, output string, now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.outputs[id]; !ok {
+		st.order = append(st.order, id)
+	}
+	st.outputs[id] = storedOutput{tool: tool, lines: strings.Split(output, "\n"), stored: now}
+	for len(st.order) > maxStepOutputs || (len(st.order) > 0 && now.Sub(st.outputs[st.order[0]].stored) > stepOutputTTL) {
+		delete(st.outputs, st.order[0])
+		st.order = st.order[1:]
+	}
+}
+
+func (st *stepOutputStore) get(id string, now time.Time) (storedOutput, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	output, ok := st.outputs[id]
+	if !ok || now.Sub(output.stored) > stepOutputTTL {
+		return storedOutput{}, false
+	}
+	return output, true
+}
+
+// StepOutputPage is a range of lines of a stored step output
+type StepOutputPage struct {
+	StepID     string `json:"step_id"`
+	Tool       string `json:"tool"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	TotalLines int    `json:"total_lines"`
+	Output     string `json:"output"`
+	NextOffset int    `json:"next_offset,omitempty"` // 0 on the last page
+}
+
+// StoreStepOutput keeps the full output of a chat step so it can be paged
+// through with get_step_output
+func (s *Server) StoreStepOutput(stepID, tool, output string) {
+	if s.stepOutputs == nil {
+		return
+	}
+	s.stepOutputs.put(stepID, tool, output, time.Now())
+}
+
+// StepOutput returns limit lines of a stored step output starting at the
+// zero-based line offset
+func (s *Server) StepOutput(stepID string, offset, limit int) (StepOutputPage, bool) {
+	if s.stepOutputs == nil {
+		return StepOutputPage{}, false
+	}
+	output, ok := s.stepOutputs.get(stepID, time.Now())
+	if !ok {
+		return StepOutputPage{}, false
+	}
+	if limit <= 0 {
+		limit = DefaultStepOutputLines
+	}
+	total := len(output.lines)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	page := StepOutputPage{
+		StepID:     stepID,
+		Tool:       output.tool,
+		Offset:     offset,
+		Limit:      limit,
+		TotalLines: total,
+		Output:     strings.Join(output.lines[offset:end], "\n"),
+	}
+	if end < total {
+		page.NextOffset = end
+	}
+	return page, true
+}
+
+func (s *Server) getStepOutputHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stepID := strings.TrimSpace(mcp.ParseString(request, "step_id", ""))
+	if stepID == "" {
+		return mcp.NewToolResultText("❌ step_id is required"), nil
+	}
+	offset, limit := 0, DefaultStepOutputLines
+	for _, param := range []struct {
+		name   string
+		target *int
+		min    int
+	}{{"offset", &offset, 0}, {"limit", &limit, 1}} {
+		value := mcp.ParseString(request, param.name, "")
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < param.min {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid %s '%s': expected a number of at least %d", param.name, value, param.min)), nil
+		}
+		*param.target = parsed
+	}
+
+	page, ok := s.StepOutput(stepID, offset, limit)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ No stored output for step %s; outputs are kept for %s", stepID, stepOutputTTL)), nil
+	}
+
+	last := page.Offset + strings.Count(page.Output, "\n") + 1
+	if page.Offset >= page.TotalLines {
+		last = page.Offset
+	}
+	result := fmt.Sprintf("📄 Step output %s (%s): lines %d-%d of %d\n", page.StepID, page.Tool, page.Offset+1, last, page.TotalLines)
+	result += "=====================\n\n"
+	result += page.Output + "\n"
+	if page.NextOffset > 0 {
+		result += fmt.Sprintf("\n💡 More output: use get_step_output step_id=%s offset=%d", page.StepID, page.NextOffset)
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// GetStepOutputHandler is a public wrapper for getStepOutputHandler
+func (s *Server) GetStepOutputHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getStepOutputHandler(ctx, request)
+}
+
+func (s *Server) initStepOutputTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("get_step_output",
+			mcp.WithDescription("Page through the full output of a chat step whose result was truncated"),
+			mcp.WithString("step_id", mcp.Required(), mcp.Description("Step ID from the truncated step result")),
+			mcp.WithString("offset", mcp.Description("Zero-based line to start from (default 0)")),
+			mcp.WithString("limit", mcp.Description(fmt.Sprintf("Number of lines to return (default %d)", DefaultStepOutputLines))),
+			mcp.WithTitleAnnotation("Server: Step Output"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getStepOutputHandler)},
+	}
+}
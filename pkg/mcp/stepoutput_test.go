@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestStepOutputStoreEviction(t *testing.T) {
+	store := newStepOutputStore()
+	now := time.Now()
+	store.put("old", "list_pods", "x", now.Add(-3*time.Hour))
+	for i := 0; i < maxStepOutputs; i++ {
+		store.put(fmt.Sprintf("step-%d", i), "list_pods", "x", now)
+	}
+	store.put("latest", "list_pods", "x", now)
+
+	if _, ok := store.get("old", now); ok {
+		t.Errorf("expired output was not evicted")
+	}
+	if _, ok := store.get("step-0", now); ok {
+		t.Errorf("oldest output was not evicted beyond %d entries", maxStepOutputs)
+	}
+	if _, ok := store.get("latest", now); !ok {
+		t.Errorf("latest output was evicted")
+	}
+	if _, ok := store.get("latest", now.Add(3*time.Hour)); ok {
+		t.Errorf("output returned after its TTL")
+	}
+}
+
+func TestGetStepOutput(t *testing.T) {
+	s := &Server{stepOutputs: newStepOutputStore()}
+	var lines []string
+	for i := 1; i <= 5; i++ {
+		lines = append(lines, fmt.Sprintf("pod-%d Running", i))
+	}
+	s.StoreStepOutput("plan-1-step-1", "list_pods", strings.Join(lines, "\n"))
+
+	page, ok := s.StepOutput("plan-1-step-1", 3, 10)
+	if !ok || page.Output != "pod-4 Running\npod-5 Running" || page.TotalLines != 5 || page.NextOffset != 0 {
+		t.Errorf("StepOutput(offset 3) = %+v, %v", page, ok)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"step_id": "plan-1-step-1", "offset": "1", "limit": "2"}
+	result, err := s.GetStepOutputHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GetStepOutputHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"lines 2-3 of 5",
+		"pod-2 Running\npod-3 Running",
+		"use get_step_output step_id=plan-1-step-1 offset=3",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("get_step_output output missing %q:\n%s", want, text)
+		}
+	}
+
+	request.Params.Arguments = map[string]interface{}{"step_id": "plan-9-step-1"}
+	result, _ = s.GetStepOutputHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌ No stored output for step plan-9-step-1") {
+		t.Errorf("get_step_output for an unknown step = %q", text)
+	}
+}
@@ -0,0 +1,645 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	selectedNodeAnnotation        = "volume.kubernetes.io/selected-node"
+	storageProvisionerAnnotation  = "volume.kubernetes.io/storage-provisioner"
+	noProvisioner                 = "kubernetes.io/no-provisioner"
+)
+
+// volumeEventReasons are the warning events that explain why a claim does
+// not bind or its volume does not attach or mount
+var volumeEventReasons = map[string]bool{
+	"ProvisioningFailed": true,
+	"FailedBinding":      true,
+	"FailedAttachVolume": true,
+	"FailedMount":        true,
+	"FailedMapVolume":    true,
+	"VolumeResizeFailed": true,
+}
+
+var accessModeNames = map[corev1.PersistentVolumeAccessMode]string{
+	corev1.ReadWriteOnce:    "RWO",
+	corev1.ReadOnlyMany:     "ROX",
+	corev1.ReadWriteMany:    "RWX",
+	corev1.ReadWriteOncePod: "RWOP",
+}
+
+func formatAccessModes(modes []corev1.PersistentVolumeAccessMode) string {
+	names := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		if name, ok := accessModeNames[mode]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, string(mode))
+		}
+	}
+	return valueOrNone(strings.Join(names, ","))
+}
+
+// pvcStorageClass returns the claim's class; nil means the default class and
+// an empty string means static binding only
+func pvcStorageClass(pvc *corev1.PersistentVolumeClaim) *string {
+	if pvc.Spec.StorageClassName != nil {
+		return pvc.Spec.StorageClassName
+	}
+	if class, ok := pvc.Annotations[corev1.BetaStorageClassAnnotation]; ok {
+		return &class
+	}
+	return nil
+}
+
+func formatStorageClass(class *string) string {
+	switch {
+	case class == nil:
+		return "(cluster default)"
+	case *class == "":
+		return `"" (static binding only)`
+	}
+	return *class
+}
+
+func pvcRequest(pvc *corev1.PersistentVolumeClaim) string {
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	return request.String()
+}
+
+func pvcCapacity(pvc *corev1.PersistentVolumeClaim) string {
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return capacity.String()
+	}
+	return pvcRequest(pvc) + " requested"
+}
+
+func pvcIcon(phase corev1.PersistentVolumeClaimPhase) string {
+	switch phase {
+	case corev1.ClaimBound:
+		return "✅"
+	case corev1.ClaimLost:
+		return "❌"
+	}
+	return "⚠️ "
+}
+
+// pvcConsumers returns the pods mounting a claim
+func pvcConsumers(pods []corev1.Pod, claim string) []*corev1.Pod {
+	var consumers []*corev1.Pod
+	for i := range pods {
+		for _, volume := range pods[i].Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim {
+				consumers = append(consumers, &pods[i])
+				break
+			}
+		}
+	}
+	return consumers
+}
+
+// objectEvents returns the events about an object, oldest first
+func (s *Server) objectEvents(ctx context.Context, namespace, kind, name string) []corev1.Event {
+	events, err := s.k8sClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	var matched []corev1.Event
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind == kind && event.InvolvedObject.Name == name {
+			matched = append(matched, event)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].LastTimestamp.Before(&matched[j].LastTimestamp) })
+	return matched
+}
+
+// volumeEvents returns the storage-related events about a claim and the pods mounting it
+func (s *Server) volumeEvents(ctx context.Context, pvc *corev1.PersistentVolumeClaim, consumers []*corev1.Pod) []corev1.Event {
+	events := s.objectEvents(ctx, pvc.Namespace, "PersistentVolumeClaim", pvc.Name)
+	for _, pod := range consumers {
+		for _, event := range s.objectEvents(ctx, pod.Namespace, "Pod", pod.Name) {
+			if volumeEventReasons[event.Reason] {
+				events = append(events, event)
+			}
+		}
+	}
+	return events
+}
+
+func formatObjectEvent(event corev1.Event) string {
+	icon := "ℹ️ "
+	if event.Type == corev1.EventTypeWarning {
+		icon = "⚠️ "
+	}
+	return fmt.Sprintf("%s %s/%s %s: %s", icon, strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, event.Reason, event.Message)
+}
+
+// pvMatchesClaim reports whether an available volume could bind a claim
+func pvMatchesClaim(pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim, class string) bool {
+	if pv.Status.Phase != corev1.VolumeAvailable || pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.Name != pvc.Name {
+		return false
+	}
+	if pv.Spec.StorageClassName != class {
+		return false
+	}
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+	if request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; capacity.Cmp(request) < 0 {
+		return false
+	}
+	for _, mode := range pvc.Spec.AccessModes {
+		found := false
+		for _, offered := range pv.Spec.AccessModes {
+			if offered == mode {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if pvc.Spec.VolumeMode != nil && pv.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode != *pv.Spec.VolumeMode {
+		return false
+	}
+	if pvc.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(pvc.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(pv.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultStorageClass returns the class marked as the cluster default, or nil
+func defaultStorageClass(classes []storagev1.StorageClass) *storagev1.StorageClass {
+	for i := range classes {
+		if classes[i].Annotations[defaultStorageClassAnnotation] == "true" {
+			return &classes[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) initStorage() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("list_pvcs",
+			mcp.WithDescription("List PersistentVolumeClaims with binding status, bound volume, capacity, access modes and storage class"),
+			mcp.WithString("namespace", mcp.Description("Namespace to list claims from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=postgres")),
+			mcp.WithTitleAnnotation("Storage: List PVCs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.listPVCsHandler)},
+		{Tool: mcp.NewTool("get_pvc",
+			mcp.WithDescription("Show a PersistentVolumeClaim with its bound volume, storage class, the pods mounting it and recent volume events"),
+			mcp.WithString("pvc_name", mcp.Description("Name of the claim"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the claim (default: default)")),
+			mcp.WithTitleAnnotation("Storage: Get PVC"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.getPVCHandler)},
+		{Tool: mcp.NewTool("diagnose_pvc",
+			mcp.WithDescription("Explain why a PersistentVolumeClaim is not bound or its volume does not attach or mount: missing storage class, no matching PV, WaitForFirstConsumer, CSI driver and provisioning failures"),
+			mcp.WithString("pvc_name", mcp.Description("Name of the claim"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace of the claim (default: default)")),
+			mcp.WithTitleAnnotation("Storage: Diagnose PVC"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.diagnosePVCHandler)},
+	}
+}
+
+func (s *Server) listPVCsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	pvcs, err := s.k8sClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list PVCs in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	sort.SliceStable(pvcs.Items, func(i, j int) bool {
+		a, b := pvcs.Items[i], pvcs.Items[j]
+		if (a.Status.Phase == corev1.ClaimBound) != (b.Status.Phase == corev1.ClaimBound) {
+			return b.Status.Phase == corev1.ClaimBound
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	result := "💾 PVC List Results\n"
+	result += "===================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+
+	unbound := 0
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			unbound++
+		}
+	}
+	result += fmt.Sprintf("📦 Found %d PVCs", len(pvcs.Items))
+	if unbound > 0 {
+		result += fmt.Sprintf(", ⚠️  %d not bound", unbound)
+	}
+	result += ":\n"
+
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		name := pvc.Name
+		if namespace == metav1.NamespaceAll {
+			name = pvc.Namespace + "/" + pvc.Name
+		}
+		status := string(pvc.Status.Phase)
+		if pvc.Spec.VolumeName != "" {
+			status += " to " + pvc.Spec.VolumeName
+		}
+		result += fmt.Sprintf("%s %s - %s, %s, %s, class %s, age %s\n", pvcIcon(pvc.Status.Phase), name, status,
+			pvcCapacity(pvc), formatAccessModes(pvc.Spec.AccessModes), formatStorageClass(pvcStorageClass(pvc)), formatAge(pvc.CreationTimestamp.Time))
+	}
+	if len(pvcs.Items) == 0 {
+		result += "📭 None found\n"
+	}
+	if unbound > 0 {
+		result += "\n💡 Run diagnose_pvc on a claim that is not bound to find out why\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// getClaim reads the pvc_name and namespace parameters and fetches the claim
+func (s *Server) getClaim(ctx context.Context, request mcp.CallToolRequest) (*corev1.PersistentVolumeClaim, *mcp.CallToolResult) {
+	if s.k8sClient == nil {
+		return nil, mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig.")
+	}
+	name := strings.TrimSpace(mcp.ParseString(request, "pvc_name", ""))
+	if name == "" {
+		return nil, mcp.NewToolResultText("❌ pvc_name is required")
+	}
+	namespace := mcp.ParseString(request, "namespace", "default")
+
+	pvc, err := s.k8sClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, toolError(ctx, fmt.Sprintf("Failed to get PVC %s/%s", namespace, name), err)
+	}
+	return pvc, nil
+}
+
+// claimConsumers lists the pods in the claim's namespace that mount it
+func (s *Server) claimConsumers(ctx context.Context, pvc *corev1.PersistentVolumeClaim) ([]*corev1.Pod, error) {
+	pods, err := s.k8sClient.CoreV1().Pods(pvc.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return pvcConsumers(pods.Items, pvc.Name), nil
+}
+
+func (s *Server) getPVCHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pvc, failure := s.getClaim(ctx, request)
+	if failure != nil {
+		return failure, nil
+	}
+	consumers, err := s.claimConsumers(ctx, pvc)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list pods in namespace %s", pvc.Namespace), err), nil
+	}
+
+	result := fmt.Sprintf("💾 PVC %s/%s\n", pvc.Namespace, pvc.Name)
+	result += "====================\n\n"
+	result += fmt.Sprintf("%s Status: %s\n", pvcIcon(pvc.Status.Phase), pvc.Status.Phase)
+	result += fmt.Sprintf("Volume: %s\n", valueOrNone(pvc.Spec.VolumeName))
+	result += fmt.Sprintf("Requested: %s, Capacity: %s\n", pvcRequest(pvc), pvcCapacity(pvc))
+	result += fmt.Sprintf("Access modes: %s\n", formatAccessModes(pvc.Spec.AccessModes))
+	result += fmt.Sprintf("Storage class: %s\n", formatStorageClass(pvcStorageClass(pvc)))
+	if pvc.Spec.VolumeMode != nil {
+		result += fmt.Sprintf("Volume mode: %s\n", *pvc.Spec.VolumeMode)
+	}
+	if provisioner := pvc.Annotations[storageProvisionerAnnotation]; provisioner != "" {
+		result += fmt.Sprintf("Provisioner: %s\n", provisioner)
+	}
+	if node := pvc.Annotations[selectedNodeAnnotation]; node != "" {
+		result += fmt.Sprintf("Selected node: %s\n", node)
+	}
+	result += fmt.Sprintf("Age: %s\n", formatAge(pvc.CreationTimestamp.Time))
+
+	if pvc.Spec.VolumeName != "" {
+		if pv, err := s.k8sClient.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{}); err == nil {
+			result += fmt.Sprintf("\n📀 Volume %s:\n", pv.Name)
+			result += fmt.Sprintf("• Phase: %s, Reclaim policy: %s\n", pv.Status.Phase, pv.Spec.PersistentVolumeReclaimPolicy)
+			if pv.Spec.CSI != nil {
+				result += fmt.Sprintf("• CSI driver: %s, Handle: %s\n", pv.Spec.CSI.Driver, pv.Spec.CSI.VolumeHandle)
+			}
+		}
+	}
+
+	result += "\n📦 Mounted by:\n"
+	if len(consumers) == 0 {
+		result += "• No pods\n"
+	}
+	for _, pod := range consumers {
+		result += fmt.Sprintf("• %s (%s, node %s)\n", pod.Name, pod.Status.Phase, valueOrNone(pod.Spec.NodeName))
+	}
+
+	events := s.volumeEvents(ctx, pvc, consumers)
+	result += "\n📜 Volume events:\n"
+	if len(events) == 0 {
+		result += "• None\n"
+	}
+	for _, event := range events {
+		result += formatObjectEvent(event) + "\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// diagnosticFinding is a diagnosed problem and the way to fix it
+type diagnosticFinding struct {
+	problem string
+	fix     string
+}
+
+// diagnoseClaim inspects a claim, its class, volumes, CSI driver, consumers
+// and events and returns the problems found
+func (s *Server) diagnoseClaim(ctx context.Context, pvc *corev1.PersistentVolumeClaim, consumers []*corev1.Pod, events []corev1.Event) []diagnosticFinding {
+	var findings []diagnosticFinding
+	client := s.k8sClient
+
+	var class *storagev1.StorageClass
+	classes, err := client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err == nil {
+		switch name := pvcStorageClass(pvc); {
+		case name == nil:
+			class = defaultStorageClass(classes.Items)
+			if class == nil && pvc.Status.Phase == corev1.ClaimPending {
+				findings = append(findings, diagnosticFinding{
+					"The claim names no storage class and the cluster has no default class, so only a pre-created PV can bind it",
+					fmt.Sprintf("Set storageClassName, or mark a class as default with the %s=true annotation", defaultStorageClassAnnotation),
+				})
+			}
+		case *name != "":
+			for i := range classes.Items {
+				if classes.Items[i].Name == *name {
+					class = &classes.Items[i]
+				}
+			}
+			if class == nil {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("Storage class %s does not exist", *name),
+					"Recreate the claim with one of the existing storage classes (oc get storageclass)",
+				})
+			}
+		}
+	}
+
+	if pvc.Status.Phase == corev1.ClaimPending {
+		findings = append(findings, s.diagnosePendingClaim(ctx, pvc, class, consumers)...)
+	}
+	if pvc.Status.Phase == corev1.ClaimLost {
+		findings = append(findings, diagnosticFinding{
+			fmt.Sprintf("The claim lost its volume %s, which was deleted or rebound", pvc.Spec.VolumeName),
+			"Restore the volume from a backup or snapshot, then recreate the claim; the data on the lost volume is not reachable",
+		})
+	}
+
+	// A CSI driver missing from the cluster cannot provision, attach or mount
+	provisioner := pvc.Annotations[storageProvisionerAnnotation]
+	if provisioner == "" && class != nil {
+		provisioner = class.Provisioner
+	}
+	if provisioner != "" && provisioner != noProvisioner && !strings.HasPrefix(provisioner, "kubernetes.io/") {
+		_, err := client.StorageV1().CSIDrivers().Get(ctx, provisioner, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("CSI driver %s is not registered in the cluster", provisioner),
+				"Install or repair the storage operator providing the driver and check its controller and node pods",
+			})
+		} else if err == nil {
+			findings = append(findings, s.unregisteredCSINodes(ctx, provisioner, consumers)...)
+		}
+	}
+
+	if pvc.Status.Phase == corev1.ClaimBound && pvc.Spec.VolumeName != "" {
+		attachments, err := client.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for _, attachment := range attachments.Items {
+				source := attachment.Spec.Source.PersistentVolumeName
+				if source == nil || *source != pvc.Spec.VolumeName || attachment.Status.AttachError == nil {
+					continue
+				}
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("Attaching the volume to node %s fails: %s", attachment.Spec.NodeName, attachment.Status.AttachError.Message),
+					fmt.Sprintf("Check the %s controller logs and whether the cloud disk is still attached to another node", attachment.Spec.Attacher),
+				})
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning || seen[event.Reason] {
+			continue
+		}
+		seen[event.Reason] = true
+		switch {
+		case event.Reason == "ProvisioningFailed":
+			findings = append(findings, diagnosticFinding{
+				"Provisioning failed: " + event.Message,
+				"Check the storage class parameters, cloud quotas and the CSI controller logs",
+			})
+		case strings.Contains(event.Message, "Multi-Attach"):
+			findings = append(findings, diagnosticFinding{
+				"The volume is still attached to another node: " + event.Message,
+				"A ReadWriteOnce volume attaches to one node at a time; wait for or delete the old pod, or use a Recreate deployment strategy",
+			})
+		case event.Reason == "FailedAttachVolume" || event.Reason == "FailedMount" || event.Reason == "FailedMapVolume":
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("%s on %s: %s", event.Reason, event.InvolvedObject.Name, event.Message),
+				"Check the CSI node pod on the pod's node and the kubelet logs for the volume",
+			})
+		case event.Reason == "VolumeResizeFailed":
+			findings = append(findings, diagnosticFinding{
+				"Volume expansion failed: " + event.Message,
+				"Check that the storage class allows volume expansion and the CSI resizer logs",
+			})
+		}
+	}
+	return findings
+}
+
+// unregisteredCSINodes reports the nodes of the consumers on which a CSI
+// driver's node plugin has not registered with the kubelet, so the volume
+// cannot be mounted there
+func (s *Server) unregisteredCSINodes(ctx context.Context, driver string, consumers []*corev1.Pod) []diagnosticFinding {
+	var findings []diagnosticFinding
+	checked := make(map[string]bool)
+	for _, pod := range consumers {
+		node := pod.Spec.NodeName
+		if node == "" || checked[node] {
+			continue
+		}
+		checked[node] = true
+		csiNode, err := s.k8sClient.StorageV1().CSINodes().Get(ctx, node, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		registered := false
+		for _, registration := range csiNode.Spec.Drivers {
+			if registration.Name == driver {
+				registered = true
+			}
+		}
+		if !registered {
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("The node plugin of CSI driver %s is not registered on node %s, where %s runs, so the volume cannot be mounted there", driver, node, pod.Name),
+				"Check the driver's node pod on that node; collect_storage_diagnostics gathers the driver pods' health and logs",
+			})
+		}
+	}
+	return findings
+}
+
+// diagnosePendingClaim explains why a claim has not bound yet
+func (s *Server) diagnosePendingClaim(ctx context.Context, pvc *corev1.PersistentVolumeClaim, class *storagev1.StorageClass, consumers []*corev1.Pod) []diagnosticFinding {
+	var findings []diagnosticFinding
+
+	if pvc.Spec.VolumeName != "" {
+		pv, err := s.k8sClient.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("The claim requests volume %s, which does not exist", pvc.Spec.VolumeName),
+				"Create the PV or remove volumeName from the claim",
+			})
+		case err == nil && pv.Spec.ClaimRef != nil && (pv.Spec.ClaimRef.Namespace != pvc.Namespace || pv.Spec.ClaimRef.Name != pvc.Name):
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("Volume %s is reserved for %s/%s", pv.Name, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name),
+				"Clear the PV's claimRef if the other claim is gone, or request a different volume",
+			})
+		}
+		return findings
+	}
+
+	if class != nil && class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		if len(consumers) == 0 {
+			findings = append(findings, diagnosticFinding{
+				fmt.Sprintf("Storage class %s uses WaitForFirstConsumer, so the claim binds only once a pod uses it; no pod does yet", class.Name),
+				"This is expected: create the pod or workload that mounts the claim",
+			})
+		}
+		for _, pod := range consumers {
+			if pod.Spec.NodeName == "" {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("Storage class %s uses WaitForFirstConsumer and pod %s is not scheduled, so no node has been picked for the volume", class.Name, pod.Name),
+					"Fix the pod's scheduling first (openshift_diagnose): node selectors, taints, resources or volume topology",
+				})
+			}
+		}
+	}
+
+	// Static binding: an existing PV must match the claim
+	static := class == nil || class.Provisioner == noProvisioner
+	if static {
+		className := ""
+		if class != nil {
+			className = class.Name
+		}
+		pvs, err := s.k8sClient.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+		if err == nil {
+			matched := false
+			for i := range pvs.Items {
+				if pvMatchesClaim(&pvs.Items[i], pvc, className) {
+					matched = true
+				}
+			}
+			if !matched {
+				findings = append(findings, diagnosticFinding{
+					fmt.Sprintf("No available PV matches the claim (class %s, %s, %s)",
+						formatStorageClass(&className), pvcRequest(pvc), formatAccessModes(pvc.Spec.AccessModes)),
+					"Create a PV with a matching storage class, enough capacity and the requested access modes, or use a class with a dynamic provisioner",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func (s *Server) diagnosePVCHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pvc, failure := s.getClaim(ctx, request)
+	if failure != nil {
+		return failure, nil
+	}
+	consumers, err := s.claimConsumers(ctx, pvc)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list pods in namespace %s", pvc.Namespace), err), nil
+	}
+	events := s.volumeEvents(ctx, pvc, consumers)
+	findings := s.diagnoseClaim(ctx, pvc, consumers, events)
+
+	result := "🔍 PVC Diagnostic Report\n"
+	result += "========================\n\n"
+	result += fmt.Sprintf("Claim: %s/%s\n", pvc.Namespace, pvc.Name)
+	result += fmt.Sprintf("%s Status: %s\n", pvcIcon(pvc.Status.Phase), pvc.Status.Phase)
+	result += fmt.Sprintf("Storage class: %s, Request: %s, Access modes: %s\n",
+		formatStorageClass(pvcStorageClass(pvc)), pvcRequest(pvc), formatAccessModes(pvc.Spec.AccessModes))
+	result += fmt.Sprintf("Mounted by: %d pod(s)\n", len(consumers))
+
+	if len(findings) == 0 {
+		result += "\n✅ No problems found"
+		if pvc.Status.Phase == corev1.ClaimPending {
+			result += "; provisioning may still be in progress"
+		}
+		result += "\n"
+	} else {
+		result += fmt.Sprintf("\n⚠️  Found %d issue(s):\n", len(findings))
+		for i, finding := range findings {
+			result += fmt.Sprintf("%d. %s\n", i+1, finding.problem)
+			result += fmt.Sprintf("   💡 %s\n", finding.fix)
+		}
+	}
+
+	if len(events) > 0 {
+		result += "\n📜 Recent volume events:\n"
+		if len(events) > 10 {
+			events = events[len(events)-10:]
+		}
+		for _, event := range events {
+			result += formatObjectEvent(event) + "\n"
+		}
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// ListPVCsHandler is a public wrapper for listPVCsHandler
+func (s *Server) ListPVCsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listPVCsHandler(ctx, request)
+}
+
+// GetPVCHandler is a public wrapper for getPVCHandler
+func (s *Server) GetPVCHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.getPVCHandler(ctx, request)
+}
+
+// DiagnosePVCHandler is a public wrapper for diagnosePVCHandler
+func (s *Server) DiagnosePVCHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.diagnosePVCHandler(ctx, request)
+}
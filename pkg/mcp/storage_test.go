@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func testPVC(name string, class *string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: class,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func testStorageClass(name, provisioner string, mode storagev1.VolumeBindingMode, isDefault bool) *storagev1.StorageClass {
+	class := &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: name},
+		Provisioner:       provisioner,
+		VolumeBindingMode: &mode,
+	}
+	if isDefault {
+		class.Annotations = map[string]string{defaultStorageClassAnnotation: "true"}
+	}
+	return class
+}
+
+func testPV(name, class, size string, modes ...corev1.PersistentVolumeAccessMode) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: class,
+			AccessModes:      modes,
+			Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeAvailable},
+	}
+}
+
+func podMountingClaim(name, claim, node string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+			}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func warningEvent(name, kind, object, reason, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+	}
+}
+
+func TestPVMatchesClaim(t *testing.T) {
+	pvc := testPVC("data", nil, corev1.ClaimPending)
+	bound := testPV("bound", "", "20Gi", corev1.ReadWriteOnce)
+	bound.Status.Phase = corev1.VolumeBound
+
+	tests := []struct {
+		pv    *corev1.PersistentVolume
+		class string
+		match bool
+	}{
+		{testPV("pv", "", "10Gi", corev1.ReadWriteOnce), "", true},
+		{testPV("pv", "", "20Gi", corev1.ReadWriteOnce, corev1.ReadWriteMany), "", true},
+		{testPV("pv", "", "5Gi", corev1.ReadWriteOnce), "", false},
+		{testPV("pv", "", "10Gi", corev1.ReadOnlyMany), "", false},
+		{testPV("pv", "local", "10Gi", corev1.ReadWriteOnce), "", false},
+		{testPV("pv", "local", "10Gi", corev1.ReadWriteOnce), "local", true},
+		{bound, "", false},
+	}
+
+	for _, tt := range tests {
+		if match := pvMatchesClaim(tt.pv, pvc, tt.class); match != tt.match {
+			t.Errorf("pvMatchesClaim(%s %s %v, class %q) = %v, expected %v", tt.pv.Name, tt.pv.Spec.StorageClassName,
+				tt.pv.Spec.AccessModes, tt.class, match, tt.match)
+		}
+	}
+}
+
+func TestDiagnosePVC(t *testing.T) {
+	gp3, local, missing, empty := "gp3", "local", "fast", ""
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    []string
+	}{
+		{
+			name: "no default class",
+			objects: []runtime.Object{
+				testPVC("data", nil, corev1.ClaimPending),
+			},
+			want: []string{"no default class"},
+		},
+		{
+			name: "missing class",
+			objects: []runtime.Object{
+				testPVC("data", &missing, corev1.ClaimPending),
+			},
+			want: []string{"Storage class fast does not exist"},
+		},
+		{
+			name: "no matching PV",
+			objects: []runtime.Object{
+				testPVC("data", &empty, corev1.ClaimPending),
+				testPV("small", "", "1Gi", corev1.ReadWriteOnce),
+			},
+			want: []string{"No available PV matches the claim"},
+		},
+		{
+			name: "wait for first consumer",
+			objects: []runtime.Object{
+				testPVC("data", &local, corev1.ClaimPending),
+				testStorageClass("local", noProvisioner, storagev1.VolumeBindingWaitForFirstConsumer, false),
+				testPV("local-1", "local", "100Gi", corev1.ReadWriteOnce),
+			},
+			want: []string{"WaitForFirstConsumer", "no pod does yet"},
+		},
+		{
+			name: "unscheduled consumer",
+			objects: []runtime.Object{
+				testPVC("data", &local, corev1.ClaimPending),
+				testStorageClass("local", noProvisioner, storagev1.VolumeBindingWaitForFirstConsumer, false),
+				podMountingClaim("db-0", "data", ""),
+			},
+			want: []string{"pod db-0 is not scheduled", "No available PV matches"},
+		},
+		{
+			name: "CSI driver missing",
+			objects: []runtime.Object{
+				testPVC("data", &gp3, corev1.ClaimPending),
+				testStorageClass("gp3", "ebs.csi.aws.com", storagev1.VolumeBindingImmediate, true),
+				warningEvent("e1", "PersistentVolumeClaim", "data", "ProvisioningFailed", "rpc error: UnauthorizedOperation"),
+			},
+			want: []string{"CSI driver ebs.csi.aws.com is not registered", "Provisioning failed: rpc error: UnauthorizedOperation"},
+		},
+		{
+			name: "mount failure",
+			objects: []runtime.Object{
+				testPVC("data", &gp3, corev1.ClaimBound),
+				testStorageClass("gp3", "ebs.csi.aws.com", storagev1.VolumeBindingImmediate, true),
+				&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+				podMountingClaim("db-0", "data", "worker-1"),
+				warningEvent("e1", "Pod", "db-0", "FailedAttachVolume", "Multi-Attach error for volume pvc-1"),
+				warningEvent("e2", "Pod", "other", "FailedMount", "unrelated"),
+			},
+			want: []string{"Mounted by: 1 pod(s)", "still attached to another node", "Multi-Attach error"},
+		},
+		{
+			name: "node plugin not registered",
+			objects: []runtime.Object{
+				testPVC("data", &gp3, corev1.ClaimBound),
+				testStorageClass("gp3", "ebs.csi.aws.com", storagev1.VolumeBindingImmediate, true),
+				&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+				&storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}, Spec: storagev1.CSINodeSpec{
+					Drivers: []storagev1.CSINodeDriver{{Name: "csi.vsphere.vmware.com", NodeID: "worker-1"}},
+				}},
+				podMountingClaim("db-0", "data", "worker-1"),
+			},
+			want: []string{"The node plugin of CSI driver ebs.csi.aws.com is not registered on node worker-1, where db-0 runs"},
+		},
+		{
+			name: "healthy",
+			objects: []runtime.Object{
+				testPVC("data", &gp3, corev1.ClaimBound),
+				testStorageClass("gp3", "ebs.csi.aws.com", storagev1.VolumeBindingImmediate, true),
+				&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+			},
+			want: []string{"✅ No problems found"},
+		},
+	}
+
+	for _, tt := range tests {
+		s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(tt.objects...)}
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"pvc_name": "data", "namespace": "default"}
+		result, err := s.DiagnosePVCHandler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("%s: DiagnosePVCHandler error = %v", tt.name, err)
+		}
+		text := resultText(result)
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s: DiagnosePVCHandler output missing %q:\n%s", tt.name, want, text)
+			}
+		}
+		if strings.Contains(text, "unrelated") {
+			t.Errorf("%s: DiagnosePVCHandler reported another pod's event:\n%s", tt.name, text)
+		}
+	}
+}
+
+func TestListPVCs(t *testing.T) {
+	gp3 := "gp3"
+	bound := testPVC("logs", &gp3, corev1.ClaimBound)
+	bound.Spec.VolumeName = "pvc-123"
+	bound.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(bound, testPVC("data", nil, corev1.ClaimPending))}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "default"}
+	result, err := s.ListPVCsHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ListPVCsHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Found 2 PVCs, ⚠️  1 not bound",
+		"⚠️  data - Pending, 10Gi requested, RWO, class (cluster default)",
+		"✅ logs - Bound to pvc-123, 20Gi, RWO, class gp3",
+		"diagnose_pvc",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("ListPVCsHandler output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "data -") > strings.Index(text, "logs -") {
+		t.Errorf("ListPVCsHandler should list unbound claims first:\n%s", text)
+	}
+}
+
+func TestGetPVC(t *testing.T) {
+	gp3 := "gp3"
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(
+		testPVC("data", &gp3, corev1.ClaimBound),
+		podMountingClaim("db-0", "data", "worker-1"),
+		warningEvent("e1", "Pod", "db-0", "FailedMount", "timed out waiting for the condition"),
+		warningEvent("e2", "Pod", "db-0", "BackOff", "back-off restarting container"),
+	)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"pvc_name": "data"}
+	result, err := s.GetPVCHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GetPVCHandler error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{"Storage class: gp3", "db-0 (Pending, node worker-1)", "pod/db-0 FailedMount"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("GetPVCHandler output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "BackOff") {
+		t.Errorf("GetPVCHandler should only show volume events of mounting pods:\n%s", text)
+	}
+}
@@ -0,0 +1,520 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultTenantTemplate is the template used for template=default unless
+// the tenant template directory defines its own default.yaml
+const defaultTenantTemplate = "default"
+
+// tenantTeamLabel records the owning team on a provisioned namespace
+const tenantTeamLabel = "tenant.openshift.io/team"
+
+// tenantPlaceholder matches {{namespace}} and {{team}} in template values
+var tenantPlaceholder = regexp.MustCompile(`\{\{\s*(namespace|team)\s*\}\}`)
+
+// TenantTemplate describes what create_namespace provisions with a new
+// namespace. Labels, annotations and role groups may use {{namespace}} and
+// {{team}}; entries that expand to nothing are dropped.
+type TenantTemplate struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Quota is the hard limit of the tenant ResourceQuota, e.g. requests.cpu: "4"
+	Quota map[string]string `json:"quota,omitempty"`
+
+	// LimitDefaults and LimitDefaultRequests are the container limits and
+	// requests of the tenant LimitRange, e.g. memory: 512Mi
+	LimitDefaults        map[string]string `json:"limit_defaults,omitempty"`
+	LimitDefaultRequests map[string]string `json:"limit_default_requests,omitempty"`
+
+	// NetworkPolicies are generate_network_policy templates applied to every pod
+	NetworkPolicies []string `json:"network_policies,omitempty"`
+
+	// Roles binds cluster roles (admin, edit, view or custom) to groups
+	Roles map[string][]string `json:"roles,omitempty"`
+}
+
+// builtinTenantTemplate is a conservative starting point: bounded quota,
+// default container sizes, ingress closed except from the namespace itself,
+// the router and monitoring, and the team as namespace admins
+func builtinTenantTemplate() TenantTemplate {
+	return TenantTemplate{
+		Name:        defaultTenantTemplate,
+		Description: "Quota, default limits, baseline network policies and team admin access",
+		Labels: map[string]string{
+			tenantTeamLabel:                "{{team}}",
+			"app.kubernetes.io/managed-by": "openshift-mcp",
+		},
+		Quota: map[string]string{
+			"requests.cpu":           "4",
+			"requests.memory":        "8Gi",
+			"limits.cpu":             "8",
+			"limits.memory":          "16Gi",
+			"pods":                   "50",
+			"persistentvolumeclaims": "10",
+			"requests.storage":       "100Gi",
+		},
+		LimitDefaults:        map[string]string{"cpu": "500m", "memory": "512Mi"},
+		LimitDefaultRequests: map[string]string{"cpu": "100m", "memory": "128Mi"},
+		NetworkPolicies:      []string{"deny-all-ingress", "allow-same-namespace", "allow-from-ingress", "allow-from-monitoring"},
+		Roles:                map[string][]string{"admin": {"{{team}}"}},
+	}
+}
+
+// tenantTemplateDir returns the configured tenant template directory, or ""
+func (s *Server) tenantTemplateDir() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.TenantTemplateDir
+}
+
+// parseTenantTemplate reads a template file, named after the file unless
+// it sets a name
+func parseTenantTemplate(filename string, data []byte) (TenantTemplate, error) {
+	var template TenantTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return template, fmt.Errorf("invalid template: %v", err)
+	}
+	if template.Name == "" {
+		template.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	for _, policy := range template.NetworkPolicies {
+		if _, ok := networkPolicyTemplates[policy]; !ok {
+			return template, fmt.Errorf("unknown network policy template %q", policy)
+		}
+	}
+	return template, nil
+}
+
+// tenantTemplates returns the available templates by name: the built-in
+// default and those in the tenant template directory
+func (s *Server) tenantTemplates() (map[string]TenantTemplate, error) {
+	templates := map[string]TenantTemplate{defaultTenantTemplate: builtinTenantTemplate()}
+	dir := s.tenantTemplateDir()
+	if dir == "" {
+		return templates, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return templates, fmt.Errorf("reading tenant template directory: %v", err)
+	}
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return templates, err
+		}
+		template, err := parseTenantTemplate(entry.Name(), data)
+		if err != nil {
+			return templates, fmt.Errorf("%s: %v", entry.Name(), err)
+		}
+		templates[template.Name] = template
+	}
+	return templates, nil
+}
+
+// expandTenantValue substitutes the placeholders of a template value
+func expandTenantValue(value, namespace, team string) string {
+	return tenantPlaceholder.ReplaceAllStringFunc(value, func(match string) string {
+		if strings.Contains(match, "namespace") {
+			return namespace
+		}
+		return team
+	})
+}
+
+// expandTenantMap substitutes placeholders and drops entries left empty
+func expandTenantMap(values map[string]string, namespace, team string) map[string]string {
+	expanded := make(map[string]string)
+	for key, value := range values {
+		if value = expandTenantValue(value, namespace, team); value != "" {
+			expanded[key] = value
+		}
+	}
+	return expanded
+}
+
+// parseResourceList parses a template's resource quantities
+func parseResourceList(values map[string]string) (corev1.ResourceList, error) {
+	list := make(corev1.ResourceList)
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q for %s", value, name)
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
+
+// tenantRequest is the namespace, team and extra settings of one provisioning
+type tenantRequest struct {
+	Namespace string
+	Team      string
+	Labels    map[string]string
+	Roles     map[string][]string // added to the template's role groups
+}
+
+// tenantBundle is what provisioning a namespace creates, in creation order
+type tenantBundle struct {
+	Namespace       *corev1.Namespace
+	Quota           *corev1.ResourceQuota
+	LimitRange      *corev1.LimitRange
+	NetworkPolicies []*networkingv1.NetworkPolicy
+	RoleBindings    []*rbacv1.RoleBinding
+}
+
+// buildTenantBundle expands a template for a namespace
+func buildTenantBundle(template TenantTemplate, tenant tenantRequest) (*tenantBundle, error) {
+	labels := expandTenantMap(template.Labels, tenant.Namespace, tenant.Team)
+	for key, value := range tenant.Labels {
+		labels[key] = value
+	}
+	annotations := expandTenantMap(template.Annotations, tenant.Namespace, tenant.Team)
+	annotations["openshift-mcp/tenant-template"] = template.Name
+	bundle := &tenantBundle{Namespace: &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.Namespace, Labels: labels, Annotations: annotations},
+	}}
+	managed := map[string]string{"app.kubernetes.io/managed-by": "openshift-mcp"}
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: tenant.Namespace, Labels: managed}
+	}
+
+	if len(template.Quota) > 0 {
+		hard, err := parseResourceList(template.Quota)
+		if err != nil {
+			return nil, fmt.Errorf("quota: %v", err)
+		}
+		bundle.Quota = &corev1.ResourceQuota{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuota"},
+			ObjectMeta: objectMeta("tenant-quota"),
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		}
+	}
+
+	if len(template.LimitDefaults) > 0 || len(template.LimitDefaultRequests) > 0 {
+		defaults, err := parseResourceList(template.LimitDefaults)
+		if err != nil {
+			return nil, fmt.Errorf("limit_defaults: %v", err)
+		}
+		requests, err := parseResourceList(template.LimitDefaultRequests)
+		if err != nil {
+			return nil, fmt.Errorf("limit_default_requests: %v", err)
+		}
+		bundle.LimitRange = &corev1.LimitRange{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRange"},
+			ObjectMeta: objectMeta("tenant-limits"),
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Default:        defaults,
+				DefaultRequest: requests,
+			}}},
+		}
+	}
+
+	for _, name := range template.NetworkPolicies {
+		spec, err := networkPolicyTemplate(name, metav1.LabelSelector{}, "", nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		bundle.NetworkPolicies = append(bundle.NetworkPolicies, &networkingv1.NetworkPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: objectMeta(name),
+			Spec:       spec,
+		})
+	}
+
+	roles := make(map[string][]string)
+	for role, groups := range template.Roles {
+		for _, group := range groups {
+			if group = expandTenantValue(group, tenant.Namespace, tenant.Team); group != "" {
+				roles[role] = append(roles[role], group)
+			}
+		}
+	}
+	for role, groups := range tenant.Roles {
+		roles[role] = append(roles[role], groups...)
+	}
+	for _, role := range sortedKeys(roles) {
+		binding := &rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+			ObjectMeta: objectMeta("tenant-" + role),
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role},
+		}
+		seen := make(map[string]bool)
+		for _, group := range roles[role] {
+			if !seen[group] {
+				seen[group] = true
+				binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: group})
+			}
+		}
+		bundle.RoleBindings = append(bundle.RoleBindings, binding)
+	}
+	return bundle, nil
+}
+
+// tenantObject is an object of a bundle with its kind
+type tenantObject struct {
+	kind   string
+	object metav1.Object
+}
+
+// objects lists the bundle's objects in creation order
+func (b *tenantBundle) objects() []tenantObject {
+	objects := []tenantObject{{"Namespace", b.Namespace}}
+	if b.Quota != nil {
+		objects = append(objects, tenantObject{"ResourceQuota", b.Quota})
+	}
+	if b.LimitRange != nil {
+		objects = append(objects, tenantObject{"LimitRange", b.LimitRange})
+	}
+	for _, policy := range b.NetworkPolicies {
+		objects = append(objects, tenantObject{"NetworkPolicy", policy})
+	}
+	for _, binding := range b.RoleBindings {
+		objects = append(objects, tenantObject{"RoleBinding", binding})
+	}
+	return objects
+}
+
+// manifests renders the bundle as files with a kustomization listing them
+func (b *tenantBundle) manifests() (map[string]string, []string, error) {
+	manifests := make(map[string]string)
+	var files []string
+	for _, object := range b.objects() {
+		filename := fmt.Sprintf("%s-%s.yaml", strings.ToLower(object.kind), object.object.GetName())
+		data, err := yaml.Marshal(object.object)
+		if err != nil {
+			return nil, nil, err
+		}
+		manifests[filename] = strings.Replace(string(data), "  creationTimestamp: null\n", "", 1)
+		files = append(files, filename)
+	}
+	kustomization, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  files,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	manifests["kustomization.yaml"] = string(kustomization)
+	return manifests, files, nil
+}
+
+// createTenantBundle creates the bundle's objects in order, stopping at the
+// first failure, and returns what was created
+func (s *Server) createTenantBundle(ctx context.Context, bundle *tenantBundle) ([]string, error) {
+	var created []string
+	core, networking, rbac := s.k8sClient.CoreV1(), s.k8sClient.NetworkingV1(), s.k8sClient.RbacV1()
+	namespace := bundle.Namespace.Name
+
+	if _, err := core.Namespaces().Create(ctx, bundle.Namespace, metav1.CreateOptions{}); err != nil {
+		return created, fmt.Errorf("creating Namespace %s: %w", namespace, err)
+	}
+	created = append(created, "Namespace "+namespace)
+	s.recordAction(ctx, "create_namespace", objectReference("v1", "Namespace", bundle.Namespace), ReasonCreated, "Provisioned Namespace from tenant template")
+
+	if bundle.Quota != nil {
+		if _, err := core.ResourceQuotas(namespace).Create(ctx, bundle.Quota, metav1.CreateOptions{}); err != nil {
+			return created, fmt.Errorf("creating ResourceQuota: %w", err)
+		}
+		created = append(created, "ResourceQuota "+bundle.Quota.Name)
+		s.recordAction(ctx, "create_namespace", objectReference("v1", "ResourceQuota", bundle.Quota), ReasonCreated, "Created tenant ResourceQuota")
+	}
+	if bundle.LimitRange != nil {
+		if _, err := core.LimitRanges(namespace).Create(ctx, bundle.LimitRange, metav1.CreateOptions{}); err != nil {
+			return created, fmt.Errorf("creating LimitRange: %w", err)
+		}
+		created = append(created, "LimitRange "+bundle.LimitRange.Name)
+		s.recordAction(ctx, "create_namespace", objectReference("v1", "LimitRange", bundle.LimitRange), ReasonCreated, "Created tenant LimitRange")
+	}
+	for _, policy := range bundle.NetworkPolicies {
+		if _, err := networking.NetworkPolicies(namespace).Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+			return created, fmt.Errorf("creating NetworkPolicy %s: %w", policy.Name, err)
+		}
+		created = append(created, "NetworkPolicy "+policy.Name)
+		s.recordAction(ctx, "create_namespace", objectReference("networking.k8s.io/v1", "NetworkPolicy", policy), ReasonCreated, "Created tenant NetworkPolicy")
+	}
+	for _, binding := range bundle.RoleBindings {
+		if _, err := rbac.RoleBindings(namespace).Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+			return created, fmt.Errorf("creating RoleBinding %s: %w", binding.Name, err)
+		}
+		created = append(created, "RoleBinding "+binding.Name)
+		s.recordAction(ctx, "create_namespace", objectReference("rbac.authorization.k8s.io/v1", "RoleBinding", binding), ReasonCreated, "Created tenant RoleBinding")
+	}
+	return created, nil
+}
+
+// describeTenantBundle lists what a bundle provisions
+func describeTenantBundle(bundle *tenantBundle) string {
+	result := ""
+	if len(bundle.Namespace.Labels) > 0 {
+		result += "🏷️  Labels:\n"
+		for _, key := range sortedKeys(bundle.Namespace.Labels) {
+			result += fmt.Sprintf("   • %s=%s\n", key, bundle.Namespace.Labels[key])
+		}
+	}
+	if bundle.Quota != nil {
+		result += "📊 ResourceQuota tenant-quota:\n"
+		hard := make(map[string]string)
+		for name, quantity := range bundle.Quota.Spec.Hard {
+			hard[string(name)] = quantity.String()
+		}
+		for _, name := range sortedKeys(hard) {
+			result += fmt.Sprintf("   • %s: %s\n", name, hard[name])
+		}
+	}
+	if bundle.LimitRange != nil {
+		limits := bundle.LimitRange.Spec.Limits[0]
+		result += "📏 LimitRange tenant-limits (per container):\n"
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			request, limit := limits.DefaultRequest[name], limits.Default[name]
+			result += fmt.Sprintf("   • %s: request %s, limit %s\n", name, valueOrNone(request.String()), valueOrNone(limit.String()))
+		}
+	}
+	if len(bundle.NetworkPolicies) > 0 {
+		result += "🛡️  NetworkPolicies:\n"
+		for _, policy := range bundle.NetworkPolicies {
+			result += fmt.Sprintf("   • %s: %s\n", policy.Name, networkPolicyTemplates[policy.Name])
+		}
+	}
+	if len(bundle.RoleBindings) > 0 {
+		result += "🔐 RoleBindings:\n"
+		for _, binding := range bundle.RoleBindings {
+			var groups []string
+			for _, subject := range binding.Subjects {
+				groups = append(groups, subject.Name)
+			}
+			sort.Strings(groups)
+			result += fmt.Sprintf("   • %s → %s\n", binding.RoleRef.Name, strings.Join(groups, ", "))
+		}
+	}
+	return result
+}
+
+// parseGroupList parses a comma-separated group list
+func parseGroupList(value string) []string {
+	var groups []string
+	for _, group := range strings.Split(value, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// provisionNamespaceHandler is create_namespace with a tenant template:
+// the namespace plus its quota, limits, network policies and team access,
+// committed to Git as one bundle
+func (s *Server) provisionNamespaceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace_name", ""))
+	templateName := strings.TrimSpace(mcp.ParseString(request, "template", defaultTenantTemplate))
+	dryRun := parseBoolString(mcp.ParseString(request, "dry_run", "false"))
+	saveToGit := parseBoolString(mcp.ParseString(request, "save_to_git", "true"))
+	if namespace == "" {
+		return mcp.NewToolResultText("❌ Namespace name is required"), nil
+	}
+	if !dryRun && s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+
+	templates, err := s.tenantTemplates()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to load tenant templates: %v", err)), nil
+	}
+	template, ok := templates[templateName]
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Unknown tenant template %q (available: %s)", templateName, strings.Join(sortedKeys(templates), ", "))), nil
+	}
+
+	tenant := tenantRequest{
+		Namespace: namespace,
+		Team:      strings.TrimSpace(mcp.ParseString(request, "team", "")),
+		Roles:     make(map[string][]string),
+	}
+	if tenant.Labels, err = parseKeyValues(mcp.ParseString(request, "labels", "")); err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid labels: %v", err)), nil
+	}
+	for role, parameter := range map[string]string{"admin": "admin_groups", "edit": "edit_groups", "view": "view_groups"} {
+		if groups := parseGroupList(mcp.ParseString(request, parameter, "")); len(groups) > 0 {
+			tenant.Roles[role] = groups
+		}
+	}
+
+	bundle, err := buildTenantBundle(template, tenant)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Tenant template %s is invalid: %v", template.Name, err)), nil
+	}
+	manifests, files, err := bundle.manifests()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to render the tenant bundle: %v", err)), nil
+	}
+
+	result := "🏗️  Provisioning Namespace\n"
+	result += "=========================\n\n"
+	result += fmt.Sprintf("Namespace: %s\n", namespace)
+	result += fmt.Sprintf("Template: %s\n", template.Name)
+	result += fmt.Sprintf("Team: %s\n\n", valueOrNone(tenant.Team))
+	result += describeTenantBundle(bundle)
+
+	if dryRun {
+		result += "\n🔍 Dry run: nothing was created. Bundle files:\n"
+		for _, file := range append(files, "kustomization.yaml") {
+			result += fmt.Sprintf("\n--- %s\n%s", file, manifests[file])
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	created, err := s.createTenantBundle(ctx, bundle)
+	result += "\n📦 Created:\n"
+	for _, object := range created {
+		result += fmt.Sprintf("   ✅ %s\n", object)
+	}
+	if err != nil {
+		recordToolError(ctx, err)
+		result = fmt.Sprintf("❌ Failed to provision namespace %s: %v\n\n", namespace, err) + result
+		if len(created) > 0 {
+			result += fmt.Sprintf("\n💡 The namespace was created but is incomplete; fix the cause and apply the rest, or delete namespace %s and retry", namespace)
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	if saveToGit && s.gitManager != nil && s.gitManager.IsEnabled() {
+		description := fmt.Sprintf("namespace %s from tenant template %s", namespace, template.Name)
+		if dir, err := s.gitManager.SaveTenantBundle(ctx, namespace, manifests, description); err != nil {
+			result += fmt.Sprintf("\n⚠️  Failed to commit the tenant bundle to Git: %v\n", err)
+		} else {
+			result += fmt.Sprintf("\n📝 Tenant bundle committed to Git: %s\n", dir)
+		}
+	}
+	result += "\n✅ Namespace provisioned successfully!"
+	return mcp.NewToolResultText(result), nil
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildTenantBundle(t *testing.T) {
+	bundle, err := buildTenantBundle(builtinTenantTemplate(), tenantRequest{
+		Namespace: "payments",
+		Team:      "payments-team",
+		Labels:    map[string]string{"cost-center": "42"},
+		Roles:     map[string][]string{"view": {"auditors"}, "admin": {"payments-team", "platform"}},
+	})
+	if err != nil {
+		t.Fatalf("buildTenantBundle() returned %v", err)
+	}
+	if labels := bundle.Namespace.Labels; labels[tenantTeamLabel] != "payments-team" || labels["cost-center"] != "42" {
+		t.Errorf("namespace labels = %v, expected team and cost-center", labels)
+	}
+	if hard := bundle.Quota.Spec.Hard["requests.cpu"]; hard.String() != "4" {
+		t.Errorf("quota requests.cpu = %s, expected 4", hard.String())
+	}
+	if len(bundle.NetworkPolicies) != 4 || bundle.NetworkPolicies[0].Name != "deny-all-ingress" {
+		t.Errorf("network policies = %d, expected the 4 baseline policies starting with deny-all-ingress", len(bundle.NetworkPolicies))
+	}
+	bindings := make(map[string]int)
+	for _, binding := range bundle.RoleBindings {
+		bindings[binding.RoleRef.Name] = len(binding.Subjects)
+	}
+	if bindings["admin"] != 2 || bindings["view"] != 1 || len(bindings) != 2 {
+		t.Errorf("role binding subjects = %v, expected admin: 2 (team listed once), view: 1", bindings)
+	}
+
+	// Without a team the template's {{team}} entries are dropped
+	bundle, err = buildTenantBundle(builtinTenantTemplate(), tenantRequest{Namespace: "scratch"})
+	if err != nil {
+		t.Fatalf("buildTenantBundle() without team returned %v", err)
+	}
+	if _, ok := bundle.Namespace.Labels[tenantTeamLabel]; ok || len(bundle.RoleBindings) != 0 {
+		t.Errorf("bundle without team has labels %v and %d role bindings, expected neither", bundle.Namespace.Labels, len(bundle.RoleBindings))
+	}
+
+	invalid := builtinTenantTemplate()
+	invalid.Quota = map[string]string{"requests.cpu": "lots"}
+	if _, err := buildTenantBundle(invalid, tenantRequest{Namespace: "scratch"}); err == nil {
+		t.Error("buildTenantBundle() with an invalid quantity returned no error")
+	}
+}
+
+func TestTenantTemplatesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"small.yaml":  "description: Sandbox\nquota:\n  pods: \"5\"\nnetwork_policies: [deny-all]\nroles:\n  edit: [\"{{team}}-devs\"]\n",
+		"notes.txt":   "ignored",
+		"broken.yaml": "network_policies: [allow-everything]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := &Server{config: &Config{TenantTemplateDir: dir}}
+	if _, err := s.tenantTemplates(); err == nil || !strings.Contains(err.Error(), "allow-everything") {
+		t.Errorf("tenantTemplates() with an unknown policy = %v, expected an error naming it", err)
+	}
+
+	os.Remove(filepath.Join(dir, "broken.yaml"))
+	templates, err := s.tenantTemplates()
+	if err != nil {
+		t.Fatalf("tenantTemplates() returned %v", err)
+	}
+	small, ok := templates["small"]
+	if !ok || templates[defaultTenantTemplate].Name != defaultTenantTemplate || len(templates) != 2 {
+		t.Fatalf("tenantTemplates() = %v, expected default and small", sortedKeys(templates))
+	}
+	if small.Quota["pods"] != "5" || small.Roles["edit"][0] != "{{team}}-devs" {
+		t.Errorf("small template = %+v", small)
+	}
+}
+
+func TestCreateNamespaceProvisionsTenant(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	s := &Server{config: &Config{}, k8sClient: client, gitManager: NewGitManager(nil)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace_name": "payments", "team": "payments-team", "dry_run": "true"}
+	result, _ := s.createNamespaceHandler(context.Background(), request)
+	text := resultText(result)
+	if !strings.Contains(text, "Dry run") || !strings.Contains(text, "--- resourcequota-tenant-quota.yaml") {
+		t.Errorf("dry run output missing the bundle:\n%s", text)
+	}
+	if namespaces, _ := client.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{}); len(namespaces.Items) != 0 {
+		t.Errorf("dry run created %d namespaces", len(namespaces.Items))
+	}
+
+	request.Params.Arguments = map[string]interface{}{"namespace_name": "payments", "team": "payments-team", "view_groups": "auditors"}
+	result, _ = s.createNamespaceHandler(context.Background(), request)
+	text = resultText(result)
+	for _, expected := range []string{"✅ Namespace payments", "✅ ResourceQuota tenant-quota", "✅ LimitRange tenant-limits", "✅ NetworkPolicy deny-all-ingress", "✅ RoleBinding tenant-admin", "✅ RoleBinding tenant-view", "provisioned successfully"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("create_namespace output missing %q:\n%s", expected, text)
+		}
+	}
+	ctx := context.Background()
+	namespace, err := client.CoreV1().Namespaces().Get(ctx, "payments", metav1.GetOptions{})
+	if err != nil || namespace.Labels[tenantTeamLabel] != "payments-team" {
+		t.Errorf("namespace = %v, %v, expected the team label", namespace, err)
+	}
+	if policies, _ := client.NetworkingV1().NetworkPolicies("payments").List(ctx, metav1.ListOptions{}); len(policies.Items) != 4 {
+		t.Errorf("created %d network policies, expected 4", len(policies.Items))
+	}
+
+	request.Params.Arguments = map[string]interface{}{"namespace_name": "other", "template": "gold"}
+	if text := resultText(mustCall(t, s.createNamespaceHandler, request)); !strings.Contains(text, "Unknown tenant template") {
+		t.Errorf("create_namespace with an unknown template = %q", text)
+	}
+}
@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Time formats a caller can ask for
+const (
+	TimeFormatDefault  = "default"  // 2006-01-02 15:04:05 MST
+	TimeFormatRFC3339  = "rfc3339"  // 2006-01-02T15:04:05Z07:00
+	TimeFormatRelative = "relative" // 5m ago, in 3d
+)
+
+const defaultTimeLayout = "2006-01-02 15:04:05 MST"
+
+// Parameters every tool takes to choose how its timestamps are shown
+const (
+	timezoneParam   = "timezone"
+	timeFormatParam = "time_format"
+)
+
+// TimePreferences are the zone and format timestamps are shown in. A nil
+// Location or empty Format leaves the server's default in place.
+type TimePreferences struct {
+	Location *time.Location
+	Format   string
+}
+
+type timePreferencesKey struct{}
+
+// ParseTimePreferences reads an IANA zone name (or UTC, Local) and a format
+// of default, rfc3339 or relative; either may be empty
+func ParseTimePreferences(timezone, format string) (TimePreferences, error) {
+	var prefs TimePreferences
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return prefs, fmt.Errorf("invalid timezone %q: use an IANA zone such as Europe/Berlin, UTC or Local", timezone)
+		}
+		prefs.Location = loc
+	}
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "":
+	case TimeFormatDefault, TimeFormatRFC3339, TimeFormatRelative:
+		prefs.Format = format
+	default:
+		return prefs, fmt.Errorf("invalid time format %q: use default, rfc3339 or relative", format)
+	}
+	return prefs, nil
+}
+
+// override returns p with the preferences set in other
+func (p TimePreferences) override(other TimePreferences) TimePreferences {
+	if other.Location != nil {
+		p.Location = other.Location
+	}
+	if other.Format != "" {
+		p.Format = other.Format
+	}
+	return p
+}
+
+// WithTimePreferences sets how the tool calls made with ctx show timestamps
+func WithTimePreferences(ctx context.Context, prefs TimePreferences) context.Context {
+	if current, ok := ctx.Value(timePreferencesKey{}).(TimePreferences); ok {
+		prefs = current.override(prefs)
+	}
+	return context.WithValue(ctx, timePreferencesKey{}, prefs)
+}
+
+// TimePreferences returns the server's configured preferences overridden by
+// those of the caller
+func (s *Server) TimePreferences(ctx context.Context) TimePreferences {
+	prefs := TimePreferences{Location: time.Local, Format: TimeFormatDefault}
+	if s.config != nil {
+		if configured, err := ParseTimePreferences(s.config.OutputTimezone, s.config.OutputTimeFormat); err == nil {
+			prefs = prefs.override(configured)
+		}
+	}
+	if requested, ok := ctx.Value(timePreferencesKey{}).(TimePreferences); ok {
+		prefs = prefs.override(requested)
+	}
+	return prefs
+}
+
+// withRequestTimePreferences applies the timezone and time_format arguments
+// a tool call may carry and resolves the preferences its output uses
+func (s *Server) withRequestTimePreferences(ctx context.Context, request mcp.CallToolRequest) (context.Context, error) {
+	requested, err := ParseTimePreferences(mcp.ParseString(request, timezoneParam, ""), mcp.ParseString(request, timeFormatParam, ""))
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, timePreferencesKey{}, s.TimePreferences(WithTimePreferences(ctx, requested))), nil
+}
+
+// withTimeParams adds the timezone and time_format parameters to a tool
+func withTimeParams(tool mcp.Tool) mcp.Tool {
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+2)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[timezoneParam] = map[string]any{
+		"type":        "string",
+		"description": "IANA zone to show timestamps in, e.g. Europe/Berlin (default: server setting)",
+	}
+	properties[timeFormatParam] = map[string]any{
+		"type":        "string",
+		"description": "How to show timestamps: default, rfc3339 or relative (e.g. 5m ago)",
+		"enum":        []string{TimeFormatDefault, TimeFormatRFC3339, TimeFormatRelative},
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// Render shows a timestamp in the zone and format of the preferences
+func (p TimePreferences) Render(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	switch p.Format {
+	case TimeFormatRFC3339:
+		return t.In(loc).Format(time.RFC3339)
+	case TimeFormatRelative:
+		return relativeTime(t, time.Now())
+	}
+	return t.In(loc).Format(defaultTimeLayout)
+}
+
+// formatTime renders a timestamp for the caller of a tool
+func formatTime(ctx context.Context, t time.Time) string {
+	prefs, ok := ctx.Value(timePreferencesKey{}).(TimePreferences)
+	if !ok {
+		prefs = TimePreferences{Location: time.Local, Format: TimeFormatDefault}
+	}
+	return prefs.Render(t)
+}
+
+// formatTimeWithAge renders a timestamp followed by how long ago it was,
+// or only the latter when the caller asked for relative times
+func formatTimeWithAge(ctx context.Context, t time.Time) string {
+	text := formatTime(ctx, t)
+	if prefs, _ := ctx.Value(timePreferencesKey{}).(TimePreferences); prefs.Format == TimeFormatRelative || t.IsZero() {
+		return text
+	}
+	return fmt.Sprintf("%s (%s)", text, relativeTime(t, time.Now()))
+}
+
+// relativeTime renders a timestamp relative to now, e.g. "5m ago" or "in 3d"
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d >= 0 && d < time.Second:
+		return "just now"
+	case d < 0:
+		return "in " + shortDuration(-d)
+	}
+	return shortDuration(d) + " ago"
+}
+
+// shortDuration renders a duration in its largest whole unit, e.g. "3d" or
+// "5h"
+func shortDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseTimePreferences(t *testing.T) {
+	prefs, err := ParseTimePreferences("Asia/Tokyo", "RFC3339")
+	if err != nil {
+		t.Fatalf("ParseTimePreferences returned %v", err)
+	}
+	if prefs.Location.String() != "Asia/Tokyo" || prefs.Format != TimeFormatRFC3339 {
+		t.Errorf("preferences = %v %s, expected Asia/Tokyo rfc3339", prefs.Location, prefs.Format)
+	}
+
+	if prefs, err := ParseTimePreferences("", ""); err != nil || prefs.Location != nil || prefs.Format != "" {
+		t.Errorf("empty preferences = %+v, %v, expected none set", prefs, err)
+	}
+	if _, err := ParseTimePreferences("Mars/Olympus", ""); err == nil {
+		t.Error("expected an unknown timezone to be rejected")
+	}
+	if _, err := ParseTimePreferences("", "iso"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func TestTimePreferencesRender(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("zone data unavailable: %v", err)
+	}
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		prefs    TimePreferences
+		expected string
+	}{
+		{TimePreferences{Location: time.UTC, Format: TimeFormatDefault}, "2024-03-01 12:00:00 UTC"},
+		{TimePreferences{Location: tokyo, Format: TimeFormatDefault}, "2024-03-01 21:00:00 JST"},
+		{TimePreferences{Location: tokyo, Format: TimeFormatRFC3339}, "2024-03-01T21:00:00+09:00"},
+	}
+	for _, tt := range tests {
+		if result := tt.prefs.Render(at); result != tt.expected {
+			t.Errorf("Render(%v, %s) = %s, expected %s", tt.prefs.Location, tt.prefs.Format, result, tt.expected)
+		}
+	}
+
+	relative := TimePreferences{Format: TimeFormatRelative}
+	if result := relative.Render(time.Now().Add(-5 * time.Minute)); result != "5m ago" {
+		t.Errorf("relative Render = %s, expected 5m ago", result)
+	}
+	if result := relative.Render(time.Time{}); result != "unknown" {
+		t.Errorf("zero time rendered as %s, expected unknown", result)
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{now, "just now"},
+		{now.Add(-30 * time.Second), "30s ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(72 * time.Hour), "in 3d"},
+	}
+	for _, tt := range tests {
+		if result := relativeTime(tt.t, now); result != tt.expected {
+			t.Errorf("relativeTime(%v) = %s, expected %s", now.Sub(tt.t), result, tt.expected)
+		}
+	}
+}
+
+func TestRequestTimePreferencesOverrideConfig(t *testing.T) {
+	s := &Server{config: &Config{OutputTimezone: "UTC", OutputTimeFormat: TimeFormatRFC3339}}
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	request := mcp.CallToolRequest{}
+	ctx, err := s.withRequestTimePreferences(context.Background(), request)
+	if err != nil {
+		t.Fatalf("withRequestTimePreferences returned %v", err)
+	}
+	if result := formatTime(ctx, at); result != "2024-03-01T12:00:00Z" {
+		t.Errorf("configured format = %s, expected 2024-03-01T12:00:00Z", result)
+	}
+
+	// The API's preferences apply unless the tool arguments set their own
+	ctx = WithTimePreferences(context.Background(), TimePreferences{Format: TimeFormatDefault})
+	request.Params.Arguments = map[string]any{timezoneParam: "Europe/Berlin"}
+	ctx, err = s.withRequestTimePreferences(ctx, request)
+	if err != nil {
+		t.Fatalf("withRequestTimePreferences returned %v", err)
+	}
+	if result := formatTime(ctx, at); result != "2024-03-01 13:00:00 CET" {
+		t.Errorf("requested preferences = %s, expected 2024-03-01 13:00:00 CET", result)
+	}
+}
+
+func TestWithResilienceRejectsInvalidTimeFormat(t *testing.T) {
+	s := newResilienceTestServer(5)
+	called := false
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_events"
+	request.Params.Arguments = map[string]any{timeFormatParam: "iso"}
+
+	result, err := s.withResilience("get_events", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})(context.Background(), request)
+	if err != nil {
+		t.Fatalf("withResilience returned %v", err)
+	}
+	if called {
+		t.Error("expected the handler not to run with an invalid time_format")
+	}
+	info, failed := ResultError(result)
+	if !failed || info.Kind != ErrorInvalidArgument || !strings.Contains(info.Message, "iso") {
+		t.Errorf("error = %+v, expected an InvalidArgument naming the format", info)
+	}
+}
+
+func TestWithTimeParams(t *testing.T) {
+	tool := withTimeParams(mcp.NewTool("get_events", mcp.WithString("namespace")))
+	for _, param := range []string{"namespace", timezoneParam, timeFormatParam} {
+		if _, ok := tool.InputSchema.Properties[param]; !ok {
+			t.Errorf("properties = %v, expected %s", tool.InputSchema.Properties, param)
+		}
+	}
+}
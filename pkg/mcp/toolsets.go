@@ -0,0 +1,604 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// ToolSet is a group of tools registered at runtime, by a plugin or from a
+// runbook definition, that is replaced or removed as a whole. Registering
+// or removing one notifies MCP clients that the tool list changed.
+type ToolSet struct {
+	Name       string    `json:"name"`
+	Source     string    `json:"source"`
+	Tools      []string  `json:"tools"`
+	Registered time.Time `json:"registered"`
+	digest     string    // content of a definition file, to skip unchanged reloads
+}
+
+// toolNamePattern is what clients accept as a tool name
+var toolNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// addTools guards tools with the policy mode, their timeout and circuit
+// breaker, profiling and usage tracking, and records them. The caller holds
+// toolsMu for writing, or is still constructing the server. It returns the
+// guarded tools to hand to the MCP server.
+func (s *Server) addTools(tools []server.ServerTool) []server.ServerTool {
+	guarded := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		tool.Tool = withTimeParams(withFreezeOverride(tool.Tool))
+		name := tool.Tool.Name
+		handler := s.withPolicy(name, s.withUsage(name, s.withProfiling(name, s.withResilience(name, tool.Handler))))
+		s.toolDefs[name] = tool.Tool
+		s.tools[name] = handler
+		guarded = append(guarded, server.ServerTool{Tool: tool.Tool, Handler: handler})
+	}
+	return guarded
+}
+
+// removeTools forgets tools and the deprecated aliases that call them, and
+// returns every removed name. The caller holds toolsMu for writing.
+func (s *Server) removeTools(names []string) []string {
+	removed := make(map[string]bool, len(names))
+	for _, name := range names {
+		delete(s.tools, name)
+		delete(s.toolDefs, name)
+		removed[name] = true
+	}
+	for _, deprecation := range toolDeprecations {
+		if _, isTool := s.toolDefs[deprecation.Name]; removed[deprecation.ReplacedBy] && !isTool {
+			if _, aliased := s.tools[deprecation.Name]; aliased {
+				delete(s.tools, deprecation.Name)
+				removed[deprecation.Name] = true
+			}
+		}
+	}
+	return sortedKeys(removed)
+}
+
+// RegisterToolSet adds tools under a set name without restarting the server.
+// Registering a set again replaces its tools; a tool name owned by the
+// profile or by another set is refused.
+func (s *Server) RegisterToolSet(name, source string, tools []server.ServerTool) error {
+	return s.registerToolSet(&ToolSet{Name: name, Source: source}, tools)
+}
+
+func (s *Server) registerToolSet(set *ToolSet, tools []server.ServerTool) error {
+	if !toolNamePattern.MatchString(set.Name) {
+		return fmt.Errorf("invalid tool set name %q: use lowercase letters, digits and underscores", set.Name)
+	}
+	if len(tools) == 0 {
+		return fmt.Errorf("tool set %s has no tools", set.Name)
+	}
+
+	s.toolsMu.Lock()
+	if s.tools == nil {
+		s.tools = make(map[string]server.ToolHandlerFunc)
+		s.toolDefs = make(map[string]mcp.Tool)
+	}
+	if s.toolSets == nil {
+		s.toolSets = make(map[string]*ToolSet)
+	}
+	previous := s.toolSets[set.Name]
+	owned := make(map[string]bool)
+	if previous != nil {
+		for _, tool := range previous.Tools {
+			owned[tool] = true
+		}
+	}
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		toolName := tool.Tool.Name
+		switch {
+		case !toolNamePattern.MatchString(toolName):
+			s.toolsMu.Unlock()
+			return fmt.Errorf("tool set %s: invalid tool name %q", set.Name, toolName)
+		case names[toolName]:
+			s.toolsMu.Unlock()
+			return fmt.Errorf("tool set %s: tool %s is defined twice", set.Name, toolName)
+		case tool.Handler == nil:
+			s.toolsMu.Unlock()
+			return fmt.Errorf("tool set %s: tool %s has no handler", set.Name, toolName)
+		}
+		if _, exists := s.tools[toolName]; exists && !owned[toolName] {
+			owner := "the active profile"
+			if other := s.toolSetOf(toolName); other != "" {
+				owner = "tool set " + other
+			}
+			s.toolsMu.Unlock()
+			return fmt.Errorf("tool set %s: tool %s is already registered by %s", set.Name, toolName, owner)
+		}
+		names[toolName] = true
+	}
+
+	var dropped []string
+	for tool := range owned {
+		if !names[tool] {
+			dropped = append(dropped, tool)
+		}
+	}
+	removed := s.removeTools(dropped)
+	added := s.addTools(tools)
+	set.Tools = sortedKeys(names)
+	set.Registered = time.Now()
+	s.toolSets[set.Name] = set
+	s.toolsMu.Unlock()
+
+	// AddTools and DeleteTools send notifications/tools/list_changed
+	if s.server != nil {
+		if len(removed) > 0 {
+			s.server.DeleteTools(removed...)
+		}
+		s.server.AddTools(added...)
+	}
+	s.registerToolAliases()
+	logrus.WithFields(logrus.Fields{"tool_set": set.Name, "source": set.Source, "tools": set.Tools}).Info("Registered tool set")
+	return nil
+}
+
+// UnregisterToolSet removes the tools of a set registered at runtime
+func (s *Server) UnregisterToolSet(name string) error {
+	s.toolsMu.Lock()
+	set, ok := s.toolSets[name]
+	if !ok {
+		s.toolsMu.Unlock()
+		return fmt.Errorf("tool set %q is not registered", name)
+	}
+	removed := s.removeTools(set.Tools)
+	delete(s.toolSets, name)
+	s.toolsMu.Unlock()
+
+	if s.server != nil {
+		s.server.DeleteTools(removed...)
+	}
+	logrus.WithFields(logrus.Fields{"tool_set": name, "tools": removed}).Info("Unregistered tool set")
+	return nil
+}
+
+// ToolSets returns the tool sets registered at runtime, by name
+func (s *Server) ToolSets() []ToolSet {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	sets := make([]ToolSet, 0, len(s.toolSets))
+	for _, set := range s.toolSets {
+		copied := *set
+		copied.Tools = append([]string(nil), set.Tools...)
+		sets = append(sets, copied)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets
+}
+
+// toolSetOf returns the set a tool was registered with, or "" for the
+// profile's tools. The caller holds toolsMu.
+func (s *Server) toolSetOf(tool string) string {
+	for _, set := range s.toolSets {
+		for _, name := range set.Tools {
+			if name == tool {
+				return set.Name
+			}
+		}
+	}
+	return ""
+}
+
+// toolCount returns the number of registered tools
+func (s *Server) toolCount() int {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	return len(s.toolDefs)
+}
+
+// RunbookToolSet is a tool set definition file in the tool set directory.
+// Each tool runs a fixed sequence of the profile's tools, with arguments
+// using Go template syntax over the tool's parameters, e.g. {{ .namespace }}.
+type RunbookToolSet struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Incident    string        `json:"incident,omitempty"` // what a learned runbook resolved, matched against new prompts
+	Tools       []RunbookTool `json:"tools"`
+}
+
+// RunbookTool is a tool of a runbook tool set
+type RunbookTool struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Parameters  []CatalogParameter `json:"parameters,omitempty"`
+	Steps       []RunbookStep      `json:"steps"`
+}
+
+// RunbookStep is a call to one of the profile's tools
+type RunbookStep struct {
+	Tool      string            `json:"tool"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// parseRunbookToolSet reads a tool set definition; the set is named after
+// the file unless it names itself
+func parseRunbookToolSet(filename string, data []byte) (RunbookToolSet, error) {
+	var definition RunbookToolSet
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return definition, fmt.Errorf("invalid definition: %v", err)
+	}
+	if definition.Name == "" {
+		name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		definition.Name = strings.ReplaceAll(name, "-", "_")
+	}
+	if len(definition.Tools) == 0 {
+		return definition, fmt.Errorf("no tools defined")
+	}
+	for _, tool := range definition.Tools {
+		if len(tool.Steps) == 0 {
+			return definition, fmt.Errorf("tool %s has no steps", tool.Name)
+		}
+		for _, parameter := range tool.Parameters {
+			if parameter.Name == "" {
+				return definition, fmt.Errorf("tool %s has a parameter without a name", tool.Name)
+			}
+		}
+		for i, step := range tool.Steps {
+			if step.Tool == "" {
+				return definition, fmt.Errorf("tool %s step %d names no tool", tool.Name, i+1)
+			}
+			for argument, value := range step.Arguments {
+				if _, err := template.New(argument).Option("missingkey=error").Parse(value); err != nil {
+					return definition, fmt.Errorf("tool %s step %d argument %s: %v", tool.Name, i+1, argument, err)
+				}
+			}
+		}
+	}
+	return definition, nil
+}
+
+// runbookServerTools builds the tools of a runbook tool set. Steps may only
+// call the profile's own tools, so runbooks cannot call each other in a loop.
+// A runbook is read-only when all of its steps are.
+func (s *Server) runbookServerTools(definition RunbookToolSet) ([]server.ServerTool, error) {
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+
+	var tools []server.ServerTool
+	for _, runbook := range definition.Tools {
+		readOnly, destructive := true, false
+		for i, step := range runbook.Steps {
+			tool, ok := s.toolDefs[step.Tool]
+			if !ok {
+				return nil, fmt.Errorf("tool %s step %d calls %s, which is not registered", runbook.Name, i+1, step.Tool)
+			}
+			if set := s.toolSetOf(step.Tool); set != "" && set != definition.Name {
+				return nil, fmt.Errorf("tool %s step %d calls %s from tool set %s; steps may only call the profile's tools", runbook.Name, i+1, step.Tool, set)
+			}
+			readOnly = readOnly && toolReadOnly(tool)
+			destructive = destructive || toolDestructive(tool)
+		}
+
+		description := runbook.Description
+		if description == "" {
+			description = definition.Description
+		}
+		var steps []string
+		for _, step := range runbook.Steps {
+			steps = append(steps, step.Tool)
+		}
+		options := []mcp.ToolOption{
+			mcp.WithDescription(fmt.Sprintf("%s (runbook: %s)", description, strings.Join(steps, " → "))),
+			mcp.WithTitleAnnotation("Runbook: " + runbook.Name),
+		}
+		for _, parameter := range runbook.Parameters {
+			propertyOptions := []mcp.PropertyOption{mcp.Description(parameter.Description)}
+			if parameter.Default != "" {
+				propertyOptions = append(propertyOptions, mcp.DefaultString(parameter.Default))
+			} else if parameter.Required {
+				propertyOptions = append(propertyOptions, mcp.Required())
+			}
+			options = append(options, mcp.WithString(parameter.Name, propertyOptions...))
+		}
+		if readOnly {
+			options = append(options, mcp.WithReadOnlyHintAnnotation(true))
+		} else {
+			options = append(options, mcp.WithDestructiveHintAnnotation(destructive))
+		}
+		tools = append(tools, server.ServerTool{
+			Tool:    mcp.NewTool(runbook.Name, options...),
+			Handler: s.runbookHandler(definition.Name, runbook),
+		})
+	}
+	return tools, nil
+}
+
+// runbookValues resolves a runbook's parameters from the call's arguments
+func runbookValues(runbook RunbookTool, args map[string]interface{}) (map[string]string, error) {
+	values := make(map[string]string)
+	known := make(map[string]bool)
+	var missing []string
+	for _, parameter := range runbook.Parameters {
+		known[parameter.Name] = true
+		value := parameter.Default
+		if arg, ok := args[parameter.Name]; ok && fmt.Sprint(arg) != "" {
+			value = fmt.Sprint(arg)
+		}
+		if value == "" && parameter.Required {
+			missing = append(missing, parameter.Name)
+		}
+		values[parameter.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required parameter(s): %s", strings.Join(missing, ", "))
+	}
+	for name := range args {
+		if !known[name] && name != freezeOverrideParam {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	return values, nil
+}
+
+// runbookHandler runs a runbook's steps in order through CallTool, so each
+// step is guarded like a direct call, and stops at the first failing step
+func (s *Server) runbookHandler(set string, runbook RunbookTool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		values, err := runbookValues(runbook, args)
+		if err != nil {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ %s: %v", runbook.Name, err)), nil
+		}
+
+		result := "📒 Runbook\n"
+		result += "==========\n\n"
+		result += fmt.Sprintf("Runbook: %s (tool set %s), %d step(s)\n", runbook.Name, set, len(runbook.Steps))
+
+		for i, step := range runbook.Steps {
+			stepRequest := mcp.CallToolRequest{}
+			stepRequest.Params.Name = step.Tool
+			stepArgs := make(map[string]interface{}, len(step.Arguments)+1)
+			for _, argument := range sortedKeys(step.Arguments) {
+				tmpl, err := template.New(argument).Option("missingkey=error").Parse(step.Arguments[argument])
+				if err == nil {
+					var out bytes.Buffer
+					if err = tmpl.Execute(&out, values); err == nil {
+						stepArgs[argument] = out.String()
+						continue
+					}
+				}
+				result += fmt.Sprintf("\n❌ Step %d (%s): argument %s: %v\n", i+1, step.Tool, argument, err)
+				return mcp.NewToolResultText(result), nil
+			}
+			if token, ok := args[freezeOverrideParam]; ok {
+				stepArgs[freezeOverrideParam] = token
+			}
+			stepRequest.Params.Arguments = stepArgs
+
+			result += fmt.Sprintf("\n▶️  Step %d/%d: %s\n", i+1, len(runbook.Steps), step.Tool)
+			stepResult, err := s.CallTool(ctx, stepRequest)
+			if err != nil {
+				result += fmt.Sprintf("❌ %v\n", err)
+			} else if stepResult != nil {
+				result += resultContentText(stepResult) + "\n"
+			}
+			stepError, failed := ResultError(stepResult)
+			if err != nil || stepResult == nil || stepResult.IsError || failed {
+				result += fmt.Sprintf("\n⏹️  Stopped after step %d of %d", i+1, len(runbook.Steps))
+				stopped := mcp.NewToolResultText(result)
+				// The runbook fails the way its step did
+				switch {
+				case failed:
+					withErrorKind(stopped, stepError.Kind, fmt.Sprintf("step %d (%s): %s", i+1, step.Tool, stepError.Message))
+				case err != nil:
+					withErrorKind(stopped, ClassifyError(err), fmt.Sprintf("step %d (%s): %v", i+1, step.Tool, err))
+				}
+				return stopped, nil
+			}
+		}
+		result += fmt.Sprintf("\n✅ Completed %d step(s)", len(runbook.Steps))
+		return mcp.NewToolResultText(result), nil
+	}
+}
+
+// resultContentText joins the text content of a tool result
+func resultContentText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toolSetReload is the outcome of reading the tool set directory
+type toolSetReload struct {
+	registered []string
+	unchanged  []string
+	removed    []string
+	failed     []string
+}
+
+// toolSetDir returns the configured tool set directory, or "" when runbook
+// tool sets are not enabled
+func (s *Server) toolSetDir() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.ToolSetDir
+}
+
+// reloadToolSets registers the tool sets defined in the tool set directory,
+// re-registers changed ones and unregisters those whose file is gone
+func (s *Server) reloadToolSets() (toolSetReload, error) {
+	var reload toolSetReload
+	dir := filepath.Clean(s.toolSetDir())
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return reload, fmt.Errorf("reading tool set directory: %v", err)
+	}
+
+	defined := make(map[string]bool)
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			reload.failed = append(reload.failed, fmt.Sprintf("%s: %v", entry.Name(), err))
+			continue
+		}
+		definition, err := parseRunbookToolSet(entry.Name(), data)
+		if err != nil {
+			reload.failed = append(reload.failed, fmt.Sprintf("%s: %v", entry.Name(), err))
+			continue
+		}
+		if defined[definition.Name] {
+			reload.failed = append(reload.failed, fmt.Sprintf("%s: tool set %s is already defined by another file", entry.Name(), definition.Name))
+			continue
+		}
+		defined[definition.Name] = true
+
+		sum := sha256.Sum256(data)
+		digest := hex.EncodeToString(sum[:])
+		source := "file:" + path
+		s.toolsMu.RLock()
+		current := s.toolSets[definition.Name]
+		unchanged := current != nil && current.Source == source && current.digest == digest
+		s.toolsMu.RUnlock()
+		if unchanged {
+			reload.unchanged = append(reload.unchanged, definition.Name)
+			continue
+		}
+
+		tools, err := s.runbookServerTools(definition)
+		if err == nil {
+			err = s.registerToolSet(&ToolSet{Name: definition.Name, Source: source, digest: digest}, tools)
+		}
+		if err != nil {
+			reload.failed = append(reload.failed, fmt.Sprintf("%s: %v", entry.Name(), err))
+			continue
+		}
+		reload.registered = append(reload.registered, definition.Name)
+	}
+
+	for _, set := range s.ToolSets() {
+		if strings.HasPrefix(set.Source, "file:"+dir+string(filepath.Separator)) && !defined[set.Name] {
+			if err := s.UnregisterToolSet(set.Name); err == nil {
+				reload.removed = append(reload.removed, set.Name)
+			}
+		}
+	}
+	s.indexRunbooks()
+	return reload, nil
+}
+
+// loadToolSetDir registers the runbook tool sets found at start
+func (s *Server) loadToolSetDir() {
+	if s.toolSetDir() == "" {
+		return
+	}
+	reload, err := s.reloadToolSets()
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to load tool sets")
+		return
+	}
+	for _, failure := range reload.failed {
+		logrus.Warnf("Skipping tool set %s", failure)
+	}
+}
+
+func (s *Server) listToolSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sets := s.ToolSets()
+
+	result := "🧩 Tool Sets\n"
+	result += "============\n\n"
+	result += fmt.Sprintf("Tool set directory: %s\n", valueOrNone(s.toolSetDir()))
+	if len(sets) == 0 {
+		result += "\nNo tool sets are registered; only the profile's tools are available\n"
+		result += "\n💡 Put runbook tool set definitions in the tool set directory (mcp.tool-set-dir) and call reload_tool_sets"
+		return mcp.NewToolResultText(result), nil
+	}
+
+	s.toolsMu.RLock()
+	defs := make(map[string]mcp.Tool)
+	for _, set := range sets {
+		for _, name := range set.Tools {
+			defs[name] = s.toolDefs[name]
+		}
+	}
+	s.toolsMu.RUnlock()
+
+	result += fmt.Sprintf("\n📦 %d tool set(s):\n", len(sets))
+	for _, set := range sets {
+		result += fmt.Sprintf("\n🧩 %s - %s, registered %s ago\n", set.Name, set.Source, formatAge(set.Registered))
+		for _, name := range set.Tools {
+			icon := "✏️ "
+			if toolReadOnly(defs[name]) {
+				icon = "👁️ "
+			} else if toolDestructive(defs[name]) {
+				icon = "⚠️ "
+			}
+			result += fmt.Sprintf("   %s %s - %s\n", icon, name, defs[name].Description)
+		}
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) reloadToolSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.toolSetDir() == "" {
+		return mcp.NewToolResultText("❌ No tool set directory is configured (mcp.tool-set-dir)"), nil
+	}
+	reload, err := s.reloadToolSets()
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to reload tool sets: %v", err)), nil
+	}
+
+	result := "🔄 Tool Set Reload\n"
+	result += "==================\n\n"
+	result += fmt.Sprintf("Tool set directory: %s\n\n", s.toolSetDir())
+	for _, section := range []struct {
+		icon, label string
+		names       []string
+	}{
+		{"✅", "Registered", reload.registered},
+		{"➖", "Unchanged", reload.unchanged},
+		{"🗑️ ", "Removed", reload.removed},
+		{"❌", "Failed", reload.failed},
+	} {
+		if len(section.names) > 0 {
+			result += fmt.Sprintf("%s %s: %s\n", section.icon, section.label, strings.Join(section.names, ", "))
+		}
+	}
+	if len(reload.registered)+len(reload.removed) > 0 {
+		result += "\n📣 Connected clients were notified that the tool list changed"
+	} else {
+		result += "\nNo changes to the tool list"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// ListToolSetsHandler is a public wrapper for listToolSetsHandler
+func (s *Server) ListToolSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listToolSetsHandler(ctx, request)
+}
+
+// ReloadToolSetsHandler is a public wrapper for reloadToolSetsHandler
+func (s *Server) ReloadToolSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.reloadToolSetsHandler(ctx, request)
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// listedTools returns the tool names the MCP server reports to clients
+func listedTools(t *testing.T, s *Server) []string {
+	t.Helper()
+	response := s.server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("encoding tools/list response: %v", err)
+	}
+	var decoded struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("decoding tools/list response: %v", err)
+	}
+	var names []string
+	for _, tool := range decoded.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestRegisterToolSet(t *testing.T) {
+	s := newPolicyTestServer(false)
+	s.server = server.NewMCPServer("test", "1.0.0")
+	hello := server.ServerTool{
+		Tool: mcp.NewTool("say_hello", mcp.WithReadOnlyHintAnnotation(true)),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("hello"), nil
+		},
+	}
+
+	if err := s.RegisterToolSet("greetings", "plugin:greetings", []server.ServerTool{hello}); err != nil {
+		t.Fatalf("RegisterToolSet() error = %v", err)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "say_hello"
+	if result, err := s.CallTool(context.Background(), request); err != nil || resultText(result) != "hello" {
+		t.Errorf("CallTool(say_hello) = %q, %v", resultText(result), err)
+	}
+	if listed := listedTools(t, s); !reflect.DeepEqual(listed, []string{"get_pods", "say_hello"}) {
+		t.Errorf("tools/list = %v, expected say_hello and the now aliased get_pods", listed)
+	}
+	if sets := s.ToolSets(); len(sets) != 1 || sets[0].Source != "plugin:greetings" || !reflect.DeepEqual(sets[0].Tools, []string{"say_hello"}) {
+		t.Errorf("ToolSets() = %+v", sets)
+	}
+	if caps := s.Capabilities(); caps.Tools[len(caps.Tools)-1].ToolSet != "greetings" {
+		t.Errorf("Capabilities() tool %+v, expected tool set greetings", caps.Tools[len(caps.Tools)-1])
+	}
+
+	clashes := []struct {
+		set      string
+		tool     string
+		expected string
+	}{
+		{"pods", "list_pods", "tool set pods: tool list_pods is already registered by the active profile"},
+		{"other", "say_hello", "tool set other: tool say_hello is already registered by tool set greetings"},
+		{"Bad-Name", "x", `invalid tool set name "Bad-Name": use lowercase letters, digits and underscores`},
+	}
+	for _, tt := range clashes {
+		tool := server.ServerTool{Tool: mcp.NewTool(tt.tool), Handler: hello.Handler}
+		if err := s.RegisterToolSet(tt.set, "test", []server.ServerTool{tool}); err == nil || err.Error() != tt.expected {
+			t.Errorf("RegisterToolSet(%s, %s) error = %v, expected %q", tt.set, tt.tool, err, tt.expected)
+		}
+	}
+
+	if err := s.UnregisterToolSet("greetings"); err != nil {
+		t.Fatalf("UnregisterToolSet() error = %v", err)
+	}
+	if s.HasTool("say_hello") || !reflect.DeepEqual(listedTools(t, s), []string{"get_pods"}) || len(s.ToolSets()) != 0 {
+		t.Errorf("say_hello is still registered after UnregisterToolSet")
+	}
+	if err := s.UnregisterToolSet("greetings"); err == nil {
+		t.Errorf("UnregisterToolSet() of an unknown set succeeded")
+	}
+}
+
+const triageToolSet = `description: Checkout team runbooks
+tools:
+- name: triage_checkout
+  description: First look at the checkout service
+  parameters:
+  - name: namespace
+    description: Checkout namespace
+    default: shop
+  - name: app
+    required: true
+  steps:
+  - tool: list_pods
+    arguments:
+      namespace: "{{ .namespace }}"
+      label_selector: "app={{ .app }}"
+  - tool: delete_resource
+    arguments:
+      name: "{{ .app }}"
+`
+
+func TestReloadToolSets(t *testing.T) {
+	dir := t.TempDir()
+	s := newPolicyTestServer(false)
+	s.config.ToolSetDir = dir
+	var calls []string
+	s.tools["list_pods"] = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls = append(calls, request.GetArguments()["namespace"].(string)+" "+request.GetArguments()["label_selector"].(string))
+		return mcp.NewToolResultText("📦 cart-1"), nil
+	}
+	s.tools["delete_resource"] = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("❌ refused"), nil
+	}
+
+	path := filepath.Join(dir, "checkout-team.yaml")
+	if err := os.WriteFile(path, []byte(triageToolSet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("tools:\n- name: broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reload, err := s.reloadToolSets()
+	if err != nil {
+		t.Fatalf("reloadToolSets() error = %v", err)
+	}
+	if !reflect.DeepEqual(reload.registered, []string{"checkout_team"}) || !reflect.DeepEqual(reload.failed, []string{"broken.yaml: tool broken has no steps"}) {
+		t.Errorf("reloadToolSets() = %+v", reload)
+	}
+	if tool := s.toolDefs["triage_checkout"]; toolReadOnly(tool) || !toolDestructive(tool) {
+		t.Errorf("triage_checkout has hints %+v, expected destructive like delete_resource", tool.Annotations)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "triage_checkout"
+	request.Params.Arguments = map[string]interface{}{"app": "cart"}
+	result, err := s.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CallTool(triage_checkout) error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Runbook: triage_checkout (tool set checkout_team), 2 step(s)",
+		"▶️  Step 1/2: list_pods\n📦 cart-1\n",
+		"▶️  Step 2/2: delete_resource\n❌ refused\n\n⏹️  Stopped after step 2 of 2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("triage_checkout result missing %q:\n%s", want, text)
+		}
+	}
+	if !reflect.DeepEqual(calls, []string{"shop app=cart"}) {
+		t.Errorf("list_pods called with %v, expected [shop app=cart]", calls)
+	}
+
+	request.Params.Arguments = map[string]interface{}{}
+	if result, _ := s.CallTool(context.Background(), request); resultText(result) != "❌ triage_checkout: missing required parameter(s): app" {
+		t.Errorf("CallTool(triage_checkout) without app = %q", resultText(result))
+	}
+
+	if reload, _ := s.reloadToolSets(); !reflect.DeepEqual(reload.unchanged, []string{"checkout_team"}) {
+		t.Errorf("second reloadToolSets() = %+v, expected checkout_team unchanged", reload)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if reload, _ := s.reloadToolSets(); !reflect.DeepEqual(reload.removed, []string{"checkout_team"}) || s.HasTool("triage_checkout") {
+		t.Errorf("reloadToolSets() after removing the file = %+v", reload)
+	}
+}
@@ -0,0 +1,375 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	podMetricsGVR  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	nodeMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+)
+
+// resourceUsage is the CPU and memory usage of a pod, container or node
+type resourceUsage struct {
+	namespace string
+	name      string
+	cpu       resource.Quantity
+	memory    resource.Quantity
+	// cpuOf and memoryOf are what the usage is compared to: the requests of
+	// a pod or the allocatable resources of a node
+	cpuOf    resource.Quantity
+	memoryOf resource.Quantity
+	// memoryLimit is the memory limit of a pod when every container sets one
+	memoryLimit resource.Quantity
+}
+
+func (s *Server) initResourceUsage() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("top_pods",
+			mcp.WithDescription("Show the actual CPU and memory usage of pods from the metrics API, compared with their requests and memory limits, like oc adm top pods"),
+			mcp.WithString("namespace", mcp.Description("Namespace to show pods from, or 'all' (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=web")),
+			mcp.WithString("sort_by", mcp.Description("Sort by cpu or memory (default: cpu)")),
+			mcp.WithString("containers", mcp.Description("Show the usage of each container (true/false)")),
+			mcp.WithString("limit", mcp.Description("Maximum number of pods to show (default 20)")),
+			mcp.WithTitleAnnotation("Usage: Top Pods"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.topPodsHandler)},
+		{Tool: mcp.NewTool("top_nodes",
+			mcp.WithDescription("Show the actual CPU and memory usage of nodes from the metrics API as a share of their allocatable resources, like oc adm top nodes"),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. node-role.kubernetes.io/worker=")),
+			mcp.WithString("sort_by", mcp.Description("Sort by cpu or memory (default: cpu)")),
+			mcp.WithTitleAnnotation("Usage: Top Nodes"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.topNodesHandler)},
+	}
+}
+
+// parseUsage reads the cpu and memory of a metrics usage map
+func parseUsage(usage map[string]interface{}) (cpu, memory resource.Quantity) {
+	if value, ok := usage["cpu"].(string); ok {
+		cpu, _ = resource.ParseQuantity(value)
+	}
+	if value, ok := usage["memory"].(string); ok {
+		memory, _ = resource.ParseQuantity(value)
+	}
+	return cpu, memory
+}
+
+// podMetricsUsage sums the container usage of a PodMetrics object and
+// returns the usage of each container
+func podMetricsUsage(item *unstructured.Unstructured) (resourceUsage, []resourceUsage) {
+	pod := resourceUsage{namespace: item.GetNamespace(), name: item.GetName()}
+	containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+	var perContainer []resourceUsage
+	for _, entry := range containers {
+		container, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		usage, _ := container["usage"].(map[string]interface{})
+		cpu, memory := parseUsage(usage)
+		name, _ := container["name"].(string)
+		perContainer = append(perContainer, resourceUsage{name: name, cpu: cpu, memory: memory})
+		pod.cpu.Add(cpu)
+		pod.memory.Add(memory)
+	}
+	sort.Slice(perContainer, func(i, j int) bool { return perContainer[i].name < perContainer[j].name })
+	return pod, perContainer
+}
+
+// podMemoryLimit sums the memory limits of a pod's containers; it is zero
+// when a container has no limit
+func podMemoryLimit(pod *corev1.Pod) resource.Quantity {
+	var total resource.Quantity
+	for _, container := range pod.Spec.Containers {
+		limit, ok := container.Resources.Limits[corev1.ResourceMemory]
+		if !ok {
+			return resource.Quantity{}
+		}
+		total.Add(limit)
+	}
+	return total
+}
+
+// sortUsage orders usage by cpu or memory, highest first
+func sortUsage(usage []resourceUsage, sortBy string) {
+	sort.SliceStable(usage, func(i, j int) bool {
+		if sortBy == "memory" {
+			return usage[i].memory.Cmp(usage[j].memory) > 0
+		}
+		return usage[i].cpu.Cmp(usage[j].cpu) > 0
+	})
+}
+
+// formatCPU renders CPU in millicores, as oc adm top does
+func formatCPU(quantity resource.Quantity) string {
+	return fmt.Sprintf("%dm", quantity.MilliValue())
+}
+
+// formatMemoryMi renders memory in MiB, as oc adm top does
+func formatMemoryMi(quantity resource.Quantity) string {
+	return fmt.Sprintf("%dMi", quantity.Value()/(1024*1024))
+}
+
+// formatUsage renders usage with its share of what it is compared to, e.g.
+// "250m (125% of request)"
+func formatUsage(used, of resource.Quantity, format func(resource.Quantity) string, ofName string) string {
+	if of.IsZero() {
+		return format(used)
+	}
+	return fmt.Sprintf("%s (%s of %s)", format(used), formatPercent(used, of), ofName)
+}
+
+// parseSortBy reads the sort_by parameter
+func parseSortBy(request mcp.CallToolRequest) (string, error) {
+	sortBy := strings.ToLower(strings.TrimSpace(mcp.ParseString(request, "sort_by", "cpu")))
+	if sortBy != "cpu" && sortBy != "memory" {
+		return "", fmt.Errorf("invalid sort_by '%s' (expected cpu or memory)", sortBy)
+	}
+	return sortBy, nil
+}
+
+// metricsUnavailable explains why usage cannot be shown
+func metricsUnavailable(err error) string {
+	result := fmt.Sprintf("⚠️  The resource metrics API (%s) is not available: %v\n", resourceMetricsGroupVersion, err)
+	result += "   Actual usage cannot be shown; below are the configured requests instead.\n"
+	result += "💡 Check the metrics APIService: oc get apiservice v1beta1.metrics.k8s.io, and the monitoring stack in openshift-monitoring\n\n"
+	return result
+}
+
+func (s *Server) topPodsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil || s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	sortBy, err := parseSortBy(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	showContainers := parseBoolString(mcp.ParseString(request, "containers", "false"))
+	limit, err := strconv.Atoi(mcp.ParseString(request, "limit", "20"))
+	if err != nil || limit < 1 {
+		return mcp.NewToolResultText("❌ limit must be a positive number"), nil
+	}
+
+	pods, err := s.k8sClient.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list pods in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	specs := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		specs[pods.Items[i].Namespace+"/"+pods.Items[i].Name] = &pods.Items[i]
+	}
+
+	result := "📈 Pod Resource Usage\n"
+	result += "====================\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+
+	var usage []resourceUsage
+	containers := make(map[string][]resourceUsage)
+	metrics, metricsErr := s.dynamicClient.Resource(podMetricsGVR).Namespace(namespace).List(ctx, opts)
+	if metricsErr != nil {
+		recordToolError(ctx, metricsErr)
+		result += "\n" + metricsUnavailable(metricsErr)
+		for i := range pods.Items {
+			cpu, memory := podRequests(&pods.Items[i])
+			usage = append(usage, resourceUsage{namespace: pods.Items[i].Namespace, name: pods.Items[i].Name, cpu: cpu, memory: memory})
+		}
+	} else {
+		for i := range metrics.Items {
+			pod, perContainer := podMetricsUsage(&metrics.Items[i])
+			key := pod.namespace + "/" + pod.name
+			if spec, ok := specs[key]; ok {
+				pod.cpuOf, pod.memoryOf = podRequests(spec)
+				pod.memoryLimit = podMemoryLimit(spec)
+			}
+			usage = append(usage, pod)
+			containers[key] = perContainer
+		}
+	}
+
+	sortUsage(usage, sortBy)
+	var totalCPU, totalMemory resource.Quantity
+	for _, pod := range usage {
+		totalCPU.Add(pod.cpu)
+		totalMemory.Add(pod.memory)
+	}
+	if metricsErr != nil {
+		result += fmt.Sprintf("📦 %d pods request %s CPU and %s memory in total, sorted by %s:\n", len(usage), formatCPU(totalCPU), formatMemoryMi(totalMemory), sortBy)
+	} else {
+		result += fmt.Sprintf("📦 %d pods use %s CPU and %s memory in total, sorted by %s:\n", len(usage), formatCPU(totalCPU), formatMemoryMi(totalMemory), sortBy)
+	}
+
+	var nearLimit []string
+	for i, pod := range usage {
+		if i >= limit {
+			result += fmt.Sprintf("... and %d more (raise limit to see them)\n", len(usage)-limit)
+			break
+		}
+		name := pod.name
+		if namespace == metav1.NamespaceAll {
+			name = pod.namespace + "/" + pod.name
+		}
+		line := fmt.Sprintf("📦 %s - CPU %s, memory %s", name,
+			formatUsage(pod.cpu, pod.cpuOf, formatCPU, "request"), formatUsage(pod.memory, pod.memoryOf, formatMemoryMi, "request"))
+		if !pod.memoryLimit.IsZero() {
+			line += fmt.Sprintf(", %s of memory limit", formatPercent(pod.memory, pod.memoryLimit))
+			if pod.memory.MilliValue()*100 >= pod.memoryLimit.MilliValue()*90 {
+				nearLimit = append(nearLimit, name)
+				line = "⚠️  " + strings.TrimPrefix(line, "📦 ")
+			}
+		}
+		result += line + "\n"
+		if showContainers {
+			for _, container := range containers[pod.namespace+"/"+pod.name] {
+				result += fmt.Sprintf("   └─ %s: CPU %s, memory %s\n", container.name, formatCPU(container.cpu), formatMemoryMi(container.memory))
+			}
+		}
+	}
+	if len(usage) == 0 {
+		result += "📭 No pods found\n"
+	}
+
+	if len(nearLimit) > 0 {
+		result += fmt.Sprintf("\n⚠️  %s use over 90%% of their memory limit and risk being OOMKilled; raise the limit or look for a leak\n", strings.Join(nearLimit, ", "))
+	}
+	if metricsErr == nil && len(usage) > 0 {
+		result += "\n💡 Usage is sampled by the metrics API over a short window; a percentage above 100% of request means the pod uses more than the scheduler reserved for it\n"
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+func (s *Server) topNodesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil || s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	_, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	sortBy, err := parseSortBy(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	nodes, err := s.k8sClient.CoreV1().Nodes().List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, "Failed to list nodes", err), nil
+	}
+
+	result := "📈 Node Resource Usage\n"
+	result += "=====================\n\n"
+	if opts.LabelSelector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", opts.LabelSelector)
+	}
+
+	usage := make([]resourceUsage, 0, len(nodes.Items))
+	metrics, metricsErr := s.dynamicClient.Resource(nodeMetricsGVR).List(ctx, opts)
+	if metricsErr != nil {
+		recordToolError(ctx, metricsErr)
+		result += "\n" + metricsUnavailable(metricsErr)
+
+		// Without metrics, compare what the scheduled pods request instead
+		requested := make(map[string]*resourceUsage)
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			requested[node.Name] = &resourceUsage{name: node.Name, cpuOf: node.Status.Allocatable[corev1.ResourceCPU], memoryOf: node.Status.Allocatable[corev1.ResourceMemory]}
+		}
+		pods, err := s.k8sClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return toolError(ctx, "Failed to list pods", err), nil
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			node, ok := requested[pod.Spec.NodeName]
+			if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			cpu, memory := podRequests(pod)
+			node.cpu.Add(cpu)
+			node.memory.Add(memory)
+		}
+		for _, name := range sortedKeys(requested) {
+			usage = append(usage, *requested[name])
+		}
+	} else {
+		allocatable := make(map[string]*corev1.Node, len(nodes.Items))
+		for i := range nodes.Items {
+			allocatable[nodes.Items[i].Name] = &nodes.Items[i]
+		}
+		for i := range metrics.Items {
+			usageMap, _, _ := unstructured.NestedMap(metrics.Items[i].Object, "usage")
+			cpu, memory := parseUsage(usageMap)
+			node := resourceUsage{name: metrics.Items[i].GetName(), cpu: cpu, memory: memory}
+			if spec, ok := allocatable[node.name]; ok {
+				node.cpuOf = spec.Status.Allocatable[corev1.ResourceCPU]
+				node.memoryOf = spec.Status.Allocatable[corev1.ResourceMemory]
+			}
+			usage = append(usage, node)
+		}
+	}
+
+	sortUsage(usage, sortBy)
+	verb := "use"
+	if metricsErr != nil {
+		verb = "have requests of"
+	}
+	result += fmt.Sprintf("🖥️  %d nodes, sorted by %s:\n", len(usage), sortBy)
+
+	var busy []string
+	for _, node := range usage {
+		line := fmt.Sprintf("🖥️  %s - %s CPU %s, memory %s", node.name, verb,
+			formatUsage(node.cpu, node.cpuOf, formatCPU, "allocatable"), formatUsage(node.memory, node.memoryOf, formatMemoryMi, "allocatable"))
+		if metricsErr == nil && (overShare(node.cpu, node.cpuOf, 85) || overShare(node.memory, node.memoryOf, 85)) {
+			busy = append(busy, node.name)
+			line = "⚠️  " + strings.TrimPrefix(line, "🖥️  ")
+		}
+		result += line + "\n"
+	}
+	if len(usage) == 0 {
+		result += "📭 No nodes found\n"
+	}
+	if len(busy) > 0 {
+		result += fmt.Sprintf("\n⚠️  %s use over 85%% of their allocatable CPU or memory; pods there may be throttled or evicted. Run describe_node to see what runs on them\n", strings.Join(busy, ", "))
+	}
+	return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+}
+
+// overShare reports whether used is over percent of total
+func overShare(used, total resource.Quantity, percent int64) bool {
+	return !total.IsZero() && used.MilliValue()*100 > total.MilliValue()*percent
+}
+
+// TopPodsHandler is a public wrapper for topPodsHandler
+func (s *Server) TopPodsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.topPodsHandler(ctx, request)
+}
+
+// TopNodesHandler is a public wrapper for topNodesHandler
+func (s *Server) TopNodesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.topNodesHandler(ctx, request)
+}
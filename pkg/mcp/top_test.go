@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func usagePod(name, node, cpuRequest, memoryRequest, memoryLimit string) *corev1.Pod {
+	resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+		corev1.ResourceMemory: resource.MustParse(memoryRequest),
+	}}
+	if memoryLimit != "" {
+		resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memoryLimit)}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{Name: "app", Resources: resources}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func usageNode(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}},
+	}
+}
+
+func newUsageTestServer(t *testing.T, withMetrics bool) *Server {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podMetricsGVR:  "PodMetricsList",
+		nodeMetricsGVR: "NodeMetricsList",
+	})
+	if withMetrics {
+		metrics := []struct {
+			gvr       schema.GroupVersionResource
+			namespace string
+			object    map[string]interface{}
+		}{
+			{podMetricsGVR, "shop", map[string]interface{}{
+				"apiVersion": "metrics.k8s.io/v1beta1", "kind": "PodMetrics",
+				"metadata": map[string]interface{}{"name": "web-1", "namespace": "shop"},
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "usage": map[string]interface{}{"cpu": "150000000n", "memory": "240Mi"}},
+					map[string]interface{}{"name": "proxy", "usage": map[string]interface{}{"cpu": "50m", "memory": "16Mi"}},
+				},
+			}},
+			{podMetricsGVR, "shop", map[string]interface{}{
+				"apiVersion": "metrics.k8s.io/v1beta1", "kind": "PodMetrics",
+				"metadata":   map[string]interface{}{"name": "cart-1", "namespace": "shop"},
+				"containers": []interface{}{map[string]interface{}{"name": "app", "usage": map[string]interface{}{"cpu": "400m", "memory": "64Mi"}}},
+			}},
+			{nodeMetricsGVR, "", map[string]interface{}{
+				"apiVersion": "metrics.k8s.io/v1beta1", "kind": "NodeMetrics",
+				"metadata": map[string]interface{}{"name": "worker-1"},
+				"usage":    map[string]interface{}{"cpu": "3600m", "memory": "4Gi"},
+			}},
+		}
+		for _, m := range metrics {
+			if _, err := client.Resource(m.gvr).Namespace(m.namespace).Create(context.Background(), &unstructured.Unstructured{Object: m.object}, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("creating metrics: %v", err)
+			}
+		}
+	} else {
+		client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: action.GetResource().Resource}, "")
+		})
+	}
+
+	kubeClient := kubefake.NewSimpleClientset(
+		usagePod("web-1", "worker-1", "100m", "256Mi", "256Mi"),
+		usagePod("cart-1", "worker-1", "500m", "128Mi", ""),
+		usageNode("worker-1", "4", "16Gi"),
+	)
+	return &Server{config: &Config{}, k8sClient: kubeClient, dynamicClient: client}
+}
+
+func usageRequest(args map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func TestTopPods(t *testing.T) {
+	tests := []struct {
+		withMetrics bool
+		args        map[string]interface{}
+		expected    []string
+	}{
+		{true, map[string]interface{}{"namespace": "shop"}, []string{
+			"📦 2 pods use 600m CPU and 320Mi memory in total, sorted by cpu:\n📦 cart-1 - CPU 400m (80% of request), memory 64Mi (50% of request)\n⚠️  web-1 - CPU 200m (200% of request), memory 256Mi (100% of request), 100% of memory limit",
+			"⚠️  web-1 use over 90% of their memory limit",
+		}},
+		{true, map[string]interface{}{"namespace": "shop", "sort_by": "memory", "containers": "true", "limit": "1"}, []string{
+			"⚠️  web-1 - CPU 200m", "   └─ app: CPU 150m, memory 240Mi\n   └─ proxy: CPU 50m, memory 16Mi\n... and 1 more",
+		}},
+		{false, map[string]interface{}{"namespace": "shop"}, []string{
+			"⚠️  The resource metrics API (metrics.k8s.io/v1beta1) is not available",
+			"📦 2 pods request 600m CPU and 384Mi memory in total",
+		}},
+	}
+	for _, tt := range tests {
+		result, err := newUsageTestServer(t, tt.withMetrics).topPodsHandler(context.Background(), usageRequest(tt.args))
+		if err != nil {
+			t.Fatalf("topPodsHandler(%v) error = %v", tt.args, err)
+		}
+		text := resultText(result)
+		for _, want := range tt.expected {
+			if !strings.Contains(text, want) {
+				t.Errorf("topPodsHandler(%v) missing %q:\n%s", tt.args, want, text)
+			}
+		}
+	}
+}
+
+func TestTopNodes(t *testing.T) {
+	tests := []struct {
+		withMetrics bool
+		expected    string
+	}{
+		{true, "⚠️  worker-1 - use CPU 3600m (90% of allocatable), memory 4096Mi (25% of allocatable)"},
+		{false, "🖥️  worker-1 - have requests of CPU 600m (15% of allocatable), memory 384Mi (2% of allocatable)"},
+	}
+	for _, tt := range tests {
+		result, err := newUsageTestServer(t, tt.withMetrics).topNodesHandler(context.Background(), usageRequest(nil))
+		if err != nil {
+			t.Fatalf("topNodesHandler() error = %v", err)
+		}
+		if text := resultText(result); !strings.Contains(text, tt.expected) {
+			t.Errorf("topNodesHandler() with metrics %v missing %q:\n%s", tt.withMetrics, tt.expected, text)
+		}
+	}
+}
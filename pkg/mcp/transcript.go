@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultTranscriptDir is where transcripts are stored when no directory is configured
+const defaultTranscriptDir = "/tmp/diagnostics/transcripts"
+
+// TranscriptArtifact is where an exported chat transcript was stored
+type TranscriptArtifact struct {
+	Path     string `json:"path"`
+	GitPath  string `json:"git_path,omitempty"`
+	GitError string `json:"git_error,omitempty"`
+}
+
+// transcriptDir returns the configured transcript directory
+func (s *Server) transcriptDir() string {
+	if s.config != nil && s.config.TranscriptDir != "" {
+		return s.config.TranscriptDir
+	}
+	return defaultTranscriptDir
+}
+
+// SaveTranscript stores a rendered chat transcript in the transcript
+// directory and, with commit, also commits it to the Git repository under
+// transcripts/. A failed commit is reported in the artifact, not as an error,
+// since the transcript itself was saved.
+func (s *Server) SaveTranscript(ctx context.Context, filename, content string, commit bool) (TranscriptArtifact, error) {
+	if !baselineNamePattern.MatchString(filename) {
+		return TranscriptArtifact{}, fmt.Errorf("invalid transcript file name %q", filename)
+	}
+	if err := os.MkdirAll(s.transcriptDir(), 0755); err != nil {
+		return TranscriptArtifact{}, fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+	artifact := TranscriptArtifact{Path: filepath.Join(s.transcriptDir(), filename)}
+	if err := os.WriteFile(artifact.Path, []byte(content), 0644); err != nil {
+		return TranscriptArtifact{}, fmt.Errorf("failed to write transcript: %w", err)
+	}
+
+	if commit {
+		if s.gitManager == nil {
+			artifact.GitError = ErrGitDisabled.Error()
+			return artifact, nil
+		}
+		gitPath, err := s.gitManager.SaveTranscript(ctx, filename, content, "chat session "+filename)
+		artifact.GitPath = gitPath
+		if err != nil {
+			artifact.GitError = err.Error()
+		}
+	}
+	return artifact, nil
+}
@@ -0,0 +1,27 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestSaveTranscript(t *testing.T) {
+	s := &Server{config: &Config{TranscriptDir: t.TempDir()}}
+
+	if _, err := s.SaveTranscript(context.Background(), "../escape.md", "x", false); err == nil {
+		t.Errorf("SaveTranscript(../escape.md) expected an error")
+	}
+
+	artifact, err := s.SaveTranscript(context.Background(), "incident-42.md", "# transcript\n", true)
+	if err != nil {
+		t.Fatalf("SaveTranscript error = %v", err)
+	}
+	data, err := os.ReadFile(artifact.Path)
+	if err != nil || string(data) != "# transcript\n" {
+		t.Errorf("saved transcript = %q, %v", data, err)
+	}
+	if artifact.GitPath != "" || artifact.GitError != ErrGitDisabled.Error() {
+		t.Errorf("SaveTranscript with git disabled = %+v", artifact)
+	}
+}
@@ -0,0 +1,376 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// unattributedTenant collects usage of callers without an API key, e.g. MCP
+// clients and chat requests sent without one
+const unattributedTenant = "unattributed"
+
+// defaultUsageTopN bounds the tools and actions listed per tenant
+const defaultUsageTopN = 5
+
+// UsageConfig attributes usage to teams and prices LLM tokens
+type UsageConfig struct {
+	// TeamAPIKeys maps a team to the API keys its clients present
+	TeamAPIKeys map[string][]string `json:"team_api_keys"`
+
+	// LLMPrices is the price in USD per million tokens by model or, failing
+	// that, by provider
+	LLMPrices map[string]LLMPrice `json:"llm_prices"`
+}
+
+// LLMPrice is the USD price per million input and output tokens
+type LLMPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// LLMUsage is the tokens one LLM call consumed
+type LLMUsage struct {
+	Provider     string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+type tenantKey struct{}
+
+// WithTenant attributes the chat queries, tool calls and LLM calls made with
+// ctx to a tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// tenantFrom returns the tenant of the caller
+func tenantFrom(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return unattributedTenant
+}
+
+// TenantForKey returns the team owning an API key; keys no team owns are
+// reported by a fingerprint so the key itself never shows up in reports
+func (s *Server) TenantForKey(key string) string {
+	if s.config != nil && s.config.Usage != nil {
+		for _, team := range sortedKeys(s.config.Usage.TeamAPIKeys) {
+			for _, teamKey := range s.config.Usage.TeamAPIKeys[team] {
+				if teamKey == key {
+					return team
+				}
+			}
+		}
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "key-" + hex.EncodeToString(sum[:4])
+}
+
+// llmPrice looks up the price of a model, then of its provider
+func (s *Server) llmPrice(usage LLMUsage) (LLMPrice, bool) {
+	if s.config == nil || s.config.Usage == nil {
+		return LLMPrice{}, false
+	}
+	for _, name := range []string{usage.Model, usage.Provider} {
+		if price, ok := s.config.Usage.LLMPrices[strings.ToLower(name)]; ok && name != "" {
+			return price, true
+		}
+	}
+	return LLMPrice{}, false
+}
+
+// OperationCount is how often a tenant called a tool
+type OperationCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TenantUsage is what one tenant did with the agent and what it cost
+type TenantUsage struct {
+	Tenant       string           `json:"tenant"`
+	Queries      int              `json:"queries"`
+	ToolCalls    int              `json:"tool_calls"`
+	ToolErrors   int              `json:"tool_errors"`
+	Actions      int              `json:"actions"` // calls of tools that change the cluster
+	TopTools     []OperationCount `json:"top_tools"`
+	TopActions   []OperationCount `json:"top_actions"`
+	LLMCalls     int              `json:"llm_calls"`
+	InputTokens  int              `json:"input_tokens"`
+	OutputTokens int              `json:"output_tokens"`
+	LLMCost      float64          `json:"llm_cost_usd"`
+	Unpriced     int              `json:"unpriced_llm_calls"` // LLM calls of models without a configured price
+	LastSeen     time.Time        `json:"last_seen"`
+}
+
+type tenantStats struct {
+	queries      int
+	tools        map[string]int
+	errors       int
+	actions      map[string]int
+	llmCalls     int
+	inputTokens  int
+	outputTokens int
+	cost         float64
+	unpriced     int
+	lastSeen     time.Time
+}
+
+// UsageTracker counts queries, tool calls, actions and LLM spend per tenant
+// since the server started
+type UsageTracker struct {
+	mu      sync.Mutex
+	since   time.Time
+	tenants map[string]*tenantStats
+}
+
+// NewUsageTracker creates an empty usage tracker
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{since: time.Now(), tenants: make(map[string]*tenantStats)}
+}
+
+// tenant returns the stats of a tenant, marking it seen; the caller holds mu
+func (u *UsageTracker) tenant(name string) *tenantStats {
+	stats := u.tenants[name]
+	if stats == nil {
+		stats = &tenantStats{tools: make(map[string]int), actions: make(map[string]int)}
+		u.tenants[name] = stats
+	}
+	stats.lastSeen = time.Now()
+	return stats
+}
+
+func (u *UsageTracker) recordQuery(tenant string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.tenant(tenant).queries++
+}
+
+func (u *UsageTracker) recordTool(tenant, name string, action, failed bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	stats := u.tenant(tenant)
+	stats.tools[name]++
+	if failed {
+		stats.errors++
+	} else if action {
+		stats.actions[name]++
+	}
+}
+
+func (u *UsageTracker) recordLLM(tenant string, usage LLMUsage, price LLMPrice, priced bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	stats := u.tenant(tenant)
+	stats.llmCalls++
+	stats.inputTokens += usage.InputTokens
+	stats.outputTokens += usage.OutputTokens
+	if priced {
+		stats.cost += (float64(usage.InputTokens)*price.Input + float64(usage.OutputTokens)*price.Output) / 1e6
+	} else {
+		stats.unpriced++
+	}
+}
+
+// topOperations returns the most frequent operations, then by name
+func topOperations(counts map[string]int, limit int) []OperationCount {
+	operations := make([]OperationCount, 0, len(counts))
+	for name, count := range counts {
+		operations = append(operations, OperationCount{Name: name, Count: count})
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Count != operations[j].Count {
+			return operations[i].Count > operations[j].Count
+		}
+		return operations[i].Name < operations[j].Name
+	})
+	if limit > 0 && len(operations) > limit {
+		operations = operations[:limit]
+	}
+	return operations
+}
+
+// Tenants returns the usage of every tenant, highest LLM cost then most tool
+// calls first, with up to topN tools and actions each
+func (u *UsageTracker) Tenants(topN int) []TenantUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	usage := make([]TenantUsage, 0, len(u.tenants))
+	for name, stats := range u.tenants {
+		tenant := TenantUsage{
+			Tenant:       name,
+			Queries:      stats.queries,
+			ToolErrors:   stats.errors,
+			TopTools:     topOperations(stats.tools, topN),
+			TopActions:   topOperations(stats.actions, topN),
+			LLMCalls:     stats.llmCalls,
+			InputTokens:  stats.inputTokens,
+			OutputTokens: stats.outputTokens,
+			LLMCost:      stats.cost,
+			Unpriced:     stats.unpriced,
+			LastSeen:     stats.lastSeen,
+		}
+		for _, count := range stats.tools {
+			tenant.ToolCalls += count
+		}
+		for _, count := range stats.actions {
+			tenant.Actions += count
+		}
+		usage = append(usage, tenant)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].LLMCost != usage[j].LLMCost {
+			return usage[i].LLMCost > usage[j].LLMCost
+		}
+		if usage[i].ToolCalls != usage[j].ToolCalls {
+			return usage[i].ToolCalls > usage[j].ToolCalls
+		}
+		return usage[i].Tenant < usage[j].Tenant
+	})
+	return usage
+}
+
+// Since returns when tracking started
+func (u *UsageTracker) Since() time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.since
+}
+
+// withUsage counts every tool call against the caller's tenant; calls of
+// tools without a read-only hint that succeed count as actions
+func (s *Server) withUsage(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.usage == nil {
+			return handler(ctx, request)
+		}
+		result, err := handler(ctx, request)
+		s.toolsMu.RLock()
+		tool, ok := s.toolDefs[name]
+		s.toolsMu.RUnlock()
+		s.usage.recordTool(tenantFrom(ctx), name, !ok || !toolReadOnly(tool), err != nil || (result != nil && result.IsError))
+		return result, err
+	}
+}
+
+// RecordQuery counts a chat query against the caller's tenant
+func (s *Server) RecordQuery(ctx context.Context) {
+	if s.usage != nil {
+		s.usage.recordQuery(tenantFrom(ctx))
+	}
+}
+
+// RecordLLMUsage adds the tokens of an LLM call, and their price when the
+// model or provider has one configured, to the caller's tenant
+func (s *Server) RecordLLMUsage(ctx context.Context, usage LLMUsage) {
+	if s.usage == nil {
+		return
+	}
+	price, priced := s.llmPrice(usage)
+	s.usage.recordLLM(tenantFrom(ctx), usage, price, priced)
+}
+
+// Usage returns the usage per tenant since the server started
+func (s *Server) Usage(topN int) []TenantUsage {
+	if s.usage == nil {
+		return nil
+	}
+	return s.usage.Tenants(topN)
+}
+
+func (s *Server) initUsageTools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("usage_report",
+			mcp.WithDescription("Report per team (by API key) since the server started: chat queries, tool calls and errors, the actions performed on the cluster, the most used tools and the LLM tokens and cost, to inform quota and enablement decisions"),
+			mcp.WithString("tenant", mcp.Description("Only report this team or API key fingerprint")),
+			mcp.WithString("limit", mcp.Description(fmt.Sprintf("Number of top tools and actions to list per team (default %d)", defaultUsageTopN))),
+			mcp.WithTitleAnnotation("Server: Usage Report"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.usageReportHandler)},
+	}
+}
+
+// formatOperations renders counts as "name ×count" pairs
+func formatOperations(operations []OperationCount) string {
+	parts := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		parts = append(parts, fmt.Sprintf("%s ×%d", operation.Name, operation.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *Server) usageReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.usage == nil {
+		return mcp.NewToolResultText("❌ Usage tracking is not enabled on this server"), nil
+	}
+	limit := defaultUsageTopN
+	if value := mcp.ParseString(request, "limit", ""); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ Invalid limit '%s': expected a positive number", value)), nil
+		}
+		limit = parsed
+	}
+	filter := strings.TrimSpace(mcp.ParseString(request, "tenant", ""))
+
+	var tenants []TenantUsage
+	for _, tenant := range s.usage.Tenants(limit) {
+		if filter == "" || strings.EqualFold(tenant.Tenant, filter) {
+			tenants = append(tenants, tenant)
+		}
+	}
+
+	result := "📊 Usage Report\n"
+	result += "===============\n\n"
+	result += fmt.Sprintf("Since %s\n", formatTime(ctx, s.usage.Since()))
+	if len(tenants) == 0 {
+		if filter != "" {
+			return mcp.NewToolResultText(result + fmt.Sprintf("\nNo usage recorded for %s", filter)), nil
+		}
+		return mcp.NewToolResultText(result + "\nNo usage recorded yet"), nil
+	}
+
+	var total float64
+	unpriced := false
+	for _, tenant := range tenants {
+		total += tenant.LLMCost
+		unpriced = unpriced || tenant.Unpriced > 0
+		result += fmt.Sprintf("\n👥 %s (last seen %s)\n", tenant.Tenant, formatTimeWithAge(ctx, tenant.LastSeen))
+		result += fmt.Sprintf("   Queries: %d | Tool calls: %d (%d failed) | Actions: %d\n", tenant.Queries, tenant.ToolCalls, tenant.ToolErrors, tenant.Actions)
+		if tenant.LLMCalls > 0 {
+			result += fmt.Sprintf("   LLM: %d calls, %d input + %d output tokens, $%.2f", tenant.LLMCalls, tenant.InputTokens, tenant.OutputTokens, tenant.LLMCost)
+			if tenant.Unpriced > 0 {
+				result += fmt.Sprintf(" (%d calls unpriced)", tenant.Unpriced)
+			}
+			result += "\n"
+		}
+		if len(tenant.TopTools) > 0 {
+			result += fmt.Sprintf("   Top tools: %s\n", formatOperations(tenant.TopTools))
+		}
+		if len(tenant.TopActions) > 0 {
+			result += fmt.Sprintf("   Top actions: %s\n", formatOperations(tenant.TopActions))
+		}
+	}
+
+	result += fmt.Sprintf("\n💰 LLM cost: $%.2f across %d teams", total, len(tenants))
+	if unpriced {
+		result += "\n💡 Set usage.llm-prices for the models or providers of unpriced calls to include them in the cost"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// UsageReportHandler is a public wrapper for usageReportHandler
+func (s *Server) UsageReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.usageReportHandler(ctx, request)
+}
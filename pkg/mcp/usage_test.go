@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithUsage(t *testing.T) {
+	s := newPolicyTestServer(false)
+	s.usage = NewUsageTracker()
+	s.config.Usage = &UsageConfig{
+		TeamAPIKeys: map[string][]string{"payments": {"pay-key-1", "pay-key-2"}},
+		LLMPrices:   map[string]LLMPrice{"gpt-4": {Input: 30, Output: 60}},
+	}
+
+	if tenant := s.TenantForKey("pay-key-2"); tenant != "payments" {
+		t.Errorf("TenantForKey(pay-key-2) = %s", tenant)
+	}
+	stranger := s.TenantForKey("someone-else")
+	if !strings.HasPrefix(stranger, "key-") || strings.Contains(stranger, "someone") || stranger != s.TenantForKey("someone-else") {
+		t.Errorf("TenantForKey(someone-else) = %s, expected a stable fingerprint", stranger)
+	}
+
+	for name, handler := range s.tools {
+		s.tools[name] = s.withUsage(name, handler)
+	}
+	payments := WithTenant(context.Background(), "payments")
+	for _, name := range []string{"list_pods", "list_pods", "delete_resource"} {
+		if _, err := s.CallTool(payments, mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	failing := s.withUsage("list_pods", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("forbidden")
+	})
+	failing(payments, mcp.CallToolRequest{})
+	s.CallTool(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "list_pods"}})
+
+	s.RecordQuery(payments)
+	s.RecordLLMUsage(payments, LLMUsage{Provider: "openai", Model: "GPT-4", InputTokens: 10000, OutputTokens: 1000})
+	s.RecordLLMUsage(payments, LLMUsage{Provider: "ollama", Model: "llama3.1", InputTokens: 500, OutputTokens: 50})
+
+	usage := s.Usage(5)
+	if len(usage) != 2 || usage[0].Tenant != "payments" || usage[1].Tenant != unattributedTenant {
+		t.Fatalf("Usage() = %+v", usage)
+	}
+	got := usage[0]
+	if got.Queries != 1 || got.ToolCalls != 4 || got.ToolErrors != 1 || got.Actions != 1 || got.LLMCalls != 2 || got.InputTokens != 10500 || got.Unpriced != 1 {
+		t.Errorf("payments usage = %+v", got)
+	}
+	if math.Abs(got.LLMCost-0.36) > 1e-9 {
+		t.Errorf("payments LLM cost = %f, expected 0.36", got.LLMCost)
+	}
+	if !reflect.DeepEqual(got.TopTools, []OperationCount{{"list_pods", 3}, {"delete_resource", 1}}) || !reflect.DeepEqual(got.TopActions, []OperationCount{{"delete_resource", 1}}) {
+		t.Errorf("top tools %+v, top actions %+v", got.TopTools, got.TopActions)
+	}
+
+	text := resultText(mustCall(t, s.usageReportHandler, ruleRequest(map[string]interface{}{"tenant": "PAYMENTS"})))
+	for _, want := range []string{
+		"👥 payments",
+		"Queries: 1 | Tool calls: 4 (1 failed) | Actions: 1",
+		"LLM: 2 calls, 10500 input + 1050 output tokens, $0.36 (1 calls unpriced)",
+		"Top tools: list_pods ×3, delete_resource ×1",
+		"Top actions: delete_resource ×1",
+		"💡 Set usage.llm-prices",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("usage_report output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, unattributedTenant) {
+		t.Errorf("usage_report tenant=payments listed other tenants:\n%s", text)
+	}
+}
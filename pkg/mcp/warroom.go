@@ -0,0 +1,842 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+const (
+	defaultWarRoomInterval = time.Minute
+	minWarRoomInterval     = 15 * time.Second
+	// maxWarRoomInterval keeps a room refreshing, and so active, well
+	// within the session idle timeout
+	maxWarRoomInterval     = 10 * time.Minute
+	defaultWarRoomDuration = 4 * time.Hour
+	maxWarRoomDuration     = 24 * time.Hour
+	warRoomRefreshTimeout  = 30 * time.Second
+
+	// warRoomEventLookback is how far back the first refresh lists events
+	warRoomEventLookback = 15 * time.Minute
+	// maxWarRoomEvents bounds the new warning events listed per refresh
+	maxWarRoomEvents = 10
+	// warRoomHistory bounds the updates a room keeps for war_room_status
+	// and late subscribers
+	warRoomHistory = 30
+	// warRoomSubscriberBuffer is how many updates a slow subscriber may
+	// fall behind before updates are dropped for it
+	warRoomSubscriberBuffer = 8
+
+	// warRoomErrorRateThreshold marks a service degraded
+	warRoomErrorRateThreshold = 0.05
+	// warRoomErrorRateStep is the change in error rate reported as a change
+	warRoomErrorRateStep = 0.01
+
+	// defaultErrorRateQuery is the share of 5xx responses the OpenShift
+	// router served for the service's routes; the router metrics live in
+	// openshift-ingress, so the query needs the cluster-wide querier
+	defaultErrorRateQuery = `(sum(rate(haproxy_server_http_responses_total{exported_namespace="$namespace",route=~"$service.*",code="5xx"}[5m])) or vector(0)) / sum(rate(haproxy_server_http_responses_total{exported_namespace="$namespace",route=~"$service.*"}[5m]))`
+
+	// Statuses of a war room update
+	WarRoomStable   = "stable"
+	WarRoomDegraded = "degraded"
+	WarRoomClosed   = "closed"
+)
+
+// warRoomAlertLabels are the alert labels that point at a service's workload
+var warRoomAlertLabels = []string{"pod", "deployment", "replicaset", "service", "container", "route", "job_name"}
+
+// WarRoomUpdate is one refresh of a war room, sent to the MCP client that
+// opened it and to subscribers until the room closes
+type WarRoomUpdate struct {
+	Room    string    `json:"room"`
+	Seq     int       `json:"seq"`
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status"` // stable, degraded, or closed on the last update
+	Changes []string  `json:"changes,omitempty"`
+	Summary string    `json:"summary"`
+}
+
+// warRoomSignals are the live signals of a service at one refresh
+type warRoomSignals struct {
+	Rollout     string
+	Degraded    bool // the rollout is stuck or its pods are failing
+	Pods        string
+	Restarts    int32
+	Alerts      map[string]string // firing alert lines by alert and resource
+	Events      []string          // warning events since the last refresh
+	ErrorRate   *float64
+	Unavailable []string // signals that could not be read, with why
+}
+
+// status rates the signals
+func (sig warRoomSignals) status() string {
+	if sig.Degraded || len(sig.Alerts) > 0 || (sig.ErrorRate != nil && *sig.ErrorRate >= warRoomErrorRateThreshold) {
+		return WarRoomDegraded
+	}
+	return WarRoomStable
+}
+
+// warRoom aggregates the signals of a service until it is closed, expires
+// or is reaped
+type warRoom struct {
+	ID             string
+	Namespace      string
+	Service        string
+	Interval       time.Duration
+	Opened         time.Time
+	Expires        time.Time
+	errorRateQuery string // custom query, or "" for the router error rate
+
+	tracked *trackedSession
+	notify  func(WarRoomUpdate) // pushes updates to the MCP client, if any
+
+	mu          sync.Mutex
+	last        *warRoomSignals
+	eventsSince time.Time
+	seq         int
+	updates     []WarRoomUpdate
+	subscribers map[int]chan WarRoomUpdate
+	nextSub     int
+	finishing   bool // the last update is being sent
+	closed      bool // the last update was sent
+}
+
+// latest returns the room's last update
+func (r *warRoom) latest() (WarRoomUpdate, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.updates) == 0 {
+		return WarRoomUpdate{}, false
+	}
+	return r.updates[len(r.updates)-1], true
+}
+
+// history returns the updates the room kept, oldest first
+func (r *warRoom) history() []WarRoomUpdate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]WarRoomUpdate(nil), r.updates...)
+}
+
+// publish records an update and fans it out. Subscribers that fall behind
+// miss updates rather than stall the room.
+func (r *warRoom) publish(update WarRoomUpdate) WarRoomUpdate {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return update
+	}
+	r.seq++
+	update.Room, update.Seq = r.ID, r.seq
+	r.updates = append(r.updates, update)
+	if len(r.updates) > warRoomHistory {
+		r.updates = r.updates[len(r.updates)-warRoomHistory:]
+	}
+	for id, ch := range r.subscribers {
+		select {
+		case ch <- update:
+		default:
+			logrus.Debugf("War room %s subscriber %d is behind, dropped update %d", r.ID, id, update.Seq)
+		}
+	}
+	if update.Status == WarRoomClosed {
+		r.closed = true
+		for id, ch := range r.subscribers {
+			close(ch)
+			delete(r.subscribers, id)
+		}
+	}
+	notify := r.notify
+	r.mu.Unlock()
+
+	if notify != nil {
+		notify(update)
+	}
+	return update
+}
+
+// subscribe streams the room's updates, starting with the latest
+func (r *warRoom) subscribe() (<-chan WarRoomUpdate, func(), bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, nil, false
+	}
+	if r.subscribers == nil {
+		r.subscribers = make(map[int]chan WarRoomUpdate)
+	}
+	r.nextSub++
+	id := r.nextSub
+	ch := make(chan WarRoomUpdate, warRoomSubscriberBuffer)
+	if len(r.updates) > 0 {
+		ch <- r.updates[len(r.updates)-1]
+	}
+	r.subscribers[id] = ch
+	return ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if ch, ok := r.subscribers[id]; ok {
+			close(ch)
+			delete(r.subscribers, id)
+		}
+	}, true
+}
+
+// warRooms tracks the open war rooms by ID. The zero value is ready to use.
+type warRooms struct {
+	mu    sync.Mutex
+	rooms map[string]*warRoom
+}
+
+func (w *warRooms) add(room *warRoom) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.rooms == nil {
+		w.rooms = make(map[string]*warRoom)
+	}
+	w.rooms[room.ID] = room
+}
+
+func (w *warRooms) get(id string) (*warRoom, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	room, ok := w.rooms[id]
+	return room, ok
+}
+
+func (w *warRooms) remove(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.rooms, id)
+}
+
+// find returns the open room of a service
+func (w *warRooms) find(namespace, service string) (*warRoom, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, room := range w.rooms {
+		if room.Namespace == namespace && room.Service == service {
+			return room, true
+		}
+	}
+	return nil, false
+}
+
+// list returns the open rooms, oldest first
+func (w *warRooms) list() []*warRoom {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rooms := make([]*warRoom, 0, len(w.rooms))
+	for _, room := range w.rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].Opened.Before(rooms[j].Opened) })
+	return rooms
+}
+
+// SubscribeWarRoom streams the updates of an open war room, starting with
+// its latest. The channel is closed after the room's last update; calling
+// the returned func stops the subscription early.
+func (s *Server) SubscribeWarRoom(id string) (<-chan WarRoomUpdate, func(), bool) {
+	room, ok := s.warRooms.get(id)
+	if !ok {
+		return nil, nil, false
+	}
+	return room.subscribe()
+}
+
+// clientNotifier pushes war room updates to the MCP client of a tool call as
+// log messages, which outlive the call
+func clientNotifier(ctx context.Context) func(WarRoomUpdate) {
+	mcpServer := server.ServerFromContext(ctx)
+	session := server.ClientSessionFromContext(ctx)
+	if mcpServer == nil || session == nil {
+		return nil
+	}
+	clientCtx := mcpServer.WithContext(context.Background(), session)
+	return func(update WarRoomUpdate) {
+		level := "info"
+		if update.Status == WarRoomDegraded {
+			level = "warning"
+		}
+		_ = mcpServer.SendNotificationToClient(clientCtx, "notifications/message", map[string]any{
+			"level":  level,
+			"logger": "war-room",
+			"data":   update,
+		})
+	}
+}
+
+// runWarRoom refreshes a room every interval until it is closed, reaped or
+// expires
+func (s *Server) runWarRoom(ctx context.Context, room *warRoom) {
+	ticker := time.NewTicker(room.Interval)
+	defer ticker.Stop()
+	expiry := time.NewTimer(time.Until(room.Expires))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.finishWarRoom(room, "closed")
+			return
+		case <-expiry.C:
+			s.finishWarRoom(room, fmt.Sprintf("expired after %s", room.Expires.Sub(room.Opened).Round(time.Minute)))
+			return
+		case <-ticker.C:
+			s.refreshWarRoom(ctx, room)
+		}
+	}
+}
+
+// refreshWarRoom collects the signals once and publishes what changed
+func (s *Server) refreshWarRoom(ctx context.Context, room *warRoom) WarRoomUpdate {
+	room.tracked.touch()
+	refreshCtx, cancel := context.WithTimeout(ctx, warRoomRefreshTimeout)
+	defer cancel()
+
+	now := time.Now()
+	room.mu.Lock()
+	since := room.eventsSince
+	previous := room.last
+	room.mu.Unlock()
+
+	signals := s.collectWarRoomSignals(refreshCtx, room, since)
+	changes := warRoomChanges(previous, signals)
+
+	room.mu.Lock()
+	room.last = &signals
+	room.eventsSince = now
+	room.mu.Unlock()
+
+	return room.publish(WarRoomUpdate{
+		Time:    now,
+		Status:  signals.status(),
+		Changes: changes,
+		Summary: formatWarRoom(ctx, room, now, signals, changes, previous == nil),
+	})
+}
+
+// finishWarRoom sends a room's last update and forgets it; finishing again
+// does nothing
+func (s *Server) finishWarRoom(room *warRoom, reason string) (WarRoomUpdate, bool) {
+	room.mu.Lock()
+	finishing := room.finishing
+	room.finishing = true
+	room.mu.Unlock()
+	if finishing {
+		return WarRoomUpdate{}, false
+	}
+	s.warRooms.remove(room.ID)
+	room.tracked.Close()
+
+	now := time.Now()
+	summary := fmt.Sprintf("🏁 War room %s for %s/%s %s\n", room.ID, room.Namespace, room.Service, reason)
+	summary += fmt.Sprintf("Open for %s", now.Sub(room.Opened).Round(time.Second))
+	if latest, ok := room.latest(); ok {
+		summary += fmt.Sprintf(", last status %s", latest.Status)
+	}
+	logrus.Infof("War room %s for %s/%s %s", room.ID, room.Namespace, room.Service, reason)
+	return room.publish(WarRoomUpdate{Time: now, Status: WarRoomClosed, Summary: summary}), true
+}
+
+// collectWarRoomSignals reads the rollout, pods, alerts, warning events and
+// error rate of a service. A signal that cannot be read is reported
+// unavailable; the others still are.
+func (s *Server) collectWarRoomSignals(ctx context.Context, room *warRoom, eventsSince time.Time) warRoomSignals {
+	signals := warRoomSignals{Alerts: make(map[string]string)}
+	unavailable := func(signal string, err error) {
+		signals.Unavailable = append(signals.Unavailable, fmt.Sprintf("%s: %v", signal, err))
+	}
+
+	if pods, err := s.warRoomWorkload(ctx, room, &signals); err != nil {
+		unavailable("rollout", err)
+	} else {
+		ready, restarts := 0, int32(0)
+		for _, pod := range pods {
+			if podReady(&pod) {
+				ready++
+			}
+			for _, status := range pod.Status.ContainerStatuses {
+				restarts += status.RestartCount
+			}
+		}
+		signals.Pods = fmt.Sprintf("%d/%d ready, %d restarts", ready, len(pods), restarts)
+		signals.Restarts = restarts
+	}
+
+	if events, err := s.warRoomEvents(ctx, room, eventsSince); err != nil {
+		unavailable("events", err)
+	} else {
+		signals.Events = events
+	}
+
+	if endpoint, err := s.discoverAlertmanagerEndpoint(ctx, room.Namespace); err != nil {
+		unavailable("alerts", err)
+	} else if alerts, err := s.activeAlerts(ctx, endpoint, room.Namespace, false); err != nil {
+		unavailable("alerts", err)
+	} else {
+		for _, alert := range alerts {
+			if alert.State == diagnostics.AlertFiring && alertAboutService(alert, room.Namespace, room.Service) {
+				signals.Alerts[alert.Name+" "+alertResource(alert.Labels)] = strings.TrimSuffix(formatAlert(ctx, alert), "\n")
+			}
+		}
+	}
+
+	if rate, err := s.warRoomErrorRate(ctx, room); err != nil {
+		unavailable("error rate", err)
+	} else {
+		signals.ErrorRate = rate
+	}
+	return signals
+}
+
+// warRoomWorkload reads the rollout of the service's deployment, or the
+// pods behind a Service of that name when there is no deployment
+func (s *Server) warRoomWorkload(ctx context.Context, room *warRoom, signals *warRoomSignals) ([]corev1.Pod, error) {
+	deployment, err := s.k8sClient.AppsV1().Deployments(room.Namespace).Get(ctx, room.Service, metav1.GetOptions{})
+	if err == nil {
+		pods, err := s.deploymentPods(ctx, deployment)
+		if err != nil {
+			return nil, err
+		}
+		signals.Rollout, signals.Degraded = warRoomRollout(deployment, pods)
+		return pods, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	service, err := s.k8sClient.CoreV1().Services(room.Namespace).Get(ctx, room.Service, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("no deployment or service named %s", room.Service)
+		}
+		return nil, err
+	}
+	signals.Rollout = "no deployment of this name; pods behind the service"
+	if len(service.Spec.Selector) == 0 {
+		return nil, nil
+	}
+	pods, err := s.k8sClient.CoreV1().Pods(room.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// warRoomRollout describes a deployment's rollout and whether it is
+// stuck or failing
+func warRoomRollout(deployment *appsv1.Deployment, pods []corev1.Pod) (string, bool) {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return fmt.Sprintf("stuck: progress deadline exceeded (%s)", condition.Message), true
+		}
+	}
+	if failure := rolloutFailure(pods, nil); failure != "" {
+		return "failing: " + failure, true
+	}
+	if pending := rolloutPending(deployment, deployment.Generation); pending != "" {
+		return fmt.Sprintf("in progress: %s", pending), deployment.Status.AvailableReplicas < replicas
+	}
+	return fmt.Sprintf("rolled out, %d/%d available", deployment.Status.AvailableReplicas, replicas), false
+}
+
+// warRoomEvents lists the warning events about the service's objects seen
+// since the last refresh, oldest first
+func (s *Server) warRoomEvents(ctx context.Context, room *warRoom, since time.Time) ([]string, error) {
+	events, err := s.k8sClient.CoreV1().Events(room.Namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + corev1.EventTypeWarning})
+	if err != nil {
+		return nil, err
+	}
+	var recent []corev1.Event
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning || !namesService(event.InvolvedObject.Name, room.Service) {
+			continue
+		}
+		if !eventLastSeen(event).After(since) {
+			continue
+		}
+		recent = append(recent, event)
+	}
+	sort.SliceStable(recent, func(i, j int) bool { return eventLastSeen(recent[i]).Before(eventLastSeen(recent[j])) })
+
+	lines := make([]string, 0, len(recent))
+	for _, event := range recent {
+		line := fmt.Sprintf("• %s %s %s/%s: %s", formatTime(ctx, eventLastSeen(event)), event.Reason,
+			strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, strings.TrimSpace(event.Message))
+		if event.Count > 1 {
+			line += fmt.Sprintf(" (x%d)", event.Count)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// eventLastSeen returns when an event last happened
+func eventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// namesService reports whether an object name is the service's or one of
+// its generated objects, e.g. a ReplicaSet or pod of its deployment
+func namesService(name, service string) bool {
+	return name == service || strings.HasPrefix(name, service+"-")
+}
+
+// alertAboutService reports whether an alert points at the service's
+// workload in its namespace
+func alertAboutService(alert diagnostics.Alert, namespace, service string) bool {
+	if alert.Labels["namespace"] != namespace {
+		return false
+	}
+	for _, label := range warRoomAlertLabels {
+		if value := alert.Labels[label]; value != "" && namesService(value, service) {
+			return true
+		}
+	}
+	return false
+}
+
+// warRoomErrorRate runs the room's error rate query; nil means the query
+// returned no data, e.g. the service has no route
+func (s *Server) warRoomErrorRate(ctx context.Context, room *warRoom) (*float64, error) {
+	query, scope := room.errorRateQuery, room.Namespace
+	if query == "" {
+		query, scope = defaultErrorRateQuery, ""
+	}
+	query = strings.NewReplacer("$namespace", room.Namespace, "$service", room.Service).Replace(query)
+
+	endpoint, err := s.discoverMetricsEndpoint(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.queryMetrics(ctx, endpoint, query, scope)
+	if err != nil {
+		return nil, err
+	}
+	values, err := promVectorValues(response, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	rate := 0.0
+	for _, value := range values {
+		rate += value
+	}
+	return &rate, nil
+}
+
+// warRoomChanges lists what changed between two refreshes
+func warRoomChanges(previous *warRoomSignals, current warRoomSignals) []string {
+	if previous == nil {
+		return nil
+	}
+	var changes []string
+	if current.Rollout != previous.Rollout && current.Rollout != "" {
+		changes = append(changes, fmt.Sprintf("🚀 Rollout: %s → %s", valueOrNone(previous.Rollout), current.Rollout))
+	}
+	if current.Restarts > previous.Restarts {
+		changes = append(changes, fmt.Sprintf("🔁 %d new container restart(s)", current.Restarts-previous.Restarts))
+	}
+	for _, key := range sortedKeys(current.Alerts) {
+		if _, ok := previous.Alerts[key]; !ok {
+			changes = append(changes, "🔥 New alert: "+key)
+		}
+	}
+	for _, key := range sortedKeys(previous.Alerts) {
+		if _, ok := current.Alerts[key]; !ok {
+			changes = append(changes, "✅ Alert resolved: "+key)
+		}
+	}
+	switch {
+	case current.ErrorRate != nil && previous.ErrorRate == nil:
+		changes = append(changes, fmt.Sprintf("📈 Error rate: %s", formatErrorRate(current.ErrorRate)))
+	case current.ErrorRate != nil && previous.ErrorRate != nil:
+		if delta := *current.ErrorRate - *previous.ErrorRate; delta >= warRoomErrorRateStep || delta <= -warRoomErrorRateStep {
+			changes = append(changes, fmt.Sprintf("📈 Error rate: %s → %s", formatErrorRate(previous.ErrorRate), formatErrorRate(current.ErrorRate)))
+		}
+	}
+	if len(current.Events) > 0 {
+		changes = append(changes, fmt.Sprintf("⚠️  %d new warning event(s)", len(current.Events)))
+	}
+	return changes
+}
+
+func formatErrorRate(rate *float64) string {
+	if rate == nil {
+		return "no data"
+	}
+	return strconv.FormatFloat(*rate*100, 'f', 1, 64) + "%"
+}
+
+// formatWarRoom renders the summary of one refresh
+func formatWarRoom(ctx context.Context, room *warRoom, now time.Time, signals warRoomSignals, changes []string, first bool) string {
+	title := fmt.Sprintf("🚨 War Room %s: %s/%s", room.ID, room.Namespace, room.Service)
+	result := title + "\n" + strings.Repeat("=", len([]rune(title))) + "\n\n"
+	result += fmt.Sprintf("Updated: %s (every %s, open until %s)\n", formatTime(ctx, now), room.Interval, formatTime(ctx, room.Expires))
+	if signals.status() == WarRoomDegraded {
+		result += "Status: 🔴 degraded\n"
+	} else {
+		result += "Status: 🟢 stable\n"
+	}
+
+	switch {
+	case first:
+	case len(changes) == 0:
+		result += "\nNo changes since the last refresh\n"
+	default:
+		result += "\n🔄 Changes since the last refresh:\n"
+		for _, change := range changes {
+			result += "• " + change + "\n"
+		}
+	}
+
+	result += "\n"
+	if signals.Rollout != "" {
+		result += fmt.Sprintf("🚀 Rollout: %s\n", signals.Rollout)
+	}
+	if signals.Pods != "" {
+		result += fmt.Sprintf("📦 Pods: %s\n", signals.Pods)
+	}
+	if !hasUnavailable(signals, "error rate") {
+		result += fmt.Sprintf("📈 Error rate: %s\n", formatErrorRate(signals.ErrorRate))
+	}
+	if !hasUnavailable(signals, "alerts") {
+		if len(signals.Alerts) == 0 {
+			result += "🔥 Firing alerts: none\n"
+		} else {
+			result += fmt.Sprintf("🔥 Firing alerts (%d):\n", len(signals.Alerts))
+			for _, key := range sortedKeys(signals.Alerts) {
+				result += signals.Alerts[key] + "\n"
+			}
+		}
+	}
+	if len(signals.Events) > 0 {
+		label := "since the last refresh"
+		if first {
+			label = fmt.Sprintf("in the last %s", warRoomEventLookback)
+		}
+		result += fmt.Sprintf("⚠️  Warning events %s (%d):\n", label, len(signals.Events))
+		events := signals.Events
+		if len(events) > maxWarRoomEvents {
+			events = events[len(events)-maxWarRoomEvents:]
+			result += fmt.Sprintf("  ... %d earlier\n", len(signals.Events)-maxWarRoomEvents)
+		}
+		for _, event := range events {
+			result += event + "\n"
+		}
+	}
+	if len(signals.Unavailable) > 0 {
+		result += "\n❓ Unavailable signals:\n"
+		for _, signal := range signals.Unavailable {
+			result += "• " + signal + "\n"
+		}
+	}
+	return strings.TrimRight(result, "\n")
+}
+
+// hasUnavailable reports whether a signal could not be read
+func hasUnavailable(signals warRoomSignals, signal string) bool {
+	for _, text := range signals.Unavailable {
+		if strings.HasPrefix(text, signal+": ") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) initWarRoom() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("open_war_room",
+			mcp.WithDescription("Open an incident war room for an affected service: its rollout status, pods, firing alerts, warning events and error rate are aggregated into a summary refreshed periodically and pushed to this client, and to chat clients on /api/v1/war-rooms/{room_id}/stream, until the room is closed or expires"),
+			mcp.WithString("namespace", mcp.Required(), mcp.Description("Namespace of the service")),
+			mcp.WithString("service", mcp.Required(), mcp.Description("Name of the affected deployment, or of a Service when there is no deployment of that name")),
+			mcp.WithNumber("interval_seconds", mcp.Description("How often the summary is refreshed (default 60, 15 to 600)")),
+			mcp.WithNumber("duration_minutes", mcp.Description("Close the room after this long (default 240, at most 1440)")),
+			mcp.WithString("error_rate_query", mcp.Description("PromQL for the error rate as a ratio, run in the namespace; $namespace and $service are replaced (default: share of 5xx responses of the service's routes at the router)")),
+			mcp.WithTitleAnnotation("Incident: Open War Room"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.openWarRoomHandler)},
+		{Tool: mcp.NewTool("war_room_status",
+			mcp.WithDescription("Show the latest summary and change log of a war room, or list the open war rooms"),
+			mcp.WithString("room_id", mcp.Description("War room to show; omit to list the open rooms")),
+			mcp.WithTitleAnnotation("Incident: War Room Status"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.warRoomStatusHandler)},
+		{Tool: mcp.NewTool("close_war_room",
+			mcp.WithDescription("Close a war room: its refreshes stop and subscribers get a last update"),
+			mcp.WithString("room_id", mcp.Required(), mcp.Description("War room to close")),
+			mcp.WithTitleAnnotation("Incident: Close War Room"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.closeWarRoomHandler)},
+	}
+}
+
+func (s *Server) openWarRoomHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace := strings.TrimSpace(mcp.ParseString(request, "namespace", ""))
+	service := strings.TrimSpace(mcp.ParseString(request, "service", ""))
+	if namespace == "" || service == "" {
+		return mcp.NewToolResultText("❌ namespace and service are required"), nil
+	}
+	interval := time.Duration(mcp.ParseInt(request, "interval_seconds", int(defaultWarRoomInterval.Seconds()))) * time.Second
+	if interval < minWarRoomInterval || interval > maxWarRoomInterval {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ interval_seconds must be between %d and %d", int(minWarRoomInterval.Seconds()), int(maxWarRoomInterval.Seconds()))), nil
+	}
+	duration := time.Duration(mcp.ParseInt(request, "duration_minutes", int(defaultWarRoomDuration.Minutes()))) * time.Minute
+	if duration <= 0 || duration > maxWarRoomDuration {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ duration_minutes must be between 1 and %d", int(maxWarRoomDuration.Minutes()))), nil
+	}
+
+	if room, ok := s.warRooms.find(namespace, service); ok {
+		result := fmt.Sprintf("ℹ️  War room %s is already open for %s/%s\n\n", room.ID, namespace, service)
+		if latest, ok := room.latest(); ok {
+			result += latest.Summary
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+	if _, err := s.k8sClient.AppsV1().Deployments(namespace).Get(ctx, service, metav1.GetOptions{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return toolError(ctx, fmt.Sprintf("Failed to get deployment %s/%s", namespace, service), err), nil
+		}
+		if _, err := s.k8sClient.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return mcp.NewToolResultText(fmt.Sprintf("❌ No deployment or service named %s in namespace %s", service, namespace)), nil
+			}
+			return toolError(ctx, fmt.Sprintf("Failed to get service %s/%s", namespace, service), err), nil
+		}
+	}
+
+	// The room outlives this call; it keeps the caller's time preferences
+	roomCtx, cancel := context.WithCancel(WithTimePreferences(context.Background(), s.TimePreferences(ctx)))
+	now := time.Now()
+	room := &warRoom{
+		Namespace:      namespace,
+		Service:        service,
+		Interval:       interval,
+		Opened:         now,
+		Expires:        now.Add(duration),
+		errorRateQuery: strings.TrimSpace(mcp.ParseString(request, "error_rate_query", "")),
+		eventsSince:    now.Add(-warRoomEventLookback),
+	}
+	room.tracked = s.sessions.open(SessionWarRoom, namespace+"/"+service, cancel)
+	room.ID = room.tracked.ID
+	s.warRooms.add(room)
+
+	update := s.refreshWarRoom(roomCtx, room)
+	room.notify = clientNotifier(ctx)
+	go s.runWarRoom(roomCtx, room)
+	logrus.Infof("Opened war room %s for %s/%s, refreshing every %s", room.ID, namespace, service, interval)
+
+	result := update.Summary + "\n\n"
+	result += fmt.Sprintf("💡 Room %s refreshes every %s until %s.", room.ID, interval, formatTime(ctx, room.Expires))
+	if room.notify != nil {
+		result += " Updates are pushed to this client as log messages."
+	}
+	result += fmt.Sprintf(" Chat clients stream them from /api/v1/war-rooms/%s/stream; close the room with close_war_room.", room.ID)
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) warRoomStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := strings.TrimSpace(mcp.ParseString(request, "room_id", ""))
+	if id == "" {
+		rooms := s.warRooms.list()
+		if len(rooms) == 0 {
+			return mcp.NewToolResultText("No war rooms are open\n\n💡 Open one for an affected service with open_war_room"), nil
+		}
+		result := fmt.Sprintf("🚨 Open War Rooms (%d)\n\n", len(rooms))
+		for _, room := range rooms {
+			status := "refreshing"
+			if latest, ok := room.latest(); ok {
+				status = latest.Status
+			}
+			result += fmt.Sprintf("• %s %s/%s: %s, every %s, open since %s until %s\n", room.ID, room.Namespace, room.Service,
+				status, room.Interval, formatTime(ctx, room.Opened), formatTime(ctx, room.Expires))
+		}
+		return mcp.NewToolResultText(strings.TrimRight(result, "\n")), nil
+	}
+
+	room, ok := s.warRooms.get(id)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ War room %s is not open; see war_room_status without room_id", id)), nil
+	}
+	history := room.history()
+	if len(history) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("War room %s has not refreshed yet", id)), nil
+	}
+	result := history[len(history)-1].Summary
+	var log []string
+	for _, update := range history {
+		for _, change := range update.Changes {
+			log = append(log, fmt.Sprintf("• %s %s", formatTime(ctx, update.Time), change))
+		}
+	}
+	if len(log) > 0 {
+		result += fmt.Sprintf("\n\n📜 Change log (last %d refreshes):\n%s", len(history), strings.Join(log, "\n"))
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *Server) closeWarRoomHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := strings.TrimSpace(mcp.ParseString(request, "room_id", ""))
+	if id == "" {
+		return mcp.NewToolResultText("❌ room_id is required"), nil
+	}
+	room, ok := s.warRooms.get(id)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ War room %s is not open; see war_room_status without room_id", id)), nil
+	}
+	update, ok := s.finishWarRoom(room, "closed")
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ War room %s is already closed", id)), nil
+	}
+	return mcp.NewToolResultText(update.Summary), nil
+}
+
+// OpenWarRoomHandler is a public wrapper for openWarRoomHandler
+func (s *Server) OpenWarRoomHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.openWarRoomHandler(ctx, request)
+}
+
+// WarRoomStatusHandler is a public wrapper for warRoomStatusHandler
+func (s *Server) WarRoomStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.warRoomStatusHandler(ctx, request)
+}
+
+// CloseWarRoomHandler is a public wrapper for closeWarRoomHandler
+func (s *Server) CloseWarRoomHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.closeWarRoomHandler(ctx, request)
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWarRoomChanges(t *testing.T) {
+	rate := func(value float64) *float64 { return &value }
+	previous := &warRoomSignals{
+		Rollout:   "rolled out, 3/3 available",
+		Restarts:  2,
+		Alerts:    map[string]string{"KubePodCrashLooping shop/api-1": "", "TargetDown shop/api": ""},
+		ErrorRate: rate(0.02),
+	}
+	current := warRoomSignals{
+		Rollout:   "in progress: 1 of 3 replicas updated",
+		Restarts:  5,
+		Alerts:    map[string]string{"KubePodCrashLooping shop/api-1": "", "KubeDeploymentReplicasMismatch shop/api": ""},
+		Events:    []string{"• BackOff", "• Unhealthy"},
+		ErrorRate: rate(0.084),
+	}
+	expected := []string{
+		"🚀 Rollout: rolled out, 3/3 available → in progress: 1 of 3 replicas updated",
+		"🔁 3 new container restart(s)",
+		"🔥 New alert: KubeDeploymentReplicasMismatch shop/api",
+		"✅ Alert resolved: TargetDown shop/api",
+		"📈 Error rate: 2.0% → 8.4%",
+		"⚠️  2 new warning event(s)",
+	}
+	if changes := warRoomChanges(previous, current); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("warRoomChanges() = %q, expected %q", changes, expected)
+	}
+
+	// Small moves of the error rate are not changes
+	if changes := warRoomChanges(&warRoomSignals{ErrorRate: rate(0.02)}, warRoomSignals{ErrorRate: rate(0.025)}); len(changes) != 0 {
+		t.Errorf("warRoomChanges() = %q, expected no changes", changes)
+	}
+	if changes := warRoomChanges(nil, current); changes != nil {
+		t.Errorf("warRoomChanges() of the first refresh = %q", changes)
+	}
+}
+
+func TestWarRoom(t *testing.T) {
+	var mu sync.Mutex
+	alerts := `[{"labels":{"alertname":"KubePodCrashLooping","namespace":"shop","pod":"api-6d4f-x1","severity":"warning"},"startsAt":"2024-05-01T10:02:00Z","status":{"state":"active"}},
+		{"labels":{"alertname":"KubePodCrashLooping","namespace":"shop","pod":"web-1","severity":"warning"},"startsAt":"2024-05-01T10:02:00Z","status":{"state":"active"}}]`
+	monitoring := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/alerts":
+			mu.Lock()
+			defer mu.Unlock()
+			w.Write([]byte(alerts))
+		case "/api/v1/query":
+			if query := r.URL.Query().Get("query"); !strings.Contains(query, `exported_namespace="shop",route=~"api.*"`) {
+				t.Errorf("error rate query = %s", query)
+			}
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1714557720,"0.125"]}]}}`))
+		default:
+			t.Errorf("monitoring got %s", r.URL)
+		}
+	}))
+	defer monitoring.Close()
+
+	status := waitingStatus("CrashLoopBackOff")
+	status.RestartCount = 4
+	objects := remediationObjects(corev1.Container{Name: "api", Image: "quay.io/shop/api:v1.4.0"}, status, 0)
+	objects = append(objects, &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "api-6d4f-x1.1", Namespace: "shop"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-6d4f-x1", Namespace: "shop"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Count:          3,
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Minute)),
+	})
+	s := &Server{
+		config:    &Config{Monitoring: &MonitoringConfig{QuerierURL: monitoring.URL, AlertmanagerTenancyURL: monitoring.URL}},
+		k8sClient: kubefake.NewSimpleClientset(objects...),
+	}
+
+	if text := resultText(mustCall(t, s.openWarRoomHandler, ruleRequest(map[string]interface{}{"namespace": "shop", "service": "cart"}))); !strings.Contains(text, "No deployment or service named cart") {
+		t.Errorf("unexpected result for a missing service:\n%s", text)
+	}
+
+	text := resultText(mustCall(t, s.openWarRoomHandler, ruleRequest(map[string]interface{}{"namespace": "shop", "service": "api", "interval_seconds": 600})))
+	for _, want := range []string{
+		"🚨 War Room war-room-1: shop/api",
+		"Status: 🔴 degraded",
+		"🚀 Rollout: failing: container api of new pod api-6d4f-x1 is in CrashLoopBackOff",
+		"📦 Pods: 0/1 ready, 4 restarts",
+		"📈 Error rate: 12.5%",
+		"🔥 Firing alerts (1):\n• [warning] KubePodCrashLooping on shop/api-6d4f-x1",
+		"BackOff pod/api-6d4f-x1: Back-off restarting failed container (x3)",
+		"💡 Room war-room-1 refreshes every 10m0s",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("open_war_room output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "web-1") {
+		t.Errorf("open_war_room listed an alert about another service:\n%s", text)
+	}
+	if sessions := s.sessions.list(); len(sessions) != 1 || sessions[0].Kind != SessionWarRoom || sessions[0].Target != "shop/api" {
+		t.Errorf("sessions = %+v", sessions)
+	}
+	if text := resultText(mustCall(t, s.openWarRoomHandler, ruleRequest(map[string]interface{}{"namespace": "shop", "service": "api"}))); !strings.Contains(text, "already open") {
+		t.Errorf("expected the open room to be reused, got:\n%s", text)
+	}
+
+	updates, _, ok := s.SubscribeWarRoom("war-room-1")
+	if !ok {
+		t.Fatal("SubscribeWarRoom() found no room")
+	}
+	if first := <-updates; first.Seq != 1 || first.Status != WarRoomDegraded {
+		t.Errorf("first streamed update = %+v, expected the latest", first)
+	}
+
+	mu.Lock()
+	alerts = `[]`
+	mu.Unlock()
+	room, _ := s.warRooms.get("war-room-1")
+	s.refreshWarRoom(context.Background(), room)
+	update := <-updates
+	if update.Seq != 2 || !reflect.DeepEqual(update.Changes, []string{"✅ Alert resolved: KubePodCrashLooping shop/api-6d4f-x1"}) {
+		t.Errorf("refreshed update = %+v", update)
+	}
+	if !strings.Contains(update.Summary, "🔥 Firing alerts: none") || strings.Contains(update.Summary, "Back-off restarting") {
+		t.Errorf("refreshed summary repeats old events or alerts:\n%s", update.Summary)
+	}
+
+	text = resultText(mustCall(t, s.warRoomStatusHandler, ruleRequest(map[string]interface{}{"room_id": "war-room-1"})))
+	if !strings.Contains(text, "📜 Change log (last 2 refreshes):") || !strings.Contains(text, "Alert resolved: KubePodCrashLooping") {
+		t.Errorf("unexpected war_room_status output:\n%s", text)
+	}
+
+	text = resultText(mustCall(t, s.closeWarRoomHandler, ruleRequest(map[string]interface{}{"room_id": "war-room-1"})))
+	if !strings.Contains(text, "🏁 War room war-room-1 for shop/api closed") {
+		t.Errorf("unexpected close_war_room output:\n%s", text)
+	}
+	if last := <-updates; last.Status != WarRoomClosed {
+		t.Errorf("last streamed update = %+v, expected the room to close", last)
+	}
+	if _, open := <-updates; open {
+		t.Error("the stream stayed open after the room closed")
+	}
+	if sessions := s.sessions.list(); len(sessions) != 0 {
+		t.Errorf("sessions after closing = %+v", sessions)
+	}
+	if text := resultText(mustCall(t, s.warRoomStatusHandler, ruleRequest(map[string]interface{}{}))); !strings.Contains(text, "No war rooms are open") {
+		t.Errorf("unexpected war_room_status output after closing:\n%s", text)
+	}
+}
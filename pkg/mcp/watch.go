@@ -0,0 +1,485 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	defaultWatchSeconds = 60
+	maxWatchSeconds     = 600
+	// maxWatchTransitions bounds the transitions kept in the result
+	maxWatchTransitions = 50
+)
+
+// watchCondition is what watch_resource waits for: ready, exists, deleted,
+// a condition such as Available=True or a field such as status.phase=Running
+type watchCondition struct {
+	kind  string // ready, exists, deleted, condition, field or "" to watch until the timeout
+	key   string
+	value string
+}
+
+func (s *Server) initWatch() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("watch_resource",
+			mcp.WithDescription("Watch a resource, or the resources matching a label selector, for a bounded time or until a condition is met, and report each state transition. Use it after a change to verify that it took effect, e.g. until pods are Ready"),
+			mcp.WithString("resource_type", mcp.Description("Resource type, e.g. pods, deployments or kafkas.kafka.strimzi.io"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Name of the resource (default: every resource matching label_selector)")),
+			mcp.WithString("namespace", mcp.Description("Namespace of namespaced resources (default: default)")),
+			mcp.WithString("label_selector", mcp.Description("Label selector, e.g. app=web")),
+			mcp.WithString("until", mcp.Description("Stop when every watched resource meets this: ready, exists, deleted, a condition such as Available=True, or a field such as status.phase=Running (default: watch until the timeout)")),
+			mcp.WithString("timeout_seconds", mcp.Description(fmt.Sprintf("Seconds to watch (default %d, max %d)", defaultWatchSeconds, maxWatchSeconds))),
+			mcp.WithTitleAnnotation("Resources: Watch"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), Handler: server.ToolHandlerFunc(s.watchResourceHandler)},
+	}
+}
+
+// parseWatchCondition parses the until parameter
+func parseWatchCondition(value string) (watchCondition, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "":
+		return watchCondition{}, nil
+	case "ready", "exists", "deleted":
+		return watchCondition{kind: strings.ToLower(value)}, nil
+	}
+	key, expected, ok := strings.Cut(value, "=")
+	key, expected = strings.TrimSpace(key), strings.TrimSpace(expected)
+	if !ok || key == "" || expected == "" {
+		return watchCondition{}, fmt.Errorf("invalid until %q (expected ready, exists, deleted, <Condition>=<Status> or <field.path>=<value>)", value)
+	}
+	if strings.Contains(key, ".") {
+		return watchCondition{kind: "field", key: key, value: expected}, nil
+	}
+	return watchCondition{kind: "condition", key: key, value: expected}, nil
+}
+
+func (c watchCondition) String() string {
+	switch c.kind {
+	case "condition", "field":
+		return c.key + "=" + c.value
+	default:
+		return c.kind
+	}
+}
+
+// objectCondition returns the status of a condition in status.conditions,
+// matching the condition type case-insensitively
+func objectCondition(obj *unstructured.Unstructured, conditionType string) (string, bool) {
+	for _, condition := range statusConditions(obj) {
+		if strings.EqualFold(condition.Type, conditionType) {
+			return condition.Status, true
+		}
+	}
+	return "", false
+}
+
+// nestedInt reads an integer field, which may be decoded as int64 or float64
+func nestedInt(obj *unstructured.Unstructured, path ...string) (int64, bool) {
+	value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, path...)
+	if !found {
+		return 0, false
+	}
+	switch number := value.(type) {
+	case int64:
+		return number, true
+	case float64:
+		return int64(number), true
+	}
+	return 0, false
+}
+
+// objectReady reports whether a resource is ready the way oc rollout status
+// and oc wait --for=condition=Ready judge it
+func objectReady(obj *unstructured.Unstructured) bool {
+	if obj.GetDeletionTimestamp() != nil {
+		return false
+	}
+	switch obj.GetKind() {
+	case "Pod":
+		status, _ := objectCondition(obj, "Ready")
+		return status == "True"
+	case "Job":
+		status, _ := objectCondition(obj, "Complete")
+		return status == "True"
+	case "DaemonSet":
+		desired, _ := nestedInt(obj, "status", "desiredNumberScheduled")
+		ready, _ := nestedInt(obj, "status", "numberReady")
+		updated, _ := nestedInt(obj, "status", "updatedNumberScheduled")
+		return ready == desired && updated == desired && observedCurrent(obj)
+	}
+
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); found || obj.GetKind() == "Deployment" || obj.GetKind() == "StatefulSet" {
+		replicas, ok := nestedInt(obj, "spec", "replicas")
+		if !ok {
+			replicas = 1
+		}
+		ready, _ := nestedInt(obj, "status", "readyReplicas")
+		if ready != replicas || !observedCurrent(obj) {
+			return false
+		}
+		if updated, ok := nestedInt(obj, "status", "updatedReplicas"); ok && updated != replicas {
+			return false
+		}
+		if available, ok := nestedInt(obj, "status", "availableReplicas"); ok && available != replicas {
+			return false
+		}
+		return true
+	}
+
+	for _, conditionType := range []string{"Ready", "Available"} {
+		if status, ok := objectCondition(obj, conditionType); ok {
+			return status == "True"
+		}
+	}
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Running", "Bound", "Active", "Succeeded", "Available":
+		return true
+	}
+	return false
+}
+
+// observedCurrent reports whether the controller has seen the latest spec
+func observedCurrent(obj *unstructured.Unstructured) bool {
+	observed, ok := nestedInt(obj, "status", "observedGeneration")
+	return !ok || observed >= obj.GetGeneration()
+}
+
+// objectState condenses the state of a resource into one line, so that
+// only meaningful changes are reported as transitions
+func objectState(obj *unstructured.Unstructured) string {
+	var parts []string
+	if obj.GetDeletionTimestamp() != nil {
+		parts = append(parts, "Terminating")
+	}
+
+	switch obj.GetKind() {
+	case "Pod":
+		if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+			parts = append(parts, phase)
+		}
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+		ready := 0
+		var reasons []string
+		for _, item := range statuses {
+			status, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if status["ready"] == true {
+				ready++
+			}
+			for _, state := range []string{"waiting", "terminated"} {
+				if reason, found, _ := unstructured.NestedString(status, "state", state, "reason"); found && reason != "" {
+					reasons = append(reasons, reason)
+				}
+			}
+		}
+		if len(statuses) > 0 {
+			parts = append(parts, fmt.Sprintf("ready %d/%d", ready, len(statuses)))
+		}
+		parts = append(parts, reasons...)
+		return strings.Join(parts, ", ")
+	case "DaemonSet":
+		desired, _ := nestedInt(obj, "status", "desiredNumberScheduled")
+		ready, _ := nestedInt(obj, "status", "numberReady")
+		updated, _ := nestedInt(obj, "status", "updatedNumberScheduled")
+		parts = append(parts, fmt.Sprintf("ready %d/%d, updated %d", ready, desired, updated))
+		return strings.Join(parts, ", ")
+	}
+
+	if replicas, ok := nestedInt(obj, "spec", "replicas"); ok {
+		ready, _ := nestedInt(obj, "status", "readyReplicas")
+		state := fmt.Sprintf("ready %d/%d", ready, replicas)
+		if updated, ok := nestedInt(obj, "status", "updatedReplicas"); ok {
+			state += fmt.Sprintf(", updated %d", updated)
+		}
+		if available, ok := nestedInt(obj, "status", "availableReplicas"); ok {
+			state += fmt.Sprintf(", available %d", available)
+		}
+		parts = append(parts, state)
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+		parts = append(parts, phase)
+	}
+	for _, condition := range statusConditions(obj) {
+		if condition.Status == "True" {
+			parts = append(parts, condition.Type)
+		} else {
+			parts = append(parts, condition.Type+"="+condition.Status)
+		}
+	}
+	if len(parts) == 0 {
+		return "present"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// met reports whether a resource meets the condition; deleted and exists
+// are decided by whether the resource is there at all
+func (c watchCondition) met(obj *unstructured.Unstructured) bool {
+	switch c.kind {
+	case "ready":
+		return objectReady(obj)
+	case "exists":
+		return true
+	case "condition":
+		status, _ := objectCondition(obj, c.key)
+		return strings.EqualFold(status, c.value)
+	case "field":
+		value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(c.key, ".")...)
+		return found && fmt.Sprintf("%v", value) == c.value
+	}
+	return false
+}
+
+// allMet reports whether every watched resource meets the condition
+func (c watchCondition) allMet(objects map[string]*unstructured.Unstructured) bool {
+	switch c.kind {
+	case "":
+		return false
+	case "deleted":
+		return len(objects) == 0
+	}
+	if len(objects) == 0 {
+		return false
+	}
+	for _, obj := range objects {
+		if !c.met(obj) {
+			return false
+		}
+	}
+	return true
+}
+
+// notifyProgress sends an MCP progress notification when the client asked
+// for progress on this call
+func notifyProgress(ctx context.Context, request mcp.CallToolRequest, progress int, message string) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return
+	}
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return
+	}
+	_ = mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": request.Params.Meta.ProgressToken,
+		"progress":      progress,
+		"message":       message,
+	})
+}
+
+func (s *Server) watchResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.dynamicClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	resourceType := mcp.ParseString(request, "resource_type", "")
+	name := strings.TrimSpace(mcp.ParseString(request, "name", ""))
+	namespace := mcp.ParseString(request, "namespace", "default")
+	_, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	condition, err := parseWatchCondition(mcp.ParseString(request, "until", ""))
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	timeoutSeconds, err := strconv.Atoi(mcp.ParseString(request, "timeout_seconds", strconv.Itoa(defaultWatchSeconds)))
+	if err != nil || timeoutSeconds < 1 || timeoutSeconds > maxWatchSeconds {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ timeout_seconds must be between 1 and %d", maxWatchSeconds)), nil
+	}
+
+	gvr, namespaced, err := s.resolveResource(resourceType)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+	if name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+	if !namespaced || namespace == "all" || namespace == "*" {
+		namespace = ""
+	}
+	// Across namespaces, resources are told apart by namespace/name
+	keyOf := func(obj *unstructured.Unstructured) string {
+		if namespaced && namespace == "" {
+			return obj.GetNamespace() + "/" + obj.GetName()
+		}
+		return obj.GetName()
+	}
+	client := s.resourceInterface(gvr, namespaced, namespace)
+
+	target := gvr.Resource
+	if name != "" {
+		target += "/" + name
+	}
+	if opts.LabelSelector != "" {
+		target += fmt.Sprintf(" (%s)", opts.LabelSelector)
+	}
+	switch {
+	case namespace != "":
+		target += " in " + namespace
+	case namespaced:
+		target += " in all namespaces"
+	}
+
+	start := time.Now()
+	var transitions []string
+	dropped := 0
+	record := func(line string) {
+		line = fmt.Sprintf("[+%ds] %s", int(time.Since(start).Seconds()), line)
+		notifyProgress(ctx, request, len(transitions)+dropped+1, line)
+		if len(transitions) >= maxWatchTransitions {
+			dropped++
+			return
+		}
+		transitions = append(transitions, line)
+	}
+
+	list, err := client.List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list %s", target), err), nil
+	}
+	objects := make(map[string]*unstructured.Unstructured)
+	states := make(map[string]string)
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := keyOf(obj)
+		objects[key] = obj
+		states[key] = objectState(obj)
+		record(fmt.Sprintf("%s: %s", key, states[key]))
+	}
+	if len(list.Items) == 0 {
+		record("no matching resources")
+	}
+
+	met := condition.allMet(objects)
+	watchErr := ""
+	resourceVersion := list.GetResourceVersion()
+	watchCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+	session := s.sessions.open(SessionWatch, target, cancel)
+	defer session.Close()
+
+	// The API server ends watches after a while; watch again from the last
+	// seen version until the condition is met or the time is up
+	for !met && watchCtx.Err() == nil && watchErr == "" {
+		watchOpts := opts
+		watchOpts.ResourceVersion = resourceVersion
+		watcher, err := client.Watch(watchCtx, watchOpts)
+		if err != nil {
+			if watchCtx.Err() == nil {
+				watchErr = err.Error()
+			}
+			break
+		}
+		for !met && watchErr == "" {
+			var event watch.Event
+			var open bool
+			select {
+			case <-watchCtx.Done():
+			case event, open = <-watcher.ResultChan():
+			}
+			if watchCtx.Err() != nil || !open {
+				break
+			}
+
+			if event.Type == watch.Error {
+				if status, ok := event.Object.(*metav1.Status); ok {
+					watchErr = status.Message
+				} else {
+					watchErr = "watch failed"
+				}
+				break
+			}
+			session.touch()
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			resourceVersion = obj.GetResourceVersion()
+			key := keyOf(obj)
+
+			switch event.Type {
+			case watch.Deleted:
+				delete(objects, key)
+				delete(states, key)
+				record(fmt.Sprintf("%s: deleted", key))
+			case watch.Added, watch.Modified:
+				_, known := objects[key]
+				objects[key] = obj
+				if state := objectState(obj); !known || state != states[key] {
+					states[key] = state
+					if known {
+						record(fmt.Sprintf("%s: %s", key, state))
+					} else {
+						record(fmt.Sprintf("%s: created, %s", key, state))
+					}
+				}
+			}
+			met = condition.allMet(objects)
+		}
+		watcher.Stop()
+	}
+
+	elapsed := time.Since(start).Round(time.Second)
+	result := "👀 Watch Results\n"
+	result += "================\n\n"
+	result += fmt.Sprintf("Watching: %s\n", target)
+	if condition.kind != "" {
+		result += fmt.Sprintf("Until: %s\n", condition)
+	}
+	result += fmt.Sprintf("Duration: %s (limit %ds)\n\n", elapsed, timeoutSeconds)
+
+	result += "🔄 Transitions:\n"
+	for _, line := range transitions {
+		result += line + "\n"
+	}
+	if dropped > 0 {
+		result += fmt.Sprintf("... %d more transitions not shown\n", dropped)
+	}
+
+	result += "\n"
+	switch {
+	case watchErr != "":
+		recordToolError(ctx, fmt.Errorf("watch %s: %s", target, watchErr))
+		result += fmt.Sprintf("❌ The watch failed: %s", watchErr)
+	case met:
+		result += fmt.Sprintf("✅ Condition %s met after %s", condition, elapsed)
+	case condition.kind != "":
+		var pending []string
+		for _, name := range sortedKeys(objects) {
+			if condition.kind == "deleted" || !condition.met(objects[name]) {
+				pending = append(pending, fmt.Sprintf("%s (%s)", name, states[name]))
+			}
+		}
+		result += fmt.Sprintf("⏱️  Condition %s not met within %ds", condition, timeoutSeconds)
+		if len(pending) > 0 {
+			result += fmt.Sprintf("; still waiting for: %s", strings.Join(pending, ", "))
+		} else if len(objects) == 0 {
+			result += "; no matching resources exist"
+		}
+		result += "\n💡 Check get_events and the resource's status for what blocks it, or watch again with a longer timeout_seconds"
+	default:
+		result += fmt.Sprintf("✅ Watched for %s; %d resource(s) present at the end", elapsed, len(objects))
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// WatchResourceHandler is a public wrapper for watchResourceHandler
+func (s *Server) WatchResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.watchResourceHandler(ctx, request)
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// watchPod builds a pod with one container in the given phase
+func watchPod(name, phase string, ready bool) *unstructured.Unstructured {
+	readyStatus := "False"
+	if ready {
+		readyStatus = "True"
+	}
+	containerStatus := map[string]interface{}{"name": "app", "ready": ready}
+	if !ready {
+		containerStatus["state"] = map[string]interface{}{"waiting": map[string]interface{}{"reason": "ContainerCreating"}}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "shop", "labels": map[string]interface{}{"app": "web"}},
+		"status": map[string]interface{}{
+			"phase":             phase,
+			"conditions":        []interface{}{map[string]interface{}{"type": "Ready", "status": readyStatus}},
+			"containerStatuses": []interface{}{containerStatus},
+		},
+	}}
+}
+
+func newWatchTestServer(watcher *watch.FakeWatcher, objects ...runtime.Object) *Server {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList",
+	}, objects...)
+	client.PrependWatchReactor("pods", k8stesting.DefaultWatchReactor(watcher, nil))
+	return &Server{config: &Config{}, dynamicClient: client, restMapper: mapper}
+}
+
+func TestParseWatchCondition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected watchCondition
+		valid    bool
+	}{
+		{"", watchCondition{}, true},
+		{"Ready", watchCondition{kind: "ready"}, true},
+		{"deleted", watchCondition{kind: "deleted"}, true},
+		{"Available=True", watchCondition{kind: "condition", key: "Available", value: "True"}, true},
+		{"status.phase = Running", watchCondition{kind: "field", key: "status.phase", value: "Running"}, true},
+		{"running", watchCondition{}, false},
+		{"Available=", watchCondition{}, false},
+	}
+	for _, tt := range tests {
+		condition, err := parseWatchCondition(tt.input)
+		if (err == nil) != tt.valid || condition != tt.expected {
+			t.Errorf("parseWatchCondition(%q) = %+v, %v, expected %+v", tt.input, condition, err, tt.expected)
+		}
+	}
+}
+
+func TestObjectReadyAndState(t *testing.T) {
+	deployment := func(replicas, ready, updated, available int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"name": "web", "generation": int64(2)},
+			"spec":     map[string]interface{}{"replicas": replicas},
+			"status": map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": ready,
+				"updatedReplicas": updated, "availableReplicas": available},
+		}}
+	}
+	pvc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "PersistentVolumeClaim", "status": map[string]interface{}{"phase": "Bound"},
+	}}
+
+	tests := []struct {
+		obj   *unstructured.Unstructured
+		ready bool
+		state string
+	}{
+		{watchPod("web-1", "Pending", false), false, "Pending, ready 0/1, ContainerCreating"},
+		{watchPod("web-1", "Running", true), true, "Running, ready 1/1"},
+		{deployment(3, 3, 3, 3), true, "ready 3/3, updated 3, available 3"},
+		{deployment(3, 3, 1, 3), false, "ready 3/3, updated 1, available 3"},
+		{pvc, true, "Bound"},
+	}
+	for _, tt := range tests {
+		if ready, state := objectReady(tt.obj), objectState(tt.obj); ready != tt.ready || state != tt.state {
+			t.Errorf("objectReady/objectState(%s) = %v, %q, expected %v, %q", tt.obj.GetKind(), ready, state, tt.ready, tt.state)
+		}
+	}
+}
+
+func TestWatchResourceUntilReady(t *testing.T) {
+	watcher := watch.NewFake()
+	s := newWatchTestServer(watcher, watchPod("web-1", "Pending", false))
+	go func() {
+		watcher.Modify(watchPod("web-1", "Pending", false))
+		watcher.Modify(watchPod("web-1", "Running", true))
+	}()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resource_type": "pods", "namespace": "shop", "label_selector": "app=web", "until": "ready", "timeout_seconds": "10",
+	}
+	result, err := s.watchResourceHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("watchResourceHandler() error = %v", err)
+	}
+	text := resultText(result)
+	for _, want := range []string{
+		"Watching: pods (app=web) in shop",
+		"] web-1: Pending, ready 0/1, ContainerCreating\n[+",
+		"] web-1: Running, ready 1/1\n",
+		"✅ Condition ready met after",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("watchResourceHandler() missing %q:\n%s", want, text)
+		}
+	}
+	// The unchanged Pending update is not a transition
+	if strings.Count(text, "Pending") != 1 {
+		t.Errorf("watchResourceHandler() reported an unchanged state twice:\n%s", text)
+	}
+}
+
+func TestWatchResourceTimeout(t *testing.T) {
+	s := newWatchTestServer(watch.NewFake(), watchPod("web-1", "Pending", false))
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resource_type": "pods", "namespace": "shop", "until": "deleted", "timeout_seconds": "1",
+	}
+	result, _ := s.watchResourceHandler(context.Background(), request)
+	expected := "⏱️  Condition deleted not met within 1s; still waiting for: web-1 (Pending, ready 0/1, ContainerCreating)"
+	if text := resultText(result); !strings.Contains(text, expected) {
+		t.Errorf("watchResourceHandler() missing %q:\n%s", expected, text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"resource_type": "pods", "timeout_seconds": "900"}
+	result, _ = s.watchResourceHandler(context.Background(), request)
+	if text := resultText(result); text != "❌ timeout_seconds must be between 1 and 600" {
+		t.Errorf("watchResourceHandler(timeout 900) = %q", text)
+	}
+}
@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// workloadSummary is one line of a workload listing
+type workloadSummary struct {
+	namespace string
+	name      string
+	status    string
+	images    []string
+	healthy   bool
+	created   time.Time
+}
+
+// parseWorkloadScope reads the namespace and label_selector parameters. The
+// namespace "all" lists every namespace.
+func parseWorkloadScope(request mcp.CallToolRequest) (string, metav1.ListOptions, error) {
+	namespace := mcp.ParseString(request, "namespace", "default")
+	if namespace == "all" || namespace == "*" {
+		namespace = metav1.NamespaceAll
+	}
+	selector := strings.TrimSpace(mcp.ParseString(request, "label_selector", ""))
+	if selector != "" {
+		if _, err := labels.Parse(selector); err != nil {
+			return "", metav1.ListOptions{}, fmt.Errorf("invalid label_selector %q: %v", selector, err)
+		}
+	}
+	return namespace, metav1.ListOptions{LabelSelector: selector}, nil
+}
+
+// formatAge renders how long ago a workload was created, e.g. "3d" or "5h"
+func formatAge(created time.Time) string {
+	if created.IsZero() {
+		return "unknown"
+	}
+	return shortDuration(time.Since(created))
+}
+
+func containerImages(spec corev1.PodSpec) []string {
+	images := make([]string, 0, len(spec.Containers))
+	for _, container := range spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func deploymentSummary(d *appsv1.Deployment) workloadSummary {
+	desired := replicasOrDefault(d.Spec.Replicas)
+	status := fmt.Sprintf("Ready %d/%d, Up-to-date %d, Available %d",
+		d.Status.ReadyReplicas, desired, d.Status.UpdatedReplicas, d.Status.AvailableReplicas)
+	healthy := d.Status.ReadyReplicas >= desired && d.Status.UpdatedReplicas >= desired
+
+	var notes []string
+	if d.Spec.Paused {
+		notes = append(notes, "rollout paused")
+	}
+	if desired == 0 {
+		notes = append(notes, "scaled to zero")
+	}
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
+			notes = append(notes, fmt.Sprintf("not progressing: %s", condition.Reason))
+			healthy = false
+		}
+		if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue {
+			notes = append(notes, fmt.Sprintf("replica failure: %s", condition.Message))
+			healthy = false
+		}
+	}
+	if len(notes) > 0 {
+		status += " (" + strings.Join(notes, "; ") + ")"
+	}
+	return workloadSummary{d.Namespace, d.Name, status, containerImages(d.Spec.Template.Spec), healthy, d.CreationTimestamp.Time}
+}
+
+func statefulSetSummary(sts *appsv1.StatefulSet) workloadSummary {
+	desired := replicasOrDefault(sts.Spec.Replicas)
+	status := fmt.Sprintf("Ready %d/%d, Current %d, Updated %d",
+		sts.Status.ReadyReplicas, desired, sts.Status.CurrentReplicas, sts.Status.UpdatedReplicas)
+	healthy := sts.Status.ReadyReplicas >= desired
+
+	var notes []string
+	if desired == 0 {
+		notes = append(notes, "scaled to zero")
+	}
+	if sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		notes = append(notes, fmt.Sprintf("rolling update to %s in progress", sts.Status.UpdateRevision))
+		healthy = false
+	}
+	if len(notes) > 0 {
+		status += " (" + strings.Join(notes, "; ") + ")"
+	}
+	return workloadSummary{sts.Namespace, sts.Name, status, containerImages(sts.Spec.Template.Spec), healthy, sts.CreationTimestamp.Time}
+}
+
+func daemonSetSummary(ds *appsv1.DaemonSet) workloadSummary {
+	desired := ds.Status.DesiredNumberScheduled
+	status := fmt.Sprintf("Ready %d/%d, Up-to-date %d, Available %d",
+		ds.Status.NumberReady, desired, ds.Status.UpdatedNumberScheduled, ds.Status.NumberAvailable)
+	healthy := ds.Status.NumberReady >= desired && ds.Status.UpdatedNumberScheduled >= desired
+
+	var notes []string
+	if ds.Status.NumberMisscheduled > 0 {
+		notes = append(notes, fmt.Sprintf("%d misscheduled", ds.Status.NumberMisscheduled))
+		healthy = false
+	}
+	if len(ds.Spec.Template.Spec.NodeSelector) > 0 {
+		notes = append(notes, fmt.Sprintf("node selector %s", labels.Set(ds.Spec.Template.Spec.NodeSelector).String()))
+	}
+	if len(notes) > 0 {
+		status += " (" + strings.Join(notes, "; ") + ")"
+	}
+	return workloadSummary{ds.Namespace, ds.Name, status, containerImages(ds.Spec.Template.Spec), healthy, ds.CreationTimestamp.Time}
+}
+
+// formatWorkloadList renders summaries with unhealthy workloads first
+func formatWorkloadList(kind, namespace, selector string, summaries []workloadSummary) string {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].healthy != summaries[j].healthy {
+			return !summaries[i].healthy
+		}
+		if summaries[i].namespace != summaries[j].namespace {
+			return summaries[i].namespace < summaries[j].namespace
+		}
+		return summaries[i].name < summaries[j].name
+	})
+
+	title := fmt.Sprintf("📋 %s List Results", kind)
+	result := title + "\n"
+	result += strings.Repeat("=", len(title)-2) + "\n\n"
+	if namespace == metav1.NamespaceAll {
+		result += "Namespace: all\n"
+	} else {
+		result += fmt.Sprintf("Namespace: %s\n", namespace)
+	}
+	if selector != "" {
+		result += fmt.Sprintf("Label selector: %s\n", selector)
+	}
+
+	unhealthy := 0
+	for _, summary := range summaries {
+		if !summary.healthy {
+			unhealthy++
+		}
+	}
+	result += fmt.Sprintf("📦 Found %d %s", len(summaries), strings.ToLower(kind))
+	if unhealthy > 0 {
+		result += fmt.Sprintf(", ⚠️  %d not fully ready", unhealthy)
+	}
+	result += ":\n"
+
+	for _, summary := range summaries {
+		icon := "✅"
+		if !summary.healthy {
+			icon = "⚠️ "
+		}
+		name := summary.name
+		if namespace == metav1.NamespaceAll {
+			name = summary.namespace + "/" + summary.name
+		}
+		result += fmt.Sprintf("%s %s - %s, age %s\n", icon, name, summary.status, formatAge(summary.created))
+		if len(summary.images) > 0 {
+			result += fmt.Sprintf("   Images: %s\n", strings.Join(summary.images, ", "))
+		}
+	}
+	if len(summaries) == 0 {
+		result += "📭 None found\n"
+	}
+	return strings.TrimRight(result, "\n")
+}
+
+func (s *Server) listDeploymentsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	deployments, err := s.k8sClient.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list deployments in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	summaries := make([]workloadSummary, 0, len(deployments.Items))
+	for i := range deployments.Items {
+		summaries = append(summaries, deploymentSummary(&deployments.Items[i]))
+	}
+	return mcp.NewToolResultText(formatWorkloadList("Deployments", namespace, opts.LabelSelector, summaries)), nil
+}
+
+func (s *Server) listStatefulSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	statefulSets, err := s.k8sClient.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list statefulsets in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	summaries := make([]workloadSummary, 0, len(statefulSets.Items))
+	for i := range statefulSets.Items {
+		summaries = append(summaries, statefulSetSummary(&statefulSets.Items[i]))
+	}
+	return mcp.NewToolResultText(formatWorkloadList("StatefulSets", namespace, opts.LabelSelector, summaries)), nil
+}
+
+func (s *Server) listDaemonSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.k8sClient == nil {
+		return mcp.NewToolResultText("❌ Kubernetes client not available. Please check your kubeconfig."), nil
+	}
+	namespace, opts, err := parseWorkloadScope(request)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %v", err)), nil
+	}
+
+	daemonSets, err := s.k8sClient.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return toolError(ctx, fmt.Sprintf("Failed to list daemonsets in namespace %s", valueOrNone(namespace)), err), nil
+	}
+	summaries := make([]workloadSummary, 0, len(daemonSets.Items))
+	for i := range daemonSets.Items {
+		summaries = append(summaries, daemonSetSummary(&daemonSets.Items[i]))
+	}
+	return mcp.NewToolResultText(formatWorkloadList("DaemonSets", namespace, opts.LabelSelector, summaries)), nil
+}
+
+// ListDeploymentsHandler is a public wrapper for listDeploymentsHandler
+func (s *Server) ListDeploymentsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listDeploymentsHandler(ctx, request)
+}
+
+// ListStatefulSetsHandler is a public wrapper for listStatefulSetsHandler
+func (s *Server) ListStatefulSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listStatefulSetsHandler(ctx, request)
+}
+
+// ListDaemonSetsHandler is a public wrapper for listDaemonSetsHandler
+func (s *Server) ListDaemonSetsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.listDaemonSetsHandler(ctx, request)
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		created  time.Time
+		expected string
+	}{
+		{now.Add(-30 * time.Second), "30s"},
+		{now.Add(-5 * time.Minute), "5m"},
+		{now.Add(-5 * time.Hour), "5h"},
+		{now.Add(-72 * time.Hour), "3d"},
+		{time.Time{}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if result := formatAge(tt.created); result != tt.expected {
+			t.Errorf("formatAge(%v) = %s, expected %s", tt.created, result, tt.expected)
+		}
+	}
+}
+
+func TestWorkloadSummaries(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary workloadSummary
+		status  string
+		healthy bool
+	}{
+		{
+			name: "ready deployment",
+			summary: deploymentSummary(&appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+			}),
+			status:  "Ready 3/3, Up-to-date 3, Available 3",
+			healthy: true,
+		},
+		{
+			name: "stuck deployment",
+			summary: deploymentSummary(&appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 1, Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+				}},
+			}),
+			status:  "Ready 1/2, Up-to-date 1, Available 0 (not progressing: ProgressDeadlineExceeded)",
+			healthy: false,
+		},
+		{
+			name: "statefulset mid-rollout",
+			summary: statefulSetSummary(&appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, CurrentReplicas: 2, UpdatedReplicas: 1, CurrentRevision: "db-1", UpdateRevision: "db-2"},
+			}),
+			status:  "Ready 3/3, Current 2, Updated 1 (rolling update to db-2 in progress)",
+			healthy: false,
+		},
+		{
+			name: "daemonset on labelled nodes",
+			summary: daemonSetSummary(&appsv1.DaemonSet{
+				Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+				}}},
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+			}),
+			status:  "Ready 3/3, Up-to-date 3, Available 3 (node selector node-role.kubernetes.io/infra=)",
+			healthy: true,
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.summary.status != tt.status || tt.summary.healthy != tt.healthy {
+			t.Errorf("%s: summary = %q (healthy %v), expected %q (healthy %v)", tt.name, tt.summary.status, tt.summary.healthy, tt.status, tt.healthy)
+		}
+	}
+}
+
+func TestListDeployments(t *testing.T) {
+	deployment := func(namespace, name string, ready int32, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(2),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: name + ":v1"}}}},
+			},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: ready, UpdatedReplicas: 2, AvailableReplicas: ready},
+		}
+	}
+	s := &Server{config: &Config{}, k8sClient: kubefake.NewSimpleClientset(
+		deployment("shop", "api", 2, map[string]string{"tier": "backend"}),
+		deployment("shop", "web", 1, map[string]string{"tier": "frontend"}),
+		deployment("billing", "invoices", 2, map[string]string{"tier": "backend"}),
+	)}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"namespace": "shop"}
+	result, _ := s.listDeploymentsHandler(context.Background(), request)
+	text := resultText(result)
+	if !strings.Contains(text, "Found 2 deployments, ⚠️  1 not fully ready") {
+		t.Errorf("list_deployments summary missing:\n%s", text)
+	}
+	if strings.Index(text, "⚠️  web - Ready 1/2") > strings.Index(text, "✅ api - Ready 2/2") {
+		t.Errorf("list_deployments does not list unhealthy deployments first:\n%s", text)
+	}
+	if !strings.Contains(text, "Images: web:v1") {
+		t.Errorf("list_deployments missing images:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"namespace": "all", "label_selector": "tier=backend"}
+	result, _ = s.listDeploymentsHandler(context.Background(), request)
+	text = resultText(result)
+	if !strings.Contains(text, "billing/invoices") || !strings.Contains(text, "shop/api") || strings.Contains(text, "shop/web") {
+		t.Errorf("list_deployments across namespaces with a selector:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"label_selector": "tier in (a"}
+	result, _ = s.listDeploymentsHandler(context.Background(), request)
+	if text := resultText(result); !strings.HasPrefix(text, "❌ invalid label_selector") {
+		t.Errorf("list_deployments with a bad selector = %q", text)
+	}
+}
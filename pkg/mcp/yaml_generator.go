@@ -2,14 +2,16 @@ package mcp
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -160,100 +162,151 @@ func (y *YAMLGenerator) GenerateServiceYAML(name, namespace string, selector map
 	return y.marshalToYAML(service)
 }
 
-// GenerateScaleActionYAML generates YAML for a scale action (not a resource, but an action record)
-func (y *YAMLGenerator) GenerateScaleActionYAML(deploymentName, namespace string, oldReplicas, newReplicas int32) (string, error) {
-	scaleAction := map[string]interface{}{
-		"apiVersion": "mcp.openshift.io/v1",
-		"kind":       "ScaleAction",
-		"metadata": map[string]interface{}{
-			"name":      fmt.Sprintf("scale-%s-%s", deploymentName, time.Now().Format("20060102-150405")),
-			"namespace": namespace,
-			"labels": map[string]string{
-				"action-type": "scale",
-				"created-by":  "openshift-mcp",
-				"created-at":  time.Now().Format("2006-01-02"),
-			},
+// newHPA builds an autoscaling/v2 HorizontalPodAutoscaler scaling a workload
+// on average CPU and/or memory utilization; a zero percentage leaves that
+// metric out
+func newHPA(name, namespace, targetKind, targetName string, minReplicas, maxReplicas, cpuPercent, memoryPercent int32) *autoscalingv2.HorizontalPodAutoscaler {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling/v2",
+			Kind:       "HorizontalPodAutoscaler",
 		},
-		"spec": map[string]interface{}{
-			"target": map[string]interface{}{
-				"kind":      "Deployment",
-				"name":      deploymentName,
-				"namespace": namespace,
-			},
-			"scaleSpec": map[string]interface{}{
-				"oldReplicas": oldReplicas,
-				"newReplicas": newReplicas,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":        targetName,
+				"created-by": "openshift-mcp",
+				"created-at": time.Now().Format("2006-01-02"),
 			},
 		},
-		"status": map[string]interface{}{
-			"timestamp": time.Now().Format(time.RFC3339),
-			"action":    "scale",
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       targetKind,
+				Name:       targetName,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
 		},
 	}
+	targets := []struct {
+		resource corev1.ResourceName
+		percent  int32
+	}{{corev1.ResourceCPU, cpuPercent}, {corev1.ResourceMemory, memoryPercent}}
+	for _, target := range targets {
+		if target.percent <= 0 {
+			continue
+		}
+		utilization := target.percent
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: target.resource,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+	return hpa
+}
 
-	return y.marshalToYAML(scaleAction)
+// GenerateHPAYAML generates YAML for a HorizontalPodAutoscaler
+func (y *YAMLGenerator) GenerateHPAYAML(name, namespace, targetKind, targetName string, minReplicas, maxReplicas, cpuPercent, memoryPercent int32) (string, error) {
+	return y.marshalObjectToYAML(newHPA(name, namespace, targetKind, targetName, minReplicas, maxReplicas, cpuPercent, memoryPercent))
 }
 
-// GenerateRestartActionYAML generates YAML for a restart action record
-func (y *YAMLGenerator) GenerateRestartActionYAML(deploymentName, namespace string) (string, error) {
-	restartAction := map[string]interface{}{
-		"apiVersion": "mcp.openshift.io/v1",
-		"kind":       "RestartAction",
-		"metadata": map[string]interface{}{
-			"name":      fmt.Sprintf("restart-%s-%s", deploymentName, time.Now().Format("20060102-150405")),
-			"namespace": namespace,
-			"labels": map[string]string{
-				"action-type": "restart",
-				"created-by":  "openshift-mcp",
-				"created-at":  time.Now().Format("2006-01-02"),
-			},
+// GenerateNetworkPolicyYAML generates YAML for a NetworkPolicy
+func (y *YAMLGenerator) GenerateNetworkPolicyYAML(name, namespace string, spec networkingv1.NetworkPolicySpec) (string, error) {
+	policy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
 		},
-		"spec": map[string]interface{}{
-			"target": map[string]interface{}{
-				"kind":      "Deployment",
-				"name":      deploymentName,
-				"namespace": namespace,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"created-by": "openshift-mcp",
+				"created-at": time.Now().Format("2006-01-02"),
 			},
 		},
-		"status": map[string]interface{}{
-			"timestamp":         time.Now().Format(time.RFC3339),
-			"action":            "restart",
-			"restartedAt":       time.Now().Format(time.RFC3339),
-			"restartAnnotation": "kubectl.kubernetes.io/restartedAt",
-		},
+		Spec: spec,
 	}
-
-	return y.marshalToYAML(restartAction)
+	return y.marshalObjectToYAML(policy)
 }
 
-// GenerateDeleteActionYAML generates YAML for a delete action record
-func (y *YAMLGenerator) GenerateDeleteActionYAML(resourceType, resourceName, namespace string) (string, error) {
-	deleteAction := map[string]interface{}{
-		"apiVersion": "mcp.openshift.io/v1",
-		"kind":       "DeleteAction",
-		"metadata": map[string]interface{}{
-			"name":      fmt.Sprintf("delete-%s-%s-%s", strings.ToLower(resourceType), resourceName, time.Now().Format("20060102-150405")),
-			"namespace": namespace,
-			"labels": map[string]string{
-				"action-type": "delete",
-				"created-by":  "openshift-mcp",
-				"created-at":  time.Now().Format("2006-01-02"),
-			},
-		},
-		"spec": map[string]interface{}{
-			"target": map[string]interface{}{
-				"kind":      resourceType,
-				"name":      resourceName,
-				"namespace": namespace,
+// GenerateScaleActionYAML generates an ActionRecord for a scale action
+func (y *YAMLGenerator) GenerateScaleActionYAML(deploymentName, namespace string, oldReplicas, newReplicas int32) (string, error) {
+	return y.marshalToYAML(newActionRecord("scale",
+		actionRecordTarget{APIVersion: "apps/v1", Kind: "Deployment", Name: deploymentName, Namespace: namespace},
+		time.Now(), map[string]interface{}{
+			"parameters": map[string]interface{}{
+				"oldReplicas": oldReplicas,
+				"newReplicas": newReplicas,
 			},
-		},
-		"status": map[string]interface{}{
-			"timestamp": time.Now().Format(time.RFC3339),
-			"action":    "delete",
-		},
+		}))
+}
+
+// GenerateRestartActionYAML generates an ActionRecord for a restart action
+func (y *YAMLGenerator) GenerateRestartActionYAML(deploymentName, namespace string) (string, error) {
+	now := time.Now()
+	return y.marshalToYAML(newActionRecord("restart",
+		actionRecordTarget{APIVersion: "apps/v1", Kind: "Deployment", Name: deploymentName, Namespace: namespace},
+		now, map[string]interface{}{
+			"parameters": map[string]interface{}{
+				"restartAnnotation": "kubectl.kubernetes.io/restartedAt",
+				"restartedAt":       now.Format(time.RFC3339),
+			},
+		}))
+}
+
+// GenerateRollbackActionYAML generates an ActionRecord for a rollback action
+func (y *YAMLGenerator) GenerateRollbackActionYAML(deploymentName, namespace string, fromRevision, toRevision int64, images []string) (string, error) {
+	return y.marshalToYAML(newActionRecord("rollback",
+		actionRecordTarget{APIVersion: "apps/v1", Kind: "Deployment", Name: deploymentName, Namespace: namespace},
+		time.Now(), map[string]interface{}{
+			"parameters": map[string]interface{}{
+				"fromRevision": fromRevision,
+				"toRevision":   toRevision,
+				"images":       images,
+			},
+		}))
+}
+
+// GenerateCordonActionYAML generates an ActionRecord for a cordon or uncordon action
+func (y *YAMLGenerator) GenerateCordonActionYAML(nodeName string, cordon bool) (string, error) {
+	action := "cordon"
+	if !cordon {
+		action = "uncordon"
 	}
+	return y.marshalToYAML(newActionRecord(action,
+		actionRecordTarget{APIVersion: "v1", Kind: "Node", Name: nodeName},
+		time.Now(), map[string]interface{}{
+			"parameters": map[string]interface{}{"unschedulable": cordon},
+		}))
+}
+
+// GenerateDrainActionYAML generates an ActionRecord for a drain action
+func (y *YAMLGenerator) GenerateDrainActionYAML(nodeName string, evicted, failed []string, options map[string]interface{}) (string, error) {
+	return y.marshalToYAML(newActionRecord("drain",
+		actionRecordTarget{APIVersion: "v1", Kind: "Node", Name: nodeName},
+		time.Now(), map[string]interface{}{
+			"parameters": options,
+			"result": map[string]interface{}{
+				"evictedPods": evicted,
+				"failedPods":  failed,
+			},
+		}))
+}
 
-	return y.marshalToYAML(deleteAction)
+// GenerateDeleteActionYAML generates an ActionRecord for a delete action
+func (y *YAMLGenerator) GenerateDeleteActionYAML(resourceType, resourceName, namespace string) (string, error) {
+	return y.marshalToYAML(newActionRecord("delete",
+		actionRecordTarget{Kind: resourceType, Name: resourceName, Namespace: namespace},
+		time.Now(), nil))
 }
 
 // GenerateGenericResourceYAML generates YAML from a generic resource string
@@ -295,6 +348,21 @@ func (y *YAMLGenerator) marshalToYAML(obj interface{}) (string, error) {
 	return header + string(yamlData), nil
 }
 
+// marshalObjectToYAML marshals a typed API object through its JSON field
+// names, which the YAML marshaller does not see, without the fields the
+// server sets
+func (y *YAMLGenerator) marshalObjectToYAML(obj interface{}) (string, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert object: %v", err)
+	}
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	return y.marshalToYAML(object)
+}
+
 // ParseYAMLContent parses YAML content and returns structured data
 func (y *YAMLGenerator) ParseYAMLContent(yamlContent string) (map[string]interface{}, error) {
 	var data map[string]interface{}
@@ -0,0 +1,90 @@
+package network
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// Names the DNS workflow looks up from inside a pod
+const (
+	InternalLookupName = "kubernetes.default.svc.cluster.local"
+	ExternalLookupName = "google.com"
+)
+
+// DNSLookup is the output of one nslookup run in a pod
+type DNSLookup struct {
+	Output string
+	Failed bool // the command exited non-zero or could not run
+}
+
+// ParseResolvConf returns the first nameserver and the ndots option of a
+// resolv.conf
+func ParseResolvConf(resolvConf string) (nameserver string, ndots int) {
+	for _, line := range strings.Split(resolvConf, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			if nameserver == "" {
+				nameserver = fields[1]
+			}
+		case "options":
+			for _, option := range fields[1:] {
+				if value, ok := strings.CutPrefix(option, "ndots:"); ok {
+					ndots, _ = strconv.Atoi(value)
+				}
+			}
+		}
+	}
+	return nameserver, ndots
+}
+
+// ParseDNSLookups turns the resolv.conf of a pod and its lookups of a
+// cluster-internal and an external name into DNS symptoms
+func ParseDNSLookups(podInfo PodInfo, resolvConf string, internal, external DNSLookup) diagnostics.DNSSymptoms {
+	symptoms := diagnostics.DNSSymptoms{Pod: podInfo.PodName, Namespace: podInfo.Namespace}
+	symptoms.Nameserver, symptoms.Ndots = ParseResolvConf(resolvConf)
+
+	for _, lookup := range []struct {
+		name   string
+		result DNSLookup
+		failed *bool
+	}{
+		{InternalLookupName, internal, &symptoms.InternalFailed},
+		{ExternalLookupName, external, &symptoms.ExternalFailed},
+	} {
+		output := strings.ToLower(lookup.result.Output)
+		timeout := strings.Contains(output, "timed out") || strings.Contains(output, "no servers could be reached")
+		servfail := strings.Contains(output, "servfail")
+		notFound := strings.Contains(output, "nxdomain") || strings.Contains(output, "can't find")
+		if !lookup.result.Failed && !timeout && !servfail && !notFound {
+			continue
+		}
+		*lookup.failed = true
+		symptoms.Timeout = symptoms.Timeout || timeout
+		symptoms.ServerFailure = symptoms.ServerFailure || servfail
+		symptoms.NotFound = symptoms.NotFound || (notFound && !servfail)
+		symptoms.Evidence = append(symptoms.Evidence, "nslookup "+lookup.name+": "+firstDNSError(lookup.result.Output))
+	}
+	return symptoms
+}
+
+// firstDNSError returns the line of nslookup output that explains a failure
+func firstDNSError(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		lower := strings.ToLower(line)
+		for _, marker := range []string{"timed out", "no servers", "servfail", "nxdomain", "can't find", "error"} {
+			if strings.Contains(lower, marker) {
+				return strings.TrimSpace(line)
+			}
+		}
+	}
+	if trimmed := strings.TrimSpace(output); trimmed != "" {
+		return truncateOutput(trimmed, 120)
+	}
+	return "failed without output"
+}
@@ -0,0 +1,53 @@
+package network
+
+import "testing"
+
+func TestParseResolvConf(t *testing.T) {
+	nameserver, ndots := ParseResolvConf("search shop.svc.cluster.local svc.cluster.local\nnameserver 172.30.0.10\nnameserver 10.0.0.2\noptions ndots:5 timeout:2\n")
+	if nameserver != "172.30.0.10" || ndots != 5 {
+		t.Errorf("ParseResolvConf() = %s, %d, expected 172.30.0.10, 5", nameserver, ndots)
+	}
+}
+
+func TestParseDNSLookups(t *testing.T) {
+	pod := PodInfo{PodName: "web-1", Namespace: "shop", Found: true}
+	resolved := DNSLookup{Output: "Server:\t\t172.30.0.10\nName:\tkubernetes.default.svc.cluster.local\nAddress: 172.30.0.1\n"}
+
+	tests := []struct {
+		name     string
+		internal DNSLookup
+		external DNSLookup
+		check    func(t *testing.T, internal, external, timeout, servfail, notFound bool)
+	}{
+		{"healthy", resolved, resolved, func(t *testing.T, internal, external, timeout, servfail, notFound bool) {
+			if internal || external || timeout || servfail || notFound {
+				t.Error("expected no symptoms")
+			}
+		}},
+		{"upstream servfail", resolved, DNSLookup{Output: "** server can't find google.com: SERVFAIL\n", Failed: true},
+			func(t *testing.T, internal, external, timeout, servfail, notFound bool) {
+				if internal || !external || !servfail || notFound {
+					t.Errorf("internal=%v external=%v servfail=%v notFound=%v", internal, external, servfail, notFound)
+				}
+			}},
+		{"timeout", DNSLookup{Output: ";; connection timed out; no servers could be reached\n", Failed: true},
+			DNSLookup{Output: ";; connection timed out; no servers could be reached\n", Failed: true},
+			func(t *testing.T, internal, external, timeout, servfail, notFound bool) {
+				if !internal || !external || !timeout {
+					t.Errorf("internal=%v external=%v timeout=%v", internal, external, timeout)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symptoms := ParseDNSLookups(pod, "nameserver 172.30.0.10\n", tt.internal, tt.external)
+			if symptoms.Pod != "web-1" || symptoms.Nameserver != "172.30.0.10" {
+				t.Errorf("symptoms = %+v", symptoms)
+			}
+			tt.check(t, symptoms.InternalFailed, symptoms.ExternalFailed, symptoms.Timeout, symptoms.ServerFailure, symptoms.NotFound)
+			if symptoms.Failed() != (len(symptoms.Evidence) > 0) {
+				t.Errorf("evidence = %v for failed=%v", symptoms.Evidence, symptoms.Failed())
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
 	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/executor"
 	"github.com/sirupsen/logrus"
 )
@@ -24,6 +25,10 @@ type TroubleshootingResult struct {
 	Commands     []*executor.ExecutionResult `json:"commands"`
 	Summary      string                      `json:"summary"`
 	Success      bool                        `json:"success"`
+
+	// DNSSymptoms are the lookup failures the DNS workflow found in the
+	// pod, for correlation with the CoreDNS metrics
+	DNSSymptoms *diagnostics.DNSSymptoms `json:"dns_symptoms,omitempty"`
 }
 
 // PodInfo contains extracted pod information
@@ -51,6 +56,12 @@ type DiagnosticResult struct {
 	Recommendation string   `json:"recommendation"`
 	LogsNeeded     bool     `json:"logs_needed"`
 	NextSteps      []string `json:"next_steps"`
+
+	// Scheduling is the scheduler's reason for leaving the pod Pending, from
+	// its FailedScheduling event; EvictedFor is the resource the kubelet ran
+	// short of when it evicted the pod
+	Scheduling *diagnostics.SchedulingFailure `json:"scheduling,omitempty"`
+	EvictedFor string                         `json:"evicted_for,omitempty"`
 }
 
 // Issue represents a specific problem found during analysis
@@ -355,7 +366,7 @@ func (nt *TroubleshootingEngine) generatePingSteps(podInfo PodInfo, query string
 	steps = append(steps, WorkflowStep{
 		StepNumber:  2,
 		Description: "Test DNS resolution",
-		Command:     fmt.Sprintf("kubectl exec %s -n %s -- nslookup kubernetes.default.svc.cluster.local", podInfo.PodName, podInfo.Namespace),
+		Command:     fmt.Sprintf("kubectl exec %s -n %s -- nslookup %s", podInfo.PodName, podInfo.Namespace, InternalLookupName),
 		Purpose:     "Test internal DNS resolution",
 	})
 
@@ -386,14 +397,14 @@ func (nt *TroubleshootingEngine) generateDNSSteps(podInfo PodInfo, query string)
 	steps = append(steps, WorkflowStep{
 		StepNumber:  2,
 		Description: "Test internal DNS resolution",
-		Command:     fmt.Sprintf("kubectl exec %s -n %s -- nslookup kubernetes.default.svc.cluster.local", podInfo.PodName, podInfo.Namespace),
+		Command:     fmt.Sprintf("kubectl exec %s -n %s -- nslookup %s", podInfo.PodName, podInfo.Namespace, InternalLookupName),
 		Purpose:     "Test cluster internal DNS",
 	})
 
 	steps = append(steps, WorkflowStep{
 		StepNumber:  3,
 		Description: "Test external DNS resolution",
-		Command:     fmt.Sprintf("kubectl exec %s -n %s -- nslookup google.com", podInfo.PodName, podInfo.Namespace),
+		Command:     fmt.Sprintf("kubectl exec %s -n %s -- nslookup %s", podInfo.PodName, podInfo.Namespace, ExternalLookupName),
 		Purpose:     "Test external DNS resolution",
 	})
 
@@ -579,10 +590,23 @@ func (nt *TroubleshootingEngine) executeWorkflow(result *TroubleshootingResult)
 	if result.WorkflowType == "pod_diagnostics" && len(result.Commands) >= 2 {
 		nt.parsePodDiagnostics(result)
 	}
+	if result.WorkflowType == "dns" && result.PodInfo.Found && len(result.Commands) >= 3 {
+		nt.parseDNSDiagnostics(result)
+	}
 
 	return successCount > 0
 }
 
+// parseDNSDiagnostics reads the resolv.conf and lookups of the DNS workflow
+// into DNS symptoms
+func (nt *TroubleshootingEngine) parseDNSDiagnostics(result *TroubleshootingResult) {
+	lookup := func(cmd *executor.ExecutionResult) DNSLookup {
+		return DNSLookup{Output: cmd.Output + cmd.Error, Failed: cmd.ExitCode != 0}
+	}
+	symptoms := ParseDNSLookups(result.PodInfo, result.Commands[0].Output, lookup(result.Commands[1]), lookup(result.Commands[2]))
+	result.DNSSymptoms = &symptoms
+}
+
 // generateSummary generates a summary of the troubleshooting results
 func (nt *TroubleshootingEngine) generateSummary(result *TroubleshootingResult) string {
 	var lines []string
@@ -637,6 +661,21 @@ func (nt *TroubleshootingEngine) generateSummary(result *TroubleshootingResult)
 		}
 	}
 
+	if symptoms := result.DNSSymptoms; symptoms != nil {
+		switch {
+		case symptoms.InternalFailed:
+			lines = append(lines, "🚨 DNS: cluster names do not resolve from the pod")
+		case symptoms.ExternalFailed:
+			lines = append(lines, "⚠️  DNS: cluster names resolve, external names do not")
+		default:
+			lines = append(lines, "✅ DNS: cluster and external names resolve")
+		}
+		if symptoms.Failed() {
+			lines = append(lines, "   💡 Run diagnose_cluster_dns, or check_dns_telemetry with this pod, to tell cluster DNS from upstream resolver problems")
+		}
+		lines = append(lines, "")
+	}
+
 	// Summary
 	if result.Success {
 		lines = append(lines, fmt.Sprintf("🎯 Summary: %d/%d steps completed successfully", successCount, len(result.Steps)))
@@ -754,7 +793,17 @@ func (nt *TroubleshootingEngine) parsePodStatus(output string, diagnostic *Diagn
 					Severity:   "high",
 					Category:   "scheduling",
 					Actionable: true,
-					Suggestion: "Check node resources and scheduling constraints",
+					Suggestion: "Read the scheduler's reasons in the pod's FailedScheduling events",
+				})
+			case status == "Evicted":
+				diagnostic.Issues = append(diagnostic.Issues, Issue{
+					Type:       "error",
+					Source:     "status",
+					Message:    "Pod was evicted by the kubelet",
+					Severity:   "critical",
+					Category:   "eviction",
+					Actionable: true,
+					Suggestion: "Find which resource the node ran short of in the pod's status message",
 				})
 			case strings.Contains(status, "Terminating"):
 				diagnostic.Issues = append(diagnostic.Issues, Issue{
@@ -860,7 +909,17 @@ func (nt *TroubleshootingEngine) parseDescribePodOutput(output string, diagnosti
 			}
 		}
 
+		// Evicted pods explain the eviction in their status message
+		if strings.HasPrefix(trimmedLine, "Message:") && diagnostic.EvictedFor == "" {
+			if resource, ok := diagnostics.ParseEvictionMessage(trimmedLine); ok {
+				diagnostic.EvictedFor = resource
+			}
+		}
+
 		// Parse events for additional context
+		if inEvents && (nt.parseSchedulingEvent(trimmedLine, "describe", diagnostic) || nt.parseSandboxEvent(trimmedLine, "describe", diagnostic)) {
+			continue
+		}
 		if inEvents && strings.Contains(trimmedLine, "Warning") {
 			if strings.Contains(trimmedLine, "Failed") {
 				diagnostic.Issues = append(diagnostic.Issues, Issue{
@@ -885,6 +944,10 @@ func (nt *TroubleshootingEngine) parseEventsOutput(output string, diagnostic *Di
 			continue // Skip header
 		}
 
+		if nt.parseSchedulingEvent(line, "events", diagnostic) || nt.parseSandboxEvent(line, "events", diagnostic) {
+			continue
+		}
+
 		if strings.Contains(line, "Warning") || strings.Contains(line, "Error") {
 			diagnostic.Issues = append(diagnostic.Issues, Issue{
 				Type:       "warning",
@@ -899,6 +962,69 @@ func (nt *TroubleshootingEngine) parseEventsOutput(output string, diagnostic *Di
 	}
 }
 
+// parseSchedulingEvent reads a FailedScheduling event into the scheduler's
+// reasons for rejecting every node, reporting whether the line was one
+func (nt *TroubleshootingEngine) parseSchedulingEvent(line, source string, diagnostic *DiagnosticResult) bool {
+	if !strings.Contains(line, "FailedScheduling") {
+		return false
+	}
+	failure, ok := diagnostics.ParseSchedulingFailure(line)
+	if !ok {
+		return false
+	}
+	// describe and get events show the same event; keep the first
+	if diagnostic.Scheduling != nil {
+		return true
+	}
+	diagnostic.Scheduling = &failure
+	message := line
+	if i := strings.Index(line, fmt.Sprintf("%d/%d nodes", failure.Available, failure.Total)); i >= 0 {
+		message = line[i:]
+	}
+	suggestion := "Review the scheduler's reasons for rejecting each node"
+	if advice := diagnostics.SchedulingAdvice(failure); len(advice) > 0 {
+		suggestion = advice[0]
+	}
+	diagnostic.Issues = append(diagnostic.Issues, Issue{
+		Type:       "warning",
+		Source:     source,
+		Message:    "Scheduler: " + message,
+		Severity:   "high",
+		Category:   "scheduling",
+		Actionable: true,
+		Suggestion: suggestion,
+	})
+	return true
+}
+
+// parseSandboxEvent reads a FailedCreatePodSandBox event that shows a known
+// OVN-Kubernetes failure, reporting whether the line was one
+func (nt *TroubleshootingEngine) parseSandboxEvent(line, source string, diagnostic *DiagnosticResult) bool {
+	if !strings.Contains(line, "FailedCreatePodSandBox") {
+		return false
+	}
+	signature, ok := diagnostics.MatchOVNSignature(line)
+	if !ok {
+		return false
+	}
+	// describe and get events show the same event; keep the first
+	for _, issue := range diagnostic.Issues {
+		if issue.Category == "cni" {
+			return true
+		}
+	}
+	diagnostic.Issues = append(diagnostic.Issues, Issue{
+		Type:       "error",
+		Source:     source,
+		Message:    "Pod sandbox not created: " + signature.Title,
+		Severity:   "critical",
+		Category:   "cni",
+		Actionable: true,
+		Suggestion: signature.Resolution,
+	})
+	return true
+}
+
 // parseLogsOutput analyzes pod logs for errors and issues
 func (nt *TroubleshootingEngine) parseLogsOutput(output string, diagnostic *DiagnosticResult, logType string) {
 	if strings.Contains(output, "No previous logs available") {
@@ -986,20 +1112,23 @@ func (nt *TroubleshootingEngine) analyzeRootCause(diagnostic *DiagnosticResult)
 				"Consider using horizontal pod autoscaling",
 				"Review application memory optimization opportunities",
 			}
-		case "scheduling":
+		case "scheduling", "eviction":
+			nt.explainPlacement(diagnostic)
+		case "cni":
 			diagnostic.NextSteps = []string{
-				"Check if container image is accessible: kubectl describe pod <pod> -n <namespace>",
-				"Verify node has sufficient resources (CPU, memory, disk space)",
-				"Check if persistent volumes are available and accessible",
-				"Review pod events for specific error messages",
-				"Verify image pull secrets are correctly configured",
-				"Check if any admission controllers are blocking pod creation",
+				primary.Suggestion,
+				"Find the pod's node: kubectl get pod <pod> -n <namespace> -o wide",
+				"Collect OVN-Kubernetes diagnostics for that node with the collect_ovn_diagnostics tool (node=<node>)",
+				"Check the ovnkube-node pod on the node: kubectl get pods -n openshift-ovn-kubernetes -o wide --field-selector spec.nodeName=<node>",
 			}
 		}
 	} else if len(highIssues) > 0 {
 		primary := highIssues[0]
 		diagnostic.RootCause = primary.Message
 		diagnostic.Recommendation = primary.Suggestion
+		if primary.Category == "scheduling" {
+			nt.explainPlacement(diagnostic)
+		}
 	} else {
 		diagnostic.RootCause = "Multiple minor issues detected"
 		diagnostic.Recommendation = "Review all issues and address systematically"
@@ -1014,6 +1143,58 @@ func (nt *TroubleshootingEngine) analyzeRootCause(diagnostic *DiagnosticResult)
 	}
 }
 
+// explainPlacement replaces the root cause of a Pending or evicted pod with
+// the scheduler's reasons or the resource the node ran short of
+func (nt *TroubleshootingEngine) explainPlacement(diagnostic *DiagnosticResult) {
+	capacityStep := "Run analyze_node_capacity for the namespace to compare each node's requested and allocatable resources and eviction headroom"
+	switch {
+	case diagnostic.EvictedFor == "memory":
+		diagnostic.RootCause = "The node ran low on memory and the kubelet evicted the pod"
+		diagnostic.Recommendation = "Set the pod's memory request to its real usage; pods using more than they request are evicted first"
+		diagnostic.NextSteps = []string{
+			"Compare the pod's memory usage with its request: top_pods sorted by memory",
+			capacityStep,
+			"Delete the evicted pod once its controller has replaced it: kubectl delete pod <pod> -n <namespace>",
+		}
+	case diagnostic.EvictedFor != "":
+		diagnostic.RootCause = fmt.Sprintf("The node ran low on %s and the kubelet evicted the pod", diagnostic.EvictedFor)
+		diagnostic.Recommendation = "Set ephemeral-storage requests and limits on pods that write to local disk, and free disk on the node"
+		diagnostic.NextSteps = []string{
+			"Check the node's disk usage: oc debug node/<node> -- chroot /host df -h",
+			capacityStep,
+		}
+	case diagnostic.Scheduling != nil:
+		failure := diagnostic.Scheduling
+		var reasons []string
+		for _, reason := range failure.Reasons {
+			if reason.Nodes > 0 {
+				reasons = append(reasons, fmt.Sprintf("%d %s", reason.Nodes, reason.Detail))
+			} else {
+				reasons = append(reasons, reason.Detail)
+			}
+		}
+		diagnostic.RootCause = fmt.Sprintf("No node can take the pod (%d/%d available): %s", failure.Available, failure.Total, strings.Join(reasons, ", "))
+		diagnostic.NextSteps = diagnostics.SchedulingAdvice(*failure)
+		if len(diagnostic.NextSteps) > 0 {
+			diagnostic.Recommendation = diagnostic.NextSteps[0]
+		}
+		if failure.Has(diagnostics.SchedulingInsufficientCPU) || failure.Has(diagnostics.SchedulingInsufficientMemory) {
+			diagnostic.NextSteps = append(diagnostic.NextSteps, capacityStep)
+		}
+	case diagnostic.PodStatus == "Evicted":
+		diagnostic.NextSteps = []string{
+			"Read the eviction reason in the pod's status message: kubectl describe pod <pod> -n <namespace>",
+			capacityStep,
+		}
+	default:
+		diagnostic.NextSteps = []string{
+			"Look for FailedScheduling, FailedMount or Failed pull events: kubectl get events -n <namespace> --field-selector involvedObject.name=<pod>",
+			capacityStep,
+			"Check that the pod's PersistentVolumeClaims are Bound",
+		}
+	}
+}
+
 // formatDiagnosticResult formats the diagnostic analysis for display
 func (nt *TroubleshootingEngine) formatDiagnosticResult(diagnostic *DiagnosticResult) string {
 	var lines []string
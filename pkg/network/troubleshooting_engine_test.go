@@ -257,6 +257,103 @@ func TestRootCauseAnalysis(t *testing.T) {
 	}
 }
 
+func TestPendingRootCauseAnalysis(t *testing.T) {
+	engine := NewTroubleshootingEngine()
+	diagnostic := DiagnosticResult{
+		Issues:    make([]Issue, 0),
+		NextSteps: make([]string, 0),
+	}
+
+	engine.parsePodStatus(`NAME    READY   STATUS    RESTARTS   AGE
+web-1   0/1     Pending   0          3m`, &diagnostic)
+	engine.parseDescribePodOutput(`Name:         web-1
+Status:       Pending
+Events:
+  Type     Reason            Age   From               Message
+  ----     ------            ----  ----               -------
+  Warning  FailedScheduling  3m    default-scheduler  0/5 nodes are available: 2 Insufficient memory, 3 node(s) had untolerated taint {node-role.kubernetes.io/master: }. preemption: 0/5 nodes are available: 5 Preemption is not helpful for scheduling.`, &diagnostic)
+	engine.parseEventsOutput(`LAST SEEN   TYPE      REASON             OBJECT      MESSAGE
+3m          Warning   FailedScheduling   pod/web-1   0/5 nodes are available: 2 Insufficient memory, 3 node(s) had untolerated taint {node-role.kubernetes.io/master: }.`, &diagnostic)
+	engine.analyzeRootCause(&diagnostic)
+
+	scheduling := 0
+	for _, issue := range diagnostic.Issues {
+		if issue.Category == "scheduling" {
+			scheduling++
+		}
+	}
+	if scheduling != 2 {
+		t.Errorf("Expected the Pending status and one FailedScheduling issue, got %+v", diagnostic.Issues)
+	}
+	if !strings.Contains(diagnostic.RootCause, "3 node(s) had untolerated taint") || !strings.Contains(diagnostic.RootCause, "2 Insufficient memory") {
+		t.Errorf("Expected the scheduler's reasons as root cause, got %s", diagnostic.RootCause)
+	}
+	if !strings.Contains(diagnostic.Recommendation, "toleration") {
+		t.Errorf("Expected the taint advice first, got %s", diagnostic.Recommendation)
+	}
+	if last := diagnostic.NextSteps[len(diagnostic.NextSteps)-1]; !strings.Contains(last, "analyze_node_capacity") {
+		t.Errorf("Expected a pointer to analyze_node_capacity for insufficient memory, got %v", diagnostic.NextSteps)
+	}
+}
+
+func TestEvictedRootCauseAnalysis(t *testing.T) {
+	engine := NewTroubleshootingEngine()
+	diagnostic := DiagnosticResult{
+		Issues:    make([]Issue, 0),
+		NextSteps: make([]string, 0),
+	}
+
+	engine.parsePodStatus(`NAME      READY   STATUS    RESTARTS   AGE
+cache-1   0/1     Evicted   0          1h`, &diagnostic)
+	engine.parseDescribePodOutput(`Name:         cache-1
+Status:       Failed
+Reason:       Evicted
+Message:      The node was low on resource: memory. Threshold quantity: 100Mi, available: 51200Ki.`, &diagnostic)
+	engine.analyzeRootCause(&diagnostic)
+
+	if diagnostic.EvictedFor != "memory" {
+		t.Errorf("Expected the eviction to be for memory, got %q", diagnostic.EvictedFor)
+	}
+	if !strings.Contains(diagnostic.RootCause, "low on memory") || !strings.Contains(diagnostic.Recommendation, "memory request") {
+		t.Errorf("Expected a memory eviction explanation, got %s / %s", diagnostic.RootCause, diagnostic.Recommendation)
+	}
+}
+
+func TestSandboxRootCauseAnalysis(t *testing.T) {
+	engine := NewTroubleshootingEngine()
+	diagnostic := DiagnosticResult{
+		Issues:    make([]Issue, 0),
+		NextSteps: make([]string, 0),
+	}
+
+	event := `Warning  FailedCreatePodSandBox  2m  kubelet  Failed to create pod sandbox: rpc error: code = Unknown desc = failed to add network: [default/web-1 c0ffee] failed to get pod annotation: timed out waiting for annotations: context deadline exceeded`
+	engine.parseDescribePodOutput("Name:         web-1\nEvents:\n  "+event, &diagnostic)
+	engine.parseEventsOutput("LAST SEEN   TYPE      REASON   OBJECT   MESSAGE\n2m   "+event, &diagnostic)
+	engine.analyzeRootCause(&diagnostic)
+
+	cni := 0
+	for _, issue := range diagnostic.Issues {
+		if issue.Category == "cni" {
+			cni++
+		}
+	}
+	if cni != 1 {
+		t.Errorf("Expected one CNI issue, got %d: %+v", cni, diagnostic.Issues)
+	}
+	if !strings.Contains(diagnostic.RootCause, "Pod sandbox not created") {
+		t.Errorf("Expected a sandbox root cause, got %q", diagnostic.RootCause)
+	}
+	found := false
+	for _, step := range diagnostic.NextSteps {
+		if strings.Contains(step, "collect_ovn_diagnostics") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected collect_ovn_diagnostics in the next steps, got %v", diagnostic.NextSteps)
+	}
+}
+
 func TestGeneratePodDiagnosticsSteps(t *testing.T) {
 	engine := NewTroubleshootingEngine()
 
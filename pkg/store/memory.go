@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time // zero never expires
+}
+
+// MemoryStore keeps state in the process; replicas do not share it
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time // overridden in tests
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// lookup returns a live entry, dropping it once expired; callers hold m.mu
+func (m *MemoryStore) lookup(key string) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if ok && !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, ok
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+func (m *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expires = m.now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *MemoryStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.entries {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if _, ok := m.lookup(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *MemoryStore) Lock(ctx context.Context, name string, ttl time.Duration) (func(), bool, error) {
+	key := lockKey(name)
+	token := NewID("")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, held := m.lookup(key); held {
+		return nil, false, nil
+	}
+	m.entries[key] = memoryEntry{value: []byte(token), expires: m.now().Add(ttl)}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if entry, ok := m.lookup(key); ok && string(entry.value) == token {
+			delete(m.entries, key)
+		}
+	}, true, nil
+}
+
+func (m *MemoryStore) Backend() string {
+	return BackendMemory
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
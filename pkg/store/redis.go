@@ -0,0 +1,368 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultRedisDialTimeout = 5 * time.Second
+	// redisIdleConns bounds the connections kept open between commands
+	redisIdleConns = 8
+	// redisScanCount is the SCAN page size hint
+	redisScanCount = 200
+)
+
+// redisUnlockScript deletes a lock only while it still holds the caller's
+// token, so a holder whose lock expired cannot release the next holder's
+const redisUnlockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a connection with its buffered reader
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// RedisStore keeps state in Redis, speaking the RESP protocol directly. Keys
+// are prefixed with the configured KeyPrefix so several deployments can share
+// a database.
+type RedisStore struct {
+	config  Config
+	address string      // host:port
+	tls     *tls.Config // set for rediss:// addresses
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// parseRedisAddress accepts host:port, redis://host:port or, for TLS,
+// rediss://host:port
+func parseRedisAddress(address string) (string, bool, error) {
+	if !strings.Contains(address, "://") {
+		return address, false, nil
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid redis address %q: %v", address, err)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	switch u.Scheme {
+	case "redis":
+		return u.Host, false, nil
+	case "rediss":
+		return u.Host, true, nil
+	}
+	return "", false, fmt.Errorf("invalid redis address %q: use redis:// or rediss://", address)
+}
+
+// redisTLSConfig verifies the server against the system roots, or only the
+// CAs in caFile when set, e.g. the service CA of an in-cluster Redis
+func redisTLSConfig(address, caFile string) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading the redis CA file: %v", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in the redis CA file %s", caFile)
+		}
+	}
+	return config, nil
+}
+
+// NewRedisStore connects to Redis and checks the connection with PING
+func NewRedisStore(config Config) (*RedisStore, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("the redis store backend needs an address")
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = defaultRedisDialTimeout
+	}
+	address, useTLS, err := parseRedisAddress(config.Address)
+	if err != nil {
+		return nil, err
+	}
+	r := &RedisStore{config: config, address: address}
+	if useTLS {
+		if r.tls, err = redisTLSConfig(address, config.CAFile); err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.DialTimeout)
+	defer cancel()
+	if _, err := r.do(ctx, "PING"); err != nil {
+		return nil, fmt.Errorf("connecting to redis at %s: %v", config.Address, err)
+	}
+	return r, nil
+}
+
+// dial opens a connection, authenticating and selecting the database
+func (r *RedisStore) dial(ctx context.Context) (*redisConn, error) {
+	dialer := &net.Dialer{Timeout: r.config.DialTimeout}
+	var conn net.Conn
+	var err error
+	if r.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: r.tls}).DialContext(ctx, "tcp", r.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", r.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{Conn: conn, reader: bufio.NewReader(conn)}
+	var setup [][]string
+	if r.config.Password != "" {
+		setup = append(setup, []string{"AUTH", r.config.Password})
+	}
+	if r.config.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.config.DB)})
+	}
+	for _, args := range setup {
+		if _, err := c.roundTrip(ctx, args); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s: %v", args[0], err)
+		}
+	}
+	return c, nil
+}
+
+// do runs one command on a pooled connection
+func (r *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	r.mu.Lock()
+	var conn *redisConn
+	if n := len(r.idle); n > 0 {
+		conn = r.idle[n-1]
+		r.idle = r.idle[:n-1]
+	}
+	r.mu.Unlock()
+	if conn == nil {
+		var err error
+		if conn, err = r.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := conn.roundTrip(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection may be out of step with the server; drop it
+		conn.Close()
+		return nil, err
+	}
+	r.mu.Lock()
+	if len(r.idle) < redisIdleConns {
+		r.idle = append(r.idle, conn)
+		conn = nil
+	}
+	r.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+	return reply, err
+}
+
+// roundTrip writes a command and reads its reply
+func (c *redisConn) roundTrip(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultRedisDialTimeout)
+	}
+	c.SetDeadline(deadline)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// readReply parses one RESP reply: a string, an int64, nil, a []interface{}
+// or a redisError
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nullReply(line)
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nullReply(line)
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+// nullReply reads a negative length: -1 is the null bulk string or array,
+// any other is a protocol error
+func nullReply(line string) (interface{}, error) {
+	if line[1:] != "-1" {
+		return nil, fmt.Errorf("redis: invalid length in reply %q", line)
+	}
+	return nil, nil
+}
+
+// globEscaper escapes a key prefix for SCAN MATCH
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+func (r *RedisStore) key(key string) string {
+	return r.config.KeyPrefix + key
+}
+
+func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", r.key(key))
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return []byte(value), true, nil
+}
+
+func (r *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", r.key(key), string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := r.do(ctx, args...)
+	return err
+}
+
+func (r *RedisStore) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", r.key(key))
+	return err
+}
+
+func (r *RedisStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	pattern := globEscaper.Replace(r.key(prefix)) + "*"
+	seen := make(map[string]bool)
+	cursor := "0"
+	for {
+		reply, err := r.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("redis: unexpected SCAN reply %v", reply)
+		}
+		cursor, _ = page[0].(string)
+		names, _ := page[1].([]interface{})
+		for _, name := range names {
+			if key, ok := name.(string); ok {
+				seen[strings.TrimPrefix(key, r.config.KeyPrefix)] = true
+			}
+		}
+		if cursor == "0" || cursor == "" {
+			break
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (r *RedisStore) Lock(ctx context.Context, name string, ttl time.Duration) (func(), bool, error) {
+	key := r.key(lockKey(name))
+	token := NewID("")
+	reply, err := r.do(ctx, "SET", key, token, "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), r.config.DialTimeout)
+		defer cancel()
+		if _, err := r.do(ctx, "EVAL", redisUnlockScript, "1", key, token); err != nil {
+			// The lock is held until its TTL expires
+			logrus.WithError(err).Warnf("Failed to release lock %s", name)
+		}
+	}, true, nil
+}
+
+func (r *RedisStore) Backend() string {
+	return BackendRedis
+}
+
+// Close closes the idle connections
+func (r *RedisStore) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, conn := range r.idle {
+		conn.Close()
+	}
+	r.idle = nil
+	return nil
+}
@@ -0,0 +1,122 @@
+// Package store is the state backend shared by API replicas: chat and
+// wizard sessions, approval tokens, job state and distributed locks. The
+// memory backend keeps state in the process, which suits a single replica;
+// the redis backend lets replicas scale horizontally behind a route without
+// sticky sessions.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// lockPollInterval is how often Acquire retries a held lock
+var lockPollInterval = 50 * time.Millisecond
+
+// Store keeps values by key. Keys are plain strings such as
+// "chat/session/abc"; backends may prefix them.
+type Store interface {
+	// Get returns a value and whether it exists
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set writes a value that expires after ttl, or never when ttl is 0
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// Keys returns the keys starting with prefix, sorted
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// Lock takes a named lock for ttl unless another holder has it. The
+	// returned release frees the lock only while this caller still holds it.
+	Lock(ctx context.Context, name string, ttl time.Duration) (release func(), ok bool, err error)
+	// Backend names the implementation, e.g. memory or redis
+	Backend() string
+	Close() error
+}
+
+// Config selects and configures the backend
+type Config struct {
+	Backend     string        // memory (default) or redis
+	Address     string        // redis host:port, redis://host:port or rediss://host:port for TLS
+	CAFile      string        // CAs that sign a rediss server's certificate; default the system roots
+	Password    string        // redis AUTH password
+	DB          int           // redis database number
+	KeyPrefix   string        // prepended to every redis key
+	DialTimeout time.Duration // default 5s
+}
+
+// New opens the configured backend
+func New(config Config) (Store, error) {
+	switch config.Backend {
+	case "", BackendMemory:
+		return NewMemoryStore(), nil
+	case BackendRedis:
+		return NewRedisStore(config)
+	default:
+		return nil, fmt.Errorf("unknown store backend %q: use memory or redis", config.Backend)
+	}
+}
+
+// Shared reports whether a store is visible to other replicas
+func Shared(s Store) bool {
+	return s != nil && s.Backend() != BackendMemory
+}
+
+// GetJSON decodes a JSON value into v, reporting whether it exists
+func GetJSON(ctx context.Context, s Store, key string, v interface{}) (bool, error) {
+	data, ok, err := s.Get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("decoding %s: %v", key, err)
+	}
+	return true, nil
+}
+
+// SetJSON encodes v as JSON and writes it
+func SetJSON(ctx context.Context, s Store, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %v", key, err)
+	}
+	return s.Set(ctx, key, data, ttl)
+}
+
+// Acquire waits until it holds a named lock or ctx is done. Use it around
+// read-modify-write updates that replicas may make at the same time.
+func Acquire(ctx context.Context, s Store, name string, ttl time.Duration) (func(), error) {
+	for {
+		release, ok, err := s.Lock(ctx, name, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return release, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for lock %s: %v", name, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// NewID returns a random identifier that is unique across replicas
+func NewID(prefix string) string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// lockKey is where a named lock is kept
+func lockKey(name string) string {
+	return "lock/" + name
+}
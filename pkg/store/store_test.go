@@ -0,0 +1,320 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves the commands RedisStore sends from a MemoryStore
+type fakeRedis struct {
+	data     *MemoryStore
+	password string
+}
+
+func startFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serveFakeRedis(t, listener, password)
+}
+
+func serveFakeRedis(t *testing.T, listener net.Listener, password string) (*fakeRedis, string) {
+	t.Cleanup(func() { listener.Close() })
+	f := &fakeRedis{data: NewMemoryStore(), password: password}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, listener.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if args[0] == "AUTH" {
+			authed = args[1] == f.password
+		}
+		if !authed {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		fmt.Fprint(conn, f.execute(args))
+	}
+}
+
+func bulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func (f *fakeRedis) execute(args []string) string {
+	ctx := context.Background()
+	switch args[0] {
+	case "AUTH", "SELECT", "PING":
+		return "+OK\r\n"
+	case "GET":
+		value, ok, _ := f.data.Get(ctx, args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(string(value))
+	case "SET":
+		var ttl time.Duration
+		nx := false
+		for i := 3; i < len(args); i++ {
+			switch args[i] {
+			case "NX":
+				nx = true
+			case "PX":
+				fmt.Sscan(args[i+1], &ttl)
+				ttl *= time.Millisecond
+				i++
+			}
+		}
+		if _, exists, _ := f.data.Get(ctx, args[1]); nx && exists {
+			return "$-1\r\n"
+		}
+		f.data.Set(ctx, args[1], []byte(args[2]), ttl)
+		return "+OK\r\n"
+	case "DEL":
+		f.data.Delete(ctx, args[1])
+		return ":1\r\n"
+	case "SCAN":
+		// RedisStore only matches prefixes: an escaped prefix and a *
+		prefix := strings.NewReplacer(`\\`, `\`, `\*`, `*`, `\?`, `?`, `\[`, `[`, `\]`, `]`).Replace(strings.TrimSuffix(args[3], "*"))
+		keys, _ := f.data.Keys(ctx, prefix)
+		var matched []string
+		for _, key := range keys {
+			matched = append(matched, bulk(key))
+		}
+		return fmt.Sprintf("*2\r\n%s*%d\r\n%s", bulk("0"), len(matched), strings.Join(matched, ""))
+	case "EVAL":
+		if args[1] != redisUnlockScript {
+			return "-ERR unknown script\r\n"
+		}
+		if value, ok, _ := f.data.Get(ctx, args[3]); ok && string(value) == args[4] {
+			f.data.Delete(ctx, args[3])
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func TestStores(t *testing.T) {
+	_, address := startFakeRedis(t, "s3cret")
+	redis, err := New(Config{Backend: BackendRedis, Address: address, Password: "s3cret", DB: 2, KeyPrefix: "mcp:"})
+	if err != nil {
+		t.Fatalf("New(redis) error = %v", err)
+	}
+	defer redis.Close()
+	memory, _ := New(Config{})
+
+	ctx := context.Background()
+	for _, s := range []Store{memory, redis} {
+		name := s.Backend()
+		if err := s.Set(ctx, "chat/session/a", []byte("one"), 0); err != nil {
+			t.Fatalf("%s: Set() error = %v", name, err)
+		}
+		s.Set(ctx, "chat/session/b", []byte("two"), time.Hour)
+		s.Set(ctx, "wizard/x", []byte("three"), 0)
+		if value, ok, err := s.Get(ctx, "chat/session/a"); err != nil || !ok || string(value) != "one" {
+			t.Errorf("%s: Get() = %q, %v, %v", name, value, ok, err)
+		}
+		if _, ok, err := s.Get(ctx, "chat/session/missing"); err != nil || ok {
+			t.Errorf("%s: Get(missing) = %v, %v", name, ok, err)
+		}
+		if keys, err := s.Keys(ctx, "chat/"); err != nil || !reflect.DeepEqual(keys, []string{"chat/session/a", "chat/session/b"}) {
+			t.Errorf("%s: Keys(chat/) = %v, %v", name, keys, err)
+		}
+		s.Delete(ctx, "chat/session/a")
+		if _, ok, _ := s.Get(ctx, "chat/session/a"); ok {
+			t.Errorf("%s: Get() found a deleted key", name)
+		}
+
+		var state struct{ Node string }
+		SetJSON(ctx, s, "wizard/y", map[string]string{"Node": "crashloop"}, 0)
+		if ok, err := GetJSON(ctx, s, "wizard/y", &state); !ok || err != nil || state.Node != "crashloop" {
+			t.Errorf("%s: GetJSON() = %+v, %v, %v", name, state, ok, err)
+		}
+
+		release, ok, err := s.Lock(ctx, "must-gather", time.Minute)
+		if err != nil || !ok {
+			t.Fatalf("%s: Lock() = %v, %v", name, ok, err)
+		}
+		if _, ok, _ := s.Lock(ctx, "must-gather", time.Minute); ok {
+			t.Errorf("%s: a held lock was taken again", name)
+		}
+		release()
+		release2, ok, _ := s.Lock(ctx, "must-gather", time.Minute)
+		if !ok {
+			t.Errorf("%s: a released lock could not be taken", name)
+		}
+		// A stale release cannot free the next holder's lock
+		release()
+		if _, ok, _ := s.Lock(ctx, "must-gather", time.Minute); ok {
+			t.Errorf("%s: a stale release freed the next holder's lock", name)
+		}
+		release2()
+
+		waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		s.Lock(ctx, "busy", time.Minute)
+		if _, err := Acquire(waitCtx, s, "busy", time.Minute); err == nil || !strings.Contains(err.Error(), "waiting for lock busy") {
+			t.Errorf("%s: Acquire() of a held lock error = %v", name, err)
+		}
+		cancel()
+	}
+	if Shared(memory) || !Shared(redis) {
+		t.Errorf("Shared() = %v for memory and %v for redis", Shared(memory), Shared(redis))
+	}
+}
+
+func TestRedisKeyPrefixAndAuth(t *testing.T) {
+	fake, address := startFakeRedis(t, "s3cret")
+	if _, err := New(Config{Backend: BackendRedis, Address: address, Password: "wrong"}); err == nil || !strings.Contains(err.Error(), "NOAUTH") {
+		t.Errorf("New() with a wrong password error = %v", err)
+	}
+
+	s, err := NewRedisStore(Config{Address: address, Password: "s3cret", KeyPrefix: "team[a]:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s.Set(ctx, "k", []byte("v"), 0)
+	if _, ok, _ := fake.data.Get(ctx, "team[a]:k"); !ok {
+		t.Error("the key prefix was not applied")
+	}
+	fake.data.Set(ctx, "other:k", []byte("v"), 0)
+	if keys, _ := s.Keys(ctx, ""); !reflect.DeepEqual(keys, []string{"k"}) {
+		t.Errorf("Keys() = %v, expected only this prefix's keys", keys)
+	}
+
+	if _, err := New(Config{Backend: "postgres"}); err == nil {
+		t.Error("New() accepted an unknown backend")
+	}
+}
+
+func TestRedisTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake, address := serveFakeRedis(t, listener, "s3cret")
+	_, port, _ := net.SplitHostPort(address)
+
+	s, err := New(Config{Backend: BackendRedis, Address: "rediss://localhost:" + port, CAFile: caFile, Password: "s3cret"})
+	if err != nil {
+		t.Fatalf("New(rediss) error = %v", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	s.Set(ctx, "k", []byte("v"), 0)
+	if value, ok, _ := fake.data.Get(ctx, "k"); !ok || string(value) != "v" {
+		t.Error("the value was not written over TLS")
+	}
+
+	// Without the CA the server's certificate is not trusted
+	if _, err := New(Config{Backend: BackendRedis, Address: "rediss://localhost:" + port, Password: "s3cret"}); err == nil {
+		t.Error("New(rediss) trusted a certificate from an unknown CA")
+	}
+	if _, err := New(Config{Backend: BackendRedis, Address: "http://localhost:" + port}); err == nil {
+		t.Error("New() accepted an http:// address")
+	}
+}
+
+func TestReadReplyLengths(t *testing.T) {
+	tests := []struct {
+		reply    string
+		expected interface{}
+		valid    bool
+	}{
+		{"$-1\r\n", nil, true},
+		{"*-1\r\n", nil, true},
+		{"$0\r\n\r\n", "", true},
+		{"*0\r\n", []interface{}{}, true},
+		{"$-2\r\n", nil, false},
+		{"*-5\r\n", nil, false},
+		{"$-01\r\n", nil, false},
+	}
+
+	for _, tt := range tests {
+		reply, err := readReply(bufio.NewReader(strings.NewReader(tt.reply)))
+		if (err == nil) != tt.valid || !reflect.DeepEqual(reply, tt.expected) {
+			t.Errorf("readReply(%q) = %#v, %v, expected %#v, valid %v", tt.reply, reply, err, tt.expected, tt.valid)
+		}
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	s := NewMemoryStore()
+	clock := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	s.Set(ctx, "token", []byte("x"), time.Minute)
+	s.Lock(ctx, "sync", time.Minute)
+	clock = clock.Add(2 * time.Minute)
+	if _, ok, _ := s.Get(ctx, "token"); ok {
+		t.Error("an expired value was returned")
+	}
+	if _, ok, _ := s.Lock(ctx, "sync", time.Minute); !ok {
+		t.Error("an expired lock could not be taken")
+	}
+}
@@ -0,0 +1,182 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rakeshkumarmallam/openshift-mcp-go/internal/config"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/api"
+	"github.com/rakeshkumarmallam/openshift-mcp-go/pkg/diagnostics"
+)
+
+// callTool runs a registered tool and returns its text
+func callTool(ctx context.Context, name string, args map[string]interface{}) (string, error) {
+	request := mcplib.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := env.server.CallTool(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcplib.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	if result.IsError {
+		return "", fmt.Errorf("%s failed: %s", name, text.String())
+	}
+	return text.String(), nil
+}
+
+func TestOpenShiftDiagnose(t *testing.T) {
+	for _, name := range []string{"crashloop", "imagepull", "missing-config", "unschedulable"} {
+		t.Run(name, func(t *testing.T) {
+			sc := scenarioNamed(t, name)
+			env.waitFor(t, sc)
+			eventuallyGolden(t, "diagnose-"+name, func(ctx context.Context) (string, error) {
+				text, err := callTool(ctx, "openshift_diagnose", map[string]interface{}{
+					"resource_type": "pod",
+					"resource_name": sc.pod.Name,
+					"namespace":     sc.namespace,
+				})
+				return normalize(text), err
+			})
+		})
+	}
+}
+
+// logFindings is the part of a log analysis the golden file pins down
+type logFindings struct {
+	Summary         string       `json:"summary"`
+	Issues          []logFinding `json:"issues"`
+	Recommendations []string     `json:"recommendations"`
+}
+
+type logFinding struct {
+	Severity    string `json:"severity"`
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Occurrences string `json:"occurrences,omitempty"`
+}
+
+func TestAnalyzeLogs(t *testing.T) {
+	sc := scenarioNamed(t, "crashloop")
+	env.waitFor(t, sc)
+	ctx := context.Background()
+
+	logs, err := env.client.CoreV1().Pods(sc.namespace).GetLogs(sc.pod.Name, &corev1.PodLogOptions{Container: "app", Previous: true}).DoRaw(ctx)
+	if err != nil {
+		t.Fatalf("reading the crashed container's logs: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "payments.log")
+	if err := os.WriteFile(path, logs, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The tool must read what the cluster returned...
+	if _, err := callTool(ctx, "analyze_logs", map[string]interface{}{"log_path": path}); err != nil {
+		t.Fatal(err)
+	}
+
+	// ...and the findings behind its report must not drift
+	result, err := diagnostics.NewAnalysisEngine(logrus.StandardLogger()).AnalyzeLogs(ctx, path)
+	if err != nil {
+		t.Fatalf("AnalyzeLogs() error = %v", err)
+	}
+	findings := logFindings{Summary: normalize(result.Summary), Recommendations: result.Recommendations}
+	for _, issue := range result.Issues {
+		findings.Issues = append(findings.Issues, logFinding{
+			Severity:    issue.Severity,
+			Category:    issue.Category,
+			Title:       issue.Title,
+			Occurrences: issue.Metadata["occurrences"],
+		})
+	}
+	sort.SliceStable(findings.Issues, func(i, j int) bool { return findings.Issues[i].Title < findings.Issues[j].Title })
+	assertGolden(t, "analyze-logs-crashloop", toJSON(t, findings))
+}
+
+// chatOutcome is the part of an enhanced chat response the golden file pins down
+type chatOutcome struct {
+	Completed bool       `json:"completed"`
+	Steps     []chatStep `json:"steps"`
+}
+
+type chatStep struct {
+	Tool       string                 `json:"tool"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Success    bool                   `json:"success"`
+	ErrorKind  string                 `json:"error_kind,omitempty"`
+	Findings   []string               `json:"findings,omitempty"`
+}
+
+// findingMarkers start the lines of a step result that state a diagnosis
+var findingMarkers = []string{"🐛", "🔄", "❌", "🔧 Fix"}
+
+func stepFindings(result string) []string {
+	var findings []string
+	for _, line := range strings.Split(normalize(result), "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range findingMarkers {
+			if strings.HasPrefix(line, marker) {
+				findings = append(findings, line)
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func TestEnhancedChat(t *testing.T) {
+	env.waitFor(t, scenarioNamed(t, "chat"))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// No LLM is configured, so plans come from the built-in planner
+	api.NewEnhancedChatHandler(env.server, &config.Config{}).RegisterRoutes(router)
+
+	eventuallyGolden(t, "chat-fix-failing-pod", func(ctx context.Context) (string, error) {
+		body, _ := json.Marshal(map[string]interface{}{"prompt": "Fix the failing pod in the debugger namespace", "persona": "sre"})
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/chat/enhanced", bytes.NewReader(body)).WithContext(ctx)
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			return "", fmt.Errorf("chat returned %d: %s", recorder.Code, recorder.Body.String())
+		}
+
+		var response api.EnhancedChatResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			return "", err
+		}
+		outcome := chatOutcome{Completed: response.Completed}
+		for _, step := range response.Steps {
+			outcome.Steps = append(outcome.Steps, chatStep{
+				Tool:       step.ToolUsed,
+				Parameters: step.Parameters,
+				Success:    step.Success,
+				ErrorKind:  step.ErrorKind,
+				Findings:   stepFindings(step.Result),
+			})
+		}
+		return toJSON(t, outcome), nil
+	})
+}
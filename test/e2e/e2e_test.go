@@ -0,0 +1,168 @@
+//go:build e2e
+
+// Package e2e runs the diagnosis tools against a real cluster seeded with
+// broken workloads and compares their output with golden files.
+//
+//	go test -tags e2e ./test/e2e/                      # kind cluster, created if needed
+//	go test -tags e2e ./test/e2e/ -cluster crc         # running CRC instance
+//	go test -tags e2e ./test/e2e/ -cluster existing    # whatever KUBECONFIG points at
+//	go test -tags e2e ./test/e2e/ -update              # rewrite the golden files
+package e2e
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	mcpserver "github.com/rakeshkumarmallam/openshift-mcp-go/pkg/mcp"
+)
+
+const kindClusterName = "openshift-mcp-e2e"
+
+var (
+	update      = flag.Bool("update", false, "rewrite the golden files with the current output")
+	clusterKind = flag.String("cluster", envOr("E2E_CLUSTER", "kind"), "cluster to test against: kind, crc or existing")
+	keep        = flag.Bool("keep", false, "leave the kind cluster and the seeded namespaces in place")
+)
+
+// env is the cluster and server every test runs against
+var env *testEnv
+
+type testEnv struct {
+	kubeconfig string
+	client     kubernetes.Interface
+	server     *mcpserver.Server
+	teardown   []func()
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	logrus.SetOutput(io.Discard)
+
+	var err error
+	env, err = setup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e setup failed: %v\n", err)
+		if env != nil {
+			env.close()
+		}
+		os.Exit(1)
+	}
+	code := m.Run()
+	env.close()
+	os.Exit(code)
+}
+
+func setup() (*testEnv, error) {
+	e := &testEnv{}
+	kubeconfig, err := e.clusterKubeconfig()
+	if err != nil {
+		return e, err
+	}
+	e.kubeconfig = kubeconfig
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return e, fmt.Errorf("loading %s: %v", kubeconfig, err)
+	}
+	e.client, err = kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return e, err
+	}
+	e.server = mcpserver.NewServer(&mcpserver.Config{Profile: "sre"}, kubeconfig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	if err := e.seed(ctx); err != nil {
+		return e, fmt.Errorf("seeding scenarios: %v", err)
+	}
+	return e, nil
+}
+
+// clusterKubeconfig returns the kubeconfig of the cluster under test,
+// creating the kind cluster when it does not exist yet
+func (e *testEnv) clusterKubeconfig() (string, error) {
+	switch *clusterKind {
+	case "kind":
+		return e.kindKubeconfig()
+	case "crc":
+		if path := os.Getenv("KUBECONFIG"); path != "" {
+			return path, nil
+		}
+		home, _ := os.UserHomeDir()
+		path := filepath.Join(home, ".crc", "machines", "crc", "kubeconfig")
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("CRC kubeconfig not found at %s; is CRC running (crc start)?", path)
+		}
+		return path, nil
+	case "existing":
+		if path := os.Getenv("KUBECONFIG"); path != "" {
+			return path, nil
+		}
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".kube", "config"), nil
+	}
+	return "", fmt.Errorf("unknown cluster %q: use kind, crc or existing", *clusterKind)
+}
+
+func (e *testEnv) kindKubeconfig() (string, error) {
+	if _, err := exec.LookPath("kind"); err != nil {
+		return "", fmt.Errorf("kind is not installed; install it or run with -cluster crc|existing")
+	}
+	dir, err := os.MkdirTemp("", "openshift-mcp-e2e")
+	if err != nil {
+		return "", err
+	}
+	e.teardown = append(e.teardown, func() { os.RemoveAll(dir) })
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+
+	clusters, err := exec.Command("kind", "get", "clusters").Output()
+	if err != nil {
+		return "", fmt.Errorf("kind get clusters: %v", err)
+	}
+	if !strings.Contains("\n"+string(clusters), "\n"+kindClusterName+"\n") {
+		create := exec.Command("kind", "create", "cluster", "--name", kindClusterName, "--kubeconfig", kubeconfig, "--wait", "3m")
+		create.Stdout, create.Stderr = os.Stderr, os.Stderr
+		if err := create.Run(); err != nil {
+			return "", fmt.Errorf("kind create cluster: %v", err)
+		}
+		if !*keep {
+			e.teardown = append(e.teardown, func() {
+				exec.Command("kind", "delete", "cluster", "--name", kindClusterName).Run()
+			})
+		}
+		return kubeconfig, nil
+	}
+
+	// Reuse a cluster left by an earlier -keep run
+	config, err := exec.Command("kind", "get", "kubeconfig", "--name", kindClusterName).Output()
+	if err != nil {
+		return "", fmt.Errorf("kind get kubeconfig: %v", err)
+	}
+	return kubeconfig, os.WriteFile(kubeconfig, config, 0600)
+}
+
+// close runs the teardown steps in reverse order
+func (e *testEnv) close() {
+	for i := len(e.teardown) - 1; i >= 0; i-- {
+		e.teardown[i]()
+	}
+}
@@ -0,0 +1,154 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// volatile rewrites the parts of tool output that change from run to run or
+// cluster to cluster, in order
+var volatile = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<uid>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`\b\d+(\.\d+)?(ns|µs|ms|s|m|h)\b|\b(\d+h)?(\d+m)?\d+(\.\d+)?s\b`), "<duration>"},
+	{regexp.MustCompile(`\b\d+/\d+ nodes are available`), "<n>/<n> nodes are available"},
+	{regexp.MustCompile(`\b\d+ (Insufficient \w+|node\(s\)|No preemption)`), "<n> $1"},
+	// Pull errors depend on the runtime and back-off messages on timing
+	{regexp.MustCompile(`(Waiting: (?:ImagePullBackOff|ErrImagePull|CrashLoopBackOff)) - .*`), "$1 - <message>"},
+}
+
+// normalize makes output comparable across runs: volatile values are
+// replaced and lines repeated for each copy of an event are kept once
+func normalize(output string) string {
+	for _, v := range volatile {
+		output = v.pattern.ReplaceAllString(output, v.replacement)
+	}
+	seen := make(map[string]bool)
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, " ")
+		if strings.TrimSpace(line) != "" {
+			if seen[line] {
+				continue
+			}
+			seen[line] = true
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")) + "\n"
+}
+
+// toJSON renders structured output for a golden file
+func toJSON(t *testing.T, value interface{}) string {
+	t.Helper()
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		t.Fatalf("marshalling %T: %v", value, err)
+	}
+	return buf.String()
+}
+
+func goldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// assertGolden compares output with testdata/<name>.golden, or rewrites the
+// file with -update
+func assertGolden(t *testing.T, name, output string) {
+	t.Helper()
+	path := goldenPath(name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v (run with -update to create it)", path, err)
+	}
+	if output != string(expected) {
+		t.Errorf("%s differs from %s (run with -update to accept):\n%s", name, path, lineDiff(string(expected), output))
+	}
+}
+
+// eventuallyGolden polls output until it matches the golden file; cluster
+// state such as pull back-off settles a few seconds after a pod looks ready
+func eventuallyGolden(t *testing.T, name string, output func(ctx context.Context) (string, error)) {
+	t.Helper()
+	if *update {
+		text, err := output(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, name, text)
+		return
+	}
+	expected, err := os.ReadFile(goldenPath(name))
+	if err != nil {
+		t.Fatalf("reading %s: %v (run with -update to create it)", goldenPath(name), err)
+	}
+	var last string
+	var lastErr error
+	wait.PollUntilContextTimeout(context.Background(), scenarioInterval, time.Minute, true, func(ctx context.Context) (bool, error) {
+		text, err := output(ctx)
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		last = text
+		return text == string(expected), nil
+	})
+	if last == "" && lastErr != nil {
+		t.Fatal(lastErr)
+	}
+	assertGolden(t, name, last)
+}
+
+// lineDiff lists the lines only in expected (-) or only in actual (+)
+func lineDiff(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	inExpected := make(map[string]bool)
+	for _, line := range expectedLines {
+		inExpected[line] = true
+	}
+	inActual := make(map[string]bool)
+	for _, line := range actualLines {
+		inActual[line] = true
+	}
+	var diff []string
+	for _, line := range expectedLines {
+		if !inActual[line] {
+			diff = append(diff, "- "+line)
+		}
+	}
+	for _, line := range actualLines {
+		if !inExpected[line] {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	if len(diff) == 0 {
+		return "(same lines in a different order)"
+	}
+	return strings.Join(diff, "\n")
+}
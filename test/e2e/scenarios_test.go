@@ -0,0 +1,186 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	busyboxImage     = "registry.k8s.io/e2e-test-images/busybox:1.36.1-1"
+	scenarioTimeout  = 3 * time.Minute
+	scenarioInterval = 3 * time.Second
+)
+
+// crashLogLines is what the crashlooping container prints before exiting
+const crashLogLines = `INFO starting payments service version 1.4.2
+INFO connecting to database postgres.payments.svc:5432
+ERROR failed to connect to database: dial tcp 172.30.12.4:5432: connect: connection refused
+ERROR failed to connect to database: dial tcp 172.30.12.4:5432: connect: connection refused
+FATAL unable to initialize storage: context deadline exceeded
+panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x4a2b3c]
+`
+
+// scenario is one broken workload seeded in its own namespace
+type scenario struct {
+	name      string
+	namespace string
+	pod       *corev1.Pod
+	// ready reports whether the pod has reached the broken state the golden
+	// output describes
+	ready func(pod *corev1.Pod) bool
+}
+
+func waitingReason(reason string) func(pod *corev1.Pod) bool {
+	return func(pod *corev1.Pod) bool {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == reason {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func unschedulable(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Reason == corev1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+func scenarioPod(name string, container corev1.Container) *corev1.Pod {
+	container.Name = "app"
+	if container.Image == "" {
+		container.Image = busyboxImage
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": name, "mcp-e2e": "true"}},
+		Spec: corev1.PodSpec{
+			Containers:                    []corev1.Container{container},
+			TerminationGracePeriodSeconds: new(int64),
+		},
+	}
+}
+
+var crashLoop = corev1.Container{Command: []string{"sh", "-c", "printf '%s' \"$LOG\"; exit 1"}, Env: []corev1.EnvVar{{Name: "LOG", Value: crashLogLines}}}
+
+var scenarios = []scenario{
+	{
+		name:      "crashloop",
+		namespace: "mcp-e2e-crashloop",
+		pod:       scenarioPod("payments", crashLoop),
+		ready:     waitingReason("CrashLoopBackOff"),
+	},
+	{
+		name:      "imagepull",
+		namespace: "mcp-e2e-imagepull",
+		pod:       scenarioPod("frontend", corev1.Container{Image: "registry.invalid/mcp-e2e/frontend:1.0"}),
+		ready:     waitingReason("ImagePullBackOff"),
+	},
+	{
+		name:      "missing-config",
+		namespace: "mcp-e2e-missing-config",
+		pod: scenarioPod("worker", corev1.Container{
+			Command: []string{"sleep", "3600"},
+			EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "worker-config"}}}},
+		}),
+		ready: waitingReason("CreateContainerConfigError"),
+	},
+	{
+		name:      "unschedulable",
+		namespace: "mcp-e2e-unschedulable",
+		pod: scenarioPod("batch", corev1.Container{
+			Command:   []string{"sleep", "3600"},
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Ti")}},
+		}),
+		ready: unschedulable,
+	},
+	// The chat pipeline's built-in planner diagnoses the debugger namespace
+	{
+		name:      "chat",
+		namespace: "debugger",
+		pod:       scenarioPod("failing-app", crashLoop),
+		ready:     waitingReason("CrashLoopBackOff"),
+	},
+}
+
+func scenarioNamed(t *testing.T, name string) scenario {
+	t.Helper()
+	for _, sc := range scenarios {
+		if sc.name == name {
+			return sc
+		}
+	}
+	t.Fatalf("no scenario %q", name)
+	return scenario{}
+}
+
+// seed creates every scenario's namespace and pod, replacing pods left by an
+// earlier run, and registers their removal
+func (e *testEnv) seed(ctx context.Context) error {
+	for _, sc := range scenarios {
+		sc := sc
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sc.namespace, Labels: map[string]string{"mcp-e2e": "true"}}}
+		_, err := e.client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+		created := err == nil
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("creating namespace %s: %v", sc.namespace, err)
+		}
+
+		pods := e.client.CoreV1().Pods(sc.namespace)
+		if err := pods.Delete(ctx, sc.pod.Name, metav1.DeleteOptions{GracePeriodSeconds: new(int64)}); err == nil {
+			err = wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+				_, err := pods.Get(ctx, sc.pod.Name, metav1.GetOptions{})
+				return apierrors.IsNotFound(err), nil
+			})
+			if err != nil {
+				return fmt.Errorf("removing the previous %s/%s: %v", sc.namespace, sc.pod.Name, err)
+			}
+		}
+		if _, err := pods.Create(ctx, sc.pod.DeepCopy(), metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("creating pod %s/%s: %v", sc.namespace, sc.pod.Name, err)
+		}
+
+		if *keep {
+			continue
+		}
+		e.teardown = append(e.teardown, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if created {
+				e.client.CoreV1().Namespaces().Delete(ctx, sc.namespace, metav1.DeleteOptions{})
+			} else {
+				e.client.CoreV1().Pods(sc.namespace).Delete(ctx, sc.pod.Name, metav1.DeleteOptions{})
+			}
+		})
+	}
+	return nil
+}
+
+// waitFor blocks until the scenario's pod is in its broken state
+func (e *testEnv) waitFor(t *testing.T, sc scenario) *corev1.Pod {
+	t.Helper()
+	var pod *corev1.Pod
+	err := wait.PollUntilContextTimeout(context.Background(), scenarioInterval, scenarioTimeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		pod, err = e.client.CoreV1().Pods(sc.namespace).Get(ctx, sc.pod.Name, metav1.GetOptions{})
+		return err == nil && sc.ready(pod), nil
+	})
+	if err != nil {
+		t.Fatalf("%s/%s did not reach the %s state within %v: %+v", sc.namespace, sc.pod.Name, sc.name, scenarioTimeout, pod.Status)
+	}
+	return pod
+}